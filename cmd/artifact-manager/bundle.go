@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/artifact"
+)
+
+// Bundle identity, matching FyneApp.toml for the default character
+const (
+	bundleBaseName       = "Desktop Companion"
+	bundleBaseIdentifier = "ai.opd.dds"
+	bundlePublisher      = "opd-ai"
+)
+
+func handleBundle(manager *artifact.Manager) {
+	if flag.NArg() != 5 || (flag.Arg(1) != "darwin" && flag.Arg(1) != "windows") {
+		fmt.Fprintf(os.Stderr, "Usage: bundle darwin|windows CHARACTER ARCH BINARY\n")
+		os.Exit(1)
+	}
+
+	character := flag.Arg(2)
+	arch := flag.Arg(3)
+	binary := flag.Arg(4)
+
+	characterDir := filepath.Join(*charactersDir, character)
+	if _, err := os.Stat(filepath.Join(characterDir, "character.json")); err != nil {
+		log.Fatalf("Character not found: %s", characterDir)
+	}
+
+	if flag.Arg(1) == "windows" {
+		bundleWindows(manager, character, characterDir, arch, binary)
+	} else {
+		bundleDarwin(manager, character, characterDir, arch, binary)
+	}
+}
+
+// bundleDarwin builds, signs and notarizes a .app bundle and stores it zipped
+func bundleDarwin(manager *artifact.Manager, character, characterDir, arch, binary string) {
+	cfg := artifact.DarwinBundleConfig{
+		Name:       bundleName(character),
+		Identifier: bundleIdentifier(character),
+		Version:    *bundleVersion,
+		Executable: binary,
+		Icon:       *bundleIcon,
+		// Same layout as the repository, so the companion finds the
+		// character relative to Contents/Resources
+		Assets: map[string]string{"assets/characters/" + character: characterDir},
+	}
+	if cfg.Icon == "" {
+		cfg.Icon = idleAnimationPath(characterDir)
+	}
+	signing := artifact.DarwinSigning{
+		Identity:      *signIdentity,
+		Entitlements:  *entitlements,
+		NotaryProfile: *notaryProfile,
+	}
+
+	workDir, err := os.MkdirTemp("", "dds-bundle-")
+	if err != nil {
+		log.Fatalf("Failed to create work directory: %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	appPath, err := artifact.BuildDarwinBundle(cfg, workDir)
+	if err != nil {
+		log.Fatalf("Failed to build bundle: %v", err)
+	}
+	if err := artifact.SignDarwinBundle(appPath, signing); err != nil {
+		log.Fatalf("Failed to sign bundle: %v", err)
+	}
+	if err := artifact.NotarizeDarwinBundle(appPath, signing); err != nil {
+		log.Fatalf("Failed to notarize bundle: %v", err)
+	}
+
+	zipPath := filepath.Join(workDir, character+".app.zip")
+	if err := artifact.ZipDarwinBundle(appPath, zipPath); err != nil {
+		log.Fatalf("Failed to zip bundle: %v", err)
+	}
+
+	metadata := map[string]string{
+		"stored_by": "artifact-manager",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"bundle":    filepath.Base(appPath),
+		"bundle_id": cfg.Identifier,
+		"version":   cfg.Version,
+		"signed":    strconv.FormatBool(signing.Identity != ""),
+		"notarized": strconv.FormatBool(signing.NotaryProfile != ""),
+	}
+	info, err := manager.StoreArtifact(zipPath, character, "darwin", arch, metadata)
+	if err != nil {
+		log.Fatalf("Failed to store bundle: %v", err)
+	}
+
+	fmt.Printf("✓ Bundled %s: %s\n", filepath.Base(appPath), info.Name)
+	if *verbose {
+		fmt.Printf("  Identifier: %s\n", cfg.Identifier)
+		fmt.Printf("  Version: %s\n", cfg.Version)
+		fmt.Printf("  Signed: %s, notarized: %s\n", metadata["signed"], metadata["notarized"])
+		fmt.Printf("  Size: %s\n", formatSize(info.Size))
+	}
+}
+
+// bundleWindows stores a portable zip of the executable and its character,
+// with an NSIS installer script inside when asked, and the compiled
+// installer alongside it when makensis is given
+func bundleWindows(manager *artifact.Manager, character, characterDir, arch, binary string) {
+	cfg := artifact.WindowsPackageConfig{
+		Name:       bundleName(character),
+		Identifier: bundleIdentifier(character),
+		Publisher:  bundlePublisher,
+		Version:    *bundleVersion,
+		Executable: binary,
+		Icon:       *bundleIcon,
+		// The companion finds assets/ next to its executable
+		Assets:    map[string]string{"assets/characters/" + character: characterDir},
+		Installer: *installer || *makensis != "",
+	}
+	if cfg.Icon == "" {
+		cfg.Icon = idleAnimationPath(characterDir)
+	}
+
+	workDir, err := os.MkdirTemp("", "dds-bundle-")
+	if err != nil {
+		log.Fatalf("Failed to create work directory: %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	packageDir, err := artifact.BuildWindowsPackage(cfg, workDir)
+	if err != nil {
+		log.Fatalf("Failed to build package: %v", err)
+	}
+	zipPath := filepath.Join(workDir, character+".zip")
+	if err := artifact.ZipWindowsPackage(packageDir, zipPath); err != nil {
+		log.Fatalf("Failed to zip package: %v", err)
+	}
+
+	metadata := map[string]string{
+		"stored_by": "artifact-manager",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"package":   "portable",
+		"app_id":    cfg.Identifier,
+		"version":   cfg.Version,
+		"installer": strconv.FormatBool(cfg.Installer),
+	}
+	info, err := manager.StoreArtifact(zipPath, character, "windows", arch, metadata)
+	if err != nil {
+		log.Fatalf("Failed to store package: %v", err)
+	}
+	fmt.Printf("✓ Packaged %s (portable): %s\n", cfg.Name, info.Name)
+	if *verbose {
+		fmt.Printf("  Identifier: %s\n", cfg.Identifier)
+		fmt.Printf("  Version: %s\n", cfg.Version)
+		fmt.Printf("  Installer script: %s\n", metadata["installer"])
+		fmt.Printf("  Size: %s\n", formatSize(info.Size))
+	}
+
+	if *makensis == "" {
+		return
+	}
+	setup, err := artifact.CompileWindowsInstaller(packageDir, *makensis)
+	if err != nil {
+		log.Fatalf("Failed to compile installer: %v", err)
+	}
+	metadata["package"] = "installer"
+	metadata["installer"] = filepath.Base(setup)
+	info, err = manager.StoreArtifact(setup, character, "windows", arch, metadata)
+	if err != nil {
+		log.Fatalf("Failed to store installer: %v", err)
+	}
+	fmt.Printf("✓ Packaged %s (installer): %s\n", cfg.Name, info.Name)
+	if *verbose {
+		fmt.Printf("  Size: %s\n", formatSize(info.Size))
+	}
+}
+
+// bundleName returns the app name for a character's bundle
+func bundleName(character string) string {
+	if character == "default" {
+		return bundleBaseName
+	}
+	return fmt.Sprintf("%s (%s)", bundleBaseName, character)
+}
+
+// bundleIdentifier returns the bundle ID for a character; bundle IDs only
+// allow letters, digits, dots and hyphens
+func bundleIdentifier(character string) string {
+	if character == "default" {
+		return bundleBaseIdentifier
+	}
+	return bundleBaseIdentifier + "." + strings.ReplaceAll(character, "_", "-")
+}
+
+// idleAnimationPath returns the character's idle animation for use as the
+// icon, like FyneApp.toml does, or empty when the card has none
+func idleAnimationPath(characterDir string) string {
+	data, err := os.ReadFile(filepath.Join(characterDir, "character.json"))
+	if err != nil {
+		return ""
+	}
+	var card struct {
+		Animations map[string]string `json:"animations"`
+	}
+	if err := json.Unmarshal(data, &card); err != nil || card.Animations["idle"] == "" {
+		return ""
+	}
+	return filepath.Join(characterDir, card.Animations["idle"])
+}
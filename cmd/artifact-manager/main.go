@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/opd-ai/desktop-companion/lib/artifact"
@@ -19,6 +20,16 @@ var (
 	artifactsDir = flag.String("dir", defaultArtifactsDir, "Artifacts directory")
 	verbose      = flag.Bool("verbose", false, "Enable verbose output")
 	showVersion  = flag.Bool("version", false, "Show version information")
+
+	// Platform bundles
+	charactersDir = flag.String("characters", "assets/characters", "Directory holding the characters to bundle")
+	bundleVersion = flag.String("bundle-version", version, "Version written to bundles")
+	bundleIcon    = flag.String("icon", "", "Bundle icon: .icns or .ico, PNG, GIF or JPEG (default: the character's idle animation)")
+	signIdentity  = flag.String("sign-identity", "", "codesign identity; bundles are left unsigned when empty")
+	entitlements  = flag.String("entitlements", "", "Entitlements plist passed to codesign")
+	notaryProfile = flag.String("notary-profile", "", "notarytool keychain profile; notarization is skipped when empty")
+	installer     = flag.Bool("installer", false, "Add an NSIS installer script to Windows packages")
+	makensis      = flag.String("makensis", "", "makensis to compile and store the Windows installer with (implies -installer)")
 )
 
 func main() {
@@ -32,6 +43,11 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  cleanup POLICY                       Clean up expired artifacts\n")
 		fmt.Fprintf(os.Stderr, "  compress POLICY                      Compress old artifacts\n")
 		fmt.Fprintf(os.Stderr, "  policies                             List available retention policies\n")
+		fmt.Fprintf(os.Stderr, "  keygen KEYFILE                       Generate an ed25519 signing key (KEYFILE, KEYFILE.pub)\n")
+		fmt.Fprintf(os.Stderr, "  sign KEYFILE [MANIFEST]              Write a signed manifest of all artifacts\n")
+		fmt.Fprintf(os.Stderr, "  verify PUBKEY [MANIFEST]             Verify artifacts against a signed manifest\n")
+		fmt.Fprintf(os.Stderr, "  bundle darwin CHARACTER ARCH BINARY  Build a macOS .app bundle and store it zipped\n")
+		fmt.Fprintf(os.Stderr, "  bundle windows CHARACTER ARCH BINARY Build a portable Windows zip, optionally with an installer\n")
 		fmt.Fprintf(os.Stderr, "\nOPTIONS:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nEXAMPLES:\n")
@@ -39,6 +55,11 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s list default\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s cleanup development\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s compress production\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s keygen release.key\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s sign release.key\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify release.key.pub\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -sign-identity \"Developer ID Application: ...\" -notary-profile dds bundle darwin default arm64 build/default_darwin_arm64\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -makensis makensis bundle windows default amd64 build/default_windows_amd64.exe\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -73,6 +94,14 @@ func main() {
 		handleCompress(manager)
 	case "policies":
 		handlePolicies(manager)
+	case "keygen":
+		handleKeygen()
+	case "sign":
+		handleSign(manager)
+	case "verify":
+		handleVerify(manager)
+	case "bundle":
+		handleBundle(manager)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		flag.Usage()
@@ -240,6 +269,109 @@ func handlePolicies(manager *artifact.Manager) {
 }
 
 // formatSize formats a byte size into a human-readable string
+func handleKeygen() {
+	if flag.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: keygen KEYFILE\n")
+		os.Exit(1)
+	}
+
+	keyPath := flag.Arg(1)
+	if _, err := os.Stat(keyPath); err == nil {
+		log.Fatalf("Refusing to overwrite existing key: %s", keyPath)
+	}
+
+	publicKey, err := artifact.GenerateSigningKey(keyPath)
+	if err != nil {
+		log.Fatalf("Failed to generate signing key: %v", err)
+	}
+
+	fmt.Printf("✓ Private key: %s (keep this secret)\n", keyPath)
+	fmt.Printf("✓ Public key: %s.pub\n", keyPath)
+	if *verbose {
+		fmt.Printf("  Fingerprint: %x\n", publicKey)
+	}
+}
+
+func handleSign(manager *artifact.Manager) {
+	if flag.NArg() < 2 || flag.NArg() > 3 {
+		fmt.Fprintf(os.Stderr, "Usage: sign KEYFILE [MANIFEST]\n")
+		os.Exit(1)
+	}
+
+	privateKey, err := artifact.LoadSigningKey(flag.Arg(1))
+	if err != nil {
+		log.Fatalf("Failed to load signing key: %v", err)
+	}
+
+	manifestPath := manifestPathArg()
+	signed, err := manager.SignManifest(privateKey)
+	if err != nil {
+		log.Fatalf("Failed to sign manifest: %v", err)
+	}
+	if err := artifact.SaveSignedManifest(manifestPath, signed); err != nil {
+		log.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	fmt.Printf("✓ Signed %d artifacts: %s\n", len(signed.Manifest.Artifacts), manifestPath)
+	if *verbose {
+		for _, entry := range signed.Manifest.Artifacts {
+			fmt.Printf("  %s  %s\n", entry.Checksum, entry.Path)
+		}
+	}
+}
+
+func handleVerify(manager *artifact.Manager) {
+	if flag.NArg() < 2 || flag.NArg() > 3 {
+		fmt.Fprintf(os.Stderr, "Usage: verify PUBKEY [MANIFEST]\n")
+		os.Exit(1)
+	}
+
+	publicKey, err := artifact.LoadPublicKey(flag.Arg(1))
+	if err != nil {
+		log.Fatalf("Failed to load public key: %v", err)
+	}
+
+	signed, err := artifact.LoadSignedManifest(manifestPathArg())
+	if err != nil {
+		log.Fatalf("Failed to load manifest: %v", err)
+	}
+
+	report, err := manager.VerifyManifest(signed, publicKey)
+	if err != nil {
+		log.Fatalf("Failed to verify manifest: %v", err)
+	}
+
+	if report.Signature {
+		fmt.Printf("✓ Signature valid\n")
+	} else {
+		fmt.Printf("✗ Signature invalid for this public key\n")
+	}
+	for _, path := range report.Modified {
+		fmt.Printf("✗ Modified: %s\n", path)
+	}
+	for _, path := range report.Missing {
+		fmt.Printf("✗ Missing: %s\n", path)
+	}
+	for _, path := range report.Unlisted {
+		fmt.Printf("✗ Not in manifest: %s\n", path)
+	}
+
+	if !report.OK() {
+		fmt.Printf("\nVerification FAILED (%d of %d artifacts intact)\n", report.Verified, len(signed.Manifest.Artifacts))
+		os.Exit(1)
+	}
+	fmt.Printf("✓ All %d artifacts verified\n", report.Verified)
+}
+
+// manifestPathArg returns the optional MANIFEST argument or the default
+// manifest inside the artifacts directory
+func manifestPathArg() string {
+	if flag.NArg() == 3 {
+		return flag.Arg(2)
+	}
+	return filepath.Join(*artifactsDir, artifact.DefaultManifestName)
+}
+
 func formatSize(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// diffReport is the output of "card-tool diff -format json"
+type diffReport struct {
+	Old         string                 `json:"old"`
+	New         string                 `json:"new"`
+	Changes     []character.CardChange `json:"changes"`
+	BreaksSaves bool                   `json:"breaksSaves"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "diff":
+		os.Exit(runDiffCommand(os.Args[2:], os.Stdout, os.Stderr))
+	case "help", "-h", "-help", "--help":
+		usage(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+}
+
+// usage lists the commands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: card-tool COMMAND [ARGS...]")
+	fmt.Fprintln(w, "\nTools for character card authors.")
+	fmt.Fprintln(w, "\nCOMMANDS:")
+	fmt.Fprintln(w, "  diff [options] old.json new.json   Show what changed between two versions of a card")
+}
+
+// runDiffCommand handles "card-tool diff": it loads both cards and prints
+// their semantic differences, warning about changes that break saves.
+func runDiffCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "Output format: text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: card-tool diff [options] old.json new.json")
+		fmt.Fprintln(stderr, "\nShows added, removed and changed dialogs, animations, stats and other")
+		fmt.Fprintln(stderr, "entries, and warns about changes that make existing saves lose progress.")
+		fmt.Fprintln(stderr, "Exits with status 1 when a change breaks saves.")
+		fmt.Fprintln(stderr, "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "Error: unknown format %q, want text or json\n", *format)
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	// Loading logs at info level, which would bury the diff
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	report := diffReport{Old: fs.Arg(0), New: fs.Arg(1)}
+	before, err := character.LoadCard(report.Old)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %v\n", report.Old, err)
+		return 2
+	}
+	after, err := character.LoadCard(report.New)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %v\n", report.New, err)
+		return 2
+	}
+
+	report.Changes = character.DiffCards(before, after)
+	if report.Changes == nil {
+		report.Changes = []character.CardChange{}
+	}
+	for _, change := range report.Changes {
+		report.BreaksSaves = report.BreaksSaves || change.BreaksSaves
+	}
+	status := 0
+	if report.BreaksSaves {
+		status = 1
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 2
+		}
+		return status
+	}
+
+	if len(report.Changes) == 0 {
+		fmt.Fprintln(stdout, "No changes")
+		return status
+	}
+	for _, change := range report.Changes {
+		fmt.Fprintln(stdout, change)
+	}
+	if report.BreaksSaves {
+		fmt.Fprintln(stdout, "\n⚠️  Changes that affect existing saves:")
+		for _, change := range report.Changes {
+			if change.BreaksSaves {
+				fmt.Fprintf(stdout, "  %s: %s\n", change.Path, change.SaveImpact)
+			}
+		}
+	}
+	return status
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/dialog"
+)
+
+// runDialogBenchmark runs every dialog backend over contexts generated from
+// the card without starting the GUI and prints a side-by-side comparison,
+// so authors can pick a default backend and fallback chain.
+func runDialogBenchmark(card *character.CharacterCard, characterDir string, rounds int, out io.Writer) error {
+	if rounds < 1 {
+		return fmt.Errorf("-bench-rounds must be at least 1, got %d", rounds)
+	}
+
+	// Backends log every response at info level, which would bury the report
+	if !*debug {
+		logrus.SetLevel(logrus.ErrorLevel)
+	}
+
+	char, err := character.New(card, characterDir)
+	if err != nil {
+		return fmt.Errorf("failed to create character: %w", err)
+	}
+	contexts := len(char.DialogBenchContexts())
+	results, err := char.BenchmarkDialogBackends(rounds)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Dialog benchmark: %s, %d contexts x %d rounds\n\n", card.Name, contexts, rounds)
+	writeBenchTable(out, results)
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Configured: default %s", card.DialogBackend.DefaultBackend)
+	if len(card.DialogBackend.FallbackChain) > 0 {
+		fmt.Fprintf(out, ", fallback %s", strings.Join(card.DialogBackend.FallbackChain, " > "))
+	}
+	fmt.Fprintln(out)
+
+	chain := dialog.RecommendChain(results)
+	if len(chain) == 0 {
+		fmt.Fprintln(out, "Suggested: none, no backend answered reliably")
+		return nil
+	}
+	fmt.Fprintf(out, "Suggested: default %s", chain[0])
+	if len(chain) > 1 {
+		fmt.Fprintf(out, ", fallback %s", strings.Join(chain[1:], " > "))
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// writeBenchTable prints one row per backend; backends that handled no
+// context are listed as skipped
+func writeBenchTable(out io.Writer, results []dialog.BenchResult) {
+	fmt.Fprintf(out, "%-14s %8s %6s %9s %9s %9s %7s %7s %6s %6s %6s\n",
+		"BACKEND", "ATTEMPTS", "ERRORS", "P50", "P90", "P99", "DIVERSE", "REPEAT", "CONF", "QUAL", "CALIB")
+	for _, r := range results {
+		if r.Attempts == 0 {
+			fmt.Fprintf(out, "%-14s %8s (skipped all %d contexts)\n", r.Backend, "-", r.Skipped)
+			continue
+		}
+		fmt.Fprintf(out, "%-14s %8d %5.0f%% %9s %9s %9s %6.0f%% %6.0f%% %6.2f %6.2f %6.2f\n",
+			r.Backend, r.Attempts, r.ErrorRate()*100,
+			formatBenchLatency(r.LatencyP50), formatBenchLatency(r.LatencyP90), formatBenchLatency(r.LatencyP99),
+			r.Diversity*100, r.RepetitionRate*100,
+			r.MeanConfidence, r.MeanQuality, r.CalibrationError)
+	}
+	fmt.Fprintln(out, "\nDIVERSE: distinct responses; REPEAT: same response twice in a row for a trigger;")
+	fmt.Fprintln(out, "CALIB: mean gap between claimed confidence and heuristic quality (lower is better)")
+}
+
+// formatBenchLatency rounds a latency to a readable precision
+func formatBenchLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(100 * time.Nanosecond).String()
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+	"github.com/opd-ai/desktop-companion/lib/pack"
+	"github.com/opd-ai/desktop-companion/lib/ui"
+)
+
+// selectBuiltinCharacter points -character at a character built into the
+// binary when -builtin names one, or when no -character was given and the
+// binary holds exactly one. When it holds several it returns them for the
+// picker instead.
+func selectBuiltinCharacter() []pack.Builtin {
+	caller := getCaller()
+
+	builtins, err := pack.LoadBuiltins(builtinPacks())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to load built-in characters")
+	}
+
+	// A character saved in settings.json only changes the flag default, so
+	// it doesn't hide the built-in characters; only the command line does
+	builtin, choices, err := chooseBuiltin(builtins, *builtinName, flagPassed(flag.CommandLine, "character"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if builtin != nil {
+		*characterPath = extractBuiltin(builtin)
+		return nil
+	}
+	if len(choices) > 0 && (*trainDialog || *scriptFile != "") {
+		fmt.Fprintf(os.Stderr, "Error: choose a character with -builtin (%s)\n", describeBuiltins(builtins))
+		os.Exit(1)
+	}
+	return choices
+}
+
+// chooseBuiltin decides which built-in character to start: the one named
+// by name, the only one when no character was passed, or none. Several
+// built-in characters with nothing passed are returned as choices.
+func chooseBuiltin(builtins []pack.Builtin, name string, characterPassed bool) (*pack.Builtin, []pack.Builtin, error) {
+	if name != "" {
+		builtin, ok := pack.FindBuiltin(builtins, name)
+		if !ok {
+			return nil, nil, fmt.Errorf("no built-in character %q (%s)", name, describeBuiltins(builtins))
+		}
+		return builtin, nil, nil
+	}
+	if len(builtins) == 0 || characterPassed {
+		return nil, nil, nil
+	}
+	if len(builtins) == 1 {
+		return &builtins[0], nil, nil
+	}
+	return nil, builtins, nil
+}
+
+// describeBuiltins lists the built-in characters for error messages
+func describeBuiltins(builtins []pack.Builtin) string {
+	if len(builtins) == 0 {
+		return "this binary has none"
+	}
+	ids := make([]string, len(builtins))
+	for i, builtin := range builtins {
+		ids[i] = builtin.ID
+	}
+	return "available: " + strings.Join(ids, ", ")
+}
+
+// extractBuiltin extracts a built-in pack to the user cache, returning the
+// path of its character card
+func extractBuiltin(builtin *pack.Builtin) string {
+	caller := getCaller()
+
+	cacheDir, err := pack.DefaultCacheDir()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to locate pack cache")
+	}
+	cardPath, err := builtin.Extract(cacheDir)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":  caller,
+			"builtin": builtin.ID,
+			"error":   err.Error(),
+		}).Fatal("Failed to extract built-in character")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":   caller,
+		"builtin":  builtin.ID,
+		"name":     builtin.Manifest.Name,
+		"cardPath": cardPath,
+	}).Info("Built-in character extracted")
+	return cardPath
+}
+
+// runBuiltinPicker asks which built-in character to start, then starts the
+// companion in the same Fyne app like runOnboarding
+func runBuiltinPicker(builtins []pack.Builtin, profiler *monitoring.Profiler) {
+	caller := getCaller()
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+		"count":  len(builtins),
+	}).Info("Showing built-in character picker")
+
+	choices := make([]ui.OnboardingCharacter, 0, len(builtins))
+	for i := range builtins {
+		choices = append(choices, builtinChoice(&builtins[i]))
+	}
+
+	myApp := newFyneApp()
+	cleanup := func() {}
+
+	picker := ui.NewCharacterPicker(myApp, choices, func(choice ui.OnboardingCharacter) {
+		*characterPath = choice.CardPath
+
+		card, characterDir := loadCharacterConfiguration()
+		char := createCharacterInstance(card, characterDir)
+		cleanup = startCompanion(myApp, char, characterDir, profiler)
+	})
+	picker.Show()
+	myApp.Run()
+	cleanup()
+
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+	}).Info("Desktop application completed")
+}
+
+// builtinChoice extracts a built-in character and describes it for the picker
+func builtinChoice(builtin *pack.Builtin) ui.OnboardingCharacter {
+	cardPath := extractBuiltin(builtin)
+	choice := ui.OnboardingCharacter{
+		Name:        builtin.Manifest.Name,
+		Description: builtin.Manifest.Description,
+		CardPath:    cardPath,
+	}
+	if card, err := character.LoadCard(cardPath); err == nil {
+		if idle := card.Animations["idle"]; idle != "" {
+			choice.PreviewPath = filepath.Join(filepath.Dir(cardPath), idle)
+		}
+	}
+	return choice
+}
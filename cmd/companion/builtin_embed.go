@@ -0,0 +1,23 @@
+//go:build builtin_characters
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// builtinFiles holds the packs dropped into builtin/ when building with
+// -tags builtin_characters
+//
+//go:embed builtin
+var builtinFiles embed.FS
+
+// builtinPacks returns the embedded character packs
+func builtinPacks() fs.FS {
+	sub, err := fs.Sub(builtinFiles, "builtin")
+	if err != nil {
+		return nil
+	}
+	return sub
+}
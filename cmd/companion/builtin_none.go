@@ -0,0 +1,10 @@
+//go:build !builtin_characters
+
+package main
+
+import "io/fs"
+
+// builtinPacks returns nil: regular builds embed no characters
+func builtinPacks() fs.FS {
+	return nil
+}
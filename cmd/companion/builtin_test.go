@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/pack"
+)
+
+func TestChooseBuiltin(t *testing.T) {
+	builtins := []pack.Builtin{
+		{ID: "flirty", Manifest: &pack.Manifest{Name: "Flirty"}},
+		{ID: "tsundere", Manifest: &pack.Manifest{Name: "Tsundere"}},
+	}
+
+	builtin, choices, err := chooseBuiltin(builtins, "tsundere", true)
+	if err != nil || builtin == nil || builtin.ID != "tsundere" || choices != nil {
+		t.Errorf("Expected -builtin to win over -character, got %v %v %v", builtin, choices, err)
+	}
+	if _, _, err := chooseBuiltin(builtins, "missing", false); err == nil {
+		t.Error("Expected an unknown -builtin to fail")
+	}
+
+	if builtin, choices, _ := chooseBuiltin(builtins, "", false); builtin != nil || len(choices) != 2 {
+		t.Errorf("Expected the picker for several characters, got %v %v", builtin, choices)
+	}
+	if builtin, choices, _ := chooseBuiltin(builtins, "", true); builtin != nil || choices != nil {
+		t.Errorf("Expected an explicit -character to skip built-ins, got %v %v", builtin, choices)
+	}
+	if builtin, _, _ := chooseBuiltin(builtins[:1], "", false); builtin == nil || builtin.ID != "flirty" {
+		t.Errorf("Expected the only built-in character to start directly, got %v", builtin)
+	}
+}
+
+func TestFlagPassedIgnoresDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	character := fs.String("character", "default.json", "")
+	fs.Bool("game", false, "")
+
+	// A character saved by onboarding must not hide the built-in picker
+	settings := &config.Settings{Character: "saved.json"}
+	if err := settings.ApplyToFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-game"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if *character != "saved.json" || flagPassed(fs, "character") {
+		t.Errorf("Expected the saved -character (%q) to count as not passed", *character)
+	}
+	if !flagPassed(fs, "game") {
+		t.Error("Expected -game to count as passed")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+// character_switch.go swaps the running character for another installed one
+// from the "Switch Character" dialog. The current character saves and its
+// services stop, the new character starts with its own window and saves in
+// the same Fyne app, and only then does the old window close, since closing
+// the last window would quit the app. The choice is remembered for the next
+// launch.
+
+import (
+	"path/filepath"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+	"github.com/opd-ai/desktop-companion/lib/ui"
+)
+
+// activeCompanion holds the stop func of the character on screen, which
+// changes each time the user switches characters
+var activeCompanion struct {
+	sync.Mutex
+	stop func()
+}
+
+// stopActiveCompanion stops whichever character is running
+func stopActiveCompanion() {
+	activeCompanion.Lock()
+	defer activeCompanion.Unlock()
+	if activeCompanion.stop != nil {
+		activeCompanion.stop()
+		activeCompanion.stop = nil
+	}
+}
+
+// setupCharacterSwitcher offers the installed characters in the menu
+func setupCharacterSwitcher(myApp fyne.App, window *ui.DesktopWindow, characterDir string, profiler *monitoring.Profiler) {
+	current := filepath.Join(characterDir, "character.json")
+	window.SetCharacterSwitcher(current, findInstalledCharacters, func(cardPath string) {
+		switchCharacter(myApp, window, cardPath, profiler)
+	})
+}
+
+// switchCharacter saves and stops the running character, starts the one at
+// cardPath and closes the old window. The running character stays when the
+// new card fails to load.
+func switchCharacter(myApp fyne.App, oldWindow *ui.DesktopWindow, cardPath string, profiler *monitoring.Profiler) {
+	caller := getCaller()
+
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":   caller,
+			"cardPath": cardPath,
+			"error":    err.Error(),
+		}).Warn("Cannot switch to character that fails to load")
+		return
+	}
+	characterDir := filepath.Dir(cardPath)
+	char, err := character.New(card, characterDir)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":   caller,
+			"cardPath": cardPath,
+			"error":    err.Error(),
+		}).Warn("Cannot switch to character")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":    caller,
+		"character": card.Name,
+	}).Info("Switching character")
+
+	activeCompanion.Lock()
+	if activeCompanion.stop != nil {
+		activeCompanion.stop()
+	}
+	activeCompanion.stop = launchCompanion(myApp, char, characterDir, profiler)
+	activeCompanion.Unlock()
+	oldWindow.Close()
+
+	rememberCharacter(cardPath)
+}
+
+// rememberCharacter makes the next launch start with cardPath
+func rememberCharacter(cardPath string) {
+	*characterPath = cardPath
+
+	path, err := config.DefaultSettingsPath()
+	if err != nil {
+		return
+	}
+	settings, err := config.LoadSettings(path)
+	if err != nil {
+		settings = &config.Settings{}
+	}
+	settings.Character = cardPath
+	if err := settings.Save(path); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Warn("Could not remember the character for the next launch")
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/api"
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+	"github.com/opd-ai/desktop-companion/lib/ui"
+)
+
+// daemonTick is how often a headless companion advances its character: stat
+// decay, schedules, jobs and random events. Nothing is drawn, so it can be
+// much slower than the window's frame rate.
+const daemonTick = 250 * time.Millisecond
+
+// resolveSocketPath returns -socket or the default daemon socket
+func resolveSocketPath() (string, error) {
+	if *socketPath != "" {
+		return *socketPath, nil
+	}
+	path, err := api.DefaultSocketPath()
+	if err != nil {
+		return "", fmt.Errorf("no default socket location, set -socket: %w", err)
+	}
+	return path, nil
+}
+
+// runDaemon runs the companion without a window until SIGINT or SIGTERM.
+// The game state, networking, dialog backends and auto-save live here, so
+// the pet's life goes on while no frontend is open. Frontends attach over
+// the control API on a unix socket.
+func runDaemon(card *character.CharacterCard, characterDir string) error {
+	caller := getCaller()
+
+	socket, err := resolveSocketPath()
+	if err != nil {
+		return err
+	}
+
+	char := createCharacterInstance(card, characterDir)
+	if *eventFreq > 0 {
+		char.SetEventFrequencyMultiplier(*eventFreq)
+	}
+	setupSizeStore(char)
+	setupRelationshipTuningStore(char)
+	applyProfile(char)
+
+	var cleanups []func()
+	defer func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}()
+	defer monitoring.RecoverCrash() // Runs before cleanup, which a crashed session must skip
+
+	if collector := setupAnalytics(char); collector != nil {
+		cleanups = append(cleanups, func() {
+			if err := collector.Stop(); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"caller": caller,
+					"error":  err.Error(),
+				}).Warn("Failed to save analytics")
+			}
+		})
+	}
+
+	networkManager := setupNetworkManager(char)
+	if networkManager != nil {
+		cleanups = append(cleanups, func() { networkManager.Stop() })
+	}
+	if stopBot := setupBot(char, networkManager); stopBot != nil {
+		cleanups = append(cleanups, stopBot)
+	}
+
+	if saveManager := setupAutoSave(char, nil); saveManager != nil {
+		cleanups = append(cleanups, func() {
+			finalSave(char, saveManager)
+			saveManager.Close()
+		})
+	}
+
+	if pusher := setupPush(char, nil); pusher != nil {
+		cleanups = append(cleanups, pusher.Stop)
+	}
+
+	for _, server := range []*api.Server{startAPIServer(char, "unix:"+socket), setupAPIServer(char)} {
+		if server != nil {
+			cleanups = append(cleanups, func() { server.Stop() })
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":        caller,
+		"characterName": card.Name,
+		"socket":        socket,
+	}).Info("Companion daemon running")
+	fmt.Printf("%s is running in the background. Attach a window with: companion -attach -character %s\n", card.Name, *characterPath)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(daemonTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Companion daemon stopping")
+			return nil
+		case <-ticker.C:
+			char.Update()
+		}
+	}
+}
+
+// runAttach opens a thin client window for a running daemon. The character
+// is loaded locally only to draw its animations; clicks and interactions go
+// to the daemon and its responses and state changes are mirrored.
+func runAttach(card *character.CharacterCard, characterDir string, profiler *monitoring.Profiler) error {
+	caller := getCaller()
+
+	socket, err := resolveSocketPath()
+	if err != nil {
+		return err
+	}
+	tokenPath, err := api.DefaultTokenPath()
+	if err != nil {
+		return err
+	}
+	token, err := api.LoadOrCreateToken(tokenPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClient("unix:"+socket, token)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	status, err := client.Status(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("no companion daemon on %s, start one with -daemon: %w", socket, err)
+	}
+
+	myApp := newFyneApp()
+	char := createCharacterInstance(card, characterDir)
+
+	// Game mode, events and networking run in the daemon, not here
+	window := ui.NewDesktopWindow(myApp, char, *debug, profiler, false, false, nil, false, false, false)
+	detach := window.AttachRemote(client)
+	defer detach()
+
+	logrus.WithFields(logrus.Fields{
+		"caller":        caller,
+		"characterName": status.Name,
+		"socket":        socket,
+	}).Info("Attached to companion daemon")
+
+	window.Show()
+	myApp.Run()
+	return nil
+}
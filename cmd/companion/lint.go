@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// lintReport is one card's result in "companion lint -format json"
+type lintReport struct {
+	Card     string                  `json:"card"`
+	Error    string                  `json:"error,omitempty"` // Set when the card fails validation
+	Findings []character.LintFinding `json:"findings"`
+}
+
+// runLintCommand handles "companion lint": it validates character cards and
+// reports best-practice warnings that validation lets through.
+func runLintCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "Output format: text or json")
+	strict := fs.Bool("strict", false, "Exit with status 1 when there are warnings, not just invalid cards")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: companion lint [options] [character.json ...]")
+		fmt.Fprintln(stderr, "\nValidates character cards and warns about likely mistakes.")
+		fmt.Fprintln(stderr, "Lints the default character when no card is given.")
+		fmt.Fprintln(stderr, "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "Error: unknown format %q, want text or json\n", *format)
+		return 2
+	}
+
+	cards := fs.Args()
+	if len(cards) == 0 {
+		cards = []string{"assets/characters/default/character.json"}
+	}
+
+	// Loading logs at info level, which would bury the findings
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	reports := make([]lintReport, 0, len(cards))
+	status := 0
+	for _, path := range cards {
+		report := lintCard(path)
+		if report.Error != "" {
+			status = 1
+		}
+		for _, finding := range report.Findings {
+			if *strict && finding.Severity == character.LintWarning {
+				status = 1
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return status
+	}
+
+	for _, report := range reports {
+		switch {
+		case report.Error != "":
+			fmt.Fprintf(stdout, "%s: error: %s\n", report.Card, report.Error)
+		case len(report.Findings) == 0:
+			fmt.Fprintf(stdout, "%s: ok\n", report.Card)
+		}
+		for _, finding := range report.Findings {
+			fmt.Fprintf(stdout, "%s: %s\n", report.Card, finding)
+		}
+	}
+	return status
+}
+
+// lintCard loads and lints one card
+func lintCard(path string) lintReport {
+	report := lintReport{Card: path, Findings: []character.LintFinding{}}
+	card, err := character.LoadCard(path)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	if findings := card.Lint(filepath.Dir(path)); findings != nil {
+		report.Findings = findings
+	}
+	return report
+}
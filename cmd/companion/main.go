@@ -1,21 +1,38 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"github.com/sirupsen/logrus"
 
+	"github.com/opd-ai/desktop-companion/lib/analytics"
+	"github.com/opd-ai/desktop-companion/lib/api"
 	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/dialog"
 	"github.com/opd-ai/desktop-companion/lib/monitoring"
 	"github.com/opd-ai/desktop-companion/lib/network"
+	"github.com/opd-ai/desktop-companion/lib/pack"
+	"github.com/opd-ai/desktop-companion/lib/persistence"
 	"github.com/opd-ai/desktop-companion/lib/platform"
+	"github.com/opd-ai/desktop-companion/lib/push"
+	"github.com/opd-ai/desktop-companion/lib/replay"
+	"github.com/opd-ai/desktop-companion/lib/stream"
 	"github.com/opd-ai/desktop-companion/lib/ui"
 )
 
@@ -30,7 +47,41 @@ var (
 	events        = flag.Bool("events", false, "Enable general dialog events system")
 	triggerEvent  = flag.String("trigger-event", "", "Manually trigger a specific event by name")
 	networkMode   = flag.Bool("network", false, "Enable multiplayer networking features")
+	safeMode      = flag.Bool("safe-mode", false, "Start without networking, dialog backends and events, offering to restore the last good save")
 	showNetwork   = flag.Bool("network-ui", false, "Show network overlay UI")
+	discovery     = flag.String("discovery", network.DiscoveryBoth, "Peer discovery: broadcast, mdns or both")
+	netFamily     = flag.String("net-family", network.AddressFamilyDual, "Network address family: dual, ipv4 or ipv6")
+	netBind       = flag.String("net-bind", "", "Bind networking to this IP address or interface name (default: all)")
+	netPorts      = flag.String("net-ports", "", "TCP port or range for peer connections, e.g. 47000-47010 (default: any free port)")
+	netBatch      = flag.Duration("net-batch", 50*time.Millisecond, "Send peer messages in batches this often, 0 to send each at once")
+	apiAddr       = flag.String("api-addr", "", "Enable the local control API on this address (e.g. :8765)")
+	twitchChannel = flag.String("twitch-channel", "", "Streamer mode: let this Twitch channel's chat trigger interactions")
+	twitchUser    = flag.String("twitch-user", "", "Twitch bot login for streamer mode (token from TWITCH_OAUTH_TOKEN; anonymous if empty)")
+	pushURL       = flag.String("push-url", "", "Push critical states and battle invitations to your phone: an ntfy topic URL or Gotify .../message URL (token from PUSH_TOKEN)")
+	trainDialog   = flag.Bool("train-dialog", false, "Train the markov dialog model from the character's corpus directory and exit")
+	benchDialog   = flag.Bool("bench-dialog", false, "Benchmark every dialog backend on contexts generated from the character, print a comparison and exit")
+	benchRounds   = flag.Int("bench-rounds", 3, "Times -bench-dialog runs each generated context")
+	scriptFile    = flag.String("script", "", "Replay a scripted interaction sequence with virtual time, print a pass/fail report and exit")
+	packageDir    = flag.String("package-character", "", "Bundle this character directory into a distributable .dcc pack and exit")
+	packageOut    = flag.String("o", "", "Output file for -package-character (default: <directory name>.dcc)")
+	builtinName   = flag.String("builtin", "", "Start a character built into this binary, by pack file or character name")
+	lang          = flag.String("lang", "", "Language for localized character cards, e.g. en or ja (default: OS locale)")
+	analyticsOn   = flag.Bool("analytics", false, "Record interaction analytics locally for the weekly summary (never uploaded)")
+	saveDir       = flag.String("save-dir", "", "Directory for game auto-saves (default: user config dir)")
+	profileName   = flag.String("profile", "", "User profile with its own saves, chats and memory, for several people sharing one character (default: the default profile)")
+	setup         = flag.Bool("setup", false, "Run the first-run setup wizard again")
+	eventFreq     = flag.Float64("event-frequency", 0, "Random event frequency multiplier, 0.1-3.0 (default 1.0)")
+	scale         = flag.Float64("scale", 0, "Character size as a multiple of the card's default size (default 1.0)")
+	tts           = flag.Bool("tts", false, "Read dialog aloud with the system text-to-speech")
+	metrics       = flag.Bool("metrics", false, "Show the FPS and memory overlay")
+	clipboardOn   = flag.Bool("clipboard", false, "React to copied text (opt-in; content is never stored or logged)")
+	clipboardList = flag.String("clipboard-kinds", "url,code", "Comma-separated clipboard reaction types: url, code, email, color, text")
+	daemonMode    = flag.Bool("daemon", false, "Run the companion headless so it keeps living with no window; frontends attach with -attach")
+	attachMode    = flag.Bool("attach", false, "Open a window attached to a running -daemon instead of running the character here")
+	socketPath    = flag.String("socket", "", "Unix socket for -daemon and -attach (default: companion.sock in the user runtime dir)")
+	botMode       = flag.Bool("bot", false, "Let the character act on its own: it plays with itself and, with -network, chats, battles and trades gifts with peers")
+	botArchetype  = flag.String("bot-archetype", "", "Bot personality: social, shy, playful, helper or balanced (default: the card's botPersonality)")
+	noUpdateCheck = flag.Bool("no-update-check", false, "Don't check GitHub for new versions (updates are only installed when you choose to)")
 )
 
 const appVersion = "1.0.0"
@@ -89,13 +140,36 @@ func getCaller() string {
 	return fn.Name()
 }
 
+// flagPassed reports whether the named flag was given on the command line,
+// as opposed to holding its default or a value from the settings file
+func flagPassed(fs *flag.FlagSet, name string) bool {
+	passed := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(runSimulateCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLintCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	caller := getCaller()
 	logrus.WithFields(logrus.Fields{
 		"caller": caller,
 	}).Info("Starting desktop companion application")
+	monitoring.SetCrashHandler(handleCrash)
 
+	// Saved settings become flag defaults, so the command line still wins
+	settings, settingsErr := loadSettings()
 	flag.Parse()
+	firstRun := *setup || (errors.Is(settingsErr, os.ErrNotExist) && flag.NFlag() == 0)
 
 	logrus.WithFields(logrus.Fields{
 		"caller": caller,
@@ -111,6 +185,32 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Use -help for usage information\n")
 		os.Exit(1)
 	}
+	if !network.IsValidDiscoveryMode(*discovery) {
+		fmt.Fprintf(os.Stderr, "Error: -discovery must be broadcast, mdns or both, got %q\n", *discovery)
+		os.Exit(1)
+	}
+	if !network.IsValidAddressFamily(*netFamily) {
+		fmt.Fprintf(os.Stderr, "Error: -net-family must be dual, ipv4 or ipv6, got %q\n", *netFamily)
+		os.Exit(1)
+	}
+	if _, _, err := network.ParsePortRange(*netPorts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -net-ports: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := clipboard.ParseKinds(*clipboardList); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -clipboard-kinds: %v\n", err)
+		os.Exit(1)
+	}
+	if *profileName != "" && *profileName != config.DefaultProfile {
+		if err := config.ValidateProfileName(*profileName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *daemonMode && *attachMode {
+		fmt.Fprintln(os.Stderr, "Error: -daemon and -attach cannot be used together; run the daemon and attach from a second process")
+		os.Exit(1)
+	}
 
 	if *version {
 		logrus.WithFields(logrus.Fields{
@@ -126,6 +226,18 @@ func main() {
 		"caller": caller,
 	}).Info("Debug logging configured")
 
+	if *safeMode {
+		applySafeModeFlags()
+	}
+
+	if *packageDir != "" {
+		if err := runPackageCharacter(*packageDir, *packageOut); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize performance profiler
 	profiler := monitoring.NewProfiler(50) // 50MB memory target
 
@@ -158,6 +270,24 @@ func main() {
 		}
 	}()
 
+	// Pick the language before loading so localized strings resolve correctly
+	character.SetLanguage(*lang)
+	logrus.WithFields(logrus.Fields{
+		"caller":   caller,
+		"language": character.Language(),
+	}).Info("Character language selected")
+
+	// Characters built into the binary stand in for a missing -character
+	if choices := selectBuiltinCharacter(); len(choices) > 0 {
+		runBuiltinPicker(choices, profiler)
+		return
+	}
+
+	if firstRun {
+		runOnboarding(settings, profiler)
+		return
+	}
+
 	// Load character configuration
 	card, characterDir := loadCharacterConfiguration()
 
@@ -166,6 +296,34 @@ func main() {
 		"characterDir": characterDir,
 	}).Info("Character configuration loaded")
 
+	if *trainDialog {
+		if err := runDialogTraining(card, characterDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *benchDialog {
+		if err := runDialogBenchmark(card, characterDir, *benchRounds, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *scriptFile != "" {
+		ok, err := runScript(card, characterDir, *scriptFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Record startup completion
 	profiler.RecordStartupComplete()
 
@@ -186,6 +344,21 @@ func main() {
 		}
 	}
 
+	if *daemonMode {
+		if err := runDaemon(card, characterDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *attachMode {
+		if err := runAttach(card, characterDir, profiler); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize application and show window
 	runDesktopApplication(card, characterDir, profiler)
 
@@ -210,6 +383,94 @@ func showVersionInfo() {
 	}).Info("Version information displayed")
 }
 
+// runDialogTraining trains the markov backend on the character's corpus
+// directory and saves the model, without starting the GUI.
+func runDialogTraining(card *character.CharacterCard, characterDir string) error {
+	if card.DialogBackend == nil || card.DialogBackend.Backends["markov_chain"] == nil {
+		return fmt.Errorf("character has no markov_chain dialog backend configured")
+	}
+	rawConfig := card.DialogBackend.Backends["markov_chain"]
+
+	var config dialog.MarkovConfig
+	if err := json.Unmarshal(rawConfig, &config); err != nil {
+		return fmt.Errorf("markov backend: %w", err)
+	}
+	if config.CorpusDir == "" {
+		return fmt.Errorf("markov_chain backend has no corpusDir configured")
+	}
+
+	// Initialize trains any new corpus text and saves the model
+	backend := dialog.NewMarkovChainBackend()
+	backend.SetBaseDir(characterDir)
+	if err := backend.Initialize(rawConfig); err != nil {
+		return fmt.Errorf("markov backend: %w", err)
+	}
+	stats := backend.LastCorpusStats()
+
+	fmt.Printf("Corpus: %d files, %d bytes trained (%d lines new)\n", stats.Files, stats.Bytes, stats.Lines)
+	fmt.Printf("Model: %d states\n", stats.States)
+	if stats.Rebuilt {
+		fmt.Println("Model was rebuilt from scratch")
+	}
+	if stats.CapReached {
+		fmt.Println("Warning: maxCorpusBytes reached, some corpus text was not trained")
+	}
+	return nil
+}
+
+// runScript replays a script against a fresh character without starting the
+// GUI and prints the report. It returns false when any step failed.
+func runScript(card *character.CharacterCard, characterDir, path string) (bool, error) {
+	steps, err := replay.LoadScript(path)
+	if err != nil {
+		return false, err
+	}
+
+	// Thousands of virtual minutes would otherwise bury the report in logs
+	if !*debug {
+		logrus.SetLevel(logrus.ErrorLevel)
+	}
+
+	char, err := character.New(card, characterDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to create character: %w", err)
+	}
+	if *eventFreq > 0 {
+		char.SetEventFrequencyMultiplier(*eventFreq)
+	}
+
+	report := replay.NewRunner(char).Run(steps)
+	report.Write(os.Stdout)
+	return report.OK(), nil
+}
+
+// logHook copies log entries to the character's log file, nil when the
+// file could not be opened
+var logHook *monitoring.LogFileHook
+
+// setupLogFile also writes logs to a rotating file for the character under
+// the user config directory. The returned func closes it.
+func setupLogFile(characterName string) func() {
+	dir, err := config.DefaultLogDir()
+	if err == nil {
+		logHook, err = monitoring.NewLogFileHook(filepath.Join(dir, monitoring.LogFileName(characterName)), 0, 0)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Warn("Logging to the terminal only")
+		return func() {}
+	}
+
+	logrus.AddHook(logHook)
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"path":   logHook.Path(),
+	}).Info("Logging to file")
+	return func() { logHook.Close() }
+}
+
 // configureDebugLogging sets up debug logging if enabled.
 func configureDebugLogging() {
 	caller := getCaller()
@@ -305,6 +566,17 @@ func resolveProjectRoot() string {
 		return execDir
 	}
 
+	// Inside a macOS .app bundle the executable is in Contents/MacOS and the
+	// assets in Contents/Resources
+	resourcesDir := filepath.Join(filepath.Dir(execDir), "Resources")
+	if _, err := os.Stat(filepath.Join(resourcesDir, "assets")); err == nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":      caller,
+			"projectRoot": resourcesDir,
+		}).Info("Project root found in app bundle resources")
+		return resourcesDir
+	}
+
 	// Fallback to executable directory (preserves existing behavior)
 	logrus.WithFields(logrus.Fields{
 		"caller":      caller,
@@ -404,6 +676,9 @@ func loadCharacterConfiguration() (*character.CharacterCard, string) {
 	}).Info("Loading character configuration")
 
 	absPath := resolveCharacterPath()
+	if pack.IsPack(absPath) {
+		absPath = extractCharacterPack(absPath)
+	}
 	card := loadAndValidateCharacter(absPath)
 	characterDir := filepath.Dir(absPath)
 
@@ -415,32 +690,238 @@ func loadCharacterConfiguration() (*character.CharacterCard, string) {
 	return card, characterDir
 }
 
-// runDesktopApplication creates and runs the desktop companion application.
-func runDesktopApplication(card *character.CharacterCard, characterDir string, profiler *monitoring.Profiler) {
+// extractCharacterPack verifies a .dcc pack and extracts it to the user
+// cache, returning the path of its character card
+func extractCharacterPack(packPath string) string {
 	caller := getCaller()
+
+	cacheDir, err := pack.DefaultCacheDir()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to locate pack cache")
+	}
+	cardPath, manifest, err := pack.Extract(packPath, cacheDir)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":   caller,
+			"packPath": packPath,
+			"error":    err.Error(),
+		}).Fatal("Failed to load character pack")
+	}
+
 	logrus.WithFields(logrus.Fields{
-		"caller":       caller,
-		"characterDir": characterDir,
-	}).Info("Starting desktop application")
+		"caller":   caller,
+		"packPath": packPath,
+		"name":     manifest.Name,
+		"files":    len(manifest.Files),
+		"cardPath": cardPath,
+	}).Info("Character pack verified and extracted")
+	return cardPath
+}
 
-	if err := checkDisplayAvailable(); err != nil {
+// runPackageCharacter bundles a character directory into a .dcc pack
+func runPackageCharacter(dir, output string) error {
+	if output == "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", dir, err)
+		}
+		if strings.EqualFold(filepath.Ext(absDir), ".json") {
+			absDir = filepath.Dir(absDir)
+		}
+		output = filepath.Base(absDir) + pack.Extension
+	}
+
+	manifest, err := pack.CreateFile(dir, output)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, f := range manifest.Files {
+		total += f.Size
+	}
+	fmt.Printf("Packed %s: %d files, %d KB -> %s\n", manifest.Name, len(manifest.Files), (total+1023)/1024, output)
+	return nil
+}
+
+// loadSettings reads the settings file and applies it to the flags. Errors
+// are returned so main can tell a first run (os.ErrNotExist) from the rest.
+func loadSettings() (*config.Settings, error) {
+	caller := getCaller()
+
+	path, err := config.DefaultSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.LoadSettings(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Warn("Ignoring unreadable settings file")
+		}
+		return nil, err
+	}
+
+	if err := settings.ApplyToFlags(flag.CommandLine); err != nil {
 		logrus.WithFields(logrus.Fields{
 			"caller": caller,
 			"error":  err.Error(),
-		}).Fatal("Cannot run desktop application - display not available")
+		}).Warn("Some settings could not be applied")
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"caller": caller,
-	}).Debug("Display availability check passed")
+		"path":   path,
+	}).Info("Settings loaded")
+	return settings, nil
+}
 
-	myApp := app.NewWithID("com.opdai.desktop-companion")
+// runOnboarding shows the first-run wizard, then starts the companion with
+// the chosen settings in the same Fyne app.
+func runOnboarding(settings *config.Settings, profiler *monitoring.Profiler) {
+	caller := getCaller()
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+	}).Info("Starting first-run setup wizard")
+
+	defaults := config.Settings{Character: resolveCharacterPath()}
+	if settings != nil {
+		defaults = *settings
+	}
+	if defaults.SaveDir == "" {
+		defaults.SaveDir, _ = config.DefaultSaveDir()
+	}
+
+	myApp := newFyneApp()
+	cleanup := func() {}
+
+	wizard := ui.NewOnboardingWizard(myApp, findInstalledCharacters(), defaults, func(result ui.OnboardingResult) {
+		applyOnboardingResult(result)
+
+		card, characterDir := loadCharacterConfiguration()
+		char := createCharacterInstance(card, characterDir)
+		cleanup = startCompanion(myApp, char, characterDir, profiler)
+	})
+	wizard.Show()
+	myApp.Run()
+	cleanup()
 
 	logrus.WithFields(logrus.Fields{
 		"caller": caller,
-		"appID":  "com.opdai.desktop-companion",
-	}).Info("Fyne application created")
+	}).Info("Desktop application completed")
+}
+
+// applyOnboardingResult saves the wizard's settings, creates the desktop
+// shortcut if asked and applies the settings to the flags.
+func applyOnboardingResult(result ui.OnboardingResult) {
+	caller := getCaller()
+
+	if path, err := config.DefaultSettingsPath(); err == nil {
+		err = result.Settings.Save(path)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Warn("Failed to save settings, the wizard will show again next launch")
+		}
+	}
+
+	if result.CreateShortcut {
+		if err := createShortcut(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Warn("Failed to create desktop shortcut")
+		}
+	}
+
+	if err := result.Settings.ApplyToFlags(flag.CommandLine); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Some settings could not be applied")
+	}
+}
+
+// createShortcut puts a launcher for this executable on the desktop
+func createShortcut() error {
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+	path, err := platform.CreateDesktopShortcut("Desktop Companion", execPath, nil)
+	if err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"path":   path,
+	}).Info("Desktop shortcut created")
+	return nil
+}
+
+// findInstalledCharacters lists the character cards under assets/characters
+// for the onboarding wizard. Cards that fail to load are skipped.
+func findInstalledCharacters() []ui.OnboardingCharacter {
+	caller := getCaller()
+
+	pattern := filepath.Join(resolveProjectRoot(), "assets", "characters", "*", "character.json")
+	paths, _ := filepath.Glob(pattern)
+
+	characters := make([]ui.OnboardingCharacter, 0, len(paths))
+	for _, path := range paths {
+		card, err := character.LoadCard(path)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"path":   path,
+				"error":  err.Error(),
+			}).Debug("Skipping character that failed to load")
+			continue
+		}
+
+		option := ui.OnboardingCharacter{
+			Name:        card.Name,
+			Description: card.Description,
+			CardPath:    path,
+		}
+		if idle := card.Animations["idle"]; idle != "" {
+			option.PreviewPath = filepath.Join(filepath.Dir(path), idle)
+		}
+		characters = append(characters, option)
+	}
 
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+		"count":  len(characters),
+	}).Info("Installed characters found")
+	return characters
+}
+
+// runDesktopApplication creates and runs the desktop companion application.
+func runDesktopApplication(card *character.CharacterCard, characterDir string, profiler *monitoring.Profiler) {
+	caller := getCaller()
+	logrus.WithFields(logrus.Fields{
+		"caller":       caller,
+		"characterDir": characterDir,
+	}).Info("Starting desktop application")
+
+	closeLog := setupLogFile(card.Name)
+	defer closeLog()
+	endSession := beginSession(card.Name)
+	defer endSession()
+
+	if *safeMode {
+		applySafeModeCard(card)
+	}
+
+	myApp := newFyneApp()
 	char := createCharacterInstance(card, characterDir)
 
 	if *triggerEvent != "" {
@@ -463,23 +944,10 @@ func runDesktopApplication(card *character.CharacterCard, characterDir string, p
 		return
 	}
 
-	networkManager := setupNetworkManager(char)
-	if networkManager != nil {
-		defer func() {
-			logrus.WithFields(logrus.Fields{
-				"caller": caller,
-			}).Info("Stopping network manager")
-			networkManager.Stop()
-		}()
-	}
-
-	window := createDesktopWindow(myApp, char, profiler, networkManager)
-
-	logrus.WithFields(logrus.Fields{
-		"caller": caller,
-	}).Info("Desktop window created, showing application")
+	cleanup := startCompanion(myApp, char, characterDir, profiler)
+	defer cleanup()
+	defer monitoring.RecoverCrash() // Runs before cleanup, which a crashed session must skip
 
-	window.Show()
 	myApp.Run()
 
 	logrus.WithFields(logrus.Fields{
@@ -487,40 +955,458 @@ func runDesktopApplication(card *character.CharacterCard, characterDir string, p
 	}).Info("Desktop application completed")
 }
 
-// createCharacterInstance creates a new character from the given card and directory.
-func createCharacterInstance(card *character.CharacterCard, characterDir string) *character.Character {
+// newFyneApp checks for a display and creates the Fyne application.
+// Fyne can only run one app per process, so the onboarding wizard and the
+// companion window share it.
+func newFyneApp() fyne.App {
 	caller := getCaller()
-	logrus.WithFields(logrus.Fields{
-		"caller":        caller,
-		"characterDir":  characterDir,
-		"characterName": card.Name,
-	}).Info("Creating character instance")
 
-	char, err := character.New(card, characterDir)
-	if err != nil {
+	if err := checkDisplayAvailable(); err != nil {
 		logrus.WithFields(logrus.Fields{
-			"caller":        caller,
-			"characterDir":  characterDir,
-			"characterName": card.Name,
-			"error":         err.Error(),
-		}).Fatal("Failed to create character")
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Cannot run desktop application - display not available")
 	}
 
 	logrus.WithFields(logrus.Fields{
-		"caller":        caller,
-		"characterName": card.Name,
-	}).Info("Character instance created successfully")
+		"caller": caller,
+	}).Debug("Display availability check passed")
 
-	return char
-}
+	myApp := app.NewWithID("com.opdai.desktop-companion")
 
-// setupNetworkManager creates and starts the network manager if networking is enabled.
-func setupNetworkManager(char *character.Character) *network.NetworkManager {
-	caller := getCaller()
 	logrus.WithFields(logrus.Fields{
-		"caller":      caller,
-		"networkMode": *networkMode,
-	}).Info("Setting up network manager")
+		"caller": caller,
+		"appID":  "com.opdai.desktop-companion",
+	}).Info("Fyne application created")
+
+	return myApp
+}
+
+// startCompanion starts the optional services and shows the character window.
+// The returned func stops the services of whichever character is running,
+// after switches, and must be called after the app exits.
+func startCompanion(myApp fyne.App, char *character.Character, characterDir string, profiler *monitoring.Profiler) func() {
+	activeCompanion.Lock()
+	activeCompanion.stop = launchCompanion(myApp, char, characterDir, profiler)
+	activeCompanion.Unlock()
+	return stopActiveCompanion
+}
+
+// launchCompanion starts one character's services and window, returning the
+// func that stops them
+func launchCompanion(myApp fyne.App, char *character.Character, characterDir string, profiler *monitoring.Profiler) func() {
+	caller := getCaller()
+	// Runs last, after everything else has saved and stopped
+	cleanups := []func(){relaunchIfSwitching}
+
+	collector := setupAnalytics(char)
+	if collector != nil {
+		cleanups = append(cleanups, func() {
+			if err := collector.Stop(); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"caller": caller,
+					"error":  err.Error(),
+				}).Warn("Failed to save analytics")
+			}
+		})
+	}
+
+	networkManager := setupNetworkManager(char)
+	if networkManager != nil {
+		cleanups = append(cleanups, func() {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Stopping network manager")
+			networkManager.Stop()
+		})
+	}
+
+	apiServer := setupAPIServer(char)
+	if apiServer != nil {
+		cleanups = append(cleanups, func() {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Stopping API server")
+			if err := apiServer.Stop(); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"caller": caller,
+					"error":  err.Error(),
+				}).Warn("API server did not stop cleanly")
+			}
+		})
+	}
+
+	if *eventFreq > 0 {
+		char.SetEventFrequencyMultiplier(*eventFreq)
+	}
+	setupSizeStore(char)
+	setupRelationshipTuningStore(char)
+
+	window := createDesktopWindow(myApp, char, profiler, networkManager)
+	if logHook != nil {
+		window.SetLogFile(logHook.Path())
+	}
+	setupSettings(window)
+	setupProfile(myApp, char, window)
+	setupCharacterSwitcher(myApp, window, characterDir, profiler)
+	setupAwayDetection(char, window)
+	setupWidgetMode(char, window)
+	if networkManager != nil {
+		setupPackSharing(window, characterDir)
+	}
+
+	saveManager := setupAutoSave(char, window)
+	if saveManager != nil {
+		cleanups = append(cleanups, func() {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Saving game before exit")
+			finalSave(char, saveManager)
+			saveManager.Close()
+		})
+	}
+
+	pusher := setupPush(char, window)
+	if pusher != nil {
+		cleanups = append(cleanups, func() {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Stopping push notifications")
+			pusher.Stop()
+		})
+	}
+
+	twitchBot := setupStreamerMode(char, window)
+	if twitchBot != nil {
+		cleanups = append(cleanups, func() {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Stopping streamer mode")
+			twitchBot.Stop()
+		})
+	}
+
+	if stopBot := setupBot(char, networkManager); stopBot != nil {
+		cleanups = append(cleanups, stopBot)
+	}
+
+	if stopUpdates := setupUpdateCheck(window); stopUpdates != nil {
+		cleanups = append(cleanups, stopUpdates)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+	}).Info("Desktop window created, showing application")
+
+	window.Show()
+
+	// Stop in reverse order, like the defers this replaces
+	return func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+}
+
+// createCharacterInstance creates a new character from the given card and directory.
+func createCharacterInstance(card *character.CharacterCard, characterDir string) *character.Character {
+	caller := getCaller()
+	logrus.WithFields(logrus.Fields{
+		"caller":        caller,
+		"characterDir":  characterDir,
+		"characterName": card.Name,
+	}).Info("Creating character instance")
+
+	char, err := character.New(card, characterDir)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":        caller,
+			"characterDir":  characterDir,
+			"characterName": card.Name,
+			"error":         err.Error(),
+		}).Fatal("Failed to create character")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":        caller,
+		"characterName": card.Name,
+	}).Info("Character instance created successfully")
+
+	return char
+}
+
+// setupAnalytics starts local interaction analytics if -analytics is set.
+// Data stays in the profile's memory directory and is saved every few minutes.
+func setupAnalytics(char *character.Character) *analytics.Collector {
+	caller := getCaller()
+
+	if !*analyticsOn {
+		return nil
+	}
+
+	profile, err := activeProfile()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Analytics unavailable, continuing without it")
+		return nil
+	}
+
+	path := analytics.PathIn(profile.MemoryDir(), char.GetName())
+	collector, err := analytics.NewCollector(path)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Analytics unavailable, continuing without it")
+		return nil
+	}
+
+	collector.Start(5 * time.Minute)
+	char.SetAnalytics(collector)
+
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+		"path":   path,
+	}).Info("Local analytics enabled")
+
+	return collector
+}
+
+// setupRelationshipTuningStore restores the jealousy and crisis tuning the
+// user chose for this character and remembers future changes
+func setupRelationshipTuningStore(char *character.Character) {
+	caller := getCaller()
+
+	profile, err := activeProfile()
+	if err == nil {
+		path := filepath.Join(profile.MemoryDir(), character.RelationshipTuningFileName)
+		var store *character.RelationshipTuningStore
+		if store, err = character.NewRelationshipTuningStore(path); err == nil {
+			char.SetRelationshipTuningStore(store)
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Debug("Relationship tuning store loaded")
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+		"error":  err.Error(),
+	}).Warn("Saved relationship tuning unavailable, using personality defaults")
+}
+
+// setupSizeStore applies -scale, restores the size the user last resized
+// this character to and remembers future Ctrl+scroll or pinch resizes.
+func setupSizeStore(char *character.Character) {
+	caller := getCaller()
+
+	// -scale is the size for characters that were never resized by hand;
+	// it's applied before the store is attached so it isn't saved as theirs
+	if *scale > 0 {
+		char.Resize(int(math.Round(float64(char.GetCard().Behavior.DefaultSize) * *scale)))
+	}
+
+	path, err := character.DefaultSizeStorePath()
+	if err == nil {
+		var store *character.SizeStore
+		if store, err = character.NewSizeStore(path); err == nil {
+			char.SetSizeStore(store)
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"size":   char.GetSize(),
+			}).Debug("Character size store loaded")
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+		"error":  err.Error(),
+	}).Warn("Saved character size unavailable, using card default")
+}
+
+// setupSettings enables the in-app Settings dialog. It starts from the
+// settings file with the flag defaults on top, so the dialog shows what is
+// in effect. Flags passed on the command line are one-offs: they apply to
+// this session but stay out of the dialog, so saving doesn't persist them.
+func setupSettings(window *ui.DesktopWindow) {
+	caller := getCaller()
+
+	path, err := config.DefaultSettingsPath()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("No settings file location, Settings menu disabled")
+		window.SetClipboardReactions(*clipboardOn, clipboardKinds())
+		return
+	}
+
+	settings, err := config.LoadSettings(path)
+	if err != nil {
+		settings = &config.Settings{}
+	}
+	passed := func(name string) bool { return flagPassed(flag.CommandLine, name) }
+	if !passed("debug") {
+		settings.Debug = *debug
+	}
+	if !passed("game") {
+		settings.GameMode = *gameMode
+	}
+	if !passed("stats") {
+		settings.ShowStats = *showStats
+	}
+	if !passed("network") {
+		settings.Network = *networkMode
+	}
+	if !passed("network-ui") {
+		settings.NetworkUI = *showNetwork
+	}
+	if !passed("event-frequency") {
+		settings.EventFrequency = *eventFreq
+	}
+	if !passed("scale") {
+		settings.Scale = *scale
+	}
+	if !passed("tts") {
+		settings.TTS = *tts
+	}
+	if !passed("metrics") {
+		settings.Metrics = *metrics
+	}
+	if !passed("push-url") {
+		settings.PushURL = *pushURL
+	}
+	if !passed("clipboard") {
+		settings.Clipboard = *clipboardOn
+	}
+	if !passed("clipboard-kinds") {
+		settings.ClipboardKinds = nil
+		for _, kind := range clipboardKinds() {
+			settings.ClipboardKinds = append(settings.ClipboardKinds, string(kind))
+		}
+	}
+
+	// SetSettings also starts clipboard reactions when enabled
+	window.SetSettings(*settings, path)
+	setupAutostart(window)
+
+	// Then the one-offs that live in the window
+	if passed("tts") {
+		window.SetSpeech(*tts)
+	}
+	if passed("metrics") {
+		window.SetMetricsVisible(*metrics)
+	}
+	if passed("clipboard") || passed("clipboard-kinds") {
+		window.SetClipboardReactions(*clipboardOn, clipboardKinds())
+	}
+}
+
+// setupAutostart offers "Start with system" in the Settings dialog. The
+// entry runs this executable without flags, since the settings file holds
+// the options. Every character shares one entry, so only one companion
+// greets the user at login.
+func setupAutostart(window *ui.DesktopWindow) {
+	caller := getCaller()
+
+	execPath, err := os.Executable()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Failed to get executable path, autostart disabled")
+		return
+	}
+	autostart, err := platform.NewAutostart("ai.opd.dds", "Desktop Companion", execPath, nil)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Debug("Autostart not available")
+		return
+	}
+	window.SetAutostart(autostart)
+}
+
+// clipboardKinds returns the -clipboard-kinds allowlist, validated in main
+func clipboardKinds() []clipboard.Kind {
+	kinds, _ := clipboard.ParseKinds(*clipboardList)
+	return kinds
+}
+
+// setupAutoSave restores the last saved game and auto-saves game progress to
+// the profile's save directory at the card's autoSaveInterval. Returns nil
+// outside game mode.
+func setupAutoSave(char *character.Character, window *ui.DesktopWindow) *persistence.SaveManager {
+	caller := getCaller()
+	if !*gameMode || char.GetGameState() == nil {
+		return nil
+	}
+
+	// A -save-dir is shared by all profiles, so each named profile gets a
+	// subdirectory of it
+	profile, err := activeProfile()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("No save directory available, game progress will not be saved")
+		return nil
+	}
+	dir := profile.SaveDir()
+	if *saveDir != "" {
+		dir = profile.SaveDirUnder(*saveDir)
+	}
+
+	saveManager := persistence.NewSaveManager(dir)
+	if saveManager.HasSave(char.GetName()) {
+		restoreSave(char, saveManager)
+	}
+	if *safeMode {
+		offerLastGoodSave(char, saveManager, window)
+	}
+	emergencySave = func() error {
+		data := char.SaveData()
+		if data == nil {
+			return fmt.Errorf("no game state to save")
+		}
+		return saveManager.SaveGameState(data.CharacterName, data)
+	}
+
+	// The persistence and UI status enums share the same order. A daemon
+	// has no window to show the status in.
+	if window != nil {
+		showStatus := window.SetSaveStatusCallback()
+		saveManager.SetStatusCallback(func(status persistence.SaveStatus, message string) {
+			showStatus(ui.SaveStatus(status), message)
+		})
+	}
+
+	interval := 5 * time.Minute
+	if rules := char.GetCard().GameRules; rules != nil && rules.AutoSaveInterval > 0 {
+		interval = time.Duration(rules.AutoSaveInterval) * time.Second
+	}
+	saveManager.EnableAutoSave(interval, char.SaveData)
+
+	logrus.WithFields(logrus.Fields{
+		"caller":   caller,
+		"saveDir":  dir,
+		"interval": interval,
+	}).Info("Auto-save enabled")
+	return saveManager
+}
+
+// setupNetworkManager creates and starts the network manager if networking is enabled.
+func setupNetworkManager(char *character.Character) *network.NetworkManager {
+	caller := getCaller()
+	logrus.WithFields(logrus.Fields{
+		"caller":      caller,
+		"networkMode": *networkMode,
+	}).Info("Setting up network manager")
 
 	if !*networkMode {
 		logrus.WithFields(logrus.Fields{
@@ -534,6 +1420,7 @@ func setupNetworkManager(char *character.Character) *network.NetworkManager {
 	}).Info("Network mode enabled, configuring network manager")
 
 	networkConfig := buildNetworkConfig(char)
+	networkConfig.Identity = loadPeerIdentity(char.GetName())
 
 	networkManager, err := network.NewNetworkManager(*networkConfig)
 	if err != nil {
@@ -543,6 +1430,8 @@ func setupNetworkManager(char *character.Character) *network.NetworkManager {
 		}).Fatal("Failed to create network manager")
 	}
 
+	networkManager.SetPermissionStore(loadPeerPermissions())
+
 	logrus.WithFields(logrus.Fields{
 		"caller": caller,
 	}).Info("Network manager created, starting networking")
@@ -564,11 +1453,260 @@ func setupNetworkManager(char *character.Character) *network.NetworkManager {
 	logrus.WithFields(logrus.Fields{
 		"caller":    caller,
 		"networkID": networkConfig.NetworkID,
+		"peerID":    networkManager.GetPeerID(),
 	}).Info("Network manager started successfully")
 
 	return networkManager
 }
 
+// loadPeerPermissions opens the per-peer permissions file next to the
+// settings file. Failures fall back to in-memory permissions so networking
+// still starts.
+func loadPeerPermissions() *network.PermissionStore {
+	caller := getCaller()
+
+	path := ""
+	if settingsPath, err := config.DefaultSettingsPath(); err == nil {
+		path = filepath.Join(filepath.Dir(settingsPath), network.PermissionsFileName)
+	}
+
+	store, err := network.NewPermissionStore(path)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"path":   path,
+			"error":  err.Error(),
+		}).Warn("Failed to load peer permissions, starting with defaults")
+		store, _ = network.NewPermissionStore("")
+	}
+	return store
+}
+
+// loadPeerIdentity opens the character's identity key next to the settings
+// file, creating it on first run. Failures return nil, so the network manager
+// uses a key that only lasts until exit.
+func loadPeerIdentity(characterName string) ed25519.PrivateKey {
+	settingsPath, err := config.DefaultSettingsPath()
+	if err != nil {
+		return nil
+	}
+
+	path := filepath.Join(filepath.Dir(settingsPath), network.IdentityFileName(characterName))
+	key, err := network.LoadOrCreateIdentity(path)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"path":   path,
+			"error":  err.Error(),
+		}).Warn("Failed to load peer identity, using a temporary one")
+		return nil
+	}
+	return key
+}
+
+// setupAPIServer starts the local REST/WebSocket control API if -api-addr is set.
+func setupAPIServer(char *character.Character) *api.Server {
+	caller := getCaller()
+
+	if *apiAddr == "" {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+		}).Debug("API address not set, skipping API server setup")
+		return nil
+	}
+
+	return startAPIServer(char, *apiAddr)
+}
+
+// startAPIServer serves the control API for char on addr, protected by the
+// token in the user config dir. Failures are fatal.
+func startAPIServer(char *character.Character, addr string) *api.Server {
+	caller := getCaller()
+
+	logrus.WithFields(logrus.Fields{
+		"caller":  caller,
+		"apiAddr": addr,
+	}).Info("Setting up API server")
+
+	apiServer, err := api.NewServer(addr, char)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to create API server")
+	}
+
+	// Clients read the bearer token from the config dir, so other users on
+	// the machine and web pages can't drive the companion
+	tokenPath, err := api.DefaultTokenPath()
+	if err == nil {
+		var token string
+		if token, err = api.LoadOrCreateToken(tokenPath); err == nil {
+			apiServer.SetToken(token)
+		}
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to set up API token")
+	}
+
+	if err := apiServer.Start(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to start API server")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":    caller,
+		"addr":      apiServer.Addr(),
+		"tokenFile": tokenPath,
+	}).Info("API server started successfully")
+
+	return apiServer
+}
+
+// setupStreamerMode connects to Twitch chat if -twitch-channel is set.
+// Responses to chat commands are shown in the dialog bubble with the viewer's name.
+func setupStreamerMode(char *character.Character, window *ui.DesktopWindow) *stream.TwitchBot {
+	caller := getCaller()
+
+	if *twitchChannel == "" {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+		}).Debug("Twitch channel not set, skipping streamer mode")
+		return nil
+	}
+
+	config := stream.TwitchConfig{
+		Channel:    *twitchChannel,
+		Username:   *twitchUser,
+		OAuthToken: os.Getenv("TWITCH_OAUTH_TOKEN"),
+	}
+
+	bot, err := stream.NewTwitchBot(config, char, window.ShowAttributedDialog)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to configure streamer mode")
+	}
+
+	bot.Start()
+
+	logrus.WithFields(logrus.Fields{
+		"caller":    caller,
+		"channel":   *twitchChannel,
+		"anonymous": *twitchUser == "",
+	}).Info("Streamer mode started")
+
+	return bot
+}
+
+// setupBot starts the autonomous bot if -bot is set. It acts on its own
+// goroutine; the returned func stops it.
+func setupBot(char *character.Character, networkManager *network.NetworkManager) func() {
+	caller := getCaller()
+
+	if !*botMode {
+		return nil
+	}
+
+	var transport character.BotTransport
+	if networkManager != nil {
+		transport = networkManager
+	}
+	controller, err := char.NewBot(transport, *botArchetype)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to configure bot")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go controller.Run(ctx, character.BotTickInterval)
+
+	logrus.WithFields(logrus.Fields{
+		"caller":    caller,
+		"archetype": *botArchetype,
+		"network":   transport != nil,
+	}).Info("Bot started")
+
+	return cancel
+}
+
+// setupPush sends critical states and battle invitations to the user's phone
+// if -push-url is set
+func setupPush(char *character.Character, window *ui.DesktopWindow) *push.Pusher {
+	caller := getCaller()
+
+	if *pushURL == "" {
+		return nil
+	}
+
+	pusher, err := push.NewPusher(push.Config{
+		URL:   *pushURL,
+		Token: os.Getenv("PUSH_TOKEN"),
+	}, char)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Fatal("Failed to configure push notifications")
+	}
+
+	pusher.Start()
+	if window != nil {
+		window.SetPusher(pusher)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":   caller,
+		"provider": pusher.Provider(),
+	}).Info("Push notifications enabled")
+
+	return pusher
+}
+
+// setupAwayDetection lets the character nap while the user is away from the
+// computer and take over the screen after a long idle, for cards that
+// enable either
+func setupAwayDetection(char *character.Character, window *ui.DesktopWindow) {
+	card := char.GetCard()
+	if !card.HasAwayDetection() && !card.HasScreensaver() {
+		return
+	}
+	window.StartAwayDetection(platform.NewIdleDetector())
+
+	fields := logrus.Fields{
+		"caller": getCaller(),
+	}
+	if card.HasAwayDetection() {
+		fields["idleAfter"] = card.Away.GetIdleThreshold().String()
+	}
+	if card.HasScreensaver() {
+		fields["screensaverAfter"] = card.Screensaver.GetIdleThreshold().String()
+	}
+	logrus.WithFields(fields).Info("Away detection enabled")
+}
+
+// setupWidgetMode starts the window as a compact home-screen widget for
+// cards whose mobile platform config asks for it
+func setupWidgetMode(char *character.Character, window *ui.DesktopWindow) {
+	config := character.NewPlatformAwareLoader().GetPlatformConfig(char.GetCard())
+	if config == nil || config.WindowMode != character.WindowModeWidget {
+		return
+	}
+	window.EnableWidgetMode()
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+	}).Info("Widget mode enabled")
+}
+
 // buildNetworkConfig creates network configuration using character settings and defaults.
 func buildNetworkConfig(char *character.Character) *network.NetworkManagerConfig {
 	caller := getCaller()
@@ -580,6 +1718,12 @@ func buildNetworkConfig(char *character.Character) *network.NetworkManagerConfig
 		DiscoveryPort: 8080,
 		MaxPeers:      10,
 		NetworkID:     "default-network",
+		Discovery:     *discovery,
+		Capabilities:  networkCapabilities(char.GetCard()),
+		AddressFamily: *netFamily,
+		BindAddress:   *netBind,
+		TCPPortRange:  *netPorts,
+		BatchInterval: *netBatch,
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -637,6 +1781,31 @@ func buildNetworkConfig(char *character.Character) *network.NetworkManagerConfig
 	return networkConfig
 }
 
+// networkCapabilities lists what the character can do with peers, advertised
+// in mDNS TXT records
+func networkCapabilities(card *character.CharacterCard) []string {
+	var capabilities []string
+	if card == nil {
+		return capabilities
+	}
+	if card.HasMultiplayer() && card.Multiplayer.BotCapable {
+		capabilities = append(capabilities, "bot")
+	}
+	if card.HasGameFeatures() {
+		capabilities = append(capabilities, "game")
+	}
+	if card.HasBattleSystem() {
+		capabilities = append(capabilities, "battle")
+	}
+	if card.HasRomanceFeatures() {
+		capabilities = append(capabilities, "romance")
+	}
+	if card.HasDialogBackend() {
+		capabilities = append(capabilities, "chat")
+	}
+	return capabilities
+}
+
 // createDesktopWindow creates the desktop window with all required components.
 func createDesktopWindow(myApp fyne.App, char *character.Character, profiler *monitoring.Profiler, networkManager *network.NetworkManager) *ui.DesktopWindow {
 	caller := getCaller()
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/pack"
+	"github.com/opd-ai/desktop-companion/lib/ui"
+)
+
+// setupPackSharing lets networked users share their characters. Packs we
+// offer are built fresh into the pack cache; packs peers send us land in
+// the characters directory, ready for -character.
+func setupPackSharing(window *ui.DesktopWindow, characterDir string) {
+	dir, err := config.DefaultCharactersDir()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Warn("Character sharing unavailable")
+		return
+	}
+	window.SetupPackSharing(dir, func() (string, error) {
+		return buildSharedPack(characterDir)
+	})
+}
+
+// buildSharedPack packs the running character for a peer and returns the
+// pack's path
+func buildSharedPack(characterDir string) (string, error) {
+	cacheDir, err := pack.DefaultCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sharedDir := filepath.Join(cacheDir, "shared")
+	if err := os.MkdirAll(sharedDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", sharedDir, err)
+	}
+
+	output := filepath.Join(sharedDir, filepath.Base(characterDir)+pack.Extension)
+	if _, err := pack.CreateFile(characterDir, output); err != nil {
+		return "", err
+	}
+	return output, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+// profile.go lets several people on one machine keep separate relationships
+// with the same character. Each -profile has its own saves, chat exports and
+// memory; switching from the tray menu restarts the companion as the chosen
+// profile once the current one has saved and shut down.
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/ui"
+)
+
+// relaunchProfile is the profile to restart as after the app exits, empty
+// to just exit
+var relaunchProfile string
+
+// activeProfile returns the profile chosen with -profile
+func activeProfile() (config.Profile, error) {
+	return config.NewProfile(*profileName)
+}
+
+// applyProfile tells the character which profile it is talking to, so
+// dialog can address them
+func applyProfile(char *character.Character) (config.Profile, error) {
+	profile, err := activeProfile()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Warn("Profile unavailable, using shared defaults")
+		return profile, err
+	}
+	if !profile.IsDefault() {
+		char.SetProfileName(profile.Name)
+	}
+	return profile, nil
+}
+
+// setupProfile applies the profile to the character and window and offers
+// the other profiles in the tray menu
+func setupProfile(myApp fyne.App, char *character.Character, window *ui.DesktopWindow) {
+	caller := getCaller()
+
+	profile, err := applyProfile(char)
+	if err != nil {
+		return
+	}
+	if !profile.IsDefault() {
+		window.SetChatExportDir(profile.ChatDir())
+	}
+
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Could not list profiles")
+	}
+	window.SetupProfileTray(profile.Name, profiles, func(name string) {
+		switchProfile(myApp, name)
+	})
+
+	logrus.WithFields(logrus.Fields{
+		"caller":  caller,
+		"profile": profile.Name,
+	}).Info("Profile selected")
+}
+
+// switchProfile creates the profile if needed, remembers it for the next
+// launch and quits so the companion restarts as it
+func switchProfile(myApp fyne.App, name string) {
+	caller := getCaller()
+
+	profile, err := config.NewProfile(name)
+	if err == nil {
+		err = profile.Create()
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":  caller,
+			"profile": name,
+			"error":   err.Error(),
+		}).Warn("Cannot switch profile")
+		return
+	}
+
+	if path, err := config.DefaultSettingsPath(); err == nil {
+		settings, loadErr := config.LoadSettings(path)
+		if loadErr != nil {
+			settings = &config.Settings{}
+		}
+		settings.Profile = profile.Name
+		if profile.IsDefault() {
+			settings.Profile = ""
+		}
+		if err := settings.Save(path); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Warn("Could not remember the profile for the next launch")
+		}
+	}
+
+	relaunchProfile = profile.Name
+	myApp.Quit()
+}
+
+// relaunchIfSwitching restarts the companion as the profile chosen in the
+// tray menu. Call it after everything has been saved and stopped.
+func relaunchIfSwitching() {
+	if relaunchProfile == "" {
+		return
+	}
+	caller := getCaller()
+
+	execPath, err := os.Executable()
+	if err == nil {
+		cmd := exec.Command(execPath, relaunchArgs(os.Args[1:], relaunchProfile)...)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		err = cmd.Start()
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":  caller,
+			"profile": relaunchProfile,
+			"error":   err.Error(),
+		}).Error("Failed to restart with the new profile")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":  caller,
+		"profile": relaunchProfile,
+	}).Info("Restarting with the new profile")
+}
+
+// relaunchArgs returns args with any -profile replaced by profile
+func relaunchArgs(args []string, profile string) []string {
+	out := make([]string, 0, len(args)+2)
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		switch {
+		case !strings.HasPrefix(args[i], "-"):
+		case name == "profile":
+			i++ // Skip the value too
+			continue
+		case strings.HasPrefix(name, "profile="):
+			continue
+		}
+		out = append(out, args[i])
+	}
+	return append(out, "-profile", profile)
+}
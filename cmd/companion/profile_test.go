@@ -0,0 +1,23 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelaunchArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-game"}, []string{"-game", "-profile", "kid"}},
+		{[]string{"-profile", "mum", "-game"}, []string{"-game", "-profile", "kid"}},
+		{[]string{"--profile=mum", "-stats"}, []string{"-stats", "-profile", "kid"}},
+		{[]string{"-character", "profile", "-profile=mum"}, []string{"-character", "profile", "-profile", "kid"}},
+	}
+	for _, tt := range tests {
+		if got := relaunchArgs(tt.args, "kid"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("relaunchArgs(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
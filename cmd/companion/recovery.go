@@ -0,0 +1,220 @@
+package main
+
+// recovery.go keeps a crash from costing game progress or the next start.
+// A panic in the UI, the game loop or a network handler writes an emergency
+// save before exiting, each clean exit keeps a copy of the save as the last
+// good one, and -safe-mode starts with the riskier subsystems off and offers
+// to go back to that copy.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	runtimedebug "runtime/debug"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+	"github.com/opd-ai/desktop-companion/lib/ui"
+)
+
+// emergencySave saves the game when the companion crashes, nil without
+// game mode
+var emergencySave func() error
+
+// crashExit ends the process after a crash; tests replace it
+var crashExit = os.Exit
+
+// applySafeModeFlags turns off networking, the control API, streamer mode
+// and push notifications, whatever the command line or settings asked for
+func applySafeModeFlags() {
+	*networkMode = false
+	*showNetwork = false
+	*events = false
+	*triggerEvent = ""
+	*apiAddr = ""
+	*twitchChannel = ""
+	*pushURL = ""
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+	}).Warn("Safe mode: networking, dialog backends and events are disabled")
+}
+
+// applySafeModeCard turns off the card's dialog backend and events
+func applySafeModeCard(card *character.CharacterCard) {
+	if card.DialogBackend != nil {
+		card.DialogBackend.Enabled = false
+	}
+	card.RandomEvents = nil
+	card.RomanceEvents = nil
+	card.GeneralEvents = nil
+}
+
+// beginSession notes that the companion is running, warning when the last
+// session crashed. The returned func records a clean shutdown.
+func beginSession(characterName string) func() {
+	caller := getCaller()
+
+	dir, err := config.DefaultLogDir()
+	if err != nil {
+		return func() {}
+	}
+	name := strings.TrimSuffix(monitoring.LogFileName(characterName), ".log") + ".running"
+	marker := monitoring.NewCrashMarker(filepath.Join(dir, name))
+
+	crashed, err := marker.Begin()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Cannot detect crashes this session")
+	}
+	if crashed && !*safeMode {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+		}).Warn("The last session did not shut down cleanly")
+		fmt.Fprintln(os.Stderr, "The companion did not shut down cleanly last time. If it keeps crashing, start it with -safe-mode.")
+	}
+
+	return func() {
+		if err := marker.End(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Warn("Failed to record clean shutdown")
+		}
+	}
+}
+
+// handleCrash is the crash handler for monitoring.RecoverCrash, which is
+// deferred around the UI, the daemon and the goroutines running game logic
+// and network handlers: it logs a panic with its stack, writes an emergency
+// save and exits, skipping the normal shutdown so a crashed session's save
+// is never kept as the last good one
+func handleCrash(r any) {
+	caller := getCaller()
+
+	logrus.WithFields(logrus.Fields{
+		"caller": caller,
+		"panic":  fmt.Sprint(r),
+		"stack":  string(runtimedebug.Stack()),
+	}).Error("Companion crashed")
+
+	if emergencySave != nil {
+		if err := runEmergencySave(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Error("Emergency save failed")
+		} else {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+			}).Info("Emergency save written")
+		}
+	}
+	if logHook != nil {
+		logHook.Close()
+	}
+
+	fmt.Fprintf(os.Stderr, "The companion crashed: %v\nStart it with -safe-mode if this keeps happening.\n", r)
+	crashExit(2)
+}
+
+// runEmergencySave calls emergencySave, turning a second panic from the
+// broken state into an error
+func runEmergencySave() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("save panicked: %v", r)
+		}
+	}()
+	return emergencySave()
+}
+
+// restoreSave loads the character's save, falling back to the last good one
+// when it can't be read. A broken save would otherwise be replaced by a
+// fresh game at the first auto-save.
+func restoreSave(char *character.Character, saveManager *persistence.SaveManager) {
+	caller := getCaller()
+
+	data, err := saveManager.LoadGameState(char.GetName())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Failed to load saved game, trying the last good save")
+
+		data, err = saveManager.RestoreLastGood(char.GetName())
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": caller,
+				"error":  err.Error(),
+			}).Warn("No usable save, starting fresh")
+			return
+		}
+	}
+	if data == nil {
+		return
+	}
+
+	char.RestoreSaveData(data)
+	logrus.WithFields(logrus.Fields{
+		"caller":  caller,
+		"saveDir": saveManager.GetSaveDirectory(),
+	}).Info("Saved game restored")
+}
+
+// offerLastGoodSave asks, in safe mode, whether to go back to the save from
+// the last clean exit
+func offerLastGoodSave(char *character.Character, saveManager *persistence.SaveManager, window *ui.DesktopWindow) {
+	savedAt, ok := saveManager.LastGoodTime(char.GetName())
+	if !ok {
+		return
+	}
+	if window == nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":  getCaller(),
+			"savedAt": savedAt.Format(time.RFC3339),
+		}).Info("A last good save is available; restore it from the window in safe mode")
+		return
+	}
+
+	window.OfferRestore(savedAt, func() error {
+		data, err := saveManager.RestoreLastGood(char.GetName())
+		if err != nil {
+			return err
+		}
+		char.RestoreSaveData(data)
+		return nil
+	})
+}
+
+// finalSave saves the game at a clean exit and keeps it as the last good save
+func finalSave(char *character.Character, saveManager *persistence.SaveManager) {
+	caller := getCaller()
+
+	data := char.SaveData()
+	if data == nil {
+		return
+	}
+	if err := saveManager.SaveGameState(data.CharacterName, data); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Final game save failed")
+		return
+	}
+	if err := saveManager.MarkLastGood(data.CharacterName); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Failed to keep the last good save")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+)
+
+func TestCrashOffMainGoroutineWritesEmergencySave(t *testing.T) {
+	saveManager := persistence.NewSaveManager(t.TempDir())
+	defer saveManager.Close()
+
+	emergencySave = func() error {
+		return saveManager.SaveGameState("Crashy", &persistence.GameSaveData{
+			CharacterName: "Crashy",
+			GameState:     &persistence.GameStateData{},
+		})
+	}
+	exited := make(chan int, 1)
+	crashExit = func(code int) { exited <- code }
+	monitoring.SetCrashHandler(handleCrash)
+	defer func() {
+		emergencySave, crashExit = nil, os.Exit
+		monitoring.SetCrashHandler(nil)
+	}()
+
+	// Game logic and network handlers run on goroutines of their own
+	go func() {
+		defer monitoring.RecoverCrash()
+		panic("game logic broke")
+	}()
+
+	if code := <-exited; code != 2 {
+		t.Errorf("Expected exit code 2 after a crash, got %d", code)
+	}
+	if !saveManager.HasSave("Crashy") {
+		t.Error("Expected the emergency save written")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/replay"
+)
+
+// runSimulateCommand handles "companion simulate": it fast-forwards a
+// character's stats over simulated days without a window and writes the
+// stat trajectories as CSV, for tuning decay rates and thresholds.
+func runSimulateCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	characterPath := fs.String("character", "assets/characters/default/character_with_game_features.json", "Path to character configuration file")
+	days := fs.Float64("days", 7, "Simulated days")
+	interact := fs.String("interact", "", "Interactions the simulated owner performs, e.g. feed:4h,play:8h (default: none)")
+	quiet := fs.String("quiet", "", "Owner's quiet hours with no interactions, e.g. 23:00-07:00")
+	start := fs.String("start", "08:00", "Time of day the simulation starts")
+	sample := fs.String("sample", "1h", "Time between CSV rows")
+	output := fs.String("output", "", "CSV file to write (default: stdout)")
+	eventFrequency := fs.Float64("event-frequency", 0, "Random event frequency multiplier, 0.1-3.0 (default 1.0)")
+	verbose := fs.Bool("debug", false, "Show character logs while simulating")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: companion simulate [options]")
+		fmt.Fprintln(stderr, "\nFast-forwards a character's stats and writes the trajectories as CSV.")
+		fmt.Fprintln(stderr, "A summary of each stat is printed to stderr.")
+		fmt.Fprintln(stderr, "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := simulationConfig(*days, *interact, *quiet, *start, *sample)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	// Days of virtual minutes would otherwise flood the terminal with logs
+	if !*verbose {
+		logrus.SetLevel(logrus.ErrorLevel)
+	}
+
+	card, err := character.LoadCard(*characterPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	char, err := character.New(card, filepath.Dir(*characterPath))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: failed to create character: %v\n", err)
+		return 1
+	}
+	if *eventFrequency > 0 {
+		char.SetEventFrequencyMultiplier(*eventFrequency)
+	}
+
+	out := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	result, err := replay.Simulate(char, cfg, out)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "%s over %g days:\n", card.Name, *days)
+	result.WriteSummary(stderr)
+	return 0
+}
+
+// simulationConfig builds the simulation from the command's flags
+func simulationConfig(days float64, interact, quiet, start, sample string) (replay.SimulationConfig, error) {
+	var cfg replay.SimulationConfig
+	if days <= 0 || days > 365 {
+		return cfg, fmt.Errorf("-days must be more than 0 and at most 365")
+	}
+	cfg.Duration = time.Duration(days * float64(24*time.Hour))
+
+	var err error
+	if cfg.Patterns, err = replay.ParsePatterns(interact); err != nil {
+		return cfg, fmt.Errorf("-interact: %w", err)
+	}
+	if quiet != "" {
+		if cfg.QuietStart, cfg.QuietEnd, err = replay.ParseQuietHours(quiet); err != nil {
+			return cfg, fmt.Errorf("-quiet: %w", err)
+		}
+	}
+	if cfg.StartClock, err = replay.ParseClock(start); err != nil {
+		return cfg, fmt.Errorf("-start: %w", err)
+	}
+	if cfg.Sample, err = replay.ParseDuration(sample); err != nil {
+		return cfg, fmt.Errorf("-sample: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/ui"
+	"github.com/opd-ai/desktop-companion/lib/update"
+)
+
+const (
+	updateCheckDelay    = time.Minute // Let startup settle before the first check
+	updateCheckInterval = 24 * time.Hour
+)
+
+// setupUpdateCheck checks GitHub for a newer release once shortly after
+// startup and then daily. The character announces it, and installs it only
+// when the user picks the update from the context menu.
+func setupUpdateCheck(window *ui.DesktopWindow) func() {
+	caller := getCaller()
+
+	if *noUpdateCheck {
+		return nil
+	}
+
+	updater, err := update.NewUpdater(update.Config{CurrentVersion: appVersion})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Warn("Update checks unavailable")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		timer := time.NewTimer(updateCheckDelay)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			checkForUpdate(ctx, updater, window)
+			timer.Reset(updateCheckInterval)
+		}
+	}()
+
+	logrus.WithFields(logrus.Fields{
+		"caller":  caller,
+		"version": appVersion,
+	}).Info("Update checks enabled")
+
+	return cancel
+}
+
+// checkForUpdate offers the newest release if it is newer than this build
+func checkForUpdate(ctx context.Context, updater *update.Updater, window *ui.DesktopWindow) {
+	caller := getCaller()
+
+	release, err := updater.Check(ctx)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": caller,
+			"error":  err.Error(),
+		}).Debug("Update check failed")
+		return
+	}
+	if release == nil {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":      caller,
+		"version":     release.Version,
+		"installable": release.CanInstall(),
+	}).Info("New version available")
+
+	var install func() error
+	if release.CanInstall() {
+		install = func() error {
+			path, err := os.Executable()
+			if err == nil {
+				err = updater.Install(ctx, release, path)
+			}
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"caller":  caller,
+					"version": release.Version,
+					"error":   err.Error(),
+				}).Warn("Failed to install update")
+				return err
+			}
+			logrus.WithFields(logrus.Fields{
+				"caller":  caller,
+				"version": release.Version,
+			}).Info("Update installed, takes effect on restart")
+			return nil
+		}
+	}
+	window.OfferUpdate(release.Version, release.PageURL, install)
+}
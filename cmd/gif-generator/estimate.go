@@ -0,0 +1,85 @@
+package main
+
+// estimate.go implements --estimate for the character and batch commands:
+// before committing to a long run it prints each character's stale states
+// with their steps and resolution, the projected time from previous runs'
+// timing and the order ComfyUI will finish the characters in.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+// estimateTimeout bounds reading the server's queue for an estimate
+const estimateTimeout = 10 * time.Second
+
+// runEstimate prints the projected schedule for generating charConfigs.
+func runEstimate(config *pipeline.PipelineConfig, charConfigs []*pipeline.CharacterConfig) error {
+	history, err := pipeline.LoadTimingHistory(pipeline.TimingHistoryPath(config))
+	if err != nil {
+		return err
+	}
+
+	// The queue is informative only, so an unreachable server still gets
+	// an estimate
+	client, err := newComfyUIClient(config)
+	if err != nil && globalConfig.Verbose {
+		fmt.Printf("Not reading the ComfyUI queue: %v\n", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), estimateTimeout)
+	defer cancel()
+
+	estimate, err := pipeline.EstimateBatch(ctx, config, charConfigs, history, client)
+	if err != nil {
+		return err
+	}
+	printEstimate(os.Stdout, estimate)
+	return nil
+}
+
+// printEstimate writes the schedule with characters in the order they finish.
+func printEstimate(w io.Writer, estimate *pipeline.BatchEstimate) {
+	fmt.Fprintf(w, "Estimate for %d character(s), %d at a time (ComfyUI runs one job at a time):\n",
+		len(estimate.Characters), estimate.ConcurrentJobs)
+
+	for i, character := range estimate.Characters {
+		if len(character.States) == 0 {
+			fmt.Fprintf(w, "%2d. %s: up to date, nothing to generate\n", i+1, character.Character)
+			continue
+		}
+		fmt.Fprintf(w, "%2d. %s: %d state(s), %s, starts at %s, done at %s\n", i+1, character.Character,
+			len(character.States), formatEstimate(character.Duration, character.Known),
+			formatEstimate(character.Start, estimate.Known), formatEstimate(character.Finish, estimate.Known))
+		for _, state := range character.States {
+			work := state.Work
+			fmt.Fprintf(w, "      %-12s %d steps  %dx%d x%d  %s\n", state.State, work.Steps, work.Width, work.Height,
+				max(work.Frames, 1), formatEstimate(state.Duration, state.Known))
+		}
+		if len(character.Skipped) > 0 {
+			fmt.Fprintf(w, "      up to date: %v\n", character.Skipped)
+		}
+	}
+
+	if estimate.QueueKnown && estimate.QueuedAhead > 0 {
+		fmt.Fprintf(w, "ComfyUI already has %d job(s) queued or running; they run first and aren't included\n", estimate.QueuedAhead)
+	}
+	if !estimate.Known {
+		fmt.Fprintln(w, "No timing history for some models yet; times marked ? are partial. Each finished job adds to the history.")
+	}
+	fmt.Fprintf(w, "Projected wall-clock time: %s\n", formatEstimate(estimate.WallClock, estimate.Known))
+}
+
+// formatEstimate rounds a projected duration, marking partial ones with ?
+func formatEstimate(d time.Duration, known bool) string {
+	s := d.Round(time.Second).String()
+	if !known {
+		s += "?"
+	}
+	return s
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+func TestPrintEstimate(t *testing.T) {
+	work := pipeline.WorkflowWork{Steps: 20, Width: 128, Height: 128, Frames: 6}
+	estimate := &pipeline.BatchEstimate{
+		ConcurrentJobs: 2,
+		QueuedAhead:    1,
+		QueueKnown:     true,
+		WallClock:      90 * time.Second,
+		Characters: []*pipeline.CharacterEstimate{
+			{Character: "cat", Skipped: []string{"idle"}, Duration: 90 * time.Second, Finish: 90 * time.Second, States: []pipeline.StateEstimate{
+				{State: "happy", Work: work, Duration: 90 * time.Second, Known: true},
+			}},
+		},
+	}
+
+	var out bytes.Buffer
+	printEstimate(&out, estimate)
+	text := out.String()
+	for _, want := range []string{"cat: 1 state(s), 1m30s?", "happy", "20 steps  128x128 x6", "up to date: [idle]", "1 job(s) queued", "wall-clock time: 1m30s?"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in estimate:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+// fix.go implements validate --fix: assets that fail validation for reasons
+// that can be repaired automatically (dimensions, frame count, palette, file
+// size) are rewritten in place, with a backup, and validated again.
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+// fixAsset repairs an asset with pipeline.FixGIF and validates it again.
+// Assets with nothing fixable are returned as they are.
+func fixAsset(ctx context.Context, validator pipeline.Validator, result *pipeline.ValidationResult, config *pipeline.ValidationConfig) (*pipeline.ValidationResult, error) {
+	if !pipeline.Fixable(result) {
+		return result, nil
+	}
+	if globalConfig.DryRun {
+		fmt.Printf("Would fix %s\n", result.AssetPath)
+		return result, nil
+	}
+
+	fix, err := pipeline.FixGIF(result.AssetPath, config)
+	if err != nil {
+		return nil, fmt.Errorf("fix %s: %w", result.AssetPath, err)
+	}
+	fmt.Printf("Fixed %s (original kept as %s):\n", fix.Path, fix.BackupPath)
+	for _, action := range fix.Actions {
+		fmt.Printf("  - %s\n", action)
+	}
+
+	return validator.ValidateAsset(ctx, result.AssetPath, config)
+}
+
+// fixCharacterSet repairs every fixable asset of a character and validates
+// the whole set again
+func fixCharacterSet(ctx context.Context, validator pipeline.Validator, result *pipeline.CharacterValidationResult, characterDir string, charConfig *pipeline.CharacterConfig) (*pipeline.CharacterValidationResult, error) {
+	states := make([]string, 0, len(result.AssetResults))
+	for state := range result.AssetResults {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	fixed := false
+	for _, state := range states {
+		assetResult := result.AssetResults[state]
+		if !pipeline.Fixable(assetResult) {
+			continue
+		}
+		if _, err := fixAsset(ctx, validator, assetResult, charConfig.Validation); err != nil {
+			return nil, err
+		}
+		fixed = true
+	}
+	if !fixed || globalConfig.DryRun {
+		return result, nil
+	}
+
+	return validator.ValidateCharacterSet(ctx, characterDir, charConfig)
+}
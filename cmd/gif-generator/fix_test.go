@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+func TestFixCharacterSet(t *testing.T) {
+	dir := t.TempDir()
+	animations := filepath.Join(dir, "animations")
+	if err := os.MkdirAll(animations, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// 8x8 is far below the minimum size
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	writeTestGIF(t, filepath.Join(animations, "idle.gif"), []color.RGBA{red, blue, red, blue}, 0)
+
+	charConfig := pipeline.DefaultCharacterConfig("tester")
+	charConfig.States = []string{"idle"}
+	validator := pipeline.NewValidator()
+	ctx := context.Background()
+
+	result, err := validator.ValidateCharacterSet(ctx, dir, charConfig)
+	if err != nil {
+		t.Fatalf("ValidateCharacterSet failed: %v", err)
+	}
+	if result.AssetResults["idle"].Valid {
+		t.Fatal("An 8x8 GIF should fail validation")
+	}
+
+	fixed, err := fixCharacterSet(ctx, validator, result, dir, charConfig)
+	if err != nil {
+		t.Fatalf("fixCharacterSet failed: %v", err)
+	}
+	if idle := fixed.AssetResults["idle"]; !idle.Valid {
+		t.Errorf("Expected idle to validate after the fix, got %+v", idle.Errors)
+	}
+	if _, err := os.Stat(filepath.Join(animations, "idle.gif"+pipeline.FixBackupSuffix)); err != nil {
+		t.Errorf("Expected a backup of the original: %v", err)
+	}
+}
@@ -35,13 +35,14 @@ type Command struct {
 
 // CLIConfig holds CLI application configuration.
 type CLIConfig struct {
-	ConfigPath string
-	ComfyUIURL string
-	OutputDir  string
-	TempDir    string
-	Parallel   int
-	Verbose    bool
-	DryRun     bool
+	ConfigPath   string
+	ComfyUIURL   string
+	OutputDir    string
+	TempDir      string
+	ArtifactsDir string
+	Parallel     int
+	Verbose      bool
+	DryRun       bool
 }
 
 var (
@@ -75,6 +76,36 @@ func init() {
 			Usage:       "gif-generator deploy --source SOURCE --target TARGET [options]",
 			Handler:     handleDeployCommand,
 		},
+		"reproduce": {
+			Name:        "reproduce",
+			Description: "Regenerate an asset from its generation manifest",
+			Usage:       "gif-generator reproduce --manifest STATE.manifest.json [--output FILE]",
+			Handler:     handleReproduceCommand,
+		},
+		"watch": {
+			Name:        "watch",
+			Description: "Regenerate changed animation states when character files change",
+			Usage:       "gif-generator watch --path assets/characters [options]",
+			Handler:     handleWatchCommand,
+		},
+		"sheet": {
+			Name:        "sheet",
+			Description: "Render every animation state of a character into one grid image",
+			Usage:       "gif-generator sheet --character PATH --output sheet.png [options]",
+			Handler:     handleSheetCommand,
+		},
+		"serve": {
+			Name:        "serve",
+			Description: "Serve a local web dashboard for monitoring and retrying generation",
+			Usage:       "gif-generator serve [--addr :8090] [--path assets/characters]",
+			Handler:     handleServeCommand,
+		},
+		"models": {
+			Name:        "models",
+			Description: "List checkpoints and LoRAs on the ComfyUI server or check characters against them",
+			Usage:       "gif-generator models list [--type TYPES] OR models check --path PATH [--download]",
+			Handler:     handleModelsCommand,
+		},
 		"list-templates": {
 			Name:        "list-templates",
 			Description: "List available workflow templates",
@@ -152,6 +183,11 @@ func parseGlobalFlags() int {
 				globalConfig.TempDir = os.Args[i+1]
 				i++ // Skip the value
 			}
+		case "--artifacts-dir":
+			if i+1 < len(os.Args) {
+				globalConfig.ArtifactsDir = os.Args[i+1]
+				i++ // Skip the value
+			}
 		case "--parallel":
 			if i+1 < len(os.Args) {
 				fmt.Sscanf(os.Args[i+1], "%d", &globalConfig.Parallel)
@@ -202,6 +238,7 @@ func printUsage() {
 	fmt.Println("  --comfyui-url URL    ComfyUI server URL (default: http://localhost:8188)")
 	fmt.Println("  --output DIR         Output directory")
 	fmt.Println("  --temp-dir DIR       Temporary directory")
+	fmt.Println("  --artifacts-dir DIR  Register deployed assets with the artifact manager here (default: build/artifacts)")
 	fmt.Println("  --parallel N         Number of parallel jobs (default: 2)")
 	fmt.Println("  --verbose, -v        Verbose output")
 	fmt.Println("  --dry-run            Show what would be done without executing")
@@ -220,6 +257,13 @@ func handleCharacterCommand(args []string) error {
 	output := fs.String("output", "", "Output directory (overrides default)")
 	validate := fs.Bool("validate", false, "Validate generated assets")
 	backup := fs.Bool("backup", false, "Backup existing assets before generation")
+	showPrompts := fs.Bool("show-prompts", false, "Print the rendered prompts for each state and exit")
+	reference := fs.String("reference", "", "Reference PNG; every state is generated from it with image-to-image")
+	denoise := fs.Float64("denoise", 0, "Image-to-image denoise strength 0-1 (default: card setting or 0.6)")
+	stateDenoise := fs.String("state-denoise", "", "Per-state denoise, e.g. idle=0.4,happy=0.7")
+	force := fs.Bool("force", false, "Regenerate every state, even those the lockfile says are up to date")
+	skipModelCheck := fs.Bool("skip-model-check", false, "Don't check the server has the required models first")
+	estimate := fs.Bool("estimate", false, "Print the projected generation time of each state and exit")
 
 	fs.Parse(args)
 
@@ -284,6 +328,18 @@ func handleCharacterCommand(args []string) error {
 	// Note: These are applied to the deployment config since ValidationConfig doesn't have an Enabled field
 	charConfig.Deployment.ValidateBeforeDeploy = *validate
 	charConfig.Deployment.BackupExisting = *backup
+	charConfig.Force = *force
+
+	if err := applyReferenceFlags(charConfig, *reference, *denoise, *stateDenoise); err != nil {
+		return err
+	}
+
+	if *showPrompts {
+		return printStatePrompts(config, charConfig)
+	}
+	if *estimate {
+		return runEstimate(config, []*pipeline.CharacterConfig{charConfig})
+	}
 
 	if globalConfig.DryRun {
 		if *characterFile != "" {
@@ -293,29 +349,48 @@ func handleCharacterCommand(args []string) error {
 		}
 		fmt.Printf("Model: %s\n", charConfig.Character.Traits["model"])
 		fmt.Printf("States: %v\n", charConfig.States)
+		if stale, upToDate, _, err := pipeline.StaleStates(config, charConfig, charConfig.Deployment.OutputDir, *force); err == nil {
+			fmt.Printf("Would regenerate: %v\n", stale)
+			fmt.Printf("Up to date: %v\n", upToDate)
+		}
 		fmt.Printf("Output: %s\n", charConfig.Deployment.OutputDir)
 		fmt.Printf("Validation: %t\n", charConfig.Deployment.ValidateBeforeDeploy)
 		fmt.Printf("Backup: %t\n", charConfig.Deployment.BackupExisting)
+		if ref := charConfig.Reference; ref.Enabled() {
+			fmt.Printf("Reference: %s (image-to-image)\n", ref.Image)
+			for _, state := range charConfig.States {
+				fmt.Printf("  %s denoise: %.2f\n", state, ref.DenoiseFor(state))
+			}
+		}
 		return nil
 	}
 
+	if !*skipModelCheck {
+		if err := checkModelsBeforeGeneration(config, []*pipeline.CharacterConfig{charConfig}); err != nil {
+			return err
+		}
+	}
+
 	// Create pipeline controller
 	controller, err := createController(config)
 	if err != nil {
 		return fmt.Errorf("create controller: %w", err)
 	}
 
-	// Process character
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	// Process character; Ctrl+C stops the ComfyUI jobs
+	ctx, cancel := generationContext(10*time.Minute, false)
 	defer cancel()
 
 	result, err := controller.ProcessCharacter(ctx, charConfig)
 	if err != nil {
-		return fmt.Errorf("process character: %w", err)
+		return interrupted(ctx, fmt.Errorf("process character: %w", err))
 	}
 
 	// Print results
 	printProcessResult(result)
+	if ctx.Err() != nil {
+		return interrupted(ctx, ctx.Err())
+	}
 
 	// Deploy if successful
 	if result.Success {
@@ -334,6 +409,9 @@ func handleBatchCommand(args []string) error {
 	configPath := fs.String("config", "", "Batch configuration file (required)")
 	parallel := fs.Int("parallel", globalConfig.Parallel, "Number of parallel jobs")
 	output := fs.String("output", "", "Output directory (overrides config)")
+	force := fs.Bool("force", false, "Regenerate every state, even those the lockfile says are up to date")
+	skipModelCheck := fs.Bool("skip-model-check", false, "Don't check the server has the required models first")
+	estimate := fs.Bool("estimate", false, "Print the projected time and queue order of each character and exit")
 
 	fs.Parse(args)
 
@@ -352,10 +430,11 @@ func handleBatchCommand(args []string) error {
 	}
 
 	// Override output directory if specified
-	if *output != "" {
-		for _, config := range batchConfigs {
+	for _, config := range batchConfigs {
+		if *output != "" {
 			config.Deployment.OutputDir = filepath.Join(*output, config.Character.Archetype)
 		}
+		config.Force = *force
 	}
 
 	if globalConfig.DryRun {
@@ -376,23 +455,36 @@ func handleBatchCommand(args []string) error {
 	// Override concurrent jobs
 	pipelineConfig.Generation.ConcurrentJobs = *parallel
 
+	if *estimate {
+		return runEstimate(pipelineConfig, batchConfigs)
+	}
+
+	if !*skipModelCheck {
+		if err := checkModelsBeforeGeneration(pipelineConfig, batchConfigs); err != nil {
+			return err
+		}
+	}
+
 	// Create pipeline controller
 	controller, err := createController(pipelineConfig)
 	if err != nil {
 		return fmt.Errorf("create controller: %w", err)
 	}
 
-	// Process batch
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	// Process batch; Ctrl+C stops the ComfyUI jobs
+	ctx, cancel := generationContext(30*time.Minute, true)
 	defer cancel()
 
 	result, err := controller.ProcessBatch(ctx, batchConfigs)
 	if err != nil {
-		return fmt.Errorf("process batch: %w", err)
+		return interrupted(ctx, fmt.Errorf("process batch: %w", err))
 	}
 
 	// Print results
 	printBatchResult(result)
+	if ctx.Err() != nil {
+		return interrupted(ctx, ctx.Err())
+	}
 
 	return nil
 }
@@ -402,6 +494,7 @@ func handleValidateCommand(args []string) error {
 	fs := flag.NewFlagSet("validate", flag.ExitOnError)
 	path := fs.String("path", "", "Path to validate (required)")
 	recursive := fs.Bool("recursive", false, "Validate recursively")
+	fix := fs.Bool("fix", false, "Resize, re-quantize and re-optimize failing GIFs in place (originals kept as .bak)")
 
 	fs.Parse(args)
 
@@ -442,6 +535,9 @@ func handleValidateCommand(args []string) error {
 			charConfig.Deployment.OutputDir = charDir
 
 			result, err := validator.ValidateCharacterSet(ctx, charDir, charConfig)
+			if err == nil && *fix {
+				result, err = fixCharacterSet(ctx, validator, result, charDir, charConfig)
+			}
 			if err != nil {
 				if globalConfig.Verbose {
 					fmt.Printf("Skipping %s: %v\n", entry.Name(), err)
@@ -472,6 +568,11 @@ func handleValidateCommand(args []string) error {
 			if err != nil {
 				return fmt.Errorf("validate character set: %w", err)
 			}
+			if *fix {
+				if result, err = fixCharacterSet(ctx, validator, result, *path, charConfig); err != nil {
+					return err
+				}
+			}
 
 			printCharacterValidationResult(result)
 		} else {
@@ -480,6 +581,11 @@ func handleValidateCommand(args []string) error {
 			if err != nil {
 				return fmt.Errorf("validate asset: %w", err)
 			}
+			if *fix {
+				if result, err = fixAsset(ctx, validator, result, &config.Validation); err != nil {
+					return err
+				}
+			}
 
 			printAssetValidationResult(result)
 		}
@@ -517,6 +623,59 @@ func handleDeployCommand(args []string) error {
 	return deployAssets(*source, *target, *backup)
 }
 
+// handleReproduceCommand regenerates an asset from a saved manifest.
+func handleReproduceCommand(args []string) error {
+	fs := flag.NewFlagSet("reproduce", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "", "Generation manifest file (required)")
+	output := fs.String("output", "", "Output GIF path (default: next to the manifest)")
+
+	fs.Parse(args)
+
+	if *manifestPath == "" {
+		return fmt.Errorf("--manifest is required")
+	}
+
+	manifest, err := pipeline.LoadManifest(*manifestPath)
+	if err != nil {
+		return fmt.Errorf("load manifest: %w", err)
+	}
+
+	outputPath := *output
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(*manifestPath, ".manifest.json") + ".gif"
+	}
+
+	if globalConfig.Verbose || globalConfig.DryRun {
+		fmt.Printf("Reproducing %s/%s (seed %d, template %s, graph %s)\n",
+			manifest.Character, manifest.State, manifest.Seed, manifest.TemplateVersion, manifest.GraphHash[:12])
+	}
+	if globalConfig.DryRun {
+		fmt.Printf("Would write %s\n", outputPath)
+		return nil
+	}
+
+	config, err := loadPipelineConfig()
+	if err != nil {
+		return fmt.Errorf("load pipeline config: %w", err)
+	}
+
+	controller, err := createController(config)
+	if err != nil {
+		return fmt.Errorf("create controller: %w", err)
+	}
+
+	ctx, cancel := generationContext(10*time.Minute, false)
+	defer cancel()
+
+	asset, err := controller.ReproduceAsset(ctx, manifest, outputPath)
+	if err != nil {
+		return interrupted(ctx, fmt.Errorf("reproduce asset: %w", err))
+	}
+
+	fmt.Printf("Reproduced %s in %v: %s\n", asset.State, asset.GenerationTime.Round(time.Millisecond), asset.OutputPath)
+	return nil
+}
+
 // handleListTemplatesCommand lists available workflow templates.
 func handleListTemplatesCommand(args []string) error {
 	fs := flag.NewFlagSet("list-templates", flag.ExitOnError)
@@ -588,23 +747,75 @@ func handleHelpCommand(args []string) error {
 			fmt.Println("  --description TEXT   Character description")
 			fmt.Println("  --states LIST        Comma-separated animation states")
 			fmt.Println("  --output DIR         Output directory")
+			fmt.Println("  --show-prompts       Print rendered prompts per state and exit")
+			fmt.Println("  --reference PNG      Generate every state from this image (image-to-image)")
+			fmt.Println("  --denoise N          Image-to-image denoise strength 0-1 (default: 0.6)")
+			fmt.Println("  --state-denoise LIST Per-state denoise, e.g. idle=0.4,happy=0.7")
+			fmt.Println("  --skip-model-check   Don't check the server has the required models first")
+			fmt.Println("  --estimate           Print each state's steps, resolution and projected time, then exit")
 
 		case "batch":
 			fmt.Println("\nOptions:")
 			fmt.Println("  --config FILE        Batch configuration file (required)")
 			fmt.Println("  --parallel N         Number of parallel jobs")
 			fmt.Println("  --output DIR         Output directory base")
+			fmt.Println("  --skip-model-check   Don't check the server has the required models first")
+			fmt.Println("  --estimate           Print the projected time and queue order per character, then exit")
+			fmt.Println("\nEstimates use the timing of previous runs, kept in timing.json in the temp directory.")
 
 		case "validate":
 			fmt.Println("\nOptions:")
 			fmt.Println("  --path PATH          Path to validate (required)")
 			fmt.Println("  --recursive          Validate recursively")
+			fmt.Println("  --fix                Resize, re-quantize and re-optimize failing GIFs in place,")
+			fmt.Println("                       keeping the originals as .bak, then validate again")
 
 		case "deploy":
 			fmt.Println("\nOptions:")
 			fmt.Println("  --source DIR         Source directory (required)")
 			fmt.Println("  --target DIR         Target directory (required)")
 			fmt.Println("  --backup             Backup existing files (default: true)")
+
+		case "reproduce":
+			fmt.Println("\nOptions:")
+			fmt.Println("  --manifest FILE      Generation manifest written next to the GIF (required)")
+			fmt.Println("  --output FILE        Output GIF path (default: STATE.gif next to the manifest)")
+
+		case "watch":
+			fmt.Println("\nOptions:")
+			fmt.Println("  --path DIR           Directory of character folders to watch (required)")
+			fmt.Println("  --model MODEL        AI model to use (default: flux1d)")
+			fmt.Println("  --interval DURATION  How often to check for changes (default: 1s)")
+			fmt.Println("  --debounce DURATION  Quiet period after the last save before generating (default: 2s)")
+			fmt.Println("\nOnly states whose assetGeneration settings changed are regenerated.")
+			fmt.Println("Editing shared settings such as basePrompt regenerates every state.")
+
+		case "sheet":
+			fmt.Println("\nOptions:")
+			fmt.Println("  --character PATH     character.json or its directory (required)")
+			fmt.Println("  --output FILE        Output PNG path (default: sheet.png)")
+			fmt.Println("  --columns N          States per row (default: 4)")
+			fmt.Println("  --cell N             Pixel size each frame is scaled to fit (default: 128)")
+			fmt.Println("\nEach state shows the middle frame of its GIF, labeled with the state name.")
+
+		case "serve":
+			fmt.Println("\nOptions:")
+			fmt.Println("  --addr ADDR          Address to listen on (default: localhost:8090, :8090 for every interface)")
+			fmt.Println("  --path DIR           Directory of character folders to monitor (default: assets/characters)")
+			fmt.Println("  --model MODEL        AI model used for retries (default: flux1d)")
+			fmt.Println("\nThe page shows each state's GIF, validation result and live progress,")
+			fmt.Println("with a retry button for failed, invalid and missing states.")
+
+		case "models":
+			fmt.Println("\nSubcommands:")
+			fmt.Println("  list                 List installed models")
+			fmt.Println("    --type LIST        Model folders (default: checkpoints,loras)")
+			fmt.Println("  check                Check characters' checkpoints, LoRAs, VAEs and ControlNets are installed")
+			fmt.Println("    --path PATH        character.json, its folder or a folder of characters (required)")
+			fmt.Println("    --model MODEL      Check this model instead of each card's own")
+			fmt.Println("    --download         Download missing LoRAs from URLs in workflow.models")
+			fmt.Println("    --models-dir DIR   ComfyUI models directory (default: comfyui.models_dir)")
+			fmt.Println("\nModel presets like flux1d are checked when workflow.models maps them to a file.")
 		}
 	} else {
 		return fmt.Errorf("unknown command: %s", command)
@@ -628,6 +839,9 @@ func loadPipelineConfig() (*pipeline.PipelineConfig, error) {
 		if globalConfig.TempDir != "" {
 			config.Generation.TempDir = globalConfig.TempDir
 		}
+		if globalConfig.ArtifactsDir != "" {
+			config.Deployment.ArtifactsDir = globalConfig.ArtifactsDir
+		}
 
 		return config, nil
 	}
@@ -644,6 +858,9 @@ func loadPipelineConfig() (*pipeline.PipelineConfig, error) {
 	if globalConfig.TempDir != "" {
 		config.Generation.TempDir = globalConfig.TempDir
 	}
+	if globalConfig.ArtifactsDir != "" {
+		config.Deployment.ArtifactsDir = globalConfig.ArtifactsDir
+	}
 
 	return config, nil
 }
@@ -677,7 +894,15 @@ func loadBatchConfigs(path string) ([]*pipeline.CharacterConfig, error) {
 
 // createController creates a pipeline controller with ComfyUI client.
 func createController(config *pipeline.PipelineConfig) (pipeline.Controller, error) {
-	// Create ComfyUI client
+	client, err := newComfyUIClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.NewController(config, client)
+}
+
+// newComfyUIClient connects to the server in the pipeline configuration.
+func newComfyUIClient(config *pipeline.PipelineConfig) (comfyui.Client, error) {
 	comfyuiConfig := comfyui.Config{
 		ServerURL:     config.ComfyUI.ServerURL,
 		APIKey:        config.ComfyUI.APIKey,
@@ -690,8 +915,7 @@ func createController(config *pipeline.PipelineConfig) (pipeline.Controller, err
 	if err != nil {
 		return nil, fmt.Errorf("create ComfyUI client: %w", err)
 	}
-
-	return pipeline.NewController(config, client)
+	return client, nil
 }
 
 // Utility functions for printing results would go here...
@@ -701,6 +925,9 @@ func printProcessResult(result *pipeline.ProcessResult) {
 	fmt.Printf("Character: %s\n", result.Character)
 	fmt.Printf("Success: %v\n", result.Success)
 	fmt.Printf("Generated Assets: %d\n", len(result.GeneratedAssets))
+	if len(result.SkippedStates) > 0 {
+		fmt.Printf("Up to Date (skipped): %s\n", strings.Join(result.SkippedStates, ", "))
+	}
 	fmt.Printf("Errors: %d\n", len(result.Errors))
 	fmt.Printf("Warnings: %d\n", len(result.Warnings))
 	fmt.Printf("Processing Time: %v\n", result.ProcessingTime)
@@ -794,6 +1021,16 @@ func loadCharacterConfigFromFile(filePath, model string) (*pipeline.CharacterCon
 		return nil, fmt.Errorf("read character file: %w", err)
 	}
 
+	// Merge inherited base cards, then resolve localized strings
+	data, err = character.ResolveExtends(data, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve character extends: %w", err)
+	}
+	data, err = character.LocalizeCardJSON(data, filePath, character.Language())
+	if err != nil {
+		return nil, fmt.Errorf("parse character JSON: %w", err)
+	}
+
 	// Parse character card
 	var card character.CharacterCard
 	if err := json.Unmarshal(data, &card); err != nil {
@@ -836,6 +1073,8 @@ func loadCharacterConfigFromFile(filePath, model string) (*pipeline.CharacterCon
 			FrameRate:    card.AssetGeneration.GenerationSettings.AnimationSettings.FrameRate,
 			Colors:       256, // Default adaptive palette
 			Optimization: card.AssetGeneration.GenerationSettings.AnimationSettings.Optimization,
+			// Models often paint a solid background even when asked not to
+			RemoveBackground: card.AssetGeneration.GenerationSettings.AnimationSettings.TransparencyEnabled,
 		},
 		Validation: &pipeline.ValidationConfig{
 			MaxFileSize:          card.AssetGeneration.GenerationSettings.AnimationSettings.MaxFileSize * 1024,
@@ -851,7 +1090,14 @@ func loadCharacterConfigFromFile(filePath, model string) (*pipeline.CharacterCon
 			UpdateCharacterJSON:  true,
 			ValidateBeforeDeploy: true,
 		},
+		Prompts:   extractPromptConfig(card.AssetGeneration),
+		Seeds:     extractSeedConfig(card.AssetGeneration),
+		Reference: extractReferenceConfig(card.AssetGeneration, filepath.Dir(filePath)),
+		Models:    extractModelRefs(card.AssetGeneration),
+		Outputs:   extractOutputProfiles(card.AssetGeneration),
 	}
+	charConfig.Character.Traits["name"] = card.Name
+	charConfig.Character.Traits["model"] = card.AssetGeneration.GenerationSettings.Model
 
 	// Override model if specified
 	if model != "" {
@@ -870,3 +1116,137 @@ func extractAnimationStates(mappings map[string]character.AnimationMapping) []st
 	}
 	return states
 }
+
+// extractSeedConfig collects the card's fixed seeds: the global quality seed
+// and any per-state custom seeds. Returns nil when every seed is random.
+func extractSeedConfig(assetGen *character.AssetGenerationConfig) *pipeline.SeedConfig {
+	seeds := &pipeline.SeedConfig{
+		Base:   assetGen.GenerationSettings.QualitySettings.Seed,
+		States: make(map[string]int64),
+	}
+
+	for state, mapping := range assetGen.AnimationMappings {
+		if mapping.CustomSettings != nil && mapping.CustomSettings.QualitySettings.Seed > 0 {
+			seeds.States[state] = mapping.CustomSettings.QualitySettings.Seed
+		}
+	}
+
+	if seeds.Base <= 0 && len(seeds.States) == 0 {
+		return nil
+	}
+	return seeds
+}
+
+// extractReferenceConfig collects the card's reference image, resolved
+// against the card directory, and its denoise settings. Returns nil when the
+// card sets neither.
+func extractReferenceConfig(assetGen *character.AssetGenerationConfig, cardDir string) *pipeline.ReferenceConfig {
+	ref := &pipeline.ReferenceConfig{
+		Denoise: assetGen.GenerationSettings.QualitySettings.Denoise,
+		States:  make(map[string]float64),
+	}
+	if image := assetGen.ReferenceImage; image != "" {
+		if !filepath.IsAbs(image) {
+			image = filepath.Join(cardDir, image)
+		}
+		ref.Image = image
+	}
+
+	for state, mapping := range assetGen.AnimationMappings {
+		if mapping.CustomSettings != nil && mapping.CustomSettings.QualitySettings.Denoise > 0 {
+			ref.States[state] = mapping.CustomSettings.QualitySettings.Denoise
+		}
+	}
+
+	if ref.Image == "" && ref.Denoise == 0 && len(ref.States) == 0 {
+		return nil
+	}
+	return ref
+}
+
+// applyReferenceFlags layers --reference, --denoise and --state-denoise over
+// the card's reference settings.
+func applyReferenceFlags(charConfig *pipeline.CharacterConfig, image string, denoise float64, stateSpec string) error {
+	if image == "" && denoise == 0 && stateSpec == "" {
+		return nil
+	}
+	if charConfig.Reference == nil {
+		charConfig.Reference = &pipeline.ReferenceConfig{States: make(map[string]float64)}
+	}
+	ref := charConfig.Reference
+
+	if image != "" {
+		ref.Image = image
+	}
+	if denoise != 0 {
+		ref.Denoise = denoise
+	}
+	states, err := pipeline.ParseStateDenoise(stateSpec)
+	if err != nil {
+		return err
+	}
+	if ref.States == nil {
+		ref.States = make(map[string]float64)
+	}
+	for state, value := range states {
+		ref.States[state] = value
+	}
+
+	if !ref.Enabled() {
+		return fmt.Errorf("--denoise and --state-denoise need --reference or a card referenceImage")
+	}
+	return ref.Validate()
+}
+
+// extractPromptConfig converts the card's prompt templates and per-state
+// overrides into the pipeline prompt configuration
+func extractPromptConfig(assetGen *character.AssetGenerationConfig) *pipeline.PromptConfig {
+	prompts := &pipeline.PromptConfig{
+		Template:         assetGen.PromptTemplate,
+		NegativeTemplate: assetGen.NegativePromptTemplate,
+		States:           make(map[string]pipeline.StatePromptOverride),
+	}
+
+	for state, mapping := range assetGen.AnimationMappings {
+		prompts.States[state] = pipeline.StatePromptOverride{
+			Modifier: mapping.PromptModifier,
+			Template: mapping.PromptTemplate,
+			Negative: mapping.NegativePrompt,
+		}
+	}
+
+	return prompts
+}
+
+// extractOutputProfiles converts the card's output profiles into the
+// pipeline's extra resolutions
+func extractOutputProfiles(assetGen *character.AssetGenerationConfig) []pipeline.OutputProfile {
+	var profiles []pipeline.OutputProfile
+	for _, profile := range assetGen.OutputProfiles {
+		profiles = append(profiles, pipeline.OutputProfile{
+			Suffix:   profile.Suffix,
+			Width:    profile.Width,
+			Height:   profile.Height,
+			Scale:    profile.Scale,
+			Platform: profile.Platform,
+		})
+	}
+	return profiles
+}
+
+// printStatePrompts renders and prints the prompts that would be sent for each state
+func printStatePrompts(config *pipeline.PipelineConfig, charConfig *pipeline.CharacterConfig) error {
+	prompts, err := pipeline.BuildAllPrompts(config, charConfig)
+	if err != nil {
+		return fmt.Errorf("render prompts: %w", err)
+	}
+
+	fmt.Printf("Prompts for %s (%d states):\n", charConfig.Character.Archetype, len(prompts))
+	for _, prompt := range prompts {
+		fmt.Printf("\n[%s]\n", prompt.State)
+		fmt.Printf("  Positive: %s\n", prompt.Positive)
+		fmt.Printf("  Negative: %s\n", prompt.Negative)
+	}
+
+	return nil
+}
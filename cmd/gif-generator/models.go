@@ -0,0 +1,272 @@
+package main
+
+// models.go implements the models command and the model check run before
+// generation: a batch that needs a checkpoint or LoRA the ComfyUI server
+// doesn't have fails in seconds instead of after the first queued job.
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+// modelCheckTimeout bounds listing models before generation
+const modelCheckTimeout = 30 * time.Second
+
+// listedModelFolders are the folders shown by "models list" by default
+var listedModelFolders = []string{comfyui.ModelFolderCheckpoints, comfyui.ModelFolderLoras}
+
+// handleModelsCommand lists server models or checks characters against them.
+func handleModelsCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("models needs a subcommand: list or check")
+	}
+
+	switch args[0] {
+	case "list":
+		return handleModelsList(args[1:])
+	case "check":
+		return handleModelsCheck(args[1:])
+	default:
+		return fmt.Errorf("unknown models subcommand %q, want list or check", args[0])
+	}
+}
+
+// handleModelsList prints the models installed on the ComfyUI server.
+func handleModelsList(args []string) error {
+	fs := flag.NewFlagSet("models list", flag.ExitOnError)
+	folders := fs.String("type", strings.Join(listedModelFolders, ","), "Comma-separated model folders (checkpoints, loras, vae, controlnet)")
+
+	fs.Parse(args)
+
+	lister, err := newModelLister()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), modelCheckTimeout)
+	defer cancel()
+
+	for _, folder := range strings.Split(*folders, ",") {
+		folder = strings.TrimSpace(folder)
+		names, err := lister.ListModels(ctx, folder)
+		if err != nil {
+			return fmt.Errorf("list %s: %w", folder, err)
+		}
+		sort.Strings(names)
+		fmt.Printf("%s (%d):\n", folder, len(names))
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	return nil
+}
+
+// handleModelsCheck verifies that every model the characters reference is
+// installed, optionally downloading missing LoRAs.
+func handleModelsCheck(args []string) error {
+	fs := flag.NewFlagSet("models check", flag.ExitOnError)
+	path := fs.String("path", "", "character.json, its directory or a directory of character folders (required)")
+	model := fs.String("model", "", "Check this model instead of each card's own (sdxl, flux1d, flux1s)")
+	download := fs.Bool("download", false, "Download missing LoRAs from the URLs in workflow.models")
+	modelsDir := fs.String("models-dir", "", "ComfyUI models directory to download into (default: comfyui.models_dir)")
+
+	fs.Parse(args)
+
+	if *path == "" {
+		return fmt.Errorf("--path is required")
+	}
+
+	config, err := loadPipelineConfig()
+	if err != nil {
+		return fmt.Errorf("load pipeline config: %w", err)
+	}
+
+	cards, err := findCharacterCards(*path)
+	if err != nil {
+		return err
+	}
+	var charConfigs []*pipeline.CharacterConfig
+	for _, card := range cards {
+		charConfig, err := loadCharacterConfigFromFile(card, *model)
+		if err != nil {
+			return fmt.Errorf("%s: %w", card, err)
+		}
+		charConfigs = append(charConfigs, charConfig)
+	}
+
+	required := pipeline.RequiredModels(config, charConfigs)
+	if globalConfig.Verbose {
+		fmt.Printf("Checking %d models for %d characters\n", len(required), len(charConfigs))
+	}
+
+	lister, err := newModelLister()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), modelCheckTimeout)
+	defer cancel()
+
+	missing, err := pipeline.MissingModels(ctx, lister, required)
+	if err != nil {
+		return fmt.Errorf("check models: %w", err)
+	}
+	if *download && len(missing) > 0 {
+		dir := *modelsDir
+		if dir == "" {
+			dir = config.ComfyUI.ModelsDir
+		}
+		missing = downloadMissingLoras(config, missing, dir)
+	}
+
+	if len(missing) > 0 {
+		return missingModelsError(missing)
+	}
+	fmt.Printf("All %d models are installed\n", len(required))
+	return nil
+}
+
+// downloadMissingLoras downloads the LoRAs that have a configured URL and
+// returns the models still missing
+func downloadMissingLoras(config *pipeline.PipelineConfig, missing []pipeline.ModelRef, modelsDir string) []pipeline.ModelRef {
+	var still []pipeline.ModelRef
+	for _, ref := range missing {
+		if ref.Folder != comfyui.ModelFolderLoras || config.ModelURL(ref) == "" {
+			still = append(still, ref)
+			continue
+		}
+		if globalConfig.DryRun {
+			fmt.Printf("Would download %s from %s\n", ref, config.ModelURL(ref))
+			still = append(still, ref)
+			continue
+		}
+
+		fmt.Printf("Downloading %s...\n", ref)
+		// Models are large, so only the context on Ctrl+C limits the download
+		ctx, cancel := generationContext(2*time.Hour, false)
+		target, err := pipeline.DownloadModel(ctx, http.DefaultClient, config, ref, modelsDir)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Download failed: %v\n", err)
+			still = append(still, ref)
+			continue
+		}
+		fmt.Printf("Saved %s\n", target)
+	}
+	return still
+}
+
+// checkModelsBeforeGeneration fails when the characters need models the
+// server doesn't have. Servers that can't list models are only warned about.
+func checkModelsBeforeGeneration(config *pipeline.PipelineConfig, charConfigs []*pipeline.CharacterConfig) error {
+	required := pipeline.RequiredModels(config, charConfigs)
+	if len(required) == 0 {
+		return nil
+	}
+
+	lister, err := newModelLister()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), modelCheckTimeout)
+	defer cancel()
+
+	missing, err := pipeline.MissingModels(ctx, lister, required)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not check models on the server: %v\n", err)
+		return nil
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w (run 'gif-generator models check --download' or pass --skip-model-check)", missingModelsError(missing))
+	}
+	if globalConfig.Verbose {
+		fmt.Printf("All %d required models are installed\n", len(required))
+	}
+	return nil
+}
+
+// missingModelsError lists the missing models, with where each can be fetched
+func missingModelsError(missing []pipeline.ModelRef) error {
+	names := make([]string, len(missing))
+	for i, ref := range missing {
+		names[i] = ref.String()
+	}
+	return fmt.Errorf("missing on the ComfyUI server: %s", strings.Join(names, ", "))
+}
+
+// newModelLister connects to the ComfyUI server for listing models
+func newModelLister() (comfyui.ModelLister, error) {
+	config, err := loadPipelineConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load pipeline config: %w", err)
+	}
+	client, err := newComfyUIClient(config)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := client.(comfyui.ModelLister)
+	if !ok {
+		return nil, fmt.Errorf("ComfyUI client cannot list models")
+	}
+	return lister, nil
+}
+
+// findCharacterCards returns path itself when it is a file, its
+// character.json when it is a character folder, or else every
+// character.json in its subfolders
+func findCharacterCards(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat path: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	if _, err := os.Stat(filepath.Join(path, "character.json")); err == nil {
+		return []string{filepath.Join(path, "character.json")}, nil
+	}
+
+	cards, err := filepath.Glob(filepath.Join(path, "*", "character.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("no character.json found in %s", path)
+	}
+	return cards, nil
+}
+
+// extractModelRefs collects the LoRAs, VAE and ControlNet model the card's
+// asset generation uses, including per-state overrides
+func extractModelRefs(assetGen *character.AssetGenerationConfig) []pipeline.ModelRef {
+	var refs []pipeline.ModelRef
+	add := func(settings character.ComfyUISettings) {
+		for _, lora := range settings.Loras {
+			refs = append(refs, pipeline.ModelRef{Folder: comfyui.ModelFolderLoras, Name: lora.Name})
+		}
+		if settings.VAE != "" {
+			refs = append(refs, pipeline.ModelRef{Folder: comfyui.ModelFolderVAE, Name: settings.VAE})
+		}
+		if settings.ControlNet != nil && settings.ControlNet.Model != "" {
+			refs = append(refs, pipeline.ModelRef{Folder: comfyui.ModelFolderControlNet, Name: settings.ControlNet.Model})
+		}
+	}
+
+	add(assetGen.GenerationSettings.ComfyUISettings)
+	for _, mapping := range assetGen.AnimationMappings {
+		if mapping.CustomSettings != nil {
+			add(mapping.CustomSettings.ComfyUISettings)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+func TestExtractModelRefs(t *testing.T) {
+	assetGen := testAssetGeneration()
+	assetGen.GenerationSettings.ComfyUISettings = character.ComfyUISettings{
+		VAE:   "vae.safetensors",
+		Loras: []character.LoraSettings{{Name: "pixel.safetensors", Strength: 0.8}},
+	}
+	assetGen.AnimationMappings["happy"] = character.AnimationMapping{
+		PromptModifier: "smiling",
+		CustomSettings: &character.GenerationSettings{
+			ComfyUISettings: character.ComfyUISettings{
+				ControlNet: &character.ControlNetSettings{Model: "openpose.safetensors"},
+			},
+		},
+	}
+
+	refs := extractModelRefs(assetGen)
+	want := map[string]string{
+		"pixel.safetensors":    comfyui.ModelFolderLoras,
+		"vae.safetensors":      comfyui.ModelFolderVAE,
+		"openpose.safetensors": comfyui.ModelFolderControlNet,
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("Expected %d models, got %v", len(want), refs)
+	}
+	for _, ref := range refs {
+		if want[ref.Name] != ref.Folder {
+			t.Errorf("Expected %s in %s, got %s", ref.Name, want[ref.Name], ref.Folder)
+		}
+	}
+}
+
+func TestFindCharacterCards(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"alice", "bob"} {
+		os.MkdirAll(filepath.Join(dir, name), 0o755)
+		os.WriteFile(filepath.Join(dir, name, "character.json"), []byte("{}"), 0o644)
+	}
+
+	cards, err := findCharacterCards(dir)
+	if err != nil || len(cards) != 2 {
+		t.Fatalf("Expected 2 cards in the tree, got %v (%v)", cards, err)
+	}
+
+	cards, err = findCharacterCards(filepath.Join(dir, "alice"))
+	if err != nil || len(cards) != 1 || filepath.Base(filepath.Dir(cards[0])) != "alice" {
+		t.Errorf("Expected alice's card for her folder, got %v (%v)", cards, err)
+	}
+
+	if _, err := findCharacterCards(t.TempDir()); err == nil {
+		t.Error("Expected an error for a folder without cards")
+	}
+}
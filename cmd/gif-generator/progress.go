@@ -0,0 +1,141 @@
+package main
+
+// progress.go renders live generation progress: on a terminal each state
+// gets a progress bar with the sampler step and ETA, redrawn in place;
+// elsewhere, e.g. in CI logs, only a line per finished state is printed.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+const progressBarWidth = 24
+
+// progressPrinter prints pipeline.StateProgress updates
+type progressPrinter struct {
+	mu            sync.Mutex
+	w             io.Writer
+	live          bool // Redraw the current line with \r
+	withCharacter bool // Prefix states with their character, for batches
+	now           func() time.Time
+	lineWidth     int // Length of the line being redrawn, to blank leftovers
+}
+
+// newProgressPrinter creates a printer for w, drawing live bars when w is a
+// terminal
+func newProgressPrinter(w io.Writer, withCharacter bool) *progressPrinter {
+	return &progressPrinter{w: w, live: isTerminal(w), withCharacter: withCharacter, now: time.Now}
+}
+
+// isTerminal reports whether w is an interactive terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Report prints one update. Safe for concurrent use by batch workers.
+func (p *progressPrinter) Report(update pipeline.StateProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	label := update.State
+	if p.withCharacter && update.Character != "" {
+		label = update.Character + "/" + update.State
+	}
+
+	if update.Done {
+		line := fmt.Sprintf("  %s %-16s %s", statusIcon(update.Status), label, doneSummary(update, p.now()))
+		if p.live {
+			fmt.Fprintf(p.w, "\r%-*s\n", p.lineWidth, line)
+			p.lineWidth = 0
+		} else {
+			fmt.Fprintln(p.w, line)
+		}
+		return
+	}
+	if !p.live {
+		return
+	}
+
+	line := fmt.Sprintf("  %-18s %s", label, progressLine(update, p.now()))
+	fmt.Fprintf(p.w, "\r%-*s", p.lineWidth, line)
+	p.lineWidth = max(p.lineWidth, utf8.RuneCountInString(line))
+}
+
+// progressLine formats the bar, step and ETA for a running state
+func progressLine(update pipeline.StateProgress, now time.Time) string {
+	if update.MaxSteps <= 0 {
+		node := update.Node
+		if node == "" {
+			node = "queued"
+		}
+		return fmt.Sprintf("%s  %s", node, now.Sub(update.Started).Round(time.Second))
+	}
+
+	filled := int(update.Fraction() * progressBarWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	line := fmt.Sprintf("%s %3.0f%%  %s %d/%d", bar, update.Fraction()*100, update.Node, update.Step, update.MaxSteps)
+	if eta, ok := update.ETA(now); ok {
+		line += fmt.Sprintf("  ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
+
+// doneSummary describes how a state's job ended
+func doneSummary(update pipeline.StateProgress, now time.Time) string {
+	took := now.Sub(update.Started).Round(time.Second)
+	switch update.Status {
+	case "completed":
+		return fmt.Sprintf("generated in %s", took)
+	case "cancelled":
+		return "cancelled"
+	default:
+		return fmt.Sprintf("%s after %s", update.Status, took)
+	}
+}
+
+// statusIcon marks finished states in the same way as the result summaries
+func statusIcon(status string) string {
+	switch status {
+	case "completed":
+		return "✓"
+	case "cancelled":
+		return "⏹"
+	default:
+		return "✗"
+	}
+}
+
+// generationContext returns a context that is cancelled on Ctrl+C or after
+// timeout and reports progress to stdout. Cancelling stops the ComfyUI jobs.
+func generationContext(timeout time.Duration, withCharacter bool) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx = pipeline.WithProgress(ctx, newProgressPrinter(os.Stdout, withCharacter).Report)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// interrupted turns an error caused by Ctrl+C into a short message
+func interrupted(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return errors.New("generation cancelled, ComfyUI jobs stopped")
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+func TestProgressPrinterLive(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Now()
+	p := &progressPrinter{w: &out, live: true, now: func() time.Time { return now }}
+
+	p.Report(pipeline.StateProgress{State: "idle", Node: "KSampler", Step: 5, MaxSteps: 20, Started: now.Add(-10 * time.Second)})
+	line := out.String()
+	if !strings.HasPrefix(line, "\r") || !strings.Contains(line, " 25%") || !strings.Contains(line, "KSampler 5/20") {
+		t.Errorf("Unexpected progress line: %q", line)
+	}
+
+	out.Reset()
+	p.Report(pipeline.StateProgress{State: "idle", Done: true, Status: "completed", Started: now.Add(-42 * time.Second)})
+	if line := out.String(); !strings.Contains(line, "✓ idle") || !strings.Contains(line, "generated in 42s") || !strings.HasSuffix(line, "\n") {
+		t.Errorf("Unexpected done line: %q", line)
+	}
+}
+
+func TestProgressPrinterNotLive(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgressPrinter(&out, true)
+	if p.live {
+		t.Fatal("Expected a buffer not to be treated as a terminal")
+	}
+
+	p.Report(pipeline.StateProgress{Character: "cat", State: "idle", Step: 1, MaxSteps: 20, Started: time.Now()})
+	if out.Len() != 0 {
+		t.Errorf("Expected no redrawn bars outside a terminal, got %q", out.String())
+	}
+
+	p.Report(pipeline.StateProgress{Character: "cat", State: "idle", Done: true, Status: "cancelled"})
+	if line := out.String(); !strings.Contains(line, "cat/idle") || !strings.Contains(line, "cancelled") {
+		t.Errorf("Unexpected done line: %q", line)
+	}
+}
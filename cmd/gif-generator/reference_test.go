@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+func TestExtractReferenceConfig(t *testing.T) {
+	assetGen := &character.AssetGenerationConfig{
+		ReferenceImage: "art/reference.png",
+		AnimationMappings: map[string]character.AnimationMapping{
+			"happy": {CustomSettings: &character.GenerationSettings{QualitySettings: character.QualitySettings{Denoise: 0.75}}},
+			"idle":  {},
+		},
+	}
+	assetGen.GenerationSettings.QualitySettings.Denoise = 0.5
+
+	ref := extractReferenceConfig(assetGen, "/cards/tsundere")
+	if ref.Image != filepath.Join("/cards/tsundere", "art/reference.png") {
+		t.Errorf("expected image relative to the card, got %s", ref.Image)
+	}
+	if ref.DenoiseFor("idle") != 0.5 || ref.DenoiseFor("happy") != 0.75 {
+		t.Errorf("unexpected denoise: idle %g happy %g", ref.DenoiseFor("idle"), ref.DenoiseFor("happy"))
+	}
+
+	if extractReferenceConfig(&character.AssetGenerationConfig{}, "/cards") != nil {
+		t.Error("expected nil without reference settings")
+	}
+}
+
+func TestApplyReferenceFlags(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "reference.png")
+	if err := os.WriteFile(image, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	charConfig := &pipeline.CharacterConfig{Reference: &pipeline.ReferenceConfig{Denoise: 0.5}}
+	if err := applyReferenceFlags(charConfig, "", 0, "idle=0.3"); err == nil {
+		t.Error("expected an error for denoise without a reference image")
+	}
+
+	if err := applyReferenceFlags(charConfig, image, 0, "idle=0.3"); err != nil {
+		t.Fatalf("applyReferenceFlags failed: %v", err)
+	}
+	ref := charConfig.Reference
+	if !ref.Enabled() || ref.DenoiseFor("idle") != 0.3 || ref.DenoiseFor("sad") != 0.5 {
+		t.Errorf("unexpected reference config: %+v", ref)
+	}
+}
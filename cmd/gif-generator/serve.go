@@ -0,0 +1,526 @@
+package main
+
+// serve.go implements the serve command: a small local web dashboard for a
+// folder of characters. It shows every animation state with its GIF and
+// validation result, follows regenerations started from the page with live
+// progress, and lets a failed, invalid or missing state be retried with one
+// click. Only the standard library is used; the page template is embedded.
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+//go:embed templates/dashboard.html
+var dashboardTemplates embed.FS
+
+// State statuses shown on the dashboard
+const (
+	stateValid   = "valid"
+	stateInvalid = "invalid"
+	stateMissing = "missing"
+	stateRunning = "running"
+	stateFailed  = "failed"
+)
+
+// dashboardView is everything the page shows, also served as JSON
+type dashboardView struct {
+	Root       string          `json:"root"`
+	Characters []characterView `json:"characters"`
+	Total      int             `json:"total"`
+	Valid      int             `json:"valid"`
+	Running    int             `json:"running"`
+	Failed     int             `json:"failed"` // Failed, invalid or missing
+	Percent    int             `json:"percent"`
+	DryRun     bool            `json:"dry_run"`
+}
+
+// characterView is one character card and its states
+type characterView struct {
+	ID     string      `json:"id"` // Folder name, used in URLs
+	Name   string      `json:"name"`
+	Error  string      `json:"error,omitempty"` // The card could not be read
+	Valid  int         `json:"valid"`
+	States []stateView `json:"states"`
+}
+
+// stateView is one animation state of a character
+type stateView struct {
+	State    string   `json:"state"`
+	Status   string   `json:"status"`
+	Version  int64    `json:"version,omitempty"`  // GIF mod time, busts the thumbnail cache
+	Percent  int      `json:"percent,omitempty"`  // While running
+	Node     string   `json:"node,omitempty"`     // While running
+	Errors   []string `json:"errors,omitempty"`   // Generation error or validation errors
+	Warnings []string `json:"warnings,omitempty"` // Validation warnings
+}
+
+// Retryable reports whether the page offers a retry button for the state
+func (s stateView) Retryable() bool {
+	return s.Status == stateFailed || s.Status == stateInvalid || s.Status == stateMissing
+}
+
+// dashboardJob is the latest regeneration of one state started from the page
+type dashboardJob struct {
+	running  bool
+	progress pipeline.StateProgress
+	err      string
+}
+
+// checkedAsset caches a GIF's validation until the file changes
+type checkedAsset struct {
+	modTime time.Time
+	result  *pipeline.ValidationResult
+}
+
+// dashboardCard is the part of character.json the dashboard needs. Cards
+// are read without LoadCard so that missing GIFs don't hide the character.
+type dashboardCard struct {
+	Name            string                           `json:"name"`
+	Animations      map[string]string                `json:"animations"`
+	AssetGeneration *character.AssetGenerationConfig `json:"assetGeneration"`
+}
+
+// dashboard serves the pipeline monitoring page for a folder of characters
+type dashboard struct {
+	root       string
+	model      string
+	controller pipeline.Controller // nil in dry-run mode, where retries are only logged
+	validator  pipeline.Validator
+	validation *pipeline.ValidationConfig
+	tmpl       *template.Template
+	ctx        context.Context // Cancels running regenerations on shutdown
+
+	mu      sync.Mutex
+	jobs    map[string]*dashboardJob // "character/state" -> latest regeneration
+	checked map[string]checkedAsset  // GIF path -> validation
+}
+
+// handleServeCommand starts the dashboard and serves it until Ctrl+C.
+func handleServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8090", "Address to listen on")
+	path := fs.String("path", "assets/characters", "Directory of character folders to monitor")
+	model := fs.String("model", "flux1d", "AI model used for retries (sdxl, flux1d, flux1s)")
+
+	fs.Parse(args)
+
+	config, err := loadPipelineConfig()
+	if err != nil {
+		return fmt.Errorf("load pipeline config: %w", err)
+	}
+
+	var controller pipeline.Controller
+	if !globalConfig.DryRun {
+		if controller, err = createController(config); err != nil {
+			return fmt.Errorf("create controller: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	d, err := newDashboard(ctx, *path, *model, controller, &config.Validation)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Addr: *addr, Handler: d.routes(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving the pipeline dashboard for %s on http://%s (Ctrl+C to stop)\n", *path, dashboardHost(*addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve dashboard: %w", err)
+	}
+	return nil
+}
+
+// dashboardHost turns a listen address like ":8090" into one a browser can open
+func dashboardHost(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+// newDashboard creates the dashboard for the characters under root
+func newDashboard(ctx context.Context, root, model string, controller pipeline.Controller, validation *pipeline.ValidationConfig) (*dashboard, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("stat path: %w", err)
+	}
+
+	tmpl, err := template.ParseFS(dashboardTemplates, "templates/dashboard.html")
+	if err != nil {
+		return nil, fmt.Errorf("parse dashboard template: %w", err)
+	}
+
+	return &dashboard{
+		root:       root,
+		model:      model,
+		controller: controller,
+		validator:  pipeline.NewValidator(),
+		validation: validation,
+		tmpl:       tmpl,
+		ctx:        ctx,
+		jobs:       make(map[string]*dashboardJob),
+		checked:    make(map[string]checkedAsset),
+	}, nil
+}
+
+// routes registers the dashboard's pages
+func (d *dashboard) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", d.handleIndex)
+	mux.HandleFunc("GET /api/status", d.handleStatus)
+	mux.HandleFunc("GET /gif/{character}/{state}", d.handleGIF)
+	mux.HandleFunc("POST /retry/{character}/{state}", d.handleRetry)
+	return mux
+}
+
+// handleIndex renders the dashboard page
+func (d *dashboard) handleIndex(w http.ResponseWriter, r *http.Request) {
+	view := d.snapshot(r.Context())
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := d.tmpl.Execute(w, view); err != nil {
+		fmt.Printf("⚠ render dashboard: %v\n", err)
+	}
+}
+
+// handleStatus serves the dashboard as JSON for scripts
+func (d *dashboard) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(d.snapshot(r.Context()))
+}
+
+// handleGIF serves a state's animation for its thumbnail
+func (d *dashboard) handleGIF(w http.ResponseWriter, r *http.Request) {
+	cardPath, card, err := d.findCard(r.PathValue("character"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	path, _, ok := stateGIF(card, filepath.Dir(cardPath), r.PathValue("state"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeFile(w, r, path)
+}
+
+// handleRetry starts regenerating a state and returns to the page
+func (d *dashboard) handleRetry(w http.ResponseWriter, r *http.Request) {
+	// Only the page itself may start jobs, not other sites the browser has open
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		http.Error(w, "cross-site requests are not allowed", http.StatusForbidden)
+		return
+	}
+
+	id, state := r.PathValue("character"), r.PathValue("state")
+	cardPath, card, err := d.findCard(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if !slices.Contains(cardStates(card), state) {
+		http.Error(w, fmt.Sprintf("%s has no state %q", id, state), http.StatusNotFound)
+		return
+	}
+
+	if err := d.retry(cardPath, id, state); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// retry regenerates one state in the background. A state can only have one
+// regeneration running at a time.
+func (d *dashboard) retry(cardPath, id, state string) error {
+	key := id + "/" + state
+
+	d.mu.Lock()
+	if job, exists := d.jobs[key]; exists && job.running {
+		d.mu.Unlock()
+		return fmt.Errorf("%s is already being generated", key)
+	}
+	if d.controller == nil {
+		d.mu.Unlock()
+		fmt.Printf("Would regenerate %s\n", key)
+		return nil
+	}
+	d.jobs[key] = &dashboardJob{running: true, progress: pipeline.StateProgress{Character: id, State: state, Started: time.Now()}}
+	d.mu.Unlock()
+
+	fmt.Printf("Regenerating %s\n", key)
+	go d.regenerate(cardPath, key, state)
+	return nil
+}
+
+// regenerate generates and deploys one state, recording progress and the
+// outcome for the page
+func (d *dashboard) regenerate(cardPath, key, state string) {
+	err := d.generateState(cardPath, key, state)
+
+	d.mu.Lock()
+	job := d.jobs[key]
+	job.running = false
+	if err != nil {
+		job.err = err.Error()
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		fmt.Printf("✗ %s: %v\n", key, err)
+	} else {
+		fmt.Printf("✓ %s regenerated\n", key)
+	}
+}
+
+// generateState runs the pipeline for a single state of a card
+func (d *dashboard) generateState(cardPath, key, state string) error {
+	charConfig, err := loadCharacterConfigFromFile(cardPath, d.model)
+	if err != nil {
+		return err
+	}
+	charConfig.States = []string{state}
+	charConfig.Force = true
+
+	ctx, cancel := context.WithTimeout(d.ctx, 10*time.Minute)
+	defer cancel()
+	ctx = pipeline.WithProgress(ctx, func(update pipeline.StateProgress) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.jobs[key].progress = update
+	})
+
+	result, err := d.controller.ProcessCharacter(ctx, charConfig)
+	if err != nil {
+		return err
+	}
+	if _, generated := result.GeneratedAssets[state]; !generated {
+		for _, processErr := range result.Errors {
+			return errors.New(processErr.Message)
+		}
+		return fmt.Errorf("no asset was generated")
+	}
+	if err := d.controller.DeployAssets(ctx, result); err != nil {
+		return fmt.Errorf("deploy assets: %w", err)
+	}
+	return nil
+}
+
+// snapshot collects the current state of every character for the page
+func (d *dashboard) snapshot(ctx context.Context) dashboardView {
+	view := dashboardView{Root: d.root, DryRun: d.controller == nil}
+
+	cards, err := findCharacterCards(d.root)
+	if err != nil {
+		return view
+	}
+
+	for _, cardPath := range cards {
+		c := d.characterView(ctx, cardPath)
+		for _, s := range c.States {
+			view.Total++
+			switch s.Status {
+			case stateValid:
+				view.Valid++
+			case stateRunning:
+				view.Running++
+			default:
+				view.Failed++
+			}
+		}
+		view.Characters = append(view.Characters, c)
+	}
+
+	if view.Total > 0 {
+		view.Percent = view.Valid * 100 / view.Total
+	}
+	return view
+}
+
+// characterView reports the status of each of a card's states
+func (d *dashboard) characterView(ctx context.Context, cardPath string) characterView {
+	cardDir := filepath.Dir(cardPath)
+	view := characterView{ID: filepath.Base(cardDir), Name: filepath.Base(cardDir)}
+
+	card, err := loadDashboardCard(cardPath)
+	if err != nil {
+		view.Error = err.Error()
+		return view
+	}
+	if card.Name != "" {
+		view.Name = card.Name
+	}
+
+	for _, state := range cardStates(card) {
+		s := d.stateView(ctx, card, cardDir, view.ID, state)
+		if s.Status == stateValid {
+			view.Valid++
+		}
+		view.States = append(view.States, s)
+	}
+	return view
+}
+
+// stateView reports one state: a running or failed regeneration takes
+// precedence over the validation of the GIF on disk
+func (d *dashboard) stateView(ctx context.Context, card *dashboardCard, cardDir, id, state string) stateView {
+	view := stateView{State: state, Status: stateMissing}
+
+	path, modTime, exists := stateGIF(card, cardDir, state)
+	if exists {
+		view.Version = modTime.Unix()
+		if result, err := d.validate(ctx, path, modTime); err != nil {
+			view.Status = stateInvalid
+			view.Errors = []string{err.Error()}
+		} else {
+			view.Status = stateValid
+			if !result.Valid {
+				view.Status = stateInvalid
+			}
+			for _, e := range result.Errors {
+				view.Errors = append(view.Errors, e.Message)
+			}
+			for _, w := range result.Warnings {
+				view.Warnings = append(view.Warnings, w.Message)
+			}
+		}
+	}
+
+	var job dashboardJob
+	d.mu.Lock()
+	if tracked, ok := d.jobs[id+"/"+state]; ok {
+		job = *tracked
+	}
+	d.mu.Unlock()
+
+	switch {
+	case job.running:
+		view.Status = stateRunning
+		view.Percent = int(job.progress.Fraction() * 100)
+		view.Node = job.progress.Node
+	case job.err != "":
+		view.Status = stateFailed
+		view.Errors = append([]string{job.err}, view.Errors...)
+	}
+
+	return view
+}
+
+// validate checks a GIF, reusing the last result until the file changes
+func (d *dashboard) validate(ctx context.Context, path string, modTime time.Time) (*pipeline.ValidationResult, error) {
+	d.mu.Lock()
+	cached, ok := d.checked[path]
+	d.mu.Unlock()
+	if ok && cached.modTime.Equal(modTime) {
+		return cached.result, nil
+	}
+
+	result, err := d.validator.ValidateAsset(ctx, path, d.validation)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.checked[path] = checkedAsset{modTime: modTime, result: result}
+	d.mu.Unlock()
+	return result, nil
+}
+
+// findCard returns the card of the character folder with the given name
+func (d *dashboard) findCard(id string) (string, *dashboardCard, error) {
+	cards, err := findCharacterCards(d.root)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, cardPath := range cards {
+		if filepath.Base(filepath.Dir(cardPath)) == id {
+			card, err := loadDashboardCard(cardPath)
+			return cardPath, card, err
+		}
+	}
+	return "", nil, fmt.Errorf("no character %q under %s", id, d.root)
+}
+
+// loadDashboardCard reads a card's name, animations and asset generation
+func loadDashboardCard(path string) (*dashboardCard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read character file: %w", err)
+	}
+	data, err = character.ResolveExtends(data, path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve character extends: %w", err)
+	}
+
+	var card dashboardCard
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("parse character JSON: %w", err)
+	}
+	return &card, nil
+}
+
+// cardStates lists the states a card animates or generates, in name order
+func cardStates(card *dashboardCard) []string {
+	seen := make(map[string]bool)
+	for state := range card.Animations {
+		seen[state] = true
+	}
+	if card.AssetGeneration != nil {
+		for state := range card.AssetGeneration.AnimationMappings {
+			seen[state] = true
+		}
+	}
+
+	states := make([]string, 0, len(seen))
+	for state := range seen {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// stateGIF finds a state's GIF: the card's animation path or STATE.gif next
+// to the card, where the pipeline deploys, whichever is newer
+func stateGIF(card *dashboardCard, cardDir, state string) (path string, modTime time.Time, exists bool) {
+	candidates := []string{filepath.Join(cardDir, state+".gif")}
+	if animation, ok := card.Animations[state]; ok {
+		candidates = append([]string{filepath.Join(cardDir, animation)}, candidates...)
+	}
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if !exists || info.ModTime().After(modTime) {
+			path, modTime, exists = candidate, info.ModTime(), true
+		}
+	}
+	return path, modTime, exists
+}
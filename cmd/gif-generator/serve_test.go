@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"image/color"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+// newTestDashboard creates a dashboard over one character, "cat", with an
+// idle GIF on disk and missing happy and sad GIFs
+func newTestDashboard(t *testing.T, controller pipeline.Controller) *dashboard {
+	t.Helper()
+	root := t.TempDir()
+	cardDir := filepath.Join(root, "cat")
+	if err := os.MkdirAll(filepath.Join(cardDir, "animations"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	card := `{
+		"name": "Cat",
+		"animations": {"idle": "animations/idle.gif", "sad": "animations/sad.gif"},
+		"assetGeneration": {"basePrompt": "a cat", "animationMappings": {"idle": {}, "happy": {}}}
+	}`
+	if err := os.WriteFile(filepath.Join(cardDir, "character.json"), []byte(card), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeTestGIF(t, filepath.Join(cardDir, "animations", "idle.gif"), []color.RGBA{{R: 0xff, A: 0xff}}, gif.DisposalNone)
+
+	validation := pipeline.DefaultPipelineConfig().Validation
+	d, err := newDashboard(context.Background(), root, "flux1d", controller, &validation)
+	if err != nil {
+		t.Fatalf("newDashboard failed: %v", err)
+	}
+	return d
+}
+
+func getStatus(t *testing.T, handler http.Handler) dashboardView {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+	var view dashboardView
+	if err := json.Unmarshal(rec.Body.Bytes(), &view); err != nil {
+		t.Fatalf("Bad status JSON %q: %v", rec.Body.String(), err)
+	}
+	return view
+}
+
+func TestDashboardStatus(t *testing.T) {
+	handler := newTestDashboard(t, nil).routes()
+
+	view := getStatus(t, handler)
+	if len(view.Characters) != 1 || view.Characters[0].ID != "cat" || view.Characters[0].Name != "Cat" {
+		t.Fatalf("Expected the cat character, got %+v", view.Characters)
+	}
+	states := view.Characters[0].States
+	if view.Total != 3 || len(states) != 3 {
+		t.Fatalf("Expected animated and generated states merged, got %+v", states)
+	}
+	for _, s := range states {
+		hasGIF := s.Version != 0
+		if hasGIF != (s.State == "idle") {
+			t.Errorf("%s: unexpected GIF presence %v", s.State, hasGIF)
+		}
+		if s.State != "idle" && (s.Status != stateMissing || !s.Retryable()) {
+			t.Errorf("%s: expected a retryable missing state, got %+v", s.State, s)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `action="/retry/cat/sad"`) {
+		t.Errorf("Expected the page to offer retrying sad, got %d", rec.Code)
+	}
+}
+
+func TestDashboardServesGIFs(t *testing.T) {
+	handler := newTestDashboard(t, nil).routes()
+
+	for path, want := range map[string]int{
+		"/gif/cat/idle": http.StatusOK,
+		"/gif/cat/sad":  http.StatusNotFound,
+		"/gif/dog/idle": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
+
+// failingController fails every generation
+type failingController struct {
+	pipeline.Controller
+}
+
+func (failingController) ProcessCharacter(ctx context.Context, config *pipeline.CharacterConfig) (*pipeline.ProcessResult, error) {
+	return nil, context.DeadlineExceeded
+}
+
+func TestDashboardRetry(t *testing.T) {
+	handler := newTestDashboard(t, failingController{}).routes()
+
+	post := func(path, site string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if site != "" {
+			req.Header.Set("Sec-Fetch-Site", site)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("/retry/cat/sad", "cross-site"); code != http.StatusForbidden {
+		t.Errorf("Expected cross-site retries to be refused, got %d", code)
+	}
+	if code := post("/retry/cat/dance", ""); code != http.StatusNotFound {
+		t.Errorf("Expected an unknown state to be rejected, got %d", code)
+	}
+	if code := post("/retry/cat/sad", "same-origin"); code != http.StatusSeeOther {
+		t.Fatalf("Expected a redirect back to the page, got %d", code)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var sad stateView
+		for _, s := range getStatus(t, handler).Characters[0].States {
+			if s.State == "sad" {
+				sad = s
+			}
+		}
+		if sad.Status == stateFailed {
+			if len(sad.Errors) == 0 || !sad.Retryable() {
+				t.Errorf("Expected the failure reason and a retry button, got %+v", sad)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the retry to fail, state is %+v", sad)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+// sheet.go implements the sheet command: it lays out a representative frame
+// of every animation state in one labeled grid image, so a character pack can
+// be reviewed at a glance or dropped into documentation.
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+const (
+	sheetPadding     = 8
+	sheetLabelHeight = 18
+	sheetTitleHeight = 24
+	sheetCheckerSize = 8
+)
+
+var (
+	sheetBackground = color.RGBA{R: 0x2b, G: 0x2b, B: 0x2b, A: 0xff}
+	sheetCheckLight = color.RGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff}
+	sheetCheckDark  = color.RGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xff}
+	sheetText       = color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+)
+
+// sheetCell is one animation state on the sheet
+type sheetCell struct {
+	State string
+	Frame image.Image // nil when the GIF could not be read
+}
+
+// handleSheetCommand renders a character sheet PNG for a character card.
+func handleSheetCommand(args []string) error {
+	fs := flag.NewFlagSet("sheet", flag.ExitOnError)
+	cardPath := fs.String("character", "", "Path to character.json or its directory (required)")
+	output := fs.String("output", "sheet.png", "Output PNG path")
+	columns := fs.Int("columns", 4, "Number of states per row")
+	cellSize := fs.Int("cell", 128, "Size in pixels each frame is scaled to fit")
+
+	fs.Parse(args)
+
+	if *cardPath == "" {
+		return fmt.Errorf("--character is required")
+	}
+	if *columns < 1 || *cellSize < 16 {
+		return fmt.Errorf("--columns must be at least 1 and --cell at least 16")
+	}
+
+	path := *cardPath
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "character.json")
+	}
+
+	card, err := character.LoadCard(path)
+	if err != nil {
+		return fmt.Errorf("load character: %w", err)
+	}
+
+	cells := loadSheetCells(card.Animations, filepath.Dir(path))
+	if globalConfig.DryRun {
+		fmt.Printf("Would write a %d-state sheet for %s to %s\n", len(cells), card.Name, *output)
+		return nil
+	}
+
+	sheet := renderSheet(card.Name, cells, *columns, *cellSize)
+	if err := writeSheet(*output, sheet); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d states of %s to %s\n", len(cells), card.Name, *output)
+	return nil
+}
+
+// loadSheetCells picks a representative frame for each state, in state order.
+// Unreadable animations still get a cell so gaps in a pack stay visible.
+func loadSheetCells(animations map[string]string, cardDir string) []sheetCell {
+	states := make([]string, 0, len(animations))
+	for state := range animations {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	cells := make([]sheetCell, 0, len(states))
+	for _, state := range states {
+		frame, err := representativeFrame(filepath.Join(cardDir, animations[state]))
+		if err != nil {
+			fmt.Printf("⚠ %s: %v\n", state, err)
+		}
+		cells = append(cells, sheetCell{State: state, Frame: frame})
+	}
+	return cells
+}
+
+// representativeFrame returns the middle frame of a GIF as it appears on
+// screen, i.e. with earlier frames composited underneath it.
+func representativeFrame(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open animation: %w", err)
+	}
+	defer file.Close()
+
+	anim, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("decode animation: %w", err)
+	}
+	if len(anim.Image) == 0 {
+		return nil, fmt.Errorf("animation has no frames")
+	}
+
+	bounds := image.Rect(0, 0, anim.Config.Width, anim.Config.Height)
+	if bounds.Empty() {
+		bounds = anim.Image[0].Bounds()
+	}
+	canvas := image.NewRGBA(bounds)
+
+	target := len(anim.Image) / 2
+	for i := 0; i <= target; i++ {
+		frame := anim.Image[i]
+		var previous *image.RGBA
+		if i < len(anim.Disposal) && anim.Disposal[i] == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, bounds.Min, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if i == target {
+			break
+		}
+
+		if i < len(anim.Disposal) {
+			switch anim.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				canvas = previous
+			}
+		}
+	}
+	return canvas, nil
+}
+
+// renderSheet lays the cells out in a grid under a title. Frames are scaled
+// with nearest neighbour so pixel art stays crisp, and drawn over a
+// checkerboard so transparency problems stand out.
+func renderSheet(title string, cells []sheetCell, columns, cellSize int) *image.RGBA {
+	if len(cells) < columns {
+		columns = max(len(cells), 1)
+	}
+	rows := (len(cells) + columns - 1) / columns
+
+	cellWidth := cellSize + sheetPadding
+	cellHeight := cellSize + sheetLabelHeight + sheetPadding
+	width := columns*cellWidth + sheetPadding
+	height := sheetTitleHeight + rows*cellHeight + sheetPadding
+
+	sheet := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(sheetBackground), image.Point{}, draw.Src)
+	drawLabel(sheet, title, image.Rect(0, 0, width, sheetTitleHeight))
+
+	for i, cell := range cells {
+		x := sheetPadding + (i%columns)*cellWidth
+		y := sheetTitleHeight + (i/columns)*cellHeight
+		box := image.Rect(x, y, x+cellSize, y+cellSize)
+
+		drawChecker(sheet, box)
+		if cell.Frame != nil {
+			xdraw.NearestNeighbor.Scale(sheet, fitRect(cell.Frame.Bounds(), box), cell.Frame, cell.Frame.Bounds(), draw.Over, nil)
+		}
+
+		label := cell.State
+		if cell.Frame == nil {
+			label += " (missing)"
+		}
+		drawLabel(sheet, label, image.Rect(x, box.Max.Y, x+cellSize, box.Max.Y+sheetLabelHeight))
+	}
+	return sheet
+}
+
+// fitRect scales src to fit inside box keeping its aspect ratio, centred
+func fitRect(src, box image.Rectangle) image.Rectangle {
+	w, h := src.Dx(), src.Dy()
+	if w == 0 || h == 0 {
+		return image.Rectangle{}
+	}
+	if w*box.Dy() > h*box.Dx() {
+		h = h * box.Dx() / w
+		w = box.Dx()
+	} else {
+		w = w * box.Dy() / h
+		h = box.Dy()
+	}
+	x := box.Min.X + (box.Dx()-w)/2
+	y := box.Min.Y + (box.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// drawChecker fills box with a transparency checkerboard
+func drawChecker(dst *image.RGBA, box image.Rectangle) {
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			c := sheetCheckLight
+			if ((x-box.Min.X)/sheetCheckerSize+(y-box.Min.Y)/sheetCheckerSize)%2 == 1 {
+				c = sheetCheckDark
+			}
+			dst.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawLabel writes text centred in box, trimming it to fit. The basic font
+// has no ellipsis glyph, so trimmed labels end in '~'.
+func drawLabel(dst *image.RGBA, text string, box image.Rectangle) {
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Dst: dst, Src: image.NewUniform(sheetText), Face: face}
+
+	runes := []rune(text)
+	for len(runes) > 1 && drawer.MeasureString(string(runes)).Ceil() > box.Dx() {
+		runes = append(runes[:len(runes)-2], '~')
+	}
+	text = string(runes)
+
+	width := drawer.MeasureString(text).Ceil()
+	metrics := face.Metrics()
+	x := box.Min.X + (box.Dx()-width)/2
+	y := box.Min.Y + (box.Dy()+metrics.Ascent.Ceil()-metrics.Descent.Ceil())/2
+	drawer.Dot = fixed.P(x, y)
+	drawer.DrawString(text)
+}
+
+// writeSheet saves the sheet as a PNG, creating the output directory
+func writeSheet(path string, sheet image.Image) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create output directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create sheet: %w", err)
+	}
+	if err := png.Encode(file, sheet); err != nil {
+		file.Close()
+		return fmt.Errorf("encode sheet: %w", err)
+	}
+	return file.Close()
+}
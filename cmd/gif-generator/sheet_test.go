@@ -0,0 +1,101 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestGIF writes a GIF whose frames are each filled with one colour
+func writeTestGIF(t *testing.T, path string, colors []color.RGBA, disposal byte) {
+	t.Helper()
+	anim := &gif.GIF{Config: image.Config{Width: 8, Height: 8}}
+	for _, c := range colors {
+		palette := color.Palette{color.Transparent, c}
+		frame := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		for i := range frame.Pix {
+			frame.Pix[i] = 1
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+		anim.Disposal = append(anim.Disposal, disposal)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := gif.EncodeAll(file, anim); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepresentativeFrameIsMiddleFrame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idle.gif")
+	red := color.RGBA{R: 0xff, A: 0xff}
+	green := color.RGBA{G: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	writeTestGIF(t, path, []color.RGBA{red, green, blue}, gif.DisposalNone)
+
+	frame, err := representativeFrame(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := color.RGBAModel.Convert(frame.At(4, 4)); got != green {
+		t.Errorf("Expected the middle frame's colour, got %v", got)
+	}
+
+	if _, err := representativeFrame(filepath.Join(t.TempDir(), "missing.gif")); err == nil {
+		t.Error("Expected an error for a missing animation")
+	}
+}
+
+func TestRenderSheetLayout(t *testing.T) {
+	dir := t.TempDir()
+	red := color.RGBA{R: 0xff, A: 0xff}
+	writeTestGIF(t, filepath.Join(dir, "idle.gif"), []color.RGBA{red}, gif.DisposalNone)
+
+	cells := loadSheetCells(map[string]string{
+		"talking": "missing.gif",
+		"idle":    "idle.gif",
+		"happy":   "idle.gif",
+	}, dir)
+	if len(cells) != 3 || cells[0].State != "happy" || cells[2].State != "talking" {
+		t.Fatalf("Expected states in name order, got %+v", cells)
+	}
+	if cells[2].Frame != nil {
+		t.Error("Expected no frame for a missing animation")
+	}
+
+	sheet := renderSheet("Test", cells, 2, 32)
+	wantWidth := 2*(32+sheetPadding) + sheetPadding
+	wantHeight := sheetTitleHeight + 2*(32+sheetLabelHeight+sheetPadding) + sheetPadding
+	if sheet.Bounds().Dx() != wantWidth || sheet.Bounds().Dy() != wantHeight {
+		t.Errorf("Expected %dx%d sheet, got %v", wantWidth, wantHeight, sheet.Bounds())
+	}
+
+	// The first cell's frame is scaled to fill it
+	center := sheet.RGBAAt(sheetPadding+16, sheetTitleHeight+16)
+	if center != red {
+		t.Errorf("Expected the frame scaled into its cell, got %v", center)
+	}
+
+	out := filepath.Join(dir, "out", "sheet.png")
+	if err := writeSheet(out, sheet); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("Expected the sheet to be written: %v", err)
+	}
+}
+
+func TestFitRectKeepsAspectRatio(t *testing.T) {
+	got := fitRect(image.Rect(0, 0, 20, 10), image.Rect(0, 0, 100, 100))
+	if got != image.Rect(0, 25, 100, 75) {
+		t.Errorf("Expected a centred 100x50 rect, got %v", got)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+// watch.go implements the watch command: it polls character.json files for
+// assetGeneration changes and regenerates only the animation states affected
+// by each edit. Polling keeps this standard library only and works the same
+// on every platform and network filesystem artists use.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/pipeline"
+)
+
+// watchedCard tracks one character.json between polls
+type watchedCard struct {
+	modTime      time.Time
+	changedAt    time.Time // Last time a write was seen, for debouncing
+	pending      bool
+	fingerprints map[string]string // State name -> hash of its generation inputs
+}
+
+// cardWatcher finds character.json files under a root and reports which ones
+// have settled after a change
+type cardWatcher struct {
+	root     string
+	debounce time.Duration
+	cards    map[string]*watchedCard
+	now      func() time.Time
+}
+
+// watchRun records one regeneration triggered by an edit
+type watchRun struct {
+	Card      string
+	States    []string
+	Succeeded []string
+	Failed    []string
+	Duration  time.Duration
+	Err       error
+}
+
+// newCardWatcher creates a watcher and snapshots the current cards so that
+// only later edits trigger generation
+func newCardWatcher(root string, debounce time.Duration) (*cardWatcher, error) {
+	w := &cardWatcher{
+		root:     root,
+		debounce: debounce,
+		cards:    make(map[string]*watchedCard),
+		now:      time.Now,
+	}
+
+	paths, err := w.findCards()
+	if err != nil {
+		return nil, err
+	}
+	for path, modTime := range paths {
+		card := &watchedCard{modTime: modTime}
+		// Cards without valid assetGeneration are still watched; fixing them
+		// later counts as a change to every state
+		card.fingerprints, _ = loadStateFingerprints(path)
+		w.cards[path] = card
+	}
+
+	return w, nil
+}
+
+// findCards returns every character.json under the root with its mod time
+func (w *cardWatcher) findCards() (map[string]time.Time, error) {
+	paths := make(map[string]time.Time)
+
+	err := filepath.Walk(w.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && info.Name() == "character.json" {
+			paths[path] = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", w.root, err)
+	}
+
+	return paths, nil
+}
+
+// poll checks for modified cards and returns those whose last write is older
+// than the debounce window, so a burst of saves triggers a single run
+func (w *cardWatcher) poll() ([]string, error) {
+	paths, err := w.findCards()
+	if err != nil {
+		return nil, err
+	}
+
+	now := w.now()
+	for path, modTime := range paths {
+		card, exists := w.cards[path]
+		if !exists {
+			card = &watchedCard{}
+			w.cards[path] = card
+		}
+		if !modTime.Equal(card.modTime) {
+			card.modTime = modTime
+			card.changedAt = now
+			card.pending = true
+		}
+	}
+
+	var ready []string
+	for path, card := range w.cards {
+		if _, exists := paths[path]; !exists {
+			delete(w.cards, path)
+			continue
+		}
+		if card.pending && now.Sub(card.changedAt) >= w.debounce {
+			card.pending = false
+			ready = append(ready, path)
+		}
+	}
+	sort.Strings(ready)
+
+	return ready, nil
+}
+
+// loadStateFingerprints reads a card's assetGeneration block and hashes the
+// inputs of every animation state
+func loadStateFingerprints(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read character file: %w", err)
+	}
+	data, err = character.ResolveExtends(data, path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve character extends: %w", err)
+	}
+
+	var card struct {
+		AssetGeneration *character.AssetGenerationConfig `json:"assetGeneration"`
+	}
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("parse character JSON: %w", err)
+	}
+	if card.AssetGeneration == nil {
+		return nil, fmt.Errorf("character file does not contain assetGeneration configuration")
+	}
+
+	return stateFingerprints(card.AssetGeneration)
+}
+
+// stateFingerprints hashes the settings that shape each state's output.
+// Shared settings are part of every state's hash, so editing the base prompt
+// regenerates everything while editing one mapping regenerates one state.
+// AssetMetadata is left out because the pipeline rewrites it after generating.
+func stateFingerprints(assetGen *character.AssetGenerationConfig) (map[string]string, error) {
+	shared, err := json.Marshal(struct {
+		BasePrompt             string
+		PromptTemplate         string
+		NegativePromptTemplate string
+		GenerationSettings     character.GenerationSettings
+	}{
+		assetGen.BasePrompt,
+		assetGen.PromptTemplate,
+		assetGen.NegativePromptTemplate,
+		assetGen.GenerationSettings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode generation settings: %w", err)
+	}
+
+	fingerprints := make(map[string]string, len(assetGen.AnimationMappings))
+	for state, mapping := range assetGen.AnimationMappings {
+		perState, err := json.Marshal(mapping)
+		if err != nil {
+			return nil, fmt.Errorf("encode mapping %s: %w", state, err)
+		}
+		hash := sha256.New()
+		hash.Write(shared)
+		hash.Write(perState)
+		fingerprints[state] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return fingerprints, nil
+}
+
+// changedStates returns states that are new or whose fingerprint changed
+func changedStates(previous, current map[string]string) []string {
+	var changed []string
+	for state, fingerprint := range current {
+		if previous[state] != fingerprint {
+			changed = append(changed, state)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// handleWatchCommand regenerates affected states whenever a character.json changes.
+func handleWatchCommand(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	path := fs.String("path", "", "Directory of character folders to watch (required)")
+	model := fs.String("model", "flux1d", "AI model to use (sdxl, flux1d, flux1s)")
+	interval := fs.Duration("interval", time.Second, "How often to check for changes")
+	debounce := fs.Duration("debounce", 2*time.Second, "Wait this long after the last save before generating")
+
+	fs.Parse(args)
+
+	if *path == "" {
+		return fmt.Errorf("--path is required")
+	}
+
+	watcher, err := newCardWatcher(*path, *debounce)
+	if err != nil {
+		return err
+	}
+
+	var controller pipeline.Controller
+	if !globalConfig.DryRun {
+		config, err := loadPipelineConfig()
+		if err != nil {
+			return fmt.Errorf("load pipeline config: %w", err)
+		}
+		if controller, err = createController(config); err != nil {
+			return fmt.Errorf("create controller: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Watching %d character files under %s (Ctrl+C to stop)\n", len(watcher.cards), *path)
+
+	var runs []watchRun
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			printWatchSummary(runs)
+			return nil
+		case <-ticker.C:
+		}
+
+		ready, err := watcher.poll()
+		if err != nil {
+			fmt.Printf("⚠ %v\n", err)
+			continue
+		}
+
+		for _, cardPath := range ready {
+			if run, ok := regenerateCard(ctx, controller, watcher.cards[cardPath], cardPath, *model); ok {
+				printWatchRun(run)
+				runs = append(runs, run)
+			}
+		}
+	}
+}
+
+// regenerateCard generates the states of a card whose settings changed.
+// Fingerprints are only advanced for states that generated successfully,
+// so failed states are retried on the next save.
+func regenerateCard(ctx context.Context, controller pipeline.Controller, card *watchedCard, cardPath, model string) (run watchRun, ok bool) {
+	run.Card = cardPath
+
+	current, err := loadStateFingerprints(cardPath)
+	if err != nil {
+		// Usually a half-finished edit; wait for the next save
+		fmt.Printf("⚠ %s: %v\n", cardPath, err)
+		return run, false
+	}
+
+	run.States = changedStates(card.fingerprints, current)
+	if len(run.States) == 0 {
+		if globalConfig.Verbose {
+			fmt.Printf("%s changed, but no animation state is affected\n", cardPath)
+		}
+		return run, false
+	}
+
+	if globalConfig.DryRun {
+		fmt.Printf("Would regenerate %s: %s\n", cardPath, strings.Join(run.States, ", "))
+		card.fingerprints = current
+		return run, false
+	}
+
+	fmt.Printf("Regenerating %s: %s\n", cardPath, strings.Join(run.States, ", "))
+	start := time.Now()
+	defer func() { run.Duration = time.Since(start) }()
+
+	charConfig, err := loadCharacterConfigFromFile(cardPath, model)
+	if err != nil {
+		run.Err = err
+		run.Failed = run.States
+		return run, true
+	}
+	charConfig.States = run.States
+
+	genCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	genCtx = pipeline.WithProgress(genCtx, newProgressPrinter(os.Stdout, false).Report)
+
+	result, err := controller.ProcessCharacter(genCtx, charConfig)
+	if err != nil {
+		run.Err = err
+		run.Failed = run.States
+		return run, true
+	}
+
+	if card.fingerprints == nil {
+		card.fingerprints = make(map[string]string)
+	}
+	for _, state := range run.States {
+		_, generated := result.GeneratedAssets[state]
+		if generated || slices.Contains(result.SkippedStates, state) {
+			run.Succeeded = append(run.Succeeded, state)
+			card.fingerprints[state] = current[state]
+		} else {
+			run.Failed = append(run.Failed, state)
+		}
+	}
+
+	if len(run.Succeeded) > 0 {
+		if err := controller.DeployAssets(genCtx, result); err != nil {
+			run.Err = fmt.Errorf("deploy assets: %w", err)
+		}
+	}
+
+	return run, true
+}
+
+// printWatchRun reports the outcome of one regeneration
+func printWatchRun(run watchRun) {
+	status := "✓"
+	if len(run.Failed) > 0 || run.Err != nil {
+		status = "✗"
+	}
+	fmt.Printf("%s %s: %d/%d states in %s\n", status, run.Card, len(run.Succeeded), len(run.States), run.Duration.Round(time.Millisecond))
+	if len(run.Failed) > 0 {
+		fmt.Printf("  Failed: %s\n", strings.Join(run.Failed, ", "))
+	}
+	if run.Err != nil {
+		fmt.Printf("  Error: %v\n", run.Err)
+	}
+}
+
+// printWatchSummary reports totals for the whole watch session
+func printWatchSummary(runs []watchRun) {
+	var succeeded, failed int
+	var total time.Duration
+	for _, run := range runs {
+		succeeded += len(run.Succeeded)
+		failed += len(run.Failed)
+		total += run.Duration
+	}
+
+	fmt.Printf("\nWatch Summary:\n")
+	fmt.Printf("  Runs: %d\n", len(runs))
+	fmt.Printf("  States regenerated: %d\n", succeeded)
+	fmt.Printf("  States failed: %d\n", failed)
+	fmt.Printf("  Generation time: %s\n", total.Round(time.Second))
+}
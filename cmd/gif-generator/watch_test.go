@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func testAssetGeneration() *character.AssetGenerationConfig {
+	return &character.AssetGenerationConfig{
+		BasePrompt: "a cheerful pixel art cat",
+		AnimationMappings: map[string]character.AnimationMapping{
+			"idle":  {PromptModifier: "sitting calmly"},
+			"happy": {PromptModifier: "smiling"},
+		},
+	}
+}
+
+func TestStateFingerprintsTrackAffectedStates(t *testing.T) {
+	assetGen := testAssetGeneration()
+	before, err := stateFingerprints(assetGen)
+	if err != nil {
+		t.Fatalf("stateFingerprints failed: %v", err)
+	}
+
+	// Editing one mapping only affects that state
+	mapping := assetGen.AnimationMappings["happy"]
+	mapping.PromptModifier = "grinning widely"
+	assetGen.AnimationMappings["happy"] = mapping
+	after, _ := stateFingerprints(assetGen)
+	if changed := changedStates(before, after); len(changed) != 1 || changed[0] != "happy" {
+		t.Errorf("Expected only happy to change, got %v", changed)
+	}
+
+	// Metadata written back by the pipeline is not a change
+	assetGen.AssetMetadata.Version = "2"
+	unchanged, _ := stateFingerprints(assetGen)
+	if changed := changedStates(after, unchanged); len(changed) != 0 {
+		t.Errorf("Expected metadata edits to be ignored, got %v", changed)
+	}
+
+	// Shared settings affect every state
+	assetGen.BasePrompt = "a grumpy pixel art cat"
+	shared, _ := stateFingerprints(assetGen)
+	if changed := changedStates(unchanged, shared); len(changed) != 2 {
+		t.Errorf("Expected all states to change, got %v", changed)
+	}
+}
+
+func TestCardWatcherDebouncesChanges(t *testing.T) {
+	root := t.TempDir()
+	cardPath := filepath.Join(root, "cat", "character.json")
+	if err := os.MkdirAll(filepath.Dir(cardPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cardPath, []byte(`{"assetGeneration":{"basePrompt":"cat","animationMappings":{"idle":{}}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher, err := newCardWatcher(root, 2*time.Second)
+	if err != nil {
+		t.Fatalf("newCardWatcher failed: %v", err)
+	}
+	if len(watcher.cards[cardPath].fingerprints) != 1 {
+		t.Fatal("Expected initial fingerprints to be captured")
+	}
+
+	now := time.Now()
+	watcher.now = func() time.Time { return now }
+
+	if ready, _ := watcher.poll(); len(ready) != 0 {
+		t.Errorf("Unchanged card should not be ready, got %v", ready)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(cardPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if ready, _ := watcher.poll(); len(ready) != 0 {
+		t.Errorf("Card should wait for the debounce window, got %v", ready)
+	}
+
+	now = now.Add(3 * time.Second)
+	ready, _ := watcher.poll()
+	if len(ready) != 1 || ready[0] != cardPath {
+		t.Errorf("Expected card to be ready after debounce, got %v", ready)
+	}
+	if ready, _ := watcher.poll(); len(ready) != 0 {
+		t.Errorf("Card should only be reported once, got %v", ready)
+	}
+}
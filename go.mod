@@ -8,6 +8,8 @@ require (
 	github.com/mmcdole/gofeed v1.3.0
 	github.com/opd-ai/minilm v0.0.0-20250914002606-5e5d977501ea
 	github.com/sirupsen/logrus v1.9.3
+	golang.org/x/image v0.18.0
+	golang.org/x/net v0.25.0
 	nhooyr.io/websocket v1.8.11
 )
 
@@ -43,9 +45,7 @@ require (
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef // indirect
 	github.com/stretchr/testify v1.8.4 // indirect
 	github.com/yuin/goldmark v1.7.1 // indirect
-	golang.org/x/image v0.18.0 // indirect
 	golang.org/x/mobile v0.0.0-20231127183840-76ac6878050a // indirect
-	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 	gonum.org/v1/gonum v0.7.0 // indirect
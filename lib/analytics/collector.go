@@ -0,0 +1,323 @@
+// Package analytics records how a user interacts with their companion.
+// Everything stays on the local machine: counts are aggregated per day in a
+// small JSON file and nothing is ever sent anywhere. Collection is opt-in.
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	dataVersion = 1
+	dayFormat   = "2006-01-02"
+
+	// retentionDays is how long daily summaries are kept on disk
+	retentionDays = 90
+)
+
+// DayStats aggregates one day of activity
+type DayStats struct {
+	Interactions map[string]int `json:"interactions"`        // Count per trigger ("click", "feed", ...)
+	Backends     map[string]int `json:"backends,omitempty"`  // Dialog backend that produced each response
+	LatencyTotal time.Duration  `json:"latencyTotal"`        // Sum of response latencies
+	LatencyMax   time.Duration  `json:"latencyMax"`          // Slowest response
+	Responses    int            `json:"responses"`           // Interactions that produced a response
+	MoodTotal    float64        `json:"moodTotal,omitempty"` // Sum of mood samples (0-100)
+	MoodSamples  int            `json:"moodSamples,omitempty"`
+}
+
+// data is the on-disk format
+type data struct {
+	Version int                  `json:"version"`
+	Days    map[string]*DayStats `json:"days"`
+}
+
+// Collector records interactions and mood samples for one character.
+// All methods are safe for concurrent use and a nil Collector ignores calls,
+// so callers never have to check whether analytics is enabled.
+type Collector struct {
+	mu    sync.Mutex
+	path  string
+	data  data
+	dirty bool
+	now   func() time.Time
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// DefaultPath returns the analytics file for a character in the user config dir
+func DefaultPath(characterName string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return PathIn(filepath.Join(configDir, "desktop-companion"), characterName), nil
+}
+
+// PathIn returns the analytics file for a character under dir, e.g. a
+// profile's memory directory
+func PathIn(dir, characterName string) string {
+	name := strings.ReplaceAll(strings.ToLower(characterName), " ", "_")
+	if name == "" {
+		name = "character"
+	}
+	return filepath.Join(dir, "analytics", name+".json")
+}
+
+// NewCollector creates a collector backed by path, loading earlier data if present
+func NewCollector(path string) (*Collector, error) {
+	c := &Collector{
+		path: path,
+		data: data{Version: dataVersion, Days: make(map[string]*DayStats)},
+		now:  time.Now,
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analytics: %w", err)
+	}
+	if err := json.Unmarshal(raw, &c.data); err != nil {
+		return nil, fmt.Errorf("failed to parse analytics %s: %w", path, err)
+	}
+	if c.data.Days == nil {
+		c.data.Days = make(map[string]*DayStats)
+	}
+	return c, nil
+}
+
+// today returns the stats bucket for the current day, creating it if needed.
+// Caller must hold c.mu.
+func (c *Collector) today() *DayStats {
+	key := c.now().Format(dayFormat)
+	day, exists := c.data.Days[key]
+	if !exists {
+		day = &DayStats{Interactions: make(map[string]int)}
+		c.data.Days[key] = day
+	}
+	return day
+}
+
+// RecordInteraction records one interaction, how long the response took and
+// which dialog backend answered. Empty responses count as an interaction only.
+func (c *Collector) RecordInteraction(trigger string, latency time.Duration, responded bool, backend string) {
+	if c == nil || trigger == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	day := c.today()
+	day.Interactions[trigger]++
+	if responded {
+		day.Responses++
+		day.LatencyTotal += latency
+		if latency > day.LatencyMax {
+			day.LatencyMax = latency
+		}
+		if backend != "" {
+			if day.Backends == nil {
+				day.Backends = make(map[string]int)
+			}
+			day.Backends[backend]++
+		}
+	}
+	c.dirty = true
+}
+
+// RecordMood adds a mood sample (0-100) to today's trajectory
+func (c *Collector) RecordMood(mood float64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	day := c.today()
+	day.MoodTotal += mood
+	day.MoodSamples++
+	c.dirty = true
+}
+
+// Save writes the data to disk if anything changed, dropping old days
+func (c *Collector) Save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	cutoff := c.now().AddDate(0, 0, -retentionDays).Format(dayFormat)
+	for key := range c.data.Days {
+		if key < cutoff {
+			delete(c.data.Days, key)
+		}
+	}
+
+	raw, err := json.MarshalIndent(c.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode analytics: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write analytics: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Start saves in the background every interval until Stop is called
+func (c *Collector) Start(interval time.Duration) {
+	if c == nil || c.stopChan != nil {
+		return
+	}
+
+	c.stopChan = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.Save(); err != nil {
+					logrus.WithError(err).Warn("Failed to save analytics")
+				}
+			case <-c.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends background saving and writes any pending data
+func (c *Collector) Stop() error {
+	if c == nil {
+		return nil
+	}
+	if c.stopChan != nil {
+		close(c.stopChan)
+		c.wg.Wait()
+		c.stopChan = nil
+	}
+	return c.Save()
+}
+
+// Count is a named counter used in summaries
+type Count struct {
+	Name  string
+	Count int
+}
+
+// DaySummary is one day of the weekly view
+type DaySummary struct {
+	Date         time.Time
+	Interactions int
+	Mood         float64 // Average mood, only meaningful when HasMood is set
+	HasMood      bool
+}
+
+// Summary describes recent activity for the "your companion this week" view
+type Summary struct {
+	Days              []DaySummary // Oldest first
+	TotalInteractions int
+	TopInteractions   []Count // Most used first
+	Backends          []Count // Most used first
+	AverageLatency    time.Duration
+	MaxLatency        time.Duration
+}
+
+// WeeklySummary summarizes the last seven days including today
+func (c *Collector) WeeklySummary() Summary {
+	return c.Summarize(7)
+}
+
+// Summarize summarizes the last days days including today
+func (c *Collector) Summarize(days int) Summary {
+	var summary Summary
+	if c == nil {
+		return summary
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interactions := make(map[string]int)
+	backends := make(map[string]int)
+	var latencyTotal time.Duration
+	responses := 0
+
+	today := c.now()
+	for offset := days - 1; offset >= 0; offset-- {
+		date := today.AddDate(0, 0, -offset)
+		entry := DaySummary{Date: date}
+
+		if day, exists := c.data.Days[date.Format(dayFormat)]; exists {
+			for trigger, count := range day.Interactions {
+				interactions[trigger] += count
+				entry.Interactions += count
+			}
+			for backend, count := range day.Backends {
+				backends[backend] += count
+			}
+			latencyTotal += day.LatencyTotal
+			responses += day.Responses
+			if day.LatencyMax > summary.MaxLatency {
+				summary.MaxLatency = day.LatencyMax
+			}
+			if day.MoodSamples > 0 {
+				entry.Mood = day.MoodTotal / float64(day.MoodSamples)
+				entry.HasMood = true
+			}
+		}
+
+		summary.TotalInteractions += entry.Interactions
+		summary.Days = append(summary.Days, entry)
+	}
+
+	if responses > 0 {
+		summary.AverageLatency = latencyTotal / time.Duration(responses)
+	}
+	summary.TopInteractions = sortedCounts(interactions)
+	summary.Backends = sortedCounts(backends)
+	return summary
+}
+
+// sortedCounts turns a counter map into a list, highest count first
+func sortedCounts(counts map[string]int) []Count {
+	list := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, Count{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
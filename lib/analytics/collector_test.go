@@ -0,0 +1,108 @@
+package analytics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCollector creates a collector with a controllable clock
+func newTestCollector(t *testing.T, now *time.Time) *Collector {
+	t.Helper()
+
+	c, err := NewCollector(filepath.Join(t.TempDir(), "analytics", "test.json"))
+	if err != nil {
+		t.Fatalf("NewCollector failed: %v", err)
+	}
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestCollectorWeeklySummary(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	c := newTestCollector(t, &now)
+
+	c.RecordInteraction("click", 20*time.Millisecond, true, "markov_chain")
+	c.RecordInteraction("click", 40*time.Millisecond, true, "card")
+	c.RecordInteraction("feed", 0, false, "")
+	c.RecordMood(80)
+
+	// Two days earlier, and one day that falls outside the week
+	now = now.AddDate(0, 0, -2)
+	c.RecordInteraction("click", 60*time.Millisecond, true, "markov_chain")
+	c.RecordMood(40)
+	now = now.AddDate(0, 0, -10)
+	c.RecordInteraction("pet", 0, true, "card")
+
+	now = time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+	summary := c.WeeklySummary()
+
+	if len(summary.Days) != 7 {
+		t.Fatalf("Expected 7 days, got %d", len(summary.Days))
+	}
+	if summary.TotalInteractions != 4 {
+		t.Errorf("Expected 4 interactions this week, got %d", summary.TotalInteractions)
+	}
+	if top := summary.TopInteractions[0]; top.Name != "click" || top.Count != 3 {
+		t.Errorf("Expected click x3 on top, got %+v", top)
+	}
+	if summary.AverageLatency != 40*time.Millisecond || summary.MaxLatency != 60*time.Millisecond {
+		t.Errorf("Unexpected latency avg %v max %v", summary.AverageLatency, summary.MaxLatency)
+	}
+	if backend := summary.Backends[0]; backend.Name != "markov_chain" || backend.Count != 2 {
+		t.Errorf("Expected markov_chain x2 as top backend, got %+v", backend)
+	}
+
+	last := summary.Days[6]
+	if !last.HasMood || last.Mood != 80 {
+		t.Errorf("Expected today's mood 80, got %+v", last)
+	}
+	if summary.Days[5].HasMood {
+		t.Error("Yesterday had no mood samples")
+	}
+}
+
+func TestCollectorSaveAndLoad(t *testing.T) {
+	now := time.Now()
+	c := newTestCollector(t, &now)
+	c.RecordInteraction("click", time.Millisecond, true, "card")
+
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := NewCollector(c.path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := loaded.WeeklySummary().TotalInteractions; got != 1 {
+		t.Errorf("Expected saved interaction after reload, got %d", got)
+	}
+}
+
+func TestCollectorPrunesOldDays(t *testing.T) {
+	now := time.Now().AddDate(0, 0, -retentionDays-5)
+	c := newTestCollector(t, &now)
+	c.RecordInteraction("click", 0, false, "")
+
+	now = time.Now()
+	c.RecordInteraction("click", 0, false, "")
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if len(c.data.Days) != 1 {
+		t.Errorf("Expected days older than retention to be dropped, have %d", len(c.data.Days))
+	}
+}
+
+func TestNilCollectorIsSafe(t *testing.T) {
+	var c *Collector
+	c.RecordInteraction("click", 0, true, "card")
+	c.RecordMood(50)
+	if err := c.Stop(); err != nil {
+		t.Errorf("Stop on nil collector should be a no-op, got %v", err)
+	}
+	if c.WeeklySummary().TotalInteractions != 0 {
+		t.Error("Nil collector should summarize to nothing")
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	ws "nhooyr.io/websocket"
+)
+
+// Client drives a companion through its API, over TCP or the unix socket of
+// a companion daemon. Frontends use it to attach to a running companion.
+type Client struct {
+	base  string // Scheme-less base URL, e.g. "localhost" or "127.0.0.1:8765"
+	token string
+	http  *http.Client
+}
+
+// NewClient creates a client for the server at addr, in the same forms
+// NewServer accepts. An empty token sends no Authorization header.
+func NewClient(addr, token string) (*Client, error) {
+	c := &Client{token: token}
+
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		if path == "" {
+			return nil, fmt.Errorf("invalid API address %q: socket path is empty", addr)
+		}
+		dialer := &net.Dialer{Timeout: 5 * time.Second}
+		c.base = "localhost"
+		c.http = &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		}}
+		return c, nil
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	c.base = net.JoinHostPort(host, port)
+	c.http = &http.Client{}
+	return c, nil
+}
+
+// Status returns the companion's name, state and stats
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+c.base+"/api/status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status Status
+	if err := c.do(req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Click clicks the character
+func (c *Client) Click(ctx context.Context) (ActionResponse, error) {
+	return c.post(ctx, "/api/click", struct{}{})
+}
+
+// Interact triggers a game or romance interaction by name
+func (c *Client) Interact(ctx context.Context, interaction string) (ActionResponse, error) {
+	return c.post(ctx, "/api/interact", map[string]string{"type": interaction})
+}
+
+// TriggerEvent starts a general dialog event by name
+func (c *Client) TriggerEvent(ctx context.Context, name string) (ActionResponse, error) {
+	return c.post(ctx, "/api/event", map[string]string{"name": name})
+}
+
+// Chat sends a chat message to the character
+func (c *Client) Chat(ctx context.Context, message string) (ActionResponse, error) {
+	return c.post(ctx, "/api/chat", map[string]string{"message": message})
+}
+
+// SetAnimation switches the character to an animation
+func (c *Client) SetAnimation(ctx context.Context, state string) (ActionResponse, error) {
+	return c.post(ctx, "/api/animation", map[string]string{"state": state})
+}
+
+// Subscribe streams events to handler until ctx is cancelled or the
+// connection drops. The first event is always the current state.
+func (c *Client) Subscribe(ctx context.Context, handler func(Event)) error {
+	opts := &ws.DialOptions{HTTPClient: c.http}
+	if c.token != "" {
+		opts.HTTPHeader = http.Header{"Authorization": {"Bearer " + c.token}}
+	}
+
+	conn, _, err := ws.Dial(ctx, "ws://"+c.base+"/api/ws", opts)
+	if err != nil {
+		return fmt.Errorf("failed to connect to event stream: %w", err)
+	}
+	defer conn.Close(ws.StatusNormalClosure, "")
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("event stream closed: %w", err)
+		}
+
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to parse event: %w", err)
+		}
+		handler(event)
+	}
+}
+
+// post sends a JSON action request
+func (c *Client) post(ctx context.Context, path string, body interface{}) (ActionResponse, error) {
+	var resp ActionResponse
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+c.base+path, bytes.NewReader(data))
+	if err != nil {
+		return resp, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	err = c.do(req, &resp)
+	return resp, err
+}
+
+// do sends req with the token and decodes a JSON response into v
+func (c *Client) do(req *http.Request, v interface{}) error {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("companion unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %d %s", req.Method, req.URL.Path, resp.StatusCode, apiErr.Error)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,476 @@
+// Package api provides an opt-in local control API for the desktop companion.
+//
+// The server exposes a small REST surface (status, interactions, events, chat,
+// animation switching) plus a WebSocket event stream. It is intended for local
+// integrations such as OBS overlays, Stream Deck buttons and home automation,
+// so it binds to localhost unless told otherwise. Requests must name a local
+// host, POST JSON, and carry the bearer token when one is set.
+//
+// The same API served on a unix socket is how frontends attach to a companion
+// running as a daemon; Client is the Go side of that connection.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/sirupsen/logrus"
+	ws "nhooyr.io/websocket"
+)
+
+// statePollInterval controls how often the character state is sampled for
+// WebSocket subscribers. Animation changes are not pushed by the character,
+// so a light poll is the simplest way to notice them.
+const statePollInterval = 250 * time.Millisecond
+
+// Companion is the subset of character behaviour the API drives.
+// *character.Character satisfies it; tests use a fake.
+type Companion interface {
+	GetName() string
+	GetCurrentState() string
+	IsSleeping() bool
+	GetGameState() *character.GameState
+	GetAnimationCacheStats() character.AnimationCacheStats
+	HandleClick() string
+	HandleGameInteraction(interactionType string) string
+	HandleRomanceInteraction(interactionType string) string
+	HandleGeneralEvent(eventName string) string
+	HandleChatMessage(message string) string
+	ForceState(state string) error
+}
+
+// Event is a message streamed to WebSocket subscribers
+type Event struct {
+	Type     string    `json:"type"`               // "state", "interaction", "event", "chat", "animation"
+	Name     string    `json:"name,omitempty"`     // Interaction, event or animation name
+	State    string    `json:"state,omitempty"`    // Current animation state
+	Response string    `json:"response,omitempty"` // Character response text, if any
+	Time     time.Time `json:"time"`
+}
+
+// Status is the response body of GET /api/status
+type Status struct {
+	Name     string             `json:"name"`
+	State    string             `json:"state"`
+	Sleeping bool               `json:"sleeping"`
+	Stats    map[string]float64 `json:"stats,omitempty"`
+
+	// How often switching animations found the new one already decoded
+	AnimationCache character.AnimationCacheStats `json:"animationCache"`
+}
+
+// ActionResponse is returned by the POST endpoints
+type ActionResponse struct {
+	Response string `json:"response"`
+	State    string `json:"state"`
+}
+
+// Server is the local REST/WebSocket control API
+type Server struct {
+	companion  Companion
+	server     *http.Server
+	host       string // Bind host, also accepted in the Host header
+	socketPath string // Unix socket to listen on instead of TCP
+
+	mu          sync.Mutex
+	token       string // Required bearer token, empty allows any local client
+	subscribers map[chan Event]struct{}
+	listener    net.Listener
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewServer creates an API server for the companion listening on addr.
+// A bare port such as ":8080" is bound to localhost only; "unix:PATH"
+// listens on a unix socket that only the current user can connect to.
+func NewServer(addr string, companion Companion) (*Server, error) {
+	if companion == nil {
+		return nil, fmt.Errorf("companion cannot be nil")
+	}
+
+	s := &Server{
+		companion:   companion,
+		subscribers: make(map[chan Event]struct{}),
+	}
+
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		if path == "" {
+			return nil, fmt.Errorf("invalid API address %q: socket path is empty", addr)
+		}
+		s.host = "localhost"
+		s.socketPath = path
+	} else {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API address %q: %w", addr, err)
+		}
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		s.host = host
+		addr = net.JoinHostPort(host, port)
+	}
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving all API routes behind the
+// request checks
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/click", s.handleClick)
+	mux.HandleFunc("/api/interact", s.handleInteract)
+	mux.HandleFunc("/api/event", s.handleEvent)
+	mux.HandleFunc("/api/chat", s.handleChat)
+	mux.HandleFunc("/api/animation", s.handleAnimation)
+	mux.HandleFunc("/api/ws", s.handleWebSocket)
+	return s.guard(mux)
+}
+
+// Start begins listening and serving in the background
+func (s *Server) Start() error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	s.listener = listener
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.mu.Unlock()
+
+	go s.watchState(ctx)
+	go func() {
+		defer close(s.done)
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.WithError(err).Error("API server stopped unexpectedly")
+		}
+	}()
+
+	logrus.WithField("addr", listener.Addr().String()).Info("Companion API server listening")
+	return nil
+}
+
+// listen opens the TCP port or unix socket the server was created for
+func (s *Server) listen() (net.Listener, error) {
+	if s.socketPath == "" {
+		listener, err := net.Listen("tcp", s.server.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+		}
+		return listener, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	// A socket left behind by a crash is removed, but never a live daemon's
+	if conn, err := net.DialTimeout("unix", s.socketPath, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a companion is already serving %s", s.socketPath)
+	}
+	if err := os.Remove(s.socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", s.socketPath, err)
+	}
+	if err := os.Chmod(s.socketPath, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+// Addr returns the address the server is listening on, or the configured
+// address if it has not been started
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.server.Addr
+}
+
+// Stop shuts the server down and disconnects WebSocket subscribers
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	ctx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down API server: %w", err)
+	}
+	<-done
+	return nil
+}
+
+// Publish sends an event to every WebSocket subscriber.
+// Slow subscribers drop events rather than blocking the caller.
+func (s *Server) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// subscribe registers a new event channel
+func (s *Server) subscribe() chan Event {
+	ch := make(chan Event, 32)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes an event channel
+func (s *Server) unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	delete(s.subscribers, ch)
+	s.mu.Unlock()
+}
+
+// watchState publishes a "state" event whenever the animation state changes
+func (s *Server) watchState(ctx context.Context) {
+	ticker := time.NewTicker(statePollInterval)
+	defer ticker.Stop()
+
+	lastState := s.companion.GetCurrentState()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			state := s.companion.GetCurrentState()
+			if state != lastState {
+				lastState = state
+				s.Publish(Event{Type: "state", State: state})
+			}
+		}
+	}
+}
+
+// handleStatus returns name, state and stats
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	status := Status{
+		Name:           s.companion.GetName(),
+		State:          s.companion.GetCurrentState(),
+		Sleeping:       s.companion.IsSleeping(),
+		AnimationCache: s.companion.GetAnimationCacheStats(),
+	}
+	if gameState := s.companion.GetGameState(); gameState != nil {
+		status.Stats = gameState.GetStats()
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleClick simulates a left click on the character
+func (s *Server) handleClick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	response := s.companion.HandleClick()
+	s.respondAction(w, Event{Type: "interaction", Name: "click", Response: response})
+}
+
+// handleInteract triggers a game or romance interaction by name
+func (s *Server) handleInteract(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type string `json:"type"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Type == "" {
+		writeError(w, http.StatusBadRequest, "type is required")
+		return
+	}
+
+	response := s.companion.HandleGameInteraction(req.Type)
+	if response == "" {
+		response = s.companion.HandleRomanceInteraction(req.Type)
+	}
+	s.respondAction(w, Event{Type: "interaction", Name: req.Type, Response: response})
+}
+
+// handleEvent triggers a general dialog event by name
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	response := s.companion.HandleGeneralEvent(req.Name)
+	s.respondAction(w, Event{Type: "event", Name: req.Name, Response: response})
+}
+
+// handleChat sends a chat message to the character
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Message string `json:"message"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Message == "" {
+		writeError(w, http.StatusBadRequest, "message is required")
+		return
+	}
+
+	response := s.companion.HandleChatMessage(req.Message)
+	s.respondAction(w, Event{Type: "chat", Response: response})
+}
+
+// handleAnimation switches the character to the requested animation
+func (s *Server) handleAnimation(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		State string `json:"state"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.State == "" {
+		writeError(w, http.StatusBadRequest, "state is required")
+		return
+	}
+
+	if err := s.companion.ForceState(req.State); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	s.respondAction(w, Event{Type: "animation", Name: req.State})
+}
+
+// handleWebSocket streams events to the client until it disconnects
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := ws.Accept(w, r, nil)
+	if err != nil {
+		logrus.WithError(err).Debug("API WebSocket upgrade failed")
+		return
+	}
+	defer conn.Close(ws.StatusNormalClosure, "")
+
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	// CloseRead handles pings and returns a context cancelled on disconnect
+	ctx := conn.CloseRead(r.Context())
+
+	initial := Event{Type: "state", State: s.companion.GetCurrentState(), Time: time.Now()}
+	if err := writeEvent(ctx, conn, initial); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			if err := writeEvent(ctx, conn, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// respondAction publishes the event and writes the action response
+func (s *Server) respondAction(w http.ResponseWriter, event Event) {
+	event.State = s.companion.GetCurrentState()
+	s.Publish(event)
+	writeJSON(w, http.StatusOK, ActionResponse{Response: event.Response, State: event.State})
+}
+
+// writeEvent sends a single JSON event over the WebSocket
+func writeEvent(ctx context.Context, conn *ws.Conn, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return conn.Write(writeCtx, ws.MessageText, data)
+}
+
+// decodeRequest enforces POST and decodes the JSON body; guard has
+// already checked the Content-Type.
+// Returns false after writing an error response.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.WithError(err).Debug("Failed to write API response")
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
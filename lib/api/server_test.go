@@ -0,0 +1,383 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	ws "nhooyr.io/websocket"
+)
+
+// fakeCompanion records API calls without needing animation assets
+type fakeCompanion struct {
+	mu        sync.Mutex
+	state     string
+	gameState *character.GameState
+	chats     []string
+}
+
+func (f *fakeCompanion) GetName() string { return "Tester" }
+
+func (f *fakeCompanion) GetCurrentState() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state
+}
+
+func (f *fakeCompanion) IsSleeping() bool                       { return false }
+func (f *fakeCompanion) GetGameState() *character.GameState     { return f.gameState }
+func (f *fakeCompanion) HandleClick() string                    { return "Hi!" }
+func (f *fakeCompanion) HandleRomanceInteraction(string) string { return "" }
+func (f *fakeCompanion) HandleGeneralEvent(name string) string  { return "event " + name }
+
+func (f *fakeCompanion) GetAnimationCacheStats() character.AnimationCacheStats {
+	return character.AnimationCacheStats{Hits: 3, Misses: 1, HitRate: 0.75}
+}
+
+func (f *fakeCompanion) HandleGameInteraction(interactionType string) string {
+	if interactionType == "feed" {
+		return "Yum!"
+	}
+	return ""
+}
+
+func (f *fakeCompanion) HandleChatMessage(message string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chats = append(f.chats, message)
+	return "You said: " + message
+}
+
+func (f *fakeCompanion) ForceState(state string) error {
+	if state == "missing" {
+		return fmt.Errorf("animation %s not found", state)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = state
+	return nil
+}
+
+func newTestServer(t *testing.T) (*Server, *fakeCompanion) {
+	t.Helper()
+	companion := &fakeCompanion{
+		state: "idle",
+		gameState: character.NewGameState(map[string]character.StatConfig{
+			"hunger": {Initial: 80, Max: 100},
+		}, nil),
+	}
+	server, err := NewServer(":0", companion)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	return server, companion
+}
+
+func post(t *testing.T, handler http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "http://localhost"+path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewServerBindsLocalhost(t *testing.T) {
+	server, _ := newTestServer(t)
+	if server.Addr() != "127.0.0.1:0" {
+		t.Errorf("Expected bare port to bind localhost, got %s", server.Addr())
+	}
+
+	if _, err := NewServer("not-an-address", &fakeCompanion{}); err == nil {
+		t.Error("Expected error for invalid address")
+	}
+	if _, err := NewServer(":0", nil); err == nil {
+		t.Error("Expected error for nil companion")
+	}
+}
+
+func TestStatusEndpoint(t *testing.T) {
+	server, _ := newTestServer(t)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/api/status", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var status Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if status.Name != "Tester" || status.State != "idle" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.Stats["hunger"] != 80 {
+		t.Errorf("Expected hunger stat 80, got %v", status.Stats["hunger"])
+	}
+	if status.AnimationCache.HitRate != 0.75 {
+		t.Errorf("Expected the animation cache hit rate, got %+v", status.AnimationCache)
+	}
+}
+
+func TestActionEndpoints(t *testing.T) {
+	server, companion := newTestServer(t)
+	handler := server.Handler()
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		wantCode     int
+		wantResponse string
+	}{
+		{"interaction", "/api/interact", `{"type":"feed"}`, http.StatusOK, "Yum!"},
+		{"missing interaction type", "/api/interact", `{}`, http.StatusBadRequest, ""},
+		{"event", "/api/event", `{"name":"daily_check_in"}`, http.StatusOK, "event daily_check_in"},
+		{"chat", "/api/chat", `{"message":"hello"}`, http.StatusOK, "You said: hello"},
+		{"animation", "/api/animation", `{"state":"happy"}`, http.StatusOK, ""},
+		{"unknown animation", "/api/animation", `{"state":"missing"}`, http.StatusNotFound, ""},
+		{"invalid json", "/api/chat", `{`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post(t, handler, tt.path, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp ActionResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Response != tt.wantResponse {
+				t.Errorf("Expected response %q, got %q", tt.wantResponse, resp.Response)
+			}
+		})
+	}
+
+	if companion.GetCurrentState() != "happy" {
+		t.Errorf("Expected animation to switch to happy, got %s", companion.GetCurrentState())
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/api/chat", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET on action endpoint, got %d", rec.Code)
+	}
+}
+
+func TestWebSocketStreamsEvents(t *testing.T) {
+	server, _ := newTestServer(t)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := ws.Dial(ctx, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/api/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close(ws.StatusNormalClosure, "")
+
+	readEvent := func() Event {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		return event
+	}
+
+	if initial := readEvent(); initial.Type != "state" || initial.State != "idle" {
+		t.Errorf("Unexpected initial event: %+v", initial)
+	}
+
+	post(t, server.Handler(), "/api/chat", `{"message":"ping"}`)
+
+	event := readEvent()
+	if event.Type != "chat" || event.Response != "You said: ping" {
+		t.Errorf("Unexpected chat event: %+v", event)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	server, _ := newTestServer(t)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	resp, err := http.Get("http://" + server.Addr() + "/api/status")
+	if err != nil {
+		t.Fatalf("GET status failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+}
+
+func TestRejectsForgeableRequests(t *testing.T) {
+	server, companion := newTestServer(t)
+	server.SetToken("secret")
+	handler := server.Handler()
+
+	request := func(host, contentType, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "http://"+host+"/api/chat", strings.NewReader(`{"message":"hi"}`))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name, host, contentType, auth string
+		wantCode                      int
+	}{
+		{"dns rebinding", "evil.example:8765", "application/json", "Bearer secret", http.StatusForbidden},
+		{"lan address", "192.168.1.20:8765", "application/json", "Bearer secret", http.StatusForbidden},
+		{"form post", "localhost:8765", "text/plain", "Bearer secret", http.StatusUnsupportedMediaType},
+		{"no content type", "127.0.0.1:8765", "", "Bearer secret", http.StatusUnsupportedMediaType},
+		{"missing token", "127.0.0.1:8765", "application/json", "", http.StatusUnauthorized},
+		{"wrong token", "[::1]:8765", "application/json", "Bearer guess", http.StatusUnauthorized},
+		{"allowed", "localhost:8765", "application/json; charset=utf-8", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := request(tt.host, tt.contentType, tt.auth); rec.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if len(companion.chats) != 1 {
+		t.Errorf("Expected only the allowed request to reach the character, got %v", companion.chats)
+	}
+
+	// WebSocket clients may pass the token in the query
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/api/status?token=secret", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the query token to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", TokenFileName)
+
+	token, err := LoadOrCreateToken(path)
+	if err != nil || len(token) != 64 {
+		t.Fatalf("Expected a new 64 character token, got %q, %v", token, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the token file to be private, got %v", info.Mode().Perm())
+	}
+
+	again, err := LoadOrCreateToken(path)
+	if err != nil || again != token {
+		t.Errorf("Expected the saved token back, got %q, %v", again, err)
+	}
+}
+
+func TestClientOverUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets not supported")
+	}
+
+	companion := &fakeCompanion{state: "idle", gameState: character.NewGameState(map[string]character.StatConfig{
+		"hunger": {Initial: 80, Max: 100},
+	}, nil)}
+	// Keep the path short: socket paths are limited to ~100 bytes
+	dir, err := os.MkdirTemp("", "dc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	addr := "unix:" + filepath.Join(dir, SocketFileName)
+
+	server, err := NewServer(addr, companion)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	server.SetToken("secret")
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Stop()
+
+	if second, _ := NewServer(addr, companion); second.Start() == nil {
+		second.Stop()
+		t.Error("Expected a second daemon on the same socket to be refused")
+	}
+
+	client, err := NewClient(addr, "secret")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status, err := client.Status(ctx)
+	if err != nil || status.Name != "Tester" || status.Stats["hunger"] != 80 {
+		t.Fatalf("Unexpected status: %+v, %v", status, err)
+	}
+
+	events := make(chan Event, 4)
+	go client.Subscribe(ctx, func(event Event) { events <- event })
+	if initial := <-events; initial.Type != "state" {
+		t.Errorf("Expected the current state first, got %+v", initial)
+	}
+
+	resp, err := client.Interact(ctx, "feed")
+	if err != nil || resp.Response != "Yum!" {
+		t.Errorf("Unexpected interact response: %+v, %v", resp, err)
+	}
+	select {
+	case event := <-events:
+		if event.Response != "Yum!" {
+			t.Errorf("Expected the interaction event, got %+v", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the interaction event")
+	}
+
+	if _, err := client.SetAnimation(ctx, "missing"); err == nil {
+		t.Error("Expected an error for an unknown animation")
+	}
+
+	unauthorized, _ := NewClient(addr, "")
+	if _, err := unauthorized.Status(ctx); err == nil {
+		t.Error("Expected the daemon to require the token over the socket")
+	}
+}
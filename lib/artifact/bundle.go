@@ -0,0 +1,386 @@
+package artifact
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	_ "image/gif"  // Character idle animations double as icons
+	_ "image/jpeg" // Icons may be JPEG too
+
+	"golang.org/x/image/draw"
+)
+
+// DefaultMinimumMacOSVersion is the oldest macOS Fyne apps run on
+const DefaultMinimumMacOSVersion = "10.13"
+
+// bundleIconName is the icon file in Contents/Resources, without .icns
+const bundleIconName = "AppIcon"
+
+// icnsSizes are the PNG icon types written to the .icns file, by edge length.
+// These types all hold PNG data and are understood since macOS 10.7.
+var icnsSizes = []struct {
+	osType string
+	size   int
+}{
+	{"icp4", 16},
+	{"icp5", 32},
+	{"icp6", 64},
+	{"ic07", 128},
+	{"ic08", 256},
+	{"ic09", 512},
+	{"ic10", 1024},
+}
+
+// DarwinBundleConfig describes a macOS .app bundle
+type DarwinBundleConfig struct {
+	Name                 string            // Bundle and display name, e.g. "Desktop Companion"
+	Identifier           string            // Reverse-DNS bundle ID, e.g. "ai.opd.dds"
+	Version              string            // CFBundleShortVersionString, e.g. "1.0.0"
+	Build                string            // CFBundleVersion (default: Version)
+	Executable           string            // Path to the darwin binary
+	Icon                 string            // .icns copied as is, or a PNG, GIF or JPEG converted to one
+	Assets               map[string]string // Directories copied into Contents/Resources, destination -> source
+	MinimumSystemVersion string            // LSMinimumSystemVersion (default: DefaultMinimumMacOSVersion)
+}
+
+// DarwinSigning configures the codesign and notarytool steps
+type DarwinSigning struct {
+	Identity      string // codesign identity, e.g. "Developer ID Application: Name (TEAMID)"
+	Entitlements  string // Optional entitlements plist
+	NotaryProfile string // Keychain profile saved with "xcrun notarytool store-credentials"
+}
+
+// runCommand runs an external tool, returning its output with any error.
+// Tests replace it, since codesign and notarytool only exist on macOS.
+var runCommand = func(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// validate checks the config has what every bundle needs
+func (c *DarwinBundleConfig) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("bundle name cannot be empty")
+	}
+	if c.Identifier == "" || strings.ContainsFunc(c.Identifier, func(r rune) bool {
+		return !(r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		return fmt.Errorf("bundle identifier %q must be reverse-DNS letters, digits, dots and hyphens", c.Identifier)
+	}
+	if c.Version == "" {
+		return fmt.Errorf("bundle version cannot be empty")
+	}
+	if c.Executable == "" {
+		return fmt.Errorf("bundle executable cannot be empty")
+	}
+	return nil
+}
+
+// BuildDarwinBundle writes Name.app into outputDir, replacing an existing
+// one, and returns its path. The bundle holds Info.plist, PkgInfo, the
+// executable in Contents/MacOS, the icon and the assets in
+// Contents/Resources.
+func BuildDarwinBundle(cfg DarwinBundleConfig, outputDir string) (string, error) {
+	if err := cfg.validate(); err != nil {
+		return "", err
+	}
+	if cfg.Build == "" {
+		cfg.Build = cfg.Version
+	}
+	if cfg.MinimumSystemVersion == "" {
+		cfg.MinimumSystemVersion = DefaultMinimumMacOSVersion
+	}
+
+	appPath := filepath.Join(outputDir, cfg.Name+".app")
+	if err := os.RemoveAll(appPath); err != nil {
+		return "", fmt.Errorf("failed to remove old bundle: %w", err)
+	}
+	contents := filepath.Join(appPath, "Contents")
+	for _, dir := range []string{"MacOS", "Resources"} {
+		if err := os.MkdirAll(filepath.Join(contents, dir), 0o755); err != nil {
+			return "", fmt.Errorf("failed to create bundle directory: %w", err)
+		}
+	}
+
+	executable := filepath.Base(cfg.Executable)
+	if err := copyFileMode(cfg.Executable, filepath.Join(contents, "MacOS", executable), 0o755); err != nil {
+		return "", fmt.Errorf("failed to copy executable: %w", err)
+	}
+
+	iconFile := ""
+	if cfg.Icon != "" {
+		iconFile = bundleIconName + ".icns"
+		if err := writeBundleIcon(cfg.Icon, filepath.Join(contents, "Resources", iconFile)); err != nil {
+			return "", fmt.Errorf("failed to write icon: %w", err)
+		}
+	}
+
+	for dest, src := range cfg.Assets {
+		if err := copyDir(src, filepath.Join(contents, "Resources", filepath.FromSlash(dest))); err != nil {
+			return "", fmt.Errorf("failed to copy assets %s: %w", src, err)
+		}
+	}
+
+	plist, err := darwinInfoPlist(cfg, executable, iconFile)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(contents, "Info.plist"), plist, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write Info.plist: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(contents, "PkgInfo"), []byte("APPL????"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write PkgInfo: %w", err)
+	}
+
+	return appPath, nil
+}
+
+// darwinInfoPlist renders the bundle's Info.plist
+func darwinInfoPlist(cfg DarwinBundleConfig, executable, iconFile string) ([]byte, error) {
+	type entry struct{ key, value string }
+	entries := []entry{
+		{"CFBundleDevelopmentRegion", "en"},
+		{"CFBundleDisplayName", cfg.Name},
+		{"CFBundleExecutable", executable},
+		{"CFBundleIdentifier", cfg.Identifier},
+		{"CFBundleInfoDictionaryVersion", "6.0"},
+		{"CFBundleName", cfg.Name},
+		{"CFBundlePackageType", "APPL"},
+		{"CFBundleShortVersionString", cfg.Version},
+		{"CFBundleVersion", cfg.Build},
+		{"LSMinimumSystemVersion", cfg.MinimumSystemVersion},
+	}
+	if iconFile != "" {
+		entries = append(entries, entry{"CFBundleIconFile", iconFile})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	buf.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	for _, e := range entries {
+		buf.WriteString("\t<key>" + e.key + "</key>\n\t<string>")
+		if err := xml.EscapeText(&buf, []byte(e.value)); err != nil {
+			return nil, fmt.Errorf("failed to write Info.plist: %w", err)
+		}
+		buf.WriteString("</string>\n")
+	}
+	// Sharp text on Retina displays, and let laptops stay on the integrated GPU
+	buf.WriteString("\t<key>NSHighResolutionCapable</key>\n\t<true/>\n")
+	buf.WriteString("\t<key>NSSupportsAutomaticGraphicsSwitching</key>\n\t<true/>\n")
+	buf.WriteString("</dict>\n</plist>\n")
+	return buf.Bytes(), nil
+}
+
+// writeBundleIcon copies an .icns icon, or converts an image into one
+func writeBundleIcon(src, dst string) error {
+	if strings.EqualFold(filepath.Ext(src), ".icns") {
+		return copyFileMode(src, dst, 0o644)
+	}
+
+	img, err := decodeImageFile(src)
+	if err != nil {
+		return err
+	}
+	icns, err := encodeICNS(img)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, icns, 0o644)
+}
+
+// decodeImageFile decodes the first frame of a PNG, GIF or JPEG
+func decodeImageFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// scaleIcon scales img to a size x size square
+func scaleIcon(img image.Image, size int) image.Image {
+	scaled := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return scaled
+}
+
+// encodeICNS scales img to every icon size and packs the PNGs into an
+// .icns file: "icns", the total length, then type, length and data per
+// icon, lengths big-endian and including their 8-byte headers
+func encodeICNS(img image.Image) ([]byte, error) {
+	var body bytes.Buffer
+	for _, icon := range icnsSizes {
+		var data bytes.Buffer
+		if err := png.Encode(&data, scaleIcon(img, icon.size)); err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx icon: %w", icon.size, err)
+		}
+		body.WriteString(icon.osType)
+		_ = binary.Write(&body, binary.BigEndian, uint32(data.Len()+8))
+		body.Write(data.Bytes())
+	}
+
+	var icns bytes.Buffer
+	icns.WriteString("icns")
+	_ = binary.Write(&icns, binary.BigEndian, uint32(body.Len()+8))
+	icns.Write(body.Bytes())
+	return icns.Bytes(), nil
+}
+
+// SignDarwinBundle signs the bundle with codesign for the hardened runtime
+// notarization requires, then verifies the signature. Does nothing without
+// an identity.
+func SignDarwinBundle(appPath string, signing DarwinSigning) error {
+	if signing.Identity == "" {
+		return nil
+	}
+
+	args := []string{"--force", "--options", "runtime", "--timestamp", "--sign", signing.Identity}
+	if signing.Entitlements != "" {
+		args = append(args, "--entitlements", signing.Entitlements)
+	}
+	if err := runCommand("codesign", append(args, appPath)...); err != nil {
+		return err
+	}
+	return runCommand("codesign", "--verify", "--strict", "--verbose=2", appPath)
+}
+
+// NotarizeDarwinBundle submits the signed bundle to Apple's notary service,
+// waits for the verdict and staples the ticket to the bundle. Does nothing
+// without a notary profile.
+func NotarizeDarwinBundle(appPath string, signing DarwinSigning) error {
+	if signing.NotaryProfile == "" {
+		return nil
+	}
+	if signing.Identity == "" {
+		return fmt.Errorf("notarization needs a signed bundle, set a signing identity")
+	}
+
+	submission := appPath + ".notarize.zip"
+	if err := ZipDarwinBundle(appPath, submission); err != nil {
+		return err
+	}
+	defer os.Remove(submission)
+
+	if err := runCommand("xcrun", "notarytool", "submit", submission, "--keychain-profile", signing.NotaryProfile, "--wait"); err != nil {
+		return err
+	}
+	return runCommand("xcrun", "stapler", "staple", appPath)
+}
+
+// ZipDarwinBundle zips the bundle with the .app directory at the top, like
+// "ditto -c -k --keepParent", keeping the executable bit
+func ZipDarwinBundle(appPath, zipPath string) error {
+	if err := zipTree(appPath, zipPath); err != nil {
+		return fmt.Errorf("failed to zip bundle: %w", err)
+	}
+	return nil
+}
+
+// zipTree zips dir with dir itself as the top entry, keeping file modes
+func zipTree(dir, zipPath string) error {
+	out, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	writer := zip.NewWriter(out)
+	parent := filepath.Dir(dir)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+			_, err = writer.CreateHeader(header)
+			return err
+		}
+		header.Method = zip.Deflate
+
+		dst, err := writer.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(dst, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// copyFileMode copies src to dst with the given permissions
+func copyFileMode(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyDir copies a directory tree, keeping file permissions
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFileMode(path, target, info.Mode().Perm())
+	})
+}
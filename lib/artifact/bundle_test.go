@@ -0,0 +1,203 @@
+package artifact
+
+import (
+	"archive/zip"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestBundleInputs creates an executable, a PNG icon and a character
+// directory to bundle
+func writeTestBundleInputs(t *testing.T, dir string) DarwinBundleConfig {
+	t.Helper()
+
+	executable := filepath.Join(dir, "companion")
+	if err := os.WriteFile(executable, []byte("binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	icon := filepath.Join(dir, "icon.png")
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	img.Set(10, 10, color.RGBA{255, 0, 0, 255})
+	file, err := os.Create(icon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	characterDir := filepath.Join(dir, "default")
+	if err := os.MkdirAll(filepath.Join(characterDir, "animations"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(characterDir, "character.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return DarwinBundleConfig{
+		Name:       "Desktop Companion",
+		Identifier: "ai.opd.dds",
+		Version:    "1.2.0",
+		Executable: executable,
+		Icon:       icon,
+		Assets:     map[string]string{"assets/characters/default": characterDir},
+	}
+}
+
+func TestBuildDarwinBundle(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeTestBundleInputs(t, dir)
+
+	appPath, err := BuildDarwinBundle(cfg, filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("BuildDarwinBundle failed: %v", err)
+	}
+	if filepath.Base(appPath) != "Desktop Companion.app" {
+		t.Errorf("Expected the bundle to be named after the app, got %s", appPath)
+	}
+
+	contents := filepath.Join(appPath, "Contents")
+	info, err := os.Stat(filepath.Join(contents, "MacOS", "companion"))
+	if err != nil || info.Mode().Perm()&0o111 == 0 {
+		t.Errorf("Expected an executable in Contents/MacOS, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(contents, "Resources", "assets", "characters", "default", "character.json")); err != nil {
+		t.Errorf("Expected the character in Contents/Resources: %v", err)
+	}
+	if pkgInfo, _ := os.ReadFile(filepath.Join(contents, "PkgInfo")); string(pkgInfo) != "APPL????" {
+		t.Errorf("Unexpected PkgInfo %q", pkgInfo)
+	}
+
+	icns, err := os.ReadFile(filepath.Join(contents, "Resources", "AppIcon.icns"))
+	if err != nil || !strings.HasPrefix(string(icns), "icns") || !strings.Contains(string(icns), "ic10") {
+		t.Errorf("Expected an .icns icon with a 1024px image, got %v", err)
+	}
+
+	plist, err := os.ReadFile(filepath.Join(contents, "Info.plist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<key>CFBundleExecutable</key>\n\t<string>companion</string>",
+		"<key>CFBundleIdentifier</key>\n\t<string>ai.opd.dds</string>",
+		"<key>CFBundleVersion</key>\n\t<string>1.2.0</string>",
+		"<key>CFBundleIconFile</key>\n\t<string>AppIcon.icns</string>",
+		"<key>LSMinimumSystemVersion</key>\n\t<string>" + DefaultMinimumMacOSVersion + "</string>",
+	} {
+		if !strings.Contains(string(plist), want) {
+			t.Errorf("Info.plist is missing %q", want)
+		}
+	}
+
+	// Building again replaces the bundle
+	if _, err := BuildDarwinBundle(cfg, filepath.Join(dir, "out")); err != nil {
+		t.Errorf("Rebuilding the bundle failed: %v", err)
+	}
+}
+
+func TestBuildDarwinBundleRejectsBadConfig(t *testing.T) {
+	dir := t.TempDir()
+	valid := writeTestBundleInputs(t, dir)
+
+	tests := []struct {
+		name   string
+		modify func(*DarwinBundleConfig)
+	}{
+		{"no name", func(c *DarwinBundleConfig) { c.Name = "" }},
+		{"bad identifier", func(c *DarwinBundleConfig) { c.Identifier = "ai.opd/dds" }},
+		{"no version", func(c *DarwinBundleConfig) { c.Version = "" }},
+		{"missing executable", func(c *DarwinBundleConfig) { c.Executable = filepath.Join(dir, "missing") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if _, err := BuildDarwinBundle(cfg, filepath.Join(dir, "out")); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestZipDarwinBundleKeepsParentAndModes(t *testing.T) {
+	dir := t.TempDir()
+	appPath, err := BuildDarwinBundle(writeTestBundleInputs(t, dir), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(dir, "bundle.zip")
+	if err := ZipDarwinBundle(appPath, zipPath); err != nil {
+		t.Fatalf("ZipDarwinBundle failed: %v", err)
+	}
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	found := false
+	for _, file := range reader.File {
+		if file.Name == "Desktop Companion.app/Contents/MacOS/companion" {
+			found = true
+			if file.Mode().Perm()&0o111 == 0 {
+				t.Error("Expected the executable bit to survive zipping")
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected the executable under the .app directory in the zip")
+	}
+}
+
+func TestSignAndNotarizeDarwinBundle(t *testing.T) {
+	var commands []string
+	original := runCommand
+	runCommand = func(name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	defer func() { runCommand = original }()
+
+	dir := t.TempDir()
+	appPath, err := BuildDarwinBundle(writeTestBundleInputs(t, dir), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing configured, nothing run
+	if err := SignDarwinBundle(appPath, DarwinSigning{}); err != nil || len(commands) != 0 {
+		t.Fatalf("Expected signing to be skipped, ran %v (%v)", commands, err)
+	}
+	if err := NotarizeDarwinBundle(appPath, DarwinSigning{NotaryProfile: "notary"}); err == nil {
+		t.Error("Expected notarizing an unsigned bundle to fail")
+	}
+
+	signing := DarwinSigning{Identity: "Developer ID Application: Test", NotaryProfile: "notary"}
+	if err := SignDarwinBundle(appPath, signing); err != nil {
+		t.Fatal(err)
+	}
+	if err := NotarizeDarwinBundle(appPath, signing); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"codesign --force --options runtime", "codesign --verify", "xcrun notarytool submit", "xcrun stapler staple"}
+	if len(commands) != len(want) {
+		t.Fatalf("Expected %d commands, got %v", len(want), commands)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(commands[i], prefix) {
+			t.Errorf("Command %d = %q, want %q...", i, commands[i], prefix)
+		}
+	}
+	if _, err := os.Stat(appPath + ".notarize.zip"); !os.IsNotExist(err) {
+		t.Error("Expected the notarization zip to be cleaned up")
+	}
+}
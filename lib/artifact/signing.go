@@ -0,0 +1,269 @@
+package artifact
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReleaseManifestVersion is bumped when the manifest layout changes
+const ReleaseManifestVersion = 1
+
+// DefaultManifestName is the file sign writes into the artifacts directory.
+// It ends in .json so artifact walks already skip it.
+const DefaultManifestName = "release-manifest.json"
+
+// ManifestEntry describes one stored artifact in a release manifest
+type ManifestEntry struct {
+	Path     string            `json:"path"` // Relative to the artifacts directory, slash separated
+	Size     int64             `json:"size"`
+	Checksum string            `json:"checksum"` // SHA256 hex
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ReleaseManifest lists every stored artifact with its checksum
+type ReleaseManifest struct {
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Artifacts []ManifestEntry `json:"artifacts"`
+}
+
+// SignedManifest is a release manifest plus its ed25519 signature.
+// The signature covers the compact JSON encoding of Manifest.
+type SignedManifest struct {
+	Manifest  ReleaseManifest `json:"manifest"`
+	PublicKey string          `json:"public_key"` // Hex, informational only - verify against a trusted key
+	Signature string          `json:"signature"`  // Base64
+}
+
+// VerifyReport lists every problem found while verifying a manifest
+type VerifyReport struct {
+	Verified  int      // Artifacts whose size and checksum matched
+	Missing   []string // Listed in the manifest but not on disk
+	Modified  []string // Present but size or checksum differs
+	Unlisted  []string // On disk but not in the manifest
+	Signature bool     // Signature is valid for the trusted key
+}
+
+// OK reports whether the manifest verified cleanly
+func (r *VerifyReport) OK() bool {
+	return r.Signature && len(r.Missing) == 0 && len(r.Modified) == 0 && len(r.Unlisted) == 0
+}
+
+// GenerateSigningKey creates a new ed25519 key pair and writes the private
+// key to keyPath (0600) and the public key to keyPath + ".pub"
+func GenerateSigningKey(keyPath string) (ed25519.PublicKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	if err := os.WriteFile(keyPath, []byte(hex.EncodeToString(privateKey)+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(keyPath+".pub", []byte(hex.EncodeToString(publicKey)+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write public key: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// LoadSigningKey reads a hex encoded ed25519 private key
+func LoadSigningKey(keyPath string) (ed25519.PrivateKey, error) {
+	key, err := readHexKey(keyPath, ed25519.PrivateKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.PrivateKey(key), nil
+}
+
+// LoadPublicKey reads a hex encoded ed25519 public key
+func LoadPublicKey(keyPath string) (ed25519.PublicKey, error) {
+	key, err := readHexKey(keyPath, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// readHexKey reads a hex key file and checks its length
+func readHexKey(keyPath string, size int) ([]byte, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", keyPath, err)
+	}
+	if len(key) != size {
+		return nil, fmt.Errorf("invalid key file %s: expected %d bytes, got %d", keyPath, size, len(key))
+	}
+
+	return key, nil
+}
+
+// BuildManifest walks the artifacts directory and lists every stored
+// artifact with a freshly computed checksum
+func (m *Manager) BuildManifest() (*ReleaseManifest, error) {
+	entries, err := m.scanArtifacts()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &ReleaseManifest{
+		Version:   ReleaseManifestVersion,
+		CreatedAt: time.Now().UTC(),
+		Artifacts: make([]ManifestEntry, 0, len(entries)),
+	}
+	for _, path := range sortedKeys(entries) {
+		manifest.Artifacts = append(manifest.Artifacts, entries[path])
+	}
+
+	return manifest, nil
+}
+
+// SignManifest builds a manifest of the current artifacts and signs it
+func (m *Manager) SignManifest(privateKey ed25519.PrivateKey) (*SignedManifest, error) {
+	manifest, err := m.BuildManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return &SignedManifest{
+		Manifest:  *manifest,
+		PublicKey: hex.EncodeToString(privateKey.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, payload)),
+	}, nil
+}
+
+// VerifyManifest checks the manifest signature against a trusted public key
+// and compares every listed artifact with what is on disk
+func (m *Manager) VerifyManifest(signed *SignedManifest, trustedKey ed25519.PublicKey) (*VerifyReport, error) {
+	payload, err := json.Marshal(signed.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest signature: %w", err)
+	}
+
+	report := &VerifyReport{
+		Signature: ed25519.Verify(trustedKey, payload, signature),
+	}
+
+	actual, err := m.scanArtifacts()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, expected := range signed.Manifest.Artifacts {
+		entry, ok := actual[expected.Path]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, expected.Path)
+		case entry.Size != expected.Size || entry.Checksum != expected.Checksum:
+			report.Modified = append(report.Modified, expected.Path)
+		default:
+			report.Verified++
+		}
+		delete(actual, expected.Path)
+	}
+	report.Unlisted = sortedKeys(actual)
+
+	return report, nil
+}
+
+// SaveSignedManifest writes a signed manifest as indented JSON
+func SaveSignedManifest(path string, signed *SignedManifest) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest file: %w", err)
+	}
+	defer file.Close()
+
+	return writeJSON(file, signed)
+}
+
+// LoadSignedManifest reads a signed manifest from disk
+func LoadSignedManifest(path string) (*SignedManifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest file: %w", err)
+	}
+	defer file.Close()
+
+	var signed SignedManifest
+	if err := readJSON(file, &signed); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest file: %w", err)
+	}
+	return &signed, nil
+}
+
+// scanArtifacts hashes every artifact file, keyed by relative path.
+// Metadata sidecars and manifests (.json) are not artifacts.
+func (m *Manager) scanArtifacts() (map[string]ManifestEntry, error) {
+	entries := make(map[string]ManifestEntry)
+
+	err := filepath.Walk(m.artifactsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if m.shouldSkipFile(info, path) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(m.artifactsDir, path)
+		if err != nil {
+			return err
+		}
+		checksum, err := m.calculateChecksum(path)
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s: %w", rel, err)
+		}
+
+		entry := ManifestEntry{
+			Path:     filepath.ToSlash(rel),
+			Size:     info.Size(),
+			Checksum: checksum,
+		}
+		metadataPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+		if artifactInfo, err := m.loadMetadata(metadataPath); err == nil {
+			entry.Metadata = artifactInfo.Metadata
+		}
+
+		entries[entry.Path] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk artifacts directory: %w", err)
+	}
+
+	return entries, nil
+}
+
+// sortedKeys returns map keys in a stable order
+func sortedKeys(entries map[string]ManifestEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
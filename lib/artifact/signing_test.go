@@ -0,0 +1,157 @@
+package artifact
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newSignedTestManager stores two artifacts and returns a manager plus a key pair path
+func newSignedTestManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+
+	manager, err := NewManager(filepath.Join(t.TempDir(), "artifacts"))
+	if err != nil {
+		t.Fatalf("Failed to create artifact manager: %v", err)
+	}
+
+	srcDir := t.TempDir()
+	for _, platform := range []string{"linux", "windows"} {
+		src := filepath.Join(srcDir, "companion_"+platform)
+		if err := os.WriteFile(src, []byte("binary for "+platform), 0o644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if _, err := manager.StoreArtifact(src, "default", platform, "amd64", map[string]string{"version": "1.0.0"}); err != nil {
+			t.Fatalf("Failed to store artifact: %v", err)
+		}
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "release.key")
+	if _, err := GenerateSigningKey(keyPath); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return manager, keyPath
+}
+
+// signAndReload signs the artifacts and round-trips the manifest through disk
+func signAndReload(t *testing.T, manager *Manager, keyPath string) *SignedManifest {
+	t.Helper()
+
+	privateKey, err := LoadSigningKey(keyPath)
+	if err != nil {
+		t.Fatalf("Failed to load signing key: %v", err)
+	}
+	signed, err := manager.SignManifest(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to sign manifest: %v", err)
+	}
+
+	manifestPath := filepath.Join(manager.artifactsDir, DefaultManifestName)
+	if err := SaveSignedManifest(manifestPath, signed); err != nil {
+		t.Fatalf("Failed to save manifest: %v", err)
+	}
+	loaded, err := LoadSignedManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	return loaded
+}
+
+func TestSignAndVerifyManifest(t *testing.T) {
+	manager, keyPath := newSignedTestManager(t)
+	signed := signAndReload(t, manager, keyPath)
+
+	if len(signed.Manifest.Artifacts) != 2 {
+		t.Fatalf("Expected 2 artifacts in manifest, got %d", len(signed.Manifest.Artifacts))
+	}
+	if signed.Manifest.Artifacts[0].Metadata["version"] != "1.0.0" {
+		t.Errorf("Expected artifact metadata in manifest, got %+v", signed.Manifest.Artifacts[0])
+	}
+
+	publicKey, err := LoadPublicKey(keyPath + ".pub")
+	if err != nil {
+		t.Fatalf("Failed to load public key: %v", err)
+	}
+	report, err := manager.VerifyManifest(signed, publicKey)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK() || report.Verified != 2 {
+		t.Errorf("Expected clean verification, got %+v", report)
+	}
+}
+
+func TestVerifyManifestDetectsTampering(t *testing.T) {
+	manager, keyPath := newSignedTestManager(t)
+	signed := signAndReload(t, manager, keyPath)
+	publicKey, _ := LoadPublicKey(keyPath + ".pub")
+
+	tampered := filepath.Join(manager.artifactsDir, filepath.FromSlash(signed.Manifest.Artifacts[0].Path))
+	if err := os.WriteFile(tampered, []byte("malicious binary"), 0o644); err != nil {
+		t.Fatalf("Failed to tamper with artifact: %v", err)
+	}
+	if err := os.Remove(filepath.Join(manager.artifactsDir, filepath.FromSlash(signed.Manifest.Artifacts[1].Path))); err != nil {
+		t.Fatalf("Failed to remove artifact: %v", err)
+	}
+	extra := filepath.Join(manager.artifactsDir, "default", "extra.bin")
+	if err := os.WriteFile(extra, []byte("unexpected"), 0o644); err != nil {
+		t.Fatalf("Failed to add artifact: %v", err)
+	}
+
+	report, err := manager.VerifyManifest(signed, publicKey)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if report.OK() {
+		t.Fatal("Expected verification to fail")
+	}
+	if !report.Signature {
+		t.Error("Signature should still be valid")
+	}
+	if len(report.Modified) != 1 || len(report.Missing) != 1 {
+		t.Errorf("Expected one modified and one missing artifact, got %+v", report)
+	}
+	if len(report.Unlisted) != 1 || report.Unlisted[0] != "default/extra.bin" {
+		t.Errorf("Expected extra.bin to be unlisted, got %v", report.Unlisted)
+	}
+}
+
+func TestVerifyManifestRejectsBadSignature(t *testing.T) {
+	manager, keyPath := newSignedTestManager(t)
+	signed := signAndReload(t, manager, keyPath)
+
+	otherKey := filepath.Join(t.TempDir(), "other.key")
+	otherPublic, err := GenerateSigningKey(otherKey)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	report, err := manager.VerifyManifest(signed, otherPublic)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if report.Signature || report.OK() {
+		t.Error("Expected signature from a different key to be rejected")
+	}
+
+	// Editing the manifest itself must break the signature too
+	publicKey, _ := LoadPublicKey(keyPath + ".pub")
+	signed.Manifest.Artifacts[0].Checksum = "deadbeef"
+	report, err = manager.VerifyManifest(signed, publicKey)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if report.Signature {
+		t.Error("Expected edited manifest to fail signature check")
+	}
+}
+
+func TestLoadKeyRejectsWrongSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.key")
+	if err := os.WriteFile(path, []byte("abcd\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	if _, err := LoadSigningKey(path); err == nil {
+		t.Error("Expected error for short key")
+	}
+}
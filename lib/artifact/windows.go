@@ -0,0 +1,289 @@
+package artifact
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// windowsIconFile is the icon written next to the executable
+const windowsIconFile = "icon.ico"
+
+// WindowsInstallerScript is the NSIS script written into installer packages
+const WindowsInstallerScript = "installer.nsi"
+
+// icoSizes are the icon sizes written to the .ico file. Windows picks 16
+// and 32 for lists, 48 for icon views and 256 for large tiles.
+var icoSizes = []int{16, 32, 48, 256}
+
+// WindowsPackageConfig describes a Windows package
+type WindowsPackageConfig struct {
+	Name       string            // Folder, shortcut and installer name, e.g. "Desktop Companion"
+	Identifier string            // Registry key for the uninstaller and autostart, e.g. "ai.opd.dds"
+	Publisher  string            // Shown in Apps & features
+	Version    string            // e.g. "1.0.0"
+	Executable string            // Path to the windows .exe
+	Icon       string            // .ico copied as is, or a PNG, GIF or JPEG converted to one
+	Assets     map[string]string // Directories copied next to the executable, destination -> source
+	Installer  bool              // Also write an NSIS installer script
+}
+
+// validate checks the config has what every package needs
+func (c *WindowsPackageConfig) validate() error {
+	if c.Name == "" || strings.ContainsAny(c.Name, `<>:"/\|?*`) {
+		return fmt.Errorf("package name %q must be a valid folder name", c.Name)
+	}
+	if c.Identifier == "" || strings.ContainsAny(c.Identifier, `\"`) {
+		return fmt.Errorf("package identifier %q must be a valid registry key", c.Identifier)
+	}
+	if c.Version == "" {
+		return fmt.Errorf("package version cannot be empty")
+	}
+	if !strings.EqualFold(filepath.Ext(c.Executable), ".exe") {
+		return fmt.Errorf("package executable %q must be an .exe", c.Executable)
+	}
+	return nil
+}
+
+// BuildWindowsPackage writes the Name folder into outputDir, replacing an
+// existing one, and returns its path. The folder holds the executable, its
+// icon and the assets, which is all the portable zip needs; with Installer
+// set it also holds an NSIS script that installs the same files.
+func BuildWindowsPackage(cfg WindowsPackageConfig, outputDir string) (string, error) {
+	if err := cfg.validate(); err != nil {
+		return "", err
+	}
+
+	packageDir := filepath.Join(outputDir, cfg.Name)
+	if err := os.RemoveAll(packageDir); err != nil {
+		return "", fmt.Errorf("failed to remove old package: %w", err)
+	}
+	if err := os.MkdirAll(packageDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create package directory: %w", err)
+	}
+
+	executable := filepath.Base(cfg.Executable)
+	if err := copyFileMode(cfg.Executable, filepath.Join(packageDir, executable), 0o755); err != nil {
+		return "", fmt.Errorf("failed to copy executable: %w", err)
+	}
+
+	iconFile := ""
+	if cfg.Icon != "" {
+		iconFile = windowsIconFile
+		if err := writeWindowsIcon(cfg.Icon, filepath.Join(packageDir, iconFile)); err != nil {
+			return "", fmt.Errorf("failed to write icon: %w", err)
+		}
+	}
+
+	for dest, src := range cfg.Assets {
+		if err := copyDir(src, filepath.Join(packageDir, filepath.FromSlash(dest))); err != nil {
+			return "", fmt.Errorf("failed to copy assets %s: %w", src, err)
+		}
+	}
+
+	if cfg.Installer {
+		script := nsisScript(cfg, executable, iconFile, topLevelDirs(cfg.Assets))
+		if err := os.WriteFile(filepath.Join(packageDir, WindowsInstallerScript), script, 0o644); err != nil {
+			return "", fmt.Errorf("failed to write installer script: %w", err)
+		}
+	}
+
+	return packageDir, nil
+}
+
+// ZipWindowsPackage zips the package with its folder at the top, so it
+// extracts into a single directory
+func ZipWindowsPackage(packageDir, zipPath string) error {
+	if err := zipTree(packageDir, zipPath); err != nil {
+		return fmt.Errorf("failed to zip package: %w", err)
+	}
+	return nil
+}
+
+// CompileWindowsInstaller runs makensis on the package's installer script
+// and returns the setup executable it wrote into the package folder.
+// makensis runs on Windows, Linux and macOS alike.
+func CompileWindowsInstaller(packageDir, makensis string) (string, error) {
+	script := filepath.Join(packageDir, WindowsInstallerScript)
+	if _, err := os.Stat(script); err != nil {
+		return "", fmt.Errorf("package has no installer script: %w", err)
+	}
+	if err := runCommand(makensis, "-V2", script); err != nil {
+		return "", err
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(packageDir, "* Setup *.exe"))
+	if len(matches) != 1 {
+		return "", fmt.Errorf("makensis did not write a setup executable in %s", packageDir)
+	}
+	return matches[0], nil
+}
+
+// nsisScript renders a per-user installer: the files go to
+// %LOCALAPPDATA%\Programs, so no administrator rights are needed, with a
+// Start Menu shortcut, an uninstaller registered in Apps & features, and an
+// unticked "Start with Windows" component writing the HKCU Run key
+func nsisScript(cfg WindowsPackageConfig, executable, iconFile string, assetDirs []string) []byte {
+	name := nsisEscape(cfg.Name)
+	exe := nsisEscape(executable)
+	uninstallKey := `Software\Microsoft\Windows\CurrentVersion\Uninstall\` + nsisEscape(cfg.Identifier)
+	runKey := `Software\Microsoft\Windows\CurrentVersion\Run`
+	shortcutDir := `$SMPROGRAMS\` + name
+
+	var buf bytes.Buffer
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&buf, format+"\n", args...)
+	}
+
+	line("; Generated by artifact-manager. Build the installer with: makensis %s", WindowsInstallerScript)
+	line("Unicode true")
+	line(`Name "%s"`, name)
+	line(`OutFile "%s Setup %s.exe"`, name, nsisEscape(cfg.Version))
+	line("RequestExecutionLevel user")
+	line(`InstallDir "$LOCALAPPDATA\Programs\%s"`, name)
+	line(`InstallDirRegKey HKCU "%s" "InstallLocation"`, uninstallKey)
+	if iconFile != "" {
+		line(`Icon "%s"`, iconFile)
+		line(`UninstallIcon "%s"`, iconFile)
+	}
+	line("")
+	line("Page components")
+	line("Page directory")
+	line("Page instfiles")
+	line("UninstPage uninstConfirm")
+	line("UninstPage instfiles")
+	line("")
+
+	line(`Section "%s" SecApp`, name)
+	line("  SectionIn RO")
+	line(`  SetOutPath "$INSTDIR"`)
+	line(`  File "%s"`, exe)
+	if iconFile != "" {
+		line(`  File "%s"`, iconFile)
+	}
+	for _, dir := range assetDirs {
+		line(`  File /r "%s"`, nsisEscape(dir))
+	}
+	line(`  WriteUninstaller "$INSTDIR\Uninstall.exe"`)
+	line(`  WriteRegStr HKCU "%s" "DisplayName" "%s"`, uninstallKey, name)
+	line(`  WriteRegStr HKCU "%s" "DisplayVersion" "%s"`, uninstallKey, nsisEscape(cfg.Version))
+	if cfg.Publisher != "" {
+		line(`  WriteRegStr HKCU "%s" "Publisher" "%s"`, uninstallKey, nsisEscape(cfg.Publisher))
+	}
+	if iconFile != "" {
+		line(`  WriteRegStr HKCU "%s" "DisplayIcon" "$INSTDIR\%s"`, uninstallKey, iconFile)
+	}
+	line(`  WriteRegStr HKCU "%s" "InstallLocation" "$INSTDIR"`, uninstallKey)
+	line(`  WriteRegStr HKCU "%s" "UninstallString" "$\"$INSTDIR\Uninstall.exe$\""`, uninstallKey)
+	line(`  WriteRegDWORD HKCU "%s" "NoModify" 1`, uninstallKey)
+	line(`  WriteRegDWORD HKCU "%s" "NoRepair" 1`, uninstallKey)
+	line("SectionEnd")
+	line("")
+
+	line(`Section "Start Menu shortcut" SecShortcut`)
+	line(`  CreateDirectory "%s"`, shortcutDir)
+	if iconFile != "" {
+		line(`  CreateShortCut "%s\%s.lnk" "$INSTDIR\%s" "" "$INSTDIR\%s"`, shortcutDir, name, exe, iconFile)
+	} else {
+		line(`  CreateShortCut "%s\%s.lnk" "$INSTDIR\%s"`, shortcutDir, name, exe)
+	}
+	line("SectionEnd")
+	line("")
+
+	line(`Section /o "Start with Windows" SecAutostart`)
+	line(`  WriteRegStr HKCU "%s" "%s" "$\"$INSTDIR\%s$\""`, runKey, nsisEscape(cfg.Identifier), exe)
+	line("SectionEnd")
+	line("")
+
+	line(`Section "Uninstall"`)
+	line(`  DeleteRegValue HKCU "%s" "%s"`, runKey, nsisEscape(cfg.Identifier))
+	line(`  Delete "%s\%s.lnk"`, shortcutDir, name)
+	line(`  RMDir "%s"`, shortcutDir)
+	line(`  Delete "$INSTDIR\%s"`, exe)
+	if iconFile != "" {
+		line(`  Delete "$INSTDIR\%s"`, iconFile)
+	}
+	for _, dir := range assetDirs {
+		line(`  RMDir /r "$INSTDIR\%s"`, nsisEscape(dir))
+	}
+	line(`  Delete "$INSTDIR\Uninstall.exe"`)
+	line(`  RMDir "$INSTDIR"`)
+	line(`  DeleteRegKey HKCU "%s"`, uninstallKey)
+	line("SectionEnd")
+
+	return buf.Bytes()
+}
+
+// nsisEscape escapes a value for a double-quoted NSIS string, where $
+// starts a variable and $\" is a literal quote
+func nsisEscape(s string) string {
+	return strings.NewReplacer("$", "$$", `"`, `$\"`).Replace(s)
+}
+
+// topLevelDirs returns the sorted top-level directories of the asset
+// destinations, which the installer copies and removes whole
+func topLevelDirs(assets map[string]string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for dest := range assets {
+		dir := strings.SplitN(filepath.ToSlash(dest), "/", 2)[0]
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// writeWindowsIcon copies an .ico icon, or converts an image into one
+func writeWindowsIcon(src, dst string) error {
+	if strings.EqualFold(filepath.Ext(src), ".ico") {
+		return copyFileMode(src, dst, 0o644)
+	}
+
+	img, err := decodeImageFile(src)
+	if err != nil {
+		return err
+	}
+	ico, err := encodeICO(img)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, ico, 0o644)
+}
+
+// encodeICO scales img to every icon size and packs the PNGs into an .ico
+// file: a 6-byte header, a 16-byte directory entry per icon, then the
+// images, all little-endian. Width and height 0 mean 256.
+func encodeICO(img image.Image) ([]byte, error) {
+	images := make([][]byte, len(icoSizes))
+	for i, size := range icoSizes {
+		var data bytes.Buffer
+		if err := png.Encode(&data, scaleIcon(img, size)); err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx icon: %w", size, err)
+		}
+		images[i] = data.Bytes()
+	}
+
+	var ico bytes.Buffer
+	_ = binary.Write(&ico, binary.LittleEndian, [3]uint16{0, 1, uint16(len(images))})
+	offset := 6 + 16*len(images)
+	for i, size := range icoSizes {
+		edge := uint8(size % 256)
+		ico.Write([]byte{edge, edge, 0, 0})
+		_ = binary.Write(&ico, binary.LittleEndian, [2]uint16{1, 32}) // Planes, bits per pixel
+		_ = binary.Write(&ico, binary.LittleEndian, [2]uint32{uint32(len(images[i])), uint32(offset)})
+		offset += len(images[i])
+	}
+	for _, data := range images {
+		ico.Write(data)
+	}
+	return ico.Bytes(), nil
+}
@@ -0,0 +1,156 @@
+package artifact
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestWindowsInputs reuses the bundle inputs with a windows executable
+func writeTestWindowsInputs(t *testing.T, dir string) WindowsPackageConfig {
+	t.Helper()
+
+	bundle := writeTestBundleInputs(t, dir)
+	executable := filepath.Join(dir, "companion.exe")
+	if err := os.Rename(bundle.Executable, executable); err != nil {
+		t.Fatal(err)
+	}
+
+	return WindowsPackageConfig{
+		Name:       bundle.Name,
+		Identifier: bundle.Identifier,
+		Publisher:  "opd-ai",
+		Version:    bundle.Version,
+		Executable: executable,
+		Icon:       bundle.Icon,
+		Assets:     bundle.Assets,
+		Installer:  true,
+	}
+}
+
+func TestBuildWindowsPackage(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeTestWindowsInputs(t, dir)
+
+	packageDir, err := BuildWindowsPackage(cfg, filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("BuildWindowsPackage failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(packageDir, "companion.exe")); err != nil {
+		t.Errorf("Expected the executable in the package: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(packageDir, "assets", "characters", "default", "character.json")); err != nil {
+		t.Errorf("Expected the character next to the executable: %v", err)
+	}
+
+	ico, err := os.ReadFile(filepath.Join(packageDir, "icon.ico"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ico) < 6 || binary.LittleEndian.Uint16(ico[2:]) != 1 || int(binary.LittleEndian.Uint16(ico[4:])) != len(icoSizes) {
+		t.Errorf("Expected an .ico header with %d images", len(icoSizes))
+	}
+
+	script, err := os.ReadFile(filepath.Join(packageDir, WindowsInstallerScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`OutFile "Desktop Companion Setup 1.2.0.exe"`,
+		`File "companion.exe"`,
+		`File /r "assets"`,
+		`CreateShortCut "$SMPROGRAMS\Desktop Companion\Desktop Companion.lnk" "$INSTDIR\companion.exe"`,
+		`Section /o "Start with Windows"`,
+		`WriteRegStr HKCU "Software\Microsoft\Windows\CurrentVersion\Run" "ai.opd.dds" "$\"$INSTDIR\companion.exe$\""`,
+		`DeleteRegValue HKCU "Software\Microsoft\Windows\CurrentVersion\Run" "ai.opd.dds"`,
+		`RMDir /r "$INSTDIR\assets"`,
+	} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("Installer script is missing %q", want)
+		}
+	}
+
+	zipPath := filepath.Join(dir, "portable.zip")
+	if err := ZipWindowsPackage(packageDir, zipPath); err != nil {
+		t.Fatalf("ZipWindowsPackage failed: %v", err)
+	}
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if !strings.HasPrefix(file.Name, "Desktop Companion/") {
+			t.Errorf("Expected every entry under the package folder, got %s", file.Name)
+		}
+	}
+}
+
+func TestBuildWindowsPackageRejectsBadConfig(t *testing.T) {
+	dir := t.TempDir()
+	valid := writeTestWindowsInputs(t, dir)
+
+	tests := []struct {
+		name   string
+		modify func(*WindowsPackageConfig)
+	}{
+		{"bad name", func(c *WindowsPackageConfig) { c.Name = "Desktop: Companion" }},
+		{"bad identifier", func(c *WindowsPackageConfig) { c.Identifier = `ai\opd` }},
+		{"no version", func(c *WindowsPackageConfig) { c.Version = "" }},
+		{"not an exe", func(c *WindowsPackageConfig) { c.Executable = filepath.Join(dir, "companion") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if _, err := BuildWindowsPackage(cfg, filepath.Join(dir, "out")); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestNSISEscape(t *testing.T) {
+	if got := nsisEscape(`Say "$hi"`); got != `Say $\"$$hi$\"` {
+		t.Errorf("nsisEscape = %q", got)
+	}
+}
+
+func TestCompileWindowsInstaller(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeTestWindowsInputs(t, dir)
+
+	cfg.Installer = false
+	portable, err := BuildWindowsPackage(cfg, filepath.Join(dir, "portable"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CompileWindowsInstaller(portable, "makensis"); err == nil {
+		t.Error("Expected compiling without an installer script to fail")
+	}
+
+	cfg.Installer = true
+	packageDir, err := BuildWindowsPackage(cfg, filepath.Join(dir, "installer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := runCommand
+	runCommand = func(name string, args ...string) error {
+		// Stand in for makensis writing OutFile next to the script
+		return os.WriteFile(filepath.Join(packageDir, "Desktop Companion Setup 1.2.0.exe"), nil, 0o644)
+	}
+	defer func() { runCommand = original }()
+
+	setup, err := CompileWindowsInstaller(packageDir, "makensis")
+	if err != nil {
+		t.Fatalf("CompileWindowsInstaller failed: %v", err)
+	}
+	if filepath.Base(setup) != "Desktop Companion Setup 1.2.0.exe" {
+		t.Errorf("Unexpected setup executable %s", setup)
+	}
+}
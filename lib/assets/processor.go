@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"image/color"
 	"image/color/palette"
 	"image/gif"
 	"image/png"
@@ -53,7 +54,7 @@ func (g *GIFAssembler) Process(ctx context.Context, frames []string, outPath str
 			return fmt.Errorf("decode png %s: %w", f, err)
 		}
 		// Resize if needed (skip for simplicity; assume correct size)
-		gifImg.Image = append(gifImg.Image, imageToPaletted(img))
+		gifImg.Image = append(gifImg.Image, imageToPaletted(img, cfg.Transparency))
 		gifImg.Delay = append(gifImg.Delay, 100/cfg.FrameRate)
 		if i >= cfg.FrameCount {
 			break
@@ -79,9 +80,14 @@ func (g *GIFAssembler) Process(ctx context.Context, frames []string, outPath str
 }
 
 // imageToPaletted converts image.Image to *image.Paletted for GIF encoding.
-func imageToPaletted(img image.Image) *image.Paletted {
+// With transparency, palette index 0 is reserved for transparent pixels.
+func imageToPaletted(img image.Image, transparency bool) *image.Paletted {
 	bounds := img.Bounds()
-	paletted := image.NewPaletted(bounds, palette.Plan9)
+	pal := color.Palette(palette.Plan9)
+	if transparency {
+		pal = append(color.Palette{color.Transparent}, palette.Plan9[:len(palette.Plan9)-1]...)
+	}
+	paletted := image.NewPaletted(bounds, pal)
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
 			paletted.Set(x, y, img.At(x, y))
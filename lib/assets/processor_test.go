@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"image/gif"
 	"image/png"
 	"os"
 	"testing"
@@ -83,3 +84,28 @@ func TestGIFAssembler_Process_ContextCancel(t *testing.T) {
 		t.Errorf("expected context canceled error, got %v", err)
 	}
 }
+
+func TestGIFAssembler_Process_KeepsTransparency(t *testing.T) {
+	dir := t.TempDir()
+	p := dir + "/frame.png"
+	if err := createTestPNG(p, 64, 64); err != nil {
+		t.Fatalf("create png: %v", err)
+	}
+	outPath := dir + "/out.gif"
+	cfg := GIFConfig{Width: 64, Height: 64, FrameCount: 1, FrameRate: 10, MaxFileSize: 500000, Transparency: true}
+	if err := (&GIFAssembler{}).Process(context.Background(), []string{p}, outPath, cfg); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	f, err := os.Open(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := g.Image[0].At(0, 0).RGBA(); a != 0 {
+		t.Errorf("expected transparent pixels to stay transparent, got alpha %d", a)
+	}
+}
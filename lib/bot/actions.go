@@ -16,8 +16,28 @@ const (
 	ActionChat    ActionType = "chat"    // Network chat with peers
 	ActionWait    ActionType = "wait"    // Passive waiting action
 	ActionObserve ActionType = "observe" // Watch peer interactions for learning
+	ActionBattle  ActionType = "battle"  // Challenge a peer to a battle
+	ActionGift    ActionType = "gift"    // Send a gift to a peer
 )
 
+// BattleController is implemented by network controllers that can challenge
+// peers to battles. Checked at runtime so plain NetworkControllers still work.
+type BattleController interface {
+	InitiateBattle(peerID string) error
+}
+
+// GiftController is implemented by network controllers that can send gifts
+// to peers. Returns the name of the gift that was sent.
+type GiftController interface {
+	SendGift(peerID string) (string, error)
+}
+
+// ChatComposer is implemented by character controllers that can write their
+// own chat lines for peers instead of the generic greeting.
+type ChatComposer interface {
+	ComposeChat(peerID string) string
+}
+
 // ActionResult contains the outcome of an executed action.
 // Used for learning and behavior adaptation.
 type ActionResult struct {
@@ -99,6 +119,10 @@ func (ae *ActionExecutor) ExecuteAction(decision BotDecision) (*ActionResult, er
 		response = ae.executeWaitAction(decision.Delay)
 	case ActionObserve:
 		response = ae.executeObserveAction()
+	case ActionBattle:
+		response, err = ae.executeBattleAction(decision.Target)
+	case ActionGift:
+		response, err = ae.executeGiftAction(decision.Target)
 	default:
 		return nil, fmt.Errorf("unknown action type: %s", actionType)
 	}
@@ -163,9 +187,12 @@ func (ae *ActionExecutor) executeChatAction(target string, metadata map[string]i
 		return "Chat not available - network disabled", nil
 	}
 
-	// Extract message from metadata
-	message, ok := metadata["message"].(string)
-	if !ok || message == "" {
+	// Extract message from metadata, or let the character write one
+	message, _ := metadata["message"].(string)
+	if composer, ok := ae.characterController.(ChatComposer); ok && message == "" {
+		message = composer.ComposeChat(target)
+	}
+	if message == "" {
 		message = "Hello!"
 	}
 
@@ -203,6 +230,41 @@ func (ae *ActionExecutor) executeChatAction(target string, metadata map[string]i
 	return fmt.Sprintf("Broadcasted message: %s", message), nil
 }
 
+// executeBattleAction challenges the target peer to a battle.
+// Requires a network controller that implements BattleController.
+func (ae *ActionExecutor) executeBattleAction(target string) (string, error) {
+	battles, ok := ae.networkController.(BattleController)
+	if !ok || !ae.networkController.IsNetworkEnabled() {
+		return "Battle not available - network disabled", nil
+	}
+	if target == "" {
+		return "", fmt.Errorf("battle needs a target peer")
+	}
+
+	if err := battles.InitiateBattle(target); err != nil {
+		return "", fmt.Errorf("failed to challenge %s: %w", target, err)
+	}
+	return fmt.Sprintf("Challenged %s to a battle", target), nil
+}
+
+// executeGiftAction sends a gift to the target peer.
+// Requires a network controller that implements GiftController.
+func (ae *ActionExecutor) executeGiftAction(target string) (string, error) {
+	gifts, ok := ae.networkController.(GiftController)
+	if !ok || !ae.networkController.IsNetworkEnabled() {
+		return "Gift not available - network disabled", nil
+	}
+	if target == "" {
+		return "", fmt.Errorf("gift needs a target peer")
+	}
+
+	name, err := gifts.SendGift(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to send gift to %s: %w", target, err)
+	}
+	return fmt.Sprintf("Sent %s to %s", name, target), nil
+}
+
 // executeWaitAction performs a passive wait, useful for natural timing.
 // Allows other activities to occur without bot interference.
 func (ae *ActionExecutor) executeWaitAction(duration time.Duration) string {
@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -65,6 +66,13 @@ type BotPersonality struct {
 	PreferredActions    []string `json:"preferredActions"`    // Actions this bot prefers
 }
 
+// Battles and gifts reach other people, so they wait much longer between
+// repeats than the personality's MinTimeBetweenSame
+var socialCooldowns = map[string]time.Duration{
+	string(ActionBattle): 5 * time.Minute,
+	string(ActionGift):   10 * time.Minute,
+}
+
 // BotController manages autonomous character behavior using personality-driven decision making.
 // Integrates with the existing Character.Update() cycle following the project's embedding pattern.
 //
@@ -107,13 +115,15 @@ func DefaultPersonality() BotPersonality {
 			"helpfulness": 0.8,
 			"playfulness": 0.5,
 			"curiosity":   0.7,
+			"generosity":  0.5,
 		},
 
 		EmotionalProfile: map[string]float64{
-			"empathy":       0.8,
-			"assertiveness": 0.4,
-			"patience":      0.7,
-			"enthusiasm":    0.6,
+			"empathy":        0.8,
+			"assertiveness":  0.4,
+			"patience":       0.7,
+			"enthusiasm":     0.6,
+			"aggressiveness": 0.3,
 		},
 
 		MaxActionsPerMinute: 5,
@@ -360,9 +370,47 @@ func (bc *BotController) generateNetworkActions(now time.Time) []BotDecision {
 		}
 	}
 
+	if aggressiveness := bc.trait(PersonalityTraits.Aggressiveness); aggressiveness > 0 {
+		if _, ok := bc.networkController.(BattleController); ok {
+			peerIDs := bc.networkController.GetPeerIDs()
+			if len(peerIDs) > 0 {
+				actions = append(actions, BotDecision{
+					Action:      string(ActionBattle),
+					Target:      peerIDs[bc.rng.Intn(len(peerIDs))],
+					Delay:       bc.calculateRandomDelay(),
+					Probability: aggressiveness * 0.5,
+					Priority:    3,
+				})
+			}
+		}
+	}
+
+	if generosity := bc.trait(PersonalityTraits.Generosity); generosity > 0 {
+		if _, ok := bc.networkController.(GiftController); ok {
+			peerIDs := bc.networkController.GetPeerIDs()
+			if len(peerIDs) > 0 {
+				actions = append(actions, BotDecision{
+					Action:      string(ActionGift),
+					Target:      peerIDs[bc.rng.Intn(len(peerIDs))],
+					Delay:       bc.calculateRandomDelay(),
+					Probability: generosity * 0.4,
+					Priority:    3,
+				})
+			}
+		}
+	}
+
 	return actions
 }
 
+// trait returns a personality trait from either trait map, 0 when unset
+func (bc *BotController) trait(name string) float64 {
+	if value, ok := bc.personality.SocialTendencies[name]; ok {
+		return value
+	}
+	return bc.personality.EmotionalProfile[name]
+}
+
 // filterRecentActions removes actions that violate minimum time constraints.
 // Prevents repetitive behavior that feels unnatural.
 func (bc *BotController) filterRecentActions(actions []BotDecision) []BotDecision {
@@ -370,7 +418,11 @@ func (bc *BotController) filterRecentActions(actions []BotDecision) []BotDecisio
 	minTime := time.Duration(bc.personality.MinTimeBetweenSame) * time.Second
 
 	for _, action := range actions {
-		if bc.canPerformAction(action.Action, minTime) {
+		wait := minTime
+		if cooldown, ok := socialCooldowns[action.Action]; ok && cooldown > wait {
+			wait = cooldown
+		}
+		if bc.canPerformAction(action.Action, wait) {
 			filtered = append(filtered, action)
 		}
 	}
@@ -382,6 +434,9 @@ func (bc *BotController) filterRecentActions(actions []BotDecision) []BotDecisio
 func (bc *BotController) canPerformAction(actionType string, minTime time.Duration) bool {
 	for i := len(bc.actionHistory) - 1; i >= 0; i-- {
 		if bc.actionHistory[i].Action == actionType {
+			if timestamp, ok := bc.actionHistory[i].Metadata["timestamp"].(time.Time); ok {
+				return time.Since(timestamp) >= minTime
+			}
 			return time.Since(bc.lastActionTime) >= minTime
 		}
 	}
@@ -465,8 +520,17 @@ func (bc *BotController) executeScheduledAction() bool {
 	return success
 }
 
-// recordAction adds an action to history with size limiting.
+// recordAction adds an action to history with size limiting. The action is
+// stamped with the current time for rate limiting and cooldowns.
 func (bc *BotController) recordAction(action BotDecision) {
+	metadata := make(map[string]interface{}, len(action.Metadata)+1)
+	for key, value := range action.Metadata {
+		metadata[key] = value
+	}
+	if _, ok := metadata["timestamp"]; !ok {
+		metadata["timestamp"] = time.Now()
+	}
+	action.Metadata = metadata
 	bc.actionHistory = append(bc.actionHistory, action)
 
 	// Limit history size to prevent unbounded growth
@@ -501,6 +565,34 @@ func (bc *BotController) isRateLimited() bool {
 	return recentActions >= bc.personality.MaxActionsPerMinute
 }
 
+// ShouldAcceptBattle decides whether to accept a battle invitation.
+// Aggressive bots take most challenges; a disabled bot declines them all.
+func (bc *BotController) ShouldAcceptBattle() bool {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if !bc.isEnabled {
+		return false
+	}
+	return bc.rng.Float64() < 0.2+0.8*bc.trait(PersonalityTraits.Aggressiveness)
+}
+
+// Run calls Update every interval until ctx is done. Lets the bot act from
+// its own goroutine, both headless and alongside the UI.
+func (bc *BotController) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bc.Update()
+		}
+	}
+}
+
 // GetPersonality returns a copy of the current personality configuration.
 func (bc *BotController) GetPersonality() BotPersonality {
 	bc.mu.RLock()
@@ -15,12 +15,14 @@ var PersonalityTraits = struct {
 	Helpfulness string
 	Playfulness string
 	Curiosity   string
+	Generosity  string
 
 	// Emotional characteristics
-	Empathy       string
-	Assertiveness string
-	Patience      string
-	Enthusiasm    string
+	Empathy        string
+	Assertiveness  string
+	Patience       string
+	Enthusiasm     string
+	Aggressiveness string
 
 	// Behavioral tendencies
 	Independence string
@@ -33,12 +35,14 @@ var PersonalityTraits = struct {
 	Helpfulness: "helpfulness",
 	Playfulness: "playfulness",
 	Curiosity:   "curiosity",
+	Generosity:  "generosity",
 
 	// Emotional traits
-	Empathy:       "empathy",
-	Assertiveness: "assertiveness",
-	Patience:      "patience",
-	Enthusiasm:    "enthusiasm",
+	Empathy:        "empathy",
+	Assertiveness:  "assertiveness",
+	Patience:       "patience",
+	Enthusiasm:     "enthusiasm",
+	Aggressiveness: "aggressiveness",
 
 	// Behavioral traits
 	Independence: "independence",
@@ -219,10 +223,12 @@ func (pm *PersonalityManager) CreatePersonality(archetype *PersonalityArchetype)
 	for trait, value := range archetype.Traits {
 		switch trait {
 		case PersonalityTraits.Chattiness, PersonalityTraits.Helpfulness,
-			PersonalityTraits.Playfulness, PersonalityTraits.Curiosity:
+			PersonalityTraits.Playfulness, PersonalityTraits.Curiosity,
+			PersonalityTraits.Generosity:
 			personality.SocialTendencies[trait] = value
 		case PersonalityTraits.Empathy, PersonalityTraits.Assertiveness,
-			PersonalityTraits.Patience, PersonalityTraits.Enthusiasm:
+			PersonalityTraits.Patience, PersonalityTraits.Enthusiasm,
+			PersonalityTraits.Aggressiveness:
 			personality.EmotionalProfile[trait] = value
 		default:
 			// Store unknown traits in SocialTendencies for backward compatibility
@@ -329,14 +335,16 @@ func (pm *PersonalityManager) loadBuiltinArchetypes() {
 		Name:        "Social",
 		Description: "Outgoing, chatty companion who loves interaction and helping others",
 		Traits: map[string]float64{
-			PersonalityTraits.Chattiness:    0.9,
-			PersonalityTraits.Helpfulness:   0.8,
-			PersonalityTraits.Playfulness:   0.7,
-			PersonalityTraits.Curiosity:     0.8,
-			PersonalityTraits.Empathy:       0.9,
-			PersonalityTraits.Enthusiasm:    0.8,
-			PersonalityTraits.Assertiveness: 0.6,
-			PersonalityTraits.Patience:      0.7,
+			PersonalityTraits.Chattiness:     0.9,
+			PersonalityTraits.Helpfulness:    0.8,
+			PersonalityTraits.Playfulness:    0.7,
+			PersonalityTraits.Curiosity:      0.8,
+			PersonalityTraits.Empathy:        0.9,
+			PersonalityTraits.Enthusiasm:     0.8,
+			PersonalityTraits.Assertiveness:  0.6,
+			PersonalityTraits.Patience:       0.7,
+			PersonalityTraits.Aggressiveness: 0.3,
+			PersonalityTraits.Generosity:     0.8,
 		},
 		Behavior: PersonalityBehavior{
 			ResponseDelay:       "1s-3s",
@@ -354,14 +362,16 @@ func (pm *PersonalityManager) loadBuiltinArchetypes() {
 		Name:        "Shy",
 		Description: "Quiet, thoughtful companion who prefers observation to interaction",
 		Traits: map[string]float64{
-			PersonalityTraits.Chattiness:    0.3,
-			PersonalityTraits.Helpfulness:   0.7,
-			PersonalityTraits.Playfulness:   0.4,
-			PersonalityTraits.Curiosity:     0.6,
-			PersonalityTraits.Empathy:       0.8,
-			PersonalityTraits.Enthusiasm:    0.4,
-			PersonalityTraits.Assertiveness: 0.2,
-			PersonalityTraits.Patience:      0.9,
+			PersonalityTraits.Chattiness:     0.3,
+			PersonalityTraits.Helpfulness:    0.7,
+			PersonalityTraits.Playfulness:    0.4,
+			PersonalityTraits.Curiosity:      0.6,
+			PersonalityTraits.Empathy:        0.8,
+			PersonalityTraits.Enthusiasm:     0.4,
+			PersonalityTraits.Assertiveness:  0.2,
+			PersonalityTraits.Patience:       0.9,
+			PersonalityTraits.Aggressiveness: 0.1,
+			PersonalityTraits.Generosity:     0.6,
 		},
 		Behavior: PersonalityBehavior{
 			ResponseDelay:       "3s-7s",
@@ -379,15 +389,17 @@ func (pm *PersonalityManager) loadBuiltinArchetypes() {
 		Name:        "Playful",
 		Description: "Energetic, fun-loving companion who enjoys games and playful interactions",
 		Traits: map[string]float64{
-			PersonalityTraits.Chattiness:    0.7,
-			PersonalityTraits.Helpfulness:   0.6,
-			PersonalityTraits.Playfulness:   0.9,
-			PersonalityTraits.Curiosity:     0.8,
-			PersonalityTraits.Empathy:       0.6,
-			PersonalityTraits.Enthusiasm:    0.9,
-			PersonalityTraits.Assertiveness: 0.7,
-			PersonalityTraits.Patience:      0.4,
-			PersonalityTraits.Spontaneity:   0.8,
+			PersonalityTraits.Chattiness:     0.7,
+			PersonalityTraits.Helpfulness:    0.6,
+			PersonalityTraits.Playfulness:    0.9,
+			PersonalityTraits.Curiosity:      0.8,
+			PersonalityTraits.Empathy:        0.6,
+			PersonalityTraits.Enthusiasm:     0.9,
+			PersonalityTraits.Assertiveness:  0.7,
+			PersonalityTraits.Patience:       0.4,
+			PersonalityTraits.Spontaneity:    0.8,
+			PersonalityTraits.Aggressiveness: 0.6,
+			PersonalityTraits.Generosity:     0.5,
 		},
 		Behavior: PersonalityBehavior{
 			ResponseDelay:       "500ms-2s",
@@ -405,15 +417,17 @@ func (pm *PersonalityManager) loadBuiltinArchetypes() {
 		Name:        "Helper",
 		Description: "Supportive, caring companion focused on helping and assisting users",
 		Traits: map[string]float64{
-			PersonalityTraits.Chattiness:    0.6,
-			PersonalityTraits.Helpfulness:   0.9,
-			PersonalityTraits.Playfulness:   0.5,
-			PersonalityTraits.Curiosity:     0.7,
-			PersonalityTraits.Empathy:       0.9,
-			PersonalityTraits.Enthusiasm:    0.7,
-			PersonalityTraits.Assertiveness: 0.5,
-			PersonalityTraits.Patience:      0.8,
-			PersonalityTraits.Analytical:    0.7,
+			PersonalityTraits.Chattiness:     0.6,
+			PersonalityTraits.Helpfulness:    0.9,
+			PersonalityTraits.Playfulness:    0.5,
+			PersonalityTraits.Curiosity:      0.7,
+			PersonalityTraits.Empathy:        0.9,
+			PersonalityTraits.Enthusiasm:     0.7,
+			PersonalityTraits.Assertiveness:  0.5,
+			PersonalityTraits.Patience:       0.8,
+			PersonalityTraits.Analytical:     0.7,
+			PersonalityTraits.Aggressiveness: 0.2,
+			PersonalityTraits.Generosity:     0.9,
 		},
 		Behavior: PersonalityBehavior{
 			ResponseDelay:       "2s-4s",
@@ -431,14 +445,16 @@ func (pm *PersonalityManager) loadBuiltinArchetypes() {
 		Name:        "Balanced",
 		Description: "Well-rounded companion with moderate traits suitable for most users",
 		Traits: map[string]float64{
-			PersonalityTraits.Chattiness:    0.6,
-			PersonalityTraits.Helpfulness:   0.7,
-			PersonalityTraits.Playfulness:   0.6,
-			PersonalityTraits.Curiosity:     0.7,
-			PersonalityTraits.Empathy:       0.7,
-			PersonalityTraits.Enthusiasm:    0.6,
-			PersonalityTraits.Assertiveness: 0.5,
-			PersonalityTraits.Patience:      0.7,
+			PersonalityTraits.Chattiness:     0.6,
+			PersonalityTraits.Helpfulness:    0.7,
+			PersonalityTraits.Playfulness:    0.6,
+			PersonalityTraits.Curiosity:      0.7,
+			PersonalityTraits.Empathy:        0.7,
+			PersonalityTraits.Enthusiasm:     0.6,
+			PersonalityTraits.Assertiveness:  0.5,
+			PersonalityTraits.Patience:       0.7,
+			PersonalityTraits.Aggressiveness: 0.4,
+			PersonalityTraits.Generosity:     0.5,
 		},
 		Behavior: PersonalityBehavior{
 			ResponseDelay:       "2s-4s",
@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// MockSocialNetworkController adds battles and gifts to MockNetworkController
+type MockSocialNetworkController struct {
+	*MockNetworkController
+	challenged []string
+	gifted     []string
+}
+
+func (m *MockSocialNetworkController) InitiateBattle(peerID string) error {
+	m.challenged = append(m.challenged, peerID)
+	return nil
+}
+
+func (m *MockSocialNetworkController) SendGift(peerID string) (string, error) {
+	m.gifted = append(m.gifted, peerID)
+	return "a flower", nil
+}
+
+func newSocialBot(t *testing.T, aggressiveness, generosity float64) (*BotController, *MockSocialNetworkController) {
+	personality := DefaultPersonality()
+	personality.EmotionalProfile[PersonalityTraits.Aggressiveness] = aggressiveness
+	personality.SocialTendencies[PersonalityTraits.Generosity] = generosity
+
+	net := &MockSocialNetworkController{MockNetworkController: NewMockNetworkController()}
+	net.SetEnabled(true)
+	net.SetPeers([]string{"peer1"})
+
+	bot, err := NewBotController(personality, NewMockCharacterController(), net)
+	if err != nil {
+		t.Fatalf("Failed to create bot: %v", err)
+	}
+	return bot, net
+}
+
+func TestBotController_SocialActionsFollowTraits(t *testing.T) {
+	bot, _ := newSocialBot(t, 0.8, 0)
+	actions := bot.generateNetworkActions(time.Now())
+	if !hasAction(actions, ActionBattle) || hasAction(actions, ActionGift) {
+		t.Errorf("Expected only a battle from an aggressive, stingy bot, got %+v", actions)
+	}
+
+	bot, _ = newSocialBot(t, 0, 0.8)
+	actions = bot.generateNetworkActions(time.Now())
+	if hasAction(actions, ActionBattle) || !hasAction(actions, ActionGift) {
+		t.Errorf("Expected only a gift from a peaceful, generous bot, got %+v", actions)
+	}
+
+	// Plain network controllers can't battle or gift
+	plain, err := NewBotController(bot.GetPersonality(), NewMockCharacterController(), NewMockNetworkController())
+	if err != nil {
+		t.Fatalf("Failed to create bot: %v", err)
+	}
+	plain.networkController.(*MockNetworkController).SetPeers([]string{"peer1"})
+	if actions := plain.generateNetworkActions(time.Now()); hasAction(actions, ActionGift) {
+		t.Error("Expected no gifts without a GiftController")
+	}
+}
+
+func TestActionExecutor_BattleAndGift(t *testing.T) {
+	bot, net := newSocialBot(t, 0.5, 0.5)
+
+	result, err := bot.actionExecutor.ExecuteAction(BotDecision{Action: string(ActionBattle), Target: "peer1"})
+	if err != nil || !result.Success || len(net.challenged) != 1 {
+		t.Errorf("Expected peer1 challenged, got %+v, %v", result, err)
+	}
+	result, err = bot.actionExecutor.ExecuteAction(BotDecision{Action: string(ActionGift), Target: "peer1"})
+	if err != nil || result.Response != "Sent a flower to peer1" {
+		t.Errorf("Expected a gift for peer1, got %+v, %v", result, err)
+	}
+	if _, err := bot.actionExecutor.ExecuteAction(BotDecision{Action: string(ActionGift)}); err == nil {
+		t.Error("Expected an error for a gift without a target")
+	}
+}
+
+func TestBotController_SocialCooldowns(t *testing.T) {
+	bot, _ := newSocialBot(t, 0.8, 0.8)
+	bot.recordAction(BotDecision{Action: string(ActionBattle), Target: "peer1"})
+
+	actions := bot.filterRecentActions(bot.generateNetworkActions(time.Now()))
+	if hasAction(actions, ActionBattle) {
+		t.Error("Expected no second battle while the cooldown runs")
+	}
+	if !hasAction(actions, ActionGift) {
+		t.Error("Expected gifts to have their own cooldown")
+	}
+}
+
+func TestBotController_ShouldAcceptBattle(t *testing.T) {
+	fierce, _ := newSocialBot(t, 1, 0)
+	for i := 0; i < 20; i++ {
+		if !fierce.ShouldAcceptBattle() {
+			t.Fatal("Expected a fully aggressive bot to accept every battle")
+		}
+	}
+
+	fierce.Disable()
+	if fierce.ShouldAcceptBattle() {
+		t.Error("Expected a disabled bot to decline battles")
+	}
+}
+
+func TestBotController_Run(t *testing.T) {
+	bot, _ := newSocialBot(t, 0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bot.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return once the context is done")
+	}
+}
+
+func hasAction(actions []BotDecision, action ActionType) bool {
+	for _, a := range actions {
+		if a.Action == string(action) {
+			return true
+		}
+	}
+	return false
+}
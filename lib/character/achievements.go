@@ -0,0 +1,99 @@
+package character
+
+import (
+	"fmt"
+	"time"
+)
+
+// AchievementStatus describes an achievement for list views
+type AchievementStatus struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Earned      bool   `json:"earned"`
+}
+
+// GetProgressionConfig returns the progression config including top-level achievements.
+// Returns nil when the card defines neither progression nor achievements.
+func (c *CharacterCard) GetProgressionConfig() *ProgressionConfig {
+	if len(c.Achievements) == 0 {
+		return c.Progression
+	}
+
+	merged := &ProgressionConfig{}
+	if c.Progression != nil {
+		merged.Levels = c.Progression.Levels
+		merged.Achievements = append(merged.Achievements, c.Progression.Achievements...)
+	}
+	merged.Achievements = append(merged.Achievements, c.Achievements...)
+
+	return merged
+}
+
+// validateAchievements validates the top-level achievements section
+func (c *CharacterCard) validateAchievements() error {
+	if len(c.Achievements) == 0 {
+		return nil
+	}
+
+	if !c.HasGameFeatures() {
+		return fmt.Errorf("achievements require stats to be defined")
+	}
+
+	seen := make(map[string]bool)
+	for i, achievement := range c.Achievements {
+		if seen[achievement.Name] {
+			return fmt.Errorf("achievement %d: duplicate name '%s'", i, achievement.Name)
+		}
+		seen[achievement.Name] = true
+
+		if err := c.validateProgressionAchievement(achievement, i); err != nil {
+			return fmt.Errorf("achievement %d (%s): %w", i, achievement.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// evaluateAchievementCondition evaluates the achievement's condition expression.
+// Evaluation errors (e.g. a stat removed from the card) simply fail the condition.
+func (ps *ProgressionState) evaluateAchievementCondition(condition string, gameState *GameState) bool {
+	if condition == "" {
+		return true
+	}
+
+	met, err := EvaluateCondition(condition, gameStateEnv{gs: gameState, now: time.Now()})
+	return err == nil && met
+}
+
+// GetAchievementStatuses lists every configured achievement and whether it has been earned
+func (ps *ProgressionState) GetAchievementStatuses() []AchievementStatus {
+	if ps == nil || ps.Config == nil {
+		return nil
+	}
+
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	statuses := make([]AchievementStatus, 0, len(ps.Config.Achievements))
+	for i := range ps.Config.Achievements {
+		achievement := &ps.Config.Achievements[i]
+		statuses = append(statuses, AchievementStatus{
+			Name:        achievement.Name,
+			Description: generateAchievementDescription(achievement),
+			Earned:      ps.hasAchievement(achievement.Name),
+		})
+	}
+
+	return statuses
+}
+
+// GetAchievementStatuses returns the character's achievements for display
+func (c *Character) GetAchievementStatuses() []AchievementStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.gameState == nil {
+		return nil
+	}
+	return c.gameState.Progression.GetAchievementStatuses()
+}
@@ -0,0 +1,106 @@
+package character
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConditionAchievementUnlocks(t *testing.T) {
+	gs := createTestGameState()
+	gs.SetProgression(&ProgressionConfig{
+		Achievements: []AchievementConfig{
+			{Name: "Well Fed", Description: "Fed three times", Condition: "interactions.feed >= 3 && hunger > 50"},
+			{Name: "Night Owl", Condition: "hunger > 1000"},
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		gs.RecordInteraction("feed")
+	}
+
+	// Must not deadlock while progression reads stats under the game state lock
+	done := make(chan struct{})
+	go func() {
+		gs.Update(time.Second)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("GameState.Update deadlocked while evaluating achievements")
+	}
+
+	recent := gs.GetRecentAchievements()
+	if len(recent) != 1 || recent[0].Name != "Well Fed" {
+		t.Fatalf("Expected Well Fed achievement, got %+v", recent)
+	}
+	if recent[0].Description != "Fed three times" {
+		t.Errorf("Expected configured description, got %q", recent[0].Description)
+	}
+
+	statuses := gs.Progression.GetAchievementStatuses()
+	if len(statuses) != 2 || !statuses[0].Earned || statuses[1].Earned {
+		t.Errorf("Unexpected achievement statuses: %+v", statuses)
+	}
+}
+
+func TestConditionAchievementDuration(t *testing.T) {
+	gs := createTestGameState()
+	gs.SetProgression(&ProgressionConfig{
+		Achievements: []AchievementConfig{
+			{Name: "Content", Condition: "happiness >= 50", Duration: 10},
+		},
+	})
+
+	gs.Update(time.Second)
+	if len(gs.GetRecentAchievements()) != 0 {
+		t.Fatal("Duration achievement should not unlock immediately")
+	}
+
+	gs.Update(15 * time.Second)
+	if recent := gs.GetRecentAchievements(); len(recent) != 1 {
+		t.Errorf("Expected achievement after duration elapsed, got %+v", recent)
+	}
+}
+
+func TestCardAchievementsValidation(t *testing.T) {
+	card := createTestGameCharacterCard()
+
+	card.Achievements = []AchievementConfig{{Name: "Happy", Condition: "happiness >= 90"}}
+	if err := card.validateAchievements(); err != nil {
+		t.Errorf("Valid achievement rejected: %v", err)
+	}
+
+	card.Achievements = []AchievementConfig{{Name: "Bad", Condition: "sparkle >= 90"}}
+	if err := card.validateAchievements(); err == nil {
+		t.Error("Expected error for unknown stat in condition")
+	}
+
+	card.Achievements = []AchievementConfig{{Name: "Broken", Condition: "happiness >="}}
+	if err := card.validateAchievements(); err == nil {
+		t.Error("Expected error for unparseable condition")
+	}
+
+	card.Achievements = []AchievementConfig{{Name: "Empty"}}
+	if err := card.validateAchievements(); err == nil {
+		t.Error("Expected error for achievement without requirement or condition")
+	}
+}
+
+func TestGetProgressionConfigMergesAchievements(t *testing.T) {
+	card := &CharacterCard{
+		Progression: &ProgressionConfig{
+			Levels:       []LevelConfig{{Name: "Baby", Size: 64}},
+			Achievements: []AchievementConfig{{Name: "A"}},
+		},
+		Achievements: []AchievementConfig{{Name: "B"}},
+	}
+
+	merged := card.GetProgressionConfig()
+	if len(merged.Levels) != 1 || len(merged.Achievements) != 2 {
+		t.Errorf("Unexpected merged config: %+v", merged)
+	}
+	if len(card.Progression.Achievements) != 1 {
+		t.Error("Merging must not modify the card's progression config")
+	}
+}
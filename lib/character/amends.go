@@ -0,0 +1,282 @@
+package character
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Undo and apology defaults used when the card leaves optional fields empty
+const (
+	defaultUndoSeconds          = 10
+	defaultApologyRecovery      = 0.5 // Half of what negative events cost comes back
+	defaultApologyWithinMinutes = 60
+	defaultApologyResponse      = "It's okay. Thank you for saying sorry."
+	nothingToForgiveResponse    = "There's nothing to forgive!"
+)
+
+// UndoConfig lets the user take back an interaction made by accident, such
+// as feeding twice in a row, for a few seconds after making it
+type UndoConfig struct {
+	Enabled bool `json:"enabled"`
+	Seconds int  `json:"seconds,omitempty"` // How long the last interaction can be undone (default: 10)
+}
+
+// ApologyConfig adds an "apologize" choice that wins back part of what
+// negative events, such as jealousy, crises or bad random events, cost
+type ApologyConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Recovery      float64  `json:"recovery,omitempty"`      // Share of the losses recovered, 0-1 (default: 0.5)
+	Stats         []string `json:"stats,omitempty"`         // Stats an apology mends (default: every stat the events lowered)
+	WithinMinutes int      `json:"withinMinutes,omitempty"` // How long after a negative event an apology helps (default: 60)
+	Responses     []string `json:"responses,omitempty"`     // Said when the apology is accepted (default: "It's okay. Thank you for saying sorry.")
+	Animation     string   `json:"animation,omitempty"`     // Played when the apology is accepted
+}
+
+// amendsState tracks what can still be undone or apologized for
+type amendsState struct {
+	last     *undoableInteraction
+	harm     map[string]float64 // Stat losses from negative events since the last apology
+	harmedAt time.Time          // When the latest negative event happened
+}
+
+// undoableInteraction is the last game interaction and what it changed
+type undoableInteraction struct {
+	name        string
+	at          time.Time
+	changes     map[string]float64 // Stat changes after bounds and buffs
+	coins       int
+	cooldown    time.Time // The interaction's cooldown before it was made
+	hadCooldown bool
+}
+
+// validateAmends checks the undo and apology settings against the card's
+// stats and animations
+func (c *CharacterCard) validateAmends() error {
+	if c.Undo != nil && c.Undo.Enabled && c.Undo.Seconds < 0 {
+		return fmt.Errorf("undo seconds must not be negative, got %d", c.Undo.Seconds)
+	}
+
+	a := c.Apology
+	if a == nil || !a.Enabled {
+		return nil
+	}
+	if a.Recovery < 0 || a.Recovery > 1 {
+		return fmt.Errorf("apology recovery must be between 0 and 1, got %f", a.Recovery)
+	}
+	if a.WithinMinutes < 0 {
+		return fmt.Errorf("apology withinMinutes must not be negative, got %d", a.WithinMinutes)
+	}
+	for _, stat := range a.Stats {
+		if _, exists := c.Stats[stat]; !exists {
+			return fmt.Errorf("apology stat '%s' not found in stats", stat)
+		}
+	}
+	if a.Animation != "" {
+		if _, exists := c.Animations[a.Animation]; !exists {
+			return fmt.Errorf("apology animation '%s' not found in animations map", a.Animation)
+		}
+	}
+	return nil
+}
+
+// HasUndo returns true if the card lets the user undo interactions
+func (c *CharacterCard) HasUndo() bool {
+	return c.Undo != nil && c.Undo.Enabled
+}
+
+// HasApology returns true if the card offers apologizing
+func (c *CharacterCard) HasApology() bool {
+	return c.Apology != nil && c.Apology.Enabled
+}
+
+// GetWindow returns how long the last interaction can be undone
+func (u *UndoConfig) GetWindow() time.Duration {
+	if u == nil || u.Seconds <= 0 {
+		return defaultUndoSeconds * time.Second
+	}
+	return time.Duration(u.Seconds) * time.Second
+}
+
+// GetRecovery returns the share of the losses an apology recovers
+func (a *ApologyConfig) GetRecovery() float64 {
+	if a == nil || a.Recovery <= 0 {
+		return defaultApologyRecovery
+	}
+	return a.Recovery
+}
+
+// GetWindow returns how long after a negative event an apology helps
+func (a *ApologyConfig) GetWindow() time.Duration {
+	if a == nil || a.WithinMinutes <= 0 {
+		return defaultApologyWithinMinutes * time.Minute
+	}
+	return time.Duration(a.WithinMinutes) * time.Minute
+}
+
+// rememberUndoable records the game interaction just made, from the stats
+// and coins before it, so it can be undone. Must be called with c.mu held.
+func (c *Character) rememberUndoable(name string, statsBefore map[string]float64, coinsBefore int, cooldown time.Time, hadCooldown bool) {
+	if !c.card.HasUndo() {
+		return
+	}
+	c.amends.last = &undoableInteraction{
+		name:        name,
+		at:          time.Now(),
+		changes:     statChanges(statsBefore, c.gameState.GetStats()),
+		coins:       c.gameState.GetCoins() - coinsBefore,
+		cooldown:    cooldown,
+		hadCooldown: hadCooldown,
+	}
+}
+
+// GetUndoableInteraction returns the last game interaction and how much
+// longer it can be undone, or false when there is nothing to undo
+func (c *Character) GetUndoableInteraction() (string, time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	last := c.amends.last
+	if last == nil {
+		return "", 0, false
+	}
+	remaining := c.card.Undo.GetWindow() - time.Since(last.at)
+	if remaining <= 0 {
+		return "", 0, false
+	}
+	return last.name, remaining, true
+}
+
+// UndoLastInteraction reverses the last game interaction's stat and coin
+// changes, its count and its cooldown. Changes made since, such as decay,
+// are kept. Returns false once the undo window has passed.
+func (c *Character) UndoLastInteraction() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := c.amends.last
+	if last == nil || c.gameState == nil || time.Since(last.at) >= c.card.Undo.GetWindow() {
+		return false
+	}
+	c.amends.last = nil
+
+	reverse := make(map[string]float64, len(last.changes))
+	for stat, change := range last.changes {
+		reverse[stat] = -change
+	}
+	c.gameState.adjustStats(reverse)
+	c.gameState.AddCoins(-last.coins)
+	c.gameState.GetProgression().unrecordInteraction(last.name)
+
+	if last.hadCooldown {
+		c.gameInteractionCooldowns[last.name] = last.cooldown
+	} else {
+		delete(c.gameInteractionCooldowns, last.name)
+	}
+	return true
+}
+
+// rememberHarm adds what a negative event cost, from the stats before it,
+// to what an apology can win back. Must be called with c.mu held.
+func (c *Character) rememberHarm(statsBefore map[string]float64) {
+	if !c.card.HasApology() {
+		return
+	}
+
+	now := time.Now()
+	if time.Since(c.amends.harmedAt) >= c.card.Apology.GetWindow() {
+		c.amends.harm = nil // Too long ago to make up for
+	}
+	for stat, change := range statChanges(statsBefore, c.gameState.GetStats()) {
+		if change >= 0 {
+			continue
+		}
+		if c.amends.harm == nil {
+			c.amends.harm = make(map[string]float64)
+		}
+		c.amends.harm[stat] -= change
+		c.amends.harmedAt = now
+	}
+}
+
+// Apologize makes up for recent negative events: the character recovers
+// part of what they cost in the apology's stats and forgives them. Returns
+// what the character says, the nothing to forgive response when there is
+// nothing recent, or "" when the card has no apologies.
+func (c *Character) Apologize() (text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.recordInteraction("apologize", time.Now(), &text)
+
+	if c.gameState == nil || !c.card.HasApology() {
+		return ""
+	}
+	apology := c.card.Apology
+	if len(c.amends.harm) == 0 || time.Since(c.amends.harmedAt) >= apology.GetWindow() {
+		c.amends.harm = nil
+		return nothingToForgiveResponse
+	}
+
+	recovered := make(map[string]float64)
+	for stat, loss := range c.amends.harm {
+		if len(apology.Stats) == 0 || sliceContains(apology.Stats, stat) {
+			recovered[stat] = loss * apology.GetRecovery()
+		}
+	}
+	c.amends.harm = nil
+	c.gameState.adjustStats(recovered)
+	c.gameState.RecordInteraction("apologize")
+	c.lastInteraction = time.Now()
+
+	if apology.Animation != "" {
+		c.setState(apology.Animation)
+	}
+	if len(apology.Responses) == 0 {
+		return defaultApologyResponse
+	}
+	return apology.Responses[rand.Intn(len(apology.Responses))]
+}
+
+// statChanges returns how each stat moved from before to after, leaving
+// out those that didn't
+func statChanges(before, after map[string]float64) map[string]float64 {
+	changes := make(map[string]float64)
+	for stat, value := range after {
+		if change := value - before[stat]; change != 0 {
+			changes[stat] = change
+		}
+	}
+	return changes
+}
+
+// adjustStats changes stats by the given amounts within their bounds,
+// without the buffs interactions get. Unknown stats are ignored.
+func (gs *GameState) adjustStats(changes map[string]float64) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	for name, change := range changes {
+		if stat, exists := gs.Stats[name]; exists {
+			stat.Current = math.Max(0, math.Min(stat.Max, stat.Current+change))
+		}
+	}
+}
+
+// unrecordInteraction takes back one count of an undone interaction
+func (ps *ProgressionState) unrecordInteraction(interactionType string) {
+	if ps == nil {
+		return
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.InteractionCounts[interactionType] > 0 {
+		ps.InteractionCounts[interactionType]--
+	}
+}
@@ -0,0 +1,131 @@
+package character
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValidateAmends(t *testing.T) {
+	tests := []struct {
+		name    string
+		undo    *UndoConfig
+		apology *ApologyConfig
+		wantErr bool
+	}{
+		{"disabled", &UndoConfig{Seconds: -1}, &ApologyConfig{Recovery: 5}, false},
+		{"valid", &UndoConfig{Enabled: true, Seconds: 5}, &ApologyConfig{Enabled: true, Recovery: 0.3, Stats: []string{"happiness"}, Animation: "sad"}, false},
+		{"negative undo", &UndoConfig{Enabled: true, Seconds: -1}, nil, true},
+		{"recovery above one", nil, &ApologyConfig{Enabled: true, Recovery: 1.5}, true},
+		{"unknown stat", nil, &ApologyConfig{Enabled: true, Stats: []string{"missing"}}, true},
+		{"unknown animation", nil, &ApologyConfig{Enabled: true, Animation: "missing"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Undo = tt.undo
+			card.Apology = tt.apology
+			if err := card.validateAmends(); (err != nil) != tt.wantErr {
+				t.Errorf("validateAmends() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUndoLastInteraction(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Stats["happiness"] = StatConfig{Initial: 50, Max: 100}
+	card.Undo = &UndoConfig{Enabled: true}
+	char := createTestCharacterInstance(card, true)
+
+	if _, _, ok := char.GetUndoableInteraction(); ok {
+		t.Fatal("Expected nothing to undo before any interaction")
+	}
+	if char.HandleGameInteraction("pet") == "" {
+		t.Fatal("Expected the pet interaction to succeed")
+	}
+	name, remaining, ok := char.GetUndoableInteraction()
+	if !ok || name != "pet" || remaining <= 0 || remaining > 10*time.Second {
+		t.Fatalf("Expected pet to be undoable for 10 seconds, got %q %v %v", name, remaining, ok)
+	}
+
+	if !char.UndoLastInteraction() {
+		t.Fatal("Expected the undo to succeed")
+	}
+	if happiness := char.gameState.GetStat("happiness"); math.Abs(happiness-50) > 0.01 {
+		t.Errorf("Expected happiness back at 50, got %f", happiness)
+	}
+	if count := char.gameState.GetProgression().GetInteractionCounts()["pet"]; count != 0 {
+		t.Errorf("Expected the interaction count to be taken back, got %d", count)
+	}
+	if char.UndoLastInteraction() {
+		t.Error("Expected an interaction to be undone only once")
+	}
+
+	// The cooldown is lifted too, so the intended interaction can be made
+	if char.HandleGameInteraction("pet") == "" {
+		t.Error("Expected the interaction to be available again after the undo")
+	}
+
+	// Past the window it stays
+	char.amends.last.at = time.Now().Add(-time.Minute)
+	if char.UndoLastInteraction() {
+		t.Error("Expected no undo after the window")
+	}
+}
+
+func TestApologize(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Apology = &ApologyConfig{Enabled: true, Responses: []string{"Apology accepted."}}
+	char := createTestCharacterInstance(card, true)
+
+	if got := char.Apologize(); got != nothingToForgiveResponse {
+		t.Errorf("Expected nothing to forgive, got %q", got)
+	}
+
+	char.handleTriggeredEvent(&TriggeredEvent{Name: "tantrum", Effects: map[string]float64{"happiness": -40, "energy": 10}})
+	if got := char.Apologize(); got != "Apology accepted." {
+		t.Errorf("Expected the apology to be accepted, got %q", got)
+	}
+	if happiness := char.gameState.GetStat("happiness"); math.Abs(happiness-80) > 0.01 {
+		t.Errorf("Expected half of the lost happiness back, got %f", happiness)
+	}
+	if got := char.Apologize(); got != nothingToForgiveResponse {
+		t.Errorf("Expected a second apology to have nothing to forgive, got %q", got)
+	}
+
+	// Only the configured stats are mended, and only for recent events
+	card.Apology.Stats = []string{"energy"}
+	char.handleTriggeredEvent(&TriggeredEvent{Name: "tantrum", Effects: map[string]float64{"happiness": -20, "energy": -20}})
+	char.Apologize()
+	if happiness := char.gameState.GetStat("happiness"); math.Abs(happiness-60) > 0.01 {
+		t.Errorf("Expected happiness to stay down, got %f", happiness)
+	}
+
+	char.handleTriggeredEvent(&TriggeredEvent{Name: "tantrum", Effects: map[string]float64{"energy": -20}})
+	char.amends.harmedAt = time.Now().Add(-2 * time.Hour)
+	if got := char.Apologize(); got != nothingToForgiveResponse {
+		t.Errorf("Expected an old event to be past forgiving, got %q", got)
+	}
+}
+
+func TestApologizeForJealousy(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Stats["jealousy"] = StatConfig{Initial: 100, Max: 100}
+	card.Apology = &ApologyConfig{Enabled: true}
+	char := createTestCharacterInstance(card, true)
+	char.jealousyManager = NewJealousyManager(nil, true, 50)
+
+	// Jealousy at its peak costs 3 happiness
+	char.processAdvancedRomanceFeatures()
+	if happiness := char.gameState.GetStat("happiness"); math.Abs(happiness-97) > 0.01 {
+		t.Fatalf("Expected jealousy to cost happiness, got %f", happiness)
+	}
+
+	if got := char.Apologize(); got != defaultApologyResponse {
+		t.Errorf("Expected the apology to be accepted, got %q", got)
+	}
+	if happiness := char.gameState.GetStat("happiness"); math.Abs(happiness-98.5) > 0.01 {
+		t.Errorf("Expected half of what jealousy cost back, got %f", happiness)
+	}
+}
@@ -0,0 +1,61 @@
+package character
+
+import (
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/analytics"
+)
+
+// moodSampleInterval is how often the mood is added to the analytics trajectory
+const moodSampleInterval = 15 * time.Minute
+
+// SetAnalytics enables local interaction analytics. Pass nil to disable.
+func (c *Character) SetAnalytics(collector *analytics.Collector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.analytics = collector
+}
+
+// GetAnalytics returns the analytics collector, or nil when analytics is off
+func (c *Character) GetAnalytics() *analytics.Collector {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.analytics
+}
+
+// recordInteraction records a handled interaction. Meant to be deferred at
+// the top of a handler with a pointer to its named result, so the latency
+// covers the whole handler. Caller must hold c.mu.
+func (c *Character) recordInteraction(trigger string, start time.Time, response *string) {
+	backend := c.lastResponseBackend
+	c.lastResponseBackend = ""
+
+	if c.analytics == nil {
+		return
+	}
+
+	responded := *response != ""
+	// Hovering fires constantly, so only count hovers the character reacted to
+	if trigger == "hover" && !responded {
+		return
+	}
+	if responded && backend == "" {
+		backend = "card" // Static dialogs from the character card
+	}
+
+	c.analytics.RecordInteraction(trigger, time.Since(start), responded, backend)
+}
+
+// updateAnalytics samples the mood for the weekly trajectory.
+// Caller must hold c.mu.
+func (c *Character) updateAnalytics(now time.Time) {
+	if c.analytics == nil || c.gameState == nil {
+		return
+	}
+	if now.Sub(c.lastMoodSample) < moodSampleInterval {
+		return
+	}
+
+	c.lastMoodSample = now
+	c.analytics.RecordMood(c.gameState.GetOverallMood())
+}
@@ -0,0 +1,49 @@
+package character
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/analytics"
+)
+
+func TestCharacterRecordsAnalytics(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	collector, err := analytics.NewCollector(filepath.Join(t.TempDir(), "analytics.json"))
+	if err != nil {
+		t.Fatalf("NewCollector failed: %v", err)
+	}
+	char.SetAnalytics(collector)
+
+	response := char.HandleClick()
+	char.HandleHover() // Too soon after the click, so ignored entirely
+
+	summary := collector.WeeklySummary()
+	if summary.TotalInteractions != 1 || summary.TopInteractions[0].Name != "click" {
+		t.Fatalf("Expected one click recorded, got %+v", summary.TopInteractions)
+	}
+	if response != "" && (len(summary.Backends) != 1 || summary.Backends[0].Name != "card") {
+		t.Errorf("Card dialog responses should be attributed to the card, got %+v", summary.Backends)
+	}
+
+	now := time.Now()
+	char.mu.Lock()
+	char.updateAnalytics(now)
+	char.updateAnalytics(now.Add(time.Minute)) // Within the sample interval
+	char.mu.Unlock()
+
+	today := collector.WeeklySummary().Days[6]
+	if !today.HasMood {
+		t.Error("Expected a mood sample for today")
+	}
+}
+
+func TestCharacterWithoutAnalytics(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	char.HandleClick()
+
+	if char.GetAnalytics() != nil {
+		t.Error("Analytics should be off unless enabled")
+	}
+}
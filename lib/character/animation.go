@@ -17,10 +17,22 @@ import (
 type AnimationManager struct {
 	mu          sync.RWMutex
 	animations  map[string]*gif.GIF // Loaded GIF animations
-	currentAnim string              // Currently playing animation name
+	currentAnim string              // Currently playing animation name, a transition clip during one
 	frameIndex  int                 // Current frame index
 	lastUpdate  time.Time           // Last frame update time
 	playing     bool                // Whether animation is playing
+
+	// Transitions between animations (see SetTransitions)
+	transitions map[transitionKey]AnimationTransition
+	target      string          // Animation last switched to, played once the queue empties
+	queue       []string        // Animations waiting for the transition clip to finish
+	fade        *transitionFade // Cross-fade in progress, nil when none
+
+	// Animations decoded on first use (see preload.go)
+	sources    map[string]string   // Registered files not decoded yet
+	preloading map[string]bool     // Being decoded in the background
+	preloads   sync.WaitGroup      // Background decodes in progress
+	cacheStats AnimationCacheStats // Switches that found the animation decoded
 }
 
 // NewAnimationManager creates a new animation manager
@@ -108,6 +120,7 @@ func (am *AnimationManager) LoadAnimation(name, filepath string) error {
 	// Set as current animation if this is the first one loaded
 	if am.currentAnim == "" {
 		am.currentAnim = name
+		am.target = name
 		logrus.WithFields(logrus.Fields{
 			"caller": caller,
 			"name":   name,
@@ -139,23 +152,41 @@ func (am *AnimationManager) LoadEmbeddedAnimation(name string, gifData *gif.GIF)
 	// Set as current animation if this is the first one loaded
 	if am.currentAnim == "" {
 		am.currentAnim = name
+		am.target = name
 	}
 
 	return nil
 }
 
-// SetCurrentAnimation switches to a different loaded animation
+// SetCurrentAnimation switches to a different loaded animation, through
+// the transition set for the switch if there is one. A registered animation
+// that wasn't preloaded is decoded first.
 func (am *AnimationManager) SetCurrentAnimation(name string) error {
+	decoded, err := am.ensureDecoded(name)
+	if err != nil {
+		return err
+	}
+	if clip := am.transitionClip(name); clip != "" {
+		clipDecoded, _ := am.ensureDecoded(clip) // A broken clip is skipped below
+		decoded = decoded || clipDecoded
+	}
+
 	am.mu.Lock()
 	defer am.mu.Unlock()
 
 	if _, exists := am.animations[name]; !exists {
 		return fmt.Errorf("animation '%s' not loaded", name)
 	}
+	am.recordSwitchLocked(name, decoded)
 
-	am.currentAnim = name
-	am.frameIndex = 0
-	am.lastUpdate = time.Now()
+	if !am.startTransition(name) {
+		am.currentAnim = name
+		am.frameIndex = 0
+		am.lastUpdate = time.Now()
+		am.queue = nil
+		am.fade = nil
+	}
+	am.target = name
 
 	return nil
 }
@@ -185,13 +216,15 @@ func (am *AnimationManager) GetCurrentFrame() (image.Image, bool) {
 		frameDelay = 100 * time.Millisecond // Default to 10 FPS
 	}
 
-	// Check if enough time has passed for next frame (read-only timing check)
-	needsUpdate := time.Since(am.lastUpdate) >= frameDelay
+	// Check if enough time has passed for next frame (read-only timing check);
+	// cross-fades change every update
+	needsUpdate := time.Since(am.lastUpdate) >= frameDelay || am.fade != nil
 
-	return currentGif.Image[am.frameIndex], needsUpdate
+	return am.frameLocked(), needsUpdate
 }
 
-// GetCurrentFrameImage returns just the current frame without timing logic
+// GetCurrentFrameImage returns just the current frame without timing logic,
+// blended during a cross-fade
 // Useful for rendering when timing is handled externally
 func (am *AnimationManager) GetCurrentFrameImage() image.Image {
 	am.mu.RLock()
@@ -201,12 +234,7 @@ func (am *AnimationManager) GetCurrentFrameImage() image.Image {
 		return nil
 	}
 
-	currentGif, exists := am.animations[am.currentAnim]
-	if !exists || len(currentGif.Image) == 0 {
-		return nil
-	}
-
-	return currentGif.Image[am.frameIndex]
+	return am.frameLocked()
 }
 
 // Update advances animation frames based on timing
@@ -233,14 +261,23 @@ func (am *AnimationManager) Update() bool {
 		frameDelay = 100 * time.Millisecond // Default to 10 FPS
 	}
 
-	// Advance frame if enough time has passed
+	// Advance frame if enough time has passed; a transition clip plays once
+	// and hands over to the next queued animation
+	frameChanged := false
 	if time.Since(am.lastUpdate) >= frameDelay {
-		am.frameIndex = (am.frameIndex + 1) % len(currentGif.Image)
+		if len(am.queue) > 0 && am.frameIndex == len(currentGif.Image)-1 {
+			am.advanceQueueLocked()
+		} else {
+			am.frameIndex = (am.frameIndex + 1) % len(currentGif.Image)
+		}
 		am.lastUpdate = time.Now()
-		return true // Frame changed
+		frameChanged = true
 	}
 
-	return false // No frame change
+	// Every update of a cross-fade shows a new blend, and so does its end
+	fading := am.fade != nil
+	am.updateFadeLocked()
+	return frameChanged || fading
 }
 
 // Play starts animation playback
@@ -264,27 +301,34 @@ func (am *AnimationManager) IsPlaying() bool {
 	return am.playing
 }
 
-// GetCurrentAnimationName returns the name of currently playing animation
+// GetCurrentAnimationName returns the name of currently playing animation,
+// or the one a transition is leading to
 func (am *AnimationManager) GetCurrentAnimationName() string {
 	am.mu.RLock()
 	defer am.mu.RUnlock()
-	return am.currentAnim
+	return am.target
 }
 
-// GetLoadedAnimations returns a list of all loaded animation names
+// GetLoadedAnimations returns a list of all loaded animation names,
+// including registered ones not decoded yet
 func (am *AnimationManager) GetLoadedAnimations() []string {
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 
-	names := make([]string, 0, len(am.animations))
+	names := make([]string, 0, len(am.animations)+len(am.sources))
 	for name := range am.animations {
 		names = append(names, name)
 	}
+	for name := range am.sources {
+		names = append(names, name)
+	}
 	return names
 }
 
 // GetAnimationFrameCount returns the number of frames in the specified animation
 func (am *AnimationManager) GetAnimationFrameCount(name string) int {
+	_, _ = am.ensureDecoded(name)
+
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 
@@ -294,6 +338,16 @@ func (am *AnimationManager) GetAnimationFrameCount(name string) int {
 	return 0
 }
 
+// GetAnimation returns a loaded animation, e.g. to send a copy to a peer
+func (am *AnimationManager) GetAnimation(name string) (*gif.GIF, bool) {
+	_, _ = am.ensureDecoded(name)
+
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	anim, exists := am.animations[name]
+	return anim, exists
+}
+
 // Reset resets the current animation to the first frame
 func (am *AnimationManager) Reset() {
 	am.mu.Lock()
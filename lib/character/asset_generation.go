@@ -2,6 +2,8 @@ package character
 
 import (
 	"fmt"
+	"strings"
+	"text/template"
 	"time"
 )
 
@@ -13,6 +15,17 @@ type AssetGenerationConfig struct {
 	// that describes the character's visual appearance, art style, and key characteristics
 	BasePrompt string `json:"basePrompt"`
 
+	// PromptTemplate optionally replaces the generated positive prompt with a Go
+	// text/template, e.g. "{{.Description}}, {{.StateModifier}}, {{.StylePrompt}}"
+	PromptTemplate string `json:"promptTemplate,omitempty"`
+
+	// NegativePromptTemplate is appended to the negative prompt for every state
+	NegativePromptTemplate string `json:"negativePromptTemplate,omitempty"`
+
+	// ReferenceImage is an optional PNG, relative to the card, that every state
+	// is generated from with image-to-image so the design stays consistent
+	ReferenceImage string `json:"referenceImage,omitempty"`
+
 	// AnimationMappings defines state-specific prompt modifications for each animation
 	AnimationMappings map[string]AnimationMapping `json:"animationMappings"`
 
@@ -24,6 +37,28 @@ type AssetGenerationConfig struct {
 
 	// BackupSettings controls asset backup behavior before regeneration
 	BackupSettings BackupSettings `json:"backupSettings,omitempty"`
+
+	// OutputProfiles adds resolutions written next to each state's GIF in
+	// the same run, such as a 2x desktop set or a smaller mobile set
+	OutputProfiles []OutputProfile `json:"outputProfiles,omitempty"`
+}
+
+// OutputProfile is an extra resolution of every animation state, saved as
+// the state name plus Suffix, e.g. "idle@2x.gif" or "idle_mobile.gif"
+type OutputProfile struct {
+	// Suffix names the variant files, e.g. "@2x" or "_mobile"
+	Suffix string `json:"suffix"`
+
+	// Width and Height set the variant size in pixels (32-1024)
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// Scale multiplies the generated size when Width and Height are unset
+	Scale float64 `json:"scale,omitempty"`
+
+	// Platform ("desktop" or "mobile") makes the card use this variant on
+	// that platform through platformConfig animations
+	Platform string `json:"platform,omitempty"`
 }
 
 // AnimationMapping defines how to modify the base prompt for specific animation states
@@ -31,6 +66,9 @@ type AnimationMapping struct {
 	// PromptModifier is the text to append/modify the base prompt for this animation state
 	PromptModifier string `json:"promptModifier"`
 
+	// PromptTemplate overrides the positive prompt template for this state only
+	PromptTemplate string `json:"promptTemplate,omitempty"`
+
 	// NegativePrompt defines what to avoid in generation for this state
 	NegativePrompt string `json:"negativePrompt,omitempty"`
 
@@ -82,6 +120,10 @@ type QualitySettings struct {
 	// Seed for reproducible generation (-1 = random)
 	Seed int64 `json:"seed,omitempty"`
 
+	// Denoise controls how far image-to-image may move from the reference
+	// image (0-1, default 0.6). Ignored without a reference image.
+	Denoise float64 `json:"denoise,omitempty"`
+
 	// Sampler defines the sampling method ("euler_a", "dpmpp_2m", "heun", etc.)
 	Sampler string `json:"sampler,omitempty"`
 
@@ -129,6 +171,18 @@ type ComfyUISettings struct {
 
 	// ControlNet settings for pose/composition control
 	ControlNet *ControlNetSettings `json:"controlNet,omitempty"`
+
+	// Loras applied on top of the checkpoint, e.g. to keep a character's look
+	Loras []LoraSettings `json:"loras,omitempty"`
+}
+
+// LoraSettings names a LoRA and how strongly it is applied
+type LoraSettings struct {
+	// Name is a model key from the pipeline config or a file name on the ComfyUI server
+	Name string `json:"name"`
+
+	// Strength scales the LoRA's effect (0.0-2.0, default: 1.0)
+	Strength float64 `json:"strength,omitempty"`
 }
 
 // ControlNetSettings defines ControlNet parameters for pose/composition control
@@ -341,6 +395,9 @@ func ValidateAssetGenerationConfig(config *AssetGenerationConfig) error {
 	if quality.CFGScale < 1.0 || quality.CFGScale > 20.0 {
 		return fmt.Errorf("invalid cfgScale %.1f, must be between 1.0-20.0", quality.CFGScale)
 	}
+	if quality.Denoise < 0 || quality.Denoise > 1 {
+		return fmt.Errorf("invalid denoise %.2f, must be between 0-1", quality.Denoise)
+	}
 
 	// Validate animation settings
 	anim := config.GenerationSettings.AnimationSettings
@@ -351,5 +408,77 @@ func ValidateAssetGenerationConfig(config *AssetGenerationConfig) error {
 		return fmt.Errorf("invalid duration %.1f, must be between 1.0-10.0", anim.Duration)
 	}
 
+	for i, lora := range config.GenerationSettings.ComfyUISettings.Loras {
+		if lora.Name == "" {
+			return fmt.Errorf("lora %d: name cannot be empty", i)
+		}
+		if lora.Strength < 0 || lora.Strength > 2 {
+			return fmt.Errorf("invalid strength %.2f for lora %q, must be between 0-2", lora.Strength, lora.Name)
+		}
+	}
+
+	if err := validateOutputProfiles(config.OutputProfiles); err != nil {
+		return err
+	}
+
+	return validatePromptTemplates(config)
+}
+
+// validateOutputProfiles checks each profile names its files and size, and
+// that no two profiles write the same files or claim the same platform
+func validateOutputProfiles(profiles []OutputProfile) error {
+	suffixes := make(map[string]bool)
+	platforms := make(map[string]bool)
+	for i, profile := range profiles {
+		if profile.Suffix == "" || strings.ContainsAny(profile.Suffix, `/\.`) {
+			return fmt.Errorf("output profile %d: suffix %q must be non-empty without dots or slashes", i, profile.Suffix)
+		}
+		if suffixes[profile.Suffix] {
+			return fmt.Errorf("output profile %q is defined twice", profile.Suffix)
+		}
+		suffixes[profile.Suffix] = true
+
+		if (profile.Width == 0) != (profile.Height == 0) {
+			return fmt.Errorf("output profile %q: width and height must be set together", profile.Suffix)
+		}
+		if profile.Width == 0 && profile.Scale <= 0 {
+			return fmt.Errorf("output profile %q needs a width and height or a scale", profile.Suffix)
+		}
+		if profile.Width != 0 && (profile.Width < 32 || profile.Width > 1024 || profile.Height < 32 || profile.Height > 1024) {
+			return fmt.Errorf("output profile %q: size %dx%d must be between 32-1024", profile.Suffix, profile.Width, profile.Height)
+		}
+
+		switch profile.Platform {
+		case "":
+		case "desktop", "mobile":
+			if platforms[profile.Platform] {
+				return fmt.Errorf("output profile %q: another profile is already used on %s", profile.Suffix, profile.Platform)
+			}
+			platforms[profile.Platform] = true
+		default:
+			return fmt.Errorf("output profile %q: platform must be desktop or mobile, got %q", profile.Suffix, profile.Platform)
+		}
+	}
+	return nil
+}
+
+// validatePromptTemplates ensures all prompt templates parse as Go templates
+func validatePromptTemplates(config *AssetGenerationConfig) error {
+	if _, err := template.New("prompt").Parse(config.PromptTemplate); err != nil {
+		return fmt.Errorf("invalid promptTemplate: %w", err)
+	}
+	if _, err := template.New("negative").Parse(config.NegativePromptTemplate); err != nil {
+		return fmt.Errorf("invalid negativePromptTemplate: %w", err)
+	}
+
+	for state, mapping := range config.AnimationMappings {
+		if _, err := template.New(state).Parse(mapping.PromptTemplate); err != nil {
+			return fmt.Errorf("invalid promptTemplate for %s: %w", state, err)
+		}
+		if _, err := template.New(state).Parse(mapping.NegativePrompt); err != nil {
+			return fmt.Errorf("invalid negativePrompt for %s: %w", state, err)
+		}
+	}
+
 	return nil
 }
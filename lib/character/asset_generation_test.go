@@ -343,3 +343,68 @@ func TestControlNetSettings(t *testing.T) {
 		t.Error("StartStep should be less than EndStep")
 	}
 }
+
+func TestValidateAssetGenerationLoras(t *testing.T) {
+	config := DefaultAssetGenerationConfig()
+	config.GenerationSettings.ComfyUISettings.Loras = []LoraSettings{{Name: "chibi_style", Strength: 0.8}}
+	if err := ValidateAssetGenerationConfig(config); err != nil {
+		t.Errorf("Valid lora rejected: %v", err)
+	}
+
+	config.GenerationSettings.ComfyUISettings.Loras = []LoraSettings{{Strength: 0.8}}
+	if err := ValidateAssetGenerationConfig(config); err == nil {
+		t.Error("Expected an error for a lora without a name")
+	}
+
+	config.GenerationSettings.ComfyUISettings.Loras = []LoraSettings{{Name: "chibi_style", Strength: 3}}
+	if err := ValidateAssetGenerationConfig(config); err == nil {
+		t.Error("Expected an error for a lora strength above 2")
+	}
+}
+
+func TestValidateAssetGenerationPromptTemplates(t *testing.T) {
+	config := DefaultAssetGenerationConfig()
+	config.PromptTemplate = "{{.Description}}, {{.StateModifier}}"
+	if err := ValidateAssetGenerationConfig(config); err != nil {
+		t.Fatalf("Valid prompt template rejected: %v", err)
+	}
+
+	config.PromptTemplate = "{{.Description"
+	if err := ValidateAssetGenerationConfig(config); err == nil {
+		t.Error("Expected error for unterminated prompt template")
+	}
+
+	config.PromptTemplate = ""
+	mapping := config.AnimationMappings["idle"]
+	mapping.PromptTemplate = "{{if .State}}"
+	config.AnimationMappings["idle"] = mapping
+	if err := ValidateAssetGenerationConfig(config); err == nil {
+		t.Error("Expected error for broken per-state prompt template")
+	}
+}
+
+func TestValidateAssetGenerationOutputProfiles(t *testing.T) {
+	config := DefaultAssetGenerationConfig()
+	config.OutputProfiles = []OutputProfile{
+		{Suffix: "@2x", Scale: 2, Platform: "desktop"},
+		{Suffix: "_mobile", Width: 96, Height: 96, Platform: "mobile"},
+	}
+	if err := ValidateAssetGenerationConfig(config); err != nil {
+		t.Errorf("Valid output profiles rejected: %v", err)
+	}
+
+	invalid := map[string][]OutputProfile{
+		"no suffix":        {{Scale: 2}},
+		"no size":          {{Suffix: "@2x"}},
+		"too small":        {{Suffix: "_tiny", Width: 16, Height: 16}},
+		"unknown platform": {{Suffix: "_tv", Scale: 2, Platform: "tv"}},
+		"same suffix":      {{Suffix: "@2x", Scale: 2}, {Suffix: "@2x", Scale: 3}},
+		"same platform":    {{Suffix: "_a", Scale: 2, Platform: "mobile"}, {Suffix: "_b", Scale: 3, Platform: "mobile"}},
+	}
+	for name, profiles := range invalid {
+		config.OutputProfiles = profiles
+		if err := ValidateAssetGenerationConfig(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package character
+
+import (
+	"fmt"
+	"time"
+)
+
+// Away defaults used when the card leaves optional fields empty
+const (
+	AnimationNapping = "napping"
+
+	defaultAwayIdleMinutes         = 5
+	defaultAwayDecayMultiplier     = 0.5 // Stats decay at half speed while the user is away
+	defaultWelcomeBackAfterMinutes = 30
+	defaultWelcomeBackAffection    = 5
+)
+
+// AwayConfig makes the character notice when the user steps away from the
+// computer: it naps, its stats decay slower, and it welcomes the user back
+// after a long enough absence.
+type AwayConfig struct {
+	Enabled                 bool               `json:"enabled"`
+	IdleMinutes             int                `json:"idleMinutes,omitempty"`          // Minutes without input before the user counts as away (default: 5); a locked screen counts at once
+	Animation               string             `json:"animation,omitempty"`            // Held while the user is away (default: "napping", then the sleep animation, if present)
+	DecayMultiplier         float64            `json:"decayMultiplier,omitempty"`      // Stat decay speed while away, 0-1 (default: 0.5)
+	WelcomeBackAfterMinutes int                `json:"welcomeBackAfter,omitempty"`     // Absence before the welcome back (default: 30)
+	WelcomeBackResponses    []string           `json:"welcomeBackResponses,omitempty"` // Said on return (default: "Welcome back, I missed you!")
+	WelcomeBackEffects      map[string]float64 `json:"welcomeBackEffects,omitempty"`   // Stat changes on return (default: affection +5 if the card has it)
+}
+
+// WelcomeBack describes the user's return after a long absence
+type WelcomeBack struct {
+	Absence  time.Duration
+	Response string
+	Effects  map[string]float64
+}
+
+// awayState tracks the user's absence. Zero means present.
+type awayState struct {
+	since time.Time // When input stopped
+}
+
+// validateAway checks the away settings against the card's stats and animations
+func (c *CharacterCard) validateAway() error {
+	a := c.Away
+	if a == nil || !a.Enabled {
+		return nil
+	}
+	if a.IdleMinutes < 0 {
+		return fmt.Errorf("idleMinutes must not be negative, got %d", a.IdleMinutes)
+	}
+	if a.WelcomeBackAfterMinutes < 0 {
+		return fmt.Errorf("welcomeBackAfter must not be negative, got %d", a.WelcomeBackAfterMinutes)
+	}
+	if a.DecayMultiplier < 0 || a.DecayMultiplier > 1 {
+		return fmt.Errorf("decayMultiplier must be between 0 and 1, got %f", a.DecayMultiplier)
+	}
+	if a.Animation != "" {
+		if _, exists := c.Animations[a.Animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", a.Animation)
+		}
+	}
+	if len(a.WelcomeBackEffects) > 0 && !c.HasGameFeatures() {
+		return fmt.Errorf("welcomeBackEffects require stats to be defined")
+	}
+	return c.validateJobStats(a.WelcomeBackEffects)
+}
+
+// HasAwayDetection returns true if the card reacts to the user being away
+func (c *CharacterCard) HasAwayDetection() bool {
+	return c.Away != nil && c.Away.Enabled
+}
+
+// GetIdleThreshold returns how long without input counts as away
+func (a *AwayConfig) GetIdleThreshold() time.Duration {
+	if a == nil || a.IdleMinutes <= 0 {
+		return defaultAwayIdleMinutes * time.Minute
+	}
+	return time.Duration(a.IdleMinutes) * time.Minute
+}
+
+// GetWelcomeBackAfter returns the absence that earns a welcome back
+func (a *AwayConfig) GetWelcomeBackAfter() time.Duration {
+	if a == nil || a.WelcomeBackAfterMinutes <= 0 {
+		return defaultWelcomeBackAfterMinutes * time.Minute
+	}
+	return time.Duration(a.WelcomeBackAfterMinutes) * time.Minute
+}
+
+// GetDecayMultiplier returns the stat decay speed while away
+func (a *AwayConfig) GetDecayMultiplier() float64 {
+	if a == nil || a.DecayMultiplier <= 0 {
+		return defaultAwayDecayMultiplier
+	}
+	return a.DecayMultiplier
+}
+
+// UpdatePresence reports how long the user has been idle and whether the
+// screen is locked, as polled by the UI. The character starts napping once
+// the user counts as away and wakes when they return, queueing a welcome
+// back for GetWelcomeBacks after a long absence. Does nothing unless the
+// card enables away detection.
+func (c *Character) UpdatePresence(idle time.Duration, locked bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.card.HasAwayDetection() {
+		return
+	}
+	now := time.Now()
+	away := locked || idle >= c.card.Away.GetIdleThreshold()
+
+	switch {
+	case away && c.away.since.IsZero():
+		c.away.since = now.Add(-idle)
+		c.gameState.setDecayMultiplier(c.card.Away.GetDecayMultiplier())
+		if animation, ok := c.awayAnimation(); ok {
+			c.setState(animation)
+		}
+	case !away && !c.away.since.IsZero():
+		absence := now.Sub(c.away.since)
+		c.away = awayState{}
+		c.gameState.setDecayMultiplier(1)
+		if c.currentState == c.awayAnimationName() {
+			c.setState(c.selectIdleAnimation())
+		}
+		if absence >= c.card.Away.GetWelcomeBackAfter() {
+			c.welcomeBack(absence)
+		}
+	}
+}
+
+// welcomeBack applies the return bonus and queues the greeting. Must be
+// called with c.mu held.
+func (c *Character) welcomeBack(absence time.Duration) {
+	effects := c.card.Away.WelcomeBackEffects
+	if len(effects) == 0 {
+		if _, exists := c.card.Stats["affection"]; exists {
+			effects = map[string]float64{"affection": defaultWelcomeBackAffection}
+		}
+	}
+	if c.gameState != nil && len(effects) > 0 {
+		c.gameState.ApplyInteractionEffects(effects)
+		c.gameState.RecordInteraction("welcome_back")
+	}
+	if _, exists := c.card.Animations["happy"]; exists {
+		c.setState("happy")
+	}
+
+	c.welcomeBacks = append(c.welcomeBacks, WelcomeBack{
+		Absence:  absence,
+		Response: selectJobResponse(c.card.Away.WelcomeBackResponses, "Welcome back, I missed you!"),
+		Effects:  effects,
+	})
+}
+
+// GetWelcomeBacks returns welcome backs since the last call and clears the list
+func (c *Character) GetWelcomeBacks() []WelcomeBack {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	welcomes := c.welcomeBacks
+	c.welcomeBacks = nil
+	return welcomes
+}
+
+// IsUserAway returns true while the user counts as away
+func (c *Character) IsUserAway() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.away.since.IsZero()
+}
+
+// awayAnimationName returns the nap animation the card asks for, or the
+// first default it has. Must be called with c.mu held.
+func (c *Character) awayAnimationName() string {
+	if c.card.Away.Animation != "" {
+		return c.card.Away.Animation
+	}
+	if _, exists := c.card.Animations[AnimationNapping]; exists {
+		return AnimationNapping
+	}
+	if c.card.SleepSchedule != nil {
+		return c.card.SleepSchedule.GetAnimation()
+	}
+	return AnimationSleeping
+}
+
+// awayAnimation returns the animation to hold while the user is away.
+// ok is false when they are present or the card has no such animation.
+// Must be called with c.mu held.
+func (c *Character) awayAnimation() (string, bool) {
+	if c.away.since.IsZero() {
+		return "", false
+	}
+	animation := c.awayAnimationName()
+	if _, exists := c.card.Animations[animation]; !exists {
+		return "", false
+	}
+	return animation, true
+}
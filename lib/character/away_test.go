@@ -0,0 +1,135 @@
+package character
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAwayConfigDefaults(t *testing.T) {
+	var a *AwayConfig
+	if a.GetIdleThreshold() != 5*time.Minute || a.GetWelcomeBackAfter() != 30*time.Minute || a.GetDecayMultiplier() != 0.5 {
+		t.Error("Nil away config should use the defaults")
+	}
+
+	a = &AwayConfig{IdleMinutes: 10, WelcomeBackAfterMinutes: 60, DecayMultiplier: 0.25}
+	if a.GetIdleThreshold() != 10*time.Minute || a.GetWelcomeBackAfter() != time.Hour || a.GetDecayMultiplier() != 0.25 {
+		t.Error("Configured values should be used")
+	}
+}
+
+func TestValidateAway(t *testing.T) {
+	tests := []struct {
+		name    string
+		away    *AwayConfig
+		wantErr bool
+	}{
+		{"disabled", &AwayConfig{DecayMultiplier: 5}, false},
+		{"valid", &AwayConfig{Enabled: true, Animation: "sad", WelcomeBackEffects: map[string]float64{"happiness": 10}}, false},
+		{"negative idle", &AwayConfig{Enabled: true, IdleMinutes: -1}, true},
+		{"multiplier above one", &AwayConfig{Enabled: true, DecayMultiplier: 1.5}, true},
+		{"unknown animation", &AwayConfig{Enabled: true, Animation: "missing"}, true},
+		{"unknown stat", &AwayConfig{Enabled: true, WelcomeBackEffects: map[string]float64{"missing": 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Away = tt.away
+			if err := card.validateAway(); (err != nil) != tt.wantErr {
+				t.Errorf("validateAway() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdatePresenceWelcomeBack(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Stats["affection"] = StatConfig{Initial: 50, Max: 100}
+	card.Away = &AwayConfig{Enabled: true}
+	char := createTestCharacterInstance(card, true)
+
+	char.UpdatePresence(time.Minute, false)
+	if char.IsUserAway() {
+		t.Fatal("A minute without input should not count as away")
+	}
+
+	// Locking the screen counts at once
+	char.UpdatePresence(0, true)
+	if !char.IsUserAway() {
+		t.Fatal("A locked screen should count as away")
+	}
+	if char.gameState.decayMultiplier != 0.5 {
+		t.Errorf("Expected half speed decay while away, got %f", char.gameState.decayMultiplier)
+	}
+
+	// Back after a short absence: no welcome
+	char.UpdatePresence(0, false)
+	if char.IsUserAway() || len(char.GetWelcomeBacks()) != 0 {
+		t.Error("A short absence should end quietly")
+	}
+	if char.gameState.decayMultiplier != 1 {
+		t.Errorf("Decay should return to full speed, got %f", char.gameState.decayMultiplier)
+	}
+
+	// An hour away earns the welcome back and the affection bonus
+	char.UpdatePresence(time.Hour, false)
+	char.UpdatePresence(0, false)
+	welcomes := char.GetWelcomeBacks()
+	if len(welcomes) != 1 {
+		t.Fatalf("Expected one welcome back, got %d", len(welcomes))
+	}
+	if welcomes[0].Response != "Welcome back, I missed you!" || welcomes[0].Absence < time.Hour {
+		t.Errorf("Unexpected welcome back %+v", welcomes[0])
+	}
+	if affection := char.gameState.GetStats()["affection"]; affection != 55 {
+		t.Errorf("Expected affection 55 after the welcome back, got %f", affection)
+	}
+	if len(char.GetWelcomeBacks()) != 0 {
+		t.Error("Welcome backs should be cleared once read")
+	}
+}
+
+func TestUpdatePresenceDisabled(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	char.UpdatePresence(time.Hour, true)
+	if char.IsUserAway() {
+		t.Error("Presence should be ignored without away detection")
+	}
+}
+
+func TestAwayAnimation(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Away = &AwayConfig{Enabled: true}
+	char := createTestCharacterInstance(card, false)
+
+	if _, ok := char.awayAnimation(); ok {
+		t.Error("No away animation while the user is present")
+	}
+	char.UpdatePresence(time.Hour, false)
+	if _, ok := char.awayAnimation(); ok {
+		t.Error("Cards without a nap or sleep animation should keep their state")
+	}
+
+	card.Animations[AnimationNapping] = "nap.gif"
+	if animation, ok := char.awayAnimation(); !ok || animation != AnimationNapping {
+		t.Errorf("Expected the napping animation, got %q", animation)
+	}
+	card.Away.Animation = "sad"
+	if animation, _ := char.awayAnimation(); animation != "sad" {
+		t.Errorf("The card's away animation should win, got %q", animation)
+	}
+}
+
+func TestAwayDecaysSlower(t *testing.T) {
+	gs := NewGameState(map[string]StatConfig{
+		"hunger": {Initial: 100, Max: 100, DegradationRate: 1},
+	}, &GameConfig{StatsDecayInterval: time.Minute})
+	gs.setDecayMultiplier(0.5)
+
+	gs.LastDecayUpdate = time.Now().Add(-10 * time.Minute)
+	gs.Update(time.Second)
+
+	if hunger := gs.GetStats()["hunger"]; math.Abs(hunger-95) > 0.1 {
+		t.Errorf("Expected hunger ~95 at half speed, got %f", hunger)
+	}
+}
@@ -3,6 +3,7 @@ package character
 import (
 	"fmt"
 	"image"
+	"image/gif"
 	"log"
 	"runtime"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/jdkato/prose/v2"
+	"github.com/opd-ai/desktop-companion/lib/analytics"
 	"github.com/opd-ai/desktop-companion/lib/dialog"
 	"github.com/opd-ai/desktop-companion/lib/news"
 	"github.com/opd-ai/desktop-companion/lib/platform"
@@ -75,11 +77,15 @@ type Character struct {
 	crisisRecoveryManager *CrisisRecoveryManager // Relationship crisis and recovery systems
 
 	// Crisis state tracking (bug fix for Finding #13)
-	inCrisis bool // Tracks if character is currently in crisis mode
+	inCrisis      bool            // Tracks if character is currently in crisis mode
+	knownCrises   map[string]bool // Active crisis names as of the last sync
+	crisisChanges []CrisisChange  // Crises started or resolved since the UI last asked
 
 	// Dialog backend integration (Phase 1)
 	dialogManager      *dialog.DialogManager // Advanced dialog system manager
 	useAdvancedDialogs bool                  // Whether to use advanced dialog system
+	suggestedReplies   []string              // Replies offered with the last chat response
+	conversation       conversationState     // Chat topics shared with the other dialog triggers
 	debug              bool                  // Debug logging for dialog system
 
 	// General dialog events (Phase 4)
@@ -87,6 +93,68 @@ type Character struct {
 
 	// Platform-aware behavior (Phase 5.4)
 	platformAdapter *PlatformBehaviorAdapter // Platform-aware behavior adaptation
+
+	// Sleep schedule and do-not-disturb
+	sleeping     bool // Character is inside its configured sleep window
+	doNotDisturb bool // User has silenced dialogs and notifications
+
+	// User away from the computer and welcome backs since the UI last asked (see GetWelcomeBacks)
+	away         awayState
+	welcomeBacks []WelcomeBack
+
+	// Last interaction to undo and negative events to apologize for
+	amends amendsState
+
+	// Screensaver showcase while the user is idle (see StartShowcase)
+	showcase showcaseState
+
+	// Behavior script and the lines it queued since the UI last asked (see GetScriptDialogs)
+	script scriptState
+
+	// Relationship level-ups since the UI last asked (see GetLevelUps)
+	levelUps []LevelUp
+
+	// Jobs finished since the UI last asked (see GetCompletedJobs)
+	completedJobs []JobResult
+
+	// Calendar events fired since the UI last asked (see GetCalendarEvents)
+	calendarResults    []CalendarResult
+	lastCalendarCheck  time.Time
+	localUserBirthday  string         // Used when game mode is off
+	localCalendarFired map[string]int // Event name -> year, used when game mode is off
+
+	// Scheduled events fired since the UI last asked (see GetScheduledEvents)
+	scheduler          *EventScheduler
+	scheduledResults   []ScheduledResult
+	lastScheduleCheck  time.Time
+	localScheduleFired map[string]time.Time // Event name -> last fire time, used when game mode is off
+
+	// Pomodoro timer (nil when not running) and phase changes since the UI last asked
+	focus        *focusSession
+	focusResults []FocusResult
+
+	// Particle effect triggers and bursts fired since the UI last asked (see GetParticleBursts)
+	particles      particleState
+	particleBursts []ParticleBurst
+
+	// Daily news briefing preferences and briefings ready since the UI last asked (see GetNewsDigests)
+	newsDigest  newsDigestState
+	newsDigests [][]string
+
+	// Local interaction analytics (nil unless the user opted in)
+	analytics           *analytics.Collector
+	lastResponseBackend string // Dialog backend behind the current response
+	lastMoodSample      time.Time
+
+	// Remembers user-chosen sizes between runs (nil means don't persist)
+	sizeStore *SizeStore
+
+	// User profile the character is talking to, empty for the default profile
+	profileName string
+
+	// User relationship tuning, applied over the card's (see relationship_tuning.go)
+	relationshipTuning RelationshipTuning
+	tuningStore        *RelationshipTuningStore
 }
 
 // New creates a new character instance from a character card
@@ -170,6 +238,10 @@ func NewWithPlatform(card *CharacterCard, basePath string, platformInfo *platfor
 		return nil, err
 	}
 
+	// Transitions start with the first switch, not the initial animation
+	char.animationManager.SetTransitions(card.Transitions)
+	char.preloadLikelyAnimations()
+
 	logrus.WithFields(logrus.Fields{
 		"caller": caller,
 	}).Debug("Initial animation setup completed")
@@ -312,6 +384,11 @@ func initializeCharacterSystems(char *Character) error {
 		}
 	}
 
+	// Run the behavior script last so it can read stats from the start
+	if err := char.loadScript(); err != nil {
+		return fmt.Errorf("failed to load behavior script: %w", err)
+	}
+
 	return nil
 }
 
@@ -371,7 +448,14 @@ func loadSingleAnimation(char *Character, animationName string) animationLoadRes
 		}
 	}
 
-	if err := char.animationManager.LoadAnimation(animationName, fullPath); err != nil {
+	// Idle is shown first and is decoded now, so a broken one is reported
+	// here; the rest are decoded on first use or when preloaded as a likely
+	// next animation
+	load := char.animationManager.RegisterAnimation
+	if animationName == "idle" {
+		load = char.animationManager.LoadAnimation
+	}
+	if err := load(animationName, fullPath); err != nil {
 		return animationLoadResult{
 			name:    animationName,
 			success: false,
@@ -403,15 +487,24 @@ func validateAnimationResults(loadedAnimations, failedAnimations []string, total
 }
 
 // setupInitialAnimation sets the initial animation, preferring "idle" but falling back to any available animation.
+// Animations that turn out not to decode are skipped; if none do, the character is static.
 func setupInitialAnimation(char *Character, loadedAnimations []string) error {
 	if len(loadedAnimations) > 0 {
 		// Try to set "idle" first
 		if err := char.animationManager.SetCurrentAnimation("idle"); err != nil {
-			// If idle failed, try the first available animation
-			if err := char.animationManager.SetCurrentAnimation(loadedAnimations[0]); err != nil {
-				return fmt.Errorf("failed to set any initial animation: %w", err)
+			// If idle failed, try the other animations in turn
+			for _, name := range loadedAnimations {
+				if name == "idle" {
+					continue
+				}
+				if err := char.animationManager.SetCurrentAnimation(name); err != nil {
+					fmt.Printf("Warning: failed to load animation '%s': %v\n", name, err)
+					continue
+				}
+				fmt.Printf("Warning: 'idle' animation not available, using '%s' instead\n", name)
+				return nil
 			}
-			fmt.Printf("Warning: 'idle' animation not available, using '%s' instead\n", loadedAnimations[0])
+			fmt.Println("Warning: No animation could be decoded - character will be static")
 		}
 	} else {
 		// No animations loaded - character can still function but will be static
@@ -441,9 +534,9 @@ func (c *Character) initializeGameFeatures() {
 	// Initialize game state with stats from character card
 	c.gameState = NewGameState(c.card.Stats, gameConfig)
 
-	// Initialize progression system if configured
-	if c.card.Progression != nil {
-		c.gameState.SetProgression(c.card.Progression)
+	// Initialize progression system if configured (includes top-level achievements)
+	if progressionConfig := c.card.GetProgressionConfig(); progressionConfig != nil {
+		c.gameState.SetProgression(progressionConfig)
 	}
 
 	// Initialize random events manager if random events are configured
@@ -462,6 +555,8 @@ func (c *Character) initializeGameFeatures() {
 	// Initialize advanced features if romance features are enabled
 	if c.card.HasRomanceFeatures() {
 		c.initializeAdvancedFeatures()
+	} else {
+		c.applyRelationshipTuningLocked()
 	}
 }
 
@@ -474,9 +569,7 @@ func (c *Character) initializeAdvancedFeatures() {
 
 	// Create default jealousy triggers based on personality
 	jealousyTriggers := c.createDefaultJealousyTriggers(jealousyProne)
-	jealousyThreshold := 70.0 + (jealousyProne * 20.0) // Threshold from 70-90 based on trait
-
-	c.jealousyManager = NewJealousyManager(jealousyTriggers, jealousyEnabled, jealousyThreshold)
+	c.jealousyManager = NewJealousyManager(jealousyTriggers, jealousyEnabled, c.personalityJealousyThreshold())
 
 	// Initialize compatibility analyzer with adaptation strength based on personality
 	adaptationStrength := 0.5 // Default moderate adaptation
@@ -489,6 +582,15 @@ func (c *Character) initializeAdvancedFeatures() {
 	// Initialize crisis recovery manager with personality-based thresholds
 	crisisThresholds := c.createPersonalityBasedCrisisThresholds()
 	c.crisisRecoveryManager = NewCrisisRecoveryManager(true, crisisThresholds)
+
+	// Card and user relationship tuning override the personality defaults
+	c.applyRelationshipTuningLocked()
+}
+
+// personalityJealousyThreshold returns the jealousy level where consequences
+// start, from 70 to 90 based on the jealousy_prone trait
+func (c *Character) personalityJealousyThreshold() float64 {
+	return 70.0 + (c.card.GetPersonalityTrait("jealousy_prone") * 20.0)
 }
 
 // createDefaultJealousyTriggers creates jealousy triggers based on personality traits
@@ -586,7 +688,12 @@ func (c *Character) initializeDialogSystem() error {
 
 	// Register available backends
 	c.dialogManager.RegisterBackend("simple_random", dialog.NewSimpleRandomBackend())
-	c.dialogManager.RegisterBackend("markov_chain", dialog.NewMarkovChainBackend())
+	markovBackend := dialog.NewMarkovChainBackend()
+	markovBackend.SetBaseDir(c.basePath) // Relative corpusDir/modelPath live next to the card
+	c.dialogManager.RegisterBackend("markov_chain", markovBackend)
+	scriptedBackend := dialog.NewScriptedBackend()
+	scriptedBackend.SetBaseDir(c.basePath) // A relative scriptFile lives next to the card
+	c.dialogManager.RegisterBackend("scripted", scriptedBackend)
 
 	// Register LLM backend with error handling (optional dependency)
 	llmBackend := dialog.NewLLMDialogBackend()
@@ -727,8 +834,41 @@ func (c *Character) Update() bool {
 	// Update animation frames
 	frameChanged := c.animationManager.Update()
 
+	// Enter or leave the sleep window before anything else
+	now := time.Now()
+	sleepChanged := c.updateSleepState(now)
+
+	// Finish the active job once its timer runs out
+	jobChanged := c.updateJobs(now)
+
+	// Birthdays, anniversaries and holidays
+	calendarChanged := c.updateCalendar(now)
+
+	// Daily greetings, reminders and other timed events
+	scheduleChanged := c.updateSchedule(now)
+
+	// Pomodoro work sessions and breaks
+	focusChanged := c.updateFocus(now)
+
+	c.updateAnalytics(now)
+
+	// Daily news briefing
+	c.updateNewsDigest(now)
+
+	// Hearts, sweat drops and sparkles for stat and level changes
+	c.updateParticles(now)
+
 	// Process game state updates and check for state changes
-	stateChanged := c.processGameStateUpdates()
+	stateChanged := c.processGameStateUpdates() || sleepChanged || jobChanged || calendarChanged || scheduleChanged || focusChanged
+
+	// Tell the behavior script about stats that moved
+	c.updateScriptStats()
+
+	// The screensaver showcase overrides everything else while it runs
+	if !c.showcase.startedAt.IsZero() {
+		showcaseChanged := c.updateShowcase(now)
+		return frameChanged || stateChanged || showcaseChanged
+	}
 
 	// Check for idle timeout if no other state changes occurred
 	if !stateChanged {
@@ -763,6 +903,9 @@ func (c *Character) processRandomEvents(elapsed time.Duration) bool {
 	// Process regular random events
 	if c.randomEventManager != nil {
 		frequencyMultiplier := c.eventFrequencyMultiplier // Direct access within locked context
+		if c.sleeping {
+			frequencyMultiplier *= c.card.SleepSchedule.GetEventFrequencyMultiplier()
+		}
 		triggeredEvent := c.randomEventManager.UpdateWithFrequency(elapsed, c.gameState, frequencyMultiplier)
 		if triggeredEvent != nil {
 			stateChanged = c.handleTriggeredEvent(triggeredEvent) || stateChanged
@@ -790,9 +933,11 @@ func (c *Character) processRandomEvents(elapsed time.Duration) bool {
 func (c *Character) processAdvancedRomanceFeatures() bool {
 	stateChanged := false
 
-	// Process jealousy mechanics
+	// Process jealousy mechanics, remembering its penalties for apologies
 	if c.jealousyManager != nil {
+		statsBefore := c.gameState.GetStats()
 		jealousyEvent := c.jealousyManager.Update(c.gameState, c.lastInteraction)
+		c.rememberHarm(statsBefore)
 		if jealousyEvent != nil {
 			stateChanged = c.handleTriggeredEvent(jealousyEvent) || stateChanged
 		}
@@ -808,15 +953,18 @@ func (c *Character) processAdvancedRomanceFeatures() bool {
 		}
 	}
 
-	// Process crisis management
+	// Process crisis management, remembering its penalties for apologies
 	if c.crisisRecoveryManager != nil {
+		statsBefore := c.gameState.GetStats()
 		crisisEvent, inCrisis := c.crisisRecoveryManager.Update(c.gameState)
+		c.rememberHarm(statsBefore)
 		if crisisEvent != nil {
 			stateChanged = c.handleTriggeredEvent(crisisEvent) || stateChanged
 		}
 
 		// Store crisis state for other systems to use
 		c.setInCrisisModeUnsafe(inCrisis)
+		c.syncCrisisChanges()
 	}
 
 	return stateChanged
@@ -931,11 +1079,11 @@ func (c *Character) canTriggerRomanceEvent(event RandomEventConfig, now time.Tim
 	}
 
 	// Use enhanced romance condition checking
-	if len(event.Conditions) > 0 {
-		return c.gameState.CanSatisfyRomanceRequirements(event.Conditions)
+	if len(event.Conditions) > 0 && !c.gameState.CanSatisfyRomanceRequirements(event.Conditions) {
+		return false
 	}
 
-	return true
+	return c.gameState.EvaluateCondition(event.Condition)
 }
 
 // rollEventProbability performs probability check for romance event triggering
@@ -975,10 +1123,18 @@ func (c *Character) createEnhancedGameStateForRomanceEvents() *GameState {
 
 // handleTriggeredEvent processes a triggered random event and returns true if state changed
 func (c *Character) handleTriggeredEvent(triggeredEvent *TriggeredEvent) bool {
-	// Apply stat effects
+	// Apply stat effects, remembering losses an apology can make up for
 	if triggeredEvent.HasEffects() {
+		statsBefore := c.gameState.GetStats()
 		c.gameState.ApplyInteractionEffects(triggeredEvent.Effects)
+		c.rememberHarm(statsBefore)
 	}
+	if triggeredEvent.Buff != nil {
+		c.gameState.AddBuff(*triggeredEvent.Buff)
+	}
+	c.gameState.AddJournalEntry(JournalEvent, triggeredEvent.Name, triggeredEvent.Description)
+	// The behavior script hears about the event after its own animation starts
+	defer c.notifyScriptEvent(triggeredEvent.Name)
 
 	// Trigger animation if specified
 	if triggeredEvent.HasAnimations() {
@@ -1009,10 +1165,26 @@ func (c *Character) processCriticalStates(triggeredStates []string) bool {
 
 // checkIdleTimeout checks if character should return to idle state
 func (c *Character) checkIdleTimeout() bool {
-	if c.currentState == "idle" || time.Since(c.lastStateChange) < c.idleTimeout {
+	restState := "idle"
+	if focusAnimation, ok := c.focusAnimation(); ok {
+		restState = focusAnimation
+	} else if jobAnimation, ok := c.jobAnimation(); ok {
+		restState = jobAnimation
+	} else if sleepAnimation, ok := c.sleepAnimation(); ok {
+		restState = sleepAnimation
+	} else if awayAnimation, ok := c.awayAnimation(); ok {
+		restState = awayAnimation
+	}
+
+	if c.currentState == restState || time.Since(c.lastStateChange) < c.idleTimeout {
 		return false
 	}
 
+	if restState != "idle" {
+		c.setState(restState)
+		return true
+	}
+
 	idleAnimation := c.selectIdleAnimation()
 	c.setState(idleAnimation)
 	return true
@@ -1028,28 +1200,40 @@ func (c *Character) GetCurrentFrame() image.Image {
 // HandleClick processes a click interaction on the character
 // Returns dialog text to display, or empty string if no dialog should show
 // HandleClick processes a click interaction, using advanced dialog system if enabled
-func (c *Character) HandleClick() string {
+func (c *Character) HandleClick() (text string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.recordInteraction("click", time.Now(), &text)
 
 	c.lastInteraction = time.Now()
 
+	// A sleeping character only mumbles when poked
+	if c.sleeping {
+		return c.sleepyResponse()
+	}
+
+	// A behavior script can answer the click itself
+	if response, ok := c.scriptClickResponse(); ok {
+		return response
+	}
+
 	// Try advanced dialog system first
 	if c.useAdvancedDialogs && c.dialogManager != nil {
 		context := c.buildDialogContext("click")
 		response, err := c.dialogManager.GenerateDialog(context)
 		if err == nil && response.Confidence >= c.card.DialogBackend.ConfidenceThreshold {
+			c.lastResponseBackend = response.Backend
 			c.setState(response.Animation)
 			// Update dialog memory for learning if enabled
 			if c.card.DialogBackend.MemoryEnabled {
 				c.updateDialogMemory(response, context)
 			}
-			return response.Text
+			return c.withConversationReference(response.Text)
 		}
 	}
 
 	// Fallback to existing logic
-	return c.handleClickFallback()
+	return c.withConversationReference(c.handleClickFallback())
 }
 
 // handleClickFallback implements the original click handling logic
@@ -1079,9 +1263,10 @@ func (c *Character) handleClickFallback() string {
 }
 
 // HandleRightClick processes a right-click interaction, using advanced dialog system if enabled
-func (c *Character) HandleRightClick() string {
+func (c *Character) HandleRightClick() (text string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.recordInteraction("rightclick", time.Now(), &text)
 
 	c.lastInteraction = time.Now()
 
@@ -1090,6 +1275,7 @@ func (c *Character) HandleRightClick() string {
 		context := c.buildDialogContext("rightclick")
 		response, err := c.dialogManager.GenerateDialog(context)
 		if err == nil && response.Confidence >= c.card.DialogBackend.ConfidenceThreshold {
+			c.lastResponseBackend = response.Backend
 			c.setState(response.Animation)
 			// Update dialog memory for learning if enabled
 			if c.card.DialogBackend.MemoryEnabled {
@@ -1120,9 +1306,10 @@ func (c *Character) handleRightClickFallback() string {
 }
 
 // HandleHover processes a hover interaction
-func (c *Character) HandleHover() string {
+func (c *Character) HandleHover() (text string) {
 	c.mu.Lock() // Use write lock to properly synchronize cooldown updates
 	defer c.mu.Unlock()
+	defer c.recordInteraction("hover", time.Now(), &text)
 
 	// Only process hover if not recently interacted
 	if time.Since(c.lastInteraction) < 2*time.Second {
@@ -1178,6 +1365,13 @@ func (c *Character) GetSize() int {
 	return c.size
 }
 
+// SetSize changes the character display size in pixels
+func (c *Character) SetSize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = size
+}
+
 // GetName returns character name
 func (c *Character) GetName() string {
 	c.mu.RLock()
@@ -1226,19 +1420,21 @@ func (c *Character) setState(state string) {
 		return
 	}
 
-	// Only change state if the animation exists
-	if err := c.animationManager.SetCurrentAnimation(moodState); err == nil {
+	// Only change state if the animation exists. An equipped shop skin swaps
+	// the animation played while currentState keeps the logical state.
+	if err := c.animationManager.SetCurrentAnimation(c.applySkin(moodState)); err == nil {
 		c.currentState = moodState
 		c.lastStateChange = time.Now()
 	} else {
 		// Try original state if mood state failed
 		if c.currentState != state {
-			if err := c.animationManager.SetCurrentAnimation(state); err == nil {
+			if err := c.animationManager.SetCurrentAnimation(c.applySkin(state)); err == nil {
 				c.currentState = state
 				c.lastStateChange = time.Now()
 			}
 		}
 	}
+	c.preloadLikelyAnimations()
 }
 
 // ForceState allows external code to force a specific animation state
@@ -1263,6 +1459,14 @@ func (c *Character) GetAvailableAnimations() []string {
 	return c.animationManager.GetLoadedAnimations()
 }
 
+// GetStateAnimation returns the animation played for a state, with any
+// equipped skin applied
+func (c *Character) GetStateAnimation(state string) (*gif.GIF, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.animationManager.GetAnimation(c.applySkin(state))
+}
+
 // GetDialogCooldownStatus returns cooldown information for debugging
 func (c *Character) GetDialogCooldownStatus() map[string]time.Duration {
 	c.mu.RLock()
@@ -1308,6 +1512,7 @@ func (c *Character) EnableGameMode(saveManager interface{}, loadSave string) err
 	}
 
 	c.gameState = NewGameState(c.card.Stats, gameConfig)
+	c.applyRelationshipTuningLocked()
 
 	// Initialize interaction cooldowns for game interactions
 	for interactionName := range c.card.Interactions {
@@ -1319,9 +1524,10 @@ func (c *Character) EnableGameMode(saveManager interface{}, loadSave string) err
 
 // HandleGameInteraction processes game-specific interactions (feed, play, pet, etc.)
 // Returns response text to display, or empty string if interaction is not available
-func (c *Character) HandleGameInteraction(interactionType string) string {
+func (c *Character) HandleGameInteraction(interactionType string) (text string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.recordInteraction(interactionType, time.Now(), &text)
 
 	// Check if game mode is enabled
 	if c.gameState == nil {
@@ -1341,12 +1547,17 @@ func (c *Character) HandleGameInteraction(interactionType string) string {
 	}
 
 	// Check requirements
-	if !c.gameState.CanSatisfyRequirements(interaction.Requirements) {
+	if !c.gameState.CanSatisfyRequirements(interaction.Requirements) ||
+		!c.gameState.EvaluateCondition(interaction.Condition) {
 		return "" // Requirements not met
 	}
 
-	// Apply effects
+	// Apply effects; expression effects see the stats before the flat effects
+	statsBefore, coinsBefore := c.gameState.GetStats(), c.gameState.GetCoins()
+	c.gameState.ApplyEffectExpressions(interaction.EffectExpressions)
 	c.gameState.ApplyInteractionEffects(interaction.Effects)
+	c.gameState.RecordInteraction(interactionType)
+	c.rememberUndoable(interactionType, statsBefore, coinsBefore, lastUsed, exists)
 
 	// Set cooldown
 	c.gameInteractionCooldowns[interactionType] = time.Now()
@@ -1372,9 +1583,10 @@ func (c *Character) HandleGameInteraction(interactionType string) string {
 // HandleRomanceInteraction processes romance-specific interactions (compliment, gift, conversation, etc.)
 // Returns response text to display, or empty string if interaction is not available
 // This implements the missing runtime functionality for the JSON-configured romance system
-func (c *Character) HandleRomanceInteraction(interactionType string) string {
+func (c *Character) HandleRomanceInteraction(interactionType string) (text string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.recordInteraction(interactionType, time.Now(), &text)
 
 	// Validate interaction preconditions
 	interaction, ok := c.validateRomanceInteraction(interactionType)
@@ -1428,7 +1640,8 @@ func (c *Character) checkRomanceRequirements(interaction InteractionConfig, inte
 	}
 
 	// Check requirements
-	return c.gameState.CanSatisfyRequirements(interaction.Requirements)
+	return c.gameState.CanSatisfyRequirements(interaction.Requirements) &&
+		c.gameState.EvaluateCondition(interaction.Condition)
 }
 
 // processRomanceEffects handles personality modification, effect application, and response generation
@@ -1443,6 +1656,7 @@ func (c *Character) processRomanceEffects(interaction InteractionConfig, interac
 	statsBefore := c.gameState.GetStats()
 
 	// Apply effects
+	c.gameState.ApplyEffectExpressions(interaction.EffectExpressions)
 	c.gameState.ApplyInteractionEffects(modifiedEffects)
 
 	// Record stats after interaction
@@ -1467,18 +1681,17 @@ func (c *Character) handlePostRomanceInteraction(interaction InteractionConfig,
 	c.setRomanceAnimation(interaction)
 }
 
-// updateRelationshipProgression checks and handles relationship level changes
+// updateRelationshipProgression checks for relationship level changes and
+// celebrates reaching a higher level with its ceremony
 func (c *Character) updateRelationshipProgression() {
-	if c.card.Progression != nil {
-		levelChanged := c.gameState.UpdateRelationshipLevel(c.card.Progression)
-		if levelChanged {
-			// Log level change for debugging
-			newLevel := c.gameState.GetRelationshipLevel()
-			c.setState("level_up") // Could trigger special animation
-
-			// You could also trigger a special level-up response here
-			_ = newLevel // Use for potential level-up dialogue
-		}
+	if c.card.Progression == nil {
+		return
+	}
+	from := c.gameState.GetRelationshipLevel()
+	before := c.levelUnlocks()
+	if c.gameState.UpdateRelationshipLevel(c.card.Progression) &&
+		c.card.levelIndex(c.gameState.GetRelationshipLevel()) > c.card.levelIndex(from) {
+		c.celebrateLevelUp(from, before)
 	}
 }
 
@@ -1508,6 +1721,7 @@ func (c *Character) checkCrisisRecoveryResponse(interaction InteractionConfig, i
 		if recoveryEvent != nil {
 			// Crisis was resolved! Override normal response with recovery response
 			c.handleTriggeredEvent(recoveryEvent)
+			c.syncCrisisChanges()
 			if len(recoveryEvent.Responses) > 0 {
 				recoveryResponse := recoveryEvent.Responses[int(time.Now().UnixNano())%len(recoveryEvent.Responses)]
 				return recoveryResponse
@@ -1719,6 +1933,12 @@ func (c *Character) GetGameState() *GameState {
 	return c.gameState
 }
 
+// GetCriticalStates returns the stats at or below their critical threshold,
+// nil outside game mode
+func (c *Character) GetCriticalStates() []string {
+	return c.GetGameState().GetCriticalStates()
+}
+
 // GetEventFrequencyMultiplier returns the current random event frequency multiplier
 // Feature 6: Random Event Frequency Tuning
 func (c *Character) GetEventFrequencyMultiplier() float64 {
@@ -1831,7 +2051,8 @@ func (c *Character) canSatisfyRomanceRequirements(requirements *RomanceRequireme
 
 	return c.checkStatRequirements(requirements.Stats) &&
 		c.checkRelationshipLevel(requirements.RelationshipLevel) &&
-		c.checkInteractionCounts(requirements.InteractionCount)
+		c.checkInteractionCounts(requirements.InteractionCount) &&
+		c.gameState.EvaluateCondition(requirements.Condition)
 }
 
 // checkStatRequirements validates that all stat requirements are satisfied
@@ -2230,7 +2451,8 @@ func (c *Character) CanUseGameInteraction(interactionType string) bool {
 	}
 
 	// Check requirements
-	return c.gameState.CanSatisfyRequirements(interaction.Requirements)
+	return c.gameState.CanSatisfyRequirements(interaction.Requirements) &&
+		c.gameState.EvaluateCondition(interaction.Condition)
 }
 
 // buildDialogContext creates a comprehensive context for dialog generation
@@ -2262,6 +2484,7 @@ func (c *Character) buildDialogContext(trigger string) dialog.DialogContext {
 
 	// Add time of day context
 	context.TimeOfDay = c.getTimeOfDay()
+	context.ProfileName = c.profileName
 
 	// Add fallback responses from existing dialogs
 	context.FallbackResponses = c.getFallbackResponses(trigger)
@@ -2292,6 +2515,14 @@ func (c *Character) buildDialogContext(trigger string) dialog.DialogContext {
 		}
 	}
 
+	// Share what was talked about in chat with every trigger
+	if conversation := c.conversationTopicContext(); conversation != nil {
+		if context.TopicContext == nil {
+			context.TopicContext = make(map[string]interface{})
+		}
+		context.TopicContext["conversation"] = conversation
+	}
+
 	return context
 }
 
@@ -2408,9 +2639,10 @@ func (c *Character) updateDialogMemory(response dialog.DialogResponse, context d
 
 // HandleGeneralEvent processes a general dialog event by name
 // Returns response text to display, or empty string if event cannot be triggered
-func (c *Character) HandleGeneralEvent(eventName string) string {
+func (c *Character) HandleGeneralEvent(eventName string) (text string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.recordInteraction("event", time.Now(), &text)
 
 	if c.generalEventManager == nil {
 		return "" // General events not enabled
@@ -2428,6 +2660,7 @@ func (c *Character) HandleGeneralEvent(eventName string) string {
 	if len(event.Animations) > 0 {
 		c.setState(event.Animations[0])
 	}
+	c.notifyScriptEvent(event.Name)
 
 	// Return random response
 	if len(event.Responses) > 0 {
@@ -2545,9 +2778,10 @@ func (c *Character) GetGeneralEventManager() *GeneralEventManager {
 // HandleChatMessage processes a chatbot message interaction for AI-enabled characters
 // Returns response text to display, or empty string if chatbot is not available
 // This method reuses the existing dialog backend infrastructure for consistency
-func (c *Character) HandleChatMessage(message string) string {
+func (c *Character) HandleChatMessage(message string) (text string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.recordInteraction("chat", time.Now(), &text)
 
 	c.lastInteraction = time.Now()
 
@@ -2557,6 +2791,7 @@ func (c *Character) HandleChatMessage(message string) string {
 	}
 
 	// Build dialog context for chat message
+	c.rememberChatMessage(message)
 	context := c.buildChatDialogContext(message)
 
 	// Generate response using dialog backend
@@ -2566,11 +2801,70 @@ func (c *Character) HandleChatMessage(message string) string {
 		return c.handleChatFallback(message)
 	}
 
+	return c.finishChatResponse(message, context, &response)
+}
+
+// HandleChatMessageStream works like HandleChatMessage but reports the
+// response while it is generated. onPartial receives the text so far each
+// time more arrives. Blocks until the response is complete, so call it off
+// the UI thread; the character lock is not held while the backend works.
+func (c *Character) HandleChatMessageStream(message string, onPartial func(text string)) (text string) {
+	start := time.Now()
+
+	c.mu.Lock()
+	c.lastInteraction = start
+	if !c.useAdvancedDialogs || c.dialogManager == nil {
+		c.recordInteraction("chat", start, &text)
+		c.mu.Unlock()
+		return ""
+	}
+	c.rememberChatMessage(message)
+	context := c.buildChatDialogContext(message)
+	manager := c.dialogManager
+	c.mu.Unlock()
+
+	var streamed string
+	var final *dialog.DialogResponse
+	for chunk := range manager.GenerateDialogStream(context) {
+		if chunk.Err != nil {
+			break
+		}
+		streamed += chunk.Text
+		if chunk.Text != "" && onPartial != nil {
+			onPartial(streamed)
+		}
+		if chunk.Final != nil {
+			final = chunk.Final
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.recordInteraction("chat", start, &text)
+
+	text = c.finishChatResponse(message, context, final)
+	if text != streamed && onPartial != nil {
+		// Low confidence or failure replaced what was shown
+		onPartial(text)
+	}
+	return text
+}
+
+// finishChatResponse applies a generated chat response: confidence check,
+// animation and dialog memory. Returns the text to show.
+// Caller must hold c.mu.
+func (c *Character) finishChatResponse(message string, context dialog.DialogContext, response *dialog.DialogResponse) string {
+	c.suggestedReplies = nil
+
 	// Check confidence threshold
-	if response.Confidence < c.card.DialogBackend.ConfidenceThreshold {
+	if response == nil || response.Confidence < c.card.DialogBackend.ConfidenceThreshold {
 		return c.handleChatFallback(message)
 	}
 
+	c.lastResponseBackend = response.Backend
+	c.suggestedReplies = response.SuggestedReplies
+	c.rememberResponseTopics(response.Topics)
+
 	// Set animation if specified
 	if response.Animation != "" {
 		c.setState(response.Animation)
@@ -2578,12 +2872,20 @@ func (c *Character) HandleChatMessage(message string) string {
 
 	// Update dialog memory for learning if enabled
 	if c.card.DialogBackend.MemoryEnabled {
-		c.updateDialogMemory(response, context)
+		c.updateDialogMemory(*response, context)
 	}
 
 	return response.Text
 }
 
+// GetSuggestedReplies returns the replies offered with the last chat
+// response, such as a scripted conversation's choices, or nil if there are none
+func (c *Character) GetSuggestedReplies() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.suggestedReplies...)
+}
+
 // buildChatDialogContext creates dialog context specifically for chat messages
 // Extends the standard dialog context with chat-specific information and personality traits
 func (c *Character) buildChatDialogContext(message string) dialog.DialogContext {
@@ -2593,8 +2895,22 @@ func (c *Character) buildChatDialogContext(message string) dialog.DialogContext
 	context.ConversationTurn += 1
 	context.LastResponse = message
 
-	// Add topic context based on message content
-	context.TopicContext = c.extractTopicsFromMessage(message)
+	// A message that matches a suggested reply is that reply, picked or typed
+	for _, reply := range c.suggestedReplies {
+		if strings.EqualFold(strings.TrimSpace(message), reply) {
+			context.ChosenReply = reply
+			break
+		}
+	}
+
+	// Add topic context based on message content, keeping the shared context
+	topics := c.extractTopicsFromMessage(message)
+	if context.TopicContext == nil {
+		context.TopicContext = topics
+	}
+	for topic, value := range topics {
+		context.TopicContext[topic] = value
+	}
 
 	// ENHANCEMENT: Add character personality traits to context for AI generation
 	if c.card.Personality != nil && c.card.Personality.Traits != nil {
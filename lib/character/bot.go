@@ -0,0 +1,195 @@
+package character
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/bot"
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// BotTickInterval is how often a running bot reconsiders what to do
+const BotTickInterval = 500 * time.Millisecond
+
+// botGifts are the small tokens a bot hands out to peers
+var botGifts = []string{"a flower", "a cookie", "a shiny pebble", "a drawing", "a paper crane"}
+
+// BotTransport is the part of the network manager a bot uses to reach peers
+type BotTransport interface {
+	GetPeers() []network.Peer
+	GetPeerID() string
+	SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType network.MessageType, handler network.MessageHandler)
+}
+
+// NewBot creates a bot that plays with the character on its own. The
+// personality comes from the named built-in archetype, or from the card's
+// botPersonality (falling back to the balanced default) when archetype is
+// empty. transport may be nil for a bot that only looks after its own
+// character; with one, the bot also chats, battles and trades gifts with
+// peers and answers their battle invitations.
+func (c *Character) NewBot(transport BotTransport, archetype string) (*bot.BotController, error) {
+	personality, err := c.botPersonality(archetype)
+	if err != nil {
+		return nil, err
+	}
+
+	var peers bot.NetworkController
+	var link *botNetwork
+	if transport != nil {
+		link = &botNetwork{transport: transport, character: c}
+		peers = link
+	}
+
+	controller, err := bot.NewBotController(personality, &botCharacter{c: c}, peers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bot: %w", err)
+	}
+	if link != nil {
+		link.answerBattleInvites(controller)
+	}
+	return controller, nil
+}
+
+// botPersonality resolves the personality for NewBot
+func (c *Character) botPersonality(archetype string) (bot.BotPersonality, error) {
+	pm := bot.NewPersonalityManager()
+	if archetype != "" {
+		template, err := pm.GetArchetype(archetype)
+		if err != nil {
+			return bot.BotPersonality{}, err
+		}
+		personality, err := pm.CreatePersonality(template)
+		if err != nil {
+			return bot.BotPersonality{}, err
+		}
+		return *personality, nil
+	}
+
+	personality, err := c.GetCard().GetBotPersonality()
+	if err != nil {
+		return bot.BotPersonality{}, fmt.Errorf("invalid bot personality: %w", err)
+	}
+	if personality == nil {
+		return bot.DefaultPersonality(), nil
+	}
+	return *personality, nil
+}
+
+// botCharacter lets a bot drive the character the same way a user would
+type botCharacter struct {
+	c *Character
+}
+
+func (b *botCharacter) HandleClick() string      { return b.c.HandleClick() }
+func (b *botCharacter) HandleRightClick() string { return b.c.HandleRightClick() }
+
+// HandleDoubleClick plays, like double-tapping the character in the window
+func (b *botCharacter) HandleDoubleClick() string { return b.c.HandleGameInteraction("play") }
+
+func (b *botCharacter) GetCurrentState() string { return b.c.GetCurrentState() }
+
+func (b *botCharacter) GetLastInteractionTime() time.Time {
+	b.c.mu.RLock()
+	defer b.c.mu.RUnlock()
+	return b.c.lastInteraction
+}
+
+func (b *botCharacter) GetStats() map[string]float64 { return b.c.GetGameState().GetStats() }
+func (b *botCharacter) GetMood() float64             { return b.c.GetGameState().GetOverallMood() }
+func (b *botCharacter) IsGameMode() bool             { return b.c.GetGameState() != nil }
+
+// ComposeChat writes an opening line for a peer with the dialog backend
+func (b *botCharacter) ComposeChat(peerID string) string {
+	return b.c.GeneratePeerChatLine("", "", 0)
+}
+
+// botNetwork carries bot chats, challenges and gifts over the network
+type botNetwork struct {
+	transport BotTransport
+	character *Character
+}
+
+func (n *botNetwork) GetPeerCount() int { return len(n.transport.GetPeers()) }
+
+func (n *botNetwork) GetPeerIDs() []string {
+	peers := n.transport.GetPeers()
+	ids := make([]string, len(peers))
+	for i, peer := range peers {
+		ids[i] = peer.ID
+	}
+	return ids
+}
+
+func (n *botNetwork) IsNetworkEnabled() bool { return true }
+
+// SendMessage sends a chat-style payload as a character action
+func (n *botNetwork) SendMessage(peerID string, message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	return n.transport.SendMessage(network.MessageTypeCharacterAction, payload, peerID)
+}
+
+// InitiateBattle sends a battle invitation, like the Battle menu does
+func (n *botNetwork) InitiateBattle(peerID string) error {
+	payload, err := json.Marshal(network.BattleInvitePayload{
+		FromCharacterID: n.transport.GetPeerID(),
+		ToCharacterID:   peerID,
+		BattleID:        generateBattleID(),
+		Timestamp:       time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode battle invite: %w", err)
+	}
+	return n.transport.SendMessage(network.MessageTypeBattleInvite, payload, peerID)
+}
+
+// SendGift gives the peer one of the bot gifts
+func (n *botNetwork) SendGift(peerID string) (string, error) {
+	gift := botGifts[rand.Intn(len(botGifts))]
+	payload, err := json.Marshal(map[string]string{
+		"type":   "gift",
+		"action": "give_gift",
+		"gift":   gift,
+		"from":   n.transport.GetPeerID(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode gift: %w", err)
+	}
+	if err := n.transport.SendMessage(network.MessageTypeCharacterAction, payload, peerID); err != nil {
+		return "", err
+	}
+	return gift, nil
+}
+
+// answerBattleInvites lets the bot's personality decide on incoming battle
+// invitations. Accepted ones are answered with an "accept" battle action and
+// noted in the journal; declined ones are ignored, as in the UI.
+func (n *botNetwork) answerBattleInvites(controller *bot.BotController) {
+	n.transport.RegisterMessageHandler(network.MessageTypeBattleInvite, func(msg network.Message, from *network.Peer) error {
+		var invite network.BattleInvitePayload
+		if err := json.Unmarshal(msg.Payload, &invite); err != nil {
+			return fmt.Errorf("failed to decode battle invite: %w", err)
+		}
+		if invite.ToCharacterID != n.transport.GetPeerID() || !controller.ShouldAcceptBattle() {
+			return nil
+		}
+
+		payload, err := json.Marshal(network.BattleActionPayload{
+			BattleID:   invite.BattleID,
+			ActionType: "accept",
+			ActorID:    invite.ToCharacterID,
+			TargetID:   invite.FromCharacterID,
+			Timestamp:  time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode battle acceptance: %w", err)
+		}
+		n.character.GetGameState().AddJournalEntry(JournalBattle, "Accepted a battle from "+invite.FromCharacterID, "")
+		return n.transport.SendMessage(network.MessageTypeBattleAction, payload, invite.FromCharacterID)
+	})
+}
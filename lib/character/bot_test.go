@@ -0,0 +1,128 @@
+package character
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/bot"
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// fakeBotTransport records what a bot sends to peers
+type fakeBotTransport struct {
+	sent     []network.Message
+	handlers map[network.MessageType]network.MessageHandler
+}
+
+func newFakeBotTransport() *fakeBotTransport {
+	return &fakeBotTransport{handlers: make(map[network.MessageType]network.MessageHandler)}
+}
+
+func (f *fakeBotTransport) GetPeers() []network.Peer {
+	return []network.Peer{{ID: "rival"}}
+}
+
+func (f *fakeBotTransport) GetPeerID() string { return "me" }
+
+func (f *fakeBotTransport) SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error {
+	f.sent = append(f.sent, network.Message{Type: msgType, To: targetPeerID, Payload: payload})
+	return nil
+}
+
+func (f *fakeBotTransport) RegisterMessageHandler(msgType network.MessageType, handler network.MessageHandler) {
+	f.handlers[msgType] = handler
+}
+
+func TestNewBotPersonality(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+
+	controller, err := char.NewBot(nil, "")
+	if err != nil {
+		t.Fatalf("Failed to create bot: %v", err)
+	}
+	if controller.GetPersonality().InteractionRate != bot.DefaultPersonality().InteractionRate {
+		t.Error("Expected the default personality for a card without one")
+	}
+
+	controller, err = char.NewBot(nil, "social")
+	if err != nil {
+		t.Fatalf("Failed to create social bot: %v", err)
+	}
+	if controller.GetPersonality().SocialTendencies[bot.PersonalityTraits.Chattiness] != 0.9 {
+		t.Error("Expected the social archetype's chattiness")
+	}
+
+	if _, err := char.NewBot(nil, "grumpy"); err == nil {
+		t.Error("Expected an error for an unknown archetype")
+	}
+}
+
+func TestBotNetworkSendsToPeers(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	transport := newFakeBotTransport()
+	link := &botNetwork{transport: transport, character: char}
+
+	if ids := link.GetPeerIDs(); len(ids) != 1 || ids[0] != "rival" {
+		t.Errorf("Expected the rival peer, got %v", ids)
+	}
+	if err := link.InitiateBattle("rival"); err != nil {
+		t.Fatalf("Failed to challenge: %v", err)
+	}
+	gift, err := link.SendGift("rival")
+	if err != nil || gift == "" {
+		t.Fatalf("Failed to send gift: %v", err)
+	}
+
+	if len(transport.sent) != 2 || transport.sent[0].Type != network.MessageTypeBattleInvite || transport.sent[0].To != "rival" {
+		t.Fatalf("Expected a battle invite to the rival, got %+v", transport.sent)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(transport.sent[1].Payload, &payload); err != nil || payload["type"] != "gift" || payload["gift"] != gift {
+		t.Errorf("Expected a gift character action, got %s", transport.sent[1].Payload)
+	}
+	if network.MessagePermission(transport.sent[1]) != network.PermissionGifts {
+		t.Error("Expected bot gifts to fall under the gifts permission")
+	}
+}
+
+func TestBotAnswersBattleInvites(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Multiplayer = &MultiplayerConfig{
+		Enabled:    true,
+		BotCapable: true,
+		NetworkID:  "test",
+		BotPersonality: &bot.PersonalityArchetype{
+			Name:   "Fierce",
+			Traits: map[string]float64{"aggressiveness": 1},
+			Behavior: bot.PersonalityBehavior{
+				ResponseDelay:       "1s",
+				InteractionRate:     1,
+				Attention:           0.5,
+				MaxActionsPerMinute: 2,
+				MinTimeBetweenSame:  10,
+			},
+		},
+	}
+	char := createTestCharacterInstance(card, true)
+	transport := newFakeBotTransport()
+	if _, err := char.NewBot(transport, ""); err != nil {
+		t.Fatalf("Failed to create bot: %v", err)
+	}
+
+	invite, _ := json.Marshal(network.BattleInvitePayload{FromCharacterID: "rival", ToCharacterID: "me", BattleID: "b1"})
+	handler := transport.handlers[network.MessageTypeBattleInvite]
+	if handler == nil {
+		t.Fatal("Expected the bot to answer battle invites")
+	}
+	if err := handler(network.Message{Type: network.MessageTypeBattleInvite, Payload: invite}, &network.Peer{ID: "rival"}); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+
+	if len(transport.sent) != 1 || transport.sent[0].Type != network.MessageTypeBattleAction {
+		t.Fatalf("Expected an accept battle action, got %+v", transport.sent)
+	}
+	journal := char.GetJournal()
+	if len(journal) != 1 || journal[0].Title != "Accepted a battle from rival" {
+		t.Errorf("Expected the accepted battle in the journal, got %+v", journal)
+	}
+}
@@ -0,0 +1,93 @@
+package character
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Speech bubble tail positions
+const (
+	BubbleTailNone   = "none" // No tail (default)
+	BubbleTailLeft   = "left"
+	BubbleTailCenter = "center"
+	BubbleTailRight  = "right"
+
+	minBubbleFontSize = 8
+	maxBubbleFontSize = 48
+	maxCornerRadius   = 40
+	maxBorderWidth    = 10
+)
+
+// BubbleStyle customizes the character's speech bubble. Unset fields keep
+// the default look.
+type BubbleStyle struct {
+	Background   string  `json:"background,omitempty"`   // Fill color as "#RRGGBB" or "#RRGGBBAA"
+	Border       string  `json:"border,omitempty"`       // Outline color
+	Text         string  `json:"text,omitempty"`         // Text color
+	FontSize     float32 `json:"fontSize,omitempty"`     // Text size in points, 8-48
+	CornerRadius float32 `json:"cornerRadius,omitempty"` // Rounded corners, 0-40
+	BorderWidth  float32 `json:"borderWidth,omitempty"`  // Outline width, 0-10
+	Tail         string  `json:"tail,omitempty"`         // "none", "left", "center" or "right"
+}
+
+// ParseHexColor parses "#RGB", "#RRGGBB" or "#RRGGBBAA"
+func ParseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color '%s': use #RRGGBB or #RRGGBBAA", s)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color '%s': use #RRGGBB or #RRGGBBAA", s)
+	}
+	return color.RGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
+
+// Validate checks the style's colors and ranges
+func (s *BubbleStyle) Validate() error {
+	colors := []struct{ name, value string }{
+		{"background", s.Background}, {"border", s.Border}, {"text", s.Text},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		if _, err := ParseHexColor(c.value); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+	}
+
+	if s.FontSize != 0 && (s.FontSize < minBubbleFontSize || s.FontSize > maxBubbleFontSize) {
+		return fmt.Errorf("fontSize must be %d-%d, got %g", minBubbleFontSize, maxBubbleFontSize, s.FontSize)
+	}
+	if s.CornerRadius < 0 || s.CornerRadius > maxCornerRadius {
+		return fmt.Errorf("cornerRadius must be 0-%d, got %g", maxCornerRadius, s.CornerRadius)
+	}
+	if s.BorderWidth < 0 || s.BorderWidth > maxBorderWidth {
+		return fmt.Errorf("borderWidth must be 0-%d, got %g", maxBorderWidth, s.BorderWidth)
+	}
+
+	switch s.Tail {
+	case "", BubbleTailNone, BubbleTailLeft, BubbleTailCenter, BubbleTailRight:
+	default:
+		return fmt.Errorf("unknown tail '%s', use none, left, center or right", s.Tail)
+	}
+	return nil
+}
+
+// validateBubbleStyle validates the optional speech bubble style
+func (c *CharacterCard) validateBubbleStyle() error {
+	if c.BubbleStyle == nil {
+		return nil
+	}
+	return c.BubbleStyle.Validate()
+}
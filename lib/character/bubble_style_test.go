@@ -0,0 +1,51 @@
+package character
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{"#fff", color.RGBA{255, 255, 255, 255}, false},
+		{"#336699", color.RGBA{0x33, 0x66, 0x99, 0xff}, false},
+		{"#33669980", color.RGBA{0x33, 0x66, 0x99, 0x80}, false},
+		{"336699", color.RGBA{0x33, 0x66, 0x99, 0xff}, false},
+		{"#12345", color.RGBA{}, true},
+		{"#zzzzzz", color.RGBA{}, true},
+		{"red", color.RGBA{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHexColor(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseHexColor(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestBubbleStyleValidate(t *testing.T) {
+	valid := BubbleStyle{Background: "#202040e6", Border: "#88f", Text: "#ffffff", FontSize: 16, CornerRadius: 12, BorderWidth: 2, Tail: BubbleTailCenter}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid style, got %v", err)
+	}
+	if err := (&BubbleStyle{}).Validate(); err != nil {
+		t.Errorf("Expected an empty style to keep the defaults, got %v", err)
+	}
+
+	invalid := []BubbleStyle{
+		{Text: "blue"},
+		{FontSize: 4},
+		{CornerRadius: -1},
+		{BorderWidth: 20},
+		{Tail: "top"},
+	}
+	for _, style := range invalid {
+		if err := style.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", style)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package character
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Buff stacking rules, applied when a buff is granted while it is running
+const (
+	BuffStackRefresh = "refresh" // Restart the duration (default)
+	BuffStackExtend  = "extend"  // Add the duration to the time left
+	BuffStackStack   = "stack"   // Add a stack, up to MaxStacks, and restart the duration
+	BuffStackIgnore  = "ignore"  // Keep the running buff as it is
+)
+
+// Buff limits
+const (
+	maxBuffMinutes       = 7 * 24 * 60
+	defaultBuffMaxStacks = 3
+	maxBuffStacks        = 10
+)
+
+// BuffConfig describes a temporary stat modifier granted by a gift or
+// random event, e.g. +20% happiness gain for an hour after a coffee
+type BuffConfig struct {
+	ID        string             `json:"id,omitempty"`       // Identifies the buff for stacking, defaults to Name
+	Name      string             `json:"name"`               // Shown in the stats overlay tooltip
+	Icon      string             `json:"icon,omitempty"`     // Emoji shown in the stats overlay
+	Gain      map[string]float64 `json:"gain,omitempty"`     // Stat -> fraction added to increases, 0.2 = +20%
+	Decay     map[string]float64 `json:"decay,omitempty"`    // Stat -> fraction added to decay, -0.5 = half as fast
+	Minutes   int                `json:"durationMinutes"`    // How long the buff lasts
+	Stacking  string             `json:"stacking,omitempty"` // refresh, extend, stack or ignore
+	MaxStacks int                `json:"maxStacks,omitempty"`
+}
+
+// ActiveBuff is a buff currently modifying the stats
+type ActiveBuff struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Icon      string             `json:"icon,omitempty"`
+	Gain      map[string]float64 `json:"gain,omitempty"`
+	Decay     map[string]float64 `json:"decay,omitempty"`
+	Stacks    int                `json:"stacks"`
+	ExpiresAt time.Time          `json:"expiresAt"`
+}
+
+// Validate checks a buff's duration, stacking rule and modifiers
+func (b *BuffConfig) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("buff name is required")
+	}
+	if b.Minutes < 1 || b.Minutes > maxBuffMinutes {
+		return fmt.Errorf("buff %q durationMinutes must be between 1 and %d, got %d", b.Name, maxBuffMinutes, b.Minutes)
+	}
+	switch b.Stacking {
+	case "", BuffStackRefresh, BuffStackExtend, BuffStackStack, BuffStackIgnore:
+	default:
+		return fmt.Errorf("buff %q has unknown stacking %q", b.Name, b.Stacking)
+	}
+	if b.MaxStacks < 0 || b.MaxStacks > maxBuffStacks {
+		return fmt.Errorf("buff %q maxStacks must be between 0 and %d, got %d", b.Name, maxBuffStacks, b.MaxStacks)
+	}
+	if len(b.Gain) == 0 && len(b.Decay) == 0 {
+		return fmt.Errorf("buff %q must modify gain or decay of at least one stat", b.Name)
+	}
+	for stat, value := range b.Gain {
+		if value < -1 || value > 2 {
+			return fmt.Errorf("buff %q gain for %s must be between -1 and 2, got %f", b.Name, stat, value)
+		}
+	}
+	for stat, value := range b.Decay {
+		if value < -1 || value > 2 {
+			return fmt.Errorf("buff %q decay for %s must be between -1 and 2, got %f", b.Name, stat, value)
+		}
+	}
+	return nil
+}
+
+// IsDebuff reports whether the buff hurts more than it helps: less gain or
+// faster decay
+func (b ActiveBuff) IsDebuff() bool {
+	score := 0.0
+	for _, value := range b.Gain {
+		score += value
+	}
+	for _, value := range b.Decay {
+		score -= value
+	}
+	return score < 0
+}
+
+// AddBuff grants a buff, following its stacking rule if it is already running
+func (gs *GameState) AddBuff(config BuffConfig) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.addBuff(config, time.Now())
+}
+
+// addBuff grants a buff at the given time.
+// Must be called with gs.mu held.
+func (gs *GameState) addBuff(config BuffConfig, now time.Time) {
+	gs.pruneBuffs(now)
+
+	id := config.ID
+	if id == "" {
+		id = config.Name
+	}
+	duration := time.Duration(config.Minutes) * time.Minute
+
+	for i := range gs.Buffs {
+		buff := &gs.Buffs[i]
+		if buff.ID != id {
+			continue
+		}
+		switch config.Stacking {
+		case BuffStackIgnore:
+		case BuffStackExtend:
+			buff.ExpiresAt = buff.ExpiresAt.Add(duration)
+		case BuffStackStack:
+			maxStacks := config.MaxStacks
+			if maxStacks == 0 {
+				maxStacks = defaultBuffMaxStacks
+			}
+			if buff.Stacks < maxStacks {
+				buff.Stacks++
+			}
+			buff.ExpiresAt = now.Add(duration)
+		default:
+			buff.ExpiresAt = now.Add(duration)
+		}
+		return
+	}
+
+	gs.Buffs = append(gs.Buffs, ActiveBuff{
+		ID:        id,
+		Name:      config.Name,
+		Icon:      config.Icon,
+		Gain:      copyModifiers(config.Gain),
+		Decay:     copyModifiers(config.Decay),
+		Stacks:    1,
+		ExpiresAt: now.Add(duration),
+	})
+}
+
+// GetBuffs returns the running buffs, soonest to expire first
+func (gs *GameState) GetBuffs() []ActiveBuff {
+	if gs == nil {
+		return nil
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	now := time.Now()
+	buffs := make([]ActiveBuff, 0, len(gs.Buffs))
+	for _, buff := range gs.Buffs {
+		if now.Before(buff.ExpiresAt) {
+			buffs = append(buffs, buff)
+		}
+	}
+	sort.Slice(buffs, func(i, j int) bool { return buffs[i].ExpiresAt.Before(buffs[j].ExpiresAt) })
+	return buffs
+}
+
+// pruneBuffs drops expired buffs.
+// Must be called with gs.mu held.
+func (gs *GameState) pruneBuffs(now time.Time) {
+	running := gs.Buffs[:0]
+	for _, buff := range gs.Buffs {
+		if now.Before(buff.ExpiresAt) {
+			running = append(running, buff)
+		}
+	}
+	gs.Buffs = running
+}
+
+// buffGainMultiplier scales increases to a stat by the running buffs.
+// Must be called with gs.mu held.
+func (gs *GameState) buffGainMultiplier(stat string, now time.Time) float64 {
+	return gs.buffMultiplier(stat, now, func(b ActiveBuff) map[string]float64 { return b.Gain })
+}
+
+// buffDecayMultiplier scales a stat's decay by the running buffs.
+// Must be called with gs.mu held.
+func (gs *GameState) buffDecayMultiplier(stat string, now time.Time) float64 {
+	return gs.buffMultiplier(stat, now, func(b ActiveBuff) map[string]float64 { return b.Decay })
+}
+
+// buffMultiplier sums one kind of modifier across running buffs, one step
+// per stack, never dropping below zero
+func (gs *GameState) buffMultiplier(stat string, now time.Time, modifiers func(ActiveBuff) map[string]float64) float64 {
+	multiplier := 1.0
+	for _, buff := range gs.Buffs {
+		if now.Before(buff.ExpiresAt) {
+			multiplier += modifiers(buff)[stat] * float64(buff.Stacks)
+		}
+	}
+	if multiplier < 0 {
+		return 0
+	}
+	return multiplier
+}
+
+// copyModifiers copies a buff's stat modifiers, nil when empty
+func copyModifiers(modifiers map[string]float64) map[string]float64 {
+	if len(modifiers) == 0 {
+		return nil
+	}
+	copied := make(map[string]float64, len(modifiers))
+	for stat, value := range modifiers {
+		copied[stat] = value
+	}
+	return copied
+}
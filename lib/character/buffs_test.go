@@ -0,0 +1,180 @@
+package character
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func newBuffTestState() *GameState {
+	return NewGameState(map[string]StatConfig{
+		"happiness": {Initial: 50, Max: 100, DegradationRate: 1, CriticalThreshold: 10},
+		"energy":    {Initial: 50, Max: 100, DegradationRate: 1, CriticalThreshold: 10},
+	}, nil)
+}
+
+func TestBuffScalesGains(t *testing.T) {
+	gs := newBuffTestState()
+	gs.AddBuff(BuffConfig{Name: "Coffee", Icon: "☕", Gain: map[string]float64{"happiness": 0.2}, Minutes: 60})
+
+	gs.ApplyInteractionEffects(map[string]float64{"happiness": 10, "energy": 10})
+	if got := gs.GetStat("happiness"); math.Abs(got-62) > 0.001 {
+		t.Errorf("Expected a 20%% bigger happiness gain, got %.2f", got)
+	}
+	if got := gs.GetStat("energy"); got != 60 {
+		t.Errorf("Expected energy unaffected, got %.2f", got)
+	}
+
+	gs.ApplyInteractionEffects(map[string]float64{"happiness": -10})
+	if got := gs.GetStat("happiness"); math.Abs(got-52) > 0.001 {
+		t.Errorf("Expected losses unaffected by a gain buff, got %.2f", got)
+	}
+}
+
+func TestBuffScalesDecay(t *testing.T) {
+	gs := newBuffTestState()
+	gs.AddBuff(BuffConfig{Name: "Cozy", Decay: map[string]float64{"energy": -0.5}, Minutes: 120})
+
+	gs.mu.Lock()
+	start := time.Now().Add(-10 * time.Minute)
+	gs.applyStatDegradation(start, start.Add(10*time.Minute))
+	gs.mu.Unlock()
+
+	if got := gs.GetStat("energy"); math.Abs(got-45) > 0.01 {
+		t.Errorf("Expected energy to decay half as fast, got %.2f", got)
+	}
+	if got := gs.GetStat("happiness"); math.Abs(got-40) > 0.01 {
+		t.Errorf("Expected happiness to decay normally, got %.2f", got)
+	}
+}
+
+func TestBuffStacking(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		stacking    string
+		wantStacks  int
+		wantExpires time.Duration
+	}{
+		{BuffStackRefresh, 1, 90 * time.Minute},
+		{BuffStackExtend, 1, 120 * time.Minute},
+		{BuffStackStack, 2, 90 * time.Minute},
+		{BuffStackIgnore, 1, 60 * time.Minute},
+	}
+	for _, tt := range tests {
+		gs := newBuffTestState()
+		config := BuffConfig{Name: "Sugar", Gain: map[string]float64{"energy": 0.1}, Minutes: 60, Stacking: tt.stacking}
+		gs.mu.Lock()
+		gs.addBuff(config, now)
+		gs.addBuff(config, now.Add(30*time.Minute))
+		gs.mu.Unlock()
+
+		buffs := gs.GetBuffs()
+		if len(buffs) != 1 {
+			t.Fatalf("%s: expected one running buff, got %d", tt.stacking, len(buffs))
+		}
+		if buffs[0].Stacks != tt.wantStacks || !buffs[0].ExpiresAt.Equal(now.Add(tt.wantExpires)) {
+			t.Errorf("%s: got %d stacks expiring in %v", tt.stacking, buffs[0].Stacks, buffs[0].ExpiresAt.Sub(now))
+		}
+	}
+
+	gs := newBuffTestState()
+	config := BuffConfig{Name: "Sugar", Gain: map[string]float64{"energy": 0.1}, Minutes: 60, Stacking: BuffStackStack, MaxStacks: 2}
+	for i := 0; i < 4; i++ {
+		gs.AddBuff(config)
+	}
+	if stacks := gs.GetBuffs()[0].Stacks; stacks != 2 {
+		t.Errorf("Expected stacks capped at 2, got %d", stacks)
+	}
+}
+
+func TestBuffsExpire(t *testing.T) {
+	gs := newBuffTestState()
+	gs.mu.Lock()
+	gs.addBuff(BuffConfig{Name: "Old", Gain: map[string]float64{"energy": 1}, Minutes: 1}, time.Now().Add(-2*time.Minute))
+	gs.mu.Unlock()
+
+	if len(gs.GetBuffs()) != 0 {
+		t.Error("Expected expired buffs to be hidden")
+	}
+	gs.ApplyInteractionEffects(map[string]float64{"energy": 10})
+	if got := gs.GetStat("energy"); got != 60 {
+		t.Errorf("Expected expired buffs to stop modifying gains, got %.2f", got)
+	}
+	gs.Update(time.Second)
+	if len(gs.Buffs) != 0 {
+		t.Error("Expected Update to drop expired buffs")
+	}
+}
+
+func TestBuffValidate(t *testing.T) {
+	valid := BuffConfig{Name: "Coffee", Gain: map[string]float64{"happiness": 0.2}, Minutes: 60}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid buff, got %v", err)
+	}
+
+	invalid := []BuffConfig{
+		{Gain: map[string]float64{"happiness": 0.2}, Minutes: 60},
+		{Name: "No time", Gain: map[string]float64{"happiness": 0.2}},
+		{Name: "Nothing", Minutes: 60},
+		{Name: "Odd", Gain: map[string]float64{"happiness": 0.2}, Minutes: 60, Stacking: "merge"},
+		{Name: "Huge", Gain: map[string]float64{"happiness": 5}, Minutes: 60},
+	}
+	for _, buff := range invalid {
+		if err := buff.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", buff)
+		}
+	}
+
+	if !(ActiveBuff{Decay: map[string]float64{"energy": 0.5}}).IsDebuff() {
+		t.Error("Expected faster decay to be a debuff")
+	}
+	if (ActiveBuff{Gain: map[string]float64{"energy": 0.5}}).IsDebuff() {
+		t.Error("Expected more gain to be a buff")
+	}
+}
+
+func TestGiftGrantsBuff(t *testing.T) {
+	card := createTestGameCharacterCard()
+	char := createTestCharacterInstance(card, true)
+	gm := NewGiftManager(card, char.gameState)
+	gm.AddGiftToTestCatalog(&GiftDefinition{
+		ID:   "coffee",
+		Name: "Coffee",
+		GiftEffects: GiftEffects{
+			Immediate: ImmediateEffects{Responses: []string{"Thanks!"}},
+			Buff:      &BuffConfig{Name: "Caffeinated", Icon: "☕", Gain: map[string]float64{"happiness": 0.2}, Minutes: 60},
+		},
+	})
+
+	if _, err := gm.GiveGift("coffee", ""); err != nil {
+		t.Fatalf("Failed to give gift: %v", err)
+	}
+	buffs := char.gameState.GetBuffs()
+	if len(buffs) != 1 || buffs[0].Name != "Caffeinated" {
+		t.Errorf("Expected the coffee buff, got %+v", buffs)
+	}
+}
+
+func TestBuffsSurviveSave(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	char.gameState.AddBuff(BuffConfig{Name: "Coffee", Gain: map[string]float64{"happiness": 0.2}, Minutes: 60})
+	char.gameState.mu.Lock()
+	char.gameState.addBuff(BuffConfig{Name: "Gone", Gain: map[string]float64{"happiness": 0.2}, Minutes: 1}, time.Now().Add(-time.Hour))
+	char.gameState.mu.Unlock()
+
+	restored := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	restored.RestoreSaveData(char.SaveData())
+
+	buffs := restored.gameState.GetBuffs()
+	if len(buffs) != 1 || buffs[0].Name != "Coffee" {
+		t.Errorf("Expected only the running buff restored, got %+v", buffs)
+	}
+}
+
+func TestCardRejectsBuffOnUnknownStat(t *testing.T) {
+	card := createTestGameCharacterCard()
+	buff := &BuffConfig{Name: "Shiny", Gain: map[string]float64{"sparkle": 0.5}, Minutes: 10}
+	if err := card.validateBuff(buff); err == nil {
+		t.Error("Expected a buff on an undefined stat to be rejected")
+	}
+}
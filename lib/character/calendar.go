@@ -0,0 +1,408 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Special calendar event dates. Anything else is "MM-DD" or a holiday name.
+const (
+	CalendarDateBirthday     = "birthday"     // Character birthday from calendar.birthday
+	CalendarDateUserBirthday = "userBirthday" // Set by the user from the menu
+	CalendarDateAnniversary  = "anniversary"  // First meeting, from the save's creation time
+)
+
+// CalendarConfig defines date-aware events: birthdays, anniversaries and holidays
+type CalendarConfig struct {
+	Birthday string          `json:"birthday,omitempty"` // Character birthday as "MM-DD"
+	Events   []CalendarEvent `json:"events"`
+}
+
+// CalendarEvent fires once on its date each year, the first time the
+// character is awake that day
+type CalendarEvent struct {
+	Name      string             `json:"name"`
+	Date      string             `json:"date"`                // "MM-DD", "birthday", "userBirthday", "anniversary" or a holiday name
+	Responses []string           `json:"responses,omitempty"` // "{years}" is replaced with years since first meeting
+	Animation string             `json:"animation,omitempty"`
+	Effects   map[string]float64 `json:"effects,omitempty"` // Stat changes, may include coins
+	Gift      string             `json:"gift,omitempty"`    // Inventory item given once per year
+}
+
+// CalendarResult describes a calendar event that just fired, for UI notifications
+type CalendarResult struct {
+	Name     string
+	Response string
+	Gift     string
+}
+
+// fixedHolidays are public holidays on the same date every year
+var fixedHolidays = map[string][2]int{
+	"new_year":      {1, 1},
+	"valentines":    {2, 14},
+	"st_patricks":   {3, 17},
+	"halloween":     {10, 31},
+	"christmas_eve": {12, 24},
+	"christmas":     {12, 25},
+	"new_years_eve": {12, 31},
+}
+
+// HolidayDate returns the month and day of a built-in holiday in the given year
+func HolidayDate(name string, year int) (time.Month, int, bool) {
+	if date, ok := fixedHolidays[name]; ok {
+		return time.Month(date[0]), date[1], true
+	}
+
+	switch name {
+	case "easter":
+		month, day := easterDate(year)
+		return month, day, true
+	case "thanksgiving":
+		// US Thanksgiving: fourth Thursday of November
+		first := time.Date(year, time.November, 1, 0, 0, 0, 0, time.UTC)
+		offset := (int(time.Thursday) - int(first.Weekday()) + 7) % 7
+		return time.November, 1 + offset + 21, true
+	}
+
+	return 0, 0, false
+}
+
+// easterDate computes Western Easter Sunday (anonymous Gregorian algorithm)
+func easterDate(year int) (time.Month, int) {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Month(month), day
+}
+
+// parseMonthDay parses "MM-DD"
+func parseMonthDay(value string) (time.Month, int, error) {
+	t, err := time.Parse("01-02", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid date '%s', expected MM-DD", value)
+	}
+	return t.Month(), t.Day(), nil
+}
+
+// validateCalendar validates the optional calendar section
+func (c *CharacterCard) validateCalendar() error {
+	if c.Calendar == nil {
+		return nil
+	}
+
+	if c.Calendar.Birthday != "" {
+		if _, _, err := parseMonthDay(c.Calendar.Birthday); err != nil {
+			return fmt.Errorf("birthday: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, event := range c.Calendar.Events {
+		if event.Name == "" {
+			return fmt.Errorf("event %d: name is required", i)
+		}
+		if seen[event.Name] {
+			return fmt.Errorf("duplicate event name '%s'", event.Name)
+		}
+		seen[event.Name] = true
+
+		if err := c.validateCalendarEvent(event); err != nil {
+			return fmt.Errorf("event '%s': %w", event.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateCalendarEvent checks the date keyword and references of one event
+func (c *CharacterCard) validateCalendarEvent(event CalendarEvent) error {
+	switch event.Date {
+	case CalendarDateBirthday:
+		if c.Calendar.Birthday == "" {
+			return fmt.Errorf("date 'birthday' requires calendar.birthday")
+		}
+	case CalendarDateUserBirthday, CalendarDateAnniversary:
+	default:
+		if _, _, ok := HolidayDate(event.Date, 2000); !ok {
+			if _, _, err := parseMonthDay(event.Date); err != nil {
+				return fmt.Errorf("%w or a known holiday", err)
+			}
+		}
+	}
+
+	if event.Animation != "" {
+		if _, exists := c.Animations[event.Animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", event.Animation)
+		}
+	}
+
+	if len(event.Effects) > 0 && !c.HasGameFeatures() {
+		return fmt.Errorf("effects require stats to be defined")
+	}
+	return c.validateJobStats(event.Effects)
+}
+
+// updateCalendar fires calendar events due today. Checked at most once a
+// minute and only while awake. Must be called with c.mu held.
+// Returns true if the animation state changed.
+func (c *Character) updateCalendar(now time.Time) bool {
+	if c.card.Calendar == nil || c.sleeping || now.Sub(c.lastCalendarCheck) < time.Minute {
+		return false
+	}
+	c.lastCalendarCheck = now
+
+	changed := false
+	for _, event := range c.card.Calendar.Events {
+		if !c.calendarEventDue(event, now) {
+			continue
+		}
+		c.markCalendarFired(event.Name, now.Year())
+		c.calendarResults = append(c.calendarResults, c.fireCalendarEvent(event, now))
+
+		if event.Animation != "" {
+			previousState := c.currentState
+			c.setState(event.Animation)
+			changed = changed || c.currentState != previousState
+		}
+	}
+	return changed
+}
+
+// calendarEventDue reports whether an event falls on today and has not fired
+// this year. Must be called with c.mu held.
+func (c *Character) calendarEventDue(event CalendarEvent, now time.Time) bool {
+	if c.calendarLastFired(event.Name) >= now.Year() {
+		return false
+	}
+
+	month, day, ok := c.resolveCalendarDate(event.Date, now.Year())
+	if !ok {
+		return false
+	}
+
+	// Feb 29 dates are celebrated on Feb 28 in common years
+	if month == time.February && day == 29 && !isLeapYear(now.Year()) {
+		day = 28
+	}
+	return now.Month() == month && now.Day() == day
+}
+
+// resolveCalendarDate turns an event date into this year's month and day.
+// Must be called with c.mu held.
+func (c *Character) resolveCalendarDate(date string, year int) (time.Month, int, bool) {
+	switch date {
+	case CalendarDateBirthday:
+		month, day, err := parseMonthDay(c.card.Calendar.Birthday)
+		return month, day, err == nil
+	case CalendarDateUserBirthday:
+		birthday := c.userBirthday()
+		if birthday == "" {
+			return 0, 0, false
+		}
+		month, day, err := parseMonthDay(birthday)
+		return month, day, err == nil
+	case CalendarDateAnniversary:
+		first, ok := c.firstMeeting()
+		if !ok || year <= first.Year() {
+			return 0, 0, false
+		}
+		return first.Month(), first.Day(), true
+	}
+
+	if month, day, ok := HolidayDate(date, year); ok {
+		return month, day, true
+	}
+	month, day, err := parseMonthDay(date)
+	return month, day, err == nil
+}
+
+// fireCalendarEvent applies effects and gifts and builds the result.
+// Must be called with c.mu held.
+func (c *Character) fireCalendarEvent(event CalendarEvent, now time.Time) CalendarResult {
+	result := CalendarResult{Name: event.Name}
+
+	if len(event.Responses) > 0 {
+		result.Response = event.Responses[rand.Intn(len(event.Responses))]
+	} else {
+		result.Response = fmt.Sprintf("Happy %s!", strings.ReplaceAll(event.Name, "_", " "))
+	}
+	if first, ok := c.firstMeeting(); ok {
+		result.Response = strings.ReplaceAll(result.Response, "{years}", fmt.Sprintf("%d", now.Year()-first.Year()))
+	}
+
+	if c.gameState != nil {
+		c.gameState.ApplyInteractionEffects(event.Effects)
+		if event.Gift != "" {
+			c.gameState.AddItem(event.Gift, 1)
+			result.Gift = event.Gift
+		}
+	}
+
+	return result
+}
+
+// firstMeeting returns when the save was created. Must be called with c.mu held.
+func (c *Character) firstMeeting() (time.Time, bool) {
+	if c.gameState == nil {
+		return time.Time{}, false
+	}
+	created := c.gameState.GetCreationTime()
+	return created, !created.IsZero()
+}
+
+// GetCalendarEvents returns events fired since the last call and clears the list
+func (c *Character) GetCalendarEvents() []CalendarResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := c.calendarResults
+	c.calendarResults = nil
+	return results
+}
+
+// HasUserBirthdayEvent reports whether the card celebrates the user's birthday
+func (c *Character) HasUserBirthdayEvent() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.card.Calendar == nil {
+		return false
+	}
+	for _, event := range c.card.Calendar.Events {
+		if event.Date == CalendarDateUserBirthday {
+			return true
+		}
+	}
+	return false
+}
+
+// SetUserBirthday stores the user's birthday as "MM-DD"; empty clears it.
+// It is saved with the game state when game mode is on.
+func (c *Character) SetUserBirthday(date string) error {
+	if date != "" {
+		if _, _, err := parseMonthDay(date); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gameState != nil {
+		c.gameState.SetUserBirthday(date)
+	} else {
+		c.localUserBirthday = date
+	}
+	// Re-check right away in case today is the day
+	c.lastCalendarCheck = time.Time{}
+	return nil
+}
+
+// GetUserBirthday returns the stored user birthday, or ""
+func (c *Character) GetUserBirthday() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.userBirthday()
+}
+
+// userBirthday reads the birthday from game state or memory. Must be called with c.mu held.
+func (c *Character) userBirthday() string {
+	if c.gameState != nil {
+		return c.gameState.GetUserBirthday()
+	}
+	return c.localUserBirthday
+}
+
+// calendarLastFired returns the year an event last fired. Must be called with c.mu held.
+func (c *Character) calendarLastFired(name string) int {
+	if c.gameState != nil {
+		return c.gameState.GetCalendarFired(name)
+	}
+	return c.localCalendarFired[name]
+}
+
+// markCalendarFired records that an event fired this year. Must be called with c.mu held.
+func (c *Character) markCalendarFired(name string, year int) {
+	if c.gameState != nil {
+		c.gameState.SetCalendarFired(name, year)
+		return
+	}
+	if c.localCalendarFired == nil {
+		c.localCalendarFired = make(map[string]int)
+	}
+	c.localCalendarFired[name] = year
+}
+
+// isLeapYear reports whether year has a Feb 29
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// GetCreationTime returns when the game state was first created
+func (gs *GameState) GetCreationTime() time.Time {
+	if gs == nil {
+		return time.Time{}
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.CreationTime
+}
+
+// GetUserBirthday returns the user's birthday as "MM-DD", or ""
+func (gs *GameState) GetUserBirthday() string {
+	if gs == nil {
+		return ""
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.UserBirthday
+}
+
+// SetUserBirthday stores the user's birthday as "MM-DD"
+func (gs *GameState) SetUserBirthday(date string) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.UserBirthday = date
+}
+
+// GetCalendarFired returns the year a calendar event last fired, 0 if never
+func (gs *GameState) GetCalendarFired(name string) int {
+	if gs == nil {
+		return 0
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.CalendarFired[name]
+}
+
+// SetCalendarFired records the year a calendar event fired
+func (gs *GameState) SetCalendarFired(name string, year int) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.CalendarFired == nil {
+		gs.CalendarFired = make(map[string]int)
+	}
+	gs.CalendarFired[name] = year
+}
@@ -0,0 +1,164 @@
+package character
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestCalendarCard() *CharacterCard {
+	card := createTestGameCharacterCard()
+	card.Calendar = &CalendarConfig{
+		Birthday: "03-14",
+		Events: []CalendarEvent{
+			{Name: "my_birthday", Date: "birthday", Animation: "happy", Responses: []string{"It's my birthday!"}, Effects: map[string]float64{"coins": 10}, Gift: "cake"},
+			{Name: "your_birthday", Date: "userBirthday", Responses: []string{"Happy birthday to you!"}},
+			{Name: "anniversary", Date: "anniversary", Responses: []string{"{years} year(s) together!"}},
+			{Name: "halloween", Date: "halloween"},
+		},
+	}
+	return card
+}
+
+func TestHolidayDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		year  int
+		month time.Month
+		day   int
+	}{
+		{"christmas", 2025, time.December, 25},
+		{"easter", 2024, time.March, 31},
+		{"easter", 2025, time.April, 20},
+		{"thanksgiving", 2024, time.November, 28},
+		{"thanksgiving", 2025, time.November, 27},
+	}
+
+	for _, tt := range tests {
+		month, day, ok := HolidayDate(tt.name, tt.year)
+		if !ok || month != tt.month || day != tt.day {
+			t.Errorf("HolidayDate(%s, %d) = %v %d %v, want %v %d", tt.name, tt.year, month, day, ok, tt.month, tt.day)
+		}
+	}
+
+	if _, _, ok := HolidayDate("festivus", 2025); ok {
+		t.Error("Unknown holiday should not resolve")
+	}
+}
+
+func TestCalendarBirthdayFiresOncePerYear(t *testing.T) {
+	char := createTestCharacterInstance(createTestCalendarCard(), true)
+	birthday := time.Date(2025, time.March, 14, 10, 0, 0, 0, time.Local)
+
+	char.mu.Lock()
+	char.updateCalendar(birthday.Add(-24 * time.Hour))
+	char.mu.Unlock()
+	if results := char.GetCalendarEvents(); len(results) != 0 {
+		t.Fatalf("Nothing should fire the day before, got %+v", results)
+	}
+
+	char.mu.Lock()
+	char.updateCalendar(birthday)
+	char.mu.Unlock()
+
+	results := char.GetCalendarEvents()
+	if len(results) != 1 || results[0].Name != "my_birthday" || results[0].Response != "It's my birthday!" || results[0].Gift != "cake" {
+		t.Fatalf("Expected birthday event, got %+v", results)
+	}
+	gs := char.GetGameState()
+	if gs.GetCoins() != 10 || gs.GetInventory()["cake"] != 1 {
+		t.Errorf("Expected 10 coins and a cake, got %d coins, inventory %v", gs.GetCoins(), gs.GetInventory())
+	}
+
+	char.mu.Lock()
+	char.updateCalendar(birthday.Add(2 * time.Hour))
+	char.mu.Unlock()
+	if results := char.GetCalendarEvents(); len(results) != 0 {
+		t.Errorf("Birthday should only fire once per year, got %+v", results)
+	}
+
+	char.mu.Lock()
+	char.updateCalendar(birthday.AddDate(1, 0, 0))
+	char.mu.Unlock()
+	if results := char.GetCalendarEvents(); len(results) != 1 {
+		t.Errorf("Birthday should fire again next year, got %+v", results)
+	}
+}
+
+func TestCalendarUserBirthdayAndAnniversary(t *testing.T) {
+	char := createTestCharacterInstance(createTestCalendarCard(), true)
+	char.gameState.CreationTime = time.Date(2023, time.June, 1, 12, 0, 0, 0, time.Local)
+
+	if err := char.SetUserBirthday("13-01"); err == nil {
+		t.Error("Expected invalid birthday to be rejected")
+	}
+	if err := char.SetUserBirthday("06-01"); err != nil {
+		t.Fatalf("SetUserBirthday failed: %v", err)
+	}
+	if char.GetUserBirthday() != "06-01" || char.GetGameState().GetUserBirthday() != "06-01" {
+		t.Errorf("User birthday should be stored in game state")
+	}
+
+	char.mu.Lock()
+	char.updateCalendar(time.Date(2025, time.June, 1, 9, 0, 0, 0, time.Local))
+	char.mu.Unlock()
+
+	results := char.GetCalendarEvents()
+	if len(results) != 2 {
+		t.Fatalf("Expected user birthday and anniversary, got %+v", results)
+	}
+	if results[1].Response != "2 year(s) together!" {
+		t.Errorf("Expected years placeholder to be filled, got %q", results[1].Response)
+	}
+}
+
+func TestCalendarWaitsWhileSleeping(t *testing.T) {
+	char := createTestCharacterInstance(createTestCalendarCard(), true)
+	halloween := time.Date(2025, time.October, 31, 23, 0, 0, 0, time.Local)
+
+	char.mu.Lock()
+	char.sleeping = true
+	char.updateCalendar(halloween)
+	char.sleeping = false
+	char.updateCalendar(halloween.Add(30 * time.Minute))
+	char.mu.Unlock()
+
+	results := char.GetCalendarEvents()
+	if len(results) != 1 || results[0].Response != "Happy halloween!" {
+		t.Errorf("Expected default halloween greeting after waking, got %+v", results)
+	}
+}
+
+func TestValidateCalendar(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CalendarConfig
+		wantErr string
+	}{
+		{"valid", CalendarConfig{Events: []CalendarEvent{{Name: "spring", Date: "03-20"}, {Name: "xmas", Date: "christmas"}}}, ""},
+		{"bad date", CalendarConfig{Events: []CalendarEvent{{Name: "x", Date: "someday"}}}, "invalid date"},
+		{"missing birthday", CalendarConfig{Events: []CalendarEvent{{Name: "x", Date: "birthday"}}}, "requires calendar.birthday"},
+		{"bad birthday", CalendarConfig{Birthday: "3/14"}, "birthday"},
+		{"duplicate", CalendarConfig{Events: []CalendarEvent{{Name: "x", Date: "easter"}, {Name: "x", Date: "easter"}}}, "duplicate"},
+		{"unknown animation", CalendarConfig{Events: []CalendarEvent{{Name: "x", Date: "easter", Animation: "dance"}}}, "animation"},
+		{"unknown stat", CalendarConfig{Events: []CalendarEvent{{Name: "x", Date: "easter", Effects: map[string]float64{"mana": 1}}}}, "mana"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			config := tt.config
+			card.Calendar = &config
+			err := card.validateCalendar()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
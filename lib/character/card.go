@@ -10,6 +10,7 @@ import (
 
 	"github.com/opd-ai/desktop-companion/lib/bot"
 	"github.com/opd-ai/desktop-companion/lib/dialog"
+	"github.com/opd-ai/desktop-companion/lib/network"
 	"github.com/opd-ai/desktop-companion/lib/news"
 )
 
@@ -39,6 +40,9 @@ const (
 // This follows the "lazy programmer" approach - leveraging Go's built-in JSON package
 // instead of writing custom parsers
 type CharacterCard struct {
+	// Extends names a base card this one inherits from; it is already merged
+	// in by the time the card is loaded
+	Extends     string            `json:"extends,omitempty"`
 	Name        string            `json:"name"`
 	Description string            `json:"description"`
 	Animations  map[string]string `json:"animations"`
@@ -56,6 +60,8 @@ type CharacterCard struct {
 	Personality    *PersonalityConfig  `json:"personality,omitempty"`
 	RomanceDialogs []DialogExtended    `json:"romanceDialogs,omitempty"`
 	RomanceEvents  []RandomEventConfig `json:"romanceEvents,omitempty"`
+	// Jealousy sensitivity, decay and crisis thresholds, over the personality defaults
+	RelationshipTuning *RelationshipTuning `json:"relationshipTuning,omitempty"`
 	// Advanced dialog system (Phase 1)
 	DialogBackend *dialog.DialogBackendConfig `json:"dialogBackend,omitempty"`
 	// General dialog events system (Phase 4)
@@ -72,6 +78,45 @@ type CharacterCard struct {
 	PlatformConfig *PlatformConfig `json:"platformConfig,omitempty"`
 	// Asset generation system (GIF pipeline integration)
 	AssetGeneration *AssetGenerationConfig `json:"assetGeneration,omitempty"`
+	// Achievements unlocked by condition expressions (merged with progression achievements)
+	Achievements []AchievementConfig `json:"achievements,omitempty"`
+	// Sleep schedule (quiet hours with dimmed sprite and rare events)
+	SleepSchedule *SleepScheduleConfig `json:"sleepSchedule,omitempty"`
+	// Jobs the user can assign from the menu (timed tasks with rewards)
+	Jobs map[string]JobConfig `json:"jobs,omitempty"`
+	// Shop where coins are spent on gifts, skins and event unlocks
+	Shop *ShopConfig `json:"shop,omitempty"`
+
+	// Birthdays, anniversaries and holidays
+	Calendar *CalendarConfig `json:"calendar,omitempty"`
+	// Events at fixed times: daily greetings, reminders, one-shot dates
+	Schedule []ScheduledEventConfig `json:"schedule,omitempty"`
+	// Pomodoro focus-buddy timer lengths, animations and rewards
+	Focus *FocusConfig `json:"focus,omitempty"`
+	// Napping while the user is away from the computer and welcoming them back
+	Away *AwayConfig `json:"away,omitempty"`
+	// Taking back the last interaction for a few seconds after making it
+	Undo *UndoConfig `json:"undo,omitempty"`
+	// Apologizing to win back part of what negative events cost
+	Apology *ApologyConfig `json:"apology,omitempty"`
+	// Fullscreen showcase of animations after a long time without input
+	Screensaver *ScreensaverConfig `json:"screensaver,omitempty"`
+	// Trivia questions and rewards for the built-in mini-games
+	MiniGames *MiniGamesConfig `json:"miniGames,omitempty"`
+	// Responses to copied text by kind ("url", "code", ...) when the user opts in
+	ClipboardReactions map[string][]string `json:"clipboardReactions,omitempty"`
+	// Hearts, sweat drops, sparkles and other effects drawn over the character (optional)
+	Particles []ParticleEffectConfig `json:"particles,omitempty"`
+	// Speech bubble colors, font size, corners and tail (optional)
+	BubbleStyle *BubbleStyle `json:"bubbleStyle,omitempty"`
+	// Cross-fades and clips played between animations (optional)
+	Transitions []AnimationTransition `json:"transitions,omitempty"`
+	// Lua behavior script with on_click, on_stat_change and on_event hooks,
+	// relative to the card (optional)
+	Script string `json:"script,omitempty"`
+
+	// Language used for missing translations in localized strings (default "en")
+	DefaultLanguage string `json:"defaultLanguage,omitempty"`
 }
 
 // Dialog represents an interaction trigger and response configuration
@@ -87,6 +132,8 @@ type Behavior struct {
 	IdleTimeout              int                 `json:"idleTimeout"`                        // Seconds before returning to idle
 	MovementEnabled          bool                `json:"movementEnabled"`                    // Allow dragging
 	DefaultSize              int                 `json:"defaultSize"`                        // Character size in pixels
+	MinSize                  int                 `json:"minSize,omitempty"`                  // Smallest size the user can resize to (default: 64)
+	MaxSize                  int                 `json:"maxSize,omitempty"`                  // Largest size the user can resize to (default: 512)
 	MoodAnimationPreferences map[string][]string `json:"moodAnimationPreferences,omitempty"` // Mood-based animation preferences
 }
 
@@ -109,20 +156,25 @@ type InteractionConfig struct {
 	Cooldown     int                           `json:"cooldown"`     // Seconds between uses
 	Duration     int                           `json:"duration"`     // Duration of effect (for sleep, etc.)
 	Requirements map[string]map[string]float64 `json:"requirements"` // Stat requirements to use interaction
+	// Expression-based extensions (see expression.go)
+	Condition         string            `json:"condition,omitempty"`         // Extra requirement, e.g. "affection > 50 && since.give_gift > 1h"
+	EffectExpressions map[string]string `json:"effectExpressions,omitempty"` // Computed stat changes, e.g. {"happiness": "(100 - happiness) * 0.2"}
 }
 
 // RandomEventConfig defines a random event that can affect character stats
 // Events are triggered based on probability and conditions, following "lazy programmer" approach
 type RandomEventConfig struct {
-	Name        string                        `json:"name"`        // Event name for identification
-	Description string                        `json:"description"` // Human-readable description
-	Probability float64                       `json:"probability"` // 0.0-1.0 chance of triggering per check
-	Effects     map[string]float64            `json:"effects"`     // Stat changes to apply when triggered
-	Animations  []string                      `json:"animations"`  // Animations to play when triggered
-	Responses   []string                      `json:"responses"`   // Dialog responses to show
-	Cooldown    int                           `json:"cooldown"`    // Minimum seconds between triggers
-	Duration    int                           `json:"duration"`    // Duration in seconds (0 = instant)
-	Conditions  map[string]map[string]float64 `json:"conditions"`  // Stat conditions required to trigger
+	Name        string                        `json:"name"`                // Event name for identification
+	Description string                        `json:"description"`         // Human-readable description
+	Probability float64                       `json:"probability"`         // 0.0-1.0 chance of triggering per check
+	Effects     map[string]float64            `json:"effects"`             // Stat changes to apply when triggered
+	Animations  []string                      `json:"animations"`          // Animations to play when triggered
+	Responses   []string                      `json:"responses"`           // Dialog responses to show
+	Cooldown    int                           `json:"cooldown"`            // Minimum seconds between triggers
+	Duration    int                           `json:"duration"`            // Duration in seconds (0 = instant)
+	Conditions  map[string]map[string]float64 `json:"conditions"`          // Stat conditions required to trigger
+	Condition   string                        `json:"condition,omitempty"` // Expression condition required to trigger
+	Buff        *BuffConfig                   `json:"buff,omitempty"`      // Temporary stat modifier granted when triggered
 }
 
 // Romance-specific configuration structures (Dating Simulator Phase 1)
@@ -138,6 +190,7 @@ type RomanceRequirement struct {
 	RelationshipLevel   string                        `json:"relationshipLevel,omitempty"`   // Required relationship level
 	InteractionCount    map[string]map[string]int     `json:"interactionCount,omitempty"`    // Interaction count requirements
 	AchievementUnlocked []string                      `json:"achievementUnlocked,omitempty"` // Required achievements
+	Condition           string                        `json:"condition,omitempty"`           // Expression condition, e.g. "affection > 50 && since.give_gift < 1h"
 }
 
 // DialogExtended extends the basic Dialog with romance-specific features
@@ -157,12 +210,13 @@ type InteractionConfigExtended struct {
 // MultiplayerConfig defines multiplayer networking configuration for character cards
 // Enables peer-to-peer networking features while maintaining backward compatibility
 type MultiplayerConfig struct {
-	Enabled        bool                      `json:"enabled"`                  // Enable multiplayer networking features
-	BotCapable     bool                      `json:"botCapable"`               // Can this character run autonomously as a bot
-	NetworkID      string                    `json:"networkID"`                // Unique identifier for this character type
-	MaxPeers       int                       `json:"maxPeers,omitempty"`       // Maximum number of peers to connect to (default: 8)
-	DiscoveryPort  int                       `json:"discoveryPort,omitempty"`  // UDP port for peer discovery (default: 8080)
-	BotPersonality *bot.PersonalityArchetype `json:"botPersonality,omitempty"` // Personality configuration for bot behavior
+	Enabled        bool                        `json:"enabled"`                  // Enable multiplayer networking features
+	BotCapable     bool                        `json:"botCapable"`               // Can this character run autonomously as a bot
+	NetworkID      string                      `json:"networkID"`                // Unique identifier for this character type
+	MaxPeers       int                         `json:"maxPeers,omitempty"`       // Maximum number of peers to connect to (default: 8)
+	DiscoveryPort  int                         `json:"discoveryPort,omitempty"`  // UDP port for peer discovery (default: 8080)
+	BotPersonality *bot.PersonalityArchetype   `json:"botPersonality,omitempty"` // Personality configuration for bot behavior
+	PeerChat       *network.ConversationConfig `json:"peerChat,omitempty"`       // Idle chats with other networked characters
 }
 
 // BattleSystemConfig configures JRPG-style battle features for a character
@@ -201,8 +255,12 @@ type PlatformSpecificConfig struct {
 	// Mobile-specific control configuration
 	MobileControls *MobileControlsConfig `json:"mobileControls,omitempty"`
 
+	// Animation files used on this platform instead of the card's, by state,
+	// e.g. "idle": "idle_mobile.gif". Written by gif-generator output profiles.
+	Animations map[string]string `json:"animations,omitempty"`
+
 	// Window and display configuration
-	WindowMode     string `json:"windowMode,omitempty"`     // "overlay", "fullscreen", "pip" (picture-in-picture)
+	WindowMode     string `json:"windowMode,omitempty"`     // "overlay", "fullscreen", "pip" (picture-in-picture), "widget" (mobile only)
 	DefaultSize    int    `json:"defaultSize,omitempty"`    // Platform-specific default size override
 	TouchOptimized bool   `json:"touchOptimized,omitempty"` // Enable touch-optimized UI elements
 }
@@ -260,6 +318,18 @@ func LoadCard(path string) (*CharacterCard, error) {
 		return nil, fmt.Errorf("failed to read character card %s: %w", resolvedPath, err)
 	}
 
+	// Merge in the base card named by "extends" before localizing
+	data, err = ResolveExtends(data, resolvedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve character card %s: %w", resolvedPath, err)
+	}
+
+	// Resolve per-locale strings and character.<lang>.json overrides
+	data, err = LocalizeCardJSON(data, resolvedPath, Language())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse character card %s: %w", resolvedPath, err)
+	}
+
 	var card CharacterCard
 	if err := json.Unmarshal(data, &card); err != nil {
 		return nil, fmt.Errorf("failed to parse character card %s: %w", resolvedPath, err)
@@ -336,6 +406,10 @@ func (c *CharacterCard) validateGameSystems() error {
 		return fmt.Errorf("progression: %w", err)
 	}
 
+	if err := c.validateAchievements(); err != nil {
+		return fmt.Errorf("achievements: %w", err)
+	}
+
 	if err := c.validateRandomEvents(); err != nil {
 		return fmt.Errorf("random events: %w", err)
 	}
@@ -349,6 +423,12 @@ func (c *CharacterCard) validateRomanceSystems() error {
 		return fmt.Errorf("romance features: %w", err)
 	}
 
+	if c.RelationshipTuning != nil {
+		if err := c.RelationshipTuning.Validate(); err != nil {
+			return fmt.Errorf("relationship tuning: %w", err)
+		}
+	}
+
 	if err := c.validateDialogBackend(); err != nil {
 		return fmt.Errorf("dialog backend: %w", err)
 	}
@@ -462,6 +542,70 @@ func (c *CharacterCard) validateFeatureSections() error {
 		return fmt.Errorf("asset generation: %w", err)
 	}
 
+	if err := c.validateSleepSchedule(); err != nil {
+		return fmt.Errorf("sleep schedule: %w", err)
+	}
+
+	if err := c.validateAway(); err != nil {
+		return fmt.Errorf("away: %w", err)
+	}
+
+	if err := c.validateAmends(); err != nil {
+		return fmt.Errorf("undo and apology: %w", err)
+	}
+
+	if err := c.validateScreensaver(); err != nil {
+		return fmt.Errorf("screensaver: %w", err)
+	}
+
+	if err := c.validateJobs(); err != nil {
+		return fmt.Errorf("jobs: %w", err)
+	}
+
+	if err := c.validateShop(); err != nil {
+		return fmt.Errorf("shop: %w", err)
+	}
+
+	if err := c.validateCalendar(); err != nil {
+		return fmt.Errorf("calendar: %w", err)
+	}
+
+	if err := c.validateSchedule(); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+
+	if err := c.validateFocus(); err != nil {
+		return fmt.Errorf("focus: %w", err)
+	}
+
+	if err := c.validateMiniGames(); err != nil {
+		return fmt.Errorf("mini-games: %w", err)
+	}
+
+	if err := c.validateClipboardReactions(); err != nil {
+		return fmt.Errorf("clipboard reactions: %w", err)
+	}
+
+	if err := c.validateParticles(); err != nil {
+		return fmt.Errorf("particles: %w", err)
+	}
+
+	if err := c.validateNewsDigest(); err != nil {
+		return fmt.Errorf("news digest: %w", err)
+	}
+
+	if err := c.validateBubbleStyle(); err != nil {
+		return fmt.Errorf("bubble style: %w", err)
+	}
+
+	if err := c.validateTransitions(); err != nil {
+		return fmt.Errorf("transitions: %w", err)
+	}
+
+	if err := c.validateScript(); err != nil {
+		return fmt.Errorf("script: %w", err)
+	}
+
 	return nil
 }
 
@@ -666,17 +810,33 @@ func (b *Behavior) Validate() error {
 		return fmt.Errorf("defaultSize must be 64-512 pixels, got %d", b.DefaultSize)
 	}
 
+	if b.MinSize != 0 && (b.MinSize < 64 || b.MinSize > b.DefaultSize) {
+		return fmt.Errorf("minSize must be 64 pixels up to defaultSize, got %d", b.MinSize)
+	}
+
+	if b.MaxSize != 0 && (b.MaxSize > 512 || b.MaxSize < b.DefaultSize) {
+		return fmt.Errorf("maxSize must be defaultSize up to 512 pixels, got %d", b.MaxSize)
+	}
+
 	return nil
 }
 
 // GetAnimationPath returns the full path to an animation file
-// Resolves relative paths from the character card directory
+// Resolves relative paths from the character card directory. A variant for
+// the current platform from platformConfig is preferred when its file exists.
 func (c *CharacterCard) GetAnimationPath(basePath, animationName string) (string, error) {
 	animationFile, exists := c.Animations[animationName]
 	if !exists {
 		return "", fmt.Errorf("animation '%s' not found", animationName)
 	}
 
+	if variant := c.platformAnimation(animationName); variant != "" {
+		variantPath := filepath.Join(basePath, variant)
+		if _, err := os.Stat(variantPath); err == nil {
+			return variantPath, nil
+		}
+	}
+
 	fullPath := filepath.Join(basePath, animationFile)
 
 	// Verify file exists
@@ -763,6 +923,10 @@ func (c *CharacterCard) validateStatConfig(name string, stat StatConfig) error {
 		return fmt.Errorf("critical threshold (%f) must be between 0 and max (%f)", stat.CriticalThreshold, stat.Max)
 	}
 
+	if err := stat.Decay.Validate(); err != nil {
+		return fmt.Errorf("decay: %w", err)
+	}
+
 	return nil
 }
 
@@ -797,7 +961,13 @@ func (c *CharacterCard) validateInteractionConfig(name string, interaction Inter
 		return err
 	}
 
-	return nil
+	if interaction.Condition != "" {
+		if err := validateConditionIdentifiers(interaction.Condition, c.Stats); err != nil {
+			return err
+		}
+	}
+
+	return validateEffectExpressions(interaction.EffectExpressions, c.Stats)
 }
 
 // validateInteractionTriggers validates that interaction triggers are valid and non-empty
@@ -923,7 +1093,7 @@ func (c *CharacterCard) validateProgressionLevel(level LevelConfig, index int) e
 		}
 	}
 
-	return nil
+	return c.validateLevelUpCeremony(level)
 }
 
 // validateProgressionAchievement validates a single achievement configuration
@@ -932,8 +1102,14 @@ func (c *CharacterCard) validateProgressionAchievement(achievement AchievementCo
 		return fmt.Errorf("name cannot be empty")
 	}
 
-	if len(achievement.Requirement) == 0 {
-		return fmt.Errorf("must have at least one requirement")
+	if len(achievement.Requirement) == 0 && achievement.Condition == "" {
+		return fmt.Errorf("must have at least one requirement or a condition")
+	}
+
+	if achievement.Condition != "" {
+		if err := validateConditionIdentifiers(achievement.Condition, c.Stats); err != nil {
+			return err
+		}
 	}
 
 	// Validate that required stats exist in character stats
@@ -1011,6 +1187,37 @@ func (c *CharacterCard) validateRandomEventConfig(event RandomEventConfig, index
 		return err
 	}
 
+	if event.Condition != "" {
+		if err := validateConditionIdentifiers(event.Condition, c.Stats); err != nil {
+			return err
+		}
+	}
+
+	if event.Buff != nil {
+		if err := c.validateBuff(event.Buff); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateBuff checks a buff and, when the card defines stats, that it only
+// modifies those
+func (c *CharacterCard) validateBuff(buff *BuffConfig) error {
+	if err := buff.Validate(); err != nil {
+		return err
+	}
+	if len(c.Stats) == 0 {
+		return nil
+	}
+	for _, modifiers := range []map[string]float64{buff.Gain, buff.Decay} {
+		for statName := range modifiers {
+			if _, exists := c.Stats[statName]; !exists {
+				return fmt.Errorf("buff %q references stat '%s' which is not defined", buff.Name, statName)
+			}
+		}
+	}
 	return nil
 }
 
@@ -1072,7 +1279,7 @@ func (c *CharacterCard) validateEventStatReferences(event RandomEventConfig) err
 	}
 
 	for statName := range event.Effects {
-		if _, exists := c.Stats[statName]; !exists {
+		if _, exists := c.Stats[statName]; !exists && statName != CurrencyKey {
 			return fmt.Errorf("event effects reference stat '%s' which is not defined", statName)
 		}
 	}
@@ -1227,6 +1434,12 @@ func (c *CharacterCard) validateRomanceRequirements(req *RomanceRequirement) err
 		}
 	}
 
+	if req.Condition != "" {
+		if err := validateConditionIdentifiers(req.Condition, c.Stats); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1423,6 +1636,10 @@ func (c *CharacterCard) validateMultiplayerConfig() error {
 		return err
 	}
 
+	if err := c.validatePeerChat(mp.PeerChat); err != nil {
+		return fmt.Errorf("peerChat: %w", err)
+	}
+
 	return nil
 }
 
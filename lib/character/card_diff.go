@@ -0,0 +1,397 @@
+package character
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of card changes
+const (
+	CardAdded   = "added"
+	CardRemoved = "removed"
+	CardChanged = "changed"
+)
+
+// CardChange is one difference between two versions of a card
+type CardChange struct {
+	Kind        string `json:"kind"`                  // CardAdded, CardRemoved or CardChanged
+	Path        string `json:"path"`                  // Where in the card, e.g. "stats.hunger"
+	Message     string `json:"message"`               // What changed, e.g. "max 100 → 80"
+	BreaksSaves bool   `json:"breaksSaves,omitempty"` // Existing saves lose or clamp progress
+	SaveImpact  string `json:"saveImpact,omitempty"`  // What happens to existing saves
+}
+
+// String formats the change as "+ path: message", "-" for removals and "~"
+// for changes
+func (c CardChange) String() string {
+	symbol := "~"
+	switch c.Kind {
+	case CardAdded:
+		symbol = "+"
+	case CardRemoved:
+		symbol = "-"
+	}
+	return fmt.Sprintf("%s %s: %s", symbol, c.Path, c.Message)
+}
+
+// DiffCards compares two versions of a card section by section: added and
+// removed dialogs, animations, stats, interactions and other named entries,
+// and which fields of the entries in both changed. Changes that leave
+// existing saves losing progress are marked BreaksSaves. Changes are sorted
+// by path.
+func DiffCards(before, after *CharacterCard) []CardChange {
+	d := &cardDiffer{}
+
+	if before.Name != after.Name {
+		d.add(CardChanged, "name", fmt.Sprintf("%q → %q", before.Name, after.Name))
+		d.breaks("saves are stored under the character name, so existing progress is not found")
+	}
+	if before.Description != after.Description {
+		d.add(CardChanged, "description", "text changed")
+	}
+
+	d.diffStrings("animations", before.Animations, after.Animations)
+	d.diffDialogs(before.Dialogs, after.Dialogs)
+	d.diffStats(before.Stats, after.Stats)
+	diffEntries(d, "interactions", before.Interactions, after.Interactions, "")
+	diffEntries(d, "jobs", before.Jobs, after.Jobs, "a job running in a save never completes")
+	diffEntries(d, "shop.items", shopItems(before), shopItems(after), "purchases, inventory and skins from this item are kept but no longer usable")
+	diffEntries(d, "achievements", achievementsByName(before), achievementsByName(after), "saves that earned it lose the achievement")
+	diffEntries(d, "progression.levels", levelsByName(before), levelsByName(after), "")
+	diffEntries(d, "randomEvents", eventsByName(before.RandomEvents), eventsByName(after.RandomEvents), "")
+	diffEntries(d, "romanceEvents", eventsByName(before.RomanceEvents), eventsByName(after.RomanceEvents), "")
+	diffEntries(d, "generalEvents", generalEventsByName(before.GeneralEvents), generalEventsByName(after.GeneralEvents), "")
+	d.diffSections(before, after)
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Path < d.changes[j].Path
+	})
+	return d.changes
+}
+
+// diffedSections are the card fields DiffCards compares entry by entry;
+// the rest are compared as whole sections
+var diffedSections = map[string]bool{
+	"name": true, "description": true, "animations": true, "dialogs": true,
+	"stats": true, "interactions": true, "jobs": true, "shop": true,
+	"achievements": true, "progression": true, "randomEvents": true,
+	"romanceEvents": true, "generalEvents": true,
+}
+
+// cardDiffer collects changes for one comparison
+type cardDiffer struct {
+	changes []CardChange
+}
+
+// add records a change
+func (d *cardDiffer) add(kind, path, message string) {
+	d.changes = append(d.changes, CardChange{Kind: kind, Path: path, Message: message})
+}
+
+// breaks marks the last change as breaking existing saves
+func (d *cardDiffer) breaks(impact string) {
+	last := &d.changes[len(d.changes)-1]
+	last.BreaksSaves = true
+	last.SaveImpact = impact
+}
+
+// diffStrings compares a name -> value map such as the animations
+func (d *cardDiffer) diffStrings(section string, before, after map[string]string) {
+	for _, name := range unionKeys(before, after) {
+		path := section + "." + name
+		old, inBefore := before[name]
+		updated, inAfter := after[name]
+		switch {
+		case !inBefore:
+			d.add(CardAdded, path, updated)
+		case !inAfter:
+			d.add(CardRemoved, path, old)
+		case old != updated:
+			d.add(CardChanged, path, fmt.Sprintf("%s → %s", old, updated))
+		}
+	}
+}
+
+// diffDialogs compares dialogs by trigger, counting added and removed
+// responses for triggers in both versions
+func (d *cardDiffer) diffDialogs(before, after []Dialog) {
+	group := func(dialogs []Dialog) map[string][]Dialog {
+		byTrigger := make(map[string][]Dialog)
+		for _, dialog := range dialogs {
+			byTrigger[dialog.Trigger] = append(byTrigger[dialog.Trigger], dialog)
+		}
+		return byTrigger
+	}
+	oldDialogs, newDialogs := group(before), group(after)
+
+	for _, trigger := range unionKeys(oldDialogs, newDialogs) {
+		path := "dialogs." + trigger
+		old, updated := oldDialogs[trigger], newDialogs[trigger]
+		switch {
+		case len(old) == 0:
+			d.add(CardAdded, path, fmt.Sprintf("%d responses", countResponses(updated)))
+			continue
+		case len(updated) == 0:
+			d.add(CardRemoved, path, fmt.Sprintf("%d responses", countResponses(old)))
+			continue
+		}
+
+		var details []string
+		added, removed := diffResponses(old, updated)
+		if added > 0 {
+			details = append(details, fmt.Sprintf("%d responses added", added))
+		}
+		if removed > 0 {
+			details = append(details, fmt.Sprintf("%d responses removed", removed))
+		}
+		if len(old) != len(updated) {
+			details = append(details, fmt.Sprintf("%d → %d dialogs", len(old), len(updated)))
+		}
+		if oldAnims, newAnims := dialogAnimations(old), dialogAnimations(updated); oldAnims != newAnims {
+			details = append(details, fmt.Sprintf("animation %s → %s", oldAnims, newAnims))
+		}
+		if len(details) > 0 {
+			d.add(CardChanged, path, strings.Join(details, ", "))
+		}
+	}
+}
+
+// diffStats compares stat definitions, flagging the ones saves depend on
+func (d *cardDiffer) diffStats(before, after map[string]StatConfig) {
+	if len(before) > 0 && len(after) == 0 {
+		d.add(CardRemoved, "stats", "game features removed")
+		d.breaks("saved stats, coins and progress are no longer loaded")
+		return
+	}
+	for _, name := range unionKeys(before, after) {
+		path := "stats." + name
+		old, inBefore := before[name]
+		updated, inAfter := after[name]
+		switch {
+		case !inBefore:
+			d.add(CardAdded, path, fmt.Sprintf("initial %g, max %g", updated.Initial, updated.Max))
+		case !inAfter:
+			d.add(CardRemoved, path, fmt.Sprintf("max %g", old.Max))
+			d.breaks("the saved value is dropped")
+		default:
+			if fields := changedFields(old, updated); len(fields) > 0 {
+				d.add(CardChanged, path, describeStatChange(old, updated, fields))
+				if updated.Max < old.Max {
+					d.breaks(fmt.Sprintf("saved values above %g are clamped", updated.Max))
+				}
+			}
+		}
+	}
+}
+
+// describeStatChange spells out the numeric fields that changed
+func describeStatChange(old, updated StatConfig, fields []string) string {
+	values := map[string][2]float64{
+		"initial":           {old.Initial, updated.Initial},
+		"max":               {old.Max, updated.Max},
+		"degradationRate":   {old.DegradationRate, updated.DegradationRate},
+		"criticalThreshold": {old.CriticalThreshold, updated.CriticalThreshold},
+	}
+	details := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if v, ok := values[field]; ok {
+			details = append(details, fmt.Sprintf("%s %g → %g", field, v[0], v[1]))
+		} else {
+			details = append(details, field+" changed")
+		}
+	}
+	return strings.Join(details, ", ")
+}
+
+// diffEntries compares a section of named entries, listing the fields that
+// changed in entries both versions have. removedImpact, when set, marks
+// removals as breaking saves.
+func diffEntries[T any](d *cardDiffer, section string, before, after map[string]T, removedImpact string) {
+	for _, name := range unionKeys(before, after) {
+		path := section + "." + name
+		old, inBefore := before[name]
+		updated, inAfter := after[name]
+		switch {
+		case !inBefore:
+			d.add(CardAdded, path, "new")
+		case !inAfter:
+			d.add(CardRemoved, path, "removed")
+			if removedImpact != "" {
+				d.breaks(removedImpact)
+			}
+		default:
+			if fields := changedFields(old, updated); len(fields) > 0 {
+				d.add(CardChanged, path, strings.Join(fields, ", ")+" changed")
+			}
+		}
+	}
+}
+
+// diffSections compares the remaining card fields as whole sections
+func (d *cardDiffer) diffSections(before, after *CharacterCard) {
+	oldFields, newFields := jsonFields(before), jsonFields(after)
+	for _, field := range unionKeys(oldFields, newFields) {
+		if diffedSections[field] {
+			continue
+		}
+		old, inBefore := oldFields[field]
+		updated, inAfter := newFields[field]
+		switch {
+		case !inBefore:
+			d.add(CardAdded, field, "section added")
+		case !inAfter:
+			d.add(CardRemoved, field, "section removed")
+		case !bytes.Equal(old, updated):
+			if fields := changedFields(old, updated); len(fields) > 0 {
+				d.add(CardChanged, field, strings.Join(fields, ", ")+" changed")
+			} else {
+				d.add(CardChanged, field, "section changed")
+			}
+		}
+	}
+}
+
+// changedFields returns the JSON fields that differ between two values,
+// sorted; values that aren't JSON objects yield nothing
+func changedFields(before, after interface{}) []string {
+	oldFields, newFields := jsonFields(before), jsonFields(after)
+	var fields []string
+	for _, field := range unionKeys(oldFields, newFields) {
+		if !bytes.Equal(oldFields[field], newFields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// jsonFields returns a value's JSON object fields, leaving out empty ones
+func jsonFields(v interface{}) map[string]json.RawMessage {
+	raw, ok := v.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return nil
+		}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	for field, value := range fields {
+		switch string(value) {
+		case "null", "{}", "[]", `""`:
+			delete(fields, field)
+		}
+	}
+	return fields
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countResponses totals the responses of a trigger's dialogs
+func countResponses(dialogs []Dialog) int {
+	total := 0
+	for _, dialog := range dialogs {
+		total += len(dialog.Responses)
+	}
+	return total
+}
+
+// diffResponses counts responses only in after and only in before
+func diffResponses(before, after []Dialog) (added, removed int) {
+	counts := make(map[string]int)
+	for _, dialog := range before {
+		for _, response := range dialog.Responses {
+			counts[response]++
+		}
+	}
+	for _, dialog := range after {
+		for _, response := range dialog.Responses {
+			counts[response]--
+		}
+	}
+	for _, count := range counts {
+		if count < 0 {
+			added -= count
+		} else {
+			removed += count
+		}
+	}
+	return added, removed
+}
+
+// dialogAnimations lists a trigger's dialog animations for comparison
+func dialogAnimations(dialogs []Dialog) string {
+	names := make([]string, 0, len(dialogs))
+	for _, dialog := range dialogs {
+		names = append(names, dialog.Animation)
+	}
+	return strings.Join(names, "/")
+}
+
+// shopItems returns the card's shop items, nil without a shop
+func shopItems(c *CharacterCard) map[string]ShopItem {
+	if c.Shop == nil {
+		return nil
+	}
+	return c.Shop.Items
+}
+
+// achievementsByName collects the progression and top-level achievements
+func achievementsByName(c *CharacterCard) map[string]AchievementConfig {
+	byName := make(map[string]AchievementConfig)
+	if c.Progression != nil {
+		for _, achievement := range c.Progression.Achievements {
+			byName[achievement.Name] = achievement
+		}
+	}
+	for _, achievement := range c.Achievements {
+		byName[achievement.Name] = achievement
+	}
+	return byName
+}
+
+// levelsByName collects the progression levels
+func levelsByName(c *CharacterCard) map[string]LevelConfig {
+	byName := make(map[string]LevelConfig)
+	if c.Progression != nil {
+		for _, level := range c.Progression.Levels {
+			byName[level.Name] = level
+		}
+	}
+	return byName
+}
+
+// eventsByName indexes random or romance events by name
+func eventsByName(events []RandomEventConfig) map[string]RandomEventConfig {
+	byName := make(map[string]RandomEventConfig, len(events))
+	for _, event := range events {
+		byName[event.Name] = event
+	}
+	return byName
+}
+
+// generalEventsByName indexes general dialog events by name
+func generalEventsByName(events []GeneralDialogEvent) map[string]GeneralDialogEvent {
+	byName := make(map[string]GeneralDialogEvent, len(events))
+	for _, event := range events {
+		byName[event.Name] = event
+	}
+	return byName
+}
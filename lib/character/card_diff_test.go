@@ -0,0 +1,84 @@
+package character
+
+import (
+	"testing"
+)
+
+// findChange returns the change at path, if any
+func findChange(changes []CardChange, path string) (CardChange, bool) {
+	for _, change := range changes {
+		if change.Path == path {
+			return change, true
+		}
+	}
+	return CardChange{}, false
+}
+
+func TestDiffCardsIdentical(t *testing.T) {
+	card := createTestGameCharacterCard()
+	if changes := DiffCards(card, createTestGameCharacterCard()); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestDiffCardsSections(t *testing.T) {
+	before := createTestGameCharacterCard()
+	after := createTestGameCharacterCard()
+
+	after.Animations["wave"] = "wave.gif"
+	delete(after.Animations, "sad")
+	after.Dialogs = append(after.Dialogs, Dialog{Trigger: "hover", Responses: []string{"Hi!"}, Animation: "idle"})
+	after.Stats["hunger"] = StatConfig{Initial: 50, Max: 100, DegradationRate: 2, CriticalThreshold: 20}
+	interaction := after.Interactions["play"]
+	interaction.Cooldown += 10
+	after.Interactions["play"] = interaction
+	after.BubbleStyle = &BubbleStyle{FontSize: 16}
+
+	changes := DiffCards(before, after)
+	tests := []struct {
+		path string
+		kind string
+	}{
+		{"animations.wave", CardAdded},
+		{"animations.sad", CardRemoved},
+		{"dialogs.hover", CardAdded},
+		{"stats.hunger", CardChanged},
+		{"interactions.play", CardChanged},
+		{"bubbleStyle", CardAdded},
+	}
+	for _, tt := range tests {
+		change, ok := findChange(changes, tt.path)
+		if !ok || change.Kind != tt.kind {
+			t.Errorf("Expected %s %s, got %+v (found %v)", tt.kind, tt.path, change, ok)
+		}
+		if change.BreaksSaves {
+			t.Errorf("Expected %s not to break saves", tt.path)
+		}
+	}
+	if change, _ := findChange(changes, "interactions.play"); change.Message != "cooldown changed" {
+		t.Errorf("Expected the changed field to be named, got %q", change.Message)
+	}
+}
+
+func TestDiffCardsSaveBreakingChanges(t *testing.T) {
+	before := createTestGameCharacterCard()
+	after := createTestGameCharacterCard()
+
+	after.Name = "Renamed"
+	delete(after.Stats, "energy")
+	hunger := after.Stats["hunger"]
+	hunger.Max = 80
+	after.Stats["hunger"] = hunger
+
+	changes := DiffCards(before, after)
+	for _, path := range []string{"name", "stats.energy", "stats.hunger"} {
+		if change, ok := findChange(changes, path); !ok || !change.BreaksSaves || change.SaveImpact == "" {
+			t.Errorf("Expected %s to be flagged as breaking saves, got %+v", path, change)
+		}
+	}
+
+	after.Stats = nil
+	if change, ok := findChange(DiffCards(before, after), "stats"); !ok || !change.BreaksSaves {
+		t.Errorf("Expected dropping game features to break saves, got %+v", change)
+	}
+}
@@ -174,3 +174,102 @@ func createTestCharacterCardWithDialogBackend() *CharacterCard {
 		},
 	}
 }
+
+// streamingChatBackend streams a reply word by word
+type streamingChatBackend struct {
+	*dialog.SimpleRandomBackend
+	words []string
+}
+
+func (b *streamingChatBackend) GenerateResponseStream(dialog.DialogContext) (<-chan dialog.StreamChunk, error) {
+	out := make(chan dialog.StreamChunk, len(b.words)+1)
+	for _, word := range b.words {
+		out <- dialog.StreamChunk{Text: word}
+	}
+	out <- dialog.StreamChunk{Final: &dialog.DialogResponse{Animation: "talking", Confidence: 0.95}}
+	close(out)
+	return out, nil
+}
+
+func TestHandleChatMessageStream(t *testing.T) {
+	char, err := New(createTestCharacterCardWithDialogBackend(), "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+
+	char.dialogManager.RegisterBackend("streaming", &streamingChatBackend{
+		SimpleRandomBackend: dialog.NewSimpleRandomBackend(),
+		words:               []string{"I'm ", "doing ", "great!"},
+	})
+	if err := char.dialogManager.SetDefaultBackend("streaming"); err != nil {
+		t.Fatalf("SetDefaultBackend failed: %v", err)
+	}
+
+	var partials []string
+	response := char.HandleChatMessageStream("How are you?", func(text string) {
+		partials = append(partials, text)
+	})
+
+	if response != "I'm doing great!" {
+		t.Errorf("Expected streamed reply, got %q", response)
+	}
+	if len(partials) != 3 || partials[0] != "I'm " || partials[2] != "I'm doing great!" {
+		t.Errorf("Expected growing partial text, got %q", partials)
+	}
+	if char.GetCurrentState() != "talking" {
+		t.Errorf("Expected final animation to apply, got %q", char.GetCurrentState())
+	}
+
+	// Characters without a dialog backend stay silent
+	plain, err := New(createTestCharacterCard(), "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	if got := plain.HandleChatMessageStream("Hello", nil); got != "" {
+		t.Errorf("Expected no reply without dialog backend, got %q", got)
+	}
+}
+
+// createTestCharacterCardWithScript returns a card whose chat follows a short conversation script
+func createTestCharacterCardWithScript() *CharacterCard {
+	card := createTestCharacterCardWithDialogBackend()
+	card.DialogBackend.DefaultBackend = "scripted"
+	card.DialogBackend.Backends["scripted"] = json.RawMessage(`{"script": {
+		"entries": {"chat": "ask"},
+		"nodes": {
+			"ask": {"text": "Tea or coffee?", "choices": [
+				{"text": "Tea", "next": "tea"},
+				{"text": "Coffee", "next": "coffee"}
+			]},
+			"tea": {"text": "Tea it is!"},
+			"coffee": {"text": "Coffee it is!"}
+		}
+	}}`)
+	return card
+}
+
+func TestHandleChatMessage_ScriptedChoices(t *testing.T) {
+	char, err := New(createTestCharacterCardWithScript(), "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+
+	if response := char.HandleChatMessage("hi"); response != "Tea or coffee?" {
+		t.Fatalf("Expected the script's question, got %q", response)
+	}
+	if choices := char.GetSuggestedReplies(); len(choices) != 2 || choices[0] != "Tea" || choices[1] != "Coffee" {
+		t.Fatalf("Expected the answers to be offered, got %v", choices)
+	}
+
+	// Typing a suggested reply counts as picking it
+	if chosen := char.buildChatDialogContext(" coffee").ChosenReply; chosen != "Coffee" {
+		t.Errorf("Expected the typed reply to be recognized, got %q", chosen)
+	}
+
+	if response := char.HandleChatMessage("Coffee"); response != "Coffee it is!" {
+		t.Errorf("Expected the chosen branch, got %q", response)
+	}
+	if choices := char.GetSuggestedReplies(); choices != nil {
+		t.Errorf("Expected no answers once the conversation ends, got %v", choices)
+	}
+}
@@ -0,0 +1,46 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+)
+
+// defaultClipboardReactions are used for kinds the card doesn't customize
+var defaultClipboardReactions = map[string][]string{
+	string(clipboard.KindURL):   {"Ooh, what are you reading? 👀", "A link! Anything good?"},
+	string(clipboard.KindCode):  {"Ooh, code! Need a rubber duck? 🦆", "Copying code? I'm rooting for you!"},
+	string(clipboard.KindEmail): {"Writing to someone? ✉️"},
+	string(clipboard.KindColor): {"Ooh, nice color! 🎨"},
+	string(clipboard.KindText):  {"Saving that for later?"},
+}
+
+// validateClipboardReactions checks the card only customizes known reaction types
+func (c *CharacterCard) validateClipboardReactions() error {
+	for kind, responses := range c.ClipboardReactions {
+		if !clipboard.IsKnownKind(kind) {
+			return fmt.Errorf("unknown reaction type '%s'", kind)
+		}
+		if len(responses) == 0 {
+			return fmt.Errorf("'%s' needs at least one response", kind)
+		}
+	}
+	return nil
+}
+
+// ClipboardReaction returns what the character says when the user copies
+// something of the given kind
+func (c *Character) ClipboardReaction(kind clipboard.Kind) string {
+	c.mu.RLock()
+	responses := c.card.ClipboardReactions[string(kind)]
+	c.mu.RUnlock()
+
+	if len(responses) == 0 {
+		responses = defaultClipboardReactions[string(kind)]
+	}
+	if len(responses) == 0 {
+		return ""
+	}
+	return responses[rand.Intn(len(responses))]
+}
@@ -0,0 +1,35 @@
+package character
+
+import (
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+)
+
+func TestClipboardReaction(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.ClipboardReactions = map[string][]string{"url": {"Reading again?"}}
+	if err := card.validateClipboardReactions(); err != nil {
+		t.Fatal(err)
+	}
+	char := createTestCharacterInstance(card, false)
+
+	if got := char.ClipboardReaction(clipboard.KindURL); got != "Reading again?" {
+		t.Errorf("Expected the card's URL reaction, got %q", got)
+	}
+	if got := char.ClipboardReaction(clipboard.KindCode); got == "" {
+		t.Error("Kinds the card doesn't customize should use a default reaction")
+	}
+}
+
+func TestValidateClipboardReactions(t *testing.T) {
+	card := &CharacterCard{ClipboardReactions: map[string][]string{"passwords": {"Ooh"}}}
+	if err := card.validateClipboardReactions(); err == nil {
+		t.Error("Unknown reaction types should fail validation")
+	}
+
+	card.ClipboardReactions = map[string][]string{"url": {}}
+	if err := card.validateClipboardReactions(); err == nil {
+		t.Error("Empty responses should fail validation")
+	}
+}
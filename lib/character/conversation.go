@@ -0,0 +1,165 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/dialog"
+)
+
+// conversationReferences are how a click brings a recent chat topic back
+// up; %s is the word the user used for it. Other topics, such as key
+// phrases or the backend's, use defaultConversationReference.
+var conversationReferences = map[string]string{
+	"media":      defaultConversationReference,
+	"food":       "Did you get something to eat?",
+	"health":     "Are you feeling any better?",
+	"weather":    "How's the weather out there now?",
+	"activities": "How's it going with what you were doing?",
+	"feelings":   "Are you still feeling the way you said?",
+}
+
+const defaultConversationReference = "About that %s you mentioned..."
+
+// conversationState is the conversation context shared by chat and the
+// other dialog triggers, so clicking the character after a chat can pick
+// up what was talked about
+type conversationState struct {
+	context    *dialog.ConversationContext // nil until the first chat message
+	referenced map[string]time.Time        // Topics already brought up, by when they were last mentioned
+}
+
+// rememberChatMessage adds the user's chat message to the shared
+// conversation context. Caller must hold c.mu.
+func (c *Character) rememberChatMessage(message string) {
+	if c.conversation.context == nil {
+		c.conversation.context = c.newConversationContext()
+	}
+	if err := c.conversation.context.AddMessage(context.Background(), message); err != nil && c.debug {
+		log.Printf("Failed to track conversation context: %v", err)
+	}
+}
+
+// newConversationContext creates the conversation context with the card's
+// topic settings. Caller must hold c.mu.
+func (c *Character) newConversationContext() *dialog.ConversationContext {
+	if c.card.DialogBackend == nil {
+		return dialog.NewConversationContext()
+	}
+	conversation, err := dialog.NewConversationContextWithTopics(c.card.DialogBackend.Topics)
+	if err != nil {
+		log.Printf("Invalid topic settings, using defaults: %v", err)
+		return dialog.NewConversationContext()
+	}
+	return conversation
+}
+
+// rememberResponseTopics adds the topics a dialog backend reported with
+// its chat response, for cards that take topics from the backend.
+// Caller must hold c.mu.
+func (c *Character) rememberResponseTopics(topics []string) {
+	if c.conversation.context != nil {
+		c.conversation.context.AddResponseTopics(topics)
+	}
+}
+
+// GetActiveTopics returns the topics of the recent conversation, most
+// recent first, or nil before anything has been said
+func (c *Character) GetActiveTopics() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.conversation.context == nil {
+		return nil
+	}
+	active := c.conversation.context.GetActiveTopics()
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].LastSeen.After(active[j].LastSeen)
+	})
+
+	var topics []string
+	for _, topic := range active {
+		topics = append(topics, topic.Name)
+	}
+	return topics
+}
+
+// conversationTopicContext describes the recent conversation for a dialog
+// context's TopicContext, or nil before anything has been said.
+// Caller must hold c.mu.
+func (c *Character) conversationTopicContext() map[string]interface{} {
+	conversation := c.conversation.context
+	if conversation == nil || len(conversation.RecentMessages) == 0 {
+		return nil
+	}
+
+	active := conversation.GetActiveTopics()
+	topics := make([]string, len(active))
+	for i, topic := range active {
+		topics[i] = topic.Name
+	}
+	return map[string]interface{}{
+		"summary":        conversation.GetContextSummary(),
+		"recentTopics":   topics,
+		"recentMessages": append([]string(nil), conversation.RecentMessages...),
+	}
+}
+
+// conversationReference returns a line bringing up the latest active chat
+// topic, once per mention, or "" when there is nothing new to bring up.
+// Caller must hold c.mu.
+func (c *Character) conversationReference() string {
+	if c.conversation.context == nil {
+		return ""
+	}
+
+	var latest *dialog.ConversationTopic
+	active := c.conversation.context.GetActiveTopics()
+	for i, topic := range active {
+		if _, ok := conversationReferences[topic.Name]; !ok && topic.Mention == "" {
+			continue
+		}
+		if referenced, ok := c.conversation.referenced[topic.Name]; ok && !topic.LastSeen.After(referenced) {
+			continue
+		}
+		if latest == nil || topic.LastSeen.After(latest.LastSeen) ||
+			(topic.LastSeen.Equal(latest.LastSeen) && topic.Confidence > latest.Confidence) {
+			latest = &active[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+
+	if c.conversation.referenced == nil {
+		c.conversation.referenced = make(map[string]time.Time)
+	}
+	c.conversation.referenced[latest.Name] = latest.LastSeen
+
+	line, ok := conversationReferences[latest.Name]
+	if !ok {
+		line = defaultConversationReference
+	}
+	if strings.Contains(line, "%s") {
+		line = fmt.Sprintf(line, latest.Mention)
+	}
+	return line
+}
+
+// withConversationReference leads a click response with a reference to the
+// latest chat topic, if there is one to bring up. Caller must hold c.mu.
+func (c *Character) withConversationReference(text string) string {
+	reference := c.conversationReference()
+	switch {
+	case reference == "":
+		return text
+	case text == "":
+		return reference
+	default:
+		return reference + " " + text
+	}
+}
@@ -0,0 +1,81 @@
+package character
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/dialog"
+)
+
+func TestConversationCarriesOverToClicks(t *testing.T) {
+	char, err := New(createTestCharacterCardWithDialogBackend(), "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+
+	if context := char.buildDialogContext("click"); context.TopicContext["conversation"] != nil {
+		t.Fatalf("Expected no conversation before chatting, got %v", context.TopicContext["conversation"])
+	}
+	if response := char.HandleClick(); strings.Contains(response, "mentioned") {
+		t.Fatalf("Expected no reference before chatting, got %q", response)
+	}
+
+	char.HandleChatMessage("We saw a great movie last night")
+
+	conversation, ok := char.buildDialogContext("click").TopicContext["conversation"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected the click context to carry the conversation")
+	}
+	if topics, _ := conversation["recentTopics"].([]string); len(topics) != 1 || topics[0] != "media" {
+		t.Errorf("Expected the chat topic to be shared, got %v", conversation["recentTopics"])
+	}
+
+	if response := char.HandleClick(); !strings.HasPrefix(response, "About that movie you mentioned...") {
+		t.Errorf("Expected the click to bring up the movie, got %q", response)
+	}
+	// Each mention is brought up once
+	if response := char.HandleClick(); strings.Contains(response, "mentioned") {
+		t.Errorf("Expected the movie to be brought up only once, got %q", response)
+	}
+
+	char.HandleChatMessage("I'm so hungry, what should I eat?")
+	if response := char.HandleClick(); !strings.HasPrefix(response, "Did you get something to eat?") {
+		t.Errorf("Expected the click to bring up the new topic, got %q", response)
+	}
+}
+
+func TestChatContextKeepsSharedTopics(t *testing.T) {
+	char, err := New(createTestCharacterCardWithDialogBackend(), "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+
+	char.HandleChatMessage("Have you read any good book lately?")
+	context := char.buildChatDialogContext("What about a movie?")
+	if context.TopicContext["conversation"] == nil {
+		t.Error("Expected the chat context to keep the shared conversation")
+	}
+	if context.TopicContext["message_length"] == nil {
+		t.Error("Expected the message's own topics alongside it")
+	}
+}
+
+func TestConversationTopicSettings(t *testing.T) {
+	card := createTestCharacterCardWithDialogBackend()
+	card.DialogBackend.Topics = &dialog.TopicConfig{Extractor: dialog.TopicExtractorRAKE}
+	char, err := New(card, "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+
+	if topics := char.GetActiveTopics(); topics != nil {
+		t.Errorf("Expected no topics before chatting, got %v", topics)
+	}
+	char.HandleChatMessage("We finally saw the new space movie")
+	if topics := char.GetActiveTopics(); len(topics) == 0 || topics[0] != "new space movie" {
+		t.Errorf("Expected the key phrase as a topic, got %v", topics)
+	}
+	if response := char.HandleClick(); !strings.HasPrefix(response, "About that new space movie you mentioned...") {
+		t.Errorf("Expected the click to bring up the key phrase, got %q", response)
+	}
+}
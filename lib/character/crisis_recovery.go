@@ -581,6 +581,22 @@ func (crm *CrisisRecoveryManager) SetRecoveryBonus(bonus float64) {
 	crm.recoveryBonus = math.Max(1.0, bonus) // Minimum 1.0 (no penalty)
 }
 
+// SetThresholds replaces the stat levels that trigger crises. Stats missing
+// from thresholds keep their current level.
+func (crm *CrisisRecoveryManager) SetThresholds(thresholds map[string]float64) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	updated := make(map[string]float64, len(crm.crisisThresholds))
+	for stat, level := range crm.crisisThresholds {
+		updated[stat] = level
+	}
+	for stat, level := range thresholds {
+		updated[stat] = level
+	}
+	crm.crisisThresholds = updated
+}
+
 // OverrideCrisisTimeForTesting allows tests to override crisis trigger times
 // This enables testing recovery mechanics without waiting for real time requirements
 func (crm *CrisisRecoveryManager) OverrideCrisisTimeForTesting(crisisIndex int, triggeredAt time.Time) {
@@ -0,0 +1,144 @@
+package character
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// crisisTitles are friendly names for the built-in crises
+var crisisTitles = map[string]string{
+	"jealousy_crisis":  "Jealousy",
+	"trust_crisis":     "Shaken trust",
+	"affection_crisis": "Drifting apart",
+}
+
+// CrisisStatus explains an active relationship crisis and how to recover
+type CrisisStatus struct {
+	Name          string
+	Title         string
+	Description   string
+	Severity      float64 // 0-1
+	TriggeredAt   time.Time
+	TimeRemaining time.Duration // Minimum wait left before recovery is possible
+	Interactions  []RecoveryProgress
+	StatTargets   map[string]float64 // Stat levels the recovery checks against
+}
+
+// RecoveryProgress tracks one interaction needed for recovery
+type RecoveryProgress struct {
+	Interaction string
+	Done        int
+	Required    int
+}
+
+// CrisisChange reports a crisis starting or resolving
+type CrisisChange struct {
+	Name     string
+	Title    string
+	Resolved bool
+}
+
+// crisisTitle returns a friendly title for a crisis name
+func crisisTitle(name string) string {
+	if title, ok := crisisTitles[name]; ok {
+		return title
+	}
+	title := strings.ReplaceAll(strings.TrimSuffix(name, "_crisis"), "_", " ")
+	if title == "" {
+		return name
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// CrisisTrackingEnabled reports whether this character can have relationship crises
+func (c *Character) CrisisTrackingEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.crisisRecoveryManager != nil && c.gameState != nil
+}
+
+// GetRelationshipCrises returns the active crises with recovery progress,
+// most severe first
+func (c *Character) GetRelationshipCrises() []CrisisStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.crisisRecoveryManager == nil || c.gameState == nil {
+		return nil
+	}
+
+	history := c.gameState.GetInteractionHistory()
+	now := time.Now()
+
+	var statuses []CrisisStatus
+	for _, crisis := range c.crisisRecoveryManager.GetActiveCrises() {
+		status := CrisisStatus{
+			Name:        crisis.Name,
+			Title:       crisisTitle(crisis.Name),
+			Description: crisis.Description,
+			Severity:    crisis.Severity,
+			TriggeredAt: crisis.TriggeredAt,
+		}
+
+		if config := crisis.RecoveryConfig; config != nil {
+			if remaining := config.TimeRequirement - now.Sub(crisis.TriggeredAt); remaining > 0 {
+				status.TimeRemaining = remaining
+			}
+			for interaction, required := range config.RequiredInteractions {
+				status.Interactions = append(status.Interactions, RecoveryProgress{
+					Interaction: interaction,
+					Done:        min(len(history[interaction]), required),
+					Required:    required,
+				})
+			}
+			sort.Slice(status.Interactions, func(i, j int) bool {
+				return status.Interactions[i].Interaction < status.Interactions[j].Interaction
+			})
+			status.StatTargets = config.RequiredStats
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Severity > statuses[j].Severity
+	})
+	return statuses
+}
+
+// GetCrisisChanges returns crises that started or resolved since the last
+// call and clears the list. Used by the UI for gentle notifications.
+func (c *Character) GetCrisisChanges() []CrisisChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changes := c.crisisChanges
+	c.crisisChanges = nil
+	return changes
+}
+
+// syncCrisisChanges compares the active crises with the last known set and
+// queues a change for every crisis that started or resolved.
+// Must be called with c.mu held.
+func (c *Character) syncCrisisChanges() {
+	if c.crisisRecoveryManager == nil {
+		return
+	}
+
+	active := make(map[string]bool)
+	for _, crisis := range c.crisisRecoveryManager.GetActiveCrises() {
+		active[crisis.Name] = true
+		if !c.knownCrises[crisis.Name] {
+			c.crisisChanges = append(c.crisisChanges, CrisisChange{Name: crisis.Name, Title: crisisTitle(crisis.Name)})
+		}
+	}
+
+	for name := range c.knownCrises {
+		if !active[name] {
+			c.crisisChanges = append(c.crisisChanges, CrisisChange{Name: name, Title: crisisTitle(name), Resolved: true})
+		}
+	}
+
+	c.knownCrises = active
+}
@@ -0,0 +1,53 @@
+package character
+
+import "testing"
+
+func TestRelationshipCrisisStatus(t *testing.T) {
+	char := createTestCharacterWithRomanceFeatures(createRomanceCharacterCard(), true)
+	if !char.CrisisTrackingEnabled() {
+		t.Fatal("Romance characters should track crises")
+	}
+	if crises := char.GetRelationshipCrises(); len(crises) != 0 {
+		t.Fatalf("No crisis expected yet, got %+v", crises)
+	}
+
+	char.GetGameState().ApplyInteractionEffects(map[string]float64{"jealousy": 85.0})
+	char.Update()
+
+	crises := char.GetRelationshipCrises()
+	if len(crises) != 1 {
+		t.Fatalf("Expected one active crisis, got %+v", crises)
+	}
+	crisis := crises[0]
+	if crisis.Title != "Jealousy" || crisis.TimeRemaining <= 0 {
+		t.Errorf("Unexpected crisis status %+v", crisis)
+	}
+	if len(crisis.Interactions) != 3 || crisis.Interactions[0].Interaction != "apology" || crisis.Interactions[0].Required != 2 {
+		t.Errorf("Expected sorted recovery steps starting with apology x2, got %+v", crisis.Interactions)
+	}
+
+	changes := char.GetCrisisChanges()
+	if len(changes) != 1 || changes[0].Name != "jealousy_crisis" || changes[0].Resolved {
+		t.Fatalf("Expected a crisis start notification, got %+v", changes)
+	}
+	if changes := char.GetCrisisChanges(); len(changes) != 0 {
+		t.Errorf("Changes should be reported once, got %+v", changes)
+	}
+
+	// Resolve the crisis and sync again
+	char.mu.Lock()
+	char.crisisRecoveryManager.activeCrises[0].IsActive = false
+	char.syncCrisisChanges()
+	char.mu.Unlock()
+
+	changes = char.GetCrisisChanges()
+	if len(changes) != 1 || !changes[0].Resolved {
+		t.Errorf("Expected a crisis resolved notification, got %+v", changes)
+	}
+}
+
+func TestCrisisTitleFallback(t *testing.T) {
+	if got := crisisTitle("boredom_crisis"); got != "Boredom" {
+		t.Errorf("Expected Boredom, got %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package character
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opd-ai/desktop-companion/lib/dialog"
+)
+
+// Chat messages the dialog benchmark sends, from small talk to feelings
+var benchChatMessages = []string{
+	"Hi! How are you today?",
+	"I had a really long day at work.",
+	"What should we do this weekend?",
+	"Do you like music?",
+	"I missed you!",
+}
+
+// benchStatLevels are the stat scenarios the benchmark covers, as a share
+// of each stat's maximum: struggling, getting by and thriving
+var benchStatLevels = []float64{0.1, 0.5, 0.9}
+
+// benchTimesOfDay are the times of day the benchmark covers
+var benchTimesOfDay = []string{"morning", "afternoon", "evening", "night"}
+
+// DialogBenchContexts builds a benchmark corpus from the card: every dialog
+// trigger the card uses plus chat, at low, middle and high stats and each
+// time of day
+func (c *Character) DialogBenchContexts() []dialog.DialogContext {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var contexts []dialog.DialogContext
+	for _, trigger := range c.benchTriggers() {
+		for _, level := range benchStatLevels {
+			for _, timeOfDay := range benchTimesOfDay {
+				if trigger != "chat" {
+					contexts = append(contexts, c.benchContext(trigger, level, timeOfDay))
+					continue
+				}
+				for _, message := range benchChatMessages {
+					context := c.benchContext(trigger, level, timeOfDay)
+					context.ConversationTurn++
+					context.LastResponse = message
+					context.TopicContext = c.extractTopicsFromMessage(message)
+					contexts = append(contexts, context)
+				}
+			}
+		}
+	}
+	return contexts
+}
+
+// benchTriggers returns the basic triggers, the card's romance dialog
+// triggers and chat, sorted
+func (c *Character) benchTriggers() []string {
+	triggers := map[string]bool{"click": true, "rightclick": true, "hover": true, "chat": true}
+	for _, d := range c.card.Dialogs {
+		triggers[d.Trigger] = true
+	}
+	for _, d := range c.card.RomanceDialogs {
+		triggers[d.Trigger] = true
+	}
+
+	sorted := make([]string, 0, len(triggers))
+	for trigger := range triggers {
+		sorted = append(sorted, trigger)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// benchContext builds one benchmark context with every stat at level of its
+// maximum. Must be called with c.mu held.
+func (c *Character) benchContext(trigger string, level float64, timeOfDay string) dialog.DialogContext {
+	context := c.buildDialogContext(trigger)
+	context.InteractionID = fmt.Sprintf("bench_%s_%s_%.0f", trigger, timeOfDay, level*100)
+	context.TimeOfDay = timeOfDay
+	context.CurrentMood = level * 100
+	context.CurrentStats = make(map[string]float64, len(c.card.Stats))
+	for name, stat := range c.card.Stats {
+		context.CurrentStats[name] = stat.Max * level
+	}
+	return context
+}
+
+// BenchmarkDialogBackends runs every registered dialog backend over
+// DialogBenchContexts rounds times. It returns an error when the card has no
+// dialog backend enabled.
+func (c *Character) BenchmarkDialogBackends(rounds int) ([]dialog.BenchResult, error) {
+	c.mu.RLock()
+	manager := c.dialogManager
+	c.mu.RUnlock()
+	if manager == nil {
+		return nil, fmt.Errorf("character has no dialog backend enabled")
+	}
+
+	// Backends can be slow, so they run without the character lock
+	return dialog.BenchmarkBackends(manager, c.DialogBenchContexts(), rounds), nil
+}
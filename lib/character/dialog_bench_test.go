@@ -0,0 +1,72 @@
+package character
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/dialog"
+)
+
+func TestDialogBenchContexts(t *testing.T) {
+	card := createTestGameCharacterCard()
+	char := createTestCharacterInstance(card, true)
+
+	contexts := char.DialogBenchContexts()
+	// click, hover and rightclick once per scenario, chat once per message
+	scenarios := len(benchStatLevels) * len(benchTimesOfDay)
+	if want := scenarios * (3 + len(benchChatMessages)); len(contexts) != want {
+		t.Fatalf("Expected %d contexts, got %d", want, len(contexts))
+	}
+
+	var chats int
+	lowHunger := false
+	for _, context := range contexts {
+		if context.Trigger == "chat" {
+			chats++
+			if context.LastResponse == "" {
+				t.Error("Chat contexts should carry a message")
+			}
+		}
+		if context.CurrentStats["hunger"] == 10 && context.CurrentMood == 10 {
+			lowHunger = true
+		}
+	}
+	if chats != scenarios*len(benchChatMessages) {
+		t.Errorf("Expected %d chat contexts, got %d", scenarios*len(benchChatMessages), chats)
+	}
+	if !lowHunger {
+		t.Error("Expected a scenario with stats at 10% of their maximum")
+	}
+}
+
+func TestBenchmarkDialogBackends(t *testing.T) {
+	card := createTestGameCharacterCard()
+	char := createTestCharacterInstance(card, true)
+	if _, err := char.BenchmarkDialogBackends(1); err == nil {
+		t.Error("Expected an error without a dialog backend")
+	}
+
+	card.DialogBackend = &dialog.DialogBackendConfig{
+		Enabled:        true,
+		DefaultBackend: "simple_random",
+		Backends:       map[string]json.RawMessage{"simple_random": json.RawMessage(`{}`)},
+	}
+	if err := char.initializeDialogSystem(); err != nil {
+		t.Fatalf("initializeDialogSystem failed: %v", err)
+	}
+
+	results, err := char.BenchmarkDialogBackends(2)
+	if err != nil {
+		t.Fatalf("BenchmarkDialogBackends failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Backend != "simple_random" {
+			continue
+		}
+		if result.Attempts != 2*len(char.DialogBenchContexts()) {
+			t.Errorf("Expected simple_random to answer every context, got %d attempts", result.Attempts)
+		}
+		return
+	}
+	t.Errorf("Expected a simple_random result, got %+v", results)
+}
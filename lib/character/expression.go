@@ -0,0 +1,614 @@
+package character
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Expression is a parsed expression such as "affection > 50 && since.give_gift < 1h".
+// The grammar is intentionally tiny and implemented with the standard library only:
+//
+//	expr    := or
+//	or      := and { ("||" | "or") and }
+//	and     := not { ("&&" | "and") not }
+//	not     := ("!" | "not") not | compare
+//	compare := sum [ ("<" | "<=" | ">" | ">=" | "==" | "!=") sum ]
+//	sum     := term { ("+" | "-") term }
+//	term    := unary { ("*" | "/" | "%") unary }
+//	unary   := "-" unary | primary
+//	primary := number | duration | identifier | "true" | "false"
+//	         | function "(" [ expr { "," expr } ] ")" | "(" expr ")"
+//
+// Durations such as 30s, 15m, 1h or 2d evaluate to seconds. Built-in functions
+// are min, max, abs and clamp. All values are float64; comparisons and logic
+// operators yield 1 (true) or 0 (false).
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// ExpressionEnv resolves identifiers used in expressions
+type ExpressionEnv interface {
+	Lookup(name string) (float64, bool)
+}
+
+// exprNode is a node of the parsed expression tree
+type exprNode interface {
+	eval(env ExpressionEnv) (float64, error)
+}
+
+// expressionCache avoids re-parsing conditions that are evaluated every frame
+var expressionCache sync.Map
+
+// ParseExpression parses an expression string into an evaluable tree
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// compileExpression returns a cached parse of source
+func compileExpression(source string) (*Expression, error) {
+	if cached, ok := expressionCache.Load(source); ok {
+		return cached.(*Expression), nil
+	}
+
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return nil, err
+	}
+
+	expressionCache.Store(source, expr)
+	return expr, nil
+}
+
+// EvaluateCondition parses (with caching) and evaluates source as a boolean condition
+func EvaluateCondition(source string, env ExpressionEnv) (bool, error) {
+	expr, err := compileExpression(source)
+	if err != nil {
+		return false, err
+	}
+	return expr.EvaluateBool(env)
+}
+
+// String returns the original expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate computes the numeric value of the expression
+func (e *Expression) Evaluate(env ExpressionEnv) (float64, error) {
+	return e.root.eval(env)
+}
+
+// EvaluateBool computes the expression and reports whether it is non-zero
+func (e *Expression) EvaluateBool(env ExpressionEnv) (bool, error) {
+	value, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return value != 0, nil
+}
+
+// Identifiers returns the distinct identifiers referenced by the expression
+func (e *Expression) Identifiers() []string {
+	seen := make(map[string]bool)
+	var names []string
+	collectIdentifiers(e.root, seen, &names)
+	return names
+}
+
+// collectIdentifiers walks the tree gathering identifier names in order of appearance
+func collectIdentifiers(node exprNode, seen map[string]bool, names *[]string) {
+	switch n := node.(type) {
+	case identNode:
+		if !seen[n.name] {
+			seen[n.name] = true
+			*names = append(*names, n.name)
+		}
+	case unaryNode:
+		collectIdentifiers(n.operand, seen, names)
+	case binaryNode:
+		collectIdentifiers(n.left, seen, names)
+		collectIdentifiers(n.right, seen, names)
+	case callNode:
+		for _, arg := range n.args {
+			collectIdentifiers(arg, seen, names)
+		}
+	}
+}
+
+// Expression tree nodes
+
+type numberNode float64
+
+func (n numberNode) eval(ExpressionEnv) (float64, error) {
+	return float64(n), nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n identNode) eval(env ExpressionEnv) (float64, error) {
+	if env == nil {
+		return 0, fmt.Errorf("unknown identifier %q", n.name)
+	}
+	value, ok := env.Lookup(n.name)
+	if !ok {
+		return 0, fmt.Errorf("unknown identifier %q", n.name)
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n unaryNode) eval(env ExpressionEnv) (float64, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -value, nil
+	}
+	return boolToFloat(value == 0), nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n binaryNode) eval(env ExpressionEnv) (float64, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return 0, err
+	}
+
+	// Short-circuit logic operators
+	switch n.op {
+	case "&&":
+		if left == 0 {
+			return 0, nil
+		}
+	case "||":
+		if left != 0 {
+			return 1, nil
+		}
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		return boolToFloat(right != 0), nil
+	case "<":
+		return boolToFloat(left < right), nil
+	case "<=":
+		return boolToFloat(left <= right), nil
+	case ">":
+		return boolToFloat(left > right), nil
+	case ">=":
+		return boolToFloat(left >= right), nil
+	case "==":
+		return boolToFloat(left == right), nil
+	case "!=":
+		return boolToFloat(left != right), nil
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return left / right, nil
+	case "%":
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return math.Mod(left, right), nil
+	}
+
+	return 0, fmt.Errorf("unknown operator %q", n.op)
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+// expressionFunctions are the built-in functions with their allowed argument counts
+var expressionFunctions = map[string]struct {
+	minArgs, maxArgs int
+	fn               func(args []float64) float64
+}{
+	"min": {1, -1, func(args []float64) float64 {
+		result := args[0]
+		for _, v := range args[1:] {
+			result = math.Min(result, v)
+		}
+		return result
+	}},
+	"max": {1, -1, func(args []float64) float64 {
+		result := args[0]
+		for _, v := range args[1:] {
+			result = math.Max(result, v)
+		}
+		return result
+	}},
+	"abs": {1, 1, func(args []float64) float64 {
+		return math.Abs(args[0])
+	}},
+	"clamp": {3, 3, func(args []float64) float64 {
+		return math.Max(args[1], math.Min(args[2], args[0]))
+	}},
+}
+
+func (n callNode) eval(env ExpressionEnv) (float64, error) {
+	values := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		values[i] = value
+	}
+	return expressionFunctions[n.name].fn(values), nil
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Tokenizer
+
+type exprTokenKind int
+
+const (
+	tokenNumber exprTokenKind = iota
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+}
+
+// tokenizeExpression splits source into numbers, identifiers, operators and parentheses
+func tokenizeExpression(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, exprToken{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, exprToken{tokenRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, exprToken{tokenComma, ",", i})
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Optional duration suffix: 30s, 15m, 1h, 2d
+			if i < len(runes) && strings.ContainsRune("smhd", runes[i]) &&
+				(i+1 == len(runes) || !isIdentRune(runes[i+1])) {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokenNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (isIdentRune(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokenIdent, string(runes[start:i]), start})
+		default:
+			op := matchOperator(runes[i:])
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, exprToken{tokenOperator, op, i})
+			i += len(op)
+		}
+	}
+
+	return tokens, nil
+}
+
+// isIdentRune reports whether r may appear inside an identifier
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// durationUnits maps duration suffixes to seconds
+var durationUnits = map[byte]float64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400}
+
+// parseNumberLiteral parses a plain number or a duration literal into seconds
+func parseNumberLiteral(text string) (float64, error) {
+	multiplier := 1.0
+	if unit, ok := durationUnits[text[len(text)-1]]; ok {
+		multiplier = unit
+		text = text[:len(text)-1]
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, err
+	}
+	return value * multiplier, nil
+}
+
+// matchOperator returns the longest operator at the start of runes
+func matchOperator(runes []rune) string {
+	for _, op := range []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "!", "+", "-", "*", "/", "%"} {
+		if strings.HasPrefix(string(runes[:min(len(runes), len(op))]), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// Parser
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+// acceptOperator consumes the next token if it is one of the given operators or keywords
+func (p *exprParser) acceptOperator(ops ...string) (string, bool) {
+	if p.done() {
+		return "", false
+	}
+	tok := p.peek()
+	if tok.kind != tokenOperator && tok.kind != tokenIdent {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if _, ok := p.acceptOperator("!", "not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.acceptOperator("<", "<=", ">", ">=", "==", "!="); ok {
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.acceptOperator("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parseCall parses the argument list of a built-in function call
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	spec, known := expressionFunctions[name.text]
+	if !known {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	p.pos++ // consume "("
+
+	var args []exprNode
+	if !p.done() && p.peek().kind == tokenRParen {
+		p.pos++
+	} else {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.done() {
+				return nil, fmt.Errorf("missing closing parenthesis for %s()", name.text)
+			}
+			tok := p.peek()
+			p.pos++
+			if tok.kind == tokenRParen {
+				break
+			}
+			if tok.kind != tokenComma {
+				return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+			}
+		}
+	}
+
+	if len(args) < spec.minArgs || (spec.maxArgs >= 0 && len(args) > spec.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for %s(): %d", name.text, len(args))
+	}
+
+	return callNode{name: name.text, args: args}, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	tok := p.peek()
+	p.pos++
+
+	switch tok.kind {
+	case tokenNumber:
+		value, err := parseNumberLiteral(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return numberNode(value), nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return numberNode(1), nil
+		case "false":
+			return numberNode(0), nil
+		case "and", "or", "not":
+			return nil, fmt.Errorf("unexpected keyword %q at position %d", tok.text, tok.pos)
+		}
+		if !p.done() && p.peek().kind == tokenLParen {
+			return p.parseCall(tok)
+		}
+		return identNode{name: tok.text}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", tok.pos)
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
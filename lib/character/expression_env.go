@@ -0,0 +1,185 @@
+package character
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// neverInteractedSeconds is reported by "since.<type>" for interactions that never happened
+const neverInteractedSeconds = math.MaxInt32
+
+// gameStateEnv exposes game state values to condition expressions.
+// It reads fields directly, so callers must already hold the relevant locks.
+//
+// Available identifiers:
+//   - any stat name, e.g. "happiness"
+//   - "interactions.<type>" - number of times an interaction was performed
+//   - "age" - progression age in seconds
+//   - "playtime" - total play time in seconds
+//   - "achievements" - number of earned achievements
+//   - "hour" - current hour of the day (0-23)
+//   - "since.<type>" - seconds since an interaction was last performed
+type gameStateEnv struct {
+	gs  *GameState
+	now time.Time
+}
+
+// Lookup resolves an identifier against the game state
+func (e gameStateEnv) Lookup(name string) (float64, bool) {
+	if stat, exists := e.gs.Stats[name]; exists {
+		return stat.Current, true
+	}
+
+	if strings.HasPrefix(name, "since.") {
+		last, exists := e.gs.LastInteractions[strings.TrimPrefix(name, "since.")]
+		if !exists {
+			return neverInteractedSeconds, true
+		}
+		return e.now.Sub(last).Seconds(), true
+	}
+
+	ps := e.gs.Progression
+	if strings.HasPrefix(name, "interactions.") {
+		if ps == nil {
+			return 0, true
+		}
+		return float64(ps.InteractionCounts[strings.TrimPrefix(name, "interactions.")]), true
+	}
+
+	switch name {
+	case CurrencyKey:
+		return float64(e.gs.Coins), true
+	case "age":
+		if ps == nil {
+			return 0, true
+		}
+		return ps.Age.Seconds(), true
+	case "playtime":
+		return e.gs.TotalPlayTime.Seconds(), true
+	case "achievements":
+		if ps == nil {
+			return 0, true
+		}
+		return float64(len(ps.Achievements)), true
+	case "hour":
+		return float64(e.now.Hour()), true
+	}
+
+	return 0, false
+}
+
+// validateConditionIdentifiers checks that a condition only references known names
+func validateConditionIdentifiers(condition string, stats map[string]StatConfig) error {
+	if err := validateExpressionIdentifiers(condition, stats); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+	return nil
+}
+
+// validateExpressionIdentifiers parses an expression and checks every identifier
+func validateExpressionIdentifiers(source string, stats map[string]StatConfig) error {
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range expr.Identifiers() {
+		if strings.HasPrefix(name, "interactions.") || strings.HasPrefix(name, "since.") {
+			continue
+		}
+		switch name {
+		case "age", "playtime", "achievements", "hour", CurrencyKey:
+			continue
+		}
+		if _, exists := stats[name]; !exists {
+			return fmt.Errorf("unknown stat '%s'", name)
+		}
+	}
+
+	return nil
+}
+
+// lockForExpressions read-locks the game state and its progression for evaluation.
+// The returned function releases both locks.
+func (gs *GameState) lockForExpressions() func() {
+	gs.mu.RLock()
+	ps := gs.Progression
+	if ps != nil {
+		ps.mu.RLock()
+	}
+	return func() {
+		if ps != nil {
+			ps.mu.RUnlock()
+		}
+		gs.mu.RUnlock()
+	}
+}
+
+// EvaluateCondition evaluates a condition expression against the current game state.
+// Empty conditions are always met; invalid ones never are.
+func (gs *GameState) EvaluateCondition(condition string) bool {
+	if condition == "" {
+		return true
+	}
+	if gs == nil {
+		return false
+	}
+
+	unlock := gs.lockForExpressions()
+	defer unlock()
+
+	met, err := EvaluateCondition(condition, gameStateEnv{gs: gs, now: time.Now()})
+	return err == nil && met
+}
+
+// EvaluateExpression computes a numeric expression against the current game state
+func (gs *GameState) EvaluateExpression(source string) (float64, error) {
+	if gs == nil {
+		return 0, fmt.Errorf("game state is nil")
+	}
+
+	expr, err := compileExpression(source)
+	if err != nil {
+		return 0, err
+	}
+
+	unlock := gs.lockForExpressions()
+	defer unlock()
+
+	return expr.Evaluate(gameStateEnv{gs: gs, now: time.Now()})
+}
+
+// ApplyEffectExpressions applies stat changes computed from expressions, e.g.
+// {"happiness": "(100 - happiness) * 0.2"}. All expressions see the stats as they
+// were before any change is applied. Expressions that fail to evaluate are skipped.
+func (gs *GameState) ApplyEffectExpressions(effects map[string]string) {
+	if gs == nil || len(effects) == 0 {
+		return
+	}
+
+	changes := make(map[string]float64, len(effects))
+	for statName, source := range effects {
+		change, err := gs.EvaluateExpression(source)
+		if err != nil {
+			continue
+		}
+		changes[statName] = change
+	}
+
+	gs.ApplyInteractionEffects(changes)
+}
+
+// validateEffectExpressions checks that effect expressions parse and target known stats
+func validateEffectExpressions(effects map[string]string, stats map[string]StatConfig) error {
+	for statName, source := range effects {
+		if _, exists := stats[statName]; !exists && statName != CurrencyKey {
+			return fmt.Errorf("effect expression targets unknown stat '%s'", statName)
+		}
+		if err := validateExpressionIdentifiers(source, stats); err != nil {
+			return fmt.Errorf("effect expression for '%s': %w", statName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package character
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGameStateEvaluateCondition(t *testing.T) {
+	gs := createTestGameState()
+	gs.Stats["happiness"].Current = 60
+
+	if !gs.EvaluateCondition("") {
+		t.Error("Empty condition should always be met")
+	}
+	if !gs.EvaluateCondition("happiness > 50 && hunger >= 100") {
+		t.Error("Expected stat condition to be met")
+	}
+	if gs.EvaluateCondition("happiness > 50 && sparkle > 1") {
+		t.Error("Unknown identifiers should fail the condition")
+	}
+
+	// Never-performed interactions are infinitely long ago
+	if !gs.EvaluateCondition("since.give_gift > 1h") {
+		t.Error("Expected since.give_gift to be large before any gift")
+	}
+
+	gs.RecordInteraction("give_gift")
+	if !gs.EvaluateCondition("since.give_gift < 1m") {
+		t.Error("Expected recent gift to be reflected in since.give_gift")
+	}
+
+	gs.LastInteractions["give_gift"] = time.Now().Add(-2 * time.Hour)
+	if gs.EvaluateCondition("happiness > 50 and since.give_gift < 1h") {
+		t.Error("Expected gift older than an hour to fail the condition")
+	}
+}
+
+func TestGameStateApplyEffectExpressions(t *testing.T) {
+	gs := createTestGameState()
+	gs.Stats["happiness"].Current = 40
+	gs.Stats["energy"].Current = 80
+
+	gs.ApplyEffectExpressions(map[string]string{
+		"happiness": "(100 - happiness) * 0.5",
+		"energy":    "-happiness / 4",
+		"health":    "broken +",
+	})
+
+	if got := gs.GetStat("happiness"); got != 70 {
+		t.Errorf("Expected happiness 70, got %f", got)
+	}
+	// Expressions see pre-change stats: energy drops by 40/4
+	if got := gs.GetStat("energy"); got != 70 {
+		t.Errorf("Expected energy 70, got %f", got)
+	}
+	if got := gs.GetStat("health"); got != 100 {
+		t.Errorf("Invalid expression should be skipped, health is %f", got)
+	}
+}
+
+func TestValidateEffectExpressions(t *testing.T) {
+	stats := map[string]StatConfig{"happiness": {Initial: 50, Max: 100}}
+
+	if err := validateEffectExpressions(map[string]string{"happiness": "10 - happiness / 10"}, stats); err != nil {
+		t.Errorf("Valid effect expression rejected: %v", err)
+	}
+	if err := validateEffectExpressions(map[string]string{"sparkle": "1"}, stats); err == nil {
+		t.Error("Expected error for unknown target stat")
+	}
+	if err := validateEffectExpressions(map[string]string{"happiness": "hunger + 1"}, stats); err == nil {
+		t.Error("Expected error for unknown stat in expression")
+	}
+}
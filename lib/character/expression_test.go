@@ -0,0 +1,137 @@
+package character
+
+import "testing"
+
+// mapEnv is a simple ExpressionEnv backed by a map
+type mapEnv map[string]float64
+
+func (m mapEnv) Lookup(name string) (float64, bool) {
+	value, ok := m[name]
+	return value, ok
+}
+
+func TestExpressionEvaluate(t *testing.T) {
+	env := mapEnv{"happiness": 85, "hunger": 20, "interactions.feed": 12}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{"happiness >= 80", true},
+		{"happiness > 85", false},
+		{"happiness >= 80 && hunger < 30", true},
+		{"happiness >= 80 and hunger > 30", false},
+		{"hunger > 30 || interactions.feed >= 10", true},
+		{"!(hunger > 30)", true},
+		{"not happiness", false},
+		{"true && (false || happiness == 85)", true},
+		{"happiness != 85", false},
+		{"0.5 < 1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpression failed: %v", err)
+			}
+			got, err := expr.EvaluateBool(env)
+			if err != nil {
+				t.Fatalf("EvaluateBool failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpressionParseErrors(t *testing.T) {
+	invalid := []string{"", "happiness >=", "(happiness > 1", "happiness > 1)", "a $ b", "&& a", "a and"}
+
+	for _, source := range invalid {
+		if _, err := ParseExpression(source); err == nil {
+			t.Errorf("Expected parse error for %q", source)
+		}
+	}
+}
+
+func TestExpressionUnknownIdentifier(t *testing.T) {
+	if _, err := EvaluateCondition("missing > 1", mapEnv{}); err == nil {
+		t.Error("Expected error for unknown identifier")
+	}
+
+	// Short-circuit skips the unknown identifier
+	met, err := EvaluateCondition("false && missing > 1", mapEnv{})
+	if err != nil || met {
+		t.Errorf("Expected short-circuit false, got %v, %v", met, err)
+	}
+}
+
+func TestExpressionIdentifiers(t *testing.T) {
+	expr, err := ParseExpression("happiness > 1 && (hunger < 2 || happiness < 3) && interactions.play > 0")
+	if err != nil {
+		t.Fatalf("ParseExpression failed: %v", err)
+	}
+
+	names := expr.Identifiers()
+	expected := []string{"happiness", "hunger", "interactions.play"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Identifier %d: expected %s, got %s", i, expected[i], names[i])
+		}
+	}
+}
+
+func TestExpressionArithmeticAndFunctions(t *testing.T) {
+	env := mapEnv{"happiness": 40, "hunger": 90}
+
+	tests := []struct {
+		expr     string
+		expected float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"-happiness + 50", 10},
+		{"(100 - happiness) * 0.5", 30},
+		{"10 % 4", 2},
+		{"1h", 3600},
+		{"30m + 30s", 1830},
+		{"2d / 1h", 48},
+		{"min(happiness, hunger, 50)", 40},
+		{"max(happiness, hunger)", 90},
+		{"abs(happiness - hunger)", 50},
+		{"clamp(hunger * 2, 0, 100)", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpression failed: %v", err)
+			}
+			got, err := expr.Evaluate(env)
+			if err != nil {
+				t.Fatalf("Evaluate failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpressionFunctionErrors(t *testing.T) {
+	for _, source := range []string{"unknown(1)", "abs(1, 2)", "clamp(1)", "min(", "max(1 2)"} {
+		if _, err := ParseExpression(source); err == nil {
+			t.Errorf("Expected parse error for %q", source)
+		}
+	}
+
+	if _, err := EvaluateCondition("1 / 0 > 1", mapEnv{}); err == nil {
+		t.Error("Expected division by zero error")
+	}
+}
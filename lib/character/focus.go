@@ -0,0 +1,315 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Pomodoro defaults used when the card has no focus section or leaves fields empty
+const (
+	AnimationFocused = "focused"
+
+	defaultFocusWorkMinutes      = 25
+	defaultFocusBreakMinutes     = 5
+	defaultFocusLongBreakMinutes = 15
+	defaultFocusSessionsPerCycle = 4
+)
+
+// FocusPhase is the state of the pomodoro timer
+type FocusPhase int
+
+const (
+	FocusOff   FocusPhase = iota // Timer not running
+	FocusWork                    // Work session: focused animation, dialogs suppressed
+	FocusBreak                   // Short or long break between sessions
+)
+
+// FocusConfig customizes the pomodoro focus-buddy mode. Every character can
+// run the timer; the card only changes lengths, animations and rewards.
+type FocusConfig struct {
+	WorkMinutes      int                `json:"workMinutes,omitempty"`      // Work session length (default: 25)
+	BreakMinutes     int                `json:"breakMinutes,omitempty"`     // Short break length (default: 5)
+	LongBreakMinutes int                `json:"longBreakMinutes,omitempty"` // Long break length (default: 15)
+	SessionsPerCycle int                `json:"sessionsPerCycle,omitempty"` // Work sessions before a long break (default: 4)
+	WorkAnimation    string             `json:"workAnimation,omitempty"`    // Held during work (default: "focused" if present)
+	BreakAnimations  []string           `json:"breakAnimations,omitempty"`  // One is played when a break starts, e.g. stretch or cheer
+	WorkResponses    []string           `json:"workResponses,omitempty"`    // Said when a work session starts
+	BreakResponses   []string           `json:"breakResponses,omitempty"`   // Said when a break starts
+	Rewards          map[string]float64 `json:"rewards,omitempty"`          // Stat changes per completed work session
+}
+
+// FocusStatus describes the running timer for the UI
+type FocusStatus struct {
+	Phase     FocusPhase
+	Remaining time.Duration
+	Total     time.Duration
+	Completed int // Work sessions finished since the timer started
+}
+
+// FocusResult describes a phase change for UI notifications
+type FocusResult struct {
+	Phase    FocusPhase // The phase that just started
+	Response string
+	Effects  map[string]float64
+}
+
+// focusSession is the running pomodoro timer
+type focusSession struct {
+	work, shortBreak time.Duration
+	phase            FocusPhase
+	startedAt        time.Time
+	endsAt           time.Time
+	completed        int
+}
+
+// GetWorkDuration returns the configured work session length
+func (f *FocusConfig) GetWorkDuration() time.Duration {
+	if f == nil || f.WorkMinutes <= 0 {
+		return defaultFocusWorkMinutes * time.Minute
+	}
+	return time.Duration(f.WorkMinutes) * time.Minute
+}
+
+// GetBreakDuration returns the configured short break length
+func (f *FocusConfig) GetBreakDuration() time.Duration {
+	if f == nil || f.BreakMinutes <= 0 {
+		return defaultFocusBreakMinutes * time.Minute
+	}
+	return time.Duration(f.BreakMinutes) * time.Minute
+}
+
+// GetLongBreakDuration returns the configured long break length
+func (f *FocusConfig) GetLongBreakDuration() time.Duration {
+	if f == nil || f.LongBreakMinutes <= 0 {
+		return defaultFocusLongBreakMinutes * time.Minute
+	}
+	return time.Duration(f.LongBreakMinutes) * time.Minute
+}
+
+// GetSessionsPerCycle returns how many work sessions come before a long break
+func (f *FocusConfig) GetSessionsPerCycle() int {
+	if f == nil || f.SessionsPerCycle <= 0 {
+		return defaultFocusSessionsPerCycle
+	}
+	return f.SessionsPerCycle
+}
+
+// validateFocus validates the optional focus section
+func (c *CharacterCard) validateFocus() error {
+	f := c.Focus
+	if f == nil {
+		return nil
+	}
+
+	for name, value := range map[string]int{
+		"workMinutes":      f.WorkMinutes,
+		"breakMinutes":     f.BreakMinutes,
+		"longBreakMinutes": f.LongBreakMinutes,
+		"sessionsPerCycle": f.SessionsPerCycle,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", name, value)
+		}
+	}
+
+	animations := append([]string{f.WorkAnimation}, f.BreakAnimations...)
+	for _, animation := range animations {
+		if animation == "" {
+			continue
+		}
+		if _, exists := c.Animations[animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", animation)
+		}
+	}
+
+	if len(f.Rewards) > 0 && !c.HasGameFeatures() {
+		return fmt.Errorf("rewards require stats to be defined")
+	}
+	return c.validateJobStats(f.Rewards)
+}
+
+// StartFocus starts the pomodoro timer with the given work and break
+// lengths, zero meaning the card's setting. Returns the start response.
+func (c *Character) StartFocus(work, shortBreak time.Duration) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if work <= 0 {
+		work = c.card.Focus.GetWorkDuration()
+	}
+	if shortBreak <= 0 {
+		shortBreak = c.card.Focus.GetBreakDuration()
+	}
+
+	c.focus = &focusSession{work: work, shortBreak: shortBreak}
+	return c.startFocusWork(time.Now())
+}
+
+// StopFocus stops the timer. Returns false if it wasn't running.
+func (c *Character) StopFocus() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.focus == nil {
+		return false
+	}
+	c.focus = nil
+	c.setState(c.selectIdleAnimation())
+	return true
+}
+
+// SkipFocusPhase ends the current work session or break early.
+// Skipped work sessions earn no rewards.
+func (c *Character) SkipFocusPhase() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.focus == nil {
+		return
+	}
+	if c.focus.phase == FocusWork {
+		c.focusResults = append(c.focusResults, FocusResult{Phase: FocusBreak, Response: c.startFocusBreak(time.Now())})
+		return
+	}
+	c.focusResults = append(c.focusResults, FocusResult{Phase: FocusWork, Response: c.startFocusWork(time.Now())})
+}
+
+// GetFocusStatus returns the timer state; Phase is FocusOff when not running
+func (c *Character) GetFocusStatus() FocusStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.focus == nil {
+		return FocusStatus{}
+	}
+
+	remaining := time.Until(c.focus.endsAt)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return FocusStatus{
+		Phase:     c.focus.phase,
+		Remaining: remaining,
+		Total:     c.focus.endsAt.Sub(c.focus.startedAt),
+		Completed: c.focus.completed,
+	}
+}
+
+// IsFocusing reports whether a work session is running. The UI keeps
+// dialogs quiet while it is.
+func (c *Character) IsFocusing() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.focus != nil && c.focus.phase == FocusWork
+}
+
+// GetFocusResults returns phase changes since the last call and clears the list
+func (c *Character) GetFocusResults() []FocusResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := c.focusResults
+	c.focusResults = nil
+	return results
+}
+
+// updateFocus moves the timer to the next phase once the current one ends.
+// Must be called with c.mu held. Returns true if the animation state changed.
+func (c *Character) updateFocus(now time.Time) bool {
+	if c.focus == nil || now.Before(c.focus.endsAt) {
+		return false
+	}
+
+	previousState := c.currentState
+	if c.focus.phase == FocusWork {
+		c.focus.completed++
+		result := FocusResult{Phase: FocusBreak, Effects: c.card.Focus.getRewards()}
+		if c.gameState != nil {
+			c.gameState.ApplyInteractionEffects(result.Effects)
+			c.gameState.RecordInteraction("focus")
+		}
+		result.Response = c.startFocusBreak(now)
+		c.focusResults = append(c.focusResults, result)
+	} else {
+		c.focusResults = append(c.focusResults, FocusResult{Phase: FocusWork, Response: c.startFocusWork(now)})
+	}
+	return c.currentState != previousState
+}
+
+// startFocusWork begins a work session. Must be called with c.mu held.
+func (c *Character) startFocusWork(now time.Time) string {
+	c.focus.phase = FocusWork
+	c.focus.startedAt = now
+	c.focus.endsAt = now.Add(c.focus.work)
+
+	if animation, ok := c.focusAnimation(); ok {
+		c.setState(animation)
+	}
+	return selectJobResponse(c.card.Focus.getWorkResponses(), "Let's focus! 🍅")
+}
+
+// startFocusBreak begins a short or long break and plays a break animation.
+// Must be called with c.mu held.
+func (c *Character) startFocusBreak(now time.Time) string {
+	length := c.focus.shortBreak
+	if c.focus.completed > 0 && c.focus.completed%c.card.Focus.GetSessionsPerCycle() == 0 {
+		length = c.card.Focus.GetLongBreakDuration()
+	}
+
+	c.focus.phase = FocusBreak
+	c.focus.startedAt = now
+	c.focus.endsAt = now.Add(length)
+
+	if animations := c.card.Focus.getBreakAnimations(); len(animations) > 0 {
+		c.setState(animations[rand.Intn(len(animations))])
+	} else {
+		c.setState(c.selectIdleAnimation())
+	}
+	return selectJobResponse(c.card.Focus.getBreakResponses(), fmt.Sprintf("Great work! Take a %d minute break ☕", int(length.Minutes())))
+}
+
+// focusAnimation returns the animation to hold during a work session.
+// Must be called with c.mu held.
+func (c *Character) focusAnimation() (string, bool) {
+	if c.focus == nil || c.focus.phase != FocusWork {
+		return "", false
+	}
+
+	animation := AnimationFocused
+	if c.card.Focus != nil && c.card.Focus.WorkAnimation != "" {
+		animation = c.card.Focus.WorkAnimation
+	}
+	if _, exists := c.card.Animations[animation]; !exists {
+		return "", false
+	}
+	return animation, true
+}
+
+func (f *FocusConfig) getRewards() map[string]float64 {
+	if f == nil {
+		return nil
+	}
+	return f.Rewards
+}
+
+func (f *FocusConfig) getBreakAnimations() []string {
+	if f == nil {
+		return nil
+	}
+	return f.BreakAnimations
+}
+
+func (f *FocusConfig) getWorkResponses() []string {
+	if f == nil {
+		return nil
+	}
+	return f.WorkResponses
+}
+
+func (f *FocusConfig) getBreakResponses() []string {
+	if f == nil {
+		return nil
+	}
+	return f.BreakResponses
+}
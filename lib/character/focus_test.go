@@ -0,0 +1,100 @@
+package character
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFocusConfigDefaults(t *testing.T) {
+	var f *FocusConfig
+	if f.GetWorkDuration() != 25*time.Minute || f.GetBreakDuration() != 5*time.Minute ||
+		f.GetLongBreakDuration() != 15*time.Minute || f.GetSessionsPerCycle() != 4 {
+		t.Error("Nil focus config should use the classic pomodoro lengths")
+	}
+
+	f = &FocusConfig{WorkMinutes: 50, BreakMinutes: 10}
+	if f.GetWorkDuration() != 50*time.Minute || f.GetBreakDuration() != 10*time.Minute {
+		t.Error("Configured lengths should be used")
+	}
+}
+
+func TestFocusCycle(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Focus = &FocusConfig{
+		SessionsPerCycle: 2,
+		LongBreakMinutes: 20,
+		BreakResponses:   []string{"Stretch time!"},
+		Rewards:          map[string]float64{"coins": 3},
+	}
+	if err := card.validateFocus(); err != nil {
+		t.Fatalf("validateFocus failed: %v", err)
+	}
+	char := createTestCharacterInstance(card, true)
+
+	if response := char.StartFocus(0, time.Minute); response == "" {
+		t.Error("Expected a start response")
+	}
+	if !char.IsFocusing() {
+		t.Fatal("Work session should be running")
+	}
+	status := char.GetFocusStatus()
+	if status.Phase != FocusWork || status.Total != 25*time.Minute {
+		t.Errorf("Expected a 25 minute work session, got %+v", status)
+	}
+
+	// First work session ends: short break and a reward
+	char.mu.Lock()
+	char.updateFocus(time.Now().Add(26 * time.Minute))
+	char.mu.Unlock()
+	results := char.GetFocusResults()
+	if len(results) != 1 || results[0].Phase != FocusBreak || results[0].Response != "Stretch time!" {
+		t.Fatalf("Expected a break announcement, got %+v", results)
+	}
+	if status := char.GetFocusStatus(); status.Phase != FocusBreak || status.Total != time.Minute || status.Completed != 1 {
+		t.Errorf("Expected a 1 minute break after one session, got %+v", status)
+	}
+	if char.IsFocusing() {
+		t.Error("Breaks should not count as focusing")
+	}
+	if coins := char.GetGameState().GetCoins(); coins != 3 {
+		t.Errorf("Expected 3 coins after one session, got %d", coins)
+	}
+
+	// Break ends, second session is skipped straight to its break: no reward, short break
+	char.SkipFocusPhase()
+	if status := char.GetFocusStatus(); status.Phase != FocusWork {
+		t.Fatalf("Skipping the break should start work, got %+v", status)
+	}
+	char.mu.Lock()
+	char.updateFocus(time.Now().Add(26 * time.Minute))
+	char.mu.Unlock()
+	if status := char.GetFocusStatus(); status.Total != 20*time.Minute || status.Completed != 2 {
+		t.Errorf("Expected a long break after the second session, got %+v", status)
+	}
+
+	if !char.StopFocus() || char.GetFocusStatus().Phase != FocusOff {
+		t.Error("StopFocus should turn the timer off")
+	}
+	if char.StopFocus() {
+		t.Error("Stopping twice should report nothing to stop")
+	}
+}
+
+func TestValidateFocus(t *testing.T) {
+	card := &CharacterCard{Animations: map[string]string{"idle": "idle.gif"}}
+
+	card.Focus = &FocusConfig{WorkMinutes: -1}
+	if err := card.validateFocus(); err == nil {
+		t.Error("Negative lengths should fail validation")
+	}
+
+	card.Focus = &FocusConfig{BreakAnimations: []string{"stretch"}}
+	if err := card.validateFocus(); err == nil {
+		t.Error("Unknown break animation should fail validation")
+	}
+
+	card.Focus = &FocusConfig{Rewards: map[string]float64{"happiness": 5}}
+	if err := card.validateFocus(); err == nil {
+		t.Error("Rewards without stats should fail validation")
+	}
+}
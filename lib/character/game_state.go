@@ -48,19 +48,33 @@ type GameState struct {
 	Progression        *ProgressionState      `json:"progression,omitempty"`
 	RelationshipLevel  string                 `json:"relationshipLevel,omitempty"`
 	InteractionHistory map[string][]time.Time `json:"interactionHistory,omitempty"`
+	LastInteractions   map[string]time.Time   `json:"lastInteractions,omitempty"` // Most recent time of each interaction type
 	RomanceMemories    []RomanceMemory        `json:"romanceMemories,omitempty"`
 	DialogMemories     []DialogMemory         `json:"dialogMemories,omitempty"`
 	GiftMemories       []GiftMemory           `json:"giftMemories,omitempty"`
+	Inventory          map[string]int         `json:"inventory,omitempty"`     // Items earned from jobs and other rewards
+	ActiveJob          *ActiveJob             `json:"activeJob,omitempty"`     // Job in progress, survives restarts
+	Coins              int                    `json:"coins,omitempty"`         // Currency earned from interactions, jobs and achievements
+	Purchases          map[string]int         `json:"purchases,omitempty"`     // Shop item ID -> times bought
+	ActiveSkin         string                 `json:"activeSkin,omitempty"`    // Equipped skin shop item
+	UserBirthday       string                 `json:"userBirthday,omitempty"`  // User's birthday as "MM-DD"
+	CalendarFired      map[string]int         `json:"calendarFired,omitempty"` // Calendar event name -> year it last fired
+	ScheduleFired      map[string]time.Time   `json:"scheduleFired,omitempty"` // Scheduled event name -> last fire time
+	Journal            []JournalEntry         `json:"journal,omitempty"`       // Notable happenings, oldest first (see AddJournalEntry)
+	Buffs              []ActiveBuff           `json:"buffs,omitempty"`         // Temporary stat modifiers from gifts and events
 	recentAchievements []AchievementDetails   // Non-persistent field for UI notifications
+	resumedAt          time.Time              // When a saved game was restored; decay before it happened while the app was closed
+	decayMultiplier    float64                // Slows decay while the user is away, 0 means full speed
 }
 
 // Stat represents a game statistic with boundaries and degradation rules
 // All values are float64 to support precise calculations and gradual changes
 type Stat struct {
-	Current           float64 `json:"current"`
-	Max               float64 `json:"max"`
-	DegradationRate   float64 `json:"degradationRate"`   // Points per minute of decay
-	CriticalThreshold float64 `json:"criticalThreshold"` // Threshold for critical state
+	Current           float64          `json:"current"`
+	Max               float64          `json:"max"`
+	DegradationRate   float64          `json:"degradationRate"`   // Points per minute of decay
+	CriticalThreshold float64          `json:"criticalThreshold"` // Threshold for critical state
+	Decay             *StatDecayConfig `json:"decay,omitempty"`   // Curve and schedule, nil for linear decay around the clock
 }
 
 // GameConfig holds game-wide settings that affect stat behavior
@@ -74,10 +88,11 @@ type GameConfig struct {
 // StatConfig represents the configuration for a stat from JSON
 // This is used during character card loading to initialize stats
 type StatConfig struct {
-	Initial           float64 `json:"initial"`
-	Max               float64 `json:"max"`
-	DegradationRate   float64 `json:"degradationRate"`
-	CriticalThreshold float64 `json:"criticalThreshold"`
+	Initial           float64          `json:"initial"`
+	Max               float64          `json:"max"`
+	DegradationRate   float64          `json:"degradationRate"`
+	CriticalThreshold float64          `json:"criticalThreshold"`
+	Decay             *StatDecayConfig `json:"decay,omitempty"` // Per-stat curve, active hours and pause while away
 }
 
 // NewGameState creates a new game state from stat configurations
@@ -98,12 +113,22 @@ func NewGameState(statConfigs map[string]StatConfig, config *GameConfig) *GameSt
 
 	// Initialize stats from configuration
 	for name, config := range statConfigs {
-		gs.Stats[name] = &Stat{
+		stat := &Stat{
 			Current:           config.Initial,
 			Max:               config.Max,
 			DegradationRate:   config.DegradationRate,
 			CriticalThreshold: config.CriticalThreshold,
 		}
+		if config.Decay != nil {
+			decay := *config.Decay
+			stat.Decay = &decay
+			// A linear rate replaces degradationRate, so relationship
+			// tuning still adjusts it
+			if decay.Curve != DecayCurveExponential && decay.Rate > 0 {
+				stat.DegradationRate = decay.Rate
+			}
+		}
+		gs.Stats[name] = stat
 	}
 
 	return gs
@@ -137,12 +162,19 @@ func (gs *GameState) Update(elapsed time.Duration) []string {
 
 	// Update total play time
 	gs.TotalPlayTime += elapsed
+	gs.pruneBuffs(now)
 
 	// Update progression if enabled
 	levelChanged, newAchievements := gs.updateProgression(elapsed)
 
-	// Store achievement details for UI retrieval
-	gs.recentAchievements = newAchievements
+	// Queue achievement details until the UI retrieves them
+	gs.recentAchievements = append(gs.recentAchievements, newAchievements...)
+	for _, achievement := range newAchievements {
+		gs.addJournalEntry(JournalAchievement, achievement.Name, achievement.Description, now)
+	}
+	if levelChanged {
+		gs.addJournalEntry(JournalLevelUp, "Grew up to "+gs.Progression.CurrentLevel, "", now)
+	}
 
 	// Check if enough time has passed for degradation
 	decayInterval := gs.calculateDecayInterval()
@@ -151,7 +183,7 @@ func (gs *GameState) Update(elapsed time.Duration) []string {
 	}
 
 	// Apply stat degradation and collect triggered states
-	triggeredStates := gs.applyStatDegradation(timeSinceLastDecay)
+	triggeredStates := gs.applyStatDegradation(gs.LastDecayUpdate, now)
 
 	// Add progression-based states
 	triggeredStates = append(triggeredStates, gs.buildProgressionStates(levelChanged, newAchievements)...)
@@ -206,13 +238,21 @@ func (gs *GameState) buildProgressionStates(levelChanged bool, newAchievements [
 	return triggeredStates
 }
 
-// applyStatDegradation processes stat degradation and returns triggered states
-func (gs *GameState) applyStatDegradation(timeSinceLastDecay time.Duration) []string {
-	minutesElapsed := timeSinceLastDecay.Minutes()
+// applyStatDegradation decays each stat for its share of the time from
+// from to to and returns triggered states
+func (gs *GameState) applyStatDegradation(from, to time.Time) []string {
 	triggeredStates := make([]string, 0)
 
 	for name, stat := range gs.Stats {
-		if stat.DegradationRate > 0 {
+		if !stat.decays() {
+			continue
+		}
+		minutesElapsed := stat.decayMinutes(from, to, gs.resumedAt)
+		if gs.decayMultiplier > 0 {
+			minutesElapsed *= gs.decayMultiplier
+		}
+		minutesElapsed *= gs.buffDecayMultiplier(name, to)
+		if minutesElapsed > 0 {
 			statStates := gs.processStatDegradation(name, stat, minutesElapsed)
 			triggeredStates = append(triggeredStates, statStates...)
 		}
@@ -225,12 +265,9 @@ func (gs *GameState) applyStatDegradation(timeSinceLastDecay time.Duration) []st
 func (gs *GameState) processStatDegradation(name string, stat *Stat, minutesElapsed float64) []string {
 	triggeredStates := make([]string, 0)
 
-	// Calculate degradation amount
-	degradationAmount := stat.DegradationRate * minutesElapsed
+	// Apply degradation along the stat's curve, with minimum bound of 0
 	oldValue := stat.Current
-
-	// Apply degradation with minimum bound of 0
-	stat.Current = math.Max(0, stat.Current-degradationAmount)
+	stat.Current = stat.decayBy(minutesElapsed)
 
 	// Check if we crossed the critical threshold
 	if oldValue > stat.CriticalThreshold && stat.Current <= stat.CriticalThreshold {
@@ -266,6 +303,7 @@ func (gs *GameState) getStatStateMapping(statName string) string {
 
 // ApplyInteractionEffects modifies stats based on character interactions
 // Effects map contains stat names and the amount to modify (can be positive or negative)
+// Increases are scaled by running buffs
 // All modifications respect stat boundaries (0 to Max)
 func (gs *GameState) ApplyInteractionEffects(effects map[string]float64) {
 	if gs == nil || len(effects) == 0 {
@@ -275,8 +313,16 @@ func (gs *GameState) ApplyInteractionEffects(effects map[string]float64) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 
+	now := time.Now()
 	for statName, change := range effects {
+		if _, isStat := gs.Stats[statName]; !isStat && statName == CurrencyKey {
+			gs.addCoinsLocked(int(change))
+			continue
+		}
 		if stat, exists := gs.Stats[statName]; exists {
+			if change > 0 {
+				change *= gs.buffGainMultiplier(statName, now)
+			}
 			// Apply change with bounds checking
 			newValue := stat.Current + change
 			stat.Current = math.Max(0, math.Min(stat.Max, newValue))
@@ -302,6 +348,28 @@ func (gs *GameState) GetStats() map[string]float64 {
 	return stats
 }
 
+// setDecayMultiplier scales how fast every stat decays, e.g. 0.5 for half
+// speed while the user is away. 1 restores full speed.
+func (gs *GameState) setDecayMultiplier(multiplier float64) {
+	if gs == nil {
+		return
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.decayMultiplier = multiplier
+}
+
+// setDegradationRate changes how fast a stat decays, in points per minute.
+// Unknown stats are ignored.
+func (gs *GameState) setDegradationRate(name string, rate float64) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if stat, exists := gs.Stats[name]; exists {
+		stat.DegradationRate = rate
+	}
+}
+
 // GetStat returns the current value of a specific stat
 // Returns 0 if the stat doesn't exist
 func (gs *GameState) GetStat(name string) float64 {
@@ -654,13 +722,17 @@ func (gs *GameState) GetProgression() *ProgressionState {
 
 // RecordInteraction records an interaction for progression tracking
 func (gs *GameState) RecordInteraction(interactionType string) {
-	if gs == nil || gs.Progression == nil {
+	if gs == nil {
 		return
 	}
 
-	gs.mu.RLock()
+	gs.mu.Lock()
+	if gs.LastInteractions == nil {
+		gs.LastInteractions = make(map[string]time.Time)
+	}
+	gs.LastInteractions[interactionType] = time.Now()
 	progression := gs.Progression
-	gs.mu.RUnlock()
+	gs.mu.Unlock()
 
 	if progression != nil {
 		progression.RecordInteraction(interactionType)
@@ -785,6 +857,10 @@ func (gs *GameState) validateStat(name string, stat *Stat) error {
 		return fmt.Errorf("critical threshold (%f) must be between 0 and max (%f)", stat.CriticalThreshold, stat.Max)
 	}
 
+	if err := stat.Decay.Validate(); err != nil {
+		return fmt.Errorf("decay: %w", err)
+	}
+
 	return nil
 }
 
@@ -9,14 +9,15 @@ import (
 // This extends the existing RandomEventConfig structure for user-triggered events
 type GeneralDialogEvent struct {
 	RandomEventConfig               // Embed existing random event structure
-	Category          string        `json:"category"`                  // "conversation", "roleplay", "game", "humor"
-	Trigger           string        `json:"trigger"`                   // Custom trigger identifier
-	Interactive       bool          `json:"interactive"`               // Whether event supports user choices
-	Choices           []EventChoice `json:"choices,omitempty"`         // User interaction choices
-	FollowUpEvents    []string      `json:"followUpEvents,omitempty"`  // Chain to other events
-	Keywords          []string      `json:"keywords,omitempty"`        // Keywords for event discovery
-	Difficulty        string        `json:"difficulty,omitempty"`      // "easy", "normal", "hard"
-	MinRelationship   string        `json:"minRelationship,omitempty"` // Minimum relationship level required
+	Category          string        `json:"category"`                   // "conversation", "roleplay", "game", "humor"
+	Trigger           string        `json:"trigger"`                    // Custom trigger identifier
+	Interactive       bool          `json:"interactive"`                // Whether event supports user choices
+	Choices           []EventChoice `json:"choices,omitempty"`          // User interaction choices
+	FollowUpEvents    []string      `json:"followUpEvents,omitempty"`   // Chain to other events
+	Keywords          []string      `json:"keywords,omitempty"`         // Keywords for event discovery
+	Difficulty        string        `json:"difficulty,omitempty"`       // "easy", "normal", "hard"
+	MinRelationship   string        `json:"minRelationship,omitempty"`  // Minimum relationship level required
+	RequiresPurchase  string        `json:"requiresPurchase,omitempty"` // Shop item that unlocks this event
 }
 
 // EventChoice represents a user choice within an interactive event
@@ -225,6 +226,11 @@ func (gem *GeneralEventManager) canTriggerEvent(event GeneralDialogEvent, now ti
 		}
 	}
 
+	// Events sold in the shop stay hidden until bought
+	if event.RequiresPurchase != "" && gameState.GetPurchaseCount(event.RequiresPurchase) == 0 {
+		return false
+	}
+
 	return true
 }
 
@@ -38,6 +38,7 @@ type GiftEffects struct {
 	Immediate ImmediateEffects `json:"immediate"`
 	Memory    MemoryEffects    `json:"memory"`
 	Battle    BattleItemEffect `json:"battle,omitempty"` // Battle-specific effects
+	Buff      *BuffConfig      `json:"buff,omitempty"`   // Temporary stat modifier granted with the gift
 }
 
 // ImmediateEffects represents stat changes, animations, and responses
@@ -260,6 +261,12 @@ func (g *GiftDefinition) validateEffects() error {
 		return fmt.Errorf("memory importance must be between 0 and 1, got %f", g.GiftEffects.Memory.Importance)
 	}
 
+	if g.GiftEffects.Buff != nil {
+		if err := g.GiftEffects.Buff.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -238,10 +238,13 @@ func (gm *GiftManager) GiveGift(giftID, notes string) (*GiftResponse, error) {
 	// Apply personality modifiers to stat effects (reuses existing personality system)
 	modifiedEffects := gm.applyPersonalityModifiers(gift)
 
-	// Apply stat effects using existing GameState methods
+	// Apply stat effects using existing GameState methods, then the gift's buff
 	var actualEffects map[string]float64
 	if gm.gameState != nil {
 		actualEffects = gm.applyStatEffects(modifiedEffects)
+		if gift.GiftEffects.Buff != nil {
+			gm.gameState.AddBuff(*gift.GiftEffects.Buff)
+		}
 	} else {
 		actualEffects = modifiedEffects
 	}
@@ -296,10 +299,14 @@ func (gm *GiftManager) applyStatEffects(effects map[string]float64) map[string]f
 	gm.gameState.mu.Lock()
 	defer gm.gameState.mu.Unlock()
 
+	now := time.Now()
 	actualEffects := make(map[string]float64)
 	for statName, change := range effects {
 		stat := gm.gameState.Stats[statName]
 		if stat != nil {
+			if change > 0 {
+				change *= gm.gameState.buffGainMultiplier(statName, now)
+			}
 			oldValue := stat.Current
 			stat.Current = math.Min(stat.Max, math.Max(0, stat.Current+change))
 			actualEffects[statName] = stat.Current - oldValue
@@ -380,6 +387,7 @@ func (gm *GiftManager) recordGiftMemory(gift *GiftDefinition, notes, response st
 
 	// Add new memory entry
 	gm.gameState.GiftMemories = append(gm.gameState.GiftMemories, giftMemory)
+	gm.gameState.addJournalEntry(JournalGift, "Received "+gift.Name, notes, giftMemory.Timestamp)
 
 	// Limit memory size to prevent unbounded growth (follows existing pattern)
 	maxMemories := 100
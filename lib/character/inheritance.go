@@ -0,0 +1,132 @@
+package character
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxExtendsDepth stops runaway chains that aren't strictly cycles, e.g.
+// generated paths that never repeat
+const maxExtendsDepth = 16
+
+// ResolveExtends applies card inheritance. A card with "extends" set to the
+// path of another card (relative to itself) starts from that card and
+// deep-merges its own fields on top: objects merge key by key, while arrays
+// and plain values replace the base's. A null removes an inherited field.
+// Bases may extend other cards; cycles are reported with the full chain.
+func ResolveExtends(data []byte, cardPath string) ([]byte, error) {
+	root, err := decodeCardObject(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := root["extends"]; !ok {
+		return data, nil
+	}
+
+	absPath, err := filepath.Abs(cardPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve card path %s: %w", cardPath, err)
+	}
+	resolved, err := resolveCardObject(root, absPath, []string{absPath})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
+
+// resolveCardObject merges root over its base chain. chain lists the cards
+// visited so far, starting with the card being loaded.
+func resolveCardObject(root map[string]interface{}, cardPath string, chain []string) (map[string]interface{}, error) {
+	rawExtends, ok := root["extends"]
+	if !ok {
+		return root, nil
+	}
+	extends, ok := rawExtends.(string)
+	if !ok || extends == "" {
+		return nil, fmt.Errorf("%s: extends must be a path to a character card", cardPath)
+	}
+
+	basePath := extends
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(cardPath), basePath)
+	}
+	basePath = filepath.Clean(basePath)
+
+	for _, visited := range chain {
+		if visited == basePath {
+			return nil, fmt.Errorf("card inheritance cycle: %s", strings.Join(append(chain, basePath), " -> "))
+		}
+	}
+	if len(chain) > maxExtendsDepth {
+		return nil, fmt.Errorf("card inheritance deeper than %d levels: %s", maxExtendsDepth, strings.Join(chain, " -> "))
+	}
+
+	data, err := os.ReadFile(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s extends %s: %w", cardPath, extends, err)
+	}
+	base, err := decodeCardObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s extends %s: failed to parse base card: %w", cardPath, extends, err)
+	}
+	base, err = resolveCardObject(base, basePath, append(chain, basePath))
+	if err != nil {
+		return nil, err
+	}
+
+	rebaseAnimations(base, filepath.Dir(basePath), filepath.Dir(cardPath))
+	delete(base, "extends")
+	return mergeInherited(base, root), nil
+}
+
+// decodeCardObject parses a card as a generic JSON object
+func decodeCardObject(data []byte) (map[string]interface{}, error) {
+	var root map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep large integers such as seeds exact
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// mergeInherited deep-merges child into base. Unlike locale overlays, arrays
+// replace the base's so a child can shorten a list.
+func mergeInherited(base, child map[string]interface{}) map[string]interface{} {
+	for key, childValue := range child {
+		if childValue == nil {
+			delete(base, key)
+			continue
+		}
+		childMap, childIsMap := childValue.(map[string]interface{})
+		baseMap, baseIsMap := base[key].(map[string]interface{})
+		if childIsMap && baseIsMap {
+			base[key] = mergeInherited(baseMap, childMap)
+		} else {
+			base[key] = childValue
+		}
+	}
+	return base
+}
+
+// rebaseAnimations rewrites a base card's relative animation paths so they
+// still point at the base's files from the extending card's directory
+func rebaseAnimations(base map[string]interface{}, baseDir, cardDir string) {
+	animations, ok := base["animations"].(map[string]interface{})
+	if !ok || baseDir == cardDir {
+		return
+	}
+	for name, value := range animations {
+		path, ok := value.(string)
+		if !ok || path == "" || filepath.IsAbs(path) {
+			continue
+		}
+		if rel, err := filepath.Rel(cardDir, filepath.Join(baseDir, path)); err == nil {
+			animations[name] = filepath.ToSlash(rel)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package character
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const archetypeTestCard = `{
+  "name": "Archetype",
+  "description": "Shared base",
+  "animations": {"idle": "idle.gif", "talking": "talking.gif"},
+  "dialogs": [
+    {"trigger": "click", "responses": ["Hi!"], "animation": "talking", "cooldown": 5},
+    {"trigger": "hover", "responses": ["Hmm?"], "animation": "talking", "cooldown": 5}
+  ],
+  "behavior": {"idleTimeout": 30, "movementEnabled": true, "defaultSize": 128},
+  "stats": {
+    "hunger": {"initial": 100, "max": 100, "degradationRate": 1.0, "criticalThreshold": 20},
+    "happiness": {"initial": 80, "max": 100, "degradationRate": 0.5, "criticalThreshold": 15}
+  }
+}`
+
+// writeArchetype writes the base card and its animations into dir
+func writeArchetype(t *testing.T, dir string) string {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"idle.gif", "talking.gif"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "character.json")
+	if err := os.WriteFile(path, []byte(archetypeTestCard), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeCard(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadCardExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeArchetype(t, filepath.Join(dir, "archetypes", "pet"))
+	child := writeCard(t, filepath.Join(dir, "tabby", "character.json"), `{
+  "extends": "../archetypes/pet/character.json",
+  "name": "Tabby",
+  "dialogs": [{"trigger": "click", "responses": ["Mrow!"], "animation": "talking", "cooldown": 3}],
+  "stats": {"hunger": {"degradationRate": 2.0}, "happiness": null}
+}`)
+
+	card, err := LoadCard(child)
+	if err != nil {
+		t.Fatalf("LoadCard failed: %v", err)
+	}
+	if card.Name != "Tabby" || card.Description != "Shared base" {
+		t.Errorf("Expected child name over base description, got %q / %q", card.Name, card.Description)
+	}
+	if len(card.Dialogs) != 1 || card.Dialogs[0].Responses[0] != "Mrow!" {
+		t.Errorf("Arrays should replace the base's, got %+v", card.Dialogs)
+	}
+	hunger := card.Stats["hunger"]
+	if hunger.DegradationRate != 2.0 || hunger.Initial != 100 || hunger.CriticalThreshold != 20 {
+		t.Errorf("Objects should merge by key, got %+v", hunger)
+	}
+	if _, ok := card.Stats["happiness"]; ok {
+		t.Error("null should remove the inherited stat")
+	}
+	if card.Animations["idle"] != "../archetypes/pet/idle.gif" {
+		t.Errorf("Base animations should be rebased to the child, got %q", card.Animations["idle"])
+	}
+	if card.Extends != "../archetypes/pet/character.json" {
+		t.Errorf("Expected Extends to be kept, got %q", card.Extends)
+	}
+}
+
+func TestResolveExtendsChain(t *testing.T) {
+	dir := t.TempDir()
+	writeArchetype(t, filepath.Join(dir, "base"))
+	writeCard(t, filepath.Join(dir, "mid", "character.json"),
+		`{"extends": "../base/character.json", "description": "Middle", "behavior": {"defaultSize": 96}}`)
+	leaf := writeCard(t, filepath.Join(dir, "leaf", "character.json"),
+		`{"extends": "../mid/character.json", "name": "Leaf"}`)
+
+	data, err := os.ReadFile(leaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := ResolveExtends(data, leaf)
+	if err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+
+	var card CharacterCard
+	if err := json.Unmarshal(resolved, &card); err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Leaf" || card.Description != "Middle" {
+		t.Errorf("Expected each level to override its base, got %q / %q", card.Name, card.Description)
+	}
+	if card.Behavior.DefaultSize != 96 || card.Behavior.IdleTimeout != 30 {
+		t.Errorf("Expected merged behavior, got %+v", card.Behavior)
+	}
+	if card.Animations["talking"] != "../base/talking.gif" {
+		t.Errorf("Expected animation rebased across the chain, got %q", card.Animations["talking"])
+	}
+}
+
+func TestResolveExtendsWithoutExtends(t *testing.T) {
+	data := []byte(archetypeTestCard)
+	resolved, err := ResolveExtends(data, "character.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resolved) != string(data) {
+		t.Error("Cards without extends should pass through unchanged")
+	}
+}
+
+func TestResolveExtendsErrors(t *testing.T) {
+	dir := t.TempDir()
+	a := writeCard(t, filepath.Join(dir, "a.json"), `{"extends": "b.json", "name": "A"}`)
+	writeCard(t, filepath.Join(dir, "b.json"), `{"extends": "a.json", "name": "B"}`)
+	missing := writeCard(t, filepath.Join(dir, "missing.json"), `{"extends": "nope.json"}`)
+	broken := writeCard(t, filepath.Join(dir, "broken.json"), `{"extends": "bad.json"}`)
+	writeCard(t, filepath.Join(dir, "bad.json"), `{not json`)
+	wrongType := writeCard(t, filepath.Join(dir, "wrong.json"), `{"extends": 5}`)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"cycle", a, "cycle: " + a + " -> " + filepath.Join(dir, "b.json") + " -> " + a},
+		{"missing base", missing, "extends nope.json"},
+		{"unparseable base", broken, "failed to parse base card"},
+		{"wrong type", wrongType, "extends must be a path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = ResolveExtends(data, tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package character
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// StatProjection is a stat's value now and after an interaction
+type StatProjection struct {
+	Current   float64 `json:"current"`
+	Projected float64 `json:"projected"`
+}
+
+// Delta returns the projected change, after clamping to the stat's bounds
+func (p StatProjection) Delta() float64 {
+	return p.Projected - p.Current
+}
+
+// RequirementShortfall describes a stat requirement the interaction doesn't meet
+type RequirementShortfall struct {
+	Stat    string  `json:"stat"`
+	Bound   string  `json:"bound"` // "min" or "max"
+	Limit   float64 `json:"limit"`
+	Current float64 `json:"current"` // Negative and Bound "min" when the stat doesn't exist
+}
+
+// InteractionPreview describes what an interaction would do right now
+// without doing it
+type InteractionPreview struct {
+	Interaction       string                    `json:"interaction"`
+	Modifier          float64                   `json:"modifier"` // Personality multiplier on romance stats, 1 otherwise
+	Stats             map[string]StatProjection `json:"stats,omitempty"`
+	Coins             int                       `json:"coins,omitempty"`
+	CooldownRemaining time.Duration             `json:"cooldownRemaining,omitempty"`
+	Shortfalls        []RequirementShortfall    `json:"shortfalls,omitempty"`
+	ConditionUnmet    bool                      `json:"conditionUnmet,omitempty"`
+}
+
+// Available reports whether the interaction would take effect now
+func (p InteractionPreview) Available() bool {
+	return p.CooldownRemaining <= 0 && len(p.Shortfalls) == 0 && !p.ConditionUnmet
+}
+
+// PreviewInteraction projects the outcome of a game or romance interaction:
+// stat changes after personality modifiers and clamping, remaining cooldown
+// and unmet requirements. Nothing is changed. ok is false outside game
+// mode or for unknown interactions.
+func (c *Character) PreviewInteraction(interactionType string) (preview InteractionPreview, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.gameState == nil {
+		return preview, false
+	}
+	interaction, exists := c.card.Interactions[interactionType]
+	if !exists {
+		return preview, false
+	}
+
+	preview = InteractionPreview{Interaction: interactionType, Modifier: 1}
+
+	if lastUsed, used := c.gameInteractionCooldowns[interactionType]; used {
+		remaining := time.Duration(interaction.Cooldown)*time.Second - time.Since(lastUsed)
+		preview.CooldownRemaining = max(remaining, 0)
+	}
+	preview.ConditionUnmet = !c.gameState.EvaluateCondition(interaction.Condition)
+
+	// HandleRomanceInteraction scales romance stats by personality
+	effects := interaction.Effects
+	if c.card.HasRomanceFeatures() && c.isRomanceInteraction(interaction) {
+		preview.Modifier = c.calculatePersonalityModifier(interactionType)
+		effects = c.applyPersonalityToEffects(effects, preview.Modifier)
+	}
+
+	// Expressions are evaluated against the stats before any change
+	expressionEffects := make(map[string]float64, len(interaction.EffectExpressions))
+	for statName, source := range interaction.EffectExpressions {
+		if change, err := c.gameState.EvaluateExpression(source); err == nil {
+			expressionEffects[statName] = change
+		}
+	}
+
+	c.gameState.mu.RLock()
+	defer c.gameState.mu.RUnlock()
+
+	preview.Shortfalls = requirementShortfalls(c.gameState.Stats, interaction.Requirements)
+	preview.Stats, preview.Coins = projectEffects(c.gameState.Stats, expressionEffects, effects)
+	return preview, true
+}
+
+// requirementShortfalls lists the requirement bounds the stats don't meet
+func requirementShortfalls(stats map[string]*Stat, requirements map[string]map[string]float64) []RequirementShortfall {
+	var shortfalls []RequirementShortfall
+	for statName, constraints := range requirements {
+		stat, exists := stats[statName]
+		if !exists {
+			shortfalls = append(shortfalls, RequirementShortfall{Stat: statName, Bound: "min", Limit: constraints["min"], Current: -1})
+			continue
+		}
+		if minVal, hasMin := constraints["min"]; hasMin && stat.Current < minVal {
+			shortfalls = append(shortfalls, RequirementShortfall{Stat: statName, Bound: "min", Limit: minVal, Current: stat.Current})
+		}
+		if maxVal, hasMax := constraints["max"]; hasMax && stat.Current > maxVal {
+			shortfalls = append(shortfalls, RequirementShortfall{Stat: statName, Bound: "max", Limit: maxVal, Current: stat.Current})
+		}
+	}
+	sort.Slice(shortfalls, func(i, j int) bool { return shortfalls[i].Stat < shortfalls[j].Stat })
+	return shortfalls
+}
+
+// projectEffects applies expression effects, then flat effects, to copies
+// of the stats with the same clamping as ApplyInteractionEffects
+func projectEffects(stats map[string]*Stat, passes ...map[string]float64) (map[string]StatProjection, int) {
+	projections := make(map[string]StatProjection)
+	coins := 0
+	for _, effects := range passes {
+		for statName, change := range effects {
+			stat, exists := stats[statName]
+			if !exists {
+				if statName == CurrencyKey {
+					coins += int(change)
+				}
+				continue
+			}
+			p, seen := projections[statName]
+			if !seen {
+				p = StatProjection{Current: stat.Current, Projected: stat.Current}
+			}
+			p.Projected = math.Max(0, math.Min(stat.Max, p.Projected+change))
+			projections[statName] = p
+		}
+	}
+	return projections, coins
+}
@@ -0,0 +1,78 @@
+package character
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreviewInteraction(t *testing.T) {
+	card := createTestGameCharacterCard()
+	char := createTestCharacterInstance(card, true)
+	char.gameState.Stats["hunger"].Current = 60
+	char.gameState.Stats["happiness"].Current = 90
+
+	preview, ok := char.PreviewInteraction("feed")
+	if !ok || !preview.Available() {
+		t.Fatalf("Expected feed to be available, got %+v %v", preview, ok)
+	}
+	if delta := preview.Stats["hunger"].Delta(); delta != 25 {
+		t.Errorf("Expected hunger +25, got %v", delta)
+	}
+	if got := preview.Stats["happiness"]; got.Projected != 95 {
+		t.Errorf("Expected happiness projected to 95, got %+v", got)
+	}
+
+	// Previewing changes nothing
+	if char.gameState.Stats["hunger"].Current != 60 || len(char.gameInteractionCooldowns) != 0 {
+		t.Fatal("Preview should not apply effects or start the cooldown")
+	}
+
+	char.HandleGameInteraction("feed")
+	preview, _ = char.PreviewInteraction("feed")
+	if preview.Available() {
+		t.Error("Expected feed to be unavailable after use")
+	}
+	if preview.CooldownRemaining <= 25*time.Second || preview.CooldownRemaining > 30*time.Second {
+		t.Errorf("Expected about 30s of cooldown, got %v", preview.CooldownRemaining)
+	}
+	// Hunger is now 85, above feed's max of 80
+	if len(preview.Shortfalls) != 1 || preview.Shortfalls[0].Stat != "hunger" || preview.Shortfalls[0].Bound != "max" {
+		t.Errorf("Expected a hunger shortfall, got %+v", preview.Shortfalls)
+	}
+	// Clamped at the stat's max
+	if got := preview.Stats["hunger"]; got.Projected != 100 || got.Delta() != 15 {
+		t.Errorf("Expected hunger to clamp at 100, got %+v", got)
+	}
+
+	if _, ok := char.PreviewInteraction("missing"); ok {
+		t.Error("Expected no preview for an unknown interaction")
+	}
+	if _, ok := createTestCharacterInstance(card, false).PreviewInteraction("feed"); ok {
+		t.Error("Expected no preview outside game mode")
+	}
+}
+
+func TestPreviewInteractionAppliesPersonality(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Stats["affection"] = StatConfig{Initial: 10, Max: 100}
+	card.Personality = &PersonalityConfig{Traits: map[string]float64{"affection_responsiveness": 0.5}}
+	card.Interactions["hug"] = InteractionConfig{
+		Triggers:          []string{"click"},
+		Effects:           map[string]float64{"affection": 10, "happiness": 4},
+		EffectExpressions: map[string]string{"happiness": "-happiness"},
+	}
+	char := createTestCharacterInstance(card, true)
+
+	preview, ok := char.PreviewInteraction("hug")
+	if !ok {
+		t.Fatal("Expected a preview")
+	}
+	// Romance stats are scaled by affection responsiveness, basic stats aren't
+	if preview.Modifier != 0.5 || preview.Stats["affection"].Delta() != 5 {
+		t.Errorf("Expected affection scaled by 0.5, got modifier %v and %+v", preview.Modifier, preview.Stats["affection"])
+	}
+	// The expression empties happiness, then the flat effect adds 4
+	if got := preview.Stats["happiness"]; got.Projected != 4 {
+		t.Errorf("Expected expression then flat effects, got %+v", got)
+	}
+}
@@ -0,0 +1,53 @@
+package character
+
+// AddItem adds quantity of an item to the inventory. Items are free-form
+// names (gift IDs, skins, unlocks) earned from jobs and other rewards.
+func (gs *GameState) AddItem(item string, quantity int) {
+	if gs == nil || item == "" || quantity <= 0 {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.Inventory == nil {
+		gs.Inventory = make(map[string]int)
+	}
+	gs.Inventory[item] += quantity
+}
+
+// RemoveItem removes quantity of an item. Returns false if not enough are held.
+func (gs *GameState) RemoveItem(item string, quantity int) bool {
+	if gs == nil || quantity <= 0 {
+		return false
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.Inventory[item] < quantity {
+		return false
+	}
+
+	gs.Inventory[item] -= quantity
+	if gs.Inventory[item] == 0 {
+		delete(gs.Inventory, item)
+	}
+	return true
+}
+
+// GetInventory returns a copy of the held items and their counts
+func (gs *GameState) GetInventory() map[string]int {
+	if gs == nil {
+		return nil
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	inventory := make(map[string]int, len(gs.Inventory))
+	for item, count := range gs.Inventory {
+		inventory[item] = count
+	}
+	return inventory
+}
@@ -28,6 +28,7 @@ type JealousyManager struct {
 	jealousyTriggers     []JealousyTrigger
 	checkInterval        time.Duration
 	jealousyThreshold    float64            // Jealousy level that triggers consequences
+	sensitivity          float64            // Scales trigger chance and jealousy gained, 1.0 is normal
 	jealousyConsequences map[string]float64 // Stats affected when jealous
 }
 
@@ -40,6 +41,7 @@ func NewJealousyManager(triggers []JealousyTrigger, enabled bool, threshold floa
 		jealousyTriggers:  triggers,
 		checkInterval:     30 * time.Second, // Check every 30 seconds
 		jealousyThreshold: threshold,
+		sensitivity:       1.0,
 		jealousyConsequences: map[string]float64{
 			"affection": -2.0, // Jealousy reduces affection more noticeably
 			"trust":     -1.5, // And trust
@@ -98,7 +100,7 @@ func (jm *JealousyManager) applyJealousyConsequences(gameState *GameState) {
 func (jm *JealousyManager) checkJealousyTriggers(gameState *GameState, lastInteraction, now time.Time) *TriggeredEvent {
 	for _, trigger := range jm.jealousyTriggers {
 		if jm.shouldTriggerJealousy(trigger, gameState, lastInteraction, now) {
-			if jm.rollProbability(trigger.Probability) {
+			if jm.rollProbability(math.Min(1.0, trigger.Probability*jm.sensitivity)) {
 				return jm.createJealousyEvent(trigger, gameState)
 			}
 		}
@@ -148,7 +150,7 @@ func (jm *JealousyManager) rollProbability(probability float64) bool {
 func (jm *JealousyManager) createJealousyEvent(trigger JealousyTrigger, gameState *GameState) *TriggeredEvent {
 	// Apply jealousy increment and trust penalty
 	effects := map[string]float64{
-		"jealousy": trigger.JealousyIncrement,
+		"jealousy": trigger.JealousyIncrement * jm.sensitivity,
 	}
 
 	if trigger.TrustPenalty > 0 {
@@ -200,6 +202,15 @@ func (jm *JealousyManager) SetEnabled(enabled bool) {
 	jm.enabled = enabled
 }
 
+// SetTuning changes the consequence threshold and how sensitive the
+// character is to jealousy triggers, for user relationship tuning
+func (jm *JealousyManager) SetTuning(threshold, sensitivity float64) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.jealousyThreshold = math.Max(0, math.Min(99, threshold)) // Below 100 so intensity stays finite
+	jm.sensitivity = math.Max(0, sensitivity)
+}
+
 // GetStatus returns debug information about jealousy state
 // Used for testing and debugging jealousy mechanics
 func (jm *JealousyManager) GetStatus(gameState *GameState) map[string]interface{} {
@@ -211,6 +222,7 @@ func (jm *JealousyManager) GetStatus(gameState *GameState) map[string]interface{
 		"triggerCount":  len(jm.jealousyTriggers),
 		"checkInterval": jm.checkInterval,
 		"threshold":     jm.jealousyThreshold,
+		"sensitivity":   jm.sensitivity,
 		"lastCheck":     jm.lastJealousyCheck,
 	}
 
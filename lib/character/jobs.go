@@ -0,0 +1,382 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// JobConfig defines a timed task the user can assign to the character.
+// While the job runs the character holds the job animation; when the timer
+// finishes the rewards are applied.
+type JobConfig struct {
+	Name              string                        `json:"name,omitempty"`              // Display name (defaults to the job key)
+	Description       string                        `json:"description,omitempty"`       // Shown in the job menu
+	Duration          int                           `json:"duration"`                    // Seconds the job takes
+	Animation         string                        `json:"animation,omitempty"`         // Animation while working
+	Requirements      map[string]map[string]float64 `json:"requirements,omitempty"`      // Stat requirements, same format as interactions
+	Condition         string                        `json:"condition,omitempty"`         // Extra requirement expression
+	Cost              map[string]float64            `json:"cost,omitempty"`              // Stat changes applied when the job starts
+	Rewards           map[string]float64            `json:"rewards,omitempty"`           // Stat changes applied on completion
+	RewardTable       []JobReward                   `json:"rewardTable,omitempty"`       // One weighted bonus drawn on completion
+	StartResponses    []string                      `json:"startResponses,omitempty"`    // Said when the job starts
+	CompleteResponses []string                      `json:"completeResponses,omitempty"` // Said when the job finishes
+}
+
+// JobReward is one entry of a job's weighted reward table
+type JobReward struct {
+	Weight  float64            `json:"weight"`            // Relative chance of this entry
+	Effects map[string]float64 `json:"effects,omitempty"` // Bonus stat changes
+	Item    string             `json:"item,omitempty"`    // Item added to the inventory
+	Message string             `json:"message,omitempty"` // Appended to the completion response
+}
+
+// ActiveJob is the job the character is currently working on
+type ActiveJob struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+// JobResult describes a finished job for UI notifications
+type JobResult struct {
+	JobID    string
+	Name     string
+	Response string
+	Effects  map[string]float64
+	Item     string
+}
+
+// JobStatus describes a job for menus
+type JobStatus struct {
+	ID          string
+	Name        string
+	Description string
+	Duration    time.Duration
+	Available   bool
+}
+
+// GetDisplayName returns the job name, falling back to its key
+func (j JobConfig) GetDisplayName(id string) string {
+	if j.Name != "" {
+		return j.Name
+	}
+	return id
+}
+
+// validateJobs validates the optional jobs section
+func (c *CharacterCard) validateJobs() error {
+	if len(c.Jobs) == 0 {
+		return nil
+	}
+
+	if !c.HasGameFeatures() {
+		return fmt.Errorf("jobs require stats to be defined")
+	}
+
+	for id, job := range c.Jobs {
+		if err := c.validateJob(job); err != nil {
+			return fmt.Errorf("job '%s': %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// validateJob checks durations, animations and stat references of a single job
+func (c *CharacterCard) validateJob(job JobConfig) error {
+	if job.Duration <= 0 {
+		return fmt.Errorf("duration must be positive, got %d", job.Duration)
+	}
+
+	if job.Animation != "" {
+		if _, exists := c.Animations[job.Animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", job.Animation)
+		}
+	}
+
+	if job.Condition != "" {
+		if err := validateConditionIdentifiers(job.Condition, c.Stats); err != nil {
+			return err
+		}
+	}
+
+	for _, effects := range []map[string]float64{job.Cost, job.Rewards} {
+		if err := c.validateJobStats(effects); err != nil {
+			return err
+		}
+	}
+	for stat := range job.Requirements {
+		if _, exists := c.Stats[stat]; !exists {
+			return fmt.Errorf("requirement references unknown stat '%s'", stat)
+		}
+	}
+
+	for i, reward := range job.RewardTable {
+		if reward.Weight <= 0 {
+			return fmt.Errorf("reward %d: weight must be positive", i)
+		}
+		if err := c.validateJobStats(reward.Effects); err != nil {
+			return fmt.Errorf("reward %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateJobStats ensures every stat in an effect map exists.
+// The currency key is always allowed.
+func (c *CharacterCard) validateJobStats(effects map[string]float64) error {
+	for stat := range effects {
+		if _, exists := c.Stats[stat]; !exists && stat != CurrencyKey {
+			return fmt.Errorf("unknown stat '%s'", stat)
+		}
+	}
+	return nil
+}
+
+// pickJobReward draws one entry from the reward table by weight.
+// Returns nil for an empty table.
+func pickJobReward(table []JobReward, roll float64) *JobReward {
+	total := 0.0
+	for _, reward := range table {
+		total += reward.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	target := roll * total
+	for i := range table {
+		target -= table[i].Weight
+		if target < 0 {
+			return &table[i]
+		}
+	}
+	return &table[len(table)-1]
+}
+
+// GetJobs lists the card's jobs and whether each can be started now, sorted by name
+func (c *Character) GetJobs() []JobStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.gameState == nil || len(c.card.Jobs) == 0 {
+		return nil
+	}
+
+	busy := c.gameState.GetActiveJob() != nil
+	jobs := make([]JobStatus, 0, len(c.card.Jobs))
+	for id, job := range c.card.Jobs {
+		jobs = append(jobs, JobStatus{
+			ID:          id,
+			Name:        job.GetDisplayName(id),
+			Description: job.Description,
+			Duration:    time.Duration(job.Duration) * time.Second,
+			Available:   !busy && c.canStartJob(job),
+		})
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// canStartJob checks the job's requirements. Must be called with c.mu held.
+func (c *Character) canStartJob(job JobConfig) bool {
+	return c.gameState.CanSatisfyRequirements(job.Requirements) &&
+		c.gameState.EvaluateCondition(job.Condition)
+}
+
+// StartJob assigns a job to the character and returns the start response
+func (c *Character) StartJob(id string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gameState == nil {
+		return "", fmt.Errorf("jobs require game mode")
+	}
+
+	job, exists := c.card.Jobs[id]
+	if !exists {
+		return "", fmt.Errorf("unknown job '%s'", id)
+	}
+
+	if active := c.gameState.GetActiveJob(); active != nil {
+		return "", fmt.Errorf("already working on '%s'", active.ID)
+	}
+
+	if !c.canStartJob(job) {
+		return "", fmt.Errorf("requirements for '%s' not met", job.GetDisplayName(id))
+	}
+
+	now := time.Now()
+	c.gameState.ApplyInteractionEffects(job.Cost)
+	c.gameState.SetActiveJob(&ActiveJob{
+		ID:        id,
+		StartedAt: now,
+		EndsAt:    now.Add(time.Duration(job.Duration) * time.Second),
+	})
+	c.gameState.RecordInteraction("job:" + id)
+	c.lastInteraction = now
+
+	if animation, ok := c.jobAnimation(); ok {
+		c.setState(animation)
+	}
+
+	return selectJobResponse(job.StartResponses, fmt.Sprintf("Off to %s!", job.GetDisplayName(id))), nil
+}
+
+// CancelJob abandons the current job without rewards. Costs are not refunded.
+func (c *Character) CancelJob() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gameState == nil || c.gameState.GetActiveJob() == nil {
+		return false
+	}
+
+	c.gameState.SetActiveJob(nil)
+	c.setState(c.selectIdleAnimation())
+	return true
+}
+
+// GetActiveJob returns the current job and its remaining time, or nil when idle
+func (c *Character) GetActiveJob() (*ActiveJob, time.Duration) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.gameState == nil {
+		return nil, 0
+	}
+
+	active := c.gameState.GetActiveJob()
+	if active == nil {
+		return nil, 0
+	}
+
+	remaining := time.Until(active.EndsAt)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return active, remaining
+}
+
+// GetCompletedJobs returns jobs finished since the last call and clears the list
+func (c *Character) GetCompletedJobs() []JobResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := c.completedJobs
+	c.completedJobs = nil
+	return results
+}
+
+// jobAnimation returns the animation to hold while a job runs.
+// Must be called with c.mu held.
+func (c *Character) jobAnimation() (string, bool) {
+	if c.gameState == nil {
+		return "", false
+	}
+
+	active := c.gameState.GetActiveJob()
+	if active == nil {
+		return "", false
+	}
+
+	job, exists := c.card.Jobs[active.ID]
+	if !exists || job.Animation == "" {
+		return "", false
+	}
+	if _, exists := c.card.Animations[job.Animation]; !exists {
+		return "", false
+	}
+	return job.Animation, true
+}
+
+// updateJobs finishes the active job once its timer runs out.
+// Must be called with c.mu held. Returns true if the animation state changed.
+func (c *Character) updateJobs(now time.Time) bool {
+	if c.gameState == nil {
+		return false
+	}
+
+	active := c.gameState.GetActiveJob()
+	if active == nil || now.Before(active.EndsAt) {
+		return false
+	}
+
+	c.gameState.SetActiveJob(nil)
+
+	job, exists := c.card.Jobs[active.ID]
+	if !exists {
+		// Job removed from the card while it was running
+		return false
+	}
+
+	c.completedJobs = append(c.completedJobs, c.completeJob(active.ID, job))
+
+	previousState := c.currentState
+	c.setState(c.selectIdleAnimation())
+	return c.currentState != previousState
+}
+
+// completeJob applies rewards and builds the result. Must be called with c.mu held.
+func (c *Character) completeJob(id string, job JobConfig) JobResult {
+	result := JobResult{
+		JobID:    id,
+		Name:     job.GetDisplayName(id),
+		Response: selectJobResponse(job.CompleteResponses, fmt.Sprintf("Finished %s!", job.GetDisplayName(id))),
+		Effects:  make(map[string]float64),
+	}
+
+	for stat, value := range job.Rewards {
+		result.Effects[stat] += value
+	}
+
+	if bonus := pickJobReward(job.RewardTable, rand.Float64()); bonus != nil {
+		for stat, value := range bonus.Effects {
+			result.Effects[stat] += value
+		}
+		if bonus.Item != "" {
+			c.gameState.AddItem(bonus.Item, 1)
+			result.Item = bonus.Item
+		}
+		if bonus.Message != "" {
+			result.Response += " " + bonus.Message
+		}
+	}
+
+	c.gameState.ApplyInteractionEffects(result.Effects)
+	return result
+}
+
+// selectJobResponse picks a random response or the fallback
+func selectJobResponse(responses []string, fallback string) string {
+	if len(responses) == 0 {
+		return fallback
+	}
+	return responses[rand.Intn(len(responses))]
+}
+
+// GetActiveJob returns the job in progress, or nil
+func (gs *GameState) GetActiveJob() *ActiveJob {
+	if gs == nil {
+		return nil
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.ActiveJob
+}
+
+// SetActiveJob records the job in progress; nil clears it
+func (gs *GameState) SetActiveJob(job *ActiveJob) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.ActiveJob = job
+}
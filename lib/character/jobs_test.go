@@ -0,0 +1,246 @@
+package character
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+)
+
+func createTestJobCard() *CharacterCard {
+	card := createTestGameCharacterCard()
+	card.Jobs = map[string]JobConfig{
+		"study": {
+			Name:              "Study",
+			Description:       "Hit the books",
+			Duration:          60,
+			Animation:         "talking",
+			Requirements:      map[string]map[string]float64{"energy": {"min": 30}},
+			Cost:              map[string]float64{"energy": -20},
+			Rewards:           map[string]float64{"happiness": -5},
+			RewardTable:       []JobReward{{Weight: 1, Item: "gold_star", Message: "Got a gold star!"}},
+			CompleteResponses: []string{"Done studying."},
+		},
+		"nap": {
+			Duration:     30,
+			Requirements: map[string]map[string]float64{"energy": {"max": 10}},
+		},
+	}
+	return card
+}
+
+func TestJobLifecycle(t *testing.T) {
+	char := createTestCharacterInstance(createTestJobCard(), true)
+
+	response, err := char.StartJob("study")
+	if err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+	if response != "Off to Study!" {
+		t.Errorf("Expected fallback start response, got %q", response)
+	}
+	if energy := char.GetGameState().GetStat("energy"); energy != 80 {
+		t.Errorf("Expected job cost to reduce energy to 80, got %f", energy)
+	}
+
+	if _, err := char.StartJob("study"); err == nil || !strings.Contains(err.Error(), "already working") {
+		t.Errorf("Expected busy error, got %v", err)
+	}
+
+	active, remaining := char.GetActiveJob()
+	if active == nil || active.ID != "study" || remaining <= 0 {
+		t.Fatalf("Expected active study job, got %+v (%v)", active, remaining)
+	}
+
+	// Timer not finished yet
+	char.mu.Lock()
+	char.updateJobs(time.Now())
+	char.mu.Unlock()
+	if results := char.GetCompletedJobs(); len(results) != 0 {
+		t.Fatalf("Job should not complete early, got %v", results)
+	}
+
+	char.mu.Lock()
+	char.updateJobs(active.EndsAt.Add(time.Second))
+	char.mu.Unlock()
+
+	results := char.GetCompletedJobs()
+	if len(results) != 1 {
+		t.Fatalf("Expected one completed job, got %d", len(results))
+	}
+	result := results[0]
+	if result.Response != "Done studying. Got a gold star!" || result.Item != "gold_star" {
+		t.Errorf("Unexpected job result: %+v", result)
+	}
+	if happiness := char.GetGameState().GetStat("happiness"); happiness != 95 {
+		t.Errorf("Expected reward to set happiness to 95, got %f", happiness)
+	}
+	if count := char.GetGameState().GetInventory()["gold_star"]; count != 1 {
+		t.Errorf("Expected one gold_star in inventory, got %d", count)
+	}
+	if active, _ := char.GetActiveJob(); active != nil {
+		t.Error("Active job should be cleared after completion")
+	}
+	if results := char.GetCompletedJobs(); len(results) != 0 {
+		t.Error("Completed jobs should be cleared after retrieval")
+	}
+}
+
+func TestJobRequirementsAndCancel(t *testing.T) {
+	char := createTestCharacterInstance(createTestJobCard(), true)
+
+	if _, err := char.StartJob("nap"); err == nil {
+		t.Error("Nap should require low energy")
+	}
+	if _, err := char.StartJob("unknown"); err == nil {
+		t.Error("Unknown job should fail")
+	}
+
+	jobs := char.GetJobs()
+	if len(jobs) != 2 || jobs[0].Name != "Study" || !jobs[0].Available || jobs[1].Available {
+		t.Errorf("Unexpected job list: %+v", jobs)
+	}
+
+	if _, err := char.StartJob("study"); err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+	if !char.CancelJob() {
+		t.Error("CancelJob should succeed while working")
+	}
+	if char.CancelJob() {
+		t.Error("CancelJob should fail when idle")
+	}
+}
+
+func TestJobCompletesOnUpdate(t *testing.T) {
+	char := createTestCharacterInstance(createTestJobCard(), true)
+
+	if _, err := char.StartJob("study"); err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+
+	// Pretend the timer ran out while the companion was running
+	past := time.Now().Add(-time.Minute)
+	char.GetGameState().SetActiveJob(&ActiveJob{ID: "study", StartedAt: past.Add(-time.Minute), EndsAt: past})
+	char.Update()
+
+	results := char.GetCompletedJobs()
+	if len(results) != 1 || results[0].JobID != "study" {
+		t.Fatalf("Expected Update to finish the study job, got %+v", results)
+	}
+	if results[0].Effects["happiness"] != -5 {
+		t.Errorf("Expected the happiness reward in the result, got %v", results[0].Effects)
+	}
+	if count := char.GetGameState().GetInventory()["gold_star"]; count != 1 {
+		t.Errorf("Expected the reward item in the inventory, got %d", count)
+	}
+
+	if _, err := char.StartJob("study"); err != nil {
+		t.Errorf("A new job should start once the last one finished: %v", err)
+	}
+}
+
+func TestCancelledJobPaysNothing(t *testing.T) {
+	char := createTestCharacterInstance(createTestJobCard(), true)
+
+	if _, err := char.StartJob("study"); err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+	active, _ := char.GetActiveJob()
+	char.CancelJob()
+
+	char.mu.Lock()
+	char.updateJobs(active.EndsAt.Add(time.Second))
+	char.mu.Unlock()
+
+	if results := char.GetCompletedJobs(); len(results) != 0 {
+		t.Errorf("Cancelled job should not complete, got %+v", results)
+	}
+	if energy := char.GetGameState().GetStat("energy"); energy != 80 {
+		t.Errorf("Cancelling should keep the cost paid, energy = %f", energy)
+	}
+	if happiness := char.GetGameState().GetStat("happiness"); happiness != 100 {
+		t.Errorf("Cancelling should not pay rewards, happiness = %f", happiness)
+	}
+	if count := char.GetGameState().GetInventory()["gold_star"]; count != 0 {
+		t.Errorf("Cancelling should not grant items, got %d", count)
+	}
+}
+
+func TestActiveJobSurvivesSave(t *testing.T) {
+	char := createTestCharacterInstance(createTestJobCard(), true)
+
+	if _, err := char.StartJob("study"); err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+
+	manager := persistence.NewSaveManager(t.TempDir())
+	defer manager.Close()
+	if err := manager.SaveGameState(char.GetName(), char.SaveData()); err != nil {
+		t.Fatalf("SaveGameState failed: %v", err)
+	}
+	loaded, err := manager.LoadGameState(char.GetName())
+	if err != nil {
+		t.Fatalf("LoadGameState failed: %v", err)
+	}
+
+	fresh := createTestCharacterInstance(createTestJobCard(), true)
+	fresh.RestoreSaveData(loaded)
+
+	active, _ := char.GetActiveJob()
+	restored, _ := fresh.GetActiveJob()
+	if restored == nil || restored.ID != "study" || !restored.EndsAt.Equal(active.EndsAt) {
+		t.Fatalf("Expected the running job to be restored, got %+v", restored)
+	}
+	if _, err := fresh.StartJob("study"); err == nil {
+		t.Error("The restored job should still block a second one")
+	}
+}
+
+func TestPickJobReward(t *testing.T) {
+	table := []JobReward{{Weight: 1, Item: "common"}, {Weight: 3, Item: "rare"}}
+
+	if reward := pickJobReward(table, 0.1); reward.Item != "common" {
+		t.Errorf("Roll 0.1 should pick common, got %s", reward.Item)
+	}
+	if reward := pickJobReward(table, 0.5); reward.Item != "rare" {
+		t.Errorf("Roll 0.5 should pick rare, got %s", reward.Item)
+	}
+	if reward := pickJobReward(nil, 0.5); reward != nil {
+		t.Error("Empty table should return nil")
+	}
+}
+
+func TestValidateJobs(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     JobConfig
+		wantErr string
+	}{
+		{"valid", JobConfig{Duration: 10, Rewards: map[string]float64{"hunger": 5}}, ""},
+		{"zero duration", JobConfig{Duration: 0}, "duration"},
+		{"unknown animation", JobConfig{Duration: 10, Animation: "working"}, "not found"},
+		{"unknown reward stat", JobConfig{Duration: 10, Rewards: map[string]float64{"mana": 5}}, "unknown stat"},
+		{"coin reward", JobConfig{Duration: 10, Rewards: map[string]float64{"coins": 5}}, ""},
+		{"bad weight", JobConfig{Duration: 10, RewardTable: []JobReward{{Weight: 0}}}, "weight"},
+		{"bad condition", JobConfig{Duration: 10, Condition: "mana > 1"}, "unknown stat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Jobs = map[string]JobConfig{"job": tt.job}
+			err := card.validateJobs()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package character
+
+import (
+	"time"
+)
+
+// Journal entry kinds
+const (
+	JournalEvent       = "event"       // A random event fired
+	JournalAchievement = "achievement" // An achievement was earned
+	JournalLevelUp     = "level_up"    // The character grew or the relationship deepened
+	JournalBattle      = "battle"      // A battle ended
+	JournalGift        = "gift"        // A gift was received
+)
+
+// maxJournalEntries bounds the journal; older entries are dropped first
+const maxJournalEntries = 200
+
+// JournalEntry is one notable happening in the character's life
+type JournalEntry struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Title  string    `json:"title"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// AddJournalEntry records a happening in the journal
+func (gs *GameState) AddJournalEntry(kind, title, detail string) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.addJournalEntry(kind, title, detail, time.Now())
+}
+
+// addJournalEntry appends an entry and drops the oldest past the limit.
+// Must be called with gs.mu held.
+func (gs *GameState) addJournalEntry(kind, title, detail string, at time.Time) {
+	gs.Journal = append(gs.Journal, JournalEntry{Time: at, Kind: kind, Title: title, Detail: detail})
+	if len(gs.Journal) > maxJournalEntries {
+		gs.Journal = append([]JournalEntry(nil), gs.Journal[len(gs.Journal)-maxJournalEntries:]...)
+	}
+}
+
+// GetJournal returns the journal, newest entry first
+func (gs *GameState) GetJournal() []JournalEntry {
+	if gs == nil {
+		return nil
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	journal := make([]JournalEntry, len(gs.Journal))
+	for i, entry := range gs.Journal {
+		journal[len(gs.Journal)-1-i] = entry
+	}
+	return journal
+}
+
+// GetJournal returns the character's journal, newest entry first. It is
+// empty when game features are off.
+func (c *Character) GetJournal() []JournalEntry {
+	return c.GetGameState().GetJournal()
+}
@@ -0,0 +1,78 @@
+package character
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestJournalBoundedNewestFirst(t *testing.T) {
+	gs := NewGameState(map[string]StatConfig{"hunger": {Initial: 50, Max: 100}}, nil)
+	for i := 0; i < maxJournalEntries+5; i++ {
+		gs.AddJournalEntry(JournalEvent, fmt.Sprintf("event %d", i), "")
+	}
+
+	journal := gs.GetJournal()
+	if len(journal) != maxJournalEntries {
+		t.Fatalf("Expected the journal capped at %d, got %d", maxJournalEntries, len(journal))
+	}
+	if journal[0].Title != fmt.Sprintf("event %d", maxJournalEntries+4) {
+		t.Errorf("Expected the newest entry first, got %q", journal[0].Title)
+	}
+	if journal[len(journal)-1].Title != "event 5" {
+		t.Errorf("Expected the oldest entries dropped, got %q last", journal[len(journal)-1].Title)
+	}
+
+	var nilState *GameState
+	nilState.AddJournalEntry(JournalEvent, "ignored", "")
+	if nilState.GetJournal() != nil {
+		t.Error("A nil game state has no journal")
+	}
+}
+
+func TestJournalRecordsHappenings(t *testing.T) {
+	card := createTestGameCharacterCard()
+	char := createTestCharacterInstance(card, true)
+
+	char.handleTriggeredEvent(&TriggeredEvent{Name: "rainy_day", Description: "It rained all day"})
+	char.gameState.AddJournalEntry(JournalGift, "Received Flowers", "")
+
+	journal := char.GetJournal()
+	if len(journal) != 2 {
+		t.Fatalf("Expected 2 journal entries, got %+v", journal)
+	}
+	if journal[1].Kind != JournalEvent || journal[1].Title != "rainy_day" || journal[1].Detail != "It rained all day" {
+		t.Errorf("Expected the random event in the journal, got %+v", journal[1])
+	}
+}
+
+func TestBattleOutcome(t *testing.T) {
+	tests := map[string]string{
+		"":      "Battle ended in a draw",
+		"me":    "Won a battle",
+		"rival": "Lost a battle to rival",
+	}
+	for winner, want := range tests {
+		if got := battleOutcome(winner, "me"); got != want {
+			t.Errorf("battleOutcome(%q) = %q, want %q", winner, got, want)
+		}
+	}
+}
+
+func TestJournalSurvivesSave(t *testing.T) {
+	card := createTestGameCharacterCard()
+	char := createTestCharacterInstance(card, true)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	char.gameState.mu.Lock()
+	char.gameState.addJournalEntry(JournalAchievement, "First Steps", "Played for an hour", at)
+	char.gameState.mu.Unlock()
+
+	data := char.SaveData()
+	restored := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	restored.RestoreSaveData(data)
+
+	journal := restored.GetJournal()
+	if len(journal) != 1 || journal[0].Title != "First Steps" || !journal[0].Time.Equal(at) {
+		t.Errorf("Expected the journal restored from the save, got %+v", journal)
+	}
+}
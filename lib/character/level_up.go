@@ -0,0 +1,153 @@
+package character
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AnimationLevelUp is played during a level-up ceremony when the card has it
+const AnimationLevelUp = "level_up"
+
+// LevelUpCeremony customizes the celebration when the relationship reaches
+// a level. Levels without one still get a ceremony with the defaults.
+type LevelUpCeremony struct {
+	Disabled  bool     `json:"disabled,omitempty"`  // Reach the level quietly, as before ceremonies
+	Dialog    []string `json:"dialog,omitempty"`    // Said one after another (default: "We're <level> now! 💕")
+	Animation string   `json:"animation,omitempty"` // Played during the ceremony (default: "level_up", then "happy", if present)
+	Unlocks   []string `json:"unlocks,omitempty"`   // Extra lines for the unlock summary, e.g. "Movie dates"
+	Memory    string   `json:"memory,omitempty"`    // Written to romance memories (default: "We became <level>")
+}
+
+// LevelUp describes a relationship level-up for the UI to celebrate
+type LevelUp struct {
+	From     string
+	To       string
+	Dialog   []string
+	Unlocked []string // Interactions and dialogs available since this level, plus the card's extra lines
+	At       time.Time
+}
+
+// validateLevelUpCeremony checks a level's ceremony against the card's animations
+func (c *CharacterCard) validateLevelUpCeremony(level LevelConfig) error {
+	ceremony := level.Ceremony
+	if ceremony == nil || ceremony.Animation == "" {
+		return nil
+	}
+	if _, exists := c.Animations[ceremony.Animation]; !exists {
+		return fmt.Errorf("ceremony animation '%s' not found in animations map", ceremony.Animation)
+	}
+	return nil
+}
+
+// levelCeremony returns the ceremony for the named level, nil when the
+// level has none configured
+func (c *CharacterCard) levelCeremony(name string) *LevelUpCeremony {
+	if i := c.levelIndex(name); i >= 0 {
+		return c.Progression.Levels[i].Ceremony
+	}
+	return nil
+}
+
+// levelIndex returns the position of the named level in the progression,
+// -1 for "Stranger" or a level the card doesn't have
+func (c *CharacterCard) levelIndex(name string) int {
+	if c.Progression != nil {
+		for i, level := range c.Progression.Levels {
+			if level.Name == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// levelUnlocks returns what the current relationship level makes available:
+// interactions and romance dialogs that require a relationship level
+func (c *Character) levelUnlocks() map[string]bool {
+	unlocks := make(map[string]bool)
+	level := c.gameState.GetRelationshipLevel()
+
+	for name, interaction := range c.card.Interactions {
+		conditions, gated := interaction.Requirements["relationshipLevel"]
+		if gated && c.gameState.checkRelationshipLevelConditions(conditions) {
+			unlocks[fmt.Sprintf("New interaction: %s", name)] = true
+		}
+	}
+	for _, dialog := range c.card.RomanceDialogs {
+		if dialog.Requirements != nil && dialog.Requirements.RelationshipLevel == level {
+			unlocks[fmt.Sprintf("New %s dialog", dialog.Trigger)] = true
+		}
+	}
+	return unlocks
+}
+
+// celebrateLevelUp plays the ceremony for reaching the current level:
+// queues it for the UI, plays its animation and writes a romance memory and
+// a journal entry. before is what levelUnlocks returned at the previous
+// level. Must be called with c.mu held.
+func (c *Character) celebrateLevelUp(from string, before map[string]bool) {
+	to := c.gameState.GetRelationshipLevel()
+	c.gameState.AddJournalEntry(JournalLevelUp, fmt.Sprintf("Became %s", to), fmt.Sprintf("Was %s", from))
+	ceremony := c.card.levelCeremony(to)
+	if ceremony != nil && ceremony.Disabled {
+		c.setState(AnimationLevelUp)
+		return
+	}
+	if ceremony == nil {
+		ceremony = &LevelUpCeremony{}
+	}
+
+	var unlocked []string
+	for unlock := range c.levelUnlocks() {
+		if !before[unlock] {
+			unlocked = append(unlocked, unlock)
+		}
+	}
+	sort.Strings(unlocked)
+	unlocked = append(unlocked, ceremony.Unlocks...)
+
+	dialog := ceremony.Dialog
+	if len(dialog) == 0 {
+		dialog = []string{fmt.Sprintf("We're %s now! 💕", to)}
+	}
+
+	c.setState(c.ceremonyAnimation(ceremony))
+
+	memory := ceremony.Memory
+	if memory == "" {
+		memory = fmt.Sprintf("We became %s", to)
+	}
+	stats := c.gameState.GetStats()
+	c.gameState.RecordRomanceInteraction("level_up", memory, stats, stats)
+
+	c.levelUps = append(c.levelUps, LevelUp{
+		From:     from,
+		To:       to,
+		Dialog:   dialog,
+		Unlocked: unlocked,
+		At:       time.Now(),
+	})
+}
+
+// ceremonyAnimation returns the animation to play for a ceremony, falling
+// back to "level_up" and then "happy". Must be called with c.mu held.
+func (c *Character) ceremonyAnimation(ceremony *LevelUpCeremony) string {
+	if ceremony.Animation != "" {
+		return ceremony.Animation
+	}
+	if _, exists := c.card.Animations[AnimationLevelUp]; exists {
+		return AnimationLevelUp
+	}
+	return "happy"
+}
+
+// GetLevelUps returns level-ups since the last call and clears the list
+func (c *Character) GetLevelUps() []LevelUp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	levelUps := c.levelUps
+	c.levelUps = nil
+	return levelUps
+}
@@ -0,0 +1,129 @@
+package character
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createTestLevelUpCharacter(t *testing.T) *Character {
+	t.Helper()
+
+	card := createTestGameCharacterCard()
+	card.Stats["affection"] = StatConfig{Initial: 10, Max: 100}
+	card.Interactions = map[string]InteractionConfig{
+		"kiss": {
+			Triggers:     []string{"ctrl+click"},
+			Responses:    []string{"💋"},
+			Requirements: map[string]map[string]float64{"relationshipLevel": {"min": 2}},
+		},
+	}
+	card.Progression = &ProgressionConfig{
+		Levels: []LevelConfig{
+			{Name: "Friend", Requirement: map[string]int64{"affection": 0}, Size: 128},
+			{
+				Name: "Close Friend", Requirement: map[string]int64{"affection": 50}, Size: 128,
+				Ceremony: &LevelUpCeremony{
+					Dialog:    []string{"I feel so close to you!", "Let's do more together 💕"},
+					Animation: "happy",
+					Unlocks:   []string{"Movie dates"},
+					Memory:    "The day we became close",
+				},
+			},
+		},
+	}
+	if err := card.validateProgression(); err != nil {
+		t.Fatalf("validateProgression failed: %v", err)
+	}
+	return createTestCharacterInstance(card, true)
+}
+
+func TestLevelUpCeremony(t *testing.T) {
+	char := createTestLevelUpCharacter(t)
+
+	char.mu.Lock()
+	char.updateRelationshipProgression()
+	char.mu.Unlock()
+
+	levelUps := char.GetLevelUps()
+	if len(levelUps) != 1 {
+		t.Fatalf("Expected one level-up, got %+v", levelUps)
+	}
+	if up := levelUps[0]; up.From != "Stranger" || up.To != "Friend" || !reflect.DeepEqual(up.Dialog, []string{"We're Friend now! 💕"}) {
+		t.Errorf("Expected the default ceremony for Friend, got %+v", up)
+	}
+	if len(levelUps[0].Unlocked) != 0 {
+		t.Errorf("Friend unlocks nothing, got %v", levelUps[0].Unlocked)
+	}
+
+	char.gameState.Stats["affection"].Current = 60
+	char.mu.Lock()
+	char.updateRelationshipProgression()
+	char.mu.Unlock()
+
+	levelUps = char.GetLevelUps()
+	if len(levelUps) != 1 {
+		t.Fatalf("Expected one level-up, got %+v", levelUps)
+	}
+	up := levelUps[0]
+	if up.From != "Friend" || up.To != "Close Friend" || len(up.Dialog) != 2 {
+		t.Errorf("Expected the configured ceremony, got %+v", up)
+	}
+	if want := []string{"New interaction: kiss", "Movie dates"}; !reflect.DeepEqual(up.Unlocked, want) {
+		t.Errorf("Expected unlocks %v, got %v", want, up.Unlocked)
+	}
+
+	memories := char.gameState.RomanceMemories
+	if len(memories) == 0 || memories[len(memories)-1].Response != "The day we became close" {
+		t.Errorf("Expected the ceremony in romance memories, got %+v", memories)
+	}
+
+	if len(char.GetLevelUps()) != 0 {
+		t.Error("Level-ups should be cleared once read")
+	}
+}
+
+func TestLevelDownIsNotCelebrated(t *testing.T) {
+	char := createTestLevelUpCharacter(t)
+	char.gameState.Stats["affection"].Current = 60
+	char.mu.Lock()
+	char.updateRelationshipProgression()
+	char.mu.Unlock()
+	char.GetLevelUps()
+
+	char.gameState.Stats["affection"].Current = 10
+	char.mu.Lock()
+	char.updateRelationshipProgression()
+	char.mu.Unlock()
+
+	if got := char.gameState.GetRelationshipLevel(); got != "Friend" {
+		t.Fatalf("Expected to drop back to Friend, got %q", got)
+	}
+	if levelUps := char.GetLevelUps(); len(levelUps) != 0 {
+		t.Errorf("Dropping a level is no ceremony, got %+v", levelUps)
+	}
+}
+
+func TestLevelUpCeremonyDisabled(t *testing.T) {
+	char := createTestLevelUpCharacter(t)
+	char.card.Progression.Levels[0].Ceremony = &LevelUpCeremony{Disabled: true}
+
+	char.mu.Lock()
+	char.updateRelationshipProgression()
+	char.mu.Unlock()
+
+	if levelUps := char.GetLevelUps(); len(levelUps) != 0 {
+		t.Errorf("A disabled ceremony should stay quiet, got %+v", levelUps)
+	}
+}
+
+func TestValidateLevelUpCeremony(t *testing.T) {
+	card := createTestGameCharacterCard()
+	level := LevelConfig{Name: "Friend", Size: 128, Ceremony: &LevelUpCeremony{Animation: "missing"}}
+	if err := card.validateLevelUpCeremony(level); err == nil {
+		t.Error("Expected an error for an unknown ceremony animation")
+	}
+	level.Ceremony.Animation = "happy"
+	if err := card.validateLevelUpCeremony(level); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package character
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Lint severities, from most to least serious
+const (
+	LintWarning = "warning" // Probably a mistake: content that never shows or misbehaves
+	LintInfo    = "info"    // Worth a look, but may be intended
+)
+
+// lintMaxGIFSize is the largest animation file before it is flagged; the
+// README recommends keeping GIFs under 1MB
+const lintMaxGIFSize = 1 << 20
+
+// LintFinding is a best-practice issue in a card that passed validation
+type LintFinding struct {
+	Severity string `json:"severity"` // LintWarning or LintInfo
+	Rule     string `json:"rule"`     // e.g. "duplicate-response"
+	Path     string `json:"path"`     // Where in the card, e.g. "dialogs[0].responses[2]"
+	Message  string `json:"message"`
+}
+
+// String formats the finding as "severity rule path: message"
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s %s %s: %s", f.Severity, f.Rule, f.Path, f.Message)
+}
+
+// Lint looks for problems that validation allows but players would notice:
+// repeated responses, events that can never fire, mood preferences naming
+// missing animations, oversized GIFs and zero cooldowns. basePath is the
+// card's directory for checking GIF sizes, empty to skip them. Findings are
+// sorted by path.
+func (c *CharacterCard) Lint(basePath string) []LintFinding {
+	l := &cardLinter{card: c}
+	l.lintDialogs()
+	l.lintInteractions()
+	l.lintEvents("randomEvents", c.RandomEvents)
+	l.lintEvents("romanceEvents", c.RomanceEvents)
+	l.lintRomanceDialogs()
+	l.lintMoodPreferences()
+	if basePath != "" {
+		l.lintGIFSizes(basePath)
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Path < l.findings[j].Path
+	})
+	return l.findings
+}
+
+// cardLinter collects findings for one card
+type cardLinter struct {
+	card     *CharacterCard
+	findings []LintFinding
+}
+
+// add records a finding
+func (l *cardLinter) add(severity, rule, path, format string, args ...interface{}) {
+	l.findings = append(l.findings, LintFinding{
+		Severity: severity,
+		Rule:     rule,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lintResponses flags responses repeated within one list
+func (l *cardLinter) lintResponses(path string, responses []string) {
+	seen := make(map[string]int, len(responses))
+	for i, response := range responses {
+		key := strings.TrimSpace(response)
+		if first, dup := seen[key]; dup {
+			l.add(LintWarning, "duplicate-response", fmt.Sprintf("%s.responses[%d]", path, i),
+				"same as responses[%d], so it comes up twice as often", first)
+			continue
+		}
+		seen[key] = i
+	}
+}
+
+// lintDialogs flags repeated responses and dialogs that say exactly what an
+// earlier dialog on the same trigger says
+func (l *cardLinter) lintDialogs() {
+	seen := make(map[string]int)
+	for i, dialog := range l.card.Dialogs {
+		path := fmt.Sprintf("dialogs[%d]", i)
+		l.lintResponses(path, dialog.Responses)
+
+		key := dialog.Trigger + "\x00" + strings.Join(sortedCopy(dialog.Responses), "\x00")
+		if first, dup := seen[key]; dup {
+			l.add(LintInfo, "identical-dialogs", path,
+				"has the same %s responses as dialogs[%d]", dialog.Trigger, first)
+			continue
+		}
+		seen[key] = i
+	}
+}
+
+// lintInteractions flags repeated responses and interactions without a cooldown
+func (l *cardLinter) lintInteractions() {
+	for _, name := range sortedKeys(l.card.Interactions) {
+		interaction := l.card.Interactions[name]
+		path := "interactions." + name
+		l.lintResponses(path, interaction.Responses)
+		if interaction.Cooldown == 0 && len(interaction.Effects) > 0 {
+			l.add(LintWarning, "zero-cooldown", path+".cooldown",
+				"no cooldown, so its effects can be applied as fast as the user clicks")
+		}
+	}
+}
+
+// lintEvents flags repeated responses, zero cooldowns and events whose
+// probability or stat conditions mean they never fire
+func (l *cardLinter) lintEvents(section string, events []RandomEventConfig) {
+	for i, event := range events {
+		path := fmt.Sprintf("%s[%d]", section, i)
+		l.lintResponses(path, event.Responses)
+
+		if event.Probability <= 0 {
+			l.add(LintWarning, "unreachable-event", path+".probability", "probability is 0, so %q never fires", event.Name)
+		} else if event.Cooldown == 0 {
+			l.add(LintWarning, "zero-cooldown", path+".cooldown", "%q can fire on every check", event.Name)
+		}
+		l.lintStatRanges(path+".conditions", event.Conditions)
+	}
+}
+
+// lintRomanceDialogs flags repeated responses and stat requirements no
+// value can meet
+func (l *cardLinter) lintRomanceDialogs() {
+	for i, dialog := range l.card.RomanceDialogs {
+		path := fmt.Sprintf("romanceDialogs[%d]", i)
+		l.lintResponses(path, dialog.Responses)
+		if dialog.Requirements != nil {
+			l.lintStatRanges(path+".requirements.stats", dialog.Requirements.Stats)
+		}
+	}
+}
+
+// lintStatRanges flags min/max conditions no value of the stat satisfies
+func (l *cardLinter) lintStatRanges(path string, conditions map[string]map[string]float64) {
+	for _, statName := range sortedKeys(conditions) {
+		stat, isStat := l.card.Stats[statName]
+		if !isStat {
+			continue // Special romance conditions have their own scales
+		}
+		bounds := conditions[statName]
+		min, hasMin := bounds["min"]
+		max, hasMax := bounds["max"]
+		statPath := path + "." + statName
+
+		switch {
+		case hasMin && hasMax && min > max:
+			l.add(LintWarning, "unreachable-condition", statPath, "min %g is above max %g", min, max)
+		case hasMin && stat.Max > 0 && min > stat.Max:
+			l.add(LintWarning, "unreachable-condition", statPath, "min %g is above the stat's max of %g", min, stat.Max)
+		case hasMax && max < 0:
+			l.add(LintWarning, "unreachable-condition", statPath, "max %g is below 0", max)
+		}
+	}
+}
+
+// lintMoodPreferences flags mood preferences naming animations the card lacks
+func (l *cardLinter) lintMoodPreferences() {
+	prefs := l.card.Behavior.MoodAnimationPreferences
+	for _, mood := range sortedKeys(prefs) {
+		for i, animation := range prefs[mood] {
+			if _, exists := l.card.Animations[animation]; !exists {
+				l.add(LintWarning, "missing-mood-animation", fmt.Sprintf("behavior.moodAnimationPreferences.%s[%d]", mood, i),
+					"animation %q is not in animations, so the %s mood falls back to others", animation, mood)
+			}
+		}
+	}
+}
+
+// lintGIFSizes flags animation files larger than lintMaxGIFSize
+func (l *cardLinter) lintGIFSizes(basePath string) {
+	for _, name := range sortedKeys(l.card.Animations) {
+		path := l.card.Animations[name]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(basePath, path)
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.Size() <= lintMaxGIFSize {
+			continue
+		}
+		l.add(LintWarning, "oversized-gif", "animations."+name,
+			"%s is %.1fMB; keep GIFs under 1MB for smooth playback and low memory use",
+			l.card.Animations[name], float64(info.Size())/(1<<20))
+	}
+}
+
+// sortedKeys returns a map's keys in order, for stable findings
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedCopy returns the strings sorted, leaving the original alone
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,84 @@
+package character
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func lintRules(findings []LintFinding) map[string]string {
+	rules := make(map[string]string, len(findings))
+	for _, f := range findings {
+		rules[f.Path] = f.Rule
+	}
+	return rules
+}
+
+func TestLintFindsBestPracticeIssues(t *testing.T) {
+	card := &CharacterCard{
+		Animations: map[string]string{"idle": "idle.gif", "talking": "talking.gif"},
+		Dialogs: []Dialog{
+			{Trigger: "click", Responses: []string{"Hi!", "Hello", "Hi!"}},
+			{Trigger: "click", Responses: []string{"Hello", "Hi!", "Hi!"}},
+			{Trigger: "hover", Responses: []string{"Hello", "Hi!", "Hi!"}},
+		},
+		Behavior: Behavior{MoodAnimationPreferences: map[string][]string{"happy": {"idle", "dancing"}}},
+		Stats:    map[string]StatConfig{"affection": {Max: 100}},
+		Interactions: map[string]InteractionConfig{
+			"pet":  {Effects: map[string]float64{"affection": 1}},
+			"wave": {Cooldown: 10, Effects: map[string]float64{"affection": 1}},
+		},
+		RomanceEvents: []RandomEventConfig{
+			{Name: "confession", Probability: 0.1, Cooldown: 60, Conditions: map[string]map[string]float64{"affection": {"min": 150}}},
+			{Name: "date", Probability: 0, Cooldown: 60, Conditions: map[string]map[string]float64{"affection": {"min": 50, "max": 20}}},
+			{Name: "gift", Probability: 0.1, Conditions: map[string]map[string]float64{"relationshipLevel": {"min": 2}}},
+		},
+	}
+
+	want := map[string]string{
+		"dialogs[0].responses[2]":                    "duplicate-response",
+		"dialogs[1]":                                 "identical-dialogs",
+		"dialogs[1].responses[2]":                    "duplicate-response",
+		"dialogs[2].responses[2]":                    "duplicate-response",
+		"behavior.moodAnimationPreferences.happy[1]": "missing-mood-animation",
+		"interactions.pet.cooldown":                  "zero-cooldown",
+		"romanceEvents[0].conditions.affection":      "unreachable-condition",
+		"romanceEvents[1].probability":               "unreachable-event",
+		"romanceEvents[1].conditions.affection":      "unreachable-condition",
+		"romanceEvents[2].cooldown":                  "zero-cooldown",
+	}
+	got := lintRules(card.Lint(""))
+	for path, rule := range want {
+		if got[path] != rule {
+			t.Errorf("Expected %s at %s, got %q", rule, path, got[path])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d findings, got %v", len(want), got)
+	}
+}
+
+func TestLintFlagsOversizedGIFs(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "small.gif"), make([]byte, 1024), 0o644)
+	os.WriteFile(filepath.Join(dir, "large.gif"), make([]byte, lintMaxGIFSize+1), 0o644)
+	card := &CharacterCard{Animations: map[string]string{"idle": "small.gif", "talking": "large.gif"}}
+
+	findings := card.Lint(dir)
+	if len(findings) != 1 || findings[0].Path != "animations.talking" || findings[0].Rule != "oversized-gif" {
+		t.Errorf("Expected only talking to be oversized, got %v", findings)
+	}
+	if findings := card.Lint(""); len(findings) != 0 {
+		t.Errorf("Expected GIF sizes to be skipped without a base path, got %v", findings)
+	}
+}
+
+func TestLintCleanCard(t *testing.T) {
+	card := &CharacterCard{
+		Animations: map[string]string{"idle": "idle.gif"},
+		Dialogs:    []Dialog{{Trigger: "click", Responses: []string{"Hi!", "Hello"}, Cooldown: 5}},
+	}
+	if findings := card.Lint(""); len(findings) != 0 {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+}
@@ -0,0 +1,282 @@
+package character
+
+// localization.go resolves multi-language character cards. Localizable
+// strings (names, descriptions, dialog text and every *Responses list) may be
+// written as a locale map, e.g. "responses": {"en": [...], "ja": [...]}, and
+// side-by-side files like character.ja.json can override any part of the
+// card. Everything is resolved to plain strings before the card is parsed, so
+// the rest of the package never sees locale maps.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when neither the user nor the card picks one
+const DefaultLanguage = "en"
+
+var (
+	languageMu sync.RWMutex
+	language   string
+
+	// localeKeyPattern matches locale codes such as "en", "ja", "pt-BR" or "zh_Hant"
+	localeKeyPattern = regexp.MustCompile(`^[a-z]{2,3}([-_][A-Za-z]{2,4})?$`)
+)
+
+// SetLanguage selects the language used when loading character cards.
+// An empty value falls back to the OS locale.
+func SetLanguage(lang string) {
+	languageMu.Lock()
+	defer languageMu.Unlock()
+	language = NormalizeLanguage(lang)
+}
+
+// Language returns the language used when loading character cards
+func Language() string {
+	languageMu.RLock()
+	lang := language
+	languageMu.RUnlock()
+
+	if lang == "" {
+		return DetectLanguage()
+	}
+	return lang
+}
+
+// DetectLanguage reads the OS locale from the usual environment variables,
+// returning DefaultLanguage when none is set
+func DetectLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG", "LANGUAGE"} {
+		value := os.Getenv(name)
+		if name == "LANGUAGE" {
+			// LANGUAGE is a priority list like "ja:en"
+			value = strings.Split(value, ":")[0]
+		}
+		if lang := NormalizeLanguage(value); lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// NormalizeLanguage turns locale strings like "ja_JP.UTF-8" or "PT-br" into
+// "ja-JP" and "pt-BR". The POSIX "C" locale means no preference.
+func NormalizeLanguage(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return ""
+	}
+
+	parts := strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)
+	lang := strings.ToLower(parts[0])
+	if len(parts) == 2 && parts[1] != "" {
+		region := parts[1]
+		if len(region) == 2 {
+			region = strings.ToUpper(region)
+		}
+		lang += "-" + region
+	}
+	return lang
+}
+
+// languageCandidates lists the locale keys to try for lang, most specific
+// first: "pt-BR" then "pt", then the card default and English
+func languageCandidates(lang, cardDefault string) []string {
+	var candidates []string
+	add := func(code string) {
+		if code == "" {
+			return
+		}
+		for _, existing := range candidates {
+			if strings.EqualFold(existing, code) {
+				return
+			}
+		}
+		candidates = append(candidates, code)
+	}
+
+	for _, code := range []string{lang, cardDefault, DefaultLanguage} {
+		code = NormalizeLanguage(code)
+		add(code)
+		if base, _, found := strings.Cut(code, "-"); found {
+			add(base)
+		}
+	}
+	return candidates
+}
+
+// LocalizeCardJSON resolves a raw character card for lang: locale overlay
+// files next to cardPath are merged in, then every locale map is replaced by
+// the best matching string. Pass an empty cardPath to skip overlay files.
+func LocalizeCardJSON(data []byte, cardPath, lang string) ([]byte, error) {
+	var root map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep large integers such as seeds exact
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	cardDefault, _ := root["defaultLanguage"].(string)
+	candidates := languageCandidates(lang, cardDefault)
+
+	if cardPath != "" {
+		if err := mergeLocaleOverlays(root, cardPath, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	localizeValue(root, "", candidates)
+	return json.Marshal(root)
+}
+
+// mergeLocaleOverlays merges character.<lang>.json files into root. Files for
+// less specific languages are applied first so the requested one wins.
+func mergeLocaleOverlays(root map[string]interface{}, cardPath string, candidates []string) error {
+	ext := filepath.Ext(cardPath)
+	stem := strings.TrimSuffix(cardPath, ext)
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		overlayPath := fmt.Sprintf("%s.%s%s", stem, candidates[i], ext)
+		data, err := os.ReadFile(overlayPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read locale file %s: %w", overlayPath, err)
+		}
+
+		var overlay map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&overlay); err != nil {
+			return fmt.Errorf("failed to parse locale file %s: %w", overlayPath, err)
+		}
+		mergeJSON(root, overlay)
+	}
+	return nil
+}
+
+// mergeJSON deep-merges src into dst. Objects merge key by key; arrays are
+// merged element-wise so an overlay can translate dialogs by position
+// without repeating triggers and animations.
+func mergeJSON(dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		dst[key] = mergeJSONValue(dst[key], srcValue)
+	}
+}
+
+func mergeJSONValue(dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		if d, ok := dst.(map[string]interface{}); ok {
+			mergeJSON(d, s)
+			return d
+		}
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok || !isObjectList(s) {
+			return src
+		}
+		for i, item := range s {
+			if i < len(d) {
+				d[i] = mergeJSONValue(d[i], item)
+			} else {
+				d = append(d, item)
+			}
+		}
+		return d
+	}
+	return src
+}
+
+// isObjectList reports whether every element is a JSON object. Lists of
+// strings (like responses) are replaced instead of merged.
+func isObjectList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+// isLocalizableKey reports whether values under key may be locale maps
+func isLocalizableKey(key string) bool {
+	switch key {
+	case "name", "description", "text", "responses":
+		return true
+	}
+	return strings.HasSuffix(key, "Responses")
+}
+
+// localizeValue walks value and replaces locale maps under localizable keys
+func localizeValue(value interface{}, key string, candidates []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if isLocalizableKey(key) && isLocaleMap(v) {
+			return pickLocale(v, candidates)
+		}
+		for childKey, child := range v {
+			v[childKey] = localizeValue(child, childKey, candidates)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = localizeValue(child, "", candidates)
+		}
+	}
+	return value
+}
+
+// isLocaleMap reports whether every key is a locale code and every value a
+// string or list of strings
+func isLocaleMap(m map[string]interface{}) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for key, value := range m {
+		if !localeKeyPattern.MatchString(key) {
+			return false
+		}
+		switch v := value.(type) {
+		case string:
+		case []interface{}:
+			for _, item := range v {
+				if _, ok := item.(string); !ok {
+					return false
+				}
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// pickLocale returns the first candidate present in the locale map. When
+// none match, the alphabetically first locale is used so loading never fails.
+func pickLocale(m map[string]interface{}, candidates []string) interface{} {
+	for _, candidate := range candidates {
+		for key, value := range m {
+			if strings.EqualFold(NormalizeLanguage(key), candidate) {
+				return value
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return m[keys[0]]
+}
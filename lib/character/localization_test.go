@@ -0,0 +1,151 @@
+package character
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const localizedTestCard = `{
+  "name": {"en": "Aria", "ja": "アリア"},
+  "description": "A multilingual companion",
+  "defaultLanguage": "en",
+  "animations": {"idle": "idle.gif", "talking": "talking.gif"},
+  "dialogs": [
+    {"trigger": "click", "responses": {"en": ["Hello!"], "ja": ["こんにちは！"], "pt-BR": ["Olá!"]}, "animation": "talking", "cooldown": 5},
+    {"trigger": "hover", "responses": {"en": ["Hmm?"]}, "animation": "talking", "cooldown": 5}
+  ],
+  "behavior": {"idleTimeout": 30, "movementEnabled": true, "defaultSize": 128}
+}`
+
+// writeLocalizedCard writes the localized test card and its animations into dir
+func writeLocalizedCard(t *testing.T, dir string) string {
+	t.Helper()
+
+	for _, name := range []string{"idle.gif", "talking.gif"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, "character.json")
+	if err := os.WriteFile(path, []byte(localizedTestCard), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"ja_JP.UTF-8": "ja-JP",
+		"PT-br":       "pt-BR",
+		"en":          "en",
+		"zh_Hant":     "zh-Hant",
+		"C":           "",
+		"C.UTF-8":     "",
+		"":            "",
+	}
+	for input, want := range tests {
+		if got := NormalizeLanguage(input); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "ja_JP.UTF-8")
+	if got := DetectLanguage(); got != "ja-JP" {
+		t.Errorf("Expected ja-JP from LANG, got %q", got)
+	}
+
+	t.Setenv("LANG", "C")
+	t.Setenv("LANGUAGE", "")
+	if got := DetectLanguage(); got != DefaultLanguage {
+		t.Errorf("Expected fallback to %q, got %q", DefaultLanguage, got)
+	}
+}
+
+func TestLocalizeCardJSON(t *testing.T) {
+	tests := []struct {
+		lang      string
+		name      string
+		responses []string
+	}{
+		{"ja", "アリア", []string{"こんにちは！"}},
+		{"ja-JP", "アリア", []string{"こんにちは！"}},
+		{"pt-BR", "Aria", []string{"Olá!"}},
+		{"fr", "Aria", []string{"Hello!"}},
+	}
+
+	for _, tt := range tests {
+		data, err := LocalizeCardJSON([]byte(localizedTestCard), "", tt.lang)
+		if err != nil {
+			t.Fatalf("LocalizeCardJSON(%s) failed: %v", tt.lang, err)
+		}
+
+		var card CharacterCard
+		if err := json.Unmarshal(data, &card); err != nil {
+			t.Fatalf("Localized card should parse for %s: %v", tt.lang, err)
+		}
+		if card.Name != tt.name {
+			t.Errorf("%s: expected name %q, got %q", tt.lang, tt.name, card.Name)
+		}
+		if !reflect.DeepEqual(card.Dialogs[0].Responses, tt.responses) {
+			t.Errorf("%s: expected responses %v, got %v", tt.lang, tt.responses, card.Dialogs[0].Responses)
+		}
+		if card.Dialogs[1].Responses[0] != "Hmm?" {
+			t.Errorf("%s: missing translation should fall back to English", tt.lang)
+		}
+	}
+}
+
+func TestLocalizeCardJSONLeavesOtherMapsAlone(t *testing.T) {
+	input := `{"animations": {"en": "en.gif"}, "stats": {"hunger": {"initial": 100}}}`
+	data, err := LocalizeCardJSON([]byte(input), "", "ja")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["animations"]["en"] != "en.gif" {
+		t.Error("Only localizable keys should be treated as locale maps")
+	}
+}
+
+func TestLoadCardLocaleOverlay(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLocalizedCard(t, dir)
+
+	overlay := `{"description": "多言語のコンパニオン", "dialogs": [{}, {"responses": ["ん？"]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "character.ja.json"), []byte(overlay), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetLanguage("ja_JP")
+	defer SetLanguage("")
+
+	card, err := LoadCard(path)
+	if err != nil {
+		t.Fatalf("LoadCard failed: %v", err)
+	}
+	if card.Name != "アリア" || card.Description != "多言語のコンパニオン" {
+		t.Errorf("Expected Japanese name and overlay description, got %q / %q", card.Name, card.Description)
+	}
+	if card.Dialogs[0].Trigger != "click" || card.Dialogs[0].Responses[0] != "こんにちは！" {
+		t.Errorf("Overlay should keep untouched dialogs, got %+v", card.Dialogs[0])
+	}
+	if card.Dialogs[1].Responses[0] != "ん？" || card.Dialogs[1].Animation != "talking" {
+		t.Errorf("Overlay should translate dialogs by position, got %+v", card.Dialogs[1])
+	}
+}
@@ -0,0 +1,264 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Built-in mini-games, also the interaction names they are counted under
+// for achievements: "minigame_rps", and "minigame_rps_win" for each win
+const (
+	MiniGameRPS    = "rps"
+	MiniGameTrivia = "trivia"
+	MiniGameMemory = "memory"
+)
+
+// Rock-paper-scissors throws
+const (
+	ThrowRock     = "rock"
+	ThrowPaper    = "paper"
+	ThrowScissors = "scissors"
+)
+
+// Throws lists the rock-paper-scissors throws in display order
+var Throws = []string{ThrowRock, ThrowPaper, ThrowScissors}
+
+// beats maps each throw to the one it beats
+var beats = map[string]string{
+	ThrowRock:     ThrowScissors,
+	ThrowPaper:    ThrowRock,
+	ThrowScissors: ThrowPaper,
+}
+
+// TriviaPerRound is how many questions one trivia game asks
+const TriviaPerRound = 3
+
+// defaultMiniGameRewards are the stat changes for a win when the card sets none
+var defaultMiniGameRewards = map[string]float64{"happiness": 5, CurrencyKey: 2}
+
+// memorySymbols are the faces of memory cards; each game uses the first few
+var memorySymbols = []string{"🍎", "⭐", "🎈", "🐟", "🌙", "🍀", "🎵", "🍩"}
+
+// MiniGamesConfig customizes the built-in mini-games. Rock-paper-scissors and
+// memory are always available; trivia needs questions.
+type MiniGamesConfig struct {
+	Trivia        []TriviaQuestion              `json:"trivia,omitempty"`        // Questions for the trivia game
+	Rewards       map[string]map[string]float64 `json:"rewards,omitempty"`       // Game name to stat changes on a win
+	WinAnimation  string                        `json:"winAnimation,omitempty"`  // Played when the user wins (default: "happy" if present)
+	LoseAnimation string                        `json:"loseAnimation,omitempty"` // Played when the user loses
+	WinResponses  []string                      `json:"winResponses,omitempty"`
+	LoseResponses []string                      `json:"loseResponses,omitempty"`
+}
+
+// TriviaQuestion is one multiple-choice question
+type TriviaQuestion struct {
+	Question string   `json:"question"`
+	Choices  []string `json:"choices"`
+	Answer   int      `json:"answer"` // Index of the correct choice
+}
+
+// MiniGameResult describes a finished game for UI notifications
+type MiniGameResult struct {
+	Game     string
+	Won      bool
+	Response string
+	Effects  map[string]float64 // Applied only in game mode
+}
+
+// validateMiniGames validates the optional miniGames section
+func (c *CharacterCard) validateMiniGames() error {
+	m := c.MiniGames
+	if m == nil {
+		return nil
+	}
+
+	for i, q := range m.Trivia {
+		if q.Question == "" {
+			return fmt.Errorf("trivia %d: question is required", i)
+		}
+		if len(q.Choices) < 2 {
+			return fmt.Errorf("trivia %d: at least 2 choices are required", i)
+		}
+		if q.Answer < 0 || q.Answer >= len(q.Choices) {
+			return fmt.Errorf("trivia %d: answer %d is not one of the %d choices", i, q.Answer, len(q.Choices))
+		}
+	}
+
+	for game, rewards := range m.Rewards {
+		switch game {
+		case MiniGameRPS, MiniGameTrivia, MiniGameMemory:
+		default:
+			return fmt.Errorf("rewards for unknown game '%s'", game)
+		}
+		if len(rewards) > 0 && !c.HasGameFeatures() {
+			return fmt.Errorf("rewards require stats to be defined")
+		}
+		if err := c.validateJobStats(rewards); err != nil {
+			return fmt.Errorf("%s rewards: %w", game, err)
+		}
+	}
+
+	for _, animation := range []string{m.WinAnimation, m.LoseAnimation} {
+		if animation == "" {
+			continue
+		}
+		if _, exists := c.Animations[animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", animation)
+		}
+	}
+	return nil
+}
+
+// GetMiniGames returns the games this character can play, trivia only when
+// the card has questions
+func (c *Character) GetMiniGames() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	games := []string{MiniGameRPS, MiniGameMemory}
+	if c.card.MiniGames != nil && len(c.card.MiniGames.Trivia) > 0 {
+		games = append(games, MiniGameTrivia)
+	}
+	return games
+}
+
+// PickTriviaQuestions returns up to TriviaPerRound random questions from the card
+func (c *Character) PickTriviaQuestions() []TriviaQuestion {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.card.MiniGames == nil {
+		return nil
+	}
+	questions := append([]TriviaQuestion(nil), c.card.MiniGames.Trivia...)
+	rand.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	if len(questions) > TriviaPerRound {
+		questions = questions[:TriviaPerRound]
+	}
+	return questions
+}
+
+// PlayRockPaperScissors picks the character's throw against the user's.
+// Returns the character's throw and the outcome for the user: 1 win, 0 draw, -1 loss.
+func PlayRockPaperScissors(throw string) (string, int, error) {
+	if _, ok := beats[throw]; !ok {
+		return "", 0, fmt.Errorf("unknown throw '%s'", throw)
+	}
+
+	theirs := Throws[rand.Intn(len(Throws))]
+	switch {
+	case theirs == throw:
+		return theirs, 0, nil
+	case beats[throw] == theirs:
+		return theirs, 1, nil
+	default:
+		return theirs, -1, nil
+	}
+}
+
+// FinishMiniGame records a finished game, applies the win rewards in game
+// mode and plays the win or lose animation
+func (c *Character) FinishMiniGame(game string, won bool) MiniGameResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config := c.card.MiniGames
+	if config == nil {
+		config = &MiniGamesConfig{}
+	}
+
+	result := MiniGameResult{Game: game, Won: won}
+	if won {
+		result.Effects = defaultMiniGameRewards
+		if rewards, ok := config.Rewards[game]; ok {
+			result.Effects = rewards
+		}
+		result.Response = selectJobResponse(config.WinResponses, "You win! 🎉")
+	} else {
+		result.Response = selectJobResponse(config.LoseResponses, "I win this time! 😜")
+	}
+
+	if c.gameState != nil {
+		if won {
+			c.gameState.ApplyInteractionEffects(result.Effects)
+			c.gameState.RecordInteraction("minigame_" + game + "_win")
+		}
+		c.gameState.RecordInteraction("minigame_" + game)
+	} else {
+		result.Effects = nil
+	}
+
+	animation := config.LoseAnimation
+	if won {
+		animation = config.WinAnimation
+		if animation == "" {
+			animation = "happy"
+		}
+	}
+	if _, exists := c.card.Animations[animation]; exists {
+		c.setState(animation)
+	}
+	return result
+}
+
+// MemoryGame is a board of face-down card pairs. Turning two cards that
+// match keeps them face up; otherwise they turn back over.
+type MemoryGame struct {
+	Cards   []string // Card faces, two of each
+	Matched []bool
+	Moves   int // Pairs of cards turned over
+	first   int // Index of the first card of the current move, -1 for none
+}
+
+// NewMemoryGame deals a shuffled board with the given number of pairs
+func NewMemoryGame(pairs int) *MemoryGame {
+	pairs = max(1, min(pairs, len(memorySymbols)))
+	cards := make([]string, 0, pairs*2)
+	for _, symbol := range memorySymbols[:pairs] {
+		cards = append(cards, symbol, symbol)
+	}
+	rand.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+
+	return &MemoryGame{Cards: cards, Matched: make([]bool, len(cards)), first: -1}
+}
+
+// Flip turns a card over. On the second card of a move it returns the
+// first card's index and whether they matched; first is -1 otherwise.
+// Matched cards and the card already turned can't be flipped.
+func (m *MemoryGame) Flip(i int) (first int, matched bool, err error) {
+	if i < 0 || i >= len(m.Cards) {
+		return -1, false, fmt.Errorf("no card %d", i)
+	}
+	if m.Matched[i] || i == m.first {
+		return -1, false, fmt.Errorf("card %d is already face up", i)
+	}
+
+	if m.first < 0 {
+		m.first = i
+		return -1, false, nil
+	}
+
+	first, m.first = m.first, -1
+	m.Moves++
+	if m.Cards[first] == m.Cards[i] {
+		m.Matched[first], m.Matched[i] = true, true
+		return first, true, nil
+	}
+	return first, false, nil
+}
+
+// Done reports whether every pair has been found
+func (m *MemoryGame) Done() bool {
+	for _, matched := range m.Matched {
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Won reports whether the board was cleared in no more than twice as many
+// moves as there are pairs
+func (m *MemoryGame) Won() bool {
+	return m.Done() && m.Moves <= len(m.Cards)
+}
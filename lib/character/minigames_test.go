@@ -0,0 +1,157 @@
+package character
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMiniGames(t *testing.T) {
+	tests := []struct {
+		name    string
+		games   *MiniGamesConfig
+		wantErr string
+	}{
+		{"nil section", nil, ""},
+		{"valid", &MiniGamesConfig{
+			Trivia:       []TriviaQuestion{{Question: "2+2?", Choices: []string{"3", "4"}, Answer: 1}},
+			Rewards:      map[string]map[string]float64{MiniGameRPS: {"happiness": 10, "coins": 1}},
+			WinAnimation: "happy",
+		}, ""},
+		{"answer out of range", &MiniGamesConfig{
+			Trivia: []TriviaQuestion{{Question: "2+2?", Choices: []string{"3", "4"}, Answer: 2}},
+		}, "not one of the 2 choices"},
+		{"one choice", &MiniGamesConfig{
+			Trivia: []TriviaQuestion{{Question: "2+2?", Choices: []string{"4"}}},
+		}, "at least 2 choices"},
+		{"unknown game", &MiniGamesConfig{
+			Rewards: map[string]map[string]float64{"chess": {"happiness": 1}},
+		}, "unknown game 'chess'"},
+		{"unknown stat", &MiniGamesConfig{
+			Rewards: map[string]map[string]float64{MiniGameMemory: {"mana": 1}},
+		}, "memory rewards"},
+		{"missing animation", &MiniGamesConfig{LoseAnimation: "pout"}, "'pout' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.MiniGames = tt.games
+			err := card.validateMiniGames()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPlayRockPaperScissors(t *testing.T) {
+	if _, _, err := PlayRockPaperScissors("lizard"); err == nil {
+		t.Error("Expected an error for an unknown throw")
+	}
+
+	for i := 0; i < 30; i++ {
+		theirs, outcome, err := PlayRockPaperScissors(ThrowRock)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := map[string]int{ThrowRock: 0, ThrowScissors: 1, ThrowPaper: -1}[theirs]
+		if outcome != want {
+			t.Errorf("Rock vs %s: expected outcome %d, got %d", theirs, want, outcome)
+		}
+	}
+}
+
+func TestMemoryGame(t *testing.T) {
+	m := NewMemoryGame(2)
+	if len(m.Cards) != 4 {
+		t.Fatalf("Expected 4 cards, got %d", len(m.Cards))
+	}
+
+	// Find where each pair landed after the shuffle
+	positions := make(map[string][]int)
+	for i, card := range m.Cards {
+		positions[card] = append(positions[card], i)
+	}
+	var pairs [][]int
+	for _, p := range positions {
+		pairs = append(pairs, p)
+	}
+
+	// A mismatch turns both cards back
+	if first, _, _ := m.Flip(pairs[0][0]); first != -1 {
+		t.Error("First card of a move should not report a pair")
+	}
+	if _, _, err := m.Flip(pairs[0][0]); err == nil {
+		t.Error("Flipping the same card twice should fail")
+	}
+	first, matched, err := m.Flip(pairs[1][0])
+	if err != nil || matched || first != pairs[0][0] {
+		t.Errorf("Expected a mismatch with card %d, got %d %v %v", pairs[0][0], first, matched, err)
+	}
+
+	for _, p := range pairs {
+		m.Flip(p[0])
+		if _, matched, _ := m.Flip(p[1]); !matched {
+			t.Errorf("Expected cards %v to match", p)
+		}
+	}
+	if !m.Done() || m.Moves != 3 || !m.Won() {
+		t.Errorf("Expected the board cleared and won in 3 moves, got done=%v moves=%d", m.Done(), m.Moves)
+	}
+	if _, _, err := m.Flip(pairs[0][0]); err == nil {
+		t.Error("Matched cards should not flip")
+	}
+}
+
+func TestFinishMiniGame(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.MiniGames = &MiniGamesConfig{
+		Trivia:        []TriviaQuestion{{Question: "2+2?", Choices: []string{"3", "4"}, Answer: 1}},
+		Rewards:       map[string]map[string]float64{MiniGameTrivia: {"coins": 4}},
+		LoseResponses: []string{"Rematch?"},
+	}
+	card.Progression = &ProgressionConfig{Levels: []LevelConfig{{Name: "Baby", Size: 64}}}
+	char := createTestCharacterInstance(card, true)
+
+	if games := char.GetMiniGames(); len(games) != 3 {
+		t.Errorf("Expected trivia alongside the built-in games, got %v", games)
+	}
+	if questions := char.PickTriviaQuestions(); len(questions) != 1 {
+		t.Errorf("Expected the card's question, got %v", questions)
+	}
+
+	result := char.FinishMiniGame(MiniGameTrivia, true)
+	if !result.Won || result.Effects["coins"] != 4 {
+		t.Errorf("Expected the card's trivia reward, got %+v", result)
+	}
+	if coins := char.GetGameState().GetCoins(); coins != 4 {
+		t.Errorf("Expected 4 coins, got %d", coins)
+	}
+
+	result = char.FinishMiniGame(MiniGameRPS, false)
+	if result.Response != "Rematch?" || result.Effects != nil {
+		t.Errorf("Expected a loss without rewards, got %+v", result)
+	}
+
+	counts := char.GetGameState().GetProgression().GetInteractionCounts()
+	if counts["minigame_trivia_win"] != 1 || counts["minigame_rps"] != 1 || counts["minigame_rps_win"] != 0 {
+		t.Error("Expected games and wins to be counted for achievements")
+	}
+}
+
+func TestFinishMiniGameWithoutGameMode(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), false)
+
+	if games := char.GetMiniGames(); len(games) != 2 {
+		t.Errorf("Expected only the built-in games without trivia, got %v", games)
+	}
+	if result := char.FinishMiniGame(MiniGameMemory, true); !result.Won || result.Effects != nil {
+		t.Errorf("Expected a win without rewards outside game mode, got %+v", result)
+	}
+}
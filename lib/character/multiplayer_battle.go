@@ -277,12 +277,26 @@ func (mc *MultiplayerCharacter) handleBattleEndMessage(msg NetworkMessage, peer
 	// Clear battle ID when battle ends (Finding #3 fix)
 	mc.currentBattleID = ""
 
+	mc.GetGameState().AddJournalEntry(JournalBattle, battleOutcome(payload.Winner, mc.characterID), payload.Reason)
+
 	// User notification handled by UI layer through network events
 	// Battle end events are automatically propagated to UI for user notification
 
 	return nil
 }
 
+// battleOutcome describes how a battle ended for the character with myID
+func battleOutcome(winner, myID string) string {
+	switch winner {
+	case "":
+		return "Battle ended in a draw"
+	case myID:
+		return "Won a battle"
+	default:
+		return "Lost a battle to " + winner
+	}
+}
+
 // generateBattleID creates a unique battle ID
 func generateBattleID() string {
 	return fmt.Sprintf("battle_%d", time.Now().UnixNano())
@@ -0,0 +1,183 @@
+package character
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/news"
+	"github.com/sirupsen/logrus"
+)
+
+// newsDigestEvent is the schedule key the last briefing is recorded under.
+// The colon keeps it apart from card-defined scheduled event names.
+const newsDigestEvent = "news:digest"
+
+// newsDigestState holds the user's briefing preferences
+type newsDigestState struct {
+	categories []string // Subscribed feed categories, empty means all
+	time       string   // User's briefing time, empty uses the card's
+	lastCheck  time.Time
+}
+
+// validateNewsDigest validates the optional daily briefing
+func (c *CharacterCard) validateNewsDigest() error {
+	if c.NewsFeatures == nil || c.NewsFeatures.Digest == nil {
+		return nil
+	}
+	return c.NewsFeatures.Digest.Validate()
+}
+
+// newsDigestConfig returns the card's briefing config if it is enabled
+func (c *Character) newsDigestConfig() *news.DigestConfig {
+	if !c.card.HasNewsFeatures() || c.card.NewsFeatures.Digest == nil || !c.card.NewsFeatures.Digest.Enabled {
+		return nil
+	}
+	return c.card.NewsFeatures.Digest
+}
+
+// HasNewsDigest reports whether the card offers a daily briefing
+func (c *Character) HasNewsDigest() bool {
+	return c.newsDigestConfig() != nil
+}
+
+// GetNewsCategories returns the categories of the card's enabled feeds
+func (c *Character) GetNewsCategories() []string {
+	if !c.card.HasNewsFeatures() {
+		return nil
+	}
+	return news.FeedCategories(c.card.NewsFeatures.Feeds)
+}
+
+// GetNewsSubscriptions returns the subscribed categories, empty meaning all
+func (c *Character) GetNewsSubscriptions() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.newsDigest.categories...)
+}
+
+// SetNewsSubscriptions chooses which feed categories the briefing covers;
+// an empty list covers all of them
+func (c *Character) SetNewsSubscriptions(categories []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.newsDigest.categories = append([]string(nil), categories...)
+}
+
+// GetNewsDigestTime returns the effective briefing time as "HH:MM"
+func (c *Character) GetNewsDigestTime() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.newsDigestTime()
+}
+
+// newsDigestTime returns the user's briefing time or the card's.
+// Must be called with c.mu held.
+func (c *Character) newsDigestTime() string {
+	if c.newsDigest.time != "" {
+		return c.newsDigest.time
+	}
+	if cfg := c.newsDigestConfig(); cfg != nil {
+		return cfg.GetTime()
+	}
+	return news.DefaultDigestTime
+}
+
+// SetNewsDigestTime overrides the card's briefing time; "" restores it
+func (c *Character) SetNewsDigestTime(value string) error {
+	if value != "" {
+		if _, err := news.ParseDigestTime(value); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.newsDigest.time = value
+	return nil
+}
+
+// updateNewsDigest starts the daily briefing once its time has come.
+// Briefings missed by more than scheduleGrace are skipped, like scheduled
+// events. Must be called with c.mu held.
+func (c *Character) updateNewsDigest(now time.Time) {
+	cfg := c.newsDigestConfig()
+	if cfg == nil || c.sleeping || now.Sub(c.newsDigest.lastCheck) < time.Minute {
+		return
+	}
+	c.newsDigest.lastCheck = now
+
+	minute, err := news.ParseDigestTime(c.newsDigestTime())
+	if err != nil {
+		return
+	}
+	due := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(time.Duration(minute) * time.Minute)
+	if now.Before(due) || now.Sub(due) > scheduleGrace || !c.scheduleLastFired(newsDigestEvent).Before(due) {
+		return
+	}
+
+	c.markScheduleFired(newsDigestEvent, now)
+	categories := append([]string(nil), c.newsDigest.categories...)
+
+	// Reading the feed cache can wait on a running feed update, so the
+	// briefing is built off the animation loop
+	go c.deliverNewsDigest(cfg, categories, false)
+}
+
+// RequestNewsDigest reads the briefing now, e.g. to try out subscriptions
+func (c *Character) RequestNewsDigest() error {
+	cfg := c.newsDigestConfig()
+	if cfg == nil {
+		return fmt.Errorf("news briefing not enabled for this character")
+	}
+	go c.deliverNewsDigest(cfg, c.GetNewsSubscriptions(), true)
+	return nil
+}
+
+// deliverNewsDigest builds a briefing and queues it for the UI. Scheduled
+// briefings with nothing to say are skipped; requested ones say so.
+func (c *Character) deliverNewsDigest(cfg *news.DigestConfig, categories []string, requested bool) {
+	bubbles := c.buildNewsDigest(cfg, categories)
+	if len(bubbles) == 0 {
+		if !requested {
+			return
+		}
+		bubbles = []string{"📰 No news in your categories yet. Try again after the feeds update!"}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":     getCaller(),
+		"bubbles":    len(bubbles),
+		"categories": categories,
+	}).Info("Delivering news briefing")
+
+	c.mu.Lock()
+	c.newsDigests = append(c.newsDigests, bubbles)
+	c.mu.Unlock()
+}
+
+// buildNewsDigest selects stories and phrases them in the character's
+// reading style
+func (c *Character) buildNewsDigest(cfg *news.DigestConfig, categories []string) []string {
+	backend, err := c.validateAndGetNewsBackend()
+	if err != nil {
+		return nil
+	}
+
+	style := cfg.ReadingStyle
+	if style == "" {
+		style = backend.ReadingStyle(c.getPersonalityTraitsMap())
+	}
+	items := backend.GetDigestItems(categories, cfg.GetMaxItems())
+	return news.BuildDigest(cfg, style, items)
+}
+
+// GetNewsDigests returns briefings ready since the last call, each a
+// sequence of speech bubbles, and clears them
+func (c *Character) GetNewsDigests() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	digests := c.newsDigests
+	c.newsDigests = nil
+	return digests
+}
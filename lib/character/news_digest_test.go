@@ -0,0 +1,107 @@
+package character
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/news"
+)
+
+func createNewsDigestCharacter(t *testing.T) *Character {
+	t.Helper()
+	card := createTestGameCharacterCard()
+	card.NewsFeatures = &news.NewsConfig{
+		Enabled: true,
+		Feeds: []news.RSSFeed{
+			{Name: "Tech", URL: "https://example.com/tech.xml", Category: "tech", Enabled: true},
+			{Name: "Games", URL: "https://example.com/games.xml", Category: "gaming", Enabled: true},
+		},
+		Digest: &news.DigestConfig{Enabled: true, Time: "08:00"},
+	}
+	return createTestCharacterInstance(card, true)
+}
+
+func TestValidateNewsDigest(t *testing.T) {
+	card := createTestGameCharacterCard()
+	if err := card.validateNewsDigest(); err != nil {
+		t.Errorf("Cards without news should be valid, got %v", err)
+	}
+
+	card.NewsFeatures = &news.NewsConfig{Enabled: true, Digest: &news.DigestConfig{Time: "25:00"}}
+	if err := card.validateNewsDigest(); err == nil {
+		t.Error("Expected an invalid briefing time to be rejected")
+	}
+}
+
+func TestNewsDigestPreferences(t *testing.T) {
+	char := createNewsDigestCharacter(t)
+
+	if !char.HasNewsDigest() {
+		t.Fatal("Expected the card's briefing to be enabled")
+	}
+	if got := strings.Join(char.GetNewsCategories(), ","); got != "gaming,tech" {
+		t.Errorf("Expected feed categories gaming,tech, got %s", got)
+	}
+
+	if err := char.SetNewsDigestTime("7am"); err == nil {
+		t.Error("Expected an invalid time to be rejected")
+	}
+	if err := char.SetNewsDigestTime("07:15"); err != nil || char.GetNewsDigestTime() != "07:15" {
+		t.Errorf("Expected the user's time to win, got %s, %v", char.GetNewsDigestTime(), err)
+	}
+	if err := char.SetNewsDigestTime(""); err != nil || char.GetNewsDigestTime() != "08:00" {
+		t.Errorf("Expected the card's time back, got %s, %v", char.GetNewsDigestTime(), err)
+	}
+
+	char.SetNewsSubscriptions([]string{"tech"})
+	if got := char.GetNewsSubscriptions(); len(got) != 1 || got[0] != "tech" {
+		t.Errorf("Expected tech subscription, got %v", got)
+	}
+}
+
+func TestUpdateNewsDigestFiresOncePerDay(t *testing.T) {
+	char := createNewsDigestCharacter(t)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+	char.updateNewsDigest(day.Add(7*time.Hour + 59*time.Minute))
+	if !char.scheduleLastFired(newsDigestEvent).IsZero() {
+		t.Fatal("Briefing should wait for its time")
+	}
+
+	firstRun := day.Add(8*time.Hour + 10*time.Minute)
+	char.updateNewsDigest(firstRun)
+	if !char.scheduleLastFired(newsDigestEvent).Equal(firstRun) {
+		t.Fatal("Expected the briefing to start after its time")
+	}
+
+	char.updateNewsDigest(firstRun.Add(5 * time.Minute))
+	if !char.scheduleLastFired(newsDigestEvent).Equal(firstRun) {
+		t.Error("Briefing should run once a day")
+	}
+
+	// Starting the app late the next day skips the missed briefing
+	char.updateNewsDigest(day.AddDate(0, 0, 1).Add(11 * time.Hour))
+	if !char.scheduleLastFired(newsDigestEvent).Equal(firstRun) {
+		t.Error("Briefings missed by more than the grace period should be skipped")
+	}
+}
+
+func TestRequestNewsDigestWithoutStories(t *testing.T) {
+	char := createNewsDigestCharacter(t)
+	if err := char.RequestNewsDigest(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if digests := char.GetNewsDigests(); len(digests) > 0 {
+			if len(digests[0]) != 1 || !strings.Contains(digests[0][0], "No news") {
+				t.Errorf("Expected a no-news bubble, got %v", digests)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected a requested briefing to answer even without stories")
+}
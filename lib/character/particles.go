@@ -0,0 +1,267 @@
+package character
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Particle triggers. Stat triggers take the stat name after the colon,
+// e.g. "rise:affection" or "low:energy".
+const (
+	ParticleTriggerLevelUp = "levelUp" // Age or relationship level went up
+	ParticleTriggerRise    = "rise"    // A stat increased
+	ParticleTriggerFall    = "fall"    // A stat decreased
+	ParticleTriggerLow     = "low"     // A stat is at or below its critical threshold
+
+	defaultParticleDuration = 1500 // Milliseconds
+	defaultParticleCount    = 5
+	maxParticleCount        = 20
+	defaultParticleCooldown = 2  // Seconds between rise, fall and level up bursts
+	defaultLowCooldown      = 30 // Seconds between bursts while a stat stays low
+
+	// particleCheckInterval keeps stat snapshots off the 60 FPS path
+	particleCheckInterval = 250 * time.Millisecond
+)
+
+// Particle motions
+const (
+	ParticleMotionRise  = "rise"  // Float up and fade, e.g. hearts
+	ParticleMotionFall  = "fall"  // Drip down and fade, e.g. sweat drops
+	ParticleMotionBurst = "burst" // Spread out from the center, e.g. sparkles
+)
+
+// ParticleEffectConfig is a visual effect drawn over the character when its
+// trigger fires
+type ParticleEffectConfig struct {
+	Trigger  string `json:"trigger"`            // "levelUp", "rise:<stat>", "fall:<stat>" or "low:<stat>"
+	Sprite   string `json:"sprite"`             // Image file relative to the card, or a short text/emoji glyph
+	Motion   string `json:"motion,omitempty"`   // "rise", "fall" or "burst" (default: "rise")
+	Duration int    `json:"duration,omitempty"` // Milliseconds each burst lasts (default: 1500)
+	Count    int    `json:"count,omitempty"`    // Particles per burst, 1-20 (default: 5)
+	Cooldown int    `json:"cooldown,omitempty"` // Seconds before it fires again (default: 2, or 30 for low triggers)
+}
+
+// ParticleBurst is a fired effect for the UI to draw
+type ParticleBurst struct {
+	Sprite    string // Absolute image path, or a text glyph when IsImage is false
+	IsImage   bool
+	Motion    string
+	Duration  time.Duration
+	Count     int
+	StartedAt time.Time
+}
+
+// defaultParticleEffects are used by cards with stats and no particles section
+var defaultParticleEffects = []ParticleEffectConfig{
+	{Trigger: "rise:affection", Sprite: "❤", Motion: ParticleMotionRise},
+	{Trigger: "low:energy", Sprite: "💧", Motion: ParticleMotionFall},
+	{Trigger: ParticleTriggerLevelUp, Sprite: "✨", Motion: ParticleMotionBurst, Count: 8},
+}
+
+// particleState tracks what changed since the last check
+type particleState struct {
+	effects   []ParticleEffectConfig
+	stats     map[string]float64
+	level     string
+	lastFired map[int]time.Time
+	lastCheck time.Time
+}
+
+// parseParticleTrigger splits a trigger into its kind and stat name
+func parseParticleTrigger(trigger string) (kind, stat string) {
+	kind, stat, _ = strings.Cut(trigger, ":")
+	return kind, stat
+}
+
+// isParticleImage reports whether a sprite names an image file rather than a glyph
+func isParticleImage(sprite string) bool {
+	switch strings.ToLower(filepath.Ext(sprite)) {
+	case ".png", ".gif", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+// GetDuration returns how long a burst lasts
+func (p ParticleEffectConfig) GetDuration() time.Duration {
+	if p.Duration <= 0 {
+		return defaultParticleDuration * time.Millisecond
+	}
+	return time.Duration(p.Duration) * time.Millisecond
+}
+
+// GetCount returns the number of particles per burst
+func (p ParticleEffectConfig) GetCount() int {
+	if p.Count <= 0 {
+		return defaultParticleCount
+	}
+	return p.Count
+}
+
+// GetCooldown returns the minimum time between bursts
+func (p ParticleEffectConfig) GetCooldown() time.Duration {
+	if p.Cooldown > 0 {
+		return time.Duration(p.Cooldown) * time.Second
+	}
+	if kind, _ := parseParticleTrigger(p.Trigger); kind == ParticleTriggerLow {
+		return defaultLowCooldown * time.Second
+	}
+	return defaultParticleCooldown * time.Second
+}
+
+// GetParticleEffects returns the card's effects, or the built-in hearts,
+// sweat drops and sparkles for game cards without a particles section
+func (c *CharacterCard) GetParticleEffects() []ParticleEffectConfig {
+	if c.Particles != nil {
+		return c.Particles
+	}
+	if c.HasGameFeatures() {
+		return defaultParticleEffects
+	}
+	return nil
+}
+
+// validateParticles validates the optional particles section
+func (c *CharacterCard) validateParticles() error {
+	for i, effect := range c.Particles {
+		if err := c.validateParticleEffect(effect); err != nil {
+			return fmt.Errorf("effect %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateParticleEffect checks one effect's trigger, sprite and ranges
+func (c *CharacterCard) validateParticleEffect(effect ParticleEffectConfig) error {
+	kind, stat := parseParticleTrigger(effect.Trigger)
+	switch kind {
+	case ParticleTriggerLevelUp:
+		if stat != "" {
+			return fmt.Errorf("trigger '%s' takes no stat", effect.Trigger)
+		}
+	case ParticleTriggerRise, ParticleTriggerFall, ParticleTriggerLow:
+		if stat == "" {
+			return fmt.Errorf("trigger '%s' needs a stat, e.g. '%s:happiness'", effect.Trigger, kind)
+		}
+		if _, exists := c.Stats[stat]; !exists {
+			return fmt.Errorf("trigger '%s' references undefined stat '%s'", effect.Trigger, stat)
+		}
+	default:
+		return fmt.Errorf("unknown trigger '%s'", effect.Trigger)
+	}
+
+	if strings.TrimSpace(effect.Sprite) == "" {
+		return fmt.Errorf("sprite is required")
+	}
+
+	switch effect.Motion {
+	case "", ParticleMotionRise, ParticleMotionFall, ParticleMotionBurst:
+	default:
+		return fmt.Errorf("unknown motion '%s'", effect.Motion)
+	}
+
+	if effect.Count < 0 || effect.Count > maxParticleCount {
+		return fmt.Errorf("count must be 0-%d, got %d", maxParticleCount, effect.Count)
+	}
+	if effect.Duration < 0 || effect.Cooldown < 0 {
+		return fmt.Errorf("duration and cooldown must not be negative")
+	}
+	return nil
+}
+
+// updateParticles compares stats and levels with the last check and queues
+// bursts for effects whose trigger fired. Called from Update with c.mu held.
+func (c *Character) updateParticles(now time.Time) {
+	if c.gameState == nil || now.Sub(c.particles.lastCheck) < particleCheckInterval {
+		return
+	}
+	c.particles.lastCheck = now
+
+	if c.particles.effects == nil {
+		c.particles.effects = c.card.GetParticleEffects()
+		c.particles.lastFired = make(map[int]time.Time)
+	}
+
+	stats := c.gameState.GetStats()
+	level := c.particleLevel()
+	previous, previousLevel := c.particles.stats, c.particles.level
+	c.particles.stats, c.particles.level = stats, level
+
+	// The first check only records a baseline
+	if previous == nil {
+		return
+	}
+
+	critical := make(map[string]bool)
+	for _, name := range c.gameState.GetCriticalStates() {
+		critical[name] = true
+	}
+
+	for i, effect := range c.particles.effects {
+		if now.Sub(c.particles.lastFired[i]) < effect.GetCooldown() {
+			continue
+		}
+
+		kind, stat := parseParticleTrigger(effect.Trigger)
+		fired := false
+		switch kind {
+		case ParticleTriggerLevelUp:
+			fired = level != previousLevel
+		case ParticleTriggerRise:
+			fired = stats[stat] > previous[stat]
+		case ParticleTriggerFall:
+			fired = stats[stat] < previous[stat]
+		case ParticleTriggerLow:
+			fired = critical[stat]
+		}
+		if !fired {
+			continue
+		}
+
+		c.particles.lastFired[i] = now
+		c.particleBursts = append(c.particleBursts, c.newParticleBurst(effect, now))
+	}
+}
+
+// particleLevel combines the age and relationship levels so either one
+// changing counts as a level up
+func (c *Character) particleLevel() string {
+	level := c.gameState.GetRelationshipLevel()
+	if current := c.gameState.GetProgression().GetCurrentLevel(); current != nil {
+		level = current.Name + "/" + level
+	}
+	return level
+}
+
+// newParticleBurst resolves an effect's sprite for the UI
+func (c *Character) newParticleBurst(effect ParticleEffectConfig, now time.Time) ParticleBurst {
+	burst := ParticleBurst{
+		Sprite:    effect.Sprite,
+		Motion:    effect.Motion,
+		Duration:  effect.GetDuration(),
+		Count:     effect.GetCount(),
+		StartedAt: now,
+	}
+	if burst.Motion == "" {
+		burst.Motion = ParticleMotionRise
+	}
+	if isParticleImage(effect.Sprite) {
+		burst.IsImage = true
+		if !filepath.IsAbs(effect.Sprite) {
+			burst.Sprite = filepath.Join(c.basePath, effect.Sprite)
+		}
+	}
+	return burst
+}
+
+// GetParticleBursts returns effects fired since the last call and clears them
+func (c *Character) GetParticleBursts() []ParticleBurst {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bursts := c.particleBursts
+	c.particleBursts = nil
+	return bursts
+}
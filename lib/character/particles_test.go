@@ -0,0 +1,104 @@
+package character
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateParticles(t *testing.T) {
+	tests := []struct {
+		name    string
+		effect  ParticleEffectConfig
+		wantErr string
+	}{
+		{"valid stat trigger", ParticleEffectConfig{Trigger: "rise:happiness", Sprite: "❤"}, ""},
+		{"valid level up", ParticleEffectConfig{Trigger: "levelUp", Sprite: "sparkle.png", Motion: "burst"}, ""},
+		{"unknown trigger", ParticleEffectConfig{Trigger: "spin", Sprite: "❤"}, "unknown trigger"},
+		{"missing stat", ParticleEffectConfig{Trigger: "low", Sprite: "💧"}, "needs a stat"},
+		{"undefined stat", ParticleEffectConfig{Trigger: "rise:affection", Sprite: "❤"}, "undefined stat"},
+		{"missing sprite", ParticleEffectConfig{Trigger: "levelUp"}, "sprite is required"},
+		{"bad motion", ParticleEffectConfig{Trigger: "levelUp", Sprite: "✨", Motion: "spiral"}, "unknown motion"},
+		{"too many", ParticleEffectConfig{Trigger: "levelUp", Sprite: "✨", Count: 50}, "count must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Particles = []ParticleEffectConfig{tt.effect}
+			err := card.validateParticles()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParticleEffectsDefaults(t *testing.T) {
+	card := createTestGameCharacterCard()
+	if effects := card.GetParticleEffects(); len(effects) != len(defaultParticleEffects) {
+		t.Errorf("Game cards without particles should get the defaults, got %v", effects)
+	}
+
+	card.Particles = []ParticleEffectConfig{}
+	if effects := card.GetParticleEffects(); len(effects) != 0 {
+		t.Errorf("An empty particles section should turn effects off, got %v", effects)
+	}
+
+	effect := ParticleEffectConfig{Trigger: "low:energy"}
+	if effect.GetCooldown() != defaultLowCooldown*time.Second || effect.GetCount() != defaultParticleCount {
+		t.Errorf("Unexpected defaults: cooldown %v count %d", effect.GetCooldown(), effect.GetCount())
+	}
+}
+
+func TestUpdateParticles(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Particles = []ParticleEffectConfig{
+		{Trigger: "rise:happiness", Sprite: "❤"},
+		{Trigger: "low:energy", Sprite: "drop.png", Motion: "fall"},
+	}
+	char := createTestCharacterInstance(card, true)
+	char.basePath = "/cards/pet"
+
+	now := time.Now()
+	char.updateParticles(now)
+	if bursts := char.GetParticleBursts(); len(bursts) != 0 {
+		t.Fatalf("The first check should only record a baseline, got %v", bursts)
+	}
+
+	char.gameState.ApplyInteractionEffects(map[string]float64{"happiness": -20})
+	char.updateParticles(now.Add(time.Second))
+	if bursts := char.GetParticleBursts(); len(bursts) != 0 {
+		t.Fatalf("Falling happiness should not fire a rise effect, got %v", bursts)
+	}
+
+	char.gameState.ApplyInteractionEffects(map[string]float64{"happiness": 10, "energy": -90})
+	char.updateParticles(now.Add(2 * time.Second))
+	bursts := char.GetParticleBursts()
+	if len(bursts) != 2 {
+		t.Fatalf("Expected hearts and sweat drops, got %v", bursts)
+	}
+	if bursts[0].Sprite != "❤" || bursts[0].IsImage || bursts[0].Motion != ParticleMotionRise {
+		t.Errorf("Unexpected heart burst %+v", bursts[0])
+	}
+	if bursts[1].Sprite != filepath.Join("/cards/pet", "drop.png") || !bursts[1].IsImage {
+		t.Errorf("Image sprites should resolve next to the card, got %+v", bursts[1])
+	}
+
+	// Low stats repeat only after the cooldown
+	char.updateParticles(now.Add(3 * time.Second))
+	if bursts := char.GetParticleBursts(); len(bursts) != 0 {
+		t.Errorf("Expected the low effect to wait for its cooldown, got %v", bursts)
+	}
+	char.updateParticles(now.Add(3*time.Second + defaultLowCooldown*time.Second))
+	if bursts := char.GetParticleBursts(); len(bursts) != 1 || bursts[0].Motion != ParticleMotionFall {
+		t.Errorf("Expected sweat drops again after the cooldown, got %v", bursts)
+	}
+}
@@ -0,0 +1,87 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// validatePeerChat checks the optional character-to-character chat settings
+func (c *CharacterCard) validatePeerChat(config *network.ConversationConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	if config.Chattiness < 0 || config.Chattiness > 1 {
+		return fmt.Errorf("chattiness must be between 0 and 1, got %f", config.Chattiness)
+	}
+	if config.MaxTurns < 0 || config.MaxTurns > 20 {
+		return fmt.Errorf("maxTurns must be between 0 and 20, got %d", config.MaxTurns)
+	}
+	if config.IdleSeconds < 0 || config.CheckInterval < 0 {
+		return fmt.Errorf("idleSeconds and checkInterval must not be negative")
+	}
+
+	return nil
+}
+
+// peerChatOpeners returns fallback first lines when no dialog backend is available
+func peerChatOpeners(topic string) []string {
+	if topic == "" {
+		return []string{"Hey there! How's it going?", "Hi! Nice to see another friend around.", "Hello! Got a minute to chat?"}
+	}
+	return []string{
+		fmt.Sprintf("Hey, have you thought about %s lately?", topic),
+		fmt.Sprintf("So... %s. What's your take?", topic),
+		fmt.Sprintf("I was just thinking about %s!", topic),
+	}
+}
+
+// IsIdleFor reports whether the character is awake, not working, and has not
+// been interacted with for at least d. Used to decide when to chat with peers.
+func (c *Character) IsIdleFor(d time.Duration) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.sleeping || (c.gameState != nil && c.gameState.GetActiveJob() != nil) {
+		return false
+	}
+	return time.Since(c.lastInteraction) >= d
+}
+
+// GeneratePeerChatLine produces one line of a conversation with another
+// networked character. Uses the dialog backend when available and does not
+// count as a user interaction, so the character stays idle while chatting.
+func (c *Character) GeneratePeerChatLine(topic, heard string, turn int) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.useAdvancedDialogs && c.dialogManager != nil {
+		prompt := heard
+		if prompt == "" {
+			prompt = topic
+		}
+		context := c.buildChatDialogContext(prompt)
+		context.Trigger = "peer_chat"
+		context.ConversationTurn = turn
+		if topic != "" {
+			if context.TopicContext == nil {
+				context.TopicContext = make(map[string]interface{})
+			}
+			context.TopicContext["peer_topic"] = topic
+		}
+
+		response, err := c.dialogManager.GenerateDialog(context)
+		if err == nil && response.Text != "" && response.Confidence >= c.card.DialogBackend.ConfidenceThreshold {
+			return response.Text
+		}
+	}
+
+	if heard == "" {
+		openers := peerChatOpeners(topic)
+		return openers[rand.Intn(len(openers))]
+	}
+	return c.handleChatFallback(heard)
+}
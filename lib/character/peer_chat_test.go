@@ -0,0 +1,58 @@
+package character
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+func TestPeerChatLineFallback(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), false)
+	before := char.lastInteraction
+
+	opener := char.GeneratePeerChatLine("music", "", 1)
+	if !strings.Contains(opener, "music") {
+		t.Errorf("Opener should mention the topic, got %q", opener)
+	}
+	if reply := char.GeneratePeerChatLine("music", "I love jazz", 2); reply == "" {
+		t.Error("Reply should fall back to a canned response")
+	}
+	if !char.lastInteraction.Equal(before) {
+		t.Error("Peer chat should not count as a user interaction")
+	}
+}
+
+func TestIsIdleFor(t *testing.T) {
+	char := createTestCharacterInstance(createTestJobCard(), true)
+
+	char.lastInteraction = time.Now().Add(-5 * time.Minute)
+	if !char.IsIdleFor(2 * time.Minute) {
+		t.Error("Character should be idle after 5 minutes")
+	}
+	if char.IsIdleFor(10 * time.Minute) {
+		t.Error("Character should not be idle for 10 minutes yet")
+	}
+
+	if _, err := char.StartJob("study"); err != nil {
+		t.Fatal(err)
+	}
+	char.lastInteraction = time.Now().Add(-5 * time.Minute)
+	if char.IsIdleFor(time.Minute) {
+		t.Error("Working character should not count as idle")
+	}
+}
+
+func TestValidatePeerChat(t *testing.T) {
+	card := createTestGameCharacterCard()
+	if err := card.validatePeerChat(&network.ConversationConfig{Chattiness: 0.3, Topics: []string{"games"}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := card.validatePeerChat(&network.ConversationConfig{Chattiness: 1.5}); err == nil {
+		t.Error("Chattiness above 1 should be rejected")
+	}
+	if err := card.validatePeerChat(&network.ConversationConfig{MaxTurns: 50}); err == nil {
+		t.Error("Excessive maxTurns should be rejected")
+	}
+}
@@ -4,10 +4,16 @@ package character
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/opd-ai/desktop-companion/lib/platform"
 )
 
+// WindowModeWidget shows the character as a compact home-screen style widget
+// on mobile: a small sprite with a minimal stats bar that opens the full app
+// when tapped
+const WindowModeWidget = "widget"
+
 // PlatformAwareLoader handles loading character cards with platform-specific adaptations.
 // Uses standard library JSON parsing with platform detection for adaptive configuration.
 type PlatformAwareLoader struct {
@@ -73,6 +79,19 @@ func (pal *PlatformAwareLoader) applyPlatformConfig(card *CharacterCard) *Charac
 		adaptedCard.Behavior.DefaultSize = platformConfig.DefaultSize
 	}
 
+	// Apply animation variants
+	if len(platformConfig.Animations) > 0 {
+		adaptedCard.Animations = make(map[string]string, len(card.Animations))
+		for name, file := range card.Animations {
+			adaptedCard.Animations[name] = file
+		}
+		for name, file := range platformConfig.Animations {
+			if _, exists := card.Animations[name]; exists {
+				adaptedCard.Animations[name] = file
+			}
+		}
+	}
+
 	// Apply interaction overrides
 	if len(platformConfig.Interactions) > 0 {
 		adaptedCard.Interactions = pal.mergeInteractions(card.Interactions, platformConfig.Interactions)
@@ -184,6 +203,9 @@ func ValidatePlatformConfig(card *CharacterCard) error {
 		if err := validatePlatformSpecificConfig(card.PlatformConfig.Desktop, "desktop"); err != nil {
 			return fmt.Errorf("invalid desktop platform config: %w", err)
 		}
+		if err := validatePlatformAnimations(card.PlatformConfig.Desktop.Animations, card.Animations); err != nil {
+			return fmt.Errorf("invalid desktop platform config: %w", err)
+		}
 	}
 
 	// Validate mobile configuration
@@ -191,6 +213,9 @@ func ValidatePlatformConfig(card *CharacterCard) error {
 		if err := validatePlatformSpecificConfig(card.PlatformConfig.Mobile, "mobile"); err != nil {
 			return fmt.Errorf("invalid mobile platform config: %w", err)
 		}
+		if err := validatePlatformAnimations(card.PlatformConfig.Mobile.Animations, card.Animations); err != nil {
+			return fmt.Errorf("invalid mobile platform config: %w", err)
+		}
 	}
 
 	return nil
@@ -212,9 +237,10 @@ func validatePlatformSpecificConfig(config *PlatformSpecificConfig, platformType
 	// Validate window mode
 	if config.WindowMode != "" {
 		validModes := map[string]bool{
-			"overlay":    true,
-			"fullscreen": true,
-			"pip":        true,
+			"overlay":        true,
+			"fullscreen":     true,
+			"pip":            true,
+			WindowModeWidget: true,
 		}
 		if !validModes[config.WindowMode] {
 			return fmt.Errorf("invalid window mode: %s", config.WindowMode)
@@ -225,6 +251,9 @@ func validatePlatformSpecificConfig(config *PlatformSpecificConfig, platformType
 			// Warning: overlay mode may not work well on mobile
 			// But we don't prevent it - let the user decide
 		}
+		if platformType != "mobile" && config.WindowMode == WindowModeWidget {
+			return fmt.Errorf("widget window mode only valid for mobile platform")
+		}
 	}
 
 	// Validate mobile controls (only for mobile platform)
@@ -235,6 +264,36 @@ func validatePlatformSpecificConfig(config *PlatformSpecificConfig, platformType
 	return nil
 }
 
+// validatePlatformAnimations checks that animation variants replace states
+// the card has, with GIF files
+func validatePlatformAnimations(variants, animations map[string]string) error {
+	for name, file := range variants {
+		if _, exists := animations[name]; !exists {
+			return fmt.Errorf("animation variant '%s' has no animation in the card to replace", name)
+		}
+		if !strings.HasSuffix(strings.ToLower(file), ".gif") {
+			return fmt.Errorf("animation variant '%s' must be a GIF file, got: %s", name, file)
+		}
+	}
+	return nil
+}
+
+// platformAnimation returns the card's animation variant for the current
+// platform, or "" when there is none
+func (c *CharacterCard) platformAnimation(name string) string {
+	if c.PlatformConfig == nil {
+		return ""
+	}
+	config := c.PlatformConfig.Desktop
+	if platform.GetPlatformInfo().IsMobile() {
+		config = c.PlatformConfig.Mobile
+	}
+	if config == nil {
+		return ""
+	}
+	return config.Animations[name]
+}
+
 // GetPlatformConfig returns the appropriate platform configuration for the current platform.
 // Returns nil if no platform-specific configuration exists.
 func (pal *PlatformAwareLoader) GetPlatformConfig(card *CharacterCard) *PlatformSpecificConfig {
@@ -371,6 +371,29 @@ func TestValidatePlatformConfig(t *testing.T) {
 			expectError: true,
 			errorMsg:    "mobile controls configuration only valid for mobile platform",
 		},
+		{
+			name: "widget mode on mobile",
+			card: &CharacterCard{
+				PlatformConfig: &PlatformConfig{
+					Mobile: &PlatformSpecificConfig{
+						WindowMode: WindowModeWidget,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "widget mode on desktop",
+			card: &CharacterCard{
+				PlatformConfig: &PlatformConfig{
+					Desktop: &PlatformSpecificConfig{
+						WindowMode: WindowModeWidget,
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "widget window mode only valid for mobile platform",
+		},
 		{
 			name: "no platform config",
 			card: &CharacterCard{
@@ -604,3 +627,46 @@ func TestPlatformAwareLoader_MergeInteractions(t *testing.T) {
 		t.Error("expected feed interaction to be added")
 	}
 }
+
+// TestPlatformAnimations tests animation variants picked for the current platform.
+func TestPlatformAnimations(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"idle.gif", "idle_variant.gif"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("GIF89a"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	variants := &PlatformSpecificConfig{Animations: map[string]string{"idle": "idle_variant.gif", "talking": "talking_variant.gif"}}
+	card := &CharacterCard{
+		Animations:     map[string]string{"idle": "idle.gif", "talking": "talking.gif"},
+		PlatformConfig: &PlatformConfig{Desktop: variants, Mobile: variants},
+	}
+
+	path, err := card.GetAnimationPath(dir, "idle")
+	if err != nil || filepath.Base(path) != "idle_variant.gif" {
+		t.Errorf("Expected the platform variant, got %s (%v)", path, err)
+	}
+	// A missing variant file falls back to the card's animation
+	if path, _ := card.GetAnimationPath(dir, "talking"); filepath.Base(path) == "talking_variant.gif" {
+		t.Error("Expected the missing variant to be skipped")
+	}
+
+	adapted := NewPlatformAwareLoader().applyPlatformConfig(card)
+	if adapted.Animations["idle"] != "idle_variant.gif" || card.Animations["idle"] != "idle.gif" {
+		t.Error("Expected the adapted card to use the variant without changing the original")
+	}
+
+	if err := ValidatePlatformConfig(card); err != nil {
+		t.Errorf("Valid variants rejected: %v", err)
+	}
+	variants.Animations["sleeping"] = "sleeping_variant.gif"
+	if err := ValidatePlatformConfig(card); err == nil {
+		t.Error("Expected an error for a variant of an animation the card doesn't have")
+	}
+	delete(variants.Animations, "sleeping")
+	variants.Animations["idle"] = "idle_variant.png"
+	if err := ValidatePlatformConfig(card); err == nil {
+		t.Error("Expected an error for a variant that isn't a GIF")
+	}
+}
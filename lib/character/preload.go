@@ -0,0 +1,280 @@
+package character
+
+// preload.go decodes a character's animations when they are first needed
+// instead of all at startup, and decodes the ones most likely to play next
+// in the background so switching to them never waits on the GIF decoder.
+// Likely animations come from the card: idle follows everything, dialogs
+// and interactions follow their triggers, critical stats bring their
+// animations, and a transition clip plays before its target. Switches are
+// counted as cache hits or misses for the hit rate.
+
+import (
+	"fmt"
+	"image/gif"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// preloadCount is how many likely next animations are decoded ahead
+const preloadCount = 3
+
+// dialogTriggerWeights rank dialog triggers by how often they fire; other
+// triggers count once
+var dialogTriggerWeights = map[string]int{
+	"click": 3,
+	"hover": 2,
+}
+
+// AnimationCacheStats counts how often switching animations found the new
+// one already decoded
+type AnimationCacheStats struct {
+	Hits      int     `json:"hits"`      // Switches to an animation already decoded
+	Misses    int     `json:"misses"`    // Switches that waited for a decode
+	Preloaded int     `json:"preloaded"` // Animations decoded in the background
+	HitRate   float64 `json:"hitRate"`   // Hits as a fraction of switches, 0 before any
+}
+
+// RegisterAnimation adds a GIF animation that is decoded on first use or by
+// Preload. Only the GIF header is read now, so a file that isn't a GIF is
+// still rejected up front; one whose frames turn out to be broken is
+// dropped when it is first decoded.
+func (am *AnimationManager) RegisterAnimation(name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open animation file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := gif.DecodeConfig(file); err != nil {
+		return fmt.Errorf("failed to decode GIF %s: %w", path, err)
+	}
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if am.sources == nil {
+		am.sources = make(map[string]string)
+	}
+	if _, decoded := am.animations[name]; !decoded {
+		am.sources[name] = path
+	}
+	return nil
+}
+
+// Preload decodes registered animations in the background, in the order
+// given, skipping ones already decoded or being decoded
+func (am *AnimationManager) Preload(names ...string) {
+	am.mu.Lock()
+	var pending []string
+	for _, name := range names {
+		if _, registered := am.sources[name]; registered && !am.preloading[name] {
+			if am.preloading == nil {
+				am.preloading = make(map[string]bool)
+			}
+			am.preloading[name] = true
+			pending = append(pending, name)
+		}
+	}
+	am.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	am.preloads.Add(1)
+	go func() {
+		defer am.preloads.Done()
+		for _, name := range pending {
+			decoded, err := am.decodeRegistered(name)
+
+			am.mu.Lock()
+			delete(am.preloading, name)
+			if decoded {
+				am.cacheStats.Preloaded++
+			}
+			am.mu.Unlock()
+
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"caller": getCaller(),
+					"name":   name,
+					"error":  err.Error(),
+				}).Warn("Failed to preload animation")
+			}
+		}
+	}()
+}
+
+// GetCacheStats returns the animation cache hit counts
+func (am *AnimationManager) GetCacheStats() AnimationCacheStats {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	stats := am.cacheStats
+	if switches := stats.Hits + stats.Misses; switches > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(switches)
+	}
+	return stats
+}
+
+// ensureDecoded decodes a registered animation that hasn't been yet.
+// Returns whether it had to, and an error if name is unknown or broken.
+// Must be called without am.mu held.
+func (am *AnimationManager) ensureDecoded(name string) (bool, error) {
+	am.mu.RLock()
+	_, decoded := am.animations[name]
+	_, registered := am.sources[name]
+	am.mu.RUnlock()
+
+	if decoded {
+		return false, nil
+	}
+	if !registered {
+		return false, fmt.Errorf("animation '%s' not loaded", name)
+	}
+	return am.decodeRegistered(name)
+}
+
+// decodeRegistered decodes a registered animation outside the lock, so
+// frames keep playing meanwhile. Returns whether this call decoded it.
+func (am *AnimationManager) decodeRegistered(name string) (bool, error) {
+	am.mu.RLock()
+	path, registered := am.sources[name]
+	am.mu.RUnlock()
+	if !registered {
+		return false, nil // Decoded by someone else in the meantime
+	}
+
+	gifData, err := decodeAnimationFile(path)
+	if err != nil {
+		// A body that is broken past its header won't decode next time
+		// either, so it stops counting as loaded
+		am.mu.Lock()
+		delete(am.sources, name)
+		am.mu.Unlock()
+		return false, err
+	}
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if _, decoded := am.animations[name]; decoded {
+		return false, nil
+	}
+	am.animations[name] = gifData
+	delete(am.sources, name)
+	return true, nil
+}
+
+// transitionClip returns the clip played when switching to name, if any
+func (am *AnimationManager) transitionClip(name string) string {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	if am.target == "" || am.target == name {
+		return ""
+	}
+	if t, ok := am.findTransition(am.target, name); ok && t.Clip != name {
+		return t.Clip
+	}
+	return ""
+}
+
+// recordSwitchLocked counts a switch to another animation as a cache hit,
+// or a miss when it waited for a decode. Must be called with am.mu held.
+func (am *AnimationManager) recordSwitchLocked(name string, decoded bool) {
+	if am.target == "" || am.target == name {
+		return // The initial animation and restarts aren't switches
+	}
+	if decoded {
+		am.cacheStats.Misses++
+	} else {
+		am.cacheStats.Hits++
+	}
+}
+
+// decodeAnimationFile decodes a GIF with at least one frame
+func decodeAnimationFile(path string) (*gif.GIF, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open animation file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	gifData, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GIF %s: %w", path, err)
+	}
+	if len(gifData.Image) == 0 {
+		return nil, fmt.Errorf("GIF file %s contains no frames", path)
+	}
+	return gifData, nil
+}
+
+// GetAnimationCacheStats returns how often animation switches found the
+// new animation already decoded
+func (c *Character) GetAnimationCacheStats() AnimationCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.animationManager.GetCacheStats()
+}
+
+// preloadLikelyAnimations starts decoding the animations most likely to
+// follow the current state. Caller must hold c.mu.
+func (c *Character) preloadLikelyAnimations() {
+	c.animationManager.Preload(c.likelyNextAnimations()...)
+}
+
+// likelyNextAnimations ranks the animations that could play after the
+// current state by the card's dialogs, interactions, critical stats and
+// transitions, most likely first. Caller must hold c.mu.
+func (c *Character) likelyNextAnimations() []string {
+	scores := make(map[string]int)
+	add := func(state string, weight int) {
+		if state == "" {
+			return
+		}
+		animation := c.applySkin(c.selectMoodAppropriateAnimation(state))
+		if animation == c.applySkin(c.currentState) {
+			return
+		}
+		scores[animation] += weight
+		// A transition clip plays first, so it is needed just as soon
+		if clip := c.animationManager.transitionClip(animation); clip != "" {
+			scores[clip] += weight
+		}
+	}
+
+	add("idle", 3) // Every other state times out back to idle
+	for _, dialog := range c.card.Dialogs {
+		weight, ok := dialogTriggerWeights[dialog.Trigger]
+		if !ok {
+			weight = 1
+		}
+		add(dialog.Animation, weight)
+	}
+	if c.gameState != nil {
+		for _, interaction := range c.card.Interactions {
+			for _, animation := range interaction.Animations {
+				add(animation, 1)
+			}
+		}
+		for _, stat := range c.gameState.GetCriticalStates() {
+			add(c.getAnimationForGameState(stat+"_critical"), 3)
+		}
+	}
+
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if scores[names[i]] != scores[names[j]] {
+			return scores[names[i]] > scores[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > preloadCount {
+		names = names[:preloadCount]
+	}
+	return names
+}
@@ -0,0 +1,151 @@
+package character
+
+import (
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writePreloadGIFs writes a small GIF for each name into dir
+func writePreloadGIFs(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		file, err := os.Create(filepath.Join(dir, name+".gif"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gif.EncodeAll(file, solidGIF(color.RGBA{255, 0, 0, 255}, 2, 10)); err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+	}
+}
+
+func TestAnimationManagerPreload(t *testing.T) {
+	dir := t.TempDir()
+	writePreloadGIFs(t, dir, "idle", "talking", "wave")
+
+	am := NewAnimationManager()
+	for _, name := range []string{"idle", "talking", "wave"} {
+		if err := am.RegisterAnimation(name, filepath.Join(dir, name+".gif")); err != nil {
+			t.Fatalf("RegisterAnimation(%s) failed: %v", name, err)
+		}
+	}
+	if got := len(am.GetLoadedAnimations()); got != 3 {
+		t.Errorf("Expected registered animations listed as loaded, got %d", got)
+	}
+	if err := am.SetCurrentAnimation("idle"); err != nil {
+		t.Fatalf("SetCurrentAnimation failed: %v", err)
+	}
+
+	am.Preload("talking")
+	am.preloads.Wait()
+	if err := am.SetCurrentAnimation("talking"); err != nil {
+		t.Fatalf("SetCurrentAnimation failed: %v", err)
+	}
+	if err := am.SetCurrentAnimation("wave"); err != nil {
+		t.Fatalf("SetCurrentAnimation failed: %v", err)
+	}
+
+	want := AnimationCacheStats{Hits: 1, Misses: 1, Preloaded: 1, HitRate: 0.5}
+	if got := am.GetCacheStats(); got != want {
+		t.Errorf("GetCacheStats() = %+v, want %+v", got, want)
+	}
+
+	notGIF := filepath.Join(dir, "notes.gif")
+	if err := os.WriteFile(notGIF, []byte("not a gif"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := am.RegisterAnimation("notes", notGIF); err == nil {
+		t.Error("Expected a file that isn't a GIF to be rejected")
+	}
+}
+
+func TestLikelyNextAnimations(t *testing.T) {
+	dir := t.TempDir()
+	writePreloadGIFs(t, dir, "idle", "talking", "wave", "sleeping", "bow")
+
+	card := &CharacterCard{
+		Name:        "Preloader",
+		Description: "Decodes ahead",
+		Animations: map[string]string{
+			"idle":     "idle.gif",
+			"talking":  "talking.gif",
+			"wave":     "wave.gif",
+			"sleeping": "sleeping.gif",
+			"bow":      "bow.gif",
+		},
+		Dialogs: []Dialog{
+			{Trigger: "click", Responses: []string{"Hi!"}, Animation: "talking", Cooldown: 5},
+			{Trigger: "hover", Responses: []string{"Oh!"}, Animation: "wave", Cooldown: 5},
+			{Trigger: "rightclick", Responses: []string{"Zzz"}, Animation: "sleeping", Cooldown: 5},
+		},
+		Transitions: []AnimationTransition{{From: "idle", To: "talking", Clip: "bow"}},
+		Behavior:    Behavior{IdleTimeout: 30, DefaultSize: 128},
+	}
+	char, err := New(card, dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	char.animationManager.preloads.Wait()
+
+	char.mu.Lock()
+	next := char.likelyNextAnimations()
+	char.mu.Unlock()
+	if want := []string{"bow", "talking", "wave"}; !reflect.DeepEqual(next, want) {
+		t.Errorf("likelyNextAnimations() = %v, want %v", next, want)
+	}
+
+	// Clicking plays the preloaded clip and dialog animation
+	char.HandleClick()
+	if stats := char.GetAnimationCacheStats(); stats.Hits != 1 || stats.Misses != 0 || stats.Preloaded != 3 {
+		t.Errorf("Expected the click to hit the preloaded animations, got %+v", stats)
+	}
+}
+
+func TestCorruptAnimationsFallBack(t *testing.T) {
+	dir := t.TempDir()
+	writePreloadGIFs(t, dir, "wave")
+
+	// A valid header with a body that doesn't decode
+	corrupt := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00\x21\xF9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02\x04\x01\x00;")
+	for _, name := range []string{"idle", "talking"} {
+		if err := os.WriteFile(filepath.Join(dir, name+".gif"), corrupt, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	card := &CharacterCard{
+		Name:        "Broken",
+		Description: "Mostly corrupt animations",
+		Animations:  map[string]string{"idle": "idle.gif", "talking": "talking.gif"},
+		Dialogs:     []Dialog{{Trigger: "click", Responses: []string{"Hi!"}, Animation: "talking", Cooldown: 5}},
+		Behavior:    Behavior{IdleTimeout: 30, DefaultSize: 128},
+	}
+	static, err := New(card, dir)
+	if err != nil {
+		t.Fatalf("Expected a static character when nothing decodes, got %v", err)
+	}
+	static.animationManager.preloads.Wait()
+
+	card.Animations["wave"] = "wave.gif"
+	char, err := New(card, dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	char.animationManager.preloads.Wait()
+	if got := char.animationManager.GetCurrentAnimationName(); got != "wave" {
+		t.Errorf("Expected the animation that decodes to be shown, got %q", got)
+	}
+	if err := char.animationManager.SetCurrentAnimation("talking"); err == nil {
+		t.Error("Expected switching to a corrupt animation to fail")
+	}
+	for _, name := range char.animationManager.GetLoadedAnimations() {
+		if name == "talking" || name == "idle" {
+			t.Errorf("Expected %s dropped once it failed to decode", name)
+		}
+	}
+}
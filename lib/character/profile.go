@@ -0,0 +1,16 @@
+package character
+
+// SetProfileName records which user profile the character is talking to,
+// so dialog backends can address them. Empty means the default profile.
+func (c *Character) SetProfileName(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.profileName = name
+}
+
+// GetProfileName returns the user profile set with SetProfileName
+func (c *Character) GetProfileName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.profileName
+}
@@ -0,0 +1,22 @@
+package character
+
+import "testing"
+
+func TestProfileNameInDialogContext(t *testing.T) {
+	char, err := New(createTestCharacterCardWithDialogBackend(), "../../testdata")
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+
+	if ctx := char.buildDialogContext("click"); ctx.ProfileName != "" {
+		t.Errorf("Expected no profile by default, got %q", ctx.ProfileName)
+	}
+
+	char.SetProfileName("kid")
+	if char.GetProfileName() != "kid" {
+		t.Errorf("Expected profile kid, got %q", char.GetProfileName())
+	}
+	if ctx := char.buildChatDialogContext("hi"); ctx.ProfileName != "kid" {
+		t.Errorf("Expected the profile in the chat context, got %q", ctx.ProfileName)
+	}
+}
@@ -38,15 +38,19 @@ type ProgressionConfig struct {
 // LevelConfig defines a character level with requirements and changes
 type LevelConfig struct {
 	Name        string            `json:"name"`
-	Requirement map[string]int64  `json:"requirement"` // age in seconds, other criteria
-	Size        int               `json:"size"`        // Character size at this level
-	Animations  map[string]string `json:"animations"`  // Animation overrides for this level
+	Requirement map[string]int64  `json:"requirement"`        // age in seconds, other criteria
+	Size        int               `json:"size"`               // Character size at this level
+	Animations  map[string]string `json:"animations"`         // Animation overrides for this level
+	Ceremony    *LevelUpCeremony  `json:"ceremony,omitempty"` // Celebration when the relationship reaches this level
 }
 
 // AchievementConfig defines an achievement with stat-based requirements
 type AchievementConfig struct {
 	Name        string                            `json:"name"`
-	Requirement map[string]map[string]interface{} `json:"requirement"` // Complex stat requirements
+	Description string                            `json:"description,omitempty"` // Shown in notifications and the achievements list
+	Requirement map[string]map[string]interface{} `json:"requirement,omitempty"` // Complex stat requirements
+	Condition   string                            `json:"condition,omitempty"`   // Expression, e.g. "happiness >= 80 && interactions.feed >= 10"
+	Duration    int                               `json:"duration,omitempty"`    // Seconds the criteria must hold before unlocking
 	Reward      *AchievementReward                `json:"reward,omitempty"`
 }
 
@@ -55,6 +59,7 @@ type AchievementReward struct {
 	StatBoosts map[string]float64 `json:"statBoosts,omitempty"` // Permanent stat increases
 	Animations map[string]string  `json:"animations,omitempty"` // Unlocked animations
 	Size       int                `json:"size,omitempty"`       // Size change
+	Coins      int                `json:"coins,omitempty"`      // Currency granted
 }
 
 // AchievementDetails contains detailed information about a newly earned achievement
@@ -153,10 +158,14 @@ func generateAchievementDescription(config *AchievementConfig) string {
 		return "Achievement unlocked!"
 	}
 
+	if config.Description != "" {
+		return config.Description
+	}
+
 	// Create a basic description based on requirements
 	// This follows the "lazy programmer" principle - simple but functional
 	switch {
-	case len(config.Requirement) == 0:
+	case len(config.Requirement) == 0 && config.Condition == "":
 		return "Achievement unlocked!"
 	case config.Requirement["happiness"] != nil:
 		return "Maintained excellent happiness level!"
@@ -225,7 +234,8 @@ func (ps *ProgressionState) checkAchievements(gameState *GameState, elapsed time
 		}
 
 		progress := ps.AchievementProgress[achievement.Name]
-		metCriteria := ps.evaluateAchievementRequirement(achievement.Requirement, gameState)
+		metCriteria := ps.evaluateAchievementRequirement(achievement.Requirement, gameState) &&
+			ps.evaluateAchievementCondition(achievement.Condition, gameState)
 
 		if ps.shouldStartProgress(metCriteria, progress) {
 			progress = ps.initializeAchievementProgress(achievement, progress)
@@ -264,6 +274,9 @@ func (ps *ProgressionState) initializeAchievementProgress(achievement Achievemen
 	progress.MetCriteria = true
 	progress.Duration = 0
 	progress.RequiredTime = ps.extractRequiredDuration(achievement.Requirement)
+	if achievement.Duration > 0 {
+		progress.RequiredTime = time.Duration(achievement.Duration) * time.Second
+	}
 	return progress
 }
 
@@ -305,14 +318,20 @@ func (ps *ProgressionState) resetAchievementProgress(progress Progress) Progress
 	progress.MetCriteria = false
 	progress.Duration = 0
 	return progress
-} // evaluateAchievementRequirement checks if current game state meets achievement criteria
+}
+
+// evaluateAchievementRequirement checks if current game state meets achievement criteria
 func (ps *ProgressionState) evaluateAchievementRequirement(requirement map[string]map[string]interface{}, gameState *GameState) bool {
 	for statName, criteria := range requirement {
 		if ps.shouldSkipStatRequirement(statName) {
 			continue
 		}
 
-		currentValue := gameState.GetStat(statName)
+		// Read the stat directly: GameState.Update holds gameState.mu while updating progression
+		var currentValue float64
+		if stat, exists := gameState.Stats[statName]; exists {
+			currentValue = stat.Current
+		}
 		if !ps.validateStatCriteria(criteria, currentValue) {
 			return false
 		}
@@ -406,6 +425,8 @@ func (ps *ProgressionState) applyAchievementReward(reward *AchievementReward, ga
 		}
 	}
 
+	gameState.addCoinsLocked(reward.Coins)
+
 	// Note: Animation and size rewards would be applied at the UI level
 	// This keeps the progression system focused on data management
 }
@@ -432,6 +453,23 @@ func (ps *ProgressionState) RecordInteraction(interactionType string) {
 	ps.InteractionCounts[interactionType]++
 }
 
+// restore sets the earned achievements and interaction counts from a save.
+// Rewards aren't applied; the saved stats already include them.
+func (ps *ProgressionState) restore(achievements []string, counts map[string]int) {
+	if ps == nil {
+		return
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.Achievements = append([]string(nil), achievements...)
+	ps.InteractionCounts = make(map[string]int, len(counts))
+	for interaction, count := range counts {
+		ps.InteractionCounts[interaction] = count
+	}
+}
+
 // GetCurrentLevel returns the current level configuration
 func (ps *ProgressionState) GetCurrentLevel() *LevelConfig {
 	if ps == nil || ps.Config == nil {
@@ -629,8 +667,14 @@ func (ps *ProgressionState) validateAchievement(achievement AchievementConfig, i
 		return fmt.Errorf("name cannot be empty")
 	}
 
-	if len(achievement.Requirement) == 0 {
-		return fmt.Errorf("must have at least one requirement")
+	if len(achievement.Requirement) == 0 && achievement.Condition == "" {
+		return fmt.Errorf("must have at least one requirement or a condition")
+	}
+
+	if achievement.Condition != "" {
+		if _, err := ParseExpression(achievement.Condition); err != nil {
+			return fmt.Errorf("invalid condition: %w", err)
+		}
 	}
 
 	return nil
@@ -212,6 +212,7 @@ func (rem *RandomEventManager) createTriggeredEvent(event RandomEventConfig, now
 		Animations:  event.Animations,
 		Responses:   event.Responses,
 		Duration:    time.Duration(event.Duration) * time.Second,
+		Buff:        event.Buff,
 	}
 }
 
@@ -226,11 +227,11 @@ func (rem *RandomEventManager) canTriggerEvent(event RandomEventConfig, now time
 	}
 
 	// Check stat conditions if specified
-	if len(event.Conditions) > 0 {
-		return gameState.CanSatisfyRequirements(event.Conditions)
+	if len(event.Conditions) > 0 && !gameState.CanSatisfyRequirements(event.Conditions) {
+		return false
 	}
 
-	return true
+	return gameState.EvaluateCondition(event.Condition)
 }
 
 // GetRandomResponse returns a random response from the event's response list
@@ -288,6 +289,7 @@ type TriggeredEvent struct {
 	Animations  []string           // Animations to play
 	Responses   []string           // Dialog responses to show
 	Duration    time.Duration      // How long the event effect lasts
+	Buff        *BuffConfig        // Temporary stat modifier to grant, nil for none
 }
 
 // HasEffects returns true if this event modifies character stats
@@ -0,0 +1,290 @@
+package character
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// maxJealousySensitivity caps how much faster than normal jealousy can build
+const maxJealousySensitivity = 3.0
+
+// tunableCrisisStats are the stats whose crisis thresholds can be tuned.
+// Jealousy starts a crisis at or above its threshold, the others at or below.
+var tunableCrisisStats = []string{"jealousy", "trust", "affection"}
+
+// RelationshipTuning adjusts how easily a romance character gets jealous and
+// falls into crisis. Cards can set it as relationshipTuning, and the user's
+// own tuning, saved per character, applies on top. Unset fields keep the
+// values derived from personality and the card's stats.
+type RelationshipTuning struct {
+	JealousySensitivity float64            `json:"jealousySensitivity,omitempty"` // Scales jealousy trigger chance and gain, 0 keeps 1.0
+	JealousyThreshold   float64            `json:"jealousyThreshold,omitempty"`   // Jealousy level where stat penalties start
+	JealousyDecayRate   *float64           `json:"jealousyDecayRate,omitempty"`   // Jealousy points lost per minute
+	TrustDecayRate      *float64           `json:"trustDecayRate,omitempty"`      // Trust points lost per minute
+	CrisisThresholds    map[string]float64 `json:"crisisThresholds,omitempty"`    // "jealousy", "trust" or "affection" to stat level
+}
+
+// Validate checks that every set value is in range
+func (t RelationshipTuning) Validate() error {
+	if t.JealousySensitivity < 0 || t.JealousySensitivity > maxJealousySensitivity {
+		return fmt.Errorf("jealousySensitivity must be 0-%g, got %g", maxJealousySensitivity, t.JealousySensitivity)
+	}
+	if t.JealousyThreshold < 0 || t.JealousyThreshold > 100 {
+		return fmt.Errorf("jealousyThreshold must be 0-100, got %g", t.JealousyThreshold)
+	}
+	if t.JealousyDecayRate != nil && *t.JealousyDecayRate < 0 {
+		return fmt.Errorf("jealousyDecayRate cannot be negative, got %g", *t.JealousyDecayRate)
+	}
+	if t.TrustDecayRate != nil && *t.TrustDecayRate < 0 {
+		return fmt.Errorf("trustDecayRate cannot be negative, got %g", *t.TrustDecayRate)
+	}
+
+	names := make([]string, 0, len(t.CrisisThresholds))
+	for name := range t.CrisisThresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !isTunableCrisisStat(name) {
+			return fmt.Errorf("unknown crisis threshold %q (expected jealousy, trust or affection)", name)
+		}
+		if value := t.CrisisThresholds[name]; value < 0 || value > 100 {
+			return fmt.Errorf("crisis threshold %q must be 0-100, got %g", name, value)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether the tuning changes nothing
+func (t RelationshipTuning) IsZero() bool {
+	return t.JealousySensitivity == 0 && t.JealousyThreshold == 0 &&
+		t.JealousyDecayRate == nil && t.TrustDecayRate == nil && len(t.CrisisThresholds) == 0
+}
+
+// merge returns t with the fields set in over replacing its own
+func (t RelationshipTuning) merge(over RelationshipTuning) RelationshipTuning {
+	merged := t
+	if over.JealousySensitivity > 0 {
+		merged.JealousySensitivity = over.JealousySensitivity
+	}
+	if over.JealousyThreshold > 0 {
+		merged.JealousyThreshold = over.JealousyThreshold
+	}
+	if over.JealousyDecayRate != nil {
+		merged.JealousyDecayRate = over.JealousyDecayRate
+	}
+	if over.TrustDecayRate != nil {
+		merged.TrustDecayRate = over.TrustDecayRate
+	}
+	if len(t.CrisisThresholds) > 0 || len(over.CrisisThresholds) > 0 {
+		merged.CrisisThresholds = make(map[string]float64, len(tunableCrisisStats))
+		for name, value := range t.CrisisThresholds {
+			merged.CrisisThresholds[name] = value
+		}
+		for name, value := range over.CrisisThresholds {
+			merged.CrisisThresholds[name] = value
+		}
+	}
+	return merged
+}
+
+// isTunableCrisisStat reports whether a crisis threshold can be tuned
+func isTunableCrisisStat(name string) bool {
+	for _, stat := range tunableCrisisStats {
+		if stat == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRelationshipTuning returns the tuning in effect, with every field
+// filled in from personality, the card and the user's changes
+func (c *Character) GetRelationshipTuning() RelationshipTuning {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tuning := c.baseRelationshipTuningLocked()
+	if c.card.RelationshipTuning != nil {
+		tuning = tuning.merge(*c.card.RelationshipTuning)
+	}
+	return tuning.merge(c.relationshipTuning)
+}
+
+// SetRelationshipTuning replaces the user's tuning, saves it for this
+// character and applies it right away
+func (c *Character) SetRelationshipTuning(tuning RelationshipTuning) error {
+	if err := tuning.Validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.relationshipTuning = tuning
+	c.applyRelationshipTuningLocked()
+
+	if c.tuningStore != nil {
+		if err := c.tuningStore.Set(c.card.Name, tuning); err != nil {
+			return fmt.Errorf("failed to save relationship tuning: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResetRelationshipTuning drops the user's tuning, going back to the card's
+// values and those derived from personality
+func (c *Character) ResetRelationshipTuning() error {
+	return c.SetRelationshipTuning(RelationshipTuning{})
+}
+
+// SetRelationshipTuningStore attaches a tuning store and applies any tuning
+// saved for this character
+func (c *Character) SetRelationshipTuningStore(store *RelationshipTuningStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tuningStore = store
+	if store == nil || c.card == nil {
+		return
+	}
+	if tuning, ok := store.Get(c.card.Name); ok {
+		if err := tuning.Validate(); err != nil {
+			log.Printf("Ignoring saved relationship tuning: %v", err)
+			return
+		}
+		c.relationshipTuning = tuning
+		c.applyRelationshipTuningLocked()
+	}
+}
+
+// baseRelationshipTuningLocked returns the values derived from personality
+// and the card's stats; caller must hold c.mu
+func (c *Character) baseRelationshipTuningLocked() RelationshipTuning {
+	tuning := RelationshipTuning{
+		JealousySensitivity: 1.0,
+		JealousyThreshold:   c.personalityJealousyThreshold(),
+		CrisisThresholds:    make(map[string]float64, len(tunableCrisisStats)),
+	}
+
+	thresholds := c.createPersonalityBasedCrisisThresholds()
+	for _, name := range tunableCrisisStats {
+		tuning.CrisisThresholds[name] = thresholds[name]
+	}
+
+	if stat, ok := c.card.Stats["jealousy"]; ok {
+		rate := stat.DegradationRate
+		tuning.JealousyDecayRate = &rate
+	}
+	if stat, ok := c.card.Stats["trust"]; ok {
+		rate := stat.DegradationRate
+		tuning.TrustDecayRate = &rate
+	}
+	return tuning
+}
+
+// applyRelationshipTuningLocked feeds the tuning in effect to the jealousy
+// and crisis managers and the stat decay rates; caller must hold c.mu
+func (c *Character) applyRelationshipTuningLocked() {
+	tuning := c.baseRelationshipTuningLocked()
+	if c.card.RelationshipTuning != nil {
+		tuning = tuning.merge(*c.card.RelationshipTuning)
+	}
+	tuning = tuning.merge(c.relationshipTuning)
+
+	if c.jealousyManager != nil {
+		c.jealousyManager.SetTuning(tuning.JealousyThreshold, tuning.JealousySensitivity)
+	}
+	if c.crisisRecoveryManager != nil {
+		c.crisisRecoveryManager.SetThresholds(tuning.CrisisThresholds)
+	}
+	if c.gameState != nil {
+		if tuning.JealousyDecayRate != nil {
+			c.gameState.setDegradationRate("jealousy", *tuning.JealousyDecayRate)
+		}
+		if tuning.TrustDecayRate != nil {
+			c.gameState.setDegradationRate("trust", *tuning.TrustDecayRate)
+		}
+	}
+}
+
+// RelationshipTuningStore persists the relationship tuning each user chose
+// per character
+type RelationshipTuningStore struct {
+	mu      sync.Mutex
+	path    string
+	tunings map[string]RelationshipTuning // Character name -> tuning
+}
+
+// RelationshipTuningFileName is the tuning file in the user config dir or a
+// profile's memory directory
+const RelationshipTuningFileName = "relationship_tuning.json"
+
+// DefaultRelationshipTuningStorePath returns the tuning file in the user config dir
+func DefaultRelationshipTuningStorePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "desktop-companion", RelationshipTuningFileName), nil
+}
+
+// NewRelationshipTuningStore creates a store backed by path, loading saved
+// tunings if present
+func NewRelationshipTuningStore(path string) (*RelationshipTuningStore, error) {
+	s := &RelationshipTuningStore{
+		path:    path,
+		tunings: make(map[string]RelationshipTuning),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relationship tuning file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.tunings); err != nil {
+		return nil, fmt.Errorf("failed to parse relationship tuning file: %w", err)
+	}
+
+	return s, nil
+}
+
+// Get returns the saved tuning for a character
+func (s *RelationshipTuningStore) Get(name string) (RelationshipTuning, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tuning, ok := s.tunings[name]
+	return tuning, ok
+}
+
+// Set records a character's tuning and writes the file. An empty tuning
+// forgets the character.
+func (s *RelationshipTuningStore) Set(name string, tuning RelationshipTuning) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tuning.IsZero() {
+		delete(s.tunings, name)
+	} else {
+		s.tunings[name] = tuning
+	}
+
+	data, err := json.MarshalIndent(s.tunings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode relationship tuning: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write relationship tuning file: %w", err)
+	}
+	return nil
+}
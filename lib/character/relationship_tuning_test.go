@@ -0,0 +1,113 @@
+package character
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRelationshipTuningFeedsManagers(t *testing.T) {
+	card := createRomanceCharacterCard()
+	decay := 0.5
+	card.RelationshipTuning = &RelationshipTuning{JealousyDecayRate: &decay}
+	char := createTestCharacterWithRomanceFeatures(card, true)
+
+	// The card's tuning replaces the stat's own decay rate
+	if rate := char.GetGameState().Stats["jealousy"].DegradationRate; rate != 0.5 {
+		t.Errorf("Expected the card's jealousy decay 0.5, got %v", rate)
+	}
+
+	trustDecay := 0.0
+	err := char.SetRelationshipTuning(RelationshipTuning{
+		JealousySensitivity: 2.0,
+		JealousyThreshold:   50,
+		TrustDecayRate:      &trustDecay,
+		CrisisThresholds:    map[string]float64{"trust": 40},
+	})
+	if err != nil {
+		t.Fatalf("SetRelationshipTuning failed: %v", err)
+	}
+
+	status := char.jealousyManager.GetStatus(char.GetGameState())
+	if status["threshold"] != 50.0 || status["sensitivity"] != 2.0 {
+		t.Errorf("Expected the jealousy manager to be tuned, got %v", status)
+	}
+	thresholds := char.crisisRecoveryManager.GetCrisisStatus()["thresholds"].(map[string]float64)
+	if thresholds["trust"] != 40 || thresholds["happiness"] != 20 {
+		t.Errorf("Expected trust tuned and untuned thresholds kept, got %v", thresholds)
+	}
+	if rate := char.GetGameState().Stats["trust"].DegradationRate; rate != 0 {
+		t.Errorf("Expected trust decay turned off, got %v", rate)
+	}
+	if rate := char.GetGameState().Stats["jealousy"].DegradationRate; rate != 0.5 {
+		t.Errorf("Expected the card's jealousy decay to stay, got %v", rate)
+	}
+
+	// Resetting goes back to the card's values and personality defaults
+	if err := char.ResetRelationshipTuning(); err != nil {
+		t.Fatal(err)
+	}
+	tuning := char.GetRelationshipTuning()
+	if tuning.JealousySensitivity != 1.0 || tuning.JealousyThreshold != char.personalityJealousyThreshold() {
+		t.Errorf("Expected personality defaults after reset, got %+v", tuning)
+	}
+	if *tuning.TrustDecayRate != 0.05 || *tuning.JealousyDecayRate != 0.5 {
+		t.Errorf("Expected card decay rates after reset, got %v %v", *tuning.TrustDecayRate, *tuning.JealousyDecayRate)
+	}
+}
+
+func TestRelationshipTuningPersists(t *testing.T) {
+	card := createRomanceCharacterCard()
+	char := createTestCharacterWithRomanceFeatures(card, true)
+
+	path := filepath.Join(t.TempDir(), "relationship_tuning.json")
+	store, err := NewRelationshipTuningStore(path)
+	if err != nil {
+		t.Fatalf("NewRelationshipTuningStore failed: %v", err)
+	}
+	char.SetRelationshipTuningStore(store)
+	if err := char.SetRelationshipTuning(RelationshipTuning{CrisisThresholds: map[string]float64{"jealousy": 95}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh character picks up the saved tuning
+	reloaded, err := NewRelationshipTuningStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reload tuning store: %v", err)
+	}
+	other := createTestCharacterWithRomanceFeatures(card, true)
+	other.SetRelationshipTuningStore(reloaded)
+	if got := other.GetRelationshipTuning().CrisisThresholds["jealousy"]; got != 95 {
+		t.Errorf("Expected saved jealousy crisis threshold 95, got %v", got)
+	}
+
+	// Resetting forgets the character
+	if err := other.ResetRelationshipTuning(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reloaded.Get(card.Name); ok {
+		t.Error("Expected reset to remove the saved tuning")
+	}
+}
+
+func TestRelationshipTuningValidate(t *testing.T) {
+	negative := -1.0
+	tests := map[string]RelationshipTuning{
+		"sensitivity too high": {JealousySensitivity: 5},
+		"threshold too high":   {JealousyThreshold: 120},
+		"negative decay":       {TrustDecayRate: &negative},
+		"unknown crisis stat":  {CrisisThresholds: map[string]float64{"hunger": 10}},
+		"crisis out of range":  {CrisisThresholds: map[string]float64{"trust": -5}},
+	}
+	for name, tuning := range tests {
+		if err := tuning.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	card := createRomanceCharacterCard()
+	card.RelationshipTuning = &RelationshipTuning{JealousySensitivity: 5}
+	if err := card.Validate(); err == nil || !strings.Contains(err.Error(), "relationship tuning") {
+		t.Errorf("Expected card validation to reject the tuning, got %v", err)
+	}
+}
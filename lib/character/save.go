@@ -0,0 +1,139 @@
+package character
+
+import (
+	"math"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+)
+
+// SaveData snapshots the game state for the save manager. Returns nil when
+// game features are off, which the auto-save loop treats as nothing to save.
+func (c *Character) SaveData() *persistence.GameSaveData {
+	gs := c.GetGameState()
+	if gs == nil {
+		return nil
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	state := &persistence.GameStateData{
+		Stats:              make(map[string]*persistence.StatData, len(gs.Stats)),
+		LastDecayUpdate:    gs.LastDecayUpdate,
+		CreationTime:       gs.CreationTime,
+		TotalPlayTimeNanos: int64(gs.TotalPlayTime),
+		Coins:              gs.Coins,
+		Purchases:          copyCounts(gs.Purchases),
+		Inventory:          copyCounts(gs.Inventory),
+		ActiveSkin:         gs.ActiveSkin,
+		UserBirthday:       gs.UserBirthday,
+		CalendarFired:      copyCounts(gs.CalendarFired),
+		ScheduleFired:      copyTimes(gs.ScheduleFired),
+		LastInteractions:   copyTimes(gs.LastInteractions),
+	}
+	for _, entry := range gs.Journal {
+		state.Journal = append(state.Journal, persistence.JournalEntryData(entry))
+	}
+	for _, buff := range gs.Buffs {
+		state.Buffs = append(state.Buffs, persistence.BuffData(buff))
+	}
+	if gs.ActiveJob != nil {
+		state.ActiveJob = &persistence.JobData{ID: gs.ActiveJob.ID, StartedAt: gs.ActiveJob.StartedAt, EndsAt: gs.ActiveJob.EndsAt}
+	}
+	for name, stat := range gs.Stats {
+		state.Stats[name] = &persistence.StatData{
+			Current:           stat.Current,
+			Max:               stat.Max,
+			DegradationRate:   stat.DegradationRate,
+			CriticalThreshold: stat.CriticalThreshold,
+		}
+	}
+	if gs.Progression != nil {
+		state.Achievements = gs.Progression.GetAchievements()
+		state.InteractionCounts = gs.Progression.GetInteractionCounts()
+	}
+
+	return &persistence.GameSaveData{
+		CharacterName: c.GetName(),
+		GameState:     state,
+	}
+}
+
+// RestoreSaveData applies a saved game on top of the card's fresh game state.
+// Stats the card no longer defines are dropped; new stats keep card defaults.
+func (c *Character) RestoreSaveData(data *persistence.GameSaveData) {
+	gs := c.GetGameState()
+	if gs == nil || data == nil || data.GameState == nil {
+		return
+	}
+	saved := data.GameState
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	for name, stat := range saved.Stats {
+		if current, exists := gs.Stats[name]; exists {
+			current.Current = math.Min(current.Max, math.Max(0, stat.Current))
+		}
+	}
+	if !saved.CreationTime.IsZero() {
+		gs.CreationTime = saved.CreationTime
+	}
+	if !saved.LastDecayUpdate.IsZero() {
+		// Stats decay for the time the app was closed on the next update,
+		// except those that pause while away
+		gs.LastDecayUpdate = saved.LastDecayUpdate
+		gs.resumedAt = time.Now()
+	}
+	gs.TotalPlayTime = time.Duration(saved.TotalPlayTimeNanos)
+	gs.Coins = saved.Coins
+	gs.Purchases = copyCounts(saved.Purchases)
+	gs.Inventory = copyCounts(saved.Inventory)
+	gs.ActiveSkin = saved.ActiveSkin
+	gs.UserBirthday = saved.UserBirthday
+	gs.CalendarFired = copyCounts(saved.CalendarFired)
+	gs.ScheduleFired = copyTimes(saved.ScheduleFired)
+	gs.LastInteractions = copyTimes(saved.LastInteractions)
+	gs.Journal = nil
+	for _, entry := range saved.Journal {
+		gs.addJournalEntry(entry.Kind, entry.Title, entry.Detail, entry.Time)
+	}
+	// Buffs keep running while the app is closed; expired ones are dropped
+	gs.Buffs = nil
+	for _, buff := range saved.Buffs {
+		gs.Buffs = append(gs.Buffs, ActiveBuff(buff))
+	}
+	gs.pruneBuffs(time.Now())
+	// Earned achievements stay earned without paying their rewards again
+	gs.Progression.restore(saved.Achievements, saved.InteractionCounts)
+	gs.ActiveJob = nil
+	if saved.ActiveJob != nil {
+		// A job that ended while the app was closed completes on the next update
+		gs.ActiveJob = &ActiveJob{ID: saved.ActiveJob.ID, StartedAt: saved.ActiveJob.StartedAt, EndsAt: saved.ActiveJob.EndsAt}
+	}
+}
+
+// copyCounts copies a name -> count map, keeping nil as nil
+func copyCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return nil
+	}
+	copied := make(map[string]int, len(counts))
+	for name, count := range counts {
+		copied[name] = count
+	}
+	return copied
+}
+
+// copyTimes copies a name -> time map, keeping nil as nil
+func copyTimes(times map[string]time.Time) map[string]time.Time {
+	if times == nil {
+		return nil
+	}
+	copied := make(map[string]time.Time, len(times))
+	for name, at := range times {
+		copied[name] = at
+	}
+	return copied
+}
@@ -0,0 +1,89 @@
+package character
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+)
+
+func TestCharacterSaveDataRoundTrip(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	gs := char.GetGameState()
+	if gs == nil {
+		t.Fatal("Expected game state")
+	}
+
+	gs.mu.Lock()
+	for _, stat := range gs.Stats {
+		stat.Current = 42
+	}
+	gs.Coins = 7
+	gs.Inventory = map[string]int{"cookie": 2}
+	fedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	gs.LastInteractions = map[string]time.Time{"feed": fedAt}
+	gs.mu.Unlock()
+
+	manager := persistence.NewSaveManager(t.TempDir())
+	defer manager.Close()
+	if err := manager.SaveGameState(char.GetName(), char.SaveData()); err != nil {
+		t.Fatalf("SaveGameState failed: %v", err)
+	}
+	loaded, err := manager.LoadGameState(char.GetName())
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadGameState failed: %v", err)
+	}
+
+	fresh := createTestCharacterInstance(createTestGameCharacterCard(), true)
+	fresh.RestoreSaveData(loaded)
+	for name, value := range fresh.GetGameState().GetStats() {
+		if value != 42 {
+			t.Errorf("Expected restored %s of 42, got %v", name, value)
+		}
+	}
+	if fresh.GetGameState().GetCoins() != 7 || fresh.GetGameState().Inventory["cookie"] != 2 {
+		t.Errorf("Expected coins and inventory restored, got %+v", fresh.GetGameState())
+	}
+	if got := fresh.GetGameState().LastInteractions["feed"]; !got.Equal(fedAt) {
+		t.Errorf("Expected the last feeding restored for since.feed, got %v", got)
+	}
+}
+
+func TestCharacterSaveDataWithoutGame(t *testing.T) {
+	char := createTestCharacterInstance(createTestCharacterCardWithDialogBackend(), false)
+	if char.SaveData() != nil {
+		t.Error("Characters without game features have nothing to save")
+	}
+	char.RestoreSaveData(&persistence.GameSaveData{}) // Must not panic
+}
+
+func TestCharacterSaveDataKeepsAchievements(t *testing.T) {
+	newChar := func() *Character {
+		card := createTestGameCharacterCard()
+		card.Progression = &ProgressionConfig{Achievements: []AchievementConfig{{
+			Name:      "First Meal",
+			Condition: "interactions.feed >= 1",
+			Reward:    &AchievementReward{Coins: 5},
+		}}}
+		return createTestCharacterInstance(card, true)
+	}
+
+	char := newChar()
+	char.GetGameState().RecordInteraction("feed")
+	if earned := char.GetGameState().Update(time.Second); len(earned) != 1 || char.GetGameState().GetCoins() != 5 {
+		t.Fatalf("Expected the achievement earned with its reward, got %v and %d coins", earned, char.GetGameState().GetCoins())
+	}
+
+	fresh := newChar()
+	fresh.RestoreSaveData(char.SaveData())
+	gs := fresh.GetGameState()
+	if got := gs.GetProgression().GetAchievements(); len(got) != 1 || got[0] != "First Meal" {
+		t.Errorf("Expected the achievement restored, got %v", got)
+	}
+	if got := gs.GetProgression().GetInteractionCounts()["feed"]; got != 1 {
+		t.Errorf("Expected interaction counts restored, got %d", got)
+	}
+	if earned := gs.Update(time.Second); len(earned) != 0 || gs.GetCoins() != 5 {
+		t.Errorf("Expected no second unlock or reward, got %v and %d coins", earned, gs.GetCoins())
+	}
+}
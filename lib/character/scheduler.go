@@ -0,0 +1,476 @@
+package character
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleGrace is how late a scheduled event may still fire, e.g. after
+// waking up or starting the app. Older occurrences are skipped so a morning
+// greeting doesn't show up in the evening.
+const scheduleGrace = time.Hour
+
+// ScheduledEventConfig fires at fixed times instead of by chance.
+// When accepts:
+//   - "every 2h", "every 45m": a fixed interval
+//   - "every day 09:00", "every weekday 18:30", "every mon,wed 12:00": clock times
+//   - "0 9 * * 1-5": a standard five-field cron expression
+//   - "at 2026-12-31 23:59": a single date and time
+type ScheduledEventConfig struct {
+	Name       string                        `json:"name"`
+	When       string                        `json:"when"`
+	Once       bool                          `json:"once,omitempty"` // Fire the first time only, ever
+	Responses  []string                      `json:"responses,omitempty"`
+	Animation  string                        `json:"animation,omitempty"`
+	Effects    map[string]float64            `json:"effects,omitempty"`    // Stat changes, may include coins
+	Conditions map[string]map[string]float64 `json:"conditions,omitempty"` // Stat conditions required to fire
+	Condition  string                        `json:"condition,omitempty"`  // Expression condition required to fire
+}
+
+// ScheduledResult describes a scheduled event that just fired, for UI notifications
+type ScheduledResult struct {
+	Name     string
+	Response string
+}
+
+// Schedule computes when a scheduled event fires next
+type Schedule interface {
+	// Next returns the first fire time strictly after t, or the zero time
+	// if the schedule never fires again
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a scheduled event's "when" expression
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	lower := strings.ToLower(expr)
+
+	switch {
+	case strings.HasPrefix(lower, "every "):
+		return parseEverySchedule(strings.TrimSpace(lower[len("every "):]))
+	case strings.HasPrefix(lower, "at "):
+		at, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(expr[len("at "):]), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD HH:MM", expr)
+		}
+		return atSchedule(at), nil
+	case len(strings.Fields(expr)) == 5:
+		return parseCronSchedule(expr)
+	}
+	return nil, fmt.Errorf("unrecognized schedule %q", expr)
+}
+
+// intervalSchedule fires every fixed duration
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// atSchedule fires once at a fixed time
+type atSchedule time.Time
+
+func (s atSchedule) Next(t time.Time) time.Time {
+	if at := time.Time(s); at.After(t) {
+		return at
+	}
+	return time.Time{}
+}
+
+// clockSchedule fires at a time of day on selected weekdays
+type clockSchedule struct {
+	days   [7]bool // Indexed by time.Weekday
+	minute int     // Minutes after midnight
+}
+
+// Next builds each candidate from the wall clock rather than adding minutes to
+// midnight, so the time of day stays put across daylight saving changes
+func (s clockSchedule) Next(t time.Time) time.Time {
+	for i := 0; i < 8; i++ {
+		candidate := time.Date(t.Year(), t.Month(), t.Day()+i, s.minute/60, s.minute%60, 0, 0, t.Location())
+		if wall := candidate.Hour()*60 + candidate.Minute(); wall != s.minute {
+			// The time falls in a spring-forward gap; fire when the clock jumps past it
+			candidate = candidate.Add(time.Duration((s.minute-wall+24*60)%(24*60)) * time.Minute)
+		}
+		if s.days[candidate.Weekday()] && candidate.After(t) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// scheduleDays maps day words to the weekdays they cover
+var scheduleDays = map[string][]time.Weekday{
+	"day":     {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+	"weekday": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekend": {time.Saturday, time.Sunday},
+}
+
+func init() {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		scheduleDays[name] = []time.Weekday{day}
+		scheduleDays[name[:3]] = []time.Weekday{day}
+	}
+}
+
+// parseEverySchedule parses what follows "every": a duration or days and a clock time
+func parseEverySchedule(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 1 {
+		every, err := time.ParseDuration(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", fields[0], err)
+		}
+		if every < time.Minute {
+			return nil, fmt.Errorf("interval must be at least 1m, got %s", every)
+		}
+		return intervalSchedule(every), nil
+	}
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("expected \"every <interval>\" or \"every <days> HH:MM\", got %q", "every "+spec)
+	}
+
+	var schedule clockSchedule
+	for _, name := range strings.Split(fields[0], ",") {
+		days, ok := scheduleDays[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", name)
+		}
+		for _, day := range days {
+			schedule.days[day] = true
+		}
+	}
+	minute, err := parseClockTime(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	schedule.minute = minute
+	return schedule, nil
+}
+
+// cronSchedule is a five-field cron expression: minute hour day-of-month month day-of-week
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // Bit sets of allowed values
+	domStar, dowStar              bool   // Unrestricted fields, for cron's day OR rule
+}
+
+// cronFields lists the value range of each cron field
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// parseCronSchedule parses "m h dom mon dow" with *, lists, ranges and steps
+func parseCronSchedule(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron %s: %w", cronFields[i].name, err)
+		}
+		sets[i] = set
+	}
+
+	// Both 0 and 7 mean Sunday
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domStar: fields[2] == "*", dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField turns "*/15", "1-5", "0,30" and the like into a bit set
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = before, n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0) // Impossible dates like Feb 30 never match
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's rule that a restricted day-of-month and
+// day-of-week match when either does
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// EventScheduler tracks when each scheduled event fires next
+type EventScheduler struct {
+	events    []ScheduledEventConfig
+	schedules []Schedule
+	next      []time.Time
+	started   time.Time
+}
+
+// NewEventScheduler parses the events' schedules. Events that never fired
+// count from now, so starting the app doesn't replay old occurrences.
+func NewEventScheduler(events []ScheduledEventConfig, now time.Time) (*EventScheduler, error) {
+	s := &EventScheduler{
+		events:    events,
+		schedules: make([]Schedule, len(events)),
+		next:      make([]time.Time, len(events)),
+		started:   now,
+	}
+	for i, event := range events {
+		schedule, err := ParseSchedule(event.When)
+		if err != nil {
+			return nil, fmt.Errorf("event '%s': %w", event.Name, err)
+		}
+		s.schedules[i] = schedule
+	}
+	return s, nil
+}
+
+// Due returns the events due at now and moves each to its next occurrence.
+// lastFired reports when an event last fired, zero if never; once-only
+// events that already fired are never due again.
+func (s *EventScheduler) Due(now time.Time, lastFired func(name string) time.Time) []ScheduledEventConfig {
+	var due []ScheduledEventConfig
+	for i, event := range s.events {
+		last := lastFired(event.Name)
+		if event.Once && !last.IsZero() {
+			continue
+		}
+
+		if s.next[i].IsZero() {
+			base := s.started
+			if !last.IsZero() {
+				base = last
+			}
+			s.next[i] = s.schedules[i].Next(base)
+		}
+
+		next := s.next[i]
+		if next.IsZero() || now.Before(next) {
+			continue
+		}
+		s.next[i] = s.schedules[i].Next(now)
+		if now.Sub(next) > scheduleGrace {
+			continue
+		}
+		due = append(due, event)
+	}
+	return due
+}
+
+// validateSchedule validates the optional scheduled events section
+func (c *CharacterCard) validateSchedule() error {
+	seen := make(map[string]bool)
+	for i, event := range c.Schedule {
+		if event.Name == "" {
+			return fmt.Errorf("event %d: name is required", i)
+		}
+		if seen[event.Name] {
+			return fmt.Errorf("duplicate event name '%s'", event.Name)
+		}
+		seen[event.Name] = true
+
+		if err := c.validateScheduledEvent(event); err != nil {
+			return fmt.Errorf("event '%s': %w", event.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateScheduledEvent checks the expression and references of one event
+func (c *CharacterCard) validateScheduledEvent(event ScheduledEventConfig) error {
+	if _, err := ParseSchedule(event.When); err != nil {
+		return err
+	}
+
+	if event.Animation != "" {
+		if _, exists := c.Animations[event.Animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", event.Animation)
+		}
+	}
+
+	if (len(event.Effects) > 0 || len(event.Conditions) > 0 || event.Condition != "") && !c.HasGameFeatures() {
+		return fmt.Errorf("effects and conditions require stats to be defined")
+	}
+	for stat := range event.Conditions {
+		if _, exists := c.Stats[stat]; !exists {
+			return fmt.Errorf("condition references unknown stat '%s'", stat)
+		}
+	}
+	if event.Condition != "" {
+		if err := validateConditionIdentifiers(event.Condition, c.Stats); err != nil {
+			return err
+		}
+	}
+	return c.validateJobStats(event.Effects)
+}
+
+// updateSchedule fires scheduled events that are due. Checked at most once
+// a minute and only while awake. Must be called with c.mu held.
+// Returns true if the animation state changed.
+func (c *Character) updateSchedule(now time.Time) bool {
+	if len(c.card.Schedule) == 0 || c.sleeping || now.Sub(c.lastScheduleCheck) < time.Minute {
+		return false
+	}
+	c.lastScheduleCheck = now
+
+	if c.scheduler == nil {
+		scheduler, err := NewEventScheduler(c.card.Schedule, now)
+		if err != nil {
+			// The card was validated, so this only happens for hand-built cards
+			c.card.Schedule = nil
+			return false
+		}
+		c.scheduler = scheduler
+	}
+
+	changed := false
+	for _, event := range c.scheduler.Due(now, c.scheduleLastFired) {
+		if !c.gameState.CanSatisfyRequirements(event.Conditions) {
+			continue
+		}
+		if event.Condition != "" && !c.gameState.EvaluateCondition(event.Condition) {
+			continue
+		}
+
+		c.markScheduleFired(event.Name, now)
+		c.scheduledResults = append(c.scheduledResults, c.fireScheduledEvent(event))
+
+		if event.Animation != "" {
+			previousState := c.currentState
+			c.setState(event.Animation)
+			changed = changed || c.currentState != previousState
+		}
+	}
+	return changed
+}
+
+// fireScheduledEvent applies effects and builds the result.
+// Must be called with c.mu held.
+func (c *Character) fireScheduledEvent(event ScheduledEventConfig) ScheduledResult {
+	result := ScheduledResult{Name: event.Name}
+	if len(event.Responses) > 0 {
+		result.Response = event.Responses[rand.Intn(len(event.Responses))]
+	}
+	if c.gameState != nil {
+		c.gameState.ApplyInteractionEffects(event.Effects)
+	}
+	return result
+}
+
+// GetScheduledEvents returns scheduled events fired since the last call and clears the list
+func (c *Character) GetScheduledEvents() []ScheduledResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := c.scheduledResults
+	c.scheduledResults = nil
+	return results
+}
+
+// scheduleLastFired returns when a scheduled event last fired. Must be called with c.mu held.
+func (c *Character) scheduleLastFired(name string) time.Time {
+	if c.gameState != nil {
+		return c.gameState.GetScheduleFired(name)
+	}
+	return c.localScheduleFired[name]
+}
+
+// markScheduleFired records when a scheduled event fired. Must be called with c.mu held.
+func (c *Character) markScheduleFired(name string, at time.Time) {
+	if c.gameState != nil {
+		c.gameState.SetScheduleFired(name, at)
+		return
+	}
+	if c.localScheduleFired == nil {
+		c.localScheduleFired = make(map[string]time.Time)
+	}
+	c.localScheduleFired[name] = at
+}
+
+// GetScheduleFired returns when a scheduled event last fired, zero if never
+func (gs *GameState) GetScheduleFired(name string) time.Time {
+	if gs == nil {
+		return time.Time{}
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.ScheduleFired[name]
+}
+
+// SetScheduleFired records when a scheduled event fired
+func (gs *GameState) SetScheduleFired(name string, at time.Time) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.ScheduleFired == nil {
+		gs.ScheduleFired = make(map[string]time.Time)
+	}
+	gs.ScheduleFired[name] = at
+}
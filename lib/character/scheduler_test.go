@@ -0,0 +1,207 @@
+package character
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Thursday 2026-01-01 08:00
+	base := time.Date(2026, 1, 1, 8, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"every 2h", base.Add(2 * time.Hour)},
+		{"every 1h30m", base.Add(90 * time.Minute)},
+		{"every day 09:00", time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local)},
+		{"every day 07:00", time.Date(2026, 1, 2, 7, 0, 0, 0, time.Local)},
+		{"Every Weekend 10:00", time.Date(2026, 1, 3, 10, 0, 0, 0, time.Local)},
+		{"every mon,wed 12:00", time.Date(2026, 1, 5, 12, 0, 0, 0, time.Local)},
+		{"every weekday 08:00", time.Date(2026, 1, 2, 8, 0, 0, 0, time.Local)},
+		{"30 9 * * 1-5", time.Date(2026, 1, 1, 9, 30, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2026, 1, 1, 8, 15, 0, 0, time.Local)},
+		{"0 0 1 3 *", time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"0 12 * * 7", time.Date(2026, 1, 4, 12, 0, 0, 0, time.Local)},
+		{"0 12 15 * 5", time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local)}, // Day of month OR day of week
+		{"at 2026-12-31 23:59", time.Date(2026, 12, 31, 23, 59, 0, 0, time.Local)},
+		{"at 2025-12-31 23:59", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q: next after %s = %s, want %s", tt.expr, base, got, tt.want)
+		}
+	}
+}
+
+func TestClockScheduleAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// Clocks spring forward on 2026-03-08 and fall back on 2026-11-01
+		{"every day 09:00", time.Date(2026, 3, 7, 10, 0, 0, 0, newYork), time.Date(2026, 3, 8, 9, 0, 0, 0, newYork)},
+		{"every day 09:00", time.Date(2026, 10, 31, 10, 0, 0, 0, newYork), time.Date(2026, 11, 1, 9, 0, 0, 0, newYork)},
+		{"every sun 18:30", time.Date(2026, 3, 2, 12, 0, 0, 0, newYork), time.Date(2026, 3, 8, 18, 30, 0, 0, newYork)},
+		// 02:30 doesn't exist on the spring-forward day; it lands on 03:30
+		{"every day 02:30", time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), time.Date(2026, 3, 8, 3, 30, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) failed: %v", tt.expr, err)
+		}
+		got := schedule.Next(tt.from)
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: next after %s = %s, want %s", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"sometimes",
+		"every 10s",
+		"every fortnight 09:00",
+		"every day 25:00",
+		"every day at 09:00",
+		"60 * * * *",
+		"* * * * 1-9",
+		"*/0 * * * *",
+		"at tomorrow",
+	} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) should fail", expr)
+		}
+	}
+}
+
+func TestEventSchedulerDue(t *testing.T) {
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.Local)
+	events := []ScheduledEventConfig{
+		{Name: "greeting", When: "every day 09:00"},
+		{Name: "hello", When: "every 1h", Once: true},
+	}
+	scheduler, err := NewEventScheduler(events, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fired := map[string]time.Time{}
+	lastFired := func(name string) time.Time { return fired[name] }
+	dueAt := func(now time.Time) []string {
+		var names []string
+		for _, event := range scheduler.Due(now, lastFired) {
+			fired[event.Name] = now
+			names = append(names, event.Name)
+		}
+		return names
+	}
+
+	if due := dueAt(start.Add(30 * time.Minute)); len(due) != 0 {
+		t.Errorf("Nothing should be due yet, got %v", due)
+	}
+	if due := dueAt(start.Add(time.Hour)); len(due) != 2 {
+		t.Errorf("Expected greeting and hello at 09:00, got %v", due)
+	}
+	if due := dueAt(start.Add(2 * time.Hour)); len(due) != 0 {
+		t.Errorf("Once-only event should not repeat, got %v", due)
+	}
+
+	// Next morning the app is busy until noon: the 09:00 greeting is too late
+	if due := dueAt(time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local)); len(due) != 0 {
+		t.Errorf("Occurrences past the grace period should be skipped, got %v", due)
+	}
+	if due := dueAt(time.Date(2026, 1, 3, 9, 20, 0, 0, time.Local)); len(due) != 1 {
+		t.Errorf("Late occurrence within the grace period should fire, got %v", due)
+	}
+}
+
+func TestEventSchedulerResumesFromLastFired(t *testing.T) {
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.Local)
+	scheduler, err := NewEventScheduler([]ScheduledEventConfig{{Name: "stretch", When: "every 2h"}}, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Last fired before the restart, so the interval continues from then
+	lastFired := func(string) time.Time { return start.Add(-90 * time.Minute) }
+	if due := scheduler.Due(start.Add(30*time.Minute), lastFired); len(due) != 1 {
+		t.Errorf("Expected interval to resume from the saved fire time, got %v", due)
+	}
+}
+
+func TestCharacterScheduledEvents(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Schedule = []ScheduledEventConfig{
+		{Name: "reminder", When: "every 1m", Responses: []string{"Drink some water!"}, Animation: "happy", Effects: map[string]float64{"coins": 5}},
+		{Name: "hungry", When: "every 1m", Responses: []string{"Snack time?"}, Condition: "hunger < 10"},
+	}
+	if err := card.validateSchedule(); err != nil {
+		t.Fatalf("validateSchedule failed: %v", err)
+	}
+	char := createTestCharacterInstance(card, true)
+	now := time.Now()
+
+	char.mu.Lock()
+	char.updateSchedule(now)
+	char.mu.Unlock()
+	if results := char.GetScheduledEvents(); len(results) != 0 {
+		t.Fatalf("Nothing should fire on the first check, got %+v", results)
+	}
+
+	char.mu.Lock()
+	char.updateSchedule(now.Add(time.Minute))
+	char.mu.Unlock()
+
+	results := char.GetScheduledEvents()
+	if len(results) != 1 || results[0].Response != "Drink some water!" {
+		t.Fatalf("Expected only the reminder, got %+v", results)
+	}
+	if char.GetGameState().GetCoins() != 5 {
+		t.Errorf("Expected 5 coins, got %d", char.GetGameState().GetCoins())
+	}
+	if char.GetGameState().GetScheduleFired("reminder").IsZero() {
+		t.Error("Fire time should be saved in the game state")
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	card := &CharacterCard{
+		Animations: map[string]string{"idle": "idle.gif"},
+		Schedule:   []ScheduledEventConfig{{Name: "bad", When: "every now and then"}},
+	}
+	if err := card.validateSchedule(); err == nil {
+		t.Error("Invalid expression should fail validation")
+	}
+
+	card.Schedule = []ScheduledEventConfig{{Name: "a", When: "every 1h"}, {Name: "a", When: "every 2h"}}
+	if err := card.validateSchedule(); err == nil {
+		t.Error("Duplicate names should fail validation")
+	}
+
+	card.Schedule = []ScheduledEventConfig{{Name: "a", When: "every 1h", Effects: map[string]float64{"hunger": 5}}}
+	if err := card.validateSchedule(); err == nil {
+		t.Error("Effects without stats should fail validation")
+	}
+
+	card.Schedule = []ScheduledEventConfig{{Name: "a", When: "every 1h", Animation: "dance"}}
+	if err := card.validateSchedule(); err == nil {
+		t.Error("Unknown animation should fail validation")
+	}
+}
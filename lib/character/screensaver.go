@@ -0,0 +1,144 @@
+package character
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Screensaver defaults used when the card leaves optional fields empty
+const (
+	defaultScreensaverIdleMinutes = 15
+	defaultShowcaseSeconds        = 20
+)
+
+// ScreensaverConfig lets the character take over the screen after a long
+// time without input: the window goes fullscreen and the character plays
+// through a showcase of animations until the user comes back.
+type ScreensaverConfig struct {
+	Enabled             bool     `json:"enabled"`
+	IdleMinutes         int      `json:"idleMinutes,omitempty"`         // Minutes without input before the takeover (default: 15)
+	Animations          []string `json:"animations,omitempty"`          // Showcase played in order and looped (default: every animation, alphabetically)
+	SecondsPerAnimation int      `json:"secondsPerAnimation,omitempty"` // How long each showcase animation plays (default: 20)
+}
+
+// showcaseState tracks a running screensaver showcase. Zero means stopped.
+type showcaseState struct {
+	startedAt time.Time
+}
+
+// validateScreensaver checks the screensaver settings against the card's animations
+func (c *CharacterCard) validateScreensaver() error {
+	s := c.Screensaver
+	if s == nil || !s.Enabled {
+		return nil
+	}
+	if s.IdleMinutes < 0 {
+		return fmt.Errorf("idleMinutes must not be negative, got %d", s.IdleMinutes)
+	}
+	if s.SecondsPerAnimation < 0 {
+		return fmt.Errorf("secondsPerAnimation must not be negative, got %d", s.SecondsPerAnimation)
+	}
+	for _, animation := range s.Animations {
+		if _, exists := c.Animations[animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", animation)
+		}
+	}
+	return nil
+}
+
+// HasScreensaver returns true if the character takes over the screen while
+// the user is idle
+func (c *CharacterCard) HasScreensaver() bool {
+	return c.Screensaver != nil && c.Screensaver.Enabled
+}
+
+// GetIdleThreshold returns how long without input starts the screensaver
+func (s *ScreensaverConfig) GetIdleThreshold() time.Duration {
+	if s == nil || s.IdleMinutes <= 0 {
+		return defaultScreensaverIdleMinutes * time.Minute
+	}
+	return time.Duration(s.IdleMinutes) * time.Minute
+}
+
+// GetAnimationDuration returns how long each showcase animation plays
+func (s *ScreensaverConfig) GetAnimationDuration() time.Duration {
+	if s == nil || s.SecondsPerAnimation <= 0 {
+		return defaultShowcaseSeconds * time.Second
+	}
+	return time.Duration(s.SecondsPerAnimation) * time.Second
+}
+
+// showcaseAnimations returns the showcase sequence, defaulting to every
+// animation on the card
+func (c *CharacterCard) showcaseAnimations() []string {
+	if c.Screensaver != nil && len(c.Screensaver.Animations) > 0 {
+		return c.Screensaver.Animations
+	}
+	animations := make([]string, 0, len(c.Animations))
+	for name := range c.Animations {
+		animations = append(animations, name)
+	}
+	sort.Strings(animations)
+	return animations
+}
+
+// StartShowcase starts playing the screensaver showcase. Does nothing unless
+// the card enables the screensaver.
+func (c *Character) StartShowcase() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.card.HasScreensaver() || !c.showcase.startedAt.IsZero() {
+		return
+	}
+	now := time.Now()
+	c.showcase.startedAt = now
+	c.updateShowcase(now)
+}
+
+// StopShowcase ends the showcase and goes back to an idle animation
+func (c *Character) StopShowcase() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.showcase.startedAt.IsZero() {
+		return
+	}
+	c.showcase = showcaseState{}
+	c.setState(c.selectIdleAnimation())
+}
+
+// IsShowcasing returns true while the screensaver showcase runs
+func (c *Character) IsShowcasing() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.showcase.startedAt.IsZero()
+}
+
+// showcaseAnimation returns the showcase animation due at now. ok is false
+// when no showcase is running. Must be called with c.mu held.
+func (c *Character) showcaseAnimation(now time.Time) (string, bool) {
+	if c.showcase.startedAt.IsZero() {
+		return "", false
+	}
+	animations := c.card.showcaseAnimations()
+	if len(animations) == 0 {
+		return "", false
+	}
+	step := int(now.Sub(c.showcase.startedAt) / c.card.Screensaver.GetAnimationDuration())
+	return animations[step%len(animations)], true
+}
+
+// updateShowcase moves on to the showcase animation due at now, overriding
+// whatever else the character was doing. Returns true if the animation
+// changed. Must be called with c.mu held.
+func (c *Character) updateShowcase(now time.Time) bool {
+	animation, ok := c.showcaseAnimation(now)
+	if !ok || c.currentState == animation {
+		return false
+	}
+	previous := c.currentState
+	c.setState(animation)
+	return c.currentState != previous
+}
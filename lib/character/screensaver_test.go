@@ -0,0 +1,103 @@
+package character
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScreensaverConfigDefaults(t *testing.T) {
+	var s *ScreensaverConfig
+	if s.GetIdleThreshold() != 15*time.Minute || s.GetAnimationDuration() != 20*time.Second {
+		t.Error("Nil screensaver config should use the defaults")
+	}
+
+	s = &ScreensaverConfig{IdleMinutes: 30, SecondsPerAnimation: 5}
+	if s.GetIdleThreshold() != 30*time.Minute || s.GetAnimationDuration() != 5*time.Second {
+		t.Error("Configured values should be used")
+	}
+}
+
+func TestValidateScreensaver(t *testing.T) {
+	tests := []struct {
+		name        string
+		screensaver *ScreensaverConfig
+		wantErr     bool
+	}{
+		{"disabled", &ScreensaverConfig{IdleMinutes: -1}, false},
+		{"valid", &ScreensaverConfig{Enabled: true, Animations: []string{"happy", "eating"}}, false},
+		{"negative idle", &ScreensaverConfig{Enabled: true, IdleMinutes: -1}, true},
+		{"negative duration", &ScreensaverConfig{Enabled: true, SecondsPerAnimation: -5}, true},
+		{"unknown animation", &ScreensaverConfig{Enabled: true, Animations: []string{"missing"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Screensaver = tt.screensaver
+			if err := card.validateScreensaver(); (err != nil) != tt.wantErr {
+				t.Errorf("validateScreensaver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShowcaseAnimations(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Screensaver = &ScreensaverConfig{Enabled: true}
+	want := []string{"eating", "happy", "hungry", "idle", "sad", "talking"}
+	if got := card.showcaseAnimations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected every animation in order, got %v", got)
+	}
+
+	card.Screensaver.Animations = []string{"happy", "idle"}
+	if got := card.showcaseAnimations(); !reflect.DeepEqual(got, card.Screensaver.Animations) {
+		t.Errorf("Expected the configured showcase, got %v", got)
+	}
+}
+
+func TestShowcaseSequence(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Screensaver = &ScreensaverConfig{Enabled: true, Animations: []string{"happy", "sad", "eating"}, SecondsPerAnimation: 10}
+	char := createTestCharacterInstance(card, false)
+
+	char.StartShowcase()
+	if !char.IsShowcasing() {
+		t.Fatal("Showcase should be running")
+	}
+
+	char.mu.Lock()
+	start := char.showcase.startedAt
+	steps := []struct {
+		after time.Duration
+		want  string
+	}{
+		{0, "happy"},
+		{15 * time.Second, "sad"},
+		{25 * time.Second, "eating"},
+		{35 * time.Second, "happy"}, // Loops
+	}
+	for _, step := range steps {
+		if got, ok := char.showcaseAnimation(start.Add(step.after)); !ok || got != step.want {
+			t.Errorf("After %v expected %q, got %q", step.after, step.want, got)
+		}
+	}
+	char.mu.Unlock()
+
+	char.StopShowcase()
+	if char.IsShowcasing() {
+		t.Error("Showcase should have stopped")
+	}
+	char.mu.RLock()
+	if _, ok := char.showcaseAnimation(time.Now()); ok {
+		t.Error("No showcase animation once stopped")
+	}
+	char.mu.RUnlock()
+}
+
+func TestStartShowcaseDisabled(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), false)
+	char.StartShowcase()
+	if char.IsShowcasing() {
+		t.Error("Cards without a screensaver should not showcase")
+	}
+}
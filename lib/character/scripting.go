@@ -0,0 +1,234 @@
+package character
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/script"
+)
+
+// Behavior script hooks and limits
+const (
+	scriptHookClick      = "on_click"       // on_click() may return text said instead of the card's click dialog
+	scriptHookStatChange = "on_stat_change" // on_stat_change(name, old, new) runs when a stat moves by a whole point
+	scriptHookEvent      = "on_event"       // on_event(name) runs when a random or general event fires
+
+	maxScriptDialogs   = 5   // Lines queued by companion.say before more are dropped
+	maxScriptLineRunes = 300 // Longest line companion.say accepts
+)
+
+// scriptState holds the card's behavior script and what it has queued
+type scriptState struct {
+	vm    *script.State
+	said  []string           // Lines from companion.say since the UI last asked
+	stats map[string]float64 // Whole stat values last passed to on_stat_change
+}
+
+// validateScript checks the behavior script path stays inside the card's folder
+func (c *CharacterCard) validateScript() error {
+	if c.Script == "" {
+		return nil
+	}
+	if !strings.EqualFold(filepath.Ext(c.Script), ".lua") {
+		return fmt.Errorf("script must be a .lua file, got '%s'", c.Script)
+	}
+	if !filepath.IsLocal(c.Script) {
+		return fmt.Errorf("script must be a relative path inside the character folder, got '%s'", c.Script)
+	}
+	return nil
+}
+
+// HasScript returns true if the card ships a behavior script
+func (c *CharacterCard) HasScript() bool {
+	return c.Script != ""
+}
+
+// loadScript runs the card's behavior script so its hooks are defined. The
+// script only sees the companion API and a sandboxed standard library.
+func (c *Character) loadScript() error {
+	if !c.card.HasScript() {
+		return nil
+	}
+	src, err := os.ReadFile(filepath.Join(c.basePath, c.card.Script))
+	if err != nil {
+		return fmt.Errorf("failed to read script: %w", err)
+	}
+
+	vm := script.New(script.Options{
+		Print: func(text string) {
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+				"script": c.card.Script,
+			}).Debug(text)
+		},
+	})
+	vm.SetGlobal("companion", c.scriptAPI())
+	if err := vm.DoString(c.card.Script, string(src)); err != nil {
+		return fmt.Errorf("failed to run script: %w", err)
+	}
+
+	c.script.vm = vm
+	c.script.stats = c.wholeStats()
+	return nil
+}
+
+// scriptAPI builds the companion table scripts use to read stats, play
+// animations and talk. Its functions run inside hooks, with c.mu held.
+func (c *Character) scriptAPI() *script.Table {
+	api := script.NewTable()
+	_ = api.Set("name", c.card.Name)
+
+	functions := map[string]func(args []script.Value) ([]script.Value, error){
+		// stat(name) returns the stat's value, or nil if the card has no such stat
+		"stat": func(args []script.Value) ([]script.Value, error) {
+			name, _ := argAt(args, 0).(string)
+			if c.gameState == nil {
+				return []script.Value{nil}, nil
+			}
+			if _, exists := c.gameState.GetStats()[name]; !exists {
+				return []script.Value{nil}, nil
+			}
+			return []script.Value{c.gameState.GetStat(name)}, nil
+		},
+		// stats() returns a table of every stat's value
+		"stats": func([]script.Value) ([]script.Value, error) {
+			stats := script.NewTable()
+			if c.gameState != nil {
+				for name, value := range c.gameState.GetStats() {
+					_ = stats.Set(name, value)
+				}
+			}
+			return []script.Value{stats}, nil
+		},
+		// state() returns the animation playing now
+		"state": func([]script.Value) ([]script.Value, error) {
+			return []script.Value{c.currentState}, nil
+		},
+		// animate(name) plays one of the card's animations; false if it has none by that name
+		"animate": func(args []script.Value) ([]script.Value, error) {
+			name, _ := argAt(args, 0).(string)
+			if _, exists := c.card.Animations[name]; !exists {
+				return []script.Value{false}, nil
+			}
+			c.setState(name)
+			return []script.Value{true}, nil
+		},
+		// say(text) queues a line for the speech bubble
+		"say": func(args []script.Value) ([]script.Value, error) {
+			text := strings.TrimSpace(script.ToString(argAt(args, 0)))
+			if text == "" || len(c.script.said) >= maxScriptDialogs {
+				return []script.Value{false}, nil
+			}
+			if runes := []rune(text); len(runes) > maxScriptLineRunes {
+				text = string(runes[:maxScriptLineRunes])
+			}
+			c.script.said = append(c.script.said, text)
+			return []script.Value{true}, nil
+		},
+	}
+	for name, fn := range functions {
+		_ = api.Set(name, script.NewGoFunction("companion."+name, fn))
+	}
+	return api
+}
+
+// argAt returns args[i], or nil past the end
+func argAt(args []script.Value, i int) script.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+// runScriptHook calls a hook if the script defines it and returns its
+// results. Errors are logged and otherwise ignored, so a broken script
+// never stops the character. Must be called with c.mu held.
+func (c *Character) runScriptHook(hook string, args ...script.Value) []script.Value {
+	if c.script.vm == nil || !c.script.vm.HasFunction(hook) {
+		return nil
+	}
+	results, err := c.script.vm.Call(hook, args...)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"script": c.card.Script,
+			"hook":   hook,
+			"error":  err.Error(),
+		}).Warn("Behavior script failed")
+		return nil
+	}
+	return results
+}
+
+// scriptClickResponse runs on_click and returns the text it asked to say,
+// if any. Must be called with c.mu held.
+func (c *Character) scriptClickResponse() (string, bool) {
+	results := c.runScriptHook(scriptHookClick)
+	if len(results) == 0 {
+		return "", false
+	}
+	text, ok := results[0].(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return "", false
+	}
+	return text, true
+}
+
+// updateScriptStats runs on_stat_change for each stat whose whole value
+// changed since the last frame. Must be called with c.mu held.
+func (c *Character) updateScriptStats() {
+	if c.script.vm == nil || c.gameState == nil {
+		return
+	}
+	current := c.wholeStats()
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	previous := c.script.stats
+	c.script.stats = current
+	for _, name := range names {
+		old, known := previous[name]
+		if known && old != current[name] {
+			c.runScriptHook(scriptHookStatChange, name, old, current[name])
+		}
+	}
+}
+
+// wholeStats returns the stats rounded to whole points, so gradual decay
+// only reaches on_stat_change once per point
+func (c *Character) wholeStats() map[string]float64 {
+	stats := make(map[string]float64)
+	if c.gameState == nil {
+		return stats
+	}
+	for name, value := range c.gameState.GetStats() {
+		stats[name] = math.Round(value)
+	}
+	return stats
+}
+
+// notifyScriptEvent runs on_event for a fired event. Must be called with
+// c.mu held.
+func (c *Character) notifyScriptEvent(name string) {
+	c.runScriptHook(scriptHookEvent, name)
+}
+
+// GetScriptDialogs returns lines the behavior script queued with
+// companion.say since the last call and clears the queue
+func (c *Character) GetScriptDialogs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	said := c.script.said
+	c.script.said = nil
+	return said
+}
@@ -0,0 +1,152 @@
+package character
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateScript(t *testing.T) {
+	tests := []struct {
+		script  string
+		wantErr bool
+	}{
+		{"", false},
+		{"behavior.lua", false},
+		{"scripts/behavior.LUA", false},
+		{"behavior.js", true},
+		{"../other/behavior.lua", true},
+		{"/etc/behavior.lua", true},
+	}
+	for _, tt := range tests {
+		card := &CharacterCard{Script: tt.script}
+		if err := card.validateScript(); (err != nil) != tt.wantErr {
+			t.Errorf("validateScript(%q) error = %v, wantErr %v", tt.script, err, tt.wantErr)
+		}
+	}
+}
+
+// createScriptedCharacter writes src as the card's behavior script and loads it
+func createScriptedCharacter(t *testing.T, src string) *Character {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "behavior.lua"), []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	card := createTestGameCharacterCard()
+	card.Script = "behavior.lua"
+	char := createTestCharacterInstance(card, true)
+	char.basePath = dir
+	if err := char.loadScript(); err != nil {
+		t.Fatalf("loadScript failed: %v", err)
+	}
+	return char
+}
+
+func TestScriptClickResponse(t *testing.T) {
+	char := createScriptedCharacter(t, `
+		function on_click()
+			if companion.stat("hunger") > 50 then
+				animated = companion.animate("happy")
+				return "I'm " .. companion.name .. " and I'm full!"
+			end
+		end
+	`)
+
+	if got := char.HandleClick(); got != "I'm Test Game Pet and I'm full!" {
+		t.Errorf("Expected the script's click response, got %q", got)
+	}
+	if char.script.vm.GetGlobal("animated") != true {
+		t.Error("Expected companion.animate to accept the card's happy animation")
+	}
+
+	// Returning nothing falls back to the card's click dialog
+	char.gameState.ApplyInteractionEffects(map[string]float64{"hunger": -80})
+	if got := char.HandleClick(); got != "Hello!" {
+		t.Errorf("Expected the card's click dialog, got %q", got)
+	}
+}
+
+func TestScriptStatChangeAndSay(t *testing.T) {
+	char := createScriptedCharacter(t, `
+		function on_stat_change(name, old, new)
+			companion.say(name .. " " .. old .. "->" .. new)
+		end
+		function on_event(name)
+			companion.say("event " .. name .. " " .. tostring(companion.animate("missing")))
+		end
+	`)
+
+	char.gameState.ApplyInteractionEffects(map[string]float64{"hunger": -10.4, "energy": -0.2})
+	char.mu.Lock()
+	char.updateScriptStats()
+	char.notifyScriptEvent("party")
+	char.mu.Unlock()
+
+	said := char.GetScriptDialogs()
+	want := []string{"hunger 100->90", "event party false"}
+	if strings.Join(said, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %q, got %q", want, said)
+	}
+	if len(char.GetScriptDialogs()) != 0 {
+		t.Error("GetScriptDialogs should clear the queue")
+	}
+}
+
+func TestScriptSayLimits(t *testing.T) {
+	char := createScriptedCharacter(t, `
+		function on_event()
+			companion.say("   ")
+			companion.say(string.rep("a", 400))
+			for i = 1, 10 do companion.say("line " .. i) end
+		end
+	`)
+
+	char.mu.Lock()
+	char.notifyScriptEvent("spam")
+	char.mu.Unlock()
+
+	said := char.GetScriptDialogs()
+	if len(said) != maxScriptDialogs {
+		t.Fatalf("Expected %d queued lines, got %d", maxScriptDialogs, len(said))
+	}
+	if len([]rune(said[0])) != maxScriptLineRunes {
+		t.Errorf("Expected long lines cut to %d runes, got %d", maxScriptLineRunes, len([]rune(said[0])))
+	}
+}
+
+func TestScriptFailuresAreContained(t *testing.T) {
+	char := createScriptedCharacter(t, `
+		function on_click()
+			while true do end
+		end
+		function on_event()
+			error("broken")
+		end
+	`)
+
+	if got := char.HandleClick(); got != "Hello!" {
+		t.Errorf("A runaway script should fall back to the card's dialog, got %q", got)
+	}
+	char.mu.Lock()
+	char.notifyScriptEvent("anything")
+	char.mu.Unlock()
+}
+
+func TestLoadScriptErrors(t *testing.T) {
+	card := createTestGameCharacterCard()
+	card.Script = "missing.lua"
+	char := createTestCharacterInstance(card, true)
+	char.basePath = t.TempDir()
+	if err := char.loadScript(); err == nil {
+		t.Error("Expected an error for a missing script")
+	}
+
+	if err := os.WriteFile(filepath.Join(char.basePath, "missing.lua"), []byte("function on_click("), 0o644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	if err := char.loadScript(); err == nil {
+		t.Error("Expected a syntax error to fail loading")
+	}
+}
@@ -0,0 +1,73 @@
+package character
+
+import (
+	"math"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// GetSharedPetValues returns what shared pet mode keeps in sync with the
+// partner: stats, age, achievements and interaction counts. Empty outside
+// game mode.
+func (c *Character) GetSharedPetValues() network.SharedPetValues {
+	gs := c.GetGameState()
+	values := network.SharedPetValues{Stats: gs.GetStats()}
+	if progression := gs.GetProgression(); progression != nil {
+		values.Age = progression.GetAge()
+		values.Achievements = progression.GetAchievements()
+		values.Interactions = progression.GetInteractionCounts()
+	}
+	return values
+}
+
+// ApplySharedPetValues takes on the partner's side of a shared pet. Stats
+// are set outright; progression only moves forward, and achievements
+// earned by the partner come without their rewards, which the partner's
+// stats already include. Stats the card doesn't have are ignored.
+func (c *Character) ApplySharedPetValues(values network.SharedPetValues) {
+	gs := c.GetGameState()
+	if gs == nil {
+		return
+	}
+	gs.setStats(values.Stats)
+	gs.GetProgression().mergeShared(values.Age, values.Achievements, values.Interactions)
+}
+
+// setStats sets stats to the given values within their bounds.
+// Unknown stats are ignored.
+func (gs *GameState) setStats(values map[string]float64) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	for name, value := range values {
+		if stat, exists := gs.Stats[name]; exists {
+			stat.Current = math.Max(0, math.Min(stat.Max, value))
+		}
+	}
+}
+
+// mergeShared brings progression up to a partner's: the older age, every
+// achievement either side earned and the higher interaction counts. Levels
+// follow age with the next update.
+func (ps *ProgressionState) mergeShared(age time.Duration, achievements []string, counts map[string]int) {
+	if ps == nil {
+		return
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.Age = max(ps.Age, age)
+	for _, achievement := range achievements {
+		if !ps.hasAchievement(achievement) {
+			ps.Achievements = append(ps.Achievements, achievement)
+		}
+	}
+	if ps.InteractionCounts == nil {
+		ps.InteractionCounts = make(map[string]int)
+	}
+	for interaction, count := range counts {
+		ps.InteractionCounts[interaction] = max(ps.InteractionCounts[interaction], count)
+	}
+}
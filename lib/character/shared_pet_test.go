@@ -0,0 +1,58 @@
+package character
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+func TestSharedPetValuesRoundTrip(t *testing.T) {
+	owner := createTestLevelUpCharacter(t)
+	partner := createTestLevelUpCharacter(t)
+
+	owner.GetGameState().ApplyInteractionEffects(map[string]float64{"hunger": -40})
+	progression := owner.GetGameState().GetProgression()
+	progression.RecordInteraction("feed")
+	progression.mergeShared(2*time.Hour, []string{"first_steps"}, nil)
+
+	partner.ApplySharedPetValues(owner.GetSharedPetValues())
+
+	got := partner.GetSharedPetValues()
+	if got.Stats["hunger"] != 60 || got.Stats["affection"] != 10 {
+		t.Errorf("Expected the owner's stats, got %v", got.Stats)
+	}
+	if got.Age != 2*time.Hour || len(got.Achievements) != 1 || got.Interactions["feed"] != 1 {
+		t.Errorf("Expected the owner's progression, got %+v", got)
+	}
+}
+
+func TestApplySharedPetValuesOnlyMovesProgressionForward(t *testing.T) {
+	char := createTestLevelUpCharacter(t)
+	progression := char.GetGameState().GetProgression()
+	progression.mergeShared(3*time.Hour, []string{"b"}, map[string]int{"feed": 5})
+
+	char.ApplySharedPetValues(network.SharedPetValues{
+		Stats:        map[string]float64{"hunger": 150, "unknown": 10},
+		Age:          time.Hour,
+		Achievements: []string{"a", "b"},
+		Interactions: map[string]int{"feed": 2, "play": 1},
+	})
+
+	values := char.GetSharedPetValues()
+	if values.Stats["hunger"] != 100 {
+		t.Errorf("Expected hunger to stay within its max, got %v", values.Stats["hunger"])
+	}
+	if _, ok := values.Stats["unknown"]; ok {
+		t.Error("Expected stats the card doesn't have to be ignored")
+	}
+	if values.Age != 3*time.Hour {
+		t.Errorf("Expected the older age to stay, got %v", values.Age)
+	}
+	if len(values.Achievements) != 2 {
+		t.Errorf("Expected the union of achievements, got %v", values.Achievements)
+	}
+	if values.Interactions["feed"] != 5 || values.Interactions["play"] != 1 {
+		t.Errorf("Expected the higher counts, got %v", values.Interactions)
+	}
+}
@@ -0,0 +1,375 @@
+package character
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CurrencyKey is the effect key that adds or removes coins. It can be used in
+// any effect map (interactions, jobs, choices) and in condition expressions.
+// A card stat with the same name takes precedence.
+const CurrencyKey = "coins"
+
+// Shop item types
+const (
+	ShopItemGift   = "gift"   // Added to the inventory
+	ShopItemSkin   = "skin"   // Replaces animations while equipped
+	ShopItemUnlock = "unlock" // Makes a general event with requiresPurchase available
+)
+
+// ShopConfig lists the items the user can buy with coins
+type ShopConfig struct {
+	Items map[string]ShopItem `json:"items"`
+}
+
+// ShopItem is one entry in the card's shop
+type ShopItem struct {
+	Name        string            `json:"name,omitempty"`        // Display name (defaults to the item key)
+	Description string            `json:"description,omitempty"` // Shown in the shop dialog
+	Type        string            `json:"type"`                  // "gift", "skin" or "unlock"
+	Price       int               `json:"price"`                 // Cost in coins
+	Stock       int               `json:"stock,omitempty"`       // Total purchases allowed, 0 = unlimited
+	Item        string            `json:"item,omitempty"`        // Gift: inventory item (defaults to the item key)
+	Animations  map[string]string `json:"animations,omitempty"`  // Skin: state -> replacement animation
+	Condition   string            `json:"condition,omitempty"`   // Extra requirement expression
+	Responses   []string          `json:"responses,omitempty"`   // Said after buying
+}
+
+// ShopListing describes a shop item for menus
+type ShopListing struct {
+	ID          string
+	Name        string
+	Description string
+	Type        string
+	Price       int
+	Remaining   int // -1 for unlimited stock
+	Owned       int
+	Equipped    bool
+	Affordable  bool
+	Available   bool
+}
+
+// GetDisplayName returns the item name, falling back to its key
+func (s ShopItem) GetDisplayName(id string) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return id
+}
+
+// inventoryItem returns the inventory key a gift purchase adds
+func (s ShopItem) inventoryItem(id string) string {
+	if s.Item != "" {
+		return s.Item
+	}
+	return id
+}
+
+// remaining returns how many more can be bought, -1 for unlimited
+func (s ShopItem) remaining(bought int) int {
+	if s.Stock <= 0 {
+		return -1
+	}
+	if bought >= s.Stock {
+		return 0
+	}
+	return s.Stock - bought
+}
+
+// validateShop validates the optional shop section
+func (c *CharacterCard) validateShop() error {
+	if c.Shop == nil {
+		return c.validatePurchaseReferences()
+	}
+
+	if !c.HasGameFeatures() {
+		return fmt.Errorf("shop requires stats to be defined")
+	}
+
+	for id, item := range c.Shop.Items {
+		if err := c.validateShopItem(item); err != nil {
+			return fmt.Errorf("item '%s': %w", id, err)
+		}
+	}
+
+	return c.validatePurchaseReferences()
+}
+
+// validateShopItem checks price, stock and type-specific fields of one item
+func (c *CharacterCard) validateShopItem(item ShopItem) error {
+	if item.Price < 0 {
+		return fmt.Errorf("price must not be negative, got %d", item.Price)
+	}
+	if item.Stock < 0 {
+		return fmt.Errorf("stock must not be negative, got %d", item.Stock)
+	}
+
+	switch item.Type {
+	case ShopItemGift, ShopItemUnlock:
+	case ShopItemSkin:
+		if len(item.Animations) == 0 {
+			return fmt.Errorf("skin must replace at least one animation")
+		}
+		for state, animation := range item.Animations {
+			if _, exists := c.Animations[animation]; !exists {
+				return fmt.Errorf("skin animation '%s' for '%s' not found in animations map", animation, state)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown type '%s' (expected gift, skin or unlock)", item.Type)
+	}
+
+	if item.Condition != "" {
+		if err := validateConditionIdentifiers(item.Condition, c.Stats); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePurchaseReferences ensures general events only require unlock items that exist
+func (c *CharacterCard) validatePurchaseReferences() error {
+	for _, event := range c.GeneralEvents {
+		if event.RequiresPurchase == "" {
+			continue
+		}
+		if c.Shop == nil {
+			return fmt.Errorf("event '%s' requires purchase '%s' but no shop is defined", event.Name, event.RequiresPurchase)
+		}
+		item, exists := c.Shop.Items[event.RequiresPurchase]
+		if !exists {
+			return fmt.Errorf("event '%s' requires unknown shop item '%s'", event.Name, event.RequiresPurchase)
+		}
+		if item.Type != ShopItemUnlock {
+			return fmt.Errorf("event '%s' requires '%s', which is not an unlock item", event.Name, event.RequiresPurchase)
+		}
+	}
+	return nil
+}
+
+// GetShopItems lists the shop with prices, stock and ownership, sorted by price then name
+func (c *Character) GetShopItems() []ShopListing {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.gameState == nil || c.card.Shop == nil {
+		return nil
+	}
+
+	coins := c.gameState.GetCoins()
+	skin := c.gameState.GetActiveSkin()
+
+	listings := make([]ShopListing, 0, len(c.card.Shop.Items))
+	for id, item := range c.card.Shop.Items {
+		bought := c.gameState.GetPurchaseCount(id)
+		remaining := item.remaining(bought)
+		listings = append(listings, ShopListing{
+			ID:          id,
+			Name:        item.GetDisplayName(id),
+			Description: item.Description,
+			Type:        item.Type,
+			Price:       item.Price,
+			Remaining:   remaining,
+			Owned:       bought,
+			Equipped:    skin == id,
+			Affordable:  coins >= item.Price,
+			Available:   remaining != 0 && c.gameState.EvaluateCondition(item.Condition),
+		})
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		if listings[i].Price != listings[j].Price {
+			return listings[i].Price < listings[j].Price
+		}
+		return listings[i].Name < listings[j].Name
+	})
+	return listings
+}
+
+// Purchase buys a shop item and returns the character's response.
+// Skins are equipped straight away.
+func (c *Character) Purchase(id string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gameState == nil {
+		return "", fmt.Errorf("shop requires game mode")
+	}
+	if c.card.Shop == nil {
+		return "", fmt.Errorf("this character has no shop")
+	}
+
+	item, exists := c.card.Shop.Items[id]
+	if !exists {
+		return "", fmt.Errorf("unknown shop item '%s'", id)
+	}
+
+	if item.remaining(c.gameState.GetPurchaseCount(id)) == 0 {
+		return "", fmt.Errorf("'%s' is sold out", item.GetDisplayName(id))
+	}
+	if !c.gameState.EvaluateCondition(item.Condition) {
+		return "", fmt.Errorf("'%s' is not available yet", item.GetDisplayName(id))
+	}
+	if !c.gameState.SpendCoins(item.Price) {
+		return "", fmt.Errorf("not enough coins for '%s' (costs %d)", item.GetDisplayName(id), item.Price)
+	}
+
+	c.gameState.RecordPurchase(id)
+	c.gameState.RecordInteraction("purchase:" + id)
+
+	switch item.Type {
+	case ShopItemGift:
+		c.gameState.AddItem(item.inventoryItem(id), 1)
+	case ShopItemSkin:
+		c.gameState.SetActiveSkin(id)
+		c.refreshSkin()
+	}
+
+	return selectJobResponse(item.Responses, fmt.Sprintf("Thanks for the %s!", item.GetDisplayName(id))), nil
+}
+
+// EquipSkin switches to an owned skin; an empty id restores the default animations
+func (c *Character) EquipSkin(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gameState == nil {
+		return fmt.Errorf("skins require game mode")
+	}
+
+	if id != "" {
+		if c.card.Shop == nil || c.card.Shop.Items[id].Type != ShopItemSkin {
+			return fmt.Errorf("unknown skin '%s'", id)
+		}
+		if c.gameState.GetPurchaseCount(id) == 0 {
+			return fmt.Errorf("skin '%s' has not been bought", id)
+		}
+	}
+
+	c.gameState.SetActiveSkin(id)
+	c.refreshSkin()
+	return nil
+}
+
+// refreshSkin replays the current state with the equipped skin. Must be called with c.mu held.
+func (c *Character) refreshSkin() {
+	if c.currentState == "" {
+		return
+	}
+	if err := c.animationManager.SetCurrentAnimation(c.applySkin(c.currentState)); err != nil {
+		// Skin animation failed to load, keep the default
+		_ = c.animationManager.SetCurrentAnimation(c.currentState)
+	}
+}
+
+// applySkin maps a state to the equipped skin's animation. Must be called with c.mu held.
+func (c *Character) applySkin(state string) string {
+	if c.gameState == nil || c.card.Shop == nil {
+		return state
+	}
+
+	skin := c.gameState.GetActiveSkin()
+	if skin == "" {
+		return state
+	}
+
+	if animation, ok := c.card.Shop.Items[skin].Animations[state]; ok {
+		return animation
+	}
+	return state
+}
+
+// GetCoins returns the current coin balance
+func (gs *GameState) GetCoins() int {
+	if gs == nil {
+		return 0
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.Coins
+}
+
+// AddCoins changes the balance by amount, never dropping below zero
+func (gs *GameState) AddCoins(amount int) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.addCoinsLocked(amount)
+}
+
+// addCoinsLocked changes the balance. Must be called with gs.mu held.
+func (gs *GameState) addCoinsLocked(amount int) {
+	gs.Coins += amount
+	if gs.Coins < 0 {
+		gs.Coins = 0
+	}
+}
+
+// SpendCoins removes amount from the balance. Returns false if there are not enough.
+func (gs *GameState) SpendCoins(amount int) bool {
+	if gs == nil || amount < 0 {
+		return false
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.Coins < amount {
+		return false
+	}
+	gs.Coins -= amount
+	return true
+}
+
+// RecordPurchase counts one purchase of a shop item
+func (gs *GameState) RecordPurchase(id string) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.Purchases == nil {
+		gs.Purchases = make(map[string]int)
+	}
+	gs.Purchases[id]++
+}
+
+// GetPurchaseCount returns how many times a shop item was bought
+func (gs *GameState) GetPurchaseCount(id string) int {
+	if gs == nil {
+		return 0
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.Purchases[id]
+}
+
+// GetActiveSkin returns the equipped skin item, or "" for default animations
+func (gs *GameState) GetActiveSkin() string {
+	if gs == nil {
+		return ""
+	}
+
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.ActiveSkin
+}
+
+// SetActiveSkin equips a skin item; "" restores default animations
+func (gs *GameState) SetActiveSkin(id string) {
+	if gs == nil {
+		return
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.ActiveSkin = id
+}
@@ -0,0 +1,143 @@
+package character
+
+import (
+	"strings"
+	"testing"
+)
+
+func createTestShopCard() *CharacterCard {
+	card := createTestGameCharacterCard()
+	card.Shop = &ShopConfig{Items: map[string]ShopItem{
+		"flowers": {Name: "Flowers", Type: ShopItemGift, Price: 10, Stock: 1},
+		"party":   {Type: ShopItemSkin, Price: 20, Animations: map[string]string{"idle": "happy"}},
+		"story":   {Type: ShopItemUnlock, Price: 5, Responses: []string{"Story time!"}},
+	}}
+	card.GeneralEvents = []GeneralDialogEvent{{
+		RandomEventConfig: RandomEventConfig{Name: "bedtime_story"},
+		Category:          "conversation",
+		RequiresPurchase:  "story",
+	}}
+	return card
+}
+
+func TestCoinsFromEffects(t *testing.T) {
+	char := createTestCharacterInstance(createTestShopCard(), true)
+	gs := char.GetGameState()
+
+	gs.ApplyInteractionEffects(map[string]float64{CurrencyKey: 15, "happiness": -10})
+	if coins := gs.GetCoins(); coins != 15 {
+		t.Errorf("Expected 15 coins, got %d", coins)
+	}
+	if !gs.EvaluateCondition("coins >= 15") {
+		t.Error("coins should be usable in conditions")
+	}
+
+	gs.ApplyInteractionEffects(map[string]float64{CurrencyKey: -100})
+	if coins := gs.GetCoins(); coins != 0 {
+		t.Errorf("Coins should not go negative, got %d", coins)
+	}
+	if critical := gs.GetCriticalStates(); len(critical) != 0 {
+		t.Errorf("Empty wallet should not be a critical state, got %v", critical)
+	}
+}
+
+func TestPurchase(t *testing.T) {
+	char := createTestCharacterInstance(createTestShopCard(), true)
+	gs := char.GetGameState()
+
+	if _, err := char.Purchase("flowers"); err == nil || !strings.Contains(err.Error(), "not enough coins") {
+		t.Errorf("Expected not enough coins error, got %v", err)
+	}
+
+	gs.AddCoins(40)
+	response, err := char.Purchase("flowers")
+	if err != nil {
+		t.Fatalf("Purchase failed: %v", err)
+	}
+	if response != "Thanks for the Flowers!" {
+		t.Errorf("Unexpected response %q", response)
+	}
+	if gs.GetCoins() != 30 || gs.GetInventory()["flowers"] != 1 {
+		t.Errorf("Expected 30 coins and flowers in inventory, got %d %v", gs.GetCoins(), gs.GetInventory())
+	}
+	if _, err := char.Purchase("flowers"); err == nil || !strings.Contains(err.Error(), "sold out") {
+		t.Errorf("Expected sold out error, got %v", err)
+	}
+
+	if _, err := char.Purchase("party"); err != nil {
+		t.Fatalf("Skin purchase failed: %v", err)
+	}
+	if gs.GetActiveSkin() != "party" {
+		t.Errorf("Skin should be equipped after purchase, got %q", gs.GetActiveSkin())
+	}
+	char.mu.Lock()
+	skinned, unskinned := char.applySkin("idle"), char.applySkin("talking")
+	char.mu.Unlock()
+	if skinned != "happy" || unskinned != "talking" {
+		t.Errorf("applySkin mapped to %q and %q", skinned, unskinned)
+	}
+	if err := char.EquipSkin(""); err != nil || gs.GetActiveSkin() != "" {
+		t.Errorf("Unequip failed: %v", err)
+	}
+
+	listings := char.GetShopItems()
+	if len(listings) != 3 || listings[0].ID != "story" || listings[1].Remaining != 0 || listings[2].Owned != 1 {
+		t.Errorf("Unexpected listings: %+v", listings)
+	}
+}
+
+func TestPurchaseUnlocksEvent(t *testing.T) {
+	char := createTestCharacterInstance(createTestShopCard(), true)
+	gem := NewGeneralEventManager(char.card.GeneralEvents, true)
+	gs := char.GetGameState()
+
+	if events := gem.GetAvailableEvents(gs); len(events) != 0 {
+		t.Fatalf("Locked event should be hidden, got %d", len(events))
+	}
+
+	gs.AddCoins(5)
+	if response, err := char.Purchase("story"); err != nil || response != "Story time!" {
+		t.Fatalf("Purchase failed: %q %v", response, err)
+	}
+	if events := gem.GetAvailableEvents(gs); len(events) != 1 {
+		t.Errorf("Event should unlock after purchase, got %d", len(events))
+	}
+}
+
+func TestValidateShop(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    ShopItem
+		wantErr string
+	}{
+		{"valid gift", ShopItem{Type: ShopItemGift, Price: 5}, ""},
+		{"negative price", ShopItem{Type: ShopItemGift, Price: -1}, "price"},
+		{"unknown type", ShopItem{Type: "hat"}, "unknown type"},
+		{"empty skin", ShopItem{Type: ShopItemSkin}, "at least one"},
+		{"missing skin animation", ShopItem{Type: ShopItemSkin, Animations: map[string]string{"idle": "idle_gold"}}, "not found"},
+		{"bad condition", ShopItem{Type: ShopItemGift, Condition: "mana > 1"}, "unknown stat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Shop = &ShopConfig{Items: map[string]ShopItem{"item": tt.item}}
+			err := card.validateShop()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	card := createTestShopCard()
+	card.GeneralEvents[0].RequiresPurchase = "flowers"
+	if err := card.validateShop(); err == nil || !strings.Contains(err.Error(), "not an unlock") {
+		t.Errorf("Expected unlock type error, got %v", err)
+	}
+}
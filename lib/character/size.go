@@ -0,0 +1,144 @@
+package character
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// Resize limits used when a card doesn't declare minSize/maxSize.
+	// They match the range card validation allows for defaultSize.
+	DefaultMinSize = 64
+	DefaultMaxSize = 512
+)
+
+// SizeLimits returns the smallest and largest size the user may resize to
+func (c *Character) SizeLimits() (minSize, maxSize int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sizeLimitsLocked()
+}
+
+// sizeLimitsLocked returns the resize limits; caller must hold c.mu
+func (c *Character) sizeLimitsLocked() (minSize, maxSize int) {
+	minSize, maxSize = DefaultMinSize, DefaultMaxSize
+	if c.card == nil {
+		return minSize, maxSize
+	}
+	if c.card.Behavior.MinSize > 0 {
+		minSize = c.card.Behavior.MinSize
+	}
+	if c.card.Behavior.MaxSize > 0 {
+		maxSize = c.card.Behavior.MaxSize
+	}
+	return minSize, maxSize
+}
+
+// Resize changes the character size within the card's limits and remembers
+// the choice if a size store is set. Returns the size actually applied.
+func (c *Character) Resize(size int) int {
+	c.mu.Lock()
+	minSize, maxSize := c.sizeLimitsLocked()
+	size = max(minSize, min(maxSize, size))
+	c.size = size
+	store := c.sizeStore
+	var name string
+	if c.card != nil {
+		name = c.card.Name
+	}
+	c.mu.Unlock()
+
+	if store != nil && name != "" {
+		if err := store.Set(name, size); err != nil {
+			log.Printf("Failed to save character size: %v", err)
+		}
+	}
+	return size
+}
+
+// SetSizeStore attaches a size store and applies any size saved for this character
+func (c *Character) SetSizeStore(store *SizeStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sizeStore = store
+	if store == nil || c.card == nil {
+		return
+	}
+	if size, ok := store.Get(c.card.Name); ok {
+		minSize, maxSize := c.sizeLimitsLocked()
+		c.size = max(minSize, min(maxSize, size))
+	}
+}
+
+// SizeStore persists the size each character was last resized to
+type SizeStore struct {
+	mu    sync.Mutex
+	path  string
+	sizes map[string]int // Character name -> size in pixels
+}
+
+// DefaultSizeStorePath returns the sizes file in the user config dir
+func DefaultSizeStorePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "desktop-companion", "sizes.json"), nil
+}
+
+// NewSizeStore creates a store backed by path, loading saved sizes if present
+func NewSizeStore(path string) (*SizeStore, error) {
+	s := &SizeStore{
+		path:  path,
+		sizes: make(map[string]int),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sizes file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.sizes); err != nil {
+		return nil, fmt.Errorf("failed to parse sizes file: %w", err)
+	}
+
+	return s, nil
+}
+
+// Get returns the saved size for a character
+func (s *SizeStore) Get(name string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size, ok := s.sizes[name]
+	return size, ok
+}
+
+// Set records a character's size and writes the file
+func (s *SizeStore) Set(name string, size int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sizes[name] == size {
+		return nil
+	}
+	s.sizes[name] = size
+
+	data, err := json.MarshalIndent(s.sizes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sizes: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write sizes file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package character
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCharacterResizeClampsAndPersists(t *testing.T) {
+	card := createTestCharacterCardWithDialogBackend()
+	card.Behavior.MinSize = 80
+	card.Behavior.MaxSize = 200
+	char := createTestCharacterInstance(card, false)
+
+	path := filepath.Join(t.TempDir(), "sizes.json")
+	store, err := NewSizeStore(path)
+	if err != nil {
+		t.Fatalf("NewSizeStore failed: %v", err)
+	}
+	char.SetSizeStore(store)
+
+	if got := char.Resize(1000); got != 200 {
+		t.Errorf("Expected resize to clamp to maxSize 200, got %d", got)
+	}
+	if got := char.Resize(10); got != 80 || char.GetSize() != 80 {
+		t.Errorf("Expected resize to clamp to minSize 80, got %d", got)
+	}
+	char.Resize(150)
+
+	// A fresh character picks up the saved size
+	reloaded, err := NewSizeStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reload size store: %v", err)
+	}
+	other := createTestCharacterInstance(card, false)
+	other.SetSizeStore(reloaded)
+	if other.GetSize() != 150 {
+		t.Errorf("Expected saved size 150, got %d", other.GetSize())
+	}
+}
+
+func TestBehaviorValidateSizeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int
+		wantErr  bool
+	}{
+		{"unset", 0, 0, false},
+		{"valid", 64, 256, false},
+		{"min above default", 120, 0, true},
+		{"min too small", 32, 0, true},
+		{"max below default", 0, 90, true},
+		{"max too large", 0, 1024, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Behavior{IdleTimeout: 30, DefaultSize: 100, MinSize: tt.min, MaxSize: tt.max}
+			if err := b.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
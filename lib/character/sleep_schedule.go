@@ -0,0 +1,222 @@
+package character
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sleep schedule defaults used when the card leaves optional fields empty
+const (
+	AnimationSleeping = "sleeping"
+
+	defaultSleepEventMultiplier = 0.25 // Random events are four times rarer while asleep
+	defaultSleepDimOpacity      = 0.6  // Sprite is rendered at 60% opacity while asleep
+)
+
+// SleepScheduleConfig defines the hours during which a character sleeps.
+// While asleep the character plays its sleeping animation, random events
+// are much rarer, and the UI dims the sprite.
+type SleepScheduleConfig struct {
+	Enabled                  bool     `json:"enabled"`                            // Enable the sleep schedule
+	StartTime                string   `json:"startTime"`                          // Bedtime in 24h "HH:MM" format
+	EndTime                  string   `json:"endTime"`                            // Wake-up time in 24h "HH:MM" format
+	Animation                string   `json:"animation,omitempty"`                // Animation while asleep (default: "sleeping")
+	EventFrequencyMultiplier float64  `json:"eventFrequencyMultiplier,omitempty"` // Random event multiplier while asleep (default: 0.25)
+	DimOpacity               float64  `json:"dimOpacity,omitempty"`               // Sprite opacity while asleep, 0.1-1.0 (default: 0.6)
+	Responses                []string `json:"responses,omitempty"`                // Sleepy responses when clicked while asleep
+}
+
+// parseClockTime converts a "HH:MM" string into minutes after midnight
+func parseClockTime(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("time must be in HH:MM format, got %q", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, fmt.Errorf("hour must be 0-23, got %q", parts[0])
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("minute must be 0-59, got %q", parts[1])
+	}
+
+	return hours*60 + minutes, nil
+}
+
+// Validate ensures the sleep schedule is well formed and references known animations
+func (s *SleepScheduleConfig) Validate(animations map[string]string) error {
+	if s == nil || !s.Enabled {
+		return nil
+	}
+
+	start, err := parseClockTime(s.StartTime)
+	if err != nil {
+		return fmt.Errorf("startTime: %w", err)
+	}
+
+	end, err := parseClockTime(s.EndTime)
+	if err != nil {
+		return fmt.Errorf("endTime: %w", err)
+	}
+
+	if start == end {
+		return fmt.Errorf("startTime and endTime must differ")
+	}
+
+	if s.Animation != "" {
+		if _, exists := animations[s.Animation]; !exists {
+			return fmt.Errorf("animation '%s' not found in animations map", s.Animation)
+		}
+	}
+
+	if s.EventFrequencyMultiplier < 0 || s.EventFrequencyMultiplier > 1 {
+		return fmt.Errorf("eventFrequencyMultiplier must be 0-1, got %f", s.EventFrequencyMultiplier)
+	}
+
+	if s.DimOpacity != 0 && (s.DimOpacity < 0.1 || s.DimOpacity > 1) {
+		return fmt.Errorf("dimOpacity must be 0.1-1.0, got %f", s.DimOpacity)
+	}
+
+	return nil
+}
+
+// IsSleepTime reports whether the given time falls inside the sleep window.
+// Windows that cross midnight (e.g. 22:00-07:00) are handled transparently.
+func (s *SleepScheduleConfig) IsSleepTime(t time.Time) bool {
+	if s == nil || !s.Enabled {
+		return false
+	}
+
+	start, err := parseClockTime(s.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := parseClockTime(s.EndTime)
+	if err != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// GetAnimation returns the animation to play while asleep
+func (s *SleepScheduleConfig) GetAnimation() string {
+	if s == nil || s.Animation == "" {
+		return AnimationSleeping
+	}
+	return s.Animation
+}
+
+// GetEventFrequencyMultiplier returns the random event multiplier applied while asleep
+func (s *SleepScheduleConfig) GetEventFrequencyMultiplier() float64 {
+	if s == nil || s.EventFrequencyMultiplier <= 0 {
+		return defaultSleepEventMultiplier
+	}
+	return s.EventFrequencyMultiplier
+}
+
+// GetDimOpacity returns the sprite opacity used while asleep
+func (s *SleepScheduleConfig) GetDimOpacity() float64 {
+	if s == nil || s.DimOpacity <= 0 {
+		return defaultSleepDimOpacity
+	}
+	return s.DimOpacity
+}
+
+// validateSleepSchedule validates the optional sleep schedule section
+func (c *CharacterCard) validateSleepSchedule() error {
+	return c.SleepSchedule.Validate(c.Animations)
+}
+
+// HasSleepSchedule returns true if the card defines an enabled sleep schedule
+func (c *CharacterCard) HasSleepSchedule() bool {
+	return c.SleepSchedule != nil && c.SleepSchedule.Enabled
+}
+
+// updateSleepState enters or leaves sleep according to the card's schedule.
+// Must be called with c.mu held. Returns true if the animation state changed.
+func (c *Character) updateSleepState(now time.Time) bool {
+	if !c.card.HasSleepSchedule() {
+		return false
+	}
+
+	shouldSleep := c.card.SleepSchedule.IsSleepTime(now)
+	if shouldSleep == c.sleeping {
+		return false
+	}
+
+	c.sleeping = shouldSleep
+	previousState := c.currentState
+	if sleepAnimation, ok := c.sleepAnimation(); ok {
+		c.setState(sleepAnimation)
+	} else {
+		c.setState(c.selectIdleAnimation())
+	}
+
+	return c.currentState != previousState
+}
+
+// sleepAnimation returns the animation to hold while asleep.
+// ok is false when awake or when the card has no matching animation.
+// Must be called with c.mu held.
+func (c *Character) sleepAnimation() (string, bool) {
+	if !c.sleeping {
+		return "", false
+	}
+	animation := c.card.SleepSchedule.GetAnimation()
+	if _, exists := c.card.Animations[animation]; !exists {
+		return "", false
+	}
+	return animation, true
+}
+
+// IsSleeping returns true while the character is inside its sleep window
+func (c *Character) IsSleeping() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sleeping
+}
+
+// GetSleepOpacity returns the opacity the renderer should use for the sprite.
+// Returns 1.0 while awake.
+func (c *Character) GetSleepOpacity() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.sleeping {
+		return 1.0
+	}
+	return c.card.SleepSchedule.GetDimOpacity()
+}
+
+// sleepyResponse returns a response used when the character is woken by a click
+func (c *Character) sleepyResponse() string {
+	responses := c.card.SleepSchedule.Responses
+	if len(responses) == 0 {
+		return "Zzz..."
+	}
+	return responses[int(time.Now().UnixNano())%len(responses)]
+}
+
+// SetDoNotDisturb enables or disables the user-level do-not-disturb mode.
+// While enabled the UI suppresses dialogs and notifications.
+func (c *Character) SetDoNotDisturb(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doNotDisturb = enabled
+}
+
+// IsDoNotDisturb returns whether do-not-disturb mode is active
+func (c *Character) IsDoNotDisturb() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.doNotDisturb
+}
@@ -0,0 +1,137 @@
+package character
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSleepScheduleIsSleepTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		clock    string
+		expected bool
+	}{
+		{"overnight before midnight", "22:00", "07:00", "23:30", true},
+		{"overnight after midnight", "22:00", "07:00", "03:15", true},
+		{"overnight at wake time", "22:00", "07:00", "07:00", false},
+		{"overnight afternoon", "22:00", "07:00", "14:00", false},
+		{"daytime nap inside", "13:00", "14:30", "13:45", true},
+		{"daytime nap outside", "13:00", "14:30", "15:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &SleepScheduleConfig{Enabled: true, StartTime: tt.start, EndTime: tt.end}
+			now, err := time.Parse("15:04", tt.clock)
+			if err != nil {
+				t.Fatalf("bad test clock: %v", err)
+			}
+			if got := schedule.IsSleepTime(now); got != tt.expected {
+				t.Errorf("IsSleepTime(%s) = %v, want %v", tt.clock, got, tt.expected)
+			}
+		})
+	}
+
+	disabled := &SleepScheduleConfig{Enabled: false, StartTime: "00:00", EndTime: "23:59"}
+	if disabled.IsSleepTime(time.Now()) {
+		t.Error("Disabled schedule should never report sleep time")
+	}
+}
+
+func TestSleepScheduleValidate(t *testing.T) {
+	animations := map[string]string{"idle": "idle.gif", "sleeping": "sleeping.gif"}
+
+	tests := []struct {
+		name     string
+		schedule *SleepScheduleConfig
+		wantErr  string
+	}{
+		{"nil schedule", nil, ""},
+		{"valid", &SleepScheduleConfig{Enabled: true, StartTime: "22:00", EndTime: "07:00", Animation: "sleeping"}, ""},
+		{"bad start", &SleepScheduleConfig{Enabled: true, StartTime: "25:00", EndTime: "07:00"}, "startTime"},
+		{"bad end", &SleepScheduleConfig{Enabled: true, StartTime: "22:00", EndTime: "7am"}, "endTime"},
+		{"same times", &SleepScheduleConfig{Enabled: true, StartTime: "22:00", EndTime: "22:00"}, "must differ"},
+		{"unknown animation", &SleepScheduleConfig{Enabled: true, StartTime: "22:00", EndTime: "07:00", Animation: "snoring"}, "not found"},
+		{"bad opacity", &SleepScheduleConfig{Enabled: true, StartTime: "22:00", EndTime: "07:00", DimOpacity: 1.5}, "dimOpacity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate(animations)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCharacterSleepState(t *testing.T) {
+	card := &CharacterCard{
+		Name:        "Sleepy",
+		Description: "Sleep schedule test",
+		Animations:  map[string]string{"idle": "idle.gif"},
+		Behavior:    Behavior{IdleTimeout: 30, DefaultSize: 128},
+		SleepSchedule: &SleepScheduleConfig{
+			Enabled:    true,
+			StartTime:  "22:00",
+			EndTime:    "07:00",
+			DimOpacity: 0.5,
+			Responses:  []string{"Five more minutes..."},
+		},
+	}
+	char := createTestCharacterInstance(card, false)
+
+	night := time.Date(2024, 1, 1, 23, 0, 0, 0, time.Local)
+	char.mu.Lock()
+	char.updateSleepState(night)
+	char.mu.Unlock()
+
+	if !char.IsSleeping() {
+		t.Fatal("Character should be asleep at 23:00")
+	}
+	if opacity := char.GetSleepOpacity(); opacity != 0.5 {
+		t.Errorf("Expected sleep opacity 0.5, got %f", opacity)
+	}
+	if response := char.HandleClick(); response != "Five more minutes..." {
+		t.Errorf("Expected sleepy response, got %q", response)
+	}
+
+	morning := time.Date(2024, 1, 2, 8, 0, 0, 0, time.Local)
+	char.mu.Lock()
+	char.updateSleepState(morning)
+	char.mu.Unlock()
+
+	if char.IsSleeping() {
+		t.Error("Character should be awake at 08:00")
+	}
+	if opacity := char.GetSleepOpacity(); opacity != 1.0 {
+		t.Errorf("Expected full opacity while awake, got %f", opacity)
+	}
+}
+
+func TestCharacterDoNotDisturb(t *testing.T) {
+	char := createTestCharacterInstance(createTestGameCharacterCard(), false)
+
+	if char.IsDoNotDisturb() {
+		t.Error("Do not disturb should be off by default")
+	}
+
+	char.SetDoNotDisturb(true)
+	if !char.IsDoNotDisturb() {
+		t.Error("Do not disturb should be on after enabling")
+	}
+
+	char.SetDoNotDisturb(false)
+	if char.IsDoNotDisturb() {
+		t.Error("Do not disturb should be off after disabling")
+	}
+}
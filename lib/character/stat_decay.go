@@ -0,0 +1,150 @@
+package character
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Decay curves for StatDecayConfig.Curve
+const (
+	DecayCurveLinear      = "linear"
+	DecayCurveExponential = "exponential"
+)
+
+// StatDecayConfig refines how a single stat decays. Without it a stat loses
+// degradationRate points per minute around the clock, including while the
+// app is closed.
+type StatDecayConfig struct {
+	Curve          string  `json:"curve,omitempty"`          // "linear" (default) or "exponential"
+	Rate           float64 `json:"rate,omitempty"`           // Linear: points per minute, overriding degradationRate. Exponential: fraction of the value lost per minute, between 0 and 1
+	ActiveHours    string  `json:"activeHours,omitempty"`    // Local "HH:MM-HH:MM" when the stat decays, may wrap past midnight; empty means always
+	PauseWhileAway bool    `json:"pauseWhileAway,omitempty"` // Don't decay for the time the app was closed
+}
+
+// Validate checks the curve, rate and active hours
+func (d *StatDecayConfig) Validate() error {
+	if d == nil {
+		return nil
+	}
+
+	switch d.Curve {
+	case "", DecayCurveLinear:
+		if d.Rate < 0 {
+			return fmt.Errorf("linear decay rate cannot be negative, got %f", d.Rate)
+		}
+	case DecayCurveExponential:
+		if d.Rate <= 0 || d.Rate >= 1 {
+			return fmt.Errorf("exponential decay rate must be between 0 and 1 (exclusive), got %f", d.Rate)
+		}
+	default:
+		return fmt.Errorf("decay curve must be %q or %q, got %q", DecayCurveLinear, DecayCurveExponential, d.Curve)
+	}
+
+	if _, _, err := parseActiveHours(d.ActiveHours); err != nil {
+		return fmt.Errorf("activeHours: %w", err)
+	}
+	return nil
+}
+
+// parseActiveHours parses "HH:MM-HH:MM" into minutes after midnight. An
+// empty value returns a window covering the whole day.
+func parseActiveHours(value string) (start, end int, err error) {
+	if value == "" {
+		return 0, 0, nil
+	}
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("must be in HH:MM-HH:MM format, got %q", value)
+	}
+	if start, err = parseClockTime(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClockTime(parts[1]); err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("start and end must differ, got %q", value)
+	}
+	return start, end, nil
+}
+
+// isExponential reports whether the stat decays by a fraction of its value
+func (s *Stat) isExponential() bool {
+	return s.Decay != nil && s.Decay.Curve == DecayCurveExponential
+}
+
+// decays reports whether the stat loses value over time at all
+func (s *Stat) decays() bool {
+	if s.isExponential() {
+		return s.Decay.Rate > 0
+	}
+	return s.DegradationRate > 0
+}
+
+// decayMinutes returns how many minutes of [from, to) count towards the
+// stat's decay: time before resumedAt, when the app was closed, is skipped
+// for stats paused while away, and only active hours count
+func (s *Stat) decayMinutes(from, to, resumedAt time.Time) float64 {
+	if s.Decay == nil {
+		return to.Sub(from).Minutes()
+	}
+	if s.Decay.PauseWhileAway && resumedAt.After(from) {
+		from = resumedAt
+	}
+	if !to.After(from) {
+		return 0
+	}
+
+	start, end, err := parseActiveHours(s.Decay.ActiveHours)
+	if err != nil || start == end {
+		return to.Sub(from).Minutes()
+	}
+	return activeOverlap(from, to, start, end).Minutes()
+}
+
+// decayBy returns the stat's value after minutes of decay
+func (s *Stat) decayBy(minutes float64) float64 {
+	if s.isExponential() {
+		return s.Current * math.Pow(1-s.Decay.Rate, minutes)
+	}
+	return math.Max(0, s.Current-s.DegradationRate*minutes)
+}
+
+// activeOverlap returns how much of [from, to) falls in the daily window
+// from start to end minutes after local midnight, wrapping past midnight
+// when end is before start
+func activeOverlap(from, to time.Time, start, end int) time.Duration {
+	var total time.Duration
+	// Start a day early so a window that wraps from the previous evening counts
+	y, m, d := from.AddDate(0, 0, -1).Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, from.Location()); day.Before(to); day = day.AddDate(0, 0, 1) {
+		windowStart := day.Add(time.Duration(start) * time.Minute)
+		windowEnd := day.Add(time.Duration(end) * time.Minute)
+		if end < start {
+			windowEnd = day.AddDate(0, 0, 1).Add(time.Duration(end) * time.Minute)
+		}
+		lo, hi := maxTime(from, windowStart), minTime(to, windowEnd)
+		if hi.After(lo) {
+			total += hi.Sub(lo)
+		}
+	}
+	return total
+}
+
+// maxTime returns the later of a and b
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// minTime returns the earlier of a and b
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,135 @@
+package character
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+)
+
+func TestStatDecayCurves(t *testing.T) {
+	gs := NewGameState(map[string]StatConfig{
+		"hunger":    {Initial: 100, Max: 100, DegradationRate: 1},
+		"energy":    {Initial: 100, Max: 100, DegradationRate: 1, Decay: &StatDecayConfig{Rate: 2}},
+		"happiness": {Initial: 100, Max: 100, Decay: &StatDecayConfig{Curve: DecayCurveExponential, Rate: 0.1}},
+	}, &GameConfig{StatsDecayInterval: time.Minute})
+
+	if gs.Stats["energy"].DegradationRate != 2 {
+		t.Errorf("A linear decay rate should replace degradationRate, got %f", gs.Stats["energy"].DegradationRate)
+	}
+
+	gs.LastDecayUpdate = time.Now().Add(-10 * time.Minute)
+	gs.Update(time.Second)
+
+	stats := gs.GetStats()
+	if math.Abs(stats["hunger"]-90) > 0.1 {
+		t.Errorf("Expected linear hunger ~90, got %f", stats["hunger"])
+	}
+	if math.Abs(stats["energy"]-80) > 0.1 {
+		t.Errorf("Expected linear energy ~80, got %f", stats["energy"])
+	}
+	// 100 * 0.9^10
+	if math.Abs(stats["happiness"]-34.87) > 0.1 {
+		t.Errorf("Expected exponential happiness ~34.87, got %f", stats["happiness"])
+	}
+}
+
+func TestStatDecayActiveHours(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	tests := []struct {
+		name     string
+		hours    string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"inside", "09:00-17:00", at(10, 0), at(11, 0), time.Hour},
+		{"before", "09:00-17:00", at(6, 0), at(8, 0), 0},
+		{"partial", "09:00-17:00", at(8, 0), at(10, 0), time.Hour},
+		{"several days", "09:00-17:00", at(0, 0), at(72, 0), 24 * time.Hour},
+		{"wraps midnight", "22:00-06:00", at(21, 0), at(31, 0), 8 * time.Hour},
+		{"wrapped from the evening before", "22:00-06:00", at(1, 0), at(7, 0), 5 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stat := &Stat{DegradationRate: 1, Decay: &StatDecayConfig{ActiveHours: tt.hours}}
+			got := stat.decayMinutes(tt.from, tt.to, time.Time{})
+			if math.Abs(got-tt.want.Minutes()) > 0.001 {
+				t.Errorf("Expected %v of decay, got %.1f minutes", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatDecayPauseWhileAway(t *testing.T) {
+	gs := NewGameState(map[string]StatConfig{
+		"hunger":    {Initial: 100, Max: 100, DegradationRate: 1},
+		"happiness": {Initial: 100, Max: 100, DegradationRate: 1, Decay: &StatDecayConfig{PauseWhileAway: true}},
+	}, &GameConfig{StatsDecayInterval: time.Minute})
+
+	// The app was closed for an hour
+	saved := &persistence.GameSaveData{GameState: &persistence.GameStateData{
+		Stats: map[string]*persistence.StatData{
+			"hunger":    {Current: 100},
+			"happiness": {Current: 100},
+		},
+		LastDecayUpdate: time.Now().Add(-time.Hour),
+	}}
+	char := &Character{gameState: gs}
+	char.RestoreSaveData(saved)
+	gs.Update(time.Second)
+
+	stats := gs.GetStats()
+	if stats["hunger"] > 41 {
+		t.Errorf("Hunger should decay for the time away, got %f", stats["hunger"])
+	}
+	if stats["happiness"] < 99.9 {
+		t.Errorf("Happiness should pause while away, got %f", stats["happiness"])
+	}
+
+	// Once running again it decays normally, here over simulated time
+	gs.rewind(10 * time.Minute)
+	gs.Update(time.Second)
+	if got := gs.GetStats()["happiness"]; math.Abs(got-90) > 0.1 {
+		t.Errorf("Expected happiness ~90 after ten minutes running, got %f", got)
+	}
+}
+
+func TestStatDecayValidation(t *testing.T) {
+	tests := []struct {
+		decay   StatDecayConfig
+		wantErr string
+	}{
+		{StatDecayConfig{}, ""},
+		{StatDecayConfig{Curve: DecayCurveLinear, Rate: 0.5, ActiveHours: "08:00-20:00", PauseWhileAway: true}, ""},
+		{StatDecayConfig{Curve: DecayCurveExponential, Rate: 0.05, ActiveHours: "22:00-06:00"}, ""},
+		{StatDecayConfig{Curve: "quadratic"}, "decay curve"},
+		{StatDecayConfig{Rate: -1}, "cannot be negative"},
+		{StatDecayConfig{Curve: DecayCurveExponential}, "between 0 and 1"},
+		{StatDecayConfig{Curve: DecayCurveExponential, Rate: 1}, "between 0 and 1"},
+		{StatDecayConfig{ActiveHours: "9-5"}, "activeHours"},
+		{StatDecayConfig{ActiveHours: "25:00-06:00"}, "activeHours"},
+		{StatDecayConfig{ActiveHours: "08:00-08:00"}, "must differ"},
+	}
+	for _, tt := range tests {
+		err := tt.decay.Validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %v", tt.decay, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%+v: expected error containing %q, got %v", tt.decay, tt.wantErr, err)
+		}
+	}
+
+	card := &CharacterCard{}
+	err := card.validateStatConfig("hunger", StatConfig{Max: 100, Decay: &StatDecayConfig{Curve: "sine"}})
+	if err == nil || !strings.Contains(err.Error(), "decay:") {
+		t.Errorf("Card validation should check decay, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package character
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+)
+
+// AnyAnimation matches every animation in a transition's from or to
+const AnyAnimation = "*"
+
+// maxCrossFadeMillis keeps cross-fades from holding up state changes
+const maxCrossFadeMillis = 5000
+
+// AnimationTransition smooths switching from one animation to another:
+// the old animation fades into the new one, a clip plays once in between,
+// or both, fading into the clip
+type AnimationTransition struct {
+	From      string `json:"from"`                // Animation switched from, "*" for any
+	To        string `json:"to"`                  // Animation switched to, "*" for any
+	CrossFade int    `json:"crossFade,omitempty"` // Milliseconds the old frame fades into the new one
+	Clip      string `json:"clip,omitempty"`      // Animation played once before the new one
+}
+
+// transitionKey identifies a transition by the animations it joins
+type transitionKey struct{ from, to string }
+
+// transitionFade blends the frame shown when a switch started into the
+// new animation's frames
+type transitionFade struct {
+	from     image.Image
+	start    time.Time
+	duration time.Duration
+	frame    image.Image // Blend for the current point of the fade
+}
+
+// validateTransitions checks the transitions against the card's animations
+func (c *CharacterCard) validateTransitions() error {
+	seen := make(map[transitionKey]bool)
+	for i, t := range c.Transitions {
+		for _, name := range []string{t.From, t.To} {
+			if name == "" {
+				return fmt.Errorf("transition %d needs from and to", i)
+			}
+			if _, exists := c.Animations[name]; !exists && name != AnyAnimation {
+				return fmt.Errorf("transition %d: animation '%s' not found in animations map", i, name)
+			}
+		}
+		if t.Clip != "" {
+			if _, exists := c.Animations[t.Clip]; !exists {
+				return fmt.Errorf("transition %d: clip '%s' not found in animations map", i, t.Clip)
+			}
+		}
+		if t.CrossFade < 0 || t.CrossFade > maxCrossFadeMillis {
+			return fmt.Errorf("transition %d: crossFade must be between 0 and %d ms, got %d", i, maxCrossFadeMillis, t.CrossFade)
+		}
+		if t.CrossFade == 0 && t.Clip == "" {
+			return fmt.Errorf("transition %d from '%s' to '%s' needs a crossFade or a clip", i, t.From, t.To)
+		}
+
+		key := transitionKey{t.From, t.To}
+		if seen[key] {
+			return fmt.Errorf("duplicate transition from '%s' to '%s'", t.From, t.To)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// SetTransitions sets the transitions played on later animation switches
+func (am *AnimationManager) SetTransitions(transitions []AnimationTransition) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.transitions = make(map[transitionKey]AnimationTransition, len(transitions))
+	for _, t := range transitions {
+		am.transitions[transitionKey{t.From, t.To}] = t
+	}
+}
+
+// IsTransitioning returns whether a cross-fade or transition clip is playing
+func (am *AnimationManager) IsTransitioning() bool {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	return am.fade != nil || len(am.queue) > 0
+}
+
+// findTransition returns the transition between two animations, preferring
+// exact matches over wildcards. Must be called with am.mu held.
+func (am *AnimationManager) findTransition(from, to string) (AnimationTransition, bool) {
+	for _, key := range []transitionKey{{from, to}, {from, AnyAnimation}, {AnyAnimation, to}, {AnyAnimation, AnyAnimation}} {
+		if t, ok := am.transitions[key]; ok {
+			return t, true
+		}
+	}
+	return AnimationTransition{}, false
+}
+
+// startTransition switches to name through its transition, queueing name
+// behind the clip if there is one. Returns false when there is no
+// transition to play. Must be called with am.mu held.
+func (am *AnimationManager) startTransition(name string) bool {
+	if am.target == "" || am.target == name {
+		return false
+	}
+	t, ok := am.findTransition(am.target, name)
+	if !ok {
+		return false
+	}
+
+	from := am.frameLocked()
+	am.fade = nil
+	am.queue = nil
+	if t.CrossFade > 0 && from != nil {
+		am.fade = &transitionFade{
+			from:     from,
+			start:    time.Now(),
+			duration: time.Duration(t.CrossFade) * time.Millisecond,
+		}
+	}
+
+	am.currentAnim = name
+	if _, exists := am.animations[t.Clip]; exists && t.Clip != name {
+		am.currentAnim = t.Clip
+		am.queue = []string{name}
+	}
+	am.frameIndex = 0
+	am.lastUpdate = time.Now()
+	am.updateFadeLocked()
+	return true
+}
+
+// advanceQueueLocked moves on from a finished transition clip to the next
+// queued animation. Must be called with am.mu held.
+func (am *AnimationManager) advanceQueueLocked() {
+	am.currentAnim = am.queue[0]
+	am.queue = am.queue[1:]
+	am.frameIndex = 0
+}
+
+// updateFadeLocked blends the fade's frame for now, ending the fade once
+// it has run. Returns whether a fade is running. Must be called with am.mu
+// held.
+func (am *AnimationManager) updateFadeLocked() bool {
+	if am.fade == nil {
+		return false
+	}
+	progress := float64(time.Since(am.fade.start)) / float64(am.fade.duration)
+	to := am.rawFrameLocked()
+	if progress >= 1 || to == nil {
+		am.fade = nil
+		return false
+	}
+	am.fade.frame = crossFade(am.fade.from, to, progress)
+	return true
+}
+
+// frameLocked returns the frame on screen, blended during a fade. Must be
+// called with am.mu held.
+func (am *AnimationManager) frameLocked() image.Image {
+	if am.fade != nil && am.fade.frame != nil {
+		return am.fade.frame
+	}
+	return am.rawFrameLocked()
+}
+
+// rawFrameLocked returns the current animation's current frame. Must be
+// called with am.mu held.
+func (am *AnimationManager) rawFrameLocked() image.Image {
+	currentGif, exists := am.animations[am.currentAnim]
+	if !exists || len(currentGif.Image) == 0 {
+		return nil
+	}
+	return currentGif.Image[am.frameIndex]
+}
+
+// crossFade blends from into to, progress running from 0 (all from) to 1
+// (all to). Pixels outside one image count as transparent.
+func crossFade(from, to image.Image, progress float64) image.Image {
+	bounds := from.Bounds().Union(to.Bounds())
+	blended := image.NewRGBA64(bounds)
+	lerp := func(a, b uint32) uint16 {
+		return uint16(float64(a)*(1-progress) + float64(b)*progress)
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, a1 := from.At(x, y).RGBA()
+			r2, g2, b2, a2 := to.At(x, y).RGBA()
+			blended.SetRGBA64(x, y, color.RGBA64{
+				R: lerp(r1, r2), G: lerp(g1, g2), B: lerp(b1, b2), A: lerp(a1, a2),
+			})
+		}
+	}
+	return blended
+}
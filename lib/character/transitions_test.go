@@ -0,0 +1,146 @@
+package character
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+// solidGIF returns an animation of frames frames filled with c, each shown
+// for delay hundredths of a second
+func solidGIF(c color.RGBA, frames, delay int) *gif.GIF {
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		img := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{c})
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return anim
+}
+
+func newTransitionTestManager(t *testing.T, transitions ...AnimationTransition) *AnimationManager {
+	t.Helper()
+
+	am := NewAnimationManager()
+	for name, c := range map[string]color.RGBA{
+		"idle":    {255, 0, 0, 255},
+		"talking": {0, 0, 255, 255},
+		"wave":    {0, 255, 0, 255},
+	} {
+		if err := am.LoadEmbeddedAnimation(name, solidGIF(c, 2, 1)); err != nil {
+			t.Fatalf("LoadEmbeddedAnimation failed: %v", err)
+		}
+	}
+	if err := am.SetCurrentAnimation("idle"); err != nil {
+		t.Fatalf("SetCurrentAnimation failed: %v", err)
+	}
+	am.SetTransitions(transitions)
+	return am
+}
+
+// waitForTransition updates the manager until the transition is over
+func waitForTransition(t *testing.T, am *AnimationManager) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); am.IsTransitioning(); {
+		if time.Now().After(deadline) {
+			t.Fatal("Transition never finished")
+		}
+		time.Sleep(5 * time.Millisecond)
+		am.Update()
+	}
+}
+
+func TestValidateTransitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		transitions []AnimationTransition
+		wantErr     bool
+	}{
+		{"none", nil, false},
+		{"valid", []AnimationTransition{{From: "idle", To: "talking", CrossFade: 200}, {From: "*", To: "happy", Clip: "eating"}}, false},
+		{"missing to", []AnimationTransition{{From: "idle", CrossFade: 200}}, true},
+		{"unknown animation", []AnimationTransition{{From: "idle", To: "missing", CrossFade: 200}}, true},
+		{"unknown clip", []AnimationTransition{{From: "idle", To: "talking", Clip: "missing"}}, true},
+		{"no effect", []AnimationTransition{{From: "idle", To: "talking"}}, true},
+		{"fade too long", []AnimationTransition{{From: "idle", To: "talking", CrossFade: 60000}}, true},
+		{"duplicate", []AnimationTransition{{From: "idle", To: "talking", CrossFade: 100}, {From: "idle", To: "talking", Clip: "happy"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := createTestGameCharacterCard()
+			card.Transitions = tt.transitions
+			if err := card.validateTransitions(); (err != nil) != tt.wantErr {
+				t.Errorf("validateTransitions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransitionClipPlaysOnce(t *testing.T) {
+	am := newTransitionTestManager(t, AnimationTransition{From: "idle", To: "talking", Clip: "wave"})
+
+	if err := am.SetCurrentAnimation("talking"); err != nil {
+		t.Fatalf("SetCurrentAnimation failed: %v", err)
+	}
+	if am.GetCurrentAnimationName() != "talking" || !am.IsTransitioning() {
+		t.Fatalf("Expected a transition into talking, got %q", am.GetCurrentAnimationName())
+	}
+	if r, g, _, _ := am.GetCurrentFrameImage().At(0, 0).RGBA(); r != 0 || g == 0 {
+		t.Error("Expected the clip to play first")
+	}
+
+	waitForTransition(t, am)
+	if _, _, b, _ := am.GetCurrentFrameImage().At(0, 0).RGBA(); b == 0 {
+		t.Error("Expected talking to play after the clip")
+	}
+
+	// Going back has no transition and switches at once
+	_ = am.SetCurrentAnimation("idle")
+	if am.IsTransitioning() {
+		t.Error("Expected no transition from talking to idle")
+	}
+}
+
+func TestTransitionCrossFade(t *testing.T) {
+	am := newTransitionTestManager(t, AnimationTransition{From: "*", To: "talking", CrossFade: 50})
+
+	_ = am.SetCurrentAnimation("talking")
+	time.Sleep(20 * time.Millisecond)
+	am.Update()
+	r, _, b, _ := am.GetCurrentFrameImage().At(0, 0).RGBA()
+	if r == 0 || b == 0 {
+		t.Errorf("Expected a blend of idle and talking, got r=%d b=%d", r, b)
+	}
+	if _, needsUpdate := am.GetCurrentFrame(); !needsUpdate {
+		t.Error("Expected every update of a fade to need drawing")
+	}
+
+	waitForTransition(t, am)
+	if r, _, b, _ := am.GetCurrentFrameImage().At(0, 0).RGBA(); r != 0 || b == 0 {
+		t.Errorf("Expected plain talking after the fade, got r=%d b=%d", r, b)
+	}
+}
+
+func TestFindTransitionPrefersExactMatches(t *testing.T) {
+	am := newTransitionTestManager(t,
+		AnimationTransition{From: "*", To: "*", CrossFade: 100},
+		AnimationTransition{From: "*", To: "talking", CrossFade: 200},
+		AnimationTransition{From: "idle", To: "talking", CrossFade: 300},
+	)
+
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{"idle", "talking", 300},
+		{"wave", "talking", 200},
+		{"idle", "wave", 100},
+	}
+	for _, tt := range tests {
+		if got, _ := am.findTransition(tt.from, tt.to); got.CrossFade != tt.want {
+			t.Errorf("findTransition(%q, %q) = %d ms, want %d", tt.from, tt.to, got.CrossFade, tt.want)
+		}
+	}
+}
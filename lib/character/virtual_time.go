@@ -0,0 +1,78 @@
+package character
+
+import "time"
+
+// AdvanceTime makes d pass for the character without waiting: every
+// timestamp it measures elapsed time from is moved d into the past, then the
+// character is updated once. Stat decay, cooldowns, idle timeouts, random
+// events and play time all see the skipped time. Schedules tied to the wall
+// clock (sleep, calendar, scheduled events, jobs, focus and news) do not.
+//
+// Step large jumps in increments of the decay interval so critical states
+// and random events fire along the way instead of all at once.
+func (c *Character) AdvanceTime(d time.Duration) bool {
+	if d <= 0 {
+		return c.Update()
+	}
+
+	c.mu.Lock()
+	c.lastStateChange = c.lastStateChange.Add(-d)
+	c.lastInteraction = c.lastInteraction.Add(-d)
+	c.lastRomanceEventCheck = c.lastRomanceEventCheck.Add(-d)
+	rewindTimes(c.dialogCooldowns, d)
+	rewindTimes(c.gameInteractionCooldowns, d)
+	rewindTimes(c.romanceEventCooldowns, d)
+	c.randomEventManager.rewind(d)
+	c.romanceEventManager.rewind(d)
+	c.gameState.rewind(d)
+	c.mu.Unlock()
+
+	return c.Update()
+}
+
+// rewindTimes moves every non-zero time in m back by d. Zero times mean
+// "never" and stay that way.
+func rewindTimes(m map[string]time.Time, d time.Duration) {
+	for key, t := range m {
+		if !t.IsZero() {
+			m[key] = t.Add(-d)
+		}
+	}
+}
+
+// rewind moves the last check and event cooldowns back by d
+func (rem *RandomEventManager) rewind(d time.Duration) {
+	if rem == nil {
+		return
+	}
+	rem.mu.Lock()
+	defer rem.mu.Unlock()
+	rem.lastCheck = rem.lastCheck.Add(-d)
+	rewindTimes(rem.eventCooldowns, d)
+}
+
+// rewind moves the decay clock and creation time back by d
+func (gs *GameState) rewind(d time.Duration) {
+	if gs == nil {
+		return
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.LastDecayUpdate = gs.LastDecayUpdate.Add(-d)
+	gs.CreationTime = gs.CreationTime.Add(-d)
+	if !gs.resumedAt.IsZero() {
+		// Simulated time passes with the app open
+		gs.resumedAt = gs.resumedAt.Add(-d)
+	}
+}
+
+// DecayInterval returns how often stats decay, the natural step for
+// AdvanceTime
+func (gs *GameState) DecayInterval() time.Duration {
+	if gs == nil {
+		return time.Minute
+	}
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.calculateDecayInterval()
+}
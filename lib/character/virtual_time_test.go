@@ -0,0 +1,32 @@
+package character
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvanceTime(t *testing.T) {
+	char, err := createTestCharacter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	char.gameState = NewGameState(map[string]StatConfig{
+		"hunger": {Initial: 100, Max: 100, DegradationRate: 1.0, CriticalThreshold: 20},
+	}, nil)
+	char.gameInteractionCooldowns["feed"] = time.Now()
+
+	char.AdvanceTime(10 * time.Minute)
+
+	if hunger := char.gameState.GetStat("hunger"); hunger < 89.9 || hunger > 90.1 {
+		t.Errorf("Expected 10 minutes of decay, hunger is %.2f", hunger)
+	}
+	if age := char.gameState.GetAge(); age < 10*time.Minute {
+		t.Errorf("Expected the character to age, got %s", age)
+	}
+	if time.Since(char.gameInteractionCooldowns["feed"]) < 10*time.Minute {
+		t.Error("Expected cooldowns to run down with virtual time")
+	}
+	if char.gameState.DecayInterval() != time.Minute {
+		t.Errorf("Expected the default decay interval, got %s", char.gameState.DecayInterval())
+	}
+}
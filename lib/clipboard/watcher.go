@@ -0,0 +1,258 @@
+// Package clipboard lets the companion react to copied text. It is opt-in
+// and private by design: only the kind of content is reported, never the
+// content itself (except a copied URL, so it can be summarized on request),
+// nothing is logged or stored, and likely secrets are ignored entirely.
+package clipboard
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Kind is a reaction type users can allow
+type Kind string
+
+const (
+	KindURL   Kind = "url"   // A single http(s) link
+	KindCode  Kind = "code"  // Source code
+	KindEmail Kind = "email" // An email address
+	KindColor Kind = "color" // A hex color such as #ff8800
+	KindText  Kind = "text"  // Any other prose
+)
+
+// AllKinds lists every reaction type in display order
+var AllKinds = []Kind{KindURL, KindCode, KindEmail, KindColor, KindText}
+
+// DefaultKinds are allowed when the user hasn't picked any
+var DefaultKinds = []Kind{KindURL, KindCode}
+
+const (
+	// DefaultPollInterval is how often the clipboard is checked
+	DefaultPollInterval = 2 * time.Second
+
+	// reactionCooldown keeps a burst of copies from becoming a burst of dialogs
+	reactionCooldown = 30 * time.Second
+
+	// maxClassifyLength skips huge clipboards instead of scanning them
+	maxClassifyLength = 20000
+)
+
+var (
+	emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[a-zA-Z]{2,}$`)
+	colorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	cardPattern  = regexp.MustCompile(`^[0-9][0-9 -]{10,22}[0-9]$`)
+	codeMarkers  = []string{"func ", "function ", "def ", "class ", "import ", "package ", "#include", "return ", "=>", "};", "){", ") {", "</"}
+)
+
+// ParseKinds parses a comma-separated allowlist such as "url,code"
+func ParseKinds(spec string) ([]Kind, error) {
+	var kinds []Kind
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		if !IsKnownKind(name) {
+			return nil, fmt.Errorf("unknown clipboard reaction %q", name)
+		}
+		kinds = append(kinds, Kind(name))
+	}
+	return kinds, nil
+}
+
+// IsKnownKind reports whether name is a reaction type
+func IsKnownKind(name string) bool {
+	for _, kind := range AllKinds {
+		if string(kind) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify returns the kind of copied text, or "" for content the companion
+// should never react to: empty, huge, or likely a password, token or card number.
+func Classify(text string) Kind {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > maxClassifyLength {
+		return ""
+	}
+
+	if !strings.ContainsAny(text, " \t\n") {
+		switch {
+		case isWebURL(text):
+			return KindURL
+		case emailPattern.MatchString(text):
+			return KindEmail
+		case colorPattern.MatchString(text):
+			return KindColor
+		case looksLikeSecret(text):
+			return ""
+		}
+	}
+	if cardPattern.MatchString(text) {
+		return ""
+	}
+	if looksLikeCode(text) {
+		return KindCode
+	}
+	return KindText
+}
+
+// isWebURL reports whether text is a single http(s) link
+func isWebURL(text string) bool {
+	u, err := url.Parse(text)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// looksLikeSecret flags single long tokens that mix character classes, the
+// shape of passwords and API keys
+func looksLikeSecret(text string) bool {
+	if len(text) < 12 {
+		return false
+	}
+	var lower, upper, digit, other bool
+	for _, r := range text {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			classes++
+		}
+	}
+	return classes >= 3
+}
+
+// looksLikeCode guesses source code from common keywords and punctuation
+func looksLikeCode(text string) bool {
+	hits := 0
+	for _, marker := range codeMarkers {
+		if strings.Contains(text, marker) {
+			hits++
+		}
+	}
+	if strings.Count(text, ";\n") >= 2 || strings.Count(text, "{\n") >= 1 {
+		hits++
+	}
+	return hits >= 2
+}
+
+// Event is a clipboard change the companion may react to
+type Event struct {
+	Kind Kind
+	URL  string // Only set for KindURL
+}
+
+// Watcher polls the clipboard and reports allowed changes. It keeps only a
+// hash of the last content to detect changes.
+type Watcher struct {
+	mu        sync.Mutex
+	read      func() string
+	allowed   map[Kind]bool
+	onEvent   func(Event)
+	lastHash  [sha256.Size]byte
+	primed    bool
+	lastEvent time.Time
+	cooldown  time.Duration
+	stop      chan struct{}
+}
+
+// NewWatcher creates a watcher that reads the clipboard with read and calls
+// onEvent for changes of an allowed kind. An empty allowlist uses DefaultKinds.
+func NewWatcher(read func() string, kinds []Kind, onEvent func(Event)) *Watcher {
+	if len(kinds) == 0 {
+		kinds = DefaultKinds
+	}
+	allowed := make(map[Kind]bool, len(kinds))
+	for _, kind := range kinds {
+		allowed[kind] = true
+	}
+	return &Watcher{
+		read:     read,
+		allowed:  allowed,
+		onEvent:  onEvent,
+		cooldown: reactionCooldown,
+	}
+}
+
+// Start polls every interval until Stop. Whatever is already on the
+// clipboard is ignored; only later copies count.
+func (w *Watcher) Start(interval time.Duration) {
+	w.mu.Lock()
+	if w.stop != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.stop = make(chan struct{})
+	stop := w.stop
+	w.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		w.poll(time.Now())
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				w.poll(now)
+			}
+		}
+	}()
+}
+
+// Stop ends polling
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// poll checks the clipboard once
+func (w *Watcher) poll(now time.Time) {
+	text := w.read()
+	hash := sha256.Sum256([]byte(text))
+
+	w.mu.Lock()
+	changed := w.primed && hash != w.lastHash
+	w.lastHash = hash
+	w.primed = true
+	if !changed || now.Sub(w.lastEvent) < w.cooldown {
+		w.mu.Unlock()
+		return
+	}
+
+	kind := Classify(text)
+	if kind == "" || !w.allowed[kind] {
+		w.mu.Unlock()
+		return
+	}
+	w.lastEvent = now
+	w.mu.Unlock()
+
+	event := Event{Kind: kind}
+	if kind == KindURL {
+		event.URL = strings.TrimSpace(text)
+	}
+	w.onEvent(event)
+}
@@ -0,0 +1,64 @@
+package comfyui
+
+// cancel.go stops jobs on the server when the caller gives up on them, so an
+// interrupted generation doesn't keep the GPU busy. Like ImageUploader it is
+// an optional interface callers type-assert for.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// JobCanceller is implemented by clients that can stop a submitted job.
+type JobCanceller interface {
+	// CancelJob removes the job from the queue if it hasn't started and
+	// interrupts it if it is running.
+	CancelJob(ctx context.Context, jobID string) error
+}
+
+// CancelJob deletes the job from ComfyUI's /queue, then interrupts it via
+// /interrupt. Interrupting by prompt_id leaves other clients' jobs running.
+func (c *client) CancelJob(ctx context.Context, jobID string) error {
+	if jobID == "" {
+		return errors.New("jobID required")
+	}
+	if err := c.postJSON(ctx, "/queue", map[string]interface{}{"delete": []string{jobID}}); err != nil {
+		return fmt.Errorf("remove queued job: %w", err)
+	}
+	if err := c.postJSON(ctx, "/interrupt", map[string]string{"prompt_id": jobID}); err != nil {
+		return fmt.Errorf("interrupt job: %w", err)
+	}
+	return nil
+}
+
+// postJSON posts body as JSON and expects a 200 response.
+func (c *client) postJSON(ctx context.Context, path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.ServerURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
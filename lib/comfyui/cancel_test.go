@@ -0,0 +1,43 @@
+package comfyui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCancelJob(t *testing.T) {
+	var paths []string
+	var interrupt map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/interrupt" {
+			json.NewDecoder(r.Body).Decode(&interrupt)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.ServerURL = srv.URL
+	cli, _ := New(cfg)
+
+	canceller, ok := cli.(JobCanceller)
+	if !ok {
+		t.Fatal("expected the HTTP client to implement JobCanceller")
+	}
+	if err := canceller.CancelJob(context.Background(), "abc"); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/queue" || paths[1] != "/interrupt" {
+		t.Errorf("expected queue delete then interrupt, got %v", paths)
+	}
+	if interrupt["prompt_id"] != "abc" {
+		t.Errorf("expected the interrupt to target the job, got %v", interrupt)
+	}
+	if err := canceller.CancelJob(context.Background(), ""); err == nil {
+		t.Error("expected an error for an empty job id")
+	}
+}
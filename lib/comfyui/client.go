@@ -263,6 +263,9 @@ type JobProgress struct {
 	Status   string  `json:"status"`
 	Progress float64 `json:"progress"`
 	Message  string  `json:"message,omitempty"`
+	Node     string  `json:"node,omitempty"`      // Workflow node executing, when the server reports it
+	Step     int     `json:"step,omitempty"`      // Sampler step within Node
+	MaxSteps int     `json:"max_steps,omitempty"` // Sampler steps in Node; 0 for nodes without steps
 	Err      error   `json:"-"`
 }
 
@@ -271,7 +274,8 @@ type JobProgress struct {
 //   - Simplicity: single output channel, errors embedded in final message.
 //   - Early validation: reject empty jobID.
 //   - Graceful termination: close on completion states (completed, failed, error).
-//   - Minimal parsing: decode JSON into JobProgress; unrecognized fields ignored.
+//   - Minimal parsing: flat JobProgress frames or ComfyUI's native events
+//     (see progress.go); unrecognized fields ignored.
 //   - No reconnection logic yet — can be layered later without changing signature.
 func (c *client) MonitorJob(ctx context.Context, jobID string) (<-chan JobProgress, error) {
 	if jobID == "" {
@@ -331,7 +335,10 @@ func (c *client) readProgress(ctx context.Context, conn *ws.Conn, jobID string,
 			ch <- JobProgress{JobID: jobID, Status: "error", Err: err}
 			return
 		}
-		prog, terminal := parseProgress(data, jobID)
+		prog, terminal, ok := decodeFrame(data, jobID)
+		if !ok {
+			continue
+		}
 		ch <- prog
 		if terminal || prog.Err != nil {
 			return
@@ -0,0 +1,58 @@
+package comfyui
+
+// models.go lists the model files installed on the server, so a batch can
+// check its checkpoints and LoRAs exist before queueing hours of work. Like
+// JobCanceller it is an optional interface callers type-assert for.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Model folders as ComfyUI names them under its models directory.
+const (
+	ModelFolderCheckpoints = "checkpoints"
+	ModelFolderLoras       = "loras"
+	ModelFolderVAE         = "vae"
+	ModelFolderControlNet  = "controlnet"
+)
+
+// ModelLister is implemented by clients that can list installed models.
+type ModelLister interface {
+	// ListModels returns the file names in a model folder such as
+	// ModelFolderCheckpoints.
+	ListModels(ctx context.Context, folder string) ([]string, error)
+}
+
+// ListModels queries /models/{folder}. An unknown folder is an error.
+func (c *client) ListModels(ctx context.Context, folder string) ([]string, error) {
+	if folder == "" {
+		return nil, errors.New("folder required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.ServerURL+"/models/"+url.PathEscape(folder), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", folder, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(b))
+	}
+	var names []string
+	if err := json.NewDecoder(resp.Body).Decode(&names); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", folder, err)
+	}
+	return names, nil
+}
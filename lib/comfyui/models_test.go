@@ -0,0 +1,42 @@
+package comfyui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/loras" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode([]string{"chibi_style.safetensors", "sub/pixel.safetensors"})
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.ServerURL = srv.URL
+	cli, _ := New(cfg)
+
+	lister, ok := cli.(ModelLister)
+	if !ok {
+		t.Fatal("expected the HTTP client to implement ModelLister")
+	}
+	names, err := lister.ListModels(context.Background(), ModelFolderLoras)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(names) != 2 || names[0] != "chibi_style.safetensors" {
+		t.Errorf("unexpected models %v", names)
+	}
+	if _, err := lister.ListModels(context.Background(), "unknown"); err == nil {
+		t.Error("expected an error for an unknown folder")
+	}
+	if _, err := lister.ListModels(context.Background(), ""); err == nil {
+		t.Error("expected an error for an empty folder")
+	}
+}
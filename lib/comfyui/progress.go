@@ -0,0 +1,86 @@
+package comfyui
+
+// progress.go decodes ComfyUI's native WebSocket events. The server wraps
+// every message as {"type": ..., "data": {...}} and broadcasts events for all
+// prompts to every client, so frames are filtered by prompt_id. Sampler
+// nodes report per-step progress; other nodes only announce that they are
+// executing. Flat {"job_id", "status", "progress"} frames are still
+// understood by parseProgress.
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// comfyEvent is the envelope of a native ComfyUI WebSocket message.
+type comfyEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// comfyEventData holds the fields used from any native event's data.
+type comfyEventData struct {
+	PromptID         string  `json:"prompt_id"`
+	Node             *string `json:"node"`    // executing, progress; null when the prompt finished
+	NodeID           string  `json:"node_id"` // execution_error
+	Value            int     `json:"value"`   // progress
+	Max              int     `json:"max"`     // progress
+	ExceptionMessage string  `json:"exception_message"`
+}
+
+// decodeFrame parses a WebSocket frame in either format. ok is false for
+// frames that carry nothing for jobID: other prompts, queue status and
+// cache notices.
+func decodeFrame(data []byte, jobID string) (prog JobProgress, terminal, ok bool) {
+	var event comfyEvent
+	if err := json.Unmarshal(data, &event); err != nil || event.Type == "" {
+		prog, terminal = parseProgress(data, jobID)
+		return prog, terminal, true
+	}
+	return parseComfyEvent(event, jobID)
+}
+
+// parseComfyEvent maps a native event to a progress update for jobID.
+func parseComfyEvent(event comfyEvent, jobID string) (prog JobProgress, terminal, ok bool) {
+	var d comfyEventData
+	if len(event.Data) > 0 {
+		if err := json.Unmarshal(event.Data, &d); err != nil {
+			return JobProgress{JobID: jobID, Status: "error", Err: fmt.Errorf("decode %s event: %w", event.Type, err)}, true, true
+		}
+	}
+	// Older servers omit prompt_id from progress events
+	if d.PromptID != "" && d.PromptID != jobID {
+		return JobProgress{}, false, false
+	}
+
+	prog = JobProgress{JobID: jobID, Status: "running"}
+	switch event.Type {
+	case "execution_start":
+	case "executing":
+		if d.Node == nil {
+			prog.Status, prog.Progress = "completed", 1
+			return prog, true, true
+		}
+		prog.Node = *d.Node
+	case "progress":
+		if d.Node != nil {
+			prog.Node = *d.Node
+		}
+		prog.Step, prog.MaxSteps = d.Value, d.Max
+		if d.Max > 0 {
+			prog.Progress = float64(d.Value) / float64(d.Max)
+		}
+	case "execution_success":
+		prog.Status, prog.Progress = "completed", 1
+		return prog, true, true
+	case "execution_error":
+		prog.Status, prog.Node, prog.Message = "failed", d.NodeID, d.ExceptionMessage
+		return prog, true, true
+	case "execution_interrupted":
+		prog.Status = "cancelled"
+		return prog, true, true
+	default:
+		return JobProgress{}, false, false
+	}
+	return prog, false, true
+}
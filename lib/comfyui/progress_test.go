@@ -0,0 +1,88 @@
+package comfyui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ws "nhooyr.io/websocket"
+)
+
+func TestDecodeFrameNativeEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		frame        string
+		wantOK       bool
+		wantTerminal bool
+		want         JobProgress
+	}{
+		{"flat frame", `{"job_id":"p1","status":"running","progress":0.5}`, true, false,
+			JobProgress{JobID: "p1", Status: "running", Progress: 0.5}},
+		{"sampler step", `{"type":"progress","data":{"value":5,"max":20,"prompt_id":"p1","node":"3"}}`, true, false,
+			JobProgress{JobID: "p1", Status: "running", Progress: 0.25, Node: "3", Step: 5, MaxSteps: 20}},
+		{"executing node", `{"type":"executing","data":{"node":"8","prompt_id":"p1"}}`, true, false,
+			JobProgress{JobID: "p1", Status: "running", Node: "8"}},
+		{"finished", `{"type":"executing","data":{"node":null,"prompt_id":"p1"}}`, true, true,
+			JobProgress{JobID: "p1", Status: "completed", Progress: 1}},
+		{"success", `{"type":"execution_success","data":{"prompt_id":"p1"}}`, true, true,
+			JobProgress{JobID: "p1", Status: "completed", Progress: 1}},
+		{"error", `{"type":"execution_error","data":{"prompt_id":"p1","node_id":"3","exception_message":"out of memory"}}`, true, true,
+			JobProgress{JobID: "p1", Status: "failed", Node: "3", Message: "out of memory"}},
+		{"interrupted", `{"type":"execution_interrupted","data":{"prompt_id":"p1"}}`, true, true,
+			JobProgress{JobID: "p1", Status: "cancelled"}},
+		{"other prompt", `{"type":"progress","data":{"value":1,"max":20,"prompt_id":"p2"}}`, false, false, JobProgress{}},
+		{"queue status", `{"type":"status","data":{"status":{"exec_info":{"queue_remaining":1}}}}`, false, false, JobProgress{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prog, terminal, ok := decodeFrame([]byte(tt.frame), "p1")
+			if ok != tt.wantOK || terminal != tt.wantTerminal {
+				t.Fatalf("expected ok=%v terminal=%v, got ok=%v terminal=%v", tt.wantOK, tt.wantTerminal, ok, terminal)
+			}
+			if ok && prog != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, prog)
+			}
+		})
+	}
+}
+
+func TestMonitorJobNativeEvents(t *testing.T) {
+	srv := startWSServer(t, func(ctx context.Context, c *ws.Conn) {
+		msgs := []string{
+			`{"type":"status","data":{"status":{"exec_info":{"queue_remaining":1}}}}`,
+			`{"type":"execution_start","data":{"prompt_id":"abc"}}`,
+			`{"type":"progress","data":{"value":1,"max":2,"prompt_id":"abc","node":"3"}}`,
+			`{"type":"progress","data":{"value":1,"max":9,"prompt_id":"other","node":"3"}}`,
+			`{"type":"progress","data":{"value":2,"max":2,"prompt_id":"abc","node":"3"}}`,
+			`{"type":"executing","data":{"node":null,"prompt_id":"abc"}}`,
+		}
+		for _, m := range msgs {
+			if err := c.Write(ctx, ws.MessageText, []byte(m)); err != nil {
+				return
+			}
+		}
+	})
+	defer srv.Close()
+	cfg := DefaultConfig()
+	cfg.ServerURL = srv.URL
+	cfg.WSPath = "/"
+	cli, _ := New(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	ch, err := cli.MonitorJob(ctx, "abc")
+	if err != nil {
+		t.Fatalf("monitor: %v", err)
+	}
+	var got []JobProgress
+	for p := range ch {
+		got = append(got, p)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 updates for the job, got %d: %+v", len(got), got)
+	}
+	if got[1].Step != 1 || got[1].MaxSteps != 2 || got[3].Status != "completed" {
+		t.Errorf("unexpected updates: %+v", got)
+	}
+}
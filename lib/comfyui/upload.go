@@ -0,0 +1,98 @@
+package comfyui
+
+// upload.go adds reference image uploads for image-to-image workflows.
+// It is an optional interface so existing Client implementations and test
+// doubles keep compiling; callers type-assert when they need it.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// ImageUploader is implemented by clients that can store input images on
+// the ComfyUI server for LoadImage nodes.
+type ImageUploader interface {
+	// UploadImage stores data as name in the server's input directory,
+	// replacing any file with the same name, and returns the stored name.
+	UploadImage(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// uploadResponse is the JSON body returned by /upload/image.
+type uploadResponse struct {
+	Name      string `json:"name"`
+	Subfolder string `json:"subfolder"`
+	Type      string `json:"type"`
+}
+
+// UploadImage posts the image to ComfyUI's /upload/image endpoint.
+func (c *client) UploadImage(ctx context.Context, name string, data []byte) (string, error) {
+	if name == "" {
+		return "", errors.New("image name required")
+	}
+
+	body, contentType, err := uploadForm(name, data)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.ServerURL+"/upload/image", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(b))
+	}
+
+	var uploaded uploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&uploaded); err != nil {
+		return "", fmt.Errorf("decode upload response: %w", err)
+	}
+	if uploaded.Name == "" {
+		return "", errors.New("empty image name in response")
+	}
+	if uploaded.Subfolder != "" {
+		return uploaded.Subfolder + "/" + uploaded.Name, nil
+	}
+	return uploaded.Name, nil
+}
+
+// uploadForm builds the multipart body ComfyUI expects for an input image.
+func uploadForm(name string, data []byte) ([]byte, string, error) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+
+	part, err := form.CreateFormFile("image", name)
+	if err != nil {
+		return nil, "", fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", fmt.Errorf("write form file: %w", err)
+	}
+	if err := form.WriteField("type", "input"); err != nil {
+		return nil, "", fmt.Errorf("write form field: %w", err)
+	}
+	if err := form.WriteField("overwrite", "true"); err != nil {
+		return nil, "", fmt.Errorf("write form field: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", fmt.Errorf("close form: %w", err)
+	}
+	return buf.Bytes(), form.FormDataContentType(), nil
+}
@@ -0,0 +1,52 @@
+package comfyui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestUploadImage(t *testing.T) {
+	cli, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/upload/image" {
+			t.Fatalf("unexpected path/method: %s %s", r.Method, r.URL.Path)
+		}
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			t.Fatalf("missing image field: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if header.Filename != "ref.png" || string(data) != "png-bytes" {
+			t.Fatalf("unexpected upload %s %q", header.Filename, data)
+		}
+		if r.FormValue("overwrite") != "true" {
+			t.Fatalf("expected overwrite=true")
+		}
+		_, _ = w.Write([]byte(`{"name":"ref.png","subfolder":"","type":"input"}`))
+	})
+	defer srv.Close()
+
+	uploader, ok := cli.(ImageUploader)
+	if !ok {
+		t.Fatal("client should implement ImageUploader")
+	}
+	name, err := uploader.UploadImage(context.Background(), "ref.png", []byte("png-bytes"))
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if name != "ref.png" {
+		t.Fatalf("unexpected name: %s", name)
+	}
+}
+
+func TestUploadImageError(t *testing.T) {
+	cli, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad image", http.StatusBadRequest)
+	})
+	defer srv.Close()
+
+	if _, err := cli.(ImageUploader).UploadImage(context.Background(), "ref.png", nil); err == nil {
+		t.Fatal("expected error for rejected upload")
+	}
+}
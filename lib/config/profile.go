@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// DefaultProfile is the profile used when none is chosen. It keeps the
+// directory layout from before profiles existed, so earlier saves still load.
+const DefaultProfile = "default"
+
+// profilesDirName holds the named profiles in the user config directory
+const profilesDirName = "profiles"
+
+// profileNamePattern allows names that are safe as directory names everywhere
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,31}$`)
+
+// Profile is one user's separate relationship with the companion: their own
+// saves, chat exports and remembered preferences. Settings like window size
+// are shared by all profiles.
+type Profile struct {
+	Name string
+	Root string // Directory holding the profile's data
+}
+
+// ValidateProfileName checks that name can be used as a profile
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use up to 32 letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+// NewProfile returns the named profile in the user config directory. An
+// empty name is the default profile.
+func NewProfile(name string) (Profile, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return profileIn(filepath.Join(configDir, "desktop-companion"), name)
+}
+
+// profileIn returns the named profile under base
+func profileIn(base, name string) (Profile, error) {
+	if name == "" || name == DefaultProfile {
+		return Profile{Name: DefaultProfile, Root: base}, nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return Profile{}, err
+	}
+	return Profile{Name: name, Root: filepath.Join(base, profilesDirName, name)}, nil
+}
+
+// IsDefault reports whether p is the default profile
+func (p Profile) IsDefault() bool {
+	return p.Name == DefaultProfile
+}
+
+// SaveDir returns where the profile's games are auto-saved
+func (p Profile) SaveDir() string {
+	return filepath.Join(p.Root, "saves")
+}
+
+// SaveDirUnder returns the profile's save directory inside a save directory
+// chosen with -save-dir, which all profiles share
+func (p Profile) SaveDirUnder(base string) string {
+	if p.IsDefault() {
+		return base
+	}
+	return filepath.Join(base, profilesDirName, p.Name)
+}
+
+// ChatDir returns where the profile's chat conversations are exported
+func (p Profile) ChatDir() string {
+	return filepath.Join(p.Root, "chat")
+}
+
+// MemoryDir returns where the profile's analytics and relationship tuning
+// are kept. The default profile keeps them directly in its root.
+func (p Profile) MemoryDir() string {
+	if p.IsDefault() {
+		return p.Root
+	}
+	return filepath.Join(p.Root, "memory")
+}
+
+// ListProfiles returns the default profile followed by the named profiles
+// that have been used, sorted by name
+func ListProfiles() ([]string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return listProfilesIn(filepath.Join(configDir, "desktop-companion"))
+}
+
+// listProfilesIn lists the profiles under base
+func listProfilesIn(base string) ([]string, error) {
+	names := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(filepath.Join(base, profilesDirName))
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return names, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var named []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProfileName(entry.Name()) == nil && entry.Name() != DefaultProfile {
+			named = append(named, entry.Name())
+		}
+	}
+	sort.Strings(named)
+	return append(names, named...), nil
+}
+
+// Create makes the profile's directory so it is listed from now on
+func (p Profile) Create() error {
+	if err := os.MkdirAll(p.Root, 0o755); err != nil {
+		return fmt.Errorf("failed to create profile %s: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfileDirectories(t *testing.T) {
+	base := t.TempDir()
+
+	def, err := profileIn(base, "")
+	if err != nil {
+		t.Fatalf("profileIn failed: %v", err)
+	}
+	if !def.IsDefault() || def.Name != DefaultProfile {
+		t.Fatalf("Expected the default profile, got %+v", def)
+	}
+	// The default profile keeps the layout from before profiles existed
+	if def.SaveDir() != filepath.Join(base, "saves") || def.MemoryDir() != base {
+		t.Errorf("Default profile moved its data: saves %s, memory %s", def.SaveDir(), def.MemoryDir())
+	}
+	if def.SaveDirUnder("/custom") != "/custom" {
+		t.Errorf("Default profile should use -save-dir as is, got %s", def.SaveDirUnder("/custom"))
+	}
+
+	kid, err := profileIn(base, "kid")
+	if err != nil {
+		t.Fatalf("profileIn failed: %v", err)
+	}
+	root := filepath.Join(base, "profiles", "kid")
+	if kid.SaveDir() != filepath.Join(root, "saves") ||
+		kid.ChatDir() != filepath.Join(root, "chat") ||
+		kid.MemoryDir() != filepath.Join(root, "memory") {
+		t.Errorf("Unexpected directories for %+v", kid)
+	}
+	if got := kid.SaveDirUnder("/custom"); got != filepath.Join("/custom", "profiles", "kid") {
+		t.Errorf("Expected a subdirectory of -save-dir, got %s", got)
+	}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"kid", "Mum_2", "a-b"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("Expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../evil", "a/b", ".hidden", "has space", "x123456789012345678901234567890123"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+		if _, err := profileIn(t.TempDir(), name); name != "" && err == nil {
+			t.Errorf("profileIn accepted %q", name)
+		}
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	base := t.TempDir()
+
+	names, err := listProfilesIn(base)
+	if err != nil || !reflect.DeepEqual(names, []string{DefaultProfile}) {
+		t.Fatalf("Expected only the default profile, got %v, %v", names, err)
+	}
+
+	for _, name := range []string{"zoe", "alex"} {
+		p, _ := profileIn(base, name)
+		if err := p.Create(); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	// Stray files and unusable names aren't profiles
+	if err := os.WriteFile(filepath.Join(base, "profiles", "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(base, "profiles", ".cache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err = listProfilesIn(base)
+	if err != nil {
+		t.Fatalf("listProfilesIn failed: %v", err)
+	}
+	if want := []string{DefaultProfile, "alex", "zoe"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
+
+func TestSettingsApplyToFlagsProfile(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	profile := fs.String("profile", "", "")
+
+	settings := &Settings{Profile: "kid"}
+	if err := settings.ApplyToFlags(fs); err != nil {
+		t.Fatalf("ApplyToFlags failed: %v", err)
+	}
+	if *profile != "kid" {
+		t.Errorf("Expected the saved profile as the flag default, got %q", *profile)
+	}
+}
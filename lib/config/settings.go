@@ -0,0 +1,184 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SettingsFileName is the settings file in the user config directory
+const SettingsFileName = "settings.json"
+
+// Settings holds user choices that would otherwise have to be passed as
+// flags every launch. They are applied as flag defaults before flag parsing,
+// so anything given on the command line still wins.
+type Settings struct {
+	Character          string `json:"character,omitempty"` // Path to character.json
+	GameMode           bool   `json:"gameMode"`
+	ShowStats          bool   `json:"showStats"`
+	Network            bool   `json:"network"`
+	NetworkUI          bool   `json:"networkUI"`
+	SaveDir            string `json:"saveDir,omitempty"` // Where game progress is auto-saved
+	Profile            string `json:"profile,omitempty"` // Profile picked in the tray menu, empty means default
+	OnboardingComplete bool   `json:"onboardingComplete"`
+
+	Debug          bool    `json:"debug"`
+	EventFrequency float64 `json:"eventFrequency,omitempty"` // Random event multiplier, 0 keeps 1.0
+	Scale          float64 `json:"scale,omitempty"`          // Multiplier of the card's default size, 0 keeps 1.0
+	TTS            bool    `json:"tts"`                      // Read dialog aloud
+	Metrics        bool    `json:"metrics"`                  // Show the FPS and memory overlay
+
+	Clipboard      bool     `json:"clipboard"`                // React to copied text (opt-in)
+	ClipboardKinds []string `json:"clipboardKinds,omitempty"` // Allowed reaction types, empty means url and code
+
+	PushURL       string   `json:"pushURL,omitempty"`       // ntfy topic or Gotify URL for phone notifications
+	Notifications []string `json:"notifications,omitempty"` // Events raised as desktop notifications: "critical", "achievement", "battle", "reminder"
+
+	NewsCategories []string `json:"newsCategories,omitempty"` // Feed categories in the daily briefing, empty means all
+	NewsDigestTime string   `json:"newsDigestTime,omitempty"` // Briefing time as HH:MM, empty uses the card's
+
+	Theme       string            `json:"theme,omitempty"`       // "system", "light", "dark" or "custom", empty means system
+	ThemeColors map[string]string `json:"themeColors,omitempty"` // Hex colors for the custom theme, e.g. "primary": "#ff8800"
+
+	ClickThrough string `json:"clickThrough,omitempty"` // What takes clicks: "sprite", "bounds" or "off" (whole window), empty means sprite
+	Fullscreen   string `json:"fullscreen,omitempty"`   // While another app is fullscreen: "hide", "corner", "pause" or "off", empty means hide
+
+	BattleLatency int `json:"battleLatency,omitempty"` // Highest ping in ms a battle starts with, 0 means 300, negative means no limit
+}
+
+// DefaultSettingsPath returns the settings file in the user config dir
+func DefaultSettingsPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "desktop-companion", SettingsFileName), nil
+}
+
+// DefaultSaveDir returns the default auto-save directory
+func DefaultSaveDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "desktop-companion", "saves"), nil
+}
+
+// DefaultCharactersDir returns where character packs shared by peers are saved
+func DefaultCharactersDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "desktop-companion", "characters"), nil
+}
+
+// DefaultLogDir returns where each character's log file is written
+func DefaultLogDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "desktop-companion", "logs"), nil
+}
+
+// LoadSettings reads settings from path. A missing file returns an error
+// matching os.ErrNotExist, which callers use to detect a first run.
+func LoadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings %s: %w", path, err)
+	}
+	return &settings, nil
+}
+
+// Save writes settings to path, creating the directory if needed
+func (s *Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a half-written file
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
+
+// ApplyToFlags makes the settings the flag defaults. Call it before Parse so
+// command line flags override the file; the flags stay unset, so fs.Visit
+// still reports only what was passed on the command line. Flags the set
+// doesn't define are skipped, and a bad value doesn't stop the rest from
+// being applied.
+func (s *Settings) ApplyToFlags(fs *flag.FlagSet) error {
+	values := map[string]string{
+		"game":       strconv.FormatBool(s.GameMode),
+		"stats":      strconv.FormatBool(s.ShowStats),
+		"network":    strconv.FormatBool(s.Network),
+		"network-ui": strconv.FormatBool(s.NetworkUI),
+		"debug":      strconv.FormatBool(s.Debug),
+		"tts":        strconv.FormatBool(s.TTS),
+		"metrics":    strconv.FormatBool(s.Metrics),
+		"clipboard":  strconv.FormatBool(s.Clipboard),
+	}
+	if s.EventFrequency > 0 {
+		values["event-frequency"] = strconv.FormatFloat(s.EventFrequency, 'g', -1, 64)
+	}
+	if s.Scale > 0 {
+		values["scale"] = strconv.FormatFloat(s.Scale, 'g', -1, 64)
+	}
+	if s.Character != "" {
+		values["character"] = s.Character
+	}
+	if s.SaveDir != "" {
+		values["save-dir"] = s.SaveDir
+	}
+	if s.Profile != "" {
+		values["profile"] = s.Profile
+	}
+	if s.PushURL != "" {
+		values["push-url"] = s.PushURL
+	}
+	if len(s.ClipboardKinds) > 0 {
+		values["clipboard-kinds"] = strings.Join(s.ClipboardKinds, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if err := f.Value.Set(values[name]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid setting for -%s: %w", name, err))
+			continue
+		}
+		f.DefValue = f.Value.String()
+	}
+	return errors.Join(errs...)
+}
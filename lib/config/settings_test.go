@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSettingsSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", SettingsFileName)
+
+	if _, err := LoadSettings(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected a not-exist error for a first run, got %v", err)
+	}
+
+	saved := &Settings{Character: "/cards/tsundere/character.json", GameMode: true, ShowStats: true, OnboardingComplete: true, Scale: 1.5, TTS: true, ClipboardKinds: []string{"url"}}
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("Expected %+v, got %+v", saved, loaded)
+	}
+}
+
+func TestSettingsApplyToFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	character := fs.String("character", "default.json", "")
+	game := fs.Bool("game", false, "")
+	stats := fs.Bool("stats", false, "")
+
+	settings := &Settings{Character: "saved.json", GameMode: true, ShowStats: true}
+	if err := settings.ApplyToFlags(fs); err != nil {
+		t.Fatalf("ApplyToFlags failed: %v", err)
+	}
+
+	// Command line flags override the settings file
+	if err := fs.Parse([]string{"-stats=false"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if *character != "saved.json" || !*game {
+		t.Errorf("Expected settings to become flag values, got character=%q game=%v", *character, *game)
+	}
+	if *stats {
+		t.Error("Expected -stats=false on the command line to override the setting")
+	}
+}
+
+func TestSettingsApplyToFlagsTuning(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	frequency := fs.Float64("event-frequency", 0, "")
+	scale := fs.Float64("scale", 0, "")
+	tts := fs.Bool("tts", false, "")
+	clipboard := fs.Bool("clipboard", false, "")
+	clipboardKinds := fs.String("clipboard-kinds", "url,code", "")
+	pushURL := fs.String("push-url", "", "")
+
+	settings := &Settings{EventFrequency: 1.5, TTS: true, Clipboard: true, ClipboardKinds: []string{"url", "email"}, PushURL: "https://ntfy.sh/pet"}
+	if err := settings.ApplyToFlags(fs); err != nil {
+		t.Fatalf("ApplyToFlags failed: %v", err)
+	}
+
+	if *frequency != 1.5 || !*tts {
+		t.Errorf("Expected frequency 1.5 and tts on, got %v and %v", *frequency, *tts)
+	}
+	if *scale != 0 {
+		t.Errorf("Expected an unset scale to leave the flag default, got %v", *scale)
+	}
+	if !*clipboard || *clipboardKinds != "url,email" {
+		t.Errorf("Expected clipboard reactions for url,email, got %v %q", *clipboard, *clipboardKinds)
+	}
+	if *pushURL != "https://ntfy.sh/pet" {
+		t.Errorf("Expected the push URL, got %q", *pushURL)
+	}
+}
+
+func TestSettingsApplyToFlagsAsDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("character", "default.json", "")
+	fs.Bool("game", false, "")
+	scale := fs.Float64("scale", 1, "")
+	frequency := fs.Float64("event-frequency", 1, "")
+
+	// A broken flag value doesn't stop the other settings from applying
+	settings := &Settings{Character: "saved.json", GameMode: true, Scale: 2, EventFrequency: 0.5}
+	fs.Lookup("game").Value = badValue{}
+	err := settings.ApplyToFlags(fs)
+	if err == nil {
+		t.Error("Expected the bad -game value to be reported")
+	}
+	if *scale != 2 || *frequency != 0.5 || fs.Lookup("character").Value.String() != "saved.json" {
+		t.Errorf("Expected the remaining settings to apply, got scale=%v frequency=%v", *scale, *frequency)
+	}
+
+	if err := fs.Parse([]string{"-scale=3"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var passed []string
+	fs.Visit(func(f *flag.Flag) { passed = append(passed, f.Name) })
+	if !reflect.DeepEqual(passed, []string{"scale"}) {
+		t.Errorf("Expected only -scale to count as passed, got %v", passed)
+	}
+	if fs.NFlag() != 1 {
+		t.Errorf("Expected settings not to count toward NFlag, got %d", fs.NFlag())
+	}
+	if def := fs.Lookup("character").DefValue; def != "saved.json" {
+		t.Errorf("Expected the saved character as the default, got %q", def)
+	}
+}
+
+// badValue is a flag value that rejects everything
+type badValue struct{}
+
+func (badValue) String() string   { return "" }
+func (badValue) Set(string) error { return errors.New("rejected") }
@@ -0,0 +1,164 @@
+package dialog
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// BenchResult summarizes one backend's run over a benchmark corpus
+type BenchResult struct {
+	Backend  string `json:"backend"`
+	Attempts int    `json:"attempts"` // Contexts the backend said it can handle, times rounds
+	Skipped  int    `json:"skipped"`  // Contexts CanHandle refused, times rounds
+	Errors   int    `json:"errors"`   // Attempts that failed or returned no text
+
+	LatencyP50 time.Duration `json:"latencyP50"`
+	LatencyP90 time.Duration `json:"latencyP90"`
+	LatencyP99 time.Duration `json:"latencyP99"`
+	LatencyMax time.Duration `json:"latencyMax"`
+
+	Diversity      float64 `json:"diversity"`      // Distinct responses per response (0-1, higher is more varied)
+	RepetitionRate float64 `json:"repetitionRate"` // Responses identical to the previous one for the same trigger (0-1)
+
+	MeanConfidence   float64 `json:"meanConfidence"`   // What the backend claimed (0-1)
+	MeanQuality      float64 `json:"meanQuality"`      // Heuristic quality score of its responses (0-1)
+	CalibrationError float64 `json:"calibrationError"` // Mean gap between confidence and quality (0-1, lower is better)
+}
+
+// ErrorRate returns the share of attempts that failed
+func (r BenchResult) ErrorRate() float64 {
+	if r.Attempts == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Attempts)
+}
+
+// BenchmarkBackends runs every registered backend over the contexts, rounds
+// times, and reports latency, variety and how well each backend's confidence
+// matches the quality of what it says. Results are sorted by backend name.
+// Backends see the contexts in order, so stateful backends behave as they
+// would in a conversation.
+func BenchmarkBackends(manager *DialogManager, contexts []DialogContext, rounds int) []BenchResult {
+	if rounds < 1 {
+		rounds = 1
+	}
+	names := manager.GetRegisteredBackends()
+	sort.Strings(names)
+
+	results := make([]BenchResult, 0, len(names))
+	for _, name := range names {
+		backend, exists := manager.GetBackend(name)
+		if !exists {
+			continue
+		}
+		results = append(results, benchmarkBackend(name, backend, contexts, rounds))
+	}
+	return results
+}
+
+// benchmarkBackend runs one backend over the corpus
+func benchmarkBackend(name string, backend DialogBackend, contexts []DialogContext, rounds int) BenchResult {
+	result := BenchResult{Backend: name}
+	quality := NewQualityAssessment(NewConversationContext())
+
+	var latencies []time.Duration
+	var confidence, score, gap float64
+	seen := make(map[string]bool)
+	lastByTrigger := make(map[string]string)
+	responses, repeats := 0, 0
+
+	for round := 0; round < rounds; round++ {
+		for _, context := range contexts {
+			if !backend.CanHandle(context) {
+				result.Skipped++
+				continue
+			}
+			result.Attempts++
+
+			start := time.Now()
+			response, err := backend.GenerateResponse(context)
+			latencies = append(latencies, time.Since(start))
+			if err != nil || response.Text == "" {
+				result.Errors++
+				continue
+			}
+
+			responses++
+			seen[response.Text] = true
+			if lastByTrigger[context.Trigger] == response.Text {
+				repeats++
+			}
+			lastByTrigger[context.Trigger] = response.Text
+
+			metrics := quality.ScoreResponse(response, benchInput(context), context.PersonalityTraits)
+			confidence += response.Confidence
+			score += metrics.OverallQuality
+			gap += math.Abs(response.Confidence - metrics.OverallQuality)
+		}
+	}
+
+	result.LatencyP50 = latencyPercentile(latencies, 0.50)
+	result.LatencyP90 = latencyPercentile(latencies, 0.90)
+	result.LatencyP99 = latencyPercentile(latencies, 0.99)
+	result.LatencyMax = latencyPercentile(latencies, 1)
+	if responses > 0 {
+		n := float64(responses)
+		result.Diversity = float64(len(seen)) / n
+		result.RepetitionRate = float64(repeats) / n
+		result.MeanConfidence = confidence / n
+		result.MeanQuality = score / n
+		result.CalibrationError = gap / n
+	}
+	return result
+}
+
+// benchInput is what the user "said" for quality scoring: the chat message,
+// or the trigger for other interactions
+func benchInput(context DialogContext) string {
+	if context.Trigger == "chat" && context.LastResponse != "" {
+		return context.LastResponse
+	}
+	return context.Trigger
+}
+
+// latencyPercentile returns the nearest-rank percentile p (0-1) of the
+// latencies, 0 when there are none
+func latencyPercentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// RecommendChain orders the backends that answered most of their attempts
+// by quality, best first, as a suggested default and fallback chain.
+// Backends that handled nothing or failed half their attempts are left out.
+func RecommendChain(results []BenchResult) []string {
+	usable := make([]BenchResult, 0, len(results))
+	for _, r := range results {
+		if r.Attempts > 0 && r.ErrorRate() < 0.5 {
+			usable = append(usable, r)
+		}
+	}
+	sort.SliceStable(usable, func(i, j int) bool {
+		si := usable[i].MeanQuality * (1 - usable[i].ErrorRate())
+		sj := usable[j].MeanQuality * (1 - usable[j].ErrorRate())
+		if si != sj {
+			return si > sj
+		}
+		return usable[i].LatencyP90 < usable[j].LatencyP90
+	})
+
+	chain := make([]string, len(usable))
+	for i, r := range usable {
+		chain[i] = r.Backend
+	}
+	return chain
+}
@@ -0,0 +1,106 @@
+package dialog
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// stubBenchBackend cycles through fixed replies and can refuse triggers
+type stubBenchBackend struct {
+	replies    []string
+	confidence float64
+	refuse     string // Trigger CanHandle refuses
+	failEvery  int    // Fail every nth call when set
+	calls      int
+}
+
+func (b *stubBenchBackend) Initialize(json.RawMessage) error { return nil }
+
+func (b *stubBenchBackend) GenerateResponse(DialogContext) (DialogResponse, error) {
+	b.calls++
+	if b.failEvery > 0 && b.calls%b.failEvery == 0 {
+		return DialogResponse{}, errors.New("stub failure")
+	}
+	text := b.replies[(b.calls-1)%len(b.replies)]
+	return DialogResponse{Text: text, Confidence: b.confidence}, nil
+}
+
+func (b *stubBenchBackend) GetBackendInfo() BackendInfo { return BackendInfo{Name: "stub_bench"} }
+
+func (b *stubBenchBackend) CanHandle(context DialogContext) bool { return context.Trigger != b.refuse }
+
+func (b *stubBenchBackend) UpdateMemory(DialogContext, DialogResponse, *UserFeedback) error {
+	return nil
+}
+
+func TestBenchmarkBackends(t *testing.T) {
+	manager := NewDialogManager(false)
+	manager.RegisterBackend("varied", &stubBenchBackend{
+		replies:    []string{"Hello there, friend!", "What a lovely day to play together.", "I missed you so much!", "Tell me a story?"},
+		confidence: 0.8,
+	})
+	manager.RegisterBackend("parrot", &stubBenchBackend{
+		replies:    []string{"Hi."},
+		confidence: 1.0,
+		refuse:     "hover",
+	})
+	manager.RegisterBackend("flaky", &stubBenchBackend{
+		replies:   []string{"Sometimes."},
+		failEvery: 2,
+	})
+
+	contexts := []DialogContext{
+		{Trigger: "click"},
+		{Trigger: "hover"},
+		{Trigger: "chat", LastResponse: "How was your day?"},
+	}
+	results := BenchmarkBackends(manager, contexts, 4)
+
+	if len(results) != 3 || results[0].Backend != "flaky" || results[1].Backend != "parrot" || results[2].Backend != "varied" {
+		t.Fatalf("Expected results sorted by backend name, got %+v", results)
+	}
+	flaky, parrot, varied := results[0], results[1], results[2]
+
+	if parrot.Attempts != 8 || parrot.Skipped != 4 {
+		t.Errorf("Expected parrot to skip hover, got %d attempts and %d skipped", parrot.Attempts, parrot.Skipped)
+	}
+	if parrot.Diversity != 1.0/8 || parrot.RepetitionRate != 6.0/8 {
+		t.Errorf("Expected one distinct reply repeated after the first per trigger, got diversity %v repetition %v",
+			parrot.Diversity, parrot.RepetitionRate)
+	}
+	if varied.Diversity != 4.0/12 || varied.RepetitionRate != 0 {
+		t.Errorf("Expected varied replies never to repeat back to back, got diversity %v repetition %v",
+			varied.Diversity, varied.RepetitionRate)
+	}
+	if flaky.Errors != 6 || flaky.ErrorRate() != 0.5 {
+		t.Errorf("Expected half of flaky's attempts to fail, got %d errors", flaky.Errors)
+	}
+	if parrot.MeanConfidence != 1.0 || parrot.CalibrationError <= 0 {
+		t.Errorf("Expected parrot's confidence to overshoot its quality, got %+v", parrot)
+	}
+	if varied.LatencyMax < varied.LatencyP50 {
+		t.Errorf("Latency percentiles out of order: %+v", varied)
+	}
+
+	// flaky fails half the time, so it isn't recommended at all
+	chain := RecommendChain(results)
+	if len(chain) != 2 || chain[0] != "varied" {
+		t.Errorf("Expected varied to lead the recommended chain, got %v", chain)
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []time.Duration{5, 1, 4, 2, 3}
+	got := []time.Duration{
+		latencyPercentile(latencies, 0.5),
+		latencyPercentile(latencies, 0.9),
+		latencyPercentile(latencies, 1),
+		latencyPercentile(nil, 0.5),
+	}
+	if want := []time.Duration{3, 5, 5, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
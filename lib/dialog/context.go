@@ -7,6 +7,7 @@ package dialog
 import (
 	"context"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -16,6 +17,7 @@ type ConversationTopic struct {
 	Name       string    `json:"name"`       // Topic name (e.g., "weather", "feelings", "activities")
 	Confidence float64   `json:"confidence"` // Confidence score 0.0-1.0
 	LastSeen   time.Time `json:"last_seen"`  // When this topic was last detected
+	Mention    string    `json:"mention"`    // Keyword that last brought the topic up (e.g., "movie")
 }
 
 // EmotionalState tracks the emotional context of conversation
@@ -33,6 +35,12 @@ type ConversationContext struct {
 	EmotionalState EmotionalState      `json:"emotional_state"` // Current emotional context
 	RecentMessages []string            `json:"recent_messages"` // Last N messages for context
 	MaxHistory     int                 `json:"max_history"`     // Maximum messages to remember
+
+	// Topic settings, defaults when unset
+	extractor   TopicExtractor
+	decayWindow time.Duration
+	decay       float64
+	maxTopics   int
 }
 
 // NewConversationContext creates a new conversation context with default settings
@@ -45,6 +53,24 @@ func NewConversationContext() *ConversationContext {
 	}
 }
 
+// NewConversationContextWithTopics creates a conversation context whose
+// topic extraction and decay follow config; nil or zero fields keep defaults
+func NewConversationContextWithTopics(config *TopicConfig) (*ConversationContext, error) {
+	cc := NewConversationContext()
+	if config == nil {
+		return cc, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	cc.extractor, _ = NewTopicExtractor(config.Extractor)
+	cc.decayWindow = time.Duration(config.DecayWindow) * time.Second
+	cc.decay = config.Decay
+	cc.maxTopics = config.MaxTopics
+	return cc, nil
+}
+
 // AddMessage processes a new message and updates conversation context
 func (cc *ConversationContext) AddMessage(ctx context.Context, message string) error {
 	if message == "" {
@@ -66,9 +92,13 @@ func (cc *ConversationContext) AddMessage(ctx context.Context, message string) e
 	return nil
 }
 
-// GetActiveTopics returns topics seen recently (within last 5 minutes)
+// GetActiveTopics returns topics seen within the decay window (default: 5 minutes)
 func (cc *ConversationContext) GetActiveTopics() []ConversationTopic {
-	cutoff := time.Now().Add(-5 * time.Minute)
+	window := cc.decayWindow
+	if window <= 0 {
+		window = defaultTopicDecayWindow * time.Second
+	}
+	cutoff := time.Now().Add(-window)
 	active := make([]ConversationTopic, 0)
 
 	for _, topic := range cc.Topics {
@@ -104,30 +134,49 @@ func (cc *ConversationContext) GetContextSummary() string {
 
 // updateTopics analyzes message content and updates topic tracking
 func (cc *ConversationContext) updateTopics(message string) {
-	lower := strings.ToLower(message)
+	extractor := cc.extractor
+	if extractor == nil {
+		extractor = keywordExtractor{}
+	}
+
 	now := time.Now()
+	for _, match := range extractor.ExtractTopics(message) {
+		cc.updateTopic(match.Name, match.Mention, match.Confidence, now)
+	}
+	cc.trimTopics()
+}
 
-	// Simple keyword-based topic detection
-	topicKeywords := map[string][]string{
-		"weather":    {"weather", "rain", "sunny", "cloudy", "temperature", "hot", "cold"},
-		"feelings":   {"feel", "happy", "sad", "angry", "excited", "worried", "love", "hate"},
-		"activities": {"doing", "playing", "working", "studying", "watching", "reading"},
-		"food":       {"eat", "hungry", "food", "cook", "meal", "drink", "taste"},
-		"health":     {"sick", "tired", "energy", "sleep", "pain", "doctor", "medicine"},
+// AddResponseTopics records the topics a dialog backend reports with its
+// response. They are ignored unless the context takes its topics from the
+// backend.
+func (cc *ConversationContext) AddResponseTopics(topics []string) {
+	if _, ok := cc.extractor.(backendExtractor); !ok {
+		return
 	}
 
-	for topicName, keywords := range topicKeywords {
-		confidence := 0.0
-		for _, keyword := range keywords {
-			if containsWord(lower, keyword) {
-				confidence += 0.2 // Simple scoring
-			}
+	now := time.Now()
+	for _, topic := range topics {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			cc.updateTopic(topic, topic, keywordTopicConfidence, now)
 		}
+	}
+	cc.trimTopics()
+}
 
-		if confidence > 0 {
-			cc.updateTopic(topicName, confidence, now)
-		}
+// trimTopics forgets the least recently seen topics beyond the limit
+func (cc *ConversationContext) trimTopics() {
+	limit := cc.maxTopics
+	if limit <= 0 {
+		limit = defaultMaxTopics
+	}
+	if len(cc.Topics) <= limit {
+		return
 	}
+
+	sort.SliceStable(cc.Topics, func(i, j int) bool {
+		return cc.Topics[i].LastSeen.After(cc.Topics[j].LastSeen)
+	})
+	cc.Topics = cc.Topics[:limit]
 }
 
 // containsWord checks if a word exists as a complete word (not substring) in text
@@ -139,17 +188,21 @@ func containsWord(text, word string) bool {
 }
 
 // updateTopic updates or adds a topic with new confidence score
-func (cc *ConversationContext) updateTopic(name string, confidence float64, timestamp time.Time) {
+func (cc *ConversationContext) updateTopic(name, mention string, confidence float64, timestamp time.Time) {
 	// Find existing topic
 	for i, topic := range cc.Topics {
 		if topic.Name == name {
 			// Update existing topic with decayed confidence
-			decay := 0.8 // Previous confidence decay factor
+			decay := cc.decay
+			if decay <= 0 {
+				decay = defaultTopicDecay
+			}
 			cc.Topics[i].Confidence = decay*topic.Confidence + confidence
 			if cc.Topics[i].Confidence > 1.0 {
 				cc.Topics[i].Confidence = 1.0
 			}
 			cc.Topics[i].LastSeen = timestamp
+			cc.Topics[i].Mention = mention
 			return
 		}
 	}
@@ -159,6 +212,7 @@ func (cc *ConversationContext) updateTopic(name string, confidence float64, time
 		Name:       name,
 		Confidence: confidence,
 		LastSeen:   timestamp,
+		Mention:    mention,
 	})
 }
 
@@ -129,6 +129,11 @@ func TestTopicDetection(t *testing.T) {
 			message:       "I'm hungry, let's eat something",
 			expectedTopic: "food",
 		},
+		{
+			name:          "media topic",
+			message:       "We saw a great movie last night",
+			expectedTopic: "media",
+		},
 	}
 
 	for _, tt := range tests {
@@ -154,6 +159,22 @@ func TestTopicDetection(t *testing.T) {
 	}
 }
 
+func TestTopicMention(t *testing.T) {
+	ctx := NewConversationContext()
+	ctx.AddMessage(context.Background(), "Have you read that book?")
+	ctx.AddMessage(context.Background(), "The movie was better though")
+
+	for _, topic := range ctx.Topics {
+		if topic.Name == "media" {
+			if topic.Mention != "movie" {
+				t.Errorf("Expected the latest mention, got %q", topic.Mention)
+			}
+			return
+		}
+	}
+	t.Fatalf("Expected a media topic, got %+v", ctx.Topics)
+}
+
 func TestGetActiveTopics(t *testing.T) {
 	ctx := NewConversationContext()
 
@@ -366,3 +387,69 @@ func BenchmarkGetContextSummary(b *testing.B) {
 		ctx.GetContextSummary()
 	}
 }
+
+func TestConversationContextWithTopics(t *testing.T) {
+	if _, err := NewConversationContextWithTopics(&TopicConfig{Extractor: "lda"}); err == nil {
+		t.Error("Expected an unknown extractor to be rejected")
+	}
+
+	ctx, err := NewConversationContextWithTopics(&TopicConfig{DecayWindow: 60, MaxTopics: 2})
+	if err != nil {
+		t.Fatalf("NewConversationContextWithTopics failed: %v", err)
+	}
+	ctx.AddMessage(context.Background(), "I'm hungry")
+	ctx.AddMessage(context.Background(), "It's so sunny")
+	ctx.AddMessage(context.Background(), "Let's watch a movie")
+	if len(ctx.Topics) != 2 || ctx.Topics[0].Name != "media" || ctx.Topics[1].Name != "weather" {
+		t.Errorf("Expected the two latest topics to be kept, got %+v", ctx.Topics)
+	}
+
+	// Topics older than the window are no longer active
+	ctx.Topics[1].LastSeen = time.Now().Add(-2 * time.Minute)
+	if active := ctx.GetActiveTopics(); len(active) != 1 || active[0].Name != "media" {
+		t.Errorf("Expected only the recent topic to be active, got %+v", active)
+	}
+}
+
+func TestConversationContextBackendTopics(t *testing.T) {
+	ctx, err := NewConversationContextWithTopics(&TopicConfig{Extractor: TopicExtractorBackend})
+	if err != nil {
+		t.Fatalf("NewConversationContextWithTopics failed: %v", err)
+	}
+	ctx.AddMessage(context.Background(), "I'm so hungry")
+	if len(ctx.Topics) != 0 {
+		t.Errorf("Expected no topics from the message itself, got %+v", ctx.Topics)
+	}
+
+	ctx.AddResponseTopics([]string{"dinner plans", " "})
+	if len(ctx.Topics) != 1 || ctx.Topics[0].Name != "dinner plans" || ctx.Topics[0].Mention != "dinner plans" {
+		t.Errorf("Expected the backend's topic, got %+v", ctx.Topics)
+	}
+
+	// Contexts extracting their own topics ignore the backend's
+	keyword := NewConversationContext()
+	keyword.AddResponseTopics([]string{"dinner plans"})
+	if len(keyword.Topics) != 0 {
+		t.Errorf("Expected backend topics to be ignored, got %+v", keyword.Topics)
+	}
+}
+
+func TestRAKEExtractor(t *testing.T) {
+	matches := rakeExtractor{}.ExtractTopics("We finally watched the new space movie, and honestly the soundtrack was great!")
+	if len(matches) == 0 {
+		t.Fatal("Expected key phrases")
+	}
+	if matches[0].Name != "new space movie" || matches[0].Mention != "new space movie" {
+		t.Errorf("Expected the longest phrase to score best, got %+v", matches)
+	}
+	if matches[0].Confidence != 1 {
+		t.Errorf("Expected the best phrase at full confidence, got %f", matches[0].Confidence)
+	}
+	if len(matches) > rakeMaxPhrases {
+		t.Errorf("Expected at most %d phrases, got %d", rakeMaxPhrases, len(matches))
+	}
+
+	if matches := (rakeExtractor{}).ExtractTopics("it is what it is"); matches != nil {
+		t.Errorf("Expected no phrases from stop words, got %+v", matches)
+	}
+}
@@ -46,11 +46,13 @@ type DialogContext struct {
 	InteractionHistory []InteractionRecord `json:"interactionHistory,omitempty"` // Recent interactions
 	AchievementStatus  map[string]bool     `json:"achievementStatus,omitempty"`  // Unlocked achievements
 	TimeOfDay          string              `json:"timeOfDay,omitempty"`          // "morning", "afternoon", "evening", "night"
+	ProfileName        string              `json:"profileName,omitempty"`        // User profile the character is talking to, empty for the default profile
 
 	// Conversation context
 	LastResponse     string                 `json:"lastResponse,omitempty"` // Previous dialog response
 	ConversationTurn int                    `json:"conversationTurn"`       // Turn number in current conversation
 	TopicContext     map[string]interface{} `json:"topicContext,omitempty"` // Current conversation topics
+	ChosenReply      string                 `json:"chosenReply,omitempty"`  // Suggested reply the user picked, if the message is one
 
 	// Fallback configuration
 	FallbackResponses []string `json:"fallbackResponses"` // Default responses if backend fails
@@ -70,6 +72,10 @@ type DialogResponse struct {
 	EmotionalTone string                 `json:"emotionalTone,omitempty"` // "happy", "sad", "flirty", "shy", etc.
 	Topics        []string               `json:"topics,omitempty"`        // Topics covered in this response
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`      // Backend-specific metadata
+	Backend       string                 `json:"backend,omitempty"`       // Backend that produced the response, set by DialogManager
+
+	// Replies the user can pick instead of typing; the chat shows the first four
+	SuggestedReplies []string `json:"suggestedReplies,omitempty"`
 
 	// Memory and learning
 	MemoryImportance float64 `json:"memoryImportance,omitempty"` // How important is this for memory (0-1)
@@ -189,6 +195,7 @@ func (dm *DialogManager) tryDefaultBackend(context DialogContext) (DialogRespons
 		return DialogResponse{}, false
 	}
 
+	response.Backend = defaultBackend
 	return response, true
 }
 
@@ -226,6 +233,7 @@ func (dm *DialogManager) tryFallbackBackend(backendName string, context DialogCo
 		return DialogResponse{}, false
 	}
 
+	response.Backend = backendName
 	return response, true
 }
 
@@ -249,6 +257,7 @@ func (dm *DialogManager) createFallbackResponse(context DialogContext) DialogRes
 		Animation:    animation,
 		Confidence:   0.1, // Very low confidence for fallback
 		ResponseType: "fallback",
+		Backend:      "fallback",
 	}
 }
 
@@ -317,6 +326,9 @@ type DialogBackendConfig struct {
 	ConfidenceThreshold float64 `json:"confidenceThreshold"`       // Minimum confidence to accept response
 	ResponseTimeout     int     `json:"responseTimeout,omitempty"` // Max time to wait for response (ms)
 	DebugMode           bool    `json:"debugMode,omitempty"`       // Enable debug logging
+
+	// Conversation topic extraction and decay
+	Topics *TopicConfig `json:"topics,omitempty"`
 }
 
 // ValidateBackendConfig ensures the backend configuration is valid
@@ -337,6 +349,12 @@ func ValidateBackendConfig(config DialogBackendConfig) error {
 		return fmt.Errorf("responseTimeout must be non-negative, got %d", config.ResponseTimeout)
 	}
 
+	if config.Topics != nil {
+		if err := config.Topics.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
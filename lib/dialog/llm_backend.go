@@ -370,6 +370,11 @@ func (llm *LLMDialogBackend) buildPersonalityPrompt(context DialogContext) strin
 		prompt += fmt.Sprintf("\n\nRelationship level: %s", context.RelationshipLevel)
 	}
 
+	// Each profile is a different person with their own relationship
+	if context.ProfileName != "" {
+		prompt += fmt.Sprintf("\n\nYou are talking with %s.", context.ProfileName)
+	}
+
 	// Add situation context
 	if llm.config.UseSituation {
 		prompt += fmt.Sprintf("\n\nSituation: User %s the character", context.Trigger)
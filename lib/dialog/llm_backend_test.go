@@ -172,6 +172,7 @@ func TestLLMDialogBackend_PersonalityExtraction(t *testing.T) {
 		CurrentMood:       75.0,
 		RelationshipLevel: "friend",
 		TimeOfDay:         "evening",
+		ProfileName:       "kid",
 	}
 
 	prompt := backend.buildPersonalityPrompt(context)
@@ -192,6 +193,9 @@ func TestLLMDialogBackend_PersonalityExtraction(t *testing.T) {
 	if !strings.Contains(prompt, "during evening") {
 		t.Error("Prompt should include time of day information")
 	}
+	if !strings.Contains(prompt, "You are talking with kid.") {
+		t.Error("Prompt should include the user profile")
+	}
 }
 
 // TestLLMDialogBackend_GenerateResponse tests response generation with error scenarios
@@ -18,6 +18,11 @@ type MarkovChainBackend struct {
 	chains      map[string]*MarkovChain // Per-trigger chain storage
 	globalChain *MarkovChain            // Global chain for fallback
 	initialized bool
+	baseDir     string // Character directory, for resolving relative corpus paths
+
+	// Chains trained from the on-disk corpus only; these are what gets saved
+	corpusModel     *markovModel
+	lastCorpusStats CorpusStats
 
 	// Enhanced context tracking
 	conversationContext *ConversationContext // Track conversation topics and emotional state
@@ -38,6 +43,12 @@ type MarkovConfig struct {
 	UseDialogHistory bool     `json:"useDialogHistory"`        // Include character's dialog history
 	UsePersonality   bool     `json:"usePersonality"`          // Adjust responses based on personality
 
+	// On-disk corpus and model (see markov_training.go)
+	CorpusDir      string `json:"corpusDir,omitempty"`      // Directory of .txt files, one sample per line
+	ModelPath      string `json:"modelPath,omitempty"`      // Trained model file, defaults to <corpusDir>/model.json
+	MaxCorpusBytes int64  `json:"maxCorpusBytes,omitempty"` // Stop training past this many corpus bytes (default 5MB)
+	MaxStates      int    `json:"maxStates,omitempty"`      // Cap on chain states to bound memory (default 50000)
+
 	// Response filtering and enhancement
 	ForbiddenWords   []string `json:"forbiddenWords,omitempty"` // Words to avoid in responses
 	RequiredWords    []string `json:"requiredWords,omitempty"`  // Words that should appear more often
@@ -74,6 +85,7 @@ type MarkovConfig struct {
 // MarkovChain represents a single Markov chain for text generation
 type MarkovChain struct {
 	order       int
+	maxStates   int                 // 0 = unlimited
 	states      map[string][]string // state -> possible next words
 	starters    []string            // possible sentence starters
 	wordCounts  map[string]int      // word frequency tracking
@@ -109,22 +121,21 @@ func (m *MarkovChainBackend) Initialize(config json.RawMessage) error {
 	// Initialize conversation context tracking
 	m.conversationContext = NewConversationContext()
 
-	// Create global chain
-	m.globalChain = NewMarkovChain(m.config.ChainOrder)
-
-	// Create trigger-specific chains if enabled
-	if m.config.TriggerSpecific {
-		triggers := []string{"click", "rightclick", "hover", "compliment", "give_gift", "deep_conversation"}
-		for _, trigger := range triggers {
-			m.chains[trigger] = NewMarkovChain(m.config.ChainOrder)
-		}
-	}
+	// Create global chain and trigger-specific chains if enabled
+	m.globalChain, m.chains = m.newChainSet()
 
 	// Train chains with initial data
 	if err := m.trainWithInitialData(); err != nil {
 		return fmt.Errorf("failed to train initial chains: %w", err)
 	}
 
+	// Load the saved model and pick up any new corpus text
+	if m.config.CorpusDir != "" {
+		if _, err := m.TrainCorpus(); err != nil {
+			return fmt.Errorf("failed to train corpus: %w", err)
+		}
+	}
+
 	m.initialized = true
 	return nil
 }
@@ -147,6 +158,10 @@ func (m *MarkovChainBackend) validateConfig() error {
 		return fmt.Errorf("temperature values must be 0 <= min <= max <= 2")
 	}
 
+	if m.config.MaxCorpusBytes < 0 || m.config.MaxStates < 0 {
+		return fmt.Errorf("maxCorpusBytes and maxStates must not be negative")
+	}
+
 	return nil
 }
 
@@ -1195,6 +1210,12 @@ func (m *MarkovChainBackend) UpdateMemory(context DialogContext, response Dialog
 
 	// For positive feedback, reinforce the response patterns
 	if feedback.Positive && feedback.Engagement > 0.7 {
+		// Keep it in the corpus too so it survives restarts
+		if m.config.CorpusDir != "" {
+			if err := m.AddToCorpus(response.Text); err != nil {
+				logrus.WithError(err).Warn("Failed to add response to dialog corpus")
+			}
+		}
 		// Add the successful response to training data
 		return m.trainWithText(response.Text, context.Trigger)
 	}
@@ -1275,10 +1296,15 @@ func (c *MarkovChain) Train(text string) {
 		state := c.createState(words[i : i+c.order])
 		nextWord := words[i+c.order]
 
-		c.states[state.key] = append(c.states[state.key], nextWord)
+		// Past the caps, only reinforce what is already known
+		transitions, exists := c.states[state.key]
+		if (!exists && c.maxStates > 0 && len(c.states) >= c.maxStates) || len(transitions) >= maxTransitionsPerState {
+			continue
+		}
+		c.states[state.key] = append(transitions, nextWord)
 
 		// Record sentence starters
-		if i == 0 {
+		if i == 0 && (c.maxStates == 0 || len(c.starters) < c.maxStates) {
 			c.starters = append(c.starters, state.key)
 		}
 	}
@@ -0,0 +1,468 @@
+package dialog
+
+// markov_training.go lets the Markov backend learn from a per-character corpus
+// directory instead of only inline trainingData. Training is incremental: the
+// model remembers how many bytes of each corpus file it has seen and only reads
+// what was appended since. Only corpus-derived chains are saved, so inline
+// training data is never counted twice across restarts.
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxCorpusBytes  = 5 << 20 // 5MB of corpus text
+	defaultMaxStates       = 50000
+	maxTransitionsPerState = 200
+
+	markovModelVersion = 1
+	learnedCorpusFile  = "learned.txt" // Responses kept from positive feedback
+)
+
+// markovModel holds the chains trained from the corpus plus how far each
+// corpus file has been read
+type markovModel struct {
+	global *MarkovChain
+	chains map[string]*MarkovChain
+	corpus map[string]int64 // file name -> bytes trained
+}
+
+// markovModelFile is the JSON form of markovModel
+type markovModelFile struct {
+	Version int                      `json:"version"`
+	Order   int                      `json:"order"`
+	Global  chainSnapshot            `json:"global"`
+	Chains  map[string]chainSnapshot `json:"chains,omitempty"`
+	Corpus  map[string]int64         `json:"corpus"`
+}
+
+// chainSnapshot is the JSON form of a MarkovChain
+type chainSnapshot struct {
+	States     map[string][]string `json:"states"`
+	Starters   []string            `json:"starters"`
+	WordCounts map[string]int      `json:"wordCounts"`
+	TotalWords int                 `json:"totalWords"`
+}
+
+// CorpusStats summarizes a training run
+type CorpusStats struct {
+	Files      int   // Corpus files found
+	Bytes      int64 // Corpus bytes trained in total
+	NewBytes   int64 // Bytes trained in this run
+	Lines      int   // Lines trained in this run
+	States     int   // States in the corpus global chain
+	Rebuilt    bool  // Model was discarded and trained from scratch
+	CapReached bool  // maxCorpusBytes stopped training early
+}
+
+// SetBaseDir sets the directory relative corpusDir and modelPath are resolved
+// against, normally the character card's directory. Call before Initialize.
+func (m *MarkovChainBackend) SetBaseDir(dir string) {
+	m.baseDir = dir
+}
+
+// resolvePath makes a configured path absolute relative to baseDir
+func (m *MarkovChainBackend) resolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || m.baseDir == "" {
+		return path
+	}
+	return filepath.Join(m.baseDir, path)
+}
+
+// corpusDir returns the resolved corpus directory
+func (m *MarkovChainBackend) corpusDir() string {
+	return m.resolvePath(m.config.CorpusDir)
+}
+
+// modelPath returns the resolved model file path
+func (m *MarkovChainBackend) modelPath() string {
+	if m.config.ModelPath != "" {
+		return m.resolvePath(m.config.ModelPath)
+	}
+	return filepath.Join(m.corpusDir(), "model.json")
+}
+
+// maxCorpusBytes returns the configured corpus cap or the default
+func (m *MarkovChainBackend) maxCorpusBytes() int64 {
+	if m.config.MaxCorpusBytes > 0 {
+		return m.config.MaxCorpusBytes
+	}
+	return defaultMaxCorpusBytes
+}
+
+// newChainSet creates a global chain plus trigger chains when triggerSpecific is on
+func (m *MarkovChainBackend) newChainSet() (*MarkovChain, map[string]*MarkovChain) {
+	maxStates := m.config.MaxStates
+	if maxStates == 0 {
+		maxStates = defaultMaxStates
+	}
+
+	global := NewMarkovChain(m.config.ChainOrder)
+	global.maxStates = maxStates
+
+	chains := make(map[string]*MarkovChain)
+	if m.config.TriggerSpecific {
+		triggers := []string{"click", "rightclick", "hover", "compliment", "give_gift", "deep_conversation"}
+		for _, trigger := range triggers {
+			chain := NewMarkovChain(m.config.ChainOrder)
+			chain.maxStates = maxStates
+			chains[trigger] = chain
+		}
+	}
+	return global, chains
+}
+
+// TrainCorpus loads the saved model on first use, trains on corpus text
+// added since the last run and saves the model again. Corpus files named
+// after a trigger (e.g. click.txt) also train that trigger's chain.
+func (m *MarkovChainBackend) TrainCorpus() (CorpusStats, error) {
+	var stats CorpusStats
+	if m.config.CorpusDir == "" {
+		return stats, fmt.Errorf("no corpusDir configured")
+	}
+
+	if m.corpusModel == nil {
+		model, err := m.loadModel()
+		if err != nil {
+			logrus.WithError(err).WithField("path", m.modelPath()).Warn("Discarding unreadable dialog model, retraining from corpus")
+			stats.Rebuilt = true
+		}
+		if model == nil {
+			model = m.newModel()
+		}
+		m.corpusModel = model
+		m.mergeModel(model)
+	}
+
+	files, err := m.corpusFiles()
+	if err != nil {
+		return stats, err
+	}
+	stats.Files = len(files)
+
+	// A file that shrank was edited rather than appended to, so start over
+	for _, name := range files {
+		info, err := os.Stat(filepath.Join(m.corpusDir(), name))
+		if err == nil && info.Size() < m.corpusModel.corpus[name] {
+			m.rebuildCorpus()
+			stats.Rebuilt = true
+			break
+		}
+	}
+
+	for _, name := range files {
+		total := m.trainedCorpusBytes()
+		if total >= m.maxCorpusBytes() {
+			break
+		}
+
+		trained, lines, err := m.trainCorpusFile(name, m.maxCorpusBytes()-total)
+		if err != nil {
+			return stats, err
+		}
+		stats.NewBytes += trained
+		stats.Lines += lines
+	}
+	if size, err := corpusSize(m.corpusDir()); err == nil && size > m.trainedCorpusBytes() {
+		stats.CapReached = true
+	}
+
+	stats.Bytes = m.trainedCorpusBytes()
+	stats.States = len(m.corpusModel.global.states)
+	m.lastCorpusStats = stats
+
+	if stats.NewBytes > 0 || stats.Rebuilt {
+		if err := m.saveModel(); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// LastCorpusStats returns the result of the most recent TrainCorpus run
+func (m *MarkovChainBackend) LastCorpusStats() CorpusStats {
+	return m.lastCorpusStats
+}
+
+// AddToCorpus appends a line to the learned corpus file. It is trained into
+// the saved model on the next TrainCorpus run. Skipped once the corpus is full.
+func (m *MarkovChainBackend) AddToCorpus(text string) error {
+	line := strings.TrimSpace(strings.ReplaceAll(text, "\n", " "))
+	if line == "" {
+		return nil
+	}
+
+	dir := m.corpusDir()
+	size, err := corpusSize(dir)
+	if err != nil {
+		return err
+	}
+	if size+int64(len(line))+1 > m.maxCorpusBytes() {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create corpus dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, learnedCorpusFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open corpus file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("write corpus file: %w", err)
+	}
+	return nil
+}
+
+// corpusFiles lists the .txt files in the corpus directory, sorted
+func (m *MarkovChainBackend) corpusFiles() ([]string, error) {
+	entries, err := os.ReadDir(m.corpusDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read corpus dir: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".txt") {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// corpusSize sums the size of the .txt files in dir
+func corpusSize(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read corpus dir: %w", err)
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".txt") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			total += info.Size()
+		}
+	}
+	return total, nil
+}
+
+// trainCorpusFile trains on the part of a corpus file not seen yet, reading
+// at most limit bytes. Returns bytes and lines trained.
+func (m *MarkovChainBackend) trainCorpusFile(name string, limit int64) (int64, int, error) {
+	f, err := os.Open(filepath.Join(m.corpusDir(), name))
+	if err != nil {
+		return 0, 0, fmt.Errorf("open corpus file: %w", err)
+	}
+	defer f.Close()
+
+	offset := m.corpusModel.corpus[name]
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, fmt.Errorf("seek corpus file: %w", err)
+	}
+
+	trigger := strings.TrimSuffix(name, filepath.Ext(name))
+	reader := bufio.NewReader(io.LimitReader(f, limit))
+
+	var trained int64
+	lines := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			// Leave a line cut off by the cap for the next run
+			if !strings.HasSuffix(line, "\n") && trained+int64(len(line)) >= limit {
+				break
+			}
+			trained += int64(len(line))
+			if text := m.cleanTrainingText(line); len(text) >= 3 {
+				m.trainCorpusText(text, trigger)
+				lines++
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return trained, lines, fmt.Errorf("read corpus file: %w", err)
+		}
+	}
+
+	m.corpusModel.corpus[name] = offset + trained
+	return trained, lines, nil
+}
+
+// trainCorpusText trains both the live chains and the saved corpus chains
+func (m *MarkovChainBackend) trainCorpusText(text, trigger string) {
+	_ = m.trainWithText(text, trigger)
+
+	m.corpusModel.global.Train(text)
+	if chain, exists := m.corpusModel.chains[trigger]; exists {
+		chain.Train(text)
+	}
+}
+
+// trainedCorpusBytes sums how much of the corpus has been trained
+func (m *MarkovChainBackend) trainedCorpusBytes() int64 {
+	var total int64
+	for _, size := range m.corpusModel.corpus {
+		total += size
+	}
+	return total
+}
+
+// rebuildCorpus drops everything learned from the corpus. Live chains are
+// retrained from inline data, since corpus text can't be subtracted from them.
+func (m *MarkovChainBackend) rebuildCorpus() {
+	m.globalChain, m.chains = m.newChainSet()
+	_ = m.trainWithInitialData()
+	m.corpusModel = m.newModel()
+}
+
+// newModel creates an empty corpus model
+func (m *MarkovChainBackend) newModel() *markovModel {
+	global, chains := m.newChainSet()
+	return &markovModel{global: global, chains: chains, corpus: make(map[string]int64)}
+}
+
+// mergeModel adds the corpus chains to the live chains
+func (m *MarkovChainBackend) mergeModel(model *markovModel) {
+	m.globalChain.merge(model.global)
+	for trigger, chain := range model.chains {
+		if live, exists := m.chains[trigger]; exists {
+			live.merge(chain)
+		}
+	}
+}
+
+// loadModel reads the saved model. Returns nil without error when there is
+// no model yet or it was trained with a different chain order.
+func (m *MarkovChainBackend) loadModel() (*markovModel, error) {
+	data, err := os.ReadFile(m.modelPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read model: %w", err)
+	}
+
+	var file markovModelFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse model: %w", err)
+	}
+	if file.Version != markovModelVersion || file.Order != m.config.ChainOrder {
+		return nil, nil
+	}
+
+	model := m.newModel()
+	model.global.restore(file.Global)
+	for trigger, snapshot := range file.Chains {
+		if chain, exists := model.chains[trigger]; exists {
+			chain.restore(snapshot)
+		}
+	}
+	for name, size := range file.Corpus {
+		model.corpus[name] = size
+	}
+	return model, nil
+}
+
+// saveModel writes the corpus model to disk atomically
+func (m *MarkovChainBackend) saveModel() error {
+	file := markovModelFile{
+		Version: markovModelVersion,
+		Order:   m.config.ChainOrder,
+		Global:  m.corpusModel.global.snapshot(),
+		Chains:  make(map[string]chainSnapshot),
+		Corpus:  m.corpusModel.corpus,
+	}
+	for trigger, chain := range m.corpusModel.chains {
+		file.Chains[trigger] = chain.snapshot()
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("encode model: %w", err)
+	}
+
+	path := m.modelPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create model dir: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("write model: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace model: %w", err)
+	}
+	return nil
+}
+
+// snapshot copies the chain into its JSON form
+func (c *MarkovChain) snapshot() chainSnapshot {
+	return chainSnapshot{
+		States:     c.states,
+		Starters:   c.starters,
+		WordCounts: c.wordCounts,
+		TotalWords: c.totalWords,
+	}
+}
+
+// restore replaces the chain contents with a snapshot
+func (c *MarkovChain) restore(s chainSnapshot) {
+	c.states = make(map[string][]string, len(s.States))
+	c.wordCounts = make(map[string]int, len(s.WordCounts))
+	c.starters = nil
+	c.totalWords = 0
+	c.merge(&MarkovChain{states: s.States, starters: s.Starters, wordCounts: s.WordCounts, totalWords: s.TotalWords})
+}
+
+// merge adds another chain's transitions and counts, respecting the caps
+func (c *MarkovChain) merge(other *MarkovChain) {
+	for key, next := range other.states {
+		transitions, exists := c.states[key]
+		if !exists && c.maxStates > 0 && len(c.states) >= c.maxStates {
+			continue
+		}
+		room := maxTransitionsPerState - len(transitions)
+		if room <= 0 {
+			continue
+		}
+		if len(next) > room {
+			next = next[:room]
+		}
+		c.states[key] = append(transitions, next...)
+	}
+
+	for _, starter := range other.starters {
+		if c.maxStates > 0 && len(c.starters) >= c.maxStates {
+			break
+		}
+		c.starters = append(c.starters, starter)
+	}
+
+	for word, count := range other.wordCounts {
+		c.wordCounts[word] += count
+	}
+	c.totalWords += other.totalWords
+}
@@ -0,0 +1,149 @@
+package dialog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newCorpusBackend initializes a backend with corpusDir "corpus" under dir
+func newCorpusBackend(t *testing.T, dir string, maxCorpusBytes int64) *MarkovChainBackend {
+	t.Helper()
+
+	config := createTestMarkovConfig()
+	config.CorpusDir = "corpus"
+	config.MaxCorpusBytes = maxCorpusBytes
+	configJSON, _ := json.Marshal(config)
+
+	backend := NewMarkovChainBackend()
+	backend.SetBaseDir(dir)
+	if err := backend.Initialize(configJSON); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	return backend
+}
+
+func writeCorpus(t *testing.T, dir, name, text string, appendText bool) {
+	t.Helper()
+
+	corpusDir := filepath.Join(dir, "corpus")
+	if err := os.MkdirAll(corpusDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendText {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(filepath.Join(corpusDir, name), flags, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMarkovCorpusIncrementalTraining(t *testing.T) {
+	dir := t.TempDir()
+	writeCorpus(t, dir, "general.txt", "the purple dragon sleeps under the mountain\n", false)
+
+	backend := newCorpusBackend(t, dir, 0)
+	stats := backend.LastCorpusStats()
+	if stats.Files != 1 || stats.Lines != 1 || stats.NewBytes == 0 {
+		t.Fatalf("Expected one corpus line trained, got %+v", stats)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "corpus", "model.json")); err != nil {
+		t.Fatalf("Model should be saved next to the corpus: %v", err)
+	}
+	if len(backend.globalChain.states["purple dragon"]) == 0 {
+		t.Error("Live chain should include corpus text")
+	}
+
+	// A restart loads the model and trains nothing new
+	backend = newCorpusBackend(t, dir, 0)
+	if stats := backend.LastCorpusStats(); stats.NewBytes != 0 || stats.Lines != 0 {
+		t.Errorf("Unchanged corpus should not be retrained, got %+v", stats)
+	}
+	if got := len(backend.globalChain.states["purple dragon"]); got != 1 {
+		t.Errorf("Saved model should be merged once, got %d transitions", got)
+	}
+
+	// Appended text is picked up on the next run
+	writeCorpus(t, dir, "general.txt", "a purple dragon hoards shiny coins\n", true)
+	backend = newCorpusBackend(t, dir, 0)
+	if stats := backend.LastCorpusStats(); stats.Lines != 1 || stats.Rebuilt {
+		t.Errorf("Expected only the appended line to train, got %+v", stats)
+	}
+	if got := len(backend.globalChain.states["purple dragon"]); got != 2 {
+		t.Errorf("Expected both corpus lines in the chain, got %d transitions", got)
+	}
+
+	// Rewriting a file with less text forces a rebuild
+	writeCorpus(t, dir, "general.txt", "green frogs sing at night\n", false)
+	backend = newCorpusBackend(t, dir, 0)
+	if stats := backend.LastCorpusStats(); !stats.Rebuilt {
+		t.Errorf("Shrunk corpus file should trigger a rebuild, got %+v", stats)
+	}
+	if len(backend.globalChain.states["purple dragon"]) != 0 {
+		t.Error("Rebuild should forget removed corpus text")
+	}
+}
+
+func TestMarkovCorpusCap(t *testing.T) {
+	dir := t.TempDir()
+	writeCorpus(t, dir, "general.txt", "one two three four five\nsix seven eight nine ten\n", false)
+
+	backend := newCorpusBackend(t, dir, 30)
+	stats := backend.LastCorpusStats()
+	if !stats.CapReached || stats.Lines != 1 || stats.Bytes > 30 {
+		t.Errorf("Expected training to stop at the cap after one line, got %+v", stats)
+	}
+
+	// Full corpus: learned responses are no longer appended
+	if err := backend.AddToCorpus("this response was liked a lot"); err != nil {
+		t.Fatalf("AddToCorpus failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "corpus", learnedCorpusFile)); !os.IsNotExist(err) {
+		t.Error("AddToCorpus should skip writing past maxCorpusBytes")
+	}
+}
+
+func TestMarkovAddToCorpus(t *testing.T) {
+	dir := t.TempDir()
+	backend := newCorpusBackend(t, dir, 0)
+
+	if err := backend.AddToCorpus("You always\nmake me smile"); err != nil {
+		t.Fatalf("AddToCorpus failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "corpus", learnedCorpusFile))
+	if err != nil {
+		t.Fatalf("Learned corpus should be written: %v", err)
+	}
+	if string(data) != "You always make me smile\n" {
+		t.Errorf("Unexpected corpus contents %q", data)
+	}
+
+	stats, err := backend.TrainCorpus()
+	if err != nil || stats.Lines != 1 {
+		t.Errorf("Learned line should train on the next run, got %+v (%v)", stats, err)
+	}
+}
+
+func TestMarkovChainStateCap(t *testing.T) {
+	chain := NewMarkovChain(1)
+	chain.maxStates = 3
+	chain.Train("a b c d e f g")
+
+	if len(chain.states) != 3 {
+		t.Errorf("Expected states capped at 3, got %d", len(chain.states))
+	}
+
+	// Known states keep learning past the cap
+	chain.Train("a x")
+	if got := strings.Join(chain.states["a"], " "); got != "b x" {
+		t.Errorf("Expected existing state to learn new transition, got %q", got)
+	}
+}
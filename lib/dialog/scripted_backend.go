@@ -0,0 +1,374 @@
+package dialog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxScriptJumps bounds how many jumps and branches one reply may follow,
+// so a script that loops back on itself fails instead of hanging
+const maxScriptJumps = 32
+
+// ScriptedBackend plays author-written conversation trees, visual novel
+// style. Each reply is a node; the user moves through the tree by picking
+// one of the node's choices in the chat. No AI is involved, so the same
+// script always tells the same story.
+type ScriptedBackend struct {
+	mu      sync.Mutex
+	script  ConversationScript
+	baseDir string // Character directory, for resolving a relative scriptFile
+
+	current string          // Node waiting for the user's answer, "" between conversations
+	offered []ScriptChoice  // Choices shown with the current node
+	flags   map[string]bool // Script memory, set by nodes and choices
+}
+
+// ScriptedConfig defines JSON configuration for the scripted backend. The
+// script is given inline or loaded from a file next to the character card.
+type ScriptedConfig struct {
+	ScriptFile string              `json:"scriptFile,omitempty"` // Script JSON, relative to the character card
+	Script     *ConversationScript `json:"script,omitempty"`     // Inline script, used when scriptFile is empty
+}
+
+// ConversationScript is a tree of nodes and the triggers that start it
+type ConversationScript struct {
+	Entries   map[string]string      `json:"entries"`             // Trigger ("chat", "click", ...) to starting node
+	Nodes     map[string]*ScriptNode `json:"nodes"`               // Node ID to node
+	Unmatched string                 `json:"unmatched,omitempty"` // Reply when a message matches no choice; the choices are offered again
+}
+
+// ScriptNode is one line of the conversation. Branches are checked first
+// and jump to the first node whose conditions hold. A node without text
+// jumps straight to Next; one with text but no choices continues at Next
+// on the user's next message, and ends the conversation without it.
+type ScriptNode struct {
+	Text      string          `json:"text,omitempty"`
+	Animation string          `json:"animation,omitempty"`
+	Choices   []ScriptChoice  `json:"choices,omitempty"`
+	Branches  []ScriptBranch  `json:"branches,omitempty"`
+	Next      string          `json:"next,omitempty"`
+	SetFlags  map[string]bool `json:"setFlags,omitempty"` // Remembered when the node is reached
+}
+
+// ScriptChoice is an answer the user can pick. Choices whose conditions
+// don't hold are not offered.
+type ScriptChoice struct {
+	Text       string           `json:"text"`
+	Next       string           `json:"next"`
+	Conditions *ScriptCondition `json:"conditions,omitempty"`
+	SetFlags   map[string]bool  `json:"setFlags,omitempty"` // Remembered when the choice is picked
+}
+
+// ScriptBranch jumps to Next when its conditions hold
+type ScriptBranch struct {
+	Conditions ScriptCondition `json:"conditions"`
+	Next       string          `json:"next"`
+}
+
+// ScriptCondition holds when every listed requirement does
+type ScriptCondition struct {
+	Stats        map[string]StatRange `json:"stats,omitempty"`        // Stat name to allowed range
+	Flags        map[string]bool      `json:"flags,omitempty"`        // Script memory values; unset flags are false
+	Relationship []string             `json:"relationship,omitempty"` // Allowed relationship levels
+}
+
+// StatRange bounds a stat; a zero Max means no upper bound
+type StatRange struct {
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+}
+
+// NewScriptedBackend creates a scripted backend with an empty script
+func NewScriptedBackend() *ScriptedBackend {
+	return &ScriptedBackend{flags: make(map[string]bool)}
+}
+
+// SetBaseDir sets the directory a relative scriptFile is resolved against
+func (s *ScriptedBackend) SetBaseDir(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.baseDir = dir
+}
+
+// Initialize loads and validates the conversation script
+func (s *ScriptedBackend) Initialize(config json.RawMessage) error {
+	var cfg ScriptedConfig
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("failed to parse scripted config: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var script ConversationScript
+	switch {
+	case cfg.ScriptFile != "":
+		path := cfg.ScriptFile
+		if !filepath.IsAbs(path) && s.baseDir != "" {
+			path = filepath.Join(s.baseDir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read conversation script: %w", err)
+		}
+		if err := json.Unmarshal(data, &script); err != nil {
+			return fmt.Errorf("failed to parse conversation script %s: %w", path, err)
+		}
+	case cfg.Script != nil:
+		script = *cfg.Script
+	default:
+		return fmt.Errorf("scripted backend needs a script or scriptFile")
+	}
+
+	if err := validateScript(script); err != nil {
+		return fmt.Errorf("invalid conversation script: %w", err)
+	}
+
+	s.script = script
+	s.current = ""
+	s.offered = nil
+
+	logrus.WithFields(logrus.Fields{
+		"caller":  getCaller(),
+		"nodes":   len(script.Nodes),
+		"entries": len(script.Entries),
+	}).Info("Scripted dialog backend initialized")
+	return nil
+}
+
+// validateScript checks that the script has an entry and every jump lands on a node
+func validateScript(script ConversationScript) error {
+	if len(script.Entries) == 0 {
+		return fmt.Errorf("no entries")
+	}
+
+	exists := func(id string) bool {
+		_, ok := script.Nodes[id]
+		return ok
+	}
+	for trigger, id := range script.Entries {
+		if !exists(id) {
+			return fmt.Errorf("entry %q starts at unknown node %q", trigger, id)
+		}
+	}
+
+	for id, node := range script.Nodes {
+		if node == nil {
+			return fmt.Errorf("node %q is empty", id)
+		}
+		if node.Next != "" && !exists(node.Next) {
+			return fmt.Errorf("node %q continues at unknown node %q", id, node.Next)
+		}
+		if node.Text == "" && node.Next == "" && len(node.Branches) == 0 {
+			return fmt.Errorf("node %q has no text and leads nowhere", id)
+		}
+		for _, branch := range node.Branches {
+			if !exists(branch.Next) {
+				return fmt.Errorf("node %q branches to unknown node %q", id, branch.Next)
+			}
+		}
+		for _, choice := range node.Choices {
+			if choice.Text == "" {
+				return fmt.Errorf("node %q has a choice without text", id)
+			}
+			if !exists(choice.Next) {
+				return fmt.Errorf("choice %q in node %q leads to unknown node %q", choice.Text, id, choice.Next)
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateResponse advances the conversation. Chat messages answer the
+// current node; other triggers, and chat outside a conversation, start the
+// script at the trigger's entry.
+func (s *ScriptedBackend) GenerateResponse(context DialogContext) (DialogResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if context.Trigger == "chat" && s.current != "" {
+		return s.answer(context)
+	}
+
+	entry, ok := s.script.Entries[context.Trigger]
+	if !ok {
+		return DialogResponse{}, fmt.Errorf("no script entry for trigger %q", context.Trigger)
+	}
+	return s.show(entry, context)
+}
+
+// answer moves on from the current node using the user's message; caller must hold s.mu
+func (s *ScriptedBackend) answer(context DialogContext) (DialogResponse, error) {
+	node := s.script.Nodes[s.current]
+
+	if len(s.offered) == 0 {
+		return s.show(node.Next, context)
+	}
+
+	message := context.LastResponse
+	if context.ChosenReply != "" {
+		message = context.ChosenReply
+	}
+	if choice, ok := matchChoice(s.offered, message); ok {
+		for flag, value := range choice.SetFlags {
+			s.flags[flag] = value
+		}
+		return s.show(choice.Next, context)
+	}
+
+	text := s.script.Unmatched
+	if text == "" {
+		text = node.Text
+	}
+	return s.response(s.current, text, node.Animation, s.offered), nil
+}
+
+// matchChoice finds the choice a message picks, by its text or its number
+func matchChoice(choices []ScriptChoice, message string) (ScriptChoice, bool) {
+	message = strings.ToLower(strings.TrimSpace(message))
+	if n, err := strconv.Atoi(message); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1], true
+	}
+	for _, choice := range choices {
+		if strings.ToLower(choice.Text) == message {
+			return choice, true
+		}
+	}
+	return ScriptChoice{}, false
+}
+
+// show follows jumps and branches from id and replies with the node it
+// lands on; caller must hold s.mu
+func (s *ScriptedBackend) show(id string, context DialogContext) (DialogResponse, error) {
+	for jumps := 0; ; jumps++ {
+		if jumps > maxScriptJumps {
+			s.current = ""
+			return DialogResponse{}, fmt.Errorf("conversation script loops at node %q", id)
+		}
+
+		node := s.script.Nodes[id]
+		for flag, value := range node.SetFlags {
+			s.flags[flag] = value
+		}
+
+		if next, ok := s.branch(node, context); ok {
+			id = next
+			continue
+		}
+		if node.Text == "" {
+			id = node.Next
+			continue
+		}
+
+		var offered []ScriptChoice
+		for _, choice := range node.Choices {
+			if choice.Conditions == nil || s.holds(*choice.Conditions, context) {
+				offered = append(offered, choice)
+			}
+		}
+
+		s.current, s.offered = id, offered
+		if len(offered) == 0 && node.Next == "" {
+			s.current = "" // The conversation ends here
+		}
+		return s.response(id, node.Text, node.Animation, offered), nil
+	}
+}
+
+// branch returns the first branch target whose conditions hold
+func (s *ScriptedBackend) branch(node *ScriptNode, context DialogContext) (string, bool) {
+	for _, branch := range node.Branches {
+		if s.holds(branch.Conditions, context) {
+			return branch.Next, true
+		}
+	}
+	return "", false
+}
+
+// holds reports whether a condition is met by the character's state and script memory
+func (s *ScriptedBackend) holds(condition ScriptCondition, context DialogContext) bool {
+	for stat, bounds := range condition.Stats {
+		value, ok := context.CurrentStats[stat]
+		if !ok || value < bounds.Min || (bounds.Max != 0 && value > bounds.Max) {
+			return false
+		}
+	}
+	for flag, want := range condition.Flags {
+		if s.flags[flag] != want {
+			return false
+		}
+	}
+	if len(condition.Relationship) > 0 {
+		matched := false
+		for _, level := range condition.Relationship {
+			if strings.EqualFold(level, context.RelationshipLevel) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// response builds a reply offering the choices as suggested replies
+func (s *ScriptedBackend) response(id, text, animation string, offered []ScriptChoice) DialogResponse {
+	var replies []string
+	for _, choice := range offered {
+		replies = append(replies, choice.Text)
+	}
+
+	return DialogResponse{
+		Text:             text,
+		Animation:        animation,
+		Confidence:       0.9, // Authored lines are exactly what the writer intended
+		ResponseType:     "scripted",
+		Metadata:         map[string]interface{}{"node": id},
+		SuggestedReplies: replies,
+	}
+}
+
+// GetBackendInfo returns metadata about the scripted backend
+func (s *ScriptedBackend) GetBackendInfo() BackendInfo {
+	return BackendInfo{
+		Name:        "scripted",
+		Version:     "1.0.0",
+		Description: "Author-written branching conversations with choices, conditions and jumps",
+		Capabilities: []string{
+			"branching_conversations",
+			"user_choices",
+			"stat_conditions",
+			"script_memory",
+		},
+		Author:  "DDS Development Team",
+		License: "MIT",
+	}
+}
+
+// CanHandle reports whether a conversation is under way or the trigger starts one
+func (s *ScriptedBackend) CanHandle(context DialogContext) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if context.Trigger == "chat" && s.current != "" {
+		return true
+	}
+	_, ok := s.script.Entries[context.Trigger]
+	return ok
+}
+
+// UpdateMemory does nothing; the script's memory is driven by its own flags
+func (s *ScriptedBackend) UpdateMemory(context DialogContext, response DialogResponse, feedback *UserFeedback) error {
+	return nil
+}
@@ -0,0 +1,173 @@
+package dialog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testScript is a short conversation: a greeting with two answers, a
+// choice that is only offered to happy characters, and a node that
+// remembers the user shared a secret
+const testScript = `{
+	"entries": {"chat": "start", "click": "wave"},
+	"unmatched": "Pick one of the answers, please.",
+	"nodes": {
+		"start": {
+			"branches": [{"conditions": {"flags": {"secret": true}}, "next": "again"}],
+			"next": "hello"
+		},
+		"hello": {
+			"text": "Hi! How are you?",
+			"animation": "talking",
+			"choices": [
+				{"text": "Great!", "next": "great"},
+				{"text": "Tired.", "next": "tired"},
+				{"text": "Let's dance!", "next": "dance", "conditions": {"stats": {"happiness": {"min": 80}}}}
+			]
+		},
+		"great": {"text": "Want to hear a secret?", "choices": [
+			{"text": "Yes", "next": "secret", "setFlags": {"secret": true}},
+			{"text": "No", "next": "bye"}
+		]},
+		"tired": {"text": "Get some rest.", "next": "bye"},
+		"dance": {"text": "*dances*"},
+		"secret": {"text": "I like you."},
+		"bye": {"text": "See you!"},
+		"again": {"text": "Remember our secret?"},
+		"wave": {"text": "*waves*"}
+	}
+}`
+
+func newScriptedBackend(t *testing.T) *ScriptedBackend {
+	t.Helper()
+	backend := NewScriptedBackend()
+	if err := backend.Initialize([]byte(`{"script": ` + testScript + `}`)); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	return backend
+}
+
+// say sends a chat message and returns the reply and offered choices
+func say(t *testing.T, backend *ScriptedBackend, message string, stats map[string]float64) (string, []string) {
+	t.Helper()
+	context := DialogContext{Trigger: "chat", LastResponse: message, CurrentStats: stats}
+	if !backend.CanHandle(context) {
+		t.Fatalf("Expected backend to handle %q", message)
+	}
+	response, err := backend.GenerateResponse(context)
+	if err != nil {
+		t.Fatalf("GenerateResponse(%q) failed: %v", message, err)
+	}
+	return response.Text, response.SuggestedReplies
+}
+
+func TestScriptedBackendConversation(t *testing.T) {
+	backend := newScriptedBackend(t)
+
+	text, choices := say(t, backend, "hey", nil)
+	if text != "Hi! How are you?" || !reflect.DeepEqual(choices, []string{"Great!", "Tired."}) {
+		t.Fatalf("Expected the greeting with two answers, got %q %v", text, choices)
+	}
+
+	text, choices = say(t, backend, "what?", nil)
+	if text != "Pick one of the answers, please." || len(choices) != 2 {
+		t.Errorf("Expected unmatched messages to offer the choices again, got %q %v", text, choices)
+	}
+
+	// Choices can be picked by number; a node without choices continues at next
+	if text, _ = say(t, backend, "2", nil); text != "Get some rest." {
+		t.Errorf("Expected the second answer, got %q", text)
+	}
+	if text, _ = say(t, backend, "ok", nil); text != "See you!" {
+		t.Errorf("Expected the conversation to continue at next, got %q", text)
+	}
+
+	// The conversation is over; the next message starts it again
+	if text, _ = say(t, backend, "hello again", nil); text != "Hi! How are you?" {
+		t.Errorf("Expected a new conversation, got %q", text)
+	}
+}
+
+func TestScriptedBackendChosenReply(t *testing.T) {
+	backend := newScriptedBackend(t)
+	say(t, backend, "hey", nil)
+
+	// A picked quick reply wins over the raw message text
+	response, err := backend.GenerateResponse(DialogContext{Trigger: "chat", LastResponse: "1", ChosenReply: "Tired."})
+	if err != nil || response.Text != "Get some rest." {
+		t.Errorf("Expected the chosen reply to be followed, got %q %v", response.Text, err)
+	}
+}
+
+func TestScriptedBackendConditions(t *testing.T) {
+	backend := newScriptedBackend(t)
+
+	_, choices := say(t, backend, "hey", map[string]float64{"happiness": 90})
+	if len(choices) != 3 || choices[2] != "Let's dance!" {
+		t.Fatalf("Expected the dance choice for a happy character, got %v", choices)
+	}
+
+	say(t, backend, "great!", nil)
+	if text, _ := say(t, backend, "yes", nil); text != "I like you." {
+		t.Fatalf("Expected the secret, got %q", text)
+	}
+
+	// The remembered flag sends the next conversation down another branch
+	if text, choices := say(t, backend, "hey", nil); text != "Remember our secret?" || len(choices) != 0 {
+		t.Errorf("Expected the secret branch, got %q %v", text, choices)
+	}
+}
+
+func TestScriptedBackendTriggers(t *testing.T) {
+	backend := newScriptedBackend(t)
+
+	if backend.CanHandle(DialogContext{Trigger: "hover"}) {
+		t.Error("Triggers without an entry should be left to other backends")
+	}
+	response, err := backend.GenerateResponse(DialogContext{Trigger: "click"})
+	if err != nil || response.Text != "*waves*" || response.Confidence <= 0.5 {
+		t.Errorf("Expected the click entry, got %+v %v", response, err)
+	}
+}
+
+func TestScriptedBackendValidation(t *testing.T) {
+	tests := map[string]string{
+		"no script":    `{}`,
+		"no entries":   `{"script": {"nodes": {"a": {"text": "hi"}}}}`,
+		"unknown next": `{"script": {"entries": {"chat": "a"}, "nodes": {"a": {"text": "hi", "next": "b"}}}}`,
+		"dead end":     `{"script": {"entries": {"chat": "a"}, "nodes": {"a": {}}}}`,
+		"bad choice":   `{"script": {"entries": {"chat": "a"}, "nodes": {"a": {"text": "hi", "choices": [{"text": "x", "next": "b"}]}}}}`,
+	}
+	for name, config := range tests {
+		if err := NewScriptedBackend().Initialize([]byte(config)); err == nil {
+			t.Errorf("%s: expected Initialize to fail", name)
+		}
+	}
+
+	loop := NewScriptedBackend()
+	if err := loop.Initialize([]byte(`{"script": {"entries": {"chat": "a"}, "nodes": {"a": {"next": "b"}, "b": {"next": "a"}}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loop.GenerateResponse(DialogContext{Trigger: "chat"}); err == nil || !strings.Contains(err.Error(), "loops") {
+		t.Errorf("Expected a looping script to fail, got %v", err)
+	}
+}
+
+func TestScriptedBackendScriptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "story.json"), []byte(testScript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := NewScriptedBackend()
+	backend.SetBaseDir(dir)
+	if err := backend.Initialize([]byte(`{"scriptFile": "story.json"}`)); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if text, _ := say(t, backend, "hey", nil); text != "Hi! How are you?" {
+		t.Errorf("Expected the script from the file, got %q", text)
+	}
+}
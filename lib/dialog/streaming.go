@@ -0,0 +1,99 @@
+package dialog
+
+// StreamChunk is one piece of a streamed response. Backends send text deltas
+// as they are produced and finish with a chunk carrying the final response.
+type StreamChunk struct {
+	Text  string          // Text produced since the previous chunk
+	Final *DialogResponse // Set on the last chunk: full text, animation and confidence
+	Err   error           // Set if generation failed; no further chunks follow
+}
+
+// StreamingBackend is implemented by backends that can deliver a response
+// incrementally, such as LLMs. It is optional - the manager falls back to
+// GenerateResponse for backends that don't implement it.
+type StreamingBackend interface {
+	DialogBackend
+
+	// GenerateResponseStream starts generating a response and returns a
+	// channel of chunks. The backend must close the channel when done.
+	GenerateResponseStream(context DialogContext) (<-chan StreamChunk, error)
+}
+
+// GenerateDialogStream produces a response like GenerateDialog but delivers
+// it incrementally when the default backend supports streaming. Other
+// backends, and streams that fail before producing any text, fall back to
+// GenerateDialog and arrive as a single chunk. The returned channel always
+// ends with a chunk carrying Final.
+func (dm *DialogManager) GenerateDialogStream(context DialogContext) <-chan StreamChunk {
+	out := make(chan StreamChunk, 16)
+
+	go func() {
+		defer close(out)
+
+		if dm.streamDefaultBackend(context, out) {
+			return
+		}
+
+		response, err := dm.GenerateDialog(context)
+		if err != nil {
+			out <- StreamChunk{Err: err}
+			return
+		}
+		out <- StreamChunk{Text: response.Text, Final: &response}
+	}()
+
+	return out
+}
+
+// streamDefaultBackend forwards the default backend's stream to out.
+// Returns false if nothing was sent, so the caller can fall back.
+func (dm *DialogManager) streamDefaultBackend(context DialogContext, out chan<- StreamChunk) bool {
+	dm.mu.RLock()
+	name := dm.defaultBackend
+	backend, exists := dm.backends[name]
+	dm.mu.RUnlock()
+
+	streamer, ok := backend.(StreamingBackend)
+	if name == "" || !exists || !ok || !streamer.CanHandle(context) {
+		return false
+	}
+
+	chunks, err := streamer.GenerateResponseStream(context)
+	if err != nil {
+		return false
+	}
+
+	var text string
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			if text == "" {
+				return false
+			}
+			// Partial text is already on screen; finish with what we have
+			out <- StreamChunk{Final: &DialogResponse{Text: text, Confidence: 0.5, ResponseType: "partial", Backend: name}}
+			return true
+		}
+
+		text += chunk.Text
+		if chunk.Final != nil {
+			final := *chunk.Final
+			if final.Text == "" {
+				final.Text = text
+			}
+			final.Backend = name
+			chunk.Final = &final
+		}
+		out <- chunk
+
+		if chunk.Final != nil {
+			return true
+		}
+	}
+
+	// Backend closed the stream without a final chunk
+	if text == "" {
+		return false
+	}
+	out <- StreamChunk{Final: &DialogResponse{Text: text, Confidence: 1.0, ResponseType: "streamed", Backend: name}}
+	return true
+}
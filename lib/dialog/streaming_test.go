@@ -0,0 +1,118 @@
+package dialog
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// stubStreamBackend streams a fixed list of chunks
+type stubStreamBackend struct {
+	chunks   []string
+	failWith error // Sent instead of the final chunk
+}
+
+func (b *stubStreamBackend) Initialize(json.RawMessage) error { return nil }
+
+func (b *stubStreamBackend) GenerateResponse(DialogContext) (DialogResponse, error) {
+	return DialogResponse{Text: "whole reply", Confidence: 1.0}, nil
+}
+
+func (b *stubStreamBackend) GetBackendInfo() BackendInfo { return BackendInfo{Name: "stub_stream"} }
+
+func (b *stubStreamBackend) CanHandle(DialogContext) bool { return true }
+
+func (b *stubStreamBackend) UpdateMemory(DialogContext, DialogResponse, *UserFeedback) error {
+	return nil
+}
+
+func (b *stubStreamBackend) GenerateResponseStream(DialogContext) (<-chan StreamChunk, error) {
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		for _, text := range b.chunks {
+			out <- StreamChunk{Text: text}
+		}
+		if b.failWith != nil {
+			out <- StreamChunk{Err: b.failWith}
+			return
+		}
+		out <- StreamChunk{Final: &DialogResponse{Animation: "talking", Confidence: 0.9}}
+	}()
+	return out, nil
+}
+
+// collectStream drains a stream into its text deltas and final response
+func collectStream(t *testing.T, chunks <-chan StreamChunk) ([]string, *DialogResponse) {
+	t.Helper()
+
+	var deltas []string
+	var final *DialogResponse
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			t.Fatalf("Unexpected stream error: %v", chunk.Err)
+		}
+		if chunk.Text != "" {
+			deltas = append(deltas, chunk.Text)
+		}
+		if chunk.Final != nil {
+			final = chunk.Final
+		}
+	}
+	if final == nil {
+		t.Fatal("Stream ended without a final response")
+	}
+	return deltas, final
+}
+
+func newStreamTestManager(t *testing.T, backend DialogBackend) *DialogManager {
+	t.Helper()
+
+	dm := NewDialogManager(false)
+	dm.RegisterBackend("test", backend)
+	if err := dm.SetDefaultBackend("test"); err != nil {
+		t.Fatalf("SetDefaultBackend failed: %v", err)
+	}
+	return dm
+}
+
+func TestGenerateDialogStreamForwardsChunks(t *testing.T) {
+	dm := newStreamTestManager(t, &stubStreamBackend{chunks: []string{"Hel", "lo ", "there"}})
+
+	deltas, final := collectStream(t, dm.GenerateDialogStream(DialogContext{Trigger: "chat"}))
+	if len(deltas) != 3 {
+		t.Errorf("Expected 3 incremental chunks, got %v", deltas)
+	}
+	if final.Text != "Hello there" || final.Animation != "talking" || final.Backend != "test" {
+		t.Errorf("Unexpected final response %+v", final)
+	}
+}
+
+func TestGenerateDialogStreamFallsBackForPlainBackends(t *testing.T) {
+	dm := newStreamTestManager(t, NewSimpleRandomBackend())
+	dm.backends["test"].Initialize(json.RawMessage(`{}`))
+
+	deltas, final := collectStream(t, dm.GenerateDialogStream(DialogContext{
+		Trigger:           "chat",
+		FallbackResponses: []string{"Hi!"},
+	}))
+	if len(deltas) != 1 || deltas[0] != final.Text {
+		t.Errorf("Expected the whole reply as one chunk, got %v / %q", deltas, final.Text)
+	}
+}
+
+func TestGenerateDialogStreamRecoversFromErrors(t *testing.T) {
+	// Failing before any text falls back to a regular response
+	dm := newStreamTestManager(t, &stubStreamBackend{failWith: errors.New("model crashed")})
+	_, final := collectStream(t, dm.GenerateDialogStream(DialogContext{Trigger: "chat"}))
+	if final.Text != "whole reply" {
+		t.Errorf("Expected non-streamed fallback, got %q", final.Text)
+	}
+
+	// Failing midway keeps the text already shown
+	dm = newStreamTestManager(t, &stubStreamBackend{chunks: []string{"Half a"}, failWith: errors.New("timeout")})
+	_, final = collectStream(t, dm.GenerateDialogStream(DialogContext{Trigger: "chat"}))
+	if final.Text != "Half a" || final.ResponseType != "partial" {
+		t.Errorf("Expected partial response, got %+v", final)
+	}
+}
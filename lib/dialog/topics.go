@@ -0,0 +1,243 @@
+// topics.go: Pluggable topic extraction for conversation context
+// Topics come from keyword lists, RAKE-style key phrases, or the dialog backend's own responses
+// Uses Go stdlib only for tokenizing and scoring
+
+package dialog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Topic extractor names accepted in TopicConfig
+const (
+	TopicExtractorKeyword = "keyword" // Fixed keyword lists per topic (default)
+	TopicExtractorRAKE    = "rake"    // Key phrases scored RAKE-style, each its own topic
+	TopicExtractorBackend = "backend" // Topics the dialog backend reports with its responses
+)
+
+// Topic config defaults
+const (
+	defaultTopicDecayWindow = 300 // Seconds a topic stays active
+	defaultTopicDecay       = 0.8 // Confidence kept when a topic comes up again
+	defaultMaxTopics        = 10
+	keywordTopicConfidence  = 0.2 // Confidence added per matching keyword
+	rakeMaxPhrases          = 3   // Key phrases taken from one message
+)
+
+// TopicConfig tunes how the conversation context picks out and forgets topics
+type TopicConfig struct {
+	Extractor   string  `json:"extractor,omitempty"`   // "keyword" (default), "rake" or "backend"
+	DecayWindow int     `json:"decayWindow,omitempty"` // Seconds a topic stays active after it last came up (default: 300)
+	Decay       float64 `json:"decay,omitempty"`       // Share of a topic's confidence kept when it comes up again, 0-1 (default: 0.8)
+	MaxTopics   int     `json:"maxTopics,omitempty"`   // Topics remembered, least recent forgotten first (default: 10)
+}
+
+// Validate checks the topic settings
+func (tc *TopicConfig) Validate() error {
+	if _, err := NewTopicExtractor(tc.Extractor); err != nil {
+		return err
+	}
+	if tc.DecayWindow < 0 {
+		return fmt.Errorf("topic decayWindow must be non-negative, got %d", tc.DecayWindow)
+	}
+	if tc.Decay < 0 || tc.Decay > 1 {
+		return fmt.Errorf("topic decay must be between 0 and 1, got %f", tc.Decay)
+	}
+	if tc.MaxTopics < 0 {
+		return fmt.Errorf("maxTopics must be non-negative, got %d", tc.MaxTopics)
+	}
+	return nil
+}
+
+// TopicMatch is a topic found in a message
+type TopicMatch struct {
+	Name       string  // Topic the message is about
+	Mention    string  // Words the message used for it
+	Confidence float64 // Confidence added by this message
+}
+
+// TopicExtractor finds the topics in a user's message
+type TopicExtractor interface {
+	ExtractTopics(message string) []TopicMatch
+}
+
+// NewTopicExtractor returns the named extractor, keyword for ""
+func NewTopicExtractor(name string) (TopicExtractor, error) {
+	switch name {
+	case "", TopicExtractorKeyword:
+		return keywordExtractor{}, nil
+	case TopicExtractorRAKE:
+		return rakeExtractor{}, nil
+	case TopicExtractorBackend:
+		return backendExtractor{}, nil
+	default:
+		return nil, fmt.Errorf("unknown topic extractor %q (want %q, %q or %q)",
+			name, TopicExtractorKeyword, TopicExtractorRAKE, TopicExtractorBackend)
+	}
+}
+
+// topicKeywords are the keyword extractor's topics
+var topicKeywords = map[string][]string{
+	"weather":    {"weather", "rain", "sunny", "cloudy", "temperature", "hot", "cold"},
+	"feelings":   {"feel", "happy", "sad", "angry", "excited", "worried", "love", "hate"},
+	"activities": {"doing", "playing", "working", "studying", "watching", "reading"},
+	"food":       {"eat", "hungry", "food", "cook", "meal", "drink", "taste"},
+	"health":     {"sick", "tired", "energy", "sleep", "pain", "doctor", "medicine"},
+	"media":      {"movie", "film", "show", "series", "book", "game", "song", "album", "music"},
+}
+
+// keywordExtractor matches messages against fixed keyword lists
+type keywordExtractor struct{}
+
+// ExtractTopics scores each topic by the keywords the message contains
+func (keywordExtractor) ExtractTopics(message string) []TopicMatch {
+	lower := strings.ToLower(message)
+
+	var matches []TopicMatch
+	for topicName, keywords := range topicKeywords {
+		match := TopicMatch{Name: topicName}
+		for _, keyword := range keywords {
+			if containsWord(lower, keyword) {
+				match.Confidence += keywordTopicConfidence
+				if match.Mention == "" {
+					match.Mention = keyword
+				}
+			}
+		}
+
+		if match.Confidence > 0 {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// rakeStopWords split messages into candidate key phrases
+var rakeStopWords = wordSet(`a about above after again all also am an and any are as at be
+	because been before being below between both but by can could did do does doing down during each
+	few for from further had has have having he her here hers him his how i i'd i'll i'm i've if in into
+	is isn't it it's its just last let's like lot me more most much my myself next no nor not now of off
+	on once only or other our ours out over own really same she should so some such than that that's
+	the their them then there these they this those through to today tomorrow tonight too under until
+	up us very was we were what when where which while who why will with would yesterday yet you your
+	you're yours`)
+
+// wordSet returns the whitespace-separated words of list as a set
+func wordSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(list) {
+		set[word] = true
+	}
+	return set
+}
+
+// rakeExtractor takes the best-scoring key phrases of a message as topics,
+// as in Rapid Automatic Keyword Extraction: phrases are the runs of words
+// between stop words and punctuation, and each word scores its degree over
+// its frequency, favoring words that appear in longer phrases
+type rakeExtractor struct{}
+
+// ExtractTopics returns the top rakeMaxPhrases phrases, the best at full confidence
+func (rakeExtractor) ExtractTopics(message string) []TopicMatch {
+	phrases := rakePhrases(message)
+	if len(phrases) == 0 {
+		return nil
+	}
+
+	frequency := make(map[string]int)
+	degree := make(map[string]int)
+	for _, phrase := range phrases {
+		for _, word := range phrase {
+			frequency[word]++
+			degree[word] += len(phrase)
+		}
+	}
+
+	scores := make(map[string]float64)
+	for _, phrase := range phrases {
+		score := 0.0
+		for _, word := range phrase {
+			score += float64(degree[word]) / float64(frequency[word])
+		}
+		scores[strings.Join(phrase, " ")] = score
+	}
+
+	matches := make([]TopicMatch, 0, len(scores))
+	for phrase, score := range scores {
+		matches = append(matches, TopicMatch{Name: phrase, Mention: phrase, Confidence: score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	if len(matches) > rakeMaxPhrases {
+		matches = matches[:rakeMaxPhrases]
+	}
+
+	best := matches[0].Confidence
+	for i := range matches {
+		matches[i].Confidence /= best
+	}
+	return matches
+}
+
+// rakePhrases splits a message into lowercase candidate phrases. Words of
+// one letter and numbers are dropped along with stop words.
+func rakePhrases(message string) [][]string {
+	var phrases [][]string
+	var phrase []string
+	var word strings.Builder
+
+	endWord := func() {
+		w := word.String()
+		word.Reset()
+		if w == "" {
+			return
+		}
+		if rakeStopWords[w] || len([]rune(w)) < 2 || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			endPhrase(&phrases, &phrase)
+			return
+		}
+		phrase = append(phrase, w)
+	}
+
+	for _, r := range strings.ToLower(message) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '’':
+			if r == '’' {
+				r = '\''
+			}
+			word.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			endWord()
+		default:
+			endWord()
+			endPhrase(&phrases, &phrase)
+		}
+	}
+	endWord()
+	endPhrase(&phrases, &phrase)
+	return phrases
+}
+
+// endPhrase closes the phrase being built, if any
+func endPhrase(phrases *[][]string, phrase *[]string) {
+	if len(*phrase) > 0 {
+		*phrases = append(*phrases, *phrase)
+		*phrase = nil
+	}
+}
+
+// backendExtractor leaves messages alone; topics come from the dialog
+// backend through ConversationContext.AddResponseTopics
+type backendExtractor struct{}
+
+// ExtractTopics finds nothing, the backend reports the topics
+func (backendExtractor) ExtractTopics(message string) []TopicMatch {
+	return nil
+}
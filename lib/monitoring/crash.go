@@ -0,0 +1,69 @@
+package monitoring
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// CrashMarker is a file that exists while the companion runs. Finding it at
+// startup means the previous session never shut down cleanly: it crashed,
+// was killed or the machine lost power.
+type CrashMarker struct {
+	path string
+}
+
+// NewCrashMarker returns a marker kept at path
+func NewCrashMarker(path string) *CrashMarker {
+	return &CrashMarker{path: path}
+}
+
+// Begin records that a session has started. previousCrashed is true when
+// the last session left its marker behind.
+func (m *CrashMarker) Begin() (previousCrashed bool, err error) {
+	_, statErr := os.Stat(m.path)
+	previousCrashed = statErr == nil
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return previousCrashed, fmt.Errorf("failed to create crash marker directory: %w", err)
+	}
+	started := []byte(time.Now().Format(time.RFC3339) + "\n")
+	if err := os.WriteFile(m.path, started, 0o644); err != nil {
+		return previousCrashed, fmt.Errorf("failed to write crash marker: %w", err)
+	}
+	return previousCrashed, nil
+}
+
+// End records that the session shut down cleanly
+func (m *CrashMarker) End() error {
+	if err := os.Remove(m.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove crash marker: %w", err)
+	}
+	return nil
+}
+
+// crashHandler is given the panics recovered by RecoverCrash
+var crashHandler atomic.Pointer[func(recovered any)]
+
+// SetCrashHandler sets what RecoverCrash does with a panic, such as writing
+// an emergency save and exiting. Without one the panic carries on.
+func SetCrashHandler(handler func(recovered any)) {
+	crashHandler.Store(&handler)
+}
+
+// RecoverCrash is deferred at the top of long-running goroutines, such as
+// the animation loop and network handlers, whose panics a recover on the
+// main goroutine never sees. A panic is handed to the crash handler.
+func RecoverCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if handler := crashHandler.Load(); handler != nil && *handler != nil {
+		(*handler)(r)
+		return
+	}
+	panic(r)
+}
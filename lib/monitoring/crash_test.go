@@ -0,0 +1,50 @@
+package monitoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCrashMarker(t *testing.T) {
+	marker := NewCrashMarker(filepath.Join(t.TempDir(), "logs", "pet.running"))
+
+	if crashed, err := marker.Begin(); err != nil || crashed {
+		t.Fatalf("Expected a clean first start, got crashed=%v err=%v", crashed, err)
+	}
+	if err := marker.End(); err != nil {
+		t.Fatal(err)
+	}
+	if crashed, _ := marker.Begin(); crashed {
+		t.Error("Expected a clean start after a clean shutdown")
+	}
+
+	// No End: the session crashed
+	if crashed, _ := marker.Begin(); !crashed {
+		t.Error("Expected the crash to be noticed on the next start")
+	}
+}
+
+func TestRecoverCrashOffMainGoroutine(t *testing.T) {
+	save := filepath.Join(t.TempDir(), "emergency.json")
+	recovered := make(chan any, 1)
+	SetCrashHandler(func(r any) {
+		if err := os.WriteFile(save, []byte("{}"), 0o644); err != nil {
+			t.Error(err)
+		}
+		recovered <- r
+	})
+	defer SetCrashHandler(nil)
+
+	go func() {
+		defer RecoverCrash()
+		panic("game logic broke")
+	}()
+
+	if r := <-recovered; r != "game logic broke" {
+		t.Errorf("Expected the panic handed to the crash handler, got %v", r)
+	}
+	if _, err := os.Stat(save); err != nil {
+		t.Errorf("Expected the emergency save written, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultFrameBudget is the time a frame may take before it counts as slow,
+// matching the 30 FPS target
+const DefaultFrameBudget = time.Second / 30
+
+// Frame budget tuning: a window of frames more than half of which are slow
+// degrades one level; recoverWindows windows in a row with hardly any slow
+// frames undo one level. Each time a restored level turns out too much
+// again, the wait doubles, up to maxRecoverWindows, so the app doesn't
+// flip back and forth.
+const (
+	frameBudgetWindow      = 60
+	slowFrameShare         = 0.5
+	healthyFrameShare      = 0.1
+	recoverWindows         = 10
+	maxRecoverWindows      = 160
+	overlayRefreshSlowdown = 3 // Overlays refresh this many times slower once degraded that far
+)
+
+// DegradationLevel is how far the app has scaled back to keep up on slow
+// hardware. Each level keeps the cutbacks of the ones before it.
+type DegradationLevel int
+
+const (
+	DegradationNone         DegradationLevel = iota // Full quality
+	DegradationReducedFPS                           // Animations run at 30 FPS instead of 60
+	DegradationNoParticles                          // Particle effects are turned off
+	DegradationSlowOverlays                         // Overlays refresh less often and animations run at 20 FPS
+)
+
+// String returns a short description for logs and the settings dialog
+func (l DegradationLevel) String() string {
+	switch l {
+	case DegradationNone:
+		return "full quality"
+	case DegradationReducedFPS:
+		return "reduced frame rate"
+	case DegradationNoParticles:
+		return "particles off"
+	case DegradationSlowOverlays:
+		return "slow overlays"
+	default:
+		return "unknown"
+	}
+}
+
+// MaxFPS returns the animation frame rate to use instead of fullFPS
+func (l DegradationLevel) MaxFPS(fullFPS int) int {
+	switch {
+	case l >= DegradationSlowOverlays:
+		return min(fullFPS, 20)
+	case l >= DegradationReducedFPS:
+		return min(fullFPS, 30)
+	default:
+		return fullFPS
+	}
+}
+
+// ParticlesEnabled reports whether particle effects still run
+func (l DegradationLevel) ParticlesEnabled() bool {
+	return l < DegradationNoParticles
+}
+
+// OverlayRefresh returns how often overlays refresh instead of every interval
+func (l DegradationLevel) OverlayRefresh(interval time.Duration) time.Duration {
+	if l >= DegradationSlowOverlays {
+		return interval * overlayRefreshSlowdown
+	}
+	return interval
+}
+
+// FrameBudget watches frame times and decides when the app should scale
+// back. Single slow frames are ignored; only a window of mostly slow
+// frames degrades, and quality comes back a level at a time once frames
+// have stayed well within budget for a while.
+type FrameBudget struct {
+	mu             sync.Mutex
+	budget         time.Duration
+	level          DegradationLevel
+	frames         int // Frames in the current window
+	slowFrames     int // Of those, frames over budget
+	healthyWindows int // Windows in a row with hardly any slow frames
+	recoverAfter   int // Healthy windows needed to restore a level
+	restored       bool
+}
+
+// NewFrameBudget creates a frame budget, DefaultFrameBudget for 0
+func NewFrameBudget(budget time.Duration) *FrameBudget {
+	if budget <= 0 {
+		budget = DefaultFrameBudget
+	}
+	return &FrameBudget{budget: budget, recoverAfter: recoverWindows}
+}
+
+// Level returns the current degradation level
+func (fb *FrameBudget) Level() DegradationLevel {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	return fb.level
+}
+
+// Record adds how long a frame took and returns the degradation level,
+// with whether it just changed
+func (fb *FrameBudget) Record(frameTime time.Duration) (DegradationLevel, bool) {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	fb.frames++
+	if frameTime > fb.budget {
+		fb.slowFrames++
+	}
+	if fb.frames < frameBudgetWindow {
+		return fb.level, false
+	}
+
+	slowShare := float64(fb.slowFrames) / float64(fb.frames)
+	fb.frames, fb.slowFrames = 0, 0
+	previous := fb.level
+
+	switch {
+	case slowShare > slowFrameShare:
+		fb.healthyWindows = 0
+		if fb.level < DegradationSlowOverlays {
+			fb.level++
+			if fb.restored {
+				fb.recoverAfter = min(fb.recoverAfter*2, maxRecoverWindows)
+				fb.restored = false
+			}
+		}
+	case slowShare <= healthyFrameShare:
+		fb.healthyWindows++
+		if fb.healthyWindows >= fb.recoverAfter && fb.level > DegradationNone {
+			fb.level--
+			fb.healthyWindows = 0
+			fb.restored = true
+		}
+	default:
+		fb.healthyWindows = 0
+	}
+
+	if fb.level == previous {
+		return fb.level, false
+	}
+	fb.logChange(previous, slowShare)
+	return fb.level, true
+}
+
+// logChange records a degradation decision. Must be called with fb.mu held.
+func (fb *FrameBudget) logChange(previous DegradationLevel, slowShare float64) {
+	fields := logrus.Fields{
+		"caller":     getCaller(),
+		"from":       previous.String(),
+		"to":         fb.level.String(),
+		"budget":     fb.budget.String(),
+		"slowFrames": slowShare,
+	}
+	if fb.level > previous {
+		logrus.WithFields(fields).Warn("Frames over budget, degrading to keep up")
+		return
+	}
+	logrus.WithFields(fields).Info("Frames back within budget, restoring quality")
+}
@@ -0,0 +1,86 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+)
+
+// recordWindow records one window of frames, slow of them over budget
+func recordWindow(fb *FrameBudget, slow int) (DegradationLevel, bool) {
+	var level DegradationLevel
+	var changed bool
+	for i := 0; i < frameBudgetWindow; i++ {
+		frameTime := time.Millisecond
+		if i < slow {
+			frameTime = time.Second
+		}
+		level, changed = fb.Record(frameTime)
+	}
+	return level, changed
+}
+
+func TestFrameBudgetDegradesOnSustainedSlowFrames(t *testing.T) {
+	fb := NewFrameBudget(0)
+
+	// A few slow frames are ignored
+	if level, changed := recordWindow(fb, 10); level != DegradationNone || changed {
+		t.Fatalf("Expected occasional slow frames to be ignored, got %v", level)
+	}
+
+	for _, want := range []DegradationLevel{DegradationReducedFPS, DegradationNoParticles, DegradationSlowOverlays} {
+		if level, changed := recordWindow(fb, frameBudgetWindow); level != want || !changed {
+			t.Fatalf("Expected to degrade to %v, got %v (changed %v)", want, level, changed)
+		}
+	}
+	if level, changed := recordWindow(fb, frameBudgetWindow); level != DegradationSlowOverlays || changed {
+		t.Errorf("Expected to stay at the last level, got %v", level)
+	}
+}
+
+func TestFrameBudgetRecoversWithBackoff(t *testing.T) {
+	fb := NewFrameBudget(0)
+	recordWindow(fb, frameBudgetWindow)
+
+	for i := 1; i < recoverWindows; i++ {
+		if level, _ := recordWindow(fb, 0); level != DegradationReducedFPS {
+			t.Fatalf("Expected to wait %d healthy windows, recovered after %d", recoverWindows, i)
+		}
+	}
+	if level, changed := recordWindow(fb, 0); level != DegradationNone || !changed {
+		t.Fatalf("Expected full quality back, got %v", level)
+	}
+
+	// Slow again right away: the next recovery takes twice as long
+	recordWindow(fb, frameBudgetWindow)
+	for i := 0; i < recoverWindows; i++ {
+		recordWindow(fb, 0)
+	}
+	if fb.Level() != DegradationReducedFPS {
+		t.Errorf("Expected the wait to double after flipping back, got %v", fb.Level())
+	}
+}
+
+func TestDegradationLevelCutbacks(t *testing.T) {
+	tests := []struct {
+		level     DegradationLevel
+		fps       int
+		particles bool
+		refresh   time.Duration
+	}{
+		{DegradationNone, 60, true, 2 * time.Second},
+		{DegradationReducedFPS, 30, true, 2 * time.Second},
+		{DegradationNoParticles, 30, false, 2 * time.Second},
+		{DegradationSlowOverlays, 20, false, 6 * time.Second},
+	}
+	for _, tt := range tests {
+		if fps := tt.level.MaxFPS(60); fps != tt.fps {
+			t.Errorf("%v: MaxFPS = %d, want %d", tt.level, fps, tt.fps)
+		}
+		if tt.level.ParticlesEnabled() != tt.particles {
+			t.Errorf("%v: ParticlesEnabled = %v, want %v", tt.level, !tt.particles, tt.particles)
+		}
+		if refresh := tt.level.OverlayRefresh(2 * time.Second); refresh != tt.refresh {
+			t.Errorf("%v: OverlayRefresh = %v, want %v", tt.level, refresh, tt.refresh)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Log file defaults: 5MB per file and three rotated files keep about a
+// week of normal use
+const (
+	DefaultLogMaxSize    = 5 << 20
+	DefaultLogMaxBackups = 3
+)
+
+// LogFileName returns the log file for a character, e.g. "aria-luna.log"
+func LogFileName(characterName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(characterName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		name = "character"
+	}
+	return name + ".log"
+}
+
+// LogFileHook is a logrus hook that also writes every logged entry to a
+// file, so users without a terminal can attach logs to bug reports. The file
+// is rotated to path.1, path.2, ... once it grows past maxSize.
+type LogFileHook struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	formatter  logrus.Formatter
+}
+
+// NewLogFileHook opens (or creates) the log file at path, creating its
+// directory. Sizes of 0 or less use the defaults.
+func NewLogFileHook(path string, maxSize int64, maxBackups int) (*LogFileHook, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultLogMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultLogMaxBackups
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	h := &LogFileHook{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		formatter:  &logrus.TextFormatter{DisableColors: true, FullTimestamp: true},
+	}
+	if err := h.open(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Path returns the current log file
+func (h *LogFileHook) Path() string {
+	return h.path
+}
+
+// Levels returns every level; the logger's own level decides what is logged
+func (h *LogFileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire appends the entry to the file, rotating first if it would grow past
+// the size limit
+func (h *LogFileHook) Fire(entry *logrus.Entry) error {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return fmt.Errorf("log file closed")
+	}
+	if h.size > 0 && h.size+int64(len(line)) > h.maxSize {
+		if err := h.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := h.file.Write(line)
+	h.size += int64(n)
+	return err
+}
+
+// Close closes the log file. Entries logged afterwards are dropped.
+func (h *LogFileHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+	err := h.file.Close()
+	h.file = nil
+	return err
+}
+
+// open opens the log file for appending. Must be called with h.mu held or
+// before the hook is shared.
+func (h *LogFileHook) open() error {
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	h.file = file
+	h.size = info.Size()
+	return nil
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest, moves the current
+// file to path.1 and starts a new one. Must be called with h.mu held.
+func (h *LogFileHook) rotate() error {
+	h.file.Close()
+	h.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", h.path, h.maxBackups))
+	for i := h.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", h.path, i), fmt.Sprintf("%s.%d", h.path, i+1))
+	}
+	if err := os.Rename(h.path, h.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return h.open()
+}
+
+// ReadLogTail returns up to the last n lines of the log file at path whose
+// level is minLevel or more severe. Lines without a level, such as
+// continuation lines, are kept with the entry before them.
+func ReadLogTail(path string, n int, minLevel logrus.Level) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	keep := false
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if level, ok := logLineLevel(line); ok {
+			keep = level <= minLevel
+		}
+		if !keep {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return lines, err
+	}
+	return lines, nil
+}
+
+// logLineLevel parses the level=... field written by the text formatter
+func logLineLevel(line string) (logrus.Level, bool) {
+	_, rest, found := strings.Cut(line, " level=")
+	if !found {
+		return 0, false
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	level, err := logrus.ParseLevel(name)
+	if err != nil {
+		return 0, false
+	}
+	return level, true
+}
@@ -0,0 +1,95 @@
+package monitoring
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogFileName(t *testing.T) {
+	tests := map[string]string{
+		"Aria Luna":  "aria-luna.log",
+		"Klippy!":    "klippy.log",
+		"../../etc":  "etc.log",
+		"":           "character.log",
+		"Tsundere 2": "tsundere-2.log",
+	}
+	for name, want := range tests {
+		if got := LogFileName(name); got != want {
+			t.Errorf("LogFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLogFileHookRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "test.log")
+	hook, err := NewLogFileHook(path, 512, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hook.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(hook)
+	for i := 0; i < 40; i++ {
+		logger.WithField("n", i).Info("a line long enough to fill the file quickly")
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Expected %s to exist: %v", name, err)
+		}
+		if info.Size() > 512 {
+			t.Errorf("Expected %s to stay under 512 bytes, got %d", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("Expected only 2 rotated files to be kept")
+	}
+
+	// The newest entry is in the current file
+	lines, err := ReadLogTail(path, 1, logrus.InfoLevel)
+	if err != nil || len(lines) != 1 || !strings.Contains(lines[0], "n=39") {
+		t.Errorf("Expected the last entry in the current file, got %v (%v)", lines, err)
+	}
+}
+
+func TestReadLogTailFiltersLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	hook, err := NewLogFileHook(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.DebugLevel)
+	logger.AddHook(hook)
+	for i := 0; i < 5; i++ {
+		logger.Debug(fmt.Sprintf("debug %d", i))
+		logger.Warn(fmt.Sprintf("warning %d", i))
+	}
+	logger.Error("broken")
+	hook.Close()
+
+	lines, _ := ReadLogTail(path, 100, logrus.WarnLevel)
+	if len(lines) != 6 {
+		t.Fatalf("Expected 5 warnings and 1 error, got %d: %v", len(lines), lines)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "level=debug") {
+			t.Errorf("Expected debug lines to be filtered out, got %q", line)
+		}
+	}
+
+	lines, _ = ReadLogTail(path, 3, logrus.DebugLevel)
+	if len(lines) != 3 || !strings.Contains(lines[2], "broken") {
+		t.Errorf("Expected the last 3 lines, got %v", lines)
+	}
+}
@@ -36,21 +36,23 @@ type Profiler struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	stats          *PerformanceStats
-	targetMemoryMB int // Target <50MB
+	targetMemoryMB int          // Target <50MB
+	budget         *FrameBudget // Scales the app back when frames run slow
 }
 
 // PerformanceStats tracks real-time performance metrics
 type PerformanceStats struct {
 	mu                sync.RWMutex
-	StartTime         time.Time     `json:"start_time"`
-	CurrentMemoryMB   float64       `json:"current_memory_mb"`
-	PeakMemoryMB      float64       `json:"peak_memory_mb"`
-	StartupDuration   time.Duration `json:"startup_duration"`
-	FrameRate         float64       `json:"frame_rate"`
-	LastFrameUpdate   time.Time     `json:"last_frame_update"`
-	TotalFrames       uint64        `json:"total_frames"`
-	MemoryAllocations uint64        `json:"memory_allocations"`
-	GCRuns            uint32        `json:"gc_runs"`
+	StartTime         time.Time        `json:"start_time"`
+	CurrentMemoryMB   float64          `json:"current_memory_mb"`
+	PeakMemoryMB      float64          `json:"peak_memory_mb"`
+	StartupDuration   time.Duration    `json:"startup_duration"`
+	FrameRate         float64          `json:"frame_rate"`
+	LastFrameUpdate   time.Time        `json:"last_frame_update"`
+	TotalFrames       uint64           `json:"total_frames"`
+	MemoryAllocations uint64           `json:"memory_allocations"`
+	GCRuns            uint32           `json:"gc_runs"`
+	Degradation       DegradationLevel `json:"degradation"`
 }
 
 // NewProfiler creates a new performance profiler
@@ -68,6 +70,7 @@ func NewProfiler(memoryTargetMB int) *Profiler {
 		targetMemoryMB: memoryTargetMB,
 		ctx:            ctx,
 		cancel:         cancel,
+		budget:         NewFrameBudget(DefaultFrameBudget),
 		stats: &PerformanceStats{
 			StartTime: time.Now(),
 		},
@@ -398,6 +401,18 @@ func (p *Profiler) RecordFrame() {
 	p.stats.mu.Unlock()
 }
 
+// RecordFrameTime adds how long a frame took to the frame budget and
+// returns how far the app should scale back, with whether that just
+// changed. Works whether or not monitoring was started.
+func (p *Profiler) RecordFrameTime(frameTime time.Duration) (DegradationLevel, bool) {
+	return p.budget.Record(frameTime)
+}
+
+// GetDegradation returns how far the app has scaled back for slow frames
+func (p *Profiler) GetDegradation() DegradationLevel {
+	return p.budget.Level()
+}
+
 // RecordStartupComplete marks the end of application startup
 func (p *Profiler) RecordStartupComplete() {
 	if !p.enabled {
@@ -424,6 +439,7 @@ func (p *Profiler) GetStats() PerformanceStats {
 		TotalFrames:       p.stats.TotalFrames,
 		MemoryAllocations: p.stats.MemoryAllocations,
 		GCRuns:            p.stats.GCRuns,
+		Degradation:       p.budget.Level(),
 	}
 }
 
@@ -0,0 +1,302 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Address families for NetworkManagerConfig.AddressFamily
+const (
+	AddressFamilyDual = "dual" // IPv4 and IPv6 (default)
+	AddressFamilyIPv4 = "ipv4"
+	AddressFamilyIPv6 = "ipv6"
+)
+
+// ipv6AllNodes is the link-local all-nodes multicast group. IPv6 has no
+// broadcast, so discovery announcements go here on each interface instead.
+var ipv6AllNodes = net.ParseIP("ff02::1")
+
+// IsValidAddressFamily reports whether family is a known address family
+func IsValidAddressFamily(family string) bool {
+	switch family {
+	case AddressFamilyDual, AddressFamilyIPv4, AddressFamilyIPv6:
+		return true
+	}
+	return false
+}
+
+// ParsePortRange parses "9000" or "9000-9010". An empty string returns
+// 0, 0, meaning any free port.
+func ParsePortRange(s string) (low, high int, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 0, nil
+	}
+
+	first, last, found := strings.Cut(s, "-")
+	if low, err = strconv.Atoi(strings.TrimSpace(first)); err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q", s)
+	}
+	high = low
+	if found {
+		if high, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+			return 0, 0, fmt.Errorf("invalid port range %q", s)
+		}
+	}
+	if low < 1 || high > 65535 || low > high {
+		return 0, 0, fmt.Errorf("invalid port range %q: ports must be 1-65535, low to high", s)
+	}
+	return low, high, nil
+}
+
+// familyAllows reports whether ip belongs to the address family
+func familyAllows(family string, ip net.IP) bool {
+	switch family {
+	case AddressFamilyIPv4:
+		return ip.To4() != nil
+	case AddressFamilyIPv6:
+		return ip.To4() == nil
+	}
+	return true
+}
+
+// familyNetwork returns the Go network name for base ("tcp" or "udp")
+func familyNetwork(base, family string) string {
+	switch family {
+	case AddressFamilyIPv4:
+		return base + "4"
+	case AddressFamilyIPv6:
+		return base + "6"
+	}
+	return base
+}
+
+// localInterface is an up, non-loopback interface and the addresses on it
+// usable for the address family
+type localInterface struct {
+	iface net.Interface
+	nets  []*net.IPNet
+}
+
+// localInterfaces lists the interfaces discovery runs on. bind narrows them
+// to the named interface or the one holding that address.
+func localInterfaces(family, bind string) ([]localInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	bindIP := net.ParseIP(bind)
+	var result []localInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if bind != "" && bindIP == nil && iface.Name != bind {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		local := localInterface{iface: iface}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || !familyAllows(family, ipNet.IP) {
+				continue
+			}
+			if bindIP != nil && !ipNet.IP.Equal(bindIP) {
+				continue
+			}
+			local.nets = append(local.nets, ipNet)
+		}
+		if len(local.nets) > 0 {
+			result = append(result, local)
+		}
+	}
+	return result, nil
+}
+
+// resolveBindIP turns a bind setting into the IP sockets listen on. An IP is
+// used as is; an interface name picks its first address in the family,
+// preferring IPv4. An empty bind listens on every address.
+func resolveBindIP(family, bind string) (net.IP, error) {
+	if bind == "" {
+		return nil, nil
+	}
+	if ip := net.ParseIP(bind); ip != nil {
+		if !familyAllows(family, ip) {
+			return nil, fmt.Errorf("bind address %s is not %s", bind, family)
+		}
+		return ip, nil
+	}
+
+	ifaces, err := localInterfaces(family, bind)
+	if err != nil {
+		return nil, err
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("interface %q has no usable %s address", bind, family)
+	}
+
+	var fallback net.IP
+	for _, ipNet := range ifaces[0].nets {
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP, nil
+		}
+		if fallback == nil && !ipNet.IP.IsLinkLocalUnicast() {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("interface %q has no routable address", bind)
+	}
+	return fallback, nil
+}
+
+// listenHostPort formats a listen address for ip, which may be nil
+func listenHostPort(ip net.IP, port int) string {
+	host := ""
+	if ip != nil {
+		host = ip.String()
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// listenTCPInRange listens on the first free port from low to high. A zero
+// range lets the OS choose.
+func listenTCPInRange(network string, ip net.IP, low, high int) (net.Listener, error) {
+	if low == 0 {
+		return net.Listen(network, listenHostPort(ip, 0))
+	}
+
+	var lastErr error
+	for port := low; port <= high; port++ {
+		listener, err := net.Listen(network, listenHostPort(ip, port))
+		if err == nil {
+			return listener, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no free port in %d-%d: %w", low, high, lastErr)
+}
+
+// advertisedAddresses lists the addresses peers can reach us on, for
+// discovery payloads. IPv6 link-local addresses are left out because they
+// mean nothing without the receiver's own zone; peers on the same link
+// reach us through the packet's source address instead.
+func advertisedAddresses(ifaces []localInterface) []string {
+	var addrs []string
+	for _, local := range ifaces {
+		for _, ipNet := range local.nets {
+			if ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			addrs = append(addrs, ipNet.IP.String())
+		}
+	}
+	return addrs
+}
+
+// discoveryTargets returns where discovery announcements are sent: the
+// directed broadcast address of every IPv4 subnet, so each interface of a
+// multi-homed machine is covered, and the IPv6 all-nodes group on every
+// multicast-capable interface.
+func discoveryTargets(ifaces []localInterface, port int) []*net.UDPAddr {
+	var targets []*net.UDPAddr
+	seen := make(map[string]bool)
+	add := func(addr *net.UDPAddr) {
+		if key := addr.String(); !seen[key] {
+			seen[key] = true
+			targets = append(targets, addr)
+		}
+	}
+
+	for _, local := range ifaces {
+		hasIPv6 := false
+		for _, ipNet := range local.nets {
+			if ip4 := ipNet.IP.To4(); ip4 != nil {
+				if local.iface.Flags&net.FlagBroadcast != 0 {
+					add(&net.UDPAddr{IP: directedBroadcast(ip4, ipNet.Mask), Port: port})
+				}
+			} else {
+				hasIPv6 = true
+			}
+		}
+		if hasIPv6 && local.iface.Flags&net.FlagMulticast != 0 {
+			add(&net.UDPAddr{IP: ipv6AllNodes, Port: port, Zone: local.iface.Name})
+		}
+	}
+	return targets
+}
+
+// directedBroadcast returns the broadcast address of an IPv4 subnet
+func directedBroadcast(ip net.IP, mask net.IPMask) net.IP {
+	if len(mask) == net.IPv6len {
+		mask = mask[12:]
+	}
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range broadcast {
+		broadcast[i] = ip[i] | ^mask[i]
+	}
+	return broadcast
+}
+
+// peerDialAddresses lists the addresses to try when connecting to a peer:
+// the source of its announcement first, as that already proved reachable,
+// then whatever else it advertised
+func peerDialAddresses(sourceHost string, advertised []string, tcpPort int) []string {
+	port := strconv.Itoa(tcpPort)
+	seen := make(map[string]bool)
+	var addrs []string
+	for _, host := range append([]string{sourceHost}, advertised...) {
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		addrs = append(addrs, net.JoinHostPort(host, port))
+	}
+	return addrs
+}
+
+// dial connects to a peer, from the bound address when one is set so
+// replies come back on the same interface
+func (nm *NetworkManager) dial(address string) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	if nm.bindIP != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: nm.bindIP}
+	}
+	return dialer.Dial(familyNetwork("tcp", nm.addressFamily), address)
+}
+
+// acceptsSource reports whether a discovery announcement from addr is on a
+// network we are bound to. Without a bind address every source is accepted.
+func (nm *NetworkManager) acceptsSource(addr net.Addr) bool {
+	if nm.bindAddress == "" {
+		return true
+	}
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return false
+	}
+
+	ifaces, err := localInterfaces(nm.addressFamily, nm.bindAddress)
+	if err != nil {
+		return false
+	}
+	for _, local := range ifaces {
+		if udpAddr.Zone != "" && udpAddr.Zone == local.iface.Name {
+			return true
+		}
+		for _, ipNet := range local.nets {
+			if ipNet.Contains(udpAddr.IP) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package network
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		in        string
+		low, high int
+		wantErr   bool
+	}{
+		{"", 0, 0, false},
+		{"9000", 9000, 9000, false},
+		{"9000-9010", 9000, 9010, false},
+		{" 9000 - 9010 ", 9000, 9010, false},
+		{"9010-9000", 0, 0, true},
+		{"0-10", 0, 0, true},
+		{"9000-70000", 0, 0, true},
+		{"ports", 0, 0, true},
+	}
+	for _, tt := range tests {
+		low, high, err := ParsePortRange(tt.in)
+		if (err != nil) != tt.wantErr || low != tt.low || high != tt.high {
+			t.Errorf("ParsePortRange(%q) = %d, %d, %v", tt.in, low, high, err)
+		}
+	}
+}
+
+func TestNewNetworkManagerAddressingValidation(t *testing.T) {
+	if _, err := NewNetworkManager(NetworkManagerConfig{AddressFamily: "ipx"}); err == nil {
+		t.Error("Expected an unknown address family to be rejected")
+	}
+	if _, err := NewNetworkManager(NetworkManagerConfig{TCPPortRange: "10-1"}); err == nil {
+		t.Error("Expected a backwards port range to be rejected")
+	}
+
+	nm, err := NewNetworkManager(NetworkManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nm.addressFamily != AddressFamilyDual {
+		t.Errorf("Expected dual stack by default, got %s", nm.addressFamily)
+	}
+}
+
+func TestDiscoveryTargetsCoverEveryInterface(t *testing.T) {
+	ifaces := []localInterface{
+		{
+			iface: net.Interface{Name: "eth0", Flags: net.FlagUp | net.FlagBroadcast | net.FlagMulticast},
+			nets: []*net.IPNet{
+				{IP: net.ParseIP("192.168.1.20"), Mask: net.CIDRMask(24, 32)},
+				{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+			},
+		},
+		{
+			iface: net.Interface{Name: "wlan0", Flags: net.FlagUp | net.FlagBroadcast},
+			nets:  []*net.IPNet{{IP: net.ParseIP("10.0.8.3"), Mask: net.CIDRMask(16, 32)}},
+		},
+	}
+
+	var got []string
+	for _, addr := range discoveryTargets(ifaces, 8080) {
+		got = append(got, addr.String())
+	}
+	want := "192.168.1.255:8080,[ff02::1%eth0]:8080,10.0.255.255:8080"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, ","))
+	}
+
+	// Link-local IPv6 addresses need the receiver's zone, so aren't advertised
+	if addrs := advertisedAddresses(ifaces); strings.Join(addrs, ",") != "192.168.1.20,10.0.8.3" {
+		t.Errorf("Unexpected advertised addresses %v", addrs)
+	}
+}
+
+func TestPeerDialAddresses(t *testing.T) {
+	got := peerDialAddresses("192.168.1.20", []string{"10.0.8.3", "192.168.1.20", "2001:db8::5"}, 4000)
+	want := "192.168.1.20:4000,10.0.8.3:4000,[2001:db8::5]:4000"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Expected the source first and no duplicates, got %v", got)
+	}
+}
+
+func TestListenTCPInRangeSkipsBusyPorts(t *testing.T) {
+	busy, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	port := busy.Addr().(*net.TCPAddr).Port
+
+	listener, err := listenTCPInRange("tcp4", net.ParseIP("127.0.0.1"), port, port+5)
+	if err != nil {
+		t.Skipf("No free port next to %d: %v", port, err)
+	}
+	defer listener.Close()
+	if got := listener.Addr().(*net.TCPAddr).Port; got <= port || got > port+5 {
+		t.Errorf("Expected a port in %d-%d, got %d", port+1, port+5, got)
+	}
+
+	if _, err := listenTCPInRange("tcp4", net.ParseIP("127.0.0.1"), port, port); err == nil {
+		t.Error("Expected an error when the whole range is busy")
+	}
+}
+
+func TestStartWithBindAddress(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{
+		DiscoveryPort: findAvailablePort(t),
+		Discovery:     DiscoveryBroadcast,
+		AddressFamily: AddressFamilyIPv4,
+		BindAddress:   "127.0.0.1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer nm.Stop()
+
+	addr := nm.tcpListener.Addr().(*net.TCPAddr)
+	if !addr.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Expected the TCP listener on the bind address, got %s", addr)
+	}
+
+	if nm.acceptsSource(&net.UDPAddr{IP: net.ParseIP("203.0.113.9")}) {
+		t.Error("Expected announcements from other networks to be ignored")
+	}
+
+	if _, err := resolveBindIP(AddressFamilyIPv4, "::1"); err == nil {
+		t.Error("Expected an IPv6 bind address to be rejected for IPv4 only")
+	}
+}
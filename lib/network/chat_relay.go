@@ -0,0 +1,271 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MessageTypeChatRelay carries text typed by one user to another, shown
+// next to the sending character's avatar. Checked against the chats permission.
+const MessageTypeChatRelay MessageType = "chat_relay"
+
+const (
+	// MaxChatRelayLength is the longest message, in characters, a user can send
+	MaxChatRelayLength = 500
+	// maxChatAvatarBytes caps the avatar image a peer may send us
+	maxChatAvatarBytes = 64 << 10
+	// chatRelayHistory is how many lines are kept per peer
+	chatRelayHistory = 100
+)
+
+// Rate limits: users may send a few lines in quick succession but not flood
+// a peer, and a peer sending faster than that is dropped
+const (
+	chatSendLimit    = 5
+	chatReceiveLimit = 10
+	chatLimitWindow  = 10 * time.Second
+)
+
+// ErrChatRateLimited is returned when a user sends faster than the rate limit
+var ErrChatRateLimited = errors.New("sending too fast, wait a moment")
+
+// ChatRelayPayload is the wire format of a relayed chat message. The avatar
+// goes with the first message to each peer and again after it changes.
+type ChatRelayPayload struct {
+	Text      string `json:"text"`
+	Character string `json:"character"`
+	Avatar    []byte `json:"avatar,omitempty"` // PNG thumbnail of the sending character
+}
+
+// ChatRelayLine is one message in a conversation with a peer, for UI rendering
+type ChatRelayLine struct {
+	PeerID    string
+	Character string // Name of the character the sender is using
+	Text      string
+	At        time.Time
+	Remote    bool
+}
+
+// ChatRelayTransport is the subset of the network manager used for chat relay
+type ChatRelayTransport interface {
+	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+}
+
+// ChatRelay lets users talk to each other through their companions. Each
+// peer has its own conversation; lines are rate limited in both directions.
+// Whether a peer may chat with us is enforced by the network manager's
+// permission store before messages reach the relay.
+type ChatRelay struct {
+	mu         sync.Mutex
+	transport  ChatRelayTransport
+	character  string
+	avatar     []byte
+	avatarSent map[string]bool   // Peers that have our current avatar
+	avatars    map[string][]byte // Peer ID to the avatar they sent
+	characters map[string]string // Peer ID to their character's name
+	history    map[string][]ChatRelayLine
+	sent       *chatRateLimit
+	received   *chatRateLimit
+	onLine     func(ChatRelayLine)
+}
+
+// NewChatRelay creates a relay speaking as characterName and registers its message handler
+func NewChatRelay(transport ChatRelayTransport, characterName string) *ChatRelay {
+	cr := &ChatRelay{
+		transport:  transport,
+		character:  characterName,
+		avatarSent: make(map[string]bool),
+		avatars:    make(map[string][]byte),
+		characters: make(map[string]string),
+		history:    make(map[string][]ChatRelayLine),
+		sent:       newChatRateLimit(chatSendLimit, chatLimitWindow),
+		received:   newChatRateLimit(chatReceiveLimit, chatLimitWindow),
+	}
+
+	if transport != nil {
+		transport.RegisterMessageHandler(MessageTypeChatRelay, cr.handleMessage)
+	}
+	return cr
+}
+
+// SetLineHandler sets the callback that renders each sent or received line
+func (cr *ChatRelay) SetLineHandler(handler func(ChatRelayLine)) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.onLine = handler
+}
+
+// SetCharacter changes the character name our messages are shown with
+func (cr *ChatRelay) SetCharacter(name string) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.character = name
+}
+
+// SetAvatar changes the PNG image peers show next to our messages. Every
+// peer is sent the new avatar with our next message to them.
+func (cr *ChatRelay) SetAvatar(avatar []byte) error {
+	if len(avatar) > maxChatAvatarBytes {
+		return fmt.Errorf("avatar is %d bytes, at most %d allowed", len(avatar), maxChatAvatarBytes)
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.avatar = avatar
+	cr.avatarSent = make(map[string]bool)
+	return nil
+}
+
+// Send relays text to a peer and records it in that conversation
+func (cr *ChatRelay) Send(peerID, text string) error {
+	text = strings.TrimSpace(text)
+	if peerID == "" {
+		return fmt.Errorf("no peer selected")
+	}
+	if text == "" {
+		return fmt.Errorf("message is empty")
+	}
+	if utf8.RuneCountInString(text) > MaxChatRelayLength {
+		return fmt.Errorf("message is longer than %d characters", MaxChatRelayLength)
+	}
+
+	now := time.Now()
+	cr.mu.Lock()
+	if !cr.sent.allow(peerID, now) {
+		cr.mu.Unlock()
+		return ErrChatRateLimited
+	}
+	payload := ChatRelayPayload{Text: text, Character: cr.character}
+	if !cr.avatarSent[peerID] {
+		payload.Avatar = cr.avatar
+	}
+	cr.mu.Unlock()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode chat message: %w", err)
+	}
+	if err := cr.transport.SendMessage(MessageTypeChatRelay, data, peerID); err != nil {
+		return fmt.Errorf("failed to send chat message: %w", err)
+	}
+
+	cr.mu.Lock()
+	if payload.Avatar != nil {
+		cr.avatarSent[peerID] = true
+	}
+	cr.mu.Unlock()
+
+	cr.record(ChatRelayLine{PeerID: peerID, Character: payload.Character, Text: text, At: now})
+	return nil
+}
+
+// handleMessage records a line from a peer, dropping floods and oversized messages
+func (cr *ChatRelay) handleMessage(msg Message, from *Peer) error {
+	if from == nil {
+		return fmt.Errorf("chat message without sender")
+	}
+
+	var payload ChatRelayPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse chat message: %w", err)
+	}
+	text := strings.TrimSpace(payload.Text)
+	if text == "" || utf8.RuneCountInString(text) > MaxChatRelayLength {
+		return fmt.Errorf("chat message from %s is empty or too long", from.ID)
+	}
+
+	now := time.Now()
+	cr.mu.Lock()
+	if !cr.received.allow(from.ID, now) {
+		cr.mu.Unlock()
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": from.ID,
+		}).Debug("Dropping chat message over the rate limit")
+		return nil
+	}
+	if payload.Avatar != nil && len(payload.Avatar) <= maxChatAvatarBytes {
+		cr.avatars[from.ID] = payload.Avatar
+	}
+	if payload.Character != "" {
+		cr.characters[from.ID] = payload.Character
+	}
+	character := cr.characters[from.ID]
+	cr.mu.Unlock()
+
+	cr.record(ChatRelayLine{PeerID: from.ID, Character: character, Text: text, At: now, Remote: true})
+	return nil
+}
+
+// record appends a line to the peer's history and hands it to the UI
+func (cr *ChatRelay) record(line ChatRelayLine) {
+	cr.mu.Lock()
+	lines := append(cr.history[line.PeerID], line)
+	if len(lines) > chatRelayHistory {
+		lines = lines[len(lines)-chatRelayHistory:]
+	}
+	cr.history[line.PeerID] = lines
+	handler := cr.onLine
+	cr.mu.Unlock()
+
+	if handler != nil {
+		handler(line)
+	}
+}
+
+// History returns a copy of the conversation with a peer, oldest first
+func (cr *ChatRelay) History(peerID string) []ChatRelayLine {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return append([]ChatRelayLine(nil), cr.history[peerID]...)
+}
+
+// PeerAvatar returns the avatar a peer sent, nil if none yet
+func (cr *ChatRelay) PeerAvatar(peerID string) []byte {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.avatars[peerID]
+}
+
+// PeerCharacter returns the character name a peer chats as, "" if unknown
+func (cr *ChatRelay) PeerCharacter(peerID string) string {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.characters[peerID]
+}
+
+// chatRateLimit allows at most limit events per peer within window
+type chatRateLimit struct {
+	limit  int
+	window time.Duration
+	events map[string][]time.Time
+}
+
+func newChatRateLimit(limit int, window time.Duration) *chatRateLimit {
+	return &chatRateLimit{limit: limit, window: window, events: make(map[string][]time.Time)}
+}
+
+// allow reports whether peerID may have another event at now and, if so,
+// records it. Callers serialize access.
+func (rl *chatRateLimit) allow(peerID string, now time.Time) bool {
+	recent := rl.events[peerID][:0]
+	for _, at := range rl.events[peerID] {
+		if now.Sub(at) < rl.window {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= rl.limit {
+		rl.events[peerID] = recent
+		return false
+	}
+	rl.events[peerID] = append(recent, now)
+	return true
+}
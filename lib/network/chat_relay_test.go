@@ -0,0 +1,108 @@
+package network
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChatRelaySendsBetweenPeers(t *testing.T) {
+	a, b := newTransportPair()
+	alice := NewChatRelay(a, "Pixel")
+	bob := NewChatRelay(b, "Luna")
+	if err := alice.SetAvatar([]byte("png")); err != nil {
+		t.Fatal(err)
+	}
+
+	var received []ChatRelayLine
+	bob.SetLineHandler(func(line ChatRelayLine) { received = append(received, line) })
+
+	if err := alice.Send("peer-b", "  hi there  "); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(received) != 1 || received[0].Text != "hi there" || received[0].Character != "Pixel" || !received[0].Remote {
+		t.Fatalf("Expected the line from Pixel, got %+v", received)
+	}
+	if string(bob.PeerAvatar("peer-a")) != "png" || bob.PeerCharacter("peer-a") != "Pixel" {
+		t.Error("Expected the sender's avatar and character to be remembered")
+	}
+
+	// Each side keeps the conversation under the other peer's ID
+	if history := alice.History("peer-b"); len(history) != 1 || history[0].Remote {
+		t.Errorf("Expected our own line in the history, got %+v", history)
+	}
+	if history := bob.History("peer-a"); len(history) != 1 {
+		t.Errorf("Expected the received line in the history, got %+v", history)
+	}
+}
+
+func TestChatRelaySendsAvatarOnce(t *testing.T) {
+	a, b := newTransportPair()
+	alice := NewChatRelay(a, "Pixel")
+	alice.SetAvatar([]byte("first"))
+
+	var avatars []string
+	b.handlers[MessageTypeChatRelay] = func(msg Message, from *Peer) error {
+		avatars = append(avatars, string(msg.Payload))
+		return nil
+	}
+
+	alice.Send("peer-b", "one")
+	alice.Send("peer-b", "two")
+	alice.SetAvatar([]byte("second"))
+	alice.Send("peer-b", "three")
+
+	if len(avatars) != 3 || !strings.Contains(avatars[0], `"avatar"`) || strings.Contains(avatars[1], `"avatar"`) || !strings.Contains(avatars[2], `"avatar"`) {
+		t.Errorf("Expected the avatar with the first message and after it changed, got %v", avatars)
+	}
+}
+
+func TestChatRelayRateLimits(t *testing.T) {
+	a, b := newTransportPair()
+	alice := NewChatRelay(a, "Pixel")
+	NewChatRelay(b, "Luna")
+
+	for i := 0; i < chatSendLimit; i++ {
+		if err := alice.Send("peer-b", "hello"); err != nil {
+			t.Fatalf("Message %d should be allowed: %v", i+1, err)
+		}
+	}
+	if err := alice.Send("peer-b", "hello"); !errors.Is(err, ErrChatRateLimited) {
+		t.Errorf("Expected the rate limit, got %v", err)
+	}
+
+	// A peer flooding us is cut off at the receive limit
+	bob := NewChatRelay(b, "Luna")
+	for i := 0; i < chatReceiveLimit+5; i++ {
+		bob.handleMessage(Message{Type: MessageTypeChatRelay, Payload: []byte(`{"text":"spam"}`)}, &Peer{ID: "peer-a"})
+	}
+	if got := len(bob.History("peer-a")); got != chatReceiveLimit {
+		t.Errorf("Expected %d lines kept, got %d", chatReceiveLimit, got)
+	}
+
+	// The window slides
+	limit := newChatRateLimit(1, time.Second)
+	now := time.Now()
+	if !limit.allow("p", now) || limit.allow("p", now) || !limit.allow("p", now.Add(time.Second)) {
+		t.Error("Expected one event per second")
+	}
+}
+
+func TestChatRelayRejectsBadMessages(t *testing.T) {
+	a, _ := newTransportPair()
+	relay := NewChatRelay(a, "Pixel")
+
+	if err := relay.Send("", "hi"); err == nil {
+		t.Error("Expected an error without a peer")
+	}
+	if err := relay.Send("peer-b", strings.Repeat("x", MaxChatRelayLength+1)); err == nil {
+		t.Error("Expected an error for a long message")
+	}
+	if err := relay.SetAvatar(make([]byte, maxChatAvatarBytes+1)); err == nil {
+		t.Error("Expected an error for a large avatar")
+	}
+	if err := relay.handleMessage(Message{Payload: []byte(`{"text":"   "}`)}, &Peer{ID: "peer-b"}); err == nil {
+		t.Error("Expected an error for an empty message")
+	}
+}
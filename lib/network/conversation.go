@@ -0,0 +1,407 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// MessageTypeConversation carries character-to-character chat lines
+const MessageTypeConversation MessageType = "conversation"
+
+// Conversation payload kinds
+const (
+	ConversationOpen  = "open"  // First line, asks the peer to join
+	ConversationLine  = "line"  // Follow-up line
+	ConversationClose = "close" // Decline or abort
+)
+
+// ConversationConfig controls how often idle characters talk to each other.
+// Lives in the character card under multiplayer.peerChat.
+type ConversationConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Chattiness    float64  `json:"chattiness"`              // Chance per check to start talking (0-1)
+	Topics        []string `json:"topics,omitempty"`        // Picked at random for each conversation
+	MaxTurns      int      `json:"maxTurns,omitempty"`      // Lines per conversation, both sides (default 6)
+	TurnDelay     int      `json:"turnDelay,omitempty"`     // Seconds between lines (default 4, negative for none)
+	IdleSeconds   int      `json:"idleSeconds,omitempty"`   // Idle time before chatting (default 120)
+	CheckInterval int      `json:"checkInterval,omitempty"` // Seconds between chattiness rolls (default 60)
+}
+
+// ConversationPayload is the wire format of a conversation message
+type ConversationPayload struct {
+	SessionID string `json:"sessionId"`
+	Kind      string `json:"kind"`
+	Speaker   string `json:"speaker"`
+	Topic     string `json:"topic,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Turn      int    `json:"turn"`
+}
+
+// ConversationSession is an ongoing chat with one peer
+type ConversationSession struct {
+	ID           string
+	PeerID       string
+	Topic        string
+	Turn         int
+	StartedAt    time.Time
+	LastActivity time.Time
+}
+
+// ConversationLineEvent is one spoken line, local or remote, for UI rendering
+type ConversationLineEvent struct {
+	SessionID string
+	PeerID    string
+	Speaker   string
+	Text      string
+	Turn      int
+	Remote    bool
+}
+
+// ConversationTransport is the subset of the network manager used for conversations
+type ConversationTransport interface {
+	GetPeers() []Peer
+	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+}
+
+// ConversationSpeaker generates the local character's lines
+type ConversationSpeaker interface {
+	GetName() string
+	IsIdleFor(d time.Duration) bool
+	GeneratePeerChatLine(topic, heard string, turn int) string
+}
+
+// ConversationManager lets idle networked characters chat with each other.
+// Only one conversation runs at a time; lines alternate between the peers.
+type ConversationManager struct {
+	mu        sync.Mutex
+	transport ConversationTransport
+	speaker   ConversationSpeaker
+	config    ConversationConfig
+	session   *ConversationSession
+	onLine    func(ConversationLineEvent)
+	rng       *rand.Rand
+	stopCh    chan struct{}
+	running   bool
+}
+
+// NewConversationManager creates a manager and registers its message handler.
+// Zero config values are replaced with defaults.
+func NewConversationManager(transport ConversationTransport, speaker ConversationSpeaker, config ConversationConfig) *ConversationManager {
+	if config.MaxTurns <= 0 {
+		config.MaxTurns = 6
+	}
+	if config.TurnDelay < 0 {
+		config.TurnDelay = 0
+	} else if config.TurnDelay == 0 {
+		config.TurnDelay = 4
+	}
+	if config.IdleSeconds <= 0 {
+		config.IdleSeconds = 120
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = 60
+	}
+
+	cm := &ConversationManager{
+		transport: transport,
+		speaker:   speaker,
+		config:    config,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	if transport != nil {
+		transport.RegisterMessageHandler(MessageTypeConversation, cm.handleMessage)
+	}
+	return cm
+}
+
+// SetLineHandler sets the callback that renders each line as a speech bubble
+func (cm *ConversationManager) SetLineHandler(handler func(ConversationLineEvent)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.onLine = handler
+}
+
+// Start begins periodic chattiness checks
+func (cm *ConversationManager) Start() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.running {
+		return
+	}
+	cm.running = true
+	cm.stopCh = make(chan struct{})
+	go cm.loop(cm.stopCh, time.Duration(cm.config.CheckInterval)*time.Second)
+}
+
+// Stop ends periodic checks. A running conversation is dropped.
+func (cm *ConversationManager) Stop() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !cm.running {
+		return
+	}
+	cm.running = false
+	close(cm.stopCh)
+	cm.session = nil
+}
+
+// loop rolls for a new conversation every interval
+func (cm *ConversationManager) loop(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			cm.tick(now)
+		}
+	}
+}
+
+// tick expires stale sessions and maybe starts a new conversation
+func (cm *ConversationManager) tick(now time.Time) {
+	cm.mu.Lock()
+	if cm.session != nil && now.Sub(cm.session.LastActivity) > cm.sessionTimeout() {
+		cm.session = nil
+	}
+	busy := cm.session != nil
+	roll := cm.rng.Float64()
+	cm.mu.Unlock()
+
+	if busy || roll >= cm.config.Chattiness {
+		return
+	}
+	if !cm.speaker.IsIdleFor(time.Duration(cm.config.IdleSeconds) * time.Second) {
+		return
+	}
+
+	peers := cm.transport.GetPeers()
+	if len(peers) == 0 {
+		return
+	}
+
+	cm.mu.Lock()
+	peer := peers[cm.rng.Intn(len(peers))]
+	topic := cm.pickTopic()
+	cm.mu.Unlock()
+
+	// A failed send just means the peer dropped; the next tick tries again
+	_ = cm.StartConversation(peer.ID, topic)
+}
+
+// sessionTimeout is how long a conversation may go quiet before it is dropped
+func (cm *ConversationManager) sessionTimeout() time.Duration {
+	return time.Duration(cm.config.TurnDelay*3+30) * time.Second
+}
+
+// pickTopic returns a random configured topic. Must be called with cm.mu held.
+func (cm *ConversationManager) pickTopic() string {
+	if len(cm.config.Topics) == 0 {
+		return ""
+	}
+	return cm.config.Topics[cm.rng.Intn(len(cm.config.Topics))]
+}
+
+// StartConversation opens a conversation with a peer and says the first line
+func (cm *ConversationManager) StartConversation(peerID, topic string) error {
+	now := time.Now()
+
+	cm.mu.Lock()
+	if cm.session != nil {
+		busyWith := cm.session.PeerID
+		cm.mu.Unlock()
+		return fmt.Errorf("already talking to %s", busyWith)
+	}
+	session := &ConversationSession{
+		ID:           fmt.Sprintf("conv_%d", now.UnixNano()),
+		PeerID:       peerID,
+		Topic:        topic,
+		Turn:         1,
+		StartedAt:    now,
+		LastActivity: now,
+	}
+	cm.session = session
+	cm.mu.Unlock()
+
+	text := cm.speaker.GeneratePeerChatLine(topic, "", 1)
+	cm.emit(ConversationLineEvent{SessionID: session.ID, PeerID: peerID, Speaker: cm.speaker.GetName(), Text: text, Turn: 1})
+
+	if err := cm.send(peerID, ConversationPayload{
+		SessionID: session.ID,
+		Kind:      ConversationOpen,
+		Topic:     topic,
+		Text:      text,
+		Turn:      1,
+	}); err != nil {
+		cm.endSession(session.ID)
+		return err
+	}
+	return nil
+}
+
+// GetSession returns a copy of the running conversation, or nil
+func (cm *ConversationManager) GetSession() *ConversationSession {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.session == nil {
+		return nil
+	}
+	session := *cm.session
+	return &session
+}
+
+// handleMessage processes conversation messages from peers
+func (cm *ConversationManager) handleMessage(msg Message, from *Peer) error {
+	var payload ConversationPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal conversation message: %w", err)
+	}
+
+	peerID := msg.From
+	if from != nil {
+		peerID = from.ID
+	}
+
+	switch payload.Kind {
+	case ConversationOpen:
+		return cm.handleOpen(peerID, payload)
+	case ConversationLine:
+		return cm.handleLine(peerID, payload)
+	case ConversationClose:
+		cm.endSession(payload.SessionID)
+		return nil
+	default:
+		return fmt.Errorf("unknown conversation message kind: %s", payload.Kind)
+	}
+}
+
+// handleOpen joins a conversation if idle, otherwise politely declines
+func (cm *ConversationManager) handleOpen(peerID string, payload ConversationPayload) error {
+	idle := cm.speaker.IsIdleFor(time.Duration(cm.config.IdleSeconds) * time.Second)
+
+	cm.mu.Lock()
+	if cm.session != nil || !idle {
+		cm.mu.Unlock()
+		return cm.send(peerID, ConversationPayload{SessionID: payload.SessionID, Kind: ConversationClose})
+	}
+	now := time.Now()
+	cm.session = &ConversationSession{
+		ID:           payload.SessionID,
+		PeerID:       peerID,
+		Topic:        payload.Topic,
+		Turn:         payload.Turn,
+		StartedAt:    now,
+		LastActivity: now,
+	}
+	cm.mu.Unlock()
+
+	cm.emit(ConversationLineEvent{SessionID: payload.SessionID, PeerID: peerID, Speaker: payload.Speaker, Text: payload.Text, Turn: payload.Turn, Remote: true})
+	cm.scheduleReply(payload.SessionID, payload.Text, payload.Turn+1)
+	return nil
+}
+
+// handleLine shows the peer's line and answers until the turn limit
+func (cm *ConversationManager) handleLine(peerID string, payload ConversationPayload) error {
+	cm.mu.Lock()
+	if cm.session == nil || cm.session.ID != payload.SessionID || cm.session.PeerID != peerID {
+		cm.mu.Unlock()
+		return nil // Stale line from an expired conversation
+	}
+	cm.session.Turn = payload.Turn
+	cm.session.LastActivity = time.Now()
+	cm.mu.Unlock()
+
+	cm.emit(ConversationLineEvent{SessionID: payload.SessionID, PeerID: peerID, Speaker: payload.Speaker, Text: payload.Text, Turn: payload.Turn, Remote: true})
+
+	if payload.Turn >= cm.config.MaxTurns {
+		cm.endSession(payload.SessionID)
+		return nil
+	}
+	cm.scheduleReply(payload.SessionID, payload.Text, payload.Turn+1)
+	return nil
+}
+
+// scheduleReply answers after the turn delay so the bubbles alternate visibly
+func (cm *ConversationManager) scheduleReply(sessionID, heard string, turn int) {
+	delay := time.Duration(cm.config.TurnDelay) * time.Second
+	if delay == 0 {
+		cm.reply(sessionID, heard, turn)
+		return
+	}
+	time.AfterFunc(delay, func() {
+		cm.reply(sessionID, heard, turn)
+	})
+}
+
+// reply generates and sends the local character's next line
+func (cm *ConversationManager) reply(sessionID, heard string, turn int) {
+	cm.mu.Lock()
+	if cm.session == nil || cm.session.ID != sessionID {
+		cm.mu.Unlock()
+		return
+	}
+	peerID, topic := cm.session.PeerID, cm.session.Topic
+	cm.mu.Unlock()
+
+	text := cm.speaker.GeneratePeerChatLine(topic, heard, turn)
+
+	cm.mu.Lock()
+	if cm.session == nil || cm.session.ID != sessionID {
+		cm.mu.Unlock()
+		return // Closed while the backend was thinking
+	}
+	cm.session.Turn = turn
+	cm.session.LastActivity = time.Now()
+	cm.mu.Unlock()
+
+	cm.emit(ConversationLineEvent{SessionID: sessionID, PeerID: peerID, Speaker: cm.speaker.GetName(), Text: text, Turn: turn})
+	if err := cm.send(peerID, ConversationPayload{SessionID: sessionID, Kind: ConversationLine, Topic: topic, Text: text, Turn: turn}); err != nil {
+		cm.endSession(sessionID)
+		return
+	}
+
+	if turn >= cm.config.MaxTurns {
+		cm.endSession(sessionID)
+	}
+}
+
+// endSession clears the conversation if it is still the current one
+func (cm *ConversationManager) endSession(sessionID string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.session != nil && cm.session.ID == sessionID {
+		cm.session = nil
+	}
+}
+
+// send marshals and sends a payload to one peer
+func (cm *ConversationManager) send(peerID string, payload ConversationPayload) error {
+	payload.Speaker = cm.speaker.GetName()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversation message: %w", err)
+	}
+	return cm.transport.SendMessage(MessageTypeConversation, data, peerID)
+}
+
+// emit hands a line to the UI callback
+func (cm *ConversationManager) emit(line ConversationLineEvent) {
+	cm.mu.Lock()
+	handler := cm.onLine
+	cm.mu.Unlock()
+
+	if handler != nil && line.Text != "" {
+		handler(line)
+	}
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pairTransport delivers messages straight to a linked transport's handler
+type pairTransport struct {
+	id       string
+	other    *pairTransport
+	handlers map[MessageType]MessageHandler
+	offline  bool
+
+	capabilities []string // Advertised to the other side
+}
+
+func newTransportPair() (*pairTransport, *pairTransport) {
+	a := &pairTransport{id: "peer-a", handlers: make(map[MessageType]MessageHandler), capabilities: wireCapabilities(nil)}
+	b := &pairTransport{id: "peer-b", handlers: make(map[MessageType]MessageHandler), capabilities: wireCapabilities(nil)}
+	a.other, b.other = b, a
+	return a, b
+}
+
+func (p *pairTransport) GetPeers() []Peer {
+	return []Peer{{ID: p.other.id, Capabilities: p.other.capabilities}}
+}
+
+func (p *pairTransport) SendMessage(msgType MessageType, payload []byte, targetPeerID string) error {
+	if p.offline {
+		return fmt.Errorf("peer offline")
+	}
+	handler := p.other.handlers[msgType]
+	if handler == nil {
+		return nil
+	}
+	return handler(Message{Type: msgType, From: p.id, To: targetPeerID, Payload: payload}, &Peer{ID: p.id})
+}
+
+func (p *pairTransport) RegisterMessageHandler(msgType MessageType, handler MessageHandler) {
+	p.handlers[msgType] = handler
+}
+
+type testSpeaker struct {
+	name string
+	idle bool
+}
+
+func (s *testSpeaker) GetName() string                { return s.name }
+func (s *testSpeaker) IsIdleFor(d time.Duration) bool { return s.idle }
+func (s *testSpeaker) GeneratePeerChatLine(topic, heard string, turn int) string {
+	return fmt.Sprintf("%s:%d:%s", s.name, turn, topic)
+}
+
+type lineLog struct {
+	mu    sync.Mutex
+	lines []ConversationLineEvent
+}
+
+func (l *lineLog) add(line ConversationLineEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+}
+
+func TestConversationAlternatesLines(t *testing.T) {
+	ta, tb := newTransportPair()
+	config := ConversationConfig{Enabled: true, MaxTurns: 4, TurnDelay: -1}
+	alice := NewConversationManager(ta, &testSpeaker{name: "Alice", idle: true}, config)
+	bob := NewConversationManager(tb, &testSpeaker{name: "Bob", idle: true}, config)
+
+	var aliceLog, bobLog lineLog
+	alice.SetLineHandler(aliceLog.add)
+	bob.SetLineHandler(bobLog.add)
+
+	if err := alice.StartConversation("peer-b", "weather"); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+
+	expected := []string{"Alice:1:weather", "Bob:2:weather", "Alice:3:weather", "Bob:4:weather"}
+	if len(aliceLog.lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %+v", len(expected), aliceLog.lines)
+	}
+	for i, line := range aliceLog.lines {
+		if line.Text != expected[i] {
+			t.Errorf("Line %d = %q, want %q", i, line.Text, expected[i])
+		}
+		if line.Remote != (i%2 == 1) {
+			t.Errorf("Line %d remote = %v", i, line.Remote)
+		}
+	}
+	if len(bobLog.lines) != len(expected) || bobLog.lines[0].Speaker != "Alice" || !bobLog.lines[0].Remote {
+		t.Errorf("Bob should see the same conversation, got %+v", bobLog.lines)
+	}
+
+	if alice.GetSession() != nil || bob.GetSession() != nil {
+		t.Error("Sessions should end at the turn limit")
+	}
+}
+
+func TestConversationDeclinedWhenBusy(t *testing.T) {
+	ta, tb := newTransportPair()
+	config := ConversationConfig{Enabled: true, TurnDelay: -1}
+	alice := NewConversationManager(ta, &testSpeaker{name: "Alice", idle: true}, config)
+	bob := NewConversationManager(tb, &testSpeaker{name: "Bob", idle: false}, config)
+
+	var bobLog lineLog
+	bob.SetLineHandler(bobLog.add)
+
+	if err := alice.StartConversation("peer-b", ""); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+	if alice.GetSession() != nil {
+		t.Error("Busy peer should close the conversation")
+	}
+	if len(bobLog.lines) != 0 {
+		t.Errorf("Busy peer should not show lines, got %+v", bobLog.lines)
+	}
+}
+
+func TestConversationTick(t *testing.T) {
+	ta, tb := newTransportPair()
+	alice := NewConversationManager(ta, &testSpeaker{name: "Alice", idle: true}, ConversationConfig{Chattiness: 1, Topics: []string{"games"}})
+	// Bob answers after the default delay, so Alice's session stays open during the test
+	NewConversationManager(tb, &testSpeaker{name: "Bob", idle: true}, ConversationConfig{})
+
+	now := time.Now()
+	alice.tick(now)
+	session := alice.GetSession()
+	if session == nil || session.Topic != "games" || session.PeerID != "peer-b" {
+		t.Fatalf("Chattiness 1 should start a conversation, got %+v", session)
+	}
+
+	alice.config.Chattiness = 0
+	alice.tick(now.Add(time.Hour))
+	if alice.GetSession() != nil {
+		t.Error("Stale session should expire, and chattiness 0 should not start another")
+	}
+
+	alice.config.Chattiness = 1
+	ta.offline = true
+	alice.tick(now)
+	if alice.GetSession() != nil {
+		t.Error("Failed send should not leave a session behind")
+	}
+}
@@ -0,0 +1,591 @@
+package network
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MessageTypeFileTransfer carries a file offered by one user to another,
+// such as a character pack. Nothing is sent until the receiving user
+// accepts, and only blocking stops offers.
+const MessageTypeFileTransfer MessageType = "file_transfer"
+
+// File transfer message kinds
+const (
+	TransferKindOffer    = "offer"    // Sender describes the file
+	TransferKindRequest  = "request"  // Receiver asks for the chunk at an offset; the first one accepts
+	TransferKindChunk    = "chunk"    // Sender returns the requested chunk
+	TransferKindDecline  = "decline"  // Receiver doesn't want the file
+	TransferKindComplete = "complete" // Receiver has the whole file and it checked out
+	TransferKindCancel   = "cancel"   // Either side gives up
+)
+
+const (
+	// FileChunkSize is how much of the file travels per message. The
+	// receiver asks for each chunk after writing the last, so a slow link
+	// only ever has one in flight.
+	FileChunkSize = 64 << 10
+	// MaxTransferSize is the largest file a peer may offer
+	MaxTransferSize = 512 << 20
+	// partialSuffix marks a download that hasn't finished yet
+	partialSuffix = ".part"
+)
+
+// A peer may offer a few files in quick succession, not flood the user with prompts
+const (
+	transferOfferLimit  = 3
+	transferOfferWindow = time.Minute
+)
+
+// ErrTransferUnknown is returned for a transfer ID that isn't pending
+var ErrTransferUnknown = errors.New("no such file transfer")
+
+// FileTransferPayload is the wire format of every file transfer message;
+// Kind says which fields are set
+type FileTransferPayload struct {
+	Kind       string `json:"kind"`
+	TransferID string `json:"transferId"`
+	Name       string `json:"name,omitempty"`   // With offer: the file's base name
+	Size       int64  `json:"size,omitempty"`   // With offer
+	SHA256     string `json:"sha256,omitempty"` // With offer: hex checksum of the whole file
+	Offset     int64  `json:"offset,omitempty"` // With request and chunk
+	Data       []byte `json:"data,omitempty"`   // With chunk
+	Reason     string `json:"reason,omitempty"` // With cancel
+}
+
+// FileOffer is a file a peer wants to send us, waiting for the user
+type FileOffer struct {
+	PeerID     string
+	TransferID string
+	Name       string
+	Size       int64
+	SHA256     string
+}
+
+// FileTransferProgress reports a download or upload. Done is set once with
+// Path on success or Err on failure.
+type FileTransferProgress struct {
+	TransferID string
+	PeerID     string
+	Name       string
+	Received   int64
+	Size       int64
+	Incoming   bool
+	Done       bool
+	Path       string // Where the finished download was saved
+	Err        error
+}
+
+// FileTransferTransport is the subset of the network manager used for file transfers
+type FileTransferTransport interface {
+	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+}
+
+// outgoingTransfer is a file we offered
+type outgoingTransfer struct {
+	peerID string
+	path   string
+	name   string
+	size   int64
+}
+
+// incomingTransfer is a file offered to us
+type incomingTransfer struct {
+	offer    FileOffer
+	accepted bool
+	received int64
+}
+
+// FileTransfers sends and receives files between peers in checksummed
+// chunks. Downloads land in dir; a partial download is kept next to them
+// under the file's checksum, so accepting the same file again, even after a
+// restart, picks up where it stopped.
+type FileTransfers struct {
+	mu         sync.Mutex
+	transport  FileTransferTransport
+	dir        string
+	outgoing   map[string]*outgoingTransfer
+	incoming   map[string]*incomingTransfer
+	offers     *chatRateLimit
+	onOffer    func(FileOffer)
+	onProgress func(FileTransferProgress)
+}
+
+// NewFileTransfers creates transfers saving downloads to dir and registers
+// the message handler
+func NewFileTransfers(transport FileTransferTransport, dir string) *FileTransfers {
+	ft := &FileTransfers{
+		transport: transport,
+		dir:       dir,
+		outgoing:  make(map[string]*outgoingTransfer),
+		incoming:  make(map[string]*incomingTransfer),
+		offers:    newChatRateLimit(transferOfferLimit, transferOfferWindow),
+	}
+
+	if transport != nil {
+		transport.RegisterMessageHandler(MessageTypeFileTransfer, ft.handleMessage)
+	}
+	return ft
+}
+
+// SetOfferHandler sets the callback asking the user about an offered file.
+// It must answer with Accept or Decline; without a handler offers are declined.
+func (ft *FileTransfers) SetOfferHandler(handler func(FileOffer)) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.onOffer = handler
+}
+
+// SetProgressHandler sets the callback told about progress and completion
+func (ft *FileTransfers) SetProgressHandler(handler func(FileTransferProgress)) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.onProgress = handler
+}
+
+// Offer tells a peer we'd like to send them the file at path, returning the
+// transfer ID. The file is read when the peer asks for it, so it must stay
+// in place until the transfer completes.
+func (ft *FileTransfers) Offer(peerID, path string) (string, error) {
+	if peerID == "" {
+		return "", fmt.Errorf("no peer selected")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.Size() == 0 || info.Size() > MaxTransferSize {
+		return "", fmt.Errorf("%s is %d bytes, between 1 and %d can be sent", path, info.Size(), MaxTransferSize)
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return "", err
+	}
+	id, err := newTransferID()
+	if err != nil {
+		return "", err
+	}
+
+	out := &outgoingTransfer{peerID: peerID, path: path, name: filepath.Base(path), size: info.Size()}
+	ft.mu.Lock()
+	ft.outgoing[id] = out
+	ft.mu.Unlock()
+
+	offer := FileTransferPayload{Kind: TransferKindOffer, TransferID: id, Name: out.name, Size: out.size, SHA256: sum}
+	if err := ft.send(peerID, offer); err != nil {
+		ft.mu.Lock()
+		delete(ft.outgoing, id)
+		ft.mu.Unlock()
+		return "", fmt.Errorf("failed to send file offer: %w", err)
+	}
+	return id, nil
+}
+
+// Accept starts or resumes downloading an offered file
+func (ft *FileTransfers) Accept(transferID string) error {
+	ft.mu.Lock()
+	in := ft.incoming[transferID]
+	if in == nil {
+		ft.mu.Unlock()
+		return ErrTransferUnknown
+	}
+	in.accepted = true
+	offer := in.offer
+	ft.mu.Unlock()
+
+	if err := os.MkdirAll(ft.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	// Anything left from an earlier attempt at the same file is kept
+	var offset int64
+	if info, err := os.Stat(ft.partialPath(offer)); err == nil {
+		switch {
+		case info.Size() < offer.Size:
+			offset = info.Size()
+		case info.Size() == offer.Size:
+			return ft.finish(offer) // Stopped just before it was moved into place
+		default:
+			os.Remove(ft.partialPath(offer)) // Too big to be this file, start over
+		}
+	}
+
+	ft.mu.Lock()
+	in.received = offset
+	ft.mu.Unlock()
+	return ft.request(offer, offset)
+}
+
+// Resume asks again for the next chunk of an accepted download, e.g. after
+// the peer reconnected
+func (ft *FileTransfers) Resume(transferID string) error {
+	ft.mu.Lock()
+	in := ft.incoming[transferID]
+	if in == nil || !in.accepted {
+		ft.mu.Unlock()
+		return ErrTransferUnknown
+	}
+	offer, offset := in.offer, in.received
+	ft.mu.Unlock()
+	return ft.request(offer, offset)
+}
+
+// Decline turns down an offered file
+func (ft *FileTransfers) Decline(transferID string) error {
+	ft.mu.Lock()
+	in := ft.incoming[transferID]
+	delete(ft.incoming, transferID)
+	ft.mu.Unlock()
+	if in == nil {
+		return ErrTransferUnknown
+	}
+	return ft.send(in.offer.PeerID, FileTransferPayload{Kind: TransferKindDecline, TransferID: transferID})
+}
+
+// Cancel stops a transfer in either direction. A partial download is kept
+// so the same file can resume later.
+func (ft *FileTransfers) Cancel(transferID string) error {
+	ft.mu.Lock()
+	in := ft.incoming[transferID]
+	out := ft.outgoing[transferID]
+	delete(ft.incoming, transferID)
+	delete(ft.outgoing, transferID)
+	ft.mu.Unlock()
+
+	switch {
+	case in != nil:
+		return ft.send(in.offer.PeerID, FileTransferPayload{Kind: TransferKindCancel, TransferID: transferID})
+	case out != nil:
+		return ft.send(out.peerID, FileTransferPayload{Kind: TransferKindCancel, TransferID: transferID})
+	}
+	return ErrTransferUnknown
+}
+
+// Pending returns downloads offered to us or under way, e.g. to resume them
+func (ft *FileTransfers) Pending() []FileOffer {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	offers := make([]FileOffer, 0, len(ft.incoming))
+	for _, in := range ft.incoming {
+		offers = append(offers, in.offer)
+	}
+	return offers
+}
+
+// handleMessage dispatches a file transfer message by kind. Every kind but
+// an offer must come from the peer on the other end of that transfer.
+func (ft *FileTransfers) handleMessage(msg Message, from *Peer) error {
+	if from == nil {
+		return fmt.Errorf("file transfer message without sender")
+	}
+
+	var payload FileTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse file transfer message: %w", err)
+	}
+
+	switch payload.Kind {
+	case TransferKindOffer:
+		return ft.handleOffer(from.ID, payload)
+	case TransferKindRequest:
+		return ft.handleRequest(from.ID, payload)
+	case TransferKindChunk:
+		return ft.handleChunk(from.ID, payload)
+	case TransferKindDecline, TransferKindComplete, TransferKindCancel:
+		ft.handleEnd(from.ID, payload)
+	default:
+		return fmt.Errorf("unknown file transfer kind %q", payload.Kind)
+	}
+	return nil
+}
+
+// handleOffer asks the user about a file, declining offers that are too
+// big, badly named or come too fast
+func (ft *FileTransfers) handleOffer(peerID string, payload FileTransferPayload) error {
+	name := filepath.Base(payload.Name)
+	if payload.TransferID == "" || name != payload.Name || name == "." || name == ".." || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("file offer from %s has an invalid name %q", peerID, payload.Name)
+	}
+	if payload.Size <= 0 || payload.Size > MaxTransferSize {
+		return fmt.Errorf("file offer from %s is %d bytes, at most %d allowed", peerID, payload.Size, MaxTransferSize)
+	}
+	if sum, err := hex.DecodeString(payload.SHA256); err != nil || len(sum) != sha256.Size {
+		return fmt.Errorf("file offer from %s has an invalid checksum", peerID)
+	}
+
+	offer := FileOffer{PeerID: peerID, TransferID: payload.TransferID, Name: name, Size: payload.Size, SHA256: payload.SHA256}
+	ft.mu.Lock()
+	if !ft.offers.allow(peerID, time.Now()) {
+		ft.mu.Unlock()
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": peerID,
+		}).Debug("Dropping file offer over the rate limit")
+		return nil
+	}
+	handler := ft.onOffer
+	if handler != nil {
+		ft.incoming[offer.TransferID] = &incomingTransfer{offer: offer}
+	}
+	ft.mu.Unlock()
+
+	if handler == nil {
+		return ft.send(peerID, FileTransferPayload{Kind: TransferKindDecline, TransferID: offer.TransferID})
+	}
+	handler(offer)
+	return nil
+}
+
+// handleRequest sends the chunk a receiver asked for
+func (ft *FileTransfers) handleRequest(peerID string, payload FileTransferPayload) error {
+	ft.mu.Lock()
+	out := ft.outgoing[payload.TransferID]
+	ft.mu.Unlock()
+	if out == nil || out.peerID != peerID {
+		return ErrTransferUnknown
+	}
+	if payload.Offset < 0 || payload.Offset >= out.size {
+		return fmt.Errorf("chunk offset %d outside %s", payload.Offset, out.name)
+	}
+
+	data, err := readChunk(out.path, payload.Offset)
+	if err != nil {
+		_ = ft.Cancel(payload.TransferID)
+		ft.report(FileTransferProgress{TransferID: payload.TransferID, PeerID: peerID, Name: out.name, Size: out.size, Done: true, Err: err})
+		return err
+	}
+	ft.report(FileTransferProgress{TransferID: payload.TransferID, PeerID: peerID, Name: out.name, Received: payload.Offset + int64(len(data)), Size: out.size})
+	chunk := FileTransferPayload{Kind: TransferKindChunk, TransferID: payload.TransferID, Offset: payload.Offset, Data: data}
+	if err := ft.send(peerID, chunk); err != nil {
+		return fmt.Errorf("failed to send chunk: %w", err)
+	}
+	return nil
+}
+
+// handleChunk appends a chunk to the partial download and asks for the
+// next one, or checks and keeps the finished file
+func (ft *FileTransfers) handleChunk(peerID string, payload FileTransferPayload) error {
+	ft.mu.Lock()
+	in := ft.incoming[payload.TransferID]
+	if in == nil || !in.accepted || in.offer.PeerID != peerID {
+		ft.mu.Unlock()
+		return ErrTransferUnknown
+	}
+	offer, received := in.offer, in.received
+	ft.mu.Unlock()
+
+	if payload.Offset != received || len(payload.Data) == 0 || len(payload.Data) > FileChunkSize || received+int64(len(payload.Data)) > offer.Size {
+		return fmt.Errorf("unexpected chunk at %d for %s, have %d bytes", payload.Offset, offer.Name, received)
+	}
+	if err := appendChunk(ft.partialPath(offer), payload.Data); err != nil {
+		ft.fail(offer, received, err)
+		return err
+	}
+	received += int64(len(payload.Data))
+
+	ft.mu.Lock()
+	in.received = received
+	ft.mu.Unlock()
+
+	if received < offer.Size {
+		ft.report(FileTransferProgress{TransferID: offer.TransferID, PeerID: peerID, Name: offer.Name, Received: received, Size: offer.Size, Incoming: true})
+		return ft.request(offer, received)
+	}
+	return ft.finish(offer)
+}
+
+// finish verifies a complete download and moves it into place
+func (ft *FileTransfers) finish(offer FileOffer) error {
+	partial := ft.partialPath(offer)
+	sum, err := fileSHA256(partial)
+	if err == nil && sum != offer.SHA256 {
+		os.Remove(partial) // Corrupt, no point resuming it
+		err = fmt.Errorf("checksum mismatch for %s", offer.Name)
+	}
+	var dest string
+	if err == nil {
+		dest = availablePath(filepath.Join(ft.dir, offer.Name))
+		err = os.Rename(partial, dest)
+	}
+	if err != nil {
+		ft.fail(offer, offer.Size, err)
+		return err
+	}
+
+	ft.mu.Lock()
+	delete(ft.incoming, offer.TransferID)
+	ft.mu.Unlock()
+	if err := ft.send(offer.PeerID, FileTransferPayload{Kind: TransferKindComplete, TransferID: offer.TransferID}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": offer.PeerID,
+			"error":  err.Error(),
+		}).Debug("Could not tell the sender the file arrived")
+	}
+	ft.report(FileTransferProgress{TransferID: offer.TransferID, PeerID: offer.PeerID, Name: offer.Name, Received: offer.Size, Size: offer.Size, Incoming: true, Done: true, Path: dest})
+	return nil
+}
+
+// fail gives up on a download and tells both users
+func (ft *FileTransfers) fail(offer FileOffer, received int64, err error) {
+	ft.mu.Lock()
+	delete(ft.incoming, offer.TransferID)
+	ft.mu.Unlock()
+	_ = ft.send(offer.PeerID, FileTransferPayload{Kind: TransferKindCancel, TransferID: offer.TransferID, Reason: err.Error()})
+	ft.report(FileTransferProgress{TransferID: offer.TransferID, PeerID: offer.PeerID, Name: offer.Name, Received: received, Size: offer.Size, Incoming: true, Done: true, Err: err})
+}
+
+// handleEnd closes a transfer the other side declined, finished or cancelled
+func (ft *FileTransfers) handleEnd(peerID string, payload FileTransferPayload) {
+	ft.mu.Lock()
+	out := ft.outgoing[payload.TransferID]
+	if out != nil && out.peerID != peerID {
+		out = nil
+	}
+	in := ft.incoming[payload.TransferID]
+	if in != nil && in.offer.PeerID != peerID {
+		in = nil
+	}
+	if out != nil {
+		delete(ft.outgoing, payload.TransferID)
+	}
+	if in != nil {
+		delete(ft.incoming, payload.TransferID)
+	}
+	ft.mu.Unlock()
+
+	progress := FileTransferProgress{TransferID: payload.TransferID, PeerID: peerID, Done: true}
+	switch {
+	case out != nil:
+		progress.Name, progress.Size = out.name, out.size
+		switch payload.Kind {
+		case TransferKindComplete:
+			progress.Received = out.size
+		case TransferKindDecline:
+			progress.Err = fmt.Errorf("%s was declined", out.name)
+		default:
+			progress.Err = fmt.Errorf("%s was cancelled: %s", out.name, payload.Reason)
+		}
+	case in != nil && payload.Kind == TransferKindCancel:
+		progress.Name, progress.Size, progress.Received, progress.Incoming = in.offer.Name, in.offer.Size, in.received, true
+		progress.Err = fmt.Errorf("%s was cancelled by the sender", in.offer.Name)
+	default:
+		return
+	}
+	ft.report(progress)
+}
+
+// request asks the sender for the chunk at offset
+func (ft *FileTransfers) request(offer FileOffer, offset int64) error {
+	return ft.send(offer.PeerID, FileTransferPayload{Kind: TransferKindRequest, TransferID: offer.TransferID, Offset: offset})
+}
+
+// partialPath is where a download collects until it is complete, named by
+// checksum so any offer of the same file resumes it
+func (ft *FileTransfers) partialPath(offer FileOffer) string {
+	return filepath.Join(ft.dir, "."+offer.SHA256[:16]+partialSuffix)
+}
+
+// send encodes and sends a file transfer message
+func (ft *FileTransfers) send(peerID string, payload FileTransferPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode file transfer message: %w", err)
+	}
+	return ft.transport.SendMessage(MessageTypeFileTransfer, data, peerID)
+}
+
+// report hands progress to the UI
+func (ft *FileTransfers) report(progress FileTransferProgress) {
+	ft.mu.Lock()
+	handler := ft.onProgress
+	ft.mu.Unlock()
+
+	if handler != nil {
+		handler(progress)
+	}
+}
+
+// newTransferID returns a random transfer ID
+func newTransferID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to create transfer ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// fileSHA256 returns the hex SHA-256 checksum of the file at path
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readChunk reads up to FileChunkSize bytes of the file at path from offset
+func readChunk(path string, offset int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	data := make([]byte, FileChunkSize)
+	n, err := f.ReadAt(data, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%s is shorter than offered", path)
+	}
+	return data[:n], nil
+}
+
+// appendChunk adds data to the end of the partial download at path
+func appendChunk(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open partial download: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write partial download: %w", err)
+	}
+	return f.Close()
+}
+
+// availablePath returns path, or path with a number added before the
+// extension when a file by that name already exists
+func availablePath(path string) string {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+	}
+}
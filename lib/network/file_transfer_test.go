@@ -0,0 +1,173 @@
+package network
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestFile writes size random bytes to a file named name in a new temp directory
+func newTestFile(t *testing.T, name string, size int) (string, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	rand.Read(data)
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+func TestFileTransferSendsInChunks(t *testing.T) {
+	a, b := newTransportPair()
+	sender := NewFileTransfers(a, t.TempDir())
+	receiver := NewFileTransfers(b, t.TempDir())
+	path, data := newTestFile(t, "luna.dcc", 2*FileChunkSize+100)
+
+	var offered []FileOffer
+	receiver.SetOfferHandler(func(offer FileOffer) { offered = append(offered, offer) })
+	var sent, got []FileTransferProgress
+	sender.SetProgressHandler(func(p FileTransferProgress) { sent = append(sent, p) })
+	receiver.SetProgressHandler(func(p FileTransferProgress) { got = append(got, p) })
+
+	if _, err := sender.Offer("peer-b", path); err != nil {
+		t.Fatalf("Offer failed: %v", err)
+	}
+	if len(offered) != 1 || offered[0].Name != "luna.dcc" || offered[0].Size != int64(len(data)) {
+		t.Fatalf("Expected the offer to reach the user, got %+v", offered)
+	}
+	if len(got) != 0 {
+		t.Fatal("Nothing should be downloaded before the user accepts")
+	}
+
+	if err := receiver.Accept(offered[0].TransferID); err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	last := got[len(got)-1]
+	if !last.Done || last.Err != nil || last.Path != filepath.Join(receiver.dir, "luna.dcc") {
+		t.Fatalf("Expected the download to finish, got %+v", last)
+	}
+	saved, _ := os.ReadFile(last.Path)
+	if !bytes.Equal(saved, data) {
+		t.Error("Downloaded file differs from the original")
+	}
+	if done := sent[len(sent)-1]; !done.Done || done.Err != nil {
+		t.Errorf("Expected the sender to hear the file arrived, got %+v", done)
+	}
+	if len(receiver.Pending()) != 0 {
+		t.Error("Expected no pending downloads after completion")
+	}
+}
+
+func TestFileTransferResumesPartialDownload(t *testing.T) {
+	a, b := newTransportPair()
+	sender := NewFileTransfers(a, t.TempDir())
+	receiver := NewFileTransfers(b, t.TempDir())
+	path, data := newTestFile(t, "luna.dcc", 2*FileChunkSize)
+
+	var offer FileOffer
+	receiver.SetOfferHandler(func(o FileOffer) { offer = o })
+	if _, err := sender.Offer("peer-b", path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first chunk arrived in an earlier session
+	if err := os.WriteFile(receiver.partialPath(offer), data[:FileChunkSize], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var offsets []int64
+	handler := a.handlers[MessageTypeFileTransfer]
+	a.handlers[MessageTypeFileTransfer] = func(msg Message, from *Peer) error {
+		var p FileTransferPayload
+		json.Unmarshal(msg.Payload, &p)
+		if p.Kind == TransferKindRequest {
+			offsets = append(offsets, p.Offset)
+		}
+		return handler(msg, from)
+	}
+
+	if err := receiver.Accept(offer.TransferID); err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if len(offsets) != 1 || offsets[0] != FileChunkSize {
+		t.Errorf("Expected only the second chunk to be requested, got %v", offsets)
+	}
+	saved, _ := os.ReadFile(filepath.Join(receiver.dir, "luna.dcc"))
+	if !bytes.Equal(saved, data) {
+		t.Error("Resumed file differs from the original")
+	}
+}
+
+func TestFileTransferRejectsChecksumMismatch(t *testing.T) {
+	a, b := newTransportPair()
+	sender := NewFileTransfers(a, t.TempDir())
+	receiver := NewFileTransfers(b, t.TempDir())
+	path, data := newTestFile(t, "luna.dcc", 1000)
+
+	var offer FileOffer
+	receiver.SetOfferHandler(func(o FileOffer) { offer = o })
+	var got []FileTransferProgress
+	receiver.SetProgressHandler(func(p FileTransferProgress) { got = append(got, p) })
+	if _, err := sender.Offer("peer-b", path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file changes after it was offered
+	data[0] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	if err := receiver.Accept(offer.TransferID); err == nil {
+		t.Error("Expected the checksum mismatch to fail the download")
+	}
+	if len(got) == 0 || got[len(got)-1].Err == nil {
+		t.Errorf("Expected a failed download to be reported, got %+v", got)
+	}
+	if entries, _ := os.ReadDir(receiver.dir); len(entries) != 0 {
+		t.Errorf("Expected nothing kept after a bad download, found %d files", len(entries))
+	}
+}
+
+func TestFileTransferDeclinedWithoutHandler(t *testing.T) {
+	a, b := newTransportPair()
+	sender := NewFileTransfers(a, t.TempDir())
+	NewFileTransfers(b, t.TempDir())
+	path, _ := newTestFile(t, "luna.dcc", 10)
+
+	var sent []FileTransferProgress
+	sender.SetProgressHandler(func(p FileTransferProgress) { sent = append(sent, p) })
+	if _, err := sender.Offer("peer-b", path); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].Err == nil {
+		t.Errorf("Expected the offer to be declined, got %+v", sent)
+	}
+}
+
+func TestFileTransferRejectsUnsafeNames(t *testing.T) {
+	_, b := newTransportPair()
+	receiver := NewFileTransfers(b, t.TempDir())
+	receiver.SetOfferHandler(func(FileOffer) { t.Error("Unsafe offer should not reach the user") })
+
+	for _, name := range []string{"../evil.dcc", ".hidden", "dir/luna.dcc"} {
+		payload, _ := json.Marshal(FileTransferPayload{Kind: TransferKindOffer, TransferID: "x", Name: name, Size: 10,
+			SHA256: "0000000000000000000000000000000000000000000000000000000000000000"})
+		if err := receiver.handleMessage(Message{Type: MessageTypeFileTransfer, Payload: payload}, &Peer{ID: "peer-a"}); err == nil {
+			t.Errorf("Expected offer of %q to be rejected", name)
+		}
+	}
+}
+
+func TestAvailablePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "luna.dcc")
+	if got := availablePath(path); got != path {
+		t.Errorf("Expected the free name to be kept, got %s", got)
+	}
+	os.WriteFile(path, nil, 0o644)
+	if got := availablePath(path); got != filepath.Join(dir, "luna (2).dcc") {
+		t.Errorf("Expected a numbered name, got %s", got)
+	}
+}
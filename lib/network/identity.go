@@ -0,0 +1,91 @@
+package network
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IdentityFileName returns the identity key file for a character in the user
+// config directory. Each character gets its own key so companions running side
+// by side on one machine still have different peer IDs.
+func IdentityFileName(characterName string) string {
+	return "peer_identity_" + strings.ToLower(mdnsLabel(characterName)) + ".key"
+}
+
+// PeerIDFromKey returns the peer ID for an identity key: the hex encoded
+// ed25519 public key. It is unique per instance and can only be used by
+// whoever holds the private key.
+func PeerIDFromKey(key ed25519.PublicKey) string {
+	return hex.EncodeToString(key)
+}
+
+// LoadOrCreateIdentity reads the hex encoded ed25519 seed at path, generating
+// one readable only by the user the first time. A stable identity lets peers
+// keep the permissions they gave us across restarts.
+func LoadOrCreateIdentity(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid peer identity in %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read peer identity: %w", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate peer identity: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create identity directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write peer identity: %w", err)
+	}
+	return key, nil
+}
+
+// signMessage wraps msg with this instance's signature and public key
+func (nm *NetworkManager) signMessage(msg Message) (*SignedMessage, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return &SignedMessage{
+		Message:   msg,
+		Signature: ed25519.Sign(nm.identity, data),
+		PublicKey: nm.identity.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+// verifySender checks a signed message and returns the peer ID of the key
+// that signed it. The message must name that key in its From field, so a
+// peer can't speak for another.
+func verifySender(signed *SignedMessage) (string, error) {
+	if len(signed.PublicKey) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("invalid public key size: %d", len(signed.PublicKey))
+	}
+	data, err := json.Marshal(signed.Message)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message for verification: %w", err)
+	}
+	if !ed25519.Verify(signed.PublicKey, data, signed.Signature) {
+		return "", fmt.Errorf("signature verification failed")
+	}
+
+	sender := PeerIDFromKey(signed.PublicKey)
+	if signed.Message.From != sender {
+		return "", fmt.Errorf("message from %q signed by %s", signed.Message.From, sender)
+	}
+	return sender, nil
+}
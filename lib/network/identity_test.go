@@ -0,0 +1,52 @@
+package network
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadOrCreateIdentity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", IdentityFileName("Pixel Pal"))
+
+	key, err := LoadOrCreateIdentity(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateIdentity failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the identity to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Identity should only be readable by the user, got %v", info.Mode().Perm())
+	}
+
+	reloaded, err := LoadOrCreateIdentity(path)
+	if err != nil {
+		t.Fatalf("Reloading the identity failed: %v", err)
+	}
+	if !key.Equal(reloaded) {
+		t.Error("Expected the same identity after a restart")
+	}
+
+	nm, err := NewNetworkManager(NetworkManagerConfig{Identity: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := PeerIDFromKey(key.Public().(ed25519.PublicKey)); nm.GetPeerID() != want {
+		t.Errorf("Peer ID should be the public key, got %s want %s", nm.GetPeerID(), want)
+	}
+
+	os.WriteFile(path, []byte("not a key"), 0o600)
+	if _, err := LoadOrCreateIdentity(path); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("Expected a corrupt identity to be reported, got %v", err)
+	}
+}
+
+func TestIdentityFileName(t *testing.T) {
+	if a, b := IdentityFileName("Pixel Pal"), IdentityFileName("Robo"); a == b || a != "peer_identity_pixel-pal.key" {
+		t.Errorf("Expected distinct per-character files, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,328 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Echo messages measuring the round trip to each peer. A ping is answered
+// with a pong carrying the same sequence number.
+const (
+	MessageTypePing MessageType = "ping"
+	MessageTypePong MessageType = "pong"
+)
+
+const (
+	// DefaultPingInterval is how often every peer is pinged
+	DefaultPingInterval = 5 * time.Second
+	// pingTimeout is how long a ping may go unanswered before it counts as lost
+	pingTimeout = 3 * time.Second
+	// pingWindow is how many recent pings per peer the measurements cover
+	pingWindow = 20
+)
+
+// Connection quality thresholds: good needs both the latency and loss under
+// the good limits, fair under the fair ones
+const (
+	goodLatency = 100 * time.Millisecond
+	fairLatency = 250 * time.Millisecond
+	goodLoss    = 0.02
+	fairLoss    = 0.10
+)
+
+// ConnectionQuality rates a peer's latency and packet loss
+type ConnectionQuality int
+
+const (
+	QualityUnknown ConnectionQuality = iota // Not measured yet
+	QualityGood
+	QualityFair
+	QualityPoor
+)
+
+// String returns the quality's name
+func (q ConnectionQuality) String() string {
+	switch q {
+	case QualityGood:
+		return "good"
+	case QualityFair:
+		return "fair"
+	case QualityPoor:
+		return "poor"
+	default:
+		return "unknown"
+	}
+}
+
+// sentImmediately reports whether a message type skips batching. Pings and
+// pongs are written at once, or the batch interval would count as latency.
+func sentImmediately(msgType MessageType) bool {
+	return msgType == MessageTypePing || msgType == MessageTypePong
+}
+
+// answerPing answers a ping with a pong carrying the same sequence number,
+// so peers can measure us even when no LatencyMonitor is running here
+func (nm *NetworkManager) answerPing(msg Message, from *Peer) error {
+	if from == nil {
+		return nil
+	}
+	return nm.SendMessage(MessageTypePong, msg.Payload, from.ID)
+}
+
+// PingPayload is the wire format of pings and pongs
+type PingPayload struct {
+	Seq uint64 `json:"seq"`
+}
+
+// PeerLatency is the measured connection to a peer over its recent pings
+type PeerLatency struct {
+	Latency time.Duration     // Average round trip of the answered pings
+	Loss    float64           // Fraction of pings not answered in time, 0-1
+	Samples int               // Pings answered or timed out
+	Quality ConnectionQuality // Rating of Latency and Loss
+}
+
+// LatencyTransport is the subset of the network manager used for pinging
+type LatencyTransport interface {
+	GetPeers() []Peer
+	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+}
+
+// probe is one ping sent to a peer
+type probe struct {
+	seq      uint64
+	sent     time.Time
+	rtt      time.Duration
+	answered bool
+}
+
+// LatencyMonitor pings every peer periodically and estimates each one's
+// latency and packet loss from the echoes
+type LatencyMonitor struct {
+	mu        sync.Mutex
+	transport LatencyTransport
+	seq       uint64
+	probes    map[string][]probe // Peer ID to its recent pings, oldest first
+	stop      chan struct{}
+	now       func() time.Time
+}
+
+// NewLatencyMonitor creates a monitor and registers its ping and pong
+// handlers. Call Start to begin pinging.
+func NewLatencyMonitor(transport LatencyTransport) *LatencyMonitor {
+	lm := &LatencyMonitor{
+		transport: transport,
+		probes:    make(map[string][]probe),
+		now:       time.Now,
+	}
+
+	if transport != nil {
+		transport.RegisterMessageHandler(MessageTypePing, lm.handlePing)
+		transport.RegisterMessageHandler(MessageTypePong, lm.handlePong)
+	}
+	return lm
+}
+
+// Start pings every peer each interval, DefaultPingInterval when 0
+func (lm *LatencyMonitor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPingInterval
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	if lm.stop != nil || lm.transport == nil {
+		return
+	}
+	lm.stop = make(chan struct{})
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		lm.PingPeers()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				lm.PingPeers()
+			}
+		}
+	}(lm.stop)
+}
+
+// Stop stops pinging; measurements so far are kept
+func (lm *LatencyMonitor) Stop() {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	if lm.stop != nil {
+		close(lm.stop)
+		lm.stop = nil
+	}
+}
+
+// PingPeers sends one ping to every peer that advertises CapabilityPing and
+// forgets peers that are gone. Older peers drop pings, so they stay
+// unmeasured rather than looking unreachable.
+func (lm *LatencyMonitor) PingPeers() {
+	if lm.transport == nil {
+		return
+	}
+	var peers []Peer
+	for _, peer := range lm.transport.GetPeers() {
+		if peer.supports(CapabilityPing) {
+			peers = append(peers, peer)
+		}
+	}
+
+	lm.mu.Lock()
+	current := make(map[string][]probe, len(peers))
+	for _, peer := range peers {
+		current[peer.ID] = lm.probes[peer.ID]
+	}
+	lm.probes = current
+	lm.mu.Unlock()
+
+	for _, peer := range peers {
+		lm.ping(peer.ID)
+	}
+}
+
+// ping records a ping to a peer and sends it. The ping is recorded first
+// because the pong may arrive before SendMessage returns.
+func (lm *LatencyMonitor) ping(peerID string) {
+	lm.mu.Lock()
+	lm.seq++
+	seq := lm.seq
+	probes := append(lm.probes[peerID], probe{seq: seq, sent: lm.now()})
+	if len(probes) > pingWindow {
+		probes = probes[len(probes)-pingWindow:]
+	}
+	lm.probes[peerID] = probes
+	lm.mu.Unlock()
+
+	payload, _ := json.Marshal(PingPayload{Seq: seq})
+	if err := lm.transport.SendMessage(MessageTypePing, payload, peerID); err != nil {
+		lm.forget(peerID, seq) // Never sent, so it says nothing about the connection
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": peerID,
+			"error":  err.Error(),
+		}).Debug("Failed to ping peer")
+	}
+}
+
+// forget drops a ping that couldn't be sent
+func (lm *LatencyMonitor) forget(peerID string, seq uint64) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	probes := lm.probes[peerID]
+	for i := range probes {
+		if probes[i].seq == seq {
+			lm.probes[peerID] = append(probes[:i], probes[i+1:]...)
+			return
+		}
+	}
+}
+
+// handlePing answers a peer's ping with the same sequence number
+func (lm *LatencyMonitor) handlePing(msg Message, from *Peer) error {
+	if from == nil {
+		return nil
+	}
+	return lm.transport.SendMessage(MessageTypePong, msg.Payload, from.ID)
+}
+
+// handlePong records the round trip of one of our pings
+func (lm *LatencyMonitor) handlePong(msg Message, from *Peer) error {
+	if from == nil {
+		return nil
+	}
+	var payload PingPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid pong: %w", err)
+	}
+
+	now := lm.now()
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	probes := lm.probes[from.ID]
+	for i := range probes {
+		if probes[i].seq == payload.Seq && !probes[i].answered {
+			probes[i].answered = true
+			probes[i].rtt = now.Sub(probes[i].sent)
+			break
+		}
+	}
+	return nil
+}
+
+// Latency returns the measured connection to a peer
+func (lm *LatencyMonitor) Latency(peerID string) PeerLatency {
+	now := lm.now()
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	var total time.Duration
+	var answered, lost int
+	for _, p := range lm.probes[peerID] {
+		switch {
+		case p.answered:
+			total += p.rtt
+			answered++
+		case now.Sub(p.sent) >= pingTimeout:
+			lost++
+		}
+	}
+
+	result := PeerLatency{Samples: answered + lost}
+	if result.Samples == 0 {
+		return result
+	}
+	result.Loss = float64(lost) / float64(result.Samples)
+	if answered > 0 {
+		result.Latency = total / time.Duration(answered)
+	}
+	result.Quality = rateConnection(result.Latency, result.Loss, answered)
+	return result
+}
+
+// CheckBattle returns an error when a peer's latency is above limit, so a
+// battle with them would lag. Peers not measured yet, including those that
+// don't answer pings at all, pass.
+func (lm *LatencyMonitor) CheckBattle(peerID string, limit time.Duration) error {
+	if limit <= 0 {
+		return nil
+	}
+	latency := lm.Latency(peerID)
+	if latency.Samples == 0 {
+		return nil
+	}
+	if latency.Loss >= 1 {
+		return fmt.Errorf("%s isn't answering pings", peerID)
+	}
+	if latency.Latency > limit {
+		return fmt.Errorf("ping to %s is %d ms, above the %d ms battle limit",
+			peerID, latency.Latency.Milliseconds(), limit.Milliseconds())
+	}
+	return nil
+}
+
+// rateConnection rates a latency and loss measured over answered pongs
+func rateConnection(latency time.Duration, loss float64, answered int) ConnectionQuality {
+	switch {
+	case answered == 0:
+		return QualityPoor
+	case latency < goodLatency && loss < goodLoss:
+		return QualityGood
+	case latency < fairLatency && loss < fairLoss:
+		return QualityFair
+	default:
+		return QualityPoor
+	}
+}
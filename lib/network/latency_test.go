@@ -0,0 +1,112 @@
+package network
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencyMonitorMeasuresRoundTrips(t *testing.T) {
+	a, b := newTransportPair()
+	clock := time.Unix(1000, 0)
+	alice := NewLatencyMonitor(a)
+	alice.now = func() time.Time { return clock }
+	NewLatencyMonitor(b) // Answers alice's pings
+
+	if got := alice.Latency("peer-b"); got.Quality != QualityUnknown || got.Samples != 0 {
+		t.Errorf("Expected an unmeasured peer, got %+v", got)
+	}
+
+	// b's pong takes 40ms
+	b.handlers[MessageTypePing] = func(msg Message, from *Peer) error {
+		clock = clock.Add(40 * time.Millisecond)
+		return b.SendMessage(MessageTypePong, msg.Payload, from.ID)
+	}
+	for i := 0; i < 3; i++ {
+		alice.PingPeers()
+	}
+
+	got := alice.Latency("peer-b")
+	if got.Latency != 40*time.Millisecond || got.Loss != 0 || got.Samples != 3 || got.Quality != QualityGood {
+		t.Errorf("Expected 3 good 40ms pings, got %+v", got)
+	}
+	if err := alice.CheckBattle("peer-b", 30*time.Millisecond); err == nil || !strings.Contains(err.Error(), "40 ms") {
+		t.Errorf("Expected a 30ms limit to refuse the battle, got %v", err)
+	}
+	if err := alice.CheckBattle("peer-b", 100*time.Millisecond); err != nil {
+		t.Errorf("Expected a 100ms limit to allow the battle, got %v", err)
+	}
+}
+
+func TestLatencyMonitorEstimatesLoss(t *testing.T) {
+	a, b := newTransportPair()
+	clock := time.Unix(1000, 0)
+	alice := NewLatencyMonitor(a)
+	alice.now = func() time.Time { return clock }
+
+	// Every other ping goes unanswered
+	pings := 0
+	b.handlers[MessageTypePing] = func(msg Message, from *Peer) error {
+		pings++
+		if pings%2 == 0 {
+			return nil
+		}
+		return b.SendMessage(MessageTypePong, msg.Payload, from.ID)
+	}
+	for i := 0; i < 4; i++ {
+		alice.PingPeers()
+	}
+
+	// Unanswered pings only count as lost once they time out
+	if got := alice.Latency("peer-b"); got.Samples != 2 || got.Loss != 0 {
+		t.Errorf("Expected pending pings left out, got %+v", got)
+	}
+	clock = clock.Add(pingTimeout)
+	if got := alice.Latency("peer-b"); got.Samples != 4 || got.Loss != 0.5 || got.Quality != QualityPoor {
+		t.Errorf("Expected half the pings lost, got %+v", got)
+	}
+
+	// Pings that couldn't be sent don't count
+	a.offline = true
+	alice.PingPeers()
+	if got := alice.Latency("peer-b"); got.Samples != 4 {
+		t.Errorf("Expected the unsent ping ignored, got %+v", got)
+	}
+
+	b.handlers[MessageTypePing] = func(Message, *Peer) error { return nil }
+	a.offline = false
+	silent := NewLatencyMonitor(a)
+	silent.now = alice.now
+	silent.PingPeers()
+	clock = clock.Add(pingTimeout)
+	if err := silent.CheckBattle("peer-b", time.Second); err == nil {
+		t.Error("Expected a peer that never answers to be refused")
+	}
+}
+
+func TestLatencyMonitorSkipsPeersWithoutPing(t *testing.T) {
+	a, b := newTransportPair()
+	clock := time.Unix(1000, 0)
+	alice := NewLatencyMonitor(a)
+	alice.now = func() time.Time { return clock }
+
+	// An older peer doesn't advertise ping and drops any it gets
+	b.capabilities = []string{CapabilityBatch, CapabilityGzip}
+	pinged := false
+	b.handlers[MessageTypePing] = func(Message, *Peer) error {
+		pinged = true
+		return nil
+	}
+	alice.PingPeers()
+	clock = clock.Add(pingTimeout)
+
+	if pinged {
+		t.Error("Expected a peer without the ping capability not to be pinged")
+	}
+	if got := alice.Latency("peer-b"); got.Samples != 0 || got.Quality != QualityUnknown {
+		t.Errorf("Expected the older peer unmeasured, got %+v", got)
+	}
+	if err := alice.CheckBattle("peer-b", time.Millisecond); err != nil {
+		t.Errorf("Expected an unmeasured peer to pass, got %v", err)
+	}
+}
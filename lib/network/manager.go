@@ -2,11 +2,18 @@ package network
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
 )
 
 // NetworkManager handles peer discovery and communication for multiplayer functionality.
@@ -19,6 +26,10 @@ type NetworkManager struct {
 	maxPeers      int
 	networkID     string
 
+	// Identity: the public key is this instance's peer ID
+	identity ed25519.PrivateKey
+	peerID   string
+
 	// Connection management - using interface types for testability
 	discoveryConn net.PacketConn // UDP for peer discovery
 	tcpListener   net.Listener   // TCP for reliable messaging
@@ -29,7 +40,9 @@ type NetworkManager struct {
 
 	// Message handling
 	messageQueue chan Message
+	outbox       map[string][]SignedMessage // Batches waiting for the next flush, by peer ID
 	handlers     map[MessageType]MessageHandler
+	observers    map[MessageType][]MessageHandler // Extra subscribers that see messages alongside the handler
 
 	// Lifecycle management
 	ctx    context.Context
@@ -38,6 +51,23 @@ type NetworkManager struct {
 
 	// Discovery state
 	discoveryInterval time.Duration
+	discovery         string         // DiscoveryBroadcast, DiscoveryMDNS or DiscoveryBoth
+	capabilities      []string       // Advertised in mDNS TXT records
+	mdns              *mdnsResponder // Nil unless mDNS discovery is running
+
+	// Addressing
+	addressFamily string // AddressFamilyDual, AddressFamilyIPv4 or AddressFamilyIPv6
+	bindAddress   string // IP or interface name, empty for all
+	bindIP        net.IP // Resolved from bindAddress at Start, nil for all
+	tcpPortLow    int    // TCP listen port range, 0 lets the OS choose
+	tcpPortHigh   int
+
+	// Per-peer permissions, nil allows everything
+	permissions *PermissionStore
+
+	// Wire framing, see wire.go
+	batchInterval     time.Duration // 0 sends each message at once
+	compressThreshold int           // Negative never compresses
 }
 
 // Peer represents a connected peer in the network
@@ -47,6 +77,9 @@ type Peer struct {
 	AddrStr  string    `json:"addr"` // Serializable address
 	LastSeen time.Time `json:"lastSeen"`
 	Conn     net.Conn  `json:"-"` // TCP connection, nil if not connected
+
+	Capabilities []string `json:"capabilities,omitempty"` // From mDNS TXT records, empty for broadcast peers
+	Advertised   []string `json:"advertised,omitempty"`   // Addresses the peer says it is reachable on
 }
 
 // MessageType defines the type of network message
@@ -84,6 +117,9 @@ type DiscoveryPayload struct {
 	NetworkID string `json:"networkId"`
 	PeerID    string `json:"peerId"`
 	TCPPort   int    `json:"tcpPort"`
+
+	Addrs        []string `json:"addrs,omitempty"`        // Reachable addresses of a multi-homed sender
+	Capabilities []string `json:"capabilities,omitempty"` // As advertised over mDNS, absent from older peers
 }
 
 // PersonalityRequestPayload requests personality data from a peer
@@ -117,6 +153,17 @@ type NetworkManagerConfig struct {
 	MaxPeers          int           `json:"maxPeers"`
 	NetworkID         string        `json:"networkId"`
 	DiscoveryInterval time.Duration `json:"discoveryInterval"`
+	Discovery         string        `json:"discovery,omitempty"`         // "broadcast", "mdns" or "both" (default)
+	Capabilities      []string      `json:"capabilities,omitempty"`      // e.g. "battle", "chat", "bot"
+	AddressFamily     string        `json:"addressFamily,omitempty"`     // "dual" (default), "ipv4" or "ipv6"
+	BindAddress       string        `json:"bindAddress,omitempty"`       // IP address or interface name, empty for all
+	TCPPortRange      string        `json:"tcpPortRange,omitempty"`      // e.g. "47000-47010", empty for any free port
+	BatchInterval     time.Duration `json:"batchInterval,omitempty"`     // Send messages to batch-capable peers together this often, 0 sends each at once
+	CompressThreshold int           `json:"compressThreshold,omitempty"` // Gzip frames above this many bytes (default 1024), negative never compresses
+
+	// Identity is this instance's ed25519 key; its public key is the peer ID.
+	// A fresh key is generated when nil.
+	Identity ed25519.PrivateKey `json:"-"`
 }
 
 // NewNetworkManager creates a new NetworkManager with the given configuration.
@@ -135,6 +182,33 @@ func NewNetworkManager(config NetworkManagerConfig) (*NetworkManager, error) {
 	if config.DiscoveryInterval <= 0 {
 		config.DiscoveryInterval = 5 * time.Second
 	}
+	if config.Discovery == "" {
+		config.Discovery = DiscoveryBoth
+	}
+	if !IsValidDiscoveryMode(config.Discovery) {
+		return nil, fmt.Errorf("unknown discovery mode %q", config.Discovery)
+	}
+	if config.AddressFamily == "" {
+		config.AddressFamily = AddressFamilyDual
+	}
+	if !IsValidAddressFamily(config.AddressFamily) {
+		return nil, fmt.Errorf("unknown address family %q", config.AddressFamily)
+	}
+	tcpPortLow, tcpPortHigh, err := ParsePortRange(config.TCPPortRange)
+	if err != nil {
+		return nil, err
+	}
+	if config.CompressThreshold == 0 {
+		config.CompressThreshold = defaultCompressThreshold
+	}
+	if config.Identity == nil {
+		if _, config.Identity, err = ed25519.GenerateKey(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate peer identity: %w", err)
+		}
+	}
+	if len(config.Identity) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid identity key size: %d", len(config.Identity))
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -142,17 +216,30 @@ func NewNetworkManager(config NetworkManagerConfig) (*NetworkManager, error) {
 		discoveryPort:     config.DiscoveryPort,
 		maxPeers:          config.MaxPeers,
 		networkID:         config.NetworkID,
+		identity:          config.Identity,
+		peerID:            PeerIDFromKey(config.Identity.Public().(ed25519.PublicKey)),
 		peers:             make(map[string]*Peer),
 		messageQueue:      make(chan Message, 100), // Buffered channel for async processing
+		outbox:            make(map[string][]SignedMessage),
 		handlers:          make(map[MessageType]MessageHandler),
+		observers:         make(map[MessageType][]MessageHandler),
 		ctx:               ctx,
 		cancel:            cancel,
 		discoveryInterval: config.DiscoveryInterval,
+		discovery:         config.Discovery,
+		capabilities:      wireCapabilities(config.Capabilities),
+		addressFamily:     config.AddressFamily,
+		bindAddress:       config.BindAddress,
+		tcpPortLow:        tcpPortLow,
+		tcpPortHigh:       tcpPortHigh,
+		batchInterval:     config.BatchInterval,
+		compressThreshold: config.CompressThreshold,
 	}
 
 	// Register default message handlers
 	nm.handlers[MessageTypeDiscovery] = nm.handleDiscoveryMessage
 	nm.handlers[MessageTypePeerList] = nm.handlePeerListMessage
+	nm.handlers[MessageTypePing] = nm.answerPing // Advertised with CapabilityPing
 
 	return nm, nil
 }
@@ -160,18 +247,30 @@ func NewNetworkManager(config NetworkManagerConfig) (*NetworkManager, error) {
 // Start initializes the network manager and begins peer discovery.
 // Returns error if network initialization fails.
 func (nm *NetworkManager) Start() error {
-	// Start UDP discovery listener
-	discoveryAddr := fmt.Sprintf(":%d", nm.discoveryPort)
-	conn, err := net.ListenPacket("udp", discoveryAddr)
+	bindIP, err := resolveBindIP(nm.addressFamily, nm.bindAddress)
 	if err != nil {
-		return fmt.Errorf("failed to start discovery listener: %w", err)
+		return fmt.Errorf("failed to resolve bind address: %w", err)
+	}
+	nm.bindIP = bindIP
+
+	// Start UDP discovery listener. It listens on every address because
+	// sockets bound to a unicast address never see broadcasts; announcements
+	// from outside the bound interface are filtered instead.
+	if nm.discovery != DiscoveryMDNS {
+		discoveryAddr := listenHostPort(nil, nm.discoveryPort)
+		conn, err := net.ListenPacket(familyNetwork("udp", nm.addressFamily), discoveryAddr)
+		if err != nil {
+			return fmt.Errorf("failed to start discovery listener: %w", err)
+		}
+		nm.discoveryConn = conn
 	}
-	nm.discoveryConn = conn
 
 	// Start TCP listener for peer connections
-	tcpListener, err := net.Listen("tcp", ":0") // Let OS assign port
+	tcpListener, err := listenTCPInRange(familyNetwork("tcp", nm.addressFamily), nm.bindIP, nm.tcpPortLow, nm.tcpPortHigh)
 	if err != nil {
-		nm.discoveryConn.Close()
+		if nm.discoveryConn != nil {
+			nm.discoveryConn.Close()
+		}
 		return fmt.Errorf("failed to start TCP listener: %w", err)
 	}
 	nm.tcpListener = tcpListener
@@ -179,11 +278,28 @@ func (nm *NetworkManager) Start() error {
 	// Store local address for discovery messages
 	nm.localAddr = tcpListener.Addr()
 
+	// Advertise and browse over mDNS; with "both" broadcast still works if it fails
+	if nm.discovery != DiscoveryBroadcast {
+		if err := nm.startMDNS(); err != nil {
+			if nm.discovery == DiscoveryMDNS {
+				tcpListener.Close()
+				return err
+			}
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+				"error":  err.Error(),
+			}).Warn("mDNS discovery unavailable, using UDP broadcast only")
+		}
+	}
+
 	// Start background goroutines
-	nm.wg.Add(3)
-	go nm.discoveryListener()
+	nm.wg.Add(2)
 	go nm.tcpConnectionHandler()
 	go nm.messageProcessor()
+	if nm.discoveryConn != nil {
+		nm.wg.Add(1)
+		go nm.discoveryListener()
+	}
 
 	// Start periodic discovery broadcasts
 	nm.wg.Add(1)
@@ -196,6 +312,10 @@ func (nm *NetworkManager) Start() error {
 func (nm *NetworkManager) Stop() error {
 	nm.cancel() // Signal all goroutines to stop
 
+	if nm.mdns != nil {
+		nm.mdns.close()
+	}
+
 	// Close network connections
 	if nm.discoveryConn != nil {
 		nm.discoveryConn.Close()
@@ -243,6 +363,12 @@ func (nm *NetworkManager) GetNetworkID() string {
 	return nm.networkID
 }
 
+// GetPeerID returns this instance's peer ID. Every instance on a network
+// shares the network ID but has its own peer ID.
+func (nm *NetworkManager) GetPeerID() string {
+	return nm.peerID
+}
+
 // RegisterMessageHandler registers a handler for a specific message type
 func (nm *NetworkManager) RegisterMessageHandler(msgType MessageType, handler MessageHandler) {
 	nm.mu.Lock()
@@ -250,11 +376,20 @@ func (nm *NetworkManager) RegisterMessageHandler(msgType MessageType, handler Me
 	nm.handlers[msgType] = handler
 }
 
+// AddMessageObserver subscribes to a message type without taking the
+// handler slot. Observers see every permitted message of that type
+// alongside the handler registered with RegisterMessageHandler.
+func (nm *NetworkManager) AddMessageObserver(msgType MessageType, observer MessageHandler) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.observers[msgType] = append(nm.observers[msgType], observer)
+}
+
 // SendMessage sends a message to a specific peer or broadcasts to all peers
 func (nm *NetworkManager) SendMessage(msgType MessageType, payload []byte, targetPeerID string) error {
 	message := Message{
 		Type:      msgType,
-		From:      nm.networkID,
+		From:      nm.peerID,
 		To:        targetPeerID,
 		Payload:   payload,
 		Timestamp: time.Now(),
@@ -298,7 +433,7 @@ func (nm *NetworkManager) discoveryListener() {
 		}
 
 		// Process discovery message
-		if msg.Type == MessageTypeDiscovery {
+		if msg.Type == MessageTypeDiscovery && nm.acceptsSource(addr) {
 			nm.processDiscoveryMessage(msg, addr)
 		}
 	}
@@ -310,9 +445,13 @@ func (nm *NetworkManager) processDiscoveryMessage(msg Message, from net.Addr) {
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 		return
 	}
+	nm.addDiscoveredPeer(payload, from, payload.Capabilities)
+}
 
+// addDiscoveredPeer records a peer found by broadcast or mDNS and connects to it
+func (nm *NetworkManager) addDiscoveredPeer(payload DiscoveryPayload, from net.Addr, capabilities []string) {
 	// Ignore messages from ourselves
-	if payload.PeerID == nm.networkID {
+	if payload.PeerID == nm.peerID {
 		return
 	}
 
@@ -342,6 +481,12 @@ func (nm *NetworkManager) processDiscoveryMessage(msg Message, from net.Addr) {
 		nm.peers[payload.PeerID] = peer
 	}
 	peer.LastSeen = time.Now()
+	if capabilities != nil {
+		peer.Capabilities = capabilities
+	}
+	if payload.Addrs != nil {
+		peer.Advertised = payload.Addrs
+	}
 	nm.mu.Unlock()
 
 	// Attempt TCP connection if not already connected
@@ -358,9 +503,18 @@ func (nm *NetworkManager) connectToPeer(peer *Peer, tcpPort int) {
 		return
 	}
 
-	tcpAddr := net.JoinHostPort(host, fmt.Sprintf("%d", tcpPort))
-	conn, err := net.DialTimeout("tcp", tcpAddr, 5*time.Second)
-	if err != nil {
+	nm.mu.RLock()
+	candidates := peerDialAddresses(host, peer.Advertised, tcpPort)
+	nm.mu.RUnlock()
+
+	// Multi-homed peers may only be reachable on some of their addresses
+	var conn net.Conn
+	for _, tcpAddr := range candidates {
+		if conn, err = nm.dial(tcpAddr); err == nil {
+			break
+		}
+	}
+	if conn == nil {
 		return
 	}
 
@@ -369,11 +523,12 @@ func (nm *NetworkManager) connectToPeer(peer *Peer, tcpPort int) {
 	nm.mu.Unlock()
 
 	// Handle the connection in a separate goroutine
-	go nm.handlePeerConnection(peer)
+	go nm.handlePeerConnection(peer, newFrameReader(conn))
 }
 
-// handlePeerConnection manages a TCP connection to a peer
-func (nm *NetworkManager) handlePeerConnection(peer *Peer) {
+// handlePeerConnection manages a TCP connection to a peer, reading messages
+// through reader
+func (nm *NetworkManager) handlePeerConnection(peer *Peer, reader *frameReader) {
 	defer func() {
 		if peer.Conn != nil {
 			peer.Conn.Close()
@@ -383,7 +538,6 @@ func (nm *NetworkManager) handlePeerConnection(peer *Peer) {
 		}
 	}()
 
-	decoder := json.NewDecoder(peer.Conn)
 	for {
 		select {
 		case <-nm.ctx.Done():
@@ -391,18 +545,49 @@ func (nm *NetworkManager) handlePeerConnection(peer *Peer) {
 		default:
 		}
 
-		var msg Message
-		if err := decoder.Decode(&msg); err != nil {
-			return // Connection error, cleanup and exit
+		signed, err := reader.next()
+		if err != nil {
+			return // Connection error or malformed frame, cleanup and exit
+		}
+
+		// Only messages signed by the key this connection belongs to count
+		sender, err := verifySender(&signed)
+		if err != nil || sender != peer.ID {
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+				"peerID": peer.ID,
+				"from":   signed.Message.From,
+				"error":  err,
+			}).Debug("Dropping message with an invalid signature")
+			continue
 		}
+		msg := signed.Message
 
-		// Forward message to handler
-		if handler, exists := nm.handlers[msg.Type]; exists {
-			go handler(msg, peer) // Handle in separate goroutine to avoid blocking
+		if !nm.permits(msg, sender) {
+			continue
+		}
+
+		// Forward message to the handler and any observers
+		nm.mu.RLock()
+		handler := nm.handlers[msg.Type]
+		observers := nm.observers[msg.Type]
+		nm.mu.RUnlock()
+		if handler != nil {
+			go runHandler(handler, msg, peer) // Handle in separate goroutine to avoid blocking
+		}
+		for _, observer := range observers {
+			go runHandler(observer, msg, peer)
 		}
 	}
 }
 
+// runHandler runs a message handler on its own goroutine; a panic in it is
+// handed to the crash handler, which saves the game before exiting
+func runHandler(handler MessageHandler, msg Message, peer *Peer) {
+	defer monitoring.RecoverCrash()
+	handler(msg, peer)
+}
+
 // tcpConnectionHandler accepts incoming TCP connections from peers
 func (nm *NetworkManager) tcpConnectionHandler() {
 	defer nm.wg.Done()
@@ -436,15 +621,19 @@ func (nm *NetworkManager) handleIncomingConnection(conn net.Conn) {
 	// Set a timeout for initial handshake
 	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
 
-	decoder := json.NewDecoder(conn)
-	var msg Message
-	if err := decoder.Decode(&msg); err != nil {
+	reader := newFrameReader(conn)
+	signed, err := reader.next()
+	if err != nil {
 		return
 	}
 
-	// First message should identify the peer
+	// First message should identify the peer by its signature
+	sender, err := verifySender(&signed)
+	if err != nil {
+		return
+	}
 	nm.mu.RLock()
-	peer, exists := nm.peers[msg.From]
+	peer, exists := nm.peers[sender]
 	nm.mu.RUnlock()
 
 	if !exists {
@@ -457,7 +646,7 @@ func (nm *NetworkManager) handleIncomingConnection(conn net.Conn) {
 	nm.mu.Unlock()
 
 	// Continue handling messages on this connection
-	nm.handlePeerConnection(peer)
+	nm.handlePeerConnection(peer, reader)
 }
 
 // discoveryBroadcaster periodically sends discovery messages
@@ -472,7 +661,12 @@ func (nm *NetworkManager) discoveryBroadcaster() {
 		case <-nm.ctx.Done():
 			return
 		case <-ticker.C:
-			nm.sendDiscoveryBroadcast()
+			if nm.discoveryConn != nil {
+				nm.sendDiscoveryBroadcast()
+			}
+			if nm.mdns != nil {
+				nm.mdns.query()
+			}
 		}
 	}
 }
@@ -487,10 +681,17 @@ func (nm *NetworkManager) sendDiscoveryBroadcast() {
 		}
 	}
 
+	ifaces, err := localInterfaces(nm.addressFamily, nm.bindAddress)
+	if err != nil {
+		return
+	}
+
 	payload := DiscoveryPayload{
-		NetworkID: nm.networkID,
-		PeerID:    nm.networkID,
-		TCPPort:   tcpPort,
+		NetworkID:    nm.networkID,
+		PeerID:       nm.peerID,
+		TCPPort:      tcpPort,
+		Addrs:        advertisedAddresses(ifaces),
+		Capabilities: nm.capabilities,
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -500,7 +701,7 @@ func (nm *NetworkManager) sendDiscoveryBroadcast() {
 
 	msg := Message{
 		Type:      MessageTypeDiscovery,
-		From:      nm.networkID,
+		From:      nm.peerID,
 		Payload:   payloadBytes,
 		Timestamp: time.Now(),
 	}
@@ -510,26 +711,37 @@ func (nm *NetworkManager) sendDiscoveryBroadcast() {
 		return
 	}
 
-	// Broadcast to local network
-	broadcastAddr := net.JoinHostPort("255.255.255.255", fmt.Sprintf("%d", nm.discoveryPort))
-	addr, err := net.ResolveUDPAddr("udp", broadcastAddr)
-	if err != nil {
-		return
+	// Announce on every interface so multi-homed machines are found on
+	// each of their networks
+	targets := discoveryTargets(ifaces, nm.discoveryPort)
+	if len(targets) == 0 && nm.addressFamily != AddressFamilyIPv6 && nm.bindAddress == "" {
+		targets = append(targets, &net.UDPAddr{IP: net.IPv4bcast, Port: nm.discoveryPort})
+	}
+	for _, addr := range targets {
+		nm.discoveryConn.WriteTo(msgBytes, addr) // Best effort, the next interval retries
 	}
-
-	nm.discoveryConn.WriteTo(msgBytes, addr)
 }
 
-// messageProcessor handles outgoing messages from the queue
+// messageProcessor handles outgoing messages from the queue and flushes
+// batches. It is the only writer to peer connections.
 func (nm *NetworkManager) messageProcessor() {
 	defer nm.wg.Done()
 
+	var flush <-chan time.Time
+	if nm.batchInterval > 0 {
+		ticker := time.NewTicker(nm.batchInterval)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+
 	for {
 		select {
 		case <-nm.ctx.Done():
 			return
 		case msg := <-nm.messageQueue:
 			nm.processOutgoingMessage(msg)
+		case <-flush:
+			nm.flushOutbox()
 		}
 	}
 }
@@ -554,10 +766,18 @@ func (nm *NetworkManager) processOutgoingMessage(msg Message) {
 	}
 }
 
-// sendMessageToPeer sends a message to a specific peer over TCP
+// sendMessageToPeer signs a message and sends it to a specific peer over
+// TCP, or queues it for the next batch if the peer reads batches
 func (nm *NetworkManager) sendMessageToPeer(msg Message, peer *Peer) {
-	encoder := json.NewEncoder(peer.Conn)
-	encoder.Encode(msg) // Ignore errors for now, connection will be cleaned up by handler
+	signed, err := nm.signMessage(msg)
+	if err != nil {
+		return
+	}
+	if nm.batchInterval > 0 && peer.supports(CapabilityBatch) && !sentImmediately(msg.Type) {
+		nm.queueForBatch(peer, *signed)
+		return
+	}
+	nm.writeFrame(peer, []SignedMessage{*signed})
 }
 
 // Default message handlers
@@ -586,3 +806,55 @@ func (nm *NetworkManager) handlePeerListMessage(msg Message, from *Peer) error {
 
 	return nil
 }
+
+// startMDNS advertises this instance as a DNS-SD service and browses for others
+func (nm *NetworkManager) startMDNS() error {
+	if nm.addressFamily == AddressFamilyIPv6 {
+		return fmt.Errorf("mDNS discovery needs IPv4")
+	}
+
+	tcpPort := 0
+	if addr, ok := nm.tcpListener.Addr().(*net.TCPAddr); ok {
+		tcpPort = addr.Port
+	}
+
+	service := newLocalMDNSService(tcpPort, nm.networkID, nm.peerID, nm.capabilities)
+	if nm.bindAddress != "" {
+		ifaces, err := localInterfaces(AddressFamilyIPv4, nm.bindAddress)
+		if err != nil {
+			return err
+		}
+		for _, local := range ifaces {
+			for _, ipNet := range local.nets {
+				service.IPs = append(service.IPs, ipNet.IP)
+			}
+		}
+		if len(service.IPs) == 0 {
+			return fmt.Errorf("bind address %s has no IPv4 address to announce over mDNS", nm.bindAddress)
+		}
+	}
+	responder := newMDNSResponder(service, nm.handleMDNSEntry)
+	if err := responder.start(); err != nil {
+		return err
+	}
+	nm.mdns = responder
+	return nil
+}
+
+// handleMDNSEntry turns an mDNS answer into a discovered peer
+func (nm *NetworkManager) handleMDNSEntry(entry mdnsEntry) {
+	payload := DiscoveryPayload{
+		NetworkID: entry.TXT["networkId"],
+		PeerID:    entry.TXT["peerId"],
+		TCPPort:   entry.Port,
+	}
+	if payload.PeerID == "" {
+		payload.PeerID = entry.Instance
+	}
+
+	capabilities := []string{}
+	if caps := entry.TXT["caps"]; caps != "" {
+		capabilities = strings.Split(caps, ",")
+	}
+	nm.addDiscoveredPeer(payload, &net.UDPAddr{IP: entry.IP, Port: entry.Port}, capabilities)
+}
@@ -339,13 +339,13 @@ func TestNetworkManager_ProcessDiscoveryMessage(t *testing.T) {
 	// Test ignoring messages from self
 	selfPayload := DiscoveryPayload{
 		NetworkID: "test-network",
-		PeerID:    nm.networkID, // Same as our network ID
+		PeerID:    nm.GetPeerID(), // Our own announcement
 		TCPPort:   8081,
 	}
 	selfPayloadBytes, _ := json.Marshal(selfPayload)
 	selfMsg := Message{
 		Type:      MessageTypeDiscovery,
-		From:      nm.networkID,
+		From:      nm.GetPeerID(),
 		Payload:   selfPayloadBytes,
 		Timestamp: time.Now(),
 	}
@@ -496,3 +496,23 @@ func TestNetworkManager_ContextCancellation(t *testing.T) {
 		t.Errorf("Stop() took %v, expected < 500ms", duration)
 	}
 }
+
+func TestMessageObserversRunAlongsideHandler(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := receivedFrom(nm, MessageTypeBattleResult)
+	for _, name := range []string{"spectators", "feed"} {
+		name := name
+		nm.AddMessageObserver(MessageTypeBattleResult, func(msg Message, from *Peer) error {
+			received <- name + ":" + from.ID
+			return nil
+		})
+	}
+
+	rival := connectTestPeer(t, nm)
+	rival.send(t, Message{Type: MessageTypeBattleResult})
+	id := rival.sender.GetPeerID()
+	expectReceived(t, received, "battle_result:"+id, "spectators:"+id, "feed:"+id)
+}
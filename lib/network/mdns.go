@@ -0,0 +1,426 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// mDNS/DNS-SD discovery finds peers on networks that drop UDP broadcasts
+// (segmented or client-isolated Wi-Fi) but still pass link-local multicast.
+const (
+	// MDNSServiceType is the DNS-SD service companions advertise
+	MDNSServiceType = "_ddscompanion._tcp"
+
+	// Discovery modes for NetworkManagerConfig.Discovery
+	DiscoveryBroadcast = "broadcast" // UDP broadcast only (the original mechanism)
+	DiscoveryMDNS      = "mdns"      // mDNS/DNS-SD only
+	DiscoveryBoth      = "both"      // Both in parallel (default)
+
+	mdnsTTL        = 120 // Seconds peers may cache our records
+	mdnsMaxPacket  = 9000
+	mdnsTXTVersion = "1"
+)
+
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// IsValidDiscoveryMode reports whether mode is a known discovery mode
+func IsValidDiscoveryMode(mode string) bool {
+	switch mode {
+	case DiscoveryBroadcast, DiscoveryMDNS, DiscoveryBoth:
+		return true
+	}
+	return false
+}
+
+// mdnsEntry is a companion instance found through mDNS
+type mdnsEntry struct {
+	Instance string
+	Host     string
+	Port     int
+	IP       net.IP
+	TXT      map[string]string
+}
+
+// mdnsService describes the local instance being advertised
+type mdnsService struct {
+	Instance     string // Unique instance label, e.g. "host-41234"
+	Host         string // Host label without ".local"
+	Port         int    // TCP port for peer connections
+	NetworkID    string
+	PeerID       string
+	Capabilities []string
+	IPs          []net.IP // Addresses to announce, nil for every local IPv4 address
+}
+
+// serviceName returns the fully qualified DNS-SD service name
+func mdnsServiceName() string {
+	return MDNSServiceType + ".local."
+}
+
+// instanceName returns the fully qualified instance name
+func (s mdnsService) instanceName() string {
+	return s.Instance + "." + mdnsServiceName()
+}
+
+// hostName returns the fully qualified host name
+func (s mdnsService) hostName() string {
+	return s.Host + ".local."
+}
+
+// txt returns the TXT record strings
+func (s mdnsService) txt() []string {
+	return []string{
+		"v=" + mdnsTXTVersion,
+		"networkId=" + s.NetworkID,
+		"peerId=" + s.PeerID,
+		"caps=" + strings.Join(s.Capabilities, ","),
+	}
+}
+
+// mdnsLabel makes s safe to use as a single DNS label
+func mdnsLabel(s string) string {
+	label := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return '-'
+	}, s)
+	if len(label) > 63 {
+		label = label[:63]
+	}
+	if label == "" {
+		label = "companion"
+	}
+	return label
+}
+
+// buildMDNSQuery builds a PTR query for the companion service
+func buildMDNSQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(mdnsServiceName())
+	if err != nil {
+		return nil, err
+	}
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// buildMDNSResponse builds the PTR, SRV and TXT answers for the service with
+// A records for ips. A ttl of 0 announces that the service is going away.
+func buildMDNSResponse(s mdnsService, ips []net.IP, ttl uint32) ([]byte, error) {
+	service, err := dnsmessage.NewName(mdnsServiceName())
+	if err != nil {
+		return nil, err
+	}
+	instance, err := dnsmessage.NewName(s.instanceName())
+	if err != nil {
+		return nil, err
+	}
+	host, err := dnsmessage.NewName(s.hostName())
+	if err != nil {
+		return nil, err
+	}
+
+	header := func(name dnsmessage.Name, t dnsmessage.Type) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Type: t, Class: dnsmessage.ClassINET, TTL: ttl}
+	}
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{Response: true, Authoritative: true})
+	b.EnableCompression()
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	if err := b.PTRResource(header(service, dnsmessage.TypePTR), dnsmessage.PTRResource{PTR: instance}); err != nil {
+		return nil, err
+	}
+	if err := b.SRVResource(header(instance, dnsmessage.TypeSRV), dnsmessage.SRVResource{Port: uint16(s.Port), Target: host}); err != nil {
+		return nil, err
+	}
+	if err := b.TXTResource(header(instance, dnsmessage.TypeTXT), dnsmessage.TXTResource{TXT: s.txt()}); err != nil {
+		return nil, err
+	}
+
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		ip4 := ip.To4()
+		if ip4 == nil {
+			continue
+		}
+		var a dnsmessage.AResource
+		copy(a.A[:], ip4)
+		if err := b.AResource(header(host, dnsmessage.TypeA), a); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
+
+// isMDNSQuery reports whether packet asks for the companion service
+func isMDNSQuery(packet []byte) bool {
+	var p dnsmessage.Parser
+	h, err := p.Start(packet)
+	if err != nil || h.Response {
+		return false
+	}
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return false
+	}
+	for _, q := range questions {
+		if (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL) && strings.EqualFold(q.Name.String(), mdnsServiceName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMDNSResponse extracts companion instances from a response. Instances
+// without an A record for their host use the sender's address. Entries with
+// a zero TTL (goodbyes) are skipped.
+func parseMDNSResponse(packet []byte, from net.IP) []mdnsEntry {
+	var p dnsmessage.Parser
+	h, err := p.Start(packet)
+	if err != nil || !h.Response {
+		return nil
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil
+	}
+	answers, err := p.AllAnswers()
+	if err != nil {
+		return nil
+	}
+	if err := p.SkipAllAuthorities(); err != nil {
+		return nil
+	}
+	additionals, _ := p.AllAdditionals() // Answers alone are still usable
+
+	instances := make(map[string]*mdnsEntry)
+	hosts := make(map[string]net.IP)
+	var order []string
+	entry := func(name string) *mdnsEntry {
+		e, ok := instances[name]
+		if !ok {
+			e = &mdnsEntry{Instance: name, TXT: make(map[string]string)}
+			instances[name] = e
+		}
+		return e
+	}
+
+	service := mdnsServiceName()
+	for _, r := range append(answers, additionals...) {
+		name := strings.ToLower(r.Header.Name.String())
+		switch body := r.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if strings.EqualFold(name, service) && r.Header.TTL > 0 {
+				instance := strings.ToLower(body.PTR.String())
+				entry(instance)
+				order = append(order, instance)
+			}
+		case *dnsmessage.SRVResource:
+			if strings.HasSuffix(name, "."+service) {
+				e := entry(name)
+				e.Host = strings.ToLower(body.Target.String())
+				e.Port = int(body.Port)
+			}
+		case *dnsmessage.TXTResource:
+			if strings.HasSuffix(name, "."+service) {
+				e := entry(name)
+				for _, kv := range body.TXT {
+					if key, value, ok := strings.Cut(kv, "="); ok {
+						e.TXT[key] = value
+					}
+				}
+			}
+		case *dnsmessage.AResource:
+			hosts[name] = net.IP(body.A[:])
+		}
+	}
+
+	var entries []mdnsEntry
+	for _, name := range order {
+		e := instances[name]
+		if e.Port == 0 || e.TXT["networkId"] == "" {
+			continue
+		}
+		e.IP = hosts[e.Host]
+		if e.IP == nil {
+			e.IP = from
+		}
+		entries = append(entries, *e)
+	}
+	return entries
+}
+
+// mdnsResponder answers queries for the local service and reports peers
+// found through other instances' answers
+type mdnsResponder struct {
+	service mdnsService
+	onEntry func(mdnsEntry)
+
+	mu   sync.Mutex
+	conn *net.UDPConn
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newMDNSResponder creates a responder for service. Call start to join the group.
+func newMDNSResponder(service mdnsService, onEntry func(mdnsEntry)) *mdnsResponder {
+	return &mdnsResponder{service: service, onEntry: onEntry}
+}
+
+// start joins the mDNS multicast group, announces the service and listens
+func (r *mdnsResponder) start() error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		return fmt.Errorf("failed to join mDNS group: %w", err)
+	}
+
+	r.mu.Lock()
+	r.conn = conn
+	r.stop = make(chan struct{})
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go r.listen()
+
+	r.announce(mdnsTTL)
+	r.query()
+	return nil
+}
+
+// close sends a goodbye so peers forget us at once, then leaves the group
+func (r *mdnsResponder) close() {
+	r.mu.Lock()
+	conn := r.conn
+	if conn == nil {
+		r.mu.Unlock()
+		return
+	}
+	close(r.stop)
+	r.mu.Unlock()
+
+	r.announce(0)
+	conn.Close()
+	r.wg.Wait()
+
+	r.mu.Lock()
+	r.conn = nil
+	r.mu.Unlock()
+}
+
+// query asks other instances to announce themselves
+func (r *mdnsResponder) query() {
+	packet, err := buildMDNSQuery()
+	if err == nil {
+		r.send(packet)
+	}
+}
+
+// announce multicasts our records; ttl 0 says goodbye
+func (r *mdnsResponder) announce(ttl uint32) {
+	ips := r.service.IPs
+	if len(ips) == 0 {
+		ips = localIPv4s()
+	}
+	packet, err := buildMDNSResponse(r.service, ips, ttl)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Warn("Failed to build mDNS response")
+		return
+	}
+	r.send(packet)
+}
+
+// send multicasts a packet to the mDNS group
+func (r *mdnsResponder) send(packet []byte) {
+	r.mu.Lock()
+	conn := r.conn
+	r.mu.Unlock()
+	if conn != nil {
+		conn.WriteToUDP(packet, mdnsGroup) // Best effort, the next interval retries
+	}
+}
+
+// listen answers queries and reports other instances until close
+func (r *mdnsResponder) listen() {
+	defer r.wg.Done()
+
+	buffer := make([]byte, mdnsMaxPacket)
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		r.conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, from, err := r.conn.ReadFromUDP(buffer)
+		if err != nil {
+			continue // Timeouts let us check for stop
+		}
+
+		packet := buffer[:n]
+		if isMDNSQuery(packet) {
+			r.announce(mdnsTTL)
+			continue
+		}
+		for _, entry := range parseMDNSResponse(packet, from.IP) {
+			if entry.Instance == strings.ToLower(r.service.instanceName()) {
+				continue // Our own announcement
+			}
+			r.onEntry(entry)
+		}
+	}
+}
+
+// localIPv4s returns the non-loopback IPv4 addresses of this machine
+func localIPv4s() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		ips = append(ips, ipNet.IP)
+	}
+	return ips
+}
+
+// newLocalMDNSService describes this process for advertising
+func newLocalMDNSService(tcpPort int, networkID, peerID string, capabilities []string) mdnsService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "companion"
+	}
+	host = mdnsLabel(strings.Split(host, ".")[0])
+	return mdnsService{
+		Instance:     mdnsLabel(fmt.Sprintf("%s-%d", host, tcpPort)),
+		Host:         host,
+		Port:         tcpPort,
+		NetworkID:    networkID,
+		PeerID:       peerID,
+		Capabilities: capabilities,
+	}
+}
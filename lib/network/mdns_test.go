@@ -0,0 +1,169 @@
+package network
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMDNSResponseRoundTrip(t *testing.T) {
+	service := mdnsService{
+		Instance:     "desk-41234",
+		Host:         "desk",
+		Port:         41234,
+		NetworkID:    "pets",
+		PeerID:       "pets",
+		Capabilities: []string{"battle", "chat"},
+	}
+	packet, err := buildMDNSResponse(service, []net.IP{net.ParseIP("192.168.1.20"), net.ParseIP("fe80::1")}, mdnsTTL)
+	if err != nil {
+		t.Fatalf("buildMDNSResponse failed: %v", err)
+	}
+
+	entries := parseMDNSResponse(packet, net.ParseIP("10.0.0.9"))
+	if len(entries) != 1 {
+		t.Fatalf("Expected one instance, got %+v", entries)
+	}
+	entry := entries[0]
+	if entry.Instance != "desk-41234._ddscompanion._tcp.local." || entry.Port != 41234 {
+		t.Errorf("Unexpected instance %+v", entry)
+	}
+	if !entry.IP.Equal(net.ParseIP("192.168.1.20")) {
+		t.Errorf("Expected the A record address, got %v", entry.IP)
+	}
+	want := map[string]string{"v": "1", "networkId": "pets", "peerId": "pets", "caps": "battle,chat"}
+	if !reflect.DeepEqual(entry.TXT, want) {
+		t.Errorf("Expected TXT %v, got %v", want, entry.TXT)
+	}
+
+	// Without A records the sender's address is used
+	packet, _ = buildMDNSResponse(service, nil, mdnsTTL)
+	if entries := parseMDNSResponse(packet, net.ParseIP("10.0.0.9")); len(entries) != 1 || !entries[0].IP.Equal(net.ParseIP("10.0.0.9")) {
+		t.Errorf("Expected the sender address fallback, got %+v", entries)
+	}
+
+	// Goodbyes don't add peers
+	packet, _ = buildMDNSResponse(service, nil, 0)
+	if entries := parseMDNSResponse(packet, net.ParseIP("10.0.0.9")); len(entries) != 0 {
+		t.Errorf("Goodbye packets should be ignored, got %+v", entries)
+	}
+}
+
+func TestMDNSQuery(t *testing.T) {
+	query, err := buildMDNSQuery()
+	if err != nil {
+		t.Fatalf("buildMDNSQuery failed: %v", err)
+	}
+	if !isMDNSQuery(query) {
+		t.Error("Expected our own query to be recognized")
+	}
+	if entries := parseMDNSResponse(query, nil); entries != nil {
+		t.Errorf("Queries carry no instances, got %+v", entries)
+	}
+
+	response, _ := buildMDNSResponse(mdnsService{Instance: "a", Host: "a", Port: 1, NetworkID: "n"}, nil, mdnsTTL)
+	if isMDNSQuery(response) || isMDNSQuery([]byte("garbage")) {
+		t.Error("Responses and garbage are not queries")
+	}
+}
+
+func TestMDNSLabel(t *testing.T) {
+	tests := map[string]string{
+		"My Laptop":    "My-Laptop",
+		"host_1.local": "host-1-local",
+		"":             "companion",
+	}
+	for in, want := range tests {
+		if got := mdnsLabel(in); got != want {
+			t.Errorf("mdnsLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDiscoveryModeConfig(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nm.discovery != DiscoveryBoth {
+		t.Errorf("Expected both discovery mechanisms by default, got %q", nm.discovery)
+	}
+
+	if _, err := NewNetworkManager(NetworkManagerConfig{Discovery: "carrier-pigeon"}); err == nil {
+		t.Error("Unknown discovery modes should be rejected")
+	}
+}
+
+func TestHandleMDNSEntry(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{NetworkID: "pets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nm.handleMDNSEntry(mdnsEntry{
+		Instance: "other-1._ddscompanion._tcp.local.",
+		Port:     1, // Nothing listens here; the connection attempt just fails
+		IP:       net.ParseIP("127.0.0.1"),
+		TXT:      map[string]string{"networkId": "other-net", "peerId": "someone", "caps": "chat"},
+	})
+	if nm.GetPeerCount() != 0 {
+		t.Error("Peers from other networks should be ignored")
+	}
+
+	nm.handleMDNSEntry(mdnsEntry{
+		Instance: "self._ddscompanion._tcp.local.",
+		Port:     1,
+		IP:       net.ParseIP("127.0.0.1"),
+		TXT:      map[string]string{"networkId": "pets", "peerId": nm.GetPeerID(), "caps": "chat"},
+	})
+	if nm.GetPeerCount() != 0 {
+		t.Error("Our own peer ID should be ignored")
+	}
+
+	// Instances on the same network share its ID but not their peer ID
+	nm.handleMDNSEntry(mdnsEntry{
+		Instance: "other-2._ddscompanion._tcp.local.",
+		Port:     1,
+		IP:       net.ParseIP("127.0.0.1"),
+		TXT:      map[string]string{"networkId": "pets", "peerId": "someone", "caps": "chat"},
+	})
+	peers := nm.GetPeers()
+	if len(peers) != 1 || peers[0].ID != "someone" || !reflect.DeepEqual(peers[0].Capabilities, []string{"chat"}) {
+		t.Errorf("Expected the other instance to be added, got %+v", peers)
+	}
+}
+
+func TestSecondInstanceDiscovered(t *testing.T) {
+	first, err := NewNetworkManager(NetworkManagerConfig{NetworkID: "pets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewNetworkManager(NetworkManagerConfig{NetworkID: "pets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.GetPeerID() == second.GetPeerID() {
+		t.Fatal("Instances on the same network need different peer IDs")
+	}
+
+	// Each instance hears its own announcement and the other's
+	for _, nm := range []*NetworkManager{first, second} {
+		for i, announcer := range []*NetworkManager{first, second} {
+			service := newLocalMDNSService(40000+i, announcer.GetNetworkID(), announcer.GetPeerID(), []string{"battle"})
+			packet, err := buildMDNSResponse(service, []net.IP{net.ParseIP("127.0.0.1")}, mdnsTTL)
+			if err != nil {
+				t.Fatalf("buildMDNSResponse failed: %v", err)
+			}
+			for _, entry := range parseMDNSResponse(packet, nil) {
+				nm.handleMDNSEntry(entry)
+			}
+		}
+	}
+
+	for _, pair := range []struct{ nm, other *NetworkManager }{{first, second}, {second, first}} {
+		peers := pair.nm.GetPeers()
+		if len(peers) != 1 || peers[0].ID != pair.other.GetPeerID() {
+			t.Errorf("Expected %s to find only %s, got %+v", pair.nm.GetPeerID(), pair.other.GetPeerID(), peers)
+		}
+	}
+}
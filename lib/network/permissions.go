@@ -0,0 +1,216 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PermissionsFileName is the per-peer permissions file in the user config directory
+const PermissionsFileName = "peer_permissions.json"
+
+// Permission categories that incoming messages are checked against
+const (
+	PermissionBattles = "battles"
+	PermissionGifts   = "gifts"
+	PermissionChats   = "chats"
+)
+
+// PeerPermissions controls what a single peer may send us. Blocked peers
+// are cut off entirely apart from discovery, so they can still be listed
+// and unblocked.
+type PeerPermissions struct {
+	AllowBattles bool `json:"allowBattles"`
+	AllowGifts   bool `json:"allowGifts"`
+	AllowChats   bool `json:"allowChats"`
+	Blocked      bool `json:"blocked"`
+}
+
+// DefaultPeerPermissions allows everything, matching the behaviour before
+// permissions existed
+func DefaultPeerPermissions() PeerPermissions {
+	return PeerPermissions{AllowBattles: true, AllowGifts: true, AllowChats: true}
+}
+
+// Allows reports whether a message in the given category is permitted.
+// Messages without a category are only stopped by blocking.
+func (p PeerPermissions) Allows(category string) bool {
+	if p.Blocked {
+		return false
+	}
+	switch category {
+	case PermissionBattles:
+		return p.AllowBattles
+	case PermissionGifts:
+		return p.AllowGifts
+	case PermissionChats:
+		return p.AllowChats
+	}
+	return true
+}
+
+// PermissionStore keeps per-peer permissions keyed by peer ID, which is the
+// peer's ed25519 public key, and persists them so choices survive restarts.
+// Messages are only checked against the key that signed them.
+type PermissionStore struct {
+	mu    sync.RWMutex
+	path  string // Empty keeps permissions in memory only
+	peers map[string]PeerPermissions
+}
+
+// NewPermissionStore loads permissions from path. A missing file starts an
+// empty store; an empty path never touches disk.
+func NewPermissionStore(path string) (*PermissionStore, error) {
+	ps := &PermissionStore{path: path, peers: make(map[string]PeerPermissions)}
+	if path == "" {
+		return ps, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ps, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read peer permissions: %w", err)
+	}
+	if err := json.Unmarshal(data, &ps.peers); err != nil {
+		return nil, fmt.Errorf("failed to parse peer permissions %s: %w", path, err)
+	}
+	return ps, nil
+}
+
+// Get returns a peer's permissions, or the defaults for unknown peers
+func (ps *PermissionStore) Get(peerID string) PeerPermissions {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	if perms, ok := ps.peers[peerID]; ok {
+		return perms
+	}
+	return DefaultPeerPermissions()
+}
+
+// Set stores a peer's permissions and saves the file
+func (ps *PermissionStore) Set(peerID string, perms PeerPermissions) error {
+	if peerID == "" {
+		return fmt.Errorf("peer ID is required")
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if perms == DefaultPeerPermissions() {
+		delete(ps.peers, peerID)
+	} else {
+		ps.peers[peerID] = perms
+	}
+	return ps.save()
+}
+
+// Allows reports whether peerID may send a message in category
+func (ps *PermissionStore) Allows(peerID, category string) bool {
+	return ps.Get(peerID).Allows(category)
+}
+
+// Peers returns the IDs of peers with non-default permissions, sorted
+func (ps *PermissionStore) Peers() []string {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	ids := make([]string, 0, len(ps.peers))
+	for id := range ps.peers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// save writes the store to disk. Must be called with ps.mu held.
+func (ps *PermissionStore) save() error {
+	if ps.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(ps.peers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode peer permissions: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(ps.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create permissions directory: %w", err)
+	}
+	if err := os.WriteFile(ps.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write peer permissions: %w", err)
+	}
+	return nil
+}
+
+// MessagePermission returns the permission category an incoming message
+// needs, or "" when only blocking applies. Visits stream dialog, so they
+// count as chats. Chat lines and gifts share the character_action type, so
+// its payload is inspected to tell them apart.
+func MessagePermission(msg Message) string {
+	switch msg.Type {
+	case MessageTypeBattleInvite, MessageTypeBattleAction, MessageTypeBattleResult, MessageTypeBattleEnd:
+		return PermissionBattles
+	case MessageTypeConversation, MessageTypeChatRelay, MessageTypeVisit:
+		return PermissionChats
+	case MessageTypeCharacterAction:
+		var payload struct {
+			Type   string `json:"type"`
+			Action string `json:"action"`
+		}
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return ""
+		}
+		switch {
+		case payload.Type == "chat":
+			return PermissionChats
+		case payload.Type == "gift", payload.Action == "gift", payload.Action == "give_gift":
+			return PermissionGifts
+		}
+	}
+	return ""
+}
+
+// permits reports whether a message from sender, the verified peer ID of the
+// key that signed it, may reach its handler. Discovery traffic always passes
+// so blocked peers stay visible.
+func (nm *NetworkManager) permits(msg Message, sender string) bool {
+	nm.mu.RLock()
+	store := nm.permissions
+	nm.mu.RUnlock()
+
+	if store == nil || msg.Type == MessageTypeDiscovery || msg.Type == MessageTypePeerList {
+		return true
+	}
+
+	category := MessagePermission(msg)
+	if store.Allows(sender, category) {
+		return true
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":   getCaller(),
+		"peerID":   sender,
+		"type":     msg.Type,
+		"category": category,
+	}).Debug("Dropping message not permitted for peer")
+	return false
+}
+
+// SetPermissionStore enforces per-peer permissions on incoming messages;
+// nil allows everything
+func (nm *NetworkManager) SetPermissionStore(store *PermissionStore) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.permissions = store
+}
+
+// GetPermissionStore returns the permission store, nil if none is set
+func (nm *NetworkManager) GetPermissionStore() *PermissionStore {
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
+	return nm.permissions
+}
@@ -0,0 +1,217 @@
+package network
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMessagePermission(t *testing.T) {
+	tests := []struct {
+		msg  Message
+		want string
+	}{
+		{Message{Type: MessageTypeBattleInvite}, PermissionBattles},
+		{Message{Type: MessageTypeBattleAction}, PermissionBattles},
+		{Message{Type: MessageTypeConversation}, PermissionChats},
+		{Message{Type: MessageTypeChatRelay}, PermissionChats},
+		{Message{Type: MessageTypeVisit}, PermissionChats},
+		{Message{Type: MessageTypeCharacterAction, Payload: []byte(`{"type":"chat","message":"hi"}`)}, PermissionChats},
+		{Message{Type: MessageTypeCharacterAction, Payload: []byte(`{"action":"give_gift"}`)}, PermissionGifts},
+		{Message{Type: MessageTypeCharacterAction, Payload: []byte(`{"action":"pet"}`)}, ""},
+		{Message{Type: MessageTypeStateSync}, ""},
+	}
+	for _, tt := range tests {
+		if got := MessagePermission(tt.msg); got != tt.want {
+			t.Errorf("%s %s: expected %q, got %q", tt.msg.Type, tt.msg.Payload, tt.want, got)
+		}
+	}
+}
+
+func TestPeerPermissionsAllows(t *testing.T) {
+	perms := DefaultPeerPermissions()
+	perms.AllowBattles = false
+	if perms.Allows(PermissionBattles) || !perms.Allows(PermissionChats) || !perms.Allows("") {
+		t.Errorf("Only battles should be refused: %+v", perms)
+	}
+
+	perms.Blocked = true
+	if perms.Allows(PermissionChats) || perms.Allows("") {
+		t.Error("Blocked peers should be refused everything")
+	}
+}
+
+func TestPermissionStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", PermissionsFileName)
+	store, err := NewPermissionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Get("alice") != DefaultPeerPermissions() {
+		t.Error("Unknown peers should get the defaults")
+	}
+
+	if err := store.Set("alice", PeerPermissions{AllowChats: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("bob", PeerPermissions{Blocked: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewPermissionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Allows("alice", PermissionBattles) || !reloaded.Allows("alice", PermissionChats) {
+		t.Errorf("Expected alice's permissions to persist, got %+v", reloaded.Get("alice"))
+	}
+	if !reloaded.Get("bob").Blocked {
+		t.Error("Expected bob to stay blocked")
+	}
+
+	// Restoring the defaults forgets the peer
+	if err := reloaded.Set("bob", DefaultPeerPermissions()); err != nil {
+		t.Fatal(err)
+	}
+	if peers := reloaded.Peers(); len(peers) != 1 || peers[0] != "alice" {
+		t.Errorf("Expected only alice to be stored, got %v", peers)
+	}
+}
+
+// testPeer is a remote instance with its own identity, connected to nm over a pipe
+type testPeer struct {
+	sender  *NetworkManager // Signs as the remote instance
+	encoder *json.Encoder
+	conn    net.Conn
+}
+
+// connectTestPeer registers a new remote instance with nm and starts reading its connection
+func connectTestPeer(t *testing.T, nm *NetworkManager) *testPeer {
+	t.Helper()
+	sender, err := NewNetworkManager(NetworkManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local, remote := net.Pipe()
+	t.Cleanup(func() { remote.Close() })
+	peer := &Peer{ID: sender.GetPeerID(), Conn: local}
+	go nm.handlePeerConnection(peer, newFrameReader(local))
+	return &testPeer{sender: sender, encoder: json.NewEncoder(remote), conn: remote}
+}
+
+// send signs msg as the remote instance and writes it to the connection
+func (p *testPeer) send(t *testing.T, msg Message) {
+	t.Helper()
+	if msg.From == "" {
+		msg.From = p.sender.GetPeerID()
+	}
+	signed, err := p.sender.signMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.encoder.Encode(signed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// receivedFrom collects which peer each delivered message came from
+func receivedFrom(nm *NetworkManager, types ...MessageType) chan string {
+	received := make(chan string, 8)
+	for _, msgType := range types {
+		msgType := msgType
+		nm.RegisterMessageHandler(msgType, func(msg Message, from *Peer) error {
+			received <- string(msgType) + ":" + from.ID
+			return nil
+		})
+	}
+	return received
+}
+
+// expectReceived checks the delivered messages, in any order, and that nothing else arrives
+func expectReceived(t *testing.T, received chan string, want ...string) {
+	t.Helper()
+	pending := make(map[string]bool)
+	for _, w := range want {
+		pending[w] = true
+	}
+	for len(pending) > 0 {
+		select {
+		case got := <-received:
+			if !pending[got] {
+				t.Errorf("Unexpected message %s", got)
+			}
+			delete(pending, got)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected %v to be delivered", pending)
+		}
+	}
+	select {
+	case got := <-received:
+		t.Errorf("Unexpected message %s", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNetworkManagerDropsUnpermittedMessages(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, _ := NewPermissionStore("")
+	nm.SetPermissionStore(store)
+	received := receivedFrom(nm, MessageTypeBattleInvite, MessageTypeConversation)
+
+	rival := connectTestPeer(t, nm)
+	if err := store.Set(rival.sender.GetPeerID(), PeerPermissions{AllowChats: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	rival.send(t, Message{Type: MessageTypeBattleInvite})
+	rival.send(t, Message{Type: MessageTypeConversation})
+	expectReceived(t, received, "conversation:"+rival.sender.GetPeerID())
+}
+
+func TestPermissionsFollowTheSigningKey(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, _ := NewPermissionStore("")
+	nm.SetPermissionStore(store)
+	received := receivedFrom(nm, MessageTypeBattleInvite, MessageTypeConversation)
+
+	friend := connectTestPeer(t, nm)
+	stranger := connectTestPeer(t, nm)
+	if err := store.Set(friend.sender.GetPeerID(), PeerPermissions{AllowBattles: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(stranger.sender.GetPeerID(), PeerPermissions{AllowChats: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	friend.send(t, Message{Type: MessageTypeBattleInvite})
+	friend.send(t, Message{Type: MessageTypeConversation})
+	stranger.send(t, Message{Type: MessageTypeBattleInvite})
+	stranger.send(t, Message{Type: MessageTypeConversation})
+	expectReceived(t, received,
+		"battle_invite:"+friend.sender.GetPeerID(),
+		"conversation:"+stranger.sender.GetPeerID())
+
+	// The stranger can't borrow the friend's permissions by claiming its ID
+	stranger.send(t, Message{Type: MessageTypeBattleInvite, From: friend.sender.GetPeerID()})
+	// Nor by sending the friend's signed message over its own connection
+	forged, _ := friend.sender.signMessage(Message{Type: MessageTypeBattleInvite, From: friend.sender.GetPeerID()})
+	if err := stranger.encoder.Encode(forged); err != nil {
+		t.Fatal(err)
+	}
+	// Tampering breaks the signature
+	tampered, _ := stranger.sender.signMessage(Message{Type: MessageTypeConversation, From: stranger.sender.GetPeerID()})
+	tampered.Message.Type = MessageTypeBattleInvite
+	if err := stranger.encoder.Encode(tampered); err != nil {
+		t.Fatal(err)
+	}
+	expectReceived(t, received)
+}
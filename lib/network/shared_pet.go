@@ -0,0 +1,655 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MessageTypeSharedPet carries a character co-owned by two peers: the offer
+// to share it, and its stats, interactions and progression while shared.
+// Only the peer the character is shared with is listened to, so it needs no
+// permission beyond not being blocked.
+const MessageTypeSharedPet MessageType = "shared_pet"
+
+// Shared pet message kinds
+const (
+	SharedPetKindOffer   = "offer"   // Owner offers to share its character, with its state
+	SharedPetKindAccept  = "accept"  // Peer took the offer and adopted the state
+	SharedPetKindDecline = "decline" // Peer turned the offer down
+	SharedPetKindUpdate  = "update"  // Stats or progression changed on the sender's side
+	SharedPetKindState   = "state"   // Full state, sent periodically to reconcile
+	SharedPetKindLeave   = "leave"   // Either side stops sharing
+)
+
+const (
+	// DefaultSharedPetReconcileInterval is how often the full state is sent
+	DefaultSharedPetReconcileInterval = 30 * time.Second
+	// sharedStatThreshold is the smallest stat change sent as an update;
+	// slow decay catches up with the next reconciliation instead
+	sharedStatThreshold = 0.5
+	// maxSharedClockSkew is how far ahead of our clock a partner's stat
+	// timestamps may be; later ones are pulled back to it so a partner
+	// with a clock far ahead can't keep winning over our writes
+	maxSharedClockSkew = 5 * time.Second
+)
+
+// A partner normally sends a few updates a minute; one sending faster than
+// this has its messages dropped
+const (
+	sharedPetMessageLimit = 30
+	sharedPetLimitWindow  = 10 * time.Second
+	sharedPetOfferLimit   = 3
+)
+
+// SharedStat is one stat's value with when and by whom it was last set.
+// The last writer wins; ties go to the higher peer ID so both sides agree.
+// Partner timestamps are never trusted beyond maxSharedClockSkew ahead.
+type SharedStat struct {
+	Value   float64   `json:"value"`
+	Updated time.Time `json:"updated"`
+	By      string    `json:"by"`
+}
+
+// newerThan reports whether s wins over other
+func (s SharedStat) newerThan(other SharedStat) bool {
+	if s.Updated.Equal(other.Updated) {
+		return s.By > other.By
+	}
+	return s.Updated.After(other.Updated)
+}
+
+// SharedPetState is the replicated state of a shared character. Stats are
+// last-writer-wins registers; progression only moves forward, so age and
+// interaction counts take the higher value and achievements are a union.
+type SharedPetState struct {
+	Stats        map[string]SharedStat `json:"stats,omitempty"`
+	Age          time.Duration         `json:"age,omitempty"`
+	Achievements []string              `json:"achievements,omitempty"`
+	Interactions map[string]int        `json:"interactions,omitempty"`
+
+	// Future-dated stats already merged, as the partner sent them, so the
+	// partner resending one doesn't override our later writes
+	clamped map[string]SharedStat
+}
+
+// SharedPetValues is the part of a character's state that shared pets keep
+// in sync, as read from and applied to the local character
+type SharedPetValues struct {
+	Stats        map[string]float64
+	Age          time.Duration
+	Achievements []string
+	Interactions map[string]int
+}
+
+// Values returns the state without its timestamps
+func (st *SharedPetState) Values() SharedPetValues {
+	values := SharedPetValues{
+		Stats:        make(map[string]float64, len(st.Stats)),
+		Age:          st.Age,
+		Achievements: append([]string(nil), st.Achievements...),
+		Interactions: make(map[string]int, len(st.Interactions)),
+	}
+	for name, stat := range st.Stats {
+		values.Stats[name] = stat.Value
+	}
+	for interaction, count := range st.Interactions {
+		values.Interactions[interaction] = count
+	}
+	return values
+}
+
+// merge folds remote into the state at now and reports whether anything
+// the character shows changed, and which interactions' counts went up. Age
+// alone doesn't count, it is applied with the next change.
+func (st *SharedPetState) merge(remote SharedPetState, now time.Time) (bool, []string) {
+	if st.Stats == nil {
+		st.Stats = make(map[string]SharedStat)
+	}
+	if st.Interactions == nil {
+		st.Interactions = make(map[string]int)
+	}
+
+	changed := false
+	var interactions []string
+	limit := now.Add(maxSharedClockSkew)
+	for name, stat := range remote.Stats {
+		if stat.Updated.After(limit) {
+			if seen, ok := st.clamped[name]; ok && seen.By == stat.By && seen.Updated.Equal(stat.Updated) {
+				continue // Merged when it first arrived
+			}
+			if st.clamped == nil {
+				st.clamped = make(map[string]SharedStat)
+			}
+			st.clamped[name] = stat
+			stat.Updated = limit
+		}
+		if local, ok := st.Stats[name]; !ok || stat.newerThan(local) {
+			changed = changed || !ok || local.Value != stat.Value
+			st.Stats[name] = stat
+		}
+	}
+	st.Age = max(st.Age, remote.Age)
+	for _, achievement := range remote.Achievements {
+		if st.addAchievement(achievement) {
+			changed = true
+		}
+	}
+	for interaction, count := range remote.Interactions {
+		if count > st.Interactions[interaction] {
+			st.Interactions[interaction] = count
+			interactions = append(interactions, interaction)
+			changed = true
+		}
+	}
+	sort.Strings(interactions)
+	return changed, interactions
+}
+
+// addAchievement adds an achievement, keeping the list sorted. Returns
+// false when it was already there.
+func (st *SharedPetState) addAchievement(achievement string) bool {
+	i := sort.SearchStrings(st.Achievements, achievement)
+	if i < len(st.Achievements) && st.Achievements[i] == achievement {
+		return false
+	}
+	st.Achievements = append(st.Achievements, "")
+	copy(st.Achievements[i+1:], st.Achievements[i:])
+	st.Achievements[i] = achievement
+	return true
+}
+
+// copyState returns a deep copy safe to send while the original changes
+func (st *SharedPetState) copyState() SharedPetState {
+	out := SharedPetState{
+		Stats:        make(map[string]SharedStat, len(st.Stats)),
+		Age:          st.Age,
+		Achievements: append([]string(nil), st.Achievements...),
+		Interactions: make(map[string]int, len(st.Interactions)),
+	}
+	for name, stat := range st.Stats {
+		out.Stats[name] = stat
+	}
+	for interaction, count := range st.Interactions {
+		out.Interactions[interaction] = count
+	}
+	return out
+}
+
+// SharedPetPayload is the wire format of every shared pet message; Kind
+// says which fields are set
+type SharedPetPayload struct {
+	Kind      string          `json:"kind"`
+	Character string          `json:"character,omitempty"` // With offer
+	State     *SharedPetState `json:"state,omitempty"`     // Full with offer and state, the changes with update
+	Reason    string          `json:"reason,omitempty"`    // With decline
+}
+
+// Shared pet event kinds reported to the UI
+const (
+	SharedPetOffered     = "offered"     // A peer offered to share its character
+	SharedPetStarted     = "started"     // Sharing began, from either side
+	SharedPetDeclined    = "declined"    // The peer turned our offer down
+	SharedPetChanged     = "changed"     // The partner's changes won; apply Values to the character
+	SharedPetInteraction = "interaction" // The partner's user interacted with the character, e.g. fed it
+	SharedPetEnded       = "ended"       // Sharing stopped
+)
+
+// SharedPetEvent tells the UI something changed about the shared pet
+type SharedPetEvent struct {
+	Kind        string
+	PeerID      string
+	Character   string          // Offered character, with SharedPetOffered
+	Values      SharedPetValues // State to apply, with SharedPetChanged and when accepting an offer with SharedPetStarted
+	Interaction string          // With SharedPetInteraction
+	Reason      string          // With SharedPetDeclined
+}
+
+// SharedPetTransport is the subset of the network manager used for shared pets
+type SharedPetTransport interface {
+	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+}
+
+// SharedPet lets two peers co-own one character. The owner offers its
+// character with its state; a peer with the same character accepts and
+// adopts that state. From then on each side publishes its local changes as
+// they happen and the full state periodically, and both merge what they
+// receive the same way, so they converge even if an update is lost.
+type SharedPet struct {
+	mu        sync.Mutex
+	transport SharedPetTransport
+	peerID    string // Ours, to break timestamp ties
+	character string
+	partner   string                     // Peer we share the character with, "" when not shared
+	offered   map[string]bool            // Peers we offered the character to
+	offers    map[string]*SharedPetState // Peers offering theirs, to the state it comes with
+	state     SharedPetState
+	interval  time.Duration
+	stop      chan struct{} // Stops the reconcile loop
+	messages  *chatRateLimit
+	incoming  *chatRateLimit
+	onEvent   func(SharedPetEvent)
+}
+
+// NewSharedPet creates shared pet support for characterName on the peer
+// peerID and registers the message handler
+func NewSharedPet(transport SharedPetTransport, peerID, characterName string) *SharedPet {
+	sp := &SharedPet{
+		transport: transport,
+		peerID:    peerID,
+		character: characterName,
+		offered:   make(map[string]bool),
+		offers:    make(map[string]*SharedPetState),
+		interval:  DefaultSharedPetReconcileInterval,
+		messages:  newChatRateLimit(sharedPetMessageLimit, sharedPetLimitWindow),
+		incoming:  newChatRateLimit(sharedPetOfferLimit, sharedPetLimitWindow),
+	}
+
+	if transport != nil {
+		transport.RegisterMessageHandler(MessageTypeSharedPet, sp.handleMessage)
+	}
+	return sp
+}
+
+// SetEventHandler sets the callback told about offers, changes and the
+// partner's interactions
+func (sp *SharedPet) SetEventHandler(handler func(SharedPetEvent)) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.onEvent = handler
+}
+
+// SetCharacter changes the character offered and accepted
+func (sp *SharedPet) SetCharacter(name string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.character = name
+}
+
+// SetReconcileInterval changes how often the full state is sent. Takes
+// effect the next time sharing starts.
+func (sp *SharedPet) SetReconcileInterval(interval time.Duration) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if interval > 0 {
+		sp.interval = interval
+	}
+}
+
+// Partner returns the peer the character is shared with, "" when it isn't
+func (sp *SharedPet) Partner() string {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.partner
+}
+
+// Offers returns the peers offering to share their character with us
+func (sp *SharedPet) Offers() []string {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	peers := make([]string, 0, len(sp.offers))
+	for peerID := range sp.offers {
+		peers = append(peers, peerID)
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// Offer asks a peer to co-own our character, starting from values
+func (sp *SharedPet) Offer(peerID string, values SharedPetValues) error {
+	if peerID == "" {
+		return fmt.Errorf("no peer selected")
+	}
+
+	sp.mu.Lock()
+	if sp.partner != "" {
+		sp.mu.Unlock()
+		return fmt.Errorf("already sharing with %s", sp.partner)
+	}
+	sp.state = SharedPetState{}
+	sp.recordLocked(values, time.Now())
+	state := sp.state.copyState()
+	sp.offered[peerID] = true
+	character := sp.character
+	sp.mu.Unlock()
+
+	if err := sp.send(peerID, SharedPetPayload{Kind: SharedPetKindOffer, Character: character, State: &state}); err != nil {
+		sp.mu.Lock()
+		delete(sp.offered, peerID)
+		sp.mu.Unlock()
+		return fmt.Errorf("failed to send shared pet offer: %w", err)
+	}
+	return nil
+}
+
+// Accept takes a peer's offer: our character adopts the offered state,
+// reported with SharedPetStarted, and the peer is told
+func (sp *SharedPet) Accept(peerID string) error {
+	sp.mu.Lock()
+	if sp.partner != "" {
+		sp.mu.Unlock()
+		return fmt.Errorf("already sharing with %s", sp.partner)
+	}
+	state, ok := sp.offers[peerID]
+	if !ok {
+		sp.mu.Unlock()
+		return fmt.Errorf("no offer from %s", peerID)
+	}
+	delete(sp.offers, peerID)
+	sp.state = SharedPetState{}
+	sp.state.merge(*state, time.Now()) // Adopted as a whole, nothing to report yet
+	values := sp.state.Values()
+	sp.mu.Unlock()
+
+	// Sharing starts first so the partner's first update isn't dropped
+	sp.start(peerID)
+	if err := sp.send(peerID, SharedPetPayload{Kind: SharedPetKindAccept}); err != nil {
+		sp.mu.Lock()
+		sp.stopLocked()
+		sp.mu.Unlock()
+		return fmt.Errorf("failed to accept shared pet: %w", err)
+	}
+	sp.emit(SharedPetEvent{Kind: SharedPetStarted, PeerID: peerID, Values: values})
+	return nil
+}
+
+// Decline turns down a peer's offer
+func (sp *SharedPet) Decline(peerID string) error {
+	sp.mu.Lock()
+	_, ok := sp.offers[peerID]
+	delete(sp.offers, peerID)
+	sp.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return sp.send(peerID, SharedPetPayload{Kind: SharedPetKindDecline, Reason: "declined"})
+}
+
+// Publish sends the partner whatever changed in our character since the
+// last call: stats that moved by at least sharedStatThreshold, new
+// achievements and interaction counts, which tell the partner what our user
+// did. Age is only reconciled. Cheap when nothing changed, so it can be
+// called every frame.
+func (sp *SharedPet) Publish(values SharedPetValues) {
+	sp.mu.Lock()
+	partner := sp.partner
+	if partner == "" {
+		sp.mu.Unlock()
+		return
+	}
+	changes := sp.recordLocked(values, time.Now())
+	sp.mu.Unlock()
+
+	if changes == nil {
+		return
+	}
+	if err := sp.send(partner, SharedPetPayload{Kind: SharedPetKindUpdate, State: changes}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": partner,
+			"error":  err.Error(),
+		}).Debug("Failed to send shared pet update")
+	}
+}
+
+// recordLocked stamps our local changes into the state as written by us at
+// now and returns them, or nil when there are none. Must be called with
+// sp.mu held.
+func (sp *SharedPet) recordLocked(values SharedPetValues, now time.Time) *SharedPetState {
+	if sp.state.Stats == nil {
+		sp.state.Stats = make(map[string]SharedStat)
+	}
+	if sp.state.Interactions == nil {
+		sp.state.Interactions = make(map[string]int)
+	}
+
+	changes := &SharedPetState{}
+	changed := false
+	for name, value := range values.Stats {
+		if known, ok := sp.state.Stats[name]; ok && math.Abs(known.Value-value) < sharedStatThreshold {
+			continue
+		}
+		stat := SharedStat{Value: value, Updated: now, By: sp.peerID}
+		sp.state.Stats[name] = stat
+		if changes.Stats == nil {
+			changes.Stats = make(map[string]SharedStat)
+		}
+		changes.Stats[name] = stat
+		changed = true
+	}
+	sp.state.Age = max(sp.state.Age, values.Age)
+	changes.Age = sp.state.Age
+	for _, achievement := range values.Achievements {
+		if sp.state.addAchievement(achievement) {
+			changes.Achievements = append(changes.Achievements, achievement)
+			changed = true
+		}
+	}
+	for interaction, count := range values.Interactions {
+		if count > sp.state.Interactions[interaction] {
+			sp.state.Interactions[interaction] = count
+			if changes.Interactions == nil {
+				changes.Interactions = make(map[string]int)
+			}
+			changes.Interactions[interaction] = count
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return changes
+}
+
+// Reconcile sends the partner the full state, as the reconcile loop does
+func (sp *SharedPet) Reconcile() {
+	sp.mu.Lock()
+	partner := sp.partner
+	state := sp.state.copyState()
+	sp.mu.Unlock()
+	if partner == "" {
+		return
+	}
+
+	if err := sp.send(partner, SharedPetPayload{Kind: SharedPetKindState, State: &state}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": partner,
+			"error":  err.Error(),
+		}).Debug("Failed to send shared pet state")
+	}
+}
+
+// Leave stops sharing the character; each side keeps its own copy
+func (sp *SharedPet) Leave() error {
+	sp.mu.Lock()
+	partner := sp.partner
+	sp.stopLocked()
+	sp.mu.Unlock()
+
+	if partner == "" {
+		return nil
+	}
+	sp.emit(SharedPetEvent{Kind: SharedPetEnded, PeerID: partner})
+	return sp.send(partner, SharedPetPayload{Kind: SharedPetKindLeave})
+}
+
+// start makes peerID the partner and runs the reconcile loop
+func (sp *SharedPet) start(peerID string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.stopLocked()
+	sp.partner = peerID
+	sp.offered = make(map[string]bool)
+	sp.stop = make(chan struct{})
+	go sp.reconcileLoop(sp.stop, sp.interval)
+}
+
+// stopLocked ends sharing and the reconcile loop. Must be called with
+// sp.mu held.
+func (sp *SharedPet) stopLocked() {
+	sp.partner = ""
+	if sp.stop != nil {
+		close(sp.stop)
+		sp.stop = nil
+	}
+}
+
+// reconcileLoop sends the full state every interval until stop is closed
+func (sp *SharedPet) reconcileLoop(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			sp.Reconcile()
+		}
+	}
+}
+
+// handleMessage dispatches a shared pet message by kind
+func (sp *SharedPet) handleMessage(msg Message, from *Peer) error {
+	if from == nil {
+		return fmt.Errorf("shared pet message without sender")
+	}
+
+	var payload SharedPetPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse shared pet message: %w", err)
+	}
+
+	sp.mu.Lock()
+	allowed := sp.messages.allow(from.ID, time.Now())
+	sp.mu.Unlock()
+	if !allowed {
+		return nil
+	}
+
+	switch payload.Kind {
+	case SharedPetKindOffer:
+		return sp.handleOffer(from.ID, payload)
+	case SharedPetKindAccept:
+		sp.handleAccept(from.ID)
+	case SharedPetKindDecline:
+		sp.mu.Lock()
+		offered := sp.offered[from.ID]
+		delete(sp.offered, from.ID)
+		sp.mu.Unlock()
+		if offered {
+			sp.emit(SharedPetEvent{Kind: SharedPetDeclined, PeerID: from.ID, Reason: payload.Reason})
+		}
+	case SharedPetKindUpdate, SharedPetKindState:
+		sp.handleState(from.ID, payload.State)
+	case SharedPetKindLeave:
+		sp.mu.Lock()
+		partner := sp.partner == from.ID
+		if partner {
+			sp.stopLocked()
+		}
+		delete(sp.offers, from.ID)
+		sp.mu.Unlock()
+		if partner {
+			sp.emit(SharedPetEvent{Kind: SharedPetEnded, PeerID: from.ID})
+		}
+	default:
+		return fmt.Errorf("unknown shared pet message kind %q", payload.Kind)
+	}
+	return nil
+}
+
+// handleOffer holds an offer for the user to accept, declining at once
+// when we run a different character or offers come too fast
+func (sp *SharedPet) handleOffer(peerID string, payload SharedPetPayload) error {
+	if payload.State == nil {
+		return fmt.Errorf("shared pet offer from %s without state", peerID)
+	}
+
+	sp.mu.Lock()
+	if !sp.incoming.allow(peerID, time.Now()) {
+		sp.mu.Unlock()
+		return nil
+	}
+	character := sp.character
+	if payload.Character != character {
+		sp.mu.Unlock()
+		return sp.send(peerID, SharedPetPayload{Kind: SharedPetKindDecline, Reason: fmt.Sprintf("runs %s, not %s", character, payload.Character)})
+	}
+	if sp.partner != "" {
+		sp.mu.Unlock()
+		return sp.send(peerID, SharedPetPayload{Kind: SharedPetKindDecline, Reason: "already sharing"})
+	}
+	sp.offers[peerID] = payload.State
+	sp.mu.Unlock()
+
+	sp.emit(SharedPetEvent{Kind: SharedPetOffered, PeerID: peerID, Character: payload.Character})
+	return nil
+}
+
+// handleAccept starts sharing with a peer that took our offer
+func (sp *SharedPet) handleAccept(peerID string) {
+	sp.mu.Lock()
+	offered := sp.offered[peerID] && sp.partner == ""
+	sp.mu.Unlock()
+	if !offered {
+		return
+	}
+
+	// Our character already has the state, so no Values
+	sp.start(peerID)
+	sp.emit(SharedPetEvent{Kind: SharedPetStarted, PeerID: peerID})
+}
+
+// handleState merges the partner's changes or full state, reporting the
+// interactions behind them and the changes that won
+func (sp *SharedPet) handleState(peerID string, state *SharedPetState) {
+	if state == nil {
+		return
+	}
+
+	sp.mu.Lock()
+	if sp.partner != peerID {
+		sp.mu.Unlock()
+		return
+	}
+	changed, interactions := sp.state.merge(*state, time.Now())
+	values := sp.state.Values()
+	sp.mu.Unlock()
+
+	for _, interaction := range interactions {
+		sp.emit(SharedPetEvent{Kind: SharedPetInteraction, PeerID: peerID, Interaction: interaction})
+	}
+	if changed {
+		sp.emit(SharedPetEvent{Kind: SharedPetChanged, PeerID: peerID, Values: values})
+	}
+}
+
+// send encodes and sends a shared pet message
+func (sp *SharedPet) send(peerID string, payload SharedPetPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode shared pet message: %w", err)
+	}
+	return sp.transport.SendMessage(MessageTypeSharedPet, data, peerID)
+}
+
+// emit hands an event to the UI
+func (sp *SharedPet) emit(event SharedPetEvent) {
+	sp.mu.Lock()
+	handler := sp.onEvent
+	sp.mu.Unlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
@@ -0,0 +1,173 @@
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSharedPetOfferAndSync(t *testing.T) {
+	a, b := newTransportPair()
+	owner := NewSharedPet(a, "peer-a", "Pixel")
+	partner := NewSharedPet(b, "peer-b", "Pixel")
+
+	var ownerEvents, partnerEvents []SharedPetEvent
+	owner.SetEventHandler(func(e SharedPetEvent) { ownerEvents = append(ownerEvents, e) })
+	partner.SetEventHandler(func(e SharedPetEvent) { partnerEvents = append(partnerEvents, e) })
+
+	start := SharedPetValues{
+		Stats:        map[string]float64{"hunger": 80, "happiness": 60},
+		Age:          time.Hour,
+		Achievements: []string{"first_steps"},
+		Interactions: map[string]int{"feed": 3},
+	}
+	if err := owner.Offer("peer-b", start); err != nil {
+		t.Fatalf("Offer failed: %v", err)
+	}
+	if len(partnerEvents) != 1 || partnerEvents[0].Kind != SharedPetOffered || partnerEvents[0].Character != "Pixel" {
+		t.Fatalf("Expected the offer, got %+v", partnerEvents)
+	}
+	if offers := partner.Offers(); len(offers) != 1 || offers[0] != "peer-a" {
+		t.Fatalf("Expected the offer to wait for the user, got %v", offers)
+	}
+
+	if err := partner.Accept("peer-a"); err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	adopted := partnerEvents[len(partnerEvents)-1]
+	if adopted.Kind != SharedPetStarted || adopted.Values.Stats["hunger"] != 80 || adopted.Values.Age != time.Hour ||
+		len(adopted.Values.Achievements) != 1 || adopted.Values.Interactions["feed"] != 3 {
+		t.Fatalf("Expected the partner to adopt the owner's state, got %+v", adopted)
+	}
+	if len(ownerEvents) != 1 || ownerEvents[0].Kind != SharedPetStarted {
+		t.Fatalf("Expected the owner to be told, got %+v", ownerEvents)
+	}
+	if owner.Partner() != "peer-b" || partner.Partner() != "peer-a" {
+		t.Fatalf("Expected both sides to share, got %q and %q", owner.Partner(), partner.Partner())
+	}
+
+	// The partner feeds the pet; the owner gets the interaction and the new stat
+	partner.Publish(SharedPetValues{
+		Stats:        map[string]float64{"hunger": 95, "happiness": 60.2}, // Happiness moved too little to send
+		Interactions: map[string]int{"feed": 4},
+	})
+	if len(ownerEvents) != 3 || ownerEvents[1].Kind != SharedPetInteraction || ownerEvents[1].Interaction != "feed" {
+		t.Fatalf("Expected the interaction, got %+v", ownerEvents)
+	}
+	changed := ownerEvents[2]
+	if changed.Kind != SharedPetChanged || changed.Values.Stats["hunger"] != 95 || changed.Values.Stats["happiness"] != 60 ||
+		changed.Values.Interactions["feed"] != 4 {
+		t.Fatalf("Expected the partner's change to win, got %+v", changed)
+	}
+
+	// Publishing what was just received sends nothing back
+	partnerCount := len(partnerEvents)
+	owner.Publish(changed.Values)
+	if len(partnerEvents) != partnerCount {
+		t.Errorf("Expected applied changes not to echo, got %+v", partnerEvents[partnerCount:])
+	}
+
+	// Reconciling agreed state changes nothing
+	owner.Reconcile()
+	if len(partnerEvents) != partnerCount {
+		t.Errorf("Expected reconciliation of the same state to be quiet, got %+v", partnerEvents[partnerCount:])
+	}
+
+	if err := partner.Leave(); err != nil {
+		t.Fatalf("Leave failed: %v", err)
+	}
+	if owner.Partner() != "" || ownerEvents[len(ownerEvents)-1].Kind != SharedPetEnded {
+		t.Errorf("Expected the owner to stop sharing, got %+v", ownerEvents)
+	}
+}
+
+func TestSharedPetDeclinesOtherCharacters(t *testing.T) {
+	a, b := newTransportPair()
+	owner := NewSharedPet(a, "peer-a", "Pixel")
+	other := NewSharedPet(b, "peer-b", "Luna")
+
+	var events []SharedPetEvent
+	owner.SetEventHandler(func(e SharedPetEvent) { events = append(events, e) })
+
+	if err := owner.Offer("peer-b", SharedPetValues{Stats: map[string]float64{"hunger": 50}}); err != nil {
+		t.Fatalf("Offer failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != SharedPetDeclined || events[0].Reason == "" {
+		t.Fatalf("Expected a different character to decline, got %+v", events)
+	}
+	if len(other.Offers()) != 0 {
+		t.Errorf("Expected no offer to be held, got %v", other.Offers())
+	}
+}
+
+func TestSharedPetStateMerge(t *testing.T) {
+	now := time.Now()
+	local := SharedPetState{
+		Stats: map[string]SharedStat{
+			"hunger":    {Value: 40, Updated: now, By: "peer-a"},
+			"happiness": {Value: 70, Updated: now, By: "peer-a"},
+		},
+		Age:          2 * time.Hour,
+		Achievements: []string{"b"},
+		Interactions: map[string]int{"feed": 5, "play": 1},
+	}
+	remote := SharedPetState{
+		Stats: map[string]SharedStat{
+			"hunger":    {Value: 90, Updated: now.Add(-time.Minute), By: "peer-b"}, // Older, loses
+			"happiness": {Value: 20, Updated: now, By: "peer-b"},                   // Tie, higher peer ID wins
+			"energy":    {Value: 10, Updated: now, By: "peer-b"},                   // New
+		},
+		Age:          time.Hour,
+		Achievements: []string{"a", "b"},
+		Interactions: map[string]int{"feed": 2, "play": 4},
+	}
+
+	changed, interactions := local.merge(remote, now)
+	if !changed {
+		t.Fatal("Expected the merge to change the state")
+	}
+	if len(interactions) != 1 || interactions[0] != "play" {
+		t.Errorf("Expected the raised count to be reported, got %v", interactions)
+	}
+	if local.Stats["hunger"].Value != 40 || local.Stats["happiness"].Value != 20 || local.Stats["energy"].Value != 10 {
+		t.Errorf("Unexpected stats after merge: %+v", local.Stats)
+	}
+	if local.Age != 2*time.Hour {
+		t.Errorf("Expected the older age to stay, got %v", local.Age)
+	}
+	if len(local.Achievements) != 2 || local.Achievements[0] != "a" || local.Achievements[1] != "b" {
+		t.Errorf("Expected the union of achievements, got %v", local.Achievements)
+	}
+	if local.Interactions["feed"] != 5 || local.Interactions["play"] != 4 {
+		t.Errorf("Expected the higher counts, got %v", local.Interactions)
+	}
+
+	if changed, _ := local.merge(remote, now); changed {
+		t.Error("Expected merging the same state again to change nothing")
+	}
+}
+
+func TestSharedPetStateMergeClampsFutureStats(t *testing.T) {
+	now := time.Now()
+	local := SharedPetState{}
+	future := SharedPetState{Stats: map[string]SharedStat{
+		"hunger": {Value: 90, Updated: now.Add(24 * time.Hour), By: "peer-b"},
+	}}
+
+	local.merge(future, now)
+	if got := local.Stats["hunger"]; got.Value != 90 || !got.Updated.Equal(now.Add(maxSharedClockSkew)) {
+		t.Fatalf("Expected the future-dated stat pulled back to the skew allowance, got %+v", got)
+	}
+
+	// Our later write survives the partner resending its future-dated stat
+	later := now.Add(maxSharedClockSkew + time.Second)
+	local.Stats["hunger"] = SharedStat{Value: 40, Updated: later, By: "peer-a"}
+	if changed, _ := local.merge(future, later); changed || local.Stats["hunger"].Value != 40 {
+		t.Errorf("Expected the later local write to win, got %+v", local.Stats["hunger"])
+	}
+
+	// New writes from the partner still get through
+	future.Stats["hunger"] = SharedStat{Value: 70, Updated: now.Add(25 * time.Hour), By: "peer-b"}
+	if changed, _ := local.merge(future, later); !changed || local.Stats["hunger"].Value != 70 {
+		t.Errorf("Expected the partner's new write to win, got %+v", local.Stats["hunger"])
+	}
+}
@@ -0,0 +1,531 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Battle spectating message types. The battle initiator announces its battle,
+// other peers ask to watch, and the initiator relays state updates to every
+// accepted spectator.
+const (
+	MessageTypeBattleAnnounce        MessageType = "battle_announce"
+	MessageTypeBattleSpectate        MessageType = "battle_spectate"
+	MessageTypeBattleSpectateReply   MessageType = "battle_spectate_reply"
+	MessageTypeBattleSpectatorUpdate MessageType = "battle_spectator_update"
+)
+
+// SpectatorPolicy controls who may watch a battle. Only the battle initiator
+// sets it; spectators can never change a battle.
+type SpectatorPolicy int
+
+const (
+	// SpectatorsDenied rejects every spectate request (default)
+	SpectatorsDenied SpectatorPolicy = iota
+	// SpectatorsAllowed accepts every spectate request
+	SpectatorsAllowed
+	// SpectatorsAsk asks the initiator to approve each request
+	SpectatorsAsk
+)
+
+// BattleAnnouncePayload tells peers a battle is running and may be watched.
+// Ended announcements remove the battle from peers' lists.
+type BattleAnnouncePayload struct {
+	BattleID     string    `json:"battleId"`
+	HostID       string    `json:"hostId"`
+	Participants []string  `json:"participants"`
+	Ended        bool      `json:"ended,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// BattleSpectatePayload asks to start (or stop) watching a battle
+type BattleSpectatePayload struct {
+	BattleID string `json:"battleId"`
+	Stop     bool   `json:"stop,omitempty"`
+}
+
+// BattleSpectateReplyPayload answers a spectate request
+type BattleSpectateReplyPayload struct {
+	BattleID string `json:"battleId"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// BattleSpectatorUpdatePayload is the read-only battle view sent to spectators
+type BattleSpectatorUpdatePayload struct {
+	BattleID     string                            `json:"battleId"`
+	Participants map[string]BattleParticipantStats `json:"participants"`
+	LastAction   string                            `json:"lastAction,omitempty"` // Human readable summary
+	Ended        bool                              `json:"ended,omitempty"`
+	Winner       string                            `json:"winner,omitempty"`
+	Timestamp    time.Time                         `json:"timestamp"`
+}
+
+// BattleListing is a battle another peer announced
+type BattleListing struct {
+	BattleID     string
+	HostID       string
+	Participants []string
+	LastSeen     time.Time
+}
+
+// hostedBattle is a battle this peer started and may relay to spectators
+type hostedBattle struct {
+	participants []string
+	policy       SpectatorPolicy
+	spectators   map[string]bool
+	last         *BattleSpectatorUpdatePayload
+}
+
+// SpectatorHub implements both sides of battle spectating over an existing
+// network manager: hosting battles this peer initiated and watching battles
+// other peers announced.
+type SpectatorHub struct {
+	mu sync.Mutex
+
+	networkManager NetworkManagerInterface
+	hosted         map[string]*hostedBattle
+	listings       map[string]*BattleListing
+	watching       string // Battle ID being watched, empty if none
+
+	// Approve decides SpectatorsAsk requests; nil rejects them.
+	// It must call respond exactly once, from any goroutine.
+	Approve func(battleID, peerID string, respond func(accepted bool))
+
+	onListings func([]BattleListing)
+	onUpdate   func(BattleSpectatorUpdatePayload)
+	onReply    func(BattleSpectateReplyPayload)
+}
+
+// NewSpectatorHub creates a hub and registers its message handlers. Battle
+// results and endings are observed rather than handled, so the battle code
+// keeps its own handlers for them.
+func NewSpectatorHub(nm NetworkManagerInterface) *SpectatorHub {
+	h := &SpectatorHub{
+		networkManager: nm,
+		hosted:         make(map[string]*hostedBattle),
+		listings:       make(map[string]*BattleListing),
+	}
+
+	nm.RegisterMessageHandler(MessageTypeBattleAnnounce, h.handleAnnounce)
+	nm.RegisterMessageHandler(MessageTypeBattleSpectate, h.handleSpectate)
+	nm.RegisterMessageHandler(MessageTypeBattleSpectateReply, h.handleReply)
+	nm.RegisterMessageHandler(MessageTypeBattleSpectatorUpdate, h.handleUpdate)
+	nm.AddMessageObserver(MessageTypeBattleResult, h.handleBattleResult)
+	nm.AddMessageObserver(MessageTypeBattleEnd, h.handleBattleEnd)
+
+	return h
+}
+
+// SetCallbacks sets the functions called when the list of watchable battles
+// changes, when a watched battle updates, and when a spectate request is answered
+func (h *SpectatorHub) SetCallbacks(onListings func([]BattleListing), onUpdate func(BattleSpectatorUpdatePayload), onReply func(BattleSpectateReplyPayload)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onListings = onListings
+	h.onUpdate = onUpdate
+	h.onReply = onReply
+}
+
+// HostBattle registers a battle this peer initiated. Unless the policy is
+// SpectatorsDenied the battle is announced so other peers can ask to watch.
+func (h *SpectatorHub) HostBattle(battleID string, participants []string, policy SpectatorPolicy) error {
+	h.mu.Lock()
+	h.hosted[battleID] = &hostedBattle{
+		participants: participants,
+		policy:       policy,
+		spectators:   make(map[string]bool),
+	}
+	h.mu.Unlock()
+
+	if policy == SpectatorsDenied {
+		return nil
+	}
+	return h.send(MessageTypeBattleAnnounce, BattleAnnouncePayload{
+		BattleID:     battleID,
+		HostID:       h.networkManager.GetPeerID(),
+		Participants: participants,
+		Timestamp:    time.Now(),
+	}, "")
+}
+
+// SetPolicy changes who may watch a hosted battle. Existing spectators are
+// dropped when spectating is turned off.
+func (h *SpectatorHub) SetPolicy(battleID string, policy SpectatorPolicy) error {
+	h.mu.Lock()
+	battle, exists := h.hosted[battleID]
+	if !exists {
+		h.mu.Unlock()
+		return fmt.Errorf("battle %s is not hosted here", battleID)
+	}
+	battle.policy = policy
+	var dropped []string
+	if policy == SpectatorsDenied {
+		dropped = sortedPeers(battle.spectators)
+		battle.spectators = make(map[string]bool)
+	}
+	h.mu.Unlock()
+
+	for _, peerID := range dropped {
+		h.send(MessageTypeBattleSpectateReply, BattleSpectateReplyPayload{
+			BattleID: battleID,
+			Reason:   "spectating was turned off",
+		}, peerID)
+	}
+	return nil
+}
+
+// Spectators returns the peers watching a hosted battle
+func (h *SpectatorHub) Spectators(battleID string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if battle, exists := h.hosted[battleID]; exists {
+		return sortedPeers(battle.spectators)
+	}
+	return nil
+}
+
+// PublishUpdate sends the current state of a hosted battle to its spectators.
+// Ending the battle also withdraws its announcement.
+func (h *SpectatorHub) PublishUpdate(update BattleSpectatorUpdatePayload) error {
+	h.mu.Lock()
+	battle, exists := h.hosted[update.BattleID]
+	if !exists {
+		h.mu.Unlock()
+		return fmt.Errorf("battle %s is not hosted here", update.BattleID)
+	}
+	if update.Timestamp.IsZero() {
+		update.Timestamp = time.Now()
+	}
+	battle.last = &update
+	spectators := sortedPeers(battle.spectators)
+	policy := battle.policy
+	if update.Ended {
+		delete(h.hosted, update.BattleID)
+	}
+	h.mu.Unlock()
+
+	var firstErr error
+	for _, peerID := range spectators {
+		if err := h.send(MessageTypeBattleSpectatorUpdate, update, peerID); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if update.Ended && policy != SpectatorsDenied {
+		h.send(MessageTypeBattleAnnounce, BattleAnnouncePayload{
+			BattleID:  update.BattleID,
+			HostID:    h.networkManager.GetPeerID(),
+			Ended:     true,
+			Timestamp: time.Now(),
+		}, "")
+	}
+	return firstErr
+}
+
+// Listings returns battles announced by other peers
+func (h *SpectatorHub) Listings() []BattleListing {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.listingsLocked()
+}
+
+// Spectate asks a battle's host for permission to watch it. The answer
+// arrives through the reply callback; updates follow if accepted.
+func (h *SpectatorHub) Spectate(battleID string) error {
+	h.mu.Lock()
+	listing, exists := h.listings[battleID]
+	if !exists {
+		h.mu.Unlock()
+		return fmt.Errorf("battle %s is not available to watch", battleID)
+	}
+	previous := h.watching
+	h.watching = battleID
+	hostID := listing.HostID
+	h.mu.Unlock()
+
+	if previous != "" && previous != battleID {
+		h.StopSpectating(previous)
+	}
+	return h.send(MessageTypeBattleSpectate, BattleSpectatePayload{BattleID: battleID}, hostID)
+}
+
+// StopSpectating tells the host we no longer want updates
+func (h *SpectatorHub) StopSpectating(battleID string) error {
+	h.mu.Lock()
+	if h.watching == battleID {
+		h.watching = ""
+	}
+	listing, exists := h.listings[battleID]
+	h.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+	return h.send(MessageTypeBattleSpectate, BattleSpectatePayload{BattleID: battleID, Stop: true}, listing.HostID)
+}
+
+// Watching returns the battle being watched, or "" if none
+func (h *SpectatorHub) Watching() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.watching
+}
+
+// handleAnnounce tracks battles other peers are willing to show. The host is
+// the peer the message came from, and only that peer may update or end the
+// listing afterwards.
+func (h *SpectatorHub) handleAnnounce(msg Message, from *Peer) error {
+	var payload BattleAnnouncePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal battle announce payload: %w", err)
+	}
+
+	h.mu.Lock()
+	if listing, exists := h.listings[payload.BattleID]; exists && listing.HostID != from.ID {
+		h.mu.Unlock()
+		return nil
+	}
+	if payload.Ended {
+		delete(h.listings, payload.BattleID)
+	} else {
+		h.listings[payload.BattleID] = &BattleListing{
+			BattleID:     payload.BattleID,
+			HostID:       from.ID,
+			Participants: payload.Participants,
+			LastSeen:     time.Now(),
+		}
+	}
+	listings := h.listingsLocked()
+	callback := h.onListings
+	h.mu.Unlock()
+
+	if callback != nil {
+		callback(listings)
+	}
+	return nil
+}
+
+// handleSpectate applies the initiator's policy to a spectate request.
+// Spectators are keyed by the sending peer's identity.
+func (h *SpectatorHub) handleSpectate(msg Message, from *Peer) error {
+	var payload BattleSpectatePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal spectate payload: %w", err)
+	}
+	peerID := from.ID
+
+	h.mu.Lock()
+	battle, exists := h.hosted[payload.BattleID]
+	if !exists {
+		h.mu.Unlock()
+		return h.send(MessageTypeBattleSpectateReply, BattleSpectateReplyPayload{
+			BattleID: payload.BattleID,
+			Reason:   "battle not found",
+		}, peerID)
+	}
+	if payload.Stop {
+		delete(battle.spectators, peerID)
+		h.mu.Unlock()
+		return nil
+	}
+	policy := battle.policy
+	approve := h.Approve
+	h.mu.Unlock()
+
+	switch {
+	case policy == SpectatorsAllowed:
+		return h.admitSpectator(payload.BattleID, peerID)
+	case policy == SpectatorsAsk && approve != nil:
+		approve(payload.BattleID, peerID, func(accepted bool) {
+			if accepted {
+				h.admitSpectator(payload.BattleID, peerID)
+				return
+			}
+			h.send(MessageTypeBattleSpectateReply, BattleSpectateReplyPayload{
+				BattleID: payload.BattleID,
+				Reason:   "the host declined",
+			}, peerID)
+		})
+		return nil
+	default:
+		return h.send(MessageTypeBattleSpectateReply, BattleSpectateReplyPayload{
+			BattleID: payload.BattleID,
+			Reason:   "spectators are not allowed",
+		}, peerID)
+	}
+}
+
+// admitSpectator adds a spectator and sends the latest state right away
+func (h *SpectatorHub) admitSpectator(battleID, peerID string) error {
+	h.mu.Lock()
+	battle, exists := h.hosted[battleID]
+	if !exists {
+		h.mu.Unlock()
+		return fmt.Errorf("battle %s is not hosted here", battleID)
+	}
+	battle.spectators[peerID] = true
+	last := battle.last
+	h.mu.Unlock()
+
+	if err := h.send(MessageTypeBattleSpectateReply, BattleSpectateReplyPayload{BattleID: battleID, Accepted: true}, peerID); err != nil {
+		return err
+	}
+	if last != nil {
+		return h.send(MessageTypeBattleSpectatorUpdate, *last, peerID)
+	}
+	return nil
+}
+
+// handleReply passes the host's answer to the UI
+func (h *SpectatorHub) handleReply(msg Message, from *Peer) error {
+	var payload BattleSpectateReplyPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal spectate reply payload: %w", err)
+	}
+
+	h.mu.Lock()
+	if !h.isHostLocked(payload.BattleID, from.ID) {
+		h.mu.Unlock()
+		return nil // Only the battle's host can answer for it
+	}
+	if !payload.Accepted && h.watching == payload.BattleID {
+		h.watching = ""
+	}
+	callback := h.onReply
+	h.mu.Unlock()
+
+	if callback != nil {
+		callback(payload)
+	}
+	return nil
+}
+
+// handleUpdate passes updates for the watched battle to the UI
+func (h *SpectatorHub) handleUpdate(msg Message, from *Peer) error {
+	var payload BattleSpectatorUpdatePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal spectator update payload: %w", err)
+	}
+
+	h.mu.Lock()
+	if h.watching != payload.BattleID || !h.isHostLocked(payload.BattleID, from.ID) {
+		h.mu.Unlock()
+		return nil // Stale update, or one not sent by the battle's host
+	}
+	if payload.Ended {
+		h.watching = ""
+		delete(h.listings, payload.BattleID)
+	}
+	callback := h.onUpdate
+	h.mu.Unlock()
+
+	if callback != nil {
+		callback(payload)
+	}
+	return nil
+}
+
+// handleBattleResult relays an opponent's battle result to spectators
+func (h *SpectatorHub) handleBattleResult(msg Message, from *Peer) error {
+	var payload BattleResultPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal battle result payload: %w", err)
+	}
+	if !h.isHosted(payload.BattleID) {
+		return nil
+	}
+
+	return h.PublishUpdate(BattleSpectatorUpdatePayload{
+		BattleID:     payload.BattleID,
+		Participants: payload.ParticipantStats,
+		LastAction:   describeBattleResult(payload),
+	})
+}
+
+// handleBattleEnd tells spectators how a hosted battle ended
+func (h *SpectatorHub) handleBattleEnd(msg Message, from *Peer) error {
+	var payload BattleEndPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal battle end payload: %w", err)
+	}
+	if !h.isHosted(payload.BattleID) {
+		return nil
+	}
+
+	h.mu.Lock()
+	var participants map[string]BattleParticipantStats
+	if last := h.hosted[payload.BattleID].last; last != nil {
+		participants = last.Participants
+	}
+	h.mu.Unlock()
+
+	return h.PublishUpdate(BattleSpectatorUpdatePayload{
+		BattleID:     payload.BattleID,
+		Participants: participants,
+		LastAction:   fmt.Sprintf("Battle over (%s)", payload.Reason),
+		Ended:        true,
+		Winner:       payload.Winner,
+	})
+}
+
+// isHosted reports whether a battle was started by this peer
+func (h *SpectatorHub) isHosted(battleID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, exists := h.hosted[battleID]
+	return exists
+}
+
+// isHostLocked reports whether peerID announced the listed battle; caller
+// must hold h.mu
+func (h *SpectatorHub) isHostLocked(battleID, peerID string) bool {
+	listing, exists := h.listings[battleID]
+	return exists && listing.HostID == peerID
+}
+
+// listingsLocked returns listings sorted by battle ID; caller must hold h.mu
+func (h *SpectatorHub) listingsLocked() []BattleListing {
+	listings := make([]BattleListing, 0, len(h.listings))
+	for _, listing := range h.listings {
+		listings = append(listings, *listing)
+	}
+	sort.Slice(listings, func(i, j int) bool { return listings[i].BattleID < listings[j].BattleID })
+	return listings
+}
+
+// send encodes a payload and sends it to one peer, or broadcasts if peerID is empty
+func (h *SpectatorHub) send(msgType MessageType, payload interface{}, peerID string) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", msgType, err)
+	}
+	return h.networkManager.SendMessage(msgType, data, peerID)
+}
+
+// describeBattleResult summarizes a battle result for the spectator view
+func describeBattleResult(result BattleResultPayload) string {
+	switch {
+	case !result.Success:
+		return fmt.Sprintf("%s's %s missed", result.ActorID, result.ActionType)
+	case result.Damage > 0:
+		return fmt.Sprintf("%s used %s on %s for %.0f damage", result.ActorID, result.ActionType, result.TargetID, result.Damage)
+	case result.Healing > 0:
+		return fmt.Sprintf("%s used %s and healed %.0f", result.ActorID, result.ActionType, result.Healing)
+	default:
+		return fmt.Sprintf("%s used %s", result.ActorID, result.ActionType)
+	}
+}
+
+// sortedPeers returns set members in a stable order
+func sortedPeers(set map[string]bool) []string {
+	peers := make([]string, 0, len(set))
+	for peer := range set {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	return peers
+}
@@ -0,0 +1,198 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// deliver forwards everything a peer sent to another peer's handlers
+func deliver(t *testing.T, from, to *mockNetworkManager) {
+	t.Helper()
+	for _, msg := range from.GetSentMessages() {
+		if msg.To != "" && msg.To != to.networkID {
+			continue
+		}
+		if err := to.SimulateIncomingMessage(msg.Type, msg.Payload, from.networkID); err != nil {
+			t.Fatalf("Delivering %s failed: %v", msg.Type, err)
+		}
+	}
+	from.ClearSentMessages()
+}
+
+func TestSpectatorHubWatchBattle(t *testing.T) {
+	hostNM := newMockNetworkManager("host")
+	viewerNM := newMockNetworkManager("viewer")
+	host := NewSpectatorHub(hostNM)
+	viewer := NewSpectatorHub(viewerNM)
+
+	var updates []BattleSpectatorUpdatePayload
+	var replies []BattleSpectateReplyPayload
+	viewer.SetCallbacks(nil,
+		func(u BattleSpectatorUpdatePayload) { updates = append(updates, u) },
+		func(r BattleSpectateReplyPayload) { replies = append(replies, r) })
+
+	if err := host.HostBattle("b1", []string{"host", "rival"}, SpectatorsAllowed); err != nil {
+		t.Fatalf("HostBattle failed: %v", err)
+	}
+	deliver(t, hostNM, viewerNM)
+	if listings := viewer.Listings(); len(listings) != 1 || listings[0].HostID != "host" {
+		t.Fatalf("Expected viewer to see the announced battle, got %+v", listings)
+	}
+
+	if err := viewer.Spectate("b1"); err != nil {
+		t.Fatalf("Spectate failed: %v", err)
+	}
+	deliver(t, viewerNM, hostNM)
+	deliver(t, hostNM, viewerNM)
+	if len(replies) != 1 || !replies[0].Accepted {
+		t.Fatalf("Expected spectate request to be accepted, got %+v", replies)
+	}
+
+	// The opponent's result reaches the host, which relays it
+	result, _ := json.Marshal(BattleResultPayload{
+		BattleID: "b1", ActionType: "attack", ActorID: "rival", TargetID: "host",
+		Success: true, Damage: 12,
+		ParticipantStats: map[string]BattleParticipantStats{"host": {HP: 88, MaxHP: 100}},
+	})
+	if err := hostNM.SimulateIncomingMessage(MessageTypeBattleResult, result, "rival"); err != nil {
+		t.Fatalf("Battle result failed: %v", err)
+	}
+	deliver(t, hostNM, viewerNM)
+	if len(updates) != 1 || updates[0].Participants["host"].HP != 88 {
+		t.Fatalf("Expected relayed battle state, got %+v", updates)
+	}
+
+	end, _ := json.Marshal(BattleEndPayload{BattleID: "b1", Winner: "rival", Reason: "defeat"})
+	hostNM.SimulateIncomingMessage(MessageTypeBattleEnd, end, "rival")
+	deliver(t, hostNM, viewerNM)
+	if !updates[len(updates)-1].Ended || viewer.Watching() != "" {
+		t.Errorf("Expected battle end to stop spectating, got %+v", updates[len(updates)-1])
+	}
+	if len(viewer.Listings()) != 0 {
+		t.Error("Ended battle should be removed from listings")
+	}
+}
+
+func TestSpectatorHubPolicy(t *testing.T) {
+	hostNM := newMockNetworkManager("host")
+	viewerNM := newMockNetworkManager("viewer")
+	host := NewSpectatorHub(hostNM)
+	viewer := NewSpectatorHub(viewerNM)
+
+	var replies []BattleSpectateReplyPayload
+	viewer.SetCallbacks(nil, nil, func(r BattleSpectateReplyPayload) { replies = append(replies, r) })
+
+	var asked string
+	host.Approve = func(battleID, peerID string, respond func(bool)) {
+		asked = peerID
+		respond(false)
+	}
+	host.HostBattle("b2", []string{"host", "rival"}, SpectatorsAsk)
+	deliver(t, hostNM, viewerNM)
+
+	viewer.Spectate("b2")
+	deliver(t, viewerNM, hostNM)
+	deliver(t, hostNM, viewerNM)
+	if asked != "viewer" || len(replies) != 1 || replies[0].Accepted {
+		t.Fatalf("Expected the host to be asked and decline, got asked=%q replies=%+v", asked, replies)
+	}
+	if viewer.Watching() != "" {
+		t.Error("Declined request should clear the watched battle")
+	}
+
+	// Denied battles are never announced
+	host.HostBattle("b3", []string{"host", "rival"}, SpectatorsDenied)
+	if sent := hostNM.GetSentMessages(); len(sent) != 0 {
+		t.Errorf("Denied battle should not be announced, sent %+v", sent)
+	}
+
+	// Turning spectating off drops existing spectators
+	host.HostBattle("b4", []string{"host", "rival"}, SpectatorsAllowed)
+	deliver(t, hostNM, viewerNM)
+	viewer.Spectate("b4")
+	deliver(t, viewerNM, hostNM)
+	if len(host.Spectators("b4")) != 1 {
+		t.Fatalf("Expected one spectator, got %v", host.Spectators("b4"))
+	}
+	host.SetPolicy("b4", SpectatorsDenied)
+	if len(host.Spectators("b4")) != 0 {
+		t.Error("Expected spectators to be dropped when spectating is turned off")
+	}
+}
+
+func TestSpectatorHubKeysPeersByIdentity(t *testing.T) {
+	hostNM := newMockNetworkManager("shared-network")
+	host := NewSpectatorHub(hostNM)
+	host.HostBattle("b1", []string{"host", "rival"}, SpectatorsAllowed)
+
+	// Both instances share a network ID; their peer identities tell them apart
+	request, _ := json.Marshal(BattleSpectatePayload{BattleID: "b1"})
+	for _, id := range []string{"viewer-a", "viewer-b"} {
+		msg := Message{Type: MessageTypeBattleSpectate, From: "shared-network", Payload: request}
+		if err := host.handleSpectate(msg, &Peer{ID: id}); err != nil {
+			t.Fatalf("Spectate from %s failed: %v", id, err)
+		}
+	}
+	if got := host.Spectators("b1"); len(got) != 2 || got[0] != "viewer-a" || got[1] != "viewer-b" {
+		t.Fatalf("Expected both viewers to spectate, got %v", got)
+	}
+
+	viewer := NewSpectatorHub(newMockNetworkManager("shared-network"))
+	var updates []BattleSpectatorUpdatePayload
+	viewer.SetCallbacks(nil, func(u BattleSpectatorUpdatePayload) { updates = append(updates, u) }, nil)
+
+	announce, _ := json.Marshal(BattleAnnouncePayload{BattleID: "b1", Participants: []string{"host", "rival"}})
+	viewer.handleAnnounce(Message{From: "shared-network", Payload: announce}, &Peer{ID: "host-key"})
+	if listings := viewer.Listings(); len(listings) != 1 || listings[0].HostID != "host-key" {
+		t.Fatalf("Expected the listing to name the announcing peer, got %+v", listings)
+	}
+	viewer.Spectate("b1")
+
+	// Another peer can neither take over the listing nor feed updates for it
+	ended, _ := json.Marshal(BattleAnnouncePayload{BattleID: "b1", Ended: true})
+	viewer.handleAnnounce(Message{From: "shared-network", Payload: ended}, &Peer{ID: "impostor"})
+	update, _ := json.Marshal(BattleSpectatorUpdatePayload{BattleID: "b1", LastAction: "fake"})
+	viewer.handleUpdate(Message{From: "shared-network", Payload: update}, &Peer{ID: "impostor"})
+	if len(viewer.Listings()) != 1 || len(updates) != 0 {
+		t.Errorf("Expected messages from another peer to be ignored, got listings=%+v updates=%+v", viewer.Listings(), updates)
+	}
+
+	viewer.handleUpdate(Message{From: "shared-network", Payload: update}, &Peer{ID: "host-key"})
+	if len(updates) != 1 {
+		t.Errorf("Expected the host's update to arrive, got %+v", updates)
+	}
+}
+
+func TestSpectatorHubLeavesBattleHandlersInPlace(t *testing.T) {
+	hostNM := newMockNetworkManager("host")
+	var handled []MessageType
+	record := func(msg Message, from *Peer) error {
+		handled = append(handled, msg.Type)
+		return nil
+	}
+	hostNM.RegisterMessageHandler(MessageTypeBattleResult, record)
+	host := NewSpectatorHub(hostNM)
+	hostNM.RegisterMessageHandler(MessageTypeBattleEnd, record)
+
+	host.HostBattle("b1", []string{"host", "rival"}, SpectatorsAllowed)
+	host.admitSpectator("b1", "viewer")
+	hostNM.ClearSentMessages()
+
+	result, _ := json.Marshal(BattleResultPayload{BattleID: "b1", ActionType: "attack", ActorID: "rival", TargetID: "host"})
+	hostNM.SimulateIncomingMessage(MessageTypeBattleResult, result, "rival")
+	end, _ := json.Marshal(BattleEndPayload{BattleID: "b1", Winner: "rival"})
+	hostNM.SimulateIncomingMessage(MessageTypeBattleEnd, end, "rival")
+
+	if len(handled) != 2 {
+		t.Errorf("Expected the battle handlers to see result and end, got %v", handled)
+	}
+	relayed := 0
+	for _, msg := range hostNM.GetSentMessages() {
+		if msg.Type == MessageTypeBattleSpectatorUpdate && msg.To == "viewer" {
+			relayed++
+		}
+	}
+	if relayed != 2 {
+		t.Errorf("Expected the hub to relay result and end to the spectator, got %d updates", relayed)
+	}
+}
@@ -14,8 +14,10 @@ import (
 // NetworkManagerInterface defines the interface for network management operations
 type NetworkManagerInterface interface {
 	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+	AddMessageObserver(msgType MessageType, observer MessageHandler)
 	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
 	GetNetworkID() string
+	GetPeerID() string
 }
 
 // ProtocolManagerInterface defines the interface for protocol management operations
@@ -505,7 +507,7 @@ func (ss *StateSynchronizer) isSignificantChange(state *CharacterState, hadPrevi
 // getLocalPeerID returns the local peer's identifier
 func (ss *StateSynchronizer) getLocalPeerID() string {
 	if ss.networkManager != nil {
-		return ss.networkManager.GetNetworkID()
+		return ss.networkManager.GetPeerID()
 	}
 	return "local"
 }
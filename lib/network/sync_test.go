@@ -14,6 +14,7 @@ type mockNetworkManager struct {
 	mu                sync.RWMutex
 	networkID         string
 	handlers          map[MessageType]MessageHandler
+	observers         map[MessageType][]MessageHandler
 	sentMessages      []Message
 	shouldFailSend    bool
 	shouldFailHandler bool
@@ -33,6 +34,15 @@ func (m *mockNetworkManager) RegisterMessageHandler(msgType MessageType, handler
 	m.handlers[msgType] = handler
 }
 
+func (m *mockNetworkManager) AddMessageObserver(msgType MessageType, observer MessageHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.observers == nil {
+		m.observers = make(map[MessageType][]MessageHandler)
+	}
+	m.observers[msgType] = append(m.observers[msgType], observer)
+}
+
 func (m *mockNetworkManager) SendMessage(msgType MessageType, payload []byte, targetPeerID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -57,6 +67,10 @@ func (m *mockNetworkManager) GetNetworkID() string {
 	return m.networkID
 }
 
+func (m *mockNetworkManager) GetPeerID() string {
+	return m.networkID
+}
+
 func (m *mockNetworkManager) GetSentMessages() []Message {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -74,9 +88,10 @@ func (m *mockNetworkManager) ClearSentMessages() {
 func (m *mockNetworkManager) SimulateIncomingMessage(msgType MessageType, payload []byte, fromPeer string) error {
 	m.mu.RLock()
 	handler, exists := m.handlers[msgType]
+	observers := m.observers[msgType]
 	m.mu.RUnlock()
 
-	if !exists {
+	if !exists && len(observers) == 0 {
 		return &MockError{msg: "no handler registered"}
 	}
 
@@ -97,7 +112,16 @@ func (m *mockNetworkManager) SimulateIncomingMessage(msgType MessageType, payloa
 		LastSeen: time.Now(),
 	}
 
-	return handler(message, peer)
+	var err error
+	if exists {
+		err = handler(message, peer)
+	}
+	for _, observer := range observers {
+		if observerErr := observer(message, peer); observerErr != nil && err == nil {
+			err = observerErr
+		}
+	}
+	return err
 }
 
 type mockProtocolManager struct {
@@ -0,0 +1,486 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/gif"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MessageTypeVisit carries a character visiting another user's desktop: the
+// invitation, the animations it brings along, and its state and dialog while
+// it stays. Checked against the chats permission.
+const MessageTypeVisit MessageType = "visit"
+
+// Visit message kinds
+const (
+	VisitKindRequest = "request" // Host asks a peer's character over
+	VisitKindArrive  = "arrive"  // Visitor sends its animations and current state
+	VisitKindUpdate  = "update"  // Visitor changed state or said something
+	VisitKindLeave   = "leave"   // Either side ends the visit
+	VisitKindDecline = "decline" // Visitor can't come, e.g. it has nothing to show
+)
+
+const (
+	// maxVisitAnimationBytes caps a single GIF a visitor may bring
+	maxVisitAnimationBytes = 1 << 20
+	// maxVisitAssetBytes caps all of a visitor's animations together
+	maxVisitAssetBytes = 8 << 20
+	// maxVisitAnimationSide caps a visiting GIF's width and height. The GIF
+	// decoder allocates each frame's pixels before reading them, so a tiny
+	// file declaring a huge image could otherwise exhaust the host's memory.
+	maxVisitAnimationSide = 1024
+)
+
+// A visitor normally sends a handful of updates a minute; one sending faster
+// than this has its updates dropped
+const (
+	visitUpdateLimit  = 20
+	visitLimitWindow  = 10 * time.Second
+	visitRequestLimit = 3
+)
+
+// VisitPayload is the wire format of every visit message; Kind says which
+// fields are set
+type VisitPayload struct {
+	Kind       string            `json:"kind"`
+	Character  string            `json:"character,omitempty"`
+	Animations map[string][]byte `json:"animations,omitempty"` // GIF data by animation name, with arrive
+	State      string            `json:"state,omitempty"`
+	Dialog     string            `json:"dialog,omitempty"`
+}
+
+// VisitGuest is a peer's character shown on our desktop
+type VisitGuest struct {
+	PeerID     string
+	Character  string
+	Animations map[string][]byte
+	State      string
+	Since      time.Time
+}
+
+// Visit event kinds reported to the UI
+const (
+	VisitGuestArrived = "guest_arrived" // A peer's character came over
+	VisitGuestUpdated = "guest_updated" // The guest changed state or spoke
+	VisitGuestLeft    = "guest_left"    // The guest went home
+	VisitDeclined     = "declined"      // The peer's character couldn't come
+	VisitStarted      = "started"       // Our character went to visit a peer
+	VisitEnded        = "ended"         // Our character was sent home
+)
+
+// VisitEvent tells the UI something changed about a visit
+type VisitEvent struct {
+	Kind   string
+	PeerID string
+	Guest  VisitGuest // Set for guest events
+	Dialog string     // What the guest just said, with VisitGuestUpdated
+}
+
+// VisitTransport is the subset of the network manager used for visits
+type VisitTransport interface {
+	SendMessage(msgType MessageType, payload []byte, targetPeerID string) error
+	RegisterMessageHandler(msgType MessageType, handler MessageHandler)
+}
+
+// Visits lets a peer's character appear on our desktop as a read-only guest,
+// and ours on theirs. The host asks, the visitor sends its animations once
+// and then streams state changes and dialog until either side ends the
+// visit. Only characters we asked for are let in.
+type Visits struct {
+	mu        sync.Mutex
+	transport VisitTransport
+	character string
+	state     string
+	assets    func() map[string][]byte // Our animations, gathered when a peer asks us over
+	invited   map[string]bool          // Peers we asked over that haven't arrived yet
+	guests    map[string]*VisitGuest   // Peer ID to their character on our desktop
+	visiting  map[string]bool          // Peers our character is visiting
+	updates   *chatRateLimit
+	requests  *chatRateLimit
+	onEvent   func(VisitEvent)
+}
+
+// NewVisits creates visits for characterName and registers the message handler
+func NewVisits(transport VisitTransport, characterName string) *Visits {
+	v := &Visits{
+		transport: transport,
+		character: characterName,
+		invited:   make(map[string]bool),
+		guests:    make(map[string]*VisitGuest),
+		visiting:  make(map[string]bool),
+		updates:   newChatRateLimit(visitUpdateLimit, visitLimitWindow),
+		requests:  newChatRateLimit(visitRequestLimit, visitLimitWindow),
+	}
+
+	if transport != nil {
+		transport.RegisterMessageHandler(MessageTypeVisit, v.handleMessage)
+	}
+	return v
+}
+
+// SetEventHandler sets the callback told about guests and our own visits
+func (v *Visits) SetEventHandler(handler func(VisitEvent)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.onEvent = handler
+}
+
+// SetCharacter changes the name our character visits as
+func (v *Visits) SetCharacter(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.character = name
+}
+
+// SetAssets sets where our animations come from when a peer asks us over.
+// Without it, requests are declined.
+func (v *Visits) SetAssets(assets func() map[string][]byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.assets = assets
+}
+
+// Invite asks a peer's character to visit our desktop
+func (v *Visits) Invite(peerID string) error {
+	if peerID == "" {
+		return fmt.Errorf("no peer selected")
+	}
+
+	v.mu.Lock()
+	if _, here := v.guests[peerID]; here {
+		v.mu.Unlock()
+		return fmt.Errorf("that character is already visiting")
+	}
+	v.invited[peerID] = true
+	v.mu.Unlock()
+
+	if err := v.send(peerID, VisitPayload{Kind: VisitKindRequest}); err != nil {
+		v.mu.Lock()
+		delete(v.invited, peerID)
+		v.mu.Unlock()
+		return fmt.Errorf("failed to send visit invitation: %w", err)
+	}
+	return nil
+}
+
+// Publish streams our character's state and, if not empty, something it
+// said to every peer it is visiting. Unchanged states without dialog are
+// not sent, so it can be called every frame.
+func (v *Visits) Publish(state, dialog string) {
+	dialog = strings.TrimSpace(dialog)
+	if utf8.RuneCountInString(dialog) > MaxChatRelayLength {
+		dialog = string([]rune(dialog)[:MaxChatRelayLength])
+	}
+
+	v.mu.Lock()
+	if state == v.state && dialog == "" {
+		v.mu.Unlock()
+		return
+	}
+	v.state = state
+	peers := make([]string, 0, len(v.visiting))
+	for peerID := range v.visiting {
+		peers = append(peers, peerID)
+	}
+	v.mu.Unlock()
+
+	for _, peerID := range peers {
+		if err := v.send(peerID, VisitPayload{Kind: VisitKindUpdate, State: state, Dialog: dialog}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+				"peerID": peerID,
+				"error":  err.Error(),
+			}).Debug("Failed to send visit update")
+		}
+	}
+}
+
+// End sends a guest home or brings our character back from a peer
+func (v *Visits) End(peerID string) error {
+	v.mu.Lock()
+	guest, hosting := v.guests[peerID]
+	visiting := v.visiting[peerID]
+	delete(v.guests, peerID)
+	delete(v.visiting, peerID)
+	delete(v.invited, peerID)
+	v.mu.Unlock()
+
+	if guest == nil && !visiting {
+		return nil
+	}
+	if hosting {
+		v.emit(VisitEvent{Kind: VisitGuestLeft, PeerID: peerID, Guest: *guest})
+	}
+	if visiting {
+		v.emit(VisitEvent{Kind: VisitEnded, PeerID: peerID})
+	}
+	return v.send(peerID, VisitPayload{Kind: VisitKindLeave})
+}
+
+// EndAll ends every visit in both directions, e.g. before shutting down
+func (v *Visits) EndAll() {
+	v.mu.Lock()
+	peers := make(map[string]bool, len(v.guests)+len(v.visiting))
+	for peerID := range v.guests {
+		peers[peerID] = true
+	}
+	for peerID := range v.visiting {
+		peers[peerID] = true
+	}
+	v.mu.Unlock()
+
+	for peerID := range peers {
+		_ = v.End(peerID) // Peers that are gone can't be told
+	}
+}
+
+// Guests returns copies of the characters visiting us
+func (v *Visits) Guests() []VisitGuest {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	guests := make([]VisitGuest, 0, len(v.guests))
+	for _, guest := range v.guests {
+		guests = append(guests, *guest)
+	}
+	return guests
+}
+
+// Visiting returns the peers our character is visiting
+func (v *Visits) Visiting() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	peers := make([]string, 0, len(v.visiting))
+	for peerID := range v.visiting {
+		peers = append(peers, peerID)
+	}
+	return peers
+}
+
+// handleMessage dispatches a visit message by kind
+func (v *Visits) handleMessage(msg Message, from *Peer) error {
+	if from == nil {
+		return fmt.Errorf("visit message without sender")
+	}
+
+	var payload VisitPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse visit message: %w", err)
+	}
+
+	switch payload.Kind {
+	case VisitKindRequest:
+		return v.handleRequest(from.ID)
+	case VisitKindArrive:
+		return v.handleArrive(from.ID, payload)
+	case VisitKindUpdate:
+		v.handleUpdate(from.ID, payload)
+	case VisitKindLeave:
+		v.handleLeave(from.ID)
+	case VisitKindDecline:
+		v.mu.Lock()
+		invited := v.invited[from.ID]
+		delete(v.invited, from.ID)
+		v.mu.Unlock()
+		if invited {
+			v.emit(VisitEvent{Kind: VisitDeclined, PeerID: from.ID})
+		}
+	default:
+		return fmt.Errorf("unknown visit message kind %q", payload.Kind)
+	}
+	return nil
+}
+
+// handleRequest sends our character over with as many animations as fit
+func (v *Visits) handleRequest(peerID string) error {
+	v.mu.Lock()
+	if !v.requests.allow(peerID, time.Now()) {
+		v.mu.Unlock()
+		return nil
+	}
+	assets, character, state := v.assets, v.character, v.state
+	v.mu.Unlock()
+
+	var animations map[string][]byte
+	if assets != nil {
+		animations = limitVisitAnimations(assets(), state)
+	}
+	if len(animations) == 0 {
+		return v.send(peerID, VisitPayload{Kind: VisitKindDecline})
+	}
+
+	v.mu.Lock()
+	v.visiting[peerID] = true
+	v.mu.Unlock()
+
+	arrive := VisitPayload{Kind: VisitKindArrive, Character: character, Animations: animations, State: state}
+	if err := v.send(peerID, arrive); err != nil {
+		v.mu.Lock()
+		delete(v.visiting, peerID)
+		v.mu.Unlock()
+		return fmt.Errorf("failed to send our character: %w", err)
+	}
+	v.emit(VisitEvent{Kind: VisitStarted, PeerID: peerID})
+	return nil
+}
+
+// handleArrive lets in a character we invited
+func (v *Visits) handleArrive(peerID string, payload VisitPayload) error {
+	// Checked before the animations, which uninvited peers don't get read
+	v.mu.Lock()
+	invited := v.invited[peerID]
+	v.mu.Unlock()
+	if !invited {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": peerID,
+		}).Debug("Ignoring uninvited visitor")
+		return nil
+	}
+
+	animations := limitVisitAnimations(payload.Animations, payload.State)
+	if len(animations) == 0 {
+		return fmt.Errorf("visitor from %s brought no usable animations", peerID)
+	}
+
+	v.mu.Lock()
+	if !v.invited[peerID] {
+		v.mu.Unlock()
+		return nil // The invitation was withdrawn meanwhile
+	}
+	delete(v.invited, peerID)
+	guest := &VisitGuest{
+		PeerID:     peerID,
+		Character:  payload.Character,
+		Animations: animations,
+		State:      payload.State,
+		Since:      time.Now(),
+	}
+	v.guests[peerID] = guest
+	event := VisitEvent{Kind: VisitGuestArrived, PeerID: peerID, Guest: *guest}
+	v.mu.Unlock()
+
+	v.emit(event)
+	return nil
+}
+
+// handleUpdate changes a guest's state and passes on its dialog, dropping
+// floods
+func (v *Visits) handleUpdate(peerID string, payload VisitPayload) {
+	dialog := strings.TrimSpace(payload.Dialog)
+	if utf8.RuneCountInString(dialog) > MaxChatRelayLength {
+		dialog = ""
+	}
+
+	v.mu.Lock()
+	guest := v.guests[peerID]
+	if guest == nil || !v.updates.allow(peerID, time.Now()) {
+		v.mu.Unlock()
+		return
+	}
+	if _, ok := guest.Animations[payload.State]; ok {
+		guest.State = payload.State
+	}
+	event := VisitEvent{Kind: VisitGuestUpdated, PeerID: peerID, Guest: *guest, Dialog: dialog}
+	v.mu.Unlock()
+
+	v.emit(event)
+}
+
+// handleLeave ends whichever visits we have with the peer
+func (v *Visits) handleLeave(peerID string) {
+	v.mu.Lock()
+	guest := v.guests[peerID]
+	visiting := v.visiting[peerID]
+	delete(v.guests, peerID)
+	delete(v.visiting, peerID)
+	v.mu.Unlock()
+
+	if guest != nil {
+		v.emit(VisitEvent{Kind: VisitGuestLeft, PeerID: peerID, Guest: *guest})
+	}
+	if visiting {
+		v.emit(VisitEvent{Kind: VisitEnded, PeerID: peerID})
+	}
+}
+
+// send encodes and sends a visit message
+func (v *Visits) send(peerID string, payload VisitPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode visit message: %w", err)
+	}
+	return v.transport.SendMessage(MessageTypeVisit, data, peerID)
+}
+
+// emit hands an event to the UI
+func (v *Visits) emit(event VisitEvent) {
+	v.mu.Lock()
+	handler := v.onEvent
+	v.mu.Unlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// DecodeVisitAnimation decodes a GIF a visitor brought along. Its declared
+// size is checked before any frame is decoded.
+func DecodeVisitAnimation(data []byte) (*gif.GIF, error) {
+	if err := checkVisitAnimationSize(data); err != nil {
+		return nil, err
+	}
+	return gif.DecodeAll(bytes.NewReader(data))
+}
+
+// checkVisitAnimationSize reads only a GIF's header and returns an error
+// when it isn't a GIF or is larger than maxVisitAnimationSide
+func checkVisitAnimationSize(data []byte) error {
+	config, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("not a GIF: %w", err)
+	}
+	if config.Width > maxVisitAnimationSide || config.Height > maxVisitAnimationSide {
+		return fmt.Errorf("%dx%d GIF is larger than %dx%d", config.Width, config.Height,
+			maxVisitAnimationSide, maxVisitAnimationSide)
+	}
+	return nil
+}
+
+// limitVisitAnimations drops animations over the size limits or that aren't
+// GIFs small enough to decode safely, keeping the current state's animation
+// first so the visitor always has something to show
+func limitVisitAnimations(animations map[string][]byte, state string) map[string][]byte {
+	kept := make(map[string][]byte, len(animations))
+	total := 0
+	add := func(name string, data []byte) {
+		if len(data) == 0 || len(data) > maxVisitAnimationBytes || total+len(data) > maxVisitAssetBytes ||
+			checkVisitAnimationSize(data) != nil {
+			return
+		}
+		kept[name] = data
+		total += len(data)
+	}
+
+	if data, ok := animations[state]; ok {
+		add(state, data)
+	}
+	names := make([]string, 0, len(animations))
+	for name := range animations {
+		if name != state {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names) // Smaller budgets still drop the same animations every time
+	for _, name := range names {
+		add(name, animations[name])
+	}
+	return kept
+}
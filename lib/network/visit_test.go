@@ -0,0 +1,127 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVisitArrivesAndStreamsUpdates(t *testing.T) {
+	a, b := newTransportPair()
+	host := NewVisits(a, "Pixel")
+	visitor := NewVisits(b, "Luna")
+	visitor.SetAssets(func() map[string][]byte {
+		return map[string][]byte{"idle": visitGIF(32, 32, 16), "happy": visitGIF(32, 32, 24)}
+	})
+	visitor.Publish("idle", "")
+
+	var hostEvents, visitorEvents []VisitEvent
+	host.SetEventHandler(func(e VisitEvent) { hostEvents = append(hostEvents, e) })
+	visitor.SetEventHandler(func(e VisitEvent) { visitorEvents = append(visitorEvents, e) })
+
+	if err := host.Invite("peer-b"); err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	if len(hostEvents) != 1 || hostEvents[0].Kind != VisitGuestArrived {
+		t.Fatalf("Expected the guest to arrive, got %+v", hostEvents)
+	}
+	guest := hostEvents[0].Guest
+	if guest.Character != "Luna" || guest.State != "idle" || !bytes.Equal(guest.Animations["happy"], visitGIF(32, 32, 24)) {
+		t.Errorf("Expected Luna with her animations, got %+v", guest)
+	}
+	if len(visitorEvents) != 1 || visitorEvents[0].Kind != VisitStarted {
+		t.Errorf("Expected the visitor to be told it left, got %+v", visitorEvents)
+	}
+
+	visitor.Publish("happy", "Nice desktop!")
+	visitor.Publish("happy", "") // Unchanged, not sent
+	if len(hostEvents) != 2 || hostEvents[1].Guest.State != "happy" || hostEvents[1].Dialog != "Nice desktop!" {
+		t.Fatalf("Expected one update with the dialog, got %+v", hostEvents)
+	}
+
+	// States the guest didn't bring keep the last one
+	visitor.Publish("dancing", "")
+	if got := host.Guests()[0].State; got != "happy" {
+		t.Errorf("Expected an unknown state to be ignored, got %q", got)
+	}
+
+	if err := host.End("peer-b"); err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+	if len(host.Guests()) != 0 || len(visitor.Visiting()) != 0 {
+		t.Error("Expected both sides to end the visit")
+	}
+	if last := visitorEvents[len(visitorEvents)-1]; last.Kind != VisitEnded {
+		t.Errorf("Expected the visitor to be sent home, got %+v", last)
+	}
+}
+
+func TestVisitIgnoresUninvitedCharacters(t *testing.T) {
+	_, b := newTransportPair()
+	host := NewVisits(b, "Pixel")
+
+	arrive := []byte(`{"kind":"arrive","character":"Intruder","animations":{"idle":"R0lG"}}`)
+	if err := host.handleMessage(Message{Type: MessageTypeVisit, Payload: arrive}, &Peer{ID: "peer-a"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(host.Guests()) != 0 {
+		t.Error("Expected an uninvited character to be turned away")
+	}
+}
+
+func TestVisitDeclinedWithoutAnimations(t *testing.T) {
+	a, b := newTransportPair()
+	host := NewVisits(a, "Pixel")
+	NewVisits(b, "Luna") // No assets set
+
+	var events []VisitEvent
+	host.SetEventHandler(func(e VisitEvent) { events = append(events, e) })
+	if err := host.Invite("peer-b"); err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != VisitDeclined {
+		t.Errorf("Expected the visit to be declined, got %+v", events)
+	}
+}
+
+func TestLimitVisitAnimations(t *testing.T) {
+	animations := map[string][]byte{
+		"huge":      visitGIF(32, 32, maxVisitAnimationBytes+1),
+		"state":     visitGIF(32, 32, 16),
+		"giant":     visitGIF(65535, 65535, 16),
+		"not-a-gif": []byte("small"),
+	}
+	// Eight full-size animations only fit seven times next to the state's
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		animations[name] = visitGIF(32, 32, maxVisitAnimationBytes)
+	}
+	kept := limitVisitAnimations(animations, "state")
+
+	if _, ok := kept["huge"]; ok {
+		t.Error("Expected an animation over the per-file limit to be dropped")
+	}
+	if _, ok := kept["giant"]; ok {
+		t.Error("Expected a GIF declaring a huge image to be dropped")
+	}
+	if _, ok := kept["not-a-gif"]; ok {
+		t.Error("Expected data that isn't a GIF to be dropped")
+	}
+	if _, err := DecodeVisitAnimation(animations["giant"]); err == nil {
+		t.Error("Expected a GIF declaring a huge image to be refused before decoding")
+	}
+	if _, ok := kept["state"]; !ok {
+		t.Error("Expected the current state's animation to be kept first")
+	}
+	if _, ok := kept["h"]; ok || len(kept) != 8 {
+		t.Errorf("Expected animations past the total limit to be dropped, kept %d", len(kept))
+	}
+}
+
+// visitGIF returns a GIF header declaring a width x height image, padded to
+// size bytes. Only the header is read when animations are checked.
+func visitGIF(width, height, size int) []byte {
+	data := []byte{'G', 'I', 'F', '8', '9', 'a', byte(width), byte(width >> 8), byte(height), byte(height >> 8), 0, 0, 0}
+	for len(data) < size {
+		data = append(data, 0)
+	}
+	return data
+}
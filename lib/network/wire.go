@@ -0,0 +1,191 @@
+package network
+
+// wire.go frames the signed messages peers exchange over TCP. A frame is a
+// single SignedMessage, which every peer understands, or for peers that
+// advertise the capability a batch of them, gzipped when large. State sync
+// sends many small messages in quick succession, so on Wi-Fi batching saves
+// a packet per message and compression most of the JSON.
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Wire capabilities every NetworkManager advertises alongside the
+// configured ones, telling peers which frames it can read
+const (
+	CapabilityBatch = "batch" // Reads frames holding several messages
+	CapabilityGzip  = "gzip"  // Reads gzipped frames
+	CapabilityPing  = "ping"  // Answers pings; older peers drop them
+)
+
+const (
+	defaultCompressThreshold = 1024 // Frames above this many bytes are gzipped
+	maxBatchMessages         = 64   // A peer's batch is sent early once this full
+	maxFrameSize             = 16 << 20
+)
+
+// wireFrame is what peers exchange over TCP. A single message uses the
+// SignedMessage fields, so peers without batch support read it unchanged;
+// a batch uses Batch, or Gzip holding the gzipped JSON of the batch.
+type wireFrame struct {
+	Message   *Message        `json:"message,omitempty"`
+	Signature []byte          `json:"signature,omitempty"`
+	PublicKey []byte          `json:"publicKey,omitempty"`
+	Batch     []SignedMessage `json:"batch,omitempty"`
+	Gzip      []byte          `json:"gzip,omitempty"`
+}
+
+// messages unpacks the signed messages the frame carries
+func (f *wireFrame) messages() ([]SignedMessage, error) {
+	switch {
+	case f.Gzip != nil:
+		reader, err := gzip.NewReader(bytes.NewReader(f.Gzip))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip frame: %w", err)
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(io.LimitReader(reader, maxFrameSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip frame: %w", err)
+		}
+		if len(data) > maxFrameSize {
+			return nil, fmt.Errorf("gzip frame larger than %d bytes", maxFrameSize)
+		}
+		var batch []SignedMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("invalid gzip frame: %w", err)
+		}
+		return batch, nil
+	case f.Batch != nil:
+		return f.Batch, nil
+	case f.Message != nil:
+		return []SignedMessage{{Message: *f.Message, Signature: f.Signature, PublicKey: f.PublicKey}}, nil
+	}
+	return nil, fmt.Errorf("empty frame")
+}
+
+// encodeFrame builds the frame for msgs. Several messages need a peer with
+// CapabilityBatch; gzip is used above threshold bytes when the peer reads
+// it and it actually makes the frame smaller. A negative threshold never
+// compresses.
+func encodeFrame(msgs []SignedMessage, threshold int, gzipOK bool) ([]byte, error) {
+	var data []byte
+	var err error
+	if len(msgs) == 1 {
+		data, err = json.Marshal(msgs[0])
+	} else {
+		data, err = json.Marshal(wireFrame{Batch: msgs})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal frame: %w", err)
+	}
+
+	if gzipOK && threshold >= 0 && len(data) > threshold {
+		if compressed, err := gzipFrame(msgs); err == nil && len(compressed) < len(data) {
+			data = compressed
+		}
+	}
+	return append(data, '\n'), nil
+}
+
+// gzipFrame returns the frame carrying msgs as gzipped JSON
+func gzipFrame(msgs []SignedMessage) ([]byte, error) {
+	batch, err := json.Marshal(msgs)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(batch); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(wireFrame{Gzip: buf.Bytes()})
+}
+
+// frameReader reads signed messages from a peer connection one at a time,
+// unpacking batches
+type frameReader struct {
+	decoder *json.Decoder
+	pending []SignedMessage
+}
+
+// newFrameReader reads frames from r
+func newFrameReader(r io.Reader) *frameReader {
+	return &frameReader{decoder: json.NewDecoder(r)}
+}
+
+// next returns the next message, reading a new frame when needed
+func (fr *frameReader) next() (SignedMessage, error) {
+	for len(fr.pending) == 0 {
+		var frame wireFrame
+		if err := fr.decoder.Decode(&frame); err != nil {
+			return SignedMessage{}, err
+		}
+		msgs, err := frame.messages()
+		if err != nil {
+			return SignedMessage{}, err
+		}
+		fr.pending = msgs
+	}
+	msg := fr.pending[0]
+	fr.pending = fr.pending[1:]
+	return msg, nil
+}
+
+// wireCapabilities returns capabilities with the wire capabilities added
+func wireCapabilities(capabilities []string) []string {
+	all := slices.Clone(capabilities)
+	for _, capability := range []string{CapabilityBatch, CapabilityGzip, CapabilityPing} {
+		if !slices.Contains(all, capability) {
+			all = append(all, capability)
+		}
+	}
+	return all
+}
+
+// supports reports whether the peer advertised the capability
+func (p *Peer) supports(capability string) bool {
+	return slices.Contains(p.Capabilities, capability)
+}
+
+// writeFrame sends msgs to the peer in one frame. Must only be called from
+// messageProcessor, so frames on a connection never interleave.
+func (nm *NetworkManager) writeFrame(peer *Peer, msgs []SignedMessage) {
+	frame, err := encodeFrame(msgs, nm.compressThreshold, peer.supports(CapabilityGzip))
+	if err != nil {
+		return
+	}
+	peer.Conn.Write(frame) // Ignore errors, the connection handler cleans up
+}
+
+// queueForBatch holds a signed message for the peer until the next flush,
+// sending the batch early once it is full. Like writeFrame, must only be
+// called from messageProcessor, which owns the outbox.
+func (nm *NetworkManager) queueForBatch(peer *Peer, signed SignedMessage) {
+	nm.outbox[peer.ID] = append(nm.outbox[peer.ID], signed)
+	if len(nm.outbox[peer.ID]) >= maxBatchMessages {
+		nm.writeFrame(peer, nm.outbox[peer.ID])
+		delete(nm.outbox, peer.ID)
+	}
+}
+
+// flushOutbox sends every peer its queued batch. Called by messageProcessor.
+func (nm *NetworkManager) flushOutbox() {
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
+
+	for peerID, batch := range nm.outbox {
+		if peer, exists := nm.peers[peerID]; exists && peer.Conn != nil {
+			nm.writeFrame(peer, batch)
+		}
+		delete(nm.outbox, peerID)
+	}
+}
@@ -0,0 +1,249 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedTestMessages returns n signed state sync messages with payloads of size bytes
+func signedTestMessages(t *testing.T, nm *NetworkManager, n, size int) []SignedMessage {
+	t.Helper()
+	msgs := make([]SignedMessage, n)
+	for i := range msgs {
+		signed, err := nm.signMessage(Message{
+			Type:      MessageTypeStateSync,
+			From:      nm.GetPeerID(),
+			Payload:   []byte(strings.Repeat("x", size)),
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs[i] = *signed
+	}
+	return msgs
+}
+
+// decodeFrames reads every message in data through a frameReader
+func decodeFrames(t *testing.T, data []byte) []SignedMessage {
+	t.Helper()
+	reader := newFrameReader(bytes.NewReader(data))
+	var msgs []SignedMessage
+	for {
+		msg, err := reader.next()
+		if err != nil {
+			return msgs
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func TestEncodeFrameSingleMessageIsPlainSignedMessage(t *testing.T) {
+	nm, _ := NewNetworkManager(NetworkManagerConfig{})
+	msgs := signedTestMessages(t, nm, 1, 16)
+
+	frame, err := encodeFrame(msgs, defaultCompressThreshold, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Peers without batch support decode frames as SignedMessage
+	var signed SignedMessage
+	if err := json.Unmarshal(frame, &signed); err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := verifySender(&signed); err != nil || sender != nm.GetPeerID() {
+		t.Errorf("Expected a valid message from %s, got %s (%v)", nm.GetPeerID(), sender, err)
+	}
+}
+
+func TestEncodeFrameRoundTrip(t *testing.T) {
+	nm, _ := NewNetworkManager(NetworkManagerConfig{})
+
+	tests := []struct {
+		name      string
+		count     int
+		threshold int
+		gzipOK    bool
+		wantGzip  bool
+	}{
+		{"small batch", 3, 1 << 16, true, false},
+		{"large batch compressed", 20, defaultCompressThreshold, true, true},
+		{"peer without gzip", 20, defaultCompressThreshold, false, false},
+		{"compression disabled", 20, -1, true, false},
+		{"large single compressed", 1, 10, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := signedTestMessages(t, nm, tt.count, 200)
+			frame, err := encodeFrame(msgs, tt.threshold, tt.gzipOK)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			isGzip := bytes.Contains(frame, []byte(`"gzip"`))
+			if isGzip != tt.wantGzip {
+				t.Errorf("Expected gzip %v, got frame %.80s", tt.wantGzip, frame)
+			}
+
+			got := decodeFrames(t, frame)
+			if len(got) != tt.count {
+				t.Fatalf("Expected %d messages, got %d", tt.count, len(got))
+			}
+			for _, signed := range got {
+				if _, err := verifySender(&signed); err != nil {
+					t.Errorf("Signature no longer verifies: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFrameReaderRejectsMalformedFrames(t *testing.T) {
+	for _, data := range []string{`{}`, `{"gzip":"bm90IGd6aXA="}`} {
+		if _, err := newFrameReader(strings.NewReader(data)).next(); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestNetworkManagerBatchesToCapablePeers(t *testing.T) {
+	nm, err := NewNetworkManager(NetworkManagerConfig{BatchInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	batched := &Peer{ID: "batched", Conn: local, Capabilities: []string{CapabilityBatch, CapabilityGzip}}
+	nm.peers[batched.ID] = batched
+
+	for i := 0; i < 3; i++ {
+		nm.sendMessageToPeer(Message{Type: MessageTypeStateSync, From: nm.GetPeerID(), Timestamp: time.Now()}, batched)
+	}
+	if len(nm.outbox[batched.ID]) != 3 {
+		t.Fatalf("Expected 3 queued messages, got %d", len(nm.outbox[batched.ID]))
+	}
+
+	received := make(chan []SignedMessage, 1)
+	go func() {
+		var frame wireFrame
+		json.NewDecoder(remote).Decode(&frame)
+		msgs, _ := frame.messages()
+		received <- msgs
+	}()
+	nm.flushOutbox()
+
+	select {
+	case msgs := <-received:
+		if len(msgs) != 3 {
+			t.Errorf("Expected one frame with 3 messages, got %d", len(msgs))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the batch to be sent")
+	}
+	if len(nm.outbox) != 0 {
+		t.Errorf("Expected the outbox to be empty after flushing, got %v", nm.outbox)
+	}
+}
+
+func TestNetworkManagerSendsPingsUnbatched(t *testing.T) {
+	nm, _ := NewNetworkManager(NetworkManagerConfig{BatchInterval: time.Hour})
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	batched := &Peer{ID: "batched", Conn: local, Capabilities: wireCapabilities(nil)}
+
+	received := make(chan SignedMessage, 1)
+	go func() {
+		var signed SignedMessage
+		json.NewDecoder(remote).Decode(&signed)
+		received <- signed
+	}()
+	nm.sendMessageToPeer(Message{Type: MessageTypePing, From: nm.GetPeerID(), Timestamp: time.Now()}, batched)
+
+	select {
+	case signed := <-received:
+		if signed.Message.Type != MessageTypePing {
+			t.Errorf("Expected a plain ping, got %+v", signed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the ping to be sent without waiting for a flush")
+	}
+	if len(nm.outbox[batched.ID]) != 0 {
+		t.Errorf("Expected nothing queued, got %v", nm.outbox[batched.ID])
+	}
+}
+
+func TestNetworkManagerSendsAtOnceToOlderPeers(t *testing.T) {
+	nm, _ := NewNetworkManager(NetworkManagerConfig{BatchInterval: time.Hour})
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	older := &Peer{ID: "older", Conn: local}
+
+	received := make(chan SignedMessage, 1)
+	go func() {
+		var signed SignedMessage
+		json.NewDecoder(remote).Decode(&signed)
+		received <- signed
+	}()
+	nm.sendMessageToPeer(Message{Type: MessageTypeStateSync, From: nm.GetPeerID(), Timestamp: time.Now()}, older)
+
+	select {
+	case signed := <-received:
+		if signed.Message.Type != MessageTypeStateSync {
+			t.Errorf("Expected a plain state sync message, got %+v", signed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the message to be sent without waiting for a flush")
+	}
+}
+
+func TestNetworkManagerAdvertisesWireCapabilities(t *testing.T) {
+	nm, _ := NewNetworkManager(NetworkManagerConfig{Capabilities: []string{"chat", CapabilityGzip}})
+
+	want := []string{"chat", CapabilityGzip, CapabilityBatch, CapabilityPing}
+	if strings.Join(nm.capabilities, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected capabilities %v, got %v", want, nm.capabilities)
+	}
+	if nm.compressThreshold != defaultCompressThreshold {
+		t.Errorf("Expected the default compress threshold, got %d", nm.compressThreshold)
+	}
+}
+
+func TestHandlePeerConnectionUnpacksBatches(t *testing.T) {
+	nm, _ := NewNetworkManager(NetworkManagerConfig{})
+	sender, _ := NewNetworkManager(NetworkManagerConfig{})
+	received := receivedFrom(nm, MessageTypeStateSync)
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	go nm.handlePeerConnection(&Peer{ID: sender.GetPeerID(), Conn: local}, newFrameReader(local))
+
+	frame, err := encodeFrame(signedTestMessages(t, sender, 10, 300), 100, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	expectReceivedCount(t, received, 10)
+}
+
+// expectReceivedCount waits for n deliveries
+func expectReceivedCount(t *testing.T, received chan string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-received:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected %d messages, got %d", n, i)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package news
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/desktop-companion/lib/dialog"
+)
+
+const (
+	// DefaultDigestTime is when the daily briefing runs if the card doesn't say
+	DefaultDigestTime = "08:00"
+
+	defaultDigestItems = 3
+	maxDigestItems     = 10
+	maxDigestSummary   = 140 // Characters of summary read per story
+)
+
+// DigestConfig configures the daily news briefing, delivered as a sequence
+// of speech bubbles: an intro, one bubble per story and an outro
+type DigestConfig struct {
+	Enabled      bool     `json:"enabled"`
+	Time         string   `json:"time,omitempty"`         // Local "HH:MM" (default: 08:00)
+	MaxItems     int      `json:"maxItems,omitempty"`     // Stories per briefing (default: 3, max 10)
+	ReadingStyle string   `json:"readingStyle,omitempty"` // "casual", "formal" or "enthusiastic"; from personality when empty
+	Intro        []string `json:"intro,omitempty"`        // Opening lines, {COUNT} is the number of stories
+	Outro        []string `json:"outro,omitempty"`        // Closing lines
+}
+
+// GetTime returns the configured briefing time or the default
+func (d *DigestConfig) GetTime() string {
+	if d.Time == "" {
+		return DefaultDigestTime
+	}
+	return d.Time
+}
+
+// GetMaxItems returns the stories per briefing, applying the default
+func (d *DigestConfig) GetMaxItems() int {
+	if d.MaxItems <= 0 {
+		return defaultDigestItems
+	}
+	return d.MaxItems
+}
+
+// Validate checks the briefing time, story count and reading style
+func (d *DigestConfig) Validate() error {
+	if _, err := ParseDigestTime(d.GetTime()); err != nil {
+		return err
+	}
+	if d.MaxItems < 0 || d.MaxItems > maxDigestItems {
+		return fmt.Errorf("maxItems must be 0-%d, got %d", maxDigestItems, d.MaxItems)
+	}
+	switch d.ReadingStyle {
+	case "", "casual", "formal", "enthusiastic":
+		return nil
+	}
+	return fmt.Errorf("unknown readingStyle %q", d.ReadingStyle)
+}
+
+// ParseDigestTime parses "HH:MM" into minutes after midnight
+func ParseDigestTime(value string) (int, error) {
+	hour, minute, ok := strings.Cut(strings.TrimSpace(value), ":")
+	h, herr := strconv.Atoi(hour)
+	m, merr := strconv.Atoi(minute)
+	if !ok || herr != nil || merr != nil || h < 0 || h > 23 || m < 0 || m > 59 || len(minute) != 2 {
+		return 0, fmt.Errorf("invalid digest time %q, expected HH:MM", value)
+	}
+	return h*60 + m, nil
+}
+
+// FeedCategories returns the distinct categories of the enabled feeds, sorted
+func FeedCategories(feeds []RSSFeed) []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, feed := range feeds {
+		if !feed.Enabled || feed.Category == "" || seen[feed.Category] {
+			continue
+		}
+		seen[feed.Category] = true
+		categories = append(categories, feed.Category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// SelectDigestItems picks up to max stories from the subscribed categories,
+// all of them when none are given. Categories take turns, newest story
+// first, so one busy feed can't fill the whole briefing.
+func SelectDigestItems(items []*NewsItem, categories []string, max int) []*NewsItem {
+	subscribed := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		subscribed[category] = true
+	}
+
+	byCategory := make(map[string][]*NewsItem)
+	var order []string
+	for _, item := range items {
+		if len(subscribed) > 0 && !subscribed[item.Category] {
+			continue
+		}
+		if _, ok := byCategory[item.Category]; !ok {
+			order = append(order, item.Category)
+		}
+		byCategory[item.Category] = append(byCategory[item.Category], item)
+	}
+	sort.Strings(order)
+	for _, category := range order {
+		group := byCategory[category]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Published.After(group[j].Published) })
+	}
+
+	var selected []*NewsItem
+	for round := 0; len(selected) < max; round++ {
+		added := false
+		for _, category := range order {
+			if group := byCategory[category]; round < len(group) && len(selected) < max {
+				selected = append(selected, group[round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return selected
+}
+
+// BuildDigest returns the bubbles of a briefing in the given reading style,
+// or nil when there are no stories to read
+func BuildDigest(cfg *DigestConfig, style string, items []*NewsItem) []string {
+	if len(items) == 0 {
+		return nil
+	}
+
+	intro := cfg.Intro
+	if len(intro) == 0 {
+		intro = digestIntros(style)
+	}
+	outro := cfg.Outro
+	if len(outro) == 0 {
+		outro = digestOutros(style)
+	}
+
+	// Vary the lines from day to day without randomness, so a briefing
+	// reads the same if it's rebuilt
+	pick := func(lines []string, salt int) string {
+		return lines[(len(items[0].Title)+salt)%len(lines)]
+	}
+
+	bubbles := []string{strings.ReplaceAll(pick(intro, 0), "{COUNT}", strconv.Itoa(len(items)))}
+	for i, item := range items {
+		bubbles = append(bubbles, digestStory(item, style, i))
+	}
+	return append(bubbles, pick(outro, 1))
+}
+
+// digestStory phrases one story for the briefing
+func digestStory(item *NewsItem, style string, index int) string {
+	category := item.Category
+	if category == "" {
+		category = "news"
+	}
+
+	var lead string
+	switch style {
+	case "enthusiastic":
+		leads := []string{"Ooh, %s news! ", "And in %s: ", "This %s story is wild! "}
+		lead = fmt.Sprintf(leads[index%len(leads)], category)
+	case "formal":
+		lead = fmt.Sprintf("In %s: ", category)
+	default:
+		leads := []string{"In %s, ", "Also in %s, ", "Over in %s, "}
+		lead = fmt.Sprintf(leads[index%len(leads)], category)
+	}
+
+	text := lead + item.Title
+	if summary := summarize(item.Summary); summary != "" && !strings.EqualFold(summary, item.Title) {
+		text += "\n" + summary
+	}
+	if item.Source != "" {
+		text += fmt.Sprintf("\n— %s", item.Source)
+	}
+	return text
+}
+
+// summarize keeps the first sentence of a summary, cut at a word boundary
+// if it is still too long for a bubble
+func summarize(summary string) string {
+	summary = strings.Join(strings.Fields(summary), " ")
+	if end := strings.Index(summary, ". "); end >= 0 {
+		summary = summary[:end+1]
+	}
+	if len(summary) <= maxDigestSummary {
+		return summary
+	}
+	cut := strings.LastIndex(summary[:maxDigestSummary], " ")
+	if cut <= 0 {
+		cut = maxDigestSummary
+	}
+	return strings.TrimRight(summary[:cut], ",;:") + "…"
+}
+
+// digestIntros returns the default opening lines for a reading style
+func digestIntros(style string) []string {
+	switch style {
+	case "enthusiastic":
+		return []string{
+			"📰 Breaking news time! I've got {COUNT} stories for you!",
+			"📰 Good morning, good morning! Here's your news roundup!",
+		}
+	case "formal":
+		return []string{
+			"📰 Good day. Here is your daily briefing: {COUNT} stories.",
+			"📰 This is your daily news summary.",
+		}
+	default:
+		return []string{
+			"📰 Here's your daily news roundup!",
+			"📰 Time for the news! I picked {COUNT} stories for you.",
+		}
+	}
+}
+
+// digestOutros returns the default closing lines for a reading style
+func digestOutros(style string) []string {
+	switch style {
+	case "enthusiastic":
+		return []string{"That's the news! What a day! ✨", "And that's a wrap! Back to you! 🎤"}
+	case "formal":
+		return []string{"That concludes today's briefing.", "That is all for today's briefing."}
+	default:
+		return []string{"That's all for today!", "And that's the news. Back to you!"}
+	}
+}
+
+// GetDigestItems returns stories for a briefing from the cached feeds
+func (nb *NewsBlogBackend) GetDigestItems(categories []string, max int) []*NewsItem {
+	nb.mu.RLock()
+	defer nb.mu.RUnlock()
+	return SelectDigestItems(nb.cache.GetRecentItems(nb.cache.maxItems), categories, max)
+}
+
+// ReadingStyle returns the reading style the backend uses for a personality
+func (nb *NewsBlogBackend) ReadingStyle(traits map[string]float64) string {
+	return nb.determineReadingStyle(dialog.DialogContext{PersonalityTraits: traits})
+}
@@ -0,0 +1,112 @@
+package news
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDigestTime(t *testing.T) {
+	if minute, err := ParseDigestTime("07:30"); err != nil || minute != 450 {
+		t.Errorf("Expected 450, got %d, %v", minute, err)
+	}
+	for _, value := range []string{"", "7", "24:00", "07:60", "7:5", "noon"} {
+		if _, err := ParseDigestTime(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestDigestConfigValidate(t *testing.T) {
+	if err := (&DigestConfig{Enabled: true}).Validate(); err != nil {
+		t.Errorf("Defaults should be valid, got %v", err)
+	}
+	if err := (&DigestConfig{MaxItems: 20}).Validate(); err == nil {
+		t.Error("Expected too many items to be rejected")
+	}
+	if err := (&DigestConfig{ReadingStyle: "shouty"}).Validate(); err == nil {
+		t.Error("Expected an unknown reading style to be rejected")
+	}
+}
+
+func TestFeedCategories(t *testing.T) {
+	feeds := []RSSFeed{
+		{Name: "a", Category: "tech", Enabled: true},
+		{Name: "b", Category: "gaming", Enabled: true},
+		{Name: "c", Category: "tech", Enabled: true},
+		{Name: "d", Category: "sports", Enabled: false},
+	}
+	if got := strings.Join(FeedCategories(feeds), ","); got != "gaming,tech" {
+		t.Errorf("Expected gaming,tech, got %s", got)
+	}
+}
+
+func TestSelectDigestItems(t *testing.T) {
+	now := time.Now()
+	items := []*NewsItem{
+		{Title: "tech old", Category: "tech", Published: now.Add(-3 * time.Hour)},
+		{Title: "tech new", Category: "tech", Published: now},
+		{Title: "tech mid", Category: "tech", Published: now.Add(-time.Hour)},
+		{Title: "gaming", Category: "gaming", Published: now.Add(-2 * time.Hour)},
+		{Title: "sports", Category: "sports", Published: now},
+	}
+
+	titles := func(selected []*NewsItem) string {
+		var names []string
+		for _, item := range selected {
+			names = append(names, item.Title)
+		}
+		return strings.Join(names, ",")
+	}
+
+	// Categories take turns so gaming isn't crowded out by tech
+	if got := titles(SelectDigestItems(items, []string{"tech", "gaming"}, 3)); got != "gaming,tech new,tech mid" {
+		t.Errorf("Unexpected selection %s", got)
+	}
+	if got := titles(SelectDigestItems(items, nil, 2)); got != "gaming,sports" {
+		t.Errorf("No subscriptions should cover every category, got %s", got)
+	}
+	if got := SelectDigestItems(items, []string{"weather"}, 3); len(got) != 0 {
+		t.Errorf("Expected nothing for an unused category, got %s", titles(got))
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	items := []*NewsItem{
+		{Title: "Chips get faster", Summary: "A new chip is out. It is fast.", Category: "tech", Source: "Tech Daily"},
+		{Title: "Game released", Category: "gaming"},
+	}
+
+	if BuildDigest(&DigestConfig{}, "casual", nil) != nil {
+		t.Error("Expected no briefing without stories")
+	}
+
+	cfg := &DigestConfig{Intro: []string{"{COUNT} stories today"}, Outro: []string{"Bye"}}
+	bubbles := BuildDigest(cfg, "formal", items)
+	if len(bubbles) != 4 {
+		t.Fatalf("Expected intro, two stories and outro, got %q", bubbles)
+	}
+	if bubbles[0] != "2 stories today" || bubbles[3] != "Bye" {
+		t.Errorf("Unexpected intro or outro: %q", bubbles)
+	}
+	if bubbles[1] != "In tech: Chips get faster\nA new chip is out.\n— Tech Daily" {
+		t.Errorf("Unexpected story bubble %q", bubbles[1])
+	}
+
+	for _, style := range []string{"casual", "formal", "enthusiastic"} {
+		if bubbles := BuildDigest(&DigestConfig{}, style, items); len(bubbles) != 4 || bubbles[0] == "" {
+			t.Errorf("Style %s: expected default intro and outro, got %q", style, bubbles)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+	got := summarize(long)
+	if len(got) > maxDigestSummary+len("…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected a shortened summary, got %q", got)
+	}
+	if got := summarize("First.  Second sentence."); got != "First." {
+		t.Errorf("Expected the first sentence, got %q", got)
+	}
+}
@@ -45,6 +45,7 @@ type NewsConfig struct {
 	PreferredCategories []string            `json:"preferredCategories"` // Preferred news categories
 	Feeds               []RSSFeed           `json:"feeds"`               // List of RSS feeds
 	ReadingEvents       []NewsEvent         `json:"readingEvents"`       // News-specific events
+	Digest              *DigestConfig       `json:"digest,omitempty"`    // Daily news briefing
 }
 
 // NewsEvent extends general dialog events for news-specific scenarios
@@ -0,0 +1,70 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Builtin is a pack compiled into the binary
+type Builtin struct {
+	ID       string // File name without the extension, used by -builtin
+	Manifest *Manifest
+	data     []byte
+}
+
+// LoadBuiltins reads and verifies every pack at the root of fsys, sorted by
+// ID. A nil fsys holds no packs.
+func LoadBuiltins(fsys fs.FS) ([]Builtin, error) {
+	if fsys == nil {
+		return nil, nil
+	}
+	names, err := fs.Glob(fsys, "*"+Extension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list built-in packs: %w", err)
+	}
+	sort.Strings(names)
+
+	builtins := make([]Builtin, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in pack %s: %w", name, err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open built-in pack %s: %w", name, err)
+		}
+		manifest, err := Verify(zr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid built-in pack %s: %w", name, err)
+		}
+		builtins = append(builtins, Builtin{
+			ID:       strings.TrimSuffix(name, Extension),
+			Manifest: manifest,
+			data:     data,
+		})
+	}
+	return builtins, nil
+}
+
+// FindBuiltin returns the built-in pack whose ID or character name matches
+// name, ignoring case
+func FindBuiltin(builtins []Builtin, name string) (*Builtin, bool) {
+	for i := range builtins {
+		if strings.EqualFold(builtins[i].ID, name) || strings.EqualFold(builtins[i].Manifest.Name, name) {
+			return &builtins[i], true
+		}
+	}
+	return nil, false
+}
+
+// Extract extracts the pack below cacheDir like the package-level Extract,
+// returning the path of its character card
+func (b *Builtin) Extract(cacheDir string) (string, error) {
+	cardPath, _, err := ExtractData(b.ID+Extension, b.data, cacheDir)
+	return cardPath, err
+}
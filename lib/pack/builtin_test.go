@@ -0,0 +1,60 @@
+package pack
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestLoadBuiltins(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"character.json":         testCard,
+		"animations/idle.gif":    string(validGIF),
+		"animations/talking.gif": string(validGIF),
+	})
+	var buf bytes.Buffer
+	if _, err := Create(dir, &buf, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"pet.dcc":   {Data: buf.Bytes()},
+		"README.md": {Data: []byte("not a pack")},
+	}
+	builtins, err := LoadBuiltins(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builtins) != 1 || builtins[0].ID != "pet" || builtins[0].Manifest.Name != "Packed Pet" {
+		t.Fatalf("Expected the pet pack, got %+v", builtins)
+	}
+
+	for _, name := range []string{"pet", "PET", "packed pet"} {
+		if _, ok := FindBuiltin(builtins, name); !ok {
+			t.Errorf("Expected %q to find the pack", name)
+		}
+	}
+	if _, ok := FindBuiltin(builtins, "other"); ok {
+		t.Error("Expected no match for an unknown name")
+	}
+
+	cardPath, err := builtins[0].Extract(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := character.LoadCard(cardPath); err != nil {
+		t.Errorf("Expected the extracted card to load: %v", err)
+	}
+
+	if builtins, err := LoadBuiltins(nil); err != nil || len(builtins) != 0 {
+		t.Errorf("Expected no packs from a nil FS, got %v, %v", builtins, err)
+	}
+
+	fsys["broken.dcc"] = &fstest.MapFile{Data: []byte("not a zip")}
+	if _, err := LoadBuiltins(fsys); err == nil {
+		t.Error("Expected a broken pack to be rejected")
+	}
+}
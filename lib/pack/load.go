@@ -0,0 +1,260 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Limits on what a pack may hold, so a hostile archive can't fill the disk
+const (
+	maxPackFiles = 10000
+	maxPackBytes = 512 << 20 // Total uncompressed size
+)
+
+// DefaultCacheDir returns where packs are extracted to, in the user cache dir
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "desktop-companion", "packs"), nil
+}
+
+// Extract verifies the pack at packPath and extracts it below cacheDir,
+// returning the path of its character card. Each pack version gets its own
+// directory named after its checksum, so opening the same pack again reuses
+// the earlier extraction.
+func Extract(packPath, cacheDir string) (string, *Manifest, error) {
+	sum, err := fileChecksum(packPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	zr, err := zip.OpenReader(packPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open pack %s: %w", packPath, err)
+	}
+	defer zr.Close()
+
+	manifest, err := Verify(&zr.Reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid pack %s: %w", packPath, err)
+	}
+	cardPath, err := extractVerified(&zr.Reader, manifest, sum, cacheDir)
+	if err != nil {
+		return "", nil, err
+	}
+	return cardPath, manifest, nil
+}
+
+// ExtractData is Extract for a pack held in memory, such as one embedded in
+// the binary. name only labels errors.
+func ExtractData(name string, data []byte, cacheDir string) (string, *Manifest, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open pack %s: %w", name, err)
+	}
+	manifest, err := Verify(zr)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid pack %s: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	cardPath, err := extractVerified(zr, manifest, hex.EncodeToString(sum[:]), cacheDir)
+	if err != nil {
+		return "", nil, err
+	}
+	return cardPath, manifest, nil
+}
+
+// extractVerified extracts a verified pack with checksum sum below cacheDir,
+// reusing an earlier extraction of the same pack
+func extractVerified(zr *zip.Reader, manifest *Manifest, sum, cacheDir string) (string, error) {
+	dest := filepath.Join(cacheDir, sum[:16])
+	cardPath := filepath.Join(dest, filepath.FromSlash(manifest.Card))
+	if _, err := os.Stat(filepath.Join(dest, ManifestName)); err == nil {
+		return cardPath, nil
+	}
+
+	// Extract next to the final directory and rename, so an interrupted
+	// extraction is never mistaken for a complete one
+	tmp, err := os.MkdirTemp(cacheDir, ".extract-")
+	if err != nil {
+		if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+			return "", fmt.Errorf("failed to create pack cache: %w", err)
+		}
+		if tmp, err = os.MkdirTemp(cacheDir, ".extract-"); err != nil {
+			return "", fmt.Errorf("failed to create pack cache: %w", err)
+		}
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, f := range zr.File {
+		if err := extractFile(f, tmp); err != nil {
+			return "", err
+		}
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		// Another instance may have extracted the same pack meanwhile
+		if _, statErr := os.Stat(filepath.Join(dest, ManifestName)); statErr != nil {
+			return "", fmt.Errorf("failed to extract pack: %w", err)
+		}
+	}
+	return cardPath, nil
+}
+
+// Verify checks every file in the archive against the manifest: no file
+// missing, none extra, every size and checksum matching.
+func Verify(zr *zip.Reader) (*Manifest, error) {
+	if len(zr.File) > maxPackFiles {
+		return nil, fmt.Errorf("pack holds %d files, more than %d", len(zr.File), maxPackFiles)
+	}
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if err := checkEntryName(f.Name); err != nil {
+			return nil, err
+		}
+		if _, dup := entries[f.Name]; dup {
+			return nil, fmt.Errorf("duplicate file %s", f.Name)
+		}
+		entries[f.Name] = f
+	}
+
+	manifestFile, ok := entries[ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("missing %s", ManifestName)
+	}
+	manifest, err := readManifest(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		entry, ok := entries[file.Path]
+		if !ok {
+			return nil, fmt.Errorf("missing file %s", file.Path)
+		}
+		total += file.Size
+		if total > maxPackBytes {
+			return nil, fmt.Errorf("pack is larger than %d MB", maxPackBytes>>20)
+		}
+		sum, size, err := entryChecksum(entry, file.Size)
+		if err != nil {
+			return nil, err
+		}
+		if size != file.Size || sum != file.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", file.Path)
+		}
+		listed[file.Path] = true
+	}
+	for name := range entries {
+		if name != ManifestName && !listed[name] {
+			return nil, fmt.Errorf("file %s is not in the manifest", name)
+		}
+	}
+	if !listed[manifest.Card] {
+		return nil, fmt.Errorf("character card %s is not in the pack", manifest.Card)
+	}
+	return manifest, nil
+}
+
+// readManifest decodes and checks the manifest entry
+func readManifest(f *zip.File) (*Manifest, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(io.LimitReader(rc, 16<<20)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Format < 1 || manifest.Format > FormatVersion {
+		return nil, fmt.Errorf("unsupported pack format %d, this version reads up to %d", manifest.Format, FormatVersion)
+	}
+	if manifest.Card == "" {
+		return nil, fmt.Errorf("manifest names no character card")
+	}
+	return &manifest, nil
+}
+
+// checkEntryName rejects names that would escape the extraction directory
+func checkEntryName(name string) error {
+	clean := path.Clean(name)
+	if name == "" || strings.HasSuffix(name, "/") || strings.Contains(name, "\\") ||
+		path.IsAbs(name) || clean != name || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("unsafe file name %q", name)
+	}
+	return nil
+}
+
+// entryChecksum hashes an entry, reading at most one byte more than expected
+// so a lying size can't make it read forever
+func entryChecksum(f *zip.File, expected int64) (string, int64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, io.LimitReader(rc, expected+1))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// extractFile writes one verified entry below dir
+func extractFile(f *zip.File, dir string) error {
+	target := filepath.Join(dir, filepath.FromSlash(f.Name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	if _, err := io.Copy(out, io.LimitReader(rc, maxPackBytes)); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// fileChecksum returns the SHA-256 of a file as hex
+func fileChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to open pack: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to read pack: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
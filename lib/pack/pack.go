@@ -0,0 +1,313 @@
+// Package pack bundles a character into a single distributable archive
+// (.dcc) and loads characters back from one.
+//
+// A pack is a zip file holding the character directory: the card, its
+// animations, localized overrides, lore and anything else beside it, plus a
+// manifest.json listing every file with its size and SHA-256 checksum.
+// Packs are verified against the manifest before anything is extracted.
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+const (
+	// Extension is the file extension of character packs
+	Extension = ".dcc"
+	// ManifestName is the manifest's path inside a pack
+	ManifestName = "manifest.json"
+	// FormatVersion is the pack format this version writes and reads
+	FormatVersion = 1
+
+	// inheritedDir holds animations from a base card outside the character
+	// directory, see flattenCard
+	inheritedDir = "inherited"
+)
+
+// Manifest describes a pack's contents
+type Manifest struct {
+	Format      int       `json:"format"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Card        string    `json:"card"` // Path of the character card inside the pack
+	Created     time.Time `json:"created"`
+	Files       []File    `json:"files"`
+}
+
+// File is one packed file and its checksum
+type File struct {
+	Path   string `json:"path"` // Slash-separated, relative to the pack root
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// IsPack reports whether path names a character pack
+func IsPack(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), Extension)
+}
+
+// CreateFile packs the character at cardPath into the archive at outPath.
+// cardPath may be the card or its directory, which must hold character.json.
+func CreateFile(cardPath, outPath string) (*Manifest, error) {
+	f, err := os.Create(outPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pack: %w", err)
+	}
+
+	manifest, err := Create(cardPath, f, outPath)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write pack: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(outPath)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Create writes a pack of the character at cardPath to w. Hidden files and
+// other packs in the directory are left out, as is skip (the output file,
+// when it is written inside the directory).
+func Create(cardPath string, w io.Writer, skip string) (*Manifest, error) {
+	cardPath, err := resolveCardPath(cardPath)
+	if err != nil {
+		return nil, err
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(cardPath)
+	cardName := filepath.Base(cardPath)
+	files, err := listFiles(dir, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cards extending a base outside the directory are packed merged
+	flatCard, inherited, err := flattenCard(cardPath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Format:      FormatVersion,
+		Name:        card.Name,
+		Description: card.Description,
+		Card:        cardName,
+		Created:     time.Now().UTC(),
+	}
+
+	zw := zip.NewWriter(w)
+	for _, rel := range files {
+		var entry File
+		if rel == cardName && flatCard != nil {
+			entry, err = writeBytes(zw, rel, flatCard)
+		} else {
+			entry, err = writeFile(zw, rel, filepath.Join(dir, filepath.FromSlash(rel)))
+		}
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	for _, src := range inherited.sources() {
+		entry, err := writeFile(zw, inherited[src], src)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if _, err := writeBytes(zw, ManifestName, data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write pack: %w", err)
+	}
+	return manifest, nil
+}
+
+// resolveCardPath accepts a card file or a directory holding character.json
+func resolveCardPath(cardPath string) (string, error) {
+	absPath, err := filepath.Abs(cardPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", cardPath, err)
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read character: %w", err)
+	}
+	if info.IsDir() {
+		absPath = filepath.Join(absPath, "character.json")
+	}
+	return absPath, nil
+}
+
+// listFiles returns every regular file under dir as sorted slash paths
+func listFiles(dir, skip string) ([]string, error) {
+	skipAbs := ""
+	if skip != "" {
+		skipAbs, _ = filepath.Abs(skip)
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || IsPack(p) || p == skipAbs {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if filepath.ToSlash(rel) == ManifestName {
+			return fmt.Errorf("%s would clash with the pack manifest", p)
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list character files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// inheritedFiles maps files from outside the character directory to their
+// path in the pack
+type inheritedFiles map[string]string
+
+// sources returns the outside files in a stable order
+func (f inheritedFiles) sources() []string {
+	sources := make([]string, 0, len(f))
+	for src := range f {
+		sources = append(sources, src)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// flattenCard handles cards that extend a base outside their directory,
+// whose files would be missing from the pack. It returns the card merged
+// with its base, with the base's animations moved into inheritedDir, or nil
+// when the card can be packed as is.
+func flattenCard(cardPath string) ([]byte, inheritedFiles, error) {
+	data, err := os.ReadFile(cardPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read character card: %w", err)
+	}
+	var probe struct {
+		Extends string `json:"extends"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil || probe.Extends == "" {
+		return nil, nil, nil
+	}
+
+	merged, err := character.ResolveExtends(data, cardPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var root map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.UseNumber() // keep large integers such as seeds exact
+	if err := decoder.Decode(&root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse merged card: %w", err)
+	}
+	delete(root, "extends")
+
+	dir := filepath.Dir(cardPath)
+	inherited := make(inheritedFiles)
+	animations, _ := root["animations"].(map[string]interface{})
+	states := make([]string, 0, len(animations))
+	for state := range animations {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		file, ok := animations[state].(string)
+		if !ok || !escapes(file) {
+			continue
+		}
+		src := file
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(dir, src)
+		}
+		src = filepath.Clean(src)
+		if _, seen := inherited[src]; !seen {
+			inherited[src] = path.Join(inheritedDir, fmt.Sprintf("%d_%s", len(inherited), filepath.Base(src)))
+		}
+		animations[state] = inherited[src]
+	}
+
+	flat, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode merged card: %w", err)
+	}
+	return flat, inherited, nil
+}
+
+// escapes reports whether a card-relative path points outside the card's directory
+func escapes(p string) bool {
+	if filepath.IsAbs(p) {
+		return true
+	}
+	clean := filepath.ToSlash(filepath.Clean(p))
+	return clean == ".." || strings.HasPrefix(clean, "../")
+}
+
+// writeFile adds the file at src to the archive as name
+func writeFile(zw *zip.Writer, name, src string) (File, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	defer f.Close()
+	return writeEntry(zw, name, f)
+}
+
+// writeBytes adds data to the archive as name
+func writeBytes(zw *zip.Writer, name string, data []byte) (File, error) {
+	return writeEntry(zw, name, bytes.NewReader(data))
+}
+
+// writeEntry copies r into the archive, checksumming it on the way
+func writeEntry(zw *zip.Writer, name string, r io.Reader) (File, error) {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return File{}, fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, hash), r)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return File{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
@@ -0,0 +1,183 @@
+package pack
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// Minimal valid GIF data
+var validGIF = []byte{71, 73, 70, 56, 57, 97, 1, 0, 1, 0, 128, 0, 0, 255, 255, 255, 0, 0, 0, 44, 0, 0, 0, 0, 1, 0, 1, 0, 0, 2, 2, 68, 1, 0, 59}
+
+const testCard = `{
+	"name": "Packed Pet",
+	"description": "A pet in a pack",
+	"animations": {"idle": "animations/idle.gif", "talking": "animations/talking.gif"},
+	"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+	"behavior": {"idleTimeout": 30, "defaultSize": 128}
+}`
+
+// writeFiles creates files below dir from a path to content map
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCreateAndExtract(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"character.json":         testCard,
+		"animations/idle.gif":    string(validGIF),
+		"animations/talking.gif": string(validGIF),
+		"lore/backstory.md":      "Once upon a time",
+		".git/HEAD":              "ref: main",
+	})
+
+	packPath := filepath.Join(dir, "pet.dcc")
+	manifest, err := CreateFile(dir, packPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range manifest.Files {
+		paths = append(paths, f.Path)
+	}
+	if got := strings.Join(paths, ","); got != "animations/idle.gif,animations/talking.gif,character.json,lore/backstory.md" {
+		t.Errorf("Unexpected packed files %s", got)
+	}
+	if manifest.Name != "Packed Pet" || manifest.Card != "character.json" {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+
+	cache := t.TempDir()
+	cardPath, _, err := Extract(packPath, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Expected the extracted card to load: %v", err)
+	}
+	if card.Name != "Packed Pet" {
+		t.Errorf("Expected Packed Pet, got %s", card.Name)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(cardPath), "lore", "backstory.md")); err != nil {
+		t.Errorf("Expected lore to be extracted: %v", err)
+	}
+
+	// The same pack reuses its extraction
+	again, _, err := Extract(packPath, cache)
+	if err != nil || again != cardPath {
+		t.Errorf("Expected the earlier extraction to be reused, got %s, %v", again, err)
+	}
+}
+
+func TestCreateFlattensOutsideBase(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"base/character.json":         testCard,
+		"base/animations/idle.gif":    string(validGIF),
+		"base/animations/talking.gif": string(validGIF),
+		"pet/character.json":          `{"extends": "../base/character.json", "name": "Derived Pet"}`,
+	})
+
+	packPath := filepath.Join(t.TempDir(), "derived.dcc")
+	if _, err := CreateFile(filepath.Join(root, "pet"), packPath); err != nil {
+		t.Fatal(err)
+	}
+	cardPath, _, err := Extract(packPath, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Expected the flattened card to load on its own: %v", err)
+	}
+	if card.Name != "Derived Pet" || !strings.HasPrefix(card.Animations["idle"], inheritedDir+"/") {
+		t.Errorf("Expected inherited animations inside the pack, got %s %v", card.Name, card.Animations)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"character.json":         testCard,
+		"animations/idle.gif":    string(validGIF),
+		"animations/talking.gif": string(validGIF),
+	})
+	packPath := filepath.Join(t.TempDir(), "pet.dcc")
+	manifest, err := CreateFile(dir, packPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuild the archive with one file changed but the old manifest
+	tampered := filepath.Join(t.TempDir(), "tampered.dcc")
+	rewritePack(t, packPath, tampered, map[string]string{"character.json": strings.Replace(testCard, "Hello!", "Gotcha", 1)})
+	if _, _, err := Extract(tampered, t.TempDir()); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	extra := filepath.Join(t.TempDir(), "extra.dcc")
+	rewritePack(t, packPath, extra, map[string]string{"payload.sh": "rm -rf ~"})
+	if _, _, err := Extract(extra, t.TempDir()); err == nil || !strings.Contains(err.Error(), "not in the manifest") {
+		t.Errorf("Expected an unlisted file to be rejected, got %v", err)
+	}
+
+	escape := filepath.Join(t.TempDir(), "escape.dcc")
+	rewritePack(t, packPath, escape, map[string]string{"../evil.json": "{}"})
+	if _, _, err := Extract(escape, t.TempDir()); err == nil || !strings.Contains(err.Error(), "unsafe file name") {
+		t.Errorf("Expected path traversal to be rejected, got %v", err)
+	}
+
+	if len(manifest.Files) != 3 {
+		t.Errorf("Expected 3 files, got %d", len(manifest.Files))
+	}
+}
+
+// rewritePack copies a pack, replacing or adding the given entries
+func rewritePack(t *testing.T, src, dst string, changes map[string]string) {
+	t.Helper()
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	for _, f := range zr.File {
+		if _, replaced := changes[f.Name]; replaced {
+			continue
+		}
+		if err := zw.Copy(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range changes {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
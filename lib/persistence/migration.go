@@ -0,0 +1,216 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentSaveVersion is the save schema written by this build. Bump it and
+// register a migration from the previous version whenever saved data changes
+// in a way older files can't be loaded as-is.
+const CurrentSaveVersion = 2
+
+// ErrSaveTooNew is returned for saves written by a newer build
+var ErrSaveTooNew = errors.New("save was written by a newer version")
+
+// Migration upgrades a decoded save from version From to From+1. It works on
+// the raw JSON object so it can handle shapes the current structs can't.
+type Migration struct {
+	From        int
+	Description string
+	Migrate     func(save map[string]interface{}) error
+}
+
+// migrations holds the registered upgrades keyed by the version they start from
+var migrations = map[int]Migration{}
+
+// RegisterMigration adds an upgrade step. Registering the same starting
+// version twice is a programming error and panics.
+func RegisterMigration(m Migration) {
+	if _, exists := migrations[m.From]; exists {
+		panic(fmt.Sprintf("save migration from version %d registered twice", m.From))
+	}
+	migrations[m.From] = m
+}
+
+func init() {
+	RegisterMigration(Migration{
+		From:        0,
+		Description: "fill in timestamps and stat maxima missing from pre-versioned saves",
+		Migrate:     migrateUnversioned,
+	})
+	RegisterMigration(Migration{
+		From:        1,
+		Description: "clamp stat values that drifted out of range",
+		Migrate:     migrateClampStats,
+	})
+}
+
+// FormatSaveVersion returns the saveVersion string for a schema version
+func FormatSaveVersion(version int) string {
+	return strconv.Itoa(version)
+}
+
+// ParseSaveVersion reads a saveVersion value. Missing versions are 0 and the
+// original "1.0" string is 1.
+func ParseSaveVersion(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int(v), nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		major, _, _ := strings.Cut(v, ".")
+		version, err := strconv.Atoi(major)
+		if err != nil {
+			return 0, fmt.Errorf("unknown save version %q", v)
+		}
+		return version, nil
+	}
+	return 0, fmt.Errorf("unknown save version %v", value)
+}
+
+// MigrateSave upgrades raw save JSON to CurrentSaveVersion. It returns the
+// upgraded JSON and the version the save started at; saves that are already
+// current are returned unchanged.
+func MigrateSave(data []byte) ([]byte, int, error) {
+	var save map[string]interface{}
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse save file: %w", err)
+	}
+
+	from, err := ParseSaveVersion(save["saveVersion"])
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > CurrentSaveVersion {
+		return nil, from, fmt.Errorf("%w: version %d, this build reads up to %d", ErrSaveTooNew, from, CurrentSaveVersion)
+	}
+	if from == CurrentSaveVersion {
+		return data, from, nil
+	}
+
+	for version := from; version < CurrentSaveVersion; version++ {
+		m, ok := migrations[version]
+		if !ok {
+			return nil, from, fmt.Errorf("no save migration from version %d", version)
+		}
+		if err := m.Migrate(save); err != nil {
+			return nil, from, fmt.Errorf("migration from version %d (%s) failed: %w", version, m.Description, err)
+		}
+	}
+
+	save["saveVersion"] = FormatSaveVersion(CurrentSaveVersion)
+	if metadata, ok := save["metadata"].(map[string]interface{}); ok {
+		metadata["version"] = FormatSaveVersion(CurrentSaveVersion)
+	}
+
+	migrated, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to encode migrated save: %w", err)
+	}
+	return migrated, from, nil
+}
+
+// backupSave copies the original file before it is migrated. An existing
+// backup for the same version is kept, since it is the older copy.
+func backupSave(savePath string, version int, data []byte) (string, error) {
+	backupPath := fmt.Sprintf("%s.v%d.bak", savePath, version)
+	file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return backupPath, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create save backup: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(backupPath)
+		return "", fmt.Errorf("failed to write save backup: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(backupPath)
+		return "", fmt.Errorf("failed to write save backup: %w", err)
+	}
+	return backupPath, nil
+}
+
+// migrateUnversioned upgrades saves written before saveVersion existed.
+// They may lack the creation and decay timestamps and stat maxima, all of
+// which validation requires.
+func migrateUnversioned(save map[string]interface{}) error {
+	gameState, ok := save["gameState"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("save has no gameState")
+	}
+
+	// The last save time is the best guess for both timestamps; decay then
+	// resumes from when the game was last played
+	fallback := time.Now().Format(time.RFC3339Nano)
+	if metadata, ok := save["metadata"].(map[string]interface{}); ok {
+		if lastSaved, ok := metadata["lastSaved"].(string); ok && !isZeroTime(lastSaved) {
+			fallback = lastSaved
+		}
+	}
+	for _, field := range []string{"creationTime", "lastDecayUpdate"} {
+		if value, ok := gameState[field].(string); !ok || isZeroTime(value) {
+			gameState[field] = fallback
+		}
+	}
+
+	stats, _ := gameState["stats"].(map[string]interface{})
+	for _, raw := range stats {
+		stat, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if max, _ := stat["max"].(float64); max <= 0 {
+			stat["max"] = 100.0
+		}
+	}
+	return nil
+}
+
+// migrateClampStats fixes stats outside [0, max], which older builds could
+// write after a card lowered a maximum and which validation now rejects
+func migrateClampStats(save map[string]interface{}) error {
+	gameState, ok := save["gameState"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("save has no gameState")
+	}
+
+	stats, _ := gameState["stats"].(map[string]interface{})
+	for _, raw := range stats {
+		stat, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		max, _ := stat["max"].(float64)
+		if max <= 0 {
+			continue // Nothing sensible to clamp to; validation reports it
+		}
+		for _, field := range []string{"current", "criticalThreshold"} {
+			value, _ := stat[field].(float64)
+			stat[field] = math.Max(0, math.Min(max, value))
+		}
+		if rate, _ := stat["degradationRate"].(float64); rate < 0 {
+			stat["degradationRate"] = 0.0
+		}
+	}
+	return nil
+}
+
+// isZeroTime reports whether an RFC 3339 timestamp is Go's zero time
+func isZeroTime(value string) bool {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	return err != nil || t.IsZero()
+}
@@ -0,0 +1,185 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Save files as each historical version wrote them
+var historicalSaves = map[int]string{
+	0: `{
+  "characterName": "Old Pet",
+  "gameState": {
+    "stats": {
+      "hunger": {"current": 40, "degradationRate": 1, "criticalThreshold": 20}
+    },
+    "totalPlayTimeNanos": 0
+  },
+  "metadata": {"lastSaved": "2024-03-01T12:00:00Z"}
+}`,
+	1: `{
+  "characterName": "Old Pet",
+  "saveVersion": "1.0",
+  "gameState": {
+    "stats": {
+      "hunger": {"current": 130, "max": 100, "degradationRate": 1, "criticalThreshold": 20},
+      "energy": {"current": -5, "max": 80, "degradationRate": -2, "criticalThreshold": 90}
+    },
+    "lastDecayUpdate": "2024-03-01T12:00:00Z",
+    "creationTime": "2024-01-01T12:00:00Z",
+    "totalPlayTimeNanos": 0
+  },
+  "metadata": {"lastSaved": "2024-03-01T12:00:00Z", "version": "1.0"}
+}`,
+}
+
+func TestEveryVersionHasMigration(t *testing.T) {
+	for version := 0; version < CurrentSaveVersion; version++ {
+		if _, ok := migrations[version]; !ok {
+			t.Errorf("No migration registered from version %d", version)
+		}
+		if _, ok := historicalSaves[version]; !ok {
+			t.Errorf("No test fixture for version %d saves", version)
+		}
+	}
+}
+
+func TestParseSaveVersion(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    int
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{"", 0, false},
+		{"1.0", 1, false},
+		{"2", 2, false},
+		{float64(3), 3, false},
+		{"beta", 0, true},
+		{true, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSaveVersion(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSaveVersion(%v) = %d, %v; want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadMigratesHistoricalSaves(t *testing.T) {
+	for version, fixture := range historicalSaves {
+		dir := t.TempDir()
+		sm := NewSaveManager(dir)
+		savePath := filepath.Join(dir, sm.generateSaveFileName("Old Pet"))
+		if err := os.WriteFile(savePath, []byte(fixture), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := sm.LoadGameState("Old Pet")
+		if err != nil {
+			t.Fatalf("Version %d: load failed: %v", version, err)
+		}
+		if data.SaveVersion != FormatSaveVersion(CurrentSaveVersion) {
+			t.Errorf("Version %d: expected current version, got %q", version, data.SaveVersion)
+		}
+		for name, stat := range data.GameState.Stats {
+			if stat.Current < 0 || stat.Current > stat.Max || stat.CriticalThreshold > stat.Max || stat.DegradationRate < 0 {
+				t.Errorf("Version %d: stat %s still out of range: %+v", version, name, stat)
+			}
+		}
+
+		backup, err := os.ReadFile(savePath + ".v" + FormatSaveVersion(version) + ".bak")
+		if err != nil || string(backup) != fixture {
+			t.Errorf("Version %d: expected original file backed up, got %v", version, err)
+		}
+
+		// The upgraded file loads without migrating again
+		reloaded, err := os.ReadFile(savePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, from, err := MigrateSave(reloaded); err != nil || from != CurrentSaveVersion {
+			t.Errorf("Version %d: expected file rewritten as current, got version %d, %v", version, from, err)
+		}
+	}
+}
+
+func TestMigrateUnversionedTimestamps(t *testing.T) {
+	migrated, from, err := MigrateSave([]byte(historicalSaves[0]))
+	if err != nil || from != 0 {
+		t.Fatalf("Unexpected result: version %d, %v", from, err)
+	}
+
+	var decoded GameSaveData
+	if err := json.Unmarshal(migrated, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	lastSaved := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if !decoded.GameState.CreationTime.Equal(lastSaved) || !decoded.GameState.LastDecayUpdate.Equal(lastSaved) {
+		t.Errorf("Expected timestamps from lastSaved, got %v and %v",
+			decoded.GameState.CreationTime, decoded.GameState.LastDecayUpdate)
+	}
+	if decoded.GameState.Stats["hunger"].Max != 100 {
+		t.Errorf("Expected default max of 100, got %v", decoded.GameState.Stats["hunger"].Max)
+	}
+}
+
+func TestCurrentSaveNotMigrated(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewSaveManager(dir)
+	data := &GameSaveData{
+		CharacterName: "Pet",
+		GameState: &GameStateData{
+			Stats:           map[string]*StatData{"hunger": {Current: 50, Max: 100, DegradationRate: 1, CriticalThreshold: 20}},
+			LastDecayUpdate: time.Now(),
+			CreationTime:    time.Now(),
+		},
+	}
+	if err := sm.SaveGameState("Pet", data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sm.LoadGameState("Pet"); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "*.bak"))
+	if len(backups) != 0 {
+		t.Errorf("Current saves should not be backed up, got %v", backups)
+	}
+}
+
+func TestNewerSaveRejected(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewSaveManager(dir)
+	savePath := filepath.Join(dir, sm.generateSaveFileName("Pet"))
+	future := `{"characterName": "Pet", "saveVersion": "99", "gameState": {"stats": {}}}`
+	if err := os.WriteFile(savePath, []byte(future), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := sm.LoadGameState("Pet")
+	if !errors.Is(err, ErrSaveTooNew) {
+		t.Errorf("Expected ErrSaveTooNew, got %v", err)
+	}
+	if content, _ := os.ReadFile(savePath); string(content) != future {
+		t.Error("A save from a newer version must be left untouched")
+	}
+}
+
+func TestBackupKeepsOldestCopy(t *testing.T) {
+	savePath := filepath.Join(t.TempDir(), "pet.json")
+	if _, err := backupSave(savePath, 1, []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	backupPath, err := backupSave(savePath, 1, []byte("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(backupPath); string(content) != "first" {
+		t.Errorf("Expected the first backup kept, got %q", content)
+	}
+}
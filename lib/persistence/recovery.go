@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lastGoodSuffix names the copy of a save kept from the last session that
+// ended cleanly. A crash or a save that can no longer be loaded leaves it
+// untouched, so there is always something to go back to.
+const lastGoodSuffix = ".good"
+
+// lastGoodPath returns where the character's last good save is kept
+func (sm *SaveManager) lastGoodPath(characterName string) string {
+	return filepath.Join(sm.savePath, sm.generateSaveFileName(characterName)+lastGoodSuffix)
+}
+
+// MarkLastGood copies the character's save as the last good one. Call it
+// once the session has ended cleanly and the final save succeeded.
+func (sm *SaveManager) MarkLastGood(characterName string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(sm.savePath, sm.generateSaveFileName(characterName)))
+	if err != nil {
+		return fmt.Errorf("failed to read save: %w", err)
+	}
+	if err := writeFileAtomic(sm.lastGoodPath(characterName), data); err != nil {
+		return fmt.Errorf("failed to keep last good save: %w", err)
+	}
+	return nil
+}
+
+// LastGoodTime returns when the last good save was kept, false if there
+// is none
+func (sm *SaveManager) LastGoodTime(characterName string) (time.Time, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	info, err := os.Stat(sm.lastGoodPath(characterName))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// RestoreLastGood replaces the character's save with the last good one and
+// returns its data. The current save is only overwritten once the last good
+// one has been checked to load.
+func (sm *SaveManager) RestoreLastGood(characterName string) (*GameSaveData, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	data, err := os.ReadFile(sm.lastGoodPath(characterName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last good save: %w", err)
+	}
+
+	migrated, _, err := MigrateSave(data)
+	if err != nil {
+		return nil, err
+	}
+	var saveData GameSaveData
+	if err := json.Unmarshal(migrated, &saveData); err != nil {
+		return nil, fmt.Errorf("failed to parse last good save: %w", err)
+	}
+	if err := sm.validateSaveData(&saveData); err != nil {
+		return nil, fmt.Errorf("invalid last good save: %w", err)
+	}
+
+	if err := sm.ensureSaveDirectory(); err != nil {
+		return nil, fmt.Errorf("failed to create save directory: %w", err)
+	}
+	savePath := filepath.Join(sm.savePath, sm.generateSaveFileName(characterName))
+	if err := sm.atomicWriteJSON(savePath, &saveData); err != nil {
+		return nil, fmt.Errorf("failed to restore last good save: %w", err)
+	}
+	return &saveData, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLastGoodSaveRoundTrip(t *testing.T) {
+	sm := NewSaveManager(t.TempDir())
+	defer sm.Close()
+
+	if _, ok := sm.LastGoodTime("pet"); ok {
+		t.Fatal("Expected no last good save before a clean exit")
+	}
+	if err := sm.MarkLastGood("pet"); err == nil {
+		t.Error("Expected an error marking a save that doesn't exist")
+	}
+
+	good := createTestSaveData("pet")
+	if err := sm.SaveGameState("pet", good); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.MarkLastGood("pet"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sm.LastGoodTime("pet"); !ok {
+		t.Fatal("Expected a last good save after marking")
+	}
+
+	// A crash leaves a broken save behind
+	savePath := filepath.Join(sm.GetSaveDirectory(), "pet.json")
+	if err := os.WriteFile(savePath, []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sm.LoadGameState("pet"); err == nil {
+		t.Fatal("Expected the broken save to fail to load")
+	}
+
+	restored, err := sm.RestoreLastGood("pet")
+	if err != nil {
+		t.Fatalf("RestoreLastGood failed: %v", err)
+	}
+	if restored.GameState.Stats["hunger"].Current != 80 {
+		t.Errorf("Expected the good hunger of 80, got %f", restored.GameState.Stats["hunger"].Current)
+	}
+	if loaded, err := sm.LoadGameState("pet"); err != nil || loaded == nil {
+		t.Errorf("Expected the restored save to load, got %v", err)
+	}
+
+	saves, _ := sm.ListSaves()
+	if len(saves) != 1 {
+		t.Errorf("Expected the last good copy not to be listed as a save, got %v", saves)
+	}
+}
+
+func TestRestoreLastGoodKeepsSaveWhenBackupIsBroken(t *testing.T) {
+	sm := NewSaveManager(t.TempDir())
+	defer sm.Close()
+
+	if err := sm.SaveGameState("pet", createTestSaveData("pet")); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(sm.lastGoodPath("pet"), []byte("not json"), 0o644)
+
+	if _, err := sm.RestoreLastGood("pet"); err == nil {
+		t.Fatal("Expected a broken last good save to be rejected")
+	}
+	if loaded, err := sm.LoadGameState("pet"); err != nil || loaded == nil {
+		t.Errorf("Expected the current save to be untouched, got %v", err)
+	}
+}
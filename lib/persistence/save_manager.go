@@ -73,6 +73,48 @@ type GameStateData struct {
 	LastDecayUpdate    time.Time            `json:"lastDecayUpdate"`
 	CreationTime       time.Time            `json:"creationTime"`
 	TotalPlayTimeNanos int64                `json:"totalPlayTimeNanos"`
+	Achievements       []string             `json:"achievements,omitempty"`  // Names of earned achievements
+	Coins              int                  `json:"coins,omitempty"`         // Shop currency balance
+	Purchases          map[string]int       `json:"purchases,omitempty"`     // Shop item ID -> times bought
+	Inventory          map[string]int       `json:"inventory,omitempty"`     // Items held (gifts, job rewards)
+	ActiveSkin         string               `json:"activeSkin,omitempty"`    // Equipped skin shop item
+	UserBirthday       string               `json:"userBirthday,omitempty"`  // User's birthday as "MM-DD"
+	CalendarFired      map[string]int       `json:"calendarFired,omitempty"` // Calendar event name -> year it last fired
+	ScheduleFired      map[string]time.Time `json:"scheduleFired,omitempty"` // Scheduled event name -> last fire time
+	ActiveJob          *JobData             `json:"activeJob,omitempty"`     // Job running when the game was saved
+	Journal            []JournalEntryData   `json:"journal,omitempty"`       // Notable happenings, oldest first
+	Buffs              []BuffData           `json:"buffs,omitempty"`         // Temporary stat modifiers still running
+
+	// Interaction type -> times performed, for interactions.X conditions
+	InteractionCounts map[string]int `json:"interactionCounts,omitempty"`
+	// Interaction type -> when it was last performed, for since.X conditions
+	LastInteractions map[string]time.Time `json:"lastInteractions,omitempty"`
+}
+
+// JournalEntryData represents one entry of the character's journal
+type JournalEntryData struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Title  string    `json:"title"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// BuffData represents a running buff or debuff
+type BuffData struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Icon      string             `json:"icon,omitempty"`
+	Gain      map[string]float64 `json:"gain,omitempty"`
+	Decay     map[string]float64 `json:"decay,omitempty"`
+	Stacks    int                `json:"stacks"`
+	ExpiresAt time.Time          `json:"expiresAt"`
+}
+
+// JobData represents a job that was still running at save time
+type JobData struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	EndsAt    time.Time `json:"endsAt"`
 }
 
 // StatData represents a single stat's persistent data
@@ -311,9 +353,9 @@ func (sm *SaveManager) SaveGameState(characterName string, data *GameSaveData) e
 	data.Metadata = &SaveMetadata{
 		LastSaved:     time.Now(),
 		TotalPlayTime: time.Duration(data.GameState.TotalPlayTimeNanos),
-		Version:       "1.0",
+		Version:       FormatSaveVersion(CurrentSaveVersion),
 	}
-	data.SaveVersion = "1.0"
+	data.SaveVersion = FormatSaveVersion(CurrentSaveVersion)
 
 	// Perform atomic write
 	if err := sm.atomicWriteJSON(savePath, data); err != nil {
@@ -326,10 +368,11 @@ func (sm *SaveManager) SaveGameState(characterName string, data *GameSaveData) e
 }
 
 // LoadGameState loads game state from a JSON file
-// Returns nil if the save file doesn't exist (new game)
+// Returns nil if the save file doesn't exist (new game). Saves from older
+// versions are backed up, migrated and written back in the current format.
 func (sm *SaveManager) LoadGameState(characterName string) (*GameSaveData, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	sm.mu.Lock() // Migration may rewrite the file
+	defer sm.mu.Unlock()
 
 	fileName := sm.generateSaveFileName(characterName)
 	savePath := filepath.Join(sm.savePath, fileName)
@@ -347,8 +390,13 @@ func (sm *SaveManager) LoadGameState(characterName string) (*GameSaveData, error
 		return nil, fmt.Errorf("failed to read save file: %w", err)
 	}
 
+	migrated, fromVersion, err := MigrateSave(data)
+	if err != nil {
+		return nil, err
+	}
+
 	var saveData GameSaveData
-	if err := json.Unmarshal(data, &saveData); err != nil {
+	if err := json.Unmarshal(migrated, &saveData); err != nil {
 		return nil, fmt.Errorf("failed to parse save file: %w", err)
 	}
 
@@ -357,9 +405,36 @@ func (sm *SaveManager) LoadGameState(characterName string) (*GameSaveData, error
 		return nil, fmt.Errorf("invalid save data: %w", err)
 	}
 
+	if fromVersion < CurrentSaveVersion {
+		if err := sm.persistMigratedSave(savePath, fromVersion, data, &saveData); err != nil {
+			return nil, err
+		}
+	}
+
 	return &saveData, nil
 }
 
+// persistMigratedSave backs up the original file and replaces it with the
+// migrated data, so the upgrade only runs once and can be undone by hand
+func (sm *SaveManager) persistMigratedSave(savePath string, fromVersion int, original []byte, saveData *GameSaveData) error {
+	backupPath, err := backupSave(savePath, fromVersion, original)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.atomicWriteJSON(savePath, saveData); err != nil {
+		return fmt.Errorf("failed to write migrated save: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller":      getCaller(),
+		"fromVersion": fromVersion,
+		"toVersion":   CurrentSaveVersion,
+		"backup":      backupPath,
+	}).Info("Migrated save file")
+	return nil
+}
+
 // HasSave checks if a save file exists for the given character
 func (sm *SaveManager) HasSave(characterName string) bool {
 	sm.mu.RLock()
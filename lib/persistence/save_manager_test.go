@@ -548,7 +548,7 @@ func TestSaveManagerStatValidationEdgeCases(t *testing.T) {
 	// Create save data with invalid stats
 	invalidStatsData := &GameSaveData{
 		CharacterName: "InvalidStatsTest",
-		SaveVersion:   "1.0",
+		SaveVersion:   FormatSaveVersion(CurrentSaveVersion), // Older versions are repaired by migration
 		GameState: &GameStateData{
 			Stats: map[string]*StatData{
 				"invalid_current": {
@@ -0,0 +1,334 @@
+package pipeline
+
+// background.go keys out the solid backgrounds diffusion models tend to
+// paint even when transparency is requested. The background color is taken
+// from the frame border, removed by flood fill from the edges so matching
+// colors inside the character survive, and the remaining edge pixels get a
+// soft alpha with the background color unmixed from them.
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+)
+
+const (
+	// defaultBackgroundTolerance is the RGB distance still counted as background
+	defaultBackgroundTolerance = 48
+
+	// minBorderUniformity is the share of border pixels that must match the
+	// key color before a frame is treated as having a solid background
+	minBorderUniformity = 0.5
+
+	// minBackgroundQuality is the report quality below which validation warns
+	minBackgroundQuality = 0.6
+)
+
+// BackgroundRemovalReport summarizes background removal across the frames
+// of one animation.
+type BackgroundRemovalReport struct {
+	Frames           int      `json:"frames"`
+	FramesProcessed  int      `json:"frames_processed"`           // Frames with a solid background that was removed
+	BackgroundColor  string   `json:"background_color,omitempty"` // Key color of the first processed frame
+	BorderUniformity float64  `json:"border_uniformity"`          // Lowest share of border pixels matching the key color
+	RemovedRatio     float64  `json:"removed_ratio"`              // Average share of pixels made transparent
+	SoftEdgeRatio    float64  `json:"soft_edge_ratio"`            // Average share of pixels given partial alpha
+	Quality          float64  `json:"quality"`                    // 0.0-1.0 confidence the cut-out is clean
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// backgroundStats describes background removal on a single frame
+type backgroundStats struct {
+	alreadyTransparent bool
+	solid              bool
+	key                color.NRGBA
+	uniformity         float64
+	removed            float64
+	soft               float64
+}
+
+// RemoveBackgrounds rewrites each PNG frame with its solid background made
+// transparent. Frames that are already transparent or have no uniform
+// border are left untouched and noted in the report, as are frames that
+// can't be decoded. A tolerance of 0 uses the default.
+func RemoveBackgrounds(frames []string, tolerance int) (*BackgroundRemovalReport, error) {
+	if tolerance <= 0 {
+		tolerance = defaultBackgroundTolerance
+	}
+
+	report := &BackgroundRemovalReport{Frames: len(frames), BorderUniformity: 1, Quality: 1}
+	for i, path := range frames {
+		img, err := readPNG(path)
+		if err != nil {
+			// Leave it for the assembler, which reports unreadable frames
+			report.Warnings = append(report.Warnings, fmt.Sprintf("frame %d: %v, skipped", i, err))
+			continue
+		}
+
+		out, stats := removeBackground(img, float64(tolerance))
+		report.add(i, stats)
+		if !stats.solid {
+			continue
+		}
+		if err := writePNG(path, out); err != nil {
+			return nil, err
+		}
+	}
+
+	if report.FramesProcessed > 0 {
+		report.RemovedRatio /= float64(report.FramesProcessed)
+		report.SoftEdgeRatio /= float64(report.FramesProcessed)
+	}
+	return report, nil
+}
+
+// add folds one frame's statistics into the report
+func (r *BackgroundRemovalReport) add(frame int, stats backgroundStats) {
+	if stats.alreadyTransparent {
+		return
+	}
+	r.BorderUniformity = math.Min(r.BorderUniformity, stats.uniformity)
+
+	if !stats.solid {
+		r.Quality = math.Min(r.Quality, stats.uniformity)
+		r.Warnings = append(r.Warnings, fmt.Sprintf("frame %d: background is not uniform (%.0f%% of border), left as is", frame, stats.uniformity*100))
+		return
+	}
+
+	r.FramesProcessed++
+	if r.BackgroundColor == "" {
+		r.BackgroundColor = fmt.Sprintf("#%02x%02x%02x", stats.key.R, stats.key.G, stats.key.B)
+	}
+	r.RemovedRatio += stats.removed
+	r.SoftEdgeRatio += stats.soft
+
+	// A clean cut-out removes a good part of the frame but not all of it
+	coverage := 1.0
+	switch {
+	case stats.removed < 0.2:
+		coverage = stats.removed / 0.2
+		r.Warnings = append(r.Warnings, fmt.Sprintf("frame %d: only %.0f%% removed, background may not be solid", frame, stats.removed*100))
+	case stats.removed > 0.9:
+		coverage = (1 - stats.removed) / 0.1
+		r.Warnings = append(r.Warnings, fmt.Sprintf("frame %d: %.0f%% removed, character may have been keyed out", frame, stats.removed*100))
+	}
+	r.Quality = math.Min(r.Quality, stats.uniformity*coverage)
+}
+
+// removeBackground returns a copy of img with the background connected to
+// its border made transparent
+func removeBackground(img image.Image, tolerance float64) (*image.NRGBA, backgroundStats) {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+
+	var stats backgroundStats
+	w, h := out.Rect.Dx(), out.Rect.Dy()
+	if w == 0 || h == 0 {
+		return out, stats
+	}
+
+	border := borderIndexes(w, h)
+	transparent := 0
+	for _, i := range border {
+		if out.Pix[i*4+3] < 128 {
+			transparent++
+		}
+	}
+	if transparent*2 > len(border) {
+		stats.alreadyTransparent = true
+		return out, stats
+	}
+
+	stats.key = borderKeyColor(out, border)
+	matching := 0
+	for _, i := range border {
+		if colorDistance(out.Pix[i*4:], stats.key) <= tolerance {
+			matching++
+		}
+	}
+	stats.uniformity = float64(matching) / float64(len(border))
+	if stats.uniformity < minBorderUniformity {
+		return out, stats
+	}
+	stats.solid = true
+
+	background := floodBackground(out, border, stats.key, tolerance)
+	removed, soft := 0, 0
+	for i, isBackground := range background {
+		if isBackground {
+			out.Pix[i*4+3] = 0
+			removed++
+			continue
+		}
+		if touchesBackground(background, i, w, h) && matteEdge(out.Pix[i*4:i*4+4], stats.key, tolerance) {
+			soft++
+		}
+	}
+
+	total := float64(w * h)
+	stats.removed = float64(removed) / total
+	stats.soft = float64(soft) / total
+	return out, stats
+}
+
+// borderIndexes lists the pixel indexes along the edge of a w×h image
+func borderIndexes(w, h int) []int {
+	var indexes []int
+	for x := 0; x < w; x++ {
+		indexes = append(indexes, x)
+		if h > 1 {
+			indexes = append(indexes, (h-1)*w+x)
+		}
+	}
+	for y := 1; y < h-1; y++ {
+		indexes = append(indexes, y*w)
+		if w > 1 {
+			indexes = append(indexes, y*w+w-1)
+		}
+	}
+	return indexes
+}
+
+// borderKeyColor picks the most common border color. Colors are bucketed
+// to 4 bits per channel so noise and JPEG-style artifacts merge.
+func borderKeyColor(img *image.NRGBA, border []int) color.NRGBA {
+	type bucket struct{ count, r, g, b int }
+	buckets := make(map[uint16]*bucket)
+	var best *bucket
+	var bestKey uint16
+	for _, i := range border {
+		p := img.Pix[i*4 : i*4+3]
+		key := uint16(p[0]>>4)<<8 | uint16(p[1]>>4)<<4 | uint16(p[2]>>4)
+		bk, ok := buckets[key]
+		if !ok {
+			bk = &bucket{}
+			buckets[key] = bk
+		}
+		bk.count++
+		bk.r += int(p[0])
+		bk.g += int(p[1])
+		bk.b += int(p[2])
+		if best == nil || bk.count > best.count || (bk.count == best.count && key < bestKey) {
+			best, bestKey = bk, key
+		}
+	}
+	return color.NRGBA{
+		R: uint8(best.r / best.count),
+		G: uint8(best.g / best.count),
+		B: uint8(best.b / best.count),
+		A: 255,
+	}
+}
+
+// floodBackground marks pixels within tolerance of key that are connected
+// to the border, so enclosed areas of the same color are kept
+func floodBackground(img *image.NRGBA, border []int, key color.NRGBA, tolerance float64) []bool {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	background := make([]bool, w*h)
+	queue := make([]int, 0, len(border))
+	for _, i := range border {
+		if !background[i] && colorDistance(img.Pix[i*4:], key) <= tolerance {
+			background[i] = true
+			queue = append(queue, i)
+		}
+	}
+
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		x, y := i%w, i/w
+		for _, n := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+			if n[0] < 0 || n[0] >= w || n[1] < 0 || n[1] >= h {
+				continue
+			}
+			j := n[1]*w + n[0]
+			if !background[j] && colorDistance(img.Pix[j*4:], key) <= tolerance {
+				background[j] = true
+				queue = append(queue, j)
+			}
+		}
+	}
+	return background
+}
+
+// touchesBackground reports whether pixel i has a background neighbor
+func touchesBackground(background []bool, i, w, h int) bool {
+	x, y := i%w, i/w
+	return (x > 0 && background[i-1]) || (x < w-1 && background[i+1]) ||
+		(y > 0 && background[i-w]) || (y < h-1 && background[i+w])
+}
+
+// matteEdge gives an edge pixel that is still close to the key color a
+// partial alpha and removes the background's share of its color. Returns
+// true if the pixel was changed.
+func matteEdge(p []uint8, key color.NRGBA, tolerance float64) bool {
+	distance := colorDistance(p, key)
+	if distance >= 2*tolerance {
+		return false
+	}
+
+	alpha := (distance - tolerance) / tolerance
+	if alpha < 0.1 {
+		alpha = 0.1
+	}
+	unmix := func(observed, background uint8) uint8 {
+		v := (float64(observed) - (1-alpha)*float64(background)) / alpha
+		return uint8(math.Round(math.Max(0, math.Min(255, v))))
+	}
+	p[0] = unmix(p[0], key.R)
+	p[1] = unmix(p[1], key.G)
+	p[2] = unmix(p[2], key.B)
+	p[3] = uint8(math.Round(float64(p[3]) * alpha))
+	return true
+}
+
+// colorDistance is the RGB distance between a pixel and a color
+func colorDistance(p []uint8, c color.NRGBA) float64 {
+	dr := float64(p[0]) - float64(c.R)
+	dg := float64(p[1]) - float64(c.G)
+	db := float64(p[2]) - float64(c.B)
+	return math.Sqrt(dr*dr + dg*dg + db*db)
+}
+
+// readPNG decodes a PNG frame
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open frame %s: %w", path, err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode frame %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// writePNG replaces a PNG frame through a temporary file
+func writePNG(path string, img image.Image) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("create frame: %w", err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("encode frame: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close frame: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replace frame: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBackgroundFrame draws a 20x20 frame filled with bg and a 10x10
+// character square in the middle, with a hole of bg color inside it
+func writeBackgroundFrame(t *testing.T, path string, bg, fg color.NRGBA) {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			c := bg
+			if x >= 5 && x < 15 && y >= 5 && y < 15 {
+				c = fg
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	img.SetNRGBA(10, 10, bg) // Enclosed, must survive
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveBackgrounds(t *testing.T) {
+	dir := t.TempDir()
+	green := color.NRGBA{G: 255, A: 255}
+	red := color.NRGBA{R: 200, G: 30, B: 30, A: 255}
+
+	frames := []string{filepath.Join(dir, "0.png"), filepath.Join(dir, "1.png")}
+	for _, frame := range frames {
+		writeBackgroundFrame(t, frame, green, red)
+	}
+
+	report, err := RemoveBackgrounds(frames, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.FramesProcessed != 2 || report.BackgroundColor != "#00ff00" {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	if report.RemovedRatio != 0.75 || report.BorderUniformity != 1 || report.Quality != 1 {
+		t.Errorf("Expected 75%% removed from a uniform border, got %+v", report)
+	}
+
+	img, err := readPNG(frames[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Error("Border background should be transparent")
+	}
+	if _, _, _, a := img.At(7, 7).RGBA(); a == 0 {
+		t.Error("Character pixels should stay opaque")
+	}
+	if _, _, _, a := img.At(10, 10).RGBA(); a == 0 {
+		t.Error("Background-colored pixels inside the character should be kept")
+	}
+}
+
+func TestRemoveBackgroundsSkipsUnsuitableFrames(t *testing.T) {
+	dir := t.TempDir()
+
+	// Already transparent: nothing to do, no warning
+	transparent := filepath.Join(dir, "transparent.png")
+	writeBackgroundFrame(t, transparent, color.NRGBA{}, color.NRGBA{R: 255, A: 255})
+
+	// Noisy border: left alone with a warning
+	noisy := filepath.Join(dir, "noisy.png")
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(i*37), uint8(i*91), uint8(i*13), 255
+	}
+	f, err := os.Create(noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, img)
+	f.Close()
+	before, _ := os.ReadFile(noisy)
+
+	report, err := RemoveBackgrounds([]string{transparent, noisy}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.FramesProcessed != 0 {
+		t.Errorf("Expected no frames processed, got %d", report.FramesProcessed)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "frame 1") {
+		t.Errorf("Expected a warning for the noisy frame, got %v", report.Warnings)
+	}
+	if report.Quality >= minBackgroundQuality {
+		t.Errorf("Expected low quality for a frame that couldn't be keyed, got %.2f", report.Quality)
+	}
+	if after, _ := os.ReadFile(noisy); string(after) != string(before) {
+		t.Error("Frames without a solid background should not be rewritten")
+	}
+}
+
+func TestMatteEdge(t *testing.T) {
+	key := color.NRGBA{G: 255, A: 255}
+
+	// Halfway between the key and pure red, just past the tolerance
+	p := []uint8{60, 200, 0, 255}
+	if !matteEdge(p, key, 48) {
+		t.Fatal("Expected an edge pixel near the key color to be matted")
+	}
+	if p[3] == 255 || p[3] == 0 {
+		t.Errorf("Expected partial alpha, got %d", p[3])
+	}
+	if p[1] >= 200 {
+		t.Errorf("Expected the key color unmixed from the pixel, got green %d", p[1])
+	}
+
+	far := []uint8{255, 0, 0, 255}
+	if matteEdge(far, key, 48) || far[3] != 255 {
+		t.Error("Pixels far from the key color should be left alone")
+	}
+}
+
+func TestAddBackgroundRemovalReport(t *testing.T) {
+	result := &ValidationResult{Metrics: &AssetMetrics{}}
+	addBackgroundRemovalReport(result, &AssetMetrics{BackgroundRemoval: &BackgroundRemovalReport{Quality: 0.3}})
+
+	if result.Metrics.BackgroundRemoval == nil {
+		t.Error("Expected the report in the validation metrics")
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Code != "BACKGROUND_REMOVAL_QUALITY" {
+		t.Errorf("Expected a quality warning, got %v", result.Warnings)
+	}
+}
@@ -16,10 +16,19 @@ import (
 // CharacterConfig defines complete character processing configuration.
 type CharacterConfig struct {
 	Character  *CharacterRequest  `json:"character"`
-	States     []string           `json:"states"`     // Required animation states
-	GIFConfig  *ExtendedGIFConfig `json:"gif_config"` // GIF generation settings
-	Validation *ValidationConfig  `json:"validation"` // Quality requirements
-	Deployment *DeploymentConfig  `json:"deployment"` // Output configuration
+	States     []string           `json:"states"`              // Required animation states
+	GIFConfig  *ExtendedGIFConfig `json:"gif_config"`          // GIF generation settings
+	Validation *ValidationConfig  `json:"validation"`          // Quality requirements
+	Deployment *DeploymentConfig  `json:"deployment"`          // Output configuration
+	Prompts    *PromptConfig      `json:"prompts,omitempty"`   // Optional prompt templates and per-state overrides
+	Seeds      *SeedConfig        `json:"seeds,omitempty"`     // Optional fixed seeds for reproducible generation
+	Reference  *ReferenceConfig   `json:"reference,omitempty"` // Optional reference image for image-to-image generation
+	Models     []ModelRef         `json:"models,omitempty"`    // LoRAs, VAEs and ControlNets besides the checkpoint
+	Outputs    []OutputProfile    `json:"outputs,omitempty"`   // Extra resolutions written for every state (see variants.go)
+
+	// Force regenerates every state even when the lockfile says its deployed
+	// asset is up to date. Set from the command line, never from JSON.
+	Force bool `json:"-"`
 }
 
 // CharacterRequest defines character generation parameters.
@@ -48,6 +57,15 @@ type ExtendedGIFConfig struct {
 	FrameRate    int    `json:"frame_rate"`   // 10-15 FPS
 	Colors       int    `json:"colors"`       // Indexed color count (256 max)
 	Optimization string `json:"optimization"` // "size" or "quality"
+
+	// Background removal applied to frames before assembly (see background.go)
+	RemoveBackground    bool `json:"remove_background,omitempty"`    // Key out solid backgrounds the model painted
+	BackgroundTolerance int  `json:"background_tolerance,omitempty"` // RGB distance counted as background (default 48)
+
+	// Post-processing applied after the GIF is assembled (see postprocess.go)
+	NormalizeFrames bool `json:"normalize_frames,omitempty"` // Resample to FrameCount at FrameRate
+	Interpolate     bool `json:"interpolate,omitempty"`      // Cross-fade in-between frames instead of repeating
+	OptimizePalette bool `json:"optimize_palette,omitempty"` // Rebuild a shared palette of up to Colors entries
 }
 
 // ValidationConfig defines quality requirements.
@@ -62,10 +80,11 @@ type ValidationConfig struct {
 
 // DeploymentConfig specifies output and deployment settings.
 type DeploymentConfig struct {
-	OutputDir            string `json:"output_dir"`             // Target directory
-	BackupExisting       bool   `json:"backup_existing"`        // Backup existing assets
-	UpdateCharacterJSON  bool   `json:"update_character_json"`  // Update character.json files
-	ValidateBeforeDeploy bool   `json:"validate_before_deploy"` // Validate before deployment
+	OutputDir            string `json:"output_dir"`              // Target directory
+	BackupExisting       bool   `json:"backup_existing"`         // Backup existing assets
+	UpdateCharacterJSON  bool   `json:"update_character_json"`   // Update character.json files
+	ValidateBeforeDeploy bool   `json:"validate_before_deploy"`  // Validate before deployment
+	ArtifactsDir         string `json:"artifacts_dir,omitempty"` // Artifact manager directory deployed assets are registered in, empty to skip
 }
 
 // ComfyUIConfig holds ComfyUI server configuration.
@@ -75,6 +94,7 @@ type ComfyUIConfig struct {
 	Timeout       time.Duration `json:"timeout"`        // Request timeout
 	RetryAttempts int           `json:"retry_attempts"` // Failed request retries
 	QueueLimit    int           `json:"queue_limit"`    // Max concurrent jobs
+	ModelsDir     string        `json:"models_dir"`     // ComfyUI's models directory, for downloading missing LoRAs
 }
 
 // WorkflowConfig defines workflow template configuration.
@@ -89,6 +109,7 @@ type WorkflowConfig struct {
 type ModelConfig struct {
 	Name       string                 `json:"name"`       // Model name/path
 	Type       string                 `json:"type"`       // Model type (checkpoint, lora, etc.)
+	URL        string                 `json:"url"`        // Download source for missing LoRAs
 	Parameters map[string]interface{} `json:"parameters"` // Model-specific parameters
 }
 
@@ -192,6 +213,7 @@ func DefaultPipelineConfig() *PipelineConfig {
 			BackupExisting:       true,
 			UpdateCharacterJSON:  true,
 			ValidateBeforeDeploy: true,
+			ArtifactsDir:         "build/artifacts",
 		},
 	}
 }
@@ -397,11 +419,14 @@ func DefaultCharacterConfig(archetype string) *CharacterConfig {
 				Transparency: true,
 				MaxFileSize:  500000,
 			},
-			Width:        128,
-			Height:       128,
-			FrameRate:    12,
-			Colors:       256,
-			Optimization: "size",
+			Width:            128,
+			Height:           128,
+			FrameRate:        12,
+			Colors:           256,
+			Optimization:     "size",
+			RemoveBackground: true,
+			NormalizeFrames:  true,
+			OptimizePalette:  true,
 		},
 		Validation: &ValidationConfig{
 			MaxFileSize:          500000,
@@ -29,6 +29,9 @@ type Controller interface {
 
 	// DeployAssets moves validated assets to target locations
 	DeployAssets(ctx context.Context, result *ProcessResult) error
+
+	// ReproduceAsset regenerates an asset from a saved generation manifest
+	ReproduceAsset(ctx context.Context, manifest *GenerationManifest, outputPath string) (*GeneratedAsset, error)
 }
 
 // ProcessResult contains the result of processing a single character.
@@ -36,6 +39,9 @@ type ProcessResult struct {
 	Character        string                     `json:"character"`
 	Success          bool                       `json:"success"`
 	GeneratedAssets  map[string]*GeneratedAsset `json:"generated_assets"`
+	SkippedStates    []string                   `json:"skipped_states,omitempty"` // Up to date per the lockfile
+	OutputDir        string                     `json:"output_dir,omitempty"`     // Where DeployAssets puts the assets
+	InputHashes      map[string]string          `json:"input_hashes,omitempty"`   // State -> input hash, recorded on deploy
 	ValidationResult *ValidationResult          `json:"validation_result,omitempty"`
 	Errors           []ProcessError             `json:"errors,omitempty"`
 	Warnings         []ProcessWarning           `json:"warnings,omitempty"`
@@ -54,12 +60,14 @@ type BatchResult struct {
 
 // GeneratedAsset represents a single generated asset file.
 type GeneratedAsset struct {
-	State          string        `json:"state"`        // Animation state (idle, talking, etc.)
-	SourceFiles    []string      `json:"source_files"` // Original frame files
-	OutputPath     string        `json:"output_path"`  // Final GIF path
-	Metrics        *AssetMetrics `json:"metrics,omitempty"`
-	JobID          string        `json:"job_id,omitempty"` // ComfyUI job ID
-	GenerationTime time.Duration `json:"generation_time"`
+	State          string         `json:"state"`                   // Animation state (idle, talking, etc.)
+	SourceFiles    []string       `json:"source_files"`            // Original frame files
+	OutputPath     string         `json:"output_path"`             // Final GIF path
+	ManifestPath   string         `json:"manifest_path,omitempty"` // Generation manifest next to the GIF
+	Variants       []AssetVariant `json:"variants,omitempty"`      // Extra resolutions from the output profiles
+	Metrics        *AssetMetrics  `json:"metrics,omitempty"`
+	JobID          string         `json:"job_id,omitempty"` // ComfyUI job ID
+	GenerationTime time.Duration  `json:"generation_time"`
 }
 
 // ProcessError represents an error during processing.
@@ -132,6 +140,9 @@ func (c *pipelineController) ProcessCharacter(ctx context.Context, config *Chara
 	if config == nil {
 		return nil, fmt.Errorf("character config required")
 	}
+	if err := ValidateOutputProfiles(config.Outputs); err != nil {
+		return nil, err
+	}
 
 	startTime := time.Now()
 	result := &ProcessResult{
@@ -149,10 +160,40 @@ func (c *pipelineController) ProcessCharacter(ctx context.Context, config *Chara
 		return nil, fmt.Errorf("create temp directory: %w", err)
 	}
 	result.Metadata.TempDir = tempDir
-	defer c.cleanupTempDir(tempDir)
+	// Successful assets stay in the temp directory until DeployAssets copies them
+	defer func() {
+		if !result.Success || len(result.GeneratedAssets) == 0 {
+			c.cleanupTempDir(tempDir)
+		}
+	}()
 
-	// Generate assets for each required state
-	for _, state := range config.States {
+	// Only states whose inputs changed since they were deployed are generated
+	result.OutputDir = c.config.Deployment.OutputDir
+	if config.Deployment != nil && config.Deployment.OutputDir != "" {
+		result.OutputDir = config.Deployment.OutputDir
+	}
+	states, skipped, hashes, err := StaleStates(c.config, config, result.OutputDir, config.Force)
+	if err != nil {
+		return nil, fmt.Errorf("check lockfile: %w", err)
+	}
+	result.SkippedStates = skipped
+	result.InputHashes = hashes
+	if len(states) == 0 {
+		result.Success = true
+		result.ProcessingTime = time.Since(startTime)
+		return result, nil
+	}
+
+	// Every state starts from the same reference image, so upload it once
+	if config.Reference.Enabled() {
+		if err := c.uploadReference(ctx, config.Reference); err != nil {
+			return nil, fmt.Errorf("prepare reference image: %w", err)
+		}
+		result.Metadata.GenerationParams["reference"] = config.Reference.Image
+	}
+
+	// Generate assets for each stale state
+	for _, state := range states {
 		select {
 		case <-ctx.Done():
 			return result, ctx.Err()
@@ -190,7 +231,7 @@ func (c *pipelineController) ProcessCharacter(ctx context.Context, config *Chara
 	}
 
 	// Determine overall success
-	result.Success = len(result.Errors) == 0 && len(result.GeneratedAssets) == len(config.States)
+	result.Success = len(result.Errors) == 0 && len(result.GeneratedAssets) == len(states)
 	result.ProcessingTime = time.Since(startTime)
 
 	return result, nil
@@ -238,6 +279,11 @@ func (c *pipelineController) ProcessBatch(ctx context.Context, configs []*Charac
 				}
 			}
 
+			// Batch results are reported, not deployed
+			if charResult.Metadata != nil {
+				c.cleanupTempDir(charResult.Metadata.TempDir)
+			}
+
 			// Store result
 			mu.Lock()
 			result.Characters[cfg.Character.Archetype] = charResult
@@ -275,6 +321,9 @@ func (c *pipelineController) DeployAssets(ctx context.Context, result *ProcessRe
 	if !result.Success {
 		return fmt.Errorf("cannot deploy failed processing result")
 	}
+	if result.Metadata != nil {
+		defer c.cleanupTempDir(result.Metadata.TempDir)
+	}
 
 	// Validate assets before deployment if required
 	if c.config.Deployment.ValidateBeforeDeploy {
@@ -290,14 +339,19 @@ func (c *pipelineController) DeployAssets(ctx context.Context, result *ProcessRe
 	}
 
 	// Create target directory
-	targetDir := c.config.Deployment.OutputDir
+	targetDir := result.OutputDir
+	if targetDir == "" {
+		targetDir = c.config.Deployment.OutputDir
+	}
 	if err := os.MkdirAll(targetDir, 0o755); err != nil {
 		return fmt.Errorf("create target directory: %w", err)
 	}
 
 	// Deploy each asset
+	targets := make(map[string]string, len(result.GeneratedAssets))
 	for state, asset := range result.GeneratedAssets {
 		targetPath := filepath.Join(targetDir, state+".gif")
+		targets[state] = targetPath
 
 		// Backup existing file if required
 		if c.config.Deployment.BackupExisting {
@@ -310,6 +364,51 @@ func (c *pipelineController) DeployAssets(ctx context.Context, result *ProcessRe
 		if err := c.copyFile(asset.OutputPath, targetPath); err != nil {
 			return fmt.Errorf("deploy asset %s: %w", state, err)
 		}
+
+		for _, variant := range asset.Variants {
+			variantPath := VariantPath(targetPath, variant.Suffix)
+			if c.config.Deployment.BackupExisting {
+				if err := c.backupExistingAsset(variantPath); err != nil {
+					return fmt.Errorf("backup existing asset %s%s: %w", state, variant.Suffix, err)
+				}
+			}
+			if err := c.copyFile(variant.OutputPath, variantPath); err != nil {
+				return fmt.Errorf("deploy asset %s%s: %w", state, variant.Suffix, err)
+			}
+		}
+
+		// Keep the manifest with the asset so it can be reproduced later
+		if asset.ManifestPath != "" {
+			if err := c.copyFile(asset.ManifestPath, ManifestPath(targetPath)); err != nil {
+				return fmt.Errorf("deploy manifest %s: %w", state, err)
+			}
+		}
+	}
+
+	// Remember what the deployed states were generated from
+	deployed := make(map[string]string, len(result.GeneratedAssets))
+	for state := range result.GeneratedAssets {
+		if hash, ok := result.InputHashes[state]; ok {
+			deployed[state] = hash
+		}
+	}
+	if err := recordDeployedStates(targetDir, deployed); err != nil {
+		return fmt.Errorf("update lockfile: %w", err)
+	}
+
+	// Point the card at the variants made for each platform
+	cardPath := filepath.Join(targetDir, "character.json")
+	if _, err := os.Stat(cardPath); err == nil && c.config.Deployment.UpdateCharacterJSON {
+		if err := UpdateCardAnimations(cardPath, platformVariants(result.GeneratedAssets, targets)); err != nil {
+			return fmt.Errorf("update character card: %w", err)
+		}
+	}
+
+	// Track the assets' lineage alongside the character binaries
+	if dir := c.config.Deployment.ArtifactsDir; dir != "" {
+		if err := registerDeployedAssets(dir, result, targets); err != nil {
+			return fmt.Errorf("register deployed assets: %w", err)
+		}
 	}
 
 	return nil
@@ -320,11 +419,96 @@ func (c *pipelineController) generateAssetForState(ctx context.Context, config *
 	startTime := time.Now()
 
 	// Create workflow for this state
-	workflow, err := c.createWorkflowForState(config, state)
+	workflow, manifest, err := c.buildStateWorkflow(config, state)
 	if err != nil {
 		return nil, fmt.Errorf("create workflow: %w", err)
 	}
 
+	outputPath := filepath.Join(tempDir, state+".gif")
+	asset, err := c.runWorkflow(ctx, workflow, config.GIFConfig, state, outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Variants, err = WriteVariants(outputPath, config.Outputs, config.GIFConfig.Colors); err != nil {
+		return nil, fmt.Errorf("write variants: %w", err)
+	}
+
+	asset.ManifestPath = ManifestPath(outputPath)
+	if err := WriteManifest(asset.ManifestPath, manifest); err != nil {
+		return nil, err
+	}
+
+	asset.GenerationTime = time.Since(startTime)
+	return asset, nil
+}
+
+// ReproduceAsset regenerates an asset from a saved generation manifest.
+// The recorded workflow graph is submitted unchanged, so the same model and
+// seed produce the same frames.
+func (c *pipelineController) ReproduceAsset(ctx context.Context, manifest *GenerationManifest, outputPath string) (*GeneratedAsset, error) {
+	if manifest == nil || manifest.Workflow == nil || manifest.GIFConfig == nil {
+		return nil, fmt.Errorf("complete manifest required")
+	}
+	startTime := time.Now()
+
+	hash, err := HashWorkflowGraph(manifest.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	if hash != manifest.GraphHash {
+		return nil, fmt.Errorf("workflow graph hash mismatch")
+	}
+
+	tempDir, err := c.createTempDir(manifest.Character + "_reproduce")
+	if err != nil {
+		return nil, fmt.Errorf("create temp directory: %w", err)
+	}
+	defer c.cleanupTempDir(tempDir)
+
+	workflow := *manifest.Workflow
+	workflow.ID = fmt.Sprintf("%s_%s_%d", manifest.Character, manifest.State, time.Now().Unix())
+
+	tempOutput := filepath.Join(tempDir, manifest.State+".gif")
+	asset, err := c.runWorkflow(ctx, &workflow, manifest.GIFConfig, manifest.State, tempOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	if dir := filepath.Dir(outputPath); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create output directory: %w", err)
+		}
+	}
+	if err := c.copyFile(tempOutput, outputPath); err != nil {
+		return nil, fmt.Errorf("write reproduced asset: %w", err)
+	}
+
+	asset.OutputPath = outputPath
+	asset.SourceFiles = nil // frames lived in the removed temp directory
+	asset.GenerationTime = time.Since(startTime)
+	return asset, nil
+}
+
+// cancelJob stops a job the caller gave up on, so ComfyUI doesn't keep
+// generating it. The request gets its own short context since the caller's
+// is usually already cancelled.
+func (c *pipelineController) cancelJob(jobID string) error {
+	canceller, ok := c.comfyuiClient.(comfyui.JobCanceller)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := canceller.CancelJob(ctx, jobID); err != nil {
+		return fmt.Errorf("ComfyUI job %s may still be running: %w", jobID, err)
+	}
+	return nil
+}
+
+// runWorkflow submits a workflow, waits for it and assembles the frames into
+// a GIF at outputPath. Frames are saved next to the GIF.
+func (c *pipelineController) runWorkflow(ctx context.Context, workflow *comfyui.Workflow, gifCfg *ExtendedGIFConfig, state, outputPath string) (*GeneratedAsset, error) {
 	// Submit workflow to ComfyUI
 	job, err := c.comfyuiClient.SubmitWorkflow(ctx, workflow)
 	if err != nil {
@@ -334,30 +518,48 @@ func (c *pipelineController) generateAssetForState(ctx context.Context, config *
 	// Monitor job progress
 	progressChan, err := c.comfyuiClient.MonitorJob(ctx, job.ID)
 	if err != nil {
+		if cancelErr := c.cancelJob(job.ID); cancelErr != nil {
+			return nil, fmt.Errorf("monitor job: %w (%v)", err, cancelErr)
+		}
 		return nil, fmt.Errorf("monitor job: %w", err)
 	}
 
 	// Wait for completion
+	tracker := newProgressTracker(ctx, workflow, state)
 	var finalProgress comfyui.JobProgress
 	for progress := range progressChan {
 		finalProgress = progress
-		if progress.Err != nil {
-			return nil, fmt.Errorf("job failed: %w", progress.Err)
-		}
+		tracker.update(progress)
 	}
 
+	if ctx.Err() != nil {
+		if cancelErr := c.cancelJob(job.ID); cancelErr != nil {
+			return nil, fmt.Errorf("generation cancelled: %w (%v)", ctx.Err(), cancelErr)
+		}
+		return nil, fmt.Errorf("generation cancelled: %w", ctx.Err())
+	}
+	if finalProgress.Err != nil {
+		return nil, fmt.Errorf("job failed: %w", finalProgress.Err)
+	}
 	if finalProgress.Status != "completed" {
+		if finalProgress.Message != "" {
+			return nil, fmt.Errorf("job failed with status %s: %s", finalProgress.Status, finalProgress.Message)
+		}
 		return nil, fmt.Errorf("job failed with status: %s", finalProgress.Status)
 	}
 
+	// Remember the job's pace for --estimate. A lost sample only makes the
+	// next estimate a little less informed, so errors are ignored.
+	_ = recordTiming(TimingHistoryPath(c.config), MeasureWorkflow(workflow, gifCfg.FrameCount), tracker.executionTime())
+
 	// Get job result
 	jobResult, err := c.comfyuiClient.GetResult(ctx, job.ID)
 	if err != nil {
 		return nil, fmt.Errorf("get job result: %w", err)
 	}
 
-	// Save artifacts to temp directory
-	framesDir := filepath.Join(tempDir, state+"_frames")
+	// Save artifacts next to the output
+	framesDir := filepath.Join(filepath.Dir(outputPath), state+"_frames")
 	if err := os.MkdirAll(framesDir, 0o755); err != nil {
 		return nil, fmt.Errorf("create frames directory: %w", err)
 	}
@@ -372,57 +574,86 @@ func (c *pipelineController) generateAssetForState(ctx context.Context, config *
 		return nil, fmt.Errorf("collect frame files: %w", err)
 	}
 
+	var backgroundReport *BackgroundRemovalReport
+	if gifCfg.RemoveBackground {
+		backgroundReport, err = RemoveBackgrounds(frameFiles, gifCfg.BackgroundTolerance)
+		if err != nil {
+			return nil, fmt.Errorf("remove background: %w", err)
+		}
+	}
+
 	// Create GIF from frames
-	outputPath := filepath.Join(tempDir, state+".gif")
 	gifConfig := assets.GIFConfig{
-		Width:        config.GIFConfig.Width,
-		Height:       config.GIFConfig.Height,
-		FrameCount:   config.GIFConfig.FrameCount,
-		FrameRate:    config.GIFConfig.FrameRate,
-		MaxFileSize:  config.GIFConfig.MaxFileSize,
-		Transparency: config.GIFConfig.Transparency,
+		Width:        gifCfg.Width,
+		Height:       gifCfg.Height,
+		FrameCount:   gifCfg.FrameCount,
+		FrameRate:    gifCfg.FrameRate,
+		MaxFileSize:  gifCfg.MaxFileSize,
+		Transparency: gifCfg.Transparency,
 	}
 
 	if err := c.assetProcessor.Process(ctx, frameFiles, outputPath, gifConfig); err != nil {
 		return nil, fmt.Errorf("create GIF: %w", err)
 	}
 
+	if err := PostProcessGIF(outputPath, gifCfg); err != nil {
+		return nil, fmt.Errorf("post-process GIF: %w", err)
+	}
+
 	// Extract metrics
 	metrics, err := c.extractAssetMetrics(outputPath)
 	if err != nil {
 		return nil, fmt.Errorf("extract metrics: %w", err)
 	}
+	metrics.BackgroundRemoval = backgroundReport
 
 	return &GeneratedAsset{
-		State:          state,
-		SourceFiles:    frameFiles,
-		OutputPath:     outputPath,
-		Metrics:        metrics,
-		JobID:          job.ID,
-		GenerationTime: time.Since(startTime),
+		State:       state,
+		SourceFiles: frameFiles,
+		OutputPath:  outputPath,
+		Metrics:     metrics,
+		JobID:       job.ID,
 	}, nil
 }
 
 // createWorkflowForState creates a ComfyUI workflow for a character state.
 func (c *pipelineController) createWorkflowForState(config *CharacterConfig, state string) (*comfyui.Workflow, error) {
+	workflow, _, err := c.buildStateWorkflow(config, state)
+	return workflow, err
+}
+
+// buildStateWorkflow creates the workflow for a state along with the
+// manifest describing it. The seed is resolved here so it can be recorded.
+func (c *pipelineController) buildStateWorkflow(config *CharacterConfig, state string) (*comfyui.Workflow, *GenerationManifest, error) {
+	prompts, err := BuildStatePrompt(c.config, config, state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build prompt for state %s: %w", state, err)
+	}
+
+	seed := ResolveSeed(c.config, config, state)
+	generation := map[string]interface{}{
+		"width":     config.Character.OutputConfig.Width,
+		"height":    config.Character.OutputConfig.Height,
+		"steps":     c.config.Workflow.Quality.Steps,
+		"cfg_scale": c.config.Workflow.Quality.CFGScale,
+		"sampler":   c.config.Workflow.Quality.Sampler,
+		"scheduler": c.config.Workflow.Quality.Scheduler,
+		"seed":      seed,
+	}
+	if model := config.Character.Traits["model"]; model != "" {
+		generation["model"] = model
+	}
+
 	// This is a simplified workflow creation - in a full implementation,
 	// this would use the workflow template system and dynamic prompt injection
 	workflow := &comfyui.Workflow{
 		ID: fmt.Sprintf("%s_%s_%d", config.Character.Archetype, state, time.Now().Unix()),
 		Nodes: map[string]interface{}{
 			"prompt": map[string]interface{}{
-				"positive": c.buildPositivePrompt(config, state),
-				"negative": c.buildNegativePrompt(config, state),
-			},
-			"generation": map[string]interface{}{
-				"width":     config.Character.OutputConfig.Width,
-				"height":    config.Character.OutputConfig.Height,
-				"steps":     c.config.Workflow.Quality.Steps,
-				"cfg_scale": c.config.Workflow.Quality.CFGScale,
-				"sampler":   c.config.Workflow.Quality.Sampler,
-				"scheduler": c.config.Workflow.Quality.Scheduler,
-				"seed":      c.config.Workflow.Quality.Seed,
+				"positive": prompts.Positive,
+				"negative": prompts.Negative,
 			},
+			"generation": generation,
 		},
 		Meta: map[string]interface{}{
 			"archetype": config.Character.Archetype,
@@ -430,51 +661,30 @@ func (c *pipelineController) createWorkflowForState(config *CharacterConfig, sta
 			"style":     config.Character.Style,
 		},
 	}
-
-	return workflow, nil
-}
-
-// buildPositivePrompt constructs the positive prompt for generation.
-func (c *pipelineController) buildPositivePrompt(config *CharacterConfig, state string) string {
-	// Base character description
-	prompt := config.Character.Description
-
-	// Add style-specific prompts
-	if style, exists := c.config.Workflow.Styles[config.Character.Style]; exists {
-		prompt += ", " + style.Prompts.Positive
-	}
-
-	// Add state-specific modifiers
-	stateModifiers := map[string]string{
-		"idle":    "relaxed, calm, neutral expression",
-		"talking": "speaking, mouth open, expressive",
-		"happy":   "smiling, joyful, positive expression",
-		"sad":     "downcast, melancholy, subdued",
-		"shy":     "blushing, bashful, looking away",
-		"flirty":  "winking, playful, confident",
-		"loving":  "gentle smile, warm expression, affectionate",
-		"jealous": "frowning, crossed arms, possessive",
+	if config.Reference.Enabled() {
+		node, err := referenceNode(config.Reference, state)
+		if err != nil {
+			return nil, nil, err
+		}
+		workflow.Nodes["reference"] = node
+		workflow.Meta["mode"] = "img2img"
 	}
 
-	if modifier, exists := stateModifiers[state]; exists {
-		prompt += ", " + modifier
+	manifest, err := newGenerationManifest(config, state, workflow, prompts, seed)
+	if err != nil {
+		return nil, nil, err
 	}
+	return workflow, manifest, nil
+}
 
-	return prompt
+// buildPositivePrompt constructs the default positive prompt for generation.
+func (c *pipelineController) buildPositivePrompt(config *CharacterConfig, state string) string {
+	return defaultPositivePrompt(NewPromptVariables(c.config, config, state))
 }
 
-// buildNegativePrompt constructs the negative prompt for generation.
+// buildNegativePrompt constructs the default negative prompt for generation.
 func (c *pipelineController) buildNegativePrompt(config *CharacterConfig, state string) string {
-	prompt := "blurry, low quality, distorted, malformed"
-
-	// Add style-specific negative prompts
-	if style, exists := c.config.Workflow.Styles[config.Character.Style]; exists {
-		if style.Prompts.Negative != "" {
-			prompt += ", " + style.Prompts.Negative
-		}
-	}
-
-	return prompt
+	return defaultNegativePrompt(NewPromptVariables(c.config, config, state))
 }
 
 // collectFrameFiles finds all image files in a directory for GIF creation.
@@ -519,6 +729,7 @@ func (c *pipelineController) validateGeneratedAssets(ctx context.Context, assets
 		if err != nil {
 			return nil, fmt.Errorf("validate asset %s: %w", state, err)
 		}
+		addBackgroundRemovalReport(result, asset.Metrics)
 		assetResults[state] = result
 	}
 
@@ -548,6 +759,24 @@ func (c *pipelineController) validateGeneratedAssets(ctx context.Context, assets
 	return overall, nil
 }
 
+// addBackgroundRemovalReport carries the background removal report from
+// generation into the validation metrics and warns about poor cut-outs.
+func addBackgroundRemovalReport(result *ValidationResult, metrics *AssetMetrics) {
+	if metrics == nil || metrics.BackgroundRemoval == nil || result.Metrics == nil {
+		return
+	}
+	report := metrics.BackgroundRemoval
+	result.Metrics.BackgroundRemoval = report
+
+	if report.Quality < minBackgroundQuality {
+		result.Warnings = append(result.Warnings, ValidationWarning{
+			Code:       "BACKGROUND_REMOVAL_QUALITY",
+			Message:    fmt.Sprintf("Background removal quality %.2f is below %.2f", report.Quality, minBackgroundQuality),
+			Suggestion: "Ask for a plain single-color background in the prompt or adjust background_tolerance",
+		})
+	}
+}
+
 // createTempDir creates a temporary directory for processing.
 func (c *pipelineController) createTempDir(character string) (string, error) {
 	baseDir := c.config.Generation.TempDir
@@ -0,0 +1,361 @@
+package pipeline
+
+// estimate.go projects how long a batch will take before any job is queued.
+// Every finished job records how long ComfyUI spent executing it against
+// the work in its workflow (sampler steps x megapixels x frames), so an
+// estimate multiplies the same measure of the planned workflows by the
+// per-model rate seen so far. ComfyUI executes one prompt at a time, so the
+// batch is simulated as a single queue fed by ConcurrentJobs characters.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// TimingHistoryName is the timing history kept in the generation temp directory.
+const TimingHistoryName = "timing.json"
+
+// TimingHistoryVersion is the current timing history format.
+const TimingHistoryVersion = 1
+
+// anyModel keys the timing of every model together, used for models
+// without history of their own.
+const anyModel = "*"
+
+// timingMu serializes updates to the history file by batch workers.
+var timingMu sync.Mutex
+
+// TimingHistory accumulates execution time per unit of work by model.
+type TimingHistory struct {
+	Version int                    `json:"version"`
+	Models  map[string]*StepTiming `json:"models"`
+}
+
+// StepTiming totals the jobs measured for one model.
+type StepTiming struct {
+	Jobs    int     `json:"jobs"`
+	Work    float64 `json:"work"`    // Megapixel-steps executed
+	Seconds float64 `json:"seconds"` // Execution time, queue wait excluded
+}
+
+// SecondsPerUnit is the average time per megapixel-step, 0 before any job.
+func (t *StepTiming) SecondsPerUnit() float64 {
+	if t == nil || t.Work <= 0 {
+		return 0
+	}
+	return t.Seconds / t.Work
+}
+
+// WorkflowWork is the sampling work in a workflow.
+type WorkflowWork struct {
+	Model  string // Checkpoint or model preset, "" when the workflow names none
+	Steps  int
+	Width  int
+	Height int
+	Frames int // Images sampled at once
+}
+
+// Units is the work in megapixel-steps.
+func (w WorkflowWork) Units() float64 {
+	return float64(w.Steps) * float64(w.Width) * float64(w.Height) * float64(max(w.Frames, 1)) / 1e6
+}
+
+// MeasureWorkflow reads the step count, resolution and model from a
+// workflow: the pipeline's own "generation" node or ComfyUI's KSampler,
+// EmptyLatentImage and checkpoint loader inputs. frames is used when the
+// workflow sets no batch size.
+func MeasureWorkflow(workflow *comfyui.Workflow, frames int) WorkflowWork {
+	work := WorkflowWork{Frames: frames}
+	if workflow == nil {
+		return work
+	}
+	for _, raw := range workflow.Nodes {
+		node, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if inputs, ok := node["inputs"].(map[string]interface{}); ok {
+			node = inputs
+		}
+		if steps, ok := intValue(node["steps"]); ok {
+			work.Steps = max(work.Steps, steps)
+		}
+		if width, ok := intValue(node["width"]); ok {
+			work.Width = max(work.Width, width)
+		}
+		if height, ok := intValue(node["height"]); ok {
+			work.Height = max(work.Height, height)
+		}
+		if batch, ok := intValue(node["batch_size"]); ok {
+			work.Frames = batch
+		}
+		for _, key := range []string{"model", "ckpt_name"} {
+			if model, ok := node[key].(string); ok && model != "" {
+				work.Model = model
+			}
+		}
+	}
+	return work
+}
+
+// intValue reads a number decoded from JSON or set from Go.
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// TimingHistoryPath returns where the timing history is kept for a config.
+func TimingHistoryPath(cfg *PipelineConfig) string {
+	dir := cfg.Generation.TempDir
+	if dir == "" {
+		dir = "temp/generation"
+	}
+	return filepath.Join(dir, TimingHistoryName)
+}
+
+// LoadTimingHistory reads a timing history. A missing file is an empty
+// history, so the first batch runs without an estimate.
+func LoadTimingHistory(path string) (*TimingHistory, error) {
+	history := &TimingHistory{Version: TimingHistoryVersion, Models: make(map[string]*StepTiming)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read timing history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("parse timing history: %w", err)
+	}
+	if history.Version != TimingHistoryVersion {
+		return nil, fmt.Errorf("unsupported timing history version %d", history.Version)
+	}
+	if history.Models == nil {
+		history.Models = make(map[string]*StepTiming)
+	}
+	return history, nil
+}
+
+// WriteTimingHistory saves a timing history as indented JSON.
+func WriteTimingHistory(path string, history *TimingHistory) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode timing history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create timing history directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write timing history: %w", err)
+	}
+	return nil
+}
+
+// Record adds a finished job to the model's timing and the overall timing.
+func (h *TimingHistory) Record(work WorkflowWork, took time.Duration) {
+	if work.Units() <= 0 || took <= 0 {
+		return
+	}
+	for _, model := range []string{work.Model, anyModel} {
+		timing := h.Models[model]
+		if timing == nil {
+			timing = &StepTiming{}
+			h.Models[model] = timing
+		}
+		timing.Jobs++
+		timing.Work += work.Units()
+		timing.Seconds += took.Seconds()
+	}
+}
+
+// Estimate projects the execution time of work. ok is false when no job
+// has been measured yet; other models' timing stands in for a model
+// without its own.
+func (h *TimingHistory) Estimate(work WorkflowWork) (time.Duration, bool) {
+	rate := h.Models[work.Model].SecondsPerUnit()
+	if rate <= 0 {
+		rate = h.Models[anyModel].SecondsPerUnit()
+	}
+	if rate <= 0 {
+		return 0, false
+	}
+	seconds := work.Units() * rate
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond), true
+}
+
+// recordTiming adds a finished job to the history file.
+func recordTiming(path string, work WorkflowWork, took time.Duration) error {
+	if work.Units() <= 0 || took <= 0 {
+		return nil
+	}
+	timingMu.Lock()
+	defer timingMu.Unlock()
+
+	history, err := LoadTimingHistory(path)
+	if err != nil {
+		return err
+	}
+	history.Record(work, took)
+	return WriteTimingHistory(path, history)
+}
+
+// StateEstimate is the projected work and time of one state.
+type StateEstimate struct {
+	State    string
+	Work     WorkflowWork
+	Duration time.Duration
+	Known    bool // Duration comes from timing history
+}
+
+// CharacterEstimate is the projected schedule of one character.
+type CharacterEstimate struct {
+	Character string
+	States    []StateEstimate
+	Skipped   []string      // Up to date per the lockfile
+	Duration  time.Duration // Execution time of its states
+	Start     time.Duration // When its first job runs, from the start of the batch
+	Finish    time.Duration // When its last job finishes
+	Known     bool          // Every state's duration comes from timing history
+}
+
+// BatchEstimate is the projected schedule of a batch.
+type BatchEstimate struct {
+	Characters     []*CharacterEstimate // In the order they finish
+	ConcurrentJobs int
+	QueuedAhead    int  // Jobs already pending or running on the server
+	QueueKnown     bool // The server's queue could be read
+	WallClock      time.Duration
+	Known          bool // Every duration comes from timing history
+}
+
+// EstimateBatch projects the time each character's stale states will take
+// and the order ComfyUI will finish them in. The server is only asked for
+// its queue, which client may be nil to skip.
+func EstimateBatch(ctx context.Context, cfg *PipelineConfig, configs []*CharacterConfig, history *TimingHistory, client comfyui.Client) (*BatchEstimate, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no character configs provided")
+	}
+	estimate := &BatchEstimate{ConcurrentJobs: max(cfg.Generation.ConcurrentJobs, 1), Known: true}
+
+	if client != nil {
+		if status, err := client.GetQueueStatus(ctx); err == nil {
+			estimate.QueuedAhead = status.Pending + status.Running
+			estimate.QueueKnown = true
+		}
+	}
+
+	builder := &pipelineController{config: cfg}
+	characters := make([]*CharacterEstimate, 0, len(configs))
+	for _, config := range configs {
+		character, err := builder.estimateCharacter(config, history)
+		if err != nil {
+			return nil, fmt.Errorf("estimate %s: %w", config.Character.Archetype, err)
+		}
+		estimate.Known = estimate.Known && character.Known
+		characters = append(characters, character)
+	}
+
+	estimate.Characters, estimate.WallClock = scheduleCharacters(characters, estimate.ConcurrentJobs)
+	return estimate, nil
+}
+
+// estimateCharacter builds the workflows of a character's stale states and
+// estimates each.
+func (c *pipelineController) estimateCharacter(config *CharacterConfig, history *TimingHistory) (*CharacterEstimate, error) {
+	outputDir := c.config.Deployment.OutputDir
+	if config.Deployment != nil && config.Deployment.OutputDir != "" {
+		outputDir = config.Deployment.OutputDir
+	}
+	states, skipped, _, err := StaleStates(c.config, config, outputDir, config.Force)
+	if err != nil {
+		return nil, fmt.Errorf("check lockfile: %w", err)
+	}
+
+	frames := c.config.Generation.FrameCount
+	if config.GIFConfig != nil && config.GIFConfig.FrameCount > 0 {
+		frames = config.GIFConfig.FrameCount
+	}
+
+	character := &CharacterEstimate{Character: config.Character.Archetype, Skipped: skipped, Known: true}
+	for _, state := range states {
+		workflow, _, err := c.buildStateWorkflow(config, state)
+		if err != nil {
+			return nil, err
+		}
+		work := MeasureWorkflow(workflow, frames)
+		duration, known := history.Estimate(work)
+		character.States = append(character.States, StateEstimate{State: state, Work: work, Duration: duration, Known: known})
+		character.Duration += duration
+		character.Known = character.Known && known
+	}
+	return character, nil
+}
+
+// scheduleCharacters simulates ProcessBatch: up to concurrent characters
+// submit their states one after another, and ComfyUI runs the submitted
+// jobs in order, one at a time. Returns the characters in the order they
+// finish and when the last one does.
+func scheduleCharacters(characters []*CharacterEstimate, concurrent int) ([]*CharacterEstimate, time.Duration) {
+	type job struct {
+		character *CharacterEstimate
+		state     int
+	}
+
+	var queue []job
+	var finished []*CharacterEstimate
+	next := 0
+	var now time.Duration
+
+	// start submits the next waiting character's first state
+	start := func() {
+		for next < len(characters) {
+			character := characters[next]
+			next++
+			character.Start = now
+			if len(character.States) == 0 {
+				character.Finish = now
+				finished = append(finished, character)
+				continue
+			}
+			queue = append(queue, job{character: character})
+			return
+		}
+	}
+
+	for i := 0; i < concurrent && i < len(characters); i++ {
+		start()
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.state == 0 {
+			current.character.Start = now
+		}
+		now += current.character.States[current.state].Duration
+
+		if current.state+1 < len(current.character.States) {
+			queue = append(queue, job{character: current.character, state: current.state + 1})
+			continue
+		}
+		current.character.Finish = now
+		finished = append(finished, current.character)
+		start()
+	}
+	return finished, now
+}
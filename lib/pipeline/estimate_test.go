@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+func TestMeasureWorkflow(t *testing.T) {
+	// ComfyUI's API format keeps parameters under inputs
+	workflow := &comfyui.Workflow{Nodes: map[string]interface{}{
+		"4": map[string]interface{}{"class_type": "CheckpointLoaderSimple", "inputs": map[string]interface{}{"ckpt_name": "sdxl.safetensors"}},
+		"5": map[string]interface{}{"class_type": "EmptyLatentImage", "inputs": map[string]interface{}{"width": 512.0, "height": 768.0, "batch_size": 4.0}},
+		"3": map[string]interface{}{"class_type": "KSampler", "inputs": map[string]interface{}{"steps": 25.0}},
+	}}
+	want := WorkflowWork{Model: "sdxl.safetensors", Steps: 25, Width: 512, Height: 768, Frames: 4}
+	if got := MeasureWorkflow(workflow, 6); got != want {
+		t.Errorf("MeasureWorkflow() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTimingHistoryEstimate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), TimingHistoryName)
+	work := WorkflowWork{Model: "flux1d", Steps: 20, Width: 500, Height: 500, Frames: 4} // 20 megapixel-steps
+
+	history, err := LoadTimingHistory(path)
+	if err != nil {
+		t.Fatalf("LoadTimingHistory() on a missing file: %v", err)
+	}
+	if _, ok := history.Estimate(work); ok {
+		t.Error("Expected no estimate without history")
+	}
+
+	if err := recordTiming(path, work, 40*time.Second); err != nil {
+		t.Fatalf("recordTiming() error = %v", err)
+	}
+	history, err = LoadTimingHistory(path)
+	if err != nil {
+		t.Fatalf("LoadTimingHistory() error = %v", err)
+	}
+
+	double := work
+	double.Steps = 40
+	if got, ok := history.Estimate(double); !ok || got != 80*time.Second {
+		t.Errorf("Estimate() = %v, %v, want 80s scaled by steps", got, ok)
+	}
+	other := work
+	other.Model = "sdxl"
+	if got, ok := history.Estimate(other); !ok || got != 40*time.Second {
+		t.Errorf("Estimate() for an unmeasured model = %v, %v, want the overall rate", got, ok)
+	}
+}
+
+func TestScheduleCharactersSharesOneQueue(t *testing.T) {
+	states := func(durations ...time.Duration) []StateEstimate {
+		var out []StateEstimate
+		for _, d := range durations {
+			out = append(out, StateEstimate{Duration: d, Known: true})
+		}
+		return out
+	}
+	a := &CharacterEstimate{Character: "a", States: states(10*time.Second, 10*time.Second)}
+	b := &CharacterEstimate{Character: "b", States: states(5 * time.Second)}
+	c := &CharacterEstimate{Character: "c", States: states(20 * time.Second)}
+
+	order, total := scheduleCharacters([]*CharacterEstimate{a, b, c}, 2)
+
+	// a1 0-10, b1 10-15, then a2 15-25; c takes b's slot at 15 but its job
+	// is queued behind a2, so c1 runs 25-45
+	if total != 45*time.Second {
+		t.Errorf("Wall clock = %v, want 45s", total)
+	}
+	if len(order) != 3 || order[0] != b || order[1] != a || order[2] != c {
+		t.Fatalf("Unexpected finish order: %v", order)
+	}
+	if b.Finish != 15*time.Second || a.Finish != 25*time.Second || c.Start != 25*time.Second {
+		t.Errorf("Unexpected schedule: b done %v, a done %v, c starts %v", b.Finish, a.Finish, c.Start)
+	}
+}
+
+func TestEstimateBatchReadsQueue(t *testing.T) {
+	cfg := DefaultPipelineConfig()
+	cfg.Generation.TempDir = t.TempDir()
+	charCfg := DefaultCharacterConfig("default")
+	charCfg.Deployment.OutputDir = t.TempDir()
+
+	history := &TimingHistory{Models: map[string]*StepTiming{}}
+	history.Record(WorkflowWork{Steps: 20, Width: 128, Height: 128, Frames: 6}, 10*time.Second)
+
+	client := &mockComfyUIClient{getQueueStatusFunc: func(ctx context.Context) (*comfyui.QueueStatus, error) {
+		return &comfyui.QueueStatus{Pending: 2, Running: 1}, nil
+	}}
+	estimate, err := EstimateBatch(context.Background(), cfg, []*CharacterConfig{charCfg}, history, client)
+	if err != nil {
+		t.Fatalf("EstimateBatch() error = %v", err)
+	}
+
+	if !estimate.QueueKnown || estimate.QueuedAhead != 3 {
+		t.Errorf("Expected 3 jobs ahead, got %d (known %t)", estimate.QueuedAhead, estimate.QueueKnown)
+	}
+	states := len(charCfg.States)
+	if !estimate.Known || estimate.WallClock != time.Duration(states)*10*time.Second {
+		t.Errorf("Expected %d states at 10s each, got %v (known %t)", states, estimate.WallClock, estimate.Known)
+	}
+	if work := estimate.Characters[0].States[0].Work; work.Steps != 20 || work.Width != 128 || work.Frames != 6 {
+		t.Errorf("Unexpected measured work %+v", work)
+	}
+}
@@ -0,0 +1,206 @@
+package pipeline
+
+// fix.go repairs GIFs that fail validation instead of leaving users to fix
+// them by hand: out-of-range dimensions are scaled, frame counts resampled,
+// frames re-quantized onto one shared palette, and the palette shrunk until
+// the file fits the size limit. The original is kept as a backup.
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Limits the fixes aim for, matching the validator's checks
+const (
+	minFixDimension = 64
+	maxFixDimension = 256
+	minFixFrames    = 4
+	maxFixFrames    = 8
+	minFixColors    = 16   // Fewest palette colors tried before scaling down to meet the size limit
+	fixShrinkFactor = 0.75 // Scale applied per step when fewer colors aren't enough
+)
+
+// FixBackupSuffix is appended to a GIF's path for the copy kept by FixGIF
+const FixBackupSuffix = ".bak"
+
+// FixResult describes what FixGIF changed.
+type FixResult struct {
+	Path       string   `json:"path"`
+	BackupPath string   `json:"backup_path"`
+	Actions    []string `json:"actions"` // Changes made, e.g. "resized 512x512 to 256x256"
+}
+
+// Fixable reports whether result has problems FixGIF can repair.
+func Fixable(result *ValidationResult) bool {
+	if result == nil {
+		return false
+	}
+	for _, e := range result.Errors {
+		switch e.Code {
+		case "INVALID_DIMENSIONS", "INVALID_FRAME_COUNT", "FILE_SIZE_EXCEEDED":
+			return true
+		}
+	}
+	for _, w := range result.Warnings {
+		if w.Code == "TOO_MANY_COLORS" {
+			return true
+		}
+	}
+	return false
+}
+
+// FixGIF rewrites the GIF at path so it passes the checks in config,
+// keeping the original at path+FixBackupSuffix. An existing backup is left
+// alone so repeated fixes never lose the original.
+func FixGIF(path string, config *ValidationConfig) (*FixResult, error) {
+	if config == nil {
+		return nil, fmt.Errorf("validation config required")
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read gif: %w", err)
+	}
+	src, err := gif.DecodeAll(bytes.NewReader(original))
+	if err != nil {
+		return nil, fmt.Errorf("decode gif: %w", err)
+	}
+	if len(src.Image) == 0 {
+		return nil, fmt.Errorf("gif has no frames")
+	}
+
+	result := &FixResult{Path: path, BackupPath: path + FixBackupSuffix}
+	frames := compositeFrames(src)
+
+	bounds := frames[0].Bounds()
+	width, height := fitDimensions(bounds.Dx(), bounds.Dy())
+	if width != bounds.Dx() || height != bounds.Dy() {
+		frames = scaleFrames(frames, width, height)
+		result.Actions = append(result.Actions, fmt.Sprintf("resized %dx%d to %dx%d", bounds.Dx(), bounds.Dy(), width, height))
+	}
+
+	if count := clampInt(len(frames), minFixFrames, maxFixFrames); count != len(frames) {
+		result.Actions = append(result.Actions, fmt.Sprintf("resampled %d frames to %d", len(frames), count))
+		frames = resampleFrames(frames, count, false)
+	}
+
+	transparency := (&assetValidator{}).checkGIFTransparency(src)
+	delay := frameDelay(0, src.Delay)
+	colors := 256
+	data, err := encodeFrames(frames, colors, transparency, delay, src.LoopCount)
+	if err != nil {
+		return nil, err
+	}
+	for config.MaxFileSize > 0 && len(data) > config.MaxFileSize {
+		if colors > minFixColors {
+			colors /= 2
+		} else if w, h := frames[0].Bounds().Dx(), frames[0].Bounds().Dy(); w > minFixDimension && h > minFixDimension {
+			width, height = max(minFixDimension, int(float64(w)*fixShrinkFactor)), max(minFixDimension, int(float64(h)*fixShrinkFactor))
+			frames = scaleFrames(frames, width, height)
+			result.Actions = append(result.Actions, fmt.Sprintf("scaled down to %dx%d to fit %d bytes", width, height, config.MaxFileSize))
+		} else {
+			break // Re-validation reports what is still too big
+		}
+		if data, err = encodeFrames(frames, colors, transparency, delay, src.LoopCount); err != nil {
+			return nil, err
+		}
+	}
+	result.Actions = append(result.Actions, fmt.Sprintf("re-quantized to one %d color palette (%d bytes, was %d)", colors, len(data), len(original)))
+
+	if err := backupFile(path, result.BackupPath, original); err != nil {
+		return nil, err
+	}
+	if err := replaceFile(path, data); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fitDimensions scales width and height into the allowed range, keeping the
+// aspect ratio where the range allows
+func fitDimensions(width, height int) (int, int) {
+	scale := 1.0
+	if longest := max(width, height); longest > maxFixDimension {
+		scale = float64(maxFixDimension) / float64(longest)
+	} else if shortest := min(width, height); shortest < minFixDimension {
+		scale = float64(minFixDimension) / float64(shortest)
+	}
+	return clampInt(int(float64(width)*scale+0.5), minFixDimension, maxFixDimension),
+		clampInt(int(float64(height)*scale+0.5), minFixDimension, maxFixDimension)
+}
+
+// scaleFrames resizes every frame to width by height
+func scaleFrames(frames []*image.RGBA, width, height int) []*image.RGBA {
+	scaled := make([]*image.RGBA, len(frames))
+	for i, frame := range frames {
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), frame, frame.Bounds(), draw.Src, nil)
+		scaled[i] = dst
+	}
+	return scaled
+}
+
+// encodeFrames encodes frames as a GIF sharing one palette of up to colors
+// entries, returning the encoded bytes
+func encodeFrames(frames []*image.RGBA, colors int, transparency bool, delay, loopCount int) ([]byte, error) {
+	pal := buildPalette(frames, colors, transparency)
+	out := &gif.GIF{LoopCount: loopCount}
+	for _, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), pal)
+		draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+		out.Image = append(out.Image, paletted)
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, gif.DisposalBackground)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, out); err != nil {
+		return nil, fmt.Errorf("encode gif: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// backupFile writes data to backupPath unless a backup already exists
+func backupFile(path, backupPath string, data []byte) error {
+	f, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if os.IsExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("backup %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("backup %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("backup %s: %w", path, err)
+	}
+	return nil
+}
+
+// replaceFile writes data next to path and renames it over path, so a
+// failed write never leaves a truncated file
+func replaceFile(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("write gif: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replace gif: %w", err)
+	}
+	return nil
+}
+
+// clampInt limits v to [lo, hi]
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
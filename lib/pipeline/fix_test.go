@@ -0,0 +1,143 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFixGIFDimensionsAndFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idle.gif")
+	createTestGIF(t, path, 10, 512, 384, true)
+	original, _ := os.ReadFile(path)
+
+	config := &ValidationConfig{MaxFileSize: 500000, MinFrameRate: 1, TransparencyRequired: true}
+	before, err := NewValidator().ValidateAsset(context.Background(), path, config)
+	if err != nil {
+		t.Fatalf("ValidateAsset failed: %v", err)
+	}
+	if before.Valid || !Fixable(before) {
+		t.Fatalf("Expected a fixable invalid GIF, got %+v", before.Errors)
+	}
+
+	result, err := FixGIF(path, config)
+	if err != nil {
+		t.Fatalf("FixGIF failed: %v", err)
+	}
+	if len(result.Actions) < 2 {
+		t.Errorf("Expected resize and resample actions, got %v", result.Actions)
+	}
+
+	after, err := NewValidator().ValidateAsset(context.Background(), path, config)
+	if err != nil {
+		t.Fatalf("ValidateAsset failed: %v", err)
+	}
+	if !after.Valid {
+		t.Fatalf("Expected the fixed GIF to validate, got %+v", after.Errors)
+	}
+	if after.Metrics.Dimensions != [2]int{256, 192} || after.Metrics.FrameCount != 8 {
+		t.Errorf("Expected 8 frames at 256x192, got %d at %v", after.Metrics.FrameCount, after.Metrics.Dimensions)
+	}
+	if !after.Metrics.HasTransparency {
+		t.Error("Transparency should survive the fix")
+	}
+
+	backup, err := os.ReadFile(result.BackupPath)
+	if err != nil || !bytes.Equal(backup, original) {
+		t.Fatalf("Expected the original in %s: %v", result.BackupPath, err)
+	}
+
+	// A second fix keeps the first backup
+	if _, err := FixGIF(path, config); err != nil {
+		t.Fatalf("Second FixGIF failed: %v", err)
+	}
+	if backup, _ := os.ReadFile(result.BackupPath); !bytes.Equal(backup, original) {
+		t.Error("A second fix must not replace the original backup")
+	}
+}
+
+func TestFixGIFFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "noisy.gif")
+	createNoisyGIF(t, path, 6, 128)
+
+	info, _ := os.Stat(path)
+	config := &ValidationConfig{MaxFileSize: int(info.Size() / 4), MinFrameRate: 1}
+
+	if _, err := FixGIF(path, config); err != nil {
+		t.Fatalf("FixGIF failed: %v", err)
+	}
+	result, err := NewValidator().ValidateAsset(context.Background(), path, config)
+	if err != nil {
+		t.Fatalf("ValidateAsset failed: %v", err)
+	}
+	if !result.ComplianceChecks["file_size"] {
+		t.Errorf("Expected the file to fit %d bytes, got %d", config.MaxFileSize, result.Metrics.FileSize)
+	}
+	if !result.ComplianceChecks["colors"] {
+		t.Error("Expected one shared palette after the fix")
+	}
+}
+
+func TestFitDimensions(t *testing.T) {
+	tests := []struct {
+		width, height int
+		wantW, wantH  int
+	}{
+		{128, 128, 128, 128},
+		{512, 512, 256, 256},
+		{512, 256, 256, 128},
+		{32, 48, 64, 96},
+		{1024, 64, 256, 64}, // Too wide to keep the aspect ratio
+	}
+	for _, tt := range tests {
+		w, h := fitDimensions(tt.width, tt.height)
+		if w != tt.wantW || h != tt.wantH {
+			t.Errorf("fitDimensions(%d, %d) = %dx%d, want %dx%d", tt.width, tt.height, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestFixable(t *testing.T) {
+	if Fixable(&ValidationResult{Errors: []ValidationError{{Code: "TRANSPARENCY_REQUIRED"}}}) {
+		t.Error("Missing transparency can't be fixed")
+	}
+	if !Fixable(&ValidationResult{Warnings: []ValidationWarning{{Code: "TOO_MANY_COLORS"}}}) {
+		t.Error("Too many colors should be fixable")
+	}
+}
+
+// createNoisyGIF writes frames of random colors, each with its own palette,
+// so the file is large and uses far more than 256 colors overall
+func createNoisyGIF(t *testing.T, path string, frameCount, size int) {
+	t.Helper()
+
+	rng := rand.New(rand.NewSource(1))
+	g := &gif.GIF{}
+	for i := 0; i < frameCount; i++ {
+		pal := make(color.Palette, 256)
+		for j := range pal {
+			pal[j] = color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+		}
+		img := image.NewPaletted(image.Rect(0, 0, size, size), pal)
+		for p := range img.Pix {
+			img.Pix[p] = uint8(rng.Intn(256))
+		}
+		g.Image = append(g.Image, img)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create test GIF: %v", err)
+	}
+	defer f.Close()
+	if err := gif.EncodeAll(f, g); err != nil {
+		t.Fatalf("Failed to encode test GIF: %v", err)
+	}
+}
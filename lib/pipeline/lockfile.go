@@ -0,0 +1,183 @@
+package pipeline
+
+// lockfile.go skips regenerating animation states whose inputs haven't
+// changed. Every state's output depends on the shared settings (style,
+// quality, templates, reference image) plus its own prompt and seed, so each
+// state is fingerprinted from all of them: editing a shared setting makes
+// every state stale while editing one state's prompt makes only that state
+// stale. Fingerprints of deployed states are kept in generation.lock.json
+// next to the assets.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LockfileName is the lockfile written next to deployed assets.
+const LockfileName = "generation.lock.json"
+
+// LockfileVersion is the current lockfile format.
+const LockfileVersion = 1
+
+// Lockfile records the input hash each deployed state was generated from.
+type Lockfile struct {
+	Version int               `json:"version"`
+	States  map[string]string `json:"states"` // State name -> input hash
+}
+
+// LockfilePath returns the lockfile location for an asset directory.
+func LockfilePath(outputDir string) string {
+	return filepath.Join(outputDir, LockfileName)
+}
+
+// LoadLockfile reads a lockfile. A missing file is an empty lockfile, so a
+// first run generates everything.
+func LoadLockfile(path string) (*Lockfile, error) {
+	lock := &Lockfile{Version: LockfileVersion, States: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("parse lockfile: %w", err)
+	}
+	if lock.Version != LockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d", lock.Version)
+	}
+	if lock.States == nil {
+		lock.States = make(map[string]string)
+	}
+	return lock, nil
+}
+
+// WriteLockfile saves a lockfile as indented JSON.
+func WriteLockfile(path string, lock *Lockfile) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	return nil
+}
+
+// StateInputHash fingerprints everything that shapes a state's output: the
+// rendered prompts, built-in template version, model, quality and size
+// settings, GIF settings, reference image and the configured seed. A random
+// seed is hashed as unset, so unpinned states aren't regenerated just because
+// a new seed would be drawn.
+func StateInputHash(cfg *PipelineConfig, charCfg *CharacterConfig, state string) (string, error) {
+	if charCfg == nil || charCfg.Character == nil || charCfg.Character.OutputConfig == nil {
+		return "", fmt.Errorf("character config with output settings required")
+	}
+
+	prompts, err := BuildStatePrompt(cfg, charCfg, state)
+	if err != nil {
+		return "", fmt.Errorf("build prompt for state %s: %w", state, err)
+	}
+	seed, pinned := configuredSeed(cfg, charCfg, state)
+
+	inputs := struct {
+		Positive  string
+		Negative  string
+		Template  string
+		Model     string
+		Style     string
+		Width     int
+		Height    int
+		Quality   QualityConfig
+		Seed      int64
+		Pinned    bool
+		GIF       *ExtendedGIFConfig
+		Outputs   []OutputProfile        `json:",omitempty"`
+		Reference map[string]interface{} `json:",omitempty"`
+	}{
+		Positive: prompts.Positive,
+		Negative: prompts.Negative,
+		Template: PromptTemplateVersion, // Custom templates show up in the rendered prompts
+		Model:    charCfg.Character.Traits["model"],
+		Style:    charCfg.Character.Style,
+		Width:    charCfg.Character.OutputConfig.Width,
+		Height:   charCfg.Character.OutputConfig.Height,
+		Seed:     seed,
+		Pinned:   pinned,
+		GIF:      charCfg.GIFConfig,
+		Outputs:  charCfg.Outputs,
+	}
+	if cfg != nil {
+		inputs.Quality = cfg.Workflow.Quality
+		inputs.Quality.Seed = 0 // Covered by Seed and Pinned
+	}
+	if charCfg.Reference.Enabled() {
+		// The node names the image by content, so editing it changes the hash
+		if inputs.Reference, err = referenceNode(charCfg.Reference, state); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return "", fmt.Errorf("encode inputs for state %s: %w", state, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// StaleStates splits a character's states into those that need generating
+// and those whose deployed asset still matches its inputs. Force marks every
+// state stale. Hashes for all states are returned for recording after deployment.
+func StaleStates(cfg *PipelineConfig, charCfg *CharacterConfig, outputDir string, force bool) (stale, upToDate []string, hashes map[string]string, err error) {
+	lock, err := LoadLockfile(LockfilePath(outputDir))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	hashes = make(map[string]string, len(charCfg.States))
+	for _, state := range charCfg.States {
+		hash, err := StateInputHash(cfg, charCfg, state)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		hashes[state] = hash
+
+		if !force && lock.States[state] == hash && fileExists(filepath.Join(outputDir, state+".gif")) {
+			upToDate = append(upToDate, state)
+		} else {
+			stale = append(stale, state)
+		}
+	}
+	return stale, upToDate, hashes, nil
+}
+
+// recordDeployedStates adds freshly deployed states to the lockfile in outputDir.
+func recordDeployedStates(outputDir string, hashes map[string]string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	path := LockfilePath(outputDir)
+	lock, err := LoadLockfile(path)
+	if err != nil {
+		return err
+	}
+	for state, hash := range hashes {
+		lock.States[state] = hash
+	}
+	return WriteLockfile(path, lock)
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
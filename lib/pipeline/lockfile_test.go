@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+func TestStateInputHash(t *testing.T) {
+	config := DefaultPipelineConfig()
+	charConfig := DefaultCharacterConfig("test")
+
+	idle, err := StateInputHash(config, charConfig, "idle")
+	if err != nil {
+		t.Fatalf("StateInputHash failed: %v", err)
+	}
+	again, _ := StateInputHash(config, charConfig, "idle")
+	if idle != again {
+		t.Error("Unchanged inputs should hash the same, even with a random seed")
+	}
+	talking, _ := StateInputHash(config, charConfig, "talking")
+
+	// A per-state prompt change only affects that state
+	charConfig.Prompts = &PromptConfig{States: map[string]StatePromptOverride{"idle": {Modifier: "yawning"}}}
+	changedIdle, _ := StateInputHash(config, charConfig, "idle")
+	if changedIdle == idle {
+		t.Error("Editing the idle prompt should change its hash")
+	}
+
+	// A shared setting affects every state
+	config.Workflow.Quality.Steps++
+	if changed, _ := StateInputHash(config, charConfig, "talking"); changed == talking {
+		t.Error("Editing quality settings should change every state's hash")
+	}
+}
+
+func TestLoadLockfile(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := LoadLockfile(LockfilePath(dir))
+	if err != nil || len(lock.States) != 0 {
+		t.Fatalf("A missing lockfile should load empty, got %+v, %v", lock, err)
+	}
+
+	lock.States["idle"] = "abc"
+	if err := WriteLockfile(LockfilePath(dir), lock); err != nil {
+		t.Fatalf("WriteLockfile failed: %v", err)
+	}
+	loaded, err := LoadLockfile(LockfilePath(dir))
+	if err != nil || loaded.States["idle"] != "abc" {
+		t.Errorf("Expected the saved state, got %+v, %v", loaded, err)
+	}
+
+	os.WriteFile(LockfilePath(dir), []byte(`{"version": 99, "states": {}}`), 0o644)
+	if _, err := LoadLockfile(LockfilePath(dir)); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
+
+func TestProcessCharacterRegeneratesOnlyChangedStates(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+	pc.config.Deployment.ValidateBeforeDeploy = false
+
+	charConfig := DefaultCharacterConfig("test")
+	charConfig.States = []string{"idle", "talking"}
+	charConfig.Deployment.OutputDir = t.TempDir()
+
+	generate := func() *ProcessResult {
+		t.Helper()
+		submitted = nil
+		result, err := pc.ProcessCharacter(context.Background(), charConfig)
+		if err != nil || !result.Success {
+			t.Fatalf("ProcessCharacter failed: %v %+v", err, result)
+		}
+		if err := pc.DeployAssets(context.Background(), result); err != nil {
+			t.Fatalf("DeployAssets failed: %v", err)
+		}
+		return result
+	}
+
+	generate()
+	if len(submitted) != 2 {
+		t.Fatalf("First run should generate every state, submitted %d", len(submitted))
+	}
+	if _, err := os.Stat(filepath.Join(charConfig.Deployment.OutputDir, LockfileName)); err != nil {
+		t.Fatalf("Expected a lockfile next to the assets: %v", err)
+	}
+
+	result := generate()
+	if len(submitted) != 0 || !reflect.DeepEqual(result.SkippedStates, []string{"idle", "talking"}) {
+		t.Errorf("Unchanged states should be skipped, submitted %d, skipped %v", len(submitted), result.SkippedStates)
+	}
+
+	charConfig.Prompts = &PromptConfig{States: map[string]StatePromptOverride{"talking": {Modifier: "waving"}}}
+	result = generate()
+	if len(submitted) != 1 || submitted[0].Meta["state"] != "talking" {
+		t.Errorf("Only the edited state should regenerate, submitted %d", len(submitted))
+	}
+	if !reflect.DeepEqual(result.SkippedStates, []string{"idle"}) {
+		t.Errorf("Expected idle skipped, got %v", result.SkippedStates)
+	}
+
+	// A deleted asset is regenerated even though its hash matches
+	os.Remove(filepath.Join(charConfig.Deployment.OutputDir, "idle.gif"))
+	generate()
+	if len(submitted) != 1 || submitted[0].Meta["state"] != "idle" {
+		t.Errorf("A missing asset should regenerate, submitted %d", len(submitted))
+	}
+
+	charConfig.Force = true
+	generate()
+	if len(submitted) != 2 {
+		t.Errorf("Force should regenerate every state, submitted %d", len(submitted))
+	}
+}
@@ -0,0 +1,186 @@
+package pipeline
+
+// manifest.go records how each asset was generated so it can be regenerated
+// exactly: the resolved seed, rendered prompts, template version and the full
+// ComfyUI graph together with its hash. Manifests are written next to the GIF
+// as <state>.manifest.json.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// ManifestVersion is the current generation manifest format.
+const ManifestVersion = 1
+
+// SeedConfig pins generation seeds for a character. Values <= 0 mean unset.
+type SeedConfig struct {
+	Base   int64            `json:"base,omitempty"`   // Seed for every state, offset per state so poses differ
+	States map[string]int64 `json:"states,omitempty"` // Exact seed for a state, overrides Base
+}
+
+// GenerationManifest describes everything needed to regenerate one asset.
+type GenerationManifest struct {
+	Version         int                `json:"version"`
+	Character       string             `json:"character"`
+	State           string             `json:"state"`
+	Model           string             `json:"model,omitempty"`
+	Seed            int64              `json:"seed"`
+	Prompt          string             `json:"prompt"`
+	NegativePrompt  string             `json:"negative_prompt"`
+	TemplateVersion string             `json:"template_version"`
+	GraphHash       string             `json:"graph_hash"` // sha256 of the workflow nodes
+	Workflow        *comfyui.Workflow  `json:"workflow"`
+	GIFConfig       *ExtendedGIFConfig `json:"gif_config"`
+	GeneratedAt     time.Time          `json:"generated_at"`
+}
+
+// ResolveSeed picks the seed for a state: the per-state seed, then the
+// character base seed, then the pipeline quality seed. When none is set a
+// random seed is chosen, which the manifest records so it can be reused.
+func ResolveSeed(cfg *PipelineConfig, charCfg *CharacterConfig, state string) int64 {
+	if seed, ok := configuredSeed(cfg, charCfg, state); ok {
+		return seed
+	}
+	return rand.Int63n(1 << 32)
+}
+
+// configuredSeed returns the seed pinned for a state, if any
+func configuredSeed(cfg *PipelineConfig, charCfg *CharacterConfig, state string) (int64, bool) {
+	if charCfg != nil && charCfg.Seeds != nil {
+		if seed := charCfg.Seeds.States[state]; seed > 0 {
+			return seed, true
+		}
+		if charCfg.Seeds.Base > 0 {
+			return charCfg.Seeds.Base + stateSeedOffset(state), true
+		}
+	}
+	if cfg != nil && cfg.Workflow.Quality.Seed >= 0 {
+		return cfg.Workflow.Quality.Seed, true
+	}
+	return 0, false
+}
+
+// stateSeedOffset derives a stable per-state offset from the state name
+func stateSeedOffset(state string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(state))
+	return int64(h.Sum32() % 1000000)
+}
+
+// templateVersion identifies the prompt templates used for a character.
+// Custom templates are fingerprinted since they live in the character card.
+func templateVersion(charCfg *CharacterConfig) string {
+	version := "builtin-" + PromptTemplateVersion
+	if charCfg == nil || charCfg.Prompts == nil {
+		return version
+	}
+
+	data, err := json.Marshal(charCfg.Prompts)
+	if err != nil || string(data) == "{}" {
+		return version
+	}
+	sum := sha256.Sum256(data)
+	return version + "+custom-" + hex.EncodeToString(sum[:4])
+}
+
+// HashWorkflowGraph returns a stable sha256 of a workflow's node graph.
+// encoding/json sorts map keys, so equal graphs always hash the same.
+func HashWorkflowGraph(workflow *comfyui.Workflow) (string, error) {
+	if workflow == nil {
+		return "", fmt.Errorf("workflow required")
+	}
+	data, err := json.Marshal(workflow.Nodes)
+	if err != nil {
+		return "", fmt.Errorf("encode workflow graph: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ManifestPath returns the manifest location for a generated asset.
+func ManifestPath(assetPath string) string {
+	return strings.TrimSuffix(assetPath, ".gif") + ".manifest.json"
+}
+
+// WriteManifest saves a manifest as indented JSON.
+func WriteManifest(path string, manifest *GenerationManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadManifest reads a manifest and checks that its workflow still matches
+// the recorded graph hash. Numbers are kept exact so large seeds survive.
+func LoadManifest(path string) (*GenerationManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var manifest GenerationManifest
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+	if manifest.Workflow == nil || manifest.GIFConfig == nil {
+		return nil, fmt.Errorf("manifest is missing the workflow or gif config")
+	}
+
+	hash, err := HashWorkflowGraph(manifest.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	if hash != manifest.GraphHash {
+		return nil, fmt.Errorf("workflow graph hash mismatch: manifest was edited or is corrupt")
+	}
+
+	return &manifest, nil
+}
+
+// newGenerationManifest builds the manifest for a freshly created workflow.
+func newGenerationManifest(charCfg *CharacterConfig, state string, workflow *comfyui.Workflow, prompt *StatePrompt, seed int64) (*GenerationManifest, error) {
+	hash, err := HashWorkflowGraph(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &GenerationManifest{
+		Version:         ManifestVersion,
+		State:           state,
+		Seed:            seed,
+		Prompt:          prompt.Positive,
+		NegativePrompt:  prompt.Negative,
+		TemplateVersion: templateVersion(charCfg),
+		GraphHash:       hash,
+		Workflow:        workflow,
+		GIFConfig:       charCfg.GIFConfig,
+		GeneratedAt:     time.Now(),
+	}
+	if charCfg.Character != nil {
+		manifest.Character = charCfg.Character.Archetype
+		manifest.Model = charCfg.Character.Traits["model"]
+	}
+	return manifest, nil
+}
@@ -0,0 +1,160 @@
+package pipeline
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/assets"
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// stubGIFProcessor writes a tiny valid GIF instead of assembling real frames
+type stubGIFProcessor struct{}
+
+func (stubGIFProcessor) Process(ctx context.Context, frames []string, outPath string, cfg assets.GIFConfig) error {
+	frame := image.NewPaletted(image.Rect(0, 0, 4, 4), []color.Color{color.Transparent, color.Black})
+	f, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gif.EncodeAll(f, &gif.GIF{Image: []*image.Paletted{frame, frame}, Delay: []int{10, 10}})
+}
+
+// newRecordingController returns a controller whose mock client records submitted workflows
+func newRecordingController(t *testing.T, submitted *[]*comfyui.Workflow) *pipelineController {
+	t.Helper()
+
+	config := DefaultPipelineConfig()
+	config.Generation.TempDir = t.TempDir()
+	config.Deployment.ArtifactsDir = t.TempDir()
+	client := &mockComfyUIClient{
+		submitWorkflowFunc: func(ctx context.Context, wf *comfyui.Workflow) (*comfyui.Job, error) {
+			*submitted = append(*submitted, wf)
+			return &comfyui.Job{ID: "test-job-id", Status: "queued"}, nil
+		},
+	}
+	controller, err := NewController(config, client)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	pc := controller.(*pipelineController)
+	pc.assetProcessor = stubGIFProcessor{}
+	return pc
+}
+
+func TestResolveSeed(t *testing.T) {
+	config := DefaultPipelineConfig()
+	charConfig := DefaultCharacterConfig("test")
+	charConfig.Seeds = &SeedConfig{Base: 1000, States: map[string]int64{"happy": 42}}
+
+	if seed := ResolveSeed(config, charConfig, "happy"); seed != 42 {
+		t.Errorf("Expected per-state seed 42, got %d", seed)
+	}
+
+	idle := ResolveSeed(config, charConfig, "idle")
+	if idle != ResolveSeed(config, charConfig, "idle") {
+		t.Error("Base seed should resolve the same way every time")
+	}
+	if idle == ResolveSeed(config, charConfig, "sad") {
+		t.Error("States sharing a base seed should get different seeds")
+	}
+
+	charConfig.Seeds = nil
+	config.Workflow.Quality.Seed = 7
+	if seed := ResolveSeed(config, charConfig, "idle"); seed != 7 {
+		t.Errorf("Expected pipeline quality seed 7, got %d", seed)
+	}
+
+	config.Workflow.Quality.Seed = -1
+	if seed := ResolveSeed(config, charConfig, "idle"); seed < 0 {
+		t.Errorf("Random seed should be non-negative, got %d", seed)
+	}
+}
+
+func TestGenerateAssetWritesManifest(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+
+	charConfig := DefaultCharacterConfig("test")
+	charConfig.Character.Traits["model"] = "flux1d"
+	charConfig.Seeds = &SeedConfig{States: map[string]int64{"idle": 123456789012}}
+
+	asset, err := pc.generateAssetForState(context.Background(), charConfig, "idle", t.TempDir())
+	if err != nil {
+		t.Fatalf("generateAssetForState failed: %v", err)
+	}
+	if asset.ManifestPath != ManifestPath(asset.OutputPath) {
+		t.Errorf("Manifest should sit next to the GIF, got %s", asset.ManifestPath)
+	}
+
+	manifest, err := LoadManifest(asset.ManifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	if manifest.Seed != 123456789012 || manifest.Model != "flux1d" || manifest.State != "idle" {
+		t.Errorf("Unexpected manifest fields: %+v", manifest)
+	}
+	if manifest.Prompt == "" || manifest.TemplateVersion == "" {
+		t.Error("Manifest should record the prompt and template version")
+	}
+
+	hash, _ := HashWorkflowGraph(submitted[0])
+	if hash != manifest.GraphHash {
+		t.Error("Manifest graph hash should match the submitted workflow")
+	}
+}
+
+func TestReproduceAssetSubmitsSameGraph(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+
+	charConfig := DefaultCharacterConfig("test")
+	asset, err := pc.generateAssetForState(context.Background(), charConfig, "happy", t.TempDir())
+	if err != nil {
+		t.Fatalf("generateAssetForState failed: %v", err)
+	}
+	manifest, err := LoadManifest(asset.ManifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "out", "happy.gif")
+	reproduced, err := pc.ReproduceAsset(context.Background(), manifest, outputPath)
+	if err != nil {
+		t.Fatalf("ReproduceAsset failed: %v", err)
+	}
+	if _, err := os.Stat(reproduced.OutputPath); err != nil {
+		t.Errorf("Reproduced GIF should exist: %v", err)
+	}
+
+	original, _ := HashWorkflowGraph(submitted[0])
+	again, _ := HashWorkflowGraph(submitted[1])
+	if original != again {
+		t.Error("Reproduction should submit the identical workflow graph")
+	}
+}
+
+func TestLoadManifestRejectsEditedGraph(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+
+	workflow, manifest, err := pc.buildStateWorkflow(DefaultCharacterConfig("test"), "idle")
+	if err != nil {
+		t.Fatalf("buildStateWorkflow failed: %v", err)
+	}
+	workflow.Nodes["prompt"] = map[string]interface{}{"positive": "something else"}
+
+	path := filepath.Join(t.TempDir(), "idle.manifest.json")
+	if err := WriteManifest(path, manifest); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	if _, err := LoadManifest(path); err == nil {
+		t.Error("Expected a hash mismatch for an edited workflow")
+	}
+}
@@ -0,0 +1,178 @@
+package pipeline
+
+// models.go checks that the checkpoints and LoRAs a batch needs are
+// installed on the ComfyUI server before any job is queued, and fetches
+// missing LoRAs from the URLs in workflow.models when asked to.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// modelTypeFolders maps workflow.models types to ComfyUI model folders.
+var modelTypeFolders = map[string]string{
+	"checkpoint": comfyui.ModelFolderCheckpoints,
+	"lora":       comfyui.ModelFolderLoras,
+	"vae":        comfyui.ModelFolderVAE,
+	"controlnet": comfyui.ModelFolderControlNet,
+}
+
+// modelFileExtensions mark a model reference as a file name rather than a
+// preset like "flux1d".
+var modelFileExtensions = []string{".safetensors", ".ckpt", ".pt", ".pth", ".bin", ".gguf"}
+
+// ModelRef is a model file generation needs on the server.
+type ModelRef struct {
+	Folder string `json:"folder"` // comfyui.ModelFolderLoras etc.
+	Name   string `json:"name"`   // Key in workflow.models or a file name
+}
+
+// String formats the reference as folder/name.
+func (m ModelRef) String() string {
+	return m.Folder + "/" + m.Name
+}
+
+// ResolveModel turns a model key into the file the server must have. Keys in
+// workflow.models use the configured name and type; anything else counts as
+// a file name when it has a model file extension. ok is false for presets
+// without a configured file, which can't be checked.
+func (pc *PipelineConfig) ResolveModel(ref ModelRef) (ModelRef, bool) {
+	if model, exists := pc.Workflow.Models[ref.Name]; exists && model.Name != "" {
+		if folder, known := modelTypeFolders[model.Type]; known {
+			ref.Folder = folder
+		}
+		ref.Name = model.Name
+		return ref, true
+	}
+	ext := strings.ToLower(path.Ext(ref.Name))
+	for _, modelExt := range modelFileExtensions {
+		if ext == modelExt {
+			return ref, true
+		}
+	}
+	return ref, false
+}
+
+// ModelURL returns where a missing model can be downloaded from, if the
+// pipeline config says.
+func (pc *PipelineConfig) ModelURL(ref ModelRef) string {
+	for _, model := range pc.Workflow.Models {
+		if model.Name == ref.Name && model.URL != "" && modelTypeFolders[model.Type] == ref.Folder {
+			return model.URL
+		}
+	}
+	return ""
+}
+
+// RequiredModels returns the model files the characters need, resolved and
+// without duplicates: each character's checkpoint plus its extra models.
+func RequiredModels(pc *PipelineConfig, configs []*CharacterConfig) []ModelRef {
+	seen := make(map[ModelRef]bool)
+	var refs []ModelRef
+	add := func(ref ModelRef) {
+		if resolved, ok := pc.ResolveModel(ref); ok && !seen[resolved] {
+			seen[resolved] = true
+			refs = append(refs, resolved)
+		}
+	}
+
+	for _, config := range configs {
+		if config.Character != nil {
+			if model := config.Character.Traits["model"]; model != "" {
+				add(ModelRef{Folder: comfyui.ModelFolderCheckpoints, Name: model})
+			}
+		}
+		for _, ref := range config.Models {
+			add(ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	return refs
+}
+
+// MissingModels returns the required models the server doesn't list. Files
+// in subfolders match by their base name too, since ComfyUI lists them with
+// their relative path.
+func MissingModels(ctx context.Context, lister comfyui.ModelLister, required []ModelRef) ([]ModelRef, error) {
+	installed := make(map[string]map[string]bool)
+	var missing []ModelRef
+	for _, ref := range required {
+		names, listed := installed[ref.Folder]
+		if !listed {
+			files, err := lister.ListModels(ctx, ref.Folder)
+			if err != nil {
+				return nil, fmt.Errorf("list %s: %w", ref.Folder, err)
+			}
+			names = make(map[string]bool, len(files)*2)
+			for _, file := range files {
+				names[file] = true
+				names[path.Base(filepath.ToSlash(file))] = true
+			}
+			installed[ref.Folder] = names
+		}
+		if !names[ref.Name] {
+			missing = append(missing, ref)
+		}
+	}
+	return missing, nil
+}
+
+// DownloadModel fetches a model from its configured URL into the matching
+// folder under modelsDir, e.g. ComfyUI/models/loras, and returns the path.
+// The file only appears once fully written, so ComfyUI never sees half a model.
+func DownloadModel(ctx context.Context, httpc *http.Client, pc *PipelineConfig, ref ModelRef, modelsDir string) (string, error) {
+	url := pc.ModelURL(ref)
+	if url == "" {
+		return "", fmt.Errorf("no download URL configured for %s", ref)
+	}
+	if modelsDir == "" {
+		return "", fmt.Errorf("models directory required to download %s", ref)
+	}
+	if strings.Contains(ref.Name, "..") {
+		return "", fmt.Errorf("invalid model name %q", ref.Name)
+	}
+
+	target := filepath.Join(modelsDir, ref.Folder, filepath.FromSlash(ref.Name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("create model folder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	resp, err := httpc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: unexpected status %d", ref, resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".download-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("download %s: %w", ref, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("write %s: %w", ref, err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("save %s: %w", ref, err)
+	}
+	return target, nil
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// fakeModelLister serves fixed folder listings
+type fakeModelLister map[string][]string
+
+func (f fakeModelLister) ListModels(ctx context.Context, folder string) ([]string, error) {
+	return f[folder], nil
+}
+
+func modelTestConfig() *PipelineConfig {
+	pc := DefaultPipelineConfig()
+	pc.Workflow.Models = map[string]ModelConfig{
+		"flux1d":      {Name: "flux1-dev.safetensors", Type: "checkpoint"},
+		"chibi_style": {Name: "chibi_style.safetensors", Type: "lora", URL: "http://example.invalid/chibi"},
+	}
+	return pc
+}
+
+func TestRequiredModels(t *testing.T) {
+	pc := modelTestConfig()
+
+	first := DefaultCharacterConfig("a")
+	first.Character.Traits = map[string]string{"model": "flux1d"}
+	first.Models = []ModelRef{{Folder: comfyui.ModelFolderLoras, Name: "chibi_style"}}
+	second := DefaultCharacterConfig("b")
+	second.Character.Traits = map[string]string{"model": "sdxl"} // Preset without a file: not checked
+	second.Models = []ModelRef{
+		{Folder: comfyui.ModelFolderLoras, Name: "chibi_style"},
+		{Folder: comfyui.ModelFolderVAE, Name: "kl-f8.safetensors"},
+	}
+
+	refs := RequiredModels(pc, []*CharacterConfig{first, second})
+	want := []ModelRef{
+		{Folder: comfyui.ModelFolderCheckpoints, Name: "flux1-dev.safetensors"},
+		{Folder: comfyui.ModelFolderLoras, Name: "chibi_style.safetensors"},
+		{Folder: comfyui.ModelFolderVAE, Name: "kl-f8.safetensors"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, refs)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("ref %d: expected %v, got %v", i, want[i], refs[i])
+		}
+	}
+}
+
+func TestMissingModels(t *testing.T) {
+	lister := fakeModelLister{
+		comfyui.ModelFolderCheckpoints: {"flux/flux1-dev.safetensors"},
+		comfyui.ModelFolderLoras:       {"other.safetensors"},
+	}
+	required := []ModelRef{
+		{Folder: comfyui.ModelFolderCheckpoints, Name: "flux1-dev.safetensors"},
+		{Folder: comfyui.ModelFolderLoras, Name: "chibi_style.safetensors"},
+	}
+
+	missing, err := MissingModels(context.Background(), lister, required)
+	if err != nil {
+		t.Fatalf("missing models: %v", err)
+	}
+	if len(missing) != 1 || missing[0].Name != "chibi_style.safetensors" {
+		t.Errorf("expected only the LoRA to be missing, got %v", missing)
+	}
+}
+
+func TestDownloadModel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("weights"))
+	}))
+	defer srv.Close()
+
+	pc := modelTestConfig()
+	lora := pc.Workflow.Models["chibi_style"]
+	lora.URL = srv.URL + "/chibi"
+	pc.Workflow.Models["chibi_style"] = lora
+
+	dir := t.TempDir()
+	ref := ModelRef{Folder: comfyui.ModelFolderLoras, Name: "chibi_style.safetensors"}
+	target, err := DownloadModel(context.Background(), srv.Client(), pc, ref, dir)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if target != filepath.Join(dir, "loras", "chibi_style.safetensors") {
+		t.Errorf("unexpected target %s", target)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "weights" {
+		t.Errorf("unexpected contents %q", data)
+	}
+
+	unknown := ModelRef{Folder: comfyui.ModelFolderLoras, Name: "unknown.safetensors"}
+	if _, err := DownloadModel(context.Background(), srv.Client(), pc, unknown, dir); err == nil {
+		t.Error("expected an error without a configured URL")
+	}
+}
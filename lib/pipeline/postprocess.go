@@ -0,0 +1,261 @@
+package pipeline
+
+// postprocess.go normalizes generated GIFs so every animation state ends up
+// with the same frame count and frame rate. Missing frames can be filled with
+// simple cross-fades, and the palette is rebuilt from the actual frame colors.
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"math"
+	"os"
+	"sort"
+)
+
+// needsPostProcessing reports whether any post-processing option is enabled.
+func (g *ExtendedGIFConfig) needsPostProcessing() bool {
+	return g != nil && (g.NormalizeFrames || g.Interpolate || g.OptimizePalette)
+}
+
+// PostProcessGIF rewrites the GIF at path according to the post-processing
+// options in cfg. Frames are resampled to cfg.FrameCount at cfg.FrameRate;
+// with Interpolate the in-between frames are cross-faded instead of repeated.
+func PostProcessGIF(path string, cfg *ExtendedGIFConfig) error {
+	if !cfg.needsPostProcessing() {
+		return nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open gif: %w", err)
+	}
+	src, err := gif.DecodeAll(in)
+	in.Close()
+	if err != nil {
+		return fmt.Errorf("decode gif: %w", err)
+	}
+	if len(src.Image) == 0 {
+		return fmt.Errorf("gif has no frames")
+	}
+
+	frames := compositeFrames(src)
+	delay := frameDelay(cfg.FrameRate, src.Delay)
+	if cfg.NormalizeFrames || cfg.Interpolate {
+		count := cfg.FrameCount
+		if count <= 0 {
+			count = len(frames)
+		}
+		frames = resampleFrames(frames, count, cfg.Interpolate)
+	}
+
+	colors := cfg.Colors
+	if colors <= 0 || colors > 256 {
+		colors = 256
+	}
+
+	var pal color.Palette
+	if cfg.OptimizePalette {
+		pal = buildPalette(frames, colors, cfg.Transparency)
+	} else {
+		pal = src.Image[0].Palette
+	}
+
+	out := &gif.GIF{LoopCount: src.LoopCount}
+	for _, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), pal)
+		if cfg.Optimization == "quality" {
+			draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+		} else {
+			draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+		}
+		out.Image = append(out.Image, paletted)
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, gif.DisposalBackground)
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("create gif: %w", err)
+	}
+	if err := gif.EncodeAll(f, out); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("encode gif: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close gif: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace gif: %w", err)
+	}
+	return nil
+}
+
+// compositeFrames renders each GIF frame onto the full canvas so frames that
+// only cover part of the image can be blended and resampled independently.
+func compositeFrames(g *gif.GIF) []*image.RGBA {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		bounds = g.Image[0].Bounds()
+	}
+
+	canvas := image.NewRGBA(bounds)
+	frames := make([]*image.RGBA, 0, len(g.Image))
+	for i, img := range g.Image {
+		var previous *image.RGBA
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+		frames = append(frames, cloneRGBA(canvas))
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames
+}
+
+// frameDelay converts a frame rate into a GIF delay in 1/100s.
+// Falls back to the first source delay when no rate is configured.
+func frameDelay(frameRate int, sourceDelays []int) int {
+	if frameRate > 0 {
+		delay := int(math.Round(100 / float64(frameRate)))
+		if delay < 2 {
+			// Most viewers clamp delays below 2 to 10, which slows the animation down
+			delay = 2
+		}
+		return delay
+	}
+	if len(sourceDelays) > 0 && sourceDelays[0] > 0 {
+		return sourceDelays[0]
+	}
+	return 10
+}
+
+// resampleFrames stretches or shrinks a looping animation to count frames.
+// With interpolate, frames that fall between two source frames are
+// cross-faded; otherwise the nearest source frame is reused.
+func resampleFrames(frames []*image.RGBA, count int, interpolate bool) []*image.RGBA {
+	if count <= 0 || len(frames) == 0 {
+		return frames
+	}
+	if count == len(frames) {
+		return frames
+	}
+
+	step := float64(len(frames)) / float64(count)
+	result := make([]*image.RGBA, 0, count)
+	for i := 0; i < count; i++ {
+		pos := float64(i) * step
+		index := int(pos)
+		frac := pos - float64(index)
+
+		if interpolate && frac > 0.01 {
+			next := (index + 1) % len(frames)
+			result = append(result, crossFade(frames[index], frames[next], frac))
+			continue
+		}
+
+		nearest := int(math.Round(pos)) % len(frames)
+		result = append(result, frames[nearest])
+	}
+	return result
+}
+
+// crossFade blends b over a with weight t (0 = a, 1 = b).
+func crossFade(a, b *image.RGBA, t float64) *image.RGBA {
+	out := image.NewRGBA(a.Bounds())
+	for i := range out.Pix {
+		var bv uint8
+		if i < len(b.Pix) {
+			bv = b.Pix[i]
+		}
+		out.Pix[i] = uint8(math.Round(float64(a.Pix[i])*(1-t) + float64(bv)*t))
+	}
+	return out
+}
+
+// buildPalette picks the most common colors across all frames. Colors are
+// bucketed to 5 bits per channel first so near-identical shades merge.
+// With transparency, index 0 is reserved for the transparent color.
+func buildPalette(frames []*image.RGBA, size int, transparency bool) color.Palette {
+	type bucket struct {
+		key     uint32
+		count   int
+		r, g, b int
+	}
+	buckets := make(map[uint32]*bucket)
+
+	for _, frame := range frames {
+		for i := 0; i+3 < len(frame.Pix); i += 4 {
+			r, g, b, a := frame.Pix[i], frame.Pix[i+1], frame.Pix[i+2], frame.Pix[i+3]
+			if a < 128 {
+				continue
+			}
+			key := uint32(r>>3)<<10 | uint32(g>>3)<<5 | uint32(b>>3)
+			bk, ok := buckets[key]
+			if !ok {
+				bk = &bucket{key: key}
+				buckets[key] = bk
+			}
+			bk.count++
+			bk.r += int(r)
+			bk.g += int(g)
+			bk.b += int(b)
+		}
+	}
+
+	ranked := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		ranked = append(ranked, bk)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].count != ranked[j].count {
+			return ranked[i].count > ranked[j].count
+		}
+		return ranked[i].key < ranked[j].key
+	})
+
+	pal := color.Palette{}
+	if transparency {
+		pal = append(pal, color.RGBA{})
+	}
+	for _, bk := range ranked {
+		if len(pal) >= size {
+			break
+		}
+		pal = append(pal, color.RGBA{
+			R: uint8(bk.r / bk.count),
+			G: uint8(bk.g / bk.count),
+			B: uint8(bk.b / bk.count),
+			A: 255,
+		})
+	}
+	if len(pal) == 0 || (transparency && len(pal) == 1) {
+		// Fully transparent animation still needs an opaque entry for the encoder
+		pal = append(pal, color.RGBA{A: 255})
+	}
+	return pal
+}
+
+// cloneRGBA copies an RGBA image.
+func cloneRGBA(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	copy(dst.Pix, src.Pix)
+	return dst
+}
@@ -0,0 +1,147 @@
+package pipeline
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestGIF(t *testing.T, path string, shades []uint8, delay int) {
+	t.Helper()
+
+	pal := color.Palette{color.RGBA{}}
+	for _, shade := range shades {
+		pal = append(pal, color.RGBA{R: shade, A: 255})
+	}
+
+	g := &gif.GIF{}
+	for i := range shades {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), pal)
+		for p := range frame.Pix {
+			frame.Pix[p] = uint8(i + 1)
+		}
+		frame.Pix[0] = 0 // keep a transparent corner
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, delay)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := gif.EncodeAll(f, g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestGIF(t *testing.T, path string) *gif.GIF {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestPostProcessGIFInterpolates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idle.gif")
+	writeTestGIF(t, path, []uint8{0, 200}, 50)
+
+	cfg := &ExtendedGIFConfig{
+		GIFConfig:       GIFConfig{FrameCount: 4, Transparency: true},
+		FrameRate:       10,
+		Colors:          16,
+		NormalizeFrames: true,
+		Interpolate:     true,
+		OptimizePalette: true,
+	}
+	if err := PostProcessGIF(path, cfg); err != nil {
+		t.Fatalf("PostProcessGIF failed: %v", err)
+	}
+
+	g := readTestGIF(t, path)
+	if len(g.Image) != 4 {
+		t.Fatalf("Expected 4 frames, got %d", len(g.Image))
+	}
+	for i, delay := range g.Delay {
+		if delay != 10 {
+			t.Errorf("Frame %d delay = %d, want 10", i, delay)
+		}
+	}
+
+	// Frame 1 sits halfway between the two source frames
+	r, _, _, _ := g.Image[1].At(2, 2).RGBA()
+	if mid := r >> 8; mid < 90 || mid > 110 {
+		t.Errorf("Expected cross-faded red around 100, got %d", mid)
+	}
+
+	if _, _, _, a := g.Image[0].Palette[0].RGBA(); a != 0 {
+		t.Error("Palette index 0 should stay transparent")
+	}
+	if _, _, _, a := g.Image[0].At(0, 0).RGBA(); a != 0 {
+		t.Error("Transparent pixels should survive post-processing")
+	}
+	if len(g.Image[0].Palette) > 16 {
+		t.Errorf("Palette should be limited to 16 colors, got %d", len(g.Image[0].Palette))
+	}
+}
+
+func TestPostProcessGIFDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idle.gif")
+	writeTestGIF(t, path, []uint8{10, 20, 30}, 7)
+
+	if err := PostProcessGIF(path, &ExtendedGIFConfig{FrameRate: 10}); err != nil {
+		t.Fatalf("PostProcessGIF failed: %v", err)
+	}
+	if g := readTestGIF(t, path); len(g.Image) != 3 || g.Delay[0] != 7 {
+		t.Error("GIF should be untouched when post-processing is disabled")
+	}
+}
+
+func TestResampleFrames(t *testing.T) {
+	frames := make([]*image.RGBA, 8)
+	for i := range frames {
+		frames[i] = image.NewRGBA(image.Rect(0, 0, 1, 1))
+		frames[i].Pix[0] = uint8(i)
+	}
+
+	down := resampleFrames(frames, 4, false)
+	if len(down) != 4 || down[1].Pix[0] != 2 || down[3].Pix[0] != 6 {
+		t.Errorf("Unexpected downsample: %v", pixValues(down))
+	}
+
+	up := resampleFrames(frames[:2], 4, false)
+	if got := pixValues(up); len(got) != 4 || got[0] != 0 || got[3] != 0 {
+		t.Errorf("Nearest upsample should wrap to the first frame, got %v", got)
+	}
+}
+
+func pixValues(frames []*image.RGBA) []uint8 {
+	values := make([]uint8, len(frames))
+	for i, f := range frames {
+		values[i] = f.Pix[0]
+	}
+	return values
+}
+
+func TestFrameDelay(t *testing.T) {
+	if d := frameDelay(12, nil); d != 8 {
+		t.Errorf("12 FPS delay = %d, want 8", d)
+	}
+	if d := frameDelay(0, []int{25}); d != 25 {
+		t.Errorf("Fallback delay = %d, want 25", d)
+	}
+	if d := frameDelay(100, nil); d != 2 {
+		t.Errorf("Delay should clamp to 2, got %d", d)
+	}
+}
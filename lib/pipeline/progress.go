@@ -0,0 +1,140 @@
+package pipeline
+
+// progress.go reports generation progress per animation state while ComfyUI
+// works. Callers opt in with WithProgress, so the Controller interface and
+// batch processing stay unchanged. Only sampler nodes report steps, and
+// they dominate generation time, so the ETA extrapolates the current
+// node's step rate.
+
+import (
+	"context"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// StateProgress is one progress update for a state being generated.
+type StateProgress struct {
+	Character string
+	State     string
+	Node      string    // Node executing: its class_type when the workflow has one, else its ID
+	Step      int       // Sampler step within Node
+	MaxSteps  int       // 0 when Node doesn't report steps
+	Started   time.Time // When the state's job was submitted
+	NodeStart time.Time // When Node reported its first step
+	Done      bool      // The job finished, failed or was cancelled
+	Status    string    // Job status from ComfyUI
+
+	firstStep int // Step reported at NodeStart
+}
+
+// ProgressFunc receives progress updates. Batches call it from several
+// goroutines at once.
+type ProgressFunc func(StateProgress)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes the controller report progress
+// for every state it generates to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressFrom returns the context's ProgressFunc, or a no-op.
+func progressFrom(ctx context.Context) ProgressFunc {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		return fn
+	}
+	return func(StateProgress) {}
+}
+
+// Fraction is how far the current node is, from 0 to 1, or 0 when it
+// doesn't report steps.
+func (p StateProgress) Fraction() float64 {
+	if p.Done && p.Status == "completed" {
+		return 1
+	}
+	if p.MaxSteps <= 0 {
+		return 0
+	}
+	return float64(p.Step) / float64(p.MaxSteps)
+}
+
+// ETA estimates the time left in the current node from its step rate so
+// far. ok is false until a step has completed.
+func (p StateProgress) ETA(now time.Time) (eta time.Duration, ok bool) {
+	steps := p.Step - p.firstStep
+	if p.MaxSteps <= 0 || steps <= 0 || p.NodeStart.IsZero() {
+		return 0, false
+	}
+	perStep := now.Sub(p.NodeStart) / time.Duration(steps)
+	return perStep * time.Duration(p.MaxSteps-p.Step), true
+}
+
+// progressTracker turns a job's progress frames into StateProgress updates.
+type progressTracker struct {
+	report    ProgressFunc
+	workflow  *comfyui.Workflow
+	current   StateProgress
+	executing time.Time // When ComfyUI started running the job, after any queue wait
+}
+
+// newProgressTracker starts tracking a state whose job was just submitted.
+func newProgressTracker(ctx context.Context, workflow *comfyui.Workflow, state string) *progressTracker {
+	t := &progressTracker{
+		report:   progressFrom(ctx),
+		workflow: workflow,
+		current:  StateProgress{State: state, Started: time.Now()},
+	}
+	if archetype, ok := workflow.Meta["archetype"].(string); ok {
+		t.current.Character = archetype
+	}
+	return t
+}
+
+// update records a progress frame and reports it.
+func (t *progressTracker) update(progress comfyui.JobProgress) {
+	p := &t.current
+	if t.executing.IsZero() && progress.Status == "running" {
+		t.executing = time.Now()
+	}
+	p.Status = progress.Status
+	p.Done = progress.Status == "completed" || progress.Status == "failed" ||
+		progress.Status == "error" || progress.Status == "cancelled"
+
+	if progress.Node != "" {
+		node := nodeLabel(t.workflow, progress.Node)
+		if node != p.Node || progress.Step < p.Step {
+			p.Node, p.NodeStart = node, time.Time{}
+		}
+	}
+	p.Step, p.MaxSteps = progress.Step, progress.MaxSteps
+	if p.MaxSteps > 0 && p.NodeStart.IsZero() {
+		// The rate is measured from the first reported step, since the
+		// time spent loading the model before it says nothing about steps
+		p.NodeStart, p.firstStep = time.Now(), p.Step
+	}
+
+	t.report(*p)
+}
+
+// nodeLabel names a workflow node by its class_type, falling back to its ID.
+func nodeLabel(workflow *comfyui.Workflow, id string) string {
+	if workflow != nil {
+		if node, ok := workflow.Nodes[id].(map[string]interface{}); ok {
+			if classType, ok := node["class_type"].(string); ok && classType != "" {
+				return classType
+			}
+		}
+	}
+	return id
+}
+
+// executionTime is how long ComfyUI spent running the job, or 0 when it
+// never reported running, e.g. because every node was cached.
+func (t *progressTracker) executionTime() time.Duration {
+	if t.executing.IsZero() {
+		return 0
+	}
+	return time.Since(t.executing)
+}
@@ -0,0 +1,113 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// cancellableClient records CancelJob calls on top of the mock client
+type cancellableClient struct {
+	mockComfyUIClient
+	mu        sync.Mutex
+	cancelled []string
+}
+
+func (c *cancellableClient) CancelJob(ctx context.Context, jobID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelled = append(c.cancelled, jobID)
+	return nil
+}
+
+func TestStateProgressETA(t *testing.T) {
+	now := time.Now()
+	p := StateProgress{Step: 6, MaxSteps: 20, NodeStart: now.Add(-10 * time.Second), firstStep: 1}
+
+	eta, ok := p.ETA(now)
+	if !ok || eta != 28*time.Second {
+		t.Errorf("Expected 2s per step for 14 steps, got %v %v", eta, ok)
+	}
+	if p.Fraction() != 0.3 {
+		t.Errorf("Expected 30%% done, got %v", p.Fraction())
+	}
+
+	if _, ok := (StateProgress{Step: 1, MaxSteps: 20, NodeStart: now, firstStep: 1}).ETA(now); ok {
+		t.Error("Expected no ETA before a step has been timed")
+	}
+}
+
+func TestProcessCharacterReportsProgress(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+	pc.comfyuiClient.(*mockComfyUIClient).monitorJobFunc = func(ctx context.Context, jobID string) (<-chan comfyui.JobProgress, error) {
+		ch := make(chan comfyui.JobProgress, 4)
+		ch <- comfyui.JobProgress{JobID: jobID, Status: "running", Node: "generation", Step: 1, MaxSteps: 4}
+		ch <- comfyui.JobProgress{JobID: jobID, Status: "running", Node: "generation", Step: 3, MaxSteps: 4}
+		ch <- comfyui.JobProgress{JobID: jobID, Status: "completed", Progress: 1}
+		close(ch)
+		return ch, nil
+	}
+
+	charConfig := DefaultCharacterConfig("test")
+	charConfig.States = []string{"idle"}
+	charConfig.Deployment.OutputDir = t.TempDir()
+
+	var updates []StateProgress
+	ctx := WithProgress(context.Background(), func(p StateProgress) { updates = append(updates, p) })
+	if result, err := pc.ProcessCharacter(ctx, charConfig); err != nil || !result.Success {
+		t.Fatalf("ProcessCharacter failed: %v %+v", err, result)
+	}
+
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 updates, got %+v", updates)
+	}
+	if updates[1].State != "idle" || updates[1].Node != "generation" || updates[1].Step != 3 {
+		t.Errorf("Unexpected step update: %+v", updates[1])
+	}
+	if _, ok := updates[1].ETA(time.Now()); !ok {
+		t.Error("Expected an ETA once steps have been timed")
+	}
+	if last := updates[2]; !last.Done || last.Fraction() != 1 {
+		t.Errorf("Expected a finished update, got %+v", last)
+	}
+}
+
+func TestRunWorkflowCancelsJobOnContextCancel(t *testing.T) {
+	client := &cancellableClient{}
+	client.monitorJobFunc = func(ctx context.Context, jobID string) (<-chan comfyui.JobProgress, error) {
+		ch := make(chan comfyui.JobProgress, 1)
+		go func() {
+			<-ctx.Done()
+			ch <- comfyui.JobProgress{JobID: jobID, Status: "cancelled", Err: ctx.Err()}
+			close(ch)
+		}()
+		return ch, nil
+	}
+	controller, err := NewController(DefaultPipelineConfig(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := controller.(*pipelineController)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = WithProgress(ctx, func(p StateProgress) {})
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	charConfig := DefaultCharacterConfig("test")
+	workflow, err := pc.createWorkflowForState(charConfig, "idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pc.runWorkflow(ctx, workflow, charConfig.GIFConfig, "idle", t.TempDir()+"/idle.gif")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancellation error, got %v", err)
+	}
+	if len(client.cancelled) != 1 || client.cancelled[0] != "test-job-id" {
+		t.Errorf("Expected the ComfyUI job to be cancelled, got %v", client.cancelled)
+	}
+}
@@ -0,0 +1,203 @@
+package pipeline
+
+// prompt.go provides prompt templating for asset generation. Prompts are
+// Go text/template strings rendered against PromptVariables, so character
+// authors can control exactly what is sent to ComfyUI for each state.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// defaultStateModifiers are appended to the prompt when no override is configured.
+var defaultStateModifiers = map[string]string{
+	"idle":    "relaxed, calm, neutral expression",
+	"talking": "speaking, mouth open, expressive",
+	"happy":   "smiling, joyful, positive expression",
+	"sad":     "downcast, melancholy, subdued",
+	"shy":     "blushing, bashful, looking away",
+	"flirty":  "winking, playful, confident",
+	"loving":  "gentle smile, warm expression, affectionate",
+	"jealous": "frowning, crossed arms, possessive",
+}
+
+// PromptTemplateVersion identifies the built-in prompt construction. Bump it
+// whenever defaultPositivePrompt, defaultNegativePrompt or the state modifiers
+// change, so manifests show which prompts an asset was made with.
+const PromptTemplateVersion = "1"
+
+// baseNegativePrompt terms are always included in the negative prompt.
+const baseNegativePrompt = "blurry, low quality, distorted, malformed"
+
+// PromptConfig holds optional prompt templates for a character.
+// Empty templates fall back to the built-in prompt construction.
+type PromptConfig struct {
+	Template         string                         `json:"template,omitempty"`          // Positive prompt template for every state
+	NegativeTemplate string                         `json:"negative_template,omitempty"` // Extra negative prompt template for every state
+	States           map[string]StatePromptOverride `json:"states,omitempty"`            // Per-state overrides
+}
+
+// StatePromptOverride customizes the prompt for a single animation state.
+type StatePromptOverride struct {
+	Modifier string `json:"modifier,omitempty"` // Replaces the built-in state modifier
+	Template string `json:"template,omitempty"` // Replaces the positive template for this state
+	Negative string `json:"negative,omitempty"` // Extra negative prompt template for this state
+}
+
+// PromptVariables are the values available inside prompt templates,
+// e.g. "{{.Description}}, {{.State}}, {{.Traits.hair}}".
+type PromptVariables struct {
+	Archetype     string            // Character archetype or name
+	Description   string            // Character description / base prompt
+	Style         string            // Art style name
+	StylePrompt   string            // Positive prompt of the configured style
+	StyleNegative string            // Negative prompt of the configured style
+	State         string            // Animation state being generated
+	StateModifier string            // Expression/pose modifier for the state
+	Traits        map[string]string // Visual traits and other free-form values
+}
+
+// StatePrompt is the fully rendered prompt pair for one animation state.
+type StatePrompt struct {
+	State    string `json:"state"`
+	Positive string `json:"positive"`
+	Negative string `json:"negative"`
+}
+
+// NewPromptVariables collects template variables for a character state.
+func NewPromptVariables(cfg *PipelineConfig, charCfg *CharacterConfig, state string) PromptVariables {
+	vars := PromptVariables{
+		State:         state,
+		StateModifier: defaultStateModifiers[state],
+		Traits:        map[string]string{},
+	}
+
+	if charCfg.Character != nil {
+		vars.Archetype = charCfg.Character.Archetype
+		vars.Description = charCfg.Character.Description
+		vars.Style = charCfg.Character.Style
+		for k, v := range charCfg.Character.Traits {
+			vars.Traits[k] = v
+		}
+	}
+
+	if cfg != nil {
+		if style, exists := cfg.Workflow.Styles[vars.Style]; exists {
+			vars.StylePrompt = style.Prompts.Positive
+			vars.StyleNegative = style.Prompts.Negative
+		}
+	}
+
+	if charCfg.Prompts != nil {
+		if override, exists := charCfg.Prompts.States[state]; exists && override.Modifier != "" {
+			vars.StateModifier = override.Modifier
+		}
+	}
+
+	return vars
+}
+
+// RenderPrompt executes a prompt template against the given variables.
+// Missing map keys are treated as errors so typos surface during preview.
+func RenderPrompt(text string, vars PromptVariables) (string, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse prompt template: %w", err)
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, vars); err != nil {
+		return "", fmt.Errorf("execute prompt template: %w", err)
+	}
+
+	return strings.TrimSpace(result.String()), nil
+}
+
+// BuildStatePrompt renders the positive and negative prompts for a state,
+// applying the character's templates and per-state overrides.
+func BuildStatePrompt(cfg *PipelineConfig, charCfg *CharacterConfig, state string) (*StatePrompt, error) {
+	vars := NewPromptVariables(cfg, charCfg, state)
+	result := &StatePrompt{
+		State:    state,
+		Positive: defaultPositivePrompt(vars),
+		Negative: defaultNegativePrompt(vars),
+	}
+
+	if charCfg.Prompts == nil {
+		return result, nil
+	}
+
+	override := charCfg.Prompts.States[state]
+
+	positiveTemplate := charCfg.Prompts.Template
+	if override.Template != "" {
+		positiveTemplate = override.Template
+	}
+	if positiveTemplate != "" {
+		positive, err := RenderPrompt(positiveTemplate, vars)
+		if err != nil {
+			return nil, fmt.Errorf("positive prompt: %w", err)
+		}
+		result.Positive = positive
+	}
+
+	for _, negativeTemplate := range []string{charCfg.Prompts.NegativeTemplate, override.Negative} {
+		if negativeTemplate == "" {
+			continue
+		}
+		negative, err := RenderPrompt(negativeTemplate, vars)
+		if err != nil {
+			return nil, fmt.Errorf("negative prompt: %w", err)
+		}
+		if negative != "" {
+			result.Negative += ", " + negative
+		}
+	}
+
+	return result, nil
+}
+
+// BuildAllPrompts renders prompts for every configured state, sorted by state name.
+func BuildAllPrompts(cfg *PipelineConfig, charCfg *CharacterConfig) ([]*StatePrompt, error) {
+	states := append([]string(nil), charCfg.States...)
+	sort.Strings(states)
+
+	prompts := make([]*StatePrompt, 0, len(states))
+	for _, state := range states {
+		prompt, err := BuildStatePrompt(cfg, charCfg, state)
+		if err != nil {
+			return nil, fmt.Errorf("state %s: %w", state, err)
+		}
+		prompts = append(prompts, prompt)
+	}
+
+	return prompts, nil
+}
+
+// defaultPositivePrompt joins description, style and state modifier.
+func defaultPositivePrompt(vars PromptVariables) string {
+	prompt := vars.Description
+
+	if vars.StylePrompt != "" {
+		prompt += ", " + vars.StylePrompt
+	}
+
+	if vars.StateModifier != "" {
+		prompt += ", " + vars.StateModifier
+	}
+
+	return prompt
+}
+
+// defaultNegativePrompt combines the base negative terms with the style's negatives.
+func defaultNegativePrompt(vars PromptVariables) string {
+	prompt := baseNegativePrompt
+
+	if vars.StyleNegative != "" {
+		prompt += ", " + vars.StyleNegative
+	}
+
+	return prompt
+}
@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildStatePromptDefaults(t *testing.T) {
+	cfg := DefaultPipelineConfig()
+	charCfg := DefaultCharacterConfig("test")
+	charCfg.Character.Description = "A cute fox"
+	charCfg.Character.Style = "anime"
+
+	prompt, err := BuildStatePrompt(cfg, charCfg, "happy")
+	if err != nil {
+		t.Fatalf("BuildStatePrompt failed: %v", err)
+	}
+
+	if !strings.HasPrefix(prompt.Positive, "A cute fox") {
+		t.Errorf("Expected description first, got %q", prompt.Positive)
+	}
+	if !strings.Contains(prompt.Positive, "smiling") {
+		t.Errorf("Expected happy modifier, got %q", prompt.Positive)
+	}
+	if !strings.HasPrefix(prompt.Negative, baseNegativePrompt) {
+		t.Errorf("Expected base negative terms, got %q", prompt.Negative)
+	}
+}
+
+func TestBuildStatePromptTemplates(t *testing.T) {
+	cfg := DefaultPipelineConfig()
+	charCfg := DefaultCharacterConfig("fox")
+	charCfg.Character.Description = "A cute fox"
+	charCfg.Character.Traits = map[string]string{"fur": "orange"}
+	charCfg.Prompts = &PromptConfig{
+		Template:         "{{.Archetype}} with {{.Traits.fur}} fur, {{.StateModifier}}",
+		NegativeTemplate: "extra tails",
+		States: map[string]StatePromptOverride{
+			"sad":   {Modifier: "ears drooping"},
+			"happy": {Template: "{{.Description}} dancing", Negative: "frown"},
+		},
+	}
+
+	sad, err := BuildStatePrompt(cfg, charCfg, "sad")
+	if err != nil {
+		t.Fatalf("BuildStatePrompt(sad) failed: %v", err)
+	}
+	if sad.Positive != "fox with orange fur, ears drooping" {
+		t.Errorf("Unexpected sad prompt: %q", sad.Positive)
+	}
+	if !strings.HasSuffix(sad.Negative, ", extra tails") {
+		t.Errorf("Expected global negative template, got %q", sad.Negative)
+	}
+
+	happy, err := BuildStatePrompt(cfg, charCfg, "happy")
+	if err != nil {
+		t.Fatalf("BuildStatePrompt(happy) failed: %v", err)
+	}
+	if happy.Positive != "A cute fox dancing" {
+		t.Errorf("Per-state template not applied: %q", happy.Positive)
+	}
+	if !strings.HasSuffix(happy.Negative, ", extra tails, frown") {
+		t.Errorf("Expected both negative templates, got %q", happy.Negative)
+	}
+}
+
+func TestBuildStatePromptTemplateError(t *testing.T) {
+	charCfg := DefaultCharacterConfig("fox")
+	charCfg.Prompts = &PromptConfig{Template: "{{.Traits.missing}}"}
+
+	if _, err := BuildStatePrompt(DefaultPipelineConfig(), charCfg, "idle"); err == nil {
+		t.Error("Expected error for missing trait key")
+	}
+}
+
+func TestBuildAllPromptsSorted(t *testing.T) {
+	charCfg := DefaultCharacterConfig("fox")
+	charCfg.States = []string{"sad", "idle", "happy"}
+
+	prompts, err := BuildAllPrompts(DefaultPipelineConfig(), charCfg)
+	if err != nil {
+		t.Fatalf("BuildAllPrompts failed: %v", err)
+	}
+	if len(prompts) != 3 || prompts[0].State != "happy" || prompts[2].State != "sad" {
+		t.Errorf("Expected prompts sorted by state, got %+v", prompts)
+	}
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+// provenance.go registers deployed assets with the artifact manager, so
+// generated GIFs are tracked alongside character binaries: checksum, size
+// and how each was generated. Assets are stored under the "assets" platform
+// with the animation state in place of the architecture, which keeps every
+// state's history apart.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/artifact"
+)
+
+// ArtifactPlatform is the artifact manager platform of generated assets.
+const ArtifactPlatform = "assets"
+
+// registerDeployedAssets stores each deployed state's GIF, keyed by state in
+// deployed, with the artifact manager in artifactsDir. Metadata links it
+// back to its generation manifest and lockfile input hash.
+func registerDeployedAssets(artifactsDir string, result *ProcessResult, deployed map[string]string) error {
+	manager, err := artifact.NewManager(artifactsDir)
+	if err != nil {
+		return err
+	}
+
+	for state, asset := range deployed {
+		metadata := map[string]string{
+			"stored_by":   "gif-generator",
+			"timestamp":   time.Now().Format(time.RFC3339),
+			"state":       state,
+			"deployed_to": asset,
+		}
+		if hash, ok := result.InputHashes[state]; ok {
+			metadata["input_hash"] = hash
+		}
+		if err := addManifestMetadata(metadata, ManifestPath(asset)); err != nil {
+			return fmt.Errorf("%s: %w", state, err)
+		}
+
+		if _, err := manager.StoreArtifact(asset, result.Character, ArtifactPlatform, state, metadata); err != nil {
+			return fmt.Errorf("store %s: %w", state, err)
+		}
+	}
+	return nil
+}
+
+// addManifestMetadata copies the generation manifest, compacted, into the
+// metadata along with the fields most often looked up. Assets deployed
+// without a manifest are registered without one.
+func addManifestMetadata(metadata map[string]string, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read manifest: %w", err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return fmt.Errorf("parse manifest: %w", err)
+	}
+	metadata["manifest"] = compact.String()
+	metadata["manifest_path"] = path
+
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		return err
+	}
+	metadata["graph_hash"] = manifest.GraphHash
+	metadata["seed"] = fmt.Sprint(manifest.Seed)
+	if manifest.Model != "" {
+		metadata["model"] = manifest.Model
+	}
+	return nil
+}
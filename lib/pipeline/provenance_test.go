@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/artifact"
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+func TestDeployAssetsRegistersArtifacts(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+	pc.config.Deployment.ValidateBeforeDeploy = false
+
+	charConfig := DefaultCharacterConfig("test")
+	charConfig.States = []string{"idle"}
+	charConfig.Deployment.OutputDir = t.TempDir()
+
+	result, err := pc.ProcessCharacter(context.Background(), charConfig)
+	if err != nil || !result.Success {
+		t.Fatalf("ProcessCharacter failed: %v %+v", err, result)
+	}
+	if err := pc.DeployAssets(context.Background(), result); err != nil {
+		t.Fatalf("DeployAssets failed: %v", err)
+	}
+
+	manager, err := artifact.NewManager(pc.config.Deployment.ArtifactsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := manager.ListArtifacts("test", ArtifactPlatform, "idle")
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("Expected the idle GIF registered once, got %d (%v)", len(stored), err)
+	}
+
+	info := stored[0]
+	if info.Checksum == "" || info.Metadata["input_hash"] != result.InputHashes["idle"] {
+		t.Errorf("Expected checksum and input hash, got %+v", info)
+	}
+	var manifest GenerationManifest
+	if err := json.Unmarshal([]byte(info.Metadata["manifest"]), &manifest); err != nil {
+		t.Fatalf("Expected the generation manifest in metadata: %v", err)
+	}
+	if manifest.State != "idle" || info.Metadata["graph_hash"] != manifest.GraphHash {
+		t.Errorf("Manifest metadata doesn't match: state %q, graph hash %q vs %q", manifest.State, info.Metadata["graph_hash"], manifest.GraphHash)
+	}
+}
+
+func TestDeployAssetsSkipsRegistrationWithoutDir(t *testing.T) {
+	var submitted []*comfyui.Workflow
+	pc := newRecordingController(t, &submitted)
+	pc.config.Deployment.ValidateBeforeDeploy = false
+	pc.config.Deployment.ArtifactsDir = ""
+
+	charConfig := DefaultCharacterConfig("test")
+	charConfig.States = []string{"idle"}
+	charConfig.Deployment.OutputDir = t.TempDir()
+
+	result, err := pc.ProcessCharacter(context.Background(), charConfig)
+	if err != nil || !result.Success {
+		t.Fatalf("ProcessCharacter failed: %v %+v", err, result)
+	}
+	if err := pc.DeployAssets(context.Background(), result); err != nil {
+		t.Errorf("DeployAssets without an artifacts dir failed: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package pipeline
+
+// reference.go implements image-to-image generation from an artist's
+// reference picture. Every state starts from the same uploaded image so the
+// character keeps its design; denoise controls how far each state may drift.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opd-ai/desktop-companion/lib/comfyui"
+)
+
+// DefaultReferenceDenoise keeps the reference's design while leaving room
+// for a new pose.
+const DefaultReferenceDenoise = 0.6
+
+// ReferenceConfig switches generation to image-to-image from a reference
+// image. Without an Image it only carries denoise settings and is ignored.
+type ReferenceConfig struct {
+	Image   string             `json:"image,omitempty"`   // Path to the reference PNG
+	Denoise float64            `json:"denoise,omitempty"` // 0-1, how much each state may change the reference
+	States  map[string]float64 `json:"states,omitempty"`  // Per-state denoise, overrides Denoise
+}
+
+// Enabled reports whether generation should start from the reference image.
+func (r *ReferenceConfig) Enabled() bool {
+	return r != nil && r.Image != ""
+}
+
+// DenoiseFor returns the denoise strength for a state.
+func (r *ReferenceConfig) DenoiseFor(state string) float64 {
+	if denoise, ok := r.States[state]; ok && denoise > 0 {
+		return denoise
+	}
+	if r.Denoise > 0 {
+		return r.Denoise
+	}
+	return DefaultReferenceDenoise
+}
+
+// Validate checks that the image exists and denoise values are in range.
+func (r *ReferenceConfig) Validate() error {
+	if r.Image == "" {
+		return fmt.Errorf("reference image path required")
+	}
+	if _, err := os.Stat(r.Image); err != nil {
+		return fmt.Errorf("reference image: %w", err)
+	}
+	if r.Denoise < 0 || r.Denoise > 1 {
+		return fmt.Errorf("reference denoise must be between 0 and 1, got %g", r.Denoise)
+	}
+	for state, denoise := range r.States {
+		if denoise < 0 || denoise > 1 {
+			return fmt.Errorf("reference denoise for %s must be between 0 and 1, got %g", state, denoise)
+		}
+	}
+	return nil
+}
+
+// load reads the image and names it by content, so re-uploading an edited
+// reference never reuses a stale server copy and manifests pin the exact image.
+func (r *ReferenceConfig) load() (string, []byte, error) {
+	data, err := os.ReadFile(r.Image)
+	if err != nil {
+		return "", nil, fmt.Errorf("read reference image: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	ext := strings.ToLower(filepath.Ext(r.Image))
+	return "reference_" + hex.EncodeToString(sum[:6]) + ext, data, nil
+}
+
+// ParseStateDenoise parses "idle=0.4,talking=0.6" into per-state strengths.
+func ParseStateDenoise(spec string) (map[string]float64, error) {
+	states := make(map[string]float64)
+	if strings.TrimSpace(spec) == "" {
+		return states, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		state, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || state == "" {
+			return nil, fmt.Errorf("invalid state denoise %q, want state=value", pair)
+		}
+		var denoise float64
+		if _, err := fmt.Sscanf(value, "%g", &denoise); err != nil {
+			return nil, fmt.Errorf("invalid denoise for %s: %w", state, err)
+		}
+		states[state] = denoise
+	}
+	return states, nil
+}
+
+// uploadReference sends the character's reference image to ComfyUI.
+func (c *pipelineController) uploadReference(ctx context.Context, ref *ReferenceConfig) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	uploader, ok := c.comfyuiClient.(comfyui.ImageUploader)
+	if !ok {
+		return fmt.Errorf("comfyui client cannot upload reference images")
+	}
+
+	name, data, err := ref.load()
+	if err != nil {
+		return err
+	}
+	if _, err := uploader.UploadImage(ctx, name, data); err != nil {
+		return fmt.Errorf("upload reference image: %w", err)
+	}
+	return nil
+}
+
+// referenceNode is the img2img input node added to a state's workflow.
+func referenceNode(ref *ReferenceConfig, state string) (map[string]interface{}, error) {
+	name, _, err := ref.load()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"image":   name,
+		"denoise": ref.DenoiseFor(state),
+	}, nil
+}
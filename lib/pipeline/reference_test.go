@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// uploadingClient adds reference uploads to the mock ComfyUI client.
+type uploadingClient struct {
+	mockComfyUIClient
+	uploaded map[string][]byte
+}
+
+func (u *uploadingClient) UploadImage(ctx context.Context, name string, data []byte) (string, error) {
+	u.uploaded[name] = data
+	return name, nil
+}
+
+func writeReference(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Reference.PNG")
+	if err := os.WriteFile(path, []byte("png-bytes"), 0o644); err != nil {
+		t.Fatalf("write reference: %v", err)
+	}
+	return path
+}
+
+func TestReferenceDenoiseFor(t *testing.T) {
+	ref := &ReferenceConfig{States: map[string]float64{"happy": 0.8}}
+	if got := ref.DenoiseFor("idle"); got != DefaultReferenceDenoise {
+		t.Errorf("expected default denoise, got %g", got)
+	}
+	ref.Denoise = 0.4
+	if got := ref.DenoiseFor("idle"); got != 0.4 {
+		t.Errorf("expected character denoise 0.4, got %g", got)
+	}
+	if got := ref.DenoiseFor("happy"); got != 0.8 {
+		t.Errorf("expected state denoise 0.8, got %g", got)
+	}
+}
+
+func TestParseStateDenoise(t *testing.T) {
+	states, err := ParseStateDenoise("idle=0.4, talking=0.65")
+	if err != nil {
+		t.Fatalf("ParseStateDenoise failed: %v", err)
+	}
+	if states["idle"] != 0.4 || states["talking"] != 0.65 {
+		t.Errorf("unexpected states: %v", states)
+	}
+	if _, err := ParseStateDenoise("idle"); err == nil {
+		t.Error("expected error for missing value")
+	}
+}
+
+func TestReferenceWorkflow(t *testing.T) {
+	client := &uploadingClient{uploaded: make(map[string][]byte)}
+	controller, err := NewController(DefaultPipelineConfig(), client)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	pc := controller.(*pipelineController)
+
+	charCfg := DefaultCharacterConfig("test")
+	charCfg.Reference = &ReferenceConfig{Image: writeReference(t), States: map[string]float64{"idle": 0.3}}
+
+	if err := pc.uploadReference(context.Background(), charCfg.Reference); err != nil {
+		t.Fatalf("uploadReference failed: %v", err)
+	}
+	if len(client.uploaded) != 1 {
+		t.Fatalf("expected one upload, got %d", len(client.uploaded))
+	}
+
+	workflow, _, err := pc.buildStateWorkflow(charCfg, "idle")
+	if err != nil {
+		t.Fatalf("buildStateWorkflow failed: %v", err)
+	}
+	node, ok := workflow.Nodes["reference"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected a reference node in the workflow")
+	}
+	name := node["image"].(string)
+	if _, uploaded := client.uploaded[name]; !uploaded || !strings.HasSuffix(name, ".png") {
+		t.Errorf("reference node should use the uploaded name, got %q", name)
+	}
+	if node["denoise"] != 0.3 {
+		t.Errorf("expected idle denoise 0.3, got %v", node["denoise"])
+	}
+}
+
+func TestReferenceRequiresUploader(t *testing.T) {
+	controller, _ := NewController(DefaultPipelineConfig(), &mockComfyUIClient{})
+	pc := controller.(*pipelineController)
+
+	err := pc.uploadReference(context.Background(), &ReferenceConfig{Image: writeReference(t)})
+	if err == nil || !strings.Contains(err.Error(), "cannot upload") {
+		t.Errorf("expected an upload capability error, got %v", err)
+	}
+
+	err = pc.uploadReference(context.Background(), &ReferenceConfig{Image: writeReference(t), Denoise: 1.5})
+	if err == nil {
+		t.Error("expected denoise validation error")
+	}
+}
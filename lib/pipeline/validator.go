@@ -83,6 +83,8 @@ type AssetMetrics struct {
 	Colors           int           `json:"colors"`                      // Color count
 	HasTransparency  bool          `json:"has_transparency"`            // Transparency support
 	CompressionRatio float64       `json:"compression_ratio,omitempty"` // Size efficiency
+
+	BackgroundRemoval *BackgroundRemovalReport `json:"background_removal,omitempty"` // Set when frames were keyed before assembly
 }
 
 // StyleConsistencyResult contains style consistency analysis.
@@ -163,6 +165,7 @@ func (v *assetValidator) ValidateAsset(ctx context.Context, assetPath string, co
 	v.checkFrameRate(result, config)
 	v.checkTransparency(result, config)
 	v.checkDimensions(result, config)
+	v.checkColors(result)
 	v.checkFormat(result, assetPath)
 
 	// Determine overall validity
@@ -411,6 +414,21 @@ func (v *assetValidator) checkDimensions(result *ValidationResult, config *Valid
 	}
 }
 
+// checkColors warns when frames use more colors between them than one GIF
+// palette holds, which means each frame carries its own palette.
+func (v *assetValidator) checkColors(result *ValidationResult) {
+	if result.Metrics.Colors > 256 {
+		result.Warnings = append(result.Warnings, ValidationWarning{
+			Code:       "TOO_MANY_COLORS",
+			Message:    fmt.Sprintf("Frames use %d colors, more than one 256 color palette", result.Metrics.Colors),
+			Suggestion: "Re-quantize to a shared palette with validate --fix",
+		})
+		result.ComplianceChecks["colors"] = false
+	} else {
+		result.ComplianceChecks["colors"] = true
+	}
+}
+
 // checkFormat validates file format.
 func (v *assetValidator) checkFormat(result *ValidationResult, assetPath string) {
 	if !strings.HasSuffix(strings.ToLower(assetPath), ".gif") {
@@ -0,0 +1,290 @@
+package pipeline
+
+// variants.go writes extra resolutions of every generated GIF in the same
+// run, so one character gets a 2x desktop set and a smaller Android set
+// without generating twice. Each variant is named after its state plus the
+// profile's suffix (idle@2x.gif, idle_mobile.gif). Profiles tied to a
+// platform are recorded in the card's platformConfig animations on deploy,
+// which the companion prefers over the base animation on that platform.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Variant size limits, matching the card's platform default sizes
+const (
+	minVariantDimension = 32
+	maxVariantDimension = 1024
+)
+
+// OutputProfile is an extra resolution written for every state.
+type OutputProfile struct {
+	Suffix   string  `json:"suffix"`             // Appended to the state name, e.g. "@2x" or "_mobile"
+	Width    int     `json:"width,omitempty"`    // Variant width, set together with Height
+	Height   int     `json:"height,omitempty"`   // Variant height
+	Scale    float64 `json:"scale,omitempty"`    // Multiple of the GIF size when Width and Height are unset
+	Platform string  `json:"platform,omitempty"` // "desktop" or "mobile" to use the variant on that platform
+}
+
+// AssetVariant is one extra resolution of a generated asset.
+type AssetVariant struct {
+	Suffix     string `json:"suffix"`
+	Platform   string `json:"platform,omitempty"`
+	OutputPath string `json:"output_path"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+// Validate checks the profile names its files and has a usable size.
+func (p OutputProfile) Validate() error {
+	if p.Suffix == "" || strings.ContainsAny(p.Suffix, `/\.`) {
+		return fmt.Errorf("output profile suffix %q must be non-empty without dots or slashes", p.Suffix)
+	}
+	if (p.Width == 0) != (p.Height == 0) {
+		return fmt.Errorf("output profile %s: width and height must be set together", p.Suffix)
+	}
+	if p.Width == 0 && p.Scale <= 0 {
+		return fmt.Errorf("output profile %s needs a width and height or a scale", p.Suffix)
+	}
+	if p.Platform != "" && p.Platform != "desktop" && p.Platform != "mobile" {
+		return fmt.Errorf("output profile %s: platform must be desktop or mobile, got %q", p.Suffix, p.Platform)
+	}
+	return nil
+}
+
+// ValidateOutputProfiles checks every profile, and that no two write the
+// same files or are used on the same platform.
+func ValidateOutputProfiles(profiles []OutputProfile) error {
+	suffixes := make(map[string]bool)
+	platforms := make(map[string]bool)
+	for _, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+		if suffixes[profile.Suffix] {
+			return fmt.Errorf("output profile %s is defined twice", profile.Suffix)
+		}
+		suffixes[profile.Suffix] = true
+		if profile.Platform != "" {
+			if platforms[profile.Platform] {
+				return fmt.Errorf("output profile %s: another profile is already used on %s", profile.Suffix, profile.Platform)
+			}
+			platforms[profile.Platform] = true
+		}
+	}
+	return nil
+}
+
+// size returns the variant size for a GIF of width by height.
+func (p OutputProfile) size(width, height int) (int, int) {
+	if p.Width > 0 && p.Height > 0 {
+		return clampInt(p.Width, minVariantDimension, maxVariantDimension), clampInt(p.Height, minVariantDimension, maxVariantDimension)
+	}
+	return clampInt(int(float64(width)*p.Scale+0.5), minVariantDimension, maxVariantDimension),
+		clampInt(int(float64(height)*p.Scale+0.5), minVariantDimension, maxVariantDimension)
+}
+
+// VariantPath returns the file a profile writes for the GIF at path,
+// e.g. "out/idle.gif" with "@2x" gives "out/idle@2x.gif".
+func VariantPath(path, suffix string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + suffix + ext
+}
+
+// WriteVariants writes every profile's resolution of the GIF at path next
+// to it. Frames, timing and the palette size carry over; only the size changes.
+func WriteVariants(path string, profiles []OutputProfile, colors int) ([]AssetVariant, error) {
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open gif: %w", err)
+	}
+	src, err := gif.DecodeAll(in)
+	in.Close()
+	if err != nil {
+		return nil, fmt.Errorf("decode gif: %w", err)
+	}
+	if len(src.Image) == 0 {
+		return nil, fmt.Errorf("gif has no frames")
+	}
+
+	frames := compositeFrames(src)
+	bounds := frames[0].Bounds()
+	transparency := (&assetValidator{}).checkGIFTransparency(src)
+	delay := frameDelay(0, src.Delay)
+	if colors <= 0 || colors > 256 {
+		colors = 256
+	}
+
+	variants := make([]AssetVariant, 0, len(profiles))
+	for _, profile := range profiles {
+		width, height := profile.size(bounds.Dx(), bounds.Dy())
+		data, err := encodeFrames(resizeFrames(frames, width, height), colors, transparency, delay, src.LoopCount)
+		if err != nil {
+			return nil, fmt.Errorf("variant %s: %w", profile.Suffix, err)
+		}
+
+		variantPath := VariantPath(path, profile.Suffix)
+		if err := replaceFile(variantPath, data); err != nil {
+			return nil, fmt.Errorf("variant %s: %w", profile.Suffix, err)
+		}
+		variants = append(variants, AssetVariant{
+			Suffix:     profile.Suffix,
+			Platform:   profile.Platform,
+			OutputPath: variantPath,
+			Width:      width,
+			Height:     height,
+		})
+	}
+	return variants, nil
+}
+
+// resizeFrames scales frames to width by height. Whole-number enlargements
+// repeat pixels so pixel art stays crisp; anything else is resampled.
+func resizeFrames(frames []*image.RGBA, width, height int) []*image.RGBA {
+	bounds := frames[0].Bounds()
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return frames
+	}
+	if width%bounds.Dx() != 0 || height%bounds.Dy() != 0 {
+		return scaleFrames(frames, width, height)
+	}
+
+	scaled := make([]*image.RGBA, len(frames))
+	for i, frame := range frames {
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		xdraw.NearestNeighbor.Scale(dst, dst.Bounds(), frame, frame.Bounds(), draw.Src, nil)
+		scaled[i] = dst
+	}
+	return scaled
+}
+
+// UpdateCardAnimations records deployed platform variants in the card's
+// platformConfig, e.g. {"mobile": {"idle": "idle_mobile.gif"}}. Only the
+// platformConfig entry is rewritten; the card's other fields keep their order.
+func UpdateCardAnimations(cardPath string, variants map[string]map[string]string) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(cardPath)
+	if err != nil {
+		return fmt.Errorf("read card: %w", err)
+	}
+	keys, fields, err := decodeObject(data)
+	if err != nil {
+		return fmt.Errorf("parse card: %w", err)
+	}
+
+	platformConfig := make(map[string]map[string]json.RawMessage)
+	if raw, ok := fields["platformConfig"]; ok {
+		if err := json.Unmarshal(raw, &platformConfig); err != nil {
+			return fmt.Errorf("parse card platformConfig: %w", err)
+		}
+	} else {
+		keys = append(keys, "platformConfig")
+	}
+	if platformConfig == nil {
+		platformConfig = make(map[string]map[string]json.RawMessage) // "platformConfig": null
+	}
+
+	for platform, files := range variants {
+		config := platformConfig[platform]
+		if config == nil {
+			config = make(map[string]json.RawMessage)
+		}
+		animations := make(map[string]string)
+		if raw, ok := config["animations"]; ok {
+			if err := json.Unmarshal(raw, &animations); err != nil {
+				return fmt.Errorf("parse card %s animations: %w", platform, err)
+			}
+		}
+		for state, file := range files {
+			animations[state] = file
+		}
+		if config["animations"], err = json.Marshal(animations); err != nil {
+			return fmt.Errorf("encode card %s animations: %w", platform, err)
+		}
+		platformConfig[platform] = config
+	}
+	if fields["platformConfig"], err = json.Marshal(platformConfig); err != nil {
+		return fmt.Errorf("encode card platformConfig: %w", err)
+	}
+
+	var compact bytes.Buffer
+	compact.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			compact.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		compact.Write(name)
+		compact.WriteByte(':')
+		compact.Write(fields[key])
+	}
+	compact.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return fmt.Errorf("format card: %w", err)
+	}
+	out.WriteByte('\n')
+	return replaceFile(cardPath, out.Bytes())
+}
+
+// decodeObject splits a JSON object into its keys, in file order, and raw values.
+func decodeObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected a JSON object")
+	}
+
+	var keys []string
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, seen := fields[key]; !seen {
+			keys = append(keys, key)
+		}
+		fields[key] = value
+	}
+	return keys, fields, nil
+}
+
+// platformVariants groups deployed variant file names by platform and state.
+func platformVariants(assets map[string]*GeneratedAsset, targets map[string]string) map[string]map[string]string {
+	variants := make(map[string]map[string]string)
+	for state, asset := range assets {
+		for _, variant := range asset.Variants {
+			if variant.Platform == "" {
+				continue
+			}
+			if variants[variant.Platform] == nil {
+				variants[variant.Platform] = make(map[string]string)
+			}
+			variants[variant.Platform][state] = filepath.Base(VariantPath(targets[state], variant.Suffix))
+		}
+	}
+	return variants
+}
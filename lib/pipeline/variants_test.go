@@ -0,0 +1,179 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteVariants(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "idle.gif")
+	writeTestGIF(t, path, []uint8{60, 120, 180, 240}, 8)
+
+	variants, err := WriteVariants(path, []OutputProfile{
+		{Suffix: "@2x", Scale: 8},
+		{Suffix: "_mobile", Width: 48, Height: 40, Platform: "mobile"},
+	}, 0)
+	if err != nil {
+		t.Fatalf("WriteVariants failed: %v", err)
+	}
+	if len(variants) != 2 {
+		t.Fatalf("Expected 2 variants, got %d", len(variants))
+	}
+
+	tests := []struct {
+		file          string
+		width, height int
+	}{
+		{"idle@2x.gif", 32, 32},
+		{"idle_mobile.gif", 48, 40},
+	}
+	for i, tt := range tests {
+		if variants[i].OutputPath != filepath.Join(dir, tt.file) {
+			t.Errorf("Expected variant at %s, got %s", tt.file, variants[i].OutputPath)
+		}
+		g := readTestGIF(t, variants[i].OutputPath)
+		if g.Config.Width != tt.width || g.Config.Height != tt.height {
+			t.Errorf("%s is %dx%d, want %dx%d", tt.file, g.Config.Width, g.Config.Height, tt.width, tt.height)
+		}
+		if len(g.Image) != 4 || g.Delay[0] != 8 {
+			t.Errorf("%s should keep 4 frames at delay 8, got %d at %v", tt.file, len(g.Image), g.Delay)
+		}
+	}
+
+	// Whole-number enlargements keep hard pixel edges
+	g := readTestGIF(t, variants[0].OutputPath)
+	if _, _, _, a := g.Image[0].At(7, 7).RGBA(); a != 0 {
+		t.Error("Expected the transparent corner pixel to cover 8x8 pixels in the 8x variant")
+	}
+}
+
+func TestValidateOutputProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []OutputProfile
+		wantErr  string
+	}{
+		{"valid", []OutputProfile{{Suffix: "@2x", Scale: 2}, {Suffix: "_mobile", Width: 96, Height: 96, Platform: "mobile"}}, ""},
+		{"empty suffix", []OutputProfile{{Scale: 2}}, "suffix"},
+		{"dotted suffix", []OutputProfile{{Suffix: ".2x", Scale: 2}}, "suffix"},
+		{"no size", []OutputProfile{{Suffix: "_small"}}, "needs a width"},
+		{"half a size", []OutputProfile{{Suffix: "_small", Width: 64}}, "together"},
+		{"unknown platform", []OutputProfile{{Suffix: "_tv", Scale: 2, Platform: "tv"}}, "platform"},
+		{"duplicate suffix", []OutputProfile{{Suffix: "@2x", Scale: 2}, {Suffix: "@2x", Scale: 3}}, "twice"},
+		{"shared platform", []OutputProfile{{Suffix: "_a", Scale: 2, Platform: "mobile"}, {Suffix: "_b", Scale: 3, Platform: "mobile"}}, "already used"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputProfiles(tt.profiles)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateCardAnimations(t *testing.T) {
+	cardPath := filepath.Join(t.TempDir(), "character.json")
+	card := `{
+  "name": "Pixel",
+  "animations": {"idle": "idle.gif", "talking": "talking.gif"},
+  "platformConfig": {"mobile": {"windowMode": "widget", "animations": {"talking": "old.gif"}}},
+  "dialogs": []
+}`
+	if err := os.WriteFile(cardPath, []byte(card), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateCardAnimations(cardPath, map[string]map[string]string{
+		"mobile":  {"idle": "idle_mobile.gif", "talking": "talking_mobile.gif"},
+		"desktop": {"idle": "idle@2x.gif"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateCardAnimations failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cardPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, dialogs := strings.Index(string(data), `"name"`), strings.Index(string(data), `"dialogs"`); name < 0 || dialogs < name {
+		t.Errorf("Expected the card's fields to keep their order:\n%s", data)
+	}
+
+	var updated struct {
+		Animations     map[string]string
+		PlatformConfig map[string]struct {
+			WindowMode string
+			Animations map[string]string
+		}
+	}
+	if err := json.Unmarshal(data, &updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Animations["idle"] != "idle.gif" {
+		t.Error("The base animations should not change")
+	}
+	mobile := updated.PlatformConfig["mobile"]
+	if mobile.WindowMode != "widget" || mobile.Animations["idle"] != "idle_mobile.gif" || mobile.Animations["talking"] != "talking_mobile.gif" {
+		t.Errorf("Unexpected mobile config: %+v", mobile)
+	}
+	if updated.PlatformConfig["desktop"].Animations["idle"] != "idle@2x.gif" {
+		t.Errorf("Expected a desktop config to be added: %+v", updated.PlatformConfig["desktop"])
+	}
+}
+
+func TestDeployAssetsVariants(t *testing.T) {
+	generated := t.TempDir()
+	idle := filepath.Join(generated, "idle.gif")
+	writeTestGIF(t, idle, []uint8{60, 120, 180, 240}, 8)
+	variants, err := WriteVariants(idle, []OutputProfile{{Suffix: "_mobile", Scale: 16, Platform: "mobile"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := t.TempDir()
+	cardPath := filepath.Join(target, "character.json")
+	if err := os.WriteFile(cardPath, []byte(`{"name": "Pixel", "animations": {"idle": "idle.gif"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultPipelineConfig()
+	config.Deployment.ValidateBeforeDeploy = false
+	config.Deployment.BackupExisting = false
+	config.Deployment.ArtifactsDir = ""
+	controller, err := NewController(config, &mockComfyUIClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := &ProcessResult{
+		Character: "pixel",
+		Success:   true,
+		OutputDir: target,
+		GeneratedAssets: map[string]*GeneratedAsset{
+			"idle": {State: "idle", OutputPath: idle, Variants: variants},
+		},
+	}
+	if err := controller.DeployAssets(context.Background(), result); err != nil {
+		t.Fatalf("DeployAssets failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "idle_mobile.gif")); err != nil {
+		t.Errorf("Expected the variant next to the asset: %v", err)
+	}
+	data, err := os.ReadFile(cardPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"idle": "idle_mobile.gif"`) {
+		t.Errorf("Expected the card to use the variant on mobile:\n%s", data)
+	}
+}
@@ -0,0 +1,169 @@
+package platform
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// ErrNoAutostart is returned when the OS has no login autostart this
+// package knows how to manage
+var ErrNoAutostart = errors.New("autostart not available")
+
+// windowsRunKey lists the programs Windows starts when the user logs in
+const windowsRunKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`
+
+// Autostart starts the app when the user logs in
+type Autostart interface {
+	Enabled() (bool, error)
+	Enable() error
+	Disable() error
+}
+
+// NewAutostart returns the autostart entry for this OS that runs target
+// with args: an XDG autostart .desktop file on Linux, a LaunchAgent on macOS
+// and a Run key value on Windows. appID names the entry, e.g. "ai.opd.dds",
+// and matches the value the Windows installer writes; name is the display
+// name.
+func NewAutostart(appID, name, target string, args []string) (Autostart, error) {
+	var dir string
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		dir, err = os.UserHomeDir()
+	case "windows":
+	default:
+		dir, err = os.UserConfigDir()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find autostart directory: %w", err)
+	}
+	return newAutostart(runtime.GOOS, dir, appID, name, target, args, runOutput)
+}
+
+// newAutostart builds the entry for goos. dir is the user config directory
+// on Linux and the home directory on macOS.
+func newAutostart(goos, dir, appID, name, target string, args []string, run commandRunner) (Autostart, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		content := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=%s\nTerminal=false\nX-GNOME-Autostart-enabled=true\n",
+			name, shellCommand(target, args))
+		return &fileAutostart{
+			path:    filepath.Join(dir, "autostart", appID+".desktop"),
+			content: []byte(content),
+		}, nil
+	case "darwin":
+		return &fileAutostart{
+			path:    filepath.Join(dir, "Library", "LaunchAgents", appID+".plist"),
+			content: launchAgentPlist(appID, append([]string{target}, args...)),
+		}, nil
+	case "windows":
+		return &runKeyAutostart{name: appID, command: windowsCommand(target, args), run: run}, nil
+	default:
+		return nil, ErrNoAutostart
+	}
+}
+
+// fileAutostart is an entry that exists as a file: the .desktop file the
+// session starts on Linux, or the LaunchAgent launchd loads at login on macOS
+type fileAutostart struct {
+	path    string
+	content []byte
+}
+
+// Enabled reports whether the file exists
+func (a *fileAutostart) Enabled() (bool, error) {
+	if _, err := os.Stat(a.path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Enable writes the file, replacing an entry for an older executable
+func (a *fileAutostart) Enable() error {
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create autostart directory: %w", err)
+	}
+	if err := os.WriteFile(a.path, a.content, 0o644); err != nil {
+		return fmt.Errorf("failed to write autostart entry: %w", err)
+	}
+	return nil
+}
+
+// Disable removes the file
+func (a *fileAutostart) Disable() error {
+	if err := os.Remove(a.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove autostart entry: %w", err)
+	}
+	return nil
+}
+
+// runKeyAutostart is a value under the Run key, managed with reg.exe
+type runKeyAutostart struct {
+	name    string
+	command string
+	run     commandRunner
+}
+
+// Enabled reports whether the value exists; reg query exits 1 when it
+// doesn't
+func (a *runKeyAutostart) Enabled() (bool, error) {
+	_, err := a.run("reg", "query", windowsRunKey, "/v", a.name)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reg query: %w", err)
+	}
+	return true, nil
+}
+
+// Enable writes the value
+func (a *runKeyAutostart) Enable() error {
+	if _, err := a.run("reg", "add", windowsRunKey, "/v", a.name, "/t", "REG_SZ", "/d", a.command, "/f"); err != nil {
+		return fmt.Errorf("reg add: %w", err)
+	}
+	return nil
+}
+
+// Disable deletes the value if it exists
+func (a *runKeyAutostart) Disable() error {
+	enabled, err := a.Enabled()
+	if err != nil || !enabled {
+		return err
+	}
+	if _, err := a.run("reg", "delete", windowsRunKey, "/v", a.name, "/f"); err != nil {
+		return fmt.Errorf("reg delete: %w", err)
+	}
+	return nil
+}
+
+// launchAgentPlist renders a LaunchAgent that runs program once at login
+func launchAgentPlist(label string, program []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	buf.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	buf.WriteString("\t<key>Label</key>\n\t<string>")
+	xml.EscapeText(&buf, []byte(label))
+	buf.WriteString("</string>\n\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range program {
+		buf.WriteString("\t\t<string>")
+		xml.EscapeText(&buf, []byte(arg))
+		buf.WriteString("</string>\n")
+	}
+	buf.WriteString("\t</array>\n")
+	buf.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	buf.WriteString("\t<key>ProcessType</key>\n\t<string>Interactive</string>\n")
+	buf.WriteString("</dict>\n</plist>\n")
+	return buf.Bytes()
+}
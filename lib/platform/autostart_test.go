@@ -0,0 +1,122 @@
+package platform
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileAutostart(t *testing.T) {
+	tests := []struct {
+		goos string
+		path string
+		want []string
+	}{
+		{"linux", filepath.Join("autostart", "ai.opd.dds.desktop"), []string{
+			"Name=Desktop Companion",
+			`Exec="/opt/dds/companion" "-debug"`,
+			"X-GNOME-Autostart-enabled=true",
+		}},
+		{"darwin", filepath.Join("Library", "LaunchAgents", "ai.opd.dds.plist"), []string{
+			"<key>Label</key>\n\t<string>ai.opd.dds</string>",
+			"<string>/opt/dds/companion</string>\n\t\t<string>-debug</string>",
+			"<key>RunAtLoad</key>\n\t<true/>",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			dir := t.TempDir()
+			autostart, err := newAutostart(tt.goos, dir, "ai.opd.dds", "Desktop Companion", "/opt/dds/companion", []string{"-debug"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if enabled, err := autostart.Enabled(); err != nil || enabled {
+				t.Fatalf("Expected autostart off at first, got %v (%v)", enabled, err)
+			}
+			if err := autostart.Enable(); err != nil {
+				t.Fatalf("Enable failed: %v", err)
+			}
+			if enabled, _ := autostart.Enabled(); !enabled {
+				t.Error("Expected autostart on after Enable")
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, tt.path))
+			if err != nil {
+				t.Fatalf("Expected the entry at %s: %v", tt.path, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("Entry is missing %q:\n%s", want, content)
+				}
+			}
+
+			if err := autostart.Disable(); err != nil {
+				t.Fatalf("Disable failed: %v", err)
+			}
+			if enabled, _ := autostart.Enabled(); enabled {
+				t.Error("Expected autostart off after Disable")
+			}
+			if err := autostart.Disable(); err != nil {
+				t.Errorf("Disabling twice should succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRunKeyAutostart(t *testing.T) {
+	values := map[string]string{}
+	var commands []string
+	run := func(name string, args ...string) (string, error) {
+		commands = append(commands, args[0])
+		switch args[0] {
+		case "query":
+			if _, ok := values[args[3]]; !ok {
+				// reg query exits 1 for a missing value
+				return "", exec.Command("sh", "-c", "exit 1").Run()
+			}
+		case "add":
+			values[args[3]] = args[7]
+		case "delete":
+			delete(values, args[3])
+		}
+		return "", nil
+	}
+
+	autostart, err := newAutostart("windows", "", "ai.opd.dds", "Desktop Companion", `C:\DDS\companion.exe`, nil, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enabled, err := autostart.Enabled(); err != nil || enabled {
+		t.Fatalf("Expected autostart off at first, got %v (%v)", enabled, err)
+	}
+	if err := autostart.Enable(); err != nil {
+		t.Fatal(err)
+	}
+	if values["ai.opd.dds"] != `"C:\DDS\companion.exe"` {
+		t.Errorf("Expected the quoted executable in the Run key, got %q", values["ai.opd.dds"])
+	}
+	if enabled, _ := autostart.Enabled(); !enabled {
+		t.Error("Expected autostart on after Enable")
+	}
+
+	if err := autostart.Disable(); err != nil {
+		t.Fatal(err)
+	}
+	if err := autostart.Disable(); err != nil {
+		t.Errorf("Disabling twice should succeed, got %v", err)
+	}
+	if got := strings.Join(commands, " "); got != "query add query query delete query" {
+		t.Errorf("Unexpected reg commands: %s", got)
+	}
+}
+
+func TestAutostartUnsupported(t *testing.T) {
+	if _, err := newAutostart("android", t.TempDir(), "ai.opd.dds", "Desktop Companion", "companion", nil, nil); err != ErrNoAutostart {
+		t.Errorf("Expected ErrNoAutostart, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoIdleDetection is returned when the OS offers no way to read how long
+// the user has been idle
+var ErrNoIdleDetection = errors.New("idle detection not available")
+
+// windowsIdleScript prints milliseconds since the last input, then "locked"
+// while the lock screen is up
+const windowsIdleScript = `Add-Type @'
+using System; using System.Runtime.InteropServices;
+public static class Idle {
+  [StructLayout(LayoutKind.Sequential)] struct LastInput { public uint Size; public uint Time; }
+  [DllImport("user32.dll")] static extern bool GetLastInputInfo(ref LastInput info);
+  public static uint Millis() { var i = new LastInput(); i.Size = (uint)Marshal.SizeOf(i); GetLastInputInfo(ref i); return (uint)Environment.TickCount - i.Time; }
+}
+'@
+[Idle]::Millis()
+if (Get-Process LogonUI -ErrorAction SilentlyContinue) { 'locked' }`
+
+// IdleState is the user's activity as seen by the OS
+type IdleState struct {
+	Idle   time.Duration // Time since the last keyboard or mouse input
+	Locked bool          // The screen is locked
+}
+
+// IdleDetector reports how long the user has been away from the computer
+type IdleDetector interface {
+	IdleState() (IdleState, error)
+}
+
+// commandRunner runs a command and returns its standard output
+type commandRunner func(name string, args ...string) (string, error)
+
+// commandIdleDetector reads idle time through OS tools, like Speak does for
+// speech: xprintidle and loginctl on Linux, ioreg on macOS and
+// GetLastInputInfo through PowerShell on Windows
+type commandIdleDetector struct {
+	goos     string
+	run      commandRunner
+	lookPath func(string) (string, error)
+	session  string // XDG_SESSION_ID for the lock hint on Linux
+}
+
+// NewIdleDetector returns the idle detector for this OS
+func NewIdleDetector() IdleDetector {
+	return &commandIdleDetector{
+		goos:     runtime.GOOS,
+		run:      runOutput,
+		lookPath: exec.LookPath,
+		session:  os.Getenv("XDG_SESSION_ID"),
+	}
+}
+
+// runOutput runs a command and returns its output
+func runOutput(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	return string(out), err
+}
+
+// IdleState asks the OS for the idle time and lock state
+func (d *commandIdleDetector) IdleState() (IdleState, error) {
+	switch d.goos {
+	case "darwin":
+		return d.darwinIdleState()
+	case "windows":
+		return d.windowsIdleState()
+	case "android", "ios":
+		return IdleState{}, ErrNoIdleDetection
+	default:
+		return d.linuxIdleState()
+	}
+}
+
+// linuxIdleState uses xprintidle for X11 idle time and logind's LockedHint
+// for the lock screen, which also works on Wayland. Either is enough.
+func (d *commandIdleDetector) linuxIdleState() (IdleState, error) {
+	var state IdleState
+	found := false
+
+	if path, err := d.lookPath("xprintidle"); err == nil {
+		out, err := d.run(path)
+		if err != nil {
+			return state, fmt.Errorf("xprintidle: %w", err)
+		}
+		millis, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+		if err != nil {
+			return state, fmt.Errorf("xprintidle: unexpected output %q", out)
+		}
+		state.Idle = time.Duration(millis) * time.Millisecond
+		found = true
+	}
+
+	if path, err := d.lookPath("loginctl"); err == nil && d.session != "" {
+		if out, err := d.run(path, "show-session", d.session, "-p", "LockedHint", "--value"); err == nil {
+			state.Locked = strings.TrimSpace(out) == "yes"
+			found = true
+		}
+	}
+
+	if !found {
+		return state, ErrNoIdleDetection
+	}
+	return state, nil
+}
+
+// darwinIdleState reads HIDIdleTime, in nanoseconds, from the HID system
+func (d *commandIdleDetector) darwinIdleState() (IdleState, error) {
+	out, err := d.run("ioreg", "-c", "IOHIDSystem", "-d", "4")
+	if err != nil {
+		return IdleState{}, fmt.Errorf("ioreg: %w", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.Contains(line, `"HIDIdleTime"`) {
+			continue
+		}
+		_, value, ok := strings.Cut(line, "=")
+		if !ok {
+			break
+		}
+		nanos, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			break
+		}
+		return IdleState{Idle: time.Duration(nanos)}, nil
+	}
+	return IdleState{}, ErrNoIdleDetection
+}
+
+// windowsIdleState runs windowsIdleScript
+func (d *commandIdleDetector) windowsIdleState() (IdleState, error) {
+	out, err := d.run("powershell", "-NoProfile", "-Command", windowsIdleScript)
+	if err != nil {
+		return IdleState{}, fmt.Errorf("powershell: %w", err)
+	}
+	lines := strings.Fields(out)
+	if len(lines) == 0 {
+		return IdleState{}, ErrNoIdleDetection
+	}
+	millis, err := strconv.ParseInt(lines[0], 10, 64)
+	if err != nil {
+		return IdleState{}, fmt.Errorf("unexpected idle time %q", lines[0])
+	}
+	return IdleState{
+		Idle:   time.Duration(millis) * time.Millisecond,
+		Locked: len(lines) > 1 && lines[1] == "locked",
+	}, nil
+}
@@ -0,0 +1,78 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeCommands returns canned output for the installed tools
+func fakeCommands(outputs map[string]string) (func(string) (string, error), commandRunner) {
+	lookPath := func(name string) (string, error) {
+		if _, ok := outputs[name]; ok {
+			return name, nil
+		}
+		return "", errors.New("not found")
+	}
+	run := func(name string, args ...string) (string, error) {
+		out, ok := outputs[name]
+		if !ok {
+			return "", errors.New("not found")
+		}
+		return out, nil
+	}
+	return lookPath, run
+}
+
+func TestLinuxIdleState(t *testing.T) {
+	lookPath, run := fakeCommands(map[string]string{"xprintidle": "90000\n", "loginctl": "yes\n"})
+	d := &commandIdleDetector{goos: "linux", run: run, lookPath: lookPath, session: "2"}
+
+	state, err := d.IdleState()
+	if err != nil {
+		t.Fatalf("IdleState failed: %v", err)
+	}
+	if state.Idle != 90*time.Second || !state.Locked {
+		t.Errorf("Expected 90s idle and locked, got %+v", state)
+	}
+
+	// Without a session only the idle time is known
+	d.session = ""
+	if state, _ := d.IdleState(); state.Locked {
+		t.Error("Lock state should not be read without a session")
+	}
+
+	lookPath, run = fakeCommands(nil)
+	d = &commandIdleDetector{goos: "linux", run: run, lookPath: lookPath}
+	if _, err := d.IdleState(); !errors.Is(err, ErrNoIdleDetection) {
+		t.Errorf("Expected ErrNoIdleDetection without tools, got %v", err)
+	}
+}
+
+func TestDarwinIdleState(t *testing.T) {
+	ioreg := `    | |   "HIDIdleTime" = 120000000000
+    | |   "HIDKeyboardModifierMappingPairs" = ()`
+	lookPath, run := fakeCommands(map[string]string{"ioreg": ioreg})
+	d := &commandIdleDetector{goos: "darwin", run: run, lookPath: lookPath}
+
+	state, err := d.IdleState()
+	if err != nil {
+		t.Fatalf("IdleState failed: %v", err)
+	}
+	if state.Idle != 2*time.Minute {
+		t.Errorf("Expected 2m idle, got %v", state.Idle)
+	}
+}
+
+func TestWindowsIdleState(t *testing.T) {
+	lookPath, run := fakeCommands(map[string]string{"powershell": "1500\r\nlocked\r\n"})
+	d := &commandIdleDetector{goos: "windows", run: run, lookPath: lookPath}
+
+	state, err := d.IdleState()
+	if err != nil {
+		t.Fatalf("IdleState failed: %v", err)
+	}
+	if state.Idle != 1500*time.Millisecond || !state.Locked {
+		t.Errorf("Expected 1.5s idle and locked, got %+v", state)
+	}
+}
@@ -0,0 +1,17 @@
+package native
+
+import "errors"
+
+// ErrAlwaysOnTopUnsupported is returned when the window manager offers no
+// way to keep a window above others. Callers should fall back to periodic
+// focus requests.
+var ErrAlwaysOnTopUnsupported = errors.New("always-on-top is not supported on this platform")
+
+// SetAlwaysOnTop keeps the native window above normal windows, or releases it.
+// On macOS this must run on the main thread, e.g. inside Fyne's RunNative callback.
+func SetAlwaysOnTop(win NativeWindow, enabled bool) error {
+	if win.Handle == 0 {
+		return ErrAlwaysOnTopUnsupported
+	}
+	return setNativeAlwaysOnTop(win, enabled)
+}
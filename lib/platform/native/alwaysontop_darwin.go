@@ -0,0 +1,31 @@
+//go:build darwin && cgo
+
+package native
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa
+#import <Cocoa/Cocoa.h>
+
+static void companion_set_floating(void *handle, int enabled) {
+	NSWindow *window = (__bridge NSWindow *)handle;
+	[window setLevel:(enabled ? NSFloatingWindowLevel : NSNormalWindowLevel)];
+}
+*/
+import "C"
+
+import "unsafe"
+
+// setNativeAlwaysOnTop switches the NSWindow between floating and normal level
+func setNativeAlwaysOnTop(win NativeWindow, enabled bool) error {
+	if win.Kind != NativeWindowCocoa {
+		return ErrAlwaysOnTopUnsupported
+	}
+
+	flag := C.int(0)
+	if enabled {
+		flag = 1
+	}
+	C.companion_set_floating(unsafe.Pointer(win.Handle), flag)
+	return nil
+}
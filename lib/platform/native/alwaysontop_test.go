@@ -0,0 +1,39 @@
+package native
+
+import "testing"
+
+func TestSetAlwaysOnTopRejectsUnsupportedWindows(t *testing.T) {
+	if err := SetAlwaysOnTop(NativeWindow{Kind: NativeWindowX11}, true); err != ErrAlwaysOnTopUnsupported {
+		t.Errorf("Expected ErrAlwaysOnTopUnsupported for missing handle, got %v", err)
+	}
+	if err := SetAlwaysOnTop(NativeWindow{Kind: NativeWindowWayland, Handle: 1}, true); err != ErrAlwaysOnTopUnsupported {
+		t.Errorf("Expected ErrAlwaysOnTopUnsupported for Wayland, got %v", err)
+	}
+}
+
+func TestWindowPositionRejectsUnsupportedWindows(t *testing.T) {
+	if _, _, err := WindowGeometry(NativeWindow{Kind: NativeWindowX11}); err != ErrPositionUnsupported {
+		t.Errorf("Expected ErrPositionUnsupported for missing handle, got %v", err)
+	}
+	if err := MoveWindow(NativeWindow{Kind: NativeWindowWayland, Handle: 1}, 10, 10); err != ErrPositionUnsupported {
+		t.Errorf("Expected ErrPositionUnsupported for Wayland, got %v", err)
+	}
+}
+
+func TestFocusedWindowFrameRejectsUnsupportedWindows(t *testing.T) {
+	if _, err := FocusedWindowFrame(NativeWindow{Kind: NativeWindowX11}); err != ErrPositionUnsupported {
+		t.Errorf("Expected ErrPositionUnsupported for missing handle, got %v", err)
+	}
+	if _, err := FocusedWindowFrame(NativeWindow{Kind: NativeWindowWayland, Handle: 1}); err != ErrPositionUnsupported {
+		t.Errorf("Expected ErrPositionUnsupported for Wayland, got %v", err)
+	}
+}
+
+func TestFocusedWindowFullscreenRejectsUnsupportedWindows(t *testing.T) {
+	if _, err := FocusedWindowFullscreen(NativeWindow{Kind: NativeWindowX11}); err != ErrPositionUnsupported {
+		t.Errorf("Expected ErrPositionUnsupported for missing handle, got %v", err)
+	}
+	if _, err := FocusedWindowFullscreen(NativeWindow{Kind: NativeWindowWayland, Handle: 1}); err != ErrPositionUnsupported {
+		t.Errorf("Expected ErrPositionUnsupported for Wayland, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package native
+
+import "fmt"
+
+var procSetWindowPos = user32.NewProc("SetWindowPos")
+
+// SetWindowPos arguments
+const (
+	hwndTopmost   = ^uintptr(0)     // HWND_TOPMOST (-1)
+	hwndNoTopmost = ^uintptr(0) - 1 // HWND_NOTOPMOST (-2)
+
+	swpNoSize     = 0x0001
+	swpNoMove     = 0x0002
+	swpNoActivate = 0x0010
+)
+
+// setNativeAlwaysOnTop moves the HWND into or out of the topmost band
+func setNativeAlwaysOnTop(win NativeWindow, enabled bool) error {
+	if win.Kind != NativeWindowWin32 {
+		return ErrAlwaysOnTopUnsupported
+	}
+
+	insertAfter := hwndNoTopmost
+	if enabled {
+		insertAfter = hwndTopmost
+	}
+
+	if ok, _, err := procSetWindowPos.Call(win.Handle, insertAfter, 0, 0, 0, 0, swpNoMove|swpNoSize|swpNoActivate); ok == 0 {
+		return fmt.Errorf("SetWindowPos failed: %w", err)
+	}
+	return nil
+}
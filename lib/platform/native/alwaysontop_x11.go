@@ -0,0 +1,55 @@
+//go:build linux && cgo && !android
+
+package native
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+#include <string.h>
+
+// companion_set_above asks the window manager to add or remove
+// _NET_WM_STATE_ABOVE, as described by the EWMH specification.
+static int companion_set_above(Window win, int enabled) {
+	Display *dpy = XOpenDisplay(NULL);
+	if (dpy == NULL) {
+		return 0;
+	}
+
+	XEvent event;
+	memset(&event, 0, sizeof(event));
+	event.xclient.type = ClientMessage;
+	event.xclient.window = win;
+	event.xclient.message_type = XInternAtom(dpy, "_NET_WM_STATE", False);
+	event.xclient.format = 32;
+	event.xclient.data.l[0] = enabled ? 1 : 0; // _NET_WM_STATE_ADD / _NET_WM_STATE_REMOVE
+	event.xclient.data.l[1] = XInternAtom(dpy, "_NET_WM_STATE_ABOVE", False);
+	event.xclient.data.l[2] = 0;
+	event.xclient.data.l[3] = 1; // Source indication: normal application
+
+	XSendEvent(dpy, DefaultRootWindow(dpy), False,
+		SubstructureRedirectMask | SubstructureNotifyMask, &event);
+	XFlush(dpy);
+	XCloseDisplay(dpy);
+	return 1;
+}
+*/
+import "C"
+
+import "fmt"
+
+// setNativeAlwaysOnTop sets _NET_WM_STATE_ABOVE on X11.
+// Wayland has no client-side equivalent.
+func setNativeAlwaysOnTop(win NativeWindow, enabled bool) error {
+	if win.Kind != NativeWindowX11 {
+		return ErrAlwaysOnTopUnsupported
+	}
+
+	flag := C.int(0)
+	if enabled {
+		flag = 1
+	}
+	if C.companion_set_above(C.Window(win.Handle), flag) == 0 {
+		return fmt.Errorf("failed to open X display: %w", ErrAlwaysOnTopUnsupported)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build !(linux && cgo && !android) && !windows && !(darwin && cgo)
+
+package native
+
+import "image"
+
+// enableNativeTransparency has no shim on this platform or build configuration
+// (e.g. CGO_ENABLED=0); the window stays rectangular.
+func enableNativeTransparency(win NativeWindow) (TransparentWindow, error) {
+	return nil, ErrTransparencyUnsupported
+}
+
+// setNativeAlwaysOnTop has no shim on this platform or build configuration
+func setNativeAlwaysOnTop(win NativeWindow, enabled bool) error {
+	return ErrAlwaysOnTopUnsupported
+}
+
+// nativeWindowGeometry has no shim on this platform or build configuration
+func nativeWindowGeometry(win NativeWindow) (frame, screen image.Rectangle, err error) {
+	return frame, screen, ErrPositionUnsupported
+}
+
+// moveNativeWindow has no shim on this platform or build configuration
+func moveNativeWindow(win NativeWindow, x, y int) error {
+	return ErrPositionUnsupported
+}
+
+// nativeFocusedWindowFrame has no shim on this platform or build configuration
+func nativeFocusedWindowFrame(self NativeWindow) (image.Rectangle, error) {
+	return image.Rectangle{}, ErrPositionUnsupported
+}
+
+// nativeFocusedWindowFullscreen has no shim on this platform or build configuration
+func nativeFocusedWindowFullscreen(self NativeWindow) (bool, error) {
+	return false, ErrPositionUnsupported
+}
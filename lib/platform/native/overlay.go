@@ -0,0 +1,66 @@
+package native
+
+import (
+	"errors"
+	"image"
+)
+
+// ErrOverlayUnsupported is returned when the session has no way to show a
+// separate always-above overlay surface (not Wayland, or the compositor
+// lacks wlr-layer-shell). Callers should keep the regular window.
+var ErrOverlayUnsupported = errors.New("overlay surfaces are not supported in this session")
+
+// PointerEventKind says what happened to the pointer over an overlay
+type PointerEventKind int
+
+const (
+	PointerEnter PointerEventKind = iota
+	PointerLeave
+	PointerMotion
+	PointerPress
+	PointerRelease
+)
+
+// PointerButton identifies the mouse button of a press or release
+type PointerButton int
+
+const (
+	ButtonNone PointerButton = iota
+	ButtonLeft
+	ButtonRight
+	ButtonMiddle
+)
+
+// PointerEvent is pointer input on an overlay. X and Y are in surface
+// pixels and hold the last known position for presses and releases.
+type PointerEvent struct {
+	Kind   PointerEventKind
+	X, Y   float64
+	Button PointerButton
+}
+
+// Overlay is a surface the compositor keeps above regular windows. It only
+// receives input inside its input region, so clicks elsewhere reach the
+// windows underneath.
+type Overlay interface {
+	// Present shows frame, with alpha, as the whole overlay. The overlay
+	// takes the frame's size.
+	Present(frame image.Image) error
+	// SetInputRegion limits pointer input to rects, in surface pixels.
+	// A nil slice accepts input everywhere, an empty one nowhere.
+	SetInputRegion(rects []image.Rectangle) error
+	// Move places the overlay's top-left corner at x, y on its output
+	Move(x, y int) error
+	// Events delivers pointer input. It is closed when the compositor
+	// drops the overlay or the connection ends.
+	Events() <-chan PointerEvent
+	// Close removes the overlay and releases its resources
+	Close() error
+}
+
+// OpenOverlay creates an overlay surface in the current session. namespace
+// names it for compositor rules (e.g. "desktop-companion").
+// Returns ErrOverlayUnsupported when no overlay protocol is available.
+func OpenOverlay(namespace string) (Overlay, error) {
+	return openNativeOverlay(namespace)
+}
@@ -0,0 +1,8 @@
+//go:build !linux || android
+
+package native
+
+// openNativeOverlay has no overlay protocol on this platform
+func openNativeOverlay(namespace string) (Overlay, error) {
+	return nil, ErrOverlayUnsupported
+}
@@ -0,0 +1,499 @@
+//go:build linux && !android
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Interfaces and opcodes from wayland.xml and wlr-layer-shell-unstable-v1.xml
+const (
+	wlCompositorCreateSurface = 0
+	wlCompositorCreateRegion  = 1
+
+	wlShmCreatePool         = 0
+	wlShmPoolCreateBuffer   = 0
+	wlShmPoolDestroy        = 1
+	wlShmFormatARGB8888     = 0
+	wlBufferDestroy         = 0
+	wlBufferReleaseEvent    = 0
+	wlRegionDestroy         = 0
+	wlRegionAdd             = 1
+	wlSurfaceDestroy        = 0
+	wlSurfaceAttach         = 1
+	wlSurfaceDamage         = 2
+	wlSurfaceSetInputRegion = 5
+	wlSurfaceCommit         = 6
+
+	wlSeatGetPointer        = 0
+	wlSeatCapabilitiesEvent = 0
+	wlSeatCapabilityPointer = 1
+	wlPointerEnterEvent     = 0
+	wlPointerLeaveEvent     = 1
+	wlPointerMotionEvent    = 2
+	wlPointerButtonEvent    = 3
+	wlPointerButtonPressed  = 1
+	linuxButtonLeft         = 0x110
+	linuxButtonRight        = 0x111
+	linuxButtonMiddle       = 0x112
+
+	layerShellGetLayerSurface          = 0
+	layerShellOverlay                  = 3
+	layerSurfaceSetSize                = 0
+	layerSurfaceSetAnchor              = 1
+	layerSurfaceSetExclusiveZone       = 2
+	layerSurfaceSetMargin              = 3
+	layerSurfaceSetKeyboardInteractive = 4
+	layerSurfaceAckConfigure           = 6
+	layerSurfaceDestroy                = 7
+	layerSurfaceConfigureEvent         = 0
+	layerSurfaceClosedEvent            = 1
+	layerSurfaceAnchorTopLeft          = 1 | 4
+)
+
+const (
+	overlayConfigureTimeout = 2 * time.Second
+	overlayMaxBuffers       = 3 // Double buffering, plus one while the compositor lags
+	overlayEventBuffer      = 64
+)
+
+// openNativeOverlay connects to the session's Wayland compositor
+func openNativeOverlay(namespace string) (Overlay, error) {
+	display := os.Getenv("WAYLAND_DISPLAY")
+	if display == "" {
+		return nil, ErrOverlayUnsupported
+	}
+	if !filepath.IsAbs(display) {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			return nil, ErrOverlayUnsupported
+		}
+		display = filepath.Join(runtimeDir, display)
+	}
+
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: display, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Wayland display: %w", err)
+	}
+	return newLayerOverlay(conn, namespace)
+}
+
+// layerOverlay is a wlr-layer-shell surface on the overlay layer, drawn from
+// shared-memory buffers
+type layerOverlay struct {
+	c            *wlConn
+	compositor   uint32
+	shm          uint32
+	surface      uint32
+	layerSurface uint32
+
+	mu         sync.Mutex
+	pointer    uint32
+	buffers    []*shmBuffer
+	width      int
+	height     int
+	configured chan struct{}
+	closed     bool
+
+	pointerX, pointerY float64 // Only touched by the reader
+	events             chan PointerEvent
+	closeOnce          sync.Once
+}
+
+// shmBuffer is a wl_buffer backed by memory shared with the compositor
+type shmBuffer struct {
+	id            uint32
+	data          []byte
+	width, height int
+	busy          bool // Until the compositor releases it
+}
+
+// newLayerOverlay creates the overlay surface over an open connection.
+// conn is closed on failure.
+func newLayerOverlay(conn *net.UnixConn, namespace string) (*layerOverlay, error) {
+	o := &layerOverlay{
+		c:          newWlConn(conn),
+		configured: make(chan struct{}),
+		events:     make(chan PointerEvent, overlayEventBuffer),
+	}
+	if err := o.init(namespace); err != nil {
+		o.c.close()
+		return nil, err
+	}
+	go func() {
+		<-o.c.done
+		close(o.events)
+	}()
+	return o, nil
+}
+
+// init binds the globals and creates the layer surface
+func (o *layerOverlay) init(namespace string) error {
+	registry, err := o.c.registry()
+	if err != nil {
+		return err
+	}
+	for _, iface := range []string{"wl_compositor", "wl_shm", "zwlr_layer_shell_v1"} {
+		if !registry.has(iface) {
+			return fmt.Errorf("%w: compositor has no %s", ErrOverlayUnsupported, iface)
+		}
+	}
+
+	if o.compositor, err = o.c.bind(registry, "wl_compositor", 1, nil); err != nil {
+		return err
+	}
+	if o.shm, err = o.c.bind(registry, "wl_shm", 1, nil); err != nil {
+		return err
+	}
+	layerShell, err := o.c.bind(registry, "zwlr_layer_shell_v1", 1, nil)
+	if err != nil {
+		return err
+	}
+	// Without a seat the overlay still shows, it just can't be clicked
+	if registry.has("wl_seat") {
+		seat := o.c.newID(nil)
+		o.c.handle(seat, func(opcode uint16, args *wlArgs) { o.handleSeat(seat, opcode, args) })
+		if err := o.c.bindID(registry, "wl_seat", 1, seat); err != nil {
+			return err
+		}
+	}
+
+	o.surface = o.c.newID(nil)
+	if err := o.c.request(o.compositor, wlCompositorCreateSurface, o.surface); err != nil {
+		return err
+	}
+	o.layerSurface = o.c.newID(nil)
+	o.c.handle(o.layerSurface, o.handleLayerSurface)
+	// A null output lets the compositor pick, usually the focused one
+	if err := o.c.request(layerShell, layerShellGetLayerSurface, o.layerSurface, o.surface, wlNull,
+		uint32(layerShellOverlay), namespace); err != nil {
+		return err
+	}
+
+	// Positioned by margins from the output's top-left corner, ignoring
+	// panels' exclusive zones, and never taking keyboard focus
+	requests := []struct {
+		opcode uint16
+		args   []any
+	}{
+		{layerSurfaceSetAnchor, []any{uint32(layerSurfaceAnchorTopLeft)}},
+		{layerSurfaceSetExclusiveZone, []any{int32(-1)}},
+		{layerSurfaceSetKeyboardInteractive, []any{uint32(0)}},
+		{layerSurfaceSetSize, []any{uint32(1), uint32(1)}},
+	}
+	for _, r := range requests {
+		if err := o.c.request(o.layerSurface, r.opcode, r.args...); err != nil {
+			return err
+		}
+	}
+	o.width, o.height = 1, 1
+
+	// The first commit, without a buffer, asks the compositor to configure us
+	if err := o.c.request(o.surface, wlSurfaceCommit); err != nil {
+		return err
+	}
+	select {
+	case <-o.configured:
+		return nil
+	case <-o.c.done:
+		return o.c.closedErr()
+	case <-time.After(overlayConfigureTimeout):
+		return errors.New("wayland: layer surface was never configured")
+	}
+}
+
+// handleLayerSurface acknowledges configures and notices the compositor
+// dropping the surface
+func (o *layerOverlay) handleLayerSurface(opcode uint16, args *wlArgs) {
+	switch opcode {
+	case layerSurfaceConfigureEvent:
+		serial := args.uint()
+		o.c.request(o.layerSurface, layerSurfaceAckConfigure, serial)
+		o.mu.Lock()
+		select {
+		case <-o.configured:
+		default:
+			close(o.configured)
+		}
+		o.mu.Unlock()
+	case layerSurfaceClosedEvent:
+		o.c.fail(errors.New("wayland: compositor closed the overlay"))
+	}
+}
+
+// handleSeat asks for pointer input once the seat has a pointer
+func (o *layerOverlay) handleSeat(seat uint32, opcode uint16, args *wlArgs) {
+	if opcode != wlSeatCapabilitiesEvent || args.uint()&wlSeatCapabilityPointer == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.pointer != 0 {
+		return // Capabilities are resent whenever they change
+	}
+	o.pointer = o.c.newID(o.handlePointer)
+	o.c.request(seat, wlSeatGetPointer, o.pointer)
+}
+
+// handlePointer turns pointer events on our surface into PointerEvents
+func (o *layerOverlay) handlePointer(opcode uint16, args *wlArgs) {
+	event := PointerEvent{}
+	switch opcode {
+	case wlPointerEnterEvent:
+		args.uint() // Serial
+		if args.uint() != o.surface {
+			return
+		}
+		event.Kind = PointerEnter
+		o.pointerX, o.pointerY = args.fixed(), args.fixed()
+	case wlPointerLeaveEvent:
+		event.Kind = PointerLeave
+	case wlPointerMotionEvent:
+		args.uint() // Time
+		event.Kind = PointerMotion
+		o.pointerX, o.pointerY = args.fixed(), args.fixed()
+	case wlPointerButtonEvent:
+		args.uint() // Serial
+		args.uint() // Time
+		button, state := args.uint(), args.uint()
+		event.Kind = PointerRelease
+		if state == wlPointerButtonPressed {
+			event.Kind = PointerPress
+		}
+		switch button {
+		case linuxButtonLeft:
+			event.Button = ButtonLeft
+		case linuxButtonRight:
+			event.Button = ButtonRight
+		case linuxButtonMiddle:
+			event.Button = ButtonMiddle
+		default:
+			return
+		}
+	default:
+		return // Axis and frame events
+	}
+	if args.err != nil {
+		return
+	}
+	event.X, event.Y = o.pointerX, o.pointerY
+
+	// Never block the reader on a slow consumer
+	select {
+	case o.events <- event:
+	default:
+	}
+}
+
+// Present copies frame into a free buffer and shows it
+func (o *layerOverlay) Present(frame image.Image) error {
+	if frame == nil {
+		return errors.New("no frame to present")
+	}
+	bounds := frame.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 {
+		return errors.New("frame is empty")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return errors.New("overlay is closed")
+	}
+
+	if width != o.width || height != o.height {
+		if err := o.c.request(o.layerSurface, layerSurfaceSetSize, uint32(width), uint32(height)); err != nil {
+			return err
+		}
+		o.width, o.height = width, height
+	}
+
+	buffer, err := o.freeBuffer(width, height)
+	if err != nil || buffer == nil {
+		return err // A nil buffer means the compositor is behind; skip the frame
+	}
+
+	dst := &image.RGBA{Pix: buffer.data, Stride: width * 4, Rect: image.Rect(0, 0, width, height)}
+	draw.Draw(dst, dst.Rect, frame, bounds.Min, draw.Src)
+	// image.RGBA is premultiplied R,G,B,A; little-endian ARGB8888 is B,G,R,A
+	for i := 0; i < len(dst.Pix); i += 4 {
+		dst.Pix[i], dst.Pix[i+2] = dst.Pix[i+2], dst.Pix[i]
+	}
+
+	buffer.busy = true
+	if err := o.c.request(o.surface, wlSurfaceAttach, buffer.id, int32(0), int32(0)); err != nil {
+		return err
+	}
+	if err := o.c.request(o.surface, wlSurfaceDamage, int32(0), int32(0), int32(width), int32(height)); err != nil {
+		return err
+	}
+	return o.c.request(o.surface, wlSurfaceCommit)
+}
+
+// freeBuffer returns a buffer of the given size the compositor isn't
+// reading, creating one if needed. Buffers of other sizes are dropped.
+// Returns nil when every buffer is busy. Called with o.mu held.
+func (o *layerOverlay) freeBuffer(width, height int) (*shmBuffer, error) {
+	kept := o.buffers[:0]
+	for _, b := range o.buffers {
+		if b.width == width && b.height == height {
+			kept = append(kept, b)
+			continue
+		}
+		o.destroyBuffer(b)
+	}
+	o.buffers = kept
+
+	for _, b := range o.buffers {
+		if !b.busy {
+			return b, nil
+		}
+	}
+	if len(o.buffers) >= overlayMaxBuffers {
+		return nil, nil
+	}
+
+	b, err := o.createBuffer(width, height)
+	if err != nil {
+		return nil, err
+	}
+	o.buffers = append(o.buffers, b)
+	return b, nil
+}
+
+// createBuffer allocates a shared-memory buffer. The backing file is
+// unlinked at once, so only the mapping and the compositor keep it alive.
+func (o *layerOverlay) createBuffer(width, height int) (*shmBuffer, error) {
+	size := width * height * 4
+	file, err := os.CreateTemp(os.Getenv("XDG_RUNTIME_DIR"), "companion-shm-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create overlay buffer: %w", err)
+	}
+	defer file.Close()
+	os.Remove(file.Name())
+	if err := file.Truncate(int64(size)); err != nil {
+		return nil, fmt.Errorf("failed to size overlay buffer: %w", err)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map overlay buffer: %w", err)
+	}
+
+	b := &shmBuffer{data: data, width: width, height: height}
+	pool := o.c.newID(nil)
+	b.id = o.c.newID(func(opcode uint16, args *wlArgs) {
+		if opcode == wlBufferReleaseEvent {
+			o.mu.Lock()
+			b.busy = false
+			o.mu.Unlock()
+		}
+	})
+	err = o.c.request(o.shm, wlShmCreatePool, pool, wlFD(file.Fd()), int32(size))
+	if err == nil {
+		err = o.c.request(pool, wlShmPoolCreateBuffer, b.id, int32(0), int32(width), int32(height),
+			int32(width*4), uint32(wlShmFormatARGB8888))
+	}
+	if err == nil {
+		// The buffer keeps the pool's memory alive on the compositor's side
+		err = o.c.request(pool, wlShmPoolDestroy)
+	}
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+	return b, nil
+}
+
+// destroyBuffer releases a buffer. The compositor keeps its own mapping
+// for as long as it still shows it.
+func (o *layerOverlay) destroyBuffer(b *shmBuffer) {
+	o.c.request(b.id, wlBufferDestroy)
+	o.c.forget(b.id)
+	syscall.Munmap(b.data)
+}
+
+// SetInputRegion limits where the overlay takes pointer input
+func (o *layerOverlay) SetInputRegion(rects []image.Rectangle) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return errors.New("overlay is closed")
+	}
+
+	if rects == nil {
+		if err := o.c.request(o.surface, wlSurfaceSetInputRegion, wlNull); err != nil {
+			return err
+		}
+		return o.c.request(o.surface, wlSurfaceCommit)
+	}
+
+	region := o.c.newID(nil)
+	if err := o.c.request(o.compositor, wlCompositorCreateRegion, region); err != nil {
+		return err
+	}
+	for _, r := range rects {
+		if err := o.c.request(region, wlRegionAdd, int32(r.Min.X), int32(r.Min.Y), int32(r.Dx()), int32(r.Dy())); err != nil {
+			return err
+		}
+	}
+	// The surface copies the region, so it can go right away
+	if err := o.c.request(o.surface, wlSurfaceSetInputRegion, region); err != nil {
+		return err
+	}
+	if err := o.c.request(region, wlRegionDestroy); err != nil {
+		return err
+	}
+	return o.c.request(o.surface, wlSurfaceCommit)
+}
+
+// Move sets the margins from the output's top-left corner
+func (o *layerOverlay) Move(x, y int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return errors.New("overlay is closed")
+	}
+
+	// Margins are top, right, bottom, left
+	if err := o.c.request(o.layerSurface, layerSurfaceSetMargin, int32(y), int32(0), int32(0), int32(x)); err != nil {
+		return err
+	}
+	return o.c.request(o.surface, wlSurfaceCommit)
+}
+
+// Events delivers pointer input on the overlay
+func (o *layerOverlay) Events() <-chan PointerEvent {
+	return o.events
+}
+
+// Close destroys the surface and disconnects
+func (o *layerOverlay) Close() error {
+	var err error
+	o.closeOnce.Do(func() {
+		o.mu.Lock()
+		o.closed = true
+		for _, b := range o.buffers {
+			o.destroyBuffer(b)
+		}
+		o.buffers = nil
+		o.c.request(o.layerSurface, layerSurfaceDestroy)
+		o.c.request(o.surface, wlSurfaceDestroy)
+		o.mu.Unlock()
+
+		// Let the compositor process the destroys before hanging up
+		o.c.roundtrip()
+		err = o.c.close()
+	})
+	return err
+}
@@ -0,0 +1,352 @@
+//go:build linux && !android
+
+package native
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeCompositor speaks just enough Wayland to host a layer surface and
+// records what the client asked for
+type fakeCompositor struct {
+	t    *testing.T
+	conn *net.UnixConn
+
+	mu          sync.Mutex
+	globals     []string
+	objects     map[uint32]string // ID -> interface
+	regions     map[uint32][]image.Rectangle
+	buffers     map[uint32][]byte
+	maps        [][]byte
+	fds         []int
+	surface     uint32
+	pointer     uint32
+	configured  bool
+	acked       bool
+	attached    uint32
+	layer       uint32
+	namespace   string
+	anchor      uint32
+	margin      [4]int32
+	size        [2]uint32
+	inputRegion []image.Rectangle
+	inputSet    bool
+	frames      int
+	pixel       []byte
+}
+
+// newFakeCompositor serves the given globals and returns the client end
+func newFakeCompositor(t *testing.T, globals ...string) (*fakeCompositor, *net.UnixConn) {
+	t.Helper()
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		t.Fatalf("socketpair failed: %v", err)
+	}
+	ends := make([]*net.UnixConn, 2)
+	for i, fd := range fds {
+		file := os.NewFile(uintptr(fd), "wayland")
+		conn, err := net.FileConn(file)
+		file.Close()
+		if err != nil {
+			t.Fatalf("FileConn failed: %v", err)
+		}
+		ends[i] = conn.(*net.UnixConn)
+	}
+
+	f := &fakeCompositor{
+		t:       t,
+		conn:    ends[0],
+		globals: globals,
+		objects: map[uint32]string{wlDisplayID: "wl_display"},
+		regions: make(map[uint32][]image.Rectangle),
+		buffers: make(map[uint32][]byte),
+	}
+	go f.serve()
+	t.Cleanup(func() {
+		f.conn.Close()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, m := range f.maps {
+			syscall.Munmap(m)
+		}
+	})
+	return f, ends[1]
+}
+
+// serve reads requests until the client hangs up
+func (f *fakeCompositor) serve() {
+	var buf []byte
+	chunk := make([]byte, wlMaxMessageSize)
+	oob := make([]byte, syscall.CmsgSpace(28*4))
+	for {
+		n, oobn, _, _, err := f.conn.ReadMsgUnix(chunk, oob)
+		if err != nil || n == 0 {
+			return
+		}
+		messages, _ := syscall.ParseSocketControlMessage(oob[:oobn])
+		for _, m := range messages {
+			fds, _ := syscall.ParseUnixRights(&m)
+			f.fds = append(f.fds, fds...)
+		}
+		buf = append(buf, chunk[:n]...)
+
+		for len(buf) >= wlHeaderSize {
+			size := int(binary.NativeEndian.Uint32(buf[4:]) >> 16)
+			if len(buf) < size {
+				break
+			}
+			object := binary.NativeEndian.Uint32(buf)
+			opcode := uint16(binary.NativeEndian.Uint32(buf[4:]))
+			f.handle(object, opcode, &wlArgs{data: buf[wlHeaderSize:size]})
+			buf = buf[size:]
+		}
+	}
+}
+
+// event sends an event to the client
+func (f *fakeCompositor) event(object uint32, opcode uint16, args ...any) {
+	msg, _, err := wlMarshal(object, opcode, args...)
+	if err == nil {
+		_, err = f.conn.Write(msg)
+	}
+	if err != nil {
+		f.t.Errorf("Failed to send event: %v", err)
+	}
+}
+
+// handle applies one request
+func (f *fakeCompositor) handle(object uint32, opcode uint16, args *wlArgs) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch iface := f.objects[object]; {
+	case iface == "wl_display" && opcode == 0: // sync
+		f.event(args.uint(), 0, uint32(0))
+	case iface == "wl_display" && opcode == 1: // get_registry
+		registry := args.uint()
+		f.objects[registry] = "wl_registry"
+		for i, name := range f.globals {
+			f.event(registry, 0, uint32(i+1), name, uint32(1))
+		}
+	case iface == "wl_registry": // bind
+		args.uint()
+		name := args.string()
+		args.uint()
+		id := args.uint()
+		f.objects[id] = name
+		if name == "wl_seat" {
+			f.event(id, wlSeatCapabilitiesEvent, uint32(wlSeatCapabilityPointer))
+		}
+	case iface == "wl_compositor" && opcode == wlCompositorCreateSurface:
+		f.surface = args.uint()
+		f.objects[f.surface] = "wl_surface"
+	case iface == "wl_compositor" && opcode == wlCompositorCreateRegion:
+		f.objects[args.uint()] = "wl_region"
+	case iface == "wl_region" && opcode == wlRegionAdd:
+		x, y, w, h := args.int(), args.int(), args.int(), args.int()
+		f.regions[object] = append(f.regions[object], image.Rect(int(x), int(y), int(x+w), int(y+h)))
+	case iface == "wl_shm" && opcode == wlShmCreatePool:
+		pool, size := args.uint(), int(args.int())
+		fd := f.fds[0]
+		f.fds = f.fds[1:]
+		data, err := syscall.Mmap(fd, 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+		syscall.Close(fd)
+		if err != nil {
+			f.t.Errorf("Failed to map pool: %v", err)
+			return
+		}
+		f.maps = append(f.maps, data)
+		f.objects[pool] = "wl_shm_pool"
+		f.buffers[pool] = data
+	case iface == "wl_shm_pool" && opcode == wlShmPoolCreateBuffer:
+		id, offset := args.uint(), args.int()
+		args.int()
+		height, stride := args.int(), args.int()
+		f.objects[id] = "wl_buffer"
+		f.buffers[id] = f.buffers[object][offset : offset+height*stride]
+	case iface == "zwlr_layer_shell_v1" && opcode == layerShellGetLayerSurface:
+		id := args.uint()
+		args.uint()
+		args.uint()
+		f.layer, f.namespace = args.uint(), args.string()
+		f.objects[id] = "zwlr_layer_surface_v1"
+	case iface == "zwlr_layer_surface_v1":
+		switch opcode {
+		case layerSurfaceSetSize:
+			f.size = [2]uint32{args.uint(), args.uint()}
+		case layerSurfaceSetAnchor:
+			f.anchor = args.uint()
+		case layerSurfaceSetMargin:
+			f.margin = [4]int32{args.int(), args.int(), args.int(), args.int()}
+		case layerSurfaceAckConfigure:
+			f.acked = args.uint() == 7
+		}
+	case iface == "wl_surface" && opcode == wlSurfaceAttach:
+		f.attached = args.uint()
+	case iface == "wl_surface" && opcode == wlSurfaceSetInputRegion:
+		region := args.uint()
+		f.inputSet = true
+		f.inputRegion = slices.Clone(f.regions[region])
+		if region != wlNull && f.inputRegion == nil {
+			f.inputRegion = []image.Rectangle{}
+		}
+	case iface == "wl_surface" && opcode == wlSurfaceCommit:
+		if !f.configured {
+			f.configured = true
+			layerSurface := f.find("zwlr_layer_surface_v1")
+			f.event(layerSurface, layerSurfaceConfigureEvent, uint32(7), f.size[0], f.size[1])
+			return
+		}
+		if f.attached != 0 {
+			f.frames++
+			f.pixel = slices.Clone(f.buffers[f.attached][:4])
+			f.event(f.attached, wlBufferReleaseEvent)
+			f.attached = 0
+		}
+	case iface == "wl_seat" && opcode == wlSeatGetPointer:
+		f.pointer = args.uint()
+		f.objects[f.pointer] = "wl_pointer"
+	}
+}
+
+// find returns the object implementing iface
+func (f *fakeCompositor) find(iface string) uint32 {
+	for id, name := range f.objects {
+		if name == iface {
+			return id
+		}
+	}
+	return 0
+}
+
+// waitFor polls the recorded state until check passes
+func (f *fakeCompositor) waitFor(what string, check func() bool) {
+	f.t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		f.mu.Lock()
+		ok := check()
+		f.mu.Unlock()
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			f.t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func nextEvent(t *testing.T, events <-chan PointerEvent) PointerEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a pointer event")
+		return PointerEvent{}
+	}
+}
+
+func TestLayerOverlay(t *testing.T) {
+	f, client := newFakeCompositor(t, "wl_compositor", "wl_shm", "wl_seat", "zwlr_layer_shell_v1")
+	overlay, err := newLayerOverlay(client, "desktop-companion")
+	if err != nil {
+		t.Fatalf("newLayerOverlay failed: %v", err)
+	}
+
+	f.waitFor("the configure to be acknowledged", func() bool { return f.acked })
+	f.mu.Lock()
+	if f.layer != layerShellOverlay || f.namespace != "desktop-companion" || f.anchor != layerSurfaceAnchorTopLeft {
+		t.Errorf("Expected a top-left overlay layer surface, got layer %d, namespace %q, anchor %d",
+			f.layer, f.namespace, f.anchor)
+	}
+	f.mu.Unlock()
+
+	// Premultiplied and stored as B, G, R, A
+	frame := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	frame.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 128})
+	if err := overlay.Present(frame); err != nil {
+		t.Fatalf("Present failed: %v", err)
+	}
+	f.waitFor("a frame", func() bool { return f.frames == 1 })
+	f.mu.Lock()
+	if !slices.Equal(f.pixel, []byte{0, 0, 128, 128}) || f.size != [2]uint32{4, 2} {
+		t.Errorf("Expected a 4x2 surface starting with BGRA 0,0,128,128, got %v %v", f.size, f.pixel)
+	}
+	f.mu.Unlock()
+
+	// The released buffer is reused
+	if err := overlay.Present(frame); err != nil {
+		t.Fatalf("Present failed: %v", err)
+	}
+	f.waitFor("a second frame", func() bool { return f.frames == 2 })
+	overlay.mu.Lock()
+	if n := len(overlay.buffers); n != 1 {
+		t.Errorf("Expected the released buffer reused, have %d buffers", n)
+	}
+	overlay.mu.Unlock()
+
+	sprite := []image.Rectangle{image.Rect(1, 0, 3, 2)}
+	if err := overlay.SetInputRegion(sprite); err != nil {
+		t.Fatalf("SetInputRegion failed: %v", err)
+	}
+	f.waitFor("the input region", func() bool { return f.inputSet })
+	f.mu.Lock()
+	if !slices.Equal(f.inputRegion, sprite) {
+		t.Errorf("Expected input only over the sprite, got %v", f.inputRegion)
+	}
+	f.mu.Unlock()
+
+	if err := overlay.Move(10, 20); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	f.waitFor("the margins", func() bool { return f.margin == [4]int32{20, 0, 0, 10} })
+
+	f.waitFor("a pointer", func() bool { return f.pointer != 0 })
+	f.mu.Lock()
+	f.event(f.pointer, wlPointerEnterEvent, uint32(1), f.surface, int32(2.5*256), int32(1*256))
+	f.event(f.pointer, wlPointerButtonEvent, uint32(2), uint32(0), uint32(linuxButtonRight), uint32(wlPointerButtonPressed))
+	f.mu.Unlock()
+
+	if event := nextEvent(t, overlay.Events()); event != (PointerEvent{Kind: PointerEnter, X: 2.5, Y: 1}) {
+		t.Errorf("Expected the pointer entering at 2.5,1, got %+v", event)
+	}
+	if event := nextEvent(t, overlay.Events()); event != (PointerEvent{Kind: PointerPress, X: 2.5, Y: 1, Button: ButtonRight}) {
+		t.Errorf("Expected a right press at 2.5,1, got %+v", event)
+	}
+
+	if err := overlay.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if _, ok := <-overlay.Events(); ok {
+		t.Error("Expected events to end once closed")
+	}
+	if err := overlay.Present(frame); err == nil {
+		t.Error("Expected presenting on a closed overlay to fail")
+	}
+}
+
+func TestLayerOverlayNeedsLayerShell(t *testing.T) {
+	_, client := newFakeCompositor(t, "wl_compositor", "wl_shm", "wl_seat")
+	if _, err := newLayerOverlay(client, "desktop-companion"); !errors.Is(err, ErrOverlayUnsupported) {
+		t.Errorf("Expected ErrOverlayUnsupported without wlr-layer-shell, got %v", err)
+	}
+}
+
+func TestOpenOverlayOutsideWayland(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "")
+	if _, err := OpenOverlay("desktop-companion"); !errors.Is(err, ErrOverlayUnsupported) {
+		t.Errorf("Expected ErrOverlayUnsupported outside Wayland, got %v", err)
+	}
+}
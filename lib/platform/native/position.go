@@ -0,0 +1,54 @@
+package native
+
+import (
+	"errors"
+	"image"
+)
+
+// ErrPositionUnsupported is returned when the windowing system doesn't let
+// applications read or set their window position, e.g. Wayland
+var ErrPositionUnsupported = errors.New("window positioning is not supported on this platform")
+
+// ErrNoFocusedWindow is returned when no other application's window has
+// the focus, e.g. the desktop is focused or every window is minimized
+var ErrNoFocusedWindow = errors.New("no other window is focused")
+
+// WindowGeometry returns the window's frame and the usable area of the
+// screen it is on, both in screen coordinates with the origin at the top left
+func WindowGeometry(win NativeWindow) (frame, screen image.Rectangle, err error) {
+	if win.Handle == 0 {
+		return image.Rectangle{}, image.Rectangle{}, ErrPositionUnsupported
+	}
+	return nativeWindowGeometry(win)
+}
+
+// MoveWindow puts the window's top-left corner at (x, y) in screen
+// coordinates. Windows may be placed partly off-screen, though some window
+// managers pull them back. On macOS this must run on the main thread.
+func MoveWindow(win NativeWindow, x, y int) error {
+	if win.Handle == 0 {
+		return ErrPositionUnsupported
+	}
+	return moveNativeWindow(win, x, y)
+}
+
+// FocusedWindowFrame returns the frame of the focused window, title bar
+// included, in the same coordinates as WindowGeometry. self is the caller's
+// own window, which never counts as focused.
+func FocusedWindowFrame(self NativeWindow) (image.Rectangle, error) {
+	if self.Handle == 0 {
+		return image.Rectangle{}, ErrPositionUnsupported
+	}
+	return nativeFocusedWindowFrame(self)
+}
+
+// FocusedWindowFullscreen reports whether another application's focused
+// window is fullscreen, such as a game or a video player. self is the
+// caller's own window, which never counts; with nothing else focused the
+// answer is false.
+func FocusedWindowFullscreen(self NativeWindow) (bool, error) {
+	if self.Handle == 0 {
+		return false, ErrPositionUnsupported
+	}
+	return nativeFocusedWindowFullscreen(self)
+}
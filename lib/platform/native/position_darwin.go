@@ -0,0 +1,158 @@
+//go:build darwin && cgo
+
+package native
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa
+#import <Cocoa/Cocoa.h>
+
+// Cocoa measures from the bottom left of the main screen; these helpers
+// flip to top-left coordinates so every platform looks the same to Go.
+
+static CGFloat companion_main_height(void) {
+	return [[[NSScreen screens] objectAtIndex:0] frame].size.height;
+}
+
+static void companion_geometry(void *handle, double *rect, double *screen) {
+	NSWindow *window = (__bridge NSWindow *)handle;
+	CGFloat top = companion_main_height();
+
+	NSRect frame = [window frame];
+	rect[0] = frame.origin.x;
+	rect[1] = top - frame.origin.y - frame.size.height;
+	rect[2] = frame.size.width;
+	rect[3] = frame.size.height;
+
+	NSScreen *current = [window screen];
+	if (current == nil) {
+		current = [NSScreen mainScreen];
+	}
+	NSRect visible = [current visibleFrame];
+	screen[0] = visible.origin.x;
+	screen[1] = top - visible.origin.y - visible.size.height;
+	screen[2] = visible.size.width;
+	screen[3] = visible.size.height;
+}
+
+static void companion_move(void *handle, double x, double y) {
+	NSWindow *window = (__bridge NSWindow *)handle;
+	[window setFrameTopLeftPoint:NSMakePoint(x, companion_main_height() - y)];
+}
+
+// companion_focused_frame finds the frontmost window of the active
+// application other than this one. CGWindow bounds are already measured
+// from the top left. Returns 0 when there is none.
+static int companion_focused_frame(double *rect) {
+	NSRunningApplication *front = [[NSWorkspace sharedWorkspace] frontmostApplication];
+	pid_t own = [[NSProcessInfo processInfo] processIdentifier];
+	if (front == nil || [front processIdentifier] == own) {
+		return 0;
+	}
+	pid_t pid = [front processIdentifier];
+
+	CFArrayRef windows = CGWindowListCopyWindowInfo(
+		kCGWindowListOptionOnScreenOnly | kCGWindowListExcludeDesktopElements, kCGNullWindowID);
+	if (windows == NULL) {
+		return 0;
+	}
+
+	int found = 0;
+	// The list is ordered front to back
+	for (NSDictionary *info in (__bridge NSArray *)windows) {
+		if ([info[(id)kCGWindowOwnerPID] intValue] != pid || [info[(id)kCGWindowLayer] intValue] != 0) {
+			continue;
+		}
+		CGRect bounds;
+		if (!CGRectMakeWithDictionaryRepresentation((__bridge CFDictionaryRef)info[(id)kCGWindowBounds], &bounds)) {
+			continue;
+		}
+		rect[0] = bounds.origin.x;
+		rect[1] = bounds.origin.y;
+		rect[2] = bounds.size.width;
+		rect[3] = bounds.size.height;
+		found = 1;
+		break;
+	}
+	CFRelease(windows);
+	return found;
+}
+
+// companion_focused_fullscreen checks whether the front window of the
+// frontmost application covers a whole screen, menu bar included, as
+// fullscreen apps and games do. Returns 0 when it doesn't.
+static int companion_focused_fullscreen(void) {
+	double rect[4];
+	if (!companion_focused_frame(rect)) {
+		return 0;
+	}
+
+	CGFloat top = companion_main_height();
+	for (NSScreen *screen in [NSScreen screens]) {
+		NSRect frame = [screen frame];
+		double x = frame.origin.x;
+		double y = top - frame.origin.y - frame.size.height;
+		if (rect[0] <= x && rect[1] <= y &&
+				rect[0] + rect[2] >= x + frame.size.width && rect[1] + rect[3] >= y + frame.size.height) {
+			return 1;
+		}
+	}
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"image"
+	"unsafe"
+)
+
+// nativeWindowGeometry reads the NSWindow frame and the visible frame of its
+// screen, which leaves out the menu bar and Dock
+func nativeWindowGeometry(win NativeWindow) (frame, screen image.Rectangle, err error) {
+	if win.Kind != NativeWindowCocoa {
+		return frame, screen, ErrPositionUnsupported
+	}
+
+	var rect, visible [4]C.double
+	C.companion_geometry(unsafe.Pointer(win.Handle), &rect[0], &visible[0])
+	return cocoaRect(rect), cocoaRect(visible), nil
+}
+
+// cocoaRect converts x, y, width, height to an image.Rectangle
+func cocoaRect(r [4]C.double) image.Rectangle {
+	x, y := int(r[0]), int(r[1])
+	return image.Rect(x, y, x+int(r[2]), y+int(r[3]))
+}
+
+// moveNativeWindow moves the NSWindow by its top-left corner
+func moveNativeWindow(win NativeWindow, x, y int) error {
+	if win.Kind != NativeWindowCocoa {
+		return ErrPositionUnsupported
+	}
+	C.companion_move(unsafe.Pointer(win.Handle), C.double(x), C.double(y))
+	return nil
+}
+
+// nativeFocusedWindowFrame reads the front window of the frontmost
+// application from the window server
+func nativeFocusedWindowFrame(self NativeWindow) (image.Rectangle, error) {
+	if self.Kind != NativeWindowCocoa {
+		return image.Rectangle{}, ErrPositionUnsupported
+	}
+
+	var rect [4]C.double
+	if C.companion_focused_frame(&rect[0]) == 0 {
+		return image.Rectangle{}, ErrNoFocusedWindow
+	}
+	return cocoaRect(rect), nil
+}
+
+// nativeFocusedWindowFullscreen compares the frontmost application's front
+// window with the screens
+func nativeFocusedWindowFullscreen(self NativeWindow) (bool, error) {
+	if self.Kind != NativeWindowCocoa {
+		return false, ErrPositionUnsupported
+	}
+	return C.companion_focused_fullscreen() != 0, nil
+}
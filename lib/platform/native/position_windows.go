@@ -0,0 +1,147 @@
+//go:build windows
+
+package native
+
+import (
+	"fmt"
+	"image"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procGetWindowRect     = user32.NewProc("GetWindowRect")
+	procMonitorFromWindow = user32.NewProc("MonitorFromWindow")
+	procGetMonitorInfo    = user32.NewProc("GetMonitorInfoW")
+	procGetForeground     = user32.NewProc("GetForegroundWindow")
+	procIsIconic          = user32.NewProc("IsIconic")
+	procIsWindowVisible   = user32.NewProc("IsWindowVisible")
+	procGetClassName      = user32.NewProc("GetClassNameW")
+
+	dwmapi                    = syscall.NewLazyDLL("dwmapi.dll")
+	procDwmGetWindowAttribute = dwmapi.NewProc("DwmGetWindowAttribute")
+)
+
+const (
+	swpNoZOrder             = 0x0004
+	monitorDefaultToNearest = 2 // MONITOR_DEFAULTTONEAREST
+	dwmExtendedFrameBounds  = 9 // DWMWA_EXTENDED_FRAME_BOUNDS
+)
+
+// winRect mirrors RECT
+type winRect struct {
+	Left, Top, Right, Bottom int32
+}
+
+// monitorInfo mirrors MONITORINFO
+type monitorInfo struct {
+	Size    uint32
+	Monitor winRect
+	Work    winRect
+	Flags   uint32
+}
+
+// rectangle converts a RECT to an image.Rectangle
+func (r winRect) rectangle() image.Rectangle {
+	return image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom))
+}
+
+// nativeWindowGeometry reads the window rectangle and the work area of its
+// monitor, which leaves out the taskbar
+func nativeWindowGeometry(win NativeWindow) (frame, screen image.Rectangle, err error) {
+	if win.Kind != NativeWindowWin32 {
+		return frame, screen, ErrPositionUnsupported
+	}
+
+	var rect winRect
+	if ok, _, err := procGetWindowRect.Call(win.Handle, uintptr(unsafe.Pointer(&rect))); ok == 0 {
+		return frame, screen, fmt.Errorf("GetWindowRect failed: %w", err)
+	}
+
+	monitor, _, _ := procMonitorFromWindow.Call(win.Handle, monitorDefaultToNearest)
+	info := monitorInfo{Size: uint32(unsafe.Sizeof(monitorInfo{}))}
+	if ok, _, err := procGetMonitorInfo.Call(monitor, uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return frame, screen, fmt.Errorf("GetMonitorInfo failed: %w", err)
+	}
+	return rect.rectangle(), info.Work.rectangle(), nil
+}
+
+// moveNativeWindow moves the HWND without resizing or raising it
+func moveNativeWindow(win NativeWindow, x, y int) error {
+	if win.Kind != NativeWindowWin32 {
+		return ErrPositionUnsupported
+	}
+	if ok, _, err := procSetWindowPos.Call(win.Handle, 0, uintptr(int32(x)), uintptr(int32(y)), 0, 0, swpNoSize|swpNoZOrder|swpNoActivate); ok == 0 {
+		return fmt.Errorf("SetWindowPos failed: %w", err)
+	}
+	return nil
+}
+
+// nativeFocusedWindowFrame reads the foreground window's rectangle. The DWM
+// bounds leave out the invisible resize borders GetWindowRect includes, so
+// the top edge is where the title bar is drawn.
+func nativeFocusedWindowFrame(self NativeWindow) (image.Rectangle, error) {
+	if self.Kind != NativeWindowWin32 {
+		return image.Rectangle{}, ErrPositionUnsupported
+	}
+
+	hwnd, _, _ := procGetForeground.Call()
+	if hwnd == 0 || hwnd == self.Handle {
+		return image.Rectangle{}, ErrNoFocusedWindow
+	}
+	if minimized, _, _ := procIsIconic.Call(hwnd); minimized != 0 {
+		return image.Rectangle{}, ErrNoFocusedWindow
+	}
+	if visible, _, _ := procIsWindowVisible.Call(hwnd); visible == 0 {
+		return image.Rectangle{}, ErrNoFocusedWindow
+	}
+
+	var rect winRect
+	if procDwmGetWindowAttribute.Find() == nil {
+		hr, _, _ := procDwmGetWindowAttribute.Call(hwnd, dwmExtendedFrameBounds, uintptr(unsafe.Pointer(&rect)), unsafe.Sizeof(rect))
+		if hr == 0 {
+			return rect.rectangle(), nil
+		}
+	}
+	if ok, _, err := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&rect))); ok == 0 {
+		return image.Rectangle{}, fmt.Errorf("GetWindowRect failed: %w", err)
+	}
+	return rect.rectangle(), nil
+}
+
+// nativeFocusedWindowFullscreen checks whether the foreground window covers
+// its whole monitor, taskbar included, as fullscreen games and video players
+// do. The desktop covers the monitor too, so it never counts.
+func nativeFocusedWindowFullscreen(self NativeWindow) (bool, error) {
+	if self.Kind != NativeWindowWin32 {
+		return false, ErrPositionUnsupported
+	}
+
+	hwnd, _, _ := procGetForeground.Call()
+	if hwnd == 0 || hwnd == self.Handle || isDesktopWindow(hwnd) {
+		return false, nil
+	}
+	if minimized, _, _ := procIsIconic.Call(hwnd); minimized != 0 {
+		return false, nil
+	}
+
+	var rect winRect
+	if ok, _, err := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&rect))); ok == 0 {
+		return false, fmt.Errorf("GetWindowRect failed: %w", err)
+	}
+	monitor, _, _ := procMonitorFromWindow.Call(hwnd, monitorDefaultToNearest)
+	info := monitorInfo{Size: uint32(unsafe.Sizeof(monitorInfo{}))}
+	if ok, _, err := procGetMonitorInfo.Call(monitor, uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return false, fmt.Errorf("GetMonitorInfo failed: %w", err)
+	}
+	return info.Monitor.rectangle().In(rect.rectangle()), nil
+}
+
+// isDesktopWindow reports whether hwnd is the desktop, whose Progman and
+// WorkerW windows hold the wallpaper and icons
+func isDesktopWindow(hwnd uintptr) bool {
+	var class [32]uint16
+	n, _, _ := procGetClassName.Call(hwnd, uintptr(unsafe.Pointer(&class[0])), uintptr(len(class)))
+	name := syscall.UTF16ToString(class[:n])
+	return name == "Progman" || name == "WorkerW"
+}
@@ -0,0 +1,219 @@
+//go:build linux && cgo && !android
+
+package native
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+
+// companion_geometry reads the window's position on the root window and
+// the root window's size. Returns 0 when the display can't be opened.
+static int companion_geometry(Window win, int *x, int *y, unsigned int *w, unsigned int *h,
+		unsigned int *screen_w, unsigned int *screen_h) {
+	Display *dpy = XOpenDisplay(NULL);
+	if (dpy == NULL) {
+		return 0;
+	}
+
+	Window root, child;
+	int wx, wy;
+	unsigned int border, depth;
+	if (!XGetGeometry(dpy, win, &root, &wx, &wy, w, h, &border, &depth)) {
+		XCloseDisplay(dpy);
+		return 0;
+	}
+	XTranslateCoordinates(dpy, win, root, 0, 0, x, y, &child);
+
+	XWindowAttributes attrs;
+	XGetWindowAttributes(dpy, root, &attrs);
+	*screen_w = attrs.width;
+	*screen_h = attrs.height;
+
+	XCloseDisplay(dpy);
+	return 1;
+}
+
+static int companion_move(Window win, int x, int y) {
+	Display *dpy = XOpenDisplay(NULL);
+	if (dpy == NULL) {
+		return 0;
+	}
+	XMoveWindow(dpy, win, x, y);
+	XFlush(dpy);
+	XCloseDisplay(dpy);
+	return 1;
+}
+
+// companion_window_property reads count items of a 32-bit window property.
+// Returns 0 when the property is missing or shorter.
+static int companion_window_property(Display *dpy, Window win, const char *name, Atom type,
+		unsigned long *values, unsigned long count) {
+	Atom actual;
+	int format;
+	unsigned long items, remaining;
+	unsigned char *data = NULL;
+	Atom property = XInternAtom(dpy, name, True);
+	if (property == None || XGetWindowProperty(dpy, win, property, 0, count, False, type,
+			&actual, &format, &items, &remaining, &data) != Success) {
+		return 0;
+	}
+	int ok = data != NULL && format == 32 && items >= count;
+	if (ok) {
+		// Xlib hands out 32-bit properties as longs
+		for (unsigned long i = 0; i < count; i++) {
+			values[i] = ((unsigned long *)data)[i];
+		}
+	}
+	if (data != NULL) {
+		XFree(data);
+	}
+	return ok;
+}
+
+// companion_focused_frame reads the frame of the window the window manager
+// reports as active, decorations included. Returns 0 when the display can't
+// be opened and -1 when no window other than self is focused.
+static int companion_focused_frame(Window self, int *x, int *y, unsigned int *w, unsigned int *h) {
+	Display *dpy = XOpenDisplay(NULL);
+	if (dpy == NULL) {
+		return 0;
+	}
+
+	Window root = DefaultRootWindow(dpy);
+	unsigned long active = 0;
+	if (!companion_window_property(dpy, root, "_NET_ACTIVE_WINDOW", XA_WINDOW, &active, 1) ||
+			active == None || active == self) {
+		XCloseDisplay(dpy);
+		return -1;
+	}
+
+	Window geometryRoot, child;
+	int wx, wy;
+	unsigned int border, depth;
+	if (!XGetGeometry(dpy, active, &geometryRoot, &wx, &wy, w, h, &border, &depth)) {
+		XCloseDisplay(dpy);
+		return -1;
+	}
+	XTranslateCoordinates(dpy, active, root, 0, 0, x, y, &child);
+
+	// left, right, top, bottom
+	unsigned long extents[4];
+	if (companion_window_property(dpy, active, "_NET_FRAME_EXTENTS", XA_CARDINAL, extents, 4)) {
+		*x -= extents[0];
+		*y -= extents[2];
+		*w += extents[0] + extents[1];
+		*h += extents[2] + extents[3];
+	}
+
+	XCloseDisplay(dpy);
+	return 1;
+}
+
+// companion_focused_fullscreen looks for _NET_WM_STATE_FULLSCREEN in the
+// active window's _NET_WM_STATE. Returns 0 when the display can't be
+// opened, 1 when the window is fullscreen and -1 otherwise.
+static int companion_focused_fullscreen(Window self) {
+	Display *dpy = XOpenDisplay(NULL);
+	if (dpy == NULL) {
+		return 0;
+	}
+
+	int fullscreen = -1;
+	Window root = DefaultRootWindow(dpy);
+	unsigned long active = 0;
+	Atom state = XInternAtom(dpy, "_NET_WM_STATE", True);
+	Atom wanted = XInternAtom(dpy, "_NET_WM_STATE_FULLSCREEN", True);
+	if (state != None && wanted != None &&
+			companion_window_property(dpy, root, "_NET_ACTIVE_WINDOW", XA_WINDOW, &active, 1) &&
+			active != None && active != self) {
+		Atom actual;
+		int format;
+		unsigned long items, remaining;
+		unsigned char *data = NULL;
+		if (XGetWindowProperty(dpy, active, state, 0, 64, False, XA_ATOM,
+				&actual, &format, &items, &remaining, &data) == Success && data != NULL) {
+			if (format == 32) {
+				for (unsigned long i = 0; i < items; i++) {
+					if (((unsigned long *)data)[i] == wanted) {
+						fullscreen = 1;
+						break;
+					}
+				}
+			}
+			XFree(data);
+		}
+	}
+
+	XCloseDisplay(dpy);
+	return fullscreen;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"image"
+)
+
+// nativeWindowGeometry reads the window position from X11. The screen is
+// the whole root window, which spans every monitor.
+func nativeWindowGeometry(win NativeWindow) (frame, screen image.Rectangle, err error) {
+	if win.Kind != NativeWindowX11 {
+		return frame, screen, ErrPositionUnsupported
+	}
+
+	var x, y C.int
+	var w, h, screenW, screenH C.uint
+	if C.companion_geometry(C.Window(win.Handle), &x, &y, &w, &h, &screenW, &screenH) == 0 {
+		return frame, screen, fmt.Errorf("failed to read window geometry: %w", ErrPositionUnsupported)
+	}
+	frame = image.Rect(int(x), int(y), int(x)+int(w), int(y)+int(h))
+	screen = image.Rect(0, 0, int(screenW), int(screenH))
+	return frame, screen, nil
+}
+
+// moveNativeWindow moves the X11 window
+func moveNativeWindow(win NativeWindow, x, y int) error {
+	if win.Kind != NativeWindowX11 {
+		return ErrPositionUnsupported
+	}
+	if C.companion_move(C.Window(win.Handle), C.int(x), C.int(y)) == 0 {
+		return fmt.Errorf("failed to open X display: %w", ErrPositionUnsupported)
+	}
+	return nil
+}
+
+// nativeFocusedWindowFrame reads the active window from the window manager's
+// _NET_ACTIVE_WINDOW hint, widened by its _NET_FRAME_EXTENTS
+func nativeFocusedWindowFrame(self NativeWindow) (image.Rectangle, error) {
+	if self.Kind != NativeWindowX11 {
+		return image.Rectangle{}, ErrPositionUnsupported
+	}
+
+	var x, y C.int
+	var w, h C.uint
+	switch C.companion_focused_frame(C.Window(self.Handle), &x, &y, &w, &h) {
+	case 0:
+		return image.Rectangle{}, fmt.Errorf("failed to open X display: %w", ErrPositionUnsupported)
+	case -1:
+		return image.Rectangle{}, ErrNoFocusedWindow
+	}
+	return image.Rect(int(x), int(y), int(x)+int(w), int(y)+int(h)), nil
+}
+
+// nativeFocusedWindowFullscreen asks the window manager whether the active
+// window is in the EWMH fullscreen state
+func nativeFocusedWindowFullscreen(self NativeWindow) (bool, error) {
+	if self.Kind != NativeWindowX11 {
+		return false, ErrPositionUnsupported
+	}
+
+	switch C.companion_focused_fullscreen(C.Window(self.Handle)) {
+	case 0:
+		return false, fmt.Errorf("failed to open X display: %w", ErrPositionUnsupported)
+	case 1:
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,205 @@
+// Package native contains small platform-specific window shims (cgo on X11
+// and macOS, syscalls on Windows, the Wayland wire protocol in pure Go) for
+// features Fyne does not expose. Every feature degrades to
+// ErrUnsupported-style errors so callers can fall back.
+package native
+
+import (
+	"errors"
+	"image"
+)
+
+// NativeWindowKind identifies the windowing system behind a native window handle
+type NativeWindowKind int
+
+const (
+	NativeWindowUnknown NativeWindowKind = iota
+	NativeWindowX11
+	NativeWindowWin32
+	NativeWindowCocoa
+	NativeWindowWayland
+)
+
+// NativeWindow is a platform window handle as exposed by the UI toolkit.
+// Keeping it toolkit-agnostic keeps this package free of Fyne imports.
+type NativeWindow struct {
+	Kind   NativeWindowKind
+	Handle uintptr // X11 Window, Win32 HWND or NSWindow pointer
+}
+
+// TransparencyMode describes how a platform makes the window see-through
+type TransparencyMode int
+
+const (
+	// TransparencyNone means the window stays an opaque rectangle
+	TransparencyNone TransparencyMode = iota
+	// TransparencyShaped clips the window to the sprite's opaque pixels
+	// (X11 shape extension, Win32 window regions). Edges are 1-bit, so it
+	// is only the fallback when per-pixel alpha is unavailable.
+	TransparencyShaped
+	// TransparencyAlpha composites the toolkit's own rendering with true
+	// per-pixel alpha (macOS, X11 with a 32-bit ARGB visual and a compositor)
+	TransparencyAlpha
+	// TransparencyLayered shows frames handed to Present with true per-pixel
+	// alpha (Win32 layered windows, which can't show the OpenGL swap chain)
+	TransparencyLayered
+)
+
+// String returns a readable name for logging
+func (m TransparencyMode) String() string {
+	switch m {
+	case TransparencyShaped:
+		return "shaped"
+	case TransparencyAlpha:
+		return "alpha"
+	case TransparencyLayered:
+		return "layered"
+	default:
+		return "none"
+	}
+}
+
+// ErrTransparencyUnsupported is returned when the platform or windowing
+// system has no per-pixel transparency support. Callers should fall back
+// to the plain window.
+var ErrTransparencyUnsupported = errors.New("per-pixel transparency is not supported on this platform")
+
+// ErrClickThroughUnsupported is returned when a transparent window can't
+// let clicks through to the windows underneath. Its whole rectangle keeps
+// taking mouse input.
+var ErrClickThroughUnsupported = errors.New("click-through is not supported for this window")
+
+// TransparentWindow controls per-pixel transparency for a native window
+type TransparentWindow interface {
+	// Mode reports how transparency is achieved
+	Mode() TransparencyMode
+	// SetShape limits the visible window area to rects, in window pixels.
+	// A nil slice restores the full rectangular window. Only shaped windows use it.
+	SetShape(rects []image.Rectangle) error
+	// SetInputShape limits mouse input to rects, in window pixels, so clicks
+	// elsewhere reach the windows underneath. A nil slice takes input
+	// everywhere again.
+	SetInputShape(rects []image.Rectangle) error
+	// Present shows frame, the whole window content with alpha, in window
+	// pixels. Only layered windows use it.
+	Present(frame image.Image) error
+	// Close releases any native resources
+	Close() error
+}
+
+// EnableTransparency turns on per-pixel transparency for the native window,
+// preferring true alpha and falling back to a shaped window.
+// Returns ErrTransparencyUnsupported when no platform shim applies.
+func EnableTransparency(win NativeWindow) (TransparentWindow, error) {
+	if win.Handle == 0 {
+		return nil, ErrTransparencyUnsupported
+	}
+	return enableNativeTransparency(win)
+}
+
+// alphaThreshold is the minimum alpha for a pixel to count as part of the sprite
+const alphaThreshold = 0x40
+
+// OpaqueRects computes the visible area of img when drawn "contain"-fitted
+// into a width x height window. The result is one rectangle per run of opaque
+// pixels, with identical runs on consecutive rows merged vertically.
+func OpaqueRects(img image.Image, width, height int) []image.Rectangle {
+	if img == nil || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	src := img.Bounds()
+	if src.Empty() {
+		return nil
+	}
+
+	// Same letterboxing as canvas.ImageFillContain
+	scale := float64(width) / float64(src.Dx())
+	if s := float64(height) / float64(src.Dy()); s < scale {
+		scale = s
+	}
+	drawW, drawH := int(float64(src.Dx())*scale), int(float64(src.Dy())*scale)
+	offX, offY := (width-drawW)/2, (height-drawH)/2
+
+	alpha := alphaFunc(img)
+
+	var rects []image.Rectangle
+	var previous []image.Rectangle
+	for y := 0; y < drawH; y++ {
+		sy := src.Min.Y + int(float64(y)/scale)
+		row := rowRuns(alpha, src.Min.X, sy, drawW, scale, offX, offY+y)
+		rects, previous = mergeRows(rects, previous, row)
+	}
+
+	return rects
+}
+
+// rowRuns returns the opaque runs of one destination row
+func rowRuns(alpha func(x, y int) uint8, srcMinX, sy, drawW int, scale float64, offX, dy int) []image.Rectangle {
+	var runs []image.Rectangle
+	start := -1
+	for x := 0; x <= drawW; x++ {
+		opaque := x < drawW && alpha(srcMinX+int(float64(x)/scale), sy) >= alphaThreshold
+		if opaque && start < 0 {
+			start = x
+		} else if !opaque && start >= 0 {
+			runs = append(runs, image.Rect(offX+start, dy, offX+x, dy+1))
+			start = -1
+		}
+	}
+	return runs
+}
+
+// mergeRows extends rectangles from the previous row that have the same
+// horizontal extents as the current row, otherwise appends the row's runs.
+// The previous row's rectangles are always the last ones in rects.
+func mergeRows(rects, previous, row []image.Rectangle) ([]image.Rectangle, []image.Rectangle) {
+	if len(previous) == len(row) {
+		same := true
+		for i := range row {
+			if previous[i].Min.X != row[i].Min.X || previous[i].Max.X != row[i].Max.X {
+				same = false
+				break
+			}
+		}
+		if same && len(row) > 0 {
+			// Grow the last len(row) rectangles by one row
+			base := len(rects) - len(row)
+			for i := range row {
+				rects[base+i].Max.Y = row[i].Max.Y
+			}
+			return rects, row
+		}
+	}
+
+	return append(rects, row...), row
+}
+
+// alphaFunc returns a fast alpha accessor for common image types.
+// GIF frames decode to *image.Paletted, so that path matters most.
+func alphaFunc(img image.Image) func(x, y int) uint8 {
+	switch m := img.(type) {
+	case *image.Paletted:
+		alphas := make([]uint8, len(m.Palette))
+		for i, c := range m.Palette {
+			_, _, _, a := c.RGBA()
+			alphas[i] = uint8(a >> 8)
+		}
+		return func(x, y int) uint8 {
+			index := m.ColorIndexAt(x, y)
+			if int(index) >= len(alphas) {
+				return 0
+			}
+			return alphas[index]
+		}
+	case *image.NRGBA:
+		return func(x, y int) uint8 { return m.NRGBAAt(x, y).A }
+	case *image.RGBA:
+		return func(x, y int) uint8 { return m.RGBAAt(x, y).A }
+	default:
+		return func(x, y int) uint8 {
+			_, _, _, a := img.At(x, y).RGBA()
+			return uint8(a >> 8)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+//go:build darwin && cgo
+
+package native
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa
+#include <stdlib.h>
+#include <string.h>
+#import <Cocoa/Cocoa.h>
+
+static void companion_make_transparent(void *handle) {
+	NSWindow *window = (__bridge NSWindow *)handle;
+	[window setOpaque:NO];
+	[window setBackgroundColor:[NSColor clearColor]];
+	[window setHasShadow:NO];
+	NSView *view = [window contentView];
+	[view setWantsLayer:YES];
+	view.layer.opaque = NO;
+}
+
+static void companion_make_opaque(void *handle) {
+	NSWindow *window = (__bridge NSWindow *)handle;
+	[window setOpaque:YES];
+	[window setBackgroundColor:[NSColor windowBackgroundColor]];
+	[window setHasShadow:YES];
+}
+
+// companion_update_click_through lets clicks through the window unless the
+// pointer is over one of rects, given as x, y, width, height in window
+// pixels from the top-left. A negative count takes clicks everywhere. The
+// rects are copied and checked on the main thread.
+static void companion_update_click_through(void *handle, const int *rects, int count) {
+	NSWindow *window = (__bridge NSWindow *)handle;
+	int *copy = NULL;
+	if (count > 0) {
+		copy = malloc(sizeof(int) * 4 * count);
+		memcpy(copy, rects, sizeof(int) * 4 * count);
+	}
+	dispatch_async(dispatch_get_main_queue(), ^{
+		BOOL inside = count < 0;
+		NSPoint mouse = [NSEvent mouseLocation];
+		NSRect frame = [window frame];
+		CGFloat scale = [window backingScaleFactor];
+		CGFloat x = (mouse.x - frame.origin.x) * scale;
+		CGFloat y = (frame.size.height - (mouse.y - frame.origin.y)) * scale;
+		for (int i = 0; i < count && !inside; i++) {
+			int *r = copy + i * 4;
+			inside = x >= r[0] && x < r[0] + r[2] && y >= r[1] && y < r[1] + r[3];
+		}
+		[window setIgnoresMouseEvents:!inside];
+		free(copy);
+	});
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// clickThroughInterval is how often the pointer is checked against the
+// input shape. macOS has no input shapes, so the window ignores the mouse
+// whenever the pointer isn't over the sprite.
+const clickThroughInterval = 50 * time.Millisecond
+
+// cocoaWindow makes an NSWindow non-opaque so the compositor blends it
+// with true per-pixel alpha
+type cocoaWindow struct {
+	mu         sync.Mutex
+	handle     unsafe.Pointer
+	inputShape []C.int       // x, y, width, height per rect
+	stopInput  chan struct{} // Stops the pointer check, nil when input isn't shaped
+}
+
+// enableNativeTransparency clears the NSWindow background.
+// Must run on the main thread, e.g. inside Fyne's RunNative callback.
+func enableNativeTransparency(win NativeWindow) (TransparentWindow, error) {
+	if win.Kind != NativeWindowCocoa {
+		return nil, ErrTransparencyUnsupported
+	}
+
+	handle := unsafe.Pointer(win.Handle)
+	C.companion_make_transparent(handle)
+	return &cocoaWindow{handle: handle}, nil
+}
+
+// Mode reports true alpha transparency
+func (w *cocoaWindow) Mode() TransparencyMode {
+	return TransparencyAlpha
+}
+
+// SetShape is unnecessary with real alpha compositing
+func (w *cocoaWindow) SetShape(rects []image.Rectangle) error {
+	return nil
+}
+
+// SetInputShape starts or stops following the pointer, letting clicks
+// through while it is outside rects
+func (w *cocoaWindow) SetInputShape(rects []image.Rectangle) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.handle == nil {
+		return fmt.Errorf("transparent window is closed")
+	}
+
+	if rects == nil {
+		w.stopClickThrough()
+		return nil
+	}
+
+	w.inputShape = w.inputShape[:0]
+	for _, r := range rects {
+		w.inputShape = append(w.inputShape, C.int(r.Min.X), C.int(r.Min.Y), C.int(r.Dx()), C.int(r.Dy()))
+	}
+	if w.stopInput == nil {
+		w.stopInput = make(chan struct{})
+		go w.followPointer(w.stopInput)
+	}
+	return nil
+}
+
+// followPointer updates click-through as the pointer moves
+func (w *cocoaWindow) followPointer(stop chan struct{}) {
+	ticker := time.NewTicker(clickThroughInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		w.mu.Lock()
+		if w.handle != nil && w.stopInput == stop {
+			var first *C.int
+			if len(w.inputShape) > 0 {
+				first = &w.inputShape[0]
+			}
+			C.companion_update_click_through(w.handle, first, C.int(len(w.inputShape)/4))
+		}
+		w.mu.Unlock()
+	}
+}
+
+// stopClickThrough makes the whole window take clicks again. Called with
+// w.mu held.
+func (w *cocoaWindow) stopClickThrough() {
+	if w.stopInput == nil {
+		return
+	}
+	close(w.stopInput)
+	w.stopInput = nil
+	C.companion_update_click_through(w.handle, nil, -1)
+}
+
+// Present is unnecessary, the compositor blends what the toolkit draws
+func (w *cocoaWindow) Present(frame image.Image) error {
+	return nil
+}
+
+// Close restores the opaque window
+func (w *cocoaWindow) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.handle == nil {
+		return nil
+	}
+	w.stopClickThrough()
+	C.companion_make_opaque(w.handle)
+	w.handle = nil
+	return nil
+}
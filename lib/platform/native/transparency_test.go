@@ -0,0 +1,56 @@
+package native
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestOpaqueRectsMergesRows(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	// Opaque 2x2 block in the middle
+	for y := 1; y < 3; y++ {
+		for x := 1; x < 3; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{A: 255})
+		}
+	}
+
+	rects := OpaqueRects(img, 4, 4)
+	if len(rects) != 1 {
+		t.Fatalf("Expected one merged rectangle, got %v", rects)
+	}
+	if rects[0] != image.Rect(1, 1, 3, 3) {
+		t.Errorf("Expected (1,1)-(3,3), got %v", rects[0])
+	}
+}
+
+func TestOpaqueRectsScalesAndLetterboxes(t *testing.T) {
+	// 2x1 image, left pixel opaque, drawn into an 8x8 window:
+	// scale 4, drawn 8x4 centred vertically at y=2
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), color.Palette{color.Transparent, color.Black})
+	img.SetColorIndex(0, 0, 1)
+
+	rects := OpaqueRects(img, 8, 8)
+	if len(rects) != 1 || rects[0] != image.Rect(0, 2, 4, 6) {
+		t.Errorf("Expected (0,2)-(4,6), got %v", rects)
+	}
+}
+
+func TestOpaqueRectsEmpty(t *testing.T) {
+	if rects := OpaqueRects(nil, 10, 10); rects != nil {
+		t.Errorf("Expected nil for nil image, got %v", rects)
+	}
+	transparent := image.NewNRGBA(image.Rect(0, 0, 3, 3))
+	if rects := OpaqueRects(transparent, 3, 3); len(rects) != 0 {
+		t.Errorf("Expected no rectangles for transparent image, got %v", rects)
+	}
+}
+
+func TestEnableTransparencyRejectsMissingHandle(t *testing.T) {
+	if _, err := EnableTransparency(NativeWindow{Kind: NativeWindowX11}); err != ErrTransparencyUnsupported {
+		t.Errorf("Expected ErrTransparencyUnsupported, got %v", err)
+	}
+	if _, err := EnableTransparency(NativeWindow{Kind: NativeWindowWayland, Handle: 1}); err != ErrTransparencyUnsupported {
+		t.Errorf("Expected ErrTransparencyUnsupported for Wayland, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+//go:build windows
+
+package native
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Win32 transparency. Layered windows (WS_EX_LAYERED) updated with
+// UpdateLayeredWindow give true per-pixel alpha, but they no longer show the
+// OpenGL swap chain Fyne renders into, so the UI hands every frame to Present.
+// Window regions, which clip the window to the sprite's opaque pixels, are
+// the fallback when layering is unavailable.
+var (
+	user32                  = syscall.NewLazyDLL("user32.dll")
+	gdi32                   = syscall.NewLazyDLL("gdi32.dll")
+	procSetWindowRgn        = user32.NewProc("SetWindowRgn")
+	procGetWindowLong       = user32.NewProc("GetWindowLongW")
+	procSetWindowLong       = user32.NewProc("SetWindowLongW")
+	procUpdateLayeredWindow = user32.NewProc("UpdateLayeredWindow")
+	procCreateRectRgn       = gdi32.NewProc("CreateRectRgn")
+	procCombineRgn          = gdi32.NewProc("CombineRgn")
+	procDeleteObject        = gdi32.NewProc("DeleteObject")
+	procCreateCompatibleDC  = gdi32.NewProc("CreateCompatibleDC")
+	procDeleteDC            = gdi32.NewProc("DeleteDC")
+	procCreateDIBSection    = gdi32.NewProc("CreateDIBSection")
+	procSelectObject        = gdi32.NewProc("SelectObject")
+)
+
+const (
+	rgnOr        = 2          // RGN_OR
+	wsExLayered  = 0x00080000 // WS_EX_LAYERED
+	ulwAlpha     = 2          // ULW_ALPHA
+	acSrcAlpha   = 1          // AC_SRC_ALPHA
+	dibRGBColors = 0          // DIB_RGB_COLORS
+)
+
+// gwlExStyle is GWL_EXSTYLE; a variable because the index is negative
+var gwlExStyle = int32(-20)
+
+// bitmapInfoHeader mirrors BITMAPINFOHEADER
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// win32Point and win32Size mirror POINT and SIZE
+type win32Point struct{ X, Y int32 }
+type win32Size struct{ CX, CY int32 }
+
+// blendFunction mirrors BLENDFUNCTION
+type blendFunction struct {
+	BlendOp, BlendFlags, SourceConstantAlpha, AlphaFormat byte
+}
+
+// win32Window makes an HWND see-through, layered when possible and clipped
+// with a window region otherwise
+type win32Window struct {
+	mu      sync.Mutex
+	hwnd    uintptr
+	layered bool
+}
+
+// enableNativeTransparency prefers a layered window and falls back to regions
+func enableNativeTransparency(win NativeWindow) (TransparentWindow, error) {
+	if win.Kind != NativeWindowWin32 {
+		return nil, ErrTransparencyUnsupported
+	}
+
+	if procUpdateLayeredWindow.Find() == nil && procSetWindowLong.Find() == nil {
+		style, _, _ := procGetWindowLong.Call(win.Handle, uintptr(gwlExStyle))
+		procSetWindowLong.Call(win.Handle, uintptr(gwlExStyle), style|wsExLayered)
+		if updated, _, _ := procGetWindowLong.Call(win.Handle, uintptr(gwlExStyle)); updated&wsExLayered != 0 {
+			return &win32Window{hwnd: win.Handle, layered: true}, nil
+		}
+	}
+
+	if err := procSetWindowRgn.Find(); err != nil {
+		return nil, fmt.Errorf("SetWindowRgn unavailable: %w", ErrTransparencyUnsupported)
+	}
+	return &win32Window{hwnd: win.Handle}, nil
+}
+
+// Mode reports layered transparency, or shaped for the region fallback
+func (w *win32Window) Mode() TransparencyMode {
+	if w.layered {
+		return TransparencyLayered
+	}
+	return TransparencyShaped
+}
+
+// SetShape builds a region from rects and hands it to the window.
+// The system owns the region after a successful SetWindowRgn.
+// Layered windows need no region.
+func (w *win32Window) SetShape(rects []image.Rectangle) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.hwnd == 0 {
+		return fmt.Errorf("transparent window is closed")
+	}
+	if w.layered {
+		return nil
+	}
+
+	if rects == nil {
+		procSetWindowRgn.Call(w.hwnd, 0, 1)
+		return nil
+	}
+
+	region, _, _ := procCreateRectRgn.Call(0, 0, 0, 0)
+	if region == 0 {
+		return fmt.Errorf("CreateRectRgn failed")
+	}
+
+	for _, r := range rects {
+		part, _, _ := procCreateRectRgn.Call(uintptr(r.Min.X), uintptr(r.Min.Y), uintptr(r.Max.X), uintptr(r.Max.Y))
+		if part == 0 {
+			continue
+		}
+		procCombineRgn.Call(region, region, part, rgnOr)
+		procDeleteObject.Call(part)
+	}
+
+	if ok, _, err := procSetWindowRgn.Call(w.hwnd, region, 1); ok == 0 {
+		procDeleteObject.Call(region)
+		return fmt.Errorf("SetWindowRgn failed: %w", err)
+	}
+	return nil
+}
+
+// SetInputShape has nothing to do on Win32: layered windows already let
+// clicks through their fully transparent pixels, and a window region clips
+// input along with the window
+func (w *win32Window) SetInputShape(rects []image.Rectangle) error {
+	return nil
+}
+
+// Present copies frame into a premultiplied BGRA bitmap and shows it with
+// UpdateLayeredWindow. Region-clipped windows ignore it.
+func (w *win32Window) Present(frame image.Image) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.hwnd == 0 {
+		return fmt.Errorf("transparent window is closed")
+	}
+	if !w.layered || frame == nil {
+		return nil
+	}
+
+	bounds := frame.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	memDC, _, _ := procCreateCompatibleDC.Call(0)
+	if memDC == 0 {
+		return fmt.Errorf("CreateCompatibleDC failed")
+	}
+	defer procDeleteDC.Call(memDC)
+
+	header := bitmapInfoHeader{
+		Size:     uint32(unsafe.Sizeof(bitmapInfoHeader{})),
+		Width:    int32(width),
+		Height:   -int32(height), // Top-down rows, like image.RGBA
+		Planes:   1,
+		BitCount: 32,
+	}
+	var bits unsafe.Pointer
+	bitmap, _, _ := procCreateDIBSection.Call(memDC, uintptr(unsafe.Pointer(&header)), dibRGBColors, uintptr(unsafe.Pointer(&bits)), 0, 0)
+	if bitmap == 0 || bits == nil {
+		return fmt.Errorf("CreateDIBSection failed")
+	}
+	defer procDeleteObject.Call(bitmap)
+
+	// image.RGBA is already premultiplied; Win32 wants the bytes as BGRA
+	pixels := unsafe.Slice((*byte)(bits), width*height*4)
+	rgba := &image.RGBA{Pix: pixels, Stride: width * 4, Rect: image.Rect(0, 0, width, height)}
+	draw.Draw(rgba, rgba.Rect, frame, bounds.Min, draw.Src)
+	for i := 0; i < len(pixels); i += 4 {
+		pixels[i], pixels[i+2] = pixels[i+2], pixels[i]
+	}
+
+	previous, _, _ := procSelectObject.Call(memDC, bitmap)
+	defer procSelectObject.Call(memDC, previous)
+
+	size := win32Size{CX: int32(width), CY: int32(height)}
+	source := win32Point{}
+	blend := blendFunction{SourceConstantAlpha: 255, AlphaFormat: acSrcAlpha}
+	if ok, _, err := procUpdateLayeredWindow.Call(w.hwnd, 0, 0,
+		uintptr(unsafe.Pointer(&size)), memDC, uintptr(unsafe.Pointer(&source)),
+		0, uintptr(unsafe.Pointer(&blend)), ulwAlpha); ok == 0 {
+		return fmt.Errorf("UpdateLayeredWindow failed: %w", err)
+	}
+	return nil
+}
+
+// Close restores the rectangular, unlayered window
+func (w *win32Window) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.hwnd == 0 {
+		return nil
+	}
+	if w.layered {
+		style, _, _ := procGetWindowLong.Call(w.hwnd, uintptr(gwlExStyle))
+		procSetWindowLong.Call(w.hwnd, uintptr(gwlExStyle), style&^wsExLayered)
+	} else {
+		procSetWindowRgn.Call(w.hwnd, 0, 1)
+	}
+	w.hwnd = 0
+	return nil
+}
@@ -0,0 +1,217 @@
+//go:build linux && cgo && !android
+
+package native
+
+/*
+#cgo LDFLAGS: -lX11 -lXext
+#include <stdio.h>
+#include <stdlib.h>
+#include <X11/Xlib.h>
+#include <X11/extensions/shape.h>
+
+// companion_has_compositor reports whether a compositing manager owns the
+// _NET_WM_CM_Sn selection for the default screen, as the EWMH specifies
+static int companion_has_compositor(Display *dpy) {
+	char name[32];
+	snprintf(name, sizeof(name), "_NET_WM_CM_S%d", DefaultScreen(dpy));
+	Atom atom = XInternAtom(dpy, name, False);
+	return XGetSelectionOwner(dpy, atom) != None;
+}
+
+// companion_window_depth returns the depth of the window's visual, 32 for ARGB
+static int companion_window_depth(Display *dpy, Window win) {
+	XWindowAttributes attrs;
+	if (!XGetWindowAttributes(dpy, win, &attrs)) {
+		return 0;
+	}
+	return attrs.depth;
+}
+
+static int companion_has_shape(Display *dpy) {
+	int event_base, error_base;
+	return XShapeQueryExtension(dpy, &event_base, &error_base);
+}
+
+static void companion_set_shape(Display *dpy, Window win, XRectangle *rects, int count) {
+	XShapeCombineRectangles(dpy, win, ShapeBounding, 0, 0, rects, count, ShapeSet, Unsorted);
+	XFlush(dpy);
+}
+
+static void companion_clear_shape(Display *dpy, Window win) {
+	XShapeCombineMask(dpy, win, ShapeBounding, 0, 0, None, ShapeSet);
+	XFlush(dpy);
+}
+
+// companion_has_input_shape reports whether the server supports input
+// shapes, which arrived in version 1.1 of the shape extension
+static int companion_has_input_shape(Display *dpy) {
+	int event_base, error_base, major, minor;
+	if (!XShapeQueryExtension(dpy, &event_base, &error_base) || !XShapeQueryVersion(dpy, &major, &minor)) {
+		return 0;
+	}
+	return major > 1 || (major == 1 && minor >= 1);
+}
+
+static void companion_set_input_shape(Display *dpy, Window win, XRectangle *rects, int count) {
+	XShapeCombineRectangles(dpy, win, ShapeInput, 0, 0, rects, count, ShapeSet, Unsorted);
+	XFlush(dpy);
+}
+
+static void companion_clear_input_shape(Display *dpy, Window win) {
+	XShapeCombineMask(dpy, win, ShapeInput, 0, 0, None, ShapeSet);
+	XFlush(dpy);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"unsafe"
+)
+
+// argbDepth is the visual depth of windows with an alpha channel
+const argbDepth = 32
+
+// x11Window makes an X11 window see-through. A window created with a 32-bit
+// ARGB visual is blended per pixel by the compositor; any other window is
+// clipped with the X Shape extension. It opens its own display connection;
+// window IDs are server-global so this is safe alongside the toolkit's.
+type x11Window struct {
+	mu         sync.Mutex
+	display    *C.Display
+	window     C.Window
+	alpha      bool
+	inputShape bool // The server supports input shapes
+	inputSet   bool // An input shape is applied
+}
+
+// enableNativeTransparency uses per-pixel alpha when the window has an ARGB
+// visual and a compositor is running, and the shape extension otherwise.
+// Wayland sessions have no equivalent and report unsupported.
+func enableNativeTransparency(win NativeWindow) (TransparentWindow, error) {
+	if win.Kind != NativeWindowX11 {
+		return nil, ErrTransparencyUnsupported
+	}
+
+	display := C.XOpenDisplay(nil)
+	if display == nil {
+		return nil, fmt.Errorf("failed to open X display: %w", ErrTransparencyUnsupported)
+	}
+
+	window := C.Window(win.Handle)
+	if C.companion_window_depth(display, window) == argbDepth && C.companion_has_compositor(display) != 0 {
+		return &x11Window{display: display, window: window, alpha: true,
+			inputShape: C.companion_has_input_shape(display) != 0}, nil
+	}
+
+	if C.companion_has_shape(display) == 0 {
+		C.XCloseDisplay(display)
+		return nil, fmt.Errorf("X server lacks the shape extension: %w", ErrTransparencyUnsupported)
+	}
+
+	return &x11Window{display: display, window: window,
+		inputShape: C.companion_has_input_shape(display) != 0}, nil
+}
+
+// Mode reports alpha transparency for ARGB windows, shaped otherwise
+func (w *x11Window) Mode() TransparencyMode {
+	if w.alpha {
+		return TransparencyAlpha
+	}
+	return TransparencyShaped
+}
+
+// SetShape applies the bounding shape, or clears it for a nil slice.
+// ARGB windows need no shape.
+func (w *x11Window) SetShape(rects []image.Rectangle) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.display == nil {
+		return fmt.Errorf("transparent window is closed")
+	}
+	if w.alpha {
+		return nil
+	}
+
+	if rects == nil {
+		C.companion_clear_shape(w.display, w.window)
+		return nil
+	}
+
+	xrects, first := xRectangles(rects)
+	C.companion_set_shape(w.display, w.window, first, C.int(len(xrects)))
+	return nil
+}
+
+// SetInputShape applies the input shape, or clears it for a nil slice.
+// Shaped windows already take no input outside their bounding shape, but
+// the input shape still matters while it is cleared for overlays.
+func (w *x11Window) SetInputShape(rects []image.Rectangle) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.display == nil {
+		return fmt.Errorf("transparent window is closed")
+	}
+	if !w.inputShape {
+		return fmt.Errorf("X server lacks input shapes: %w", ErrClickThroughUnsupported)
+	}
+
+	if rects == nil {
+		C.companion_clear_input_shape(w.display, w.window)
+		w.inputSet = false
+		return nil
+	}
+
+	xrects, first := xRectangles(rects)
+	C.companion_set_input_shape(w.display, w.window, first, C.int(len(xrects)))
+	w.inputSet = true
+	return nil
+}
+
+// xRectangles converts rects for Xlib. The pointer to the first element
+// is nil for an empty slice, which the shape calls accept with a count of 0.
+func xRectangles(rects []image.Rectangle) ([]C.XRectangle, *C.XRectangle) {
+	xrects := make([]C.XRectangle, len(rects))
+	for i, r := range rects {
+		xrects[i] = C.XRectangle{
+			x:      C.short(r.Min.X),
+			y:      C.short(r.Min.Y),
+			width:  C.ushort(r.Dx()),
+			height: C.ushort(r.Dy()),
+		}
+	}
+
+	var first *C.XRectangle
+	if len(xrects) > 0 {
+		first = (*C.XRectangle)(unsafe.Pointer(&xrects[0]))
+	}
+	return xrects, first
+}
+
+// Present is unnecessary, the compositor blends what the toolkit draws
+func (w *x11Window) Present(frame image.Image) error {
+	return nil
+}
+
+// Close restores the rectangular window and closes the display connection
+func (w *x11Window) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.display == nil {
+		return nil
+	}
+	if !w.alpha {
+		C.companion_clear_shape(w.display, w.window)
+	}
+	if w.inputSet {
+		C.companion_clear_input_shape(w.display, w.window)
+	}
+	C.XCloseDisplay(w.display)
+	w.display = nil
+	return nil
+}
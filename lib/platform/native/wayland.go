@@ -0,0 +1,352 @@
+//go:build linux && !android
+
+package native
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// The Wayland wire protocol is simple enough to speak directly, which keeps
+// the overlay free of cgo and libwayland. Only what the overlay needs is
+// implemented: requests are encoded from Go values and events are handed
+// to per-object handlers on a single reader goroutine.
+
+const (
+	wlDisplayID        = 1
+	wlHeaderSize       = 8
+	wlMaxMessageSize   = 4096
+	wlRoundtripTimeout = 2 * time.Second
+)
+
+// wlFD is a file descriptor request argument, sent alongside the message
+type wlFD int
+
+// wlNull is a null object argument
+const wlNull = uint32(0)
+
+// wlArgs reads the arguments of an event in order
+type wlArgs struct {
+	data []byte
+	err  error
+}
+
+func (a *wlArgs) uint() uint32 {
+	if len(a.data) < 4 {
+		a.err = errors.New("wayland: short event")
+		return 0
+	}
+	v := binary.NativeEndian.Uint32(a.data)
+	a.data = a.data[4:]
+	return v
+}
+
+func (a *wlArgs) int() int32 {
+	return int32(a.uint())
+}
+
+// fixed reads a 24.8 fixed-point number
+func (a *wlArgs) fixed() float64 {
+	return float64(a.int()) / 256
+}
+
+func (a *wlArgs) string() string {
+	n := int(a.uint())
+	padded := (n + 3) &^ 3
+	if n == 0 || len(a.data) < padded {
+		if n != 0 {
+			a.err = errors.New("wayland: short event")
+		}
+		return ""
+	}
+	s := string(a.data[:n-1]) // Drop the terminating NUL
+	a.data = a.data[padded:]
+	return s
+}
+
+// wlHandler receives the events of one object
+type wlHandler func(opcode uint16, args *wlArgs)
+
+// wlConn is a client connection to a Wayland compositor
+type wlConn struct {
+	conn *net.UnixConn
+
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	nextID   uint32
+	handlers map[uint32]wlHandler
+	err      error // Protocol error reported by the compositor
+
+	done chan struct{} // Closed when the reader stops
+}
+
+// newWlConn starts reading events from conn
+func newWlConn(conn *net.UnixConn) *wlConn {
+	c := &wlConn{
+		conn:     conn,
+		nextID:   wlDisplayID + 1,
+		handlers: make(map[uint32]wlHandler),
+		done:     make(chan struct{}),
+	}
+	c.handlers[wlDisplayID] = c.handleDisplay
+	go c.readLoop()
+	return c
+}
+
+// newID allocates an object ID and registers its event handler, which may be nil
+func (c *wlConn) newID(handler wlHandler) uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextID
+	c.nextID++
+	if handler != nil {
+		c.handlers[id] = handler
+	}
+	return id
+}
+
+// handle registers the event handler of an object
+func (c *wlConn) handle(id uint32, handler wlHandler) {
+	c.mu.Lock()
+	c.handlers[id] = handler
+	c.mu.Unlock()
+}
+
+// forget stops dispatching events to a destroyed object
+func (c *wlConn) forget(id uint32) {
+	c.mu.Lock()
+	delete(c.handlers, id)
+	c.mu.Unlock()
+}
+
+// request sends a request. Arguments may be uint32, int32, string or wlFD.
+func (c *wlConn) request(object uint32, opcode uint16, args ...any) error {
+	msg, fds, err := wlMarshal(object, opcode, args...)
+	if err != nil {
+		return err
+	}
+	var oob []byte
+	if len(fds) > 0 {
+		oob = syscall.UnixRights(fds...)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, _, err := c.conn.WriteMsgUnix(msg, oob, nil); err != nil {
+		return fmt.Errorf("wayland: %w", err)
+	}
+	return nil
+}
+
+// wlMarshal encodes a message. File descriptors travel separately.
+func wlMarshal(object uint32, opcode uint16, args ...any) ([]byte, []int, error) {
+	msg := make([]byte, wlHeaderSize, 64)
+	var fds []int
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case uint32:
+			msg = binary.NativeEndian.AppendUint32(msg, v)
+		case int32:
+			msg = binary.NativeEndian.AppendUint32(msg, uint32(v))
+		case string:
+			msg = binary.NativeEndian.AppendUint32(msg, uint32(len(v)+1))
+			msg = append(msg, v...)
+			msg = append(msg, make([]byte, 4-len(v)%4)...) // NUL and padding
+		case wlFD:
+			fds = append(fds, int(v))
+		default:
+			return nil, nil, fmt.Errorf("wayland: unsupported argument %T", arg)
+		}
+	}
+	binary.NativeEndian.PutUint32(msg[0:], object)
+	binary.NativeEndian.PutUint32(msg[4:], uint32(len(msg))<<16|uint32(opcode))
+	return msg, fds, nil
+}
+
+// readLoop dispatches events until the connection closes
+func (c *wlConn) readLoop() {
+	defer close(c.done)
+
+	buf := make([]byte, 0, wlMaxMessageSize*2)
+	chunk := make([]byte, wlMaxMessageSize)
+	oob := make([]byte, syscall.CmsgSpace(28*4))
+	for {
+		n, oobn, _, _, err := c.conn.ReadMsgUnix(chunk, oob)
+		if err != nil {
+			return
+		}
+		closeReceivedFDs(oob[:oobn])
+		buf = append(buf, chunk[:n]...)
+
+		for len(buf) >= wlHeaderSize {
+			object := binary.NativeEndian.Uint32(buf)
+			word := binary.NativeEndian.Uint32(buf[4:])
+			size := int(word >> 16)
+			if size < wlHeaderSize {
+				c.fail(errors.New("wayland: malformed event"))
+				return
+			}
+			if len(buf) < size {
+				break
+			}
+
+			c.mu.Lock()
+			handler := c.handlers[object]
+			c.mu.Unlock()
+			if handler != nil {
+				handler(uint16(word), &wlArgs{data: buf[wlHeaderSize:size]})
+			}
+			buf = buf[size:]
+		}
+		buf = append(buf[:0], buf...)
+	}
+}
+
+// closeReceivedFDs closes descriptors the compositor passed with events.
+// None of the events the overlay uses carry one.
+func closeReceivedFDs(oob []byte) {
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return
+	}
+	for _, m := range messages {
+		fds, err := syscall.ParseUnixRights(&m)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+	}
+}
+
+// handleDisplay handles wl_display errors
+func (c *wlConn) handleDisplay(opcode uint16, args *wlArgs) {
+	if opcode != 0 {
+		return // delete_id; IDs are never reused
+	}
+	object, code, message := args.uint(), args.uint(), args.string()
+	c.fail(fmt.Errorf("wayland: protocol error %d on object %d: %s", code, object, message))
+}
+
+// fail records a fatal error and closes the connection
+func (c *wlConn) fail(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.mu.Unlock()
+	c.conn.Close()
+}
+
+// roundtrip waits until the compositor has handled every request sent so far
+func (c *wlConn) roundtrip() error {
+	synced := make(chan struct{})
+	var once sync.Once
+	callback := c.newID(func(opcode uint16, args *wlArgs) {
+		once.Do(func() { close(synced) })
+	})
+	defer c.forget(callback)
+
+	if err := c.request(wlDisplayID, 0, callback); err != nil { // wl_display.sync
+		return err
+	}
+
+	select {
+	case <-synced:
+		return nil
+	case <-c.done:
+		return c.closedErr()
+	case <-time.After(wlRoundtripTimeout):
+		return errors.New("wayland: compositor did not respond")
+	}
+}
+
+// closedErr explains why the connection ended
+func (c *wlConn) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	return errors.New("wayland: connection closed")
+}
+
+// close ends the connection and waits for the reader
+func (c *wlConn) close() error {
+	err := c.conn.Close()
+	<-c.done
+	if errors.Is(err, net.ErrClosed) {
+		return nil // Already ended by the compositor
+	}
+	return err
+}
+
+// wlGlobal is an interface the compositor advertises
+type wlGlobal struct {
+	name    uint32
+	version uint32
+}
+
+// wlRegistry is the compositor's list of globals
+type wlRegistry struct {
+	id      uint32
+	globals map[string]wlGlobal // By interface name
+}
+
+// registry lists the compositor's globals
+func (c *wlConn) registry() (*wlRegistry, error) {
+	var mu sync.Mutex
+	globals := make(map[string]wlGlobal)
+	id := c.newID(func(opcode uint16, args *wlArgs) {
+		if opcode != 0 {
+			return // global_remove
+		}
+		name, iface, version := args.uint(), args.string(), args.uint()
+		if args.err == nil {
+			mu.Lock()
+			globals[iface] = wlGlobal{name: name, version: version}
+			mu.Unlock()
+		}
+	})
+	if err := c.request(wlDisplayID, 1, id); err != nil { // wl_display.get_registry
+		return nil, err
+	}
+	if err := c.roundtrip(); err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return &wlRegistry{id: id, globals: globals}, nil
+}
+
+// has reports whether the compositor offers an interface
+func (r *wlRegistry) has(iface string) bool {
+	_, ok := r.globals[iface]
+	return ok
+}
+
+// bind creates an object for a global, at most at the given version
+func (c *wlConn) bind(r *wlRegistry, iface string, version uint32, handler wlHandler) (uint32, error) {
+	id := c.newID(handler)
+	return id, c.bindID(r, iface, version, id)
+}
+
+// bindID binds a global to an already allocated ID, for handlers that need
+// to know their own object
+func (c *wlConn) bindID(r *wlRegistry, iface string, version, id uint32) error {
+	global, ok := r.globals[iface]
+	if !ok {
+		return fmt.Errorf("wayland: compositor has no %s", iface)
+	}
+	// wl_registry.bind takes an untyped new_id: interface, version, ID
+	return c.request(r.id, 0, global.name, iface, min(version, global.version), id)
+}
@@ -0,0 +1,77 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CreateDesktopShortcut puts a launcher for target on the user's desktop and
+// returns its path. Plain text launchers are used on every OS so no extra
+// tools are needed: a .desktop entry on Linux, a .command script on macOS and
+// a .cmd script on Windows.
+func CreateDesktopShortcut(name, target string, args []string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return createShortcut(runtime.GOOS, filepath.Join(home, "Desktop"), name, target, args)
+}
+
+// createShortcut writes the launcher for goos into dir
+func createShortcut(goos, dir, name, target string, args []string) (string, error) {
+	var fileName, content string
+	mode := os.FileMode(0o755)
+
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		fileName = name + ".desktop"
+		content = fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=%s\nTerminal=false\nCategories=Game;\n",
+			name, shellCommand(target, args))
+	case "darwin":
+		fileName = name + ".command"
+		content = "#!/bin/sh\nexec " + shellCommand(target, args) + "\n"
+	case "windows":
+		fileName = name + ".cmd"
+		content = fmt.Sprintf("@echo off\r\nstart \"\" %s\r\n", windowsCommand(target, args))
+		mode = 0o644
+	default:
+		return "", fmt.Errorf("desktop shortcuts are not supported on %s", goos)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create desktop directory: %w", err)
+	}
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return "", fmt.Errorf("failed to write shortcut: %w", err)
+	}
+	return path, nil
+}
+
+// shellCommand quotes target and args for a POSIX shell or .desktop Exec line
+func shellCommand(target string, args []string) string {
+	parts := []string{shellQuote(target)}
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote wraps s in double quotes, escaping characters both shells and
+// .desktop files treat specially
+func shellQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// windowsCommand quotes target and args for cmd.exe
+func windowsCommand(target string, args []string) string {
+	parts := []string{`"` + target + `"`}
+	for _, arg := range args {
+		parts = append(parts, `"`+arg+`"`)
+	}
+	return strings.Join(parts, " ")
+}
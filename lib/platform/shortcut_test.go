@@ -0,0 +1,40 @@
+package platform
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCreateShortcut(t *testing.T) {
+	tests := []struct {
+		goos     string
+		suffix   string
+		contains string
+	}{
+		{"linux", ".desktop", `Exec="/opt/dds/companion" "-character" "/cards/my pet/character.json"`},
+		{"darwin", ".command", `exec "/opt/dds/companion" "-character"`},
+		{"windows", ".cmd", `start "" "/opt/dds/companion" "-character"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			path, err := createShortcut(tt.goos, t.TempDir(), "Desktop Companion", "/opt/dds/companion",
+				[]string{"-character", "/cards/my pet/character.json"})
+			if err != nil {
+				t.Fatalf("createShortcut failed: %v", err)
+			}
+			if !strings.HasSuffix(path, "Desktop Companion"+tt.suffix) {
+				t.Errorf("Unexpected shortcut path %s", path)
+			}
+			data, _ := os.ReadFile(path)
+			if !strings.Contains(string(data), tt.contains) {
+				t.Errorf("Shortcut should contain %q, got:\n%s", tt.contains, data)
+			}
+		})
+	}
+
+	if _, err := createShortcut("android", t.TempDir(), "x", "y", nil); err == nil {
+		t.Error("Expected an error on platforms without desktop shortcuts")
+	}
+}
@@ -0,0 +1,56 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoSpeech is returned when no text-to-speech tool is installed
+var ErrNoSpeech = errors.New("no text-to-speech tool found")
+
+// windowsSpeechScript reads stdin aloud so dialog text never needs quoting
+const windowsSpeechScript = "Add-Type -AssemblyName System.Speech; " +
+	"(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak([Console]::In.ReadToEnd())"
+
+// Speak reads text aloud with the OS speech tool and returns without waiting
+// for it to finish: say on macOS, spd-say or espeak on Linux and the .NET
+// synthesizer through PowerShell on Windows.
+func Speak(text string) error {
+	cmd, err := speechCommand(runtime.GOOS, text, exec.LookPath)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start speech: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// speechCommand builds the speech command for goos using the first tool
+// lookPath finds
+func speechCommand(goos, text string, lookPath func(string) (string, error)) (*exec.Cmd, error) {
+	var candidates [][]string
+	switch goos {
+	case "darwin":
+		candidates = [][]string{{"say"}}
+	case "windows":
+		candidates = [][]string{{"powershell", "-NoProfile", "-Command", windowsSpeechScript}}
+	default:
+		candidates = [][]string{{"spd-say", "-e"}, {"espeak-ng", "--stdin"}, {"espeak", "--stdin"}}
+	}
+
+	for _, candidate := range candidates {
+		path, err := lookPath(candidate[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd, nil
+	}
+	return nil, ErrNoSpeech
+}
@@ -0,0 +1,36 @@
+package platform
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpeechCommandPicksFirstInstalledTool(t *testing.T) {
+	installed := map[string]bool{"espeak": true}
+	lookPath := func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	cmd, err := speechCommand("linux", "hello", lookPath)
+	if err != nil {
+		t.Fatalf("speechCommand failed: %v", err)
+	}
+	if filepath.Base(cmd.Path) != "espeak" {
+		t.Errorf("Expected espeak, got %s", cmd.Path)
+	}
+
+	// Text goes through stdin so it is never parsed as arguments
+	text, _ := io.ReadAll(cmd.Stdin)
+	if string(text) != "hello" {
+		t.Errorf("Expected text on stdin, got %q", text)
+	}
+
+	if _, err := speechCommand("darwin", "hello", lookPath); !errors.Is(err, ErrNoSpeech) {
+		t.Errorf("Expected ErrNoSpeech without say installed, got %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+// Package push sends phone notifications while the desktop app runs, so
+// users away from their desk can come back before the pet gets sick. It
+// posts to a user-configured ntfy topic or Gotify server.
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Provider is the push service behind the configured URL
+type Provider string
+
+const (
+	ProviderNtfy   Provider = "ntfy"   // POST the message to a topic URL, e.g. https://ntfy.sh/my-pet
+	ProviderGotify Provider = "gotify" // POST JSON to https://gotify.example.com/message
+
+	defaultCooldown      = 30 * time.Minute
+	defaultCheckInterval = time.Minute
+	sendTimeout          = 10 * time.Second
+)
+
+// Priorities shared by both providers (ntfy uses 1-5 directly)
+const (
+	PriorityDefault = 3
+	PriorityHigh    = 4
+	PriorityUrgent  = 5
+)
+
+// Notification is a single push message
+type Notification struct {
+	Title    string
+	Message  string
+	Priority int      // 1-5, 0 means PriorityDefault
+	Tags     []string // ntfy tags/emoji shortcodes, ignored by Gotify
+}
+
+// Companion is the character state pushes are about
+type Companion interface {
+	GetName() string
+	GetCriticalStates() []string // Stats at or below their critical threshold
+	IsDoNotDisturb() bool
+}
+
+// Config configures where and how often pushes are sent
+type Config struct {
+	URL           string        // ntfy topic URL or Gotify ".../message" URL
+	Token         string        // Access token; Bearer for ntfy, X-Gotify-Key for Gotify (optional)
+	Cooldown      time.Duration // Minimum time between repeats of the same alert (default: 30m)
+	CheckInterval time.Duration // How often critical stats are checked (default: 1m)
+}
+
+// Pusher watches for critical states and sends pushes about them and about
+// battle invitations. Each alert repeats at most once per cooldown.
+type Pusher struct {
+	config    Config
+	provider  Provider
+	companion Companion
+	client    *http.Client
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+	critical map[string]bool
+	invites  []string // Battle challengers held back by do-not-disturb
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// DetectProvider picks the provider from the URL: Gotify servers take
+// messages at "/message", anything else is treated as an ntfy topic
+func DetectProvider(rawURL string) (Provider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid push URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("push URL must be an http(s) URL, got %q", rawURL)
+	}
+	if strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/message") {
+		return ProviderGotify, nil
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return "", fmt.Errorf("push URL needs an ntfy topic, e.g. https://ntfy.sh/my-pet")
+	}
+	return ProviderNtfy, nil
+}
+
+// NewPusher creates a pusher for the configured URL
+func NewPusher(config Config, companion Companion) (*Pusher, error) {
+	if companion == nil {
+		return nil, fmt.Errorf("companion cannot be nil")
+	}
+	provider, err := DetectProvider(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaultCooldown
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultCheckInterval
+	}
+
+	return &Pusher{
+		config:    config,
+		provider:  provider,
+		companion: companion,
+		client:    &http.Client{Timeout: sendTimeout},
+		lastSent:  make(map[string]time.Time),
+		critical:  make(map[string]bool),
+	}, nil
+}
+
+// Provider returns the detected push service
+func (p *Pusher) Provider() Provider {
+	return p.provider
+}
+
+// Start checks critical stats in the background until Stop
+func (p *Pusher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p.mu.Lock()
+	p.cancel = cancel
+	p.done = make(chan struct{})
+	done := p.done
+	p.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.CheckCritical(ctx, time.Now())
+			}
+		}
+	}()
+}
+
+// Stop ends critical stat checks and waits for the loop to exit
+func (p *Pusher) Stop() {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// CheckCritical pushes stats that became critical since the last check.
+// A stat that stays critical is not repeated until it recovers or the
+// cooldown passes. Nothing is checked during do-not-disturb, so stats that
+// went critical meanwhile and held invitations are pushed once it ends.
+func (p *Pusher) CheckCritical(ctx context.Context, now time.Time) {
+	if p.companion.IsDoNotDisturb() {
+		return
+	}
+
+	p.mu.Lock()
+	invites := p.invites
+	p.invites = nil
+	p.mu.Unlock()
+	for _, from := range invites {
+		p.notify(ctx, now, "battle:"+from, p.battleInvitation(from))
+	}
+
+	states := p.companion.GetCriticalStates()
+	sort.Strings(states)
+
+	p.mu.Lock()
+	critical := make(map[string]bool, len(states))
+	var fresh []string
+	for _, stat := range states {
+		critical[stat] = true
+		if !p.critical[stat] || now.Sub(p.lastSent["critical:"+stat]) >= p.config.Cooldown {
+			fresh = append(fresh, stat)
+		}
+	}
+	p.critical = critical
+	p.mu.Unlock()
+
+	if len(fresh) == 0 {
+		return
+	}
+
+	name := p.companion.GetName()
+	p.notify(ctx, now, "critical:"+strings.Join(fresh, ","), Notification{
+		Title:    fmt.Sprintf("%s needs you!", name),
+		Message:  fmt.Sprintf("%s is critically low: %s. Come back soon!", name, strings.Join(fresh, ", ")),
+		Priority: PriorityUrgent,
+		Tags:     []string{"warning", "paw_prints"},
+	}, fresh...)
+}
+
+// NotifyBattleInvitation pushes a battle invitation in the background, or
+// holds it for the first check after do-not-disturb ends
+func (p *Pusher) NotifyBattleInvitation(from string) {
+	if p.companion.IsDoNotDisturb() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if !slices.Contains(p.invites, from) {
+			p.invites = append(p.invites, from)
+		}
+		return
+	}
+	go p.notify(context.Background(), time.Now(), "battle:"+from, p.battleInvitation(from))
+}
+
+// battleInvitation builds the push for a challenge from another player
+func (p *Pusher) battleInvitation(from string) Notification {
+	return Notification{
+		Title:    "Battle invitation",
+		Message:  fmt.Sprintf("%s challenged %s to a battle!", from, p.companion.GetName()),
+		Priority: PriorityHigh,
+		Tags:     []string{"crossed_swords"},
+	}
+}
+
+// notify sends n unless do-not-disturb is on or the same alert went out
+// within the cooldown. Critical stats are passed so each is rate-limited.
+func (p *Pusher) notify(ctx context.Context, now time.Time, key string, n Notification, stats ...string) {
+	if p.companion.IsDoNotDisturb() {
+		return
+	}
+
+	p.mu.Lock()
+	if now.Sub(p.lastSent[key]) < p.config.Cooldown {
+		p.mu.Unlock()
+		return
+	}
+	p.lastSent[key] = now
+	for _, stat := range stats {
+		p.lastSent["critical:"+stat] = now
+	}
+	p.mu.Unlock()
+
+	if err := p.Send(ctx, n); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"provider": p.provider,
+			"error":    err,
+		}).Warn("Push notification failed")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"provider": p.provider,
+		"title":    n.Title,
+	}).Info("Push notification sent")
+}
+
+// Send posts a notification to the configured service
+func (p *Pusher) Send(ctx context.Context, n Notification) error {
+	if n.Priority <= 0 {
+		n.Priority = PriorityDefault
+	}
+
+	req, err := p.newRequest(ctx, n)
+	if err != nil {
+		return fmt.Errorf("failed to build push request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The URL may carry a Gotify token, keep it out of logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send push: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("push service returned %s", resp.Status)
+	}
+	return nil
+}
+
+// newRequest builds the provider-specific HTTP request
+func (p *Pusher) newRequest(ctx context.Context, n Notification) (*http.Request, error) {
+	if p.provider == ProviderGotify {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":    n.Title,
+			"message":  n.Message,
+			"priority": n.Priority * 2, // Gotify uses 0-10
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if p.config.Token != "" {
+			req.Header.Set("X-Gotify-Key", p.config.Token)
+		}
+		return req, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, strings.NewReader(n.Message))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Title", n.Title)
+	req.Header.Set("Priority", strconv.Itoa(n.Priority))
+	if len(n.Tags) > 0 {
+		req.Header.Set("Tags", strings.Join(n.Tags, ","))
+	}
+	if p.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	}
+	return req, nil
+}
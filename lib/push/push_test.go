@@ -0,0 +1,213 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeCompanion struct {
+	mu       sync.Mutex
+	critical []string
+	dnd      bool
+}
+
+func (f *fakeCompanion) GetName() string { return "Mochi" }
+
+func (f *fakeCompanion) GetCriticalStates() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.critical...)
+}
+
+func (f *fakeCompanion) IsDoNotDisturb() bool { return f.dnd }
+
+// recorder is a push server that keeps every request it receives
+type recorder struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, string(body))
+	r.mu.Unlock()
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func TestDetectProvider(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    Provider
+		wantErr bool
+	}{
+		{"https://ntfy.sh/my-pet", ProviderNtfy, false},
+		{"https://gotify.example.com/message?token=abc", ProviderGotify, false},
+		{"http://192.168.1.5:8080/message/", ProviderGotify, false},
+		{"https://ntfy.sh/", "", true},
+		{"ftp://ntfy.sh/pet", "", true},
+		{"not a url", "", true},
+	}
+	for _, tt := range tests {
+		got, err := DetectProvider(tt.url)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("DetectProvider(%q) = %q, %v; want %q, error %v", tt.url, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSendNtfy(t *testing.T) {
+	server := &recorder{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	p, err := NewPusher(Config{URL: ts.URL + "/pet", Token: "tk_1"}, &fakeCompanion{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Send(context.Background(), Notification{Title: "Hi", Message: "Hungry", Tags: []string{"warning"}}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	req := server.requests[0]
+	if server.bodies[0] != "Hungry" || req.Header.Get("Title") != "Hi" || req.Header.Get("Priority") != "3" {
+		t.Errorf("Unexpected ntfy request: body %q headers %v", server.bodies[0], req.Header)
+	}
+	if req.Header.Get("Tags") != "warning" || req.Header.Get("Authorization") != "Bearer tk_1" {
+		t.Errorf("Expected tags and bearer token, got %v", req.Header)
+	}
+}
+
+func TestSendGotify(t *testing.T) {
+	server := &recorder{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	p, err := NewPusher(Config{URL: ts.URL + "/message", Token: "app"}, &fakeCompanion{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Send(context.Background(), Notification{Title: "Hi", Message: "Hungry", Priority: PriorityUrgent}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(server.bodies[0]), &body); err != nil {
+		t.Fatalf("Expected a JSON body: %v", err)
+	}
+	if body["title"] != "Hi" || body["message"] != "Hungry" || body["priority"] != float64(10) {
+		t.Errorf("Unexpected gotify body %v", body)
+	}
+	if server.requests[0].Header.Get("X-Gotify-Key") != "app" {
+		t.Error("Expected the app token header")
+	}
+}
+
+func TestSendError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	p, _ := NewPusher(Config{URL: ts.URL + "/pet"}, &fakeCompanion{})
+	if err := p.Send(context.Background(), Notification{Message: "x"}); err == nil {
+		t.Error("Expected an error for a rejected push")
+	}
+}
+
+func TestCheckCritical(t *testing.T) {
+	server := &recorder{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	companion := &fakeCompanion{}
+	p, err := NewPusher(Config{URL: ts.URL + "/pet", Cooldown: time.Hour}, companion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+	p.CheckCritical(ctx, now)
+	if server.count() != 0 {
+		t.Fatal("Nothing critical, nothing to push")
+	}
+
+	companion.critical = []string{"hunger"}
+	p.CheckCritical(ctx, now.Add(time.Minute))
+	if server.count() != 1 || server.bodies[0] != "Mochi is critically low: hunger. Come back soon!" {
+		t.Fatalf("Expected one hunger push, got %v", server.bodies)
+	}
+
+	// Still critical: no repeat within the cooldown
+	p.CheckCritical(ctx, now.Add(2*time.Minute))
+	if server.count() != 1 {
+		t.Error("A stat that stays critical should not repeat within the cooldown")
+	}
+
+	// Recovered and critical again: still rate-limited
+	companion.critical = nil
+	p.CheckCritical(ctx, now.Add(3*time.Minute))
+	companion.critical = []string{"hunger"}
+	p.CheckCritical(ctx, now.Add(4*time.Minute))
+	if server.count() != 1 {
+		t.Error("Flapping stats should respect the cooldown")
+	}
+
+	p.CheckCritical(ctx, now.Add(2*time.Hour))
+	if server.count() != 2 {
+		t.Error("Expected a reminder after the cooldown")
+	}
+
+	companion.dnd = true
+	companion.critical = []string{"hunger", "energy"}
+	p.CheckCritical(ctx, now.Add(5*time.Hour))
+	if server.count() != 2 {
+		t.Error("Do-not-disturb should silence pushes")
+	}
+
+	// What went critical during do-not-disturb is pushed once it ends
+	companion.dnd = false
+	p.CheckCritical(ctx, now.Add(6*time.Hour))
+	if server.count() != 3 || server.bodies[2] != "Mochi is critically low: energy, hunger. Come back soon!" {
+		t.Errorf("Expected the held critical stats after do-not-disturb, got %v", server.bodies)
+	}
+}
+
+func TestBattleInvitationHeldDuringDoNotDisturb(t *testing.T) {
+	server := &recorder{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	companion := &fakeCompanion{dnd: true}
+	p, err := NewPusher(Config{URL: ts.URL + "/pet", Cooldown: time.Hour}, companion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.NotifyBattleInvitation("Rival")
+	p.NotifyBattleInvitation("Rival")
+	p.CheckCritical(context.Background(), time.Now())
+	if server.count() != 0 {
+		t.Fatal("Expected the invitation to wait for do-not-disturb to end")
+	}
+
+	companion.dnd = false
+	p.CheckCritical(context.Background(), time.Now())
+	if server.count() != 1 || server.bodies[0] != "Rival challenged Mochi to a battle!" {
+		t.Errorf("Expected one held invitation, got %v", server.bodies)
+	}
+}
@@ -0,0 +1,154 @@
+package replay
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// newTestCharacter creates a character with hunger decaying 1 point a minute
+func newTestCharacter(t *testing.T) *character.Character {
+	t.Helper()
+	dir := t.TempDir()
+
+	// Minimal valid GIF data
+	validGIF := []byte{71, 73, 70, 56, 57, 97, 1, 0, 1, 0, 128, 0, 0, 255, 255, 255, 0, 0, 0, 44, 0, 0, 0, 0, 1, 0, 1, 0, 0, 2, 2, 68, 1, 0, 59}
+	for _, name := range []string{"idle.gif", "talking.gif", "eating.gif", "hungry.gif"} {
+		if err := os.WriteFile(filepath.Join(dir, name), validGIF, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	card := `{
+		"name": "Script Pet",
+		"description": "A pet for replay tests",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "eating": "eating.gif", "hungry": "hungry.gif"},
+		"stats": {
+			"hunger": {"initial": 100, "max": 100, "degradationRate": 1.0, "criticalThreshold": 20}
+		},
+		"gameRules": {"statsDecayInterval": 60, "autoSaveInterval": 300},
+		"interactions": {
+			"feed": {"triggers": ["rightclick"], "effects": {"hunger": 25}, "animations": ["eating"], "responses": ["Yum!"], "cooldown": 30}
+		},
+		"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 128}
+	}`
+	cardPath := filepath.Join(dir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(card), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	char, err := character.New(loaded, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return char
+}
+
+func TestParse(t *testing.T) {
+	steps, err := Parse(strings.NewReader(`
+# Morning routine
+click
+feed            # bare interaction name
+chat is C# fun?
+wait 1d2h
+assert hunger >= 50
+assert response contains good morning
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 6 {
+		t.Fatalf("Expected 6 steps, got %d", len(steps))
+	}
+	if steps[1].Command != "interact" || steps[1].Args[0] != "feed" || steps[1].Line != 4 {
+		t.Errorf("Expected a bare name to become an interaction, got %+v", steps[1])
+	}
+	if steps[2].Args[0] != "is C# fun?" {
+		t.Errorf("Expected the chat message whole, got %q", steps[2].Args[0])
+	}
+
+	invalid := []string{
+		"",
+		"wait soon",
+		"wait -5m",
+		"click twice",
+		"assert hunger > lots",
+		"assert state > idle",
+		"jump over the moon",
+	}
+	for _, script := range invalid {
+		if _, err := Parse(strings.NewReader(script)); err == nil {
+			t.Errorf("Expected %q to be rejected", script)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90s":  90 * time.Second,
+		"5m":   5 * time.Minute,
+		"2d":   48 * time.Hour,
+		"1d6h": 30 * time.Hour,
+	}
+	for in, want := range tests {
+		if got, err := ParseDuration(in); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v", in, got, err)
+		}
+	}
+}
+
+func TestRunnerVirtualTime(t *testing.T) {
+	steps, err := Parse(strings.NewReader(`
+wait 30m
+assert hunger <= 71
+assert hunger >= 69
+feed
+assert response == Yum!
+assert hunger > 90
+feed
+wait 90m
+assert hunger < 20
+assert state == idle
+assert hunger > 50
+interact dance
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now()
+	report := NewRunner(newTestCharacter(t)).Run(steps)
+	if time.Since(started) > 10*time.Second {
+		t.Errorf("Expected virtual time, run took %s", time.Since(started))
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	t.Log(out.String())
+
+	if report.Passed != 10 || report.Failed != 2 {
+		t.Fatalf("Expected 10 passed and 2 failed, got %d and %d", report.Passed, report.Failed)
+	}
+	if report.Simulated != 2*time.Hour {
+		t.Errorf("Expected 2h simulated, got %s", report.Simulated)
+	}
+	if !strings.Contains(out.String(), "FAIL  line 12: assert hunger > 50 → hunger is") {
+		t.Errorf("Expected the failed assertion to show the actual value")
+	}
+	if !strings.Contains(out.String(), "card has no interaction 'dance'") {
+		t.Errorf("Expected an unknown interaction to fail")
+	}
+	if !strings.Contains(out.String(), "unavailable (cooldown or requirements)") {
+		t.Errorf("Expected the second feed to hit the cooldown")
+	}
+}
@@ -0,0 +1,227 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// maxUpdatesPerWait bounds the character updates a single wait runs, so a
+// "wait 30d" on a card with a short decay interval still finishes quickly
+const maxUpdatesPerWait = 20000
+
+// Result is the outcome of one step
+type Result struct {
+	Step    Step
+	Passed  bool
+	Message string // What happened: the dialog text, states entered or why an assertion failed
+}
+
+// Report is the outcome of a whole script
+type Report struct {
+	Character string
+	Results   []Result
+	Passed    int
+	Failed    int
+	Simulated time.Duration // Virtual time that passed
+	Duration  time.Duration // Real time the run took
+}
+
+// OK reports whether every step passed
+func (r *Report) OK() bool {
+	return r.Failed == 0
+}
+
+// Write prints the report, one line per step and a summary
+func (r *Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Script for %s\n", r.Character)
+	for _, result := range r.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		line := fmt.Sprintf("  %s  line %d: %s", status, result.Step.Line, result.Step.Text)
+		if result.Message != "" {
+			line += " → " + result.Message
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "%d passed, %d failed; %s simulated in %s\n",
+		r.Passed, r.Failed, r.Simulated, r.Duration.Round(time.Millisecond))
+}
+
+// Runner plays a script against one character
+type Runner struct {
+	char         *character.Character
+	lastResponse string
+}
+
+// NewRunner creates a runner for char. The character's timestamps are moved
+// as virtual time passes, so use a fresh character rather than a live one.
+func NewRunner(char *character.Character) *Runner {
+	return &Runner{char: char}
+}
+
+// Run plays every step in order. Failed steps are reported and the script
+// carries on, so one run shows every broken expectation.
+func (r *Runner) Run(steps []Step) *Report {
+	started := time.Now()
+	report := &Report{Character: r.char.GetName()}
+
+	for _, step := range steps {
+		result := Result{Step: step, Passed: true}
+		switch step.Command {
+		case "wait":
+			d, _ := ParseDuration(step.Args[0])
+			result.Message = r.wait(d)
+			report.Simulated += d
+		case "assert":
+			result.Passed, result.Message = r.check(step.Args)
+		default:
+			result.Passed, result.Message = r.act(step)
+		}
+
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	report.Duration = time.Since(started)
+	return report
+}
+
+// act performs an interaction and remembers its dialog for response assertions
+func (r *Runner) act(step Step) (bool, string) {
+	var text string
+	switch step.Command {
+	case "click":
+		text = r.char.HandleClick()
+	case "rightclick":
+		text = r.char.HandleRightClick()
+	case "hover":
+		text = r.char.HandleHover()
+	case "chat":
+		text = r.char.HandleChatMessage(step.Args[0])
+	case "event":
+		if !r.char.IsGeneralEventAvailable(step.Args[0]) {
+			r.lastResponse = ""
+			return false, fmt.Sprintf("event '%s' is not available", step.Args[0])
+		}
+		text = r.char.HandleGeneralEvent(step.Args[0])
+	case "interact":
+		name := step.Args[0]
+		if _, ok := r.char.GetCard().Interactions[name]; !ok {
+			r.lastResponse = ""
+			return false, fmt.Sprintf("card has no interaction '%s'", name)
+		}
+		if !r.char.CanUseGameInteraction(name) {
+			r.lastResponse = ""
+			return true, "unavailable (cooldown or requirements)"
+		}
+		text = r.char.HandleGameInteraction(name)
+	}
+
+	r.lastResponse = text
+	if text == "" {
+		return true, "no dialog"
+	}
+	return true, strconv.Quote(text)
+}
+
+// wait lets d pass in steps of the decay interval and lists the states the
+// character went through, in order
+func (r *Runner) wait(d time.Duration) string {
+	step := r.char.GetGameState().DecayInterval()
+	if d/step > maxUpdatesPerWait {
+		step = d / maxUpdatesPerWait
+	}
+
+	seen := make(map[string]bool)
+	var states []string
+	for remaining := d; remaining > 0; remaining -= step {
+		r.char.AdvanceTime(min(step, remaining))
+		if state := r.char.GetCurrentState(); !seen[state] {
+			seen[state] = true
+			states = append(states, state)
+		}
+	}
+
+	return "states: " + strings.Join(states, ", ")
+}
+
+// check evaluates an assertion, returning the actual value on failure
+func (r *Runner) check(args []string) (bool, string) {
+	subject, operator := args[0], args[1]
+	switch subject {
+	case "state":
+		return compareText(r.char.GetCurrentState(), operator, args[2], "state")
+	case "response":
+		return compareText(r.lastResponse, operator, strings.Join(args[2:], " "), "response")
+	}
+
+	gameState := r.char.GetGameState()
+	if gameState == nil {
+		return false, "character has no stats"
+	}
+
+	var actual float64
+	if subject == "mood" {
+		actual = gameState.GetOverallMood()
+	} else {
+		stats := gameState.GetStats()
+		value, ok := stats[subject]
+		if !ok {
+			return false, fmt.Sprintf("card has no stat '%s'", subject)
+		}
+		actual = value
+	}
+
+	expected, _ := strconv.ParseFloat(args[2], 64)
+	if compareNumbers(actual, operator, expected) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s is %.1f", subject, actual)
+}
+
+// compareNumbers applies a numeric operator checked by Parse
+func compareNumbers(actual float64, operator string, expected float64) bool {
+	switch operator {
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	case "==":
+		return actual == expected
+	case "!=":
+		return actual != expected
+	}
+	return false
+}
+
+// compareText applies a text operator checked by Parse
+func compareText(actual, operator, expected, subject string) (bool, string) {
+	var ok bool
+	switch operator {
+	case "==":
+		ok = actual == expected
+	case "!=":
+		ok = actual != expected
+	case "contains":
+		ok = strings.Contains(actual, expected)
+	}
+	if ok {
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s is %q", subject, actual)
+}
@@ -0,0 +1,186 @@
+// Package replay runs characters on virtual time, for regression-testing
+// character cards and tuning game balance without waiting in real time:
+// scripted interaction sequences with assertions, and long simulations that
+// record stat trajectories (see Simulate).
+//
+// A script is a text file with one step per line; blank lines and lines
+// starting with # are ignored:
+//
+//	click                  # left click, also rightclick and hover
+//	feed                   # any interaction in the card, same as "interact feed"
+//	chat how are you?      # send a chat message
+//	event morning_routine  # start a general dialog event
+//	wait 5m                # let 5 minutes pass instantly (s, m, h and d units)
+//	assert hunger > 50     # compare a stat: > >= < <= == !=
+//	assert mood >= 60      # overall mood, 0-100
+//	assert state == happy  # current animation state
+//	assert response contains Yum  # the last step's dialog text
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Step is one parsed script line
+type Step struct {
+	Line    int      // 1-based line number in the script
+	Command string   // click, rightclick, hover, interact, chat, event, wait or assert
+	Args    []string // Command arguments, already split
+	Text    string   // The line as written, for the report
+}
+
+// assertion operators by subject kind
+var (
+	numericOperators = map[string]bool{">": true, ">=": true, "<": true, "<=": true, "==": true, "!=": true}
+	textOperators    = map[string]bool{"==": true, "!=": true, "contains": true}
+)
+
+// LoadScript reads and parses the script at path
+func LoadScript(path string) ([]Step, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open script: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a script, checking every step before anything runs so a typo
+// late in a long script fails fast
+func Parse(r io.Reader) ([]Step, error) {
+	var steps []Step
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+
+		step, err := parseStep(line, text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("script has no steps")
+	}
+	return steps, nil
+}
+
+// stripComment removes a # comment. Chat messages keep a # that isn't
+// preceded by a space, e.g. "chat C# or Go?".
+func stripComment(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+	if i := strings.Index(line, " #"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// parseStep parses and checks a single non-empty line
+func parseStep(line int, text string) (Step, error) {
+	fields := strings.Fields(text)
+	step := Step{Line: line, Command: strings.ToLower(fields[0]), Args: fields[1:], Text: text}
+
+	switch step.Command {
+	case "click", "rightclick", "hover":
+		if len(step.Args) != 0 {
+			return step, fmt.Errorf("%s takes no arguments", step.Command)
+		}
+	case "interact", "event":
+		if len(step.Args) != 1 {
+			return step, fmt.Errorf("usage: %s <name>", step.Command)
+		}
+	case "chat":
+		message := strings.TrimSpace(strings.TrimPrefix(text, fields[0]))
+		if message == "" {
+			return step, fmt.Errorf("usage: chat <message>")
+		}
+		step.Args = []string{message}
+	case "wait":
+		if len(step.Args) != 1 {
+			return step, fmt.Errorf("usage: wait <duration>, e.g. wait 5m")
+		}
+		if _, err := ParseDuration(step.Args[0]); err != nil {
+			return step, err
+		}
+	case "assert":
+		if err := checkAssertion(step.Args); err != nil {
+			return step, err
+		}
+	default:
+		// Anything else names an interaction from the card
+		if len(step.Args) != 0 {
+			return step, fmt.Errorf("unknown command '%s'", step.Command)
+		}
+		step.Args = []string{fields[0]}
+		step.Command = "interact"
+	}
+	return step, nil
+}
+
+// checkAssertion validates "subject operator value"
+func checkAssertion(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: assert <stat|mood|state|response> <operator> <value>")
+	}
+
+	subject, operator := args[0], args[1]
+	switch subject {
+	case "state":
+		if !textOperators[operator] || len(args) != 3 {
+			return fmt.Errorf("state assertions use ==, != or contains with one value")
+		}
+	case "response":
+		if !textOperators[operator] {
+			return fmt.Errorf("response assertions use ==, != or contains")
+		}
+	default:
+		if !numericOperators[operator] || len(args) != 3 {
+			return fmt.Errorf("%s assertions use >, >=, <, <=, == or != with a number", subject)
+		}
+		if _, err := strconv.ParseFloat(args[2], 64); err != nil {
+			return fmt.Errorf("'%s' is not a number", args[2])
+		}
+	}
+	return nil
+}
+
+// ParseDuration accepts Go durations plus a "d" suffix for days, e.g. "2d"
+// or "1d12h"
+func ParseDuration(value string) (time.Duration, error) {
+	s := value
+	var days time.Duration
+	if i := strings.Index(s, "d"); i > 0 {
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		s = s[i+1:]
+	}
+
+	var rest time.Duration
+	if s != "" {
+		var err error
+		if rest, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+	}
+	if d := days + rest; d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("duration must be positive")
+}
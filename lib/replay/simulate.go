@@ -0,0 +1,272 @@
+package replay
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// Pattern repeats an interaction at a fixed interval, e.g. feeding every 4 hours
+type Pattern struct {
+	Interaction string
+	Every       time.Duration
+}
+
+// ParsePatterns parses "feed:4h,play:8h". An empty string means no care at
+// all, which shows how fast stats fall on their own.
+func ParsePatterns(s string) ([]Pattern, error) {
+	var patterns []Pattern
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, every, found := strings.Cut(item, ":")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid pattern '%s', use interaction:interval like feed:4h", item)
+		}
+		d, err := ParseDuration(every)
+		if err != nil {
+			return nil, fmt.Errorf("pattern '%s': %w", item, err)
+		}
+		patterns = append(patterns, Pattern{Interaction: name, Every: d})
+	}
+	return patterns, nil
+}
+
+// ParseQuietHours parses "23:00-07:00" into minutes after midnight. The
+// range may wrap past midnight.
+func ParseQuietHours(s string) (start, end int, err error) {
+	from, to, found := strings.Cut(s, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid quiet hours '%s', use HH:MM-HH:MM", s)
+	}
+	if start, err = ParseClock(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = ParseClock(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// ParseClock parses HH:MM into minutes after midnight
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', use HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// SimulationConfig describes a simulated stretch of the character's life
+type SimulationConfig struct {
+	Duration time.Duration // How long to simulate
+	Sample   time.Duration // Time between CSV rows, default 1 hour
+	Patterns []Pattern     // Interactions the simulated owner performs
+
+	// Quiet hours, in minutes after midnight of the simulated clock, when the
+	// owner is asleep and nothing is performed. Equal values mean none.
+	QuietStart, QuietEnd int
+	StartClock           int // Time of day the simulation starts, minutes after midnight
+}
+
+// StatSummary is how one stat fared over a simulation
+type StatSummary struct {
+	Name          string
+	Min, Max, End float64
+	CriticalAt    time.Duration // First time the stat reached its critical threshold, -1 if never
+	CriticalTime  time.Duration // Total time spent at or below the threshold
+}
+
+// SimulationResult summarizes a simulation for balancing
+type SimulationResult struct {
+	Stats        []StatSummary
+	Performed    map[string]int // Interactions performed by name
+	Unavailable  map[string]int // Interactions skipped for cooldowns or requirements
+	Steps        int            // Character updates run
+	RealDuration time.Duration
+}
+
+// Simulate fast-forwards char through cfg, writing a CSV row of every stat,
+// the mood and the animation state each sample interval. The character is
+// changed, so pass a fresh one.
+func Simulate(char *character.Character, cfg SimulationConfig, w io.Writer) (*SimulationResult, error) {
+	gameState := char.GetGameState()
+	if gameState == nil {
+		return nil, fmt.Errorf("character has no stats to simulate")
+	}
+	for _, pattern := range cfg.Patterns {
+		if _, ok := char.GetCard().Interactions[pattern.Interaction]; !ok {
+			return nil, fmt.Errorf("card has no interaction '%s'", pattern.Interaction)
+		}
+	}
+	if cfg.Sample <= 0 {
+		cfg.Sample = time.Hour
+	}
+
+	started := time.Now()
+	stats := gameState.GetStats()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := csv.NewWriter(w)
+	header := append([]string{"hours", "day", "clock"}, names...)
+	if err := out.Write(append(header, "mood", "state", "interactions")); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	result := &SimulationResult{Performed: make(map[string]int), Unavailable: make(map[string]int)}
+	summaries := make(map[string]*StatSummary, len(names))
+	for _, name := range names {
+		value := stats[name]
+		summaries[name] = &StatSummary{Name: name, Min: value, Max: value, End: value, CriticalAt: -1}
+	}
+	thresholds := criticalThresholds(char.GetCard())
+
+	step := min(gameState.DecayInterval(), cfg.Sample)
+	nextDue := make([]time.Duration, len(cfg.Patterns))
+	for i, pattern := range cfg.Patterns {
+		nextDue[i] = pattern.Every
+	}
+
+	var performed []string
+	writeRow := func(elapsed time.Duration) error {
+		current := char.GetGameState().GetStats()
+		minute := (cfg.StartClock + int(elapsed/time.Minute)) % (24 * 60)
+		row := []string{
+			strconv.FormatFloat(elapsed.Hours(), 'f', 2, 64),
+			strconv.Itoa(int(elapsed/(24*time.Hour)) + 1),
+			fmt.Sprintf("%02d:%02d", minute/60, minute%60),
+		}
+		for _, name := range names {
+			row = append(row, strconv.FormatFloat(current[name], 'f', 2, 64))
+		}
+		row = append(row,
+			strconv.FormatFloat(char.GetGameState().GetOverallMood(), 'f', 2, 64),
+			char.GetCurrentState(),
+			strings.Join(performed, " "))
+		performed = performed[:0]
+		return out.Write(row)
+	}
+
+	if err := writeRow(0); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	nextSample := cfg.Sample
+	for elapsed := time.Duration(0); elapsed < cfg.Duration; {
+		// Land exactly on sample times so rows are evenly spaced
+		tick := min(step, nextSample-elapsed, cfg.Duration-elapsed)
+		char.AdvanceTime(tick)
+		elapsed += tick
+		result.Steps++
+
+		quiet := cfg.isQuiet(elapsed)
+		for i, pattern := range cfg.Patterns {
+			if elapsed < nextDue[i] || quiet {
+				continue
+			}
+			nextDue[i] = elapsed + pattern.Every
+			if !char.CanUseGameInteraction(pattern.Interaction) {
+				result.Unavailable[pattern.Interaction]++
+				continue
+			}
+			char.HandleGameInteraction(pattern.Interaction)
+			result.Performed[pattern.Interaction]++
+			performed = append(performed, pattern.Interaction)
+		}
+
+		current := char.GetGameState().GetStats()
+		for _, name := range names {
+			summaries[name].track(current[name], thresholds[name], elapsed, tick)
+		}
+
+		if elapsed == nextSample || elapsed == cfg.Duration {
+			nextSample += cfg.Sample
+			if err := writeRow(elapsed); err != nil {
+				return nil, fmt.Errorf("failed to write CSV: %w", err)
+			}
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, name := range names {
+		result.Stats = append(result.Stats, *summaries[name])
+	}
+	result.RealDuration = time.Since(started)
+	return result, nil
+}
+
+// isQuiet reports whether elapsed falls in the owner's quiet hours
+func (cfg SimulationConfig) isQuiet(elapsed time.Duration) bool {
+	if cfg.QuietStart == cfg.QuietEnd {
+		return false
+	}
+	minute := (cfg.StartClock + int(elapsed/time.Minute)) % (24 * 60)
+	if cfg.QuietStart < cfg.QuietEnd {
+		return minute >= cfg.QuietStart && minute < cfg.QuietEnd
+	}
+	return minute >= cfg.QuietStart || minute < cfg.QuietEnd
+}
+
+// track records a stat value seen after a tick
+func (s *StatSummary) track(value, threshold float64, elapsed, tick time.Duration) {
+	s.Min = min(s.Min, value)
+	s.Max = max(s.Max, value)
+	s.End = value
+	if threshold > 0 && value <= threshold {
+		if s.CriticalAt < 0 {
+			s.CriticalAt = elapsed
+		}
+		s.CriticalTime += tick
+	}
+}
+
+// criticalThresholds returns each stat's critical threshold from the card
+func criticalThresholds(card *character.CharacterCard) map[string]float64 {
+	thresholds := make(map[string]float64, len(card.Stats))
+	for name, stat := range card.Stats {
+		thresholds[name] = stat.CriticalThreshold
+	}
+	return thresholds
+}
+
+// WriteSummary prints how each stat fared, for tuning decay rates and thresholds
+func (r *SimulationResult) WriteSummary(w io.Writer) {
+	for _, stat := range r.Stats {
+		line := fmt.Sprintf("%-12s min %6.1f  max %6.1f  end %6.1f", stat.Name, stat.Min, stat.Max, stat.End)
+		if stat.CriticalAt >= 0 {
+			line += fmt.Sprintf("  critical after %s, %s in total", stat.CriticalAt, stat.CriticalTime)
+		} else {
+			line += "  never critical"
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	names := make([]string, 0, len(r.Performed)+len(r.Unavailable))
+	for name := range r.Performed {
+		names = append(names, name)
+	}
+	for name := range r.Unavailable {
+		if _, ok := r.Performed[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%-12s performed %d times, unavailable %d times\n", name, r.Performed[name], r.Unavailable[name])
+	}
+	fmt.Fprintf(w, "%d updates in %s\n", r.Steps, r.RealDuration.Round(time.Millisecond))
+}
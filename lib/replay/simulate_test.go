@@ -0,0 +1,86 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePatterns(t *testing.T) {
+	patterns, err := ParsePatterns("feed:4h, play:1d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 2 || patterns[0] != (Pattern{"feed", 4 * time.Hour}) || patterns[1].Every != 24*time.Hour {
+		t.Errorf("Unexpected patterns %+v", patterns)
+	}
+	if patterns, err := ParsePatterns(""); err != nil || len(patterns) != 0 {
+		t.Errorf("Expected no patterns for an empty string, got %v, %v", patterns, err)
+	}
+	for _, invalid := range []string{"feed", "feed:often", ":4h"} {
+		if _, err := ParsePatterns(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	start, end, err := ParseQuietHours("23:00-07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := SimulationConfig{QuietStart: start, QuietEnd: end, StartClock: 8 * 60}
+	if cfg.isQuiet(time.Hour) {
+		t.Error("09:00 should not be quiet")
+	}
+	if !cfg.isQuiet(16 * time.Hour) {
+		t.Error("00:00 should be quiet")
+	}
+	if _, _, err := ParseQuietHours("late"); err == nil {
+		t.Error("Expected invalid quiet hours to be rejected")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	var out bytes.Buffer
+	result, err := Simulate(newTestCharacter(t), SimulationConfig{
+		Duration: 12 * time.Hour,
+		Sample:   2 * time.Hour,
+		Patterns: []Pattern{{"feed", 3 * time.Hour}},
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(rows[0], ","); got != "hours,day,clock,hunger,mood,state,interactions" {
+		t.Errorf("Unexpected header %s", got)
+	}
+	if len(rows) != 8 { // header, hour 0 and one row every 2 hours
+		t.Fatalf("Expected 8 rows, got %d", len(rows))
+	}
+	if rows[3][0] != "4.00" || rows[3][6] != "feed" {
+		t.Errorf("Expected the 3h feeding in the 4h row, got %v", rows[3])
+	}
+
+	if result.Performed["feed"] != 4 {
+		t.Errorf("Expected 4 feedings in 12 hours, got %d", result.Performed["feed"])
+	}
+	hunger := result.Stats[0]
+	if hunger.Name != "hunger" || hunger.Max != 100 {
+		t.Errorf("Unexpected summary %+v", hunger)
+	}
+	// Hunger falls 60 an hour between feedings of 25, so it bottoms out
+	if hunger.Min != 0 || hunger.CriticalAt != 80*time.Minute {
+		t.Errorf("Expected hunger to turn critical after 80 minutes, got %+v", hunger)
+	}
+
+	if _, err := Simulate(newTestCharacter(t), SimulationConfig{Duration: time.Hour, Patterns: []Pattern{{"dance", time.Hour}}}, &out); err == nil {
+		t.Error("Expected an unknown interaction to be rejected")
+	}
+}
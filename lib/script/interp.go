@@ -0,0 +1,687 @@
+package script
+
+import (
+	"fmt"
+	"math"
+)
+
+// control tells enclosing statements how a block finished
+type control int
+
+const (
+	ctlNone control = iota
+	ctlBreak
+	ctlReturn
+)
+
+// cell holds one variable so closures can share it
+type cell struct {
+	value Value
+}
+
+// scope holds the local variables of a block
+type scope struct {
+	vars     map[string]*cell
+	parent   *scope
+	function bool    // Outermost scope of a function call
+	varargs  []Value // The call's extra arguments, for "..."
+}
+
+// newScope opens a block scope inside parent
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent}
+}
+
+// declare creates a local variable in this scope
+func (s *scope) declare(name string, value Value) {
+	if s.vars == nil {
+		s.vars = make(map[string]*cell)
+	}
+	s.vars[name] = &cell{value: value}
+}
+
+// lookup finds the innermost local variable with the name
+func (s *scope) lookup(name string) *cell {
+	for sc := s; sc != nil; sc = sc.parent {
+		if c, ok := sc.vars[name]; ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// callVarargs returns "..." of the innermost function
+func (s *scope) callVarargs() []Value {
+	for sc := s; sc != nil; sc = sc.parent {
+		if sc.function {
+			return sc.varargs
+		}
+	}
+	return nil
+}
+
+// exec runs a block in a new scope
+func (st *State) exec(b *block, parent *scope) (control, []Value, error) {
+	return st.execIn(b, newScope(parent))
+}
+
+// execIn runs a block's statements in the given scope
+func (st *State) execIn(b *block, sc *scope) (control, []Value, error) {
+	for _, s := range b.stmts {
+		if err := st.step(); err != nil {
+			return ctlNone, nil, err
+		}
+		ctl, rets, err := st.execStmt(s, sc)
+		if err != nil || ctl != ctlNone {
+			return ctl, rets, err
+		}
+	}
+	return ctlNone, nil, nil
+}
+
+// execStmt runs one statement
+func (st *State) execStmt(s stmt, sc *scope) (control, []Value, error) {
+	switch s := s.(type) {
+	case *localStmt:
+		values, err := st.evalMulti(s.exprs, sc)
+		if err != nil {
+			return ctlNone, nil, err
+		}
+		for i, name := range s.names {
+			sc.declare(name, valueAt(values, i))
+		}
+	case *localFuncStmt:
+		sc.declare(s.name, nil) // Declared first so the function can call itself
+		sc.lookup(s.name).value = &Function{def: s.fn, env: sc}
+	case *assignStmt:
+		return ctlNone, nil, st.assign(s, sc)
+	case *callStmt:
+		_, err := st.evalCall(s.call, sc)
+		return ctlNone, nil, err
+	case *doStmt:
+		return st.exec(s.body, sc)
+	case *whileStmt:
+		return st.execWhile(s, sc)
+	case *repeatStmt:
+		return st.execRepeat(s, sc)
+	case *ifStmt:
+		for i, cond := range s.conds {
+			v, err := st.eval(cond, sc)
+			if err != nil {
+				return ctlNone, nil, err
+			}
+			if Truthy(v) {
+				return st.exec(s.blocks[i], sc)
+			}
+		}
+		if s.elseBlock != nil {
+			return st.exec(s.elseBlock, sc)
+		}
+	case *numForStmt:
+		return st.execNumFor(s, sc)
+	case *genForStmt:
+		return st.execGenFor(s, sc)
+	case *returnStmt:
+		values, err := st.evalMulti(s.exprs, sc)
+		return ctlReturn, values, err
+	case *breakStmt:
+		return ctlBreak, nil, nil
+	default:
+		return ctlNone, nil, fmt.Errorf("unknown statement %T", s)
+	}
+	return ctlNone, nil, nil
+}
+
+// assign evaluates all right-hand values before storing any of them
+func (st *State) assign(s *assignStmt, sc *scope) error {
+	type slot struct {
+		table *Table
+		key   Value
+		name  string
+		line  int
+	}
+	slots := make([]slot, len(s.targets))
+	for i, target := range s.targets {
+		switch t := target.(type) {
+		case *nameExpr:
+			slots[i] = slot{name: t.name, line: t.line}
+		case *indexExpr:
+			obj, err := st.eval(t.obj, sc)
+			if err != nil {
+				return err
+			}
+			key, err := st.eval(t.key, sc)
+			if err != nil {
+				return err
+			}
+			table, ok := obj.(*Table)
+			if !ok {
+				return st.errorf(t.line, "attempt to index a %s value", TypeName(obj))
+			}
+			slots[i] = slot{table: table, key: key, line: t.line}
+		}
+	}
+
+	values, err := st.evalMulti(s.exprs, sc)
+	if err != nil {
+		return err
+	}
+	for i, sl := range slots {
+		value := valueAt(values, i)
+		if sl.table == nil {
+			if c := sc.lookup(sl.name); c != nil {
+				c.value = value
+			} else if err := st.globals.Set(sl.name, value); err != nil {
+				return st.errorf(sl.line, "%v", err)
+			}
+			continue
+		}
+		if err := st.setIndex(sl.table, sl.key, value, sl.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setIndex stores into a table, reporting nil and NaN keys at the line
+func (st *State) setIndex(t *Table, key, value Value, line int) error {
+	if err := t.Set(key, value); err != nil {
+		return st.errorf(line, "%v", err)
+	}
+	return nil
+}
+
+func (st *State) execWhile(s *whileStmt, sc *scope) (control, []Value, error) {
+	for {
+		v, err := st.eval(s.cond, sc)
+		if err != nil {
+			return ctlNone, nil, err
+		}
+		if !Truthy(v) {
+			return ctlNone, nil, nil
+		}
+		ctl, rets, err := st.exec(s.body, sc)
+		if err != nil || ctl == ctlReturn {
+			return ctl, rets, err
+		}
+		if ctl == ctlBreak {
+			return ctlNone, nil, nil
+		}
+		if err := st.step(); err != nil {
+			return ctlNone, nil, err
+		}
+	}
+}
+
+func (st *State) execRepeat(s *repeatStmt, sc *scope) (control, []Value, error) {
+	for {
+		// The condition sees the body's locals
+		body := newScope(sc)
+		ctl, rets, err := st.execIn(s.body, body)
+		if err != nil || ctl == ctlReturn {
+			return ctl, rets, err
+		}
+		if ctl == ctlBreak {
+			return ctlNone, nil, nil
+		}
+		v, err := st.eval(s.cond, body)
+		if err != nil {
+			return ctlNone, nil, err
+		}
+		if Truthy(v) {
+			return ctlNone, nil, nil
+		}
+		if err := st.step(); err != nil {
+			return ctlNone, nil, err
+		}
+	}
+}
+
+func (st *State) execNumFor(s *numForStmt, sc *scope) (control, []Value, error) {
+	bounds := []expr{s.start, s.limit, s.step}
+	names := []string{"initial", "limit", "step"}
+	values := []float64{0, 0, 1}
+	for i, e := range bounds {
+		if e == nil {
+			continue
+		}
+		v, err := st.eval(e, sc)
+		if err != nil {
+			return ctlNone, nil, err
+		}
+		n, ok := ToNumber(v)
+		if !ok {
+			return ctlNone, nil, st.errorf(s.line, "'for' %s value must be a number", names[i])
+		}
+		values[i] = n
+	}
+	start, limit, step := values[0], values[1], values[2]
+	if step == 0 {
+		return ctlNone, nil, st.errorf(s.line, "'for' step is zero")
+	}
+
+	for i := start; (step > 0 && i <= limit) || (step < 0 && i >= limit); i += step {
+		body := newScope(sc)
+		body.declare(s.name, i)
+		ctl, rets, err := st.execIn(s.body, body)
+		if err != nil || ctl == ctlReturn {
+			return ctl, rets, err
+		}
+		if ctl == ctlBreak {
+			break
+		}
+		if err := st.step(); err != nil {
+			return ctlNone, nil, err
+		}
+	}
+	return ctlNone, nil, nil
+}
+
+func (st *State) execGenFor(s *genForStmt, sc *scope) (control, []Value, error) {
+	values, err := st.evalMulti(s.exprs, sc)
+	if err != nil {
+		return ctlNone, nil, err
+	}
+	iterator, state, position := valueAt(values, 0), valueAt(values, 1), valueAt(values, 2)
+
+	for {
+		results, err := st.call(iterator, []Value{state, position}, s.line)
+		if err != nil {
+			return ctlNone, nil, err
+		}
+		first := valueAt(results, 0)
+		if first == nil {
+			return ctlNone, nil, nil
+		}
+		position = first
+
+		body := newScope(sc)
+		for i, name := range s.names {
+			body.declare(name, valueAt(results, i))
+		}
+		ctl, rets, err := st.execIn(s.body, body)
+		if err != nil || ctl == ctlReturn {
+			return ctl, rets, err
+		}
+		if ctl == ctlBreak {
+			return ctlNone, nil, nil
+		}
+		if err := st.step(); err != nil {
+			return ctlNone, nil, err
+		}
+	}
+}
+
+// evalMulti evaluates an expression list; a call or "..." at the end
+// contributes all of its values
+func (st *State) evalMulti(exprs []expr, sc *scope) ([]Value, error) {
+	var values []Value
+	for i, e := range exprs {
+		if i == len(exprs)-1 {
+			switch e.(type) {
+			case *callExpr, *methodCallExpr:
+				rets, err := st.evalCall(e, sc)
+				if err != nil {
+					return nil, err
+				}
+				return append(values, rets...), nil
+			case *varargExpr:
+				return append(values, sc.callVarargs()...), nil
+			}
+		}
+		v, err := st.eval(e, sc)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// eval evaluates an expression to a single value
+func (st *State) eval(e expr, sc *scope) (Value, error) {
+	switch e := e.(type) {
+	case *constExpr:
+		return e.value, nil
+	case *varargExpr:
+		return valueAt(sc.callVarargs(), 0), nil
+	case *nameExpr:
+		if c := sc.lookup(e.name); c != nil {
+			return c.value, nil
+		}
+		return st.globals.Get(e.name), nil
+	case *indexExpr:
+		obj, err := st.eval(e.obj, sc)
+		if err != nil {
+			return nil, err
+		}
+		key, err := st.eval(e.key, sc)
+		if err != nil {
+			return nil, err
+		}
+		return st.index(obj, key, e.line)
+	case *callExpr, *methodCallExpr:
+		rets, err := st.evalCall(e, sc)
+		return valueAt(rets, 0), err
+	case *parenExpr:
+		return st.eval(e.e, sc)
+	case *funcExpr:
+		return &Function{def: e, env: sc}, nil
+	case *tableExpr:
+		return st.evalTable(e, sc)
+	case *unExpr:
+		return st.evalUnary(e, sc)
+	case *binExpr:
+		return st.evalBinary(e, sc)
+	}
+	return nil, fmt.Errorf("unknown expression %T", e)
+}
+
+// index reads obj[key]; strings index the string library for methods
+func (st *State) index(obj, key Value, line int) (Value, error) {
+	switch o := obj.(type) {
+	case *Table:
+		return o.Get(key), nil
+	case string:
+		if lib, ok := st.globals.Get("string").(*Table); ok {
+			return lib.Get(key), nil
+		}
+	}
+	return nil, st.errorf(line, "attempt to index a %s value", TypeName(obj))
+}
+
+func (st *State) evalTable(e *tableExpr, sc *scope) (Value, error) {
+	t := NewTable()
+	positional := 0
+	for i, item := range e.items {
+		if item.key != nil {
+			key, err := st.eval(item.key, sc)
+			if err != nil {
+				return nil, err
+			}
+			value, err := st.eval(item.value, sc)
+			if err != nil {
+				return nil, err
+			}
+			if err := st.setIndex(t, key, value, 0); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// A call or "..." last in the list contributes all of its values
+		values := []Value{}
+		if i == len(e.items)-1 {
+			var err error
+			if values, err = st.evalMulti([]expr{item.value}, sc); err != nil {
+				return nil, err
+			}
+		} else {
+			v, err := st.eval(item.value, sc)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		for _, v := range values {
+			positional++
+			if err := st.setIndex(t, float64(positional), v, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+func (st *State) evalUnary(e *unExpr, sc *scope) (Value, error) {
+	v, err := st.eval(e.e, sc)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "not":
+		return !Truthy(v), nil
+	case "-":
+		n, ok := ToNumber(v)
+		if !ok {
+			return nil, st.errorf(e.line, "attempt to perform arithmetic on a %s value", TypeName(v))
+		}
+		return -n, nil
+	case "#":
+		switch x := v.(type) {
+		case string:
+			return float64(len(x)), nil
+		case *Table:
+			return float64(x.Len()), nil
+		}
+		return nil, st.errorf(e.line, "attempt to get length of a %s value", TypeName(v))
+	}
+	return nil, st.errorf(e.line, "unknown operator %s", e.op)
+}
+
+func (st *State) evalBinary(e *binExpr, sc *scope) (Value, error) {
+	left, err := st.eval(e.l, sc)
+	if err != nil {
+		return nil, err
+	}
+	// and / or only evaluate the right side when they need it
+	switch e.op {
+	case "and":
+		if !Truthy(left) {
+			return left, nil
+		}
+		return st.eval(e.r, sc)
+	case "or":
+		if Truthy(left) {
+			return left, nil
+		}
+		return st.eval(e.r, sc)
+	}
+
+	right, err := st.eval(e.r, sc)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return rawEqual(left, right), nil
+	case "~=":
+		return !rawEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return st.compare(e.op, left, right, e.line)
+	case "..":
+		return st.concat(left, right, e.line)
+	}
+	return st.arith(e.op, left, right, e.line)
+}
+
+// rawEqual compares by value for primitives and by identity otherwise
+func rawEqual(a, b Value) bool {
+	return normalizeKey(a) == normalizeKey(b)
+}
+
+func (st *State) compare(op string, a, b Value, line int) (Value, error) {
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			switch op {
+			case "<":
+				return x < y, nil
+			case "<=":
+				return x <= y, nil
+			case ">":
+				return x > y, nil
+			default:
+				return x >= y, nil
+			}
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			switch op {
+			case "<":
+				return x < y, nil
+			case "<=":
+				return x <= y, nil
+			case ">":
+				return x > y, nil
+			default:
+				return x >= y, nil
+			}
+		}
+	}
+	ta, tb := TypeName(a), TypeName(b)
+	if ta == tb {
+		return nil, st.errorf(line, "attempt to compare two %s values", ta)
+	}
+	return nil, st.errorf(line, "attempt to compare %s with %s", ta, tb)
+}
+
+func (st *State) concat(a, b Value, line int) (Value, error) {
+	parts := [2]string{}
+	for i, v := range []Value{a, b} {
+		switch x := v.(type) {
+		case string:
+			parts[i] = x
+		case float64:
+			parts[i] = formatNumber(x)
+		default:
+			return nil, st.errorf(line, "attempt to concatenate a %s value", TypeName(v))
+		}
+	}
+	if len(parts[0])+len(parts[1]) > st.opts.MaxStringLength {
+		return nil, st.errorf(line, "string too long")
+	}
+	return parts[0] + parts[1], nil
+}
+
+func (st *State) arith(op string, a, b Value, line int) (Value, error) {
+	x, ok := ToNumber(a)
+	if !ok {
+		return nil, st.errorf(line, "attempt to perform arithmetic on a %s value", TypeName(a))
+	}
+	y, ok := ToNumber(b)
+	if !ok {
+		return nil, st.errorf(line, "attempt to perform arithmetic on a %s value", TypeName(b))
+	}
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		return x / y, nil
+	case "%":
+		return x - math.Floor(x/y)*y, nil
+	case "^":
+		return math.Pow(x, y), nil
+	}
+	return nil, st.errorf(line, "unknown operator %s", op)
+}
+
+// evalCall evaluates a function or method call and returns all results
+func (st *State) evalCall(e expr, sc *scope) ([]Value, error) {
+	var fn Value
+	var args []Value
+	var line int
+
+	switch c := e.(type) {
+	case *callExpr:
+		var err error
+		if fn, err = st.eval(c.fn, sc); err != nil {
+			return nil, err
+		}
+		if args, err = st.evalMulti(c.args, sc); err != nil {
+			return nil, err
+		}
+		line = c.line
+	case *methodCallExpr:
+		obj, err := st.eval(c.obj, sc)
+		if err != nil {
+			return nil, err
+		}
+		if fn, err = st.index(obj, c.name, c.line); err != nil {
+			return nil, err
+		}
+		rest, err := st.evalMulti(c.args, sc)
+		if err != nil {
+			return nil, err
+		}
+		args = append([]Value{obj}, rest...)
+		line = c.line
+	}
+	return st.call(fn, args, line)
+}
+
+// call invokes a script or Go function
+func (st *State) call(fn Value, args []Value, line int) ([]Value, error) {
+	if err := st.step(); err != nil {
+		return nil, err
+	}
+	st.depth++
+	defer func() { st.depth-- }()
+	if st.depth > st.opts.MaxCallDepth {
+		return nil, st.fatalf(line, "stack overflow")
+	}
+
+	switch f := fn.(type) {
+	case *GoFunction:
+		rets, err := f.Fn(args)
+		switch e := err.(type) {
+		case nil:
+		case *Error:
+			return nil, e
+		case *raisedError:
+			return nil, &Error{Script: st.running, Line: line, Message: ToString(e.value), Value: e.value}
+		default:
+			return nil, st.errorf(line, "%s: %v", f.Name, err)
+		}
+		return rets, nil
+	case *Function:
+		sc := newScope(f.env)
+		sc.function = true
+		for i, name := range f.def.params {
+			sc.declare(name, valueAt(args, i))
+		}
+		if f.def.vararg && len(args) > len(f.def.params) {
+			sc.varargs = args[len(f.def.params):]
+		}
+		ctl, rets, err := st.execIn(f.def.body, sc)
+		if err != nil {
+			return nil, err
+		}
+		if ctl == ctlReturn {
+			return rets, nil
+		}
+		return nil, nil
+	}
+	return nil, st.errorf(line, "attempt to call a %s value", TypeName(fn))
+}
+
+// valueAt returns values[i], or nil past the end
+func valueAt(values []Value, i int) Value {
+	if i < len(values) {
+		return values[i]
+	}
+	return nil
+}
+
+// errorf creates a runtime error at a line of the running script
+func (st *State) errorf(line int, format string, args ...interface{}) error {
+	return &Error{Script: st.running, Line: line, Message: fmt.Sprintf(format, args...)}
+}
+
+// fatalf creates a runtime error that pcall can't catch
+func (st *State) fatalf(line int, format string, args ...interface{}) error {
+	return &Error{Script: st.running, Line: line, Message: fmt.Sprintf(format, args...), fatal: true}
+}
+
+// step counts an instruction against the budget of the current call
+func (st *State) step() error {
+	st.steps++
+	if st.steps > st.opts.MaxSteps {
+		return &Error{Script: st.running, Message: ErrBudgetExceeded.Error(), fatal: true, budget: true}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenKind identifies a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokNumber
+	tokString
+	tokKeyword
+	tokOp
+)
+
+// token is one lexical unit of a script
+type token struct {
+	kind tokenKind
+	text string  // Name, keyword, operator or decoded string
+	num  float64 // Value of number tokens
+	line int
+}
+
+// keywords are the reserved words of the language
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true,
+	"end": true, "false": true, "for": true, "function": true, "if": true,
+	"in": true, "local": true, "nil": true, "not": true, "or": true,
+	"repeat": true, "return": true, "then": true, "true": true, "until": true,
+	"while": true,
+}
+
+// operators lists the symbols longest first so "..." wins over ".." and "."
+var operators = []string{
+	"...", "..", "==", "~=", "<=", ">=",
+	"+", "-", "*", "/", "%", "^", "#", "<", ">", "=",
+	"(", ")", "{", "}", "[", "]", ";", ":", ",", ".",
+}
+
+// lexer splits a script into tokens
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	name string // Script name for error messages
+}
+
+// tokenize returns all tokens of the source, ending with tokEOF
+func tokenize(name, src string) ([]token, error) {
+	lx := &lexer{src: src, line: 1, name: name}
+	var tokens []token
+	for {
+		tok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokEOF {
+			return tokens, nil
+		}
+	}
+}
+
+// errorf reports a syntax error at the current line
+func (lx *lexer) errorf(format string, args ...interface{}) error {
+	return &Error{Script: lx.name, Line: lx.line, Message: fmt.Sprintf(format, args...)}
+}
+
+// next reads the next token, skipping whitespace and comments
+func (lx *lexer) next() (token, error) {
+	if err := lx.skipSpace(); err != nil {
+		return token{}, err
+	}
+	if lx.pos >= len(lx.src) {
+		return token{kind: tokEOF, line: lx.line}, nil
+	}
+
+	c := lx.src[lx.pos]
+	switch {
+	case isLetter(c):
+		start := lx.pos
+		for lx.pos < len(lx.src) && (isLetter(lx.src[lx.pos]) || isDigit(lx.src[lx.pos])) {
+			lx.pos++
+		}
+		word := lx.src[start:lx.pos]
+		if keywords[word] {
+			return token{kind: tokKeyword, text: word, line: lx.line}, nil
+		}
+		return token{kind: tokName, text: word, line: lx.line}, nil
+	case isDigit(c) || (c == '.' && lx.pos+1 < len(lx.src) && isDigit(lx.src[lx.pos+1])):
+		return lx.number()
+	case c == '"' || c == '\'':
+		return lx.quotedString(c)
+	case c == '[' && lx.longBracketLevel() >= 0:
+		line := lx.line
+		text, err := lx.longString()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokString, text: text, line: line}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(lx.src[lx.pos:], op) {
+			lx.pos += len(op)
+			return token{kind: tokOp, text: op, line: lx.line}, nil
+		}
+	}
+	return token{}, lx.errorf("unexpected symbol %q", c)
+}
+
+// skipSpace moves past whitespace and comments
+func (lx *lexer) skipSpace() error {
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == '\n':
+			lx.line++
+			lx.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			lx.pos++
+		case strings.HasPrefix(lx.src[lx.pos:], "--"):
+			lx.pos += 2
+			if lx.pos < len(lx.src) && lx.src[lx.pos] == '[' && lx.longBracketLevel() >= 0 {
+				if _, err := lx.longString(); err != nil {
+					return err
+				}
+				continue
+			}
+			for lx.pos < len(lx.src) && lx.src[lx.pos] != '\n' {
+				lx.pos++
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// number reads a decimal or hexadecimal number
+func (lx *lexer) number() (token, error) {
+	start := lx.pos
+	if strings.HasPrefix(lx.src[lx.pos:], "0x") || strings.HasPrefix(lx.src[lx.pos:], "0X") {
+		lx.pos += 2
+		for lx.pos < len(lx.src) && isHexDigit(lx.src[lx.pos]) {
+			lx.pos++
+		}
+		n, err := strconv.ParseUint(lx.src[start+2:lx.pos], 16, 64)
+		if err != nil {
+			return token{}, lx.errorf("malformed number near '%s'", lx.src[start:lx.pos])
+		}
+		return token{kind: tokNumber, num: float64(n), line: lx.line}, nil
+	}
+
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		if (c == '+' || c == '-') && (lx.src[lx.pos-1] == 'e' || lx.src[lx.pos-1] == 'E') {
+			lx.pos++
+			continue
+		}
+		if !isDigit(c) && c != '.' && c != 'e' && c != 'E' {
+			break
+		}
+		lx.pos++
+	}
+	n, err := strconv.ParseFloat(lx.src[start:lx.pos], 64)
+	if err != nil {
+		return token{}, lx.errorf("malformed number near '%s'", lx.src[start:lx.pos])
+	}
+	return token{kind: tokNumber, num: n, line: lx.line}, nil
+}
+
+// quotedString reads a string in single or double quotes, decoding escapes
+func (lx *lexer) quotedString(quote byte) (token, error) {
+	line := lx.line
+	lx.pos++ // Opening quote
+	var sb strings.Builder
+	for {
+		if lx.pos >= len(lx.src) || lx.src[lx.pos] == '\n' {
+			return token{}, lx.errorf("unfinished string")
+		}
+		c := lx.src[lx.pos]
+		if c == quote {
+			lx.pos++
+			return token{kind: tokString, text: sb.String(), line: line}, nil
+		}
+		if c != '\\' {
+			sb.WriteByte(c)
+			lx.pos++
+			continue
+		}
+
+		lx.pos++
+		if lx.pos >= len(lx.src) {
+			return token{}, lx.errorf("unfinished string")
+		}
+		esc := lx.src[lx.pos]
+		lx.pos++
+		switch esc {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'a':
+			sb.WriteByte('\a')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'v':
+			sb.WriteByte('\v')
+		case '\\', '"', '\'':
+			sb.WriteByte(esc)
+		case '\n':
+			sb.WriteByte('\n')
+			lx.line++
+		default:
+			if !isDigit(esc) {
+				return token{}, lx.errorf("invalid escape sequence '\\%c'", esc)
+			}
+			// Up to three decimal digits give a byte value
+			start := lx.pos - 1
+			for lx.pos < len(lx.src) && lx.pos-start < 3 && isDigit(lx.src[lx.pos]) {
+				lx.pos++
+			}
+			n, _ := strconv.Atoi(lx.src[start:lx.pos])
+			if n > 255 {
+				return token{}, lx.errorf("escape sequence too large")
+			}
+			sb.WriteByte(byte(n))
+		}
+	}
+}
+
+// longBracketLevel returns the number of '=' in a long bracket opening at
+// the current position, or -1 if there is none
+func (lx *lexer) longBracketLevel() int {
+	i := lx.pos + 1
+	for i < len(lx.src) && lx.src[i] == '=' {
+		i++
+	}
+	if i < len(lx.src) && lx.src[i] == '[' {
+		return i - lx.pos - 1
+	}
+	return -1
+}
+
+// longString reads a [[...]] or [==[...]==] string or comment body. A
+// newline right after the opening bracket is skipped.
+func (lx *lexer) longString() (string, error) {
+	level := lx.longBracketLevel()
+	lx.pos += level + 2
+	if strings.HasPrefix(lx.src[lx.pos:], "\r\n") {
+		lx.pos += 2
+		lx.line++
+	} else if lx.pos < len(lx.src) && lx.src[lx.pos] == '\n' {
+		lx.pos++
+		lx.line++
+	}
+
+	closing := "]" + strings.Repeat("=", level) + "]"
+	end := strings.Index(lx.src[lx.pos:], closing)
+	if end < 0 {
+		return "", lx.errorf("unfinished long string")
+	}
+	text := lx.src[lx.pos : lx.pos+end]
+	lx.line += strings.Count(text, "\n")
+	lx.pos += end + len(closing)
+	return text, nil
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
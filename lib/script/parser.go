@@ -0,0 +1,809 @@
+package script
+
+import "fmt"
+
+// Statements
+
+type stmt interface{}
+
+type block struct {
+	stmts []stmt
+}
+
+type localStmt struct {
+	names []string
+	exprs []expr
+}
+
+type localFuncStmt struct {
+	name string
+	fn   *funcExpr
+}
+
+type assignStmt struct {
+	targets []expr // nameExpr or indexExpr
+	exprs   []expr
+}
+
+type callStmt struct {
+	call expr // callExpr or methodCallExpr
+}
+
+type doStmt struct {
+	body *block
+}
+
+type whileStmt struct {
+	cond expr
+	body *block
+}
+
+type repeatStmt struct {
+	body *block
+	cond expr
+}
+
+type ifStmt struct {
+	conds     []expr
+	blocks    []*block
+	elseBlock *block
+}
+
+type numForStmt struct {
+	name               string
+	start, limit, step expr
+	body               *block
+	line               int
+}
+
+type genForStmt struct {
+	names []string
+	exprs []expr
+	body  *block
+	line  int
+}
+
+type returnStmt struct {
+	exprs []expr
+}
+
+type breakStmt struct{}
+
+// Expressions
+
+type expr interface{}
+
+type constExpr struct {
+	value Value // nil, bool, float64 or string
+}
+
+type varargExpr struct{}
+
+type funcExpr struct {
+	name   string // For error messages
+	params []string
+	vararg bool
+	body   *block
+}
+
+type tableItem struct {
+	key   expr // Nil for positional items
+	value expr
+}
+
+type tableExpr struct {
+	items []tableItem
+}
+
+type binExpr struct {
+	op   string
+	l, r expr
+	line int
+}
+
+type unExpr struct {
+	op   string
+	e    expr
+	line int
+}
+
+type nameExpr struct {
+	name string
+	line int
+}
+
+type indexExpr struct {
+	obj, key expr
+	line     int
+}
+
+type callExpr struct {
+	fn   expr
+	args []expr
+	line int
+}
+
+type methodCallExpr struct {
+	obj  expr
+	name string
+	args []expr
+	line int
+}
+
+type parenExpr struct {
+	e expr // Truncated to one value
+}
+
+// Binary operator priorities as (left, right); right-associative operators
+// bind tighter on the left
+var binaryPriority = map[string][2]int{
+	"or": {1, 1}, "and": {2, 2},
+	"<": {3, 3}, ">": {3, 3}, "<=": {3, 3}, ">=": {3, 3}, "~=": {3, 3}, "==": {3, 3},
+	"..": {5, 4},
+	"+":  {6, 6}, "-": {6, 6},
+	"*": {7, 7}, "/": {7, 7}, "%": {7, 7},
+	"^": {10, 9},
+}
+
+// unaryPriority binds tighter than everything but "^"
+const unaryPriority = 8
+
+// parser builds the syntax tree of a script from its tokens
+type parser struct {
+	tokens []token
+	pos    int
+	name   string
+}
+
+// parse compiles a script into a block
+func parse(name, src string) (*block, error) {
+	tokens, err := tokenize(name, src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, name: name}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "'<eof>' expected near %s", describe(tok))
+	}
+	return body, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// check reports whether the next token is the given keyword or operator
+func (p *parser) check(text string) bool {
+	tok := p.peek()
+	return (tok.kind == tokKeyword || tok.kind == tokOp) && tok.text == text
+}
+
+// accept consumes the given keyword or operator if it comes next
+func (p *parser) accept(text string) bool {
+	if p.check(text) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+// expect consumes the given keyword or operator or fails
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return p.errorf(tok, "'%s' expected near %s", text, describe(tok))
+	}
+	return nil
+}
+
+// expectName consumes a name or fails
+func (p *parser) expectName() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokName {
+		return "", p.errorf(tok, "<name> expected near %s", describe(tok))
+	}
+	p.advance()
+	return tok.text, nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return &Error{Script: p.name, Line: tok.line, Message: fmt.Sprintf(format, args...)}
+}
+
+// describe names a token for syntax errors
+func describe(tok token) string {
+	switch tok.kind {
+	case tokEOF:
+		return "'<eof>'"
+	case tokString:
+		return fmt.Sprintf("'%s'", tok.text)
+	case tokNumber:
+		return fmt.Sprintf("'%s'", formatNumber(tok.num))
+	default:
+		return fmt.Sprintf("'%s'", tok.text)
+	}
+}
+
+// blockEnds reports whether the next token closes a block
+func (p *parser) blockEnds() bool {
+	tok := p.peek()
+	if tok.kind == tokEOF {
+		return true
+	}
+	if tok.kind != tokKeyword {
+		return false
+	}
+	switch tok.text {
+	case "end", "else", "elseif", "until":
+		return true
+	}
+	return false
+}
+
+// block parses statements up to the end of a block. return and break must
+// be the last statement.
+func (p *parser) block() (*block, error) {
+	b := &block{}
+	for !p.blockEnds() {
+		if p.check("return") || p.check("break") {
+			s, err := p.lastStatement()
+			if err != nil {
+				return nil, err
+			}
+			b.stmts = append(b.stmts, s)
+			p.accept(";")
+			if !p.blockEnds() {
+				tok := p.peek()
+				return nil, p.errorf(tok, "'end' expected near %s", describe(tok))
+			}
+			break
+		}
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			b.stmts = append(b.stmts, s)
+		}
+	}
+	return b, nil
+}
+
+// lastStatement parses return or break
+func (p *parser) lastStatement() (stmt, error) {
+	if p.accept("break") {
+		return &breakStmt{}, nil
+	}
+	p.advance() // return
+	if p.blockEnds() || p.check(";") {
+		return &returnStmt{}, nil
+	}
+	exprs, err := p.exprList()
+	if err != nil {
+		return nil, err
+	}
+	return &returnStmt{exprs: exprs}, nil
+}
+
+// statement parses one statement; a lone ";" yields nil
+func (p *parser) statement() (stmt, error) {
+	tok := p.peek()
+	if tok.kind == tokOp && tok.text == ";" {
+		p.advance()
+		return nil, nil
+	}
+	if tok.kind == tokKeyword {
+		switch tok.text {
+		case "if":
+			return p.ifStatement()
+		case "while":
+			p.advance()
+			cond, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			body, err := p.doBlock()
+			if err != nil {
+				return nil, err
+			}
+			return &whileStmt{cond: cond, body: body}, nil
+		case "do":
+			body, err := p.doBlock()
+			if err != nil {
+				return nil, err
+			}
+			return &doStmt{body: body}, nil
+		case "for":
+			return p.forStatement()
+		case "repeat":
+			p.advance()
+			body, err := p.block()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("until"); err != nil {
+				return nil, err
+			}
+			cond, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return &repeatStmt{body: body, cond: cond}, nil
+		case "function":
+			return p.functionStatement()
+		case "local":
+			p.advance()
+			if p.accept("function") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				fn, err := p.funcBody(name)
+				if err != nil {
+					return nil, err
+				}
+				return &localFuncStmt{name: name, fn: fn}, nil
+			}
+			return p.localStatement()
+		}
+	}
+	return p.exprStatement()
+}
+
+// doBlock parses "do block end"
+func (p *parser) doBlock() (*block, error) {
+	if err := p.expect("do"); err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return body, p.expect("end")
+}
+
+// ifStatement parses if/elseif/else chains
+func (p *parser) ifStatement() (stmt, error) {
+	p.advance() // if
+	s := &ifStmt{}
+	for {
+		cond, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		s.conds = append(s.conds, cond)
+		s.blocks = append(s.blocks, body)
+		if !p.accept("elseif") {
+			break
+		}
+	}
+	if p.accept("else") {
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		s.elseBlock = body
+	}
+	return s, p.expect("end")
+}
+
+// forStatement parses numeric and generic for loops
+func (p *parser) forStatement() (stmt, error) {
+	line := p.advance().line // for
+	first, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept("=") {
+		s := &numForStmt{name: first, line: line}
+		if s.start, err = p.expr(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if s.limit, err = p.expr(); err != nil {
+			return nil, err
+		}
+		if p.accept(",") {
+			if s.step, err = p.expr(); err != nil {
+				return nil, err
+			}
+		}
+		if s.body, err = p.doBlock(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	s := &genForStmt{names: []string{first}, line: line}
+	for p.accept(",") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		s.names = append(s.names, name)
+	}
+	if err := p.expect("in"); err != nil {
+		return nil, err
+	}
+	if s.exprs, err = p.exprList(); err != nil {
+		return nil, err
+	}
+	if s.body, err = p.doBlock(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// functionStatement parses "function a.b.c:d() ... end" as an assignment
+func (p *parser) functionStatement() (stmt, error) {
+	line := p.advance().line // function
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	fullName := name
+	var target expr = &nameExpr{name: name, line: line}
+	method := false
+	for p.check(".") || p.check(":") {
+		method = p.advance().text == ":"
+		key, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		fullName += "." + key
+		target = &indexExpr{obj: target, key: &constExpr{value: key}, line: line}
+		if method {
+			break
+		}
+	}
+
+	fn, err := p.funcBody(fullName)
+	if err != nil {
+		return nil, err
+	}
+	if method {
+		fn.params = append([]string{"self"}, fn.params...)
+	}
+	return &assignStmt{targets: []expr{target}, exprs: []expr{fn}}, nil
+}
+
+// localStatement parses "local a, b = ..."
+func (p *parser) localStatement() (stmt, error) {
+	s := &localStmt{}
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		s.names = append(s.names, name)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.accept("=") {
+		exprs, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		s.exprs = exprs
+	}
+	return s, nil
+}
+
+// exprStatement parses a function call or an assignment
+func (p *parser) exprStatement() (stmt, error) {
+	tok := p.peek()
+	first, err := p.suffixedExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.check("=") || p.check(",") {
+		targets := []expr{first}
+		for p.accept(",") {
+			target, err := p.suffixedExpr()
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+		}
+		for _, target := range targets {
+			switch target.(type) {
+			case *nameExpr, *indexExpr:
+			default:
+				return nil, p.errorf(tok, "syntax error near %s", describe(p.peek()))
+			}
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		exprs, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		return &assignStmt{targets: targets, exprs: exprs}, nil
+	}
+
+	switch first.(type) {
+	case *callExpr, *methodCallExpr:
+		return &callStmt{call: first}, nil
+	}
+	return nil, p.errorf(tok, "syntax error near %s", describe(p.peek()))
+}
+
+// exprList parses comma-separated expressions
+func (p *parser) exprList() ([]expr, error) {
+	var exprs []expr
+	for {
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if !p.accept(",") {
+			return exprs, nil
+		}
+	}
+}
+
+// expr parses a full expression
+func (p *parser) expr() (expr, error) {
+	return p.subExpr(0)
+}
+
+// subExpr parses binary expressions whose operators bind tighter than limit
+func (p *parser) subExpr(limit int) (expr, error) {
+	var left expr
+	tok := p.peek()
+	if (tok.kind == tokKeyword && tok.text == "not") || (tok.kind == tokOp && (tok.text == "-" || tok.text == "#")) {
+		p.advance()
+		operand, err := p.subExpr(unaryPriority)
+		if err != nil {
+			return nil, err
+		}
+		left = &unExpr{op: tok.text, e: operand, line: tok.line}
+	} else {
+		var err error
+		if left, err = p.simpleExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp && tok.kind != tokKeyword {
+			return left, nil
+		}
+		priority, ok := binaryPriority[tok.text]
+		if !ok || priority[0] <= limit {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.subExpr(priority[1])
+		if err != nil {
+			return nil, err
+		}
+		left = &binExpr{op: tok.text, l: left, r: right, line: tok.line}
+	}
+}
+
+// simpleExpr parses literals, functions, tables and suffixed expressions
+func (p *parser) simpleExpr() (expr, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNumber:
+		p.advance()
+		return &constExpr{value: tok.num}, nil
+	case tokString:
+		p.advance()
+		return &constExpr{value: tok.text}, nil
+	case tokKeyword:
+		switch tok.text {
+		case "nil":
+			p.advance()
+			return &constExpr{}, nil
+		case "true":
+			p.advance()
+			return &constExpr{value: true}, nil
+		case "false":
+			p.advance()
+			return &constExpr{value: false}, nil
+		case "function":
+			p.advance()
+			return p.funcBody("anonymous")
+		}
+	case tokOp:
+		switch tok.text {
+		case "...":
+			p.advance()
+			return &varargExpr{}, nil
+		case "{":
+			return p.tableConstructor()
+		}
+	}
+	return p.suffixedExpr()
+}
+
+// primaryExpr parses a name or a parenthesized expression
+func (p *parser) primaryExpr() (expr, error) {
+	tok := p.peek()
+	if tok.kind == tokName {
+		p.advance()
+		return &nameExpr{name: tok.text, line: tok.line}, nil
+	}
+	if p.accept("(") {
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &parenExpr{e: e}, nil
+	}
+	return nil, p.errorf(tok, "unexpected symbol near %s", describe(tok))
+}
+
+// suffixedExpr parses field access, indexing and calls after a primary
+func (p *parser) suffixedExpr() (expr, error) {
+	e, err := p.primaryExpr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case p.accept("."):
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			e = &indexExpr{obj: e, key: &constExpr{value: name}, line: tok.line}
+		case p.accept("["):
+			key, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			e = &indexExpr{obj: e, key: key, line: tok.line}
+		case p.accept(":"):
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			args, err := p.callArgs()
+			if err != nil {
+				return nil, err
+			}
+			e = &methodCallExpr{obj: e, name: name, args: args, line: tok.line}
+		case p.check("(") || p.check("{") || tok.kind == tokString:
+			args, err := p.callArgs()
+			if err != nil {
+				return nil, err
+			}
+			e = &callExpr{fn: e, args: args, line: tok.line}
+		default:
+			return e, nil
+		}
+	}
+}
+
+// callArgs parses "(a, b)", a table constructor or a string literal
+func (p *parser) callArgs() ([]expr, error) {
+	tok := p.peek()
+	if tok.kind == tokString {
+		p.advance()
+		return []expr{&constExpr{value: tok.text}}, nil
+	}
+	if p.check("{") {
+		table, err := p.tableConstructor()
+		if err != nil {
+			return nil, err
+		}
+		return []expr{table}, nil
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if p.accept(")") {
+		return nil, nil
+	}
+	args, err := p.exprList()
+	if err != nil {
+		return nil, err
+	}
+	return args, p.expect(")")
+}
+
+// tableConstructor parses "{ a, b = c, [d] = e }"
+func (p *parser) tableConstructor() (expr, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	t := &tableExpr{}
+	for !p.check("}") {
+		var item tableItem
+		switch {
+		case p.accept("["):
+			key, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			item.key = key
+		case p.peek().kind == tokName && p.tokens[p.pos+1].kind == tokOp && p.tokens[p.pos+1].text == "=":
+			item.key = &constExpr{value: p.advance().text}
+			p.advance() // =
+		}
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		item.value = value
+		t.items = append(t.items, item)
+		if !p.accept(",") && !p.accept(";") {
+			break
+		}
+	}
+	return t, p.expect("}")
+}
+
+// funcBody parses "(params) block end"
+func (p *parser) funcBody(name string) (*funcExpr, error) {
+	fn := &funcExpr{name: name}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	for !p.check(")") {
+		if p.accept("...") {
+			fn.vararg = true
+			break
+		}
+		param, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		fn.params = append(fn.params, param)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	fn.body = body
+	return fn, p.expect("end")
+}
@@ -0,0 +1,220 @@
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// evalResult runs a script and returns its global "result"
+func evalResult(t *testing.T, src string) Value {
+	t.Helper()
+	st := New(Options{})
+	if err := st.DoString("test", src); err != nil {
+		t.Fatalf("DoString failed: %v", err)
+	}
+	return st.GetGlobal("result")
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Value
+	}{
+		{"arithmetic precedence", "result = 1 + 2 * 3 ^ 2 / 2 - -1", 11.0},
+		{"power is right associative", "result = 2 ^ 3 ^ 2", 512.0},
+		{"unary minus below power", "result = -2 ^ 2", -4.0},
+		{"modulo floors", "result = -5 % 3", 1.0},
+		{"concat numbers", `result = "n=" .. 1 .. "," .. 2.5`, "n=1,2.5"},
+		{"string coercion", `result = "10" + 5`, 15.0},
+		{"comparison chain", "result = 1 < 2 and 2 <= 2 and 3 > 2 and not (1 == 2)", true},
+		{"and or idiom", "local x = nil; result = x and 1 or 2", 2.0},
+		{"length", `result = #"hello" + #{1, 2, 3}`, 8.0},
+		{"if elseif else", "local x = 5; if x > 10 then result = 'big' elseif x > 3 then result = 'mid' else result = 'small' end", "mid"},
+		{"while loop", "local i, sum = 0, 0; while i < 10 do i = i + 1; sum = sum + i end; result = sum", 55.0},
+		{"repeat sees body locals", "local n = 0; repeat local done = n >= 3; n = n + 1 until done; result = n", 4.0},
+		{"numeric for with step", "result = 0; for i = 10, 1, -3 do result = result + i end", 22.0},
+		{"break", "result = 0; for i = 1, 100 do if i > 5 then break end; result = i end", 5.0},
+		{"ipairs", "result = ''; for i, v in ipairs({'a', 'b', 'c'}) do result = result .. i .. v end", "1a2b3c"},
+		{"pairs sorted keys", "local t = {b = 2, a = 1, c = 3}; result = ''; for k, v in pairs(t) do result = result .. k .. v end", "a1b2c3"},
+		{"closures share state", "local function counter() local n = 0; return function() n = n + 1; return n end end; local c = counter(); c(); c(); result = c()", 3.0},
+		{"closures capture loop variables", "local fs = {}; for i = 1, 3 do fs[i] = function() return i end end; result = fs[1]() + fs[3]()", 4.0},
+		{"recursion", "local function fib(n) if n < 2 then return n end; return fib(n - 1) + fib(n - 2) end; result = fib(15)", 610.0},
+		{"multiple returns", "local function two() return 1, 2 end; local a, b = two(); result = a + b", 3.0},
+		{"varargs", "local function sum(...) local s = 0; for _, v in ipairs({...}) do s = s + v end; return s end; result = sum(1, 2, 3, 4)", 10.0},
+		{"select count", "result = select('#', 1, nil, 3)", 3.0},
+		{"swap", "local a, b = 1, 2; a, b = b, a; result = a * 10 + b", 21.0},
+		{"nested tables", "local t = {inner = {values = {10, 20}}}; t.inner.values[3] = 30; result = t.inner.values[3] + #t.inner.values", 33.0},
+		{"table constructor forms", "local t = {1, 2; x = 'y', ['z'] = 3, 4}; result = #t .. t.x .. t.z", "3y3"},
+		{"methods", "local obj = {n = 2}; function obj:double() return self.n * 2 end; result = obj:double()", 4.0},
+		{"dotted function names", "local m = {}; function m.add(a, b) return a + b end; result = m.add(2, 3)", 5.0},
+		{"string methods", `result = ("Hello"):upper() .. ("ABC"):lower():len()`, "HELLO3"},
+		{"string library", `result = string.sub("companion", 1, 4) .. string.sub("companion", -3) .. string.rep("ab", 2, "-")`, "compion" + "ab-ab"},
+		{"string format", `result = string.format("%s has %d%% (%.1f) %5s|%-3d|", "Pet", 80, 2.25, "x", 7)`, "Pet has 80% (2.2)     x|7  |"},
+		{"string find plain", `result = string.find("hello world", "world")`, 7.0},
+		{"table insert remove", "local t = {1, 2, 3}; table.insert(t, 1, 0); table.insert(t, 4); local r = table.remove(t, 2); result = table.concat(t, ',') .. ':' .. r", "0,2,3,4:1"},
+		{"table sort", "local t = {3, 1, 2}; table.sort(t); local d = {3, 1, 2}; table.sort(d, function(a, b) return a > b end); result = table.concat(t) .. table.concat(d)", "123321"},
+		{"math", "result = math.max(1, 5, 3) + math.min(4, 2) + math.floor(2.7) + math.abs(-1)", 10.0},
+		{"random in range", "local ok = true; for i = 1, 50 do local r = math.random(3, 5); ok = ok and r >= 3 and r <= 5 and r == math.floor(r) end; result = ok", true},
+		{"tostring tonumber", `result = tostring(12) .. tostring(nil) .. tonumber("0x10") .. tonumber("ff", 16)`, "12nil16255"},
+		{"type", "result = type(1) .. type('') .. type({}) .. type(print) .. type(nil)", "numberstringtablefunctionnil"},
+		{"pcall catches errors", "local ok, err = pcall(function() error('boom') end); result = tostring(ok) .. ' ' .. err", "false test:1: boom"},
+		{"pcall passes table errors", "local ok, err = pcall(error, {code = 7}); result = err.code", 7.0},
+		{"long strings and comments", "--[[ a\nlong comment ]] result = [[\nline]] -- trailing", "line"},
+		{"escapes", `result = "a\tb\65\\"`, "a\tbA\\"},
+		{"integers print without fraction", "result = tostring(3.0) .. ' ' .. tostring(0.5)", "3 0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evalResult(t, tt.src); got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"x = ", "test:1: unexpected symbol near '<eof>'"},
+		{"if x then\n  y = 1\n", "test:3: 'end' expected near '<eof>'"},
+		{"x = 'unfinished", "test:1: unfinished string"},
+		{"return 1\nx = 2", "test:2: 'end' expected near 'x'"},
+		{"1 + 2", "test:1: unexpected symbol near '1'"},
+		{"f() = 1", "test:1: syntax error near '='"},
+	}
+	for _, tt := range tests {
+		err := Compile("test", tt.src)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Compile(%q) = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"local t = nil\nx = t.field", "test:2: attempt to index a nil value"},
+		{"x = {} + 1", "test:1: attempt to perform arithmetic on a table value"},
+		{"x = 1 < 'a'", "test:1: attempt to compare number with string"},
+		{"undefined()", "test:1: attempt to call a nil value"},
+		{"x = 'a' .. {}", "test:1: attempt to concatenate a table value"},
+		{"table.insert(nil, 1)", "test:1: table.insert: bad argument #1 (table expected, got nil)"},
+		{"error('custom')", "test:1: custom"},
+		{"local t = {}\nt[nil] = 1", "test:2: table index is nil"},
+	}
+	for _, tt := range tests {
+		err := New(Options{}).DoString("test", tt.src)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("DoString(%q) = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestSandboxLimits(t *testing.T) {
+	st := New(Options{MaxSteps: 1000})
+	err := st.DoString("test", "while true do end")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected an endless loop to exhaust the budget, got %v", err)
+	}
+
+	// pcall must not let a script dodge the budget
+	err = st.DoString("test", "pcall(function() while true do end end) result = 'escaped'")
+	if !errors.Is(err, ErrBudgetExceeded) || st.GetGlobal("result") != nil {
+		t.Errorf("pcall should not catch the budget running out, got %v", err)
+	}
+
+	err = New(Options{}).DoString("test", "local function f() return f() + 1 end f()")
+	if err == nil || !strings.Contains(err.Error(), "stack overflow") {
+		t.Errorf("Expected a stack overflow, got %v", err)
+	}
+
+	err = New(Options{MaxStringLength: 100}).DoString("test", "local s = 'x' for i = 1, 10 do s = s .. s end")
+	if err == nil || !strings.Contains(err.Error(), "string too long") {
+		t.Errorf("Expected the string limit to stop doubling, got %v", err)
+	}
+
+	err = New(Options{MaxStringLength: 100}).DoString("test", "x = string.rep('x', 1000)")
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Expected string.rep to respect the limit, got %v", err)
+	}
+
+	for _, name := range []string{"io", "os", "require", "load", "loadstring", "dofile", "debug", "setmetatable"} {
+		if v := st.GetGlobal(name); v != nil {
+			t.Errorf("Sandbox should not expose %s", name)
+		}
+	}
+}
+
+func TestCallResetsBudget(t *testing.T) {
+	st := New(Options{MaxSteps: 500})
+	if err := st.DoString("test", "function work() local n = 0 for i = 1, 100 do n = n + i end return n end"); err != nil {
+		t.Fatalf("DoString failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		rets, err := st.Call("work")
+		if err != nil {
+			t.Fatalf("Call %d failed: %v", i, err)
+		}
+		if rets[0] != 5050.0 {
+			t.Errorf("Expected 5050, got %v", rets[0])
+		}
+	}
+	if _, err := st.Call("missing"); err == nil {
+		t.Error("Calling an undefined function should fail")
+	}
+	if st.HasFunction("missing") || !st.HasFunction("work") {
+		t.Error("HasFunction should only report defined functions")
+	}
+}
+
+func TestGoFunctions(t *testing.T) {
+	var printed []string
+	st := New(Options{Print: func(text string) { printed = append(printed, text) }})
+	st.SetGlobal("double", NewGoFunction("double", func(args []Value) ([]Value, error) {
+		n, _ := ToNumber(valueAt(args, 0))
+		return []Value{n * 2}, nil
+	}))
+	st.SetGlobal("fail", NewGoFunction("fail", func([]Value) ([]Value, error) {
+		return nil, errors.New("host refused")
+	}))
+
+	if err := st.DoString("test", "print('twice', double(21)) ok, err = pcall(fail)"); err != nil {
+		t.Fatalf("DoString failed: %v", err)
+	}
+	if len(printed) != 1 || printed[0] != "twice\t42" {
+		t.Errorf("Unexpected print output %q", printed)
+	}
+	if st.GetGlobal("ok") != false || st.GetGlobal("err") != "test: fail: host refused" {
+		t.Errorf("Expected the Go error in pcall, got %v", st.GetGlobal("err"))
+	}
+}
+
+func TestTable(t *testing.T) {
+	tbl := NewTable()
+	_ = tbl.Set(float64(2), "b")
+	_ = tbl.Set("k", "v")
+	if tbl.Len() != 0 {
+		t.Errorf("A table without index 1 has length 0, got %d", tbl.Len())
+	}
+	_ = tbl.Set(float64(1), "a")
+	if tbl.Len() != 2 {
+		t.Errorf("Setting index 1 should pull index 2 into the array, got %d", tbl.Len())
+	}
+	_ = tbl.Set(float64(2), nil)
+	if tbl.Len() != 1 || tbl.Get(float64(2)) != nil {
+		t.Errorf("Removing the last item should shrink the array, got %d", tbl.Len())
+	}
+	if keys := tbl.Keys(); len(keys) != 2 || keys[0] != float64(1) || keys[1] != "k" {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+	if err := tbl.Set(nil, 1); err == nil {
+		t.Error("nil keys should be refused")
+	}
+}
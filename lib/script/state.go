@@ -0,0 +1,129 @@
+// Package script runs character behavior scripts written in a small subset
+// of Lua 5.1: locals, tables, functions and closures, if/while/repeat/for,
+// and a trimmed standard library (math, string, table, pairs, pcall and
+// friends). There is no io, os, require or load, so a script can only reach
+// what the host hands it. Every call runs on a step budget, so a runaway
+// loop fails with ErrBudgetExceeded instead of freezing the character.
+package script
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sandbox defaults used when Options leaves a limit at zero
+const (
+	DefaultMaxSteps        = 100000
+	DefaultMaxCallDepth    = 100
+	DefaultMaxStringLength = 64 * 1024
+)
+
+// ErrBudgetExceeded is reported when a script runs longer than MaxSteps
+var ErrBudgetExceeded = errors.New("script ran too long")
+
+// Options limits what a script may use
+type Options struct {
+	MaxSteps        int               // Statements and calls per DoString or Call (default: 100000)
+	MaxCallDepth    int               // Nested calls before a stack overflow (default: 100)
+	MaxStringLength int               // Longest string concatenation or string.rep may build (default: 64KB)
+	Print           func(text string) // Receives print() output; dropped when nil
+}
+
+// Error is a syntax or runtime error in a script
+type Error struct {
+	Script  string // Script name
+	Line    int    // 0 when not tied to a line
+	Message string
+	Value   Value // Argument to error(), when a script raised it
+
+	fatal  bool // pcall can't catch it
+	budget bool // The step budget ran out
+}
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Script, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Script, e.Message)
+}
+
+// Unwrap lets errors.Is find ErrBudgetExceeded
+func (e *Error) Unwrap() error {
+	if e.budget {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// State is one script environment with its own globals. It is not safe for
+// concurrent use.
+type State struct {
+	opts    Options
+	globals *Table
+	running string // Name of the script being run, for errors
+	steps   int
+	depth   int
+}
+
+// New creates an environment with the sandboxed standard library
+func New(opts Options) *State {
+	if opts.MaxSteps <= 0 {
+		opts.MaxSteps = DefaultMaxSteps
+	}
+	if opts.MaxCallDepth <= 0 {
+		opts.MaxCallDepth = DefaultMaxCallDepth
+	}
+	if opts.MaxStringLength <= 0 {
+		opts.MaxStringLength = DefaultMaxStringLength
+	}
+	st := &State{opts: opts, globals: NewTable(), running: "script"}
+	st.openLibs()
+	return st
+}
+
+// Compile checks a script's syntax without running it
+func Compile(name, src string) error {
+	_, err := parse(name, src)
+	return err
+}
+
+// DoString runs a script's top level, which usually defines functions
+func (st *State) DoString(name, src string) error {
+	body, err := parse(name, src)
+	if err != nil {
+		return err
+	}
+	st.running = name
+	st.steps = 0
+	sc := newScope(nil)
+	sc.function = true
+	_, _, err = st.execIn(body, sc)
+	return err
+}
+
+// SetGlobal sets a global variable
+func (st *State) SetGlobal(name string, value Value) {
+	_ = st.globals.Set(name, value)
+}
+
+// GetGlobal returns a global variable, nil if unset
+func (st *State) GetGlobal(name string) Value {
+	return st.globals.Get(name)
+}
+
+// HasFunction reports whether a global function with the name is defined
+func (st *State) HasFunction(name string) bool {
+	return TypeName(st.globals.Get(name)) == "function"
+}
+
+// Call calls a global function with a fresh step budget and returns its
+// results
+func (st *State) Call(name string, args ...Value) ([]Value, error) {
+	fn := st.globals.Get(name)
+	if TypeName(fn) != "function" {
+		return nil, &Error{Script: st.running, Message: fmt.Sprintf("function '%s' is not defined", name)}
+	}
+	st.steps = 0
+	st.depth = 0
+	return st.call(fn, args, 0)
+}
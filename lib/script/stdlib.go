@@ -0,0 +1,715 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// raisedError carries the value passed to error() until the call site adds
+// its line
+type raisedError struct {
+	value Value
+}
+
+func (e *raisedError) Error() string {
+	return ToString(e.value)
+}
+
+// openLibs installs the sandboxed standard library
+func (st *State) openLibs() {
+	globals := map[string]func(args []Value) ([]Value, error){
+		"assert":   st.builtinAssert,
+		"error":    builtinError,
+		"ipairs":   builtinIpairs,
+		"pairs":    builtinPairs,
+		"pcall":    st.builtinPcall,
+		"print":    st.builtinPrint,
+		"select":   builtinSelect,
+		"tonumber": builtinTonumber,
+		"tostring": func(args []Value) ([]Value, error) { return []Value{ToString(valueAt(args, 0))}, nil },
+		"type":     builtinType,
+		"unpack":   builtinUnpack,
+	}
+	for name, fn := range globals {
+		st.SetGlobal(name, NewGoFunction(name, fn))
+	}
+
+	st.SetGlobal("math", library("math", map[string]func(args []Value) ([]Value, error){
+		"abs":    mathFunc(math.Abs),
+		"ceil":   mathFunc(math.Ceil),
+		"floor":  mathFunc(math.Floor),
+		"sqrt":   mathFunc(math.Sqrt),
+		"max":    mathMax,
+		"min":    mathMin,
+		"random": mathRandom,
+	}, map[string]Value{"huge": math.Inf(1), "pi": math.Pi}))
+
+	st.SetGlobal("string", library("string", map[string]func(args []Value) ([]Value, error){
+		"byte":    stringByte,
+		"char":    stringChar,
+		"find":    stringFind,
+		"format":  stringFormat,
+		"len":     stringLen,
+		"lower":   stringMap(strings.ToLower),
+		"upper":   stringMap(strings.ToUpper),
+		"rep":     st.stringRep,
+		"reverse": stringReverse,
+		"sub":     stringSub,
+	}, nil))
+
+	st.SetGlobal("table", library("table", map[string]func(args []Value) ([]Value, error){
+		"concat": tableConcat,
+		"insert": tableInsert,
+		"remove": tableRemove,
+		"sort":   st.tableSort,
+		"unpack": builtinUnpack,
+	}, nil))
+}
+
+// library builds a table of Go functions and constants
+func library(name string, fns map[string]func(args []Value) ([]Value, error), constants map[string]Value) *Table {
+	t := NewTable()
+	for fnName, fn := range fns {
+		_ = t.Set(fnName, NewGoFunction(name+"."+fnName, fn))
+	}
+	for key, value := range constants {
+		_ = t.Set(key, value)
+	}
+	return t
+}
+
+// Argument helpers
+
+func badArgument(i int, want string, got Value) error {
+	return fmt.Errorf("bad argument #%d (%s expected, got %s)", i+1, want, TypeName(got))
+}
+
+func argTable(args []Value, i int) (*Table, error) {
+	t, ok := valueAt(args, i).(*Table)
+	if !ok {
+		return nil, badArgument(i, "table", valueAt(args, i))
+	}
+	return t, nil
+}
+
+func argNumber(args []Value, i int) (float64, error) {
+	n, ok := ToNumber(valueAt(args, i))
+	if !ok {
+		return 0, badArgument(i, "number", valueAt(args, i))
+	}
+	return n, nil
+}
+
+func optNumber(args []Value, i int, def float64) (float64, error) {
+	if valueAt(args, i) == nil {
+		return def, nil
+	}
+	return argNumber(args, i)
+}
+
+func argString(args []Value, i int) (string, error) {
+	switch v := valueAt(args, i).(type) {
+	case string:
+		return v, nil
+	case float64:
+		return formatNumber(v), nil
+	}
+	return "", badArgument(i, "string", valueAt(args, i))
+}
+
+// Globals
+
+func (st *State) builtinAssert(args []Value) ([]Value, error) {
+	if Truthy(valueAt(args, 0)) {
+		return args, nil
+	}
+	if msg := valueAt(args, 1); msg != nil {
+		return nil, &raisedError{value: msg}
+	}
+	return nil, &raisedError{value: "assertion failed!"}
+}
+
+func builtinError(args []Value) ([]Value, error) {
+	return nil, &raisedError{value: valueAt(args, 0)}
+}
+
+func builtinIpairs(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	next := NewGoFunction("ipairs_iterator", func(args []Value) ([]Value, error) {
+		i, _ := ToNumber(valueAt(args, 1))
+		value := t.Get(i + 1)
+		if value == nil {
+			return nil, nil
+		}
+		return []Value{i + 1, value}, nil
+	})
+	return []Value{next, t, float64(0)}, nil
+}
+
+// builtinPairs iterates over the keys the table had when the loop began,
+// skipping any removed since
+func builtinPairs(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	keys := t.Keys()
+	i := 0
+	next := NewGoFunction("pairs_iterator", func([]Value) ([]Value, error) {
+		for i < len(keys) {
+			key := keys[i]
+			i++
+			if value := t.Get(key); value != nil {
+				return []Value{key, value}, nil
+			}
+		}
+		return nil, nil
+	})
+	return []Value{next, t, nil}, nil
+}
+
+// builtinPcall runs a function and returns false and the error instead of
+// failing. Running out of budget can't be caught.
+func (st *State) builtinPcall(args []Value) ([]Value, error) {
+	if len(args) == 0 {
+		return nil, badArgument(0, "value", nil)
+	}
+	rets, err := st.call(args[0], args[1:], 0)
+	if err == nil {
+		return append([]Value{true}, rets...), nil
+	}
+	scriptErr, ok := err.(*Error)
+	if !ok || scriptErr.fatal {
+		return nil, err
+	}
+	if scriptErr.Value != nil {
+		if _, isString := scriptErr.Value.(string); !isString {
+			return []Value{false, scriptErr.Value}, nil
+		}
+	}
+	return []Value{false, scriptErr.Error()}, nil
+}
+
+func (st *State) builtinPrint(args []Value) ([]Value, error) {
+	if st.opts.Print == nil {
+		return nil, nil
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = ToString(arg)
+	}
+	st.opts.Print(strings.Join(parts, "\t"))
+	return nil, nil
+}
+
+func builtinSelect(args []Value) ([]Value, error) {
+	if valueAt(args, 0) == "#" {
+		return []Value{float64(len(args) - 1)}, nil
+	}
+	n, err := argNumber(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	i := int(n)
+	if i < 0 {
+		i = len(args) + i
+	}
+	if i < 1 {
+		return nil, fmt.Errorf("bad argument #1 (index out of range)")
+	}
+	if i >= len(args) {
+		return nil, nil
+	}
+	return args[i:], nil
+}
+
+func builtinTonumber(args []Value) ([]Value, error) {
+	if valueAt(args, 1) == nil {
+		if n, ok := ToNumber(valueAt(args, 0)); ok {
+			return []Value{n}, nil
+		}
+		return []Value{nil}, nil
+	}
+	base, err := argNumber(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("bad argument #2 (base out of range)")
+	}
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(s), int(base), 64)
+	if err != nil {
+		return []Value{nil}, nil
+	}
+	return []Value{float64(n)}, nil
+}
+
+func builtinType(args []Value) ([]Value, error) {
+	if len(args) == 0 {
+		return nil, badArgument(0, "value", nil)
+	}
+	return []Value{TypeName(args[0])}, nil
+}
+
+func builtinUnpack(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	from, err := optNumber(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	to, err := optNumber(args, 2, float64(t.Len()))
+	if err != nil {
+		return nil, err
+	}
+	if to-from >= DefaultMaxSteps {
+		return nil, fmt.Errorf("too many results to unpack")
+	}
+	var values []Value
+	for i := from; i <= to; i++ {
+		values = append(values, t.Get(i))
+	}
+	return values, nil
+}
+
+// math
+
+func mathFunc(fn func(float64) float64) func(args []Value) ([]Value, error) {
+	return func(args []Value) ([]Value, error) {
+		n, err := argNumber(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []Value{fn(n)}, nil
+	}
+}
+
+func mathMax(args []Value) ([]Value, error) {
+	best, err := argNumber(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(args); i++ {
+		n, err := argNumber(args, i)
+		if err != nil {
+			return nil, err
+		}
+		best = math.Max(best, n)
+	}
+	return []Value{best}, nil
+}
+
+func mathMin(args []Value) ([]Value, error) {
+	best, err := argNumber(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(args); i++ {
+		n, err := argNumber(args, i)
+		if err != nil {
+			return nil, err
+		}
+		best = math.Min(best, n)
+	}
+	return []Value{best}, nil
+}
+
+// mathRandom returns a float in [0,1), an integer in [1,m] or in [m,n]
+func mathRandom(args []Value) ([]Value, error) {
+	if len(args) == 0 {
+		return []Value{rand.Float64()}, nil
+	}
+	low, high := 1.0, 0.0
+	var err error
+	if len(args) == 1 {
+		if high, err = argNumber(args, 0); err != nil {
+			return nil, err
+		}
+	} else {
+		if low, err = argNumber(args, 0); err != nil {
+			return nil, err
+		}
+		if high, err = argNumber(args, 1); err != nil {
+			return nil, err
+		}
+	}
+	low, high = math.Floor(low), math.Floor(high)
+	if low > high {
+		return nil, fmt.Errorf("bad argument (interval is empty)")
+	}
+	return []Value{low + float64(rand.Int63n(int64(high-low)+1))}, nil
+}
+
+// string
+
+// stringRange converts Lua's 1-based, possibly negative i and j to a byte
+// range of s
+func stringRange(length int, i, j float64) (int, int) {
+	if i < 0 {
+		i = math.Max(float64(length)+i+1, 1)
+	} else if i == 0 {
+		i = 1
+	}
+	if j < 0 {
+		j = float64(length) + j + 1
+	} else if j > float64(length) {
+		j = float64(length)
+	}
+	if i > j {
+		return 0, 0
+	}
+	return int(i) - 1, int(j)
+}
+
+func stringByte(args []Value) ([]Value, error) {
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	i, err := optNumber(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	j, err := optNumber(args, 2, i)
+	if err != nil {
+		return nil, err
+	}
+	from, to := stringRange(len(s), i, j)
+	var values []Value
+	for k := from; k < to; k++ {
+		values = append(values, float64(s[k]))
+	}
+	return values, nil
+}
+
+func stringChar(args []Value) ([]Value, error) {
+	var sb strings.Builder
+	for i := range args {
+		n, err := argNumber(args, i)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || n > 255 {
+			return nil, fmt.Errorf("bad argument #%d (value out of range)", i+1)
+		}
+		sb.WriteByte(byte(n))
+	}
+	return []Value{sb.String()}, nil
+}
+
+// stringFind searches for plain text. Lua patterns aren't supported, so a
+// pattern with special characters is refused rather than matched literally.
+func stringFind(args []Value) ([]Value, error) {
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := argString(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	init, err := optNumber(args, 2, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !Truthy(valueAt(args, 3)) && strings.ContainsAny(pattern, "^$*+?.([%-") {
+		return nil, fmt.Errorf("patterns are not supported, pass true as the fourth argument for a plain search")
+	}
+
+	from, _ := stringRange(len(s), init, float64(len(s)))
+	if init > float64(len(s)+1) {
+		return []Value{nil}, nil
+	}
+	if init > float64(len(s)) {
+		from = len(s)
+	}
+	idx := strings.Index(s[from:], pattern)
+	if idx < 0 {
+		return []Value{nil}, nil
+	}
+	start := from + idx + 1
+	return []Value{float64(start), float64(start + len(pattern) - 1)}, nil
+}
+
+// stringFormat supports the %d, %i, %f, %g, %e, %x, %X, %s, %q, %c and %%
+// directives with flags, width and precision
+func stringFormat(args []Value) ([]Value, error) {
+	format, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	arg := 1
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			sb.WriteByte(c)
+			continue
+		}
+		j := i + 1
+		for j < len(format) && strings.IndexByte("-+ #0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j >= len(format) {
+			return nil, fmt.Errorf("invalid conversion '%s' to 'format'", format[i:])
+		}
+		spec, verb := format[i:j], format[j]
+		i = j
+		if verb == '%' {
+			sb.WriteByte('%')
+			continue
+		}
+
+		switch verb {
+		case 'd', 'i':
+			n, err := argNumber(args, arg)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&sb, spec+"d", int64(n))
+		case 'x', 'X':
+			n, err := argNumber(args, arg)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&sb, spec+string(verb), int64(n))
+		case 'c':
+			n, err := argNumber(args, arg)
+			if err != nil {
+				return nil, err
+			}
+			sb.WriteByte(byte(n))
+		case 'f', 'g', 'e', 'G', 'E':
+			n, err := argNumber(args, arg)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&sb, spec+string(verb), n)
+		case 's':
+			fmt.Fprintf(&sb, spec+"s", ToString(valueAt(args, arg)))
+		case 'q':
+			s, err := argString(args, arg)
+			if err != nil {
+				return nil, err
+			}
+			sb.WriteString(strconv.Quote(s))
+		default:
+			return nil, fmt.Errorf("invalid option '%%%c' to 'format'", verb)
+		}
+		arg++
+	}
+	return []Value{sb.String()}, nil
+}
+
+func stringLen(args []Value) ([]Value, error) {
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return []Value{float64(len(s))}, nil
+}
+
+func stringMap(fn func(string) string) func(args []Value) ([]Value, error) {
+	return func(args []Value) ([]Value, error) {
+		s, err := argString(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []Value{fn(s)}, nil
+	}
+}
+
+func (st *State) stringRep(args []Value) ([]Value, error) {
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	n, err := argNumber(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	sep := ""
+	if valueAt(args, 2) != nil {
+		if sep, err = argString(args, 2); err != nil {
+			return nil, err
+		}
+	}
+	if n <= 0 {
+		return []Value{""}, nil
+	}
+	if (float64(len(s))+float64(len(sep)))*n > float64(st.opts.MaxStringLength) {
+		return nil, fmt.Errorf("resulting string too large")
+	}
+	parts := make([]string, int(n))
+	for i := range parts {
+		parts[i] = s
+	}
+	return []Value{strings.Join(parts, sep)}, nil
+}
+
+func stringReverse(args []Value) ([]Value, error) {
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return []Value{string(b)}, nil
+}
+
+func stringSub(args []Value) ([]Value, error) {
+	s, err := argString(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	i, err := optNumber(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	j, err := optNumber(args, 2, -1)
+	if err != nil {
+		return nil, err
+	}
+	from, to := stringRange(len(s), i, j)
+	return []Value{s[from:to]}, nil
+}
+
+// table
+
+func tableConcat(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	sep := ""
+	if valueAt(args, 1) != nil {
+		if sep, err = argString(args, 1); err != nil {
+			return nil, err
+		}
+	}
+	from, err := optNumber(args, 2, 1)
+	if err != nil {
+		return nil, err
+	}
+	to, err := optNumber(args, 3, float64(t.Len()))
+	if err != nil {
+		return nil, err
+	}
+	var parts []string
+	for i := from; i <= to; i++ {
+		switch v := t.Get(i).(type) {
+		case string:
+			parts = append(parts, v)
+		case float64:
+			parts = append(parts, formatNumber(v))
+		default:
+			return nil, fmt.Errorf("invalid value (at index %s) in table for 'concat'", formatNumber(i))
+		}
+	}
+	return []Value{strings.Join(parts, sep)}, nil
+}
+
+// tableInsert appends a value or inserts it at a position, shifting the rest up
+func tableInsert(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch len(args) {
+	case 2:
+		t.Append(args[1])
+		return nil, nil
+	case 3:
+		pos, err := argNumber(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		n := t.Len()
+		if pos < 1 || pos > float64(n+1) || pos != math.Trunc(pos) {
+			return nil, fmt.Errorf("bad argument #2 (position out of bounds)")
+		}
+		for i := n; i >= int(pos); i-- {
+			_ = t.Set(float64(i+1), t.Get(float64(i)))
+		}
+		_ = t.Set(pos, args[2])
+		return nil, nil
+	}
+	return nil, fmt.Errorf("wrong number of arguments to 'insert'")
+}
+
+// tableRemove removes and returns the last value or the one at a position,
+// shifting the rest down
+func tableRemove(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	n := t.Len()
+	pos, err := optNumber(args, 1, float64(n))
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return []Value{nil}, nil
+	}
+	if pos < 1 || pos > float64(n) || pos != math.Trunc(pos) {
+		return nil, fmt.Errorf("bad argument #2 (position out of bounds)")
+	}
+	removed := t.Get(pos)
+	for i := int(pos); i < n; i++ {
+		_ = t.Set(float64(i), t.Get(float64(i+1)))
+	}
+	_ = t.Set(float64(n), nil)
+	return []Value{removed}, nil
+}
+
+// tableSort sorts the array part with "<" or a comparison function
+func (st *State) tableSort(args []Value) ([]Value, error) {
+	t, err := argTable(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	less := valueAt(args, 1)
+	values := make([]Value, t.Len())
+	for i := range values {
+		values[i] = t.Get(float64(i + 1))
+	}
+
+	var sortErr error
+	sort.SliceStable(values, func(a, b int) bool {
+		if sortErr != nil {
+			return false
+		}
+		var result Value
+		if less == nil {
+			result, sortErr = st.compare("<", values[a], values[b], 0)
+		} else {
+			var rets []Value
+			rets, sortErr = st.call(less, []Value{values[a], values[b]}, 0)
+			result = valueAt(rets, 0)
+		}
+		return Truthy(result)
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+	for i, v := range values {
+		_ = t.Set(float64(i+1), v)
+	}
+	return nil, nil
+}
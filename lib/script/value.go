@@ -0,0 +1,262 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Value is a script value: nil, bool, float64, string, *Table, *Function
+// or *GoFunction
+type Value interface{}
+
+// GoFunction is a Go function callable from scripts. It receives the call's
+// arguments and returns its results; a returned error is raised in the
+// script like error() would.
+type GoFunction struct {
+	Name string
+	Fn   func(args []Value) ([]Value, error)
+}
+
+// NewGoFunction wraps fn so scripts can call it
+func NewGoFunction(name string, fn func(args []Value) ([]Value, error)) *GoFunction {
+	return &GoFunction{Name: name, Fn: fn}
+}
+
+// Function is a function defined by a script
+type Function struct {
+	def *funcExpr
+	env *scope // Variables the function closes over
+}
+
+// Table is the script's only data structure, used as array and map. Keys
+// 1..n live in a slice, everything else in a map.
+type Table struct {
+	array []Value
+	hash  map[Value]Value
+}
+
+// NewTable creates an empty table
+func NewTable() *Table {
+	return &Table{}
+}
+
+// Get returns the value stored under key, nil if there is none
+func (t *Table) Get(key Value) Value {
+	key = normalizeKey(key)
+	if i, ok := arrayIndex(key); ok && i <= len(t.array) {
+		return t.array[i-1]
+	}
+	if t.hash == nil {
+		return nil
+	}
+	return t.hash[key]
+}
+
+// Set stores value under key; storing nil removes the key. Keys must not
+// be nil or NaN.
+func (t *Table) Set(key, value Value) error {
+	key = normalizeKey(key)
+	if key == nil {
+		return fmt.Errorf("table index is nil")
+	}
+	if n, ok := key.(float64); ok && math.IsNaN(n) {
+		return fmt.Errorf("table index is NaN")
+	}
+
+	if i, ok := arrayIndex(key); ok {
+		switch {
+		case i <= len(t.array):
+			t.array[i-1] = value
+			if value == nil && i == len(t.array) {
+				t.trimArray()
+			}
+			return nil
+		case i == len(t.array)+1 && value != nil:
+			t.array = append(t.array, value)
+			t.migrateHash()
+			return nil
+		}
+	}
+
+	if value == nil {
+		delete(t.hash, key)
+		return nil
+	}
+	if t.hash == nil {
+		t.hash = make(map[Value]Value)
+	}
+	t.hash[key] = value
+	return nil
+}
+
+// Len returns the table's length: the number of items in its array part
+func (t *Table) Len() int {
+	return len(t.array)
+}
+
+// Append adds value at the end of the array part
+func (t *Table) Append(value Value) {
+	_ = t.Set(float64(len(t.array)+1), value)
+}
+
+// Keys returns the table's keys: array indexes in order, then the other
+// keys sorted so iteration is repeatable
+func (t *Table) Keys() []Value {
+	keys := make([]Value, 0, len(t.array)+len(t.hash))
+	for i := range t.array {
+		keys = append(keys, float64(i+1))
+	}
+	var rest []Value
+	for key := range t.hash {
+		rest = append(rest, key)
+	}
+	sort.Slice(rest, func(a, b int) bool { return keyLess(rest[a], rest[b]) })
+	return append(keys, rest...)
+}
+
+// trimArray drops trailing nils from the array part
+func (t *Table) trimArray() {
+	n := len(t.array)
+	for n > 0 && t.array[n-1] == nil {
+		n--
+	}
+	t.array = t.array[:n]
+}
+
+// migrateHash moves keys that now continue the array out of the map
+func (t *Table) migrateHash() {
+	for len(t.hash) > 0 {
+		next := float64(len(t.array) + 1)
+		value, ok := t.hash[next]
+		if !ok {
+			return
+		}
+		delete(t.hash, next)
+		t.array = append(t.array, value)
+	}
+}
+
+// normalizeKey turns -0 into 0 so both find the same entry
+func normalizeKey(key Value) Value {
+	if n, ok := key.(float64); ok && n == 0 {
+		return float64(0)
+	}
+	return key
+}
+
+// arrayIndex returns key as a positive integer index
+func arrayIndex(key Value) (int, bool) {
+	n, ok := key.(float64)
+	if !ok || n < 1 || n != math.Trunc(n) || n > math.MaxInt32 {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// keyLess orders keys by type, then value; tables and functions by their
+// printed address
+func keyLess(a, b Value) bool {
+	ta, tb := TypeName(a), TypeName(b)
+	if ta != tb {
+		return ta < tb
+	}
+	switch av := a.(type) {
+	case float64:
+		return av < b.(float64)
+	case string:
+		return av < b.(string)
+	case bool:
+		return !av && b.(bool)
+	}
+	return ToString(a) < ToString(b)
+}
+
+// TypeName returns the script type of a value, as type() reports it
+func TypeName(v Value) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *Table:
+		return "table"
+	case *Function, *GoFunction:
+		return "function"
+	}
+	return "userdata"
+}
+
+// Truthy reports whether a value counts as true: everything but nil and false
+func Truthy(v Value) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	}
+	return true
+}
+
+// ToString converts a value to text as tostring() does
+func ToString(v Value) string {
+	switch x := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return formatNumber(x)
+	case string:
+		return x
+	case *Table:
+		return fmt.Sprintf("table: %p", x)
+	case *Function:
+		return fmt.Sprintf("function: %p", x)
+	case *GoFunction:
+		return fmt.Sprintf("function: builtin: %s", x.Name)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// formatNumber prints integers without a fraction and other numbers with
+// up to 14 significant digits
+func formatNumber(n float64) string {
+	switch {
+	case math.IsInf(n, 1):
+		return "inf"
+	case math.IsInf(n, -1):
+		return "-inf"
+	case math.IsNaN(n):
+		return "nan"
+	case n == math.Trunc(n) && math.Abs(n) < 1e15:
+		return strconv.FormatInt(int64(n), 10)
+	}
+	return strconv.FormatFloat(n, 'g', 14, 64)
+}
+
+// ToNumber converts numbers and numeric strings to a number
+func ToNumber(v Value) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case string:
+		s := strings.TrimSpace(x)
+		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+			n, err := strconv.ParseUint(s[2:], 16, 64)
+			return float64(n), err == nil
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || strings.ContainsAny(s, "nN_") { // No "inf", "nan" or Go digit separators
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
+}
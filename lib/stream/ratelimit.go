@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles chat commands per viewer and across the whole chat.
+// A busy chat must not turn the companion into a strobe light, and one viewer
+// must not be able to monopolise it.
+type RateLimiter struct {
+	mu             sync.Mutex
+	viewerCooldown time.Duration
+	globalCooldown time.Duration
+	lastByViewer   map[string]time.Time
+	lastGlobal     time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the given cooldowns.
+// A zero cooldown disables that limit.
+func NewRateLimiter(viewerCooldown, globalCooldown time.Duration) *RateLimiter {
+	return &RateLimiter{
+		viewerCooldown: viewerCooldown,
+		globalCooldown: globalCooldown,
+		lastByViewer:   make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether the viewer may run a command at now and, if so,
+// records the use
+func (rl *RateLimiter) Allow(viewer string, now time.Time) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.globalCooldown > 0 && !rl.lastGlobal.IsZero() && now.Sub(rl.lastGlobal) < rl.globalCooldown {
+		return false
+	}
+
+	if last, exists := rl.lastByViewer[viewer]; exists && rl.viewerCooldown > 0 && now.Sub(last) < rl.viewerCooldown {
+		return false
+	}
+
+	rl.lastGlobal = now
+	rl.lastByViewer[viewer] = now
+	rl.prune(now)
+	return true
+}
+
+// prune drops viewers whose cooldown has expired so the map stays small
+// during long streams. Must be called with rl.mu held.
+func (rl *RateLimiter) prune(now time.Time) {
+	if len(rl.lastByViewer) < 1024 {
+		return
+	}
+	for viewer, last := range rl.lastByViewer {
+		if now.Sub(last) >= rl.viewerCooldown {
+			delete(rl.lastByViewer, viewer)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Package stream implements streamer mode: chat commands from a live stream
+// (currently Twitch IRC) trigger character interactions, with per-viewer rate
+// limiting and attribution of who triggered each response.
+package stream
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Twitch IRC defaults
+const (
+	DefaultTwitchServer = "irc.chat.twitch.tv:6697"
+
+	defaultViewerCooldown = 30 * time.Second
+	defaultGlobalCooldown = 3 * time.Second
+	maxReconnectDelay     = 2 * time.Minute
+)
+
+// DefaultCommands maps chat commands to interaction names found in most cards
+var DefaultCommands = map[string]string{
+	"!feed":       "feed",
+	"!pet":        "pet",
+	"!play":       "play",
+	"!gift":       "give_gift",
+	"!compliment": "compliment",
+}
+
+// Companion is the subset of character behaviour chat commands can trigger
+type Companion interface {
+	HandleGameInteraction(interactionType string) string
+	HandleRomanceInteraction(interactionType string) string
+}
+
+// Notifier displays a response with attribution to the viewer who caused it
+type Notifier func(viewer, response string)
+
+// TwitchConfig configures the Twitch chat connection
+type TwitchConfig struct {
+	Channel        string            // Channel to join, without '#'
+	Username       string            // Bot login; empty connects anonymously (read-only)
+	OAuthToken     string            // "oauth:..." token; required when Username is set
+	Server         string            // IRC server address (default: irc.chat.twitch.tv:6697)
+	Commands       map[string]string // Chat command -> interaction name (default: DefaultCommands)
+	ViewerCooldown time.Duration     // Minimum time between commands from one viewer (default: 30s)
+	GlobalCooldown time.Duration     // Minimum time between any two commands (default: 3s)
+}
+
+// ChatMessage is a single chat line from a viewer
+type ChatMessage struct {
+	Viewer string
+	Text   string
+}
+
+// TwitchBot listens to a Twitch channel and turns chat commands into interactions
+type TwitchBot struct {
+	config    TwitchConfig
+	companion Companion
+	notify    Notifier
+	limiter   *RateLimiter
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTwitchBot creates a bot for the configured channel.
+// notify may be nil if responses should only be logged.
+func NewTwitchBot(config TwitchConfig, companion Companion, notify Notifier) (*TwitchBot, error) {
+	if companion == nil {
+		return nil, fmt.Errorf("companion cannot be nil")
+	}
+
+	config.Channel = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(config.Channel), "#"))
+	if config.Channel == "" {
+		return nil, fmt.Errorf("twitch channel is required")
+	}
+	if config.Username != "" && config.OAuthToken == "" {
+		return nil, fmt.Errorf("oauth token is required when a username is set")
+	}
+	if config.Server == "" {
+		config.Server = DefaultTwitchServer
+	}
+	if len(config.Commands) == 0 {
+		config.Commands = DefaultCommands
+	}
+	if config.ViewerCooldown == 0 {
+		config.ViewerCooldown = defaultViewerCooldown
+	}
+	if config.GlobalCooldown == 0 {
+		config.GlobalCooldown = defaultGlobalCooldown
+	}
+
+	return &TwitchBot{
+		config:    config,
+		companion: companion,
+		notify:    notify,
+		limiter:   NewRateLimiter(config.ViewerCooldown, config.GlobalCooldown),
+	}, nil
+}
+
+// Start connects to Twitch in the background, reconnecting with backoff
+func (b *TwitchBot) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b.mu.Lock()
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	b.mu.Unlock()
+
+	go b.connectLoop(ctx)
+}
+
+// Stop disconnects from chat and waits for the connection loop to exit
+func (b *TwitchBot) Stop() {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// connectLoop keeps a chat connection open until ctx is cancelled
+func (b *TwitchBot) connectLoop(ctx context.Context) {
+	defer close(b.done)
+
+	delay := time.Second
+	for {
+		err := b.connectOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"channel": b.config.Channel,
+			"error":   err,
+			"retryIn": delay.String(),
+		}).Warn("Twitch chat connection lost")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// connectOnce dials the server and serves the connection until it fails
+func (b *TwitchBot) connectOnce(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", b.config.Server, &tls.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", b.config.Server, err)
+	}
+
+	// Unblock the reader when the bot is stopped
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	logrus.WithField("channel", b.config.Channel).Info("Connected to Twitch chat")
+	return b.serve(conn)
+}
+
+// serve logs in, joins the channel and processes chat lines from rw
+func (b *TwitchBot) serve(rw io.ReadWriter) error {
+	username, token := b.config.Username, b.config.OAuthToken
+	if username == "" {
+		// Twitch accepts any justinfan login as an anonymous read-only viewer
+		username = fmt.Sprintf("justinfan%d", 10000+rand.Intn(80000))
+		token = "SCHMOOPIIE"
+	}
+
+	login := fmt.Sprintf("PASS %s\r\nNICK %s\r\nJOIN #%s\r\n", token, strings.ToLower(username), b.config.Channel)
+	if _, err := io.WriteString(rw, login); err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+
+	scanner := bufio.NewScanner(rw)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "PING") {
+			if _, err := io.WriteString(rw, "PONG"+strings.TrimPrefix(line, "PING")+"\r\n"); err != nil {
+				return fmt.Errorf("failed to answer ping: %w", err)
+			}
+			continue
+		}
+
+		if msg, ok := ParsePrivmsg(line); ok {
+			b.HandleMessage(msg, time.Now())
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("chat read failed: %w", err)
+	}
+	return io.EOF
+}
+
+// HandleMessage runs the interaction for a chat command, if any.
+// Returns the character's response and whether a command was executed.
+func (b *TwitchBot) HandleMessage(msg ChatMessage, now time.Time) (string, bool) {
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	interaction, exists := b.config.Commands[strings.ToLower(fields[0])]
+	if !exists {
+		return "", false
+	}
+
+	if !b.limiter.Allow(strings.ToLower(msg.Viewer), now) {
+		logrus.WithFields(logrus.Fields{
+			"viewer":  msg.Viewer,
+			"command": fields[0],
+		}).Debug("Chat command rate limited")
+		return "", false
+	}
+
+	response := b.companion.HandleGameInteraction(interaction)
+	if response == "" {
+		response = b.companion.HandleRomanceInteraction(interaction)
+	}
+	if response == "" {
+		return "", false
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"viewer":      msg.Viewer,
+		"interaction": interaction,
+	}).Info("Chat command triggered interaction")
+
+	if b.notify != nil {
+		b.notify(msg.Viewer, response)
+	}
+	return response, true
+}
+
+// ParsePrivmsg extracts the viewer and text from an IRC PRIVMSG line such as
+// ":viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #channel :!feed".
+// IRCv3 tags ("@key=value;... ") are ignored.
+func ParsePrivmsg(line string) (ChatMessage, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "@") {
+		space := strings.IndexByte(line, ' ')
+		if space < 0 {
+			return ChatMessage{}, false
+		}
+		line = line[space+1:]
+	}
+
+	if !strings.HasPrefix(line, ":") {
+		return ChatMessage{}, false
+	}
+
+	prefix, rest, found := strings.Cut(line[1:], " ")
+	if !found || !strings.HasPrefix(rest, "PRIVMSG ") {
+		return ChatMessage{}, false
+	}
+
+	_, text, found := strings.Cut(rest, " :")
+	if !found {
+		return ChatMessage{}, false
+	}
+
+	viewer, _, _ := strings.Cut(prefix, "!")
+	if viewer == "" {
+		return ChatMessage{}, false
+	}
+
+	return ChatMessage{Viewer: viewer, Text: text}, true
+}
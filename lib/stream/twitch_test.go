@@ -0,0 +1,163 @@
+package stream
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCompanion responds to a fixed set of interactions
+type fakeCompanion struct {
+	calls []string
+}
+
+func (f *fakeCompanion) HandleGameInteraction(interactionType string) string {
+	f.calls = append(f.calls, interactionType)
+	if interactionType == "feed" || interactionType == "pet" {
+		return "Thanks for the " + interactionType + "!"
+	}
+	return ""
+}
+
+func (f *fakeCompanion) HandleRomanceInteraction(interactionType string) string {
+	if interactionType == "give_gift" {
+		return "A gift for me?"
+	}
+	return ""
+}
+
+func TestParsePrivmsg(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		viewer string
+		text   string
+		ok     bool
+	}{
+		{"plain", ":alice!alice@alice.tmi.twitch.tv PRIVMSG #chan :!feed now", "alice", "!feed now", true},
+		{"tagged", "@badge-info=;color=#FF0000 :bob!bob@bob.tmi.twitch.tv PRIVMSG #chan :!pet\r\n", "bob", "!pet", true},
+		{"ping", "PING :tmi.twitch.tv", "", "", false},
+		{"join", ":carol!carol@carol.tmi.twitch.tv JOIN #chan", "", "", false},
+		{"garbage", "hello", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := ParsePrivmsg(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("ParsePrivmsg ok = %v, want %v", ok, tt.ok)
+			}
+			if msg.Viewer != tt.viewer || msg.Text != tt.text {
+				t.Errorf("Got %+v, want viewer %q text %q", msg, tt.viewer, tt.text)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(30*time.Second, 2*time.Second)
+	start := time.Now()
+
+	if !rl.Allow("alice", start) {
+		t.Fatal("First command should be allowed")
+	}
+	if rl.Allow("bob", start.Add(time.Second)) {
+		t.Error("Global cooldown should block bob")
+	}
+	if !rl.Allow("bob", start.Add(3*time.Second)) {
+		t.Error("Bob should be allowed after the global cooldown")
+	}
+	if rl.Allow("alice", start.Add(10*time.Second)) {
+		t.Error("Viewer cooldown should block alice")
+	}
+	if !rl.Allow("alice", start.Add(31*time.Second)) {
+		t.Error("Alice should be allowed after her cooldown")
+	}
+}
+
+func TestNewTwitchBotValidation(t *testing.T) {
+	companion := &fakeCompanion{}
+
+	if _, err := NewTwitchBot(TwitchConfig{}, companion, nil); err == nil {
+		t.Error("Expected error for missing channel")
+	}
+	if _, err := NewTwitchBot(TwitchConfig{Channel: "chan", Username: "bot"}, companion, nil); err == nil {
+		t.Error("Expected error for username without token")
+	}
+	if _, err := NewTwitchBot(TwitchConfig{Channel: "chan"}, nil, nil); err == nil {
+		t.Error("Expected error for nil companion")
+	}
+
+	bot, err := NewTwitchBot(TwitchConfig{Channel: "#MyChannel"}, companion, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bot.config.Channel != "mychannel" {
+		t.Errorf("Expected normalized channel, got %q", bot.config.Channel)
+	}
+}
+
+func TestHandleMessage(t *testing.T) {
+	companion := &fakeCompanion{}
+	var attributed []string
+	bot, err := NewTwitchBot(TwitchConfig{Channel: "chan"}, companion, func(viewer, response string) {
+		attributed = append(attributed, viewer+": "+response)
+	})
+	if err != nil {
+		t.Fatalf("NewTwitchBot failed: %v", err)
+	}
+
+	now := time.Now()
+	if response, ok := bot.HandleMessage(ChatMessage{Viewer: "alice", Text: "!FEED please"}, now); !ok || response != "Thanks for the feed!" {
+		t.Errorf("Expected feed response, got %q (%v)", response, ok)
+	}
+	if _, ok := bot.HandleMessage(ChatMessage{Viewer: "bob", Text: "hello chat"}, now.Add(time.Minute)); ok {
+		t.Error("Non-command message should be ignored")
+	}
+	if _, ok := bot.HandleMessage(ChatMessage{Viewer: "alice", Text: "!pet"}, now.Add(5*time.Second)); ok {
+		t.Error("Alice should be rate limited")
+	}
+	if response, ok := bot.HandleMessage(ChatMessage{Viewer: "bob", Text: "!gift"}, now.Add(2*time.Minute)); !ok || response != "A gift for me?" {
+		t.Errorf("Expected romance fallback response, got %q (%v)", response, ok)
+	}
+
+	if len(attributed) != 2 || attributed[0] != "alice: Thanks for the feed!" {
+		t.Errorf("Unexpected attributions: %v", attributed)
+	}
+}
+
+// chatConn feeds scripted server lines and captures what the bot writes
+type chatConn struct {
+	io.Reader
+	written bytes.Buffer
+}
+
+func (c *chatConn) Write(p []byte) (int, error) { return c.written.Write(p) }
+
+func TestServeLogsInAndAnswersPing(t *testing.T) {
+	companion := &fakeCompanion{}
+	bot, err := NewTwitchBot(TwitchConfig{Channel: "chan", Username: "MyBot", OAuthToken: "oauth:abc"}, companion, nil)
+	if err != nil {
+		t.Fatalf("NewTwitchBot failed: %v", err)
+	}
+
+	conn := &chatConn{Reader: strings.NewReader(
+		"PING :tmi.twitch.tv\r\n" +
+			":alice!alice@alice.tmi.twitch.tv PRIVMSG #chan :!feed\r\n")}
+
+	if err := bot.serve(conn); err != io.EOF {
+		t.Fatalf("Expected EOF at end of stream, got %v", err)
+	}
+
+	written := conn.written.String()
+	for _, want := range []string{"PASS oauth:abc\r\n", "NICK mybot\r\n", "JOIN #chan\r\n", "PONG :tmi.twitch.tv\r\n"} {
+		if !strings.Contains(written, want) {
+			t.Errorf("Expected bot to send %q, got %q", want, written)
+		}
+	}
+	if len(companion.calls) != 1 || companion.calls[0] != "feed" {
+		t.Errorf("Expected one feed interaction, got %v", companion.calls)
+	}
+}
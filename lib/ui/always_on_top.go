@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+// focusFallbackInterval is how often the window re-requests focus when the
+// platform cannot keep it on top natively
+const focusFallbackInterval = 5 * time.Second
+
+// SetAlwaysOnTop keeps the companion above other windows, or releases it.
+// Uses the native window manager hint where available and falls back to
+// periodic focus requests elsewhere (e.g. Wayland).
+func (dw *DesktopWindow) SetAlwaysOnTop(enabled bool) {
+	nativeErr := native.ErrAlwaysOnTopUnsupported
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		nativeErr = native.SetAlwaysOnTop(win, enabled)
+	})
+
+	dw.topMu.Lock()
+	defer dw.topMu.Unlock()
+
+	dw.alwaysOnTop = enabled
+	dw.stopFocusFallback()
+
+	if nativeErr != nil && !errors.Is(nativeErr, native.ErrAlwaysOnTopUnsupported) {
+		logrus.WithError(nativeErr).Warn("Native always-on-top failed")
+	}
+
+	if enabled && nativeErr != nil {
+		logrus.Debug("Using focus requests to approximate always-on-top")
+		dw.startFocusFallback()
+	}
+}
+
+// IsAlwaysOnTop returns whether always-on-top is enabled
+func (dw *DesktopWindow) IsAlwaysOnTop() bool {
+	dw.topMu.Lock()
+	defer dw.topMu.Unlock()
+	return dw.alwaysOnTop
+}
+
+// startFocusFallback periodically raises the window.
+// Must be called with dw.topMu held.
+func (dw *DesktopWindow) startFocusFallback() {
+	stop := make(chan struct{})
+	dw.focusFallbackStop = stop
+	window := dw.window
+
+	go func() {
+		ticker := time.NewTicker(focusFallbackInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				window.RequestFocus()
+			}
+		}
+	}()
+}
+
+// stopFocusFallback stops the focus loop if it is running.
+// Must be called with dw.topMu held.
+func (dw *DesktopWindow) stopFocusFallback() {
+	if dw.focusFallbackStop != nil {
+		close(dw.focusFallbackStop)
+		dw.focusFallbackStop = nil
+	}
+}
+
+// buildAlwaysOnTopMenuItem creates the toggle for keeping the companion above other windows
+func (dw *DesktopWindow) buildAlwaysOnTopMenuItem() ContextMenuItem {
+	if dw.IsAlwaysOnTop() {
+		return ContextMenuItem{
+			Text: "📌 Disable Always on Top",
+			Callback: func() {
+				dw.SetAlwaysOnTop(false)
+			},
+		}
+	}
+
+	return ContextMenuItem{
+		Text: "📌 Always on Top",
+		Callback: func() {
+			dw.SetAlwaysOnTop(true)
+		},
+	}
+}
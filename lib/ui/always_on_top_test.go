@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestAlwaysOnTopToggle(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+	defer dw.Close()
+
+	// The test driver has no native handle, so the focus fallback is used
+	dw.SetAlwaysOnTop(true)
+	if !dw.IsAlwaysOnTop() {
+		t.Fatal("Always-on-top should be enabled")
+	}
+	if dw.focusFallbackStop == nil {
+		t.Error("Focus fallback should run without native support")
+	}
+
+	item := dw.buildAlwaysOnTopMenuItem()
+	if item.Text != "📌 Disable Always on Top" {
+		t.Errorf("Unexpected menu text while enabled: %q", item.Text)
+	}
+
+	item.Callback()
+	if dw.IsAlwaysOnTop() {
+		t.Error("Menu callback should disable always-on-top")
+	}
+	if dw.focusFallbackStop != nil {
+		t.Error("Focus fallback should stop when disabled")
+	}
+	if item := dw.buildAlwaysOnTopMenuItem(); item.Text != "📌 Always on Top" {
+		t.Errorf("Unexpected menu text while disabled: %q", item.Text)
+	}
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+)
+
+// buildUndoMenuItem offers taking back the last interaction while the
+// card's undo window is open
+func (dw *DesktopWindow) buildUndoMenuItem() (ContextMenuItem, bool) {
+	if !dw.character.GetCard().HasUndo() {
+		return ContextMenuItem{}, false
+	}
+	name, remaining, ok := dw.character.GetUndoableInteraction()
+	if !ok {
+		return ContextMenuItem{}, false
+	}
+
+	return ContextMenuItem{
+		Text: fmt.Sprintf("↩️ Undo %s (%.0fs)", name, math.Ceil(remaining.Seconds())),
+		Callback: func() {
+			if !dw.character.UndoLastInteraction() {
+				dw.showDialog("Too late to undo that.")
+				return
+			}
+			dw.showDialog(fmt.Sprintf("Undid %s.", name))
+		},
+	}, true
+}
+
+// buildApologyMenuItem offers apologizing when the card allows it
+func (dw *DesktopWindow) buildApologyMenuItem() (ContextMenuItem, bool) {
+	if !dw.character.GetCard().HasApology() {
+		return ContextMenuItem{}, false
+	}
+
+	return ContextMenuItem{
+		Text: "🙏 Apologize",
+		Callback: func() {
+			if response := dw.character.Apologize(); response != "" {
+				dw.showDialog(response)
+			}
+		},
+	}, true
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/analytics"
+)
+
+// sparkBlocks draw the mood trajectory, lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// topInteractionCount is how many interactions the weekly summary lists
+const topInteractionCount = 3
+
+// buildAnalyticsMenuItem creates the weekly summary entry.
+// Only shown when the user opted in to analytics.
+func (dw *DesktopWindow) buildAnalyticsMenuItem() (ContextMenuItem, bool) {
+	if dw.character.GetAnalytics() == nil {
+		return ContextMenuItem{}, false
+	}
+
+	return ContextMenuItem{
+		Text: "📈 This Week",
+		Callback: func() {
+			dw.showWeeklySummary()
+		},
+	}, true
+}
+
+// showWeeklySummary shows "your companion this week"
+func (dw *DesktopWindow) showWeeklySummary() {
+	summary := dw.character.GetAnalytics().WeeklySummary()
+
+	titleLabel := widget.NewLabel(fmt.Sprintf("%s this week", dw.character.GetName()))
+	titleLabel.Alignment = fyne.TextAlignCenter
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	body := widget.NewLabel(formatWeeklySummary(summary))
+	body.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(titleLabel, body)
+	content.Resize(fyne.NewSize(260, 220))
+	dw.showModalContent(content)
+}
+
+// formatWeeklySummary renders a summary as plain text for the overlay
+func formatWeeklySummary(summary analytics.Summary) string {
+	if summary.TotalInteractions == 0 {
+		return "No interactions recorded yet. Come say hi!"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "💬 %d interactions\n", summary.TotalInteractions)
+
+	b.WriteString("⭐ Favorites: ")
+	for i, entry := range summary.TopInteractions {
+		if i == topInteractionCount {
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s (%d)", entry.Name, entry.Count)
+	}
+	b.WriteString("\n")
+
+	if mood := moodSparkline(summary.Days); mood != "" {
+		fmt.Fprintf(&b, "😊 Mood: %s\n", mood)
+	}
+
+	if summary.AverageLatency > 0 {
+		fmt.Fprintf(&b, "⏱ Replies in %s on average (slowest %s)\n",
+			summary.AverageLatency.Round(time.Millisecond), summary.MaxLatency.Round(time.Millisecond))
+	}
+
+	if len(summary.Backends) > 0 {
+		parts := make([]string, len(summary.Backends))
+		for i, entry := range summary.Backends {
+			parts[i] = fmt.Sprintf("%s %d", entry.Name, entry.Count)
+		}
+		fmt.Fprintf(&b, "🧠 Dialog: %s\n", strings.Join(parts, ", "))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// moodSparkline draws one block per day from average mood (0-100).
+// Days without samples are shown as a dot; empty when there is no mood data.
+func moodSparkline(days []analytics.DaySummary) string {
+	var b strings.Builder
+	hasMood := false
+
+	for _, day := range days {
+		if !day.HasMood {
+			b.WriteRune('·')
+			continue
+		}
+		hasMood = true
+
+		index := int(day.Mood / 100 * float64(len(sparkBlocks)))
+		if index >= len(sparkBlocks) {
+			index = len(sparkBlocks) - 1
+		}
+		if index < 0 {
+			index = 0
+		}
+		b.WriteRune(sparkBlocks[index])
+	}
+
+	if !hasMood {
+		return ""
+	}
+	return b.String()
+}
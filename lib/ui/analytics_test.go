@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/analytics"
+)
+
+func TestFormatWeeklySummary(t *testing.T) {
+	if text := formatWeeklySummary(analytics.Summary{}); !strings.Contains(text, "No interactions") {
+		t.Errorf("Expected empty-week message, got %q", text)
+	}
+
+	summary := analytics.Summary{
+		TotalInteractions: 12,
+		TopInteractions:   []analytics.Count{{Name: "click", Count: 8}, {Name: "feed", Count: 3}, {Name: "pet", Count: 1}, {Name: "play", Count: 1}},
+		Backends:          []analytics.Count{{Name: "markov_chain", Count: 5}},
+		AverageLatency:    15 * time.Millisecond,
+		MaxLatency:        90 * time.Millisecond,
+		Days:              []analytics.DaySummary{{Mood: 10, HasMood: true}, {}, {Mood: 100, HasMood: true}},
+	}
+	text := formatWeeklySummary(summary)
+
+	for _, want := range []string{"12 interactions", "click (8), feed (3), pet (1)", "▁·█", "15ms", "markov_chain 5"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in summary:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "play") {
+		t.Error("Only the top interactions should be listed")
+	}
+}
+
+func TestAnalyticsMenuItemRequiresOptIn(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if _, ok := dw.buildAnalyticsMenuItem(); ok {
+		t.Error("Weekly summary should be hidden unless analytics is enabled")
+	}
+
+	collector, err := analytics.NewCollector(filepath.Join(t.TempDir(), "analytics.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	char.SetAnalytics(collector)
+	if _, ok := dw.buildAnalyticsMenuItem(); !ok {
+		t.Error("Weekly summary should be offered once analytics is enabled")
+	}
+}
@@ -66,11 +66,18 @@ func (bid *BattleInvitationDialog) setupLayout() {
 
 // Show displays the battle invitation dialog
 func (bid *BattleInvitationDialog) Show(fromCharacter string, onResponse func(accepted bool)) {
+	bid.ShowRequest("Battle Invitation", "Battle invitation from "+fromCharacter+". Do you accept?", onResponse)
+}
+
+// ShowRequest displays the dialog for any other accept/decline battle request,
+// such as a peer asking to spectate
+func (bid *BattleInvitationDialog) ShowRequest(title, message string, onResponse func(accepted bool)) {
 	bid.mu.Lock()
 	defer bid.mu.Unlock()
 
 	bid.onResponse = onResponse
-	bid.messageLabel.SetText("Battle invitation from " + fromCharacter + ". Do you accept?")
+	bid.titleLabel.SetText(title)
+	bid.messageLabel.SetText(message)
 	bid.visible = true
 	bid.content.Show()
 	bid.Refresh()
@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// spectatorPolicyOptions maps the overlay's policy picker to hub policies
+var spectatorPolicyOptions = []struct {
+	label  string
+	policy network.SpectatorPolicy
+}{
+	{"No spectators", network.SpectatorsDenied},
+	{"Ask me", network.SpectatorsAsk},
+	{"Anyone", network.SpectatorsAllowed},
+}
+
+// BattleSpectatorView is a read-only view of a battle between other peers:
+// HP bars for each participant and the latest action. It has no battle
+// controls, so watching can never affect the fight.
+type BattleSpectatorView struct {
+	content     *fyne.Container
+	titleLabel  *widget.Label
+	barsBox     *fyne.Container
+	actionLabel *widget.Label
+	stopButton  *widget.Button
+
+	mu       sync.Mutex
+	battleID string
+	bars     map[string]*widget.ProgressBar
+}
+
+// NewBattleSpectatorView creates a hidden spectator view; onStop is called
+// when the user stops watching
+func NewBattleSpectatorView(onStop func(battleID string)) *BattleSpectatorView {
+	v := &BattleSpectatorView{
+		titleLabel:  widget.NewLabel("👀 Spectating"),
+		barsBox:     container.NewVBox(),
+		actionLabel: widget.NewLabel("Waiting for the first move..."),
+		bars:        make(map[string]*widget.ProgressBar),
+	}
+	v.titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+	v.actionLabel.Wrapping = fyne.TextWrapWord
+
+	v.stopButton = widget.NewButton("Stop watching", func() {
+		battleID := v.BattleID()
+		v.Hide()
+		if onStop != nil && battleID != "" {
+			onStop(battleID)
+		}
+	})
+
+	v.content = container.NewVBox(v.titleLabel, v.barsBox, v.actionLabel, v.stopButton)
+	v.content.Hide()
+	return v
+}
+
+// Start shows the view for a battle before its first update arrives
+func (v *BattleSpectatorView) Start(listing network.BattleListing) {
+	v.mu.Lock()
+	v.battleID = listing.BattleID
+	v.bars = make(map[string]*widget.ProgressBar)
+	v.mu.Unlock()
+
+	v.titleLabel.SetText(fmt.Sprintf("👀 Spectating: %s", strings.Join(listing.Participants, " vs ")))
+	v.barsBox.RemoveAll()
+	v.actionLabel.SetText("Waiting for the first move...")
+	v.stopButton.SetText("Stop watching")
+	v.content.Show()
+}
+
+// Update shows the latest state of the watched battle
+func (v *BattleSpectatorView) Update(update network.BattleSpectatorUpdatePayload) {
+	v.mu.Lock()
+	if update.BattleID != v.battleID {
+		v.mu.Unlock()
+		return
+	}
+
+	names := make([]string, 0, len(update.Participants))
+	for name := range update.Participants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var added []fyne.CanvasObject
+	for _, name := range names {
+		stats := update.Participants[name]
+		bar, exists := v.bars[name]
+		if !exists {
+			bar = widget.NewProgressBar()
+			bar.TextFormatter = hpFormatter(bar)
+			v.bars[name] = bar
+			added = append(added, widget.NewLabel(name), bar)
+		}
+		bar.Max = stats.MaxHP
+		bar.SetValue(stats.HP)
+	}
+	v.mu.Unlock()
+
+	for _, object := range added {
+		v.barsBox.Add(object)
+	}
+
+	if update.LastAction != "" {
+		v.actionLabel.SetText(update.LastAction)
+	}
+	if update.Ended {
+		result := "Draw!"
+		if update.Winner != "" {
+			result = update.Winner + " wins!"
+		}
+		v.actionLabel.SetText(fmt.Sprintf("%s %s", update.LastAction, result))
+		v.stopButton.SetText("Close")
+	}
+}
+
+// Hide hides the view and forgets the battle
+func (v *BattleSpectatorView) Hide() {
+	v.mu.Lock()
+	v.battleID = ""
+	v.mu.Unlock()
+	v.content.Hide()
+}
+
+// BattleID returns the battle being shown, or "" if none
+func (v *BattleSpectatorView) BattleID() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.battleID
+}
+
+// GetContainer returns the container for embedding in the network overlay
+func (v *BattleSpectatorView) GetContainer() *fyne.Container {
+	return v.content
+}
+
+// hpFormatter shows "HP 42/100" instead of a percentage
+func hpFormatter(bar *widget.ProgressBar) func() string {
+	return func() string {
+		return fmt.Sprintf("HP %.0f/%.0f", bar.Value, bar.Max)
+	}
+}
+
+// createSpectatorWidgets builds the "Live Battles" section of the overlay:
+// battles other peers announced, and who may watch our own battles
+func (no *NetworkOverlay) createSpectatorWidgets() *fyne.Container {
+	no.battleList = widget.NewList(
+		func() int {
+			no.battleMutex.RLock()
+			defer no.battleMutex.RUnlock()
+			return len(no.battles)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("Battle")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			no.battleMutex.RLock()
+			defer no.battleMutex.RUnlock()
+			if id < len(no.battles) {
+				obj.(*widget.Label).SetText("⚔️ " + strings.Join(no.battles[id].Participants, " vs "))
+			}
+		},
+	)
+	no.battleList.OnSelected = func(id widget.ListItemID) {
+		no.battleList.UnselectAll()
+		no.battleMutex.RLock()
+		if id >= len(no.battles) {
+			no.battleMutex.RUnlock()
+			return
+		}
+		listing := no.battles[id]
+		no.battleMutex.RUnlock()
+		no.watchBattle(listing)
+	}
+	no.battleList.Resize(fyne.NewSize(200, 60))
+
+	labels := make([]string, len(spectatorPolicyOptions))
+	for i, option := range spectatorPolicyOptions {
+		labels[i] = option.label
+	}
+	no.spectatorPolicy = widget.NewSelect(labels, nil)
+	no.spectatorPolicy.SetSelected(labels[0])
+
+	no.spectatorView = NewBattleSpectatorView(func(battleID string) {
+		if no.spectatorHub != nil {
+			no.spectatorHub.StopSpectating(battleID)
+		}
+	})
+
+	return container.NewVBox(
+		widget.NewLabel("Live Battles (tap to watch):"),
+		no.battleList,
+		no.spectatorView.GetContainer(),
+		container.NewBorder(nil, nil, widget.NewLabel("Spectators:"), nil, no.spectatorPolicy),
+	)
+}
+
+// setupSpectating connects the spectator hub to the overlay widgets
+func (no *NetworkOverlay) setupSpectating() {
+	no.spectatorHub = network.NewSpectatorHub(no.networkManager)
+	no.spectatorHub.SetCallbacks(
+		func(listings []network.BattleListing) {
+			no.battleMutex.Lock()
+			no.battles = listings
+			no.battleMutex.Unlock()
+			no.battleList.Refresh()
+		},
+		no.spectatorView.Update,
+		func(reply network.BattleSpectateReplyPayload) {
+			if reply.Accepted || reply.BattleID != no.spectatorView.BattleID() {
+				return
+			}
+			no.spectatorView.Hide()
+			no.addChatMessage("System", "Can't watch that battle: "+reply.Reason)
+		},
+	)
+}
+
+// watchBattle asks to spectate a battle and opens the read-only view
+func (no *NetworkOverlay) watchBattle(listing network.BattleListing) {
+	if no.spectatorHub == nil {
+		return
+	}
+	if err := no.spectatorHub.Spectate(listing.BattleID); err != nil {
+		no.addChatMessage("System", fmt.Sprintf("Can't watch that battle: %v", err))
+		return
+	}
+	no.spectatorView.Start(listing)
+	no.TrackBattleAction("local", no.localCharName, "started spectating "+strings.Join(listing.Participants, " vs "))
+}
+
+// SpectatorPolicy returns who the user allows to watch battles they start
+func (no *NetworkOverlay) SpectatorPolicy() network.SpectatorPolicy {
+	if no.spectatorPolicy == nil {
+		return network.SpectatorsDenied
+	}
+	for _, option := range spectatorPolicyOptions {
+		if option.label == no.spectatorPolicy.Selected {
+			return option.policy
+		}
+	}
+	return network.SpectatorsDenied
+}
+
+// HostBattle registers a battle the local user initiated so peers can ask to
+// watch it, following the overlay's spectator setting
+func (no *NetworkOverlay) HostBattle(battleID string, participants []string) error {
+	if no.spectatorHub == nil {
+		return nil
+	}
+	return no.spectatorHub.HostBattle(battleID, participants, no.SpectatorPolicy())
+}
+
+// GetSpectatorHub returns the battle spectating hub, nil until network
+// events are registered
+func (no *NetworkOverlay) GetSpectatorHub() *network.SpectatorHub {
+	return no.spectatorHub
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// deliverToOverlay calls the overlay's registered handler as if a peer sent msg
+func deliverToOverlay(t *testing.T, nm *mockNetworkManager, msgType network.MessageType, from string, payload interface{}) {
+	t.Helper()
+	data, _ := json.Marshal(payload)
+	handler, exists := nm.handlers[msgType]
+	if !exists {
+		t.Fatalf("No handler registered for %s", msgType)
+	}
+	if err := handler(network.Message{Type: msgType, From: from, Payload: data}, &network.Peer{ID: from}); err != nil {
+		t.Fatalf("Handler for %s failed: %v", msgType, err)
+	}
+}
+
+func TestNetworkOverlaySpectatesBattle(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	nm := &mockNetworkManager{networkID: "viewer"}
+	overlay := NewNetworkOverlay(nm)
+	overlay.RegisterNetworkEvents()
+
+	deliverToOverlay(t, nm, network.MessageTypeBattleAnnounce, "host", network.BattleAnnouncePayload{
+		BattleID: "b1", Participants: []string{"host", "rival"},
+	})
+	if overlay.battleList.Length() != 1 {
+		t.Fatalf("Expected one live battle, got %d", overlay.battleList.Length())
+	}
+
+	overlay.battleList.Select(0)
+	if overlay.spectatorView.BattleID() != "b1" || !overlay.spectatorView.GetContainer().Visible() {
+		t.Fatal("Selecting a battle should open the spectator view")
+	}
+
+	deliverToOverlay(t, nm, network.MessageTypeBattleSpectateReply, "host", network.BattleSpectateReplyPayload{BattleID: "b1", Accepted: true})
+	deliverToOverlay(t, nm, network.MessageTypeBattleSpectatorUpdate, "host", network.BattleSpectatorUpdatePayload{
+		BattleID:     "b1",
+		Participants: map[string]network.BattleParticipantStats{"host": {HP: 40, MaxHP: 100}},
+		LastAction:   "rival used attack on host for 60 damage",
+	})
+	if bar := overlay.spectatorView.bars["host"]; bar == nil || bar.Value != 40 {
+		t.Fatalf("Expected host HP bar at 40, got %+v", bar)
+	}
+	if overlay.spectatorView.actionLabel.Text != "rival used attack on host for 60 damage" {
+		t.Errorf("Unexpected last action %q", overlay.spectatorView.actionLabel.Text)
+	}
+}
+
+func TestNetworkOverlaySpectatorPolicy(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	overlay := NewNetworkOverlay(&mockNetworkManager{networkID: "host"})
+	overlay.RegisterNetworkEvents()
+
+	if overlay.SpectatorPolicy() != network.SpectatorsDenied {
+		t.Error("Spectating should be off by default")
+	}
+	overlay.spectatorPolicy.SetSelected("Anyone")
+	if overlay.SpectatorPolicy() != network.SpectatorsAllowed {
+		t.Errorf("Expected SpectatorsAllowed, got %v", overlay.SpectatorPolicy())
+	}
+}
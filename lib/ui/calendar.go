@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// buildBirthdayMenuItem creates the "set my birthday" entry for the game menu.
+// Only shown when the card has an event for the user's birthday.
+func (dw *DesktopWindow) buildBirthdayMenuItem() (ContextMenuItem, bool) {
+	if !dw.character.HasUserBirthdayEvent() {
+		return ContextMenuItem{}, false
+	}
+
+	text := "🎂 Set My Birthday"
+	if birthday := dw.character.GetUserBirthday(); birthday != "" {
+		text = fmt.Sprintf("🎂 My Birthday (%s)", birthday)
+	}
+
+	return ContextMenuItem{
+		Text: text,
+		Callback: func() {
+			dw.showBirthdayEntry()
+		},
+	}, true
+}
+
+// showBirthdayEntry asks for the user's birthday as MM-DD
+func (dw *DesktopWindow) showBirthdayEntry() {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("MM-DD")
+	entry.SetText(dw.character.GetUserBirthday())
+
+	saveButton := widget.NewButton("Save", func() {
+		dw.saveUserBirthday(entry.Text)
+	})
+	entry.OnSubmitted = dw.saveUserBirthday
+
+	content := container.NewVBox(
+		widget.NewLabel("When is your birthday?"),
+		entry,
+		saveButton,
+	)
+	content.Resize(fyne.NewSize(220, 120))
+	dw.showModalContent(content)
+}
+
+// saveUserBirthday stores the birthday and confirms in a dialog
+func (dw *DesktopWindow) saveUserBirthday(date string) {
+	if err := dw.character.SetUserBirthday(date); err != nil {
+		dw.showDialog(err.Error())
+		return
+	}
+	if date == "" {
+		dw.showDialog("Okay, I'll forget your birthday.")
+		return
+	}
+	dw.showDialog(fmt.Sprintf("Got it! I'll remember %s 🎂", date))
+}
+
+// checkForCalendarEvents announces birthdays, anniversaries and holidays
+// that fired since the last frame
+func (dw *DesktopWindow) checkForCalendarEvents() {
+	if dw.character == nil {
+		return
+	}
+
+	for _, result := range dw.character.GetCalendarEvents() {
+		text := result.Response
+		if result.Gift != "" {
+			text += fmt.Sprintf("\n🎁 Received: %s", result.Gift)
+		}
+		dw.showNotice(text)
+	}
+}
+
+// checkForScheduledEvents shows the responses of scheduled events that fired
+// since the last frame
+func (dw *DesktopWindow) checkForScheduledEvents() {
+	if dw.character == nil {
+		return
+	}
+
+	for _, result := range dw.character.GetScheduledEvents() {
+		if result.Response != "" {
+			dw.notifyReminder(result.Response)
+			dw.showNotice(result.Response)
+		}
+	}
+}
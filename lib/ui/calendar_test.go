@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestSaveUserBirthday(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if _, ok := dw.buildBirthdayMenuItem(); ok {
+		t.Error("Birthday menu item should be hidden without a userBirthday event")
+	}
+
+	dw.saveUserBirthday("02-30x")
+	if text := dw.dialog.currentText; !strings.Contains(text, "invalid date") {
+		t.Errorf("Expected invalid date message, got %q", text)
+	}
+
+	dw.saveUserBirthday("06-01")
+	if char.GetUserBirthday() != "06-01" {
+		t.Errorf("Expected birthday to be stored, got %q", char.GetUserBirthday())
+	}
+	if text := dw.dialog.currentText; !strings.Contains(text, "06-01") {
+		t.Errorf("Expected confirmation, got %q", text)
+	}
+}
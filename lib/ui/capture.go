@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+)
+
+// captureDurations are the recording lengths offered in the capture menu
+var captureDurations = []int{3, 5, 10}
+
+// capturePollInterval is how often the recorder checks for a new animation frame
+const capturePollInterval = 20 * time.Millisecond
+
+// buildCaptureMenuItem creates the capture entry for the utility menu
+func (dw *DesktopWindow) buildCaptureMenuItem() ContextMenuItem {
+	text := "📷 Capture"
+	if dw.recording.Load() {
+		text = "⏺ Recording..."
+	}
+
+	return ContextMenuItem{
+		Text: text,
+		Callback: func() {
+			if dw.recording.Load() {
+				return
+			}
+			dw.showCaptureMenu()
+		},
+	}
+}
+
+// showCaptureMenu offers a PNG snapshot or a short GIF/WebM recording
+func (dw *DesktopWindow) showCaptureMenu() {
+	titleLabel := widget.NewLabel("Capture")
+	titleLabel.Alignment = fyne.TextAlignCenter
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rows := []fyne.CanvasObject{
+		titleLabel,
+		widget.NewButton("🖼 Snapshot (PNG)", func() {
+			dw.captureSnapshot()
+		}),
+	}
+
+	_, ffmpegErr := exec.LookPath("ffmpeg")
+	for _, seconds := range captureDurations {
+		duration := time.Duration(seconds) * time.Second
+		rows = append(rows, widget.NewButton(fmt.Sprintf("🎞 Record %ds GIF", seconds), func() {
+			dw.startRecording(duration, false)
+		}))
+		if ffmpegErr == nil {
+			rows = append(rows, widget.NewButton(fmt.Sprintf("🎬 Record %ds WebM", seconds), func() {
+				dw.startRecording(duration, true)
+			}))
+		}
+	}
+
+	content := container.NewVBox(rows...)
+	content.Resize(fyne.NewSize(200, float32(40*len(rows))))
+	dw.showModalContent(content)
+}
+
+// captureSnapshot saves the current animation frame as a PNG
+func (dw *DesktopWindow) captureSnapshot() {
+	frame := dw.character.GetCurrentFrame()
+	if frame == nil {
+		dw.showDialog("Nothing to capture right now")
+		return
+	}
+
+	path, err := capturePath(dw.character.GetName(), "png")
+	if err == nil {
+		err = savePNG(frame, path)
+	}
+	if err != nil {
+		dw.showDialog(fmt.Sprintf("Snapshot failed: %v", err))
+		return
+	}
+	dw.showDialog(fmt.Sprintf("📷 Saved %s", path))
+}
+
+// startRecording records the animation for duration in the background and
+// saves it as a GIF, converted to WebM with ffmpeg when webm is set
+func (dw *DesktopWindow) startRecording(duration time.Duration, webm bool) {
+	if !dw.recording.CompareAndSwap(false, true) {
+		return
+	}
+	dw.showDialog(fmt.Sprintf("⏺ Recording for %ds...", int(duration.Seconds())))
+
+	go func() {
+		defer dw.recording.Store(false)
+
+		anim := newFrameRecorder(dw.character.GetCurrentFrame).record(duration, capturePollInterval)
+		path, err := dw.saveRecording(anim, webm)
+		if err != nil {
+			logrus.WithError(err).Warn("Capture recording failed")
+			dw.showDialog(fmt.Sprintf("Recording failed: %v", err))
+			return
+		}
+		dw.showDialog(fmt.Sprintf("🎞 Saved %s", path))
+	}()
+}
+
+// saveRecording writes the recorded GIF and optionally converts it to WebM
+func (dw *DesktopWindow) saveRecording(anim *gif.GIF, webm bool) (string, error) {
+	if len(anim.Image) == 0 {
+		return "", fmt.Errorf("no frames captured")
+	}
+
+	gifPath, err := capturePath(dw.character.GetName(), "gif")
+	if err != nil {
+		return "", err
+	}
+	if err := saveGIF(anim, gifPath); err != nil {
+		return "", err
+	}
+	if !webm {
+		return gifPath, nil
+	}
+
+	webmPath := strings.TrimSuffix(gifPath, ".gif") + ".webm"
+	if err := convertToWebM(gifPath, webmPath); err != nil {
+		// The GIF is still useful, so keep it and report the conversion problem
+		return gifPath, fmt.Errorf("saved %s but WebM conversion failed: %w", gifPath, err)
+	}
+	os.Remove(gifPath)
+	return webmPath, nil
+}
+
+// frameRecorder collects animation frames from a frame source over time
+type frameRecorder struct {
+	source func() image.Image
+}
+
+// newFrameRecorder creates a recorder reading frames from source
+func newFrameRecorder(source func() image.Image) *frameRecorder {
+	return &frameRecorder{source: source}
+}
+
+// record polls the frame source for duration and returns the distinct
+// frames as a looping GIF, with delays matching how long each was shown
+func (r *frameRecorder) record(duration, interval time.Duration) *gif.GIF {
+	anim := &gif.GIF{}
+	var last image.Image
+	var lastAt time.Time
+
+	addFrame := func(frame image.Image, at time.Time) {
+		if last != nil {
+			anim.Delay = append(anim.Delay, gifDelay(at.Sub(lastAt)))
+		}
+		anim.Image = append(anim.Image, toPaletted(frame))
+		last, lastAt = frame, at
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if frame := r.source(); frame != nil && frame != last {
+			addFrame(frame, now)
+		}
+		if now.Sub(start) >= duration {
+			break
+		}
+		<-ticker.C
+	}
+
+	if last != nil {
+		anim.Delay = append(anim.Delay, gifDelay(time.Since(lastAt)))
+	}
+	setGIFBounds(anim)
+	return anim
+}
+
+// gifDelay converts a duration to GIF delay units (1/100s), at least 2
+func gifDelay(d time.Duration) int {
+	delay := int(d / (10 * time.Millisecond))
+	if delay < 2 {
+		// Most viewers treat delays below 2 as 10, slowing the animation down
+		delay = 2
+	}
+	return delay
+}
+
+// toPaletted returns frames from GIF animations as-is and quantizes anything else
+func toPaletted(img image.Image) *image.Paletted {
+	if paletted, ok := img.(*image.Paletted); ok {
+		return paletted
+	}
+	paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, img.Bounds().Min)
+	return paletted
+}
+
+// setGIFBounds sizes the GIF canvas to fit every frame
+func setGIFBounds(anim *gif.GIF) {
+	var bounds image.Rectangle
+	for _, frame := range anim.Image {
+		bounds = bounds.Union(frame.Bounds())
+	}
+	anim.Config.Width = bounds.Max.X
+	anim.Config.Height = bounds.Max.Y
+}
+
+// capturePath returns a timestamped file path in the captures folder,
+// creating ~/Pictures/desktop-companion if needed
+func capturePath(characterName, ext string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	dir := filepath.Join(homeDir, "Pictures", "desktop-companion")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create capture folder: %w", err)
+	}
+
+	name := strings.ReplaceAll(strings.ToLower(characterName), " ", "_")
+	if name == "" {
+		name = "character"
+	}
+	timestamp := time.Now().Format("20060102_150405")
+	return filepath.Join(dir, fmt.Sprintf("%s_%s.%s", name, timestamp, ext)), nil
+}
+
+// savePNG writes img to path as a PNG
+func savePNG(img image.Image, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to encode png: %w", err)
+	}
+	return f.Close()
+}
+
+// saveGIF writes anim to path
+func saveGIF(anim *gif.GIF, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to encode gif: %w", err)
+	}
+	return f.Close()
+}
+
+// convertToWebM converts a GIF to WebM with ffmpeg, keeping transparency
+func convertToWebM(gifPath, webmPath string) error {
+	cmd := exec.Command("ffmpeg", "-y", "-loglevel", "error", "-i", gifPath,
+		"-c:v", "libvpx-vp9", "-pix_fmt", "yuva420p", "-b:v", "0", "-crf", "30", webmPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFrameRecorderCollectsDistinctFrames(t *testing.T) {
+	frames := []*image.Paletted{
+		image.NewPaletted(image.Rect(0, 0, 8, 8), palette.Plan9),
+		image.NewPaletted(image.Rect(0, 0, 8, 8), palette.Plan9),
+	}
+
+	var mu sync.Mutex
+	calls := 0
+	source := func() image.Image {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		// Switch frames every 5 polls, like an animation slower than the poll rate
+		return frames[(calls/5)%2]
+	}
+
+	anim := newFrameRecorder(source).record(200*time.Millisecond, 5*time.Millisecond)
+	if len(anim.Image) < 2 {
+		t.Fatalf("Expected several frames, got %d", len(anim.Image))
+	}
+	if len(anim.Delay) != len(anim.Image) {
+		t.Errorf("Expected a delay per frame, got %d delays for %d frames", len(anim.Delay), len(anim.Image))
+	}
+	for i := 1; i < len(anim.Image); i++ {
+		if anim.Image[i] == anim.Image[i-1] {
+			t.Errorf("Frame %d repeats the previous frame", i)
+		}
+	}
+	if anim.Config.Width != 8 || anim.Config.Height != 8 {
+		t.Errorf("Expected 8x8 canvas, got %dx%d", anim.Config.Width, anim.Config.Height)
+	}
+
+	path := filepath.Join(t.TempDir(), "capture.gif")
+	if err := saveGIF(anim, path); err != nil {
+		t.Fatalf("saveGIF failed: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if decoded, err := gif.DecodeAll(f); err != nil || len(decoded.Image) != len(anim.Image) {
+		t.Errorf("Saved GIF should decode with all frames, got %v", err)
+	}
+}
+
+func TestCaptureHelpers(t *testing.T) {
+	if got := gifDelay(5 * time.Millisecond); got != 2 {
+		t.Errorf("Expected minimum delay 2, got %d", got)
+	}
+	if got := gifDelay(100 * time.Millisecond); got != 10 {
+		t.Errorf("Expected delay 10, got %d", got)
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	rgba.Set(1, 1, color.RGBA{R: 255, A: 255})
+	if paletted := toPaletted(rgba); paletted.Bounds() != rgba.Bounds() {
+		t.Errorf("Quantized frame should keep bounds, got %v", paletted.Bounds())
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.png")
+	if err := savePNG(rgba, path); err != nil {
+		t.Fatalf("savePNG failed: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("Expected PNG to be written, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// CharacterPicker asks which of several characters to start, for binaries
+// shipping more than one built-in character
+type CharacterPicker struct {
+	window     fyne.Window
+	characters []OnboardingCharacter
+	onPick     func(OnboardingCharacter)
+	selected   int
+	current    string // Card path of the running character, when switching
+
+	list         *widget.List
+	preview      *canvas.Image
+	previewLabel *widget.Label
+	startButton  *widget.Button
+}
+
+// NewCharacterPicker creates the picker window. onPick is called with the
+// chosen character before the window closes; closing the window without
+// choosing calls nothing.
+func NewCharacterPicker(app fyne.App, characters []OnboardingCharacter, onPick func(OnboardingCharacter)) *CharacterPicker {
+	p := &CharacterPicker{
+		window:     app.NewWindow("Choose a companion"),
+		characters: characters,
+		onPick:     onPick,
+		selected:   -1,
+	}
+
+	p.preview = &canvas.Image{FillMode: canvas.ImageFillContain}
+	p.preview.SetMinSize(fyne.NewSize(128, 128))
+	p.previewLabel = widget.NewLabel("")
+	p.previewLabel.Wrapping = fyne.TextWrapWord
+
+	p.list = widget.NewList(
+		func() int { return len(p.characters) },
+		func() fyne.CanvasObject { return widget.NewLabel("Character") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			name := p.characters[id].Name
+			if p.current != "" && p.characters[id].CardPath == p.current {
+				name = "✓ " + name
+			}
+			obj.(*widget.Label).SetText(name)
+		},
+	)
+	p.list.OnSelected = p.selectCharacter
+
+	p.startButton = widget.NewButton("Start", p.start)
+	p.startButton.Importance = widget.HighImportance
+	p.startButton.Disable()
+
+	previewPane := container.NewBorder(p.preview, nil, nil, nil, p.previewLabel)
+	buttons := container.NewHBox(layout.NewSpacer(), p.startButton)
+	p.window.SetContent(container.NewBorder(nil, buttons, nil, nil, container.NewHSplit(p.list, previewPane)))
+	p.window.Resize(fyne.NewSize(480, 360))
+
+	if len(characters) > 0 {
+		p.list.Select(0)
+	}
+	return p
+}
+
+// SetCurrent turns the picker into a switcher away from the running
+// character at cardPath: it is ticked, selected first and can't be picked
+func (p *CharacterPicker) SetCurrent(cardPath string) {
+	p.current = cardPath
+	p.window.SetTitle("Switch Character")
+	p.startButton.SetText("Switch")
+	p.list.Refresh()
+	for i, char := range p.characters {
+		if char.CardPath == cardPath {
+			p.list.Select(i)
+		}
+	}
+}
+
+// Show displays the picker window
+func (p *CharacterPicker) Show() {
+	p.window.Show()
+}
+
+// selectCharacter shows the preview of the highlighted character
+func (p *CharacterPicker) selectCharacter(id widget.ListItemID) {
+	if id < 0 || id >= len(p.characters) {
+		return
+	}
+	p.selected = id
+	char := p.characters[id]
+
+	p.preview.File = char.PreviewPath
+	p.preview.Refresh()
+	p.previewLabel.SetText(char.Description)
+	enableIf(p.startButton, p.current == "" || char.CardPath != p.current)
+}
+
+// start reports the selection. Like the onboarding wizard, the window is
+// hidden before onPick so the companion window opens without the app quitting.
+func (p *CharacterPicker) start() {
+	if p.selected < 0 {
+		return
+	}
+	char := p.characters[p.selected]
+	p.selected = -1 // Report once
+
+	p.window.Hide()
+	if p.onPick != nil {
+		p.onPick(char)
+	}
+	p.window.Close()
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestCharacterPickerReportsSelection(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	characters := []OnboardingCharacter{
+		{Name: "Default", CardPath: "/cache/a/character.json"},
+		{Name: "Tsundere", CardPath: "/cache/b/character.json", Description: "Hmph."},
+	}
+
+	var picked []OnboardingCharacter
+	picker := NewCharacterPicker(app, characters, func(c OnboardingCharacter) {
+		picked = append(picked, c)
+	})
+	picker.Show()
+
+	if picker.startButton.Disabled() {
+		t.Error("Expected the first character to be preselected")
+	}
+	picker.list.Select(1)
+	if picker.previewLabel.Text != "Hmph." {
+		t.Errorf("Expected the selected character's description, got %q", picker.previewLabel.Text)
+	}
+
+	picker.start()
+	picker.start()
+	if len(picked) != 1 || picked[0].CardPath != "/cache/b/character.json" {
+		t.Errorf("Expected Tsundere to be picked once, got %+v", picked)
+	}
+}
+
+func TestCharacterPickerEmpty(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	picker := NewCharacterPicker(app, nil, func(OnboardingCharacter) {
+		t.Error("Nothing should be picked from an empty list")
+	})
+	if !picker.startButton.Disabled() {
+		t.Error("Expected Start to be disabled with nothing to pick")
+	}
+	picker.start()
+}
+
+func TestCharacterPickerSwitchingAwayFromCurrent(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	characters := []OnboardingCharacter{
+		{Name: "Default", CardPath: "/cache/a/character.json"},
+		{Name: "Tsundere", CardPath: "/cache/b/character.json"},
+	}
+	picker := NewCharacterPicker(app, characters, func(OnboardingCharacter) {})
+	picker.SetCurrent("/cache/b/character.json")
+
+	if picker.selected != 1 || !picker.startButton.Disabled() {
+		t.Errorf("Expected the running character selected and not pickable, got %d", picker.selected)
+	}
+	if picker.startButton.Text != "Switch" {
+		t.Errorf("Expected a Switch button, got %q", picker.startButton.Text)
+	}
+	picker.list.Select(0)
+	if picker.startButton.Disabled() {
+		t.Error("Expected another character to be pickable")
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+// character_switcher.go lets the user swap the companion for another
+// installed character from the right-click menu, using the character picker
+// with its idle-frame previews. The app saves the current character and
+// starts the chosen one in place, without a restart.
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// SetCharacterSwitcher enables "Switch Character". current is the running
+// card's path, list returns the installed characters each time the picker
+// opens, and switchTo is called with the chosen card's path; it is expected
+// to save this character and replace the window with the new one.
+func (dw *DesktopWindow) SetCharacterSwitcher(current string, list func() []OnboardingCharacter, switchTo func(cardPath string)) {
+	dw.cardPath = current
+	dw.listCharacters = list
+	dw.switchCharacter = switchTo
+}
+
+// buildCharacterSwitcherMenuItem creates the "Switch Character" entry once
+// a switcher is set
+func (dw *DesktopWindow) buildCharacterSwitcherMenuItem() (ContextMenuItem, bool) {
+	if dw.switchCharacter == nil {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{
+		Text:     "🔄 Switch Character",
+		Callback: dw.showCharacterSwitcher,
+	}, true
+}
+
+// showCharacterSwitcher opens the picker on the installed characters with
+// the running one ticked
+func (dw *DesktopWindow) showCharacterSwitcher() {
+	characters := dw.listCharacters()
+	if len(characters) < 2 {
+		dw.showDialog("No other characters found in assets/characters.")
+		return
+	}
+
+	picker := NewCharacterPicker(fyne.CurrentApp(), characters, func(char OnboardingCharacter) {
+		dw.switchCharacter(char.CardPath)
+	})
+	picker.SetCurrent(dw.cardPath)
+	picker.Show()
+}
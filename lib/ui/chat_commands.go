@@ -0,0 +1,189 @@
+package ui
+
+// chat_commands.go lets the chat input drive the character from the
+// keyboard. Messages starting with "/" are commands rather than chat: they
+// feed, play with or give gifts to the character, show its stats or start a
+// general event, and the result is shown inline in the conversation instead
+// of being sent to the dialog backend.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// chatCommand is one slash command understood by the chat input
+type chatCommand struct {
+	name  string // Typed after the slash
+	usage string // Shown by /help
+	run   func(c *ChatbotInterface, arg string) string
+}
+
+// chatCommands are the slash commands in the order /help lists them,
+// followed by /help itself
+var chatCommands = []chatCommand{
+	{"feed", "/feed", func(c *ChatbotInterface, _ string) string { return c.runInteraction("feed") }},
+	{"play", "/play", func(c *ChatbotInterface, _ string) string { return c.runInteraction("play") }},
+	{"gift", "/gift [NAME]", (*ChatbotInterface).runGift},
+	{"stats", "/stats", func(c *ChatbotInterface, _ string) string { return c.runStats() }},
+	{"event", "/event NAME", (*ChatbotInterface).runEvent},
+}
+
+// parseChatCommand splits "/name argument" into the lowercased command name
+// and its argument. ok is false when text isn't a command.
+func parseChatCommand(text string) (name, arg string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+	name, arg, _ = strings.Cut(text[1:], " ")
+	return strings.ToLower(name), strings.TrimSpace(arg), true
+}
+
+// runCommand carries out a slash command and shows it and its result in the
+// conversation. It returns false when text isn't a command.
+func (c *ChatbotInterface) runCommand(text string) bool {
+	name, arg, ok := parseChatCommand(text)
+	if !ok {
+		return false
+	}
+
+	result := fmt.Sprintf("Unknown command /%s. Type /help for the list.", name)
+	if name == "help" {
+		result = chatCommandHelp()
+	}
+	for _, command := range chatCommands {
+		if command.name == name {
+			result = command.run(c, arg)
+			break
+		}
+	}
+
+	c.addMessage(ChatMessage{IsUser: true, Text: text, Timestamp: time.Now()})
+	c.addMessage(ChatMessage{Text: result, Timestamp: time.Now(), IsCommand: true})
+	c.updateConversationDisplay()
+	c.scrollToBottom()
+	return true
+}
+
+// SetGiftManager lets /gift give gifts, nil when the gift system is off
+func (c *ChatbotInterface) SetGiftManager(giftManager *character.GiftManager) {
+	c.giftManager = giftManager
+}
+
+// runInteraction performs a game interaction such as feeding and explains
+// why when it can't be done
+func (c *ChatbotInterface) runInteraction(interaction string) string {
+	if c.character.GetGameState() == nil {
+		return fmt.Sprintf("/%s needs game mode (start with -game).", interaction)
+	}
+	if _, exists := c.character.GetCard().Interactions[interaction]; !exists {
+		return fmt.Sprintf("%s has no %s interaction.", c.character.GetName(), interaction)
+	}
+	if response := c.character.HandleGameInteraction(interaction); response != "" {
+		return response
+	}
+	return fmt.Sprintf("Can't %s %s right now; it's on cooldown or its requirements aren't met.",
+		interaction, c.character.GetName())
+}
+
+// runGift gives the gift named by its ID or name, or lists the gifts that
+// can be given when no name is typed
+func (c *ChatbotInterface) runGift(name string) string {
+	if c.giftManager == nil {
+		return "The gift system isn't enabled for this character."
+	}
+
+	gifts := c.giftManager.GetAvailableGifts()
+	sort.Slice(gifts, func(i, j int) bool { return gifts[i].Name < gifts[j].Name })
+	if name == "" {
+		if len(gifts) == 0 {
+			return "No gifts are available right now."
+		}
+		names := make([]string, 0, len(gifts))
+		for _, gift := range gifts {
+			names = append(names, gift.Name)
+		}
+		return "Gifts: " + strings.Join(names, ", ") + ". Type /gift NAME to give one."
+	}
+
+	for _, gift := range gifts {
+		if !strings.EqualFold(gift.ID, name) && !strings.EqualFold(gift.Name, name) {
+			continue
+		}
+		if c.giftManager.IsGiftOnCooldown(gift.ID) {
+			return fmt.Sprintf("%s can be given again in %s.", gift.Name,
+				c.giftManager.GetGiftCooldownRemaining(gift.ID).Round(time.Second))
+		}
+		response, err := c.giftManager.GiveGift(gift.ID, "")
+		if err != nil {
+			return response.ErrorMessage
+		}
+		return response.Response
+	}
+	return fmt.Sprintf("No gift called %q can be given right now. Type /gift for the list.", name)
+}
+
+// runStats lists the character's stats, marking critical ones with "!"
+func (c *ChatbotInterface) runStats() string {
+	gameState := c.character.GetGameState()
+	if gameState == nil {
+		return "/stats needs game mode (start with -game)."
+	}
+	stats := gameState.GetStats()
+	if len(stats) == 0 {
+		return c.character.GetName() + " has no stats."
+	}
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	critical := gameState.GetCriticalStates()
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		part := fmt.Sprintf("%s %.0f", capitalizeFirst(name), stats[name])
+		if contains(critical, name) {
+			part += "!"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// runEvent starts a general event by name, or lists the events that can be
+// started when no name is typed
+func (c *ChatbotInterface) runEvent(name string) string {
+	if name == "" {
+		events := c.character.GetAvailableGeneralEvents()
+		if len(events) == 0 {
+			return "No events are available right now."
+		}
+		names := make([]string, 0, len(events))
+		for _, event := range events {
+			names = append(names, event.Name)
+		}
+		sort.Strings(names)
+		return "Events: " + strings.Join(names, ", ") + ". Type /event NAME to start one."
+	}
+
+	if response := c.character.HandleGeneralEvent(name); response != "" {
+		return response
+	}
+	return fmt.Sprintf("Event %q can't be started right now. Type /event for the list.", name)
+}
+
+// chatCommandHelp lists the slash commands
+func chatCommandHelp() string {
+	usages := make([]string, 0, len(chatCommands)+1)
+	for _, command := range chatCommands {
+		usages = append(usages, command.usage)
+	}
+	usages = append(usages, "/help")
+	return "Commands: " + strings.Join(usages, ", ")
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestParseChatCommand(t *testing.T) {
+	tests := []struct {
+		text, name, arg string
+		ok              bool
+	}{
+		{"/feed", "feed", "", true},
+		{"  /Event  Tea Party ", "event", "Tea Party", true},
+		{"/", "", "", true},
+		{"hello /feed", "", "", false},
+	}
+	for _, tt := range tests {
+		name, arg, ok := parseChatCommand(tt.text)
+		if name != tt.name || arg != tt.arg || ok != tt.ok {
+			t.Errorf("parseChatCommand(%q) = %q, %q, %v, want %q, %q, %v",
+				tt.text, name, arg, ok, tt.name, tt.arg, tt.ok)
+		}
+	}
+}
+
+func TestChatbotInterface_SlashCommands(t *testing.T) {
+	card := createTestCharacterCardWithDialogBackend()
+	card.Stats = map[string]character.StatConfig{
+		"hunger": {Initial: 50, Max: 100, DegradationRate: 1, CriticalThreshold: 20},
+	}
+	card.GameRules = &character.GameRulesConfig{StatsDecayInterval: 60}
+	card.Interactions = map[string]character.InteractionConfig{
+		"feed": {Effects: map[string]float64{"hunger": 10}, Responses: []string{"Yum!"}, Cooldown: 60},
+	}
+	card.GeneralEvents = []character.GeneralDialogEvent{{
+		RandomEventConfig: character.RandomEventConfig{Name: "tea_party", Responses: []string{"Tea time!"}},
+		Category:          "roleplay",
+	}}
+	char := createMockCharacter(card)
+	if char == nil {
+		t.Skip("Test character could not be created")
+	}
+	if err := char.EnableGameMode(nil, ""); err != nil {
+		t.Fatalf("EnableGameMode failed: %v", err)
+	}
+	chatbot := NewChatbotInterface(char)
+
+	giftManager := character.NewGiftManager(card, char.GetGameState())
+	giftManager.AddGiftToTestCatalog(&character.GiftDefinition{
+		ID:   "cookie",
+		Name: "Cookie",
+		GiftEffects: character.GiftEffects{
+			Immediate: character.ImmediateEffects{Responses: []string{"A cookie!"}},
+		},
+	})
+	chatbot.SetGiftManager(giftManager)
+
+	tests := []struct {
+		input, want string
+	}{
+		{"/feed", "Yum!"},
+		{"/feed", "on cooldown"},
+		{"/play", "has no play interaction"},
+		{"/stats", "Hunger 60"},
+		{"/gift", "Gifts: Cookie"},
+		{"/gift cookie", "A cookie!"},
+		{"/event tea_party", "Tea time!"},
+		{"/event", "Events: tea_party"},
+		{"/help", "/event NAME"},
+		{"/dance", "Unknown command /dance"},
+	}
+	for _, tt := range tests {
+		chatbot.messageInput.SetText(tt.input)
+		chatbot.sendMessage()
+
+		if chatbot.messageInput.Text != "" {
+			t.Errorf("%s: expected the input cleared", tt.input)
+		}
+		if chatbot.responding.Load() {
+			t.Fatalf("%s: expected the command not to be sent to the dialog backend", tt.input)
+		}
+		last := chatbot.conversationLog[len(chatbot.conversationLog)-1]
+		if !last.IsCommand || !strings.Contains(last.Text, tt.want) {
+			t.Errorf("%s: got %+v, want a command result containing %q", tt.input, last, tt.want)
+		}
+	}
+}
@@ -79,8 +79,8 @@ func (c *ChatMessageWidget) setupComponents() {
 
 // setupStarRating creates the star rating interface for character messages
 func (c *ChatMessageWidget) setupStarRating() {
-	if c.message.IsUser {
-		return // No rating for user messages
+	if c.message.IsUser || c.message.IsCommand {
+		return // No rating for user messages or command results
 	}
 
 	// Create star rating buttons (1-5 stars) using available icons
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// chatAvatarSize is the width and height of avatars sent with chat messages
+const chatAvatarSize = 48
+
+// createChatRelayWidgets builds the Peer Chat tab: pick a peer, see their
+// character's avatar and the conversation, and type to them
+func (no *NetworkOverlay) createChatRelayWidgets() fyne.CanvasObject {
+	no.relayPeerSelect = widget.NewSelect(nil, func(string) {
+		index := no.relayPeerSelect.SelectedIndex()
+		no.relayMu.Lock()
+		if index >= 0 && index < len(no.relayPeerIDs) {
+			no.relayPeer = no.relayPeerIDs[index]
+		}
+		no.relayMu.Unlock()
+		no.showChatRelayConversation()
+	})
+	no.relayPeerSelect.PlaceHolder = "Choose a peer..."
+
+	no.relayAvatar = canvas.NewImageFromImage(nil)
+	no.relayAvatar.FillMode = canvas.ImageFillContain
+	no.relayAvatar.SetMinSize(fyne.NewSize(32, 32))
+	no.relayName = widget.NewLabel("")
+
+	no.relayLog = widget.NewRichText()
+	no.relayLog.Wrapping = fyne.TextWrapWord
+	no.relayScroll = container.NewScroll(no.relayLog)
+	no.relayScroll.SetMinSize(fyne.NewSize(200, 80))
+
+	no.relayInput = widget.NewEntry()
+	no.relayInput.SetPlaceHolder("Message this peer...")
+	no.relayInput.OnSubmitted = no.sendChatRelayMessage
+	no.relaySendButton = widget.NewButton("Send", func() {
+		no.sendChatRelayMessage(no.relayInput.Text)
+	})
+
+	header := container.NewBorder(nil, nil, no.relayAvatar, nil, container.NewVBox(no.relayPeerSelect, no.relayName))
+	controls := container.NewBorder(nil, nil, nil, no.relaySendButton, no.relayInput)
+	return container.NewBorder(header, controls, nil, nil, no.relayScroll)
+}
+
+// setupChatRelay starts relaying user chat once network events are registered
+func (no *NetworkOverlay) setupChatRelay() {
+	no.chatRelay = network.NewChatRelay(no.networkManager, no.localCharName)
+	no.chatRelay.SetLineHandler(no.handleChatRelayLine)
+}
+
+// SetChatAvatar sets the image peers see next to our messages, usually a
+// frame of the local character
+func (no *NetworkOverlay) SetChatAvatar(img image.Image) {
+	if no.chatRelay == nil || img == nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaleChatAvatar(img)); err != nil {
+		return
+	}
+	_ = no.chatRelay.SetAvatar(buf.Bytes()) // A 48px PNG is always under the limit
+}
+
+// scaleChatAvatar shrinks img to fit chatAvatarSize, nearest neighbour
+// like the pixel-art sprites it comes from
+func scaleChatAvatar(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= chatAvatarSize && height <= chatAvatarSize {
+		return img
+	}
+
+	scale := float64(chatAvatarSize) / float64(max(width, height))
+	out := image.NewRGBA(image.Rect(0, 0, max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))))
+	for y := 0; y < out.Bounds().Dy(); y++ {
+		for x := 0; x < out.Bounds().Dx(); x++ {
+			out.Set(x, y, img.At(bounds.Min.X+int(float64(x)/scale), bounds.Min.Y+int(float64(y)/scale)))
+		}
+	}
+	return out
+}
+
+// sendChatRelayMessage sends the typed text to the selected peer
+func (no *NetworkOverlay) sendChatRelayMessage(text string) {
+	if no.chatRelay == nil || strings.TrimSpace(text) == "" {
+		return
+	}
+
+	no.relayMu.Lock()
+	peerID := no.relayPeer
+	no.relayMu.Unlock()
+	no.peerMutex.RLock()
+	permissions := no.permissions
+	no.peerMutex.RUnlock()
+
+	if peerID == "" {
+		no.addChatRelayNotice("Choose a peer to chat with first.")
+		return
+	}
+	if permissions != nil && !permissions.Allows(peerID, network.PermissionChats) {
+		no.addChatRelayNotice("Chats from this peer are turned off. Allow them in the peer's permissions to talk.")
+		return
+	}
+
+	err := no.chatRelay.Send(peerID, text)
+	switch {
+	case errors.Is(err, network.ErrChatRateLimited):
+		no.addChatRelayNotice("Slow down a little, then try again.")
+		return // Keep the text so it can be sent again
+	case err != nil:
+		no.addChatRelayNotice(fmt.Sprintf("Failed to send message: %v", err))
+		return
+	}
+	no.relayInput.SetText("")
+}
+
+// handleChatRelayLine shows a new line if its conversation is open. A
+// message from a peer opens their conversation when none is.
+func (no *NetworkOverlay) handleChatRelayLine(line network.ChatRelayLine) {
+	no.relayMu.Lock()
+	if no.relayPeer == "" && line.Remote {
+		no.relayPeer = line.PeerID
+	}
+	open := no.relayPeer == line.PeerID
+	no.relayMu.Unlock()
+
+	if line.Remote {
+		no.TrackChatMessage(line.PeerID, line.Character, line.Text)
+		no.refreshChatRelayPeers()
+	}
+	if open {
+		no.showChatRelayConversation()
+	}
+}
+
+// showChatRelayConversation renders the selected peer's avatar and messages
+func (no *NetworkOverlay) showChatRelayConversation() {
+	no.relayMu.Lock()
+	peerID := no.relayPeer
+	no.relayMu.Unlock()
+	if no.chatRelay == nil || peerID == "" {
+		return
+	}
+
+	no.relayAvatar.Image = nil
+	if data := no.chatRelay.PeerAvatar(peerID); data != nil {
+		if img, err := png.Decode(bytes.NewReader(data)); err == nil {
+			no.relayAvatar.Image = img
+		}
+	}
+	no.relayAvatar.Refresh()
+	no.relayName.SetText(no.chatRelay.PeerCharacter(peerID))
+
+	var b strings.Builder
+	for _, line := range no.chatRelay.History(peerID) {
+		sender := "You"
+		if line.Remote {
+			sender = line.Character
+			if sender == "" {
+				sender = shortPeerID(peerID)
+			}
+		}
+		fmt.Fprintf(&b, "[%s] %s: %s\n\n", line.At.Format("15:04"), sender, line.Text)
+	}
+	no.relayLog.Segments = []widget.RichTextSegment{&widget.TextSegment{Text: b.String()}}
+	no.relayLog.Refresh()
+	no.relayScroll.ScrollToBottom()
+}
+
+// addChatRelayNotice shows a system note under the open conversation
+func (no *NetworkOverlay) addChatRelayNotice(text string) {
+	no.relayLog.Segments = append(no.relayLog.Segments, &widget.TextSegment{
+		Text:  text,
+		Style: widget.RichTextStyle{TextStyle: fyne.TextStyle{Italic: true}},
+	})
+	no.relayLog.Refresh()
+	no.relayScroll.ScrollToBottom()
+}
+
+// refreshChatRelayPeers lists connected peers in the peer picker, by
+// character name once they've chatted, keeping the current selection
+func (no *NetworkOverlay) refreshChatRelayPeers() {
+	if no.relayPeerSelect == nil {
+		return
+	}
+
+	no.peerMutex.RLock()
+	ids := make([]string, 0, len(no.peers))
+	for _, peer := range no.peers {
+		ids = append(ids, peer.ID)
+	}
+	no.peerMutex.RUnlock()
+
+	no.relayMu.Lock()
+	if no.relayPeer != "" && !slices.Contains(ids, no.relayPeer) {
+		ids = append(ids, no.relayPeer) // Keep the open conversation listed while the peer reconnects
+	}
+	no.relayPeerIDs = ids
+	selected := -1
+	labels := make([]string, len(ids))
+	for i, id := range ids {
+		labels[i] = shortPeerID(id)
+		if no.chatRelay != nil {
+			if name := no.chatRelay.PeerCharacter(id); name != "" {
+				labels[i] = fmt.Sprintf("%s (%s)", name, shortPeerID(id))
+			}
+		}
+		if id == no.relayPeer {
+			selected = i
+		}
+	}
+	no.relayMu.Unlock()
+
+	// Setting options and selection directly avoids re-running OnChanged
+	no.relayPeerSelect.Options = labels
+	if selected >= 0 {
+		no.relayPeerSelect.Selected = labels[selected]
+	}
+	no.relayPeerSelect.Refresh()
+}
+
+// shortPeerID abbreviates a hex peer ID for display
+func shortPeerID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+func TestChatRelayTabShowsPeerMessages(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	nm := &mockNetworkManager{peers: []network.Peer{{ID: "abcdef0123456789"}}}
+	overlay := NewNetworkOverlay(nm)
+	overlay.RegisterNetworkEvents()
+	overlay.updatePeerList()
+
+	var avatar bytes.Buffer
+	png.Encode(&avatar, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	payload, _ := json.Marshal(network.ChatRelayPayload{Text: "hello!", Character: "Luna", Avatar: avatar.Bytes()})
+	handler := nm.handlers[network.MessageTypeChatRelay]
+	if handler == nil {
+		t.Fatal("Expected the chat relay to register its handler")
+	}
+	if err := handler(network.Message{Type: network.MessageTypeChatRelay, Payload: payload}, &network.Peer{ID: "abcdef0123456789"}); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+
+	// The first message opens that peer's conversation with their avatar
+	if overlay.relayName.Text != "Luna" || overlay.relayAvatar.Image == nil {
+		t.Errorf("Expected Luna's name and avatar, got %q %v", overlay.relayName.Text, overlay.relayAvatar.Image)
+	}
+	if !strings.Contains(overlay.relayLog.String(), "Luna: hello!") {
+		t.Errorf("Expected the message in the log, got %q", overlay.relayLog.String())
+	}
+	if overlay.relayPeerSelect.Selected != "Luna (abcdef01)" {
+		t.Errorf("Expected the peer picker to show Luna, got %q", overlay.relayPeerSelect.Selected)
+	}
+
+	overlay.relayInput.SetText("hi Luna")
+	test.Tap(overlay.relaySendButton)
+	if overlay.relayInput.Text != "" || !strings.Contains(overlay.relayLog.String(), "You: hi Luna") {
+		t.Errorf("Expected our reply in the log, got %q", overlay.relayLog.String())
+	}
+}
+
+func TestChatRelayTabRespectsPermissions(t *testing.T) {
+	test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	nm := &mockNetworkManager{peers: []network.Peer{{ID: "peer-1"}}}
+	overlay := NewNetworkOverlay(nm)
+	overlay.RegisterNetworkEvents()
+	overlay.updatePeerList()
+
+	store, _ := network.NewPermissionStore("")
+	store.Set("peer-1", network.PeerPermissions{AllowBattles: true, AllowGifts: true})
+	overlay.SetPermissionStore(store)
+
+	overlay.relayPeerSelect.SetSelectedIndex(0)
+	overlay.relayInput.SetText("hello?")
+	test.Tap(overlay.relaySendButton)
+
+	if len(overlay.chatRelay.History("peer-1")) != 0 {
+		t.Error("Expected nothing sent to a peer whose chats are turned off")
+	}
+	if !strings.Contains(overlay.relayLog.String(), "turned off") {
+		t.Errorf("Expected a notice about permissions, got %q", overlay.relayLog.String())
+	}
+}
+
+func TestScaleChatAvatar(t *testing.T) {
+	scaled := scaleChatAvatar(image.NewRGBA(image.Rect(0, 0, 128, 64)))
+	if scaled.Bounds().Dx() != chatAvatarSize || scaled.Bounds().Dy() != chatAvatarSize/2 {
+		t.Errorf("Expected %dx%d, got %v", chatAvatarSize, chatAvatarSize/2, scaled.Bounds())
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	typingInterval = 30 * time.Millisecond
+	typingMaxTime  = 2 * time.Second // Long replies speed up to finish within this
+)
+
+// typingMessage shows a character reply while it is being generated. Text
+// that arrives in bursts is revealed at a steady typing pace, and animated
+// dots show the character is thinking until the first words arrive.
+type typingMessage struct {
+	label  *widget.Label
+	prefix string
+
+	mu       sync.Mutex
+	target   []rune
+	shown    int
+	tick     int
+	done     bool
+	finished chan struct{}
+	onDone   func()
+}
+
+// newTypingMessage creates the placeholder label and starts animating it
+func newTypingMessage(characterName string) *typingMessage {
+	t := &typingMessage{
+		label:    widget.NewLabel(characterName + ": ."),
+		prefix:   characterName + ": ",
+		finished: make(chan struct{}),
+	}
+	t.label.Wrapping = fyne.TextWrapWord
+
+	go t.animate()
+	return t
+}
+
+// update sets the text received so far. Safe to call from any goroutine.
+func (t *typingMessage) update(text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runes := []rune(text)
+	if !strings.HasPrefix(text, string(t.target[:t.shown])) {
+		// Text was replaced rather than extended; retype from the start
+		t.shown = 0
+	}
+	t.target = runes
+}
+
+// finish sets the final text and calls onDone once it has been fully typed
+func (t *typingMessage) finish(text string, onDone func()) {
+	t.update(text)
+
+	t.mu.Lock()
+	t.done = true
+	t.onDone = onDone
+	t.mu.Unlock()
+}
+
+// animate reveals the target text a few runes per tick until finished
+func (t *typingMessage) animate() {
+	ticker := time.NewTicker(typingInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		text, complete, onDone := t.step()
+		t.label.SetText(text)
+		if complete {
+			close(t.finished)
+			if onDone != nil {
+				onDone()
+			}
+			return
+		}
+	}
+}
+
+// step advances the animation by one tick and returns the label text
+func (t *typingMessage) step() (text string, complete bool, onDone func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tick++
+	remaining := len(t.target) - t.shown
+
+	if remaining <= 0 {
+		if t.done {
+			return t.prefix + string(t.target), true, t.onDone
+		}
+		if len(t.target) == 0 {
+			// Thinking: cycle ".", "..", "..."
+			return t.prefix + strings.Repeat(".", t.tick/8%3+1), false, nil
+		}
+		return t.prefix + string(t.target) + "▌", false, nil
+	}
+
+	step := max(2, remaining*int(typingInterval)/int(typingMaxTime))
+	t.shown = min(len(t.target), t.shown+step)
+	return t.prefix + string(t.target[:t.shown]) + "▌", false, nil
+}
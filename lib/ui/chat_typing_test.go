@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypingMessageRevealsText(t *testing.T) {
+	typing := newTypingMessage("Aria")
+
+	typing.update("Hello")
+	typing.update("Hello there, friend")
+
+	done := make(chan struct{})
+	typing.finish("Hello there, friend", func() { close(done) })
+
+	select {
+	case <-done:
+	case <-time.After(typingMaxTime + time.Second):
+		t.Fatal("Typing animation did not finish")
+	}
+
+	if got := typing.label.Text; got != "Aria: Hello there, friend" {
+		t.Errorf("Expected full text without cursor, got %q", got)
+	}
+}
+
+func TestTypingMessageRetypesReplacedText(t *testing.T) {
+	typing := newTypingMessage("Aria")
+	typing.update("Something went")
+	time.Sleep(5 * typingInterval)
+
+	done := make(chan struct{})
+	typing.finish("Sorry, I lost my train of thought.", func() { close(done) })
+	<-done
+
+	if !strings.HasSuffix(typing.label.Text, "train of thought.") {
+		t.Errorf("Expected replacement text, got %q", typing.label.Text)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -50,6 +51,8 @@ type ChatbotInterface struct {
 	sendButton            *widget.Button
 	toggleButton          *widget.Button
 	historyScroll         *container.Scroll
+	replyButtons          []*widget.Button // Quick replies offered with the last response
+	topicsLabel           *widget.Label    // What the conversation is about, hidden before any topic
 
 	// State management
 	conversationLog  []ChatMessage
@@ -57,6 +60,10 @@ type ChatbotInterface struct {
 	maxHistoryLength int
 	lastMessageTime  time.Time
 	inputPlaceholder string
+	responding       atomic.Bool // A reply is being generated
+	exportDir        string      // Where conversations are exported, empty means the home directory
+
+	giftManager *character.GiftManager // Gives gifts for /gift, nil without the gift system
 }
 
 // ChatMessage represents a single message in the conversation
@@ -67,8 +74,15 @@ type ChatMessage struct {
 	Animation  string    `json:"animation"`  // Animation triggered with character response (if any)
 	IsFavorite bool      `json:"isFavorite"` // Whether this response is marked as favorite
 	Rating     float64   `json:"rating"`     // User rating for this response (1-5 stars)
+	IsCommand  bool      `json:"isCommand"`  // Result of a slash command rather than a reply, never rated
 }
 
+// maxQuickReplies caps the suggested replies shown under a response
+const maxQuickReplies = 4
+
+// maxShownTopics caps the active topics shown above the conversation
+const maxShownTopics = 3
+
 // NewChatbotInterface creates a new chatbot interface widget.
 // The interface is only available for characters with dialog backend enabled.
 //
@@ -114,6 +128,12 @@ func (c *ChatbotInterface) initializeComponents() {
 	// Add initial empty state
 	c.updateConversationDisplay()
 
+	// Create active topics line, shown once the conversation has a topic
+	c.topicsLabel = widget.NewLabel("")
+	c.topicsLabel.TextStyle = fyne.TextStyle{Italic: true}
+	c.topicsLabel.Truncation = fyne.TextTruncateEllipsis
+	c.topicsLabel.Hide()
+
 	// Create scrollable container for conversation history
 	c.historyScroll = container.NewScroll(c.conversationContainer)
 	c.historyScroll.SetMinSize(fyne.NewSize(300, 150))
@@ -143,7 +163,7 @@ func (c *ChatbotInterface) setupLayout() {
 	inputArea := container.NewBorder(nil, nil, nil, c.sendButton, c.messageInput)
 
 	// Create main chat content (history + input)
-	chatContent := container.NewVBox(c.historyScroll, inputArea)
+	chatContent := container.NewVBox(c.topicsLabel, c.historyScroll, inputArea)
 
 	// Create main container with background
 	c.content = container.NewBorder(nil, nil, nil, nil, c.background, chatContent)
@@ -175,8 +195,21 @@ func (c *ChatbotInterface) sendMessage() {
 		return
 	}
 
-	// Clear input field
+	// Slash commands are carried out here instead of being sent as chat
+	if c.runCommand(message) {
+		c.messageInput.SetText("")
+		return
+	}
+
+	// One reply at a time; the input keeps the text until the character is done
+	if !c.responding.CompareAndSwap(false, true) {
+		return
+	}
+	c.sendButton.Disable()
+
+	// Clear input field and any quick replies still on offer
 	c.messageInput.SetText("")
+	c.replyButtons = nil
 
 	// Add user message to conversation
 	userMessage := ChatMessage{
@@ -185,9 +218,33 @@ func (c *ChatbotInterface) sendMessage() {
 		Timestamp: time.Now(),
 	}
 	c.addMessage(userMessage)
+	c.updateConversationDisplay()
 
-	// Get character response
-	response := c.character.HandleChatMessage(message)
+	// Show the reply as it is typed. Generation runs off the UI thread so
+	// slow backends don't freeze the window.
+	typing := newTypingMessage(c.character.GetName())
+	c.conversationContainer.Add(typing.label)
+	c.conversationContainer.Refresh()
+	c.scrollToBottom()
+
+	go func() {
+		response := c.character.HandleChatMessageStream(message, func(partial string) {
+			typing.update(partial)
+			c.scrollToBottom()
+		})
+		typing.finish(response, func() {
+			c.conversationContainer.Remove(typing.label)
+			c.addCharacterResponse(message, response)
+			c.showQuickReplies(c.character.GetSuggestedReplies())
+			c.showActiveTopics()
+			c.sendButton.Enable()
+			c.responding.Store(false)
+		})
+	}()
+}
+
+// addCharacterResponse adds a finished character reply to the conversation
+func (c *ChatbotInterface) addCharacterResponse(message, response string) {
 	if response != "" {
 		// Check if this response is already marked as favorite
 		isFavorite, rating := false, float64(0)
@@ -215,6 +272,63 @@ func (c *ChatbotInterface) sendMessage() {
 	c.scrollToBottom()
 }
 
+// showQuickReplies offers a response's suggested replies, such as a
+// scripted conversation's choices, as buttons under the character's message.
+// Only the first maxQuickReplies are shown; the rest can still be typed.
+func (c *ChatbotInterface) showQuickReplies(replies []string) {
+	if len(replies) > maxQuickReplies {
+		replies = replies[:maxQuickReplies]
+	}
+	if len(replies) == 0 {
+		return
+	}
+
+	buttons := container.NewVBox()
+	for _, reply := range replies {
+		reply := reply
+		button := widget.NewButton(reply, func() {
+			c.chooseReply(reply)
+		})
+		c.replyButtons = append(c.replyButtons, button)
+		buttons.Add(button)
+	}
+	c.conversationContainer.Add(buttons)
+	c.conversationContainer.Refresh()
+	c.scrollToBottom()
+}
+
+// showActiveTopics updates the line naming what the conversation is about
+func (c *ChatbotInterface) showActiveTopics() {
+	text := formatActiveTopics(c.character.GetActiveTopics())
+	c.topicsLabel.SetText(text)
+	if text == "" {
+		c.topicsLabel.Hide()
+	} else {
+		c.topicsLabel.Show()
+	}
+}
+
+// formatActiveTopics names up to maxShownTopics topics, most recent first,
+// or returns "" when there are none
+func formatActiveTopics(topics []string) string {
+	if len(topics) == 0 {
+		return ""
+	}
+	if len(topics) > maxShownTopics {
+		topics = topics[:maxShownTopics]
+	}
+	return "Talking about: " + strings.Join(topics, ", ")
+}
+
+// chooseReply sends a picked quick reply as the user's next message
+func (c *ChatbotInterface) chooseReply(reply string) {
+	if c.responding.Load() {
+		return
+	}
+	c.messageInput.SetText(reply)
+	c.sendMessage()
+}
+
 // addMessage adds a message to the conversation log with history management
 func (c *ChatbotInterface) addMessage(message ChatMessage) {
 	c.conversationLog = append(c.conversationLog, message)
@@ -454,6 +568,12 @@ func (ci *ChatbotInterface) loadRecentConversations() {
 	}
 }
 
+// SetExportDir sets where ExportConversation writes, e.g. a profile's chat
+// directory. Empty means the user's home directory.
+func (ci *ChatbotInterface) SetExportDir(dir string) {
+	ci.exportDir = dir
+}
+
 // ENHANCEMENT: Export conversation history to file
 func (ci *ChatbotInterface) ExportConversation() error {
 	if len(ci.conversationLog) == 0 {
@@ -484,14 +604,20 @@ func (ci *ChatbotInterface) ExportConversation() error {
 			msg.Timestamp.Format("15:04:05"), speaker, msg.Text))
 	}
 
-	// Write to file in user's home directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %v", err)
+	// Write to the profile's chat directory, or the user's home directory
+	dir := ci.exportDir
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %v", err)
+		}
+		dir = homeDir
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create chat directory: %v", err)
 	}
 
-	filepath := filepath.Join(homeDir, filename)
-	err = os.WriteFile(filepath, []byte(conversation.String()), 0o644)
+	filepath := filepath.Join(dir, filename)
+	err := os.WriteFile(filepath, []byte(conversation.String()), 0o644)
 	if err != nil {
 		return fmt.Errorf("failed to write conversation file: %v", err)
 	}
@@ -407,3 +407,110 @@ func createMockCharacter(card *character.CharacterCard) *character.Character {
 
 	return char
 }
+
+func TestChatbotInterface_SendMessageTypesReply(t *testing.T) {
+	char := createMockCharacter(createTestCharacterCardWithDialogBackend())
+	if char == nil {
+		t.Skip("Test character could not be created")
+	}
+	chatbot := NewChatbotInterface(char)
+
+	chatbot.messageInput.SetText("Hello!")
+	chatbot.sendMessage()
+
+	if !chatbot.sendButton.Disabled() {
+		t.Error("Send should be disabled while the character is replying")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for chatbot.responding.Load() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if chatbot.responding.Load() {
+		t.Fatal("Reply was not finished in time")
+	}
+
+	if chatbot.GetConversationLength() != 2 {
+		t.Errorf("Expected user message and reply, got %d messages", chatbot.GetConversationLength())
+	}
+	if chatbot.sendButton.Disabled() {
+		t.Error("Send should be enabled again after the reply")
+	}
+}
+
+// waitForReply waits until the chatbot has finished replying
+func waitForReply(t *testing.T, chatbot *ChatbotInterface) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for chatbot.responding.Load() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if chatbot.responding.Load() {
+		t.Fatal("Reply was not finished in time")
+	}
+}
+
+func TestChatbotInterface_ScriptedChoices(t *testing.T) {
+	card := createTestCharacterCardWithDialogBackend()
+	card.DialogBackend.DefaultBackend = "scripted"
+	card.DialogBackend.Backends["scripted"] = json.RawMessage(`{"script": {
+		"entries": {"chat": "ask"},
+		"nodes": {
+			"ask": {"text": "Tea or coffee?", "choices": [
+				{"text": "Tea", "next": "tea"},
+				{"text": "Coffee", "next": "coffee"}
+			]},
+			"tea": {"text": "Tea it is!"},
+			"coffee": {"text": "Coffee it is!"}
+		}
+	}}`)
+	char := createMockCharacter(card)
+	if char == nil {
+		t.Skip("Test character could not be created")
+	}
+	chatbot := NewChatbotInterface(char)
+
+	chatbot.messageInput.SetText("hi")
+	chatbot.sendMessage()
+	waitForReply(t, chatbot)
+	if len(chatbot.replyButtons) != 2 || chatbot.replyButtons[1].Text != "Coffee" {
+		t.Fatalf("Expected both answers as buttons, got %d", len(chatbot.replyButtons))
+	}
+
+	test.Tap(chatbot.replyButtons[1])
+	waitForReply(t, chatbot)
+	if got := chatbot.conversationLog[len(chatbot.conversationLog)-1].Text; got != "Coffee it is!" {
+		t.Errorf("Expected the chosen branch, got %q", got)
+	}
+	if chatbot.conversationLog[2].Text != "Coffee" || !chatbot.conversationLog[2].IsUser {
+		t.Errorf("Expected the choice to be sent as the user's message, got %+v", chatbot.conversationLog[2])
+	}
+	if len(chatbot.replyButtons) != 0 {
+		t.Error("Expected no buttons once the conversation ends")
+	}
+}
+
+func TestChatbotInterface_QuickRepliesCapped(t *testing.T) {
+	char := createMockCharacter(createTestCharacterCardWithDialogBackend())
+	if char == nil {
+		t.Skip("Test character could not be created")
+	}
+	chatbot := NewChatbotInterface(char)
+
+	chatbot.showQuickReplies([]string{"a", "b", "c", "d", "e", "f"})
+	if len(chatbot.replyButtons) != maxQuickReplies || chatbot.replyButtons[3].Text != "d" {
+		t.Errorf("Expected the first %d replies as buttons, got %d", maxQuickReplies, len(chatbot.replyButtons))
+	}
+}
+
+func TestFormatActiveTopics(t *testing.T) {
+	if got := formatActiveTopics(nil); got != "" {
+		t.Errorf("Expected nothing without topics, got %q", got)
+	}
+	if got := formatActiveTopics([]string{"media", "food"}); got != "Talking about: media, food" {
+		t.Errorf("Unexpected topics line %q", got)
+	}
+	if got := formatActiveTopics([]string{"a", "b", "c", "d"}); got != "Talking about: a, b, c" {
+		t.Errorf("Expected at most %d topics, got %q", maxShownTopics, got)
+	}
+}
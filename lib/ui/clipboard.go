@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+)
+
+// SetClipboardReactions starts or stops reacting to copied text. Only the
+// allowed kinds are reacted to; nil allows clipboard.DefaultKinds.
+func (dw *DesktopWindow) SetClipboardReactions(enabled bool, kinds []clipboard.Kind) {
+	dw.clipboardMu.Lock()
+	defer dw.clipboardMu.Unlock()
+
+	if dw.clipboardWatcher != nil {
+		dw.clipboardWatcher.Stop()
+		dw.clipboardWatcher = nil
+	}
+	if !enabled {
+		return
+	}
+
+	read := func() string { return dw.window.Clipboard().Content() }
+	dw.clipboardWatcher = clipboard.NewWatcher(read, kinds, dw.handleClipboardEvent)
+	dw.clipboardWatcher.Start(clipboard.DefaultPollInterval)
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"kinds":  kinds,
+	}).Info("Clipboard reactions enabled")
+}
+
+// stopClipboardReactions stops the clipboard watcher on close
+func (dw *DesktopWindow) stopClipboardReactions() {
+	dw.SetClipboardReactions(false, nil)
+}
+
+// handleClipboardEvent reacts to a copy. Links get an offer to summarize
+// when the character has a dialog backend to do it. The latest copy made
+// while dialogs are silenced is reacted to once they aren't.
+func (dw *DesktopWindow) handleClipboardEvent(event clipboard.Event) {
+	if dw.isQuiet() {
+		dw.held.holdClipboard(event)
+		return
+	}
+
+	response := dw.character.ClipboardReaction(event.Kind)
+	if response == "" {
+		return
+	}
+	if event.Kind != clipboard.KindURL || !dw.character.GetCard().HasDialogBackend() {
+		dw.showDialog(response)
+		return
+	}
+	dw.offerSummary(response, event.URL)
+}
+
+// offerSummary shows the reaction with a button that asks the dialog
+// backend to summarize the copied link
+func (dw *DesktopWindow) offerSummary(response, link string) {
+	label := widget.NewLabel(response)
+	label.Wrapping = fyne.TextWrapWord
+
+	summarizeButton := widget.NewButton("📰 Summarize it", func() {
+		dw.showDialog("Let me take a look...")
+		go func() {
+			summary := dw.character.HandleChatMessage("Can you summarize this page for me? " + link)
+			if summary == "" {
+				summary = "Sorry, I couldn't read that one."
+			}
+			dw.showDialog(summary)
+		}()
+	})
+
+	content := container.NewVBox(label, summarizeButton)
+	content.Resize(fyne.NewSize(240, 100))
+	dw.showModalContent(content)
+}
@@ -8,6 +8,7 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -34,14 +35,21 @@ type ContextMenu struct {
 	visible    bool
 	menuItems  []*widget.Button
 	callbacks  []func()
+	objects    []fyne.CanvasObject // What is laid out for each item: the button or its preview wrapper
+
+	// OnPreview shows an item's preview text. Previews are requested with
+	// Alt+hover on desktop or a long press on touch screens.
+	OnPreview func(text string)
 }
 
 // ContextMenuItem represents a single menu item with text and callback.
 // The callback function is executed when the menu item is selected.
 // If Callback is nil, the menu will still hide when the item is clicked.
+// Preview optionally describes what selecting the item would do.
 type ContextMenuItem struct {
 	Text     string
 	Callback func()
+	Preview  func() string
 }
 
 // NewContextMenu creates a new context menu widget.
@@ -77,21 +85,32 @@ func (m *ContextMenu) SetMenuItems(items []ContextMenuItem) {
 	// Clear existing items
 	m.menuItems = make([]*widget.Button, 0, len(items))
 	m.callbacks = make([]func(), 0, len(items))
+	m.objects = make([]fyne.CanvasObject, 0, len(items))
 
 	// Create buttons for each menu item
 	for _, item := range items {
 		// Capture the callback in a closure to avoid loop variable issues
 		callback := item.Callback
 
-		// Create button with consistent styling
-		btn := widget.NewButton(item.Text, func() {
+		tapped := func() {
 			// Hide menu when item is clicked
 			m.Hide()
 			// Execute the callback
 			if callback != nil {
 				callback()
 			}
-		})
+		}
+
+		// Create button with consistent styling
+		var btn *widget.Button
+		if item.Preview != nil {
+			previewBtn := newPreviewButton(item.Text, tapped, item.Preview, m.showPreview)
+			btn = &previewBtn.Button
+			m.objects = append(m.objects, previewBtn)
+		} else {
+			btn = widget.NewButton(item.Text, tapped)
+			m.objects = append(m.objects, btn)
+		}
 
 		// Style the button for menu appearance
 		btn.Importance = widget.LowImportance
@@ -110,15 +129,13 @@ func (m *ContextMenu) rebuildContent() {
 	objects := []fyne.CanvasObject{m.background}
 
 	// Add all menu buttons
-	for _, btn := range m.menuItems {
-		objects = append(objects, btn)
-	}
+	objects = append(objects, m.objects...)
 
 	// Create new container with border layout
 	// Background fills the entire area, buttons are arranged vertically
 	buttonContainer := container.NewVBox()
-	for _, btn := range m.menuItems {
-		buttonContainer.Add(btn)
+	for _, obj := range m.objects {
+		buttonContainer.Add(obj)
 	}
 
 	m.content = container.NewBorder(nil, nil, nil, nil, m.background, buttonContainer)
@@ -197,6 +214,63 @@ func (m *ContextMenu) applyMenuLayout(width, height, menuX, menuY float32) {
 	}
 }
 
+// showPreview passes an item's preview text to OnPreview
+func (m *ContextMenu) showPreview(text string) {
+	if m.OnPreview != nil && text != "" {
+		m.OnPreview(text)
+	}
+}
+
+// previewButton is a menu button that shows its item's preview on
+// Alt+hover, or on a secondary tap, which touch screens send for a long press
+type previewButton struct {
+	widget.Button
+	preview func() string
+	show    func(string)
+	shown   bool // Already previewed during this hover
+}
+
+// newPreviewButton creates a menu button with a preview
+func newPreviewButton(text string, tapped func(), preview func() string, show func(string)) *previewButton {
+	b := &previewButton{preview: preview, show: show}
+	b.Text = text
+	b.OnTapped = tapped
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+// MouseIn previews when the pointer enters with Alt held
+func (b *previewButton) MouseIn(event *desktop.MouseEvent) {
+	b.Button.MouseIn(event)
+	b.previewIfAlt(event.Modifier)
+}
+
+// MouseMoved previews when Alt is pressed while hovering
+func (b *previewButton) MouseMoved(event *desktop.MouseEvent) {
+	b.Button.MouseMoved(event)
+	b.previewIfAlt(event.Modifier)
+}
+
+// MouseOut allows the next Alt+hover to preview again
+func (b *previewButton) MouseOut() {
+	b.Button.MouseOut()
+	b.shown = false
+}
+
+// TappedSecondary previews on a long press or right-click
+func (b *previewButton) TappedSecondary(*fyne.PointEvent) {
+	b.show(b.preview())
+}
+
+// previewIfAlt shows the preview once per hover while Alt is held
+func (b *previewButton) previewIfAlt(modifier fyne.KeyModifier) {
+	if modifier&fyne.KeyModifierAlt == 0 || b.shown {
+		return
+	}
+	b.shown = true
+	b.show(b.preview())
+}
+
 // Show displays the context menu
 // Following the same pattern as DialogBubble.Show()
 func (m *ContextMenu) Show() {
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// newCrisisIndicator creates the warning icon shown on the character during
+// a relationship crisis. Tapping it opens the relationship status.
+func newCrisisIndicator(onTap func()) *widget.Button {
+	indicator := widget.NewButton("⚠️", onTap)
+	indicator.Importance = widget.LowImportance
+	indicator.Resize(fyne.NewSize(24, 24))
+	indicator.Move(fyne.NewPos(4, 4))
+	indicator.Hide()
+	return indicator
+}
+
+// buildRelationshipStatusMenuItem creates the relationship status entry.
+// Only shown for characters that can have relationship crises.
+func (dw *DesktopWindow) buildRelationshipStatusMenuItem() (ContextMenuItem, bool) {
+	if !dw.character.CrisisTrackingEnabled() {
+		return ContextMenuItem{}, false
+	}
+
+	text := "💞 Relationship Status"
+	if dw.character.IsInCrisis() {
+		text = "💔 Relationship Status"
+	}
+
+	return ContextMenuItem{
+		Text: text,
+		Callback: func() {
+			dw.showRelationshipStatus()
+		},
+	}, true
+}
+
+// showRelationshipStatus explains active crises and what helps recovery
+func (dw *DesktopWindow) showRelationshipStatus() {
+	titleLabel := widget.NewLabel("Relationship Status")
+	titleLabel.Alignment = fyne.TextAlignCenter
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	body := widget.NewLabel(formatRelationshipStatus(dw.character.GetRelationshipCrises()))
+	body.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(titleLabel, body)
+	content.Resize(fyne.NewSize(260, 240))
+	dw.showModalContent(content)
+}
+
+// formatRelationshipStatus renders crisis statuses as plain text
+func formatRelationshipStatus(crises []character.CrisisStatus) string {
+	if len(crises) == 0 {
+		return "💞 Everything is fine between you two."
+	}
+
+	var b strings.Builder
+	for i, crisis := range crises {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "⚠️ %s (%s)\n%s", crisis.Title, severityLabel(crisis.Severity), crisis.Description)
+
+		if len(crisis.Interactions) > 0 {
+			b.WriteString("\nWhat helps:")
+			for _, step := range crisis.Interactions {
+				mark := "○"
+				if step.Done >= step.Required {
+					mark = "✓"
+				}
+				fmt.Fprintf(&b, "\n %s %s %d/%d", mark, strings.ReplaceAll(step.Interaction, "_", " "), step.Done, step.Required)
+			}
+		}
+
+		if crisis.TimeRemaining > 0 {
+			fmt.Fprintf(&b, "\n⏳ Needs at least %s more to heal", crisis.TimeRemaining.Round(time.Minute))
+		}
+	}
+	return b.String()
+}
+
+// severityLabel describes a 0-1 crisis severity in words
+func severityLabel(severity float64) string {
+	switch {
+	case severity >= 0.7:
+		return "serious"
+	case severity >= 0.3:
+		return "moderate"
+	default:
+		return "mild"
+	}
+}
+
+// checkForCrisisChanges keeps the warning icon in sync and gently announces
+// crises starting or resolving
+func (dw *DesktopWindow) checkForCrisisChanges() {
+	if dw.character == nil {
+		return
+	}
+
+	if dw.crisisIndicator != nil {
+		if dw.character.IsInCrisis() {
+			dw.crisisIndicator.Show()
+		} else {
+			dw.crisisIndicator.Hide()
+		}
+	}
+
+	for _, change := range dw.character.GetCrisisChanges() {
+		if change.Resolved {
+			dw.showNotice(fmt.Sprintf("💞 We worked through it (%s). Thank you.", strings.ToLower(change.Title)))
+		} else {
+			dw.showNotice(fmt.Sprintf("💔 Something feels off between us... (%s)\nRight-click → Relationship Status", strings.ToLower(change.Title)))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestFormatRelationshipStatus(t *testing.T) {
+	if text := formatRelationshipStatus(nil); !strings.Contains(text, "Everything is fine") {
+		t.Errorf("Expected all-clear message, got %q", text)
+	}
+
+	text := formatRelationshipStatus([]character.CrisisStatus{{
+		Title:         "Jealousy",
+		Description:   "High jealousy is causing relationship strain",
+		Severity:      0.8,
+		TimeRemaining: 20 * time.Minute,
+		Interactions: []character.RecoveryProgress{
+			{Interaction: "apology", Done: 2, Required: 2},
+			{Interaction: "deep_conversation", Done: 0, Required: 1},
+		},
+	}})
+
+	for _, want := range []string{"Jealousy (serious)", "✓ apology 2/2", "○ deep conversation 0/1", "20m0s more"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in status:\n%s", want, text)
+		}
+	}
+}
+
+func TestRelationshipStatusMenuItemHiddenWithoutCrises(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if _, ok := dw.buildRelationshipStatusMenuItem(); ok {
+		t.Error("Relationship status should only show for characters with crisis tracking")
+	}
+
+	dw.checkForCrisisChanges()
+	if dw.crisisIndicator.Visible() {
+		t.Error("Crisis icon should stay hidden without a crisis")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/platform"
+	"github.com/opd-ai/desktop-companion/lib/ui/gestures"
 )
 
 // DraggableCharacter implements character dragging using Fyne's event system
@@ -28,6 +30,9 @@ type DraggableCharacter struct {
 	// Hover state for tooltip
 	hoverStartTime time.Time
 	isHovering     bool
+
+	// Touch gesture recognition, nil on mouse-only platforms
+	touch *gestures.GestureHandler
 }
 
 // NewDraggableCharacter creates a new draggable character widget
@@ -39,6 +44,7 @@ func NewDraggableCharacter(window *DesktopWindow, char *character.Character, deb
 	}
 
 	dc.ExtendBaseWidget(dc)
+	dc.enableTouchGestures(platform.GetPlatformInfo())
 
 	if debug {
 		log.Println("Created draggable character wrapper")
@@ -47,6 +53,20 @@ func NewDraggableCharacter(window *DesktopWindow, char *character.Character, deb
 	return dc
 }
 
+// enableTouchGestures adds double-tap and pinch recognition on touch
+// platforms. Long press needs nothing extra: Fyne reports it as a
+// secondary tap, which already opens the context menu.
+func (dc *DraggableCharacter) enableTouchGestures(info *platform.PlatformInfo) {
+	if !info.IsMobile() && !info.HasTouch() {
+		return
+	}
+
+	dc.touch = gestures.NewGestureHandler(info, nil)
+	dc.touch.SetTapHandler(func() { dc.window.handleClick() })
+	dc.touch.SetDoubleTapHandler(func() { dc.window.handleDoubleTap() })
+	dc.touch.SetPinchHandler(func(scale float32) { dc.window.handlePinch(scale) })
+}
+
 // CreateRenderer creates the Fyne renderer for the draggable character
 func (dc *DraggableCharacter) CreateRenderer() fyne.WidgetRenderer {
 	return &draggableCharacterRenderer{
@@ -128,6 +148,7 @@ func (dc *DraggableCharacter) MouseIn(event *fyne.PointEvent) {
 	// Start hover timing for tooltip
 	dc.isHovering = true
 	dc.hoverStartTime = time.Now()
+	dc.window.shyHover(true)
 
 	// Start a goroutine to check if hover exceeds 2 seconds
 	go dc.checkHoverTimeout()
@@ -152,6 +173,7 @@ func (dc *DraggableCharacter) MouseOut() {
 	// Hide tooltip if it was showing
 	if dc.window != nil {
 		dc.window.HideStatsTooltip()
+		dc.window.shyHover(false)
 	}
 
 	if dc.debug {
@@ -167,8 +189,19 @@ func (dc *DraggableCharacter) MouseMoved(event *fyne.PointEvent) {
 
 // Tapped handles tap/click events on the character
 func (dc *DraggableCharacter) Tapped(event *fyne.PointEvent) {
-	// Delegate to the window's click handler
-	dc.window.handleClick()
+	// A tap only ends the screensaver or coaxes a hiding shy character out
+	if dc.window.stopScreensaver() || dc.window.emergeShy() {
+		return
+	}
+
+	if dc.touch != nil {
+		// Let the gesture handler tell single taps from double taps
+		dc.touch.HandleTouchStart(event.Position)
+		dc.touch.HandleTouchEnd(event.Position)
+	} else {
+		// Delegate to the window's click handler
+		dc.window.handleClick()
+	}
 
 	if dc.debug {
 		log.Printf("Character tapped at (%.1f, %.1f)", event.Position.X, event.Position.Y)
@@ -185,6 +218,18 @@ func (dc *DraggableCharacter) TappedSecondary(event *fyne.PointEvent) {
 	}
 }
 
+// Scrolled treats two-finger scrolling on touch platforms as a pinch and
+// passes the mouse wheel to the window on desktop (Ctrl+scroll resizes)
+func (dc *DraggableCharacter) Scrolled(event *fyne.ScrollEvent) {
+	if dc.touch != nil {
+		dc.touch.HandlePinch(gestures.ScrollToPinchScale(event.Scrolled.DY))
+		return
+	}
+	if dc.window != nil {
+		dc.window.handleScroll(event)
+	}
+}
+
 // checkHoverTimeout checks if hover duration exceeds 2 seconds and shows tooltip
 func (dc *DraggableCharacter) checkHoverTimeout() {
 	// Wait for 2 seconds
@@ -24,6 +24,10 @@ func (m *MockNetworkManagerForCompatibility) GetNetworkID() string {
 	return "test-network"
 }
 
+func (m *MockNetworkManagerForCompatibility) GetPeerID() string {
+	return "test-peer"
+}
+
 func (m *MockNetworkManagerForCompatibility) SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error {
 	return nil
 }
@@ -31,6 +35,9 @@ func (m *MockNetworkManagerForCompatibility) SendMessage(msgType network.Message
 func (m *MockNetworkManagerForCompatibility) RegisterMessageHandler(msgType network.MessageType, handler network.MessageHandler) {
 }
 
+func (m *MockNetworkManagerForCompatibility) AddMessageObserver(msgType network.MessageType, observer network.MessageHandler) {
+}
+
 // Helper function to create test characters with different personalities
 func createTestCharacterWithPersonality(t *testing.T, name string, traits map[string]float64) *character.Character {
 	t.Helper()
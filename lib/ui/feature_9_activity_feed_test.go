@@ -273,6 +273,10 @@ func (m *mockNetworkManager) GetNetworkID() string {
 	return m.networkID
 }
 
+func (m *mockNetworkManager) GetPeerID() string {
+	return m.networkID
+}
+
 func (m *mockNetworkManager) SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error {
 	return nil
 }
@@ -283,3 +287,6 @@ func (m *mockNetworkManager) RegisterMessageHandler(msgType network.MessageType,
 	}
 	m.handlers[msgType] = handler
 }
+
+func (m *mockNetworkManager) AddMessageObserver(msgType network.MessageType, observer network.MessageHandler) {
+}
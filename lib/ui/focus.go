@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// Session lengths offered in the focus timer setup, in minutes
+var (
+	focusWorkOptions  = []int{15, 25, 45, 50}
+	focusBreakOptions = []int{5, 10, 15}
+)
+
+// buildFocusMenuItem creates the pomodoro entry for the utility menu.
+// Shows the running phase instead when the timer is on.
+func (dw *DesktopWindow) buildFocusMenuItem() ContextMenuItem {
+	status := dw.character.GetFocusStatus()
+	if status.Phase == character.FocusOff {
+		return ContextMenuItem{
+			Text: "🍅 Focus Timer",
+			Callback: func() {
+				dw.showFocusSetup()
+			},
+		}
+	}
+
+	return ContextMenuItem{
+		Text: fmt.Sprintf("%s (%s left)", focusPhaseLabel(status.Phase), formatJobDuration(status.Remaining)),
+		Callback: func() {
+			dw.showFocusProgress()
+		},
+	}
+}
+
+// showFocusSetup lets the user pick session lengths and start the timer
+func (dw *DesktopWindow) showFocusSetup() {
+	focus := dw.character.GetCard().Focus
+
+	workSelect := widget.NewSelect(minuteOptions(focusWorkOptions, focus.GetWorkDuration()), nil)
+	workSelect.SetSelected(formatMinutes(focus.GetWorkDuration()))
+	breakSelect := widget.NewSelect(minuteOptions(focusBreakOptions, focus.GetBreakDuration()), nil)
+	breakSelect.SetSelected(formatMinutes(focus.GetBreakDuration()))
+
+	startButton := widget.NewButton("Start", func() {
+		response := dw.character.StartFocus(parseMinutes(workSelect.Selected), parseMinutes(breakSelect.Selected))
+		dw.showFocusDialog(response)
+		dw.updateFocusLabel()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("🍅 Focus Timer"),
+		container.NewGridWithColumns(2,
+			widget.NewLabel("Work"), workSelect,
+			widget.NewLabel("Break"), breakSelect,
+		),
+		startButton,
+	)
+	content.Resize(fyne.NewSize(240, 160))
+	dw.showModalContent(content)
+}
+
+// showFocusProgress shows the running phase with skip and stop buttons
+func (dw *DesktopWindow) showFocusProgress() {
+	status := dw.character.GetFocusStatus()
+	if status.Phase == character.FocusOff {
+		return
+	}
+
+	progress := widget.NewProgressBar()
+	if status.Total > 0 {
+		progress.SetValue(1 - float64(status.Remaining)/float64(status.Total))
+	}
+
+	skipText := "Skip to break"
+	if status.Phase == character.FocusBreak {
+		skipText = "Skip break"
+	}
+	skipButton := widget.NewButton(skipText, func() {
+		dw.character.SkipFocusPhase()
+	})
+	stopButton := widget.NewButton("Stop timer", func() {
+		if dw.character.StopFocus() {
+			dw.updateFocusLabel()
+			dw.showDialog(fmt.Sprintf("Nice session! %d pomodoro(s) done 🍅", status.Completed))
+		}
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("%s - %s left", focusPhaseLabel(status.Phase), formatJobDuration(status.Remaining))),
+		progress,
+		widget.NewLabel(fmt.Sprintf("Completed: %d", status.Completed)),
+		skipButton,
+		stopButton,
+	)
+	content.Resize(fyne.NewSize(240, 180))
+	dw.showModalContent(content)
+}
+
+// checkForFocusChanges announces breaks and new work sessions and keeps the
+// timer widget current
+func (dw *DesktopWindow) checkForFocusChanges() {
+	if dw.character == nil {
+		return
+	}
+
+	for _, result := range dw.character.GetFocusResults() {
+		dw.showFocusDialog(result.Response)
+	}
+	dw.updateFocusLabel()
+}
+
+// showFocusDialog shows timer announcements, which get through while a work
+// session keeps other dialogs quiet. Do-not-disturb holds them until it ends.
+func (dw *DesktopWindow) showFocusDialog(text string) {
+	if text == "" {
+		return
+	}
+	if dw.character.IsDoNotDisturb() {
+		dw.held.holdNotice(text, true)
+		return
+	}
+	dw.displayDialog(text)
+}
+
+// updateFocusLabel shows the remaining time in the overlay while the timer runs
+func (dw *DesktopWindow) updateFocusLabel() {
+	if dw.focusLabel == nil {
+		return
+	}
+
+	status := dw.character.GetFocusStatus()
+	if status.Phase == character.FocusOff {
+		if dw.focusLabel.Visible() {
+			dw.focusLabel.Hide()
+		}
+		return
+	}
+
+	icon := "🍅"
+	if status.Phase == character.FocusBreak {
+		icon = "☕"
+	}
+	remaining := status.Remaining.Round(time.Second)
+	text := fmt.Sprintf("%s %02d:%02d", icon, int(remaining.Minutes()), int(remaining.Seconds())%60)
+	if dw.focusLabel.Text != text {
+		dw.focusLabel.SetText(text)
+	}
+	if !dw.focusLabel.Visible() {
+		dw.focusLabel.Show()
+	}
+}
+
+// focusPhaseLabel names a running phase for menus
+func focusPhaseLabel(phase character.FocusPhase) string {
+	if phase == character.FocusBreak {
+		return "☕ Break"
+	}
+	return "🍅 Focusing"
+}
+
+// minuteOptions lists the preset lengths plus the card's own, sorted
+func minuteOptions(presets []int, cardDefault time.Duration) []string {
+	minutes := map[int]bool{int(cardDefault.Minutes()): true}
+	for _, preset := range presets {
+		minutes[preset] = true
+	}
+
+	sorted := make([]int, 0, len(minutes))
+	for m := range minutes {
+		sorted = append(sorted, m)
+	}
+	sort.Ints(sorted)
+
+	options := make([]string, len(sorted))
+	for i, m := range sorted {
+		options[i] = formatMinutes(time.Duration(m) * time.Minute)
+	}
+	return options
+}
+
+// formatMinutes renders a session length as "25 min"
+func formatMinutes(d time.Duration) string {
+	return fmt.Sprintf("%d min", int(d.Minutes()))
+}
+
+// parseMinutes reads "25 min" back; 0 falls back to the card's setting
+func parseMinutes(option string) time.Duration {
+	var minutes int
+	if _, err := fmt.Sscanf(option, "%d min", &minutes); err != nil {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMinuteOptions(t *testing.T) {
+	got := minuteOptions([]int{15, 25}, 20*time.Minute)
+	want := []string{"15 min", "20 min", "25 min"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("minuteOptions = %v, want %v", got, want)
+	}
+
+	if got := minuteOptions([]int{5, 10}, 5*time.Minute); len(got) != 2 {
+		t.Errorf("Card default matching a preset should not be listed twice, got %v", got)
+	}
+}
+
+func TestParseMinutes(t *testing.T) {
+	if got := parseMinutes(formatMinutes(45 * time.Minute)); got != 45*time.Minute {
+		t.Errorf("parseMinutes round trip = %v, want 45m", got)
+	}
+	if got := parseMinutes(""); got != 0 {
+		t.Errorf("Empty selection should fall back to 0, got %v", got)
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+)
+
+// overlayRefreshInterval is how often the stats and network overlays
+// refresh at full quality
+const overlayRefreshInterval = 2 * time.Second
+
+// recordFrameTime tells the profiler how long a frame took and applies any
+// change in how far the app scales back on slow hardware: fewer particles
+// and slower overlay refreshes here, a lower animation frame rate in the
+// animation loop. Returns whether the level changed. Only called from the
+// animation loop, which owns dw.degradation.
+func (dw *DesktopWindow) recordFrameTime(frameTime time.Duration) bool {
+	if dw.profiler == nil {
+		return false
+	}
+	level, changed := dw.profiler.RecordFrameTime(frameTime)
+	if !changed {
+		return false
+	}
+
+	dw.degradation = level
+	dw.applyOverlayRefresh(level)
+	return true
+}
+
+// applyOverlayRefresh sets the overlays' refresh rate for the degradation level
+func (dw *DesktopWindow) applyOverlayRefresh(level monitoring.DegradationLevel) {
+	interval := level.OverlayRefresh(overlayRefreshInterval)
+	if dw.statsOverlay != nil {
+		dw.statsOverlay.SetRefreshInterval(interval)
+	}
+	if dw.networkOverlay != nil {
+		dw.networkOverlay.SetRefreshInterval(interval)
+	}
+}
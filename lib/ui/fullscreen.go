@@ -0,0 +1,264 @@
+package ui
+
+// fullscreen.go keeps the character out of the way while another app runs
+// fullscreen, such as a game or a video: it hides, shrinks into a screen
+// corner or holds its animation still, and its dialogs wait until the app
+// leaves fullscreen. The fullscreen check comes from the platform (X11,
+// Windows, macOS); Wayland keeps other clients' windows private, so the
+// check stops there.
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+// What the character does while another app is fullscreen, for the
+// fullscreen setting
+const (
+	FullscreenHide   = "hide"   // Hide the window
+	FullscreenCorner = "corner" // Shrink into the bottom-right corner of the screen
+	FullscreenPause  = "pause"  // Stay put with the animation held still
+	FullscreenOff    = "off"    // Don't check for fullscreen apps
+)
+
+const (
+	fullscreenPollInterval = time.Second // How often the focused window is checked
+	fullscreenCornerSize   = 64          // Largest sprite side in the corner
+)
+
+// fullscreenOptions are the fullscreen policies offered in the settings window
+var fullscreenOptions = []struct {
+	label string
+	name  string
+}{
+	{"Hide", FullscreenHide},
+	{"Shrink into a corner", FullscreenCorner},
+	{"Pause animation", FullscreenPause},
+	{"Do nothing", FullscreenOff},
+}
+
+// fullscreenState tracks the fullscreen policy for the window
+type fullscreenState struct {
+	mu      sync.Mutex
+	policy  string        // One of the Fullscreen constants, empty until set
+	stop    chan struct{} // Stops the check loop
+	applied string        // Policy in effect for a fullscreen app, empty when there is none
+	home    image.Point   // Window position before moving into the corner
+	moved   bool          // The window was moved into the corner and must be moved back
+}
+
+// SetFullscreenPolicy sets what the character does while another app is
+// fullscreen, starting or stopping the check. Unknown policies, and the
+// empty one, mean FullscreenHide.
+func (dw *DesktopWindow) SetFullscreenPolicy(policy string) {
+	if fullscreenIndex(policy) < 0 {
+		policy = FullscreenHide
+	}
+
+	dw.fullscreen.mu.Lock()
+	if policy == dw.fullscreen.policy {
+		dw.fullscreen.mu.Unlock()
+		return
+	}
+	dw.fullscreen.policy = policy
+	if policy == FullscreenOff && dw.fullscreen.stop != nil {
+		close(dw.fullscreen.stop)
+		dw.fullscreen.stop = nil
+	} else if policy != FullscreenOff && dw.fullscreen.stop == nil {
+		dw.fullscreen.stop = make(chan struct{})
+		go dw.watchFullscreen(dw.fullscreen.stop)
+	}
+	applied := dw.fullscreen.applied
+	dw.fullscreen.applied = ""
+	dw.fullscreen.mu.Unlock()
+
+	// Undo the old policy; the next check applies the new one
+	if applied != "" {
+		dw.leaveFullscreenPolicy(applied)
+	}
+}
+
+// behindFullscreen reports whether a fullscreen app has the screen, so the
+// character keeps quiet
+func (dw *DesktopWindow) behindFullscreen() bool {
+	dw.fullscreen.mu.Lock()
+	defer dw.fullscreen.mu.Unlock()
+	return dw.fullscreen.applied != ""
+}
+
+// fullscreenPaused reports whether the animation is held still for a
+// fullscreen app
+func (dw *DesktopWindow) fullscreenPaused() bool {
+	dw.fullscreen.mu.Lock()
+	defer dw.fullscreen.mu.Unlock()
+	return dw.fullscreen.applied == FullscreenPause
+}
+
+// watchFullscreen checks the focused window until stop is closed, or for
+// good once the platform turns out not to support the check
+func (dw *DesktopWindow) watchFullscreen(stop chan struct{}) {
+	ticker := time.NewTicker(fullscreenPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !dw.checkFullscreen() {
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+			}).Info("Fullscreen detection unavailable, fullscreen policy disabled")
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkFullscreen asks the platform about the focused window once and
+// follows it. Returns false when the platform can't tell.
+func (dw *DesktopWindow) checkFullscreen() bool {
+	supported, fullscreen := true, false
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		var err error
+		fullscreen, err = native.FocusedWindowFullscreen(win)
+		if errors.Is(err, native.ErrPositionUnsupported) {
+			supported = false
+		} else if err != nil {
+			logrus.WithError(err).Debug("Failed to check for a fullscreen window")
+		}
+	})
+	if supported {
+		// Fyne calls wait for the main thread, so they run after RunNative
+		dw.followFullscreen(fullscreen)
+	}
+	return supported
+}
+
+// followFullscreen applies the policy when a fullscreen app comes up and
+// undoes it when the app goes
+func (dw *DesktopWindow) followFullscreen(fullscreen bool) {
+	dw.fullscreen.mu.Lock()
+	policy, applied := dw.fullscreen.policy, dw.fullscreen.applied
+	if fullscreen == (applied != "") || (fullscreen && (policy == "" || policy == FullscreenOff)) {
+		dw.fullscreen.mu.Unlock()
+		return
+	}
+	if fullscreen {
+		dw.fullscreen.applied = policy
+	} else {
+		dw.fullscreen.applied = ""
+	}
+	dw.fullscreen.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"caller":     getCaller(),
+		"fullscreen": fullscreen,
+		"policy":     policy,
+	}).Info("Fullscreen app changed")
+
+	if fullscreen {
+		dw.enterFullscreenPolicy(policy)
+	} else {
+		dw.leaveFullscreenPolicy(applied)
+	}
+}
+
+// enterFullscreenPolicy gets out of the fullscreen app's way
+func (dw *DesktopWindow) enterFullscreenPolicy(policy string) {
+	switch policy {
+	case FullscreenHide:
+		dw.window.Hide()
+	case FullscreenCorner:
+		dw.shrinkIntoCorner()
+	}
+	// FullscreenPause is read by the animation loop
+}
+
+// leaveFullscreenPolicy comes back once the fullscreen app is gone
+func (dw *DesktopWindow) leaveFullscreenPolicy(policy string) {
+	switch policy {
+	case FullscreenHide:
+		dw.window.Show()
+	case FullscreenCorner:
+		dw.restoreFromCorner()
+	}
+}
+
+// shrinkIntoCorner draws the character small in the bottom-right corner of
+// the screen. Like widget mode it leaves the character's saved size alone.
+// Where the window can't be moved it shrinks in place.
+func (dw *DesktopWindow) shrinkIntoCorner() {
+	side := min(dw.character.GetSize(), fullscreenCornerSize)
+	small := fyne.NewSize(float32(side), float32(side))
+	dw.renderer.Resize(small)
+	dw.renderer.SetSize(side)
+	dw.window.Resize(small)
+
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		frame, screen, err := native.WindowGeometry(win)
+		if err != nil {
+			return
+		}
+		if err := native.MoveWindow(win, screen.Max.X-side, screen.Max.Y-side); err != nil {
+			logrus.WithError(err).Debug("Failed to move the character into the corner")
+			return
+		}
+		dw.fullscreen.mu.Lock()
+		dw.fullscreen.home, dw.fullscreen.moved = frame.Min, true
+		dw.fullscreen.mu.Unlock()
+	})
+}
+
+// restoreFromCorner brings the character back to its size and place
+func (dw *DesktopWindow) restoreFromCorner() {
+	size := dw.character.GetSize()
+	full := fyne.NewSize(float32(size), float32(size))
+	dw.renderer.Resize(full)
+	dw.renderer.SetSize(size)
+	dw.window.Resize(full)
+
+	dw.fullscreen.mu.Lock()
+	home, moved := dw.fullscreen.home, dw.fullscreen.moved
+	dw.fullscreen.moved = false
+	dw.fullscreen.mu.Unlock()
+	if !moved {
+		return
+	}
+
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		if err := native.MoveWindow(win, home.X, home.Y); err != nil {
+			logrus.WithError(err).Debug("Failed to move the character back from the corner")
+			return
+		}
+		dw.character.SetPosition(float32(home.X), float32(home.Y))
+	})
+}
+
+// stopFullscreenWatch ends the check loop when the window closes
+func (dw *DesktopWindow) stopFullscreenWatch() {
+	dw.fullscreen.mu.Lock()
+	defer dw.fullscreen.mu.Unlock()
+
+	if dw.fullscreen.stop != nil {
+		close(dw.fullscreen.stop)
+		dw.fullscreen.stop = nil
+	}
+}
+
+// fullscreenIndex returns the option for a fullscreen policy, or -1
+func fullscreenIndex(name string) int {
+	for i, option := range fullscreenOptions {
+		if option.name == name {
+			return i
+		}
+	}
+	return -1
+}
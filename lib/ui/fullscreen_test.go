@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestFullscreenPolicies(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterForWidget(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, true, true, nil, false, false, false)
+	defer dw.Close()
+
+	size := char.GetSize()
+
+	dw.SetFullscreenPolicy(FullscreenPause)
+	dw.followFullscreen(true)
+	if !dw.fullscreenPaused() || !dw.isQuiet() {
+		t.Error("Expected the animation paused and dialogs held while an app is fullscreen")
+	}
+	dw.followFullscreen(false)
+	if dw.fullscreenPaused() || dw.isQuiet() {
+		t.Error("Expected the animation and dialogs back once the app leaves fullscreen")
+	}
+
+	dw.SetFullscreenPolicy(FullscreenCorner)
+	dw.followFullscreen(true)
+	if dw.renderer.size > fullscreenCornerSize {
+		t.Errorf("Expected the sprite shrunk to at most %d, got %d", fullscreenCornerSize, dw.renderer.size)
+	}
+	if char.GetSize() != size {
+		t.Error("Shrinking into the corner should leave the character's size alone")
+	}
+
+	// Changing the policy undoes the corner
+	dw.SetFullscreenPolicy(FullscreenHide)
+	if dw.behindFullscreen() {
+		t.Error("Expected a new policy to drop the applied one")
+	}
+	if dw.renderer.size != size {
+		t.Errorf("Expected the sprite back at %d, got %d", size, dw.renderer.size)
+	}
+
+	dw.SetFullscreenPolicy(FullscreenOff)
+	dw.followFullscreen(true)
+	if dw.behindFullscreen() {
+		t.Error("Expected nothing applied with the policy off")
+	}
+	if dw.fullscreen.stop != nil {
+		t.Error("Expected the check stopped with the policy off")
+	}
+}
+
+func TestFullscreenIndex(t *testing.T) {
+	for i, option := range fullscreenOptions {
+		if got := fullscreenIndex(option.name); got != i {
+			t.Errorf("fullscreenIndex(%q) = %d, want %d", option.name, got, i)
+		}
+	}
+	if got := fullscreenIndex(""); got != -1 {
+		t.Errorf("Expected -1 for an unset policy, got %d", got)
+	}
+}
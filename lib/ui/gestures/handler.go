@@ -23,11 +23,13 @@ type GestureHandler struct {
 	onDragStart func()                // Pan start -> drag start
 	onDrag      func(*fyne.DragEvent) // Pan -> drag
 	onDragEnd   func()                // Pan end -> drag end
+	onPinch     func(scale float32)   // Pinch -> resize, scale relative to current size
 
 	// Gesture detection state - protected by mutex
 	mu              sync.RWMutex
 	lastTapTime     time.Time
 	tapCount        int
+	tapSeq          int // Identifies the latest tap so earlier pending taps can yield to it
 	longPressTimer  *time.Timer
 	longPressActive bool
 	isDragging      bool
@@ -91,6 +93,29 @@ func (gh *GestureHandler) SetDragHandlers(onStart func(), onDrag func(*fyne.Drag
 	gh.onDragEnd = onEnd
 }
 
+// SetPinchHandler sets the callback for pinch gestures. The scale is relative
+// to the current size: above 1 grows, below 1 shrinks.
+func (gh *GestureHandler) SetPinchHandler(handler func(scale float32)) {
+	gh.onPinch = handler
+}
+
+// HandlePinch reports a pinch gesture with the given relative scale.
+// Fyne does not deliver multi-touch events, so widgets feed this from
+// two-finger scroll events or platform code that tracks both touches.
+func (gh *GestureHandler) HandlePinch(scale float32) {
+	if !gh.IsGestureTranslationNeeded() || scale <= 0 || scale == 1 {
+		return
+	}
+
+	gh.mu.RLock()
+	onPinch := gh.onPinch
+	gh.mu.RUnlock()
+
+	if onPinch != nil {
+		onPinch(scale)
+	}
+}
+
 // IsGestureTranslationNeeded returns true if the platform requires gesture translation.
 // Desktop platforms return false to maintain existing behavior unchanged.
 func (gh *GestureHandler) IsGestureTranslationNeeded() bool {
@@ -164,6 +189,8 @@ func (gh *GestureHandler) HandleTouchEnd(pos fyne.Position) {
 	}
 
 	// Capture state for the delayed tap handler
+	gh.tapSeq++
+	seq := gh.tapSeq
 	tapCount := gh.tapCount
 	onTap := gh.onTap
 	onDoubleTap := gh.onDoubleTap
@@ -173,6 +200,14 @@ func (gh *GestureHandler) HandleTouchEnd(pos fyne.Position) {
 	go func() {
 		time.Sleep(gh.config.DoubleTapWindow)
 
+		// A later tap took over; only the last tap of a sequence fires
+		gh.mu.RLock()
+		superseded := seq != gh.tapSeq
+		gh.mu.RUnlock()
+		if superseded {
+			return
+		}
+
 		if tapCount >= 2 && onDoubleTap != nil {
 			onDoubleTap()
 		} else if tapCount == 1 && onTap != nil {
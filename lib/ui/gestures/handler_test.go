@@ -371,3 +371,55 @@ func TestAbsFunction(t *testing.T) {
 		}
 	}
 }
+
+// TestDoubleTapSuppressesSingleTap verifies a double tap doesn't also fire a tap
+func TestDoubleTapSuppressesSingleTap(t *testing.T) {
+	handler := NewGestureHandler(&platform.PlatformInfo{
+		OS:           "android",
+		FormFactor:   "mobile",
+		InputMethods: []string{"touch"},
+	}, &GestureConfig{
+		DoubleTapWindow:   100 * time.Millisecond,
+		LongPressDuration: 500 * time.Millisecond,
+		DragThreshold:     10.0,
+	})
+
+	var mu sync.Mutex
+	var taps, doubleTaps int
+	handler.SetTapHandler(func() { mu.Lock(); taps++; mu.Unlock() })
+	handler.SetDoubleTapHandler(func() { mu.Lock(); doubleTaps++; mu.Unlock() })
+
+	pos := fyne.NewPos(10, 10)
+	handler.HandleTouchStart(pos)
+	handler.HandleTouchEnd(pos)
+	time.Sleep(20 * time.Millisecond)
+	handler.HandleTouchStart(pos)
+	handler.HandleTouchEnd(pos)
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if taps != 0 || doubleTaps != 1 {
+		t.Errorf("Expected only one double tap, got %d taps and %d double taps", taps, doubleTaps)
+	}
+}
+
+// TestPinchGesture verifies pinch callbacks on touch platforms only
+func TestPinchGesture(t *testing.T) {
+	touch := NewGestureHandler(&platform.PlatformInfo{OS: "android", FormFactor: "mobile", InputMethods: []string{"touch"}}, nil)
+	var got float32
+	touch.SetPinchHandler(func(scale float32) { got = scale })
+
+	touch.HandlePinch(1.25)
+	if got != 1.25 {
+		t.Errorf("Expected pinch scale 1.25, got %v", got)
+	}
+
+	desktop := NewGestureHandler(&platform.PlatformInfo{OS: "linux", FormFactor: "desktop", InputMethods: []string{"mouse"}}, nil)
+	called := false
+	desktop.SetPinchHandler(func(float32) { called = true })
+	desktop.HandlePinch(1.25)
+	if called {
+		t.Error("Desktop platforms should not translate pinch gestures")
+	}
+}
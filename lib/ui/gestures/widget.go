@@ -96,6 +96,32 @@ func (w *TouchAwareWidget) DragEnd() {
 	}
 }
 
+// Scrolled treats two-finger scrolling on touch platforms as a pinch.
+// Scrolling up grows, scrolling down shrinks.
+func (w *TouchAwareWidget) Scrolled(event *fyne.ScrollEvent) {
+	if w.gestureHandler.IsGestureTranslationNeeded() {
+		w.gestureHandler.HandlePinch(ScrollToPinchScale(event.Scrolled.DY))
+	}
+}
+
+// ScrollToPinchScale converts a vertical scroll delta into a relative scale,
+// limited to half or one and a half times per event
+func ScrollToPinchScale(dy float32) float32 {
+	scale := 1 + dy/200
+	if scale < 0.5 {
+		return 0.5
+	}
+	if scale > 1.5 {
+		return 1.5
+	}
+	return scale
+}
+
+// SetPinchHandler configures pinch-to-resize behavior for the widget
+func (w *TouchAwareWidget) SetPinchHandler(onPinch func(scale float32)) {
+	w.gestureHandler.SetPinchHandler(onPinch)
+}
+
 // SetDragHandlers configures drag behavior for the widget
 func (w *TouchAwareWidget) SetDragHandlers(onStart func(), onDrag func(*fyne.DragEvent), onEnd func()) {
 	w.gestureHandler.SetDragHandlers(onStart, onDrag, onEnd)
@@ -239,3 +239,19 @@ func TestTouchAwareWidgetDragHandlers(t *testing.T) {
 	_ = dragCalled
 	_ = dragEndCalled
 }
+
+// TestTouchAwareWidgetScrollPinch verifies two-finger scrolling resizes on touch platforms
+func TestTouchAwareWidgetScrollPinch(t *testing.T) {
+	widget := NewTouchAwareWidget(&platform.PlatformInfo{OS: "ios", FormFactor: "mobile", InputMethods: []string{"touch"}}, nil, nil, nil)
+
+	var got float32
+	widget.SetPinchHandler(func(scale float32) { got = scale })
+	widget.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.NewDelta(0, 50)})
+
+	if got != 1.25 {
+		t.Errorf("Expected scroll up to grow by 1.25, got %v", got)
+	}
+	if scale := ScrollToPinchScale(-1000); scale != 0.5 {
+		t.Errorf("Expected large scrolls to clamp to 0.5, got %v", scale)
+	}
+}
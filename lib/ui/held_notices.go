@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+)
+
+// maxHeldNotices caps the announcements kept through a long quiet period;
+// the oldest are dropped first
+const maxHeldNotices = 20
+
+// heldNotice is an announcement that arrived while dialogs were silenced
+type heldNotice struct {
+	text         string
+	throughFocus bool // Focus timer announcements may show during a work session
+}
+
+// heldNotices keeps announcements that arrive during do-not-disturb or a
+// focus session. Their sources are drained when they are polled, so
+// dropping them would lose crises, achievements and timer changes for good.
+type heldNotices struct {
+	mu           sync.Mutex
+	notices      []heldNotice
+	achievements []character.AchievementDetails
+	clipboard    *clipboard.Event // Only the latest copy is worth reacting to
+}
+
+// holdNotice queues an announcement
+func (h *heldNotices) holdNotice(text string, throughFocus bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.notices = append(h.notices, heldNotice{text: text, throughFocus: throughFocus})
+	if len(h.notices) > maxHeldNotices {
+		h.notices = h.notices[len(h.notices)-maxHeldNotices:]
+	}
+}
+
+// holdAchievement queues an achievement notification
+func (h *heldNotices) holdAchievement(details character.AchievementDetails) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.achievements = append(h.achievements, details)
+}
+
+// holdClipboard keeps a copy to react to later, replacing any earlier one
+func (h *heldNotices) holdClipboard(event clipboard.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clipboard = &event
+}
+
+// take removes what may be shown now. Nothing is released during
+// do-not-disturb; during a focus session only focus announcements are.
+func (h *heldNotices) take(doNotDisturb, focusing bool) ([]string, []character.AchievementDetails, *clipboard.Event) {
+	if doNotDisturb {
+		return nil, nil, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var texts []string
+	kept := h.notices[:0]
+	for _, notice := range h.notices {
+		if focusing && !notice.throughFocus {
+			kept = append(kept, notice)
+			continue
+		}
+		texts = append(texts, notice.text)
+	}
+	h.notices = kept
+
+	achievements := h.achievements
+	h.achievements = nil
+
+	var event *clipboard.Event
+	if !focusing {
+		event, h.clipboard = h.clipboard, nil
+	}
+	return texts, achievements, event
+}
+
+// isQuiet reports whether dialogs are silenced by do-not-disturb, a focus
+// session or a fullscreen app
+func (dw *DesktopWindow) isQuiet() bool {
+	return dw.character != nil && (dw.character.IsDoNotDisturb() || dw.character.IsFocusing() || dw.behindFullscreen())
+}
+
+// showNotice shows an announcement now, or holds it until the quiet period ends
+func (dw *DesktopWindow) showNotice(text string) {
+	if dw.isQuiet() {
+		dw.held.holdNotice(text, false)
+		return
+	}
+	dw.displayDialog(text)
+}
+
+// deliverHeldNotices shows what was held back once the quiet period allows it
+func (dw *DesktopWindow) deliverHeldNotices() {
+	if dw.character == nil {
+		return
+	}
+
+	// A fullscreen app holds everything back, like do-not-disturb
+	texts, achievements, event := dw.held.take(dw.character.IsDoNotDisturb() || dw.behindFullscreen(), dw.character.IsFocusing())
+	for _, details := range achievements {
+		dw.ShowAchievementNotification(details)
+	}
+	if len(texts) > 0 {
+		go func() {
+			for i, text := range texts {
+				if i > 0 {
+					time.Sleep(digestBubbleInterval)
+				}
+				dw.displayDialog(text)
+			}
+		}()
+	}
+	if event != nil {
+		dw.handleClipboardEvent(*event)
+	}
+}
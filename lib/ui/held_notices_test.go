@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+)
+
+func TestHeldNoticesWaitForDoNotDisturb(t *testing.T) {
+	var held heldNotices
+	held.holdNotice("💔 Something feels off", false)
+	held.holdNotice("☕ Break time!", true)
+	held.holdAchievement(character.AchievementDetails{Name: "Caretaker"})
+	held.holdClipboard(clipboard.Event{Kind: clipboard.KindURL, URL: "https://old.example"})
+	held.holdClipboard(clipboard.Event{Kind: clipboard.KindURL, URL: "https://new.example"})
+
+	if texts, achievements, event := held.take(true, false); texts != nil || achievements != nil || event != nil {
+		t.Fatalf("Expected nothing during do-not-disturb, got %v %v %v", texts, achievements, event)
+	}
+
+	// A work session only lets focus announcements through
+	texts, achievements, event := held.take(false, true)
+	if !reflect.DeepEqual(texts, []string{"☕ Break time!"}) || event != nil {
+		t.Errorf("Expected only the focus announcement, got %v %v", texts, event)
+	}
+	if len(achievements) != 1 || achievements[0].Name != "Caretaker" {
+		t.Errorf("Expected the held achievement, got %v", achievements)
+	}
+
+	texts, _, event = held.take(false, false)
+	if !reflect.DeepEqual(texts, []string{"💔 Something feels off"}) {
+		t.Errorf("Expected the crisis notice after the session, got %v", texts)
+	}
+	if event == nil || event.URL != "https://new.example" {
+		t.Errorf("Expected only the latest copy, got %+v", event)
+	}
+
+	if texts, achievements, event := held.take(false, false); len(texts) != 0 || len(achievements) != 0 || event != nil {
+		t.Errorf("Expected notices to be delivered once, got %v %v %v", texts, achievements, event)
+	}
+}
+
+func TestHeldNoticesCapped(t *testing.T) {
+	var held heldNotices
+	for i := 0; i < maxHeldNotices+5; i++ {
+		held.holdNotice(string(rune('a'+i)), false)
+	}
+	texts, _, _ := held.take(false, false)
+	if len(texts) != maxHeldNotices || texts[0] != "f" {
+		t.Errorf("Expected the newest %d notices, got %v", maxHeldNotices, texts)
+	}
+}
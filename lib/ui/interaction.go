@@ -7,6 +7,20 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// Default speech bubble look, used for anything a card's bubbleStyle leaves out
+var (
+	defaultBubbleBackground = color.RGBA{R: 255, G: 255, B: 255, A: 230}
+	defaultBubbleBorder     = color.RGBA{R: 100, G: 100, B: 100, A: 255}
+)
+
+const (
+	defaultBubbleTextSize = 14 // Fyne's default text size, which the size estimates assume
+	bubbleTailWidth       = 16
+	bubbleTailHeight      = 10
 )
 
 // DialogBubble displays speech bubbles for character interactions
@@ -18,6 +32,12 @@ type DialogBubble struct {
 	content     *fyne.Container
 	visible     bool
 	currentText string
+
+	// Card styling: the text theme, tail triangle and its position
+	textTheme *bubbleTheme
+	textHost  *container.ThemeOverride
+	tail      *canvas.Raster
+	tailSide  string
 }
 
 // NewDialogBubble creates a new dialog bubble widget
@@ -25,9 +45,11 @@ func NewDialogBubble() *DialogBubble {
 	bubble := &DialogBubble{}
 
 	// Create background rectangle with rounded corners effect
-	bubble.background = canvas.NewRectangle(color.RGBA{R: 255, G: 255, B: 255, A: 230})
-	bubble.background.StrokeColor = color.RGBA{R: 100, G: 100, B: 100, A: 255}
+	bubble.background = canvas.NewRectangle(defaultBubbleBackground)
+	bubble.background.StrokeColor = defaultBubbleBorder
 	bubble.background.StrokeWidth = 1
+	bubble.tail = canvas.NewRasterWithPixels(bubble.tailPixel)
+	bubble.tail.Hide()
 
 	// Create text widget for dialog content
 	bubble.text = widget.NewRichText()
@@ -44,8 +66,14 @@ func NewDialogBubble() *DialogBubble {
 		},
 	}
 
-	// Create container with background and text
-	bubble.content = container.NewBorder(nil, nil, nil, nil, bubble.background, bubble.text)
+	// Text is themed separately so cards can change its color and size
+	// without touching the rest of the app
+	bubble.textTheme = &bubbleTheme{}
+	bubble.textHost = container.NewThemeOverride(bubble.text, bubble.textTheme)
+
+	// Create container with background and text, laid out by applyBubbleLayout
+	bubble.content = container.NewWithoutLayout(bubble.background, bubble.tail, bubble.textHost)
+	bubble.SetStyle(nil)
 
 	// Initially hidden
 	bubble.visible = false
@@ -113,7 +141,12 @@ func (b *DialogBubble) updateSize(text string) {
 
 // calculateBubbleDimensions computes the bubble width and height based on text content
 func (b *DialogBubble) calculateBubbleDimensions(text string) (float32, float32) {
-	textLen := len(text)
+	// Larger fonts fill the bubble faster
+	scale := float32(1)
+	if b.textTheme != nil && b.textTheme.textSize > 0 {
+		scale = b.textTheme.textSize / defaultBubbleTextSize
+	}
+	textLen := int(float32(len(text)) * scale)
 
 	// Base size calculations
 	minWidth := float32(100)
@@ -127,9 +160,9 @@ func (b *DialogBubble) calculateBubbleDimensions(text string) (float32, float32)
 
 	// Calculate height based on estimated line wrapping
 	lines := 1 + textLen/30 // Rough estimate of lines needed
-	height := minHeight + float32(lines-1)*20
-	if height > 150 { // Max height
-		height = 150
+	height := minHeight + float32(lines-1)*20*scale
+	if maxHeight := 150 * max(scale, 1); height > maxHeight {
+		height = maxHeight
 	}
 
 	return width, height
@@ -137,8 +170,8 @@ func (b *DialogBubble) calculateBubbleDimensions(text string) (float32, float32)
 
 // calculateBubblePosition determines the bubble position relative to the character
 func (b *DialogBubble) calculateBubblePosition(height float32) (float32, float32) {
-	bubbleX := float32(10)           // Small offset from character
-	bubbleY := float32(-height - 10) // Above character with margin
+	bubbleX := float32(10)                         // Small offset from character
+	bubbleY := float32(-height - bubbleTailHeight) // Above character, leaving room for the tail
 	return bubbleX, bubbleY
 }
 
@@ -153,14 +186,89 @@ func (b *DialogBubble) applyBubbleLayout(width, height, bubbleX, bubbleY float32
 
 	// Update text area with padding
 	textPadding := float32(8)
-	b.text.Resize(fyne.NewSize(width-textPadding*2, height-textPadding*2))
-	b.text.Move(fyne.NewPos(textPadding, textPadding))
+	b.textHost.Resize(fyne.NewSize(width-textPadding*2, height-textPadding*2))
+	b.textHost.Move(fyne.NewPos(textPadding, textPadding))
+
+	// Hang the tail off the bottom edge, overlapping the outline
+	tailX := float32(16)
+	switch b.tailSide {
+	case character.BubbleTailCenter:
+		tailX = (width - bubbleTailWidth) / 2
+	case character.BubbleTailRight:
+		tailX = width - bubbleTailWidth - 16
+	}
+	b.tail.Resize(fyne.NewSize(bubbleTailWidth, bubbleTailHeight))
+	b.tail.Move(fyne.NewPos(tailX, height-b.background.StrokeWidth))
+}
+
+// SetStyle applies a card's bubble style; nil restores the default look.
+// Colors are validated with the card, so unparsable ones fall back too.
+func (b *DialogBubble) SetStyle(style *character.BubbleStyle) {
+	if style == nil {
+		style = &character.BubbleStyle{}
+	}
+
+	b.background.FillColor = styleColor(style.Background, defaultBubbleBackground)
+	b.background.StrokeColor = styleColor(style.Border, defaultBubbleBorder)
+	b.background.StrokeWidth = 1
+	if style.BorderWidth > 0 {
+		b.background.StrokeWidth = style.BorderWidth
+	}
+	b.background.CornerRadius = style.CornerRadius
+
+	// Default text contrasts with the bubble rather than following the app
+	// theme, which would put light text on the white bubble in dark mode
+	textFallback := color.RGBA{R: 20, G: 20, B: 20, A: 255}
+	if isDarkColor(b.background.FillColor) {
+		textFallback = color.RGBA{R: 245, G: 245, B: 245, A: 255}
+	}
+	b.textTheme.textColor = styleColor(style.Text, textFallback)
+	b.textTheme.textSize = style.FontSize
+
+	b.tailSide = style.Tail
+	if b.tailSide == "" || b.tailSide == character.BubbleTailNone {
+		b.tail.Hide()
+	} else {
+		b.tail.Show()
+	}
+
+	b.background.Refresh()
+	b.textHost.Refresh()
+	b.tail.Refresh()
+	if b.currentText != "" {
+		b.updateSize(b.currentText)
+	}
+}
+
+// styleColor parses a card color, or returns fallback when unset or invalid
+func styleColor(value string, fallback color.Color) color.Color {
+	if value == "" {
+		return fallback
+	}
+	c, err := character.ParseHexColor(value)
+	if err != nil {
+		return fallback
+	}
+	return c
+}
+
+// tailPixel draws the tail as a downward triangle in the bubble's fill color
+func (b *DialogBubble) tailPixel(x, y, w, h int) color.Color {
+	if w == 0 || h == 0 {
+		return color.Transparent
+	}
+	halfWidth := float32(w) / 2 * (1 - float32(y)/float32(h))
+	if dx := float32(x) + 0.5 - float32(w)/2; dx >= -halfWidth && dx <= halfWidth {
+		return b.background.FillColor
+	}
+	return color.Transparent
 }
 
 // SetBackgroundColor updates the bubble background color
 func (b *DialogBubble) SetBackgroundColor(c color.Color) {
 	b.background.FillColor = c
 	b.background.Refresh()
+	b.tail.Refresh()
 }
 
 // SetTextColor updates the bubble text color
@@ -0,0 +1,80 @@
+package ui
+
+// interaction_preview.go describes what a game interaction would do before
+// the user commits to it. Menu entries show the text on Alt+hover or a long
+// press; nothing about the character changes.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// previewDuration is how long a preview stays in the dialog bubble
+const previewDuration = 4 * time.Second
+
+// interactionPreview returns a menu Preview for the named interaction
+func (dw *DesktopWindow) interactionPreview(interactionType string) func() string {
+	return func() string {
+		preview, ok := dw.character.PreviewInteraction(interactionType)
+		if !ok {
+			return ""
+		}
+		return formatInteractionPreview(preview)
+	}
+}
+
+// showPreview shows preview text in the dialog bubble. Unlike showDialog it
+// isn't spoken and isn't silenced by do-not-disturb, since the user asked.
+func (dw *DesktopWindow) showPreview(text string) {
+	dw.dialog.ShowWithText(text)
+	go func() {
+		time.Sleep(previewDuration)
+		dw.dialog.Hide()
+	}()
+}
+
+// formatInteractionPreview renders a preview as a few short lines
+func formatInteractionPreview(p character.InteractionPreview) string {
+	lines := []string{"Preview: " + strings.Title(strings.ReplaceAll(p.Interaction, "_", " "))}
+
+	names := make([]string, 0, len(p.Stats))
+	for name := range p.Stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stat := p.Stats[name]
+		lines = append(lines, fmt.Sprintf("%s %+.0f (%.0f → %.0f)", name, stat.Delta(), stat.Current, stat.Projected))
+	}
+	if p.Coins != 0 {
+		lines = append(lines, fmt.Sprintf("coins %+d", p.Coins))
+	}
+	if p.Modifier != 1 {
+		lines = append(lines, fmt.Sprintf("personality ×%.2f", p.Modifier))
+	}
+
+	if p.CooldownRemaining > 0 {
+		lines = append(lines, fmt.Sprintf("⏳ ready in %s", p.CooldownRemaining.Round(time.Second)))
+	}
+	for _, s := range p.Shortfalls {
+		switch {
+		case s.Current < 0:
+			lines = append(lines, fmt.Sprintf("Needs %s", s.Stat))
+		case s.Bound == "max":
+			lines = append(lines, fmt.Sprintf("Needs %s ≤ %.0f (now %.0f)", s.Stat, s.Limit, s.Current))
+		default:
+			lines = append(lines, fmt.Sprintf("Needs %s ≥ %.0f (now %.0f)", s.Stat, s.Limit, s.Current))
+		}
+	}
+	if p.ConditionUnmet {
+		lines = append(lines, "Not available right now")
+	}
+	if p.Available() && len(p.Stats) == 0 && p.Coins == 0 {
+		lines = append(lines, "No stat changes")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestFormatInteractionPreview(t *testing.T) {
+	preview := character.InteractionPreview{
+		Interaction: "feed",
+		Modifier:    1,
+		Stats: map[string]character.StatProjection{
+			"hunger":    {Current: 85, Projected: 100},
+			"happiness": {Current: 50, Projected: 55},
+		},
+		CooldownRemaining: 12 * time.Second,
+		Shortfalls: []character.RequirementShortfall{
+			{Stat: "hunger", Bound: "max", Limit: 80, Current: 85},
+		},
+	}
+
+	text := formatInteractionPreview(preview)
+	for _, want := range []string{
+		"Preview: Feed",
+		"happiness +5 (50 → 55)",
+		"hunger +15 (85 → 100)",
+		"⏳ ready in 12s",
+		"Needs hunger ≤ 80 (now 85)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("preview %q missing %q", text, want)
+		}
+	}
+	if strings.Index(text, "happiness") > strings.Index(text, "hunger +15") {
+		t.Errorf("stats should be sorted by name: %q", text)
+	}
+	if strings.Contains(text, "personality") {
+		t.Errorf("modifier of 1 should not be shown: %q", text)
+	}
+
+	preview = character.InteractionPreview{Interaction: "compliment", Modifier: 1.25, ConditionUnmet: true}
+	text = formatInteractionPreview(preview)
+	if !strings.Contains(text, "personality ×1.25") || !strings.Contains(text, "Not available right now") {
+		t.Errorf("unexpected preview %q", text)
+	}
+}
+
+func TestContextMenuPreviewButton(t *testing.T) {
+	test.NewApp()
+
+	var shown []string
+	menu := NewContextMenu()
+	menu.OnPreview = func(text string) { shown = append(shown, text) }
+
+	called := false
+	menu.SetMenuItems([]ContextMenuItem{
+		{Text: "Feed", Callback: func() { called = true }, Preview: func() string { return "feed preview" }},
+		{Text: "Plain", Callback: func() {}},
+	})
+
+	button, ok := menu.objects[0].(*previewButton)
+	if !ok {
+		t.Fatalf("item with Preview should use a preview button, got %T", menu.objects[0])
+	}
+	if _, ok := menu.objects[1].(*previewButton); ok {
+		t.Error("item without Preview should use a plain button")
+	}
+
+	button.MouseIn(&desktop.MouseEvent{})
+	if len(shown) != 0 {
+		t.Fatalf("hover without Alt should not preview, got %v", shown)
+	}
+	alt := &desktop.MouseEvent{Modifier: fyne.KeyModifierAlt}
+	button.MouseMoved(alt)
+	button.MouseMoved(alt)
+	if len(shown) != 1 || shown[0] != "feed preview" {
+		t.Fatalf("Alt+hover should preview once, got %v", shown)
+	}
+	button.MouseOut()
+	button.MouseIn(alt)
+	if len(shown) != 2 {
+		t.Errorf("Alt+hover after leaving should preview again, got %v", shown)
+	}
+
+	button.TappedSecondary(&fyne.PointEvent{})
+	if len(shown) != 3 {
+		t.Errorf("long press should preview, got %v", shown)
+	}
+	if called {
+		t.Error("previewing must not run the item's callback")
+	}
+
+	test.Tap(button)
+	if !called {
+		t.Error("tapping should still run the callback")
+	}
+}
+
+func TestGameMenuItemsHavePreviews(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	char := createRomanceCharacterWithStats(t)
+	dw := NewDesktopWindow(app, char, false, nil, true, false, nil, false, false, false)
+
+	previews := 0
+	for _, item := range dw.buildGameModeMenuItems() {
+		if item.Text == "Feed" || item.Text == "Play" {
+			if item.Preview == nil {
+				t.Errorf("%s should have a preview", item.Text)
+			}
+			previews++
+		}
+	}
+	if previews != 2 {
+		t.Errorf("expected Feed and Play items, got %d", previews)
+	}
+}
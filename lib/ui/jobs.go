@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// buildJobMenuItem creates the jobs entry for the game menu.
+// Shows progress instead when a job is already running.
+func (dw *DesktopWindow) buildJobMenuItem() (ContextMenuItem, bool) {
+	if len(dw.character.GetJobs()) == 0 {
+		return ContextMenuItem{}, false
+	}
+
+	if active, remaining := dw.character.GetActiveJob(); active != nil {
+		return ContextMenuItem{
+			Text: fmt.Sprintf("💼 Working (%s left)", formatJobDuration(remaining)),
+			Callback: func() {
+				dw.showJobProgress()
+			},
+		}, true
+	}
+
+	return ContextMenuItem{
+		Text: "💼 Jobs",
+		Callback: func() {
+			dw.showJobList()
+		},
+	}, true
+}
+
+// showJobList displays the card's jobs with a start button for each available one
+func (dw *DesktopWindow) showJobList() {
+	jobs := dw.character.GetJobs()
+
+	titleLabel := widget.NewLabel("Jobs")
+	titleLabel.Alignment = fyne.TextAlignCenter
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rows := []fyne.CanvasObject{titleLabel}
+	for _, job := range jobs {
+		jobID := job.ID
+		button := widget.NewButton(fmt.Sprintf("%s (%s)", job.Name, formatJobDuration(job.Duration)), func() {
+			response, err := dw.character.StartJob(jobID)
+			if err != nil {
+				dw.showDialog(err.Error())
+				return
+			}
+			dw.showDialog(response)
+		})
+		if !job.Available {
+			button.Disable()
+		}
+		rows = append(rows, button)
+
+		if job.Description != "" {
+			description := widget.NewLabel(job.Description)
+			description.Wrapping = fyne.TextWrapWord
+			rows = append(rows, description)
+		}
+	}
+
+	content := container.NewVBox(rows...)
+	content.Resize(fyne.NewSize(260, float32(40+60*len(jobs))))
+	dw.showModalContent(content)
+}
+
+// showJobProgress shows the running job with a cancel button
+func (dw *DesktopWindow) showJobProgress() {
+	active, remaining := dw.character.GetActiveJob()
+	if active == nil {
+		return
+	}
+
+	name := active.ID
+	if job, exists := dw.character.GetCard().Jobs[active.ID]; exists {
+		name = job.GetDisplayName(active.ID)
+	}
+
+	total := active.EndsAt.Sub(active.StartedAt)
+	progress := widget.NewProgressBar()
+	if total > 0 {
+		progress.SetValue(1 - float64(remaining)/float64(total))
+	}
+
+	cancelButton := widget.NewButton("Cancel job", func() {
+		if dw.character.CancelJob() {
+			dw.showDialog(fmt.Sprintf("Stopped %s", name))
+		}
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("💼 %s - %s left", name, formatJobDuration(remaining))),
+		progress,
+		cancelButton,
+	)
+	content.Resize(fyne.NewSize(240, 120))
+	dw.showModalContent(content)
+}
+
+// checkForCompletedJobs announces jobs that finished since the last frame
+func (dw *DesktopWindow) checkForCompletedJobs() {
+	if dw.character == nil {
+		return
+	}
+
+	for _, result := range dw.character.GetCompletedJobs() {
+		text := result.Response
+		if result.Item != "" {
+			text += fmt.Sprintf("\n🎁 Received: %s", result.Item)
+		}
+		dw.showNotice(text)
+	}
+}
+
+// formatJobDuration renders durations like "1h 5m", "4m" or "30s"
+func formatJobDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestFormatJobDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{30 * time.Second, "30s"},
+		{4 * time.Minute, "4m"},
+		{65 * time.Minute, "1h 5m"},
+		{1500 * time.Millisecond, "2s"},
+	}
+
+	for _, tt := range tests {
+		if got := formatJobDuration(tt.duration); got != tt.expected {
+			t.Errorf("formatJobDuration(%v) = %q, want %q", tt.duration, got, tt.expected)
+		}
+	}
+}
+
+// createTestCharacterWithJob builds a character whose card offers one job
+func createTestCharacterWithJob(t *testing.T, tmpDir string) *character.Character {
+	createTestAnimationFiles(t, tmpDir)
+
+	cardContent := `{
+		"name": "Worker",
+		"description": "A test character with a job",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "happy": "happy.gif"},
+		"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 128},
+		"stats": {
+			"hunger": {"initial": 100, "max": 100, "degradationRate": 0, "criticalThreshold": 20},
+			"happiness": {"initial": 50, "max": 100, "degradationRate": 0, "criticalThreshold": 15}
+		},
+		"gameRules": {"statsDecayInterval": 60, "autoSaveInterval": 300},
+		"jobs": {
+			"deliver": {
+				"name": "Deliver mail",
+				"duration": 60,
+				"cost": {"hunger": -20},
+				"rewards": {"happiness": 30},
+				"completeResponses": ["All delivered!"]
+			}
+		}
+	}`
+
+	cardPath := filepath.Join(tmpDir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(cardContent), 0o644); err != nil {
+		t.Fatalf("Failed to write test character card: %v", err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Failed to load test character card: %v", err)
+	}
+	char, err := character.New(card, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	return char
+}
+
+func TestJobMenuLifecycle(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithJob(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	item, ok := dw.buildJobMenuItem()
+	if !ok || item.Text != "💼 Jobs" {
+		t.Fatalf("Expected the jobs menu item, got %q (%v)", item.Text, ok)
+	}
+
+	if _, err := char.StartJob("deliver"); err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+	if hunger := char.GetGameState().GetStat("hunger"); hunger != 80 {
+		t.Errorf("Expected the job cost to be paid up front, hunger = %f", hunger)
+	}
+	if item, _ := dw.buildJobMenuItem(); !strings.HasPrefix(item.Text, "💼 Working") {
+		t.Errorf("Expected progress in the menu while working, got %q", item.Text)
+	}
+	if _, err := char.StartJob("deliver"); err == nil {
+		t.Error("A second job should be refused while one is running")
+	}
+
+	// Let the timer run out and the next frame finish the job
+	past := time.Now().Add(-time.Minute)
+	char.GetGameState().SetActiveJob(&character.ActiveJob{ID: "deliver", StartedAt: past.Add(-time.Minute), EndsAt: past})
+	char.Update()
+
+	if happiness := char.GetGameState().GetStat("happiness"); happiness != 80 {
+		t.Errorf("Expected completion to pay out the reward, happiness = %f", happiness)
+	}
+
+	dw.checkForCompletedJobs()
+	if results := char.GetCompletedJobs(); len(results) != 0 {
+		t.Errorf("Completed jobs should be announced once, %d left", len(results))
+	}
+	if item, _ := dw.buildJobMenuItem(); item.Text != "💼 Jobs" {
+		t.Errorf("Expected the jobs list again after completion, got %q", item.Text)
+	}
+}
+
+func TestCompletedJobHeldDuringDoNotDisturb(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithJob(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if _, err := char.StartJob("deliver"); err != nil {
+		t.Fatalf("StartJob failed: %v", err)
+	}
+	past := time.Now().Add(-time.Minute)
+	char.GetGameState().SetActiveJob(&character.ActiveJob{ID: "deliver", StartedAt: past.Add(-time.Minute), EndsAt: past})
+	char.Update()
+
+	char.SetDoNotDisturb(true)
+	dw.checkForCompletedJobs()
+
+	texts, _, _ := dw.held.take(false, false)
+	if len(texts) != 1 || texts[0] != "All delivered!" {
+		t.Errorf("Expected the completion to be held, got %v", texts)
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// journalIcons mark each kind of journal entry
+var journalIcons = map[string]string{
+	character.JournalEvent:       "🎲",
+	character.JournalAchievement: "🏆",
+	character.JournalLevelUp:     "💖",
+	character.JournalBattle:      "⚔️",
+	character.JournalGift:        "🎁",
+}
+
+// journalIcon returns the icon for an entry kind, a bullet for unknown kinds
+func journalIcon(kind string) string {
+	if icon, ok := journalIcons[kind]; ok {
+		return icon
+	}
+	return "•"
+}
+
+// formatJournalTime shows today's and yesterday's times by day name and
+// older ones by date
+func formatJournalTime(at, now time.Time) string {
+	at = at.In(now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch {
+	case !at.Before(today):
+		return "Today " + at.Format("15:04")
+	case !at.Before(today.AddDate(0, 0, -1)):
+		return "Yesterday " + at.Format("15:04")
+	case at.Year() == now.Year():
+		return at.Format("Jan 2 15:04")
+	default:
+		return at.Format("Jan 2, 2006")
+	}
+}
+
+// formatJournalEntry renders one journal line
+func formatJournalEntry(entry character.JournalEntry, now time.Time) string {
+	text := fmt.Sprintf("%s %s · %s", journalIcon(entry.Kind), formatJournalTime(entry.Time, now), entry.Title)
+	if entry.Detail != "" {
+		text += " - " + entry.Detail
+	}
+	return text
+}
+
+// buildJournalMenuItem creates the journal entry for characters with game
+// features
+func (dw *DesktopWindow) buildJournalMenuItem() (ContextMenuItem, bool) {
+	if dw.character.GetGameState() == nil {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{Text: "📔 Journal", Callback: dw.showJournal}, true
+}
+
+// showJournal lists the character's recent happenings, newest first
+func (dw *DesktopWindow) showJournal() {
+	window := fyne.CurrentApp().NewWindow(fmt.Sprintf("%s's Journal", dw.character.GetName()))
+
+	journal := dw.character.GetJournal()
+	now := time.Now()
+	var content fyne.CanvasObject
+	if len(journal) == 0 {
+		empty := widget.NewLabel("Nothing to write about yet. Random events, achievements, level-ups, battles and gifts will appear here.")
+		empty.Wrapping = fyne.TextWrapWord
+		content = empty
+	} else {
+		content = widget.NewList(
+			func() int { return len(journal) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(id widget.ListItemID, item fyne.CanvasObject) {
+				item.(*widget.Label).SetText(formatJournalEntry(journal[id], now))
+			},
+		)
+	}
+
+	window.SetContent(container.NewBorder(
+		widget.NewLabel(fmt.Sprintf("%d entries", len(journal))), nil, nil, nil,
+		content,
+	))
+	window.Resize(fyne.NewSize(420, 360))
+	window.Show()
+}
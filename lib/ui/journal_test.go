@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestFormatJournalTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2026, 3, 10, 9, 5, 0, 0, time.UTC), "Today 09:05"},
+		{time.Date(2026, 3, 9, 23, 59, 0, 0, time.UTC), "Yesterday 23:59"},
+		{time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC), "Mar 8 23:59"},
+		{time.Date(2025, 12, 31, 8, 0, 0, 0, time.UTC), "Dec 31, 2025"},
+	}
+	for _, tt := range tests {
+		if got := formatJournalTime(tt.at, now); got != tt.want {
+			t.Errorf("formatJournalTime(%v) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestFormatJournalEntry(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	entry := character.JournalEntry{Time: now, Kind: character.JournalGift, Title: "Received Flowers", Detail: "For you"}
+	if got := formatJournalEntry(entry, now); got != "🎁 Today 15:00 · Received Flowers - For you" {
+		t.Errorf("Unexpected journal line %q", got)
+	}
+
+	entry = character.JournalEntry{Time: now, Kind: "mystery", Title: "Something"}
+	if got := formatJournalEntry(entry, now); got != "• Today 15:00 · Something" {
+		t.Errorf("Expected a bullet for unknown kinds, got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package ui
+
+// latency.go shows how good the connection to each peer is and keeps
+// battles off laggy connections. The network overlay's latency monitor
+// pings every peer; the peer list shows the round trip, packet loss and a
+// colored dot, and battles with peers slower than the limit picked in the
+// settings window are refused in both directions.
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// defaultBattleLatency is the battle ping limit when the setting is unset
+const defaultBattleLatency = 300
+
+// battleLatencyOptions are the battle ping limits offered in the settings
+// window, in milliseconds; -1 allows any ping
+var battleLatencyOptions = []struct {
+	label  string
+	millis int
+}{
+	{"150 ms", 150},
+	{"300 ms", 300},
+	{"500 ms", 500},
+	{"No limit", -1},
+}
+
+// battleLatencyLimit returns the highest ping a battle may start with, 0
+// when there is no limit
+func (dw *DesktopWindow) battleLatencyLimit() time.Duration {
+	dw.settingsMu.Lock()
+	millis := dw.settings.BattleLatency
+	dw.settingsMu.Unlock()
+
+	if millis == 0 {
+		millis = defaultBattleLatency
+	}
+	if millis < 0 {
+		return 0
+	}
+	return time.Duration(millis) * time.Millisecond
+}
+
+// refuseLaggyBattle tells the user and returns true when the connection to
+// peerID is too slow for a battle
+func (dw *DesktopWindow) refuseLaggyBattle(peerID string) bool {
+	if dw.networkOverlay == nil || dw.networkOverlay.GetLatencyMonitor() == nil {
+		return false
+	}
+	err := dw.networkOverlay.GetLatencyMonitor().CheckBattle(peerID, dw.battleLatencyLimit())
+	if err == nil {
+		return false
+	}
+	dw.showDialog(fmt.Sprintf("Battle refused: %v. Battles need a steadier connection.", err))
+	return true
+}
+
+// stopLatencyChecks stops pinging peers
+func (dw *DesktopWindow) stopLatencyChecks() {
+	if dw.networkOverlay != nil && dw.networkOverlay.GetLatencyMonitor() != nil {
+		dw.networkOverlay.GetLatencyMonitor().Stop()
+	}
+}
+
+// GetLatencyMonitor returns the peer ping measurements, nil until network
+// events are registered
+func (no *NetworkOverlay) GetLatencyMonitor() *network.LatencyMonitor {
+	return no.latency
+}
+
+// peerQuality returns the color of a peer's quality dot and its ping and
+// loss for the peer list
+func (no *NetworkOverlay) peerQuality(peerID string) (color.Color, string) {
+	if no.latency == nil {
+		return theme.Color(theme.ColorNameDisabled), ""
+	}
+
+	latency := no.latency.Latency(peerID)
+	text := fmt.Sprintf(" · %d ms", latency.Latency.Milliseconds())
+	if latency.Loss > 0 {
+		text += fmt.Sprintf(", %.0f%% loss", latency.Loss*100)
+	}
+
+	switch latency.Quality {
+	case network.QualityGood:
+		return theme.Color(theme.ColorNameSuccess), text
+	case network.QualityFair:
+		return theme.Color(theme.ColorNameWarning), text
+	case network.QualityPoor:
+		return theme.Color(theme.ColorNameError), text
+	default:
+		return theme.Color(theme.ColorNameDisabled), ""
+	}
+}
+
+// battleLatencyIndex returns the option for a limit in milliseconds: the
+// default when unset, No limit when negative and the closest one otherwise
+func battleLatencyIndex(millis int) int {
+	if millis < 0 {
+		return len(battleLatencyOptions) - 1
+	}
+	if millis == 0 {
+		millis = defaultBattleLatency
+	}
+	best := 0
+	for i, option := range battleLatencyOptions {
+		if option.millis > 0 && math.Abs(float64(option.millis-millis)) < math.Abs(float64(battleLatencyOptions[best].millis-millis)) {
+			best = i
+		}
+	}
+	return best
+}
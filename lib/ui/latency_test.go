@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// echoNetworkManager answers every ping itself after delay
+type echoNetworkManager struct {
+	mockNetworkManager
+	delay time.Duration
+}
+
+func (m *echoNetworkManager) SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error {
+	if msgType == network.MessageTypePing {
+		time.Sleep(m.delay)
+		return m.handlers[network.MessageTypePong](network.Message{Type: network.MessageTypePong, Payload: payload}, &network.Peer{ID: targetPeerID})
+	}
+	return nil
+}
+
+func TestBattleLatencyLimit(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterForWidget(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, true, true, nil, false, false, false)
+	defer dw.Close()
+
+	nm := &echoNetworkManager{mockNetworkManager: mockNetworkManager{peers: []network.Peer{{ID: "laggy", Capabilities: []string{network.CapabilityPing}}}}, delay: 20 * time.Millisecond}
+	dw.networkOverlay = NewNetworkOverlay(nm)
+	dw.networkOverlay.latency = network.NewLatencyMonitor(nm)
+	dw.networkOverlay.latency.PingPeers()
+
+	dot, text := dw.networkOverlay.peerQuality("laggy")
+	if dot != theme.Color(theme.ColorNameSuccess) || !strings.Contains(text, "ms") {
+		t.Errorf("Expected a good connection with its ping, got %v %q", dot, text)
+	}
+	if dw.refuseLaggyBattle("laggy") {
+		t.Error("Expected the default limit to allow a 20ms peer")
+	}
+
+	dw.SetSettings(config.Settings{BattleLatency: 5}, "")
+	accepted, answered := true, false
+	dw.ShowBattleInvitationDialog("laggy", func(ok bool) { accepted, answered = ok, true })
+	if !answered || accepted {
+		t.Error("Expected an invitation from a peer over the limit to be declined")
+	}
+
+	dw.SetSettings(config.Settings{BattleLatency: -1}, "")
+	if dw.refuseLaggyBattle("laggy") {
+		t.Error("Expected no limit to allow any ping")
+	}
+}
+
+func TestBattleLatencyIndex(t *testing.T) {
+	tests := []struct {
+		millis int
+		want   string
+	}{
+		{0, "300 ms"},
+		{150, "150 ms"},
+		{450, "500 ms"},
+		{-1, "No limit"},
+	}
+	for _, tt := range tests {
+		if got := battleLatencyOptions[battleLatencyIndex(tt.millis)].label; got != tt.want {
+			t.Errorf("battleLatencyIndex(%d) picks %q, want %q", tt.millis, got, tt.want)
+		}
+	}
+}
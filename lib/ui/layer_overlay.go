@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+const (
+	layerOverlayNamespace   = "desktop-companion" // For compositor rules, e.g. Hyprland's layerrule
+	layerOverlayMargin      = 64                  // Where a character without a saved position appears
+	overlayDragThreshold    = 10                  // Pixels, as for touch drags
+	overlayDoubleClickDelay = 500 * time.Millisecond
+)
+
+// layerOverlayState is the Wayland layer-shell surface the sprite is drawn
+// on when the compositor supports it. Unlike a regular window it stays
+// above everything, and only the sprite's pixels take clicks.
+type layerOverlayState struct {
+	mu        sync.Mutex
+	overlay   native.Overlay // nil when the regular window shows the character
+	lastFrame image.Image
+	lastSize  int
+	lastMode  string // Click-through mode of the input region
+	blanked   bool   // The regular window shows the character for a menu or dialog
+	x, y      int    // Overlay position on its output
+}
+
+// openLayerOverlay moves the character onto an overlay surface. Returns
+// false when the session has none, and the regular window is kept.
+func (dw *DesktopWindow) openLayerOverlay() bool {
+	overlay, err := native.OpenOverlay(layerOverlayNamespace)
+	if err != nil {
+		// Unsupported is the normal case outside Wayland sessions
+		level := logrus.WarnLevel
+		if errors.Is(err, native.ErrOverlayUnsupported) {
+			level = logrus.DebugLevel
+		}
+		logrus.WithError(err).Log(level, "Wayland overlay unavailable, using a regular window")
+		return false
+	}
+
+	fx, fy := dw.character.GetPosition()
+	x, y := int(fx), int(fy)
+	if x == 0 && y == 0 {
+		x, y = layerOverlayMargin, layerOverlayMargin
+	}
+	if err := overlay.Move(x, y); err != nil {
+		logrus.WithError(err).Debug("Failed to position Wayland overlay")
+	}
+
+	dw.layer.mu.Lock()
+	dw.layer.overlay = overlay
+	dw.layer.x, dw.layer.y = x, y
+	// The regular window stays up until the first frame reaches the overlay
+	dw.layer.blanked = true
+	dw.layer.mu.Unlock()
+
+	go dw.handleOverlayInput(overlay)
+	logrus.Info("Character shown on a Wayland overlay surface")
+	return true
+}
+
+// presentLayerOverlay keeps the overlay showing the current sprite frame,
+// clickable as the click-through setting says. Menus, dialogs and bubbles are
+// Fyne widgets, so while any is open the regular window shows the character
+// and the overlay is blanked.
+func (dw *DesktopWindow) presentLayerOverlay() {
+	dw.layer.mu.Lock()
+	defer dw.layer.mu.Unlock()
+
+	overlay := dw.layer.overlay
+	if overlay == nil {
+		return
+	}
+	size := dw.character.GetSize()
+
+	if dw.overlayVisible() {
+		if !dw.layer.blanked {
+			dw.layer.blanked = true
+			dw.layer.lastFrame = nil
+			dw.window.Show()
+			if err := overlay.Present(image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+				logrus.WithError(err).Debug("Failed to blank Wayland overlay")
+			}
+			if err := overlay.SetInputRegion([]image.Rectangle{}); err != nil {
+				logrus.WithError(err).Debug("Failed to clear Wayland overlay input region")
+			}
+		}
+		return
+	}
+
+	frame := dw.renderer.VisibleFrame()
+	mode := dw.clickThroughMode()
+	if frame == nil || (frame == dw.layer.lastFrame && size == dw.layer.lastSize && mode == dw.layer.lastMode) {
+		return
+	}
+
+	if err := overlay.Present(scaleToContain(frame, size)); err != nil {
+		logrus.WithError(err).Debug("Failed to present Wayland overlay frame")
+		return
+	}
+	if err := overlay.SetInputRegion(clickRegion(frame, size, mode)); err != nil {
+		logrus.WithError(err).Debug("Failed to update Wayland overlay input region")
+	}
+	dw.layer.lastFrame, dw.layer.lastSize, dw.layer.lastMode = frame, size, mode
+
+	if dw.layer.blanked {
+		dw.layer.blanked = false
+		dw.window.Hide()
+	}
+}
+
+// scaleToContain draws frame into a size x size image with the same
+// letterboxing as canvas.ImageFillContain and native.OpaqueRects
+func scaleToContain(frame image.Image, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	src := frame.Bounds()
+	if src.Empty() || size <= 0 {
+		return dst
+	}
+
+	scale := math.Min(float64(size)/float64(src.Dx()), float64(size)/float64(src.Dy()))
+	w, h := int(float64(src.Dx())*scale), int(float64(src.Dy())*scale)
+	target := image.Rect((size-w)/2, (size-h)/2, (size-w)/2+w, (size-h)/2+h)
+	xdraw.NearestNeighbor.Scale(dst, target, frame, src, draw.Src, nil)
+	return dst
+}
+
+// handleOverlayInput turns pointer input on the overlay into the clicks,
+// double clicks, right clicks and drags the regular window gets from Fyne
+func (dw *DesktopWindow) handleOverlayInput(overlay native.Overlay) {
+	var pressX, pressY float64
+	var pressed, dragging bool
+	var lastClick time.Time
+
+	for event := range overlay.Events() {
+		switch {
+		case event.Kind == native.PointerPress && event.Button == native.ButtonRight:
+			dw.handleRightClick()
+		case event.Kind == native.PointerPress && event.Button == native.ButtonLeft:
+			pressed, dragging = true, false
+			pressX, pressY = event.X, event.Y
+		case event.Kind == native.PointerMotion && pressed:
+			dx, dy := event.X-pressX, event.Y-pressY
+			if !dw.character.IsMovementEnabled() || (!dragging && math.Hypot(dx, dy) < overlayDragThreshold) {
+				continue
+			}
+			// The surface follows the pointer, so the press point stays under it
+			dragging = true
+			dw.moveLayerOverlay(int(dx), int(dy))
+		case event.Kind == native.PointerRelease && event.Button == native.ButtonLeft && pressed:
+			pressed = false
+			if dragging {
+				continue
+			}
+			if time.Since(lastClick) <= overlayDoubleClickDelay {
+				lastClick = time.Time{}
+				dw.handleDoubleTap()
+				continue
+			}
+			lastClick = time.Now()
+			dw.handleClick()
+		}
+	}
+
+	dw.dropLayerOverlay(overlay)
+}
+
+// moveLayerOverlay shifts the overlay and remembers where the character is
+func (dw *DesktopWindow) moveLayerOverlay(dx, dy int) {
+	dw.layer.mu.Lock()
+	defer dw.layer.mu.Unlock()
+
+	if dw.layer.overlay == nil {
+		return
+	}
+	dw.layer.x += dx
+	dw.layer.y += dy
+	if err := dw.layer.overlay.Move(dw.layer.x, dw.layer.y); err != nil {
+		logrus.WithError(err).Debug("Failed to move Wayland overlay")
+		return
+	}
+	dw.character.SetPosition(float32(dw.layer.x), float32(dw.layer.y))
+}
+
+// moveLayerOverlayTo places the overlay at x, y. Returns false when the
+// character isn't on an overlay.
+func (dw *DesktopWindow) moveLayerOverlayTo(x, y int) bool {
+	dw.layer.mu.Lock()
+	defer dw.layer.mu.Unlock()
+
+	if dw.layer.overlay == nil {
+		return false
+	}
+	dw.layer.x, dw.layer.y = x, y
+	if err := dw.layer.overlay.Move(x, y); err != nil {
+		logrus.WithError(err).Debug("Failed to move Wayland overlay")
+	}
+	return true
+}
+
+// dropLayerOverlay brings back the regular window when the compositor
+// takes the overlay away
+func (dw *DesktopWindow) dropLayerOverlay(overlay native.Overlay) {
+	dw.layer.mu.Lock()
+	if dw.layer.overlay != overlay {
+		dw.layer.mu.Unlock()
+		return // Closed by closeLayerOverlay
+	}
+	dw.layer.overlay = nil
+	dw.layer.lastFrame = nil
+	dw.layer.mu.Unlock()
+
+	overlay.Close()
+	dw.window.Show()
+	logrus.Warn("Wayland overlay closed by the compositor, using a regular window")
+}
+
+// closeLayerOverlay removes the overlay before the window is destroyed
+func (dw *DesktopWindow) closeLayerOverlay() {
+	dw.layer.mu.Lock()
+	overlay := dw.layer.overlay
+	dw.layer.overlay = nil
+	dw.layer.mu.Unlock()
+
+	if overlay == nil {
+		return
+	}
+	if err := overlay.Close(); err != nil {
+		logrus.WithError(err).Debug("Failed to close Wayland overlay")
+	}
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestScaleToContainLetterboxes(t *testing.T) {
+	// 2x1 frame, left pixel red, into 8x8: scaled 4x, centred vertically at y=2
+	frame := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	frame.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+
+	scaled := scaleToContain(frame, 8)
+	if scaled.Bounds() != image.Rect(0, 0, 8, 8) {
+		t.Fatalf("Expected an 8x8 image, got %v", scaled.Bounds())
+	}
+	for _, p := range []struct {
+		x, y  int
+		alpha uint8
+	}{
+		{0, 2, 255}, {3, 5, 255}, // Red pixel
+		{4, 2, 0}, {0, 1, 0}, {0, 6, 0}, // Transparent pixel and letterbox
+	} {
+		if a := scaled.RGBAAt(p.x, p.y).A; a != p.alpha {
+			t.Errorf("Pixel (%d,%d): expected alpha %d, got %d", p.x, p.y, p.alpha, a)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ui
+
+// level_up.go celebrates relationship level-ups: a large celebration window
+// names the new level and what it unlocks while the character says its
+// ceremony lines one after another.
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// levelUpCeremonyDuration is how long the celebration window stays up
+const levelUpCeremonyDuration = 12 * time.Second
+
+var (
+	levelUpGradientStart = color.RGBA{R: 0xff, G: 0x69, B: 0xb4, A: 0xff} // Pink
+	levelUpGradientEnd   = color.RGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0xff} // Gold
+)
+
+// checkForLevelUps celebrates relationship level-ups since the last frame.
+// During do-not-disturb or focus only the summary is held for later.
+func (dw *DesktopWindow) checkForLevelUps() {
+	if dw.character == nil {
+		return
+	}
+
+	for _, up := range dw.character.GetLevelUps() {
+		if dw.isQuiet() {
+			dw.held.holdNotice(levelUpSummary(up), false)
+			continue
+		}
+		dw.showLevelUpCeremony(up)
+		go dw.showDialogSequence(up.Dialog)
+	}
+}
+
+// showLevelUpCeremony opens the celebration window, closing it on its own
+// after levelUpCeremonyDuration
+func (dw *DesktopWindow) showLevelUpCeremony(up character.LevelUp) {
+	window := fyne.CurrentApp().NewWindow("Level Up!")
+	var once sync.Once
+	closeWindow := func() { once.Do(window.Close) }
+
+	title := canvas.NewText("💖 "+up.To+" 💖", color.White)
+	title.TextSize = 36
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	title.Alignment = fyne.TextAlignCenter
+
+	subtitle := canvas.NewText(fmt.Sprintf("%s → %s", up.From, up.To), color.White)
+	subtitle.TextSize = 16
+	subtitle.Alignment = fyne.TextAlignCenter
+
+	items := []fyne.CanvasObject{title, subtitle}
+	if len(up.Unlocked) > 0 {
+		unlocks := widget.NewLabel(levelUpUnlockText(up.Unlocked))
+		unlocks.Alignment = fyne.TextAlignCenter
+		items = append(items, unlocks)
+	}
+	items = append(items, widget.NewButton("Yay! 🎉", closeWindow))
+
+	window.SetContent(container.NewStack(
+		canvas.NewLinearGradient(levelUpGradientStart, levelUpGradientEnd, 45),
+		container.NewCenter(container.NewVBox(items...)),
+	))
+	window.Resize(fyne.NewSize(480, 320))
+	window.CenterOnScreen()
+	window.Show()
+
+	time.AfterFunc(levelUpCeremonyDuration, closeWindow)
+}
+
+// levelUpUnlockText lists what a level unlocks
+func levelUpUnlockText(unlocked []string) string {
+	return "Now available:\n• " + strings.Join(unlocked, "\n• ")
+}
+
+// levelUpSummary is the one-bubble version of a ceremony, held through
+// quiet periods
+func levelUpSummary(up character.LevelUp) string {
+	text := fmt.Sprintf("💖 We're %s now!", up.To)
+	if len(up.Unlocked) > 0 {
+		text += "\n" + levelUpUnlockText(up.Unlocked)
+	}
+	return text
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestLevelUpSummary(t *testing.T) {
+	up := character.LevelUp{From: "Friend", To: "Close Friend"}
+	if got := levelUpSummary(up); got != "💖 We're Close Friend now!" {
+		t.Errorf("Unexpected summary without unlocks: %q", got)
+	}
+
+	up.Unlocked = []string{"New interaction: kiss", "Movie dates"}
+	want := "💖 We're Close Friend now!\nNow available:\n• New interaction: kiss\n• Movie dates"
+	if got := levelUpSummary(up); got != want {
+		t.Errorf("levelUpSummary() = %q, want %q", got, want)
+	}
+}
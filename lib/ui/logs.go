@@ -0,0 +1,89 @@
+package ui
+
+// logs.go shows the character's log file in debug mode, so users who never
+// started the companion from a terminal can still copy logs into a bug report.
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+)
+
+// logViewerLines is how many of the most recent lines the viewer shows
+const logViewerLines = 200
+
+// logViewerLevels are the level filter choices, most severe first
+var logViewerLevels = []string{"error", "warning", "info", "debug", "trace"}
+
+// SetLogFile tells the window where the character's log file is, enabling
+// "View Logs" in debug mode
+func (dw *DesktopWindow) SetLogFile(path string) {
+	dw.logPath = path
+}
+
+// buildLogsMenuItem creates the "View Logs" entry, shown in debug mode when
+// logs are written to a file
+func (dw *DesktopWindow) buildLogsMenuItem() (ContextMenuItem, bool) {
+	if !dw.debug || dw.logPath == "" {
+		return ContextMenuItem{}, false
+	}
+
+	return ContextMenuItem{
+		Text: "📜 View Logs",
+		Callback: func() {
+			dw.showLogViewer()
+		},
+	}, true
+}
+
+// showLogViewer opens a window with the last lines of the log file, a level
+// filter and buttons to refresh and copy them
+func (dw *DesktopWindow) showLogViewer() {
+	window := fyne.CurrentApp().NewWindow(fmt.Sprintf("Logs - %s", dw.character.GetName()))
+
+	text := widget.NewLabel("")
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+	scroll := container.NewScroll(text)
+
+	level := widget.NewSelect(logViewerLevels, nil)
+	refresh := func() {
+		text.SetText(logViewerText(dw.logPath, level.Selected))
+		scroll.ScrollToBottom()
+	}
+	level.OnChanged = func(string) { refresh() }
+	level.SetSelected("debug")
+
+	copyButton := widget.NewButton("Copy", func() {
+		window.Clipboard().SetContent(text.Text)
+	})
+	pathLabel := widget.NewLabel(dw.logPath)
+	pathLabel.Truncation = fyne.TextTruncateEllipsis
+
+	toolbar := container.NewHBox(widget.NewLabel("Level:"), level, widget.NewButton("Refresh", refresh), copyButton)
+	window.SetContent(container.NewBorder(toolbar, pathLabel, nil, nil, scroll))
+	window.Resize(fyne.NewSize(720, 480))
+	window.Show()
+}
+
+// logViewerText returns the last logViewerLines lines at minLevel or more
+// severe, or a note saying why there are none
+func logViewerText(path, minLevel string) string {
+	level, err := logrus.ParseLevel(minLevel)
+	if err != nil {
+		level = logrus.DebugLevel
+	}
+	lines, err := monitoring.ReadLogTail(path, logViewerLines, level)
+	if err != nil && len(lines) == 0 {
+		return fmt.Sprintf("Could not read %s: %v", path, err)
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("No %s or more severe entries yet.", minLevel)
+	}
+	return strings.Join(lines, "\n")
+}
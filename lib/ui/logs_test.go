@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogViewerText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	log := `time="2026-01-01T10:00:00Z" level=debug msg="tick"
+time="2026-01-01T10:00:01Z" level=warning msg="save slow"
+time="2026-01-01T10:00:02Z" level=error msg="save failed"
+`
+	if err := os.WriteFile(path, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := logViewerText(path, "warning"); strings.Contains(got, "tick") || !strings.Contains(got, "save failed") {
+		t.Errorf("Expected warnings and errors only, got %q", got)
+	}
+	if got := logViewerText(path, "debug"); strings.Count(got, "\n") != 2 {
+		t.Errorf("Expected all three lines, got %q", got)
+	}
+	if got := logViewerText(filepath.Join(t.TempDir(), "missing.log"), "info"); !strings.HasPrefix(got, "Could not read") {
+		t.Errorf("Expected a read error note, got %q", got)
+	}
+}
+
+func TestLogsMenuItemOnlyInDebugMode(t *testing.T) {
+	dw := &DesktopWindow{logPath: "companion.log"}
+	if _, ok := dw.buildLogsMenuItem(); ok {
+		t.Error("Expected no View Logs outside debug mode")
+	}
+	dw.debug = true
+	if item, ok := dw.buildLogsMenuItem(); !ok || item.Text != "📜 View Logs" {
+		t.Errorf("Expected View Logs in debug mode, got %+v", item)
+	}
+}
@@ -0,0 +1,316 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+const (
+	// memoryPairs is how many pairs a memory board has
+	memoryPairs = 6
+	// memoryColumns lays the memory board out in rows of this many cards
+	memoryColumns = 4
+	// memoryFaceDown is shown on cards that haven't been turned over
+	memoryFaceDown = "❓"
+)
+
+// throwEmoji shows each rock-paper-scissors throw
+var throwEmoji = map[string]string{
+	character.ThrowRock:     "✊",
+	character.ThrowPaper:    "✋",
+	character.ThrowScissors: "✌️",
+}
+
+// miniGameNames are the chooser labels for each game
+var miniGameNames = map[string]string{
+	character.MiniGameRPS:    "✊ Rock Paper Scissors",
+	character.MiniGameTrivia: "❓ Trivia",
+	character.MiniGameMemory: "🃏 Memory",
+}
+
+// MiniGameDialog plays the built-in mini-games against the character.
+// Finished games are handed to onResult; rewards are applied by the character.
+type MiniGameDialog struct {
+	window    fyne.Window
+	character *character.Character
+	onResult  func(character.MiniGameResult)
+	body      *fyne.Container
+	status    *widget.Label
+
+	// stepDelay paces the rock-paper-scissors countdown and how long a
+	// mismatched memory pair stays face up; zero runs both immediately
+	stepDelay time.Duration
+
+	rpsButtons []*widget.Button
+
+	questions     []character.TriviaQuestion
+	question      int
+	correct       int
+	choiceButtons []*widget.Button
+
+	memory        *character.MemoryGame
+	memoryButtons []*widget.Button
+	memoryBusy    bool // A mismatched pair is showing; ignore taps until it turns back
+}
+
+// NewMiniGameDialog creates the mini-game window for char
+func NewMiniGameDialog(app fyne.App, char *character.Character, onResult func(character.MiniGameResult)) *MiniGameDialog {
+	d := &MiniGameDialog{
+		window:    app.NewWindow("Mini-Games"),
+		character: char,
+		onResult:  onResult,
+		body:      container.NewVBox(),
+		status:    widget.NewLabel("Pick a game to play."),
+		stepDelay: 400 * time.Millisecond,
+	}
+	d.status.Wrapping = fyne.TextWrapWord
+
+	chooser := container.NewHBox()
+	for _, game := range char.GetMiniGames() {
+		chooser.Add(widget.NewButton(miniGameNames[game], func() { d.start(game) }))
+	}
+
+	d.window.SetContent(container.NewBorder(chooser, d.status, nil, nil, d.body))
+	d.window.Resize(fyne.NewSize(360, 300))
+	return d
+}
+
+// Show displays the mini-game window
+func (d *MiniGameDialog) Show() {
+	d.window.Show()
+}
+
+// SetOnClosed sets a callback for when the window closes
+func (d *MiniGameDialog) SetOnClosed(onClosed func()) {
+	d.window.SetOnClosed(onClosed)
+}
+
+// RequestFocus brings the mini-game window to the front
+func (d *MiniGameDialog) RequestFocus() {
+	d.window.RequestFocus()
+}
+
+// start sets up a new round of game
+func (d *MiniGameDialog) start(game string) {
+	d.body.RemoveAll()
+	switch game {
+	case character.MiniGameRPS:
+		d.startRPS()
+	case character.MiniGameTrivia:
+		d.startTrivia()
+	case character.MiniGameMemory:
+		d.startMemory()
+	}
+	d.body.Refresh()
+}
+
+// later runs f after n steps of stepDelay, or right away when there's no delay
+func (d *MiniGameDialog) later(n int, f func()) {
+	if d.stepDelay <= 0 {
+		f()
+		return
+	}
+	time.AfterFunc(time.Duration(n)*d.stepDelay, f)
+}
+
+// finish hands a finished game to the character and the result callback
+func (d *MiniGameDialog) finish(game string, won bool) {
+	result := d.character.FinishMiniGame(game, won)
+	if d.onResult != nil {
+		d.onResult(result)
+	}
+}
+
+// startRPS shows one button per throw
+func (d *MiniGameDialog) startRPS() {
+	d.rpsButtons = nil
+	row := container.NewGridWithColumns(len(character.Throws))
+	for _, throw := range character.Throws {
+		button := widget.NewButton(throwEmoji[throw], func() { d.playRPS(throw) })
+		d.rpsButtons = append(d.rpsButtons, button)
+		row.Add(button)
+	}
+	d.body.Add(widget.NewLabel("Rock, paper or scissors?"))
+	d.body.Add(row)
+	d.status.SetText("Make your throw!")
+}
+
+// playRPS counts down, then reveals both throws. A draw throws again.
+func (d *MiniGameDialog) playRPS(throw string) {
+	theirs, outcome, err := character.PlayRockPaperScissors(throw)
+	if err != nil {
+		d.status.SetText(err.Error())
+		return
+	}
+	d.setButtonsEnabled(d.rpsButtons, false)
+
+	for i, word := range []string{"Rock...", "Paper...", "Scissors..."} {
+		d.later(i, func() { d.status.SetText(word) })
+	}
+	d.later(3, func() {
+		reveal := fmt.Sprintf("You %s vs %s %s", throwEmoji[throw], throwEmoji[theirs], d.character.GetName())
+		switch outcome {
+		case 0:
+			d.status.SetText(reveal + " - a draw! Throw again.")
+			d.setButtonsEnabled(d.rpsButtons, true)
+			return
+		case 1:
+			d.status.SetText(reveal + " - you win!")
+		default:
+			d.status.SetText(reveal + " - you lose!")
+		}
+		d.finish(character.MiniGameRPS, outcome > 0)
+		d.setButtonsEnabled(d.rpsButtons, true)
+	})
+}
+
+// startTrivia picks this round's questions and asks the first
+func (d *MiniGameDialog) startTrivia() {
+	d.questions = d.character.PickTriviaQuestions()
+	d.question, d.correct = 0, 0
+	if len(d.questions) == 0 {
+		d.status.SetText("This character has no trivia questions.")
+		return
+	}
+	d.askTrivia("")
+}
+
+// askTrivia shows the current question under the previous answer's verdict
+func (d *MiniGameDialog) askTrivia(verdict string) {
+	q := d.questions[d.question]
+	d.body.RemoveAll()
+	prompt := widget.NewLabel(fmt.Sprintf("Question %d of %d: %s", d.question+1, len(d.questions), q.Question))
+	prompt.Wrapping = fyne.TextWrapWord
+	d.body.Add(prompt)
+
+	d.choiceButtons = nil
+	for i, choice := range q.Choices {
+		button := widget.NewButton(choice, func() { d.answerTrivia(i) })
+		d.choiceButtons = append(d.choiceButtons, button)
+		d.body.Add(button)
+	}
+	d.body.Refresh()
+	d.status.SetText(verdict)
+}
+
+// answerTrivia scores an answer and moves on; a majority correct wins
+func (d *MiniGameDialog) answerTrivia(choice int) {
+	q := d.questions[d.question]
+	verdict := "Correct! ✔️"
+	if choice == q.Answer {
+		d.correct++
+	} else {
+		verdict = fmt.Sprintf("Not quite - it was %s.", q.Choices[q.Answer])
+	}
+
+	d.question++
+	if d.question < len(d.questions) {
+		d.askTrivia(verdict)
+		return
+	}
+
+	d.setButtonsEnabled(d.choiceButtons, false)
+	d.status.SetText(fmt.Sprintf("%s You got %d of %d right.", verdict, d.correct, len(d.questions)))
+	d.finish(character.MiniGameTrivia, d.correct*2 > len(d.questions))
+}
+
+// startMemory deals a fresh board face down
+func (d *MiniGameDialog) startMemory() {
+	d.memory = character.NewMemoryGame(memoryPairs)
+	d.memoryBusy = false
+	d.memoryButtons = nil
+
+	grid := container.NewGridWithColumns(memoryColumns)
+	for i := range d.memory.Cards {
+		button := widget.NewButton(memoryFaceDown, func() { d.flipMemory(i) })
+		d.memoryButtons = append(d.memoryButtons, button)
+		grid.Add(button)
+	}
+	d.body.Add(grid)
+	d.status.SetText(fmt.Sprintf("Find all %d pairs in %d moves or fewer.", memoryPairs, memoryPairs*2))
+}
+
+// flipMemory turns a card over and, after a mismatch, back again
+func (d *MiniGameDialog) flipMemory(i int) {
+	if d.memory == nil || d.memoryBusy {
+		return
+	}
+	first, matched, err := d.memory.Flip(i)
+	if err != nil {
+		return // Tapping a face-up card does nothing
+	}
+	d.memoryButtons[i].SetText(d.memory.Cards[i])
+	if first < 0 {
+		return
+	}
+
+	if !matched {
+		d.memoryBusy = true
+		d.status.SetText(fmt.Sprintf("Moves: %d", d.memory.Moves))
+		d.later(2, func() {
+			d.memoryButtons[first].SetText(memoryFaceDown)
+			d.memoryButtons[i].SetText(memoryFaceDown)
+			d.memoryBusy = false
+		})
+		return
+	}
+
+	d.memoryButtons[first].Disable()
+	d.memoryButtons[i].Disable()
+	if !d.memory.Done() {
+		d.status.SetText(fmt.Sprintf("A pair! Moves: %d", d.memory.Moves))
+		return
+	}
+	d.status.SetText(fmt.Sprintf("All pairs found in %d moves.", d.memory.Moves))
+	d.finish(character.MiniGameMemory, d.memory.Won())
+}
+
+// setButtonsEnabled enables or disables a set of buttons
+func (d *MiniGameDialog) setButtonsEnabled(buttons []*widget.Button, enabled bool) {
+	for _, button := range buttons {
+		if enabled {
+			button.Enable()
+		} else {
+			button.Disable()
+		}
+	}
+}
+
+// buildMiniGameMenuItem creates the mini-games entry for the game menu
+func (dw *DesktopWindow) buildMiniGameMenuItem() ContextMenuItem {
+	return ContextMenuItem{Text: "🎲 Mini-Games", Callback: dw.startMiniGameSession}
+}
+
+// startMiniGameSession opens the mini-game window, or focuses it if it's open
+func (dw *DesktopWindow) startMiniGameSession() {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+
+	if dw.miniGameDialog != nil {
+		dw.miniGameDialog.RequestFocus()
+		return
+	}
+
+	dw.miniGameDialog = NewMiniGameDialog(fyne.CurrentApp(), dw.character, dw.showMiniGameResult)
+	dw.miniGameDialog.SetOnClosed(func() {
+		dw.settingsMu.Lock()
+		dw.miniGameDialog = nil
+		dw.settingsMu.Unlock()
+	})
+	dw.miniGameDialog.Show()
+}
+
+// showMiniGameResult has the character react to a finished game
+func (dw *DesktopWindow) showMiniGameResult(result character.MiniGameResult) {
+	text := result.Response
+	if coins := result.Effects[character.CurrencyKey]; coins > 0 {
+		text += fmt.Sprintf("\n🪙 +%.0f coins", coins)
+	}
+	dw.showDialog(text)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func newTestMiniGameDialog(t *testing.T) (*MiniGameDialog, *[]character.MiniGameResult) {
+	t.Helper()
+	app := test.NewApp()
+	t.Cleanup(func() { test.NewApp() }) // Reset test app
+
+	var results []character.MiniGameResult
+	d := NewMiniGameDialog(app, createRomanceCharacterWithStats(t), func(result character.MiniGameResult) {
+		results = append(results, result)
+	})
+	d.stepDelay = 0
+	return d, &results
+}
+
+func TestMiniGameDialogRockPaperScissors(t *testing.T) {
+	d, results := newTestMiniGameDialog(t)
+	d.start(character.MiniGameRPS)
+
+	// Draws throw again without finishing the game
+	for i := 0; i < 50 && len(*results) == 0; i++ {
+		test.Tap(d.rpsButtons[0])
+	}
+	if len(*results) != 1 || (*results)[0].Game != character.MiniGameRPS {
+		t.Fatalf("Expected one finished game, got %+v", *results)
+	}
+	if d.rpsButtons[0].Disabled() {
+		t.Error("Expected the throw buttons to be enabled for another round")
+	}
+}
+
+func TestMiniGameDialogMemory(t *testing.T) {
+	d, results := newTestMiniGameDialog(t)
+	d.start(character.MiniGameMemory)
+
+	positions := make(map[string][]int)
+	for i, card := range d.memory.Cards {
+		positions[card] = append(positions[card], i)
+	}
+	for _, pair := range positions {
+		test.Tap(d.memoryButtons[pair[0]])
+		if d.memoryButtons[pair[0]].Text == memoryFaceDown {
+			t.Fatal("Expected the tapped card to turn face up")
+		}
+		test.Tap(d.memoryButtons[pair[1]])
+	}
+
+	if len(*results) != 1 || !(*results)[0].Won {
+		t.Fatalf("Expected a perfect board to win, got %+v", *results)
+	}
+	if !d.memoryButtons[0].Disabled() {
+		t.Error("Expected matched cards to be disabled")
+	}
+}
+
+func TestMiniGameDialogTriviaWithoutQuestions(t *testing.T) {
+	d, results := newTestMiniGameDialog(t)
+	d.start(character.MiniGameTrivia)
+
+	if len(d.choiceButtons) != 0 || len(*results) != 0 {
+		t.Error("Expected no trivia round without questions")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
@@ -21,8 +22,10 @@ type NetworkManagerInterface interface {
 	GetPeerCount() int
 	GetPeers() []network.Peer
 	GetNetworkID() string
+	GetPeerID() string
 	SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error
 	RegisterMessageHandler(msgType network.MessageType, handler network.MessageHandler)
+	AddMessageObserver(msgType network.MessageType, observer network.MessageHandler)
 }
 
 // CharacterInfo represents a character's location and status for UI display
@@ -61,12 +64,15 @@ type NetworkOverlay struct {
 	sendButton     *widget.Button
 	visible        bool
 	updateTicker   *time.Ticker
+	refresh        time.Duration // Update interval, 0 for overlayRefreshInterval
 	stopUpdate     chan bool
 	mu             sync.RWMutex // Protects updateTicker and background goroutine state
 
 	// Peer data for list widget
-	peers     []network.Peer
-	peerMutex sync.RWMutex
+	peers           []network.Peer
+	peerMutex       sync.RWMutex
+	permissions     *network.PermissionStore // Per-peer permissions, nil when not editable
+	permissionsView *PeerPermissionsView
 
 	// Character data for visual distinction between local and network characters
 	characters     []CharacterInfo
@@ -86,6 +92,36 @@ type NetworkOverlay struct {
 	personalityCache    map[string]*CachedPersonality
 	personalityCacheMu  sync.RWMutex
 	personalityRequests map[string]time.Time // Request ID -> timestamp for timeout tracking
+
+	// Battle spectating: watch other peers' battles, control who watches ours
+	spectatorHub    *network.SpectatorHub
+	battleList      *widget.List
+	battles         []network.BattleListing
+	battleMutex     sync.RWMutex
+	spectatorPolicy *widget.Select
+	spectatorView   *BattleSpectatorView
+
+	// Peer chat: users talking to each other through their companions
+	chatRelay       *network.ChatRelay
+	relayMu         sync.Mutex // Guards relayPeer and relayPeerIDs
+	relayPeer       string     // Peer whose conversation is open
+	relayPeerIDs    []string   // Peer IDs in the order of relayPeerSelect's options
+	relayPeerSelect *widget.Select
+	relayAvatar     *canvas.Image
+	relayName       *widget.Label
+	relayLog        *widget.RichText
+	relayScroll     *container.Scroll
+	relayInput      *widget.Entry
+	relaySendButton *widget.Button
+
+	// Visits: peers' characters shown on our desktop and ours on theirs
+	visits *network.Visits
+
+	// Shared pet: our character co-owned with a peer, stats kept in sync
+	sharedPet *network.SharedPet
+
+	// Ping and packet loss to each peer, shown in the peer list (see latency.go)
+	latency *network.LatencyMonitor
 }
 
 // NewNetworkOverlay creates a new network overlay widget
@@ -136,7 +172,8 @@ func (no *NetworkOverlay) createNetworkWidgets() {
 			return len(no.peers)
 		},
 		func() fyne.CanvasObject {
-			return widget.NewLabel("Peer")
+			// Connection quality dot, then the peer
+			return container.NewHBox(canvas.NewText("●", nil), widget.NewLabel("Peer"))
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			no.peerMutex.RLock()
@@ -144,16 +181,17 @@ func (no *NetworkOverlay) createNetworkWidgets() {
 
 			if id < len(no.peers) {
 				peer := no.peers[id]
-				statusIcon := "🔴" // Disconnected
-				if peer.Conn != nil {
-					statusIcon = "🟢" // Connected
-				}
-
-				obj.(*widget.Label).SetText(fmt.Sprintf("%s %s", statusIcon, peer.ID))
+				row := obj.(*fyne.Container)
+				dot, quality := no.peerQuality(peer.ID)
+				row.Objects[0].(*canvas.Text).Color = dot
+				row.Objects[0].Refresh()
+				row.Objects[1].(*widget.Label).SetText(fmt.Sprintf("%s %s%s", no.peerStatusIcon(peer), peer.ID, quality))
 			}
 		},
 	)
+	no.peerList.OnSelected = no.showPeerPermissions
 	no.peerList.Resize(fyne.NewSize(200, 80)) // Reduced height to make room for character list
+	no.permissionsView = NewPeerPermissionsView(no.peerList.Refresh)
 
 	// Character list widget - clearly distinguishes local vs network characters
 	no.characterList = widget.NewList(
@@ -236,8 +274,8 @@ func (no *NetworkOverlay) createNetworkWidgets() {
 	headerContainer := container.NewHBox(no.statusLabel, layout.NewSpacer(), no.peerCount)
 
 	peerSection := container.NewBorder(
-		widget.NewLabel("Network Peers:"),
-		nil, nil, nil,
+		widget.NewLabel("Network Peers (tap for permissions):"),
+		no.permissionsView.GetContainer(), nil, nil,
 		no.peerList,
 	)
 
@@ -249,10 +287,9 @@ func (no *NetworkOverlay) createNetworkWidgets() {
 	)
 
 	chatControls := container.NewBorder(nil, nil, nil, no.sendButton, no.chatInput)
-	chatSection := container.NewBorder(
-		widget.NewLabel("Network Chat:"),
-		chatControls, nil, nil,
-		no.chatScroll,
+	chatSection := container.NewAppTabs(
+		container.NewTabItem("Everyone", container.NewBorder(nil, chatControls, nil, nil, no.chatScroll)),
+		container.NewTabItem("Peer Chat", no.createChatRelayWidgets()),
 	)
 
 	// Activity feed section (Feature 9)
@@ -262,6 +299,8 @@ func (no *NetworkOverlay) createNetworkWidgets() {
 		no.activityFeed.GetContainer(),
 	)
 
+	battleSection := no.createSpectatorWidgets()
+
 	// Main container with all network UI elements - character section added
 	no.container = container.NewVBox(
 		headerContainer,
@@ -272,6 +311,8 @@ func (no *NetworkOverlay) createNetworkWidgets() {
 		widget.NewSeparator(),
 		activitySection, // Activity feed before chat for better UX
 		widget.NewSeparator(),
+		battleSection,
+		widget.NewSeparator(),
 		chatSection,
 	)
 
@@ -284,8 +325,8 @@ func (no *NetworkOverlay) styleNetworkWidgets() {
 	// Set background color for better visibility over character
 	// backgroundColor := color.RGBA{R: 0, G: 0, B: 0, A: 180} // Semi-transparent black
 
-	// Style the main container - increased height to accommodate activity feed
-	no.container.Resize(fyne.NewSize(220, 480))
+	// Style the main container - increased height to accommodate activity feed and live battles
+	no.container.Resize(fyne.NewSize(220, 560))
 
 	// Style status label with appropriate colors
 	if no.networkManager != nil && no.networkManager.GetPeerCount() > 0 {
@@ -308,7 +349,7 @@ func (no *NetworkOverlay) sendChatMessage(message string) {
 
 	// Send message through network manager
 	payload := []byte(fmt.Sprintf(`{"type":"chat","message":"%s","from":"%s"}`,
-		message, no.networkManager.GetNetworkID()))
+		message, no.networkManager.GetPeerID()))
 
 	err := no.networkManager.SendMessage(network.MessageTypeCharacterAction, payload, "")
 	if err != nil {
@@ -353,10 +394,31 @@ func (no *NetworkOverlay) Show() {
 	no.container.Show()
 
 	// Start periodic updates
-	no.updateTicker = time.NewTicker(2 * time.Second)
+	no.updateTicker = time.NewTicker(no.refreshIntervalLocked())
 	go no.updateLoop()
 }
 
+// SetRefreshInterval changes how often the overlay refreshes, taking effect
+// at once if it is showing
+func (no *NetworkOverlay) SetRefreshInterval(interval time.Duration) {
+	no.mu.Lock()
+	defer no.mu.Unlock()
+
+	no.refresh = interval
+	if no.updateTicker != nil {
+		no.updateTicker.Reset(no.refreshIntervalLocked())
+	}
+}
+
+// refreshIntervalLocked returns the update interval. Must be called with
+// no.mu held.
+func (no *NetworkOverlay) refreshIntervalLocked() time.Duration {
+	if no.refresh <= 0 {
+		return overlayRefreshInterval
+	}
+	return no.refresh
+}
+
 // Hide makes the network overlay invisible
 func (no *NetworkOverlay) Hide() {
 	no.mu.Lock()
@@ -465,6 +527,7 @@ func (no *NetworkOverlay) updatePeerList() {
 
 	// Refresh the list widget
 	no.peerList.Refresh()
+	no.refreshChatRelayPeers()
 }
 
 // updateCharacterList refreshes the character list to clearly show local vs network characters
@@ -516,6 +579,15 @@ func (no *NetworkOverlay) updateCharacterList() {
 // SetLocalCharacterName updates the local character name for display
 func (no *NetworkOverlay) SetLocalCharacterName(name string) {
 	no.localCharName = name
+	if no.chatRelay != nil {
+		no.chatRelay.SetCharacter(name)
+	}
+	if no.visits != nil {
+		no.visits.SetCharacter(name)
+	}
+	if no.sharedPet != nil {
+		no.sharedPet.SetCharacter(name)
+	}
 	no.updateCharacterList()
 }
 
@@ -535,6 +607,10 @@ func (no *NetworkOverlay) RegisterNetworkEvents() {
 					if message, ok := chatData["message"].(string); ok {
 						no.addChatMessage(from.ID, message)
 					}
+				} else if ok && msgType == "gift" {
+					if gift, ok := chatData["gift"].(string); ok {
+						no.addChatMessage(from.ID, "🎁 sent you "+gift)
+					}
 				}
 			}
 			return nil
@@ -551,9 +627,28 @@ func (no *NetworkOverlay) RegisterNetworkEvents() {
 			return no.handlePersonalityResponse(msg, from)
 		})
 
+	no.setupSpectating()
+	no.setupChatRelay()
+	no.visits = network.NewVisits(no.networkManager, no.localCharName)
+	no.sharedPet = network.NewSharedPet(no.networkManager, no.networkManager.GetPeerID(), no.localCharName)
+	no.latency = network.NewLatencyMonitor(no.networkManager)
+	no.latency.Start(0)
+
 	// Future: Add handlers for peer join/leave events when available
 }
 
+// GetVisits returns the visits to and from peers, nil until network events
+// are registered
+func (no *NetworkOverlay) GetVisits() *network.Visits {
+	return no.visits
+}
+
+// GetSharedPet returns shared pet mode, nil until network events are
+// registered
+func (no *NetworkOverlay) GetSharedPet() *network.SharedPet {
+	return no.sharedPet
+}
+
 // GetCharacterList returns current character information (for testing)
 func (no *NetworkOverlay) GetCharacterList() []CharacterInfo {
 	no.characterMutex.RLock()
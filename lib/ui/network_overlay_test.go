@@ -44,6 +44,10 @@ func (m *MockNetworkManager) GetNetworkID() string {
 	return m.networkID
 }
 
+func (m *MockNetworkManager) GetPeerID() string {
+	return m.networkID
+}
+
 func (m *MockNetworkManager) SendMessage(msgType network.MessageType, payload []byte, targetPeerID string) error {
 	m.messagesSent = append(m.messagesSent, MockMessage{
 		MsgType:  msgType,
@@ -57,6 +61,9 @@ func (m *MockNetworkManager) RegisterMessageHandler(msgType network.MessageType,
 	// Store handler if needed for testing
 }
 
+func (m *MockNetworkManager) AddMessageObserver(msgType network.MessageType, observer network.MessageHandler) {
+}
+
 // Helper methods for testing
 func (m *MockNetworkManager) SetPeerCount(count int) {
 	m.peerCount = count
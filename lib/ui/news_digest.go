@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+// digestBubbleInterval is how long each bubble of a news briefing stays up
+// before the next one. Slightly longer than a dialog's auto-hide so bubbles
+// don't overlap.
+const digestBubbleInterval = 3500 * time.Millisecond
+
+// checkForNewsDigests reads out briefings that became ready since the last frame
+func (dw *DesktopWindow) checkForNewsDigests() {
+	if dw.character == nil {
+		return
+	}
+	for _, bubbles := range dw.character.GetNewsDigests() {
+		go dw.showDialogSequence(bubbles)
+	}
+}
+
+// showDialogSequence shows bubbles one after another, like a news anchor
+// reading a segment. Bubbles are dropped the same way single dialogs are.
+func (dw *DesktopWindow) showDialogSequence(bubbles []string) {
+	for i, text := range bubbles {
+		if i > 0 {
+			time.Sleep(digestBubbleInterval)
+		}
+		dw.showDialog(text)
+	}
+}
+
+// buildNewsPreferencesMenuItem creates the briefing preferences entry when
+// the card has a daily briefing
+func (dw *DesktopWindow) buildNewsPreferencesMenuItem() (ContextMenuItem, bool) {
+	if !dw.character.HasNewsDigest() {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{Text: "🗞️ News Preferences", Callback: dw.showNewsPreferences}, true
+}
+
+// showNewsPreferences lets the user pick the briefing's categories and time
+func (dw *DesktopWindow) showNewsPreferences() {
+	window := fyne.CurrentApp().NewWindow("News Preferences")
+
+	categories := dw.character.GetNewsCategories()
+	categoryGroup := widget.NewCheckGroup(categories, nil)
+	categoryGroup.SetSelected(dw.character.GetNewsSubscriptions())
+
+	timeEntry := widget.NewEntry()
+	timeEntry.SetPlaceHolder("HH:MM")
+	timeEntry.SetText(dw.character.GetNewsDigestTime())
+
+	statusLabel := widget.NewLabel("")
+	save := func() {
+		if err := dw.saveNewsPreferences(categoryGroup.Selected, timeEntry.Text); err != nil {
+			statusLabel.SetText(err.Error())
+			return
+		}
+		statusLabel.SetText("Saved.")
+	}
+
+	window.SetContent(container.NewVBox(
+		widget.NewCard("Daily briefing", "Leave all unticked to hear every category", container.NewVBox(
+			categoryGroup,
+			container.NewBorder(nil, nil, widget.NewLabel("Time:"), nil, timeEntry),
+		)),
+		container.NewHBox(
+			widget.NewButton("Save", save),
+			widget.NewButton("Brief me now", func() {
+				save()
+				if err := dw.character.RequestNewsDigest(); err != nil {
+					statusLabel.SetText(err.Error())
+				}
+			}),
+		),
+		statusLabel,
+	))
+	window.Resize(fyne.NewSize(320, 0))
+	window.Show()
+}
+
+// saveNewsPreferences applies the briefing preferences and stores them in
+// the settings file, if there is one
+func (dw *DesktopWindow) saveNewsPreferences(categories []string, digestTime string) error {
+	if err := dw.character.SetNewsDigestTime(digestTime); err != nil {
+		return err
+	}
+	dw.character.SetNewsSubscriptions(categories)
+
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+	dw.settings.NewsCategories = append([]string(nil), categories...)
+	dw.settings.NewsDigestTime = digestTime
+	if dw.settingsDialog != nil {
+		// Keep an open settings window from saving the old preferences back
+		dw.settingsDialog.settings.NewsCategories = dw.settings.NewsCategories
+		dw.settingsDialog.settings.NewsDigestTime = digestTime
+	}
+	if dw.settingsPath == "" {
+		return nil
+	}
+	if err := dw.settings.Save(dw.settingsPath); err != nil {
+		return fmt.Errorf("could not save preferences: %w", err)
+	}
+	return nil
+}
+
+// applyNewsSettings restores briefing preferences from the settings file
+func (dw *DesktopWindow) applyNewsSettings(settings config.Settings) {
+	if dw.character == nil {
+		return
+	}
+	dw.character.SetNewsSubscriptions(settings.NewsCategories)
+	if err := dw.character.SetNewsDigestTime(settings.NewsDigestTime); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"time":   settings.NewsDigestTime,
+			"error":  err.Error(),
+		}).Warn("Ignoring invalid news briefing time in settings")
+	}
+}
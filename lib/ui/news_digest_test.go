@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+func TestSaveNewsPreferences(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if _, ok := dw.buildNewsPreferencesMenuItem(); ok {
+		t.Error("News preferences should be hidden without a daily briefing")
+	}
+
+	path := filepath.Join(t.TempDir(), config.SettingsFileName)
+	dw.SetSettings(config.Settings{}, path)
+
+	if err := dw.saveNewsPreferences([]string{"tech"}, "9am"); err == nil {
+		t.Error("Expected an invalid time to be rejected")
+	}
+	if err := dw.saveNewsPreferences([]string{"tech"}, "09:30"); err != nil {
+		t.Fatal(err)
+	}
+	if char.GetNewsDigestTime() != "09:30" || strings.Join(char.GetNewsSubscriptions(), ",") != "tech" {
+		t.Errorf("Expected preferences applied, got %s %v", char.GetNewsDigestTime(), char.GetNewsSubscriptions())
+	}
+
+	saved, err := config.LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.NewsDigestTime != "09:30" || strings.Join(saved.NewsCategories, ",") != "tech" {
+		t.Errorf("Expected preferences saved, got %+v", saved)
+	}
+
+	// Restored on the next launch
+	restored := createTestCharacterWithoutGame(t, t.TempDir())
+	NewDesktopWindow(app, restored, false, nil, false, false, nil, false, false, false).SetSettings(*saved, path)
+	if restored.GetNewsDigestTime() != "09:30" {
+		t.Errorf("Expected the briefing time restored, got %s", restored.GetNewsDigestTime())
+	}
+}
+
+func TestShowDialogSequence(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dw.showDialogSequence([]string{"only bubble"})
+	if dw.dialog.currentText != "only bubble" {
+		t.Errorf("Expected the bubble shown, got %q", dw.dialog.currentText)
+	}
+}
@@ -0,0 +1,157 @@
+package ui
+
+// notifications.go raises native desktop notifications for the character
+// events the user ticked in the settings window, so they are seen while the
+// companion is hidden, behind other windows or out of view. Fyne sends them
+// through the platform's notification service. Do-not-disturb silences them,
+// as it does phone pushes.
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// Character events that can raise a desktop notification, for the
+// notifications setting
+const (
+	NotifyCritical    = "critical"    // A stat dropped to its critical threshold
+	NotifyAchievement = "achievement" // An achievement was earned
+	NotifyBattle      = "battle"      // Another player sent a battle invitation
+	NotifyReminder    = "reminder"    // A scheduled event from the card fired
+)
+
+// criticalCheckInterval is how often stats are checked for critical ones
+const criticalCheckInterval = 5 * time.Second
+
+// notificationOptions are the events offered in the settings window
+var notificationOptions = []struct {
+	label string
+	name  string
+}{
+	{"Critical stats", NotifyCritical},
+	{"Achievements", NotifyAchievement},
+	{"Battle invitations", NotifyBattle},
+	{"Reminders", NotifyReminder},
+}
+
+// notificationState tracks which stats were already announced as critical
+type notificationState struct {
+	mu       sync.Mutex
+	critical map[string]bool // Stats critical at the last check
+	checked  time.Time       // When stats were last checked
+}
+
+// notifying reports whether the user wants desktop notifications for kind
+func (dw *DesktopWindow) notifying(kind string) bool {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+	return slices.Contains(dw.settings.Notifications, kind)
+}
+
+// sendDesktopNotification raises a desktop notification for an event of
+// kind, unless it is turned off or do-not-disturb is on
+func (dw *DesktopWindow) sendDesktopNotification(kind, title, content string) {
+	if !dw.notifying(kind) || (dw.character != nil && dw.character.IsDoNotDisturb()) {
+		return
+	}
+	app := fyne.CurrentApp()
+	if app == nil {
+		return
+	}
+
+	app.SendNotification(fyne.NewNotification(title, content))
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"kind":   kind,
+		"title":  title,
+	}).Debug("Desktop notification sent")
+}
+
+// checkForCriticalStats notifies about stats that became critical since the
+// last check. A stat that stays critical isn't repeated until it recovers.
+func (dw *DesktopWindow) checkForCriticalStats() {
+	if dw.character == nil || dw.character.GetGameState() == nil {
+		return
+	}
+
+	dw.notifications.mu.Lock()
+	if time.Since(dw.notifications.checked) < criticalCheckInterval {
+		dw.notifications.mu.Unlock()
+		return
+	}
+	dw.notifications.checked = time.Now()
+	dw.notifications.mu.Unlock()
+
+	states := dw.character.GetCriticalStates()
+	sort.Strings(states)
+
+	dw.notifications.mu.Lock()
+	critical := make(map[string]bool, len(states))
+	var fresh []string
+	for _, stat := range states {
+		critical[stat] = true
+		if !dw.notifications.critical[stat] {
+			fresh = append(fresh, stat)
+		}
+	}
+	dw.notifications.critical = critical
+	dw.notifications.mu.Unlock()
+
+	if len(fresh) == 0 {
+		return
+	}
+	name := dw.character.GetName()
+	dw.sendDesktopNotification(NotifyCritical, fmt.Sprintf("%s needs you!", name),
+		fmt.Sprintf("%s is critically low: %s", name, strings.Join(fresh, ", ")))
+}
+
+// notifyAchievement raises the desktop notification for an earned achievement
+func (dw *DesktopWindow) notifyAchievement(details character.AchievementDetails) {
+	content := details.Name
+	if details.Description != "" {
+		content += ": " + details.Description
+	}
+	dw.sendDesktopNotification(NotifyAchievement, "🏆 Achievement unlocked", content)
+}
+
+// notifyBattleInvitation raises the desktop notification for a challenge
+func (dw *DesktopWindow) notifyBattleInvitation(from string) {
+	dw.sendDesktopNotification(NotifyBattle, "⚔️ Battle invitation",
+		fmt.Sprintf("%s challenged %s to a battle!", from, dw.character.GetName()))
+}
+
+// notifyReminder raises the desktop notification for a scheduled event
+func (dw *DesktopWindow) notifyReminder(response string) {
+	dw.sendDesktopNotification(NotifyReminder, dw.character.GetName(), response)
+}
+
+// selectedNotifications returns the labels to tick for the enabled events
+func selectedNotifications(names []string) []string {
+	var labels []string
+	for _, option := range notificationOptions {
+		if slices.Contains(names, option.name) {
+			labels = append(labels, option.label)
+		}
+	}
+	return labels
+}
+
+// notificationNames converts ticked labels back to event names
+func notificationNames(labels []string) []string {
+	var names []string
+	for _, option := range notificationOptions {
+		if slices.Contains(labels, option.label) {
+			names = append(names, option.name)
+		}
+	}
+	return names
+}
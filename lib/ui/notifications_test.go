@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+func TestDesktopNotifications(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterForWidget(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, true, true, nil, false, false, false)
+	defer dw.Close()
+
+	achievement := character.AchievementDetails{Name: "First Steps", Description: "Fed your pet"}
+	test.AssertNotificationSent(t, nil, func() {
+		dw.notifyAchievement(achievement)
+	})
+
+	dw.SetSettings(config.Settings{Notifications: []string{NotifyCritical, NotifyAchievement, NotifyBattle}}, "")
+
+	test.AssertNotificationSent(t, fyne.NewNotification("🏆 Achievement unlocked", "First Steps: Fed your pet"), func() {
+		dw.notifyAchievement(achievement)
+	})
+	test.AssertNotificationSent(t, fyne.NewNotification("⚔️ Battle invitation", "Rival challenged Pocket to a battle!"), func() {
+		dw.ShowBattleInvitationDialog("Rival", func(bool) {})
+	})
+	test.AssertNotificationSent(t, nil, func() {
+		dw.notifyReminder("Time to stretch!")
+	})
+
+	// Hunger starts below its critical threshold and is announced once
+	test.AssertNotificationSent(t, fyne.NewNotification("Pocket needs you!", "Pocket is critically low: hunger"), func() {
+		dw.checkForCriticalStats()
+	})
+	dw.notifications.checked = dw.notifications.checked.Add(-criticalCheckInterval)
+	test.AssertNotificationSent(t, nil, func() {
+		dw.checkForCriticalStats()
+	})
+}
+
+func TestNotificationLabels(t *testing.T) {
+	names := []string{NotifyBattle, NotifyCritical, "unknown"}
+	labels := selectedNotifications(names)
+	if want := []string{"Critical stats", "Battle invitations"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("selectedNotifications() = %v, want %v", labels, want)
+	}
+	if got := notificationNames(labels); !reflect.DeepEqual(got, []string{NotifyCritical, NotifyBattle}) {
+		t.Errorf("notificationNames() = %v", got)
+	}
+}
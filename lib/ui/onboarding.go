@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+// OnboardingCharacter is an installed character card offered by the wizard
+// and the character switcher
+type OnboardingCharacter struct {
+	Name        string
+	Description string
+	CardPath    string // Path to character.json
+	PreviewPath string // Idle animation shown as a preview, may be empty
+}
+
+// OnboardingResult holds the choices made in the wizard
+type OnboardingResult struct {
+	Settings       config.Settings
+	CreateShortcut bool
+}
+
+// onboardingSteps are the wizard pages in order
+var onboardingSteps = []string{"Choose a companion", "Features", "Saving"}
+
+// OnboardingWizard walks a first-time user through picking a character and
+// the options that are otherwise command line flags
+type OnboardingWizard struct {
+	window     fyne.Window
+	characters []OnboardingCharacter
+	settings   config.Settings
+	onDone     func(OnboardingResult)
+	step       int
+	done       bool
+
+	stepLabel    *widget.Label
+	pages        []fyne.CanvasObject
+	backButton   *widget.Button
+	nextButton   *widget.Button
+	preview      *canvas.Image
+	previewLabel *widget.Label
+
+	characterList  *widget.List
+	gameCheck      *widget.Check
+	statsCheck     *widget.Check
+	networkCheck   *widget.Check
+	networkUICheck *widget.Check
+	saveDirEntry   *widget.Entry
+	shortcutCheck  *widget.Check
+}
+
+// NewOnboardingWizard creates the wizard window. onDone is called once, with
+// the defaults if the user closes the window early.
+func NewOnboardingWizard(app fyne.App, characters []OnboardingCharacter, defaults config.Settings, onDone func(OnboardingResult)) *OnboardingWizard {
+	w := &OnboardingWizard{
+		window:     app.NewWindow("Welcome to Desktop Companion"),
+		characters: characters,
+		settings:   defaults,
+		onDone:     onDone,
+	}
+
+	w.pages = []fyne.CanvasObject{
+		w.createCharacterPage(),
+		w.createFeaturesPage(),
+		w.createSavingPage(),
+	}
+
+	w.stepLabel = widget.NewLabel("")
+	w.stepLabel.TextStyle = fyne.TextStyle{Bold: true}
+	w.backButton = widget.NewButton("Back", w.back)
+	w.nextButton = widget.NewButton("Next", w.next)
+	w.nextButton.Importance = widget.HighImportance
+
+	buttons := container.NewHBox(layout.NewSpacer(), w.backButton, w.nextButton)
+	w.window.SetContent(container.NewBorder(w.stepLabel, buttons, nil, nil, container.NewStack(w.pages...)))
+	w.window.Resize(fyne.NewSize(480, 360))
+	w.window.SetCloseIntercept(func() {
+		// Closing early keeps the defaults rather than asking again next launch
+		w.finish()
+		w.window.Close()
+	})
+
+	w.showStep(0)
+	return w
+}
+
+// Show displays the wizard window
+func (w *OnboardingWizard) Show() {
+	w.window.Show()
+}
+
+// createCharacterPage lists installed characters with a preview of the selection
+func (w *OnboardingWizard) createCharacterPage() fyne.CanvasObject {
+	w.preview = &canvas.Image{FillMode: canvas.ImageFillContain}
+	w.preview.SetMinSize(fyne.NewSize(128, 128))
+	w.previewLabel = widget.NewLabel("")
+	w.previewLabel.Wrapping = fyne.TextWrapWord
+
+	w.characterList = widget.NewList(
+		func() int { return len(w.characters) },
+		func() fyne.CanvasObject { return widget.NewLabel("Character") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(w.characters[id].Name)
+		},
+	)
+	w.characterList.OnSelected = w.selectCharacter
+
+	for i, char := range w.characters {
+		if char.CardPath == w.settings.Character {
+			w.characterList.Select(i)
+		}
+	}
+	if w.settings.Character == "" && len(w.characters) > 0 {
+		w.characterList.Select(0)
+	}
+
+	previewPane := container.NewBorder(w.preview, nil, nil, nil, w.previewLabel)
+	return container.NewHSplit(w.characterList, previewPane)
+}
+
+// createFeaturesPage offers the optional features normally enabled by flags
+func (w *OnboardingWizard) createFeaturesPage() fyne.CanvasObject {
+	w.statsCheck = widget.NewCheck("Show stats overlay", func(on bool) { w.settings.ShowStats = on })
+	w.gameCheck = widget.NewCheck("Game mode: feed, play and care for your companion", func(on bool) {
+		w.settings.GameMode = on
+		enableIf(w.statsCheck, on)
+	})
+	w.networkUICheck = widget.NewCheck("Show network overlay", func(on bool) { w.settings.NetworkUI = on })
+	w.networkCheck = widget.NewCheck("Network mode: meet companions on your local network", func(on bool) {
+		w.settings.Network = on
+		enableIf(w.networkUICheck, on)
+	})
+
+	w.statsCheck.SetChecked(w.settings.ShowStats)
+	w.gameCheck.SetChecked(w.settings.GameMode)
+	w.networkUICheck.SetChecked(w.settings.NetworkUI)
+	w.networkCheck.SetChecked(w.settings.Network)
+	enableIf(w.statsCheck, w.settings.GameMode)
+	enableIf(w.networkUICheck, w.settings.Network)
+
+	return container.NewVBox(
+		w.gameCheck,
+		container.NewPadded(w.statsCheck),
+		widget.NewSeparator(),
+		w.networkCheck,
+		container.NewPadded(w.networkUICheck),
+		widget.NewLabel("You can change these later from ⚙️ Settings in the right-click menu."),
+	)
+}
+
+// createSavingPage asks where progress is saved and offers a desktop shortcut
+func (w *OnboardingWizard) createSavingPage() fyne.CanvasObject {
+	w.saveDirEntry = widget.NewEntry()
+	w.saveDirEntry.SetText(w.settings.SaveDir)
+	w.saveDirEntry.OnChanged = func(text string) { w.settings.SaveDir = strings.TrimSpace(text) }
+
+	w.shortcutCheck = widget.NewCheck("Create a desktop shortcut", nil)
+
+	return container.NewVBox(
+		widget.NewLabel("Game progress is saved automatically to:"),
+		w.saveDirEntry,
+		widget.NewSeparator(),
+		w.shortcutCheck,
+	)
+}
+
+// selectCharacter records the chosen card and shows its preview
+func (w *OnboardingWizard) selectCharacter(id widget.ListItemID) {
+	if id < 0 || id >= len(w.characters) {
+		return
+	}
+	char := w.characters[id]
+	w.settings.Character = char.CardPath
+
+	w.preview.File = char.PreviewPath
+	w.preview.Refresh()
+	w.previewLabel.SetText(char.Description)
+}
+
+// showStep switches the visible page and updates the buttons
+func (w *OnboardingWizard) showStep(step int) {
+	w.step = step
+	for i, page := range w.pages {
+		if i == step {
+			page.Show()
+		} else {
+			page.Hide()
+		}
+	}
+
+	w.stepLabel.SetText(fmt.Sprintf("Step %d of %d: %s", step+1, len(w.pages), onboardingSteps[step]))
+	enableIf(w.backButton, step > 0)
+	if step == len(w.pages)-1 {
+		w.nextButton.SetText("Finish")
+	} else {
+		w.nextButton.SetText("Next")
+	}
+}
+
+// next advances a page, finishing on the last one
+func (w *OnboardingWizard) next() {
+	if w.step == len(w.pages)-1 {
+		w.finish()
+		w.window.Close()
+		return
+	}
+	w.showStep(w.step + 1)
+}
+
+// back returns to the previous page
+func (w *OnboardingWizard) back() {
+	if w.step > 0 {
+		w.showStep(w.step - 1)
+	}
+}
+
+// finish reports the choices once. onDone runs before the wizard window
+// closes so it can open the main window without the app quitting in between.
+func (w *OnboardingWizard) finish() {
+	if w.done {
+		return
+	}
+	w.done = true
+
+	result := OnboardingResult{
+		Settings:       w.settings,
+		CreateShortcut: w.shortcutCheck.Checked,
+	}
+	result.Settings.OnboardingComplete = true
+	// Flag validation rejects these without their parent feature
+	result.Settings.ShowStats = result.Settings.ShowStats && result.Settings.GameMode
+	result.Settings.NetworkUI = result.Settings.NetworkUI && result.Settings.Network
+
+	w.window.Hide()
+	if w.onDone != nil {
+		w.onDone(result)
+	}
+}
+
+// enableIf enables or disables a widget
+func enableIf(d fyne.Disableable, enabled bool) {
+	if enabled {
+		d.Enable()
+	} else {
+		d.Disable()
+	}
+}
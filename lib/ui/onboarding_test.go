@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+func TestOnboardingWizardCollectsChoices(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	characters := []OnboardingCharacter{
+		{Name: "Default", CardPath: "/cards/default/character.json"},
+		{Name: "Tsundere", CardPath: "/cards/tsundere/character.json", Description: "Hmph."},
+	}
+
+	var result *OnboardingResult
+	wizard := NewOnboardingWizard(app, characters, config.Settings{SaveDir: "/saves"}, func(r OnboardingResult) {
+		result = &r
+	})
+	wizard.Show()
+
+	wizard.characterList.Select(1)
+	if wizard.previewLabel.Text != "Hmph." {
+		t.Errorf("Expected the selected character's description, got %q", wizard.previewLabel.Text)
+	}
+
+	wizard.next()
+	if !wizard.statsCheck.Disabled() {
+		t.Error("Stats overlay should be disabled until game mode is on")
+	}
+	wizard.gameCheck.SetChecked(true)
+	wizard.statsCheck.SetChecked(true)
+	wizard.networkUICheck.SetChecked(true) // Without network mode this must be dropped
+
+	wizard.next()
+	wizard.shortcutCheck.SetChecked(true)
+	wizard.next() // Finish
+
+	if result == nil {
+		t.Fatal("Expected onDone to be called")
+	}
+	want := config.Settings{
+		Character:          "/cards/tsundere/character.json",
+		GameMode:           true,
+		ShowStats:          true,
+		SaveDir:            "/saves",
+		OnboardingComplete: true,
+	}
+	if !reflect.DeepEqual(result.Settings, want) || !result.CreateShortcut {
+		t.Errorf("Expected %+v with shortcut, got %+v", want, result)
+	}
+}
+
+func TestOnboardingWizardCloseKeepsDefaults(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	calls := 0
+	wizard := NewOnboardingWizard(app, nil, config.Settings{}, func(r OnboardingResult) {
+		calls++
+		if !r.Settings.OnboardingComplete {
+			t.Error("Closing the wizard should still mark onboarding complete")
+		}
+	})
+	wizard.finish()
+	wizard.finish()
+	if calls != 1 {
+		t.Errorf("Expected onDone to be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,133 @@
+package ui
+
+// pack_share.go lets users hand each other their characters: one offers
+// their character pack to a peer, the other agrees to download it, and the
+// pack lands in the local characters directory once its checksum and
+// manifest check out.
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+	"github.com/opd-ai/desktop-companion/lib/pack"
+)
+
+// SetupPackSharing lets the user share their character with peers and
+// download characters peers share into dir. createPack builds the pack to
+// send and returns its path. Does nothing outside network mode.
+func (dw *DesktopWindow) SetupPackSharing(dir string, createPack func() (string, error)) {
+	if dw.networkOverlay == nil || dw.networkOverlay.GetNetworkManager() == nil {
+		return
+	}
+
+	dw.packTransfers = network.NewFileTransfers(dw.networkOverlay.GetNetworkManager(), dir)
+	dw.createPack = createPack
+	dw.packTransfers.SetOfferHandler(dw.askToDownloadPack)
+	dw.packTransfers.SetProgressHandler(dw.handlePackProgress)
+}
+
+// buildPackShareMenuItem offers sharing our character with a peer
+func (dw *DesktopWindow) buildPackShareMenuItem() (ContextMenuItem, bool) {
+	if dw.packTransfers == nil {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{
+		Text:     "📦 Share My Character",
+		Callback: dw.sharePack,
+	}, true
+}
+
+// sharePack builds our character pack and offers it to a peer, letting the
+// user pick the peer when there are several
+func (dw *DesktopWindow) sharePack() {
+	peers := dw.networkOverlay.GetNetworkManager().GetPeers()
+	if len(peers) == 0 {
+		dw.showDialog("No other players connected. Sharing a character needs a connected peer.")
+		return
+	}
+
+	offer := func(peer network.Peer) {
+		path, err := dw.createPack()
+		if err == nil {
+			_, err = dw.packTransfers.Offer(peer.ID, path)
+		}
+		if err != nil {
+			dw.showDialog(fmt.Sprintf("Failed to share character: %v", err))
+			return
+		}
+		dw.showDialog(fmt.Sprintf("Offered my character pack to %s...", shortPeerID(peer.ID)))
+	}
+	if len(peers) == 1 {
+		offer(peers[0])
+		return
+	}
+	dw.peerSelectionDialog.Show(peers, offer, func() {})
+}
+
+// askToDownloadPack asks the user whether to download a pack a peer offers
+func (dw *DesktopWindow) askToDownloadPack(offer network.FileOffer) {
+	if !pack.IsPack(offer.Name) {
+		_ = dw.packTransfers.Decline(offer.TransferID)
+		return
+	}
+
+	message := fmt.Sprintf("%s wants to share a character pack, %s (%s). Download it?",
+		shortPeerID(offer.PeerID), offer.Name, formatTransferSize(offer.Size))
+	dw.battleInvitationDialog.ShowRequest("Character Pack", message, func(accepted bool) {
+		if !accepted {
+			_ = dw.packTransfers.Decline(offer.TransferID)
+			return
+		}
+		if err := dw.packTransfers.Accept(offer.TransferID); err != nil {
+			dw.showDialog(fmt.Sprintf("Failed to download %s: %v", offer.Name, err))
+		}
+	})
+}
+
+// handlePackProgress announces finished and failed transfers. Downloads are
+// only kept if they are valid packs.
+func (dw *DesktopWindow) handlePackProgress(progress network.FileTransferProgress) {
+	if !progress.Done {
+		return
+	}
+	switch {
+	case progress.Err != nil:
+		dw.showNotice(fmt.Sprintf("Character pack transfer failed: %v", progress.Err))
+	case !progress.Incoming:
+		dw.showNotice(fmt.Sprintf("%s has my character pack now!", shortPeerID(progress.PeerID)))
+	default:
+		name, err := verifyDownloadedPack(progress.Path)
+		if err != nil {
+			os.Remove(progress.Path)
+			dw.showNotice(fmt.Sprintf("%s is not a valid character pack: %v", progress.Name, err))
+			return
+		}
+		dw.showNotice(fmt.Sprintf("Downloaded %s to %s. Start the companion with -character %q to meet them.", name, progress.Path, progress.Path))
+	}
+}
+
+// verifyDownloadedPack checks a downloaded pack against its manifest and
+// returns the character's name
+func verifyDownloadedPack(path string) (string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	manifest, err := pack.Verify(&zr.Reader)
+	if err != nil {
+		return "", err
+	}
+	return manifest.Name, nil
+}
+
+// formatTransferSize shows a file size in KB or MB
+func formatTransferSize(size int64) string {
+	if size < 1<<20 {
+		return fmt.Sprintf("%d KB", (size+1023)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatTransferSize(t *testing.T) {
+	tests := map[int64]string{
+		100:             "1 KB",
+		64 << 10:        "64 KB",
+		3 << 20:         "3.0 MB",
+		(5 << 20) / 2:   "2.5 MB",
+		(1 << 20) - 512: "1024 KB",
+	}
+	for size, want := range tests {
+		if got := formatTransferSize(size); got != want {
+			t.Errorf("formatTransferSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
+
+func TestVerifyDownloadedPackRejectsGarbage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "luna.dcc")
+	if err := os.WriteFile(path, []byte("not a zip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyDownloadedPack(path); err == nil {
+		t.Error("Expected a file that isn't a pack to be rejected")
+	}
+}
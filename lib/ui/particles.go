@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	_ "image/gif" // Register decoders for particle sprites
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// particle is one sprite of a burst
+type particle struct {
+	object   fyne.CanvasObject
+	origin   fyne.Position
+	vx, vy   float32 // Pixels per second
+	start    time.Time
+	duration time.Duration
+}
+
+// ParticleLayer draws short-lived effects (hearts, sweat drops, sparkles)
+// over the character. It holds no input handlers, so clicks pass through.
+type ParticleLayer struct {
+	mu        sync.Mutex
+	container *fyne.Container
+	particles []*particle
+	size      float32
+	images    map[string]image.Image // Decoded sprites, nil for files that failed to load
+}
+
+// NewParticleLayer creates an empty layer covering a character of the given size
+func NewParticleLayer(size int) *ParticleLayer {
+	layer := &ParticleLayer{
+		container: container.NewWithoutLayout(),
+		size:      float32(size),
+		images:    make(map[string]image.Image),
+	}
+	layer.container.Resize(fyne.NewSize(layer.size, layer.size))
+	return layer
+}
+
+// GetContainer returns the canvas object to add over the character
+func (l *ParticleLayer) GetContainer() *fyne.Container {
+	return l.container
+}
+
+// SetSize follows the character when it is resized
+func (l *ParticleLayer) SetSize(size int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.size = float32(size)
+	l.container.Resize(fyne.NewSize(l.size, l.size))
+}
+
+// Add spawns the particles of a burst
+func (l *ParticleLayer) Add(burst character.ParticleBurst) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	spriteSize := float32(math.Max(16, float64(l.size)/8))
+	for i := 0; i < burst.Count; i++ {
+		object := l.newSprite(burst, spriteSize)
+		if object == nil {
+			return
+		}
+		p := &particle{
+			object: object,
+			// Stagger rising and falling sprites so they trickle out
+			start:    burst.StartedAt.Add(time.Duration(i) * burst.Duration / time.Duration(burst.Count*3)),
+			duration: burst.Duration,
+		}
+		l.aim(p, burst.Motion, spriteSize)
+		object.Hide()
+		l.particles = append(l.particles, p)
+		l.container.Add(object)
+	}
+}
+
+// newSprite creates the canvas object for one particle
+func (l *ParticleLayer) newSprite(burst character.ParticleBurst, size float32) fyne.CanvasObject {
+	if !burst.IsImage {
+		text := canvas.NewText(burst.Sprite, color.White)
+		text.TextSize = size * 0.75
+		return text
+	}
+
+	img, loaded := l.images[burst.Sprite]
+	if !loaded {
+		img = loadParticleImage(burst.Sprite)
+		l.images[burst.Sprite] = img
+	}
+	if img == nil {
+		return nil
+	}
+	sprite := canvas.NewImageFromImage(img)
+	sprite.FillMode = canvas.ImageFillContain
+	sprite.Resize(fyne.NewSize(size, size))
+	return sprite
+}
+
+// loadParticleImage decodes a sprite file, logging once if it can't be read
+func loadParticleImage(path string) image.Image {
+	file, err := os.Open(path)
+	if err == nil {
+		defer file.Close()
+		var img image.Image
+		if img, _, err = image.Decode(file); err == nil {
+			return img
+		}
+	}
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"sprite": path,
+		"error":  err.Error(),
+	}).Warn("Particle sprite unavailable, effect skipped")
+	return nil
+}
+
+// aim picks a particle's starting point and velocity for its motion
+func (l *ParticleLayer) aim(p *particle, motion string, spriteSize float32) {
+	size := l.size
+	spread := func(from, to float32) float32 { return from + rand.Float32()*(to-from) }
+
+	switch motion {
+	case character.ParticleMotionFall:
+		// Drip from the forehead
+		p.origin = fyne.NewPos(spread(0.3, 0.7)*size, spread(0.1, 0.25)*size)
+		p.vx, p.vy = spread(-0.05, 0.05)*size, spread(0.25, 0.4)*size
+	case character.ParticleMotionBurst:
+		angle := rand.Float64() * 2 * math.Pi
+		speed := spread(0.3, 0.5) * size
+		p.origin = fyne.NewPos(size/2-spriteSize/2, size/2-spriteSize/2)
+		p.vx, p.vy = float32(math.Cos(angle))*speed, float32(math.Sin(angle))*speed
+	default:
+		// Float up from around the chest
+		p.origin = fyne.NewPos(spread(0.2, 0.8)*size-spriteSize/2, spread(0.45, 0.65)*size)
+		p.vx, p.vy = spread(-0.08, 0.08)*size, -spread(0.3, 0.45)*size
+	}
+}
+
+// Update moves and fades particles, removing finished ones. Returns true
+// while any are still running so the animation loop keeps its frame rate.
+func (l *ParticleLayer) Update(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	active := l.particles[:0]
+	for _, p := range l.particles {
+		elapsed := now.Sub(p.start)
+		if elapsed >= p.duration {
+			l.container.Remove(p.object)
+			continue
+		}
+		active = append(active, p)
+		if elapsed < 0 {
+			continue
+		}
+
+		seconds := float32(elapsed.Seconds())
+		p.object.Move(fyne.NewPos(p.origin.X+p.vx*seconds, p.origin.Y+p.vy*seconds))
+		fade(p.object, float64(elapsed)/float64(p.duration))
+		p.object.Show()
+		p.object.Refresh()
+	}
+	l.particles = active
+	return len(l.particles) > 0
+}
+
+// fade makes a particle more transparent as it nears the end of its life;
+// progress runs from 0 to 1
+func fade(object fyne.CanvasObject, progress float64) {
+	// Stay solid for the first half, then fade out
+	opacity := 1.0
+	if progress > 0.5 {
+		opacity = 1 - (progress-0.5)*2
+	}
+
+	switch o := object.(type) {
+	case *canvas.Image:
+		o.Translucency = 1 - opacity
+	case *canvas.Text:
+		o.Color = color.NRGBA{R: 255, G: 255, B: 255, A: uint8(opacity * 255)}
+	}
+}
+
+// Active reports whether any particles are on screen
+func (l *ParticleLayer) Active() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.particles) > 0
+}
+
+// checkForParticleBursts draws effects the character fired since the last frame
+func (dw *DesktopWindow) checkForParticleBursts() {
+	if dw.particles == nil {
+		return
+	}
+	// Bursts are dropped while slow frames have particles turned off
+	bursts := dw.character.GetParticleBursts()
+	if dw.degradation.ParticlesEnabled() {
+		for _, burst := range bursts {
+			dw.particles.Add(burst)
+		}
+	}
+	dw.particles.Update(time.Now())
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/canvas"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+func TestParticleLayerLifecycle(t *testing.T) {
+	layer := NewParticleLayer(128)
+	start := time.Now()
+	layer.Add(character.ParticleBurst{
+		Sprite:    "❤",
+		Motion:    character.ParticleMotionRise,
+		Duration:  time.Second,
+		Count:     3,
+		StartedAt: start,
+	})
+
+	if got := len(layer.GetContainer().Objects); got != 3 {
+		t.Fatalf("Expected 3 particles, got %d", got)
+	}
+	if !layer.Update(start.Add(100 * time.Millisecond)) {
+		t.Fatal("Particles should be active mid-burst")
+	}
+
+	first := layer.GetContainer().Objects[0].(*canvas.Text)
+	if !first.Visible() || first.Position().Y >= layer.particles[0].origin.Y {
+		t.Errorf("Rising particles should be shown and move up, got %v from %v", first.Position(), layer.particles[0].origin)
+	}
+
+	layer.Update(start.Add(900 * time.Millisecond))
+	if c, ok := first.Color.(color.NRGBA); !ok || c.A == 255 {
+		t.Errorf("Particles should fade near the end, got %v", first.Color)
+	}
+
+	if layer.Update(start.Add(2 * time.Second)) {
+		t.Error("Particles should be gone after their duration")
+	}
+	if got := len(layer.GetContainer().Objects); got != 0 {
+		t.Errorf("Finished particles should be removed, %d left", got)
+	}
+}
+
+func TestParticleLayerMissingImage(t *testing.T) {
+	layer := NewParticleLayer(128)
+	layer.Add(character.ParticleBurst{
+		Sprite:    "/nonexistent/sparkle.png",
+		IsImage:   true,
+		Duration:  time.Second,
+		Count:     2,
+		StartedAt: time.Now(),
+	})
+	if layer.Active() {
+		t.Error("Unreadable sprites should be skipped")
+	}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// setupPeerChat lets the character chat with idle peers when the card enables it
+func (dw *DesktopWindow) setupPeerChat(networkManager NetworkManagerInterface, char *character.Character) {
+	card := char.GetCard()
+	if card.Multiplayer == nil || card.Multiplayer.PeerChat == nil || !card.Multiplayer.PeerChat.Enabled {
+		return
+	}
+
+	dw.peerChat = network.NewConversationManager(networkManager, char, *card.Multiplayer.PeerChat)
+	dw.peerChat.SetLineHandler(dw.showPeerChatLine)
+	dw.peerChat.Start()
+}
+
+// showPeerChatLine renders one conversation line as a speech bubble.
+// Remote lines are attributed to the peer character so the two sides are easy to tell apart.
+func (dw *DesktopWindow) showPeerChatLine(line network.ConversationLineEvent) {
+	if line.Remote {
+		dw.ShowAttributedDialog("💬 "+line.Speaker, line.Text)
+		if dw.networkOverlay != nil {
+			dw.networkOverlay.addChatMessage(line.Speaker, line.Text)
+		}
+		return
+	}
+	dw.showDialog(line.Text)
+}
+
+// stopPeerChat ends peer conversations when the window closes
+func (dw *DesktopWindow) stopPeerChat() {
+	if dw.peerChat != nil {
+		dw.peerChat.Stop()
+	}
+}
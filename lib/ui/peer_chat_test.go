@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+func TestShowPeerChatLine(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dw.showPeerChatLine(network.ConversationLineEvent{Speaker: "Bob", Text: "Nice weather!", Remote: true})
+	if text := dw.dialog.currentText; !strings.Contains(text, "Nice weather!") || !strings.Contains(text, "Bob") {
+		t.Errorf("Remote line should be attributed to the peer, got %q", text)
+	}
+
+	dw.showPeerChatLine(network.ConversationLineEvent{Speaker: char.GetName(), Text: "It sure is."})
+	if text := dw.dialog.currentText; text != "It sure is." {
+		t.Errorf("Local line should show as plain speech, got %q", text)
+	}
+
+	// No peerChat config: setup is a no-op and Close must not panic
+	dw.setupPeerChat(nil, char)
+	if dw.peerChat != nil {
+		t.Error("Peer chat should stay off without card config")
+	}
+	dw.stopPeerChat()
+}
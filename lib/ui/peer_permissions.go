@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// PeerPermissionsView edits what one peer may send us. Changes apply as
+// soon as a box is ticked; the network manager drops anything not allowed
+// before it reaches the UI.
+type PeerPermissionsView struct {
+	content    *fyne.Container
+	titleLabel *widget.Label
+	battles    *widget.Check
+	gifts      *widget.Check
+	chats      *widget.Check
+	blocked    *widget.Check
+
+	store   *network.PermissionStore
+	peerID  string
+	loading bool // Suppresses saves while the checks are being filled in
+	onSaved func()
+}
+
+// NewPeerPermissionsView creates a hidden permissions editor; onSaved is
+// called after a change is stored, e.g. to refresh the peer list
+func NewPeerPermissionsView(onSaved func()) *PeerPermissionsView {
+	v := &PeerPermissionsView{titleLabel: widget.NewLabel(""), onSaved: onSaved}
+	v.titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	v.battles = widget.NewCheck("Allow battles", func(bool) { v.save() })
+	v.gifts = widget.NewCheck("Allow gifts", func(bool) { v.save() })
+	v.chats = widget.NewCheck("Allow chats", func(bool) { v.save() })
+	v.blocked = widget.NewCheck("Block peer", func(bool) { v.save() })
+
+	v.content = container.NewVBox(
+		v.titleLabel,
+		v.battles, v.gifts, v.chats, v.blocked,
+		widget.NewButton("Done", v.Hide),
+	)
+	v.content.Hide()
+	return v
+}
+
+// Show opens the editor for a peer
+func (v *PeerPermissionsView) Show(store *network.PermissionStore, peerID string) {
+	v.store = store
+	v.peerID = peerID
+	perms := store.Get(peerID)
+
+	v.loading = true
+	v.titleLabel.SetText(fmt.Sprintf("🔒 Permissions: %s", peerID))
+	v.battles.SetChecked(perms.AllowBattles)
+	v.gifts.SetChecked(perms.AllowGifts)
+	v.chats.SetChecked(perms.AllowChats)
+	v.blocked.SetChecked(perms.Blocked)
+	v.loading = false
+
+	v.updateEnabled()
+	v.content.Show()
+}
+
+// Hide closes the editor
+func (v *PeerPermissionsView) Hide() {
+	v.content.Hide()
+}
+
+// GetContainer returns the editor's container for embedding in the overlay
+func (v *PeerPermissionsView) GetContainer() *fyne.Container {
+	return v.content
+}
+
+// updateEnabled greys out the individual permissions while a peer is
+// blocked, since blocking overrides them
+func (v *PeerPermissionsView) updateEnabled() {
+	for _, check := range []*widget.Check{v.battles, v.gifts, v.chats} {
+		if v.blocked.Checked {
+			check.Disable()
+		} else {
+			check.Enable()
+		}
+	}
+}
+
+// save stores the current checkboxes for the peer
+func (v *PeerPermissionsView) save() {
+	if v.loading || v.store == nil || v.peerID == "" {
+		return
+	}
+	v.updateEnabled()
+
+	perms := network.PeerPermissions{
+		AllowBattles: v.battles.Checked,
+		AllowGifts:   v.gifts.Checked,
+		AllowChats:   v.chats.Checked,
+		Blocked:      v.blocked.Checked,
+	}
+	if err := v.store.Set(v.peerID, perms); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"peerID": v.peerID,
+			"error":  err.Error(),
+		}).Warn("Failed to save peer permissions")
+	}
+	if v.onSaved != nil {
+		v.onSaved()
+	}
+}
+
+// SetPermissionStore lets the overlay edit per-peer permissions. Without a
+// store, tapping a peer does nothing.
+func (no *NetworkOverlay) SetPermissionStore(store *network.PermissionStore) {
+	no.peerMutex.Lock()
+	defer no.peerMutex.Unlock()
+	no.permissions = store
+}
+
+// showPeerPermissions opens the permissions editor for the tapped peer
+func (no *NetworkOverlay) showPeerPermissions(id widget.ListItemID) {
+	no.peerList.UnselectAll()
+
+	no.peerMutex.RLock()
+	store := no.permissions
+	if store == nil || id >= len(no.peers) {
+		no.peerMutex.RUnlock()
+		return
+	}
+	peerID := no.peers[id].ID
+	no.peerMutex.RUnlock()
+
+	no.permissionsView.Show(store, peerID)
+}
+
+// peerStatusIcon shows a peer's connection state, or that it is blocked.
+// Must be called with no.peerMutex held.
+func (no *NetworkOverlay) peerStatusIcon(peer network.Peer) string {
+	if no.permissions != nil && no.permissions.Get(peer.ID).Blocked {
+		return "🚫"
+	}
+	if peer.Conn != nil {
+		return "🟢" // Connected
+	}
+	return "🔴" // Disconnected
+}
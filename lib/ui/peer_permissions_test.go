@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+func TestNetworkOverlay_PeerPermissions(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	mockNM := NewMockNetworkManager()
+	mockNM.AddPeer("rival", true)
+	overlay := NewNetworkOverlay(mockNM)
+	overlay.updatePeerList()
+
+	// Without a store, tapping a peer does nothing
+	overlay.showPeerPermissions(0)
+	if overlay.permissionsView.GetContainer().Visible() {
+		t.Fatal("Permissions editor should need a store")
+	}
+
+	store, _ := network.NewPermissionStore("")
+	overlay.SetPermissionStore(store)
+	overlay.showPeerPermissions(0)
+	if !overlay.permissionsView.GetContainer().Visible() {
+		t.Fatal("Expected the permissions editor to open")
+	}
+
+	view := overlay.permissionsView
+	if !view.battles.Checked || view.blocked.Checked {
+		t.Error("Expected the editor to start from the defaults")
+	}
+
+	view.battles.SetChecked(false)
+	if store.Allows("rival", network.PermissionBattles) || !store.Allows("rival", network.PermissionChats) {
+		t.Errorf("Expected only battles to be refused, got %+v", store.Get("rival"))
+	}
+
+	view.blocked.SetChecked(true)
+	if !store.Get("rival").Blocked || !view.gifts.Disabled() {
+		t.Error("Expected blocking to be saved and the other boxes greyed out")
+	}
+
+	overlay.peerMutex.RLock()
+	icon := overlay.peerStatusIcon(overlay.peers[0])
+	overlay.peerMutex.RUnlock()
+	if icon != "🚫" {
+		t.Errorf("Expected blocked peers to be marked, got %s", icon)
+	}
+}
@@ -0,0 +1,177 @@
+package ui
+
+// perch.go implements perch mode: the character sits on the top edge of
+// the focused window and follows it as it moves. The focused window comes
+// from the platform (X11, Windows, macOS); Wayland keeps other clients'
+// windows private, so the mode isn't offered there.
+
+import (
+	"errors"
+	"image"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+const perchPollInterval = 250 * time.Millisecond // How often the focused window is checked
+
+// perchState tracks perch mode for the window
+type perchState struct {
+	mu      sync.Mutex
+	enabled bool
+	stop    chan struct{}   // Stops the follow loop
+	target  image.Rectangle // Frame of the window last perched on
+}
+
+// SetPerchMode turns perch mode on, moving the character onto the focused
+// window, or off, leaving it where it sits
+func (dw *DesktopWindow) SetPerchMode(enabled bool) {
+	dw.perch.mu.Lock()
+	defer dw.perch.mu.Unlock()
+
+	if enabled == dw.perch.enabled {
+		return
+	}
+	dw.perch.enabled = enabled
+	if !enabled {
+		close(dw.perch.stop)
+		dw.perch.stop = nil
+		return
+	}
+
+	dw.perch.stop = make(chan struct{})
+	dw.perch.target = image.Rectangle{}
+	go dw.followFocusedWindow(dw.perch.stop)
+}
+
+// IsPerchMode returns whether perch mode is on
+func (dw *DesktopWindow) IsPerchMode() bool {
+	dw.perch.mu.Lock()
+	defer dw.perch.mu.Unlock()
+	return dw.perch.enabled
+}
+
+// followFocusedWindow keeps the character on the focused window until stop
+// is closed
+func (dw *DesktopWindow) followFocusedWindow(stop chan struct{}) {
+	ticker := time.NewTicker(perchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		dw.perchOnFocusedWindow()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// perchOnFocusedWindow moves the character onto the focused window when
+// that window has moved, resized or changed since the last check. In
+// between the character stays put, so it can be dragged along the edge.
+func (dw *DesktopWindow) perchOnFocusedWindow() {
+	if dw.isShyHidden() || dw.overlayVisible() {
+		return
+	}
+
+	// The lock isn't held across RunNative, which waits for the main thread
+	dw.perch.mu.Lock()
+	enabled, last := dw.perch.enabled, dw.perch.target
+	dw.perch.mu.Unlock()
+	if !enabled {
+		return
+	}
+
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		target, err := native.FocusedWindowFrame(win)
+		if err != nil {
+			if !errors.Is(err, native.ErrNoFocusedWindow) && !errors.Is(err, native.ErrPositionUnsupported) {
+				logrus.WithError(err).Warn("Failed to read the focused window")
+			}
+			return
+		}
+		if target == last {
+			return
+		}
+
+		frame, screen, err := native.WindowGeometry(win)
+		if err != nil {
+			logrus.WithError(err).Debug("Window can't be moved, not perching")
+			return
+		}
+		dw.perch.mu.Lock()
+		dw.perch.target = target
+		dw.perch.mu.Unlock()
+
+		spot, ok := perchSpot(frame, screen, target, last)
+		if !ok || spot == frame.Min {
+			return
+		}
+		if err := native.MoveWindow(win, spot.X, spot.Y); err != nil {
+			logrus.WithError(err).Debug("Failed to move the character onto the focused window")
+			return
+		}
+		dw.character.SetPosition(float32(spot.X), float32(spot.Y))
+	})
+}
+
+// perchSpot returns where the window goes to sit on top of target. When
+// target is last moved, the character keeps its place along the edge;
+// otherwise it lands as close to where it is as the edge allows. Returns
+// false when the spot would be off-screen, e.g. above a maximized window.
+func perchSpot(frame, screen, target, last image.Rectangle) (image.Point, bool) {
+	y := target.Min.Y - frame.Dy()
+	if y < screen.Min.Y || target.Min.Y >= screen.Max.Y {
+		return image.Point{}, false
+	}
+
+	x := frame.Min.X
+	if last.Size() == target.Size() && !last.Empty() {
+		x += target.Min.X - last.Min.X
+	}
+	if target.Dx() <= frame.Dx() {
+		x = target.Min.X + (target.Dx()-frame.Dx())/2
+	} else {
+		x = max(target.Min.X, min(x, target.Max.X-frame.Dx()))
+	}
+	x = max(screen.Min.X, min(x, screen.Max.X-frame.Dx()))
+	return image.Pt(x, y), true
+}
+
+// stopPerchMode ends the follow loop when the window closes
+func (dw *DesktopWindow) stopPerchMode() {
+	dw.SetPerchMode(false)
+}
+
+// buildPerchMenuItem creates the toggle for sitting on the focused window.
+// Wayland doesn't tell clients where other windows are, so there is none
+// there.
+func (dw *DesktopWindow) buildPerchMenuItem() (ContextMenuItem, bool) {
+	dw.layer.mu.Lock()
+	onOverlay := dw.layer.overlay != nil
+	dw.layer.mu.Unlock()
+	if onOverlay || os.Getenv("WAYLAND_DISPLAY") != "" {
+		return ContextMenuItem{}, false
+	}
+
+	if dw.IsPerchMode() {
+		return ContextMenuItem{
+			Text: "🪑 Stop Sitting on Windows",
+			Callback: func() {
+				dw.SetPerchMode(false)
+			},
+		}, true
+	}
+
+	return ContextMenuItem{
+		Text: "🪑 Sit on Windows",
+		Callback: func() {
+			dw.SetPerchMode(true)
+		},
+	}, true
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPerchSpot(t *testing.T) {
+	screen := image.Rect(0, 0, 1920, 1080)
+	frame := image.Rect(100, 100, 228, 228)
+	target := image.Rect(400, 300, 1200, 900)
+
+	tests := []struct {
+		name   string
+		frame  image.Rectangle
+		target image.Rectangle
+		last   image.Rectangle
+		want   image.Point
+		ok     bool
+	}{
+		{"lands on the nearest part of the edge", frame, target, image.Rectangle{}, image.Pt(400, 172), true},
+		{"keeps its place when the window moves", image.Rect(600, 172, 728, 300), target.Add(image.Pt(50, 20)), target, image.Pt(650, 192), true},
+		{"is centered on narrow windows", frame, image.Rect(400, 300, 500, 600), image.Rectangle{}, image.Pt(386, 172), true},
+		{"skips maximized windows", frame, screen, image.Rectangle{}, image.Point{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := perchSpot(tt.frame, screen, tt.target, tt.last)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("perchSpot() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Photo backgrounds may be JPEGs
+	"io"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// photoChooseImage is the background option that opens a file picker
+const photoChooseImage = "Image file..."
+
+// Background images larger than these are refused rather than decoded
+const (
+	maxPhotoBackgroundBytes  = 32 << 20
+	maxPhotoBackgroundPixels = 64 << 20 // About 8K x 8K
+)
+
+// PhotoModeDialog composes a picture of the character in any of its
+// animations, on a built-in backdrop or an image file, with an optional
+// caption sticker, and exports it as a PNG to the captures folder
+type PhotoModeDialog struct {
+	window    fyne.Window
+	character *character.Character
+
+	background     image.Image // Chosen image file, nil when a backdrop is used
+	backgroundName string
+	backdrop       photoBackdrop
+	pose           string
+	caption        string
+
+	backgroundSelect *widget.Select
+	preview          *canvas.Image
+	statusLabel      *widget.Label
+}
+
+// NewPhotoModeDialog creates the photo mode window for char
+func NewPhotoModeDialog(app fyne.App, char *character.Character) *PhotoModeDialog {
+	d := &PhotoModeDialog{
+		window:    app.NewWindow("Photo Mode"),
+		character: char,
+		backdrop:  photoBackdrops[1],
+		pose:      char.GetCurrentState(),
+	}
+
+	options := make([]string, 0, len(photoBackdrops)+1)
+	for _, backdrop := range photoBackdrops {
+		options = append(options, backdrop.Name)
+	}
+	options = append(options, photoChooseImage)
+	d.backgroundSelect = widget.NewSelect(options, d.selectBackground)
+	d.backgroundSelect.SetSelected(d.backdrop.Name)
+
+	poses := char.GetAvailableAnimations()
+	sort.Strings(poses)
+	poseSelect := widget.NewSelect(poses, func(pose string) {
+		d.pose = pose
+		d.refresh()
+	})
+	poseSelect.SetSelected(d.pose)
+
+	captionEntry := widget.NewEntry()
+	captionEntry.SetPlaceHolder("Caption (optional)")
+	captionEntry.OnChanged = func(text string) {
+		if runes := []rune(text); len(runes) > maxPhotoCaptionRunes {
+			captionEntry.SetText(string(runes[:maxPhotoCaptionRunes]))
+			return
+		}
+		d.caption = text
+		d.refresh()
+	}
+
+	d.preview = canvas.NewImageFromImage(nil)
+	d.preview.FillMode = canvas.ImageFillContain
+	d.preview.SetMinSize(fyne.NewSize(280, 280))
+	d.statusLabel = widget.NewLabel("Photos are saved to your Pictures folder.")
+	d.statusLabel.Wrapping = fyne.TextWrapWord
+
+	form := container.NewGridWithColumns(2,
+		widget.NewLabel("Background:"), d.backgroundSelect,
+		widget.NewLabel("Pose:"), poseSelect,
+		widget.NewLabel("Caption:"), captionEntry,
+	)
+	d.window.SetContent(container.NewVBox(
+		d.preview,
+		form,
+		widget.NewButton("💾 Export PNG", d.export),
+		d.statusLabel,
+	))
+	d.window.Resize(fyne.NewSize(360, 0))
+	d.refresh()
+	return d
+}
+
+// Show displays the photo mode window
+func (d *PhotoModeDialog) Show() {
+	d.window.Show()
+}
+
+// SetOnClosed sets a callback for when the window closes
+func (d *PhotoModeDialog) SetOnClosed(onClosed func()) {
+	d.window.SetOnClosed(onClosed)
+}
+
+// RequestFocus brings the photo mode window to the front
+func (d *PhotoModeDialog) RequestFocus() {
+	d.window.RequestFocus()
+}
+
+// selectBackground switches to a built-in backdrop, or asks for an image file
+func (d *PhotoModeDialog) selectBackground(name string) {
+	if name != photoChooseImage {
+		if backdrop, ok := findPhotoBackdrop(name); ok {
+			d.background, d.backgroundName, d.backdrop = nil, "", backdrop
+			d.refresh()
+		}
+		return
+	}
+
+	picker := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			d.restoreBackgroundSelection()
+			return
+		}
+		defer reader.Close()
+
+		img, err := decodePhotoBackground(reader)
+		if err != nil {
+			d.statusLabel.SetText(fmt.Sprintf("Can't use %s: %v", reader.URI().Name(), err))
+			d.restoreBackgroundSelection()
+			return
+		}
+		d.background, d.backgroundName = img, reader.URI().Name()
+		d.statusLabel.SetText(fmt.Sprintf("Background: %s", d.backgroundName))
+		d.refresh()
+	}, d.window)
+	picker.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg", ".gif"}))
+	picker.Show()
+}
+
+// restoreBackgroundSelection shows the background in use again after the
+// file picker was cancelled or failed
+func (d *PhotoModeDialog) restoreBackgroundSelection() {
+	if d.background == nil {
+		d.backgroundSelect.SetSelected(d.backdrop.Name)
+	}
+}
+
+// decodePhotoBackground decodes a PNG, JPEG or GIF, refusing huge images
+func decodePhotoBackground(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPhotoBackgroundBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxPhotoBackgroundBytes {
+		return nil, fmt.Errorf("file is larger than %d MB", maxPhotoBackgroundBytes>>20)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("not a supported image: %w", err)
+	}
+	if config.Width*config.Height > maxPhotoBackgroundPixels {
+		return nil, fmt.Errorf("image is too large (%dx%d)", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// compose draws the photo with the current choices
+func (d *PhotoModeDialog) compose() *image.RGBA {
+	var pose image.Image
+	if anim, ok := d.character.GetStateAnimation(d.pose); ok {
+		pose = poseFrame(anim)
+	}
+	return composePhoto(d.background, d.backdrop, pose, d.caption)
+}
+
+// refresh redraws the preview
+func (d *PhotoModeDialog) refresh() {
+	if d.preview == nil {
+		return // Still building the window
+	}
+	d.preview.Image = d.compose()
+	d.preview.Refresh()
+}
+
+// export saves the composed photo as a PNG in the captures folder
+func (d *PhotoModeDialog) export() {
+	path, err := capturePath(d.character.GetName()+"_photo", "png")
+	if err == nil {
+		err = savePNG(d.compose(), path)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Warn("Photo export failed")
+		d.statusLabel.SetText(fmt.Sprintf("Export failed: %v", err))
+		return
+	}
+	d.statusLabel.SetText(fmt.Sprintf("📸 Saved %s", path))
+}
+
+// buildPhotoModeMenuItem creates the photo mode entry, shown next to capture
+func (dw *DesktopWindow) buildPhotoModeMenuItem() ContextMenuItem {
+	return ContextMenuItem{Text: "📸 Photo Mode", Callback: dw.showPhotoMode}
+}
+
+// showPhotoMode opens the photo mode window, or focuses it if it's open
+func (dw *DesktopWindow) showPhotoMode() {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+
+	if dw.photoDialog != nil {
+		dw.photoDialog.RequestFocus()
+		return
+	}
+
+	dw.photoDialog = NewPhotoModeDialog(fyne.CurrentApp(), dw.character)
+	dw.photoDialog.SetOnClosed(func() {
+		dw.settingsMu.Lock()
+		dw.photoDialog = nil
+		dw.settingsMu.Unlock()
+	})
+	dw.photoDialog.Show()
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Photo layout
+const (
+	photoDefaultSize      = 512 // Width and height of a photo on a built-in backdrop
+	photoMaxSize          = 2048
+	photoPosePercent      = 60 // Height of the pose box as a percentage of the photo
+	photoMargin           = 16 // Gap between the photo's edge and the pose or caption, before scaling
+	photoCaptionLineRunes = 24 // Caption lines are wrapped at this many characters
+	photoCaptionMaxLines  = 3
+	photoCaptionPadding   = 6 // Space around the caption text inside the sticker, before scaling
+	maxPhotoCaptionRunes  = photoCaptionLineRunes * photoCaptionMaxLines
+)
+
+// Caption sticker colors
+var (
+	photoStickerFill   = color.RGBA{R: 255, G: 255, B: 255, A: 235}
+	photoStickerBorder = color.RGBA{R: 40, G: 40, B: 60, A: 255}
+	photoStickerText   = color.RGBA{R: 30, G: 30, B: 40, A: 255}
+)
+
+// photoBackdrop is a built-in background drawn at any size
+type photoBackdrop struct {
+	Name        string
+	Top, Bottom color.RGBA // Vertical gradient; both zero for a transparent photo
+}
+
+// photoBackdrops are offered before any image file in photo mode
+var photoBackdrops = []photoBackdrop{
+	{Name: "Transparent"},
+	{Name: "Sky", Top: color.RGBA{R: 120, G: 180, B: 240, A: 255}, Bottom: color.RGBA{R: 230, G: 244, B: 255, A: 255}},
+	{Name: "Sunset", Top: color.RGBA{R: 90, G: 60, B: 140, A: 255}, Bottom: color.RGBA{R: 255, G: 160, B: 90, A: 255}},
+	{Name: "Night", Top: color.RGBA{R: 10, G: 12, B: 40, A: 255}, Bottom: color.RGBA{R: 40, G: 50, B: 100, A: 255}},
+	{Name: "Meadow", Top: color.RGBA{R: 170, G: 220, B: 250, A: 255}, Bottom: color.RGBA{R: 110, G: 190, B: 90, A: 255}},
+}
+
+// findPhotoBackdrop returns the built-in backdrop with the name
+func findPhotoBackdrop(name string) (photoBackdrop, bool) {
+	for _, backdrop := range photoBackdrops {
+		if backdrop.Name == name {
+			return backdrop, true
+		}
+	}
+	return photoBackdrop{}, false
+}
+
+// draw fills dst with the backdrop's gradient
+func (b photoBackdrop) draw(dst *image.RGBA) {
+	if b.Top == (color.RGBA{}) && b.Bottom == (color.RGBA{}) {
+		return
+	}
+	bounds := dst.Bounds()
+	height := bounds.Dy()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := color.RGBA{
+			R: blendChannel(b.Top.R, b.Bottom.R, y-bounds.Min.Y, height),
+			G: blendChannel(b.Top.G, b.Bottom.G, y-bounds.Min.Y, height),
+			B: blendChannel(b.Top.B, b.Bottom.B, y-bounds.Min.Y, height),
+			A: blendChannel(b.Top.A, b.Bottom.A, y-bounds.Min.Y, height),
+		}
+		draw.Draw(dst, image.Rect(bounds.Min.X, y, bounds.Max.X, y+1), image.NewUniform(row), image.Point{}, draw.Src)
+	}
+}
+
+// blendChannel mixes from and to at step of steps
+func blendChannel(from, to uint8, step, steps int) uint8 {
+	if steps <= 1 {
+		return from
+	}
+	return uint8(int(from) + (int(to)-int(from))*step/(steps-1))
+}
+
+// photoCanvasSize returns the photo's size: the background's, shrunk to
+// photoMaxSize, or a square for built-in backdrops
+func photoCanvasSize(background image.Image) image.Point {
+	if background == nil || background.Bounds().Empty() {
+		return image.Pt(photoDefaultSize, photoDefaultSize)
+	}
+	w, h := background.Bounds().Dx(), background.Bounds().Dy()
+	if w > photoMaxSize || h > photoMaxSize {
+		if w >= h {
+			h = h * photoMaxSize / w
+			w = photoMaxSize
+		} else {
+			w = w * photoMaxSize / h
+			h = photoMaxSize
+		}
+	}
+	return image.Pt(max(w, 1), max(h, 1))
+}
+
+// composePhoto draws the pose standing at the bottom of the background, or
+// of the backdrop when background is nil, with the caption as a sticker
+// across the top. Pixel art stays crisp: the pose and caption are scaled
+// with nearest-neighbour sampling.
+func composePhoto(background image.Image, backdrop photoBackdrop, pose image.Image, caption string) *image.RGBA {
+	size := photoCanvasSize(background)
+	photo := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	if background != nil {
+		xdraw.CatmullRom.Scale(photo, photo.Bounds(), background, background.Bounds(), draw.Src, nil)
+	} else {
+		backdrop.draw(photo)
+	}
+
+	scale := photoScale(size)
+	margin := photoMargin * scale
+	if pose != nil && !pose.Bounds().Empty() {
+		poseHeight := size.Y * photoPosePercent / 100
+		box := image.Rect(margin, size.Y-margin-poseHeight, size.X-margin, size.Y-margin)
+		// Stand the pose on the bottom of its box rather than centring it
+		target := fitRect(pose.Bounds(), box)
+		target = target.Add(image.Pt(0, box.Max.Y-target.Max.Y))
+		xdraw.NearestNeighbor.Scale(photo, target, pose, pose.Bounds(), draw.Over, nil)
+	}
+
+	if sticker := captionSticker(caption); sticker != nil {
+		// Shrink the sticker's scale until it fits across the photo
+		stickerScale := scale
+		for stickerScale > 1 && sticker.Bounds().Dx()*stickerScale > size.X-2*margin {
+			stickerScale--
+		}
+		w, h := sticker.Bounds().Dx()*stickerScale, sticker.Bounds().Dy()*stickerScale
+		x := (size.X - w) / 2
+		target := image.Rect(x, margin, x+w, margin+h)
+		xdraw.NearestNeighbor.Scale(photo, target, sticker, sticker.Bounds(), draw.Over, nil)
+	}
+	return photo
+}
+
+// photoScale is how much the pose margin and caption grow with the photo,
+// so a caption reads the same on a small or large photo
+func photoScale(size image.Point) int {
+	return max(1, min(size.X, size.Y)/200)
+}
+
+// fitRect returns the largest rectangle with src's aspect ratio centred in box
+func fitRect(src, box image.Rectangle) image.Rectangle {
+	w, h := src.Dx(), src.Dy()
+	if w == 0 || h == 0 || box.Empty() {
+		return image.Rectangle{}
+	}
+	if w*box.Dy() > h*box.Dx() {
+		h = h * box.Dx() / w
+		w = box.Dx()
+	} else {
+		w = w * box.Dy() / h
+		h = box.Dy()
+	}
+	x := box.Min.X + (box.Dx()-w)/2
+	y := box.Min.Y + (box.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// captionSticker renders the caption unscaled on a bordered white label,
+// nil for a blank caption
+func captionSticker(caption string) *image.RGBA {
+	lines := wrapCaption(caption)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Face: face}
+	width := 0
+	for _, line := range lines {
+		width = max(width, drawer.MeasureString(line).Ceil())
+	}
+	lineHeight := face.Metrics().Height.Ceil()
+	w := width + 2*photoCaptionPadding
+	h := lineHeight*len(lines) + 2*photoCaptionPadding
+
+	sticker := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(sticker, sticker.Bounds(), image.NewUniform(photoStickerBorder), image.Point{}, draw.Src)
+	draw.Draw(sticker, sticker.Bounds().Inset(1), image.NewUniform(photoStickerFill), image.Point{}, draw.Src)
+
+	drawer.Dst = sticker
+	drawer.Src = image.NewUniform(photoStickerText)
+	ascent := face.Metrics().Ascent.Ceil()
+	for i, line := range lines {
+		x := (w - drawer.MeasureString(line).Ceil()) / 2
+		drawer.Dot = fixed.P(x, photoCaptionPadding+i*lineHeight+ascent)
+		drawer.DrawString(line)
+	}
+	return sticker
+}
+
+// wrapCaption splits a caption into at most photoCaptionMaxLines lines of
+// photoCaptionLineRunes, breaking between words where it can. The basic font
+// only has ASCII glyphs, so other characters show as '?'.
+func wrapCaption(caption string) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(caption) {
+		runes := []rune(word)
+		for len(runes) > 0 {
+			room := photoCaptionLineRunes - len(line)
+			if len(line) > 0 {
+				room-- // The space before the word
+			}
+			if len(runes) <= room {
+				if len(line) > 0 {
+					line = append(line, ' ')
+				}
+				line = append(line, runes...)
+				runes = nil
+				continue
+			}
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+				continue
+			}
+			// A word longer than a whole line is split
+			lines = append(lines, string(runes[:photoCaptionLineRunes]))
+			runes = runes[photoCaptionLineRunes:]
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	if len(lines) > photoCaptionMaxLines {
+		lines = lines[:photoCaptionMaxLines]
+		last := []rune(lines[photoCaptionMaxLines-1])
+		if len(last) >= photoCaptionLineRunes {
+			last = last[:photoCaptionLineRunes-1]
+		}
+		lines[photoCaptionMaxLines-1] = string(last) + "~"
+	}
+	return lines
+}
+
+// poseFrame returns an animation's first frame on its full canvas, nil for
+// an empty animation
+func poseFrame(anim *gif.GIF) image.Image {
+	if anim == nil || len(anim.Image) == 0 {
+		return nil
+	}
+	first := anim.Image[0]
+	bounds := image.Rect(0, 0, anim.Config.Width, anim.Config.Height)
+	if bounds.Empty() {
+		bounds = first.Bounds()
+	}
+	frame := image.NewRGBA(bounds)
+	draw.Draw(frame, first.Bounds(), first, first.Bounds().Min, draw.Over)
+	return frame
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// solidImage returns a w x h image filled with c
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestComposePhotoLayout(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	photo := composePhoto(solidImage(400, 300, blue), photoBackdrop{}, solidImage(10, 10, red), "")
+
+	if photo.Bounds().Dx() != 400 || photo.Bounds().Dy() != 300 {
+		t.Fatalf("Expected the photo to match the background, got %v", photo.Bounds())
+	}
+	// The pose stands centred on the bottom margin, 60% of the photo tall
+	margin := photoMargin * photoScale(image.Pt(400, 300))
+	if got := photo.RGBAAt(200, 300-margin-1); got != red {
+		t.Errorf("Expected the pose's feet just above the bottom margin, got %v", got)
+	}
+	if got := photo.RGBAAt(200, 300-margin-180-1); got != blue {
+		t.Errorf("Expected background above the pose, got %v", got)
+	}
+	if got := photo.RGBAAt(5, 5); got != blue {
+		t.Errorf("Expected background in the corner, got %v", got)
+	}
+}
+
+func TestComposePhotoBackdropAndCaption(t *testing.T) {
+	sky, _ := findPhotoBackdrop("Sky")
+	photo := composePhoto(nil, sky, nil, "Hello!")
+
+	if photo.Bounds().Dx() != photoDefaultSize || photo.Bounds().Dy() != photoDefaultSize {
+		t.Fatalf("Expected a %dpx square on a backdrop, got %v", photoDefaultSize, photo.Bounds())
+	}
+	if got := photo.RGBAAt(0, 0); got != sky.Top {
+		t.Errorf("Expected the gradient to start at the top color, got %v", got)
+	}
+	if got := photo.RGBAAt(0, photoDefaultSize-1); got != sky.Bottom {
+		t.Errorf("Expected the gradient to end at the bottom color, got %v", got)
+	}
+
+	// The sticker's border sits at the top margin, centred
+	margin := photoMargin * photoScale(photo.Bounds().Size())
+	if got := photo.RGBAAt(photoDefaultSize/2, margin); got != photoStickerBorder {
+		t.Errorf("Expected the caption sticker's border at the top, got %v", got)
+	}
+
+	transparent, _ := findPhotoBackdrop("Transparent")
+	if got := composePhoto(nil, transparent, nil, "").RGBAAt(10, 10); got.A != 0 {
+		t.Errorf("Expected a transparent photo, got %v", got)
+	}
+}
+
+func TestPhotoCanvasSize(t *testing.T) {
+	tests := []struct {
+		background image.Image
+		want       image.Point
+	}{
+		{nil, image.Pt(photoDefaultSize, photoDefaultSize)},
+		{image.NewRGBA(image.Rect(0, 0, 800, 600)), image.Pt(800, 600)},
+		{image.NewRGBA(image.Rect(0, 0, 4096, 1024)), image.Pt(photoMaxSize, 512)},
+		{image.NewRGBA(image.Rect(0, 0, 1000, 4000)), image.Pt(512, photoMaxSize)},
+	}
+	for _, tt := range tests {
+		if got := photoCanvasSize(tt.background); got != tt.want {
+			t.Errorf("photoCanvasSize = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestWrapCaption(t *testing.T) {
+	tests := []struct {
+		caption string
+		want    []string
+	}{
+		{"   ", nil},
+		{"Best friends", []string{"Best friends"}},
+		{"A lovely afternoon at the park with my favourite pet", []string{"A lovely afternoon at", "the park with my", "favourite pet"}},
+		{strings.Repeat("x", 30), []string{strings.Repeat("x", 24), strings.Repeat("x", 6)}},
+		{strings.Repeat("word ", 30), []string{"word word word word word", "word word word word word", "word word word word wor~"}},
+	}
+	for _, tt := range tests {
+		if got := wrapCaption(tt.caption); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrapCaption(%q) = %q, want %q", tt.caption, got, tt.want)
+		}
+	}
+}
+
+func TestPoseFrame(t *testing.T) {
+	if poseFrame(nil) != nil || poseFrame(&gif.GIF{}) != nil {
+		t.Error("Expected no pose for an empty animation")
+	}
+
+	// A first frame smaller than the canvas is placed at its offset
+	frame := image.NewPaletted(image.Rect(2, 2, 4, 4), palette.Plan9)
+	anim := &gif.GIF{Image: []*image.Paletted{frame}, Config: image.Config{Width: 8, Height: 6}}
+	pose := poseFrame(anim)
+	if pose.Bounds() != image.Rect(0, 0, 8, 6) {
+		t.Errorf("Expected the pose on the full canvas, got %v", pose.Bounds())
+	}
+}
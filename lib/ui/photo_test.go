@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// createTestCharacterForPhoto builds a character with a few animations to pose in
+func createTestCharacterForPhoto(t *testing.T, tmpDir string) *character.Character {
+	createTestAnimationFiles(t, tmpDir)
+
+	cardContent := `{
+		"name": "Poser",
+		"description": "A test character that likes the camera",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "happy": "happy.gif"},
+		"dialogs": [{"trigger": "click", "responses": ["Cheese!"], "animation": "happy", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 128}
+	}`
+
+	cardPath := filepath.Join(tmpDir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(cardContent), 0o644); err != nil {
+		t.Fatalf("Failed to write test character card: %v", err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Failed to load test character card: %v", err)
+	}
+	char, err := character.New(card, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	return char
+}
+
+func TestPhotoModeExport(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	char := createTestCharacterForPhoto(t, t.TempDir())
+	d := NewPhotoModeDialog(app, char)
+	if d.pose != "idle" || d.backdrop.Name != "Sky" {
+		t.Errorf("Expected the current animation on the sky backdrop, got %s on %s", d.pose, d.backdrop.Name)
+	}
+	if d.preview.Image == nil {
+		t.Error("Expected a preview as soon as the window opens")
+	}
+
+	d.selectBackground("Night")
+	d.pose = "happy"
+	d.caption = "Say cheese"
+	d.export()
+	if !strings.Contains(d.statusLabel.Text, "Saved") {
+		t.Fatalf("Expected the export to succeed, got %q", d.statusLabel.Text)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(home, "Pictures", "desktop-companion", "poser_photo_*.png"))
+	if len(matches) != 1 {
+		t.Fatalf("Expected one exported photo, got %v", matches)
+	}
+	f, err := os.Open(matches[0])
+	if err != nil {
+		t.Fatalf("Failed to open photo: %v", err)
+	}
+	defer f.Close()
+	photo, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Exported photo is not a PNG: %v", err)
+	}
+	if photo.Bounds().Dx() != photoDefaultSize {
+		t.Errorf("Expected a %dpx photo, got %v", photoDefaultSize, photo.Bounds())
+	}
+}
+
+func TestDecodePhotoBackground(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	img, err := decodePhotoBackground(bytes.NewReader(buf.Bytes()))
+	if err != nil || img.Bounds().Dx() != 40 {
+		t.Errorf("Expected the PNG to decode, got %v", err)
+	}
+
+	if _, err := decodePhotoBackground(strings.NewReader("not an image")); err == nil {
+		t.Error("Expected an error for a file that isn't an image")
+	}
+}
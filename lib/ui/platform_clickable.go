@@ -16,6 +16,7 @@ type PlatformAwareClickableWidget struct {
 	*ClickableWidget                            // Embedded for backward compatibility
 	touchWidget      *gestures.TouchAwareWidget // Touch-specific behavior
 	platform         *platform.PlatformInfo
+	onScroll         func(*fyne.ScrollEvent) // Mouse wheel handling on desktop platforms
 }
 
 // NewPlatformAwareClickableWidget creates a clickable widget that adapts to the platform.
@@ -80,15 +81,10 @@ func (w *PlatformAwareClickableWidget) Tapped(event *fyne.PointEvent) {
 	}
 }
 
-// TappedSecondary handles secondary tap events using platform-appropriate method
+// TappedSecondary handles secondary tap events. Fyne's mobile driver reports
+// a long press as a secondary tap, so touch platforms use the same path.
 func (w *PlatformAwareClickableWidget) TappedSecondary(event *fyne.PointEvent) {
-	if w.platform.IsMobile() || w.platform.HasTouch() {
-		// Use touch-aware handling on mobile platforms
-		w.touchWidget.TappedSecondary(event)
-	} else {
-		// Use traditional handling on desktop platforms
-		w.ClickableWidget.TappedSecondary(event)
-	}
+	w.ClickableWidget.TappedSecondary(event)
 }
 
 // DoubleTapped handles double tap events using platform-appropriate method
@@ -118,6 +114,28 @@ func (w *PlatformAwareClickableWidget) DragEnd() {
 	// Desktop drag end handling can be added here if needed
 }
 
+// Scrolled handles two-finger scrolling, used as pinch on touch platforms,
+// and the mouse wheel on desktop platforms
+func (w *PlatformAwareClickableWidget) Scrolled(event *fyne.ScrollEvent) {
+	if w.platform.IsMobile() || w.platform.HasTouch() {
+		w.touchWidget.Scrolled(event)
+		return
+	}
+	if w.onScroll != nil {
+		w.onScroll(event)
+	}
+}
+
+// SetScrollHandler configures mouse wheel behavior for desktop platforms
+func (w *PlatformAwareClickableWidget) SetScrollHandler(onScroll func(*fyne.ScrollEvent)) {
+	w.onScroll = onScroll
+}
+
+// SetPinchHandler configures pinch-to-resize behavior for touch platforms
+func (w *PlatformAwareClickableWidget) SetPinchHandler(onPinch func(scale float32)) {
+	w.touchWidget.SetPinchHandler(onPinch)
+}
+
 // SetDragHandlers configures drag behavior for touch platforms
 func (w *PlatformAwareClickableWidget) SetDragHandlers(onStart func(), onDrag func(*fyne.DragEvent), onEnd func()) {
 	w.touchWidget.SetDragHandlers(onStart, onDrag, onEnd)
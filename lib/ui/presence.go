@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform"
+)
+
+// presencePollInterval is how often the OS is asked for the user's idle time
+const presencePollInterval = 15 * time.Second
+
+// StartAwayDetection polls detector and tells the character when the user
+// steps away and comes back. Stops on its own when the OS can't report
+// idle time.
+func (dw *DesktopWindow) StartAwayDetection(detector platform.IdleDetector) {
+	dw.presenceMu.Lock()
+	defer dw.presenceMu.Unlock()
+
+	if dw.presenceStop != nil || detector == nil {
+		return
+	}
+	dw.presenceStop = make(chan struct{})
+	go dw.presenceLoop(detector, dw.presenceStop)
+}
+
+// StopAwayDetection stops polling for the user's presence
+func (dw *DesktopWindow) StopAwayDetection() {
+	dw.presenceMu.Lock()
+	defer dw.presenceMu.Unlock()
+
+	if dw.presenceStop != nil {
+		close(dw.presenceStop)
+		dw.presenceStop = nil
+	}
+}
+
+// presenceLoop polls the detector until stop closes
+func (dw *DesktopWindow) presenceLoop(detector platform.IdleDetector, stop chan struct{}) {
+	ticker := time.NewTicker(presencePollInterval)
+	defer ticker.Stop()
+	for {
+		if !dw.pollPresence(detector) {
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollPresence reads the idle state once and hands it to the character.
+// Returns false when the OS has no idle detection, so polling can stop.
+func (dw *DesktopWindow) pollPresence(detector platform.IdleDetector) bool {
+	state, err := detector.IdleState()
+	if errors.Is(err, platform.ErrNoIdleDetection) {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+		}).Info("Idle detection unavailable, away detection disabled")
+		return false
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Debug("Could not read idle state")
+		return true
+	}
+	dw.character.UpdatePresence(state.Idle, state.Locked)
+	dw.checkScreensaver(detector, state)
+	return true
+}
+
+// checkForWelcomeBacks greets the user when they return after a long absence
+func (dw *DesktopWindow) checkForWelcomeBacks() {
+	if dw.character == nil {
+		return
+	}
+
+	for _, welcome := range dw.character.GetWelcomeBacks() {
+		logrus.WithFields(logrus.Fields{
+			"caller":  getCaller(),
+			"absence": welcome.Absence.Round(time.Minute).String(),
+			"effects": welcome.Effects,
+		}).Info("User returned")
+		dw.showDialog(welcome.Response)
+	}
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/platform"
+)
+
+// fakeIdleDetector reports a fixed idle state
+type fakeIdleDetector struct {
+	state platform.IdleState
+	err   error
+}
+
+func (f *fakeIdleDetector) IdleState() (platform.IdleState, error) {
+	return f.state, f.err
+}
+
+// createTestCharacterWithAway builds a character that naps while the user is away
+func createTestCharacterWithAway(t *testing.T, tmpDir string) *character.Character {
+	createTestAnimationFiles(t, tmpDir)
+
+	cardContent := `{
+		"name": "Napper",
+		"description": "A test character that notices when you leave",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "happy": "happy.gif"},
+		"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 128},
+		"away": {"enabled": true, "welcomeBackAfter": 1, "welcomeBackResponses": ["There you are!"]}
+	}`
+
+	cardPath := filepath.Join(tmpDir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(cardContent), 0o644); err != nil {
+		t.Fatalf("Failed to write test character card: %v", err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Failed to load test character card: %v", err)
+	}
+	char, err := character.New(card, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	return char
+}
+
+func TestPollPresence(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithAway(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	detector := &fakeIdleDetector{state: platform.IdleState{Idle: time.Hour}}
+	if !dw.pollPresence(detector) {
+		t.Fatal("Polling should continue while idle time is available")
+	}
+	if !char.IsUserAway() {
+		t.Error("An hour without input should count as away")
+	}
+
+	detector.state = platform.IdleState{}
+	dw.pollPresence(detector)
+	welcomes := char.GetWelcomeBacks()
+	if len(welcomes) != 1 || welcomes[0].Response != "There you are!" {
+		t.Errorf("Expected one welcome back from the card, got %+v", welcomes)
+	}
+
+	detector.err = errors.New("temporary failure")
+	if !dw.pollPresence(detector) {
+		t.Error("A failed read should not stop polling")
+	}
+	detector.err = platform.ErrNoIdleDetection
+	if dw.pollPresence(detector) {
+		t.Error("Polling should stop when the OS has no idle detection")
+	}
+}
+
+func TestStartStopAwayDetection(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithAway(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dw.StartAwayDetection(&fakeIdleDetector{})
+	dw.StartAwayDetection(&fakeIdleDetector{}) // Second start is ignored
+	dw.StopAwayDetection()
+	dw.StopAwayDetection() // Stopping twice is safe
+	if dw.presenceStop != nil {
+		t.Error("Stop should clear the loop")
+	}
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// RelationshipTuningDialog lets power users tune how easily the character
+// gets jealous and falls into crisis. Changes apply right away and are saved
+// for this character; Reset goes back to the card's personality.
+type RelationshipTuningDialog struct {
+	window    fyne.Window
+	character *character.Character
+	loading   bool // Set while sliders are filled in, so nothing is saved
+
+	sensitivitySlider   *widget.Slider
+	thresholdSlider     *widget.Slider
+	jealousyDecaySlider *widget.Slider // Nil when the card has no jealousy stat
+	trustDecaySlider    *widget.Slider // Nil when the card has no trust stat
+	crisisSliders       map[string]*widget.Slider
+	labels              map[*widget.Slider]*widget.Label
+	resetButton         *widget.Button
+	statusLabel         *widget.Label
+}
+
+// NewRelationshipTuningDialog creates the tuning window for char
+func NewRelationshipTuningDialog(app fyne.App, char *character.Character) *RelationshipTuningDialog {
+	d := &RelationshipTuningDialog{
+		window:        app.NewWindow("Relationship Tuning"),
+		character:     char,
+		crisisSliders: make(map[string]*widget.Slider),
+		labels:        make(map[*widget.Slider]*widget.Label),
+	}
+
+	tuning := char.GetRelationshipTuning()
+	d.sensitivitySlider = d.newSlider(0.1, 3, 0.1)
+	d.thresholdSlider = d.newSlider(10, 99, 1)
+	if tuning.JealousyDecayRate != nil {
+		d.jealousyDecaySlider = d.newSlider(0, 5, 0.05)
+	}
+	if tuning.TrustDecayRate != nil {
+		d.trustDecaySlider = d.newSlider(0, 5, 0.05)
+	}
+	for _, stat := range []string{"jealousy", "trust", "affection"} {
+		d.crisisSliders[stat] = d.newSlider(0, 100, 1)
+	}
+	d.resetButton = widget.NewButton("Reset to Personality", d.reset)
+	d.statusLabel = widget.NewLabel("Changes are saved automatically.")
+	d.statusLabel.Wrapping = fyne.TextWrapWord
+	d.load(tuning)
+
+	jealousy := container.NewVBox(
+		d.row("Sensitivity:", d.sensitivitySlider),
+		d.row("Upset at:", d.thresholdSlider),
+	)
+	decay := container.NewVBox()
+	if d.jealousyDecaySlider != nil {
+		decay.Add(d.row("Jealousy:", d.jealousyDecaySlider))
+	}
+	if d.trustDecaySlider != nil {
+		decay.Add(d.row("Trust:", d.trustDecaySlider))
+	}
+
+	content := container.NewVBox(
+		widget.NewCard("Jealousy", "How quickly jealousy builds and when it starts to hurt", jealousy),
+		widget.NewCard("Crisis Thresholds", "Jealousy above, or trust and affection below, these levels start a crisis", container.NewVBox(
+			d.row("Jealousy:", d.crisisSliders["jealousy"]),
+			d.row("Trust:", d.crisisSliders["trust"]),
+			d.row("Affection:", d.crisisSliders["affection"]),
+		)),
+	)
+	if len(decay.Objects) > 0 {
+		content.Add(widget.NewCard("Decay", "Points lost per minute", decay))
+	}
+	content.Add(d.resetButton)
+	content.Add(d.statusLabel)
+
+	d.window.SetContent(content)
+	d.window.Resize(fyne.NewSize(380, 0))
+	return d
+}
+
+// Show displays the tuning window
+func (d *RelationshipTuningDialog) Show() {
+	d.window.Show()
+}
+
+// SetOnClosed sets a callback for when the window closes
+func (d *RelationshipTuningDialog) SetOnClosed(onClosed func()) {
+	d.window.SetOnClosed(onClosed)
+}
+
+// RequestFocus brings the tuning window to the front
+func (d *RelationshipTuningDialog) RequestFocus() {
+	d.window.RequestFocus()
+}
+
+// newSlider creates a slider that saves once a drag ends
+func (d *RelationshipTuningDialog) newSlider(min, max, step float64) *widget.Slider {
+	slider := widget.NewSlider(min, max)
+	slider.Step = step
+	label := widget.NewLabel("")
+	d.labels[slider] = label
+
+	slider.OnChanged = func(value float64) { label.SetText(formatTuningValue(value, step)) }
+	slider.OnChangeEnded = func(float64) { d.save() }
+	return slider
+}
+
+// row lays out a slider between its name and current value
+func (d *RelationshipTuningDialog) row(name string, slider *widget.Slider) fyne.CanvasObject {
+	return container.NewBorder(nil, nil, widget.NewLabel(name), d.labels[slider], slider)
+}
+
+// load shows tuning on the sliders without saving it
+func (d *RelationshipTuningDialog) load(tuning character.RelationshipTuning) {
+	d.loading = true
+	defer func() { d.loading = false }()
+
+	set := func(slider *widget.Slider, value float64) {
+		slider.SetValue(value)
+		d.labels[slider].SetText(formatTuningValue(slider.Value, slider.Step))
+	}
+
+	set(d.sensitivitySlider, tuning.JealousySensitivity)
+	set(d.thresholdSlider, tuning.JealousyThreshold)
+	if d.jealousyDecaySlider != nil && tuning.JealousyDecayRate != nil {
+		set(d.jealousyDecaySlider, *tuning.JealousyDecayRate)
+	}
+	if d.trustDecaySlider != nil && tuning.TrustDecayRate != nil {
+		set(d.trustDecaySlider, *tuning.TrustDecayRate)
+	}
+	for stat, slider := range d.crisisSliders {
+		set(slider, tuning.CrisisThresholds[stat])
+	}
+}
+
+// save applies and stores the values on the sliders
+func (d *RelationshipTuningDialog) save() {
+	if d.loading {
+		return
+	}
+
+	tuning := character.RelationshipTuning{
+		JealousySensitivity: d.sensitivitySlider.Value,
+		JealousyThreshold:   d.thresholdSlider.Value,
+		CrisisThresholds:    make(map[string]float64, len(d.crisisSliders)),
+	}
+	if d.jealousyDecaySlider != nil {
+		rate := d.jealousyDecaySlider.Value
+		tuning.JealousyDecayRate = &rate
+	}
+	if d.trustDecaySlider != nil {
+		rate := d.trustDecaySlider.Value
+		tuning.TrustDecayRate = &rate
+	}
+	for stat, slider := range d.crisisSliders {
+		tuning.CrisisThresholds[stat] = slider.Value
+	}
+
+	if err := d.character.SetRelationshipTuning(tuning); err != nil {
+		d.statusLabel.SetText(fmt.Sprintf("Could not save tuning: %v", err))
+		return
+	}
+	d.statusLabel.SetText("Saved.")
+}
+
+// reset forgets the user's tuning and shows the personality values again
+func (d *RelationshipTuningDialog) reset() {
+	if err := d.character.ResetRelationshipTuning(); err != nil {
+		d.statusLabel.SetText(fmt.Sprintf("Could not reset tuning: %v", err))
+		return
+	}
+	d.load(d.character.GetRelationshipTuning())
+	d.statusLabel.SetText("Back to the character's personality.")
+}
+
+// formatTuningValue shows whole numbers for whole-number sliders
+func formatTuningValue(value, step float64) string {
+	switch {
+	case step >= 1:
+		return fmt.Sprintf("%.0f", value)
+	case step >= 0.1:
+		return fmt.Sprintf("%.1f", value)
+	default:
+		return fmt.Sprintf("%.2f", value)
+	}
+}
+
+// buildRelationshipTuningMenuItem creates the tuning entry, shown next to
+// relationship status for characters that can have relationship crises
+func (dw *DesktopWindow) buildRelationshipTuningMenuItem() (ContextMenuItem, bool) {
+	if !dw.character.CrisisTrackingEnabled() {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{Text: "🎚️ Relationship Tuning", Callback: dw.showRelationshipTuning}, true
+}
+
+// showRelationshipTuning opens the tuning window, or focuses it if it's open
+func (dw *DesktopWindow) showRelationshipTuning() {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+
+	if dw.tuningDialog != nil {
+		dw.tuningDialog.RequestFocus()
+		return
+	}
+
+	dw.tuningDialog = NewRelationshipTuningDialog(fyne.CurrentApp(), dw.character)
+	dw.tuningDialog.SetOnClosed(func() {
+		dw.settingsMu.Lock()
+		dw.tuningDialog = nil
+		dw.settingsMu.Unlock()
+	})
+	dw.tuningDialog.Show()
+}
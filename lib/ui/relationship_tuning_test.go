@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestRelationshipTuningDialogAppliesAndResets(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createRomanceCharacterWithStats(t)
+	defaults := char.GetRelationshipTuning()
+	dialog := NewRelationshipTuningDialog(app, char)
+
+	if dialog.jealousyDecaySlider != nil || dialog.trustDecaySlider == nil {
+		t.Error("Expected decay sliders only for the stats the card has")
+	}
+	if dialog.thresholdSlider.Value != defaults.JealousyThreshold {
+		t.Errorf("Expected the slider to start at %v, got %v", defaults.JealousyThreshold, dialog.thresholdSlider.Value)
+	}
+
+	// Ending a drag applies the change right away
+	dialog.crisisSliders["trust"].SetValue(40)
+	dialog.crisisSliders["trust"].OnChangeEnded(40)
+	if got := char.GetRelationshipTuning().CrisisThresholds["trust"]; got != 40 {
+		t.Errorf("Expected trust crisis threshold 40, got %v", got)
+	}
+
+	test.Tap(dialog.resetButton)
+	if got := char.GetRelationshipTuning().CrisisThresholds["trust"]; got != defaults.CrisisThresholds["trust"] {
+		t.Errorf("Expected reset to restore %v, got %v", defaults.CrisisThresholds["trust"], got)
+	}
+	if dialog.crisisSliders["trust"].Value != defaults.CrisisThresholds["trust"] {
+		t.Error("Expected reset to move the slider back")
+	}
+}
+
+func TestRelationshipTuningMenuItemHiddenWithoutCrises(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if _, ok := dw.buildRelationshipTuningMenuItem(); ok {
+		t.Error("Relationship tuning should only show for characters with crisis tracking")
+	}
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/api"
+)
+
+// remoteRetryDelay is how long a thin client waits before reconnecting to
+// its daemon after the event stream drops
+const remoteRetryDelay = 2 * time.Second
+
+// RemoteCompanion is a companion living in another process, usually a
+// daemon reached through api.Client. The window renders it and forwards
+// interactions instead of running the character itself.
+type RemoteCompanion interface {
+	Click(ctx context.Context) (api.ActionResponse, error)
+	Interact(ctx context.Context, interaction string) (api.ActionResponse, error)
+	Subscribe(ctx context.Context, handler func(api.Event)) error
+}
+
+// AttachRemote turns the window into a thin client of remote: clicks and
+// menu interactions are forwarded, and the daemon's state changes and
+// responses are mirrored. The returned func detaches.
+func (dw *DesktopWindow) AttachRemote(remote RemoteCompanion) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	dw.settingsMu.Lock()
+	dw.remote = remote
+	dw.settingsMu.Unlock()
+
+	go dw.followRemote(ctx, remote)
+
+	return func() {
+		cancel()
+		dw.settingsMu.Lock()
+		dw.remote = nil
+		dw.settingsMu.Unlock()
+	}
+}
+
+// attachedRemote returns the remote companion, or nil when running locally
+func (dw *DesktopWindow) attachedRemote() RemoteCompanion {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+	return dw.remote
+}
+
+// followRemote mirrors the daemon's events, reconnecting until ctx is done
+// so a restarted daemon is picked up again
+func (dw *DesktopWindow) followRemote(ctx context.Context, remote RemoteCompanion) {
+	for {
+		err := remote.Subscribe(ctx, dw.handleRemoteEvent)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err,
+		}).Warn("Lost connection to companion daemon, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(remoteRetryDelay):
+		}
+	}
+}
+
+// handleRemoteEvent shows what happened to the daemon's character. Action
+// responses arrive here for every attached frontend, including the one that
+// sent the action, so they are only shown from the event stream.
+func (dw *DesktopWindow) handleRemoteEvent(event api.Event) {
+	if event.State != "" && event.State != dw.character.GetCurrentState() {
+		if err := dw.character.ForceState(event.State); err != nil && dw.debug {
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+				"state":  event.State,
+				"error":  err,
+			}).Debug("Remote state has no local animation")
+		}
+	}
+	if event.Response != "" {
+		dw.showDialog(event.Response)
+	}
+}
+
+// forwardRemote runs a remote action in the background, surfacing failures
+// in the dialog bubble since there is no local character to fall back on
+func (dw *DesktopWindow) forwardRemote(action func(ctx context.Context) (api.ActionResponse, error)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if _, err := action(ctx); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller": getCaller(),
+				"error":  err,
+			}).Warn("Remote action failed")
+			dw.showDialog("I can't reach my daemon right now.")
+		}
+	}()
+}
+
+// buildRemoteMenuItems lists the actions a thin client forwards: talking and
+// each interaction the card defines
+func (dw *DesktopWindow) buildRemoteMenuItems(remote RemoteCompanion) []ContextMenuItem {
+	menuItems := []ContextMenuItem{{
+		Text:     "Talk",
+		Callback: func() { dw.forwardRemote(remote.Click) },
+	}}
+
+	var names []string
+	for name := range dw.character.GetCard().Interactions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		menuItems = append(menuItems, ContextMenuItem{
+			Text: strings.Title(strings.ReplaceAll(name, "_", " ")),
+			Callback: func() {
+				dw.forwardRemote(func(ctx context.Context) (api.ActionResponse, error) {
+					return remote.Interact(ctx, name)
+				})
+			},
+		})
+	}
+	return menuItems
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/api"
+)
+
+// fakeRemote records forwarded actions and streams scripted events
+type fakeRemote struct {
+	mu           sync.Mutex
+	clicks       int
+	interactions []string
+	events       []api.Event
+}
+
+func (f *fakeRemote) Click(context.Context) (api.ActionResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clicks++
+	return api.ActionResponse{Response: "Hi!"}, nil
+}
+
+func (f *fakeRemote) Interact(_ context.Context, interaction string) (api.ActionResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interactions = append(f.interactions, interaction)
+	return api.ActionResponse{}, nil
+}
+
+func (f *fakeRemote) Subscribe(ctx context.Context, handler func(api.Event)) error {
+	for _, event := range f.events {
+		handler(event)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (f *fakeRemote) clickCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.clicks
+}
+
+func TestAttachRemoteForwardsAndMirrors(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	char := createRomanceCharacterWithStats(t)
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	remote := &fakeRemote{events: []api.Event{{Type: "click", Response: "Hello from the daemon", State: "idle"}}}
+	stop := dw.AttachRemote(remote)
+	defer stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for dw.dialog.currentText != "Hello from the daemon" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if dw.dialog.currentText != "Hello from the daemon" {
+		t.Errorf("Expected the daemon's response in the bubble, got %q", dw.dialog.currentText)
+	}
+
+	dw.handleClick()
+	for remote.clickCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if remote.clickCount() != 1 {
+		t.Errorf("Expected the click to be forwarded, got %d", remote.clickCount())
+	}
+
+	items := dw.buildRemoteMenuItems(remote)
+	if len(items) == 0 || items[0].Text != "Talk" {
+		t.Errorf("Expected a Talk item first, got %+v", items)
+	}
+
+	stop()
+	if dw.attachedRemote() != nil {
+		t.Error("Expected the window to run locally after detaching")
+	}
+}
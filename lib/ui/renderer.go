@@ -1,7 +1,11 @@
 package ui
 
 import (
+	"image"
+	"image/color"
 	"log"
+	"math"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -18,6 +22,12 @@ type CharacterRenderer struct {
 	image     *canvas.Image
 	debug     bool
 	size      int
+
+	peekMu       sync.Mutex
+	peekEdge     ScreenEdge  // Edge the character hides behind, EdgeNone shows all of it
+	peekFraction float64     // Part of the sprite that peeks out
+	peekSource   image.Image // Frame the cached peekFrame was made from
+	peekFrame    image.Image
 }
 
 // NewCharacterRenderer creates a new character renderer widget
@@ -59,7 +69,9 @@ func (r *CharacterRenderer) CreateRenderer() fyne.WidgetRenderer {
 func (r *CharacterRenderer) updateFrame() {
 	frame := r.character.GetCurrentFrame()
 	if frame != nil {
-		r.image.Image = frame
+		r.image.Image = r.visiblePart(frame)
+		// Dim the sprite while the character is asleep
+		r.image.Translucency = 1 - r.character.GetSleepOpacity()
 		r.image.Refresh()
 
 		if r.debug {
@@ -82,6 +94,68 @@ func (r *CharacterRenderer) SetSize(size int) {
 	r.Refresh()
 }
 
+// SetPeek shows only the part of the sprite that peeks out from behind a
+// screen edge: its top for the bottom edge, or the side facing the screen.
+// EdgeNone shows the whole sprite again.
+func (r *CharacterRenderer) SetPeek(edge ScreenEdge, fraction float64) {
+	r.peekMu.Lock()
+	r.peekEdge = edge
+	r.peekFraction = fraction
+	r.peekSource, r.peekFrame = nil, nil
+	r.peekMu.Unlock()
+	r.Refresh()
+}
+
+// VisibleFrame returns the frame as drawn, with any hidden part cleared
+func (r *CharacterRenderer) VisibleFrame() image.Image {
+	return r.image.Image
+}
+
+// visiblePart masks frame for peeking, reusing the mask while the frame
+// doesn't change so window shapes aren't recomputed every tick
+func (r *CharacterRenderer) visiblePart(frame image.Image) image.Image {
+	r.peekMu.Lock()
+	defer r.peekMu.Unlock()
+
+	if r.peekEdge == EdgeNone {
+		return frame
+	}
+	if frame != r.peekSource {
+		r.peekSource = frame
+		r.peekFrame = &peekImage{Image: frame, visible: peekRect(frame.Bounds(), r.peekEdge, r.peekFraction)}
+	}
+	return r.peekFrame
+}
+
+// peekRect returns the part of bounds left showing past edge
+func peekRect(bounds image.Rectangle, edge ScreenEdge, fraction float64) image.Rectangle {
+	switch edge {
+	case EdgeLeft:
+		width := int(math.Ceil(float64(bounds.Dx()) * fraction))
+		return image.Rect(bounds.Max.X-width, bounds.Min.Y, bounds.Max.X, bounds.Max.Y)
+	case EdgeRight:
+		width := int(math.Ceil(float64(bounds.Dx()) * fraction))
+		return image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Min.X+width, bounds.Max.Y)
+	default:
+		height := int(math.Ceil(float64(bounds.Dy()) * fraction))
+		return image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y+height)
+	}
+}
+
+// peekImage is a frame with everything outside visible made transparent
+type peekImage struct {
+	image.Image
+	visible image.Rectangle
+}
+
+// At returns transparent outside the visible part
+func (p *peekImage) At(x, y int) color.Color {
+	if !image.Pt(x, y).In(p.visible) {
+		return color.Transparent
+	}
+	return p.Image.At(x, y)
+}
+
 // GetSize returns the current character display size
 func (r *CharacterRenderer) GetSize() int {
 	return r.size
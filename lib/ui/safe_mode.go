@@ -0,0 +1,42 @@
+package ui
+
+// safe_mode.go offers to roll back to the last good save when the companion
+// is started with -safe-mode after a crash or a save that no longer loads.
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// OfferRestore asks whether to replace the current game with the save kept
+// at savedAt, the last clean exit. restore is called if the user agrees.
+func (dw *DesktopWindow) OfferRestore(savedAt time.Time, restore func() error) {
+	window := fyne.CurrentApp().NewWindow("Safe Mode")
+
+	message := widget.NewLabel(fmt.Sprintf(
+		"%s started in safe mode: networking, dialog backends and events are off.\n\n"+
+			"Restore the game saved at your last clean exit (%s)? Progress since then will be lost.",
+		dw.character.GetName(), savedAt.Format("Jan 2 15:04")))
+	message.Wrapping = fyne.TextWrapWord
+
+	restoreButton := widget.NewButton("Restore", func() {
+		window.Close()
+		if err := restore(); err != nil {
+			dw.showDialog(fmt.Sprintf("Could not restore the save: %v", err))
+			return
+		}
+		dw.showDialog("Restored the last good save")
+	})
+	restoreButton.Importance = widget.HighImportance
+
+	window.SetContent(container.NewVBox(
+		message,
+		container.NewHBox(restoreButton, widget.NewButton("Keep Current", window.Close)),
+	))
+	window.Resize(fyne.NewSize(380, 0))
+	window.Show()
+}
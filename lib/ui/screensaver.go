@@ -0,0 +1,161 @@
+package ui
+
+// screensaver.go implements the screensaver takeover: after a long time
+// without input the window goes fullscreen and the character, scaled up to
+// fill the screen, plays its showcase animations. Any input ends it at once.
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform"
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+const (
+	screensaverInputPoll = 250 * time.Millisecond // How often input is checked while the screensaver runs
+	screensaverFill      = 0.8                    // Part of the screen's shorter side the character fills
+)
+
+// screensaverState tracks the screensaver takeover for the window
+type screensaverState struct {
+	mu      sync.Mutex
+	active  bool
+	wasShy  bool          // Shy mode was on and comes back afterwards
+	stop    chan struct{} // Stops the input watch
+	watched time.Duration // Idle time at the last input check
+}
+
+// checkScreensaver starts the screensaver once the user has been idle for
+// the card's threshold. A locked screen hides it anyway, so it waits.
+func (dw *DesktopWindow) checkScreensaver(detector platform.IdleDetector, state platform.IdleState) {
+	card := dw.character.GetCard()
+	if !card.HasScreensaver() || state.Locked || state.Idle < card.Screensaver.GetIdleThreshold() {
+		return
+	}
+	dw.startScreensaver(detector, state.Idle)
+}
+
+// startScreensaver makes the window fullscreen, scales the character up and
+// starts the showcase, then watches for input
+func (dw *DesktopWindow) startScreensaver(detector platform.IdleDetector, idle time.Duration) {
+	dw.screensaver.mu.Lock()
+	defer dw.screensaver.mu.Unlock()
+
+	if dw.screensaver.active {
+		return
+	}
+	dw.screensaver.active = true
+	dw.screensaver.watched = idle
+	dw.screensaver.wasShy = dw.IsShyMode()
+	if dw.screensaver.wasShy {
+		dw.SetShyMode(false)
+	}
+
+	dw.window.SetFullScreen(true)
+	screen := dw.screenSize()
+	side := fyne.Min(screen.Width, screen.Height) * screensaverFill
+	dw.renderer.Resize(fyne.NewSize(side, side))
+	dw.renderer.Move(fyne.NewPos((screen.Width-side)/2, (screen.Height-side)/2))
+	dw.renderer.SetSize(int(side))
+	dw.character.StartShowcase()
+
+	dw.screensaver.stop = make(chan struct{})
+	go dw.watchScreensaverInput(detector, dw.screensaver.stop)
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"idle":   idle.Round(time.Minute).String(),
+	}).Info("Screensaver started")
+}
+
+// stopScreensaver puts the window and character back as they were. Returns
+// true if the screensaver was running.
+func (dw *DesktopWindow) stopScreensaver() bool {
+	dw.screensaver.mu.Lock()
+	defer dw.screensaver.mu.Unlock()
+
+	if !dw.screensaver.active {
+		return false
+	}
+	dw.screensaver.active = false
+	close(dw.screensaver.stop)
+	dw.screensaver.stop = nil
+
+	dw.character.StopShowcase()
+	size := dw.character.GetSize()
+	dw.renderer.Move(fyne.NewPos(0, 0))
+	dw.renderer.Resize(fyne.NewSize(float32(size), float32(size)))
+	dw.renderer.SetSize(size)
+	dw.window.SetFullScreen(false)
+	dw.window.Resize(fyne.NewSize(float32(size), float32(size)))
+	if dw.screensaver.wasShy {
+		dw.SetShyMode(true)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+	}).Info("Screensaver stopped")
+	return true
+}
+
+// IsScreensaverActive returns whether the screensaver has taken over the screen
+func (dw *DesktopWindow) IsScreensaverActive() bool {
+	dw.screensaver.mu.Lock()
+	defer dw.screensaver.mu.Unlock()
+	return dw.screensaver.active
+}
+
+// watchScreensaverInput polls the idle time quickly and ends the screensaver
+// as soon as it drops, which means the user touched the keyboard or mouse
+func (dw *DesktopWindow) watchScreensaverInput(detector platform.IdleDetector, stop chan struct{}) {
+	ticker := time.NewTicker(screensaverInputPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		state, err := detector.IdleState()
+		if err != nil {
+			continue
+		}
+		if dw.sawInput(state) {
+			dw.stopScreensaver()
+			return
+		}
+	}
+}
+
+// sawInput reports whether the idle time went back since the last check
+// or the screen was locked, and remembers it for the next one
+func (dw *DesktopWindow) sawInput(state platform.IdleState) bool {
+	dw.screensaver.mu.Lock()
+	defer dw.screensaver.mu.Unlock()
+
+	input := state.Locked || state.Idle < dw.screensaver.watched
+	dw.screensaver.watched = state.Idle
+	return input
+}
+
+// screenSize returns the screen size in Fyne units, from the window system
+// where it can tell, otherwise from the fullscreen canvas
+func (dw *DesktopWindow) screenSize() fyne.Size {
+	var screen image.Rectangle
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		_, screen, _ = native.WindowGeometry(win)
+	})
+	if !screen.Empty() {
+		scale := dw.window.Canvas().Scale()
+		if scale <= 0 {
+			scale = 1
+		}
+		return fyne.NewSize(float32(screen.Dx())/scale, float32(screen.Dy())/scale)
+	}
+	return dw.window.Canvas().Size()
+}
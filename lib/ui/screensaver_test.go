@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/platform"
+)
+
+// createTestCharacterWithScreensaver builds a character that takes over the
+// screen after a minute without input
+func createTestCharacterWithScreensaver(t *testing.T, tmpDir string) *character.Character {
+	createTestAnimationFiles(t, tmpDir)
+
+	cardContent := `{
+		"name": "Showoff",
+		"description": "A test character that fills the screen while you are idle",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "happy": "happy.gif"},
+		"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 128},
+		"screensaver": {"enabled": true, "idleMinutes": 1, "animations": ["happy", "talking"]}
+	}`
+
+	cardPath := filepath.Join(tmpDir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(cardContent), 0o644); err != nil {
+		t.Fatalf("Failed to write test character card: %v", err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Failed to load test character card: %v", err)
+	}
+	char, err := character.New(card, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	return char
+}
+
+func TestScreensaverTakeover(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithScreensaver(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+	defer dw.stopScreensaver()
+
+	detector := &fakeIdleDetector{state: platform.IdleState{Idle: 30 * time.Second}}
+	dw.pollPresence(detector)
+	if dw.IsScreensaverActive() {
+		t.Fatal("Half a minute of idle time should not start the screensaver")
+	}
+
+	detector.state = platform.IdleState{Idle: 2 * time.Minute}
+	dw.pollPresence(detector)
+	if !dw.IsScreensaverActive() || !char.IsShowcasing() {
+		t.Fatal("Two minutes of idle time should start the screensaver")
+	}
+	if state := char.GetCurrentState(); state != "happy" {
+		t.Errorf("Expected the showcase to open with happy, got %q", state)
+	}
+
+	if dw.sawInput(platform.IdleState{Idle: 2*time.Minute + time.Second}) {
+		t.Error("Growing idle time is not input")
+	}
+	if !dw.sawInput(platform.IdleState{Idle: time.Second}) {
+		t.Error("Idle time dropping back means the user is here")
+	}
+
+	if !dw.stopScreensaver() {
+		t.Fatal("Stopping a running screensaver should report it")
+	}
+	if dw.IsScreensaverActive() || char.IsShowcasing() {
+		t.Error("Screensaver and showcase should have stopped")
+	}
+	if dw.renderer.GetSize() != char.GetSize() {
+		t.Errorf("Expected the character back at %d, got %d", char.GetSize(), dw.renderer.GetSize())
+	}
+	if dw.stopScreensaver() {
+		t.Error("Stopping twice should be a no-op")
+	}
+}
+
+func TestScreensaverWaitsWhileLocked(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithScreensaver(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dw.pollPresence(&fakeIdleDetector{state: platform.IdleState{Idle: time.Hour, Locked: true}})
+	if dw.IsScreensaverActive() {
+		dw.stopScreensaver()
+		t.Error("The screensaver should not start behind a locked screen")
+	}
+}
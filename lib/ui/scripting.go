@@ -0,0 +1,12 @@
+package ui
+
+// checkForScriptDialogs shows what the card's behavior script said from
+// its hooks, held back during do-not-disturb like other announcements
+func (dw *DesktopWindow) checkForScriptDialogs() {
+	if dw.character == nil {
+		return
+	}
+	for _, text := range dw.character.GetScriptDialogs() {
+		dw.showNotice(text)
+	}
+}
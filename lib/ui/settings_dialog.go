@@ -0,0 +1,585 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/monitoring"
+	"github.com/opd-ai/desktop-companion/lib/platform"
+)
+
+// eventFrequencyOptions are the random event multipliers offered to users
+var eventFrequencyOptions = []struct {
+	label      string
+	multiplier float64
+}{
+	{"Very Rare (0.5x)", 0.5},
+	{"Normal (1.0x)", 1.0},
+	{"Frequent (1.5x)", 1.5},
+	{"Very Frequent (2.0x)", 2.0},
+	{"Maximum (3.0x)", 3.0},
+}
+
+// SettingsDialog edits the settings file. Every change is saved right away
+// and passed to onApply so it can take effect without a restart.
+type SettingsDialog struct {
+	window   fyne.Window
+	settings config.Settings
+	path     string
+	onApply  func(config.Settings)
+
+	debugCheck       *widget.Check
+	gameCheck        *widget.Check
+	statsCheck       *widget.Check
+	networkCheck     *widget.Check
+	networkUICheck   *widget.Check
+	frequencySelect  *widget.Select
+	themeSelect      *widget.Select
+	clickSelect      *widget.Select
+	fullscreenSelect *widget.Select
+	battleSelect     *widget.Select
+	scaleSlider      *widget.Slider
+	scaleLabel       *widget.Label
+	ttsCheck         *widget.Check
+	metricsCheck     *widget.Check
+	clipboardCheck   *widget.Check
+	clipboardKinds   *widget.CheckGroup
+	notifyGroup      *widget.CheckGroup
+	autostartCheck   *widget.Check
+	statusLabel      *widget.Label
+
+	autostart platform.Autostart
+}
+
+// NewSettingsDialog creates the settings window for the settings file at path
+func NewSettingsDialog(app fyne.App, settings config.Settings, path string, onApply func(config.Settings)) *SettingsDialog {
+	d := &SettingsDialog{
+		window:   app.NewWindow("Settings"),
+		settings: settings,
+		path:     path,
+		onApply:  onApply,
+	}
+
+	d.createWidgets()
+	d.window.SetContent(container.NewVBox(
+		widget.NewCard("Companion", "", container.NewVBox(
+			container.NewBorder(nil, nil, widget.NewLabel("Random events:"), nil, d.frequencySelect),
+			container.NewBorder(nil, nil, widget.NewLabel("Size:"), d.scaleLabel, d.scaleSlider),
+			container.NewBorder(nil, nil, widget.NewLabel("Fullscreen apps:"), nil, d.fullscreenSelect),
+			container.NewBorder(nil, nil, widget.NewLabel("Battle ping limit:"), nil, d.battleSelect),
+			d.ttsCheck,
+			d.autostartCheck,
+		)),
+		widget.NewCard("Appearance", "Custom colors are read from themeColors in settings.json", container.NewVBox(
+			container.NewBorder(nil, nil, widget.NewLabel("Theme:"), nil, d.themeSelect),
+			container.NewBorder(nil, nil, widget.NewLabel("Takes clicks:"), nil, d.clickSelect),
+		)),
+		widget.NewCard("Features", "Applied on next launch", container.NewVBox(
+			d.gameCheck,
+			container.NewPadded(d.statsCheck),
+			d.networkCheck,
+			container.NewPadded(d.networkUICheck),
+		)),
+		widget.NewCard("Desktop notifications", "Shown by the system even while the companion is hidden", d.notifyGroup),
+		widget.NewCard("Privacy", "Copied text is never stored or sent anywhere", container.NewVBox(
+			d.clipboardCheck,
+			container.NewPadded(d.clipboardKinds),
+		)),
+		widget.NewCard("Troubleshooting", "", container.NewVBox(d.debugCheck, d.metricsCheck)),
+		d.statusLabel,
+	))
+	d.window.Resize(fyne.NewSize(380, 0))
+	return d
+}
+
+// Show displays the settings window
+func (d *SettingsDialog) Show() {
+	d.window.Show()
+}
+
+// SetOnClosed sets a callback for when the window closes
+func (d *SettingsDialog) SetOnClosed(onClosed func()) {
+	d.window.SetOnClosed(onClosed)
+}
+
+// RequestFocus brings the settings window to the front
+func (d *SettingsDialog) RequestFocus() {
+	d.window.RequestFocus()
+}
+
+// createWidgets builds the controls from the current settings. Callbacks are
+// attached after the initial values so opening the dialog saves nothing.
+func (d *SettingsDialog) createWidgets() {
+	s := d.settings
+
+	d.debugCheck = widget.NewCheck("Debug logging", nil)
+	d.debugCheck.SetChecked(s.Debug)
+	d.metricsCheck = widget.NewCheck("Show performance metrics", nil)
+	d.metricsCheck.SetChecked(s.Metrics)
+	d.ttsCheck = widget.NewCheck("Read dialog aloud", nil)
+	d.ttsCheck.SetChecked(s.TTS)
+	notifyLabels := make([]string, len(notificationOptions))
+	for i, option := range notificationOptions {
+		notifyLabels[i] = option.label
+	}
+	d.notifyGroup = widget.NewCheckGroup(notifyLabels, nil)
+	d.notifyGroup.SetSelected(selectedNotifications(s.Notifications))
+	d.autostartCheck = widget.NewCheck("Start with system", nil)
+	d.autostartCheck.Hide()
+
+	d.gameCheck = widget.NewCheck("Game mode", nil)
+	d.gameCheck.SetChecked(s.GameMode)
+	d.statsCheck = widget.NewCheck("Show stats overlay", nil)
+	d.statsCheck.SetChecked(s.ShowStats)
+	d.networkCheck = widget.NewCheck("Network mode", nil)
+	d.networkCheck.SetChecked(s.Network)
+	d.networkUICheck = widget.NewCheck("Show network overlay", nil)
+	d.networkUICheck.SetChecked(s.NetworkUI)
+	enableIf(d.statsCheck, s.GameMode)
+	enableIf(d.networkUICheck, s.Network)
+
+	d.clipboardCheck = widget.NewCheck("React to copied text", nil)
+	d.clipboardCheck.SetChecked(s.Clipboard)
+	kindNames := make([]string, len(clipboard.AllKinds))
+	for i, kind := range clipboard.AllKinds {
+		kindNames[i] = string(kind)
+	}
+	d.clipboardKinds = widget.NewCheckGroup(kindNames, nil)
+	d.clipboardKinds.Horizontal = true
+	d.clipboardKinds.SetSelected(selectedClipboardKinds(s.ClipboardKinds))
+	enableIf(d.clipboardKinds, s.Clipboard)
+
+	labels := make([]string, len(eventFrequencyOptions))
+	for i, option := range eventFrequencyOptions {
+		labels[i] = option.label
+	}
+	d.frequencySelect = widget.NewSelect(labels, nil)
+	d.frequencySelect.SetSelectedIndex(eventFrequencyIndex(s.EventFrequency))
+
+	themeLabels := make([]string, len(themeOptions))
+	for i, option := range themeOptions {
+		themeLabels[i] = option.label
+	}
+	d.themeSelect = widget.NewSelect(themeLabels, nil)
+	d.themeSelect.SetSelectedIndex(themeIndex(s.Theme))
+
+	clickLabels := make([]string, len(clickThroughOptions))
+	for i, option := range clickThroughOptions {
+		clickLabels[i] = option.label
+	}
+	d.clickSelect = widget.NewSelect(clickLabels, nil)
+	d.clickSelect.SetSelectedIndex(clickThroughIndex(s.ClickThrough))
+
+	fullscreenLabels := make([]string, len(fullscreenOptions))
+	for i, option := range fullscreenOptions {
+		fullscreenLabels[i] = option.label
+	}
+	d.fullscreenSelect = widget.NewSelect(fullscreenLabels, nil)
+	d.fullscreenSelect.SetSelectedIndex(max(0, fullscreenIndex(s.Fullscreen)))
+
+	battleLabels := make([]string, len(battleLatencyOptions))
+	for i, option := range battleLatencyOptions {
+		battleLabels[i] = option.label
+	}
+	d.battleSelect = widget.NewSelect(battleLabels, nil)
+	d.battleSelect.SetSelectedIndex(battleLatencyIndex(s.BattleLatency))
+
+	d.scaleSlider = widget.NewSlider(0.5, 2.5)
+	d.scaleSlider.Step = 0.1
+	d.scaleSlider.SetValue(scaleOrDefault(s.Scale))
+	d.scaleLabel = widget.NewLabel(formatScale(d.scaleSlider.Value))
+
+	d.statusLabel = widget.NewLabel("Changes are saved automatically.")
+	d.statusLabel.Wrapping = fyne.TextWrapWord
+
+	d.debugCheck.OnChanged = func(on bool) { d.update(func(s *config.Settings) { s.Debug = on }) }
+	d.metricsCheck.OnChanged = func(on bool) { d.update(func(s *config.Settings) { s.Metrics = on }) }
+	d.ttsCheck.OnChanged = func(on bool) { d.update(func(s *config.Settings) { s.TTS = on }) }
+	d.statsCheck.OnChanged = func(on bool) { d.update(func(s *config.Settings) { s.ShowStats = on }) }
+	d.networkUICheck.OnChanged = func(on bool) { d.update(func(s *config.Settings) { s.NetworkUI = on }) }
+	d.gameCheck.OnChanged = func(on bool) {
+		enableIf(d.statsCheck, on)
+		d.update(func(s *config.Settings) {
+			s.GameMode = on
+			s.ShowStats = s.ShowStats && on // -stats requires -game
+		})
+	}
+	d.networkCheck.OnChanged = func(on bool) {
+		enableIf(d.networkUICheck, on)
+		d.update(func(s *config.Settings) {
+			s.Network = on
+			s.NetworkUI = s.NetworkUI && on // -network-ui requires -network
+		})
+	}
+	d.clipboardCheck.OnChanged = func(on bool) {
+		enableIf(d.clipboardKinds, on)
+		d.update(func(s *config.Settings) { s.Clipboard = on })
+	}
+	d.clipboardKinds.OnChanged = func(selected []string) {
+		if len(selected) == 0 {
+			// An empty allowlist means the defaults, so unticking everything turns reactions off
+			d.clipboardCheck.SetChecked(false)
+			return
+		}
+		d.update(func(s *config.Settings) { s.ClipboardKinds = append([]string(nil), selected...) })
+	}
+	d.notifyGroup.OnChanged = func(selected []string) {
+		d.update(func(s *config.Settings) { s.Notifications = notificationNames(selected) })
+	}
+	d.frequencySelect.OnChanged = func(string) {
+		index := d.frequencySelect.SelectedIndex()
+		if index < 0 {
+			return
+		}
+		d.update(func(s *config.Settings) { s.EventFrequency = eventFrequencyOptions[index].multiplier })
+	}
+	d.themeSelect.OnChanged = func(string) {
+		index := d.themeSelect.SelectedIndex()
+		if index < 0 {
+			return
+		}
+		d.update(func(s *config.Settings) { s.Theme = themeOptions[index].name })
+	}
+	d.clickSelect.OnChanged = func(string) {
+		index := d.clickSelect.SelectedIndex()
+		if index < 0 {
+			return
+		}
+		d.update(func(s *config.Settings) { s.ClickThrough = clickThroughOptions[index].name })
+	}
+	d.fullscreenSelect.OnChanged = func(string) {
+		index := d.fullscreenSelect.SelectedIndex()
+		if index < 0 {
+			return
+		}
+		d.update(func(s *config.Settings) { s.Fullscreen = fullscreenOptions[index].name })
+	}
+	d.battleSelect.OnChanged = func(string) {
+		index := d.battleSelect.SelectedIndex()
+		if index < 0 {
+			return
+		}
+		d.update(func(s *config.Settings) { s.BattleLatency = battleLatencyOptions[index].millis })
+	}
+	// Resize once the drag ends; resizing on every step makes the window jump
+	d.scaleSlider.OnChanged = func(value float64) { d.scaleLabel.SetText(formatScale(value)) }
+	d.scaleSlider.OnChangeEnded = func(value float64) {
+		d.update(func(s *config.Settings) { s.Scale = math.Round(value*10) / 10 })
+	}
+}
+
+// SetAutostart shows "Start with system" for the OS login entry. Unlike the
+// other options it isn't kept in the settings file: the entry is the
+// setting, so the box stays in step when the entry is removed elsewhere.
+func (d *SettingsDialog) SetAutostart(autostart platform.Autostart) {
+	d.autostart = autostart
+	if autostart == nil {
+		d.autostartCheck.Hide()
+		return
+	}
+
+	enabled, err := autostart.Enabled()
+	if err != nil {
+		d.statusLabel.SetText(fmt.Sprintf("Could not read autostart: %v", err))
+	}
+	d.setAutostartChecked(enabled)
+	d.autostartCheck.Show()
+}
+
+// toggleAutostart adds or removes the login entry, unticking the box again
+// when that fails
+func (d *SettingsDialog) toggleAutostart(on bool) {
+	change := d.autostart.Disable
+	if on {
+		change = d.autostart.Enable
+	}
+	if err := change(); err != nil {
+		d.statusLabel.SetText(fmt.Sprintf("Could not change autostart: %v", err))
+		d.setAutostartChecked(!on)
+		return
+	}
+	d.statusLabel.SetText("Saved.")
+}
+
+// setAutostartChecked ticks the box without touching the login entry
+func (d *SettingsDialog) setAutostartChecked(on bool) {
+	d.autostartCheck.OnChanged = nil
+	d.autostartCheck.SetChecked(on)
+	d.autostartCheck.OnChanged = d.toggleAutostart
+}
+
+// update changes the settings, saves them and applies them
+func (d *SettingsDialog) update(change func(*config.Settings)) {
+	change(&d.settings)
+
+	if err := d.settings.Save(d.path); err != nil {
+		d.statusLabel.SetText(fmt.Sprintf("Could not save settings: %v", err))
+	} else {
+		d.statusLabel.SetText("Saved.")
+	}
+
+	if d.onApply != nil {
+		d.onApply(d.settings)
+	}
+}
+
+// eventFrequencyIndex returns the option closest to multiplier, treating 0 as 1.0
+func eventFrequencyIndex(multiplier float64) int {
+	if multiplier <= 0 {
+		multiplier = 1.0
+	}
+	best := 0
+	for i, option := range eventFrequencyOptions {
+		if math.Abs(option.multiplier-multiplier) < math.Abs(eventFrequencyOptions[best].multiplier-multiplier) {
+			best = i
+		}
+	}
+	return best
+}
+
+// themeIndex returns the option for a theme name, System when unset or unknown
+func themeIndex(name string) int {
+	for i, option := range themeOptions {
+		if option.name == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// clickThroughIndex returns the option for a click-through mode, the
+// sprite for unknown or empty modes
+func clickThroughIndex(name string) int {
+	for i, option := range clickThroughOptions {
+		if option.name == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// selectedClipboardKinds returns the allowlist to tick, the defaults when unset
+func selectedClipboardKinds(names []string) []string {
+	if len(names) > 0 {
+		return names
+	}
+	selected := make([]string, len(clipboard.DefaultKinds))
+	for i, kind := range clipboard.DefaultKinds {
+		selected[i] = string(kind)
+	}
+	return selected
+}
+
+// scaleOrDefault treats an unset scale as the card's default size
+func scaleOrDefault(scale float64) float64 {
+	if scale <= 0 {
+		return 1.0
+	}
+	return scale
+}
+
+// formatScale labels the size slider
+func formatScale(scale float64) string {
+	return fmt.Sprintf("%.1fx", scale)
+}
+
+// SetSettings enables the Settings menu item for the settings file at path
+// and applies the options that live in the window: speech, metrics and theme.
+// Scale and event frequency are applied at startup by the caller so a size
+// saved with Ctrl+scroll isn't overridden.
+func (dw *DesktopWindow) SetSettings(settings config.Settings, path string) {
+	dw.settingsMu.Lock()
+	dw.settings = settings
+	dw.settingsPath = path
+	dw.settingsMu.Unlock()
+
+	dw.speech.Store(settings.TTS)
+	dw.SetMetricsVisible(settings.Metrics)
+	dw.SetClipboardReactions(settings.Clipboard, clipboardKinds(settings.ClipboardKinds))
+	dw.applyNewsSettings(settings)
+	dw.SetFullscreenPolicy(settings.Fullscreen)
+	if settings.Theme != "" {
+		dw.applyTheme(settings.Theme, settings.ThemeColors)
+	}
+}
+
+// SetAutostart offers "Start with system" in the Settings dialog for the
+// given login entry
+func (dw *DesktopWindow) SetAutostart(autostart platform.Autostart) {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+	dw.autostart = autostart
+}
+
+// buildSettingsMenuItem creates the Settings entry when a settings file is set
+func (dw *DesktopWindow) buildSettingsMenuItem() (ContextMenuItem, bool) {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+	if dw.settingsPath == "" {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{Text: "⚙️ Settings", Callback: dw.showSettingsDialog}, true
+}
+
+// showSettingsDialog opens the settings window, or focuses it if it's open
+func (dw *DesktopWindow) showSettingsDialog() {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+
+	if dw.settingsDialog != nil {
+		dw.settingsDialog.RequestFocus()
+		return
+	}
+
+	dw.settingsDialog = NewSettingsDialog(fyne.CurrentApp(), dw.settings, dw.settingsPath, dw.applySettings)
+	dw.settingsDialog.SetAutostart(dw.autostart)
+	dw.settingsDialog.SetOnClosed(func() {
+		dw.settingsMu.Lock()
+		dw.settingsDialog = nil
+		dw.settingsMu.Unlock()
+	})
+	dw.settingsDialog.Show()
+}
+
+// applySettings makes the changed live settings take effect
+func (dw *DesktopWindow) applySettings(next config.Settings) {
+	dw.settingsMu.Lock()
+	prev := dw.settings
+	dw.settings = next
+	dw.settingsMu.Unlock()
+
+	if next.Debug != prev.Debug {
+		if next.Debug {
+			logrus.SetLevel(logrus.DebugLevel)
+		} else {
+			logrus.SetLevel(logrus.InfoLevel)
+		}
+	}
+	if next.EventFrequency != prev.EventFrequency && next.EventFrequency > 0 {
+		dw.character.SetEventFrequencyMultiplier(next.EventFrequency)
+	}
+	if next.Scale != prev.Scale {
+		defaultSize := dw.character.GetCard().Behavior.DefaultSize
+		dw.resizeCharacter(int(math.Round(float64(defaultSize) * scaleOrDefault(next.Scale))))
+	}
+	dw.speech.Store(next.TTS)
+	if next.Metrics != prev.Metrics {
+		dw.SetMetricsVisible(next.Metrics)
+	}
+	if next.Clipboard != prev.Clipboard || strings.Join(next.ClipboardKinds, ",") != strings.Join(prev.ClipboardKinds, ",") {
+		dw.SetClipboardReactions(next.Clipboard, clipboardKinds(next.ClipboardKinds))
+	}
+	if next.Fullscreen != prev.Fullscreen {
+		dw.SetFullscreenPolicy(next.Fullscreen)
+	}
+	if next.Theme != prev.Theme || !maps.Equal(next.ThemeColors, prev.ThemeColors) {
+		dw.applyTheme(next.Theme, next.ThemeColors)
+	}
+}
+
+// clipboardKinds converts allowlist names from the settings file, skipping
+// any this version doesn't know
+func clipboardKinds(names []string) []clipboard.Kind {
+	var kinds []clipboard.Kind
+	for _, name := range names {
+		if clipboard.IsKnownKind(name) {
+			kinds = append(kinds, clipboard.Kind(name))
+		}
+	}
+	return kinds
+}
+
+// SetSpeech turns reading dialog aloud on or off for this session, without
+// touching the settings file
+func (dw *DesktopWindow) SetSpeech(enabled bool) {
+	dw.speech.Store(enabled)
+}
+
+// speak reads dialog text aloud when text-to-speech is on
+func (dw *DesktopWindow) speak(text string) {
+	if !dw.speech.Load() {
+		return
+	}
+	if err := platform.Speak(text); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"error":  err.Error(),
+		}).Debug("Text-to-speech unavailable")
+	}
+}
+
+// SetMetricsVisible shows or hides the FPS and memory overlay
+func (dw *DesktopWindow) SetMetricsVisible(visible bool) {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+
+	if dw.metricsLabel == nil {
+		return
+	}
+	if !visible {
+		if dw.metricsStop != nil {
+			close(dw.metricsStop)
+			dw.metricsStop = nil
+		}
+		dw.metricsLabel.Hide()
+		return
+	}
+	if dw.metricsStop != nil {
+		return
+	}
+
+	dw.metricsStop = make(chan struct{})
+	dw.updateMetrics()
+	dw.metricsLabel.Show()
+	go dw.metricsLoop(dw.metricsStop)
+}
+
+// metricsLoop refreshes the metrics overlay every second until stop closes
+func (dw *DesktopWindow) metricsLoop(stop chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			dw.updateMetrics()
+		}
+	}
+}
+
+// updateMetrics shows the profiler's frame rate and memory, falling back to
+// the Go runtime's heap size when no profiler is running, and how often
+// animation switches found the animation preloaded
+func (dw *DesktopWindow) updateMetrics() {
+	var text string
+	if dw.profiler != nil {
+		stats := dw.profiler.GetStats()
+		text = fmt.Sprintf("%.0f FPS · %.1f MB", stats.FrameRate, stats.CurrentMemoryMB)
+		if stats.Degradation != monitoring.DegradationNone {
+			text += " · " + stats.Degradation.String()
+		}
+	} else {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		text = fmt.Sprintf("%.1f MB", float64(mem.Alloc)/1024/1024)
+	}
+
+	if dw.character != nil {
+		if cache := dw.character.GetAnimationCacheStats(); cache.Hits+cache.Misses > 0 {
+			text += fmt.Sprintf(" · %.0f%% preloaded", cache.HitRate*100)
+		}
+	}
+	dw.metricsLabel.SetText(text)
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+func TestSettingsDialogSavesAndApplies(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	path := filepath.Join(t.TempDir(), config.SettingsFileName)
+	var applied []config.Settings
+	dialog := NewSettingsDialog(app, config.Settings{GameMode: true, ShowStats: true}, path, func(s config.Settings) {
+		applied = append(applied, s)
+	})
+
+	if len(applied) != 0 {
+		t.Fatalf("Opening the dialog should not apply anything, got %d changes", len(applied))
+	}
+
+	dialog.gameCheck.SetChecked(false)
+	dialog.frequencySelect.SetSelectedIndex(3)
+
+	if len(applied) != 2 {
+		t.Fatalf("Expected 2 applied changes, got %d", len(applied))
+	}
+	last := applied[len(applied)-1]
+	if last.GameMode || last.ShowStats {
+		t.Error("Turning off game mode should also turn off the stats overlay")
+	}
+	if last.EventFrequency != 2.0 {
+		t.Errorf("Expected event frequency 2.0, got %v", last.EventFrequency)
+	}
+
+	saved, err := config.LoadSettings(path)
+	if err != nil {
+		t.Fatalf("Expected the settings file to be saved: %v", err)
+	}
+	if !reflect.DeepEqual(*saved, last) {
+		t.Errorf("Expected saved settings %+v, got %+v", last, *saved)
+	}
+}
+
+func TestApplySettingsLive(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if item, ok := dw.buildSettingsMenuItem(); ok {
+		t.Errorf("Expected no Settings menu item without a settings file, got %q", item.Text)
+	}
+	dw.SetSettings(config.Settings{}, filepath.Join(t.TempDir(), config.SettingsFileName))
+	if _, ok := dw.buildSettingsMenuItem(); !ok {
+		t.Error("Expected a Settings menu item once a settings file is set")
+	}
+
+	dw.applySettings(config.Settings{Scale: 1.5, EventFrequency: 2.0, Metrics: true, TTS: true})
+
+	if size := char.GetSize(); size != 192 {
+		t.Errorf("Expected 1.5x of the 128px default size, got %d", size)
+	}
+	if multiplier := char.GetEventFrequencyMultiplier(); multiplier != 2.0 {
+		t.Errorf("Expected event frequency 2.0, got %v", multiplier)
+	}
+	if !dw.metricsLabel.Visible() || !dw.speech.Load() {
+		t.Error("Expected metrics overlay and speech to be on")
+	}
+
+	dw.applySettings(config.Settings{Scale: 1.5, EventFrequency: 2.0})
+	if dw.metricsLabel.Visible() || dw.speech.Load() {
+		t.Error("Expected metrics overlay and speech to be off")
+	}
+}
+
+func TestEventFrequencyIndex(t *testing.T) {
+	tests := map[float64]int{0: 1, 0.5: 0, 1.4: 2, 3.0: 4, 10: 4}
+	for multiplier, want := range tests {
+		if got := eventFrequencyIndex(multiplier); got != want {
+			t.Errorf("eventFrequencyIndex(%v) = %d, want %d", multiplier, got, want)
+		}
+	}
+}
+
+func TestSettingsDialogClipboardAllowlist(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	path := filepath.Join(t.TempDir(), config.SettingsFileName)
+	var last config.Settings
+	dialog := NewSettingsDialog(app, config.Settings{}, path, func(s config.Settings) { last = s })
+
+	if !dialog.clipboardKinds.Disabled() {
+		t.Error("Reaction types should be disabled until clipboard reactions are on")
+	}
+	if !reflect.DeepEqual(dialog.clipboardKinds.Selected, []string{"url", "code"}) {
+		t.Errorf("Expected the default allowlist ticked, got %v", dialog.clipboardKinds.Selected)
+	}
+
+	dialog.clipboardCheck.SetChecked(true)
+	dialog.clipboardKinds.SetSelected([]string{"email"})
+	if !last.Clipboard || !reflect.DeepEqual(last.ClipboardKinds, []string{"email"}) {
+		t.Errorf("Expected clipboard reactions for email only, got %+v", last)
+	}
+
+	dialog.clipboardKinds.SetSelected(nil)
+	if last.Clipboard {
+		t.Error("Unticking every reaction type should turn clipboard reactions off")
+	}
+}
+
+// fakeAutostart records the login entry in memory
+type fakeAutostart struct {
+	enabled bool
+	fail    error
+}
+
+func (a *fakeAutostart) Enabled() (bool, error) { return a.enabled, nil }
+
+func (a *fakeAutostart) Enable() error {
+	if a.fail != nil {
+		return a.fail
+	}
+	a.enabled = true
+	return nil
+}
+
+func (a *fakeAutostart) Disable() error {
+	a.enabled = false
+	return nil
+}
+
+func TestSettingsDialogAutostart(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	path := filepath.Join(t.TempDir(), config.SettingsFileName)
+	dialog := NewSettingsDialog(app, config.Settings{}, path, nil)
+	if dialog.autostartCheck.Visible() {
+		t.Error("Expected Start with system hidden without an autostart entry")
+	}
+
+	autostart := &fakeAutostart{enabled: true}
+	dialog.SetAutostart(autostart)
+	if !dialog.autostartCheck.Visible() || !dialog.autostartCheck.Checked {
+		t.Fatal("Expected Start with system shown and ticked for an existing entry")
+	}
+
+	dialog.autostartCheck.SetChecked(false)
+	if autostart.enabled {
+		t.Error("Expected unticking to remove the entry")
+	}
+
+	autostart.fail = errors.New("read-only")
+	dialog.autostartCheck.SetChecked(true)
+	if autostart.enabled || dialog.autostartCheck.Checked {
+		t.Error("Expected the box unticked again when adding the entry fails")
+	}
+	if _, err := config.LoadSettings(path); err == nil {
+		t.Error("Expected autostart to stay out of the settings file")
+	}
+}
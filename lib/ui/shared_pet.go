@@ -0,0 +1,141 @@
+package ui
+
+// shared_pet.go lets two networked users co-own one character. Either can
+// feed and play with it on their own desktop; stats, interactions and
+// progression follow on the other's. Offers come in as notices and are
+// answered from the context menu.
+
+import (
+	"fmt"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// setupSharedPet applies the partner's changes to our character while it
+// is shared. Only game mode characters have anything to share.
+func (dw *DesktopWindow) setupSharedPet(char *character.Character) {
+	sharedPet := dw.networkOverlay.GetSharedPet()
+	if sharedPet == nil || !dw.gameMode || char.GetGameState() == nil {
+		return
+	}
+	sharedPet.SetEventHandler(func(event network.SharedPetEvent) {
+		dw.handleSharedPetEvent(char, event)
+	})
+}
+
+// handleSharedPetEvent applies what the partner changed and tells the user
+// about offers, the partner's interactions and sharing starting or ending
+func (dw *DesktopWindow) handleSharedPetEvent(char *character.Character, event network.SharedPetEvent) {
+	peer := shortPeerID(event.PeerID)
+	switch event.Kind {
+	case network.SharedPetOffered:
+		dw.showDialog(fmt.Sprintf("%s wants to share %s with you. Right-click to accept or decline.", peer, event.Character))
+	case network.SharedPetStarted:
+		if event.Values.Stats != nil {
+			char.ApplySharedPetValues(event.Values)
+		}
+		dw.showDialog(fmt.Sprintf("I'm shared with %s now! We'll both look after me.", peer))
+	case network.SharedPetDeclined:
+		if event.Reason != "" {
+			dw.showDialog(fmt.Sprintf("%s can't share me: %s", peer, event.Reason))
+			return
+		}
+		dw.showDialog(fmt.Sprintf("%s doesn't want to share me right now.", peer))
+	case network.SharedPetChanged:
+		char.ApplySharedPetValues(event.Values)
+	case network.SharedPetInteraction:
+		dw.showDialog(fmt.Sprintf("%s gave me some attention: %s!", peer, event.Interaction))
+	case network.SharedPetEnded:
+		dw.showDialog(fmt.Sprintf("I'm not shared with %s anymore.", peer))
+	}
+}
+
+// buildSharedPetMenuItems offers sharing our character with a peer,
+// answering peers' offers and, while shared, stopping
+func (dw *DesktopWindow) buildSharedPetMenuItems() []ContextMenuItem {
+	sharedPet := dw.networkOverlay.GetSharedPet()
+	if sharedPet == nil || !dw.gameMode || dw.character.GetGameState() == nil {
+		return nil
+	}
+
+	if partner := sharedPet.Partner(); partner != "" {
+		return []ContextMenuItem{{
+			Text: fmt.Sprintf("🤝 Stop Sharing with %s", shortPeerID(partner)),
+			Callback: func() {
+				if err := sharedPet.Leave(); err != nil {
+					dw.showDialog(fmt.Sprintf("Failed to stop sharing: %v", err))
+				}
+			},
+		}}
+	}
+
+	items := []ContextMenuItem{{
+		Text:     "🤝 Share Pet",
+		Callback: dw.offerSharedPet,
+	}}
+	for _, peerID := range sharedPet.Offers() {
+		peerID := peerID
+		items = append(items,
+			ContextMenuItem{
+				Text: fmt.Sprintf("✅ Share Pet with %s", shortPeerID(peerID)),
+				Callback: func() {
+					if err := sharedPet.Accept(peerID); err != nil {
+						dw.showDialog(fmt.Sprintf("Failed to share: %v", err))
+					}
+				},
+			},
+			ContextMenuItem{
+				Text: fmt.Sprintf("❌ Decline %s's Offer", shortPeerID(peerID)),
+				Callback: func() {
+					_ = sharedPet.Decline(peerID)
+				},
+			})
+	}
+	return items
+}
+
+// offerSharedPet offers a connected peer to co-own our character, letting
+// the user pick the peer when there are several
+func (dw *DesktopWindow) offerSharedPet() {
+	peers := dw.networkOverlay.GetNetworkManager().GetPeers()
+	if len(peers) == 0 {
+		dw.showDialog("No other players connected. Sharing needs a connected peer.")
+		return
+	}
+
+	offer := func(peer network.Peer) {
+		values := dw.character.GetSharedPetValues()
+		if err := dw.networkOverlay.GetSharedPet().Offer(peer.ID, values); err != nil {
+			dw.showDialog(fmt.Sprintf("Failed to offer sharing: %v", err))
+			return
+		}
+		dw.showDialog(fmt.Sprintf("Asked %s to share me...", shortPeerID(peer.ID)))
+	}
+	if len(peers) == 1 {
+		offer(peers[0])
+		return
+	}
+	dw.peerSelectionDialog.Show(peers, offer, func() {})
+}
+
+// publishSharedPet sends the partner our character's changes while shared
+func (dw *DesktopWindow) publishSharedPet() {
+	if dw.networkOverlay == nil || !dw.gameMode {
+		return
+	}
+	if sharedPet := dw.networkOverlay.GetSharedPet(); sharedPet != nil && sharedPet.Partner() != "" {
+		sharedPet.Publish(dw.character.GetSharedPetValues())
+	}
+}
+
+// leaveSharedPet stops sharing when the window closes; each side keeps
+// its copy of the character
+func (dw *DesktopWindow) leaveSharedPet() {
+	if dw.networkOverlay == nil {
+		return
+	}
+	if sharedPet := dw.networkOverlay.GetSharedPet(); sharedPet != nil {
+		_ = sharedPet.Leave()
+	}
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// buildShopMenuItem creates the shop entry for the game menu, showing the coin balance
+func (dw *DesktopWindow) buildShopMenuItem() (ContextMenuItem, bool) {
+	if len(dw.character.GetShopItems()) == 0 {
+		return ContextMenuItem{}, false
+	}
+
+	return ContextMenuItem{
+		Text: fmt.Sprintf("🛒 Shop (🪙 %d)", dw.character.GetGameState().GetCoins()),
+		Callback: func() {
+			dw.showShop()
+		},
+	}, true
+}
+
+// showShop displays the shop items with a buy button for each
+func (dw *DesktopWindow) showShop() {
+	items := dw.character.GetShopItems()
+
+	titleLabel := widget.NewLabel(fmt.Sprintf("Shop - 🪙 %d coins", dw.character.GetGameState().GetCoins()))
+	titleLabel.Alignment = fyne.TextAlignCenter
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rows := []fyne.CanvasObject{titleLabel}
+	for _, item := range items {
+		rows = append(rows, dw.buildShopRow(item))
+
+		if item.Description != "" {
+			description := widget.NewLabel(item.Description)
+			description.Wrapping = fyne.TextWrapWord
+			rows = append(rows, description)
+		}
+	}
+
+	content := container.NewVBox(rows...)
+	content.Resize(fyne.NewSize(280, float32(40+60*len(items))))
+	dw.showModalContent(content)
+}
+
+// buildShopRow creates the buy (or equip) button for one item
+func (dw *DesktopWindow) buildShopRow(item character.ShopListing) *widget.Button {
+	itemID := item.ID
+
+	// Owned skins toggle instead of being bought again
+	if item.Type == character.ShopItemSkin && item.Owned > 0 {
+		label, skin := fmt.Sprintf("Wear %s", item.Name), itemID
+		if item.Equipped {
+			label, skin = fmt.Sprintf("Take off %s", item.Name), ""
+		}
+		return widget.NewButton(label, func() {
+			if err := dw.character.EquipSkin(skin); err != nil {
+				dw.showDialog(err.Error())
+			}
+		})
+	}
+
+	button := widget.NewButton(formatShopLabel(item), func() {
+		response, err := dw.character.Purchase(itemID)
+		if err != nil {
+			dw.showDialog(err.Error())
+			return
+		}
+		dw.showDialog(response)
+	})
+	if !item.Available || !item.Affordable {
+		button.Disable()
+	}
+	return button
+}
+
+// formatShopLabel renders "Name - 10 🪙 (2 left)"
+func formatShopLabel(item character.ShopListing) string {
+	label := fmt.Sprintf("%s - %d 🪙", item.Name, item.Price)
+	switch {
+	case item.Remaining == 0:
+		label += " (sold out)"
+	case item.Remaining > 0:
+		label += fmt.Sprintf(" (%d left)", item.Remaining)
+	}
+	return label
+}
@@ -0,0 +1,248 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/persistence"
+)
+
+func TestFormatShopLabel(t *testing.T) {
+	tests := []struct {
+		item     character.ShopListing
+		expected string
+	}{
+		{character.ShopListing{Name: "Cake", Price: 5, Remaining: -1}, "Cake - 5 🪙"},
+		{character.ShopListing{Name: "Hat", Price: 20, Remaining: 2}, "Hat - 20 🪙 (2 left)"},
+		{character.ShopListing{Name: "Crown", Price: 99, Remaining: 0}, "Crown - 99 🪙 (sold out)"},
+	}
+
+	for _, tt := range tests {
+		if got := formatShopLabel(tt.item); got != tt.expected {
+			t.Errorf("formatShopLabel(%+v) = %q, want %q", tt.item, got, tt.expected)
+		}
+	}
+}
+
+// createTestCharacterWithShop builds a character whose card sells a gift,
+// a skin and an event unlock
+func createTestCharacterWithShop(t *testing.T, tmpDir string) *character.Character {
+	createTestAnimationFiles(t, tmpDir)
+
+	cardContent := `{
+		"name": "Shopkeeper",
+		"description": "A test character with a shop",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "happy": "happy.gif"},
+		"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 128},
+		"stats": {
+			"happiness": {"initial": 50, "max": 100, "degradationRate": 0, "criticalThreshold": 15}
+		},
+		"gameRules": {"statsDecayInterval": 60, "autoSaveInterval": 300},
+		"shop": {
+			"items": {
+				"flowers": {"name": "Flowers", "type": "gift", "price": 10, "stock": 1},
+				"party": {"name": "Party Hat", "type": "skin", "price": 20, "animations": {"idle": "happy"}},
+				"story": {"name": "Story Book", "type": "unlock", "price": 5, "responses": ["Story time!"]}
+			}
+		},
+		"generalEvents": [{
+			"name": "bedtime_story",
+			"description": "Read a story together",
+			"category": "conversation",
+			"trigger": "bedtime_story",
+			"responses": ["Once upon a time..."],
+			"requiresPurchase": "story"
+		}]
+	}`
+
+	cardPath := filepath.Join(tmpDir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(cardContent), 0o644); err != nil {
+		t.Fatalf("Failed to write test character card: %v", err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Failed to load test character card: %v", err)
+	}
+	char, err := character.New(card, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	return char
+}
+
+// findShopListing returns the listing for id
+func findShopListing(t *testing.T, char *character.Character, id string) character.ShopListing {
+	for _, item := range char.GetShopItems() {
+		if item.ID == id {
+			return item
+		}
+	}
+	t.Fatalf("Shop item %q not listed", id)
+	return character.ShopListing{}
+}
+
+func TestShopRefusesWithoutCoins(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithShop(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if item, ok := dw.buildShopMenuItem(); !ok || item.Text != "🛒 Shop (🪙 0)" {
+		t.Errorf("Expected the shop with an empty wallet, got %q (%v)", item.Text, ok)
+	}
+	if button := dw.buildShopRow(findShopListing(t, char, "flowers")); !button.Disabled() {
+		t.Error("Buy button should be disabled without enough coins")
+	}
+
+	char.GetGameState().AddCoins(5)
+	if _, err := char.Purchase("flowers"); err == nil || !strings.Contains(err.Error(), "not enough coins") {
+		t.Errorf("Expected not enough coins error, got %v", err)
+	}
+	if coins := char.GetGameState().GetCoins(); coins != 5 {
+		t.Errorf("A refused purchase should not spend coins, got %d", coins)
+	}
+	if count := char.GetGameState().GetInventory()["flowers"]; count != 0 {
+		t.Errorf("A refused purchase should not add items, got %d", count)
+	}
+}
+
+func TestShopStockRunsOut(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithShop(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+	char.GetGameState().AddCoins(30)
+
+	test.Tap(dw.buildShopRow(findShopListing(t, char, "flowers")))
+	if coins := char.GetGameState().GetCoins(); coins != 20 {
+		t.Errorf("Expected the price to be paid, got %d coins", coins)
+	}
+	if count := char.GetGameState().GetInventory()["flowers"]; count != 1 {
+		t.Errorf("Expected flowers in the inventory, got %d", count)
+	}
+
+	listing := findShopListing(t, char, "flowers")
+	if listing.Remaining != 0 || listing.Available {
+		t.Errorf("Flowers should be sold out, got %+v", listing)
+	}
+	button := dw.buildShopRow(listing)
+	if !button.Disabled() || !strings.HasSuffix(button.Text, "(sold out)") {
+		t.Errorf("Expected a disabled sold out button, got %q", button.Text)
+	}
+	if _, err := char.Purchase("flowers"); err == nil || !strings.Contains(err.Error(), "sold out") {
+		t.Errorf("Expected sold out error, got %v", err)
+	}
+	if coins := char.GetGameState().GetCoins(); coins != 20 {
+		t.Errorf("A sold out item should not take coins, got %d", coins)
+	}
+}
+
+func TestShopSkinEquipAndUnequip(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithShop(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	if err := char.EquipSkin("party"); err == nil {
+		t.Error("A skin that was not bought should not be equipped")
+	}
+
+	char.GetGameState().AddCoins(20)
+	if _, err := char.Purchase("party"); err != nil {
+		t.Fatalf("Purchase failed: %v", err)
+	}
+	if skin := char.GetGameState().GetActiveSkin(); skin != "party" {
+		t.Fatalf("Skin should be equipped after purchase, got %q", skin)
+	}
+
+	button := dw.buildShopRow(findShopListing(t, char, "party"))
+	if button.Text != "Take off Party Hat" {
+		t.Fatalf("Expected an unequip button, got %q", button.Text)
+	}
+	test.Tap(button)
+	if skin := char.GetGameState().GetActiveSkin(); skin != "" {
+		t.Errorf("Skin should be taken off, got %q", skin)
+	}
+
+	button = dw.buildShopRow(findShopListing(t, char, "party"))
+	if button.Text != "Wear Party Hat" {
+		t.Fatalf("Expected an equip button, got %q", button.Text)
+	}
+	test.Tap(button)
+	if skin := char.GetGameState().GetActiveSkin(); skin != "party" {
+		t.Errorf("Skin should be worn again, got %q", skin)
+	}
+	if coins := char.GetGameState().GetCoins(); coins != 0 {
+		t.Errorf("Toggling an owned skin should be free, got %d coins", coins)
+	}
+}
+
+func TestShopPurchaseUnlocksEvent(t *testing.T) {
+	char := createTestCharacterWithShop(t, t.TempDir())
+
+	if char.IsGeneralEventAvailable("bedtime_story") {
+		t.Fatal("Event should stay locked until the story book is bought")
+	}
+
+	char.GetGameState().AddCoins(5)
+	if response, err := char.Purchase("story"); err != nil || response != "Story time!" {
+		t.Fatalf("Purchase failed: %q %v", response, err)
+	}
+	if !char.IsGeneralEventAvailable("bedtime_story") {
+		t.Error("Event should unlock after the purchase")
+	}
+}
+
+func TestShopPurchasesSurviveSave(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithShop(t, t.TempDir())
+	char.GetGameState().AddCoins(50)
+	for _, id := range []string{"flowers", "party"} {
+		if _, err := char.Purchase(id); err != nil {
+			t.Fatalf("Purchase %s failed: %v", id, err)
+		}
+	}
+
+	manager := persistence.NewSaveManager(t.TempDir())
+	defer manager.Close()
+	if err := manager.SaveGameState(char.GetName(), char.SaveData()); err != nil {
+		t.Fatalf("SaveGameState failed: %v", err)
+	}
+	loaded, err := manager.LoadGameState(char.GetName())
+	if err != nil {
+		t.Fatalf("LoadGameState failed: %v", err)
+	}
+
+	fresh := createTestCharacterWithShop(t, t.TempDir())
+	fresh.RestoreSaveData(loaded)
+	gs := fresh.GetGameState()
+
+	if coins := gs.GetCoins(); coins != 20 {
+		t.Errorf("Expected 20 coins after reload, got %d", coins)
+	}
+	if gs.GetPurchaseCount("flowers") != 1 || gs.GetPurchaseCount("party") != 1 {
+		t.Errorf("Expected purchases after reload, got flowers=%d party=%d", gs.GetPurchaseCount("flowers"), gs.GetPurchaseCount("party"))
+	}
+	if skin := gs.GetActiveSkin(); skin != "party" {
+		t.Errorf("Expected the equipped skin after reload, got %q", skin)
+	}
+	if _, err := fresh.Purchase("flowers"); err == nil || !strings.Contains(err.Error(), "sold out") {
+		t.Errorf("Stock should count purchases from before the reload, got %v", err)
+	}
+
+	dw := NewDesktopWindow(app, fresh, false, nil, false, false, nil, false, false, false)
+	if item, _ := dw.buildShopMenuItem(); item.Text != "🛒 Shop (🪙 20)" {
+		t.Errorf("Menu should show the restored balance, got %q", item.Text)
+	}
+}
@@ -0,0 +1,237 @@
+package ui
+
+// shy.go implements shy mode: the character slips behind the nearest screen
+// edge with only its ears and eyes peeking out, and comes back out when the
+// mouse hovers over it, it is tapped or it has something to say. Where the
+// window can't be moved (e.g. Wayland) it peeks in place.
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+const (
+	shyPeekFraction = 0.3             // Part of the sprite left on screen while hiding
+	shyHideDelay    = 5 * time.Second // How long the character stays out once left alone
+)
+
+// ScreenEdge is the side of the screen a shy character hides behind
+type ScreenEdge int
+
+const (
+	EdgeNone ScreenEdge = iota
+	EdgeBottom
+	EdgeLeft
+	EdgeRight
+)
+
+// shyState tracks shy mode for the window
+type shyState struct {
+	mu       sync.Mutex
+	enabled  bool
+	hidden   bool
+	moved    bool        // The window was moved to the edge and must be moved back
+	home     image.Point // Window position before hiding
+	hovering bool        // The mouse is over the character, so it stays out
+	timer    *time.Timer // Pending hide
+}
+
+// SetShyMode turns shy mode on, hiding the character at once, or off,
+// bringing it back out
+func (dw *DesktopWindow) SetShyMode(enabled bool) {
+	dw.shy.mu.Lock()
+	defer dw.shy.mu.Unlock()
+
+	dw.shy.enabled = enabled
+	dw.stopShyTimer()
+	if enabled {
+		dw.hideShyLocked()
+	} else {
+		dw.emergeShyLocked()
+	}
+}
+
+// IsShyMode returns whether shy mode is on
+func (dw *DesktopWindow) IsShyMode() bool {
+	dw.shy.mu.Lock()
+	defer dw.shy.mu.Unlock()
+	return dw.shy.enabled
+}
+
+// isShyHidden returns whether the character is hiding at the edge
+func (dw *DesktopWindow) isShyHidden() bool {
+	dw.shy.mu.Lock()
+	defer dw.shy.mu.Unlock()
+	return dw.shy.hidden
+}
+
+// shyHover brings the character out while the mouse is over it and hides
+// it again a little after the mouse leaves
+func (dw *DesktopWindow) shyHover(inside bool) {
+	dw.shy.mu.Lock()
+	defer dw.shy.mu.Unlock()
+
+	dw.shy.hovering = inside
+	if !dw.shy.enabled {
+		return
+	}
+	if inside {
+		dw.stopShyTimer()
+		dw.emergeShyLocked()
+		return
+	}
+	dw.scheduleShyHideLocked()
+}
+
+// emergeShy brings a hiding character out for a while, e.g. when tapped or
+// to say something. Returns true if it was hiding.
+func (dw *DesktopWindow) emergeShy() bool {
+	dw.shy.mu.Lock()
+	defer dw.shy.mu.Unlock()
+
+	if !dw.shy.enabled {
+		return false
+	}
+	wasHidden := dw.shy.hidden
+	dw.emergeShyLocked()
+	dw.scheduleShyHideLocked()
+	return wasHidden
+}
+
+// scheduleShyHideLocked hides the character after shyHideDelay unless the
+// mouse is over it. Must be called with dw.shy.mu held.
+func (dw *DesktopWindow) scheduleShyHideLocked() {
+	if dw.shy.hovering || dw.shy.hidden {
+		return
+	}
+	dw.stopShyTimer()
+	dw.shy.timer = time.AfterFunc(shyHideDelay, func() {
+		dw.shy.mu.Lock()
+		defer dw.shy.mu.Unlock()
+		if dw.shy.enabled && !dw.shy.hovering {
+			dw.hideShyLocked()
+		}
+	})
+}
+
+// stopShyTimer cancels a pending hide. Must be called with dw.shy.mu held.
+func (dw *DesktopWindow) stopShyTimer() {
+	if dw.shy.timer != nil {
+		dw.shy.timer.Stop()
+		dw.shy.timer = nil
+	}
+}
+
+// hideShyLocked moves the window behind the nearest screen edge and masks
+// the sprite down to the part that peeks out. Must be called with
+// dw.shy.mu held.
+func (dw *DesktopWindow) hideShyLocked() {
+	if dw.shy.hidden {
+		return
+	}
+
+	edge := EdgeBottom
+	err := native.ErrPositionUnsupported
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		var frame, screen image.Rectangle
+		if frame, screen, err = native.WindowGeometry(win); err != nil {
+			return
+		}
+		edge = nearestEdge(frame, screen)
+		peek := peekPosition(frame, screen, edge, shyPeekFraction)
+		if err = native.MoveWindow(win, peek.X, peek.Y); err == nil {
+			dw.shy.home = frame.Min
+		}
+	})
+	dw.shy.moved = err == nil
+	if err != nil {
+		level := logrus.WarnLevel
+		if errors.Is(err, native.ErrPositionUnsupported) {
+			level = logrus.DebugLevel
+		}
+		logrus.WithError(err).Log(level, "Window can't be moved, peeking in place")
+	}
+
+	dw.renderer.SetPeek(edge, shyPeekFraction)
+	dw.shy.hidden = true
+}
+
+// emergeShyLocked moves the window back and shows the whole sprite. Must be
+// called with dw.shy.mu held.
+func (dw *DesktopWindow) emergeShyLocked() {
+	if !dw.shy.hidden {
+		return
+	}
+	if dw.shy.moved {
+		home := dw.shy.home
+		withNativeWindow(dw.window, func(win native.NativeWindow) {
+			if err := native.MoveWindow(win, home.X, home.Y); err != nil {
+				logrus.WithError(err).Warn("Failed to move the window back from the screen edge")
+			}
+		})
+	}
+	dw.renderer.SetPeek(EdgeNone, 0)
+	dw.shy.hidden = false
+	dw.shy.moved = false
+}
+
+// stopShyMode cancels a pending hide when the window closes
+func (dw *DesktopWindow) stopShyMode() {
+	dw.shy.mu.Lock()
+	defer dw.shy.mu.Unlock()
+	dw.stopShyTimer()
+}
+
+// buildShyModeMenuItem creates the toggle for hiding at the screen edge
+func (dw *DesktopWindow) buildShyModeMenuItem() ContextMenuItem {
+	if dw.IsShyMode() {
+		return ContextMenuItem{
+			Text: "🙈 Disable Shy Mode",
+			Callback: func() {
+				dw.SetShyMode(false)
+			},
+		}
+	}
+
+	return ContextMenuItem{
+		Text: "🙈 Shy Mode",
+		Callback: func() {
+			dw.SetShyMode(true)
+		},
+	}
+}
+
+// nearestEdge returns the screen edge closest to the window. The top edge
+// is left out since only ears and eyes should peek out.
+func nearestEdge(frame, screen image.Rectangle) ScreenEdge {
+	edge, distance := EdgeBottom, screen.Max.Y-frame.Max.Y
+	if d := frame.Min.X - screen.Min.X; d < distance {
+		edge, distance = EdgeLeft, d
+	}
+	if d := screen.Max.X - frame.Max.X; d < distance {
+		edge = EdgeRight
+	}
+	return edge
+}
+
+// peekPosition returns where to move the window so only fraction of it is
+// left on screen past edge
+func peekPosition(frame, screen image.Rectangle, edge ScreenEdge, fraction float64) image.Point {
+	switch edge {
+	case EdgeLeft:
+		visible := int(float64(frame.Dx()) * fraction)
+		return image.Pt(screen.Min.X-frame.Dx()+visible, frame.Min.Y)
+	case EdgeRight:
+		visible := int(float64(frame.Dx()) * fraction)
+		return image.Pt(screen.Max.X-visible, frame.Min.Y)
+	default:
+		visible := int(float64(frame.Dy()) * fraction)
+		return image.Pt(frame.Min.X, screen.Max.Y-visible)
+	}
+}
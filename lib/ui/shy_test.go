@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestNearestEdge(t *testing.T) {
+	screen := image.Rect(0, 0, 1920, 1080)
+	tests := []struct {
+		name  string
+		frame image.Rectangle
+		want  ScreenEdge
+	}{
+		{"near the bottom", image.Rect(900, 900, 1028, 1028), EdgeBottom},
+		{"near the left", image.Rect(10, 400, 138, 528), EdgeLeft},
+		{"near the right", image.Rect(1780, 400, 1908, 528), EdgeRight},
+		{"near the top uses the closest side", image.Rect(1000, 0, 1128, 128), EdgeRight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearestEdge(tt.frame, screen); got != tt.want {
+				t.Errorf("nearestEdge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeekPosition(t *testing.T) {
+	screen := image.Rect(0, 0, 1920, 1080)
+	frame := image.Rect(500, 400, 600, 500)
+
+	if got := peekPosition(frame, screen, EdgeBottom, 0.3); got != image.Pt(500, 1050) {
+		t.Errorf("Bottom peek should leave 30px on screen, got %v", got)
+	}
+	if got := peekPosition(frame, screen, EdgeLeft, 0.3); got != image.Pt(-70, 400) {
+		t.Errorf("Left peek should leave 30px on screen, got %v", got)
+	}
+	if got := peekPosition(frame, screen, EdgeRight, 0.3); got != image.Pt(1890, 400) {
+		t.Errorf("Right peek should leave 30px on screen, got %v", got)
+	}
+}
+
+func TestPeekImageMasksHiddenPart(t *testing.T) {
+	frame := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			frame.Set(x, y, color.White)
+		}
+	}
+
+	peek := &peekImage{Image: frame, visible: peekRect(frame.Bounds(), EdgeBottom, 0.3)}
+	if _, _, _, a := peek.At(5, 1).RGBA(); a == 0 {
+		t.Error("The top of the sprite should peek out above the bottom edge")
+	}
+	if _, _, _, a := peek.At(5, 8).RGBA(); a != 0 {
+		t.Error("The rest of the sprite should be hidden")
+	}
+
+	if got := peekRect(frame.Bounds(), EdgeLeft, 0.3); got != image.Rect(7, 0, 10, 10) {
+		t.Errorf("Hiding off the left should show the right side, got %v", got)
+	}
+}
+
+func TestShyModeToggle(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+	defer dw.Close()
+
+	// The test driver can't move windows, so the character peeks in place
+	item := dw.buildShyModeMenuItem()
+	item.Callback()
+	if !dw.IsShyMode() || !dw.isShyHidden() {
+		t.Fatal("Shy mode should hide the character at once")
+	}
+	if _, ok := dw.renderer.VisibleFrame().(*peekImage); !ok {
+		t.Error("Only the peeking part of the sprite should be drawn")
+	}
+
+	dw.shyHover(true)
+	if dw.isShyHidden() {
+		t.Error("Hovering should bring the character out")
+	}
+	dw.shyHover(false)
+	if dw.shy.timer == nil {
+		t.Error("Leaving should schedule hiding again")
+	}
+
+	if item := dw.buildShyModeMenuItem(); item.Text != "🙈 Disable Shy Mode" {
+		t.Errorf("Unexpected menu text while enabled: %q", item.Text)
+	}
+	dw.SetShyMode(false)
+	if dw.isShyHidden() || dw.shy.timer != nil {
+		t.Error("Disabling shy mode should bring the character out for good")
+	}
+	if _, ok := dw.renderer.VisibleFrame().(*peekImage); ok {
+		t.Error("The whole sprite should be drawn again")
+	}
+}
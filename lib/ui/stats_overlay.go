@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"image/color"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,8 +22,10 @@ type StatsOverlay struct {
 	container    *fyne.Container
 	progressBars map[string]*widget.ProgressBar
 	statLabels   map[string]*widget.Label
+	buffLabel    *widget.Label // Running buffs and debuffs, hidden when there are none
 	visible      bool
 	updateTicker *time.Ticker
+	refresh      time.Duration // Update interval, 0 for overlayRefreshInterval
 	stopUpdate   chan bool
 	mu           sync.RWMutex // Protects updateTicker and background goroutine state
 }
@@ -78,6 +81,10 @@ func (so *StatsOverlay) createStatsWidgets() {
 		widgets = append(widgets, label, progressBar)
 	}
 
+	so.buffLabel = widget.NewLabel("")
+	so.buffLabel.Hide()
+	widgets = append(widgets, so.buffLabel)
+
 	// Create container with vertical layout for compact display
 	so.container = container.NewVBox(widgets...)
 	so.container.Hide() // Start hidden
@@ -129,7 +136,8 @@ func (so *StatsOverlay) IsVisible() bool {
 }
 
 // startUpdateLoop begins periodic updates of stat display
-// Updates every 2 seconds to balance responsiveness with performance
+// Updates every 2 seconds to balance responsiveness with performance,
+// less often on slow hardware (see SetRefreshInterval)
 func (so *StatsOverlay) startUpdateLoop() {
 	so.mu.Lock()
 	if so.updateTicker != nil {
@@ -137,7 +145,7 @@ func (so *StatsOverlay) startUpdateLoop() {
 		return // Already running
 	}
 
-	so.updateTicker = time.NewTicker(2 * time.Second)
+	so.updateTicker = time.NewTicker(so.refreshIntervalLocked())
 	ticker := so.updateTicker // Capture ticker under lock
 	so.mu.Unlock()
 
@@ -176,6 +184,27 @@ func (so *StatsOverlay) stopUpdateLoop() {
 	}
 }
 
+// SetRefreshInterval changes how often the stats refresh, taking effect at
+// once if the overlay is showing
+func (so *StatsOverlay) SetRefreshInterval(interval time.Duration) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+
+	so.refresh = interval
+	if so.updateTicker != nil {
+		so.updateTicker.Reset(so.refreshIntervalLocked())
+	}
+}
+
+// refreshIntervalLocked returns the update interval. Must be called with
+// so.mu held.
+func (so *StatsOverlay) refreshIntervalLocked() time.Duration {
+	if so.refresh <= 0 {
+		return overlayRefreshInterval
+	}
+	return so.refresh
+}
+
 // updateStatDisplay refreshes the progress bars and labels with current stat values
 // Uses character's GetGameState method to get current values
 func (so *StatsOverlay) updateStatDisplay() {
@@ -215,6 +244,35 @@ func (so *StatsOverlay) updateStatDisplay() {
 			}
 		}
 	}
+
+	if so.buffLabel != nil {
+		if text := formatBuffs(gameState.GetBuffs(), time.Now()); text != "" {
+			so.buffLabel.SetText(text)
+			so.buffLabel.Show()
+		} else {
+			so.buffLabel.Hide()
+		}
+	}
+}
+
+// formatBuffs renders running buffs as icons with their stacks and time
+// left, e.g. "☕ 45m  🍬×2 5m"
+func formatBuffs(buffs []character.ActiveBuff, now time.Time) string {
+	parts := make([]string, 0, len(buffs))
+	for _, buff := range buffs {
+		icon := buff.Icon
+		if icon == "" {
+			icon = "✨"
+			if buff.IsDebuff() {
+				icon = "💢"
+			}
+		}
+		if buff.Stacks > 1 {
+			icon += fmt.Sprintf("×%d", buff.Stacks)
+		}
+		parts = append(parts, icon+" "+formatJobDuration(buff.ExpiresAt.Sub(now)))
+	}
+	return strings.Join(parts, "  ")
 }
 
 // GetContainer returns the container for external positioning
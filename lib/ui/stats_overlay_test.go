@@ -497,3 +497,21 @@ func createTestAnimationFiles(t *testing.T, dir string) {
 		}
 	}
 }
+
+// TestFormatBuffs tests the buff icons shown under the stats
+func TestFormatBuffs(t *testing.T) {
+	now := time.Now()
+	buffs := []character.ActiveBuff{
+		{Name: "Coffee", Icon: "☕", Stacks: 1, ExpiresAt: now.Add(45 * time.Minute)},
+		{Name: "Sugar", Gain: map[string]float64{"energy": 0.1}, Stacks: 2, ExpiresAt: now.Add(90 * time.Minute)},
+		{Name: "Cold", Decay: map[string]float64{"health": 0.5}, Stacks: 1, ExpiresAt: now.Add(30 * time.Second)},
+	}
+
+	want := "☕ 45m  ✨×2 1h 30m  💢 30s"
+	if got := formatBuffs(buffs, now); got != want {
+		t.Errorf("formatBuffs() = %q, want %q", got, want)
+	}
+	if got := formatBuffs(nil, now); got != "" {
+		t.Errorf("Expected no text without buffs, got %q", got)
+	}
+}
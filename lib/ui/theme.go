@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// App theme names for the settings file
+const (
+	ThemeSystem = "system" // Follow the desktop's light/dark preference (default)
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeCustom = "custom" // User colors from the settings file
+)
+
+// themeOptions are the themes offered in the settings window
+var themeOptions = []struct {
+	label string
+	name  string
+}{
+	{"System", ThemeSystem},
+	{"Light", ThemeLight},
+	{"Dark", ThemeDark},
+	{"Custom", ThemeCustom},
+}
+
+// themeColorNames are the colors a custom theme may set, by settings key
+var themeColorNames = map[string]fyne.ThemeColorName{
+	"primary":           theme.ColorNamePrimary,
+	"background":        theme.ColorNameBackground,
+	"foreground":        theme.ColorNameForeground,
+	"button":            theme.ColorNameButton,
+	"inputBackground":   theme.ColorNameInputBackground,
+	"overlayBackground": theme.ColorNameOverlayBackground,
+	"menuBackground":    theme.ColorNameMenuBackground,
+	"hover":             theme.ColorNameHover,
+	"focus":             theme.ColorNameFocus,
+	"selection":         theme.ColorNameSelection,
+	"separator":         theme.ColorNameSeparator,
+}
+
+// ThemeColorKeys lists the color keys a custom theme accepts
+func ThemeColorKeys() []string {
+	keys := make([]string, 0, len(themeColorNames))
+	for key := range themeColorNames {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appTheme wraps a base theme, optionally pinning the light/dark variant and
+// replacing individual colors. Fonts, icons and sizes come from the base.
+type appTheme struct {
+	base       fyne.Theme
+	variant    fyne.ThemeVariant
+	fixVariant bool
+	colors     map[fyne.ThemeColorName]color.Color
+}
+
+// Color returns an overridden color, or the base theme's in the pinned variant
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if c, ok := t.colors[name]; ok {
+		return c
+	}
+	if t.fixVariant {
+		variant = t.variant
+	}
+	return t.base.Color(name, variant)
+}
+
+// Font returns the base theme's font
+func (t *appTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return t.base.Font(style)
+}
+
+// Icon returns the base theme's icon
+func (t *appTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return t.base.Icon(name)
+}
+
+// Size returns the base theme's size
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	return t.base.Size(name)
+}
+
+// NewAppTheme builds the named theme. Custom themes take colors keyed as in
+// ThemeColorKeys and are light or dark depending on their background.
+func NewAppTheme(name string, colors map[string]string) (fyne.Theme, error) {
+	switch name {
+	case "", ThemeSystem:
+		return theme.DefaultTheme(), nil
+	case ThemeLight:
+		return &appTheme{base: theme.DefaultTheme(), variant: theme.VariantLight, fixVariant: true}, nil
+	case ThemeDark:
+		return &appTheme{base: theme.DefaultTheme(), variant: theme.VariantDark, fixVariant: true}, nil
+	case ThemeCustom:
+		t := &appTheme{base: theme.DefaultTheme(), colors: make(map[fyne.ThemeColorName]color.Color)}
+		for key, value := range colors {
+			colorName, ok := themeColorNames[key]
+			if !ok {
+				return nil, fmt.Errorf("unknown theme color '%s'", key)
+			}
+			c, err := character.ParseHexColor(value)
+			if err != nil {
+				return nil, fmt.Errorf("theme color %s: %w", key, err)
+			}
+			t.colors[colorName] = c
+		}
+		if background, ok := t.colors[theme.ColorNameBackground]; ok {
+			t.fixVariant = true
+			t.variant = theme.VariantLight
+			if isDarkColor(background) {
+				t.variant = theme.VariantDark
+			}
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown theme '%s'", name)
+}
+
+// isDarkColor reports whether c is closer to black than white
+func isDarkColor(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	luminance := (299*r + 587*g + 114*b) / 1000
+	return luminance < 0x8000
+}
+
+// applyTheme switches the whole app to the theme in the settings. Invalid
+// themes keep the current one. Alpha-blended windows keep a transparent
+// background.
+func (dw *DesktopWindow) applyTheme(name string, colors map[string]string) {
+	th, err := NewAppTheme(name, colors)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller": getCaller(),
+			"theme":  name,
+			"error":  err.Error(),
+		}).Warn("Ignoring invalid theme in settings")
+		return
+	}
+	if dw.alphaTransparency() {
+		// Keep the window see-through under the new theme
+		th = &transparentBackgroundTheme{Theme: th}
+	}
+	if app := fyne.CurrentApp(); app != nil {
+		app.Settings().SetTheme(th)
+	}
+}
+
+// bubbleTheme styles the text inside a speech bubble. Everything it does not
+// override follows the current app theme.
+type bubbleTheme struct {
+	textColor color.Color // nil keeps the theme's foreground
+	textSize  float32     // 0 keeps the theme's text size
+}
+
+// current returns the app theme the bubble theme builds on
+func (t *bubbleTheme) current() fyne.Theme {
+	if app := fyne.CurrentApp(); app != nil && app.Settings().Theme() != nil {
+		return app.Settings().Theme()
+	}
+	return theme.DefaultTheme()
+}
+
+// Color returns the bubble's text color for the foreground
+func (t *bubbleTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if name == theme.ColorNameForeground && t.textColor != nil {
+		return t.textColor
+	}
+	return t.current().Color(name, variant)
+}
+
+// Font returns the app theme's font
+func (t *bubbleTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return t.current().Font(style)
+}
+
+// Icon returns the app theme's icon
+func (t *bubbleTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return t.current().Icon(name)
+}
+
+// Size returns the bubble's text size for text
+func (t *bubbleTheme) Size(name fyne.ThemeSizeName) float32 {
+	if name == theme.SizeNameText && t.textSize > 0 {
+		return t.textSize
+	}
+	return t.current().Size(name)
+}
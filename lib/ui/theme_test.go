@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+func TestNewAppTheme(t *testing.T) {
+	dark, err := NewAppTheme(ThemeDark, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isDarkColor(dark.Color(theme.ColorNameBackground, theme.VariantLight)) {
+		t.Error("Expected the dark theme to ignore the desktop's light variant")
+	}
+
+	custom, err := NewAppTheme(ThemeCustom, map[string]string{"background": "#101010", "primary": "#ff8800"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := custom.Color(theme.ColorNamePrimary, theme.VariantLight); got != (color.RGBA{R: 0xff, G: 0x88, A: 0xff}) {
+		t.Errorf("Expected the custom primary color, got %v", got)
+	}
+	if isDarkColor(custom.Color(theme.ColorNameForeground, theme.VariantLight)) {
+		t.Error("Expected a dark custom background to use light text")
+	}
+
+	if _, err := NewAppTheme(ThemeCustom, map[string]string{"sparkle": "#ffffff"}); err == nil {
+		t.Error("Expected an unknown color key to be rejected")
+	}
+	if _, err := NewAppTheme(ThemeCustom, map[string]string{"primary": "orange"}); err == nil {
+		t.Error("Expected an invalid color to be rejected")
+	}
+	if _, err := NewAppTheme("neon", nil); err == nil {
+		t.Error("Expected an unknown theme to be rejected")
+	}
+}
+
+func TestDialogBubbleSetStyle(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	bubble := NewDialogBubble()
+	bubble.ShowWithText("Hello there, this is a fairly long line of dialog")
+	defaultWidth := bubble.content.Size().Width
+
+	bubble.SetStyle(&character.BubbleStyle{
+		Background:   "#202040",
+		Border:       "#8888ff",
+		FontSize:     28,
+		CornerRadius: 12,
+		BorderWidth:  3,
+		Tail:         character.BubbleTailRight,
+	})
+
+	if bubble.background.FillColor != (color.RGBA{R: 0x20, G: 0x20, B: 0x40, A: 0xff}) || bubble.background.StrokeWidth != 3 {
+		t.Errorf("Expected the card colors and border, got %v / %v", bubble.background.FillColor, bubble.background.StrokeWidth)
+	}
+	if bubble.background.CornerRadius != 12 {
+		t.Errorf("Expected corner radius 12, got %v", bubble.background.CornerRadius)
+	}
+	if isDarkColor(bubble.textTheme.Color(theme.ColorNameForeground, theme.VariantLight)) {
+		t.Error("Expected light text on a dark bubble when the card sets no text color")
+	}
+	if bubble.textTheme.Size(theme.SizeNameText) != 28 {
+		t.Errorf("Expected text size 28, got %v", bubble.textTheme.Size(theme.SizeNameText))
+	}
+	if bubble.content.Size().Width <= defaultWidth {
+		t.Error("Expected larger text to widen the bubble")
+	}
+	if !bubble.tail.Visible() || bubble.tail.Position().X < bubble.background.Size().Width/2 {
+		t.Errorf("Expected the tail on the right, got %v", bubble.tail.Position())
+	}
+
+	bubble.SetStyle(nil)
+	if bubble.tail.Visible() || bubble.background.FillColor != defaultBubbleBackground {
+		t.Error("Expected nil to restore the default bubble")
+	}
+}
+
+func TestApplySettingsTheme(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dw.applySettings(config.Settings{Theme: ThemeDark})
+	if !isDarkColor(app.Settings().Theme().Color(theme.ColorNameBackground, theme.VariantLight)) {
+		t.Error("Expected the dark theme to be applied")
+	}
+
+	// An invalid custom theme keeps the current one
+	dw.applySettings(config.Settings{Theme: ThemeCustom, ThemeColors: map[string]string{"primary": "nope"}})
+	if !isDarkColor(app.Settings().Theme().Color(theme.ColorNameBackground, theme.VariantLight)) {
+		t.Error("Expected an invalid theme to be ignored")
+	}
+}
+
+func TestApplyThemeKeepsAlphaBackground(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dw.transparency = &fakeTransparentWindow{mode: native.TransparencyAlpha}
+	dw.applySettings(config.Settings{Theme: ThemeDark})
+	current := app.Settings().Theme()
+	if current.Color(theme.ColorNameBackground, theme.VariantDark) != color.Transparent {
+		t.Error("Alpha-blended windows should keep a transparent background")
+	}
+	if !isDarkColor(current.Color(theme.ColorNameInputBackground, theme.VariantLight)) {
+		t.Error("Expected the dark theme to be applied underneath")
+	}
+
+	// Shaped windows clip instead of blending, so the theme is used as is
+	dw.transparency = &fakeTransparentWindow{mode: native.TransparencyShaped}
+	dw.applyTheme(ThemeDark, nil)
+	if app.Settings().Theme().Color(theme.ColorNameBackground, theme.VariantDark) == color.Transparent {
+		t.Error("Shaped windows should use the theme background")
+	}
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"log"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+
+	"github.com/opd-ai/desktop-companion/lib/ui/gestures"
+)
+
+// handleDoubleTap plays with the character in game mode and otherwise
+// behaves like a regular click
+func (dw *DesktopWindow) handleDoubleTap() {
+	if !dw.gameMode || dw.character.GetGameState() == nil {
+		dw.handleClick()
+		return
+	}
+
+	response := dw.character.HandleGameInteraction("play")
+	if dw.debug {
+		log.Printf("Character double-tapped, play response: %q", response)
+	}
+	if response != "" {
+		dw.showDialog(response)
+	}
+}
+
+// handlePinch scales the character by a relative pinch factor
+func (dw *DesktopWindow) handlePinch(scale float32) {
+	size := int(math.Round(float64(float32(dw.character.GetSize()) * scale)))
+	dw.resizeCharacter(size)
+}
+
+// handleScroll resizes the character on Ctrl+scroll. Plain scrolling is
+// ignored so the wheel doesn't resize by accident.
+func (dw *DesktopWindow) handleScroll(event *fyne.ScrollEvent) {
+	if !dw.ctrlHeld.Load() || event.Scrolled.DY == 0 {
+		return
+	}
+	dw.handlePinch(gestures.ScrollToPinchScale(event.Scrolled.DY))
+}
+
+// setupModifierTracking follows the Ctrl key, since Fyne scroll events
+// don't report modifiers
+func (dw *DesktopWindow) setupModifierTracking(canvas fyne.Canvas) {
+	deskCanvas, ok := canvas.(desktop.Canvas)
+	if !ok {
+		return
+	}
+
+	deskCanvas.SetOnKeyDown(func(key *fyne.KeyEvent) {
+		if key.Name == desktop.KeyControlLeft || key.Name == desktop.KeyControlRight {
+			dw.ctrlHeld.Store(true)
+		}
+	})
+	deskCanvas.SetOnKeyUp(func(key *fyne.KeyEvent) {
+		if key.Name == desktop.KeyControlLeft || key.Name == desktop.KeyControlRight {
+			dw.ctrlHeld.Store(false)
+		}
+	})
+}
+
+// resizeCharacter changes the character size and everything sized after it:
+// the window, the sprite and the interactive regions. The size is clamped to
+// the card's limits and remembered for the next run.
+func (dw *DesktopWindow) resizeCharacter(size int) {
+	if size == dw.character.GetSize() {
+		return
+	}
+	previous := dw.character.GetSize()
+	if size = dw.character.Resize(size); size == previous {
+		return
+	}
+
+	newSize := fyne.NewSize(float32(size), float32(size))
+
+	dw.renderer.SetSize(size)
+	if dw.clickable != nil {
+		dw.clickable.SetSize(newSize)
+	}
+	if dw.draggable != nil {
+		dw.draggable.Resize(newSize)
+	}
+	if dw.saveStatusIndicator != nil {
+		dw.saveStatusIndicator.Move(fyne.NewPos(float32(size-20), 4))
+	}
+	if dw.metricsLabel != nil {
+		dw.metricsLabel.Move(fyne.NewPos(0, float32(size-36)))
+	}
+	if dw.focusLabel != nil {
+		dw.focusLabel.Move(fyne.NewPos(float32(size)/2-40, 0))
+	}
+	if dw.particles != nil {
+		dw.particles.SetSize(size)
+	}
+	dw.window.Resize(newSize)
+
+	if dw.debug {
+		log.Printf("Character resized to %dpx", size)
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/platform"
+)
+
+func TestPinchResizesCharacter(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	start := char.GetSize()
+	dw.handlePinch(1.5)
+
+	want := min(character.DefaultMaxSize, int(float64(start)*1.5+0.5))
+	if char.GetSize() != want {
+		t.Errorf("Expected size %d after pinch, got %d", want, char.GetSize())
+	}
+	if dw.renderer.GetSize() != want {
+		t.Errorf("Renderer should follow the character size, got %d", dw.renderer.GetSize())
+	}
+
+	// Sizes stay within the card limits
+	dw.resizeCharacter(10)
+	if char.GetSize() != character.DefaultMinSize {
+		t.Errorf("Expected size clamped to %d, got %d", character.DefaultMinSize, char.GetSize())
+	}
+	dw.resizeCharacter(5000)
+	if char.GetSize() != character.DefaultMaxSize {
+		t.Errorf("Expected size clamped to %d, got %d", character.DefaultMaxSize, char.GetSize())
+	}
+}
+
+func TestDraggableCharacterTouchGestures(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	dc := NewDraggableCharacter(dw, char, false)
+	dc.touch = nil
+	dc.enableTouchGestures(&platform.PlatformInfo{OS: "linux", FormFactor: "desktop", InputMethods: []string{"mouse"}})
+	if dc.touch != nil {
+		t.Error("Mouse-only platforms should not use gesture recognition")
+	}
+
+	dc.enableTouchGestures(&platform.PlatformInfo{OS: "android", FormFactor: "mobile", InputMethods: []string{"touch"}})
+	if dc.touch == nil {
+		t.Fatal("Touch platforms should use gesture recognition")
+	}
+
+	start := char.GetSize()
+	dc.touch.HandlePinch(0.5)
+	if want := max(character.DefaultMinSize, start/2); char.GetSize() != want {
+		t.Errorf("Expected pinch to shrink character to %d, got %d", want, char.GetSize())
+	}
+}
+
+func TestCtrlScrollResizesCharacter(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+	start := char.GetSize()
+
+	scroll := &fyne.ScrollEvent{Scrolled: fyne.Delta{DY: 50}}
+	dw.handleScroll(scroll)
+	if char.GetSize() != start {
+		t.Errorf("Scrolling without Ctrl should not resize, got %d", char.GetSize())
+	}
+
+	dw.ctrlHeld.Store(true)
+	dw.handleScroll(scroll)
+	if char.GetSize() <= start {
+		t.Errorf("Expected Ctrl+scroll up to grow the character from %d, got %d", start, char.GetSize())
+	}
+}
@@ -0,0 +1,289 @@
+package ui
+
+import (
+	"errors"
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver"
+	"fyne.io/fyne/v2/theme"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+// withNativeWindow runs fn with the platform handle of the Fyne window.
+// fn is not called when the driver exposes no usable handle.
+func withNativeWindow(window fyne.Window, fn func(native.NativeWindow)) {
+	nw, ok := window.(driver.NativeWindow)
+	if !ok {
+		return
+	}
+
+	nw.RunNative(func(context any) {
+		switch ctx := context.(type) {
+		case driver.X11WindowContext:
+			fn(native.NativeWindow{Kind: native.NativeWindowX11, Handle: ctx.WindowHandle})
+		case driver.WindowsWindowContext:
+			fn(native.NativeWindow{Kind: native.NativeWindowWin32, Handle: ctx.HWND})
+		case driver.MacWindowContext:
+			fn(native.NativeWindow{Kind: native.NativeWindowCocoa, Handle: ctx.NSWindow})
+		case driver.WaylandWindowContext:
+			fn(native.NativeWindow{Kind: native.NativeWindowWayland, Handle: ctx.WaylandSurface})
+		}
+	})
+}
+
+// enablePerPixelTransparency switches the window to real per-pixel
+// transparency where the platform supports it. The native handle only
+// exists once the window is shown. Falls back to the plain window otherwise.
+func (dw *DesktopWindow) enablePerPixelTransparency() {
+	withNativeWindow(dw.window, func(win native.NativeWindow) {
+		// Wayland windows, XWayland ones included, can't stay above
+		// everything, so a layer-shell overlay beats any window transparency
+		if (win.Kind == native.NativeWindowWayland || win.Kind == native.NativeWindowX11) && dw.openLayerOverlay() {
+			return
+		}
+
+		transparent, err := native.EnableTransparency(win)
+		if err != nil {
+			level := logrus.WarnLevel
+			if errors.Is(err, native.ErrTransparencyUnsupported) {
+				level = logrus.InfoLevel
+			}
+			logrus.WithError(err).Log(level, "Per-pixel transparency unavailable, using rectangular window")
+			return
+		}
+
+		dw.shapeMu.Lock()
+		dw.transparency = transparent
+		dw.shapeMu.Unlock()
+		logrus.WithField("mode", transparent.Mode().String()).Info("Per-pixel transparency enabled")
+	})
+
+	if dw.alphaTransparency() {
+		// The compositor can only see through pixels Fyne leaves transparent
+		app := fyne.CurrentApp()
+		app.Settings().SetTheme(&transparentBackgroundTheme{Theme: app.Settings().Theme()})
+	}
+
+	dw.updateWindowShape()
+}
+
+// alphaTransparency reports whether the window is blended with per-pixel
+// alpha, so Fyne must leave the background transparent
+func (dw *DesktopWindow) alphaTransparency() bool {
+	dw.shapeMu.Lock()
+	defer dw.shapeMu.Unlock()
+
+	if dw.transparency == nil {
+		return false
+	}
+	mode := dw.transparency.Mode()
+	return mode == native.TransparencyAlpha || mode == native.TransparencyLayered
+}
+
+// presentLayeredFrame hands the rendered window to layered windows, which
+// can't show what Fyne draws directly. Only changed frames are captured,
+// but any open overlay may change without the sprite doing so.
+func (dw *DesktopWindow) presentLayeredFrame(hasChanges bool) {
+	dw.shapeMu.Lock()
+	defer dw.shapeMu.Unlock()
+
+	if dw.transparency == nil || dw.transparency.Mode() != native.TransparencyLayered {
+		return
+	}
+	if !hasChanges && !dw.overlayVisible() {
+		return
+	}
+
+	if err := dw.transparency.Present(dw.window.Canvas().Capture()); err != nil {
+		logrus.WithError(err).Debug("Failed to present layered window frame")
+	}
+}
+
+// updateWindowShape clips shaped windows to the current sprite frame.
+// While any dialog, menu or overlay is open the full window is shown so
+// nothing drawn outside the sprite gets cut off.
+func (dw *DesktopWindow) updateWindowShape() {
+	dw.shapeMu.Lock()
+	defer dw.shapeMu.Unlock()
+
+	if dw.transparency == nil || dw.transparency.Mode() != native.TransparencyShaped {
+		return
+	}
+
+	if dw.overlayVisible() {
+		if !dw.shapeCleared {
+			if err := dw.transparency.SetShape(nil); err != nil {
+				logrus.WithError(err).Debug("Failed to clear window shape")
+			}
+			dw.shapeCleared = true
+			dw.lastShapeFrame = nil
+		}
+		return
+	}
+
+	frame := dw.renderer.VisibleFrame()
+	if frame == nil || (frame == dw.lastShapeFrame && !dw.shapeCleared) {
+		return
+	}
+
+	size := int(float32(dw.character.GetSize()) * dw.window.Canvas().Scale())
+	if err := dw.transparency.SetShape(native.OpaqueRects(frame, size, size)); err != nil {
+		logrus.WithError(err).Debug("Failed to update window shape")
+		return
+	}
+	dw.lastShapeFrame = frame
+	dw.shapeCleared = false
+}
+
+// Click-through modes for the clickThrough setting
+const (
+	ClickThroughSprite = "sprite" // Only the sprite's visible pixels take clicks
+	ClickThroughBounds = "bounds" // The rectangle around the sprite takes clicks
+	ClickThroughOff    = "off"    // The whole window takes clicks
+)
+
+// clickThroughOptions are the click-through modes offered in the settings window
+var clickThroughOptions = []struct {
+	label string
+	name  string
+}{
+	{"Only the character", ClickThroughSprite},
+	{"Box around the character", ClickThroughBounds},
+	{"Whole window", ClickThroughOff},
+}
+
+// inputShapeState is what an input shape is computed from, so it is only
+// recomputed when one of them changes
+type inputShapeState struct {
+	frame       image.Image
+	size        int
+	mode        string
+	overlayOpen bool
+}
+
+// clickThroughMode returns the click-through setting
+func (dw *DesktopWindow) clickThroughMode() string {
+	dw.settingsMu.Lock()
+	defer dw.settingsMu.Unlock()
+	return dw.settings.ClickThrough
+}
+
+// clickRegion returns the part of a size x size window showing frame that
+// takes clicks in the given click-through mode. nil means everywhere.
+func clickRegion(frame image.Image, size int, mode string) []image.Rectangle {
+	if mode == ClickThroughOff {
+		return nil
+	}
+
+	rects := native.OpaqueRects(frame, size, size)
+	if len(rects) == 0 {
+		return []image.Rectangle{} // A blank frame takes no clicks
+	}
+	if mode != ClickThroughBounds {
+		return rects
+	}
+
+	bounds := rects[0]
+	for _, r := range rects[1:] {
+		bounds = bounds.Union(r)
+	}
+	return []image.Rectangle{bounds}
+}
+
+// updateInputShape lets clicks outside the sprite through to the windows
+// underneath. While any dialog, menu or overlay is open the whole window
+// takes clicks so they stay usable.
+func (dw *DesktopWindow) updateInputShape() {
+	dw.shapeMu.Lock()
+	defer dw.shapeMu.Unlock()
+
+	if dw.transparency == nil || dw.noClickThrough {
+		return
+	}
+
+	state := inputShapeState{
+		frame:       dw.renderer.VisibleFrame(),
+		size:        int(float32(dw.character.GetSize()) * dw.window.Canvas().Scale()),
+		mode:        dw.clickThroughMode(),
+		overlayOpen: dw.overlayVisible(),
+	}
+	if state == dw.lastInputShape {
+		return
+	}
+
+	var rects []image.Rectangle
+	if !state.overlayOpen && state.frame != nil {
+		rects = clickRegion(state.frame, state.size, state.mode)
+	}
+	if err := dw.transparency.SetInputShape(rects); err != nil {
+		if errors.Is(err, native.ErrClickThroughUnsupported) {
+			dw.noClickThrough = true
+			logrus.WithError(err).Info("Click-through unavailable, the whole window takes clicks")
+			return
+		}
+		logrus.WithError(err).Debug("Failed to update window input shape")
+		return
+	}
+	dw.lastInputShape = state
+}
+
+// overlayVisible reports whether anything besides the sprite is on screen
+func (dw *DesktopWindow) overlayVisible() bool {
+	content, ok := dw.window.Content().(*fyne.Container)
+	if !ok {
+		return true
+	}
+
+	for _, obj := range content.Objects {
+		switch obj.(type) {
+		case *CharacterRenderer, *DraggableCharacter, *SaveStatusIndicator, *canvas.Rectangle:
+			continue
+		}
+
+		if v, ok := obj.(interface{ IsVisible() bool }); ok {
+			if v.IsVisible() {
+				return true
+			}
+			continue
+		}
+		if obj.Visible() {
+			return true
+		}
+	}
+	return false
+}
+
+// closeTransparency restores the native window before it is destroyed
+func (dw *DesktopWindow) closeTransparency() {
+	dw.closeLayerOverlay()
+
+	dw.shapeMu.Lock()
+	defer dw.shapeMu.Unlock()
+
+	if dw.transparency == nil {
+		return
+	}
+	if err := dw.transparency.Close(); err != nil {
+		logrus.WithError(err).Debug("Failed to release window transparency")
+	}
+	dw.transparency = nil
+}
+
+// transparentBackgroundTheme keeps the current theme but clears the window
+// background so alpha-composited windows show the desktop behind the sprite
+type transparentBackgroundTheme struct {
+	fyne.Theme
+}
+
+// Color returns a transparent background and defers everything else
+func (t *transparentBackgroundTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if name == theme.ColorNameBackground {
+		return color.Transparent
+	}
+	return t.Theme.Color(name, variant)
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+)
+
+// fakeTransparentWindow records shape updates and presented frames
+type fakeTransparentWindow struct {
+	mode        native.TransparencyMode
+	shapes      [][]image.Rectangle
+	inputShapes [][]image.Rectangle
+	frames      []image.Image
+}
+
+func (f *fakeTransparentWindow) Mode() native.TransparencyMode { return f.mode }
+func (f *fakeTransparentWindow) Close() error                  { return nil }
+
+func (f *fakeTransparentWindow) Present(frame image.Image) error {
+	f.frames = append(f.frames, frame)
+	return nil
+}
+
+func (f *fakeTransparentWindow) SetShape(rects []image.Rectangle) error {
+	f.shapes = append(f.shapes, rects)
+	return nil
+}
+
+func (f *fakeTransparentWindow) SetInputShape(rects []image.Rectangle) error {
+	f.inputShapes = append(f.inputShapes, rects)
+	return nil
+}
+
+func TestUpdateWindowShapeFollowsOverlays(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	fake := &fakeTransparentWindow{mode: native.TransparencyShaped}
+	dw.transparency = fake
+
+	dw.updateWindowShape()
+	if len(fake.shapes) != 1 || fake.shapes[0] == nil {
+		t.Fatalf("Expected sprite shape to be applied, got %v", fake.shapes)
+	}
+
+	// Same frame again should not reshape
+	dw.updateWindowShape()
+	if len(fake.shapes) != 1 {
+		t.Errorf("Expected unchanged frame to be skipped, got %d updates", len(fake.shapes))
+	}
+
+	// An open dialog needs the full window
+	dw.dialog.ShowWithText("hello")
+	dw.updateWindowShape()
+	if len(fake.shapes) != 2 || fake.shapes[1] != nil {
+		t.Fatalf("Expected shape to be cleared while dialog is open, got %v", fake.shapes)
+	}
+
+	dw.dialog.Hide()
+	dw.updateWindowShape()
+	if len(fake.shapes) != 3 || fake.shapes[2] == nil {
+		t.Errorf("Expected sprite shape to be restored, got %v", fake.shapes)
+	}
+}
+
+func TestLayeredWindowReceivesFrames(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	fake := &fakeTransparentWindow{mode: native.TransparencyLayered}
+	dw.transparency = fake
+	if !dw.alphaTransparency() {
+		t.Error("Expected layered windows to count as alpha transparency")
+	}
+
+	dw.updateWindowShape()
+	if len(fake.shapes) != 0 {
+		t.Errorf("Expected no shape for a layered window, got %v", fake.shapes)
+	}
+
+	dw.presentLayeredFrame(false)
+	if len(fake.frames) != 0 {
+		t.Error("Expected an unchanged frame not to be captured")
+	}
+	dw.presentLayeredFrame(true)
+	if len(fake.frames) != 1 || fake.frames[0] == nil {
+		t.Fatalf("Expected the changed frame to be presented, got %v", fake.frames)
+	}
+
+	// Open dialogs are captured even while the sprite holds still
+	dw.dialog.ShowWithText("hello")
+	dw.presentLayeredFrame(false)
+	if len(fake.frames) != 2 {
+		t.Errorf("Expected the dialog to be presented, got %d frames", len(fake.frames))
+	}
+}
+
+func TestInputShapeFollowsClickThroughSetting(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithoutGame(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	fake := &fakeTransparentWindow{mode: native.TransparencyAlpha}
+	dw.transparency = fake
+
+	dw.updateInputShape()
+	if len(fake.inputShapes) != 1 || fake.inputShapes[0] == nil {
+		t.Fatalf("Expected clicks limited to the sprite, got %v", fake.inputShapes)
+	}
+	dw.updateInputShape()
+	if len(fake.inputShapes) != 1 {
+		t.Errorf("Expected an unchanged frame to be skipped, got %d updates", len(fake.inputShapes))
+	}
+
+	// An open dialog takes clicks everywhere
+	dw.dialog.ShowWithText("hello")
+	dw.updateInputShape()
+	if len(fake.inputShapes) != 2 || fake.inputShapes[1] != nil {
+		t.Fatalf("Expected the input shape cleared while the dialog is open, got %v", fake.inputShapes)
+	}
+	dw.dialog.Hide()
+
+	dw.applySettings(config.Settings{ClickThrough: ClickThroughBounds})
+	dw.updateInputShape()
+	if len(fake.inputShapes) != 3 || len(fake.inputShapes[2]) != 1 {
+		t.Errorf("Expected one bounding box, got %v", fake.inputShapes)
+	}
+
+	dw.applySettings(config.Settings{ClickThrough: ClickThroughOff})
+	dw.updateInputShape()
+	if len(fake.inputShapes) != 4 || fake.inputShapes[3] != nil {
+		t.Errorf("Expected the whole window clickable, got %v", fake.inputShapes)
+	}
+}
+
+func TestClickRegionBounds(t *testing.T) {
+	// Two opaque pixels in opposite corners of a 4x4 frame
+	frame := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	frame.SetNRGBA(0, 0, color.NRGBA{A: 255})
+	frame.SetNRGBA(3, 3, color.NRGBA{A: 255})
+
+	if rects := clickRegion(frame, 4, ClickThroughSprite); len(rects) != 2 {
+		t.Errorf("Expected both pixels, got %v", rects)
+	}
+	if rects := clickRegion(frame, 4, ClickThroughBounds); len(rects) != 1 || rects[0] != image.Rect(0, 0, 4, 4) {
+		t.Errorf("Expected the box around both pixels, got %v", rects)
+	}
+	if rects := clickRegion(frame, 4, ClickThroughOff); rects != nil {
+		t.Errorf("Expected the whole window, got %v", rects)
+	}
+	if rects := clickRegion(image.NewNRGBA(image.Rect(0, 0, 4, 4)), 4, ClickThroughBounds); rects == nil || len(rects) != 0 {
+		t.Errorf("Expected a transparent frame to take no clicks, got %v", rects)
+	}
+}
+
+func TestTransparentBackgroundTheme(t *testing.T) {
+	th := &transparentBackgroundTheme{Theme: theme.DefaultTheme()}
+
+	if th.Color(theme.ColorNameBackground, theme.VariantDark) != color.Transparent {
+		t.Error("Background should be transparent")
+	}
+	if th.Color(theme.ColorNameForeground, theme.VariantDark) == color.Transparent {
+		t.Error("Foreground should come from the wrapped theme")
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+// tray.go adds a system tray menu for choices that belong to whoever is at
+// the computer rather than to the character, starting with the user profile.
+// Platforms without a tray, like Android, simply don't get the menu.
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/config"
+)
+
+// SetupProfileTray shows the system tray menu with a profile switcher.
+// switchTo is called with the chosen profile, new or existing, and is
+// expected to restart the companion as that profile.
+func (dw *DesktopWindow) SetupProfileTray(current string, profiles []string, switchTo func(name string)) {
+	desk, ok := fyne.CurrentApp().(desktop.App)
+	if !ok {
+		return
+	}
+	desk.SetSystemTrayMenu(newProfileTrayMenu(current, profiles, switchTo, func() {
+		dw.showNewProfile(switchTo)
+	}))
+}
+
+// newProfileTrayMenu builds the tray menu: a "Profile" submenu with the
+// current profile ticked, and an entry to create a new one
+func newProfileTrayMenu(current string, profiles []string, switchTo func(name string), newProfile func()) *fyne.Menu {
+	items := make([]*fyne.MenuItem, 0, len(profiles)+2)
+	for _, name := range profiles {
+		item := fyne.NewMenuItem(name, func() {
+			if name != current {
+				switchTo(name)
+			}
+		})
+		item.Checked = name == current
+		items = append(items, item)
+	}
+	items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("New Profile…", newProfile))
+
+	profileItem := fyne.NewMenuItem("Profile: "+current, nil)
+	profileItem.ChildMenu = fyne.NewMenu("", items...)
+	return fyne.NewMenu("Desktop Companion", profileItem)
+}
+
+// showNewProfile asks for a profile name and switches to it
+func (dw *DesktopWindow) showNewProfile(switchTo func(name string)) {
+	window := fyne.CurrentApp().NewWindow("New Profile")
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. alex")
+	statusLabel := widget.NewLabel("")
+
+	create := func() {
+		if err := config.ValidateProfileName(nameEntry.Text); err != nil {
+			statusLabel.SetText(err.Error())
+			return
+		}
+		window.Close()
+		switchTo(nameEntry.Text)
+	}
+	nameEntry.OnSubmitted = func(string) { create() }
+
+	window.SetContent(container.NewVBox(
+		widget.NewLabel("Each profile keeps its own relationship, saves and chats."),
+		container.NewBorder(nil, nil, widget.NewLabel("Name:"), nil, nameEntry),
+		widget.NewButton("Create and switch", create),
+		statusLabel,
+	))
+	window.Resize(fyne.NewSize(320, 0))
+	window.Show()
+}
+
+// SetChatExportDir makes "Export Chat" write to dir, e.g. the profile's chat
+// directory. Empty means the user's home directory.
+func (dw *DesktopWindow) SetChatExportDir(dir string) {
+	if dw.chatbotInterface != nil {
+		dw.chatbotInterface.SetExportDir(dir)
+	}
+}
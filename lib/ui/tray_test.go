@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewProfileTrayMenu(t *testing.T) {
+	var switched []string
+	created := false
+	menu := newProfileTrayMenu("kid", []string{"default", "kid"}, func(name string) {
+		switched = append(switched, name)
+	}, func() { created = true })
+
+	if len(menu.Items) != 1 || menu.Items[0].Label != "Profile: kid" {
+		t.Fatalf("Expected a single profile entry, got %+v", menu.Items)
+	}
+	items := menu.Items[0].ChildMenu.Items
+	if len(items) != 4 {
+		t.Fatalf("Expected two profiles, a separator and New Profile, got %d items", len(items))
+	}
+	if items[0].Checked || !items[1].Checked {
+		t.Error("Only the current profile should be ticked")
+	}
+
+	items[1].Action()
+	if len(switched) != 0 {
+		t.Errorf("Choosing the current profile should not restart, got %v", switched)
+	}
+	items[0].Action()
+	if len(switched) != 1 || switched[0] != "default" {
+		t.Errorf("Expected a switch to default, got %v", switched)
+	}
+
+	if !items[2].IsSeparator {
+		t.Error("Expected a separator before New Profile")
+	}
+	items[3].Action()
+	if !created {
+		t.Error("New Profile should ask for a name")
+	}
+}
+
+func TestChatExportDir(t *testing.T) {
+	chatbot := &ChatbotInterface{
+		conversationLog: []ChatMessage{{IsUser: true, Text: "hello"}},
+	}
+	dir := filepath.Join(t.TempDir(), "profiles", "kid", "chat")
+	chatbot.SetExportDir(dir)
+
+	if err := chatbot.ExportConversation(); err != nil {
+		t.Fatalf("ExportConversation failed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one export in the profile's chat directory, got %v, %v", entries, err)
+	}
+}
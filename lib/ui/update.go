@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+)
+
+// updateState is a newer release the character has announced
+type updateState struct {
+	mu         sync.Mutex
+	version    string
+	install    func() error // nil when the release has no build for this platform
+	installing bool
+	installed  bool
+}
+
+// OfferUpdate has the character announce a newer version. When install is
+// set the context menu offers to download, verify and install it; nothing
+// is downloaded until the user asks. Without it the character points to the
+// release page instead.
+func (dw *DesktopWindow) OfferUpdate(version, pageURL string, install func() error) {
+	dw.update.mu.Lock()
+	if dw.update.version == version {
+		dw.update.mu.Unlock()
+		return
+	}
+	dw.update.version, dw.update.install = version, install
+	dw.update.installing, dw.update.installed = false, false
+	dw.update.mu.Unlock()
+
+	dw.showNotice(updateAnnouncement(version, pageURL, install != nil))
+}
+
+// updateAnnouncement is what the character says about a new version
+func updateAnnouncement(version, pageURL string, installable bool) string {
+	if installable {
+		return fmt.Sprintf("There's a new version of me! Version %s is out. Right-click me and pick \"Update\" when you're ready.", version)
+	}
+	return fmt.Sprintf("There's a new version of me! Version %s is out: %s", version, pageURL)
+}
+
+// buildUpdateMenuItem offers installing an announced update
+func (dw *DesktopWindow) buildUpdateMenuItem() (ContextMenuItem, bool) {
+	dw.update.mu.Lock()
+	defer dw.update.mu.Unlock()
+
+	if dw.update.install == nil || dw.update.installed {
+		return ContextMenuItem{}, false
+	}
+	version := dw.update.version
+	return ContextMenuItem{
+		Text:     fmt.Sprintf("⬆️ Update to %s", version),
+		Callback: func() { dw.confirmUpdate(version) },
+	}, true
+}
+
+// confirmUpdate asks before downloading and installing the update
+func (dw *DesktopWindow) confirmUpdate(version string) {
+	message := fmt.Sprintf("Download version %s, check its checksum and install it? The new version starts the next time you open me.", version)
+	dw.battleInvitationDialog.ShowRequest("Update", message, func(accepted bool) {
+		if accepted {
+			go dw.installUpdate()
+		}
+	})
+}
+
+// installUpdate runs the announced update's install and reports the result
+func (dw *DesktopWindow) installUpdate() {
+	dw.update.mu.Lock()
+	if dw.update.installing || dw.update.installed || dw.update.install == nil {
+		dw.update.mu.Unlock()
+		return
+	}
+	dw.update.installing = true
+	version, install := dw.update.version, dw.update.install
+	dw.update.mu.Unlock()
+
+	dw.showDialog(fmt.Sprintf("Downloading version %s...", version))
+	err := install()
+
+	dw.update.mu.Lock()
+	dw.update.installing = false
+	dw.update.installed = err == nil
+	dw.update.mu.Unlock()
+
+	if err != nil {
+		dw.showNotice(fmt.Sprintf("Couldn't update to %s: %v", version, err))
+		return
+	}
+	dw.showNotice(fmt.Sprintf("Version %s is installed! Restart me to meet the new me.", version))
+}
@@ -0,0 +1,304 @@
+package ui
+
+// visit.go shows a networked peer's character on our desktop as a guest:
+// it brings its animations along and plays whatever state the peer streams,
+// with the peer's dialog in its own bubble. Guests can't be clicked or fed;
+// they only keep our character company until either side ends the visit.
+
+import (
+	"bytes"
+	"fmt"
+	"image/gif"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+// guestDialogDuration is how long a guest's dialog stays up
+const guestDialogDuration = 5 * time.Second
+
+// visitState tracks the guest windows open on our desktop
+type visitState struct {
+	mu     sync.Mutex
+	guests map[string]*guestWindow // Peer ID to their character's window
+}
+
+// guestWindow renders one visiting character from its streamed animations
+type guestWindow struct {
+	window    fyne.Window
+	animation *character.AnimationManager
+	sprite    *canvas.Image
+	bubble    *widget.Label
+	stop      chan struct{}
+	mu        sync.Mutex
+	closing   bool      // Closed by the visit ending rather than the user
+	saidAt    time.Time // When the bubble was last shown
+}
+
+// setupVisits sends our character's animations to peers who invite it and
+// opens a window for each character we invite
+func (dw *DesktopWindow) setupVisits(char *character.Character) {
+	visits := dw.networkOverlay.GetVisits()
+	if visits == nil {
+		return
+	}
+	visits.SetAssets(func() map[string][]byte { return encodeVisitAnimations(char) })
+	visits.SetEventHandler(dw.handleVisitEvent)
+}
+
+// encodeVisitAnimations returns the character's animations by state as GIF
+// data for a peer; animations that fail to encode are left behind
+func encodeVisitAnimations(char *character.Character) map[string][]byte {
+	animations := make(map[string][]byte)
+	for state := range char.GetCard().Animations {
+		anim, ok := char.GetStateAnimation(state)
+		if !ok {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := gif.EncodeAll(&buf, anim); err == nil {
+			animations[state] = buf.Bytes()
+		}
+	}
+	return animations
+}
+
+// handleVisitEvent opens, updates and closes guest windows and tells the
+// user about our own character's trips
+func (dw *DesktopWindow) handleVisitEvent(event network.VisitEvent) {
+	name := visitorName(event.Guest.Character, event.PeerID)
+	switch event.Kind {
+	case network.VisitGuestArrived:
+		if err := dw.openGuest(event.Guest); err != nil {
+			dw.showDialog(fmt.Sprintf("%s couldn't visit: %v", name, err))
+			_ = dw.networkOverlay.GetVisits().End(event.PeerID)
+			return
+		}
+		dw.showDialog(fmt.Sprintf("%s came to visit!", name))
+	case network.VisitGuestUpdated:
+		dw.updateGuest(event.PeerID, event.Guest.State, event.Dialog)
+	case network.VisitGuestLeft:
+		dw.closeGuest(event.PeerID)
+		dw.showDialog(fmt.Sprintf("%s went home.", name))
+	case network.VisitDeclined:
+		dw.showDialog(fmt.Sprintf("%s's character can't visit right now.", shortPeerID(event.PeerID)))
+	case network.VisitStarted:
+		dw.showDialog(fmt.Sprintf("I'm visiting %s's desktop!", shortPeerID(event.PeerID)))
+	case network.VisitEnded:
+		dw.showDialog("I'm back from my visit!")
+	}
+}
+
+// visitorName returns the guest's character name, or the peer ID without one
+func visitorName(characterName, peerID string) string {
+	if characterName != "" {
+		return characterName
+	}
+	return shortPeerID(peerID)
+}
+
+// openGuest decodes the guest's animations and shows it in its own window,
+// the same size as our character so the two look right side by side
+func (dw *DesktopWindow) openGuest(guest network.VisitGuest) error {
+	anim := character.NewAnimationManager()
+	for name, data := range guest.Animations {
+		decoded, err := network.DecodeVisitAnimation(data)
+		if err != nil {
+			continue // Skip what doesn't decode, as long as something does
+		}
+		_ = anim.LoadEmbeddedAnimation(name, decoded)
+	}
+	if len(anim.GetLoadedAnimations()) == 0 {
+		return fmt.Errorf("none of its animations could be read")
+	}
+	_ = anim.SetCurrentAnimation(guest.State) // Otherwise the first one loaded plays
+
+	size := float32(dw.character.GetSize())
+	g := &guestWindow{
+		window:    fyne.CurrentApp().NewWindow(fmt.Sprintf("%s (visiting)", visitorName(guest.Character, guest.PeerID))),
+		animation: anim,
+		sprite:    canvas.NewImageFromImage(anim.GetCurrentFrameImage()),
+		bubble:    widget.NewLabel(""),
+		stop:      make(chan struct{}),
+	}
+	g.sprite.FillMode = canvas.ImageFillContain
+	g.sprite.ScaleMode = canvas.ImageScalePixels
+	g.sprite.SetMinSize(fyne.NewSize(size, size))
+	g.bubble.Wrapping = fyne.TextWrapWord
+	g.bubble.Alignment = fyne.TextAlignCenter
+	g.bubble.Hide()
+
+	g.window.SetContent(container.NewBorder(g.bubble, nil, nil, nil, g.sprite))
+	g.window.Resize(fyne.NewSize(size, size))
+	g.window.SetOnClosed(func() {
+		close(g.stop)
+		dw.visits.mu.Lock()
+		if dw.visits.guests[guest.PeerID] == g {
+			delete(dw.visits.guests, guest.PeerID)
+		}
+		dw.visits.mu.Unlock()
+
+		g.mu.Lock()
+		closing := g.closing
+		g.mu.Unlock()
+		if !closing {
+			_ = dw.networkOverlay.GetVisits().End(guest.PeerID)
+		}
+	})
+
+	dw.visits.mu.Lock()
+	if dw.visits.guests == nil {
+		dw.visits.guests = make(map[string]*guestWindow)
+	}
+	dw.visits.guests[guest.PeerID] = g
+	dw.visits.mu.Unlock()
+
+	g.window.Show()
+	go g.play()
+	return nil
+}
+
+// play advances the guest's animation and hides stale dialog until the
+// window closes
+func (g *guestWindow) play() {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+			if g.animation.Update() {
+				g.sprite.Image = g.animation.GetCurrentFrameImage()
+				g.sprite.Refresh()
+			}
+			g.mu.Lock()
+			stale := time.Since(g.saidAt) >= guestDialogDuration
+			g.mu.Unlock()
+			if stale && g.bubble.Visible() {
+				g.bubble.Hide()
+			}
+		}
+	}
+}
+
+// updateGuest switches the guest's animation and shows what it said
+func (dw *DesktopWindow) updateGuest(peerID, state, dialog string) {
+	dw.visits.mu.Lock()
+	g := dw.visits.guests[peerID]
+	dw.visits.mu.Unlock()
+	if g == nil {
+		return
+	}
+
+	if state != g.animation.GetCurrentAnimationName() && g.animation.SetCurrentAnimation(state) == nil {
+		g.sprite.Image = g.animation.GetCurrentFrameImage()
+		g.sprite.Refresh()
+	}
+	if dialog != "" && !dw.isQuiet() {
+		g.mu.Lock()
+		g.saidAt = time.Now()
+		g.mu.Unlock()
+		g.bubble.SetText(dialog)
+		g.bubble.Show()
+	}
+}
+
+// closeGuest closes a guest's window after its visit ended
+func (dw *DesktopWindow) closeGuest(peerID string) {
+	dw.visits.mu.Lock()
+	g := dw.visits.guests[peerID]
+	delete(dw.visits.guests, peerID)
+	dw.visits.mu.Unlock()
+
+	if g != nil {
+		g.mu.Lock()
+		g.closing = true
+		g.mu.Unlock()
+		g.window.Close()
+	}
+}
+
+// buildVisitMenuItems offers inviting a peer's character over and, during
+// visits, ending them
+func (dw *DesktopWindow) buildVisitMenuItems() []ContextMenuItem {
+	visits := dw.networkOverlay.GetVisits()
+	if visits == nil {
+		return nil
+	}
+
+	items := []ContextMenuItem{{
+		Text:     "🏠 Invite a Visitor",
+		Callback: dw.inviteVisitor,
+	}}
+	if len(visits.Guests()) > 0 || len(visits.Visiting()) > 0 {
+		items = append(items, ContextMenuItem{
+			Text:     "👋 End Visits",
+			Callback: visits.EndAll,
+		})
+	}
+	return items
+}
+
+// inviteVisitor asks a connected peer's character over, letting the user
+// pick the peer when there are several
+func (dw *DesktopWindow) inviteVisitor() {
+	peers := dw.networkOverlay.GetNetworkManager().GetPeers()
+	if len(peers) == 0 {
+		dw.showDialog("No other players connected. Visits need a connected peer.")
+		return
+	}
+
+	invite := func(peer network.Peer) {
+		if err := dw.networkOverlay.GetVisits().Invite(peer.ID); err != nil {
+			dw.showDialog(fmt.Sprintf("Failed to invite a visitor: %v", err))
+			return
+		}
+		dw.showDialog(fmt.Sprintf("Invited %s's character over...", shortPeerID(peer.ID)))
+	}
+	if len(peers) == 1 {
+		invite(peers[0])
+		return
+	}
+	dw.peerSelectionDialog.Show(peers, invite, func() {})
+}
+
+// publishVisit streams our character's state to the peers it is visiting
+func (dw *DesktopWindow) publishVisit() {
+	if dw.networkOverlay == nil {
+		return
+	}
+	if visits := dw.networkOverlay.GetVisits(); visits != nil && len(visits.Visiting()) > 0 {
+		visits.Publish(dw.character.GetCurrentState(), "")
+	}
+}
+
+// shareVisitDialog shows what our character said to the peers it is
+// visiting. Only its responses to the user are shared, not reminders or
+// other notices meant for the user alone.
+func (dw *DesktopWindow) shareVisitDialog(text string) {
+	if dw.networkOverlay == nil {
+		return
+	}
+	if visits := dw.networkOverlay.GetVisits(); visits != nil && len(visits.Visiting()) > 0 {
+		visits.Publish(dw.character.GetCurrentState(), text)
+	}
+}
+
+// endVisits sends guests home and brings our character back when the
+// window closes
+func (dw *DesktopWindow) endVisits() {
+	if dw.networkOverlay == nil {
+		return
+	}
+	if visits := dw.networkOverlay.GetVisits(); visits != nil {
+		visits.EndAll()
+	}
+}
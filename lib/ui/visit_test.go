@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/network"
+)
+
+func TestGuestVisitLifecycle(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterWithScreensaver(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, false, false, nil, false, false, false)
+
+	nm := &mockNetworkManager{peers: []network.Peer{{ID: "abcdef0123456789"}}}
+	dw.networkOverlay = NewNetworkOverlay(nm)
+	dw.networkOverlay.RegisterNetworkEvents()
+	dw.setupVisits(char)
+
+	animations := encodeVisitAnimations(char)
+	if len(animations) != 3 {
+		t.Fatalf("Expected all three animations to be packed for a visit, got %d", len(animations))
+	}
+
+	send := func(payload network.VisitPayload) {
+		t.Helper()
+		data, _ := json.Marshal(payload)
+		if err := nm.handlers[network.MessageTypeVisit](network.Message{Type: network.MessageTypeVisit, Payload: data}, &network.Peer{ID: "abcdef0123456789"}); err != nil {
+			t.Fatalf("Visit handler failed: %v", err)
+		}
+	}
+
+	if err := dw.networkOverlay.GetVisits().Invite("abcdef0123456789"); err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	send(network.VisitPayload{Kind: network.VisitKindArrive, Character: "Luna", Animations: animations, State: "idle"})
+
+	guest := dw.visits.guests["abcdef0123456789"]
+	if guest == nil || guest.sprite.Image == nil {
+		t.Fatal("Expected the guest to be drawn in its own window")
+	}
+
+	send(network.VisitPayload{Kind: network.VisitKindUpdate, State: "happy", Dialog: "Nice place!"})
+	if guest.animation.GetCurrentAnimationName() != "happy" {
+		t.Errorf("Expected the guest to play the streamed state, got %q", guest.animation.GetCurrentAnimationName())
+	}
+	if !guest.bubble.Visible() || guest.bubble.Text != "Nice place!" {
+		t.Errorf("Expected the guest's dialog in its bubble, got %q", guest.bubble.Text)
+	}
+
+	send(network.VisitPayload{Kind: network.VisitKindLeave})
+	if len(dw.visits.guests) != 0 || len(dw.networkOverlay.GetVisits().Guests()) != 0 {
+		t.Error("Expected the guest window to close when the visit ends")
+	}
+}
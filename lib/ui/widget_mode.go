@@ -0,0 +1,209 @@
+package ui
+
+// widget_mode.go implements the compact widget layout for mobile cards that
+// ask for platformConfig.mobile.windowMode "widget": a small sprite with a
+// one-line stats bar, redrawn at a low frame rate to save battery. Tapping
+// the widget opens the full app; the context menu goes back to the widget.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	widgetSpriteSize   = 96               // Largest sprite side in widget mode
+	widgetStatsHeight  = 24               // Height of the stats bar under the sprite
+	widgetStatsRefresh = 30 * time.Second // How often the stats bar is redrawn
+	widgetActiveFPS    = time.Second / 12 // Frame interval while animating in widget mode
+	widgetIdleFPS      = time.Second / 2  // Frame interval while nothing changes in widget mode
+	widgetStatLabelLen = 3                // Letters of each stat name on the bar
+)
+
+// widgetModeState tracks the compact widget layout for the window
+type widgetModeState struct {
+	mu        sync.Mutex
+	enabled   bool          // The card asked for widget mode, so the menu offers it
+	active    bool          // The widget layout is showing
+	wasStats  bool          // The stats overlay was showing and comes back with the full app
+	statsBar  *widget.Label // Minimal stats line under the sprite
+	refreshed time.Time     // When the stats bar was last redrawn
+}
+
+// EnableWidgetMode starts the window in the compact widget layout and lets
+// the user return to it from the full app
+func (dw *DesktopWindow) EnableWidgetMode() {
+	dw.widget.mu.Lock()
+	dw.widget.enabled = true
+	dw.widget.mu.Unlock()
+	dw.enterWidgetMode()
+}
+
+// IsWidgetMode returns whether the compact widget layout is showing
+func (dw *DesktopWindow) IsWidgetMode() bool {
+	dw.widget.mu.Lock()
+	defer dw.widget.mu.Unlock()
+	return dw.widget.active
+}
+
+// enterWidgetMode shrinks the window to the sprite and stats bar and hides
+// the full stats overlay
+func (dw *DesktopWindow) enterWidgetMode() {
+	dw.widget.mu.Lock()
+	defer dw.widget.mu.Unlock()
+
+	if dw.widget.active {
+		return
+	}
+	dw.widget.active = true
+	dw.widget.wasStats = dw.statsOverlay != nil && dw.statsOverlay.IsVisible()
+	if dw.widget.wasStats {
+		dw.statsOverlay.Hide()
+	}
+
+	side := widgetSize(dw.character.GetSize())
+	dw.window.SetFullScreen(false)
+	dw.renderer.Move(fyne.NewPos(0, 0))
+	dw.renderer.Resize(fyne.NewSize(side, side))
+	dw.renderer.SetSize(int(side))
+
+	height := side
+	if bar := dw.widget.statsBar; bar != nil && dw.character.GetGameState() != nil {
+		bar.Move(fyne.NewPos(0, side))
+		bar.Resize(fyne.NewSize(side, widgetStatsHeight))
+		bar.Show()
+		dw.widget.refreshed = time.Time{} // Draw the stats on the next frame
+		height += widgetStatsHeight
+	}
+	dw.window.Resize(fyne.NewSize(side, height))
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+		"size":   side,
+	}).Info("Widget mode started")
+}
+
+// exitWidgetMode opens the full app from the widget. Returns true if the
+// widget layout was showing.
+func (dw *DesktopWindow) exitWidgetMode() bool {
+	dw.widget.mu.Lock()
+	defer dw.widget.mu.Unlock()
+
+	if !dw.widget.active {
+		return false
+	}
+	dw.widget.active = false
+	if dw.widget.statsBar != nil {
+		dw.widget.statsBar.Hide()
+	}
+
+	size := dw.character.GetSize()
+	dw.renderer.Resize(fyne.NewSize(float32(size), float32(size)))
+	dw.renderer.SetSize(size)
+	dw.window.Resize(fyne.NewSize(float32(size), float32(size)))
+	if dw.widget.wasStats {
+		dw.statsOverlay.Show()
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"caller": getCaller(),
+	}).Info("Widget mode stopped")
+	return true
+}
+
+// widgetSize returns the sprite side in widget mode, never larger than the
+// character's own size
+func widgetSize(size int) float32 {
+	if size > 0 && size < widgetSpriteSize {
+		return float32(size)
+	}
+	return widgetSpriteSize
+}
+
+// widgetStatsLabel creates the hidden stats bar shown under the widget sprite
+func (dw *DesktopWindow) widgetStatsLabel() *widget.Label {
+	dw.widget.mu.Lock()
+	defer dw.widget.mu.Unlock()
+
+	if dw.widget.statsBar == nil {
+		dw.widget.statsBar = widget.NewLabel("")
+		dw.widget.statsBar.TextStyle = fyne.TextStyle{Monospace: true}
+		dw.widget.statsBar.Alignment = fyne.TextAlignCenter
+		dw.widget.statsBar.Truncation = fyne.TextTruncateEllipsis
+		dw.widget.statsBar.Hide()
+	}
+	return dw.widget.statsBar
+}
+
+// refreshWidgetStats redraws the stats bar every widgetStatsRefresh rather
+// than every frame, so the widget stays cheap to keep on screen
+func (dw *DesktopWindow) refreshWidgetStats() {
+	dw.widget.mu.Lock()
+	if !dw.widget.active || dw.widget.statsBar == nil || time.Since(dw.widget.refreshed) < widgetStatsRefresh {
+		dw.widget.mu.Unlock()
+		return
+	}
+	dw.widget.refreshed = time.Now()
+	bar := dw.widget.statsBar
+	dw.widget.mu.Unlock()
+
+	gameState := dw.character.GetGameState()
+	if gameState == nil {
+		return
+	}
+	bar.SetText(formatWidgetStats(gameState.GetStats(), gameState.GetCriticalStates()))
+}
+
+// formatWidgetStats shortens the stats to a single line, such as
+// "Hap 80 Hun 12!", with critical stats marked
+func formatWidgetStats(stats map[string]float64, critical []string) string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		label := capitalizeFirst(name)
+		if len(label) > widgetStatLabelLen {
+			label = label[:widgetStatLabelLen]
+		}
+		part := fmt.Sprintf("%s %.0f", label, stats[name])
+		if contains(critical, name) {
+			part += "!"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// widgetFrameRates returns the animation loop's frame intervals, slower in
+// widget mode to save battery
+func (dw *DesktopWindow) widgetFrameRates(maxFPS, idleFPS time.Duration) (time.Duration, time.Duration) {
+	if dw.IsWidgetMode() {
+		return widgetActiveFPS, widgetIdleFPS
+	}
+	return maxFPS, idleFPS
+}
+
+// buildWidgetModeMenuItem offers going back to the widget from the full app
+// for cards that use widget mode
+func (dw *DesktopWindow) buildWidgetModeMenuItem() (ContextMenuItem, bool) {
+	dw.widget.mu.Lock()
+	offer := dw.widget.enabled && !dw.widget.active
+	dw.widget.mu.Unlock()
+	if !offer {
+		return ContextMenuItem{}, false
+	}
+	return ContextMenuItem{
+		Text:     "📌 Back to Widget",
+		Callback: dw.enterWidgetMode,
+	}, true
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/opd-ai/desktop-companion/lib/character"
+)
+
+// createTestCharacterForWidget builds a mobile character with stats that
+// starts as a home-screen widget
+func createTestCharacterForWidget(t *testing.T, tmpDir string) *character.Character {
+	createTestAnimationFiles(t, tmpDir)
+
+	cardContent := `{
+		"name": "Pocket",
+		"description": "A test character that lives on the home screen",
+		"animations": {"idle": "idle.gif", "talking": "talking.gif", "happy": "happy.gif"},
+		"dialogs": [{"trigger": "click", "responses": ["Hello!"], "animation": "talking", "cooldown": 5}],
+		"behavior": {"idleTimeout": 30, "movementEnabled": false, "defaultSize": 256},
+		"stats": {
+			"hunger": {"initial": 10, "max": 100, "degradationRate": 0, "criticalThreshold": 20},
+			"happiness": {"initial": 80, "max": 100, "degradationRate": 0, "criticalThreshold": 15}
+		},
+		"gameRules": {"statsDecayInterval": 60, "autoSaveInterval": 300},
+		"platformConfig": {"mobile": {"windowMode": "widget"}}
+	}`
+
+	cardPath := filepath.Join(tmpDir, "character.json")
+	if err := os.WriteFile(cardPath, []byte(cardContent), 0o644); err != nil {
+		t.Fatalf("Failed to write test character card: %v", err)
+	}
+	card, err := character.LoadCard(cardPath)
+	if err != nil {
+		t.Fatalf("Failed to load test character card: %v", err)
+	}
+	char, err := character.New(card, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create test character: %v", err)
+	}
+	return char
+}
+
+func TestWidgetModeLifecycle(t *testing.T) {
+	app := test.NewApp()
+	defer test.NewApp() // Reset test app
+
+	char := createTestCharacterForWidget(t, t.TempDir())
+	dw := NewDesktopWindow(app, char, false, nil, true, true, nil, false, false, false)
+
+	if _, ok := dw.buildWidgetModeMenuItem(); ok {
+		t.Error("The widget menu item should wait for a card that uses widget mode")
+	}
+
+	dw.EnableWidgetMode()
+	if !dw.IsWidgetMode() {
+		t.Fatal("Expected the widget layout after enabling it")
+	}
+	if dw.statsOverlay.IsVisible() {
+		t.Error("The full stats overlay should hide in widget mode")
+	}
+	size := dw.window.Canvas().Size()
+	if size.Width != widgetSpriteSize || size.Height != widgetSpriteSize+widgetStatsHeight {
+		t.Errorf("Expected a %vx%v widget, got %vx%v", widgetSpriteSize, widgetSpriteSize+widgetStatsHeight, size.Width, size.Height)
+	}
+	if _, ok := dw.buildWidgetModeMenuItem(); ok {
+		t.Error("The widget menu item should hide while the widget is showing")
+	}
+
+	dw.refreshWidgetStats()
+	if got := dw.widget.statsBar.Text; got != "Hap 80 Hun 10!" {
+		t.Errorf("Unexpected stats bar %q", got)
+	}
+
+	maxFPS, idleFPS, _ := dw.initializeFrameRates()
+	if maxFPS != widgetActiveFPS || idleFPS != widgetIdleFPS {
+		t.Errorf("Expected battery-friendly frame rates, got %v and %v", maxFPS, idleFPS)
+	}
+
+	// A tap opens the full app instead of talking
+	dw.handleClick()
+	if dw.IsWidgetMode() {
+		t.Fatal("A tap should open the full app")
+	}
+	if dw.dialog.IsVisible() {
+		t.Error("The tap that opens the app should not count as a click on the character")
+	}
+	if !dw.statsOverlay.IsVisible() {
+		t.Error("The stats overlay should come back with the full app")
+	}
+	if size := dw.window.Canvas().Size(); size.Width != 256 {
+		t.Errorf("Expected the full size back, got %v", size.Width)
+	}
+
+	item, ok := dw.buildWidgetModeMenuItem()
+	if !ok {
+		t.Fatal("Expected the menu to offer going back to the widget")
+	}
+	item.Callback()
+	if !dw.IsWidgetMode() {
+		t.Error("The menu item should bring the widget back")
+	}
+}
+
+func TestFormatWidgetStats(t *testing.T) {
+	stats := map[string]float64{"energy": 55.4, "hunger": 12, "happiness": 80}
+	if got := formatWidgetStats(stats, []string{"hunger"}); got != "Ene 55 Hap 80 Hun 12!" {
+		t.Errorf("Unexpected stats line %q", got)
+	}
+	if got := formatWidgetStats(map[string]float64{"hp": 3}, nil); got != "Hp 3" {
+		t.Errorf("Short names should be kept whole, got %q", got)
+	}
+	if got := formatWidgetStats(nil, nil); got != "" {
+		t.Errorf("Expected an empty line without stats, got %q", got)
+	}
+}
+
+func TestWidgetSize(t *testing.T) {
+	if got := widgetSize(256); got != widgetSpriteSize {
+		t.Errorf("Large characters should shrink to %v, got %v", widgetSpriteSize, got)
+	}
+	if got := widgetSize(64); got != 64 {
+		t.Errorf("Small characters should keep their size, got %v", got)
+	}
+}
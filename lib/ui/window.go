@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
 	"image/color"
 	"log"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -18,8 +21,13 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/opd-ai/desktop-companion/lib/character"
+	"github.com/opd-ai/desktop-companion/lib/clipboard"
+	"github.com/opd-ai/desktop-companion/lib/config"
 	"github.com/opd-ai/desktop-companion/lib/monitoring"
 	"github.com/opd-ai/desktop-companion/lib/network"
+	"github.com/opd-ai/desktop-companion/lib/platform"
+	"github.com/opd-ai/desktop-companion/lib/platform/native"
+	"github.com/opd-ai/desktop-companion/lib/push"
 )
 
 // getCaller returns the calling function name for structured logging
@@ -49,6 +57,9 @@ type DesktopWindow struct {
 	window                  fyne.Window
 	character               *character.Character
 	renderer                *CharacterRenderer
+	clickable               *PlatformAwareClickableWidget // Interaction overlay for non-draggable characters
+	draggable               *DraggableCharacter           // Interaction widget for movable characters
+	ctrlHeld                atomic.Bool                   // Ctrl is down; Ctrl+scroll resizes the character
 	dialog                  *DialogBubble
 	contextMenu             *ContextMenu
 	statsOverlay            *StatsOverlay
@@ -60,8 +71,56 @@ type DesktopWindow struct {
 	peerSelectionDialog     *PeerSelectionDialog
 	achievementNotification *AchievementNotification
 	groupEventNotification  *GroupEventNotification
+	peerChat                *network.ConversationManager // Idle chats with networked characters
+	packTransfers           *network.FileTransfers       // Character packs shared with peers, nil until SetupPackSharing
+	createPack              func() (string, error)       // Builds our character pack for sharing
+	cardPath                string                       // Running character's card, for the character switcher
+	listCharacters          func() []OnboardingCharacter // Installed characters offered by the switcher
+	switchCharacter         func(cardPath string)        // Replaces the companion with another character, nil until SetCharacterSwitcher
 	saveStatusIndicator     *SaveStatusIndicator
+	crisisIndicator         *widget.Button           // Warning icon shown during a relationship crisis
+	shapeMu                 sync.Mutex               // Guards the transparency fields below
+	transparency            native.TransparentWindow // nil when the platform has no per-pixel transparency
+	lastShapeFrame          image.Image
+	shapeCleared            bool
+	lastInputShape          inputShapeState // What the input shape was last computed from
+	noClickThrough          bool            // The window can't pass clicks through
+	topMu                   sync.Mutex      // Guards alwaysOnTop and focusFallbackStop
+	alwaysOnTop             bool            // Keep the window above other windows
+	focusFallbackStop       chan struct{}   // Stops the focus loop used when native always-on-top is unavailable
+	recording               atomic.Bool     // A capture recording is in progress
+	speech                  atomic.Bool     // Read dialog aloud
+	settingsMu              sync.Mutex      // Guards the settings and metrics fields below
+	settings                config.Settings
+	settingsPath            string // Empty hides the Settings menu item
+	settingsDialog          *SettingsDialog
+	autostart               platform.Autostart // Login entry behind "Start with system", nil hides it
+	tuningDialog            *RelationshipTuningDialog
+	photoDialog             *PhotoModeDialog
+	miniGameDialog          *MiniGameDialog
+	remote                  RemoteCompanion // Daemon the window is a thin client of, nil when running locally
+	metricsLabel            *widget.Label
+	focusLabel              *widget.Label  // Pomodoro countdown, hidden when the timer is off
+	particles               *ParticleLayer // Nil when the card has no particle effects
+	pusher                  *push.Pusher   // Phone notifications, nil unless -push-url is set
+	metricsStop             chan struct{}  // Stops the metrics refresh loop
+	clipboardMu             sync.Mutex
+	clipboardWatcher        *clipboard.Watcher // Nil unless the user opted in to clipboard reactions
+	presenceMu              sync.Mutex
+	presenceStop            chan struct{}     // Stops the away detection loop
+	shy                     shyState          // Hiding at the screen edge in shy mode
+	perch                   perchState        // Sitting on the focused window in perch mode
+	fullscreen              fullscreenState   // Stepping aside for fullscreen apps
+	notifications           notificationState // Stats already raised as desktop notifications
+	screensaver             screensaverState  // Fullscreen showcase while the user is idle
+	widget                  widgetModeState   // Compact home-screen layout on mobile
+	visits                  visitState        // Peers' characters visiting our desktop
+	logPath                 string            // Character's log file for "View Logs", empty when not logging to a file
+	held                    heldNotices       // Announcements waiting out do-not-disturb or focus
+	update                  updateState       // Newer release announced by the update checker
+	layer                   layerOverlayState // Wayland overlay surface showing the sprite, when supported
 	profiler                *monitoring.Profiler
+	degradation             monitoring.DegradationLevel // Cutbacks for slow frames, owned by the animation loop
 	debug                   bool
 	gameMode                bool
 	showStats               bool
@@ -176,8 +235,7 @@ func createConfiguredWindow(app fyne.App, char *character.Character, debug bool)
 	// Configure transparency for desktop overlay
 	configureTransparency(window, debug)
 
-	// Attempt to configure always-on-top behavior using available Fyne capabilities
-	// Note: Fyne has limited always-on-top support, but we can try available approaches
+	// Prepare always-on-top; the native hint is applied once the window is shown
 	configureAlwaysOnTop(window, debug)
 
 	return window
@@ -190,9 +248,13 @@ func initializeBasicComponents(dw *DesktopWindow, char *character.Character, deb
 
 	// Create dialog bubble (initially hidden)
 	dw.dialog = NewDialogBubble()
+	if card := char.GetCard(); card != nil {
+		dw.dialog.SetStyle(card.BubbleStyle)
+	}
 
 	// Create context menu (initially hidden)
 	dw.contextMenu = NewContextMenu()
+	dw.contextMenu.OnPreview = dw.showPreview
 
 	// Create battle invitation dialog (initially hidden)
 	dw.battleInvitationDialog = NewBattleInvitationDialog()
@@ -202,6 +264,9 @@ func initializeBasicComponents(dw *DesktopWindow, char *character.Character, deb
 
 	// Create save status indicator (small, positioned in corner)
 	dw.saveStatusIndicator = NewSaveStatusIndicator()
+
+	// Create crisis warning icon (hidden until a relationship crisis starts)
+	dw.crisisIndicator = newCrisisIndicator(dw.showRelationshipStatus)
 }
 
 // initializeGameFeatures sets up game-related features like stats overlay
@@ -246,6 +311,11 @@ func initializeGiftSystem(dw *DesktopWindow, gameMode bool, char *character.Char
 		dw.giftDialog.SetOnCancel(func() {
 			// Dialog closed, no action needed
 		})
+
+		// Let /gift in the chat give gifts too
+		if dw.chatbotInterface != nil {
+			dw.chatbotInterface.SetGiftManager(giftManager)
+		}
 	}
 }
 
@@ -254,10 +324,22 @@ func initializeNetworkFeatures(dw *DesktopWindow, networkMode bool, networkManag
 	if networkMode && networkManager != nil {
 		dw.networkOverlay = NewNetworkOverlay(networkManager)
 		dw.networkOverlay.RegisterNetworkEvents()
+		if provider, ok := networkManager.(interface {
+			GetPermissionStore() *network.PermissionStore
+		}); ok {
+			dw.networkOverlay.SetPermissionStore(provider.GetPermissionStore())
+		}
+
+		// Spectate requests for battles we started are approved here when
+		// the "Ask me" spectator setting is chosen
+		dw.networkOverlay.GetSpectatorHub().Approve = func(battleID, peerID string, respond func(accepted bool)) {
+			dw.battleInvitationDialog.ShowRequest("Spectate Request", peerID+" wants to watch your battle. Allow?", respond)
+		}
 
 		// Set local character name for clear UI distinction
 		if char != nil && char.GetCard() != nil {
 			dw.networkOverlay.SetLocalCharacterName(char.GetCard().Name)
+			dw.networkOverlay.SetChatAvatar(char.GetCurrentFrame())
 		}
 
 		// Feature 5: Initialize compatibility calculator for personality-based scoring
@@ -284,12 +366,45 @@ func initializeNetworkFeatures(dw *DesktopWindow, networkMode bool, networkManag
 			dw.ShowGroupEventInvitation(invitation, onResponse)
 		}
 
+		if char != nil && char.GetCard() != nil {
+			dw.setupPeerChat(networkManager, char)
+			dw.setupVisits(char)
+			dw.setupSharedPet(char)
+		}
+
 		if showNetwork {
 			dw.networkOverlay.Show()
 		}
 	}
 }
 
+// overlays returns what is drawn over the character, created on first use
+// so it survives content rebuilds: particle effects, performance metrics
+// (bottom-left, hidden until enabled) and the focus timer (top).
+func (dw *DesktopWindow) overlays() []fyne.CanvasObject {
+	size := float32(dw.character.GetSize())
+	var objects []fyne.CanvasObject
+	if dw.particles == nil && len(dw.character.GetCard().GetParticleEffects()) > 0 {
+		dw.particles = NewParticleLayer(dw.character.GetSize())
+	}
+	if dw.particles != nil {
+		objects = append(objects, dw.particles.GetContainer())
+	}
+	if dw.metricsLabel == nil {
+		dw.metricsLabel = widget.NewLabel("")
+		dw.metricsLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		dw.metricsLabel.Move(fyne.NewPos(0, size-36))
+		dw.metricsLabel.Hide()
+	}
+	if dw.focusLabel == nil {
+		dw.focusLabel = widget.NewLabel("")
+		dw.focusLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		dw.focusLabel.Move(fyne.NewPos(size/2-40, 0))
+		dw.focusLabel.Hide()
+	}
+	return append(objects, dw.metricsLabel, dw.focusLabel, dw.widgetStatsLabel())
+}
+
 // setupContent configures the window's visual content
 func (dw *DesktopWindow) setupContent() {
 	// Create list of content objects
@@ -307,6 +422,14 @@ func (dw *DesktopWindow) setupContent() {
 		objects = append(objects, dw.saveStatusIndicator)
 	}
 
+	// Add particle effects, performance metrics and focus timer labels
+	objects = append(objects, dw.overlays()...)
+
+	// Add crisis warning icon (top-left corner)
+	if dw.crisisIndicator != nil {
+		objects = append(objects, dw.crisisIndicator)
+	}
+
 	// Add stats overlay if available
 	if dw.statsOverlay != nil {
 		objects = append(objects, dw.statsOverlay.GetContainer())
@@ -368,12 +491,17 @@ func (dw *DesktopWindow) setupInteractions() {
 		return
 	}
 
-	// For non-draggable characters, create custom clickable widget that supports both left and right click
-	clickable := NewClickableWidget(
+	// For non-draggable characters, create a platform-aware clickable widget:
+	// mouse clicks on desktop, tap / long-press / double-tap / pinch on touch
+	clickable := NewPlatformAwareClickableWidgetWithDoubleTap(
 		func() { dw.handleClick() },
 		func() { dw.handleRightClick() },
+		func() { dw.handleDoubleTap() },
 	)
+	clickable.SetPinchHandler(dw.handlePinch)
+	clickable.SetScrollHandler(dw.handleScroll)
 	clickable.SetSize(fyne.NewSize(float32(dw.character.GetSize()), float32(dw.character.GetSize())))
+	dw.clickable = clickable
 
 	// Create list of content objects for interactive overlay
 	objects := []fyne.CanvasObject{
@@ -384,6 +512,7 @@ func (dw *DesktopWindow) setupInteractions() {
 		dw.battleInvitationDialog.GetContainer(),
 		dw.peerSelectionDialog.GetContainer(),
 	}
+	objects = append(objects, dw.overlays()...)
 
 	// Add save status indicator if available (positioned in corner)
 	if dw.saveStatusIndicator != nil {
@@ -392,6 +521,11 @@ func (dw *DesktopWindow) setupInteractions() {
 		objects = append(objects, dw.saveStatusIndicator)
 	}
 
+	// Add crisis warning icon (top-left corner)
+	if dw.crisisIndicator != nil {
+		objects = append(objects, dw.crisisIndicator)
+	}
+
 	// Add stats overlay if available
 	if dw.statsOverlay != nil {
 		objects = append(objects, dw.statsOverlay.GetContainer())
@@ -418,6 +552,14 @@ func (dw *DesktopWindow) setupInteractions() {
 
 // handleClick processes character click interactions
 func (dw *DesktopWindow) handleClick() {
+	if dw.stopScreensaver() || dw.exitWidgetMode() {
+		return
+	}
+	if remote := dw.attachedRemote(); remote != nil {
+		dw.forwardRemote(remote.Click)
+		return
+	}
+
 	response := dw.character.HandleClick()
 
 	if dw.debug {
@@ -426,6 +568,7 @@ func (dw *DesktopWindow) handleClick() {
 
 	if response != "" {
 		dw.showDialog(response)
+		dw.shareVisitDialog(response)
 	}
 }
 
@@ -441,8 +584,21 @@ func (dw *DesktopWindow) handleRightClick() {
 }
 
 // showDialog displays a dialog bubble with the given text
+// Dialogs are silently dropped while do-not-disturb or a focus session is
+// active; announcements that must not be lost use showNotice instead
 func (dw *DesktopWindow) showDialog(text string) {
+	if dw.isQuiet() {
+		return
+	}
+	dw.displayDialog(text)
+}
+
+// displayDialog shows and speaks text, then hides it after a few seconds
+func (dw *DesktopWindow) displayDialog(text string) {
+	// A shy character comes out to talk
+	dw.emergeShy()
 	dw.dialog.ShowWithText(text)
+	dw.speak(text)
 
 	// Auto-hide dialog after 3 seconds
 	go func() {
@@ -451,22 +607,18 @@ func (dw *DesktopWindow) showDialog(text string) {
 	}()
 }
 
+// ShowAttributedDialog shows a response credited to whoever triggered it,
+// e.g. a stream viewer running a chat command
+func (dw *DesktopWindow) ShowAttributedDialog(source, text string) {
+	dw.showDialog(fmt.Sprintf("%s\n— %s", text, source))
+}
+
 // showEventFrequencySettings displays the random event frequency settings dialog
 // Feature 6: Random Event Frequency Tuning - allows users to adjust event frequency
 func (dw *DesktopWindow) showEventFrequencySettings() {
 	currentMultiplier := dw.character.GetEventFrequencyMultiplier()
 
-	// Create frequency options with user-friendly labels
-	options := []struct {
-		label      string
-		multiplier float64
-	}{
-		{"Very Rare (0.5x)", 0.5},
-		{"Normal (1.0x)", 1.0},
-		{"Frequent (1.5x)", 1.5},
-		{"Very Frequent (2.0x)", 2.0},
-		{"Maximum (3.0x)", 3.0},
-	}
+	options := eventFrequencyOptions
 
 	// Create option labels for the select widget
 	optionLabels := make([]string, len(options))
@@ -547,6 +699,13 @@ func (dw *DesktopWindow) showModalContent(content *fyne.Container) {
 func (dw *DesktopWindow) showContextMenu() {
 	var menuItems []ContextMenuItem
 
+	if remote := dw.attachedRemote(); remote != nil {
+		menuItems = append(menuItems, dw.buildRemoteMenuItems(remote)...)
+		menuItems = append(menuItems, dw.buildUtilityMenuItems()...)
+		dw.displayContextMenu(menuItems)
+		return
+	}
+
 	menuItems = append(menuItems, dw.buildBasicMenuItems()...)
 	menuItems = append(menuItems, dw.buildGameModeMenuItems()...)
 	menuItems = append(menuItems, dw.buildBattleMenuItems()...)
@@ -594,7 +753,8 @@ func (dw *DesktopWindow) buildGameModeMenuItems() []ContextMenuItem {
 	var menuItems []ContextMenuItem
 
 	menuItems = append(menuItems, ContextMenuItem{
-		Text: "Feed",
+		Text:    "Feed",
+		Preview: dw.interactionPreview("feed"),
 		Callback: func() {
 			response := dw.character.HandleGameInteraction("feed")
 			if response != "" {
@@ -604,7 +764,8 @@ func (dw *DesktopWindow) buildGameModeMenuItems() []ContextMenuItem {
 	})
 
 	menuItems = append(menuItems, ContextMenuItem{
-		Text: "Play",
+		Text:    "Play",
+		Preview: dw.interactionPreview("play"),
 		Callback: func() {
 			response := dw.character.HandleGameInteraction("play")
 			if response != "" {
@@ -613,6 +774,16 @@ func (dw *DesktopWindow) buildGameModeMenuItems() []ContextMenuItem {
 		},
 	})
 
+	if undoItem, ok := dw.buildUndoMenuItem(); ok {
+		menuItems = append(menuItems, undoItem)
+	}
+
+	if apologyItem, ok := dw.buildApologyMenuItem(); ok {
+		menuItems = append(menuItems, apologyItem)
+	}
+
+	menuItems = append(menuItems, dw.buildMiniGameMenuItem())
+
 	// Add gift option if character has gift system enabled
 	if dw.character.GetCard().HasGiftSystem() && dw.giftDialog != nil {
 		menuItems = append(menuItems, ContextMenuItem{
@@ -623,6 +794,39 @@ func (dw *DesktopWindow) buildGameModeMenuItems() []ContextMenuItem {
 		})
 	}
 
+	if jobItem, ok := dw.buildJobMenuItem(); ok {
+		menuItems = append(menuItems, jobItem)
+	}
+
+	if shopItem, ok := dw.buildShopMenuItem(); ok {
+		menuItems = append(menuItems, shopItem)
+	}
+
+	if birthdayItem, ok := dw.buildBirthdayMenuItem(); ok {
+		menuItems = append(menuItems, birthdayItem)
+	}
+
+	if relationshipItem, ok := dw.buildRelationshipStatusMenuItem(); ok {
+		menuItems = append(menuItems, relationshipItem)
+	}
+
+	if tuningItem, ok := dw.buildRelationshipTuningMenuItem(); ok {
+		menuItems = append(menuItems, tuningItem)
+	}
+
+	if journalItem, ok := dw.buildJournalMenuItem(); ok {
+		menuItems = append(menuItems, journalItem)
+	}
+
+	if len(dw.character.GetAchievementStatuses()) > 0 {
+		menuItems = append(menuItems, ContextMenuItem{
+			Text: "🏆 Achievements",
+			Callback: func() {
+				dw.showAchievementList()
+			},
+		})
+	}
+
 	if dw.statsOverlay != nil {
 		statsText := "Show Stats"
 		if dw.statsOverlay.IsVisible() {
@@ -767,6 +971,10 @@ func (dw *DesktopWindow) buildNewsMenuItems() []ContextMenuItem {
 		},
 	})
 
+	if item, ok := dw.buildNewsPreferencesMenuItem(); ok {
+		menuItems = append(menuItems, item)
+	}
+
 	return menuItems
 }
 
@@ -791,13 +999,18 @@ func (dw *DesktopWindow) buildNetworkMenuItems() []ContextMenuItem {
 			dw.ToggleNetworkOverlay()
 		},
 	})
+	menuItems = append(menuItems, dw.buildVisitMenuItems()...)
+	menuItems = append(menuItems, dw.buildSharedPetMenuItems()...)
+	if item, ok := dw.buildPackShareMenuItem(); ok {
+		menuItems = append(menuItems, item)
+	}
 
 	return menuItems
 }
 
 // buildUtilityMenuItems creates utility menu items like About and Shortcuts
 func (dw *DesktopWindow) buildUtilityMenuItems() []ContextMenuItem {
-	return []ContextMenuItem{
+	items := []ContextMenuItem{
 		{
 			Text: "About",
 			Callback: func() {
@@ -814,6 +1027,57 @@ func (dw *DesktopWindow) buildUtilityMenuItems() []ContextMenuItem {
 				dw.showDialog(shortcutsText)
 			},
 		},
+		dw.buildDoNotDisturbMenuItem(),
+		dw.buildFocusMenuItem(),
+		dw.buildAlwaysOnTopMenuItem(),
+		dw.buildShyModeMenuItem(),
+		dw.buildCaptureMenuItem(),
+		dw.buildPhotoModeMenuItem(),
+	}
+
+	if item, ok := dw.buildPerchMenuItem(); ok {
+		items = append(items, item)
+	}
+	if item, ok := dw.buildWidgetModeMenuItem(); ok {
+		items = append(items, item)
+	}
+	if item, ok := dw.buildAnalyticsMenuItem(); ok {
+		items = append(items, item)
+	}
+	if item, ok := dw.buildLogsMenuItem(); ok {
+		items = append(items, item)
+	}
+	if item, ok := dw.buildCharacterSwitcherMenuItem(); ok {
+		items = append(items, item)
+	}
+	if item, ok := dw.buildSettingsMenuItem(); ok {
+		items = append(items, item)
+	}
+	if item, ok := dw.buildUpdateMenuItem(); ok {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// buildDoNotDisturbMenuItem creates the toggle for silencing dialogs and notifications
+func (dw *DesktopWindow) buildDoNotDisturbMenuItem() ContextMenuItem {
+	if dw.character.IsDoNotDisturb() {
+		return ContextMenuItem{
+			Text: "🔔 Disable Do Not Disturb",
+			Callback: func() {
+				dw.character.SetDoNotDisturb(false)
+				dw.showDialog("Do not disturb disabled")
+			},
+		}
+	}
+
+	return ContextMenuItem{
+		Text: "🔕 Do Not Disturb",
+		Callback: func() {
+			dw.dialog.Hide()
+			dw.character.SetDoNotDisturb(true)
+		},
 	}
 }
 
@@ -852,25 +1116,49 @@ func (dw *DesktopWindow) displayContextMenu(menuItems []ContextMenuItem) {
 // animationLoop runs the character animation update loop
 // Uses adaptive frame rate based on animation needs to optimize performance
 func (dw *DesktopWindow) animationLoop() {
+	defer monitoring.RecoverCrash() // Game logic runs here, off the main goroutine
 	maxFPS, idleFPS, currentInterval := dw.initializeFrameRates()
 	ticker := time.NewTicker(currentInterval)
 	defer ticker.Stop()
 
 	consecutiveNoChanges := 0
+	widgetMode := dw.IsWidgetMode()
 
 	for range ticker.C {
-		hasChanges := dw.character.Update()
+		frameStart := time.Now()
+
+		// Switch frame rates when the widget layout opens or closes
+		if dw.IsWidgetMode() != widgetMode {
+			widgetMode = !widgetMode
+			maxFPS, idleFPS, currentInterval = dw.initializeFrameRates()
+			ticker.Reset(currentInterval)
+		}
+
+		// Running particles need the full frame rate to move smoothly
+		hasChanges := dw.character.Update() || (dw.particles != nil && dw.particles.Active())
+		// Hold the current frame while a fullscreen app has the screen
+		if dw.fullscreenPaused() {
+			hasChanges = false
+		}
 		currentInterval, consecutiveNoChanges = dw.handleFrameRateAdaptation(
 			hasChanges, consecutiveNoChanges, currentInterval, maxFPS, idleFPS, ticker)
 		dw.processFrameUpdates(hasChanges)
+
+		// Scale back when frames keep running over budget, or restore quality
+		if dw.recordFrameTime(time.Since(frameStart)) {
+			maxFPS, idleFPS, currentInterval = dw.initializeFrameRates()
+			ticker.Reset(currentInterval)
+		}
 	}
 }
 
 // initializeFrameRates sets up the frame rate configuration for the animation loop
 func (dw *DesktopWindow) initializeFrameRates() (maxFPS, idleFPS, currentInterval time.Duration) {
-	maxFPS = time.Second / 60  // 60 FPS when actively animating
+	// 60 FPS when actively animating, less while slow frames scale the app back
+	maxFPS = time.Second / time.Duration(dw.degradation.MaxFPS(60))
 	idleFPS = time.Second / 10 // 10 FPS when idle/no changes
-	currentInterval = maxFPS   // Start with high frame rate
+	maxFPS, idleFPS = dw.widgetFrameRates(maxFPS, idleFPS)
+	currentInterval = maxFPS // Start with high frame rate
 	return maxFPS, idleFPS, currentInterval
 }
 
@@ -922,10 +1210,61 @@ func (dw *DesktopWindow) processFrameUpdates(hasChanges bool) {
 	// Check for new achievements and display notifications
 	dw.checkForNewAchievements()
 
+	// Celebrate relationship level-ups
+	dw.checkForLevelUps()
+
+	// Announce finished jobs and their rewards
+	dw.checkForCompletedJobs()
+
+	// Announce birthdays, anniversaries and holidays
+	dw.checkForCalendarEvents()
+
+	// Speak scheduled greetings and reminders
+	dw.checkForScheduledEvents()
+
+	// Announce pomodoro breaks and update the timer widget
+	dw.checkForFocusChanges()
+
+	// Show the crisis icon and announce crises starting or resolving
+	dw.checkForCrisisChanges()
+
+	// Raise desktop notifications for stats going critical
+	dw.checkForCriticalStats()
+
+	// Hearts, sweat drops and sparkles over the character
+	dw.checkForParticleBursts()
+
+	// Read out the daily news briefing
+	dw.checkForNewsDigests()
+
+	// Greet the user when they come back after a long absence
+	dw.checkForWelcomeBacks()
+
+	// Lines from the card's behavior script
+	dw.checkForScriptDialogs()
+
+	// Catch up on announcements held back by do-not-disturb or focus
+	dw.deliverHeldNotices()
+
+	// Show peers we're visiting what our character is doing
+	dw.publishVisit()
+
+	// Send the shared pet's partner what changed here
+	dw.publishSharedPet()
+
+	// Keep the widget's stats bar current
+	dw.refreshWidgetStats()
+
 	// Only refresh renderer when there are actual changes
 	if hasChanges {
 		dw.renderer.Refresh()
 	}
+
+	// Keep shaped windows in sync with the sprite and any open overlays
+	dw.updateWindowShape()
+	dw.updateInputShape()
+	dw.presentLayeredFrame(hasChanges)
+	dw.presentLayerOverlay()
 }
 
 // setupDragging configures character dragging behavior
@@ -933,6 +1272,7 @@ func (dw *DesktopWindow) setupDragging() {
 	// Create draggable wrapper that implements Fyne's drag interface
 	// This provides smooth cross-platform drag support without platform-specific code
 	draggable := NewDraggableCharacter(dw, dw.character, dw.debug)
+	dw.draggable = draggable
 
 	// Create list of content objects for draggable layout
 	objects := []fyne.CanvasObject{
@@ -940,6 +1280,7 @@ func (dw *DesktopWindow) setupDragging() {
 		dw.dialog,
 		dw.contextMenu,
 	}
+	objects = append(objects, dw.overlays()...)
 
 	// Add stats overlay if available
 	if dw.statsOverlay != nil {
@@ -964,6 +1305,8 @@ func (dw *DesktopWindow) setupDragging() {
 // Show displays the desktop window
 func (dw *DesktopWindow) Show() {
 	dw.window.Show()
+	dw.enablePerPixelTransparency()
+	dw.SetAlwaysOnTop(true)
 
 	if dw.debug {
 		log.Printf("Desktop window shown for character: %s", dw.character.GetName())
@@ -977,6 +1320,20 @@ func (dw *DesktopWindow) Hide() {
 
 // Close closes the desktop window and stops animation
 func (dw *DesktopWindow) Close() {
+	dw.topMu.Lock()
+	dw.stopFocusFallback()
+	dw.topMu.Unlock()
+	dw.closeTransparency()
+	dw.stopPeerChat()
+	dw.endVisits()
+	dw.leaveSharedPet()
+	dw.stopClipboardReactions()
+	dw.StopAwayDetection()
+	dw.stopShyMode()
+	dw.stopPerchMode()
+	dw.stopFullscreenWatch()
+	dw.stopLatencyChecks()
+	dw.stopScreensaver()
 	dw.window.Close()
 }
 
@@ -986,6 +1343,10 @@ func (dw *DesktopWindow) SetPosition(x, y int) {
 	// Store position in character for reference
 	dw.character.SetPosition(float32(x), float32(y))
 
+	if (x != 0 || y != 0) && dw.moveLayerOverlayTo(x, y) {
+		return
+	}
+
 	// Attempt to use available Fyne positioning capabilities
 	// Note: Full positioning support varies by platform, but we can try
 	if x == 0 && y == 0 {
@@ -1119,8 +1480,15 @@ func (dw *DesktopWindow) ToggleNetworkOverlay() {
 	}
 }
 
-// ShowAchievementNotification displays an achievement notification
+// ShowAchievementNotification displays an achievement notification, or
+// holds it until do-not-disturb ends
 func (dw *DesktopWindow) ShowAchievementNotification(details character.AchievementDetails) {
+	if dw.character != nil && dw.character.IsDoNotDisturb() {
+		dw.held.holdAchievement(details)
+		return
+	}
+	dw.notifyAchievement(details)
+
 	if dw.achievementNotification != nil {
 		dw.achievementNotification.ShowAchievement(details)
 		if dw.debug {
@@ -1140,6 +1508,32 @@ func (dw *DesktopWindow) ShowGroupEventInvitation(invitation network.GroupEventI
 	}
 }
 
+// showAchievementList displays earned and locked achievements in a modal overlay
+func (dw *DesktopWindow) showAchievementList() {
+	statuses := dw.character.GetAchievementStatuses()
+
+	earned := 0
+	var rows []fyne.CanvasObject
+	for _, status := range statuses {
+		marker := "🔒"
+		if status.Earned {
+			marker = "🏆"
+			earned++
+		}
+		label := widget.NewLabel(fmt.Sprintf("%s %s - %s", marker, status.Name, status.Description))
+		label.Wrapping = fyne.TextWrapWord
+		rows = append(rows, label)
+	}
+
+	titleLabel := widget.NewLabel(fmt.Sprintf("Achievements (%d/%d)", earned, len(statuses)))
+	titleLabel.Alignment = fyne.TextAlignCenter
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	content := container.NewVBox(append([]fyne.CanvasObject{titleLabel}, rows...)...)
+	content.Resize(fyne.NewSize(300, float32(40+30*len(statuses))))
+	dw.showModalContent(content)
+}
+
 // checkForNewAchievements checks if any new achievements were earned and displays notifications
 func (dw *DesktopWindow) checkForNewAchievements() {
 	if dw.character == nil || dw.achievementNotification == nil {
@@ -1158,56 +1552,25 @@ func (dw *DesktopWindow) checkForNewAchievements() {
 	}
 }
 
-// configureAlwaysOnTop attempts to configure always-on-top behavior using available Fyne capabilities
-// Following the "lazy programmer" principle: use what's available rather than implementing platform-specific code
+// configureAlwaysOnTop applies the overlay-friendly window settings Fyne exposes
+// The native window manager hint needs the window handle, so the real
+// always-on-top setting is applied by SetAlwaysOnTop once the window is shown
 func configureAlwaysOnTop(window fyne.Window, debug bool) {
-	// Fyne v2.4.5 has limited always-on-top support, but we can use available approaches:
-
-	// 1. Use RequestFocus to raise and focus the window (closest to always-on-top behavior)
+	// Raise and focus the window on creation
 	window.RequestFocus()
 
-	// 2. Try to minimize window decorations (makes it more overlay-like)
+	// Set window to fixed size to prevent accidental resizing that could lose focus
 	// Note: Title removal is already handled in configureTransparency to avoid duplication
-
-	// 3. Set window to fixed size to prevent accidental resizing that could lose focus
 	window.SetFixedSize(true)
 
-	// 4. Configure for desktop overlay use case
-	// Fyne's design philosophy focuses on cross-platform compatibility over platform-specific features
-	// True always-on-top requires platform-specific window manager hints that Fyne doesn't expose
-
-	// 5. Implement periodic focus maintenance for better desktop overlay behavior
-	go func() {
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				// Periodically request focus to maintain overlay-like behavior
-				// This helps ensure the companion stays visible during normal desktop use
-				if window != nil {
-					window.RequestFocus()
-				}
-			}
-		}
-	}()
-
 	if debug {
-		log.Println("Always-on-top configuration applied using available Fyne capabilities")
-		log.Println("Note: RequestFocus() used to raise and focus window for overlay behavior")
-		log.Println("Note: Periodic focus requests every 5 seconds to maintain visibility")
-		log.Println("Note: Fixed size prevents accidental resize that could interfere with focus")
-		log.Println("Note: Full always-on-top behavior requires platform-specific window manager support")
+		log.Println("Always-on-top pre-configuration applied; native hint is set when the window is shown")
 	}
-
-	// Future enhancement opportunity:
-	// Could implement platform-specific always-on-top using CGO or system calls,
-	// but this would violate the "lazy programmer" principle of avoiding custom platform code
 }
 
 // configureTransparency configures window transparency for desktop overlay behavior
 // Following the "lazy programmer" principle: use Fyne's available transparency features
+// Real per-pixel transparency needs the native handle and is applied in Show()
 func configureTransparency(window fyne.Window, debug bool) {
 	// Remove window padding to make character appear directly on desktop
 	window.SetPadded(false)
@@ -1230,6 +1593,7 @@ func (dw *DesktopWindow) setupKeyboardShortcuts() {
 
 	// Configure basic keyboard shortcuts
 	dw.setupBasicKeyShortcuts(canvas)
+	dw.setupModifierTracking(canvas)
 
 	// Configure conditional shortcuts based on features
 	dw.setupConditionalShortcuts(canvas)
@@ -1373,7 +1737,7 @@ func (dw *DesktopWindow) setupGeneralEventsShortcuts(canvas fyne.Canvas) {
 		dw.startRandomRoleplayScenario()
 	})
 
-	// Ctrl+G: Start a mini-game or trivia session
+	// Ctrl+G: Open the mini-games
 	ctrlG := &desktop.CustomShortcut{
 		KeyName:  fyne.KeyG,
 		Modifier: fyne.KeyModifierControl,
@@ -1531,30 +1895,6 @@ func (dw *DesktopWindow) startRandomRoleplayScenario() {
 	}
 }
 
-// startMiniGameSession triggers a game category event
-func (dw *DesktopWindow) startMiniGameSession() {
-	games := dw.character.GetGeneralEventsByCategory("game")
-
-	if len(games) == 0 {
-		dw.showDialog("No mini-games available for this character.")
-		return
-	}
-
-	// Pick a random game event
-	event := games[int(time.Now().UnixNano())%len(games)]
-
-	if dw.debug {
-		log.Printf("Triggering mini-game session: %s", event.Name)
-	}
-
-	response := dw.character.HandleGeneralEvent(event.Name)
-	if response != "" {
-		dw.showDialog(fmt.Sprintf("Mini-Game: %s\n\n%s", event.Name, response))
-	} else {
-		dw.showDialog(fmt.Sprintf("Could not start mini-game: %s", event.Name))
-	}
-}
-
 // startHumorSession triggers a humor category event
 func (dw *DesktopWindow) startHumorSession() {
 	humor := dw.character.GetGeneralEventsByCategory("humor")
@@ -1700,10 +2040,14 @@ func (dw *DesktopWindow) handleBattleInitiation() {
 
 // initiateBattleWithPeer initiates a battle with the specified peer
 func (dw *DesktopWindow) initiateBattleWithPeer(targetPeer network.Peer) {
+	if dw.refuseLaggyBattle(targetPeer.ID) {
+		return
+	}
+
 	// Create battle invitation payload
 	battleID := fmt.Sprintf("battle_%d", time.Now().UnixNano())
 	payload := network.BattleInvitePayload{
-		FromCharacterID: dw.networkOverlay.GetNetworkManager().GetNetworkID(),
+		FromCharacterID: dw.networkOverlay.GetNetworkManager().GetPeerID(),
 		ToCharacterID:   targetPeer.ID,
 		BattleID:        battleID,
 		Timestamp:       time.Now(),
@@ -1721,9 +2065,19 @@ func (dw *DesktopWindow) initiateBattleWithPeer(targetPeer network.Peer) {
 		return
 	}
 
+	dw.hostBattle(battleID, targetPeer.ID)
 	dw.showDialog(fmt.Sprintf("Battle invitation sent to %s! Waiting for response...", targetPeer.ID))
 }
 
+// hostBattle lets other peers ask to watch a battle we started, following
+// the spectator setting in the network overlay
+func (dw *DesktopWindow) hostBattle(battleID, opponentID string) {
+	participants := []string{dw.networkOverlay.GetNetworkManager().GetPeerID(), opponentID}
+	if err := dw.networkOverlay.HostBattle(battleID, participants); err != nil && dw.debug {
+		log.Printf("Failed to announce battle %s to spectators: %v", battleID, err)
+	}
+}
+
 // handleBattleInvitation handles sending battle invitations to other players in network mode
 func (dw *DesktopWindow) handleBattleInvitation() {
 	if dw.networkOverlay == nil || dw.networkOverlay.GetNetworkManager() == nil {
@@ -1754,10 +2108,14 @@ func (dw *DesktopWindow) handleBattleInvitation() {
 
 // sendBattleInvitation sends a battle invitation to the specified peer
 func (dw *DesktopWindow) sendBattleInvitation(targetPeer network.Peer, invitationType string) {
+	if dw.refuseLaggyBattle(targetPeer.ID) {
+		return
+	}
+
 	// Create battle invitation payload
 	battleID := fmt.Sprintf("battle_%s_%d", invitationType, time.Now().UnixNano())
 	payload := network.BattleInvitePayload{
-		FromCharacterID: dw.networkOverlay.GetNetworkManager().GetNetworkID(),
+		FromCharacterID: dw.networkOverlay.GetNetworkManager().GetPeerID(),
 		ToCharacterID:   targetPeer.ID,
 		BattleID:        battleID,
 		Timestamp:       time.Now(),
@@ -1775,6 +2133,7 @@ func (dw *DesktopWindow) sendBattleInvitation(targetPeer network.Peer, invitatio
 		return
 	}
 
+	dw.hostBattle(battleID, targetPeer.ID)
 	dw.showDialog(fmt.Sprintf("Battle %s sent to %s! They can accept or decline.", invitationType, targetPeer.ID))
 }
 
@@ -2058,9 +2417,22 @@ func (dw *DesktopWindow) SetSaveStatusCallback() func(SaveStatus, string) {
 	}
 }
 
+// SetPusher sends battle invitations to the user's phone as well
+func (dw *DesktopWindow) SetPusher(pusher *push.Pusher) {
+	dw.pusher = pusher
+}
+
 // ShowBattleInvitationDialog shows a battle invitation confirmation dialog
 // Provides a UI-based replacement for hardcoded battle acceptance logic
 func (dw *DesktopWindow) ShowBattleInvitationDialog(fromCharacter string, onResponse func(accepted bool)) {
+	if dw.refuseLaggyBattle(fromCharacter) {
+		onResponse(false)
+		return
+	}
+	if dw.pusher != nil {
+		dw.pusher.NotifyBattleInvitation(fromCharacter)
+	}
+	dw.notifyBattleInvitation(fromCharacter)
 	if dw.battleInvitationDialog != nil {
 		dw.battleInvitationDialog.Show(fromCharacter, onResponse)
 	}
@@ -0,0 +1,363 @@
+// Package update checks GitHub releases for a newer companion and, when the
+// user asks, replaces the running binary with the release's build for this
+// platform. Downloads are only installed after their SHA-256 checksum
+// matches the one published with the release.
+package update
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRepository = "opd-ai/desktop-companion"
+	DefaultAPIURL     = "https://api.github.com"
+	DefaultBinaryName = "companion"
+	ChecksumsFile     = "SHA256SUMS" // "<sha256>  <file>" lines published with each release
+
+	checkTimeout    = 15 * time.Second
+	downloadTimeout = 5 * time.Minute
+	maxBinarySize   = 512 << 20
+	releasesToCheck = 10
+)
+
+// Config says which releases to check and what is running now
+type Config struct {
+	CurrentVersion string // e.g. "1.0.0"; a leading "v" is ignored
+	Repository     string // "owner/name" (default: DefaultRepository)
+	APIURL         string // GitHub API base URL (default: DefaultAPIURL)
+	BinaryName     string // Release assets are BinaryName-<os>-<arch> (default: DefaultBinaryName)
+	GOOS           string // Platform to update for (default: runtime.GOOS)
+	GOARCH         string // (default: runtime.GOARCH)
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name   string
+	URL    string
+	Size   int64
+	Digest string // "sha256:<hex>" when GitHub computed it
+}
+
+// Release is a published version newer than the running one
+type Release struct {
+	Version   string // Without the leading "v"
+	Name      string
+	PageURL   string // Release page, for users who prefer to update by hand
+	Notes     string
+	Binary    *Asset // nil when the release has no build for this platform
+	Checksums *Asset // nil when the release has no ChecksumsFile
+}
+
+// CanInstall reports whether the release has a binary for this platform
+func (r *Release) CanInstall() bool {
+	return r != nil && r.Binary != nil
+}
+
+// Updater checks for and installs new releases
+type Updater struct {
+	config Config
+	client *http.Client
+}
+
+// NewUpdater creates an updater for the running version
+func NewUpdater(config Config) (*Updater, error) {
+	if _, ok := parseVersion(config.CurrentVersion); !ok {
+		return nil, fmt.Errorf("invalid current version %q", config.CurrentVersion)
+	}
+	if config.Repository == "" {
+		config.Repository = DefaultRepository
+	}
+	if strings.Count(config.Repository, "/") != 1 {
+		return nil, fmt.Errorf("repository must be owner/name, got %q", config.Repository)
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+	if config.BinaryName == "" {
+		config.BinaryName = DefaultBinaryName
+	}
+	if config.GOOS == "" {
+		config.GOOS = runtime.GOOS
+	}
+	if config.GOARCH == "" {
+		config.GOARCH = runtime.GOARCH
+	}
+
+	return &Updater{config: config, client: &http.Client{}}, nil
+}
+
+// AssetName is the release asset holding the binary for a platform, e.g.
+// companion-linux-amd64 or companion-windows-amd64.exe. macOS builds are
+// published as "macos".
+func AssetName(binary, goos, goarch string) string {
+	name := goos
+	if goos == "darwin" {
+		name = "macos"
+	}
+	asset := fmt.Sprintf("%s-%s-%s", binary, name, goarch)
+	if goos == "windows" {
+		asset += ".exe"
+	}
+	return asset
+}
+
+// githubRelease is the part of the GitHub releases API response we use
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	HTMLURL string `json:"html_url"`
+	Body    string `json:"body"`
+	Draft   bool   `json:"draft"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+		Size               int64  `json:"size"`
+		Digest             string `json:"digest"`
+	} `json:"assets"`
+}
+
+// Check returns the newest published release if it is newer than the
+// running version, or nil when up to date. Pre-releases count, since every
+// release is published as one.
+func (u *Updater) Check(ctx context.Context) (*Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=%d", u.config.APIURL, u.config.Repository, releasesToCheck)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for updates: %s", resp.Status)
+	}
+
+	var releases []githubRelease
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	current, _ := parseVersion(u.config.CurrentVersion)
+	var newest *githubRelease
+	var newestVersion [3]int
+	for i := range releases {
+		version, ok := parseVersion(releases[i].TagName)
+		if releases[i].Draft || !ok || compare(version, current) <= 0 {
+			continue
+		}
+		if newest == nil || compare(version, newestVersion) > 0 {
+			newest, newestVersion = &releases[i], version
+		}
+	}
+	if newest == nil {
+		return nil, nil
+	}
+
+	release := &Release{
+		Version: strings.TrimPrefix(newest.TagName, "v"),
+		Name:    newest.Name,
+		PageURL: newest.HTMLURL,
+		Notes:   newest.Body,
+	}
+	binaryName := AssetName(u.config.BinaryName, u.config.GOOS, u.config.GOARCH)
+	for _, a := range newest.Assets {
+		asset := &Asset{Name: a.Name, URL: a.BrowserDownloadURL, Size: a.Size, Digest: a.Digest}
+		switch a.Name {
+		case binaryName:
+			release.Binary = asset
+		case ChecksumsFile:
+			release.Checksums = asset
+		}
+	}
+	return release, nil
+}
+
+// Install downloads the release's binary, verifies its checksum and
+// replaces the executable at path with it. The new version runs from the
+// next start.
+func (u *Updater) Install(ctx context.Context, release *Release, path string) error {
+	if release == nil {
+		return fmt.Errorf("no release to install")
+	}
+	if release.Binary == nil {
+		return fmt.Errorf("version %s has no build for %s/%s", release.Version, u.config.GOOS, u.config.GOARCH)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	want, err := u.expectedChecksum(ctx, release)
+	if err != nil {
+		return err
+	}
+
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return fmt.Errorf("failed to find the installed binary: %w", err)
+	}
+
+	// Download next to the binary so the final rename stays on one filesystem
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".companion-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create download file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	got, err := u.download(ctx, release.Binary.URL, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("downloaded %s does not match its checksum", release.Binary.Name)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return fmt.Errorf("failed to make the update executable: %w", err)
+	}
+	return replaceExecutable(path, tmp.Name())
+}
+
+// expectedChecksum returns the binary's published SHA-256: GitHub's asset
+// digest, or its line in the release's ChecksumsFile
+func (u *Updater) expectedChecksum(ctx context.Context, release *Release) (string, error) {
+	if digest, ok := strings.CutPrefix(release.Binary.Digest, "sha256:"); ok {
+		return strings.ToLower(digest), nil
+	}
+	if release.Checksums == nil {
+		return "", fmt.Errorf("version %s publishes no checksum for %s, refusing to install it", release.Version, release.Binary.Name)
+	}
+
+	var sums strings.Builder
+	if _, err := u.download(ctx, release.Checksums.URL, &sums); err != nil {
+		return "", err
+	}
+	checksum, ok := findChecksum(sums.String(), release.Binary.Name)
+	if !ok {
+		return "", fmt.Errorf("%s has no checksum for %s", ChecksumsFile, release.Binary.Name)
+	}
+	return checksum, nil
+}
+
+// findChecksum looks a file up in sha256sum output
+func findChecksum(sums, name string) (string, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sha256sum marks files read in binary mode with a leading "*"
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name && len(fields[0]) == sha256.Size*2 {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// download copies url to w and returns the SHA-256 of what it wrote
+func (u *Updater) download(ctx context.Context, url string, w io.Writer) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download update: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, hash), io.LimitReader(resp.Body, maxBinarySize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download update: %w", err)
+	}
+	if n > maxBinarySize {
+		return "", fmt.Errorf("download of %s is larger than %d MB", url, maxBinarySize>>20)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// replaceExecutable swaps the binary at path for newPath. The running binary
+// is moved aside first, which Windows allows while it runs; if the swap
+// fails it is moved back.
+func replaceExecutable(path, newPath string) error {
+	old := path + ".old"
+	os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return fmt.Errorf("failed to move the old binary aside: %w", err)
+	}
+	if err := os.Rename(newPath, path); err != nil {
+		os.Rename(old, path)
+		return fmt.Errorf("failed to install the update: %w", err)
+	}
+	// Fails on Windows while the old binary runs; the next update removes it
+	os.Remove(old)
+	return nil
+}
+
+// CompareVersions compares two "v1.2.3" style versions, returning -1, 0 or
+// 1. Unparseable versions sort first.
+func CompareVersions(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	return compare(va, vb)
+}
+
+// parseVersion reads "v1.2.3", "1.2" or "1.2.3-beta.1" into its numbers.
+// Pre-release suffixes are ignored.
+func parseVersion(s string) ([3]int, bool) {
+	var version [3]int
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return version, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
+
+// compare orders parsed versions
+func compare(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
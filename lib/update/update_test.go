@@ -0,0 +1,182 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// releaseServer fakes the GitHub releases API and asset downloads
+type releaseServer struct {
+	*httptest.Server
+	releases []githubRelease
+	assets   map[string]string // Asset name -> content
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	t.Helper()
+	s := &releaseServer{assets: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/opd-ai/desktop-companion/releases", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(s.releases)
+	})
+	mux.HandleFunc("/download/{name}", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := s.assets[r.PathValue("name")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addRelease publishes a release with the named assets
+func (s *releaseServer) addRelease(tag string, draft bool, names ...string) {
+	r := githubRelease{TagName: tag, HTMLURL: "https://example.com/" + tag, Draft: draft}
+	for _, name := range names {
+		r.Assets = append(r.Assets, struct {
+			Name               string `json:"name"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+			Size               int64  `json:"size"`
+			Digest             string `json:"digest"`
+		}{Name: name, BrowserDownloadURL: s.URL + "/download/" + name})
+	}
+	s.releases = append(s.releases, r)
+}
+
+func newTestUpdater(t *testing.T, server *releaseServer, current string) *Updater {
+	t.Helper()
+	u, err := NewUpdater(Config{CurrentVersion: current, APIURL: server.URL, GOOS: "linux", GOARCH: "amd64"})
+	if err != nil {
+		t.Fatalf("NewUpdater failed: %v", err)
+	}
+	return u
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCheckFindsNewestRelease(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease("v1.1.0", false, "companion-linux-amd64")
+	server.addRelease("v1.3.0", false, "companion-windows-amd64.exe", ChecksumsFile)
+	server.addRelease("not-a-version", false)
+	server.addRelease("v0.9.0", false, "companion-linux-amd64")
+	server.addRelease("v2.0.0", true, "companion-linux-amd64")
+
+	got, err := newTestUpdater(t, server, "1.0.0").Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if got == nil || got.Version != "1.3.0" {
+		t.Fatalf("Expected the newest non-draft release 1.3.0, got %+v", got)
+	}
+	if got.CanInstall() || got.Checksums == nil {
+		t.Errorf("Expected checksums but no linux build in 1.3.0, got %+v", got)
+	}
+
+	if got, err := newTestUpdater(t, server, "v1.3.0").Check(context.Background()); err != nil || got != nil {
+		t.Errorf("Expected no update when current, got %+v, %v", got, err)
+	}
+}
+
+func TestInstallVerifiesChecksum(t *testing.T) {
+	const binary = "#!/bin/sh\necho new\n"
+	assets := map[string]string{
+		"companion-linux-amd64": binary,
+		ChecksumsFile:           sha(binary) + "  companion-linux-amd64\n" + sha("other") + "  companion-windows-amd64.exe\n",
+	}
+	server := newReleaseServer(t)
+	server.assets = assets
+	server.addRelease("v1.1.0", false, "companion-linux-amd64", ChecksumsFile)
+
+	u := newTestUpdater(t, server, "1.0.0")
+	available, err := u.Check(context.Background())
+	if err != nil || !available.CanInstall() {
+		t.Fatalf("Expected an installable release, got %+v, %v", available, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "companion")
+	if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Install(context.Background(), available, path); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != binary {
+		t.Errorf("Expected the new binary installed, got %q", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected no leftover files, got %d entries", len(entries))
+	}
+
+	// A download that doesn't match is never installed
+	assets["companion-linux-amd64"] = "tampered"
+	if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Install(context.Background(), available, path); err == nil {
+		t.Error("Expected a checksum mismatch to fail")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("Expected the old binary kept, got %q", data)
+	}
+
+	// Neither a digest nor a checksums file
+	available.Checksums = nil
+	if err := u.Install(context.Background(), available, path); err == nil {
+		t.Error("Expected a release without checksums to be refused")
+	}
+
+	// GitHub's own digest is enough
+	available.Binary.Digest = "sha256:" + sha("tampered")
+	if err := u.Install(context.Background(), available, path); err != nil {
+		t.Errorf("Expected the asset digest to verify the download, got %v", err)
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	tests := map[[2]string]string{
+		{"linux", "amd64"}:   "companion-linux-amd64",
+		{"windows", "amd64"}: "companion-windows-amd64.exe",
+		{"darwin", "arm64"}:  "companion-macos-arm64",
+	}
+	for platform, want := range tests {
+		if got := AssetName("companion", platform[0], platform[1]); got != want {
+			t.Errorf("%v: expected %s, got %s", platform, want, got)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "v1.0.0", 0},
+		{"1.2", "1.10.0", -1},
+		{"v2.0.0-beta.1", "1.9.9", 1},
+		{"garbage", "0.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := NewUpdater(Config{CurrentVersion: "dev"}); err == nil {
+		t.Error("Expected an unparseable current version to be rejected")
+	}
+}
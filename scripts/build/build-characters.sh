@@ -32,6 +32,10 @@ MAX_PARALLEL="${DDS_MAX_PARALLEL}"
 PLATFORMS="${DDS_PLATFORMS}"
 LDFLAGS="${DDS_LDFLAGS}"
 ENABLE_ARTIFACT_MGMT="${DDS_ENABLE_ARTIFACT_MGMT}"
+MACOS_SIGN_IDENTITY="${DDS_MACOS_SIGN_IDENTITY:-}"
+MACOS_NOTARY_PROFILE="${DDS_MACOS_NOTARY_PROFILE:-}"
+WINDOWS_INSTALLER="${DDS_WINDOWS_INSTALLER:-false}"
+MAKENSIS="${DDS_MAKENSIS:-}"
 SPECIFIC_CHARACTER=""
 COMMAND=""
 
@@ -73,6 +77,10 @@ ENVIRONMENT VARIABLES:
     DDS_PLATFORMS          Target platforms for builds
     DDS_LDFLAGS           Linker flags for optimization
     DDS_ENABLE_ARTIFACT_MGMT   Enable automatic artifact management (true/false)
+    DDS_MACOS_SIGN_IDENTITY    codesign identity for macOS .app bundles (unsigned when empty)
+    DDS_MACOS_NOTARY_PROFILE   notarytool keychain profile (not notarized when empty)
+    DDS_WINDOWS_INSTALLER      Add an NSIS installer script to Windows zips (true/false)
+    DDS_MAKENSIS               makensis to compile Windows installers with (none when empty)
 
 See: docs/CHARACTER_BINARY_VALIDATION_GUIDE.md for more information.
 EOF
@@ -339,6 +347,8 @@ build_character_platform() {
         
         # Store artifact if enabled
         [[ "$ENABLE_ARTIFACT_MGMT" == "true" ]] && store_artifact "$output_file"
+        [[ "$goos" == "darwin" ]] && bundle_darwin "$char" "$goarch" "$output_file"
+        [[ "$goos" == "windows" ]] && bundle_windows "$char" "$goarch" "$output_file"
         return 0
     else
         error "Failed to build $char for $platform"
@@ -494,6 +504,49 @@ store_artifact() {
     fi
 }
 
+# Wrap a macOS binary in a .app bundle, signed and notarized when configured,
+# and store it zipped (if artifact management is enabled)
+bundle_darwin() {
+    local char="$1"
+    local goarch="$2"
+    local binary="$3"
+    
+    if [[ "$ENABLE_ARTIFACT_MGMT" != "true" ]] || ! command_exists "$BUILD_DIR/artifact-manager"; then
+        return 0
+    fi
+    
+    if "$BUILD_DIR/artifact-manager" \
+        -characters "$PROJECT_ROOT/assets/characters" \
+        -sign-identity "$MACOS_SIGN_IDENTITY" \
+        -notary-profile "$MACOS_NOTARY_PROFILE" \
+        bundle darwin "$char" "$goarch" "$binary"; then
+        success "Bundled: $char for darwin/$goarch"
+    else
+        warning "Failed to bundle $char for darwin/$goarch"
+    fi
+}
+
+# Package a Windows build as a portable zip, with an installer when configured
+bundle_windows() {
+    local char="$1"
+    local goarch="$2"
+    local binary="$3"
+    
+    if [[ "$ENABLE_ARTIFACT_MGMT" != "true" ]] || ! command_exists "$BUILD_DIR/artifact-manager"; then
+        return 0
+    fi
+    
+    if "$BUILD_DIR/artifact-manager" \
+        -characters "$PROJECT_ROOT/assets/characters" \
+        -installer="$WINDOWS_INSTALLER" \
+        -makensis "$MAKENSIS" \
+        bundle windows "$char" "$goarch" "$binary"; then
+        success "Packaged: $char for windows/$goarch"
+    else
+        warning "Failed to package $char for windows/$goarch"
+    fi
+}
+
 # Manage stored artifacts
 manage_artifacts() {
     if [[ "$ENABLE_ARTIFACT_MGMT" != "true" ]]; then
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BundleValidator provides validation functionality for macOS .app bundles
+type BundleValidator struct {
+	bundlePath string
+}
+
+// requiredPlistKeys are the Info.plist keys every bundle must set
+var requiredPlistKeys = []string{
+	"CFBundleExecutable",
+	"CFBundleIdentifier",
+	"CFBundleName",
+	"CFBundlePackageType",
+	"CFBundleShortVersionString",
+	"CFBundleVersion",
+}
+
+// NewBundleValidator creates a new bundle validator for the given .app
+// directory
+func NewBundleValidator(bundlePath string) *BundleValidator {
+	return &BundleValidator{bundlePath: bundlePath}
+}
+
+// ValidateBundle performs comprehensive validation of a .app bundle
+func (v *BundleValidator) ValidateBundle() error {
+	// Check if the bundle exists and has the correct extension
+	if err := v.validateBundleExists(); err != nil {
+		return fmt.Errorf("bundle validation failed: %w", err)
+	}
+
+	// Validate the Info.plist
+	plist, err := v.readInfoPlist()
+	if err != nil {
+		return fmt.Errorf("Info.plist validation failed: %w", err)
+	}
+	if err := validatePlistKeys(plist); err != nil {
+		return fmt.Errorf("Info.plist validation failed: %w", err)
+	}
+
+	// Validate the executable and icon the plist points to
+	if err := v.validateExecutable(plist["CFBundleExecutable"]); err != nil {
+		return fmt.Errorf("executable validation failed: %w", err)
+	}
+	if err := v.validateIcon(plist["CFBundleIconFile"]); err != nil {
+		return fmt.Errorf("icon validation failed: %w", err)
+	}
+
+	// Validate PkgInfo, which Finder still reads
+	if err := v.validatePkgInfo(); err != nil {
+		return fmt.Errorf("PkgInfo validation failed: %w", err)
+	}
+
+	return nil
+}
+
+// validateBundleExists checks the bundle is a directory ending in .app
+func (v *BundleValidator) validateBundleExists() error {
+	info, err := os.Stat(v.bundlePath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("bundle does not exist: %s", v.bundlePath)
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("bundle is not a directory: %s", v.bundlePath)
+	}
+	if !strings.HasSuffix(strings.ToLower(v.bundlePath), ".app") {
+		return fmt.Errorf("bundle does not have .app extension: %s", v.bundlePath)
+	}
+	return nil
+}
+
+// contentsPath returns a path inside the bundle's Contents directory
+func (v *BundleValidator) contentsPath(elem ...string) string {
+	return filepath.Join(append([]string{v.bundlePath, "Contents"}, elem...)...)
+}
+
+// readInfoPlist reads the string values of the top-level Info.plist dict
+func (v *BundleValidator) readInfoPlist() (map[string]string, error) {
+	file, err := os.Open(v.contentsPath("Info.plist"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Info.plist: %w", err)
+	}
+	defer file.Close()
+	return parsePlistStrings(file)
+}
+
+// parsePlistStrings reads the string and boolean values of an XML plist's
+// top-level dict. Binary plists are not supported; bundles built by the
+// artifact manager use XML.
+func parsePlistStrings(r io.Reader) (map[string]string, error) {
+	decoder := xml.NewDecoder(r)
+	values := make(map[string]string)
+	depth := 0
+	key := ""
+	sawDict := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid plist XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 2 && t.Name.Local == "dict":
+				sawDict = true
+			case depth == 3 && t.Name.Local == "key":
+				var text string
+				if err := decoder.DecodeElement(&text, &t); err != nil {
+					return nil, fmt.Errorf("invalid plist key: %w", err)
+				}
+				key = text
+				depth--
+			case depth == 3 && t.Name.Local == "string":
+				var text string
+				if err := decoder.DecodeElement(&text, &t); err != nil {
+					return nil, fmt.Errorf("invalid plist value for %s: %w", key, err)
+				}
+				values[key] = text
+				depth--
+			case depth == 3 && (t.Name.Local == "true" || t.Name.Local == "false"):
+				values[key] = t.Name.Local
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if !sawDict {
+		return nil, fmt.Errorf("plist has no top-level dict")
+	}
+	return values, nil
+}
+
+// validatePlistKeys checks the required keys are set and the bundle is an
+// application
+func validatePlistKeys(plist map[string]string) error {
+	var missing []string
+	for _, key := range requiredPlistKeys {
+		if plist[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required keys: %v", missing)
+	}
+	if plist["CFBundlePackageType"] != "APPL" {
+		return fmt.Errorf("CFBundlePackageType is %q, want APPL", plist["CFBundlePackageType"])
+	}
+	if strings.ContainsAny(plist["CFBundleIdentifier"], " _/") {
+		return fmt.Errorf("CFBundleIdentifier %q may only hold letters, digits, dots and hyphens", plist["CFBundleIdentifier"])
+	}
+	return nil
+}
+
+// validateExecutable checks the executable exists in Contents/MacOS and can
+// be run
+func (v *BundleValidator) validateExecutable(name string) error {
+	info, err := os.Stat(v.contentsPath("MacOS", name))
+	if err != nil {
+		return fmt.Errorf("executable %s not found in Contents/MacOS", name)
+	}
+	if info.Size() == 0 {
+		return fmt.Errorf("executable %s is empty", name)
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("executable %s is not marked executable", name)
+	}
+	return nil
+}
+
+// validateIcon checks the icon file exists and is an .icns file
+func (v *BundleValidator) validateIcon(name string) error {
+	if name == "" {
+		return fmt.Errorf("CFBundleIconFile is not set, the app would show a generic icon")
+	}
+	if filepath.Ext(name) == "" {
+		name += ".icns"
+	}
+
+	file, err := os.Open(v.contentsPath("Resources", name))
+	if err != nil {
+		return fmt.Errorf("icon %s not found in Contents/Resources", name)
+	}
+	defer file.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(file, magic); err != nil || string(magic) != "icns" {
+		return fmt.Errorf("icon %s is not an .icns file", name)
+	}
+	return nil
+}
+
+// validatePkgInfo checks PkgInfo marks the bundle as an application
+func (v *BundleValidator) validatePkgInfo() error {
+	data, err := os.ReadFile(v.contentsPath("PkgInfo"))
+	if err != nil {
+		return fmt.Errorf("PkgInfo not found")
+	}
+	if !strings.HasPrefix(string(data), "APPL") {
+		return fmt.Errorf("PkgInfo is %q, want APPL????", data)
+	}
+	return nil
+}
+
+// GetBundleInfo extracts basic information about the bundle
+func (v *BundleValidator) GetBundleInfo() (*BundleInfo, error) {
+	plist, err := v.readInfoPlist()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &BundleInfo{
+		BundlePath: v.bundlePath,
+		Identifier: plist["CFBundleIdentifier"],
+		Version:    plist["CFBundleShortVersionString"],
+		Executable: plist["CFBundleExecutable"],
+	}
+
+	err = filepath.Walk(v.bundlePath, func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fileInfo.IsDir() {
+			info.SizeBytes += fileInfo.Size()
+			info.FileCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk bundle: %w", err)
+	}
+
+	// Assets embedded next to the executable's resources
+	if _, err := os.Stat(v.contentsPath("Resources", "assets", "characters")); err == nil {
+		info.HasAssets = true
+	}
+	// codesign writes the signature here; stapler adds the notarization ticket
+	if _, err := os.Stat(v.contentsPath("_CodeSignature", "CodeResources")); err == nil {
+		info.Signed = true
+	}
+	if _, err := os.Stat(v.contentsPath("CodeResources")); err == nil {
+		info.Stapled = true
+	}
+
+	return info, nil
+}
+
+// BundleInfo contains information about a .app bundle
+type BundleInfo struct {
+	BundlePath string
+	Identifier string
+	Version    string
+	Executable string
+	SizeBytes  int64
+	FileCount  int
+	HasAssets  bool
+	Signed     bool
+	Stapled    bool
+}
+
+// String returns a human-readable representation of bundle info
+func (info *BundleInfo) String() string {
+	return fmt.Sprintf("Bundle: %s (%s %s, %.1f KB, %d files, assets=%t, signed=%t, stapled=%t)",
+		filepath.Base(info.BundlePath),
+		info.Identifier,
+		info.Version,
+		float64(info.SizeBytes)/1024,
+		info.FileCount,
+		info.HasAssets,
+		info.Signed,
+		info.Stapled,
+	)
+}
+
+// ValidateCharacterBundle validates a character-specific bundle
+func ValidateCharacterBundle(bundlePath, expectedCharacter string) error {
+	validator := NewBundleValidator(bundlePath)
+
+	// Basic bundle validation
+	if err := validator.ValidateBundle(); err != nil {
+		return fmt.Errorf("bundle validation failed: %w", err)
+	}
+
+	// The character's card must be embedded where the companion looks for it
+	card := validator.contentsPath("Resources", "assets", "characters", expectedCharacter, "character.json")
+	if _, err := os.Stat(card); err != nil {
+		return fmt.Errorf("character %s not found in Contents/Resources/assets/characters", expectedCharacter)
+	}
+
+	return nil
+}
+
+// extractBundleZip extracts a zipped bundle, as stored by the artifact
+// manager, and returns the path of the .app inside
+func extractBundleZip(zipPath string) (appPath string, err error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open bundle zip: %w", err)
+	}
+	defer reader.Close()
+
+	dir, err := os.MkdirTemp("", "bundle-validator-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	for _, file := range reader.File {
+		target := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+			return "", fmt.Errorf("zip entry escapes the bundle: %s", file.Name)
+		}
+		if top, _, _ := strings.Cut(file.Name, "/"); strings.HasSuffix(top, ".app") {
+			appPath = filepath.Join(dir, top)
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if err := extractZipFile(file, target); err != nil {
+			return "", err
+		}
+	}
+
+	if appPath == "" {
+		return "", fmt.Errorf("no .app bundle found in %s", zipPath)
+	}
+	return appPath, nil
+}
+
+// extractZipFile writes one zip entry, keeping its permissions
+func extractZipFile(file *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, file.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// main function for standalone bundle validation tool
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Printf("Usage: %s <bundle.app|bundle.zip> [character-name]\n", os.Args[0])
+		fmt.Println("\nValidates macOS .app bundles generated by the DDS artifact manager.")
+		fmt.Println("\nExamples:")
+		fmt.Println("  go run tools/bundle-validator/main.go \"build/Desktop Companion.app\"")
+		fmt.Println("  go run tools/bundle-validator/main.go build/artifacts/default/darwin_arm64/default_darwin_arm64_20250101-120000.zip default")
+		os.Exit(1)
+	}
+
+	bundlePath := os.Args[1]
+	var character string
+	if len(os.Args) > 2 {
+		character = os.Args[2]
+	}
+
+	fmt.Printf("Validating bundle: %s\n", bundlePath)
+	if character != "" {
+		fmt.Printf("Expected character: %s\n", character)
+	}
+	fmt.Println()
+
+	os.Exit(validate(bundlePath, character))
+}
+
+// validate validates the bundle, extracting it first when zipped, and
+// returns the exit status
+func validate(bundlePath, character string) int {
+	// Stored bundles are zipped
+	if strings.HasSuffix(strings.ToLower(bundlePath), ".zip") {
+		appPath, err := extractBundleZip(bundlePath)
+		if err != nil {
+			fmt.Printf("❌ Validation failed: %v\n", err)
+			return 1
+		}
+		defer os.RemoveAll(filepath.Dir(appPath))
+		bundlePath = appPath
+	}
+
+	// Perform validation
+	var err error
+	if character != "" {
+		err = ValidateCharacterBundle(bundlePath, character)
+	} else {
+		err = NewBundleValidator(bundlePath).ValidateBundle()
+	}
+
+	if err != nil {
+		fmt.Printf("❌ Validation failed: %v\n", err)
+		return 1
+	}
+
+	// Get and display bundle info
+	info, err := NewBundleValidator(bundlePath).GetBundleInfo()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not get bundle info: %v\n", err)
+	} else {
+		fmt.Printf("🍎 %s\n", info.String())
+		if !info.Signed {
+			fmt.Println("⚠️  Bundle is not signed; Gatekeeper will block it on other Macs")
+		}
+	}
+
+	fmt.Println("✅ Bundle validation successful!")
+	return 0
+}